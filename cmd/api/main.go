@@ -3,19 +3,52 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"mini-kart/internal/breaker"
+	"mini-kart/internal/cache"
 	"mini-kart/internal/config"
 	"mini-kart/internal/coupon"
+	"mini-kart/internal/currency"
 	"mini-kart/internal/database"
+	"mini-kart/internal/events"
+	"mini-kart/internal/export"
 	"mini-kart/internal/handler"
+	"mini-kart/internal/health"
+	"mini-kart/internal/httpclient"
+	"mini-kart/internal/logging"
+	"mini-kart/internal/media"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/notification"
+	"mini-kart/internal/pricing"
 	"mini-kart/internal/repository"
 	"mini-kart/internal/router"
+	"mini-kart/internal/saga"
 	"mini-kart/internal/service"
+	"mini-kart/internal/shutdown"
+	"mini-kart/internal/webhook"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	// shutdownTimeout is the total budget for stopping the server and running
+	// the shutdown hooks.
+	shutdownTimeout = 30 * time.Second
+
+	// shutdownHookTimeout limits each shutdown hook.
+	shutdownHookTimeout = 5 * time.Second
 )
 
 func main() {
@@ -33,9 +66,17 @@ func run() error {
 	}
 
 	// Initialize logger
-	logger := config.NewLogger(cfg.Logger)
+	logger, logSinks, err := config.NewLogger(cfg.Logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	// Deferred first so it runs last, after the shutdown has been logged
+	defer logSinks.Close()
 	logger.Info().Msg("starting mini-kart API server")
 
+	// Route libraries logging through log/slog to the same output
+	slog.SetDefault(logging.Slog(logger))
+
 	// Create context for application lifecycle
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -45,59 +86,624 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
+	// Closing is idempotent; this covers start-up failures before the
+	// database pool shutdown hook is registered
 	defer pool.Close()
 
+	// Cleanup steps run in registration order after the server stops
+	hooks := shutdown.NewHooks(logger)
+
+	// Background workers run until ctx is cancelled. Stop them first so none
+	// is mid-update when the components they use are closed.
+	var workers sync.WaitGroup
+	hooks.Register("background workers", shutdownHookTimeout, func(context.Context) error {
+		cancel()
+		workers.Wait()
+		return nil
+	})
+
 	// Initialize repositories
 	productRepo := repository.NewProductRepository(pool, logger)
 	orderRepo := repository.NewOrderRepository(pool, logger)
+	couponReservationRepo := repository.NewCouponReservationRepository(pool, logger)
+	couponDiscountRepo := repository.NewCouponDiscountRepository(pool, logger)
+	idempotencyRepo := repository.NewIdempotencyRepository(pool, time.Duration(cfg.Order.IdempotencyKeyTTL)*time.Second, logger)
+	operationRepo := repository.NewOperationRepository(pool, logger)
+	priceChangeRepo := repository.NewPriceChangeRepository(pool, logger)
+	shipmentRepo := repository.NewShipmentRepository(pool, logger)
+	orderNoteRepo := repository.NewOrderNoteRepository(pool, logger)
+	orderPricingRepo := repository.NewOrderPricingRepository(pool, logger)
+	tenantRepo := repository.NewTenantRepository(pool, logger)
+	customerRepo := repository.NewCustomerRepository(pool, logger)
+	webhookRepo := repository.NewWebhookRepository(pool, logger)
+
+	// Tenant keys are only useful once their tenants exist
+	if err := tenantRepo.Ensure(ctx, cfg.Auth.TenantIDs()); err != nil {
+		return fmt.Errorf("failed to create configured tenants: %w", err)
+	}
+
+	// Fail product and order reads fast, serving stale catalogue data where
+	// possible, instead of letting every request wait on an unreachable database
+	if cfg.Database.BreakerFailureThreshold > 0 {
+		dbBreaker := breaker.New("database", breaker.Config{
+			FailureThreshold: cfg.Database.BreakerFailureThreshold,
+			Cooldown:         time.Duration(cfg.Database.BreakerCooldown) * time.Second,
+		}, logger)
+		productRepo = repository.NewBreakingProductRepository(productRepo, dbBreaker, logger)
+		orderRepo = repository.NewBreakingOrderRepository(orderRepo, dbBreaker)
+	}
+
+	// Operational metrics, such as cache hit rates and coupon file download bandwidth
+	counters := metrics.NewRegistry()
+
+	// Share cached products and rate limit counters between API replicas
+	var redisClient *cache.Client
+	if cfg.Redis.Addr != "" {
+		redisClient, err = cache.New(ctx, cfg.Redis, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize redis: %w", err)
+		}
+		// Closing twice only returns an error; this covers start-up failures
+		// before the redis shutdown hook is registered
+		defer redisClient.Close()
 
-	// Initialize coupon loader with S3 and local fallback
+		if cfg.Redis.ProductTTL > 0 {
+			productCache := counters.Cache("product")
+			productRepo = repository.NewCachedProductRepository(productRepo, redisClient, time.Duration(cfg.Redis.ProductTTL)*time.Second, productCache, logger)
+			priceChangeRepo = repository.NewCachedPriceChangeRepository(priceChangeRepo, redisClient, productCache, logger)
+		}
+	}
+
+	// Every outbound HTTP call shares one pooled client
+	httpClient := httpclient.New(cfg.HTTP)
+
+	// Coupon files can be large enough to outlast the request timeout, so
+	// object storage shares the connection pool but relies on the
+	// transport's timeouts
+	storageClient := &http.Client{Transport: httpClient.Transport}
+
+	// Download coupon files in ranged parts, retrying the parts that fail
+	s3Download := coupon.S3DownloadConfig{
+		PartSize:      int64(cfg.S3.PartSizeMB) * 1024 * 1024,
+		Concurrency:   cfg.S3.DownloadConcurrency,
+		MaxAttempts:   cfg.S3.MaxAttempts,
+		RetryDelay:    time.Duration(cfg.S3.RetryDelay) * time.Millisecond,
+		MaxRetryDelay: time.Duration(cfg.S3.MaxRetryDelay) * time.Millisecond,
+		Metrics:       counters,
+	}
+
+	// Export OpenTelemetry metrics, such as coupon file loads, when a
+	// collector is configured; otherwise they are not recorded
+	var meterProvider *sdkmetric.MeterProvider
+	if cfg.Metrics.OTLPEndpoint != "" {
+		meterProvider, err = metrics.NewOTLPMeterProvider(ctx, time.Duration(cfg.Metrics.OTLPInterval)*time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to initialize metrics export: %w", err)
+		}
+		otel.SetMeterProvider(meterProvider)
+		logger.Info().Str("endpoint", cfg.Metrics.OTLPEndpoint).Msg("exporting OpenTelemetry metrics")
+	}
+
+	// Initialize coupon loader for the configured source
 	fileLoader := coupon.NewFileLoader(logger)
 	var couponLoader coupon.Loader
 
-	if cfg.S3.Enabled {
-		// Create S3 loader
-		s3Loader, err := coupon.NewS3Loader(ctx, cfg.S3.Bucket, cfg.S3.Region, logger)
+	switch cfg.Coupon.Source {
+	case "s3", "storage":
+		// Create the object storage loader for the configured provider
+		storageLoader, err := newStorageCouponLoader(ctx, cfg, storageClient, s3Download, logger)
 		if err != nil {
 			logger.Warn().
 				Err(err).
-				Msg("failed to initialise S3 loader, falling back to local file system only")
+				Str("provider", cfg.Coupon.StorageProvider).
+				Msg("failed to initialise object storage loader, falling back to local file system only")
 			couponLoader = fileLoader
 		} else {
-			couponLoader = s3Loader
+			couponLoader = storageLoader
 		}
-	} else {
-		// S3 disabled, use local file system only
+
+		// Cache coupon files on local disk when a cache directory is configured
+		if err == nil && cfg.Cache.Dir != "" {
+			cachingLoader, cache, cacheErr := newCachingCouponLoader(ctx, cfg, fileLoader, storageClient, s3Download, logger)
+			if cacheErr != nil {
+				logger.Warn().
+					Err(cacheErr).
+					Msg("failed to initialise coupon file cache, loading directly from object storage")
+			} else {
+				couponLoader = cachingLoader
+				hooks.Register("coupon file cache", shutdownHookTimeout, func(context.Context) error {
+					return cache.Flush()
+				})
+			}
+		}
+	case "db":
+		couponLoader = coupon.NewDBLoader(repository.NewCouponCodeRepository(pool, logger), logger)
+		logger.Info().Msg("using database for coupon codes")
+	case "index":
+		couponLoader = coupon.NewIndexLoader(cfg.Coupon.IndexDir, logger)
+		logger.Info().Msg("using prebuilt coupon indexes")
+	default:
 		couponLoader = fileLoader
-		logger.Info().Msg("using local file system for coupon files (S3 disabled)")
+		logger.Info().Msg("using local file system for coupon files")
 	}
 
-	// Initialize coupon validator
+	// Abort coupon loading with diagnostics instead of being OOM-killed mid-load
+	if cfg.Coupon.MemoryLimitMB > 0 {
+		couponLoader = coupon.NewMemoryGuardedLoader(
+			couponLoader,
+			uint64(cfg.Coupon.MemoryLimitMB)*1024*1024,
+			time.Duration(cfg.Coupon.MemoryCheckInterval)*time.Millisecond,
+			logger,
+		)
+	}
+
+	// Initialize coupon validator, recording every load of the coupon files
+	// against this instance so validations can be traced to their uploads
+	couponFileRepo := repository.NewCouponFileRepository(pool, logger)
 	validatorConfig := coupon.DefaultValidatorConfig()
+	validatorConfig.Generations = couponFileRepo
+	if hostname, err := os.Hostname(); err == nil {
+		validatorConfig.Instance = hostname
+	}
+	if len(cfg.Coupon.Files) > 0 {
+		validatorConfig.FilePaths = cfg.Coupon.Files
+	}
+	validatorConfig.MinMatchCount = cfg.Coupon.MinMatchCount
+	validatorConfig.Weights = cfg.Coupon.MatchWeights()
+	validatorConfig.SetType = cfg.Coupon.SetType
+	validatorConfig.ExpectedCoupons = cfg.Coupon.ExpectedCodes
+	validatorConfig.FalsePositiveRate = cfg.Coupon.FalsePositiveRate
+	validatorConfig.CodePattern = cfg.Coupon.CodePattern
+	validatorConfig.CaseInsensitive = cfg.Coupon.CaseInsensitive
+	validatorConfig.Timeout = time.Duration(cfg.Coupon.ValidationTimeout) * time.Millisecond
+	validatorConfig.Metadata = couponDiscountRepo
+	validatorConfig.Eligibility = coupon.NewEligibilityChecker(customerRepo, logger)
+	validatorConfig.LoadInBackground = cfg.Coupon.LoadInBackground
+	if cfg.Coupon.DeltaDir != "" {
+		validatorConfig.Deltas = coupon.NewDirDeltaSource(cfg.Coupon.DeltaDir, logger)
+	}
 	validator, err := coupon.NewValidator(ctx, validatorConfig, couponLoader, logger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize coupon validator: %w", err)
 	}
-	defer validator.Close()
+	hooks.Register("coupon validator", shutdownHookTimeout, func(context.Context) error {
+		return validator.Close()
+	})
+	if meterProvider != nil {
+		// Export the metrics recorded since the last export
+		hooks.Register("metrics exporter", shutdownHookTimeout, meterProvider.Shutdown)
+	}
+
+	// Apply daily coupon deltas without reloading the base files
+	if validatorConfig.Deltas != nil {
+		workers.Go(func() {
+			coupon.RunDeltaUpdates(ctx, validator, time.Duration(cfg.Coupon.DeltaInterval)*time.Second, logger)
+		})
+	}
+
+	// Check whether codes were valid at past times against archived copies
+	// of the coupon files, with the validator's rules
+	var couponArchive coupon.ObjectSource
+	switch {
+	case cfg.Coupon.ArchiveDir != "":
+		couponArchive = coupon.NewDirSource(cfg.Coupon.ArchiveDir)
+	case cfg.Coupon.ArchivePrefix != "":
+		couponArchive, err = newStorageCouponSource(ctx, cfg, storageClient, s3Download, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize coupon archive: %w", err)
+		}
+	}
+	couponHistory, err := coupon.NewHistory(validatorConfig, couponArchive, cfg.Coupon.ArchivePrefix, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize coupon history: %w", err)
+	}
+
+	// Initialize pricing engine shared by order creation and price previews
+	pricingEngine := pricing.NewEngine(pricing.Config{
+		Currency:         cfg.Pricing.Currency,
+		ShippingFlatRate: model.MoneyFromCents(int64(cfg.Pricing.ShippingFlatRateCents)),
+	})
+
+	// Product images are uploaded by clients straight to S3, so they are
+	// only managed when an image bucket is configured
+	productServiceOpts := []service.ProductServiceOption{service.WithCatalogueCurrency(cfg.Pricing.Currency)}
+	var productImageService service.ProductImageService
+	if cfg.Images.Bucket != "" {
+		imageStore, err := media.NewS3ImageStore(ctx, media.S3ImageStoreConfig{
+			Bucket:       cfg.Images.Bucket,
+			Region:       cfg.Images.Region,
+			Prefix:       cfg.Images.Prefix,
+			BaseURL:      cfg.Images.BaseURL,
+			UploadExpiry: time.Duration(cfg.Images.UploadExpiry) * time.Second,
+		}, httpClient, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize product image store: %w", err)
+		}
+		productImageRepo := repository.NewProductImageRepository(pool, logger)
+		productServiceOpts = append(productServiceOpts, service.WithProductImages(productImageRepo, imageStore))
+		productImageService = service.NewProductImageService(productImageRepo, productRepo, imageStore, int64(cfg.Images.MaxBytes), logger)
+	}
 
 	// Initialize services
-	productService := service.NewProductService(productRepo, logger)
-	orderService := service.NewOrderService(orderRepo, productRepo, validator, logger)
+	productService := service.NewProductService(productRepo, logger, productServiceOpts...)
+	orderServiceOpts := []service.OrderServiceOption{
+		service.WithCouponReservations(couponReservationRepo),
+		service.WithAllowedSources(cfg.Order.AllowedSources),
+		service.WithStrictOrderFields(cfg.Order.UnknownFields == "reject"),
+		service.WithCouponFailOpen(cfg.Coupon.FailurePolicy == "fail-open"),
+		service.WithPricing(pricingEngine),
+		service.WithIdempotency(idempotencyRepo),
+	}
+	if cfg.Order.IdempotencyKeyTTL > 0 {
+		// Forget idempotency keys once they can no longer be replayed
+		workers.Go(func() {
+			service.RunIdempotencyKeySweeper(ctx, idempotencyRepo, time.Duration(cfg.Order.IdempotencySweepInterval)*time.Second, logger)
+		})
+	}
+	if len(cfg.Webhook.URLs) > 0 {
+		orderServiceOpts = append(orderServiceOpts, service.WithWebhooks(webhookRepo, cfg.Webhook.URLs))
+
+		// Post order events from the outbox to downstream systems
+		dispatcher := webhook.NewDispatcher(webhookRepo, httpClient, webhook.Config{
+			Secret:        []byte(cfg.Webhook.Secret),
+			BatchSize:     cfg.Webhook.BatchSize,
+			PollInterval:  cfg.Webhook.PollInterval,
+			Timeout:       cfg.Webhook.Timeout,
+			MaxAttempts:   cfg.Webhook.MaxAttempts,
+			RetryDelay:    cfg.Webhook.RetryDelay,
+			MaxRetryDelay: cfg.Webhook.MaxRetryDelay,
+		}, logger)
+		workers.Go(func() {
+			dispatcher.Run(ctx)
+		})
+	}
+	if cfg.Saga.Enabled() {
+		var steps []saga.Step
+		if cfg.Saga.PaymentURL != "" {
+			steps = append(steps, saga.NewPaymentStep(cfg.Saga.PaymentURL, httpClient))
+		}
+		if cfg.Saga.StockURL != "" {
+			steps = append(steps, saga.NewStockStep(cfg.Saga.StockURL, httpClient))
+		}
+		if cfg.Saga.CouponURL != "" {
+			steps = append(steps, saga.NewCouponStep(cfg.Saga.CouponURL, httpClient))
+		}
+		coordinator := saga.NewCoordinator(repository.NewSagaRepository(pool, logger), steps, saga.Config{
+			Timeout:       cfg.Saga.Timeout,
+			BatchSize:     cfg.Saga.BatchSize,
+			PollInterval:  cfg.Saga.PollInterval,
+			MaxAttempts:   cfg.Saga.MaxAttempts,
+			RetryDelay:    cfg.Saga.RetryDelay,
+			MaxRetryDelay: cfg.Saga.MaxRetryDelay,
+		}, logger)
+		orderServiceOpts = append(orderServiceOpts, service.WithSagas(coordinator))
+
+		// Compensate sagas whose orders were never stored
+		workers.Go(func() {
+			coordinator.Run(ctx)
+		})
+	}
+	if len(cfg.Events.KafkaBrokers) > 0 {
+		eventOutboxRepo := repository.NewEventOutboxRepository(pool, logger)
+		orderServiceOpts = append(orderServiceOpts, service.WithEvents(eventOutboxRepo))
+
+		// Publish domain events from the outbox to Kafka for analytics
+		eventPublisher := events.NewKafkaPublisher(events.KafkaConfig{
+			Brokers: cfg.Events.KafkaBrokers,
+			Topic:   cfg.Events.KafkaTopic,
+			TLS:     cfg.Events.KafkaTLS,
+		})
+		hooks.Register("event publisher", shutdownHookTimeout, func(context.Context) error {
+			return eventPublisher.Close()
+		})
+		relay := events.NewRelay(eventOutboxRepo, eventPublisher, events.RelayConfig{
+			BatchSize: cfg.Events.RelayBatchSize,
+			Interval:  cfg.Events.RelayInterval,
+			Timeout:   cfg.Events.PublishTimeout,
+		}, logger)
+		workers.Go(func() {
+			relay.Run(ctx)
+		})
+	}
+	var flashSaleService service.FlashSaleService
+	if cfg.FlashSale.Enabled {
+		flashSaleRepo := repository.NewFlashSaleRepository(pool, logger)
+		if len(cfg.FlashSale.CounterProducts) > 0 {
+			// Hot products are reserved on shared counters instead of
+			// queueing every order on their row
+			flashSaleRepo = repository.NewCounterFlashSaleRepository(flashSaleRepo, redisClient, cfg.FlashSale.CounterProducts, logger)
+		}
+		orderServiceOpts = append(orderServiceOpts, service.WithFlashSales(flashSaleRepo))
+		flashSaleService = service.NewFlashSaleService(flashSaleRepo, logger)
+
+		// Count counter reservations as sold
+		workers.Go(func() {
+			service.RunFlashSaleReconciler(ctx, flashSaleService, cfg.FlashSale.ReconcileInterval, cfg.FlashSale.ReconcileBatchSize, logger)
+		})
+	}
+	orderService := service.NewOrderService(orderRepo, productRepo, validator, logger, orderServiceOpts...)
+	operationService := service.NewOperationService(
+		orderService,
+		operationRepo,
+		time.Duration(cfg.Order.AsyncTimeout)*time.Second,
+		logger,
+	)
+	// Orders accepted asynchronously finish before the database pool closes
+	hooks.Register("order operations", shutdownHookTimeout, operationService.Close)
+	shipmentService := service.NewShipmentService(orderRepo, shipmentRepo, logger)
+	timelineService := service.NewTimelineService(orderRepo, orderNoteRepo, shipmentRepo, webhookRepo, logger)
+	orderPricingService := service.NewOrderPricingService(
+		orderRepo,
+		productRepo,
+		orderPricingRepo,
+		couponDiscountRepo,
+		pricingEngine,
+		logger,
+	)
+	pricingService := service.NewPricingService(productRepo, validator, pricingEngine, cfg.Pricing.Currency, logger)
+	priceChangeService := service.NewPriceChangeService(
+		productRepo,
+		priceChangeRepo,
+		notification.NewLogNotifier(logger),
+		float64(cfg.Pricing.ApprovalThreshold),
+		logger,
+	)
+
+	// Show prices in other currencies when exchange rates are configured
+	productHandlerOpts := []handler.ProductHandlerOption{
+		handler.WithCouponPreviews(pricingService),
+		handler.WithSaleApprovals(priceChangeService),
+	}
+	orderHandlerOpts := []handler.OrderHandlerOption{handler.WithMaxInFlight(cfg.Order.MaxInFlight)}
+	if cfg.Pricing.ConvertsCurrencies() {
+		var rates currency.RateSource = currency.NewStaticRates(cfg.Pricing.Rates())
+		if cfg.Pricing.ExchangeRatesURL != "" {
+			rates = currency.NewHTTPRates(cfg.Pricing.ExchangeRatesURL, httpClient, logger)
+		}
+		currencyService := service.NewCurrencyService(cfg.Pricing.Currency, rates, cfg.Pricing.ExchangeRatesTTL, logger)
+		productHandlerOpts = append(productHandlerOpts, handler.WithProductCurrencies(currencyService))
+		orderHandlerOpts = append(orderHandlerOpts, handler.WithOrderCurrencies(currencyService))
+	}
+
+	// Anonymous storefront carts live in signed tokens the client keeps
+	var cartService service.CartService
+	if cfg.Public.CartSecret != "" {
+		cartService = service.NewCartService(pricingService, []byte(cfg.Public.CartSecret), time.Duration(cfg.Public.CartTTL)*time.Second, logger)
+		orderHandlerOpts = append(orderHandlerOpts, handler.WithCartCheckout(cartService))
+	}
 
 	// Initialize HTTP handlers
-	productHandler := handler.NewProductHandler(productService, logger)
-	orderHandler := handler.NewOrderHandler(orderService, logger)
+	productHandler := handler.NewProductHandler(productService, logger, productHandlerOpts...)
+	if len(cfg.Order.AsyncCallers) > 0 {
+		orderHandlerOpts = append(orderHandlerOpts, handler.WithAsyncOrders(operationService, cfg.Order.AsyncCallers))
+	}
+	orderHandler := handler.NewOrderHandler(orderService, logger, orderHandlerOpts...)
+	operationHandler := handler.NewOperationHandler(operationService, logger)
+	priceChangeHandler := handler.NewPriceChangeHandler(priceChangeService, logger)
+	pricingHandler := handler.NewPricingHandler(pricingService, logger)
+	publicHandler := handler.NewPublicHandler(productService,
+		time.Duration(cfg.Public.CacheMaxAge)*time.Second, time.Duration(cfg.Public.CDNMaxAge)*time.Second, logger)
+	shipmentHandler := handler.NewShipmentHandler(shipmentService, logger)
+	timelineHandler := handler.NewTimelineHandler(timelineService, logger)
+	orderPricingHandler := handler.NewOrderPricingHandler(orderPricingService, logger)
+	customerHandler := handler.NewCustomerHandler(service.NewCustomerService(customerRepo, orderService, logger), logger)
+	upsellService := service.NewUpsellService(
+		repository.NewUpsellRepository(pool, logger),
+		model.UpsellLimits{
+			Limit:       cfg.Upsell.DefaultLimit,
+			PerCategory: cfg.Upsell.PerCategoryLimit,
+			SalesWindow: time.Duration(cfg.Upsell.SalesWindowDays) * 24 * time.Hour,
+		},
+		cfg.Upsell.MaxLimit,
+		cfg.Pricing.Currency,
+		logger,
+	)
+	upsellHandler := handler.NewUpsellHandler(upsellService, logger)
+
+	// Initialize dependency health monitor
+	healthMonitor := health.NewMonitor(&health.MonitorConfig{
+		Interval:          time.Duration(cfg.Health.ProbeInterval) * time.Second,
+		Timeout:           time.Duration(cfg.Health.ProbeTimeout) * time.Second,
+		HistorySize:       cfg.Health.HistorySize,
+		FailureThreshold:  cfg.Health.FailureThreshold,
+		RecoveryThreshold: cfg.Health.RecoveryThreshold,
+		FlapThreshold:     cfg.Health.FlapThreshold,
+	}, logger, health.NewPingChecker("database", pool))
+	// Hold readiness back until coupon codes loading in the background have loaded
+	healthMonitor.WaitFor("coupons", validator.Ready)
+	workers.Go(func() {
+		healthMonitor.Run(ctx)
+	})
+
+	healthHandler := handler.NewHealthHandler(healthMonitor, logger)
+
+	// Initialize the metrics endpoints and the API route registry. Cache hit
+	// rates are also logged at each report interval, unless it is zero
+	cacheReporter := metrics.NewCacheReporter(counters, time.Duration(cfg.Metrics.CacheReportInterval)*time.Second, logger)
+	if cfg.Metrics.CacheReportInterval > 0 {
+		workers.Go(func() {
+			cacheReporter.Run(ctx)
+		})
+	}
+	metricsHandler := handler.NewMetricsHandler(counters, cacheReporter, logger)
+	logLevelHandler := handler.NewLogLevelHandler(logger)
+	tenantHandler := handler.NewTenantHandler(service.NewTenantService(tenantRepo, logger), logger)
+	couponFileHandler := handler.NewCouponFileHandler(service.NewCouponFileService(couponFileRepo, couponHistory, logger), logger)
+	routes := newRouteRegistry(cfg.API)
+	adminHandler := handler.NewAdminHandler(productService, orderService, validator, counters, logger)
+	couponReloadHandler := handler.NewCouponReloadHandler(validator, logger)
+	configHandler := handler.NewConfigHandler(cfg.Redacted(), logger)
 
 	// Initialize router
-	mux := router.New(productHandler, orderHandler, cfg.Auth.APIKey, logger)
+	routerOpts := []router.Option{
+		router.WithHealthHandler(healthHandler),
+		router.WithPriceChangeHandler(priceChangeHandler),
+		router.WithPricingHandler(pricingHandler),
+		router.WithPublicHandler(publicHandler),
+		router.WithShipmentHandler(shipmentHandler),
+		router.WithTimelineHandler(timelineHandler),
+		router.WithOrderPricingHandler(orderPricingHandler),
+		router.WithOperationHandler(operationHandler),
+		router.WithCustomerHandler(customerHandler),
+		router.WithUpsellHandler(upsellHandler),
+		router.WithMetricsHandler(metricsHandler),
+		router.WithLogLevelHandler(logLevelHandler),
+		router.WithTenantHandler(tenantHandler),
+		router.WithCouponFileHandler(couponFileHandler),
+		router.WithAdminHandler(adminHandler),
+		router.WithCouponReloadHandler(couponReloadHandler),
+		router.WithConfigHandler(configHandler),
+		router.WithDeprecations(routes, counters),
+		router.WithRequestLimits(cfg.Server.MaxBodyBytes, cfg.Server.RequestTimeout),
+	}
+	if len(cfg.Auth.AdminKeys) > 0 {
+		routerOpts = append(routerOpts, router.WithAdminKeys(cfg.Auth.AdminKeyNames()))
+	} else {
+		logger.Warn().Msg("no admin API keys configured, admin routes under /api/admin/ are unreachable")
+	}
+	if cfg.Auth.AdminOnlyProductWrites {
+		routerOpts = append(routerOpts, router.WithAdminOnlyProductWrites())
+	}
+	if len(cfg.Auth.TenantKeys) > 0 {
+		routerOpts = append(routerOpts, router.WithTenantKeys(cfg.Auth.TenantKeyTenants()))
+	}
+	if cfg.RateLimit.Requests > 0 {
+		window := time.Duration(cfg.RateLimit.Window) * time.Second
+		limiter := middleware.NewRateLimiter(cfg.RateLimit.Requests, window)
+		if redisClient != nil {
+			limiter = middleware.NewSharedRateLimiter(cfg.RateLimit.Requests, window, redisClient, logger)
+		}
+		routerOpts = append(routerOpts, router.WithRateLimit(limiter))
+	}
+	if cfg.Quota.MonthlyRequests > 0 || cfg.Quota.MonthlyOrders > 0 {
+		quotas := middleware.NewQuotaTracker(cfg.Quota.MonthlyRequests, cfg.Quota.MonthlyOrders, cfg.Quota.Enforce)
+		quotaNotifier := notification.NewLogNotifier(logger)
+		if cfg.Quota.WebhookURL != "" {
+			quotaNotifier = notification.NewWebhookNotifier(cfg.Quota.WebhookURL, httpClient, logger)
+		}
+		routerOpts = append(routerOpts, router.WithQuotas(quotas, quotaNotifier))
+	}
+	if cfg.Snapshot.SigningKey != "" {
+		// Signed order snapshots for dispute evidence, stored under S3
+		// Object Lock when a bucket is configured
+		var archive export.SnapshotArchive
+		if cfg.Snapshot.Bucket != "" {
+			archive, err = export.NewS3SnapshotArchive(ctx, export.SnapshotArchiveConfig{
+				Bucket:    cfg.Snapshot.Bucket,
+				Region:    cfg.Snapshot.Region,
+				Prefix:    cfg.Snapshot.Prefix,
+				LockMode:  cfg.Snapshot.LockMode,
+				Retention: time.Duration(cfg.Snapshot.RetentionDays) * 24 * time.Hour,
+			}, httpClient, logger)
+			if err != nil {
+				return fmt.Errorf("failed to initialize snapshot archive: %w", err)
+			}
+		}
+		snapshotService := service.NewSnapshotService(
+			orderService,
+			timelineService,
+			orderRepo,
+			shipmentRepo,
+			orderPricingRepo,
+			archive,
+			[]byte(cfg.Snapshot.SigningKey),
+			logger,
+		)
+		routerOpts = append(routerOpts, router.WithSnapshotHandler(handler.NewSnapshotHandler(snapshotService, logger)))
+	}
+	if cartService != nil {
+		routerOpts = append(routerOpts, router.WithCartHandler(handler.NewCartHandler(cartService, logger)))
+	}
+	if productImageService != nil {
+		routerOpts = append(routerOpts, router.WithProductImageHandler(handler.NewProductImageHandler(productImageService, logger)))
+	}
+	if flashSaleService != nil {
+		routerOpts = append(routerOpts, router.WithFlashSaleHandler(handler.NewFlashSaleHandler(flashSaleService, logger)))
+	}
+	slas := model.OrderSLAs{}
+	if cfg.Order.PendingSLA > 0 {
+		slas[model.OrderStatusPending] = time.Duration(cfg.Order.PendingSLA) * time.Second
+	}
+	if cfg.Order.ConfirmedSLA > 0 {
+		slas[model.OrderStatusConfirmed] = time.Duration(cfg.Order.ConfirmedSLA) * time.Second
+	}
+	if len(slas) > 0 {
+		// Orders stuck in pending/confirmed are listed for admins and
+		// alerted on once per breach
+		slaNotifier := notification.NewLogNotifier(logger)
+		if cfg.Order.SLAWebhookURL != "" {
+			slaNotifier = notification.NewWebhookNotifier(cfg.Order.SLAWebhookURL, httpClient, logger)
+		}
+		slaService := service.NewSLAService(
+			repository.NewOrderSLARepository(pool, logger),
+			slas,
+			float64(cfg.Order.SLAWarningPercent)/100,
+			counters,
+			slaNotifier,
+			logger,
+		)
+		workers.Go(func() {
+			service.RunSLAChecks(ctx, slaService, time.Duration(cfg.Order.SLACheckInterval)*time.Second, logger)
+		})
+		routerOpts = append(routerOpts, router.WithSLAHandler(handler.NewSLAHandler(slaService, logger)))
+	}
+	if cfg.Order.ImportEnabled {
+		orderImportService := service.NewOrderImportService(orderRepo, productRepo, logger)
+		routerOpts = append(routerOpts, router.WithOrderImportHandler(handler.NewOrderImportHandler(orderImportService, logger)))
+		logger.Warn().Msg("historical order import is enabled; disable ORDER_IMPORT_ENABLED once the migration is done")
+	}
+	if cfg.API.DocsUI {
+		routerOpts = append(routerOpts, router.WithSwaggerUI())
+	}
+	if cfg.Shadow.TargetURL != "" {
+		// Validated as an http or https URL by config.Validate
+		target, _ := url.Parse(cfg.Shadow.TargetURL)
+		shadower := middleware.NewShadower(middleware.NewShadowProxy(target, httpClient.Transport), middleware.ShadowConfig{
+			Percent:       cfg.Shadow.Percent,
+			Timeout:       cfg.Shadow.Timeout,
+			MaxConcurrent: cfg.Shadow.MaxConcurrent,
+			MaxBodyBytes:  cfg.Shadow.MaxBodyBytes,
+			IgnoreFields:  cfg.Shadow.IgnoreFields,
+		}, counters, logger)
+		hooks.Register("shadow traffic", shutdownHookTimeout, shadower.Close)
+		routerOpts = append(routerOpts, router.WithShadow(shadower))
+		logger.Info().
+			Str("target", target.Scheme+"://"+target.Host).
+			Float64("percent", cfg.Shadow.Percent).
+			Msg("mirroring read traffic to shadow target")
+	}
+	mux := router.New(productHandler, orderHandler, cfg.Auth.APIKey, logger, routerOpts...)
+
+	if redisClient != nil {
+		hooks.Register("redis client", shutdownHookTimeout, func(context.Context) error {
+			return redisClient.Close()
+		})
+	}
+
+	// The database pool outlives every component that queries it
+	hooks.Register("database pool", shutdownHookTimeout, func(context.Context) error {
+		pool.Close()
+		return nil
+	})
 
 	// Create HTTP server
 	server := &http.Server{
-		Addr:         cfg.Server.Address(),
-		Handler:      mux,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:              cfg.Server.Address(),
+		Handler:           mux,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+
+	// Configure TLS (and optional mutual TLS) if enabled
+	if cfg.TLS.Enabled {
+		tlsConfig, err := config.NewServerTLSConfig(cfg.TLS)
+		if err != nil {
+			return fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		server.TLSConfig = tlsConfig
 	}
 
 	// Channel to listen for errors from the server
@@ -107,26 +713,37 @@ func run() error {
 	go func() {
 		logger.Info().
 			Str("address", cfg.Server.Address()).
+			Bool("tls", cfg.TLS.Enabled).
+			Str("client_auth", cfg.TLS.ClientAuth).
 			Msg("HTTP server started")
+		if cfg.TLS.Enabled {
+			// Certificates are already loaded into server.TLSConfig
+			serverErrors <- server.ListenAndServeTLS("", "")
+			return
+		}
 		serverErrors <- server.ListenAndServe()
 	}()
 
 	// Channel to listen for interrupt signals
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	// Block until we receive a signal or an error
 	select {
 	case err := <-serverErrors:
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+
+		_ = hooks.Run(shutdownCtx)
 		return fmt.Errorf("server error: %w", err)
 
-	case sig := <-shutdown:
+	case sig := <-stop:
 		logger.Info().
 			Str("signal", sig.String()).
 			Msg("shutdown signal received, starting graceful shutdown")
 
 		// Create a context with timeout for shutdown
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer shutdownCancel()
 
 		// Attempt graceful shutdown
@@ -136,11 +753,88 @@ func run() error {
 			if closeErr := server.Close(); closeErr != nil {
 				logger.Error().Err(closeErr).Msg("failed to close server")
 			}
+			_ = hooks.Run(shutdownCtx)
 			return fmt.Errorf("server shutdown failed: %w", err)
 		}
 
+		// Release resources once in-flight requests have finished
+		if err := hooks.Run(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("shutdown hooks failed")
+		}
+
 		logger.Info().Msg("server shutdown completed")
 	}
 
 	return nil
 }
+
+// newStorageCouponLoader creates a coupon loader for the configured object
+// storage provider. S3 and Azure share the pooled HTTP client; the GCS client
+// authenticates its own transport.
+func newStorageCouponLoader(ctx context.Context, cfg *config.Config, httpClient *http.Client, download coupon.S3DownloadConfig, logger zerolog.Logger) (coupon.Loader, error) {
+	switch cfg.Coupon.StorageProvider {
+	case coupon.StorageProviderGCS:
+		return coupon.NewGCSLoader(ctx, cfg.GCS.Bucket, logger)
+	case coupon.StorageProviderAzure:
+		return coupon.NewAzureBlobLoader(ctx, cfg.Azure.AccountURL, cfg.Azure.Container, httpClient, logger)
+	default:
+		return coupon.NewS3Loader(ctx, cfg.S3.Bucket, cfg.S3.Region, httpClient, download, logger)
+	}
+}
+
+// newStorageCouponSource creates an ObjectSource for the configured object
+// storage provider.
+func newStorageCouponSource(ctx context.Context, cfg *config.Config, httpClient *http.Client, download coupon.S3DownloadConfig, logger zerolog.Logger) (coupon.ObjectSource, error) {
+	switch cfg.Coupon.StorageProvider {
+	case coupon.StorageProviderGCS:
+		return coupon.NewGCSSource(ctx, cfg.GCS.Bucket, logger)
+	case coupon.StorageProviderAzure:
+		return coupon.NewAzureBlobSource(ctx, cfg.Azure.AccountURL, cfg.Azure.Container, httpClient, logger)
+	default:
+		return coupon.NewS3Source(ctx, cfg.S3.Bucket, cfg.S3.Region, httpClient, download, logger)
+	}
+}
+
+// newCachingCouponLoader creates a coupon loader that caches object storage
+// files on local disk. The cache is returned so its index can be flushed at
+// shutdown.
+func newCachingCouponLoader(ctx context.Context, cfg *config.Config, fileLoader coupon.Loader, httpClient *http.Client, download coupon.S3DownloadConfig, logger zerolog.Logger) (coupon.Loader, *coupon.FileCache, error) {
+	source, err := newStorageCouponSource(ctx, cfg, httpClient, download, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache, err := coupon.NewFileCache(coupon.FileCacheConfig{
+		Dir:      cfg.Cache.Dir,
+		MaxBytes: int64(cfg.Cache.MaxMB) * 1024 * 1024,
+		MaxFiles: cfg.Cache.MaxFiles,
+		MaxAge:   time.Duration(cfg.Cache.MaxAge) * time.Second,
+		Metrics:  download.Metrics,
+	}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coupon.NewCachingLoader(source, cache, fileLoader, logger), cache, nil
+}
+
+// newRouteRegistry registers the versioned API as current and, once a
+// deprecation date is configured, marks the unversioned /api/* paths deprecated.
+func newRouteRegistry(cfg config.APIConfig) *middleware.RouteRegistry {
+	routes := middleware.NewRouteRegistry()
+	routes.Register(middleware.Route{Prefix: router.VersionPrefix})
+
+	if !cfg.UnversionedDeprecated.IsZero() {
+		routes.Register(middleware.Route{
+			Prefix: "/api/",
+			Deprecation: &middleware.DeprecationPolicy{
+				Deprecated: cfg.UnversionedDeprecated,
+				Sunset:     cfg.UnversionedSunset,
+				Successor:  router.VersionPrefix,
+				Link:       cfg.DeprecationLink,
+			},
+		})
+	}
+
+	return routes
+}
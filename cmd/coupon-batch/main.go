@@ -0,0 +1,259 @@
+// Command coupon-batch validates a list of promo codes offline, so marketing
+// can check a campaign list before it goes out without sending every code
+// through the API. The coupon files are loaded once, with the API's coupon
+// configuration (COUPON_FILES, COUPON_MIN_MATCH_COUNT, COUPON_FILE_WEIGHTS,
+// COUPON_CASE_INSENSITIVE, ...), and each code is checked by the same
+// validator as checkout.
+//
+// The input is a CSV of code and context rows, with an optional header row
+// starting with "code". The context, e.g. the campaign or audience a code is
+// sent to, is copied to the results unchanged. The results CSV has a row per
+// input row, in input order:
+//
+//	code,context,valid,reason,match_count
+//	SUMMER2025,newsletter,true,,2
+//	WINTER2025,newsletter,false,INVALID_PROMO_CODE,1
+//
+// Coupon files are read from the local file system, or as coupon indexes
+// with COUPON_SOURCE=index; download files kept in object storage first.
+//
+// Usage:
+//
+//	go run ./cmd/coupon-batch -out results.csv campaign.csv
+//	go run ./cmd/coupon-batch < campaign.csv > results.csv
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"mini-kart/internal/config"
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// batchSize is the number of rows validated concurrently before their
+// results are written, bounding memory use for long lists.
+const batchSize = 4096
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	outPath := flag.String("out", "", "File to write results to (default: standard output)")
+	workers := flag.Int("workers", runtime.NumCPU(), "Codes validated concurrently")
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		return fmt.Errorf("at most one input file is allowed")
+	}
+	if *workers < 1 {
+		return fmt.Errorf("-workers must be at least 1")
+	}
+
+	in := io.Reader(os.Stdin)
+	if path := flag.Arg(0); path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	out := io.Writer(os.Stdout)
+	var outFile *os.File
+	if *outPath != "" {
+		var err error
+		outFile, err = os.Create(*outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		defer outFile.Close()
+		out = outFile
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger().Level(zerolog.InfoLevel)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	validator, err := newValidator(ctx, config.LoadCoupon(), logger)
+	if err != nil {
+		return err
+	}
+	defer validator.Close()
+	logger.Info().Dur("took", time.Since(start).Round(time.Millisecond)).Msg("coupon files loaded")
+
+	start = time.Now()
+	summary, err := validateCSV(ctx, validator, in, out, *workers)
+	if err != nil {
+		return err
+	}
+	if outFile != nil {
+		if err := outFile.Close(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Validated %d codes (%d valid, %d invalid) in %s\n",
+		summary.Valid+summary.Invalid, summary.Valid, summary.Invalid, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// newValidator loads the configured local coupon files or indexes into a
+// validator. Lookups are not timed out, since no checkout is waiting on them.
+func newValidator(ctx context.Context, cfg config.CouponConfig, logger zerolog.Logger) (coupon.Validator, error) {
+	var loader coupon.Loader
+	switch cfg.Source {
+	case "", "file":
+		loader = coupon.NewFileLoader(logger)
+	case "index":
+		loader = coupon.NewIndexLoader(cfg.IndexDir, logger)
+	default:
+		return nil, fmt.Errorf("COUPON_SOURCE=%s is not supported: download the coupon files and use file or index", cfg.Source)
+	}
+
+	validatorConfig := coupon.DefaultValidatorConfig()
+	if len(cfg.Files) > 0 {
+		validatorConfig.FilePaths = cfg.Files
+	}
+	validatorConfig.MinMatchCount = cfg.MinMatchCount
+	validatorConfig.Weights = cfg.MatchWeights()
+	validatorConfig.SetType = cfg.SetType
+	validatorConfig.ExpectedCoupons = cfg.ExpectedCodes
+	validatorConfig.FalsePositiveRate = cfg.FalsePositiveRate
+	validatorConfig.CodePattern = cfg.CodePattern
+	validatorConfig.CaseInsensitive = cfg.CaseInsensitive
+	if cfg.DeltaDir != "" {
+		validatorConfig.Deltas = coupon.NewDirDeltaSource(cfg.DeltaDir, logger)
+	}
+
+	validator, err := coupon.NewValidator(ctx, validatorConfig, loader, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coupon files: %w", err)
+	}
+	return validator, nil
+}
+
+// summary counts the outcomes of a batch.
+type summary struct {
+	Valid   int
+	Invalid int
+}
+
+// row is a code to validate and the outcome of validating it.
+type row struct {
+	code    string
+	context string
+	result  model.CouponValidation
+}
+
+// validateCSV validates the codes of a CSV of code and context rows and
+// writes a results CSV, validating up to workers codes at once. Results are
+// written in input order.
+func validateCSV(ctx context.Context, validator coupon.CodeValidator, in io.Reader, out io.Writer, workers int) (summary, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"code", "context", "valid", "reason", "match_count"}); err != nil {
+		return summary{}, fmt.Errorf("failed to write output: %w", err)
+	}
+
+	var result summary
+	batch := make([]row, 0, batchSize)
+
+	// flush validates the batch and writes its results
+	flush := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		validateRows(ctx, validator, batch, workers)
+		for _, r := range batch {
+			if r.result.Valid {
+				result.Valid++
+			} else {
+				result.Invalid++
+			}
+			record := []string{r.code, r.context, strconv.FormatBool(r.result.Valid), r.result.Reason, strconv.Itoa(r.result.MatchCount)}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read input: %w", err)
+		}
+		if len(record) > 2 {
+			line, _ := reader.FieldPos(0)
+			return result, fmt.Errorf("line %d: expected code and context, got %d fields", line, len(record))
+		}
+
+		code := strings.TrimSpace(record[0])
+		if first && strings.EqualFold(code, "code") {
+			continue
+		}
+		next := row{code: code}
+		if len(record) == 2 {
+			next.context = record[1]
+		}
+
+		batch = append(batch, next)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return result, fmt.Errorf("failed to write output: %w", err)
+	}
+	return result, nil
+}
+
+// validateRows validates every row, spreading the rows over workers.
+func validateRows(ctx context.Context, validator coupon.CodeValidator, rows []row, workers int) {
+	var wg sync.WaitGroup
+	for w := range min(workers, len(rows)) {
+		wg.Go(func() {
+			for i := w; i < len(rows); i += workers {
+				rows[i].result = validator.Check(ctx, rows[i].code)
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/config"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCouponFile writes a gzipped coupon file of the given codes.
+func writeCouponFile(t *testing.T, dir, name string, codes ...string) string {
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	_, err = gz.Write([]byte(strings.Join(codes, "\n") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return path
+}
+
+func TestValidateCSV(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.CouponConfig{
+		Source: "file",
+		Files: []string{
+			writeCouponFile(t, dir, "couponbase1.gz", "HAPPYHRS", "FIFTYOFF"),
+			writeCouponFile(t, dir, "couponbase2.gz", "HAPPYHRS"),
+			writeCouponFile(t, dir, "couponbase3.gz", "FIFTYOFF", "SUPER100"),
+		},
+		MinMatchCount: 2,
+		ExpectedCodes: 16,
+		CodePattern:   `^[A-Za-z0-9]*$`,
+	}
+
+	validator, err := newValidator(context.Background(), cfg, zerolog.Nop())
+	require.NoError(t, err)
+	defer validator.Close()
+
+	in := strings.Join([]string{
+		"code,context",
+		"HAPPYHRS,newsletter",
+		"SUPER100,newsletter",
+		"FIFTYOFF",
+		"SHORT,\"vip, spring\"",
+		"BAD-CODE!,vip",
+	}, "\n")
+
+	var out bytes.Buffer
+	summary, err := validateCSV(context.Background(), validator, strings.NewReader(in), &out, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Valid)
+	assert.Equal(t, 3, summary.Invalid)
+	assert.Equal(t, strings.Join([]string{
+		"code,context,valid,reason,match_count",
+		"HAPPYHRS,newsletter,true,,2",
+		"SUPER100,newsletter,false,INVALID_PROMO_CODE,1",
+		"FIFTYOFF,,true,,2",
+		"SHORT,\"vip, spring\",false,INVALID_PROMO_LENGTH,0",
+		"BAD-CODE!,vip,false,INVALID_PROMO_FORMAT,0",
+	}, "\n")+"\n", out.String())
+}
+
+func TestValidateCSV_InvalidInput(t *testing.T) {
+	var out bytes.Buffer
+	_, err := validateCSV(context.Background(), nil, strings.NewReader("HAPPYHRS,newsletter,extra"), &out, 1)
+	assert.ErrorContains(t, err, "line 1: expected code and context, got 3 fields")
+}
+
+func TestNewValidator_UnsupportedSource(t *testing.T) {
+	_, err := newValidator(context.Background(), config.CouponConfig{Source: "db"}, zerolog.Nop())
+	assert.ErrorContains(t, err, "COUPON_SOURCE=db is not supported")
+}
@@ -0,0 +1,59 @@
+// Command coupon-index converts gzipped coupon files into sorted coupon
+// indexes for deployments running with COUPON_SOURCE=index. The API maps
+// the indexes instead of loading every code into memory, so it starts in
+// moments rather than minutes. Each index is named after its coupon set,
+// e.g. couponbase1.gz is indexed as couponbase1.idx, and written next to the
+// coupon file unless -out is given. Deployments matching promo codes
+// case-insensitively (COUPON_CASE_INSENSITIVE) index them with -fold-case.
+//
+// Usage:
+//
+//	go run ./cmd/coupon-index data/coupons/couponbase1.gz data/coupons/couponbase2.gz
+//	go run ./cmd/coupon-index -out /var/lib/mini-kart/coupons data/coupons/*.gz
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mini-kart/internal/coupon"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	outDir := flag.String("out", "", "Directory to write indexes to (default: next to each coupon file)")
+	foldCase := flag.Bool("fold-case", false, "Index codes upper-cased, for case-insensitive matching")
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		return fmt.Errorf("at least one coupon file is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, file := range files {
+		dst := coupon.IndexPath(*outDir, file)
+		start := time.Now()
+
+		count, err := coupon.BuildIndex(ctx, file, dst, *foldCase)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Indexed %d codes from %s into %s in %s\n", count, file, dst, time.Since(start).Round(time.Millisecond))
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Command couponimport loads gzipped coupon files into the coupon_codes table
+// for deployments running with COUPON_SOURCE=db. Each file replaces the coupon
+// set named after it, e.g. couponbase1.gz is imported as "couponbase1".
+//
+// Usage:
+//
+//	go run ./cmd/couponimport data/coupons/couponbase1.gz data/coupons/couponbase2.gz
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"mini-kart/internal/config"
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/database"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	setName := flag.String("set", "", "Coupon set name (only with a single file; defaults to the file name without .gz)")
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		return fmt.Errorf("at least one coupon file is required")
+	}
+	if *setName != "" && len(files) > 1 {
+		return fmt.Errorf("-set can only be used with a single file")
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	ctx := context.Background()
+
+	pool, err := database.NewPool(ctx, config.LoadDatabase(), logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	repo := repository.NewCouponCodeRepository(pool, logger)
+
+	for _, file := range files {
+		name := coupon.SetName(file)
+		if *setName != "" {
+			name = *setName
+		}
+
+		count, err := importFile(ctx, repo, file, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d codes from %s into coupon set %s\n", count, file, name)
+	}
+
+	return nil
+}
+
+// importFile replaces a coupon set with the codes in a gzipped coupon file.
+func importFile(ctx context.Context, repo repository.CouponCodeRepository, path, set string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open coupon file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create gzip reader for %s: %w", path, err)
+	}
+	defer gzipReader.Close()
+
+	scanner := bufio.NewScanner(gzipReader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	// next yields non-blank lines, matching the file loader
+	next := func() (string, bool, error) {
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				return line, true, nil
+			}
+		}
+		return "", false, scanner.Err()
+	}
+
+	count, err := repo.ReplaceSet(ctx, set, next)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import %s: %w", path, err)
+	}
+
+	return count, nil
+}
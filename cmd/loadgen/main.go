@@ -0,0 +1,116 @@
+// Command loadgen drives synthetic traffic against a mini-kart deployment at a
+// fixed request rate and reports latency histograms per request type, for
+// soak testing an environment before a release. Traffic is a weighted mix of
+// catalogue reads, orders, orders with a valid coupon and orders with an
+// unknown coupon code. It exits non-zero when the share of unexpected
+// responses exceeds -max-error-rate.
+//
+// Usage:
+//
+//	go run ./cmd/loadgen -base-url https://staging.example.com -rps 50 -duration 2h
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	baseURL := flag.String("base-url", os.Getenv("LOADGEN_BASE_URL"), "Base URL of the deployment, e.g. https://api.example.com")
+	apiKey := flag.String("api-key", os.Getenv("LOADGEN_API_KEY"), "API key for the deployment")
+	couponCode := flag.String("coupon", os.Getenv("LOADGEN_COUPON_CODE"), "Valid coupon code; couponed orders are left out of the mix when empty")
+	rps := flag.Float64("rps", 10, "Requests per second")
+	duration := flag.Duration("duration", time.Minute, "How long to run; 0 runs until interrupted")
+	mixFlag := flag.String("mix", defaultMix, "Traffic mix as comma-separated kind=weight pairs")
+	concurrency := flag.Int("concurrency", 100, "Maximum requests in flight; requests beyond it are dropped and counted")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout per request")
+	interval := flag.Duration("report-interval", 30*time.Second, "How often to print progress; 0 disables progress lines")
+	maxErrorRate := flag.Float64("max-error-rate", 0.01, "Fraction of unexpected responses tolerated before exiting non-zero")
+	jsonPath := flag.String("json", "", "Write a JSON report to this path")
+	flag.Parse()
+
+	if *baseURL == "" || *apiKey == "" {
+		return fmt.Errorf("base URL and API key are required")
+	}
+	if *rps <= 0 {
+		return fmt.Errorf("rps must be positive")
+	}
+	if *concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+
+	weights, err := parseMix(*mixFlag)
+	if err != nil {
+		return err
+	}
+	if *couponCode == "" && weights[kindCouponOrder] > 0 {
+		fmt.Fprintln(os.Stderr, "No coupon code configured; couponed orders are left out of the mix")
+		delete(weights, kindCouponOrder)
+	}
+	m, err := newMix(weights)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := newClient(*baseURL, *apiKey, *timeout, *concurrency)
+	products, err := c.ProductIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list products: %w", err)
+	}
+	if len(products) == 0 {
+		return fmt.Errorf("catalogue is empty, nothing to order")
+	}
+
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	g := &generator{
+		client:     c,
+		mix:        m,
+		products:   products,
+		couponCode: *couponCode,
+		stats:      newStats(),
+		inFlight:   make(chan struct{}, *concurrency),
+	}
+
+	fmt.Printf("Sending %.1f requests/s to %s (mix %s)\n", *rps, *baseURL, m)
+	if *interval > 0 {
+		go g.progress(ctx, *interval)
+	}
+	g.run(ctx, *rps)
+
+	rep := g.stats.report()
+	rep.BaseURL = *baseURL
+	rep.TargetRPS = *rps
+	rep.writeText(os.Stdout)
+
+	if *jsonPath != "" {
+		if err := rep.writeJSON(*jsonPath); err != nil {
+			return err
+		}
+	}
+
+	if rep.ErrorRate > *maxErrorRate {
+		return fmt.Errorf("error rate %.2f%% exceeds %.2f%%", rep.ErrorRate*100, *maxErrorRate*100)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the endpoints the load generator calls. Orders with
+// validCoupon are accepted; any other coupon is rejected as invalid.
+func fakeAPI(t *testing.T, validCoupon string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]model.Product{{ID: "P001"}, {ID: "P002"}})
+	})
+	mux.HandleFunc("GET /api/v1/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(model.Product{ID: r.PathValue("id")})
+	})
+	mux.HandleFunc("POST /api/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("X-API-Key"))
+		var req model.OrderRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.CouponCode != nil && *req.CouponCode != validCoupon {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid promo code"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(model.OrderResponse{ID: uuid.New()})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runGenerator sends traffic with an even mix for a short while.
+func runGenerator(t *testing.T, server *httptest.Server, couponCode string) *report {
+	weights, err := parseMix("read=1,order=1,coupon-order=1,invalid-coupon=1")
+	require.NoError(t, err)
+	m, err := newMix(weights)
+	require.NoError(t, err)
+
+	c := newClient(server.URL, "test-key", time.Second, 10)
+	products, err := c.ProductIDs(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"P001", "P002"}, products)
+
+	g := &generator{
+		client:     c,
+		mix:        m,
+		products:   products,
+		couponCode: couponCode,
+		stats:      newStats(),
+		inFlight:   make(chan struct{}, 10),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	g.run(ctx, 400)
+
+	return g.stats.report()
+}
+
+func TestGenerator_Run(t *testing.T) {
+	t.Run("Healthy deployment", func(t *testing.T) {
+		rep := runGenerator(t, fakeAPI(t, "HAPPYHRS"), "HAPPYHRS")
+
+		assert.Greater(t, rep.Requests, int64(20))
+		assert.Zero(t, rep.Errors)
+		assert.Zero(t, rep.ErrorRate)
+		require.Len(t, rep.Kinds, 4)
+		for _, k := range rep.Kinds {
+			assert.Positive(t, k.Requests, k.Kind)
+			assert.Equal(t, k.Requests, k.Statuses[strconv.Itoa(expectedStatus[k.Kind])], k.Kind)
+		}
+	})
+
+	t.Run("Rejected coupon counts as an error", func(t *testing.T) {
+		rep := runGenerator(t, fakeAPI(t, "HAPPYHRS"), "EXPIRED1")
+
+		for _, k := range rep.Kinds {
+			if k.Kind == kindCouponOrder {
+				assert.Equal(t, k.Requests, k.Errors)
+				assert.Equal(t, k.Requests, k.Statuses["400"])
+			} else {
+				assert.Zero(t, k.Errors, k.Kind)
+			}
+		}
+		assert.Greater(t, rep.ErrorRate, 0.0)
+	})
+}
+
+func TestParseMix(t *testing.T) {
+	weights, err := parseMix(defaultMix)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{kindRead: 70, kindOrder: 15, kindCouponOrder: 10, kindInvalidCoupon: 5}, weights)
+
+	m, err := newMix(map[string]int{kindRead: 3, kindOrder: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "read 75%, order 25%", m.String())
+
+	for _, input := range []string{"read", "read=x", "read=-1", "browse=10"} {
+		_, err := parseMix(input)
+		assert.Error(t, err, input)
+	}
+
+	_, err = newMix(map[string]int{kindRead: 0})
+	assert.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	var h histogram
+	for i := 0; i < 90; i++ {
+		h.observe(3 * time.Millisecond)
+	}
+	for i := 0; i < 9; i++ {
+		h.observe(80 * time.Millisecond)
+	}
+	h.observe(30 * time.Second)
+
+	assert.Equal(t, 5*time.Millisecond, h.quantile(0.50))
+	assert.Equal(t, 100*time.Millisecond, h.quantile(0.95))
+	assert.Equal(t, 100*time.Millisecond, h.quantile(0.99))
+	assert.Equal(t, 30*time.Second, h.quantile(1))
+	assert.Equal(t, int64(1), h.counts[len(latencyBuckets)])
+
+	var empty histogram
+	assert.Zero(t, empty.quantile(0.95))
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	s := newStats()
+	s.record(kindRead, 4*time.Millisecond, http.StatusOK, true)
+	s.record(kindOrder, 4*time.Millisecond, 0, false)
+	s.drop(kindOrder)
+
+	rep := s.report()
+	assert.Equal(t, int64(2), rep.Requests)
+	assert.Equal(t, int64(1), rep.Errors)
+	assert.Equal(t, int64(1), rep.Dropped)
+	assert.InDelta(t, 2.0/3.0, rep.ErrorRate, 0.001)
+
+	path := filepath.Join(t.TempDir(), "loadgen.json")
+	require.NoError(t, rep.writeJSON(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 4.0, decoded["p50Ms"])
+	kinds := decoded["kinds"].([]any)
+	require.Len(t, kinds, 2)
+	assert.Equal(t, map[string]any{"no response": 1.0}, kinds[1].(map[string]any)["statuses"])
+}
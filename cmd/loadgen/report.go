@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the histogram bucket upper bounds. A final overflow
+// bucket counts anything slower.
+var latencyBuckets = [...]time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// histogram counts latencies in fixed buckets, so memory stays constant
+// however long a soak test runs.
+type histogram struct {
+	counts [len(latencyBuckets) + 1]int64 // the last is the overflow bucket
+	count  int64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// observe records one latency.
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += d
+	h.max = max(h.max, d)
+}
+
+// merge adds other's observations to h.
+func (h *histogram) merge(other *histogram) {
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+	h.count += other.count
+	h.sum += other.sum
+	h.max = max(h.max, other.max)
+}
+
+// quantile returns the upper bound of the bucket holding the q quantile, or
+// the slowest latency when it falls in the overflow bucket. Quantiles are
+// therefore conservative to bucket resolution.
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	// Nearest rank, zero-based
+	rank := int64(math.Ceil(q*float64(h.count))) - 1
+	rank = min(max(rank, 0), h.count-1)
+
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen > rank {
+			if i < len(latencyBuckets) {
+				return min(latencyBuckets[i], h.max)
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// kindStats holds the outcomes of one request kind.
+type kindStats struct {
+	latency  histogram
+	errors   int64
+	dropped  int64
+	statuses map[int]int64
+}
+
+// stats collects outcomes from concurrent requests.
+type stats struct {
+	started time.Time
+
+	mu    sync.Mutex
+	kinds map[string]*kindStats
+}
+
+// newStats starts collecting.
+func newStats() *stats {
+	s := &stats{started: time.Now(), kinds: make(map[string]*kindStats)}
+	for _, kind := range kinds {
+		s.kinds[kind] = &kindStats{statuses: make(map[int]int64)}
+	}
+	return s
+}
+
+// record stores a completed request. A status of zero means the request got
+// no response.
+func (s *stats) record(kind string, latency time.Duration, status int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := s.kinds[kind]
+	k.latency.observe(latency)
+	k.statuses[status]++
+	if !ok {
+		k.errors++
+	}
+}
+
+// drop counts a request that was not sent because too many were in flight.
+func (s *stats) drop(kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.kinds[kind].dropped++
+}
+
+// report snapshots the collected outcomes.
+func (s *stats) report() *report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rep := &report{
+		StartedAt:      s.started,
+		ElapsedSeconds: time.Since(s.started).Seconds(),
+	}
+
+	var overall histogram
+	for _, kind := range kinds {
+		k := s.kinds[kind]
+		if k.latency.count == 0 && k.dropped == 0 {
+			continue
+		}
+		overall.merge(&k.latency)
+
+		kr := kindReport{
+			Kind:     kind,
+			Requests: k.latency.count,
+			Errors:   k.errors,
+			Dropped:  k.dropped,
+			Statuses: make(map[string]int64, len(k.statuses)),
+			latency:  summarize(&k.latency),
+		}
+		for status, n := range k.statuses {
+			key := strconv.Itoa(status)
+			if status == 0 {
+				key = "no response"
+			}
+			kr.Statuses[key] = n
+		}
+		for i, n := range k.latency.counts {
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = latencyBuckets[i].String()
+			}
+			kr.Buckets = append(kr.Buckets, bucketReport{Le: le, Count: n})
+		}
+
+		rep.Requests += kr.Requests
+		rep.Errors += kr.Errors
+		rep.Dropped += kr.Dropped
+		rep.Kinds = append(rep.Kinds, kr)
+	}
+	rep.latency = summarize(&overall)
+
+	if attempted := rep.Requests + rep.Dropped; attempted > 0 {
+		rep.ErrorRate = float64(rep.Errors+rep.Dropped) / float64(attempted)
+	}
+	if rep.ElapsedSeconds > 0 {
+		rep.AchievedRPS = float64(rep.Requests) / rep.ElapsedSeconds
+	}
+
+	return rep
+}
+
+// latency summarizes a histogram in milliseconds.
+type latency struct {
+	MeanMs float64 `json:"meanMs"`
+	P50Ms  float64 `json:"p50Ms"`
+	P95Ms  float64 `json:"p95Ms"`
+	P99Ms  float64 `json:"p99Ms"`
+	MaxMs  float64 `json:"maxMs"`
+}
+
+// summarize computes latency statistics from h.
+func summarize(h *histogram) latency {
+	if h.count == 0 {
+		return latency{}
+	}
+	return latency{
+		MeanMs: ms(h.sum / time.Duration(h.count)),
+		P50Ms:  ms(h.quantile(0.50)),
+		P95Ms:  ms(h.quantile(0.95)),
+		P99Ms:  ms(h.quantile(0.99)),
+		MaxMs:  ms(h.max),
+	}
+}
+
+// report is the outcome of a load generator run. Errors are requests that
+// got an unexpected status or no response; dropped requests were never sent
+// because the in-flight limit was reached. Both count towards ErrorRate.
+type report struct {
+	BaseURL        string       `json:"baseUrl"`
+	StartedAt      time.Time    `json:"startedAt"`
+	ElapsedSeconds float64      `json:"elapsedSeconds"`
+	TargetRPS      float64      `json:"targetRps"`
+	AchievedRPS    float64      `json:"achievedRps"`
+	Requests       int64        `json:"requests"`
+	Errors         int64        `json:"errors"`
+	Dropped        int64        `json:"dropped"`
+	ErrorRate      float64      `json:"errorRate"`
+	Kinds          []kindReport `json:"kinds"`
+	latency
+}
+
+// kindReport is the outcome of one request kind.
+type kindReport struct {
+	Kind     string           `json:"kind"`
+	Requests int64            `json:"requests"`
+	Errors   int64            `json:"errors"`
+	Dropped  int64            `json:"dropped"`
+	Statuses map[string]int64 `json:"statuses"`
+	Buckets  []bucketReport   `json:"buckets"`
+	latency
+}
+
+// bucketReport is one latency histogram bucket: the number of requests that
+// took at most Le.
+type bucketReport struct {
+	Le    string `json:"le"`
+	Count int64  `json:"count"`
+}
+
+// writeText prints a summary table followed by a histogram per kind.
+func (r *report) writeText(w io.Writer) {
+	fmt.Fprintf(w, "\n%d requests in %s (%.1f/s achieved, %.1f/s target), %d errors, %d dropped\n\n",
+		r.Requests, time.Duration(r.ElapsedSeconds*float64(time.Second)).Round(time.Second),
+		r.AchievedRPS, r.TargetRPS, r.Errors, r.Dropped)
+
+	fmt.Fprintf(w, "%-15s %9s %7s %7s %9s %9s %9s %9s %9s\n", "kind", "requests", "errors", "dropped", "mean", "p50", "p95", "p99", "max")
+	for _, k := range r.Kinds {
+		fmt.Fprintf(w, "%-15s %9d %7d %7d %9s %9s %9s %9s %9s\n", k.Kind, k.Requests, k.Errors, k.Dropped,
+			formatMs(k.MeanMs), formatMs(k.P50Ms), formatMs(k.P95Ms), formatMs(k.P99Ms), formatMs(k.MaxMs))
+	}
+	fmt.Fprintf(w, "%-15s %9d %7d %7d %9s %9s %9s %9s %9s\n", "all", r.Requests, r.Errors, r.Dropped,
+		formatMs(r.MeanMs), formatMs(r.P50Ms), formatMs(r.P95Ms), formatMs(r.P99Ms), formatMs(r.MaxMs))
+
+	for _, k := range r.Kinds {
+		if k.Requests == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s latency\n", k.Kind)
+		for _, b := range k.Buckets {
+			if b.Count == 0 {
+				continue
+			}
+			bar := int(40 * b.Count / k.Requests)
+			fmt.Fprintf(w, "  <= %-7s %8d %s\n", b.Le, b.Count, strings.Repeat("#", bar))
+		}
+	}
+}
+
+// writeJSON writes the report as JSON.
+func (r *report) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ms converts a duration to fractional milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// formatMs formats milliseconds for the summary table.
+func formatMs(v float64) string {
+	return fmt.Sprintf("%.1fms", v)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/model"
+)
+
+// Request kinds in the traffic mix.
+const (
+	kindRead          = "read"
+	kindOrder         = "order"
+	kindCouponOrder   = "coupon-order"
+	kindInvalidCoupon = "invalid-coupon"
+)
+
+// defaultMix approximates production traffic: mostly catalogue reads, with
+// a share of orders, some of them couponed, and a trickle of bad codes.
+const defaultMix = "read=70,order=15,coupon-order=10,invalid-coupon=5"
+
+// kinds lists the request kinds in report order.
+var kinds = []string{kindRead, kindOrder, kindCouponOrder, kindInvalidCoupon}
+
+// expectedStatus is the response each kind should get from a healthy
+// deployment. Anything else counts as an error.
+var expectedStatus = map[string]int{
+	kindRead:          http.StatusOK,
+	kindOrder:         http.StatusCreated,
+	kindCouponOrder:   http.StatusCreated,
+	kindInvalidCoupon: http.StatusBadRequest,
+}
+
+// codeAlphabet is used for generated coupon codes.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// parseMix parses comma-separated kind=weight pairs, e.g. "read=70,order=30".
+func parseMix(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		kind, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected kind=weight", pair)
+		}
+		if _, known := expectedStatus[kind]; !known {
+			return nil, fmt.Errorf("unknown request kind %q in mix, use %s", kind, strings.Join(kinds, ", "))
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", value, kind)
+		}
+		weights[kind] = weight
+	}
+	return weights, nil
+}
+
+// mix picks request kinds at random in proportion to their weights.
+type mix struct {
+	kinds      []string
+	cumulative []int
+}
+
+// newMix creates a mix from weights. At least one weight must be positive.
+func newMix(weights map[string]int) (*mix, error) {
+	m := &mix{}
+	total := 0
+	for _, kind := range kinds {
+		if weights[kind] == 0 {
+			continue
+		}
+		total += weights[kind]
+		m.kinds = append(m.kinds, kind)
+		m.cumulative = append(m.cumulative, total)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("traffic mix has no requests")
+	}
+	return m, nil
+}
+
+// pick returns a random kind.
+func (m *mix) pick() string {
+	n := rand.IntN(m.cumulative[len(m.cumulative)-1])
+	for i, bound := range m.cumulative {
+		if n < bound {
+			return m.kinds[i]
+		}
+	}
+	return m.kinds[len(m.kinds)-1]
+}
+
+// String formats the mix as percentages.
+func (m *mix) String() string {
+	total := m.cumulative[len(m.cumulative)-1]
+	parts := make([]string, len(m.kinds))
+	prev := 0
+	for i, kind := range m.kinds {
+		parts[i] = fmt.Sprintf("%s %.0f%%", kind, float64(m.cumulative[i]-prev)*100/float64(total))
+		prev = m.cumulative[i]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// generator sends requests at a fixed rate. It is open-loop: a slow target
+// does not slow the schedule down, it fills the in-flight limit and further
+// requests are dropped, so saturation shows up in the report.
+type generator struct {
+	client     *client
+	mix        *mix
+	products   []string
+	couponCode string
+	stats      *stats
+	inFlight   chan struct{}
+}
+
+// run sends requests at rps until ctx is done, then waits for requests in
+// flight to finish.
+func (g *generator) run(ctx context.Context, rps float64) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rps))
+	defer ticker.Stop()
+
+	// Requests already sent are allowed to finish when the run ends
+	sendCtx := context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		kind := g.mix.pick()
+		select {
+		case g.inFlight <- struct{}{}:
+		default:
+			g.stats.drop(kind)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-g.inFlight }()
+			g.send(sendCtx, kind)
+		}()
+	}
+}
+
+// send issues one request of the given kind and records the outcome.
+func (g *generator) send(ctx context.Context, kind string) {
+	method, path, body := g.request(kind)
+
+	start := time.Now()
+	status, err := g.client.Send(ctx, method, path, body)
+	g.stats.record(kind, time.Since(start), status, err == nil && status == expectedStatus[kind])
+}
+
+// request builds a random request of the given kind.
+func (g *generator) request(kind string) (string, string, any) {
+	productID := g.products[rand.IntN(len(g.products))]
+
+	switch kind {
+	case kindRead:
+		if rand.IntN(2) == 0 {
+			return http.MethodGet, "/api/v1/products?limit=10", nil
+		}
+		return http.MethodGet, "/api/v1/products/" + productID, nil
+	case kindCouponOrder:
+		return http.MethodPost, "/api/v1/orders", g.order(productID, &g.couponCode)
+	case kindInvalidCoupon:
+		code := randomCode(10)
+		return http.MethodPost, "/api/v1/orders", g.order(productID, &code)
+	default:
+		return http.MethodPost, "/api/v1/orders", g.order(productID, nil)
+	}
+}
+
+// order builds an order for one to three units of a product.
+func (g *generator) order(productID string, couponCode *string) *model.OrderRequest {
+	source := "web"
+	return &model.OrderRequest{
+		CouponCode: couponCode,
+		Source:     &source,
+		Items:      []model.OrderItemRequest{{ProductID: productID, Quantity: 1 + rand.IntN(3)}},
+	}
+}
+
+// progress prints a running summary every interval until ctx is done.
+func (g *generator) progress(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rep := g.stats.report()
+			fmt.Printf("%8s  requests=%d errors=%d dropped=%d p95=%s\n",
+				time.Duration(rep.ElapsedSeconds*float64(time.Second)).Round(time.Second),
+				rep.Requests, rep.Errors, rep.Dropped, formatMs(rep.P95Ms))
+		}
+	}
+}
+
+// randomCode returns a well-formed coupon code that is almost certainly not
+// a real one, so the validator has to look it up before rejecting it.
+func randomCode(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = codeAlphabet[rand.IntN(len(codeAlphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,69 @@
+// Command orderexport writes orders created or updated since its last run to
+// S3 as Parquet files for the data warehouse. Run it nightly, e.g. from cron.
+// Files are partitioned by update date:
+//
+//	<EXPORT_S3_PREFIX>updated_date=2025-12-01/orders-20251202T015500Z.parquet
+//
+// Usage:
+//
+//	go run ./cmd/orderexport
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mini-kart/internal/config"
+	"mini-kart/internal/database"
+	"mini-kart/internal/export"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg := config.LoadExport()
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := database.NewPool(ctx, config.LoadDatabase(), logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	uploader, err := export.NewS3Uploader(ctx, cfg.Bucket, cfg.Region, nil, logger)
+	if err != nil {
+		return err
+	}
+
+	exporter := export.NewExporter(repository.NewOrderExportRepository(pool, logger), uploader, export.Config{
+		Prefix:    cfg.Prefix,
+		BatchSize: cfg.BatchSize,
+		SafetyLag: cfg.SafetyLag,
+	}, logger)
+
+	result, err := exporter.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d orders updated from %s to %s into %d files\n",
+		result.Orders, result.From.Format("2006-01-02T15:04:05Z"), result.To.Format("2006-01-02T15:04:05Z"), len(result.Files))
+	return nil
+}
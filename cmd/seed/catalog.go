@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"mini-kart/internal/model"
+)
+
+// catalogColumns lists the CSV header columns a catalog must have.
+var catalogColumns = []string{"id", "name", "price", "category"}
+
+// catalogReader returns products one at a time, in the BulkInsert iterator
+// shape, so large catalogs are streamed rather than loaded into memory.
+type catalogReader func() (model.Product, bool, error)
+
+// newCatalogReader reads a catalog in the given format: "json" for an array
+// of product objects or "csv" for rows under an id,name,price,category header.
+// Each product is validated as it is read.
+func newCatalogReader(r io.Reader, format string) (catalogReader, error) {
+	var next catalogReader
+	var err error
+	switch format {
+	case "json":
+		next, err = newJSONCatalogReader(r)
+	case "csv":
+		next, err = newCSVCatalogReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %q, use json or csv", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := 0
+	return func() (model.Product, bool, error) {
+		product, ok, err := next()
+		if err != nil || !ok {
+			return product, ok, err
+		}
+		record++
+
+		product.ID = strings.TrimSpace(product.ID)
+		product.Name = strings.TrimSpace(product.Name)
+		product.Category = strings.TrimSpace(product.Category)
+		if err := product.Validate(); err != nil {
+			return model.Product{}, false, fmt.Errorf("product %d (%q): %w", record, product.ID, err)
+		}
+		return product, true, nil
+	}, nil
+}
+
+// newJSONCatalogReader decodes a JSON array of products element by element.
+func newJSONCatalogReader(r io.Reader) (catalogReader, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("catalog must be a JSON array of products")
+	}
+
+	return func() (model.Product, bool, error) {
+		if !dec.More() {
+			return model.Product{}, false, nil
+		}
+		var req model.ProductRequest
+		if err := dec.Decode(&req); err != nil {
+			return model.Product{}, false, fmt.Errorf("failed to decode product: %w", err)
+		}
+		if req.Price == nil {
+			return model.Product{}, false, fmt.Errorf("product %q: %w", req.ID, model.ErrInvalidProduct)
+		}
+		return model.Product{ID: req.ID, Name: req.Name, Price: *req.Price, Category: req.Category}, true, nil
+	}, nil
+}
+
+// newCSVCatalogReader reads products from CSV rows. Columns are matched by
+// header name, so they may appear in any order.
+func newCSVCatalogReader(r io.Reader) (catalogReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range catalogColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", column)
+		}
+	}
+
+	return func() (model.Product, bool, error) {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return model.Product{}, false, nil
+		}
+		if err != nil {
+			return model.Product{}, false, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		price, err := model.ParseMoney(strings.TrimSpace(row[index["price"]]))
+		if err != nil || !price.WholeCents() {
+			return model.Product{}, false, fmt.Errorf("line %d: invalid price %q", line, row[index["price"]])
+		}
+
+		return model.Product{
+			ID:       row[index["id"]],
+			Name:     row[index["name"]],
+			Price:    price,
+			Category: row[index["category"]],
+		}, true, nil
+	}, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAll drains a catalog reader.
+func readAll(next catalogReader) ([]model.Product, error) {
+	var products []model.Product
+	for {
+		product, ok, err := next()
+		if err != nil {
+			return products, err
+		}
+		if !ok {
+			return products, nil
+		}
+		products = append(products, product)
+	}
+}
+
+func TestCatalogReader(t *testing.T) {
+	expected := []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(6.5), Category: "Waffle"},
+		{ID: "P002", Name: "Lemon Tart", Price: model.NewMoney(4.25), Category: "Tart"},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{
+			name:   "JSON array",
+			format: "json",
+			input: `[
+				{"id": "P001", "name": "Chicken Waffle", "price": 6.5, "category": "Waffle"},
+				{"id": "P002", "name": " Lemon Tart ", "price": 4.25, "category": "Tart"}
+			]`,
+		},
+		{
+			name:   "CSV with columns in any order",
+			format: "csv",
+			input:  "name,id,category,price\nChicken Waffle,P001,Waffle,6.50\nLemon Tart,P002,Tart,4.25\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := newCatalogReader(strings.NewReader(tt.input), tt.format)
+			require.NoError(t, err)
+
+			products, err := readAll(next)
+			require.NoError(t, err)
+			assert.Equal(t, expected, products)
+		})
+	}
+}
+
+func TestCatalogReader_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		errorMsg string
+	}{
+		{
+			name:     "Unsupported format",
+			format:   "xml",
+			input:    "<products/>",
+			errorMsg: `unsupported catalog format "xml"`,
+		},
+		{
+			name:     "JSON object instead of array",
+			format:   "json",
+			input:    `{"id": "P001"}`,
+			errorMsg: "catalog must be a JSON array",
+		},
+		{
+			name:     "JSON product without price",
+			format:   "json",
+			input:    `[{"id": "P001", "name": "Waffle", "category": "Waffle"}]`,
+			errorMsg: model.ErrInvalidProduct.Error(),
+		},
+		{
+			name:     "CSV missing column",
+			format:   "csv",
+			input:    "id,name,price\nP001,Waffle,6.50\n",
+			errorMsg: `missing the "category" column`,
+		},
+		{
+			name:     "CSV invalid price",
+			format:   "csv",
+			input:    "id,name,price,category\nP001,Waffle,6.50,Waffle\nP002,Tart,cheap,Tart\n",
+			errorMsg: `line 3: invalid price "cheap"`,
+		},
+		{
+			name:     "Negative price",
+			format:   "csv",
+			input:    "id,name,price,category\nP001,Waffle,-1,Waffle\n",
+			errorMsg: `product 1 ("P001")`,
+		},
+		{
+			name:     "Missing name",
+			format:   "json",
+			input:    `[{"id": "P001", "name": " ", "price": 1, "category": "Waffle"}]`,
+			errorMsg: model.ErrInvalidProduct.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := newCatalogReader(strings.NewReader(tt.input), tt.format)
+			if err == nil {
+				_, err = readAll(next)
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
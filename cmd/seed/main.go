@@ -0,0 +1,86 @@
+// Command seed loads a product catalog from a JSON or CSV file, so new
+// environments can be bootstrapped without hand-written SQL. Products are
+// bulk-inserted with COPY in one transaction; nothing is inserted if any
+// product is invalid or already exists.
+//
+// JSON catalogs are an array of {"id", "name", "price", "category"} objects.
+// CSV catalogs have an id,name,price,category header row.
+//
+// Usage:
+//
+//	go run ./cmd/seed catalog.json
+//	go run ./cmd/seed -truncate catalog.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mini-kart/internal/config"
+	"mini-kart/internal/database"
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	truncate := flag.Bool("truncate", false, "Delete existing products before loading (fails if orders refer to them)")
+	format := flag.String("format", "", "Catalog format: json or csv (defaults to the file extension)")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		return fmt.Errorf("exactly one catalog file is required")
+	}
+	path := flag.Arg(0)
+	if *format == "" {
+		*format = strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open catalog %s: %w", path, err)
+	}
+	defer file.Close()
+
+	next, err := newCatalogReader(file, *format)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog %s: %w", path, err)
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	ctx := context.Background()
+
+	pool, err := database.NewPool(ctx, config.LoadDatabase(), logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	repo := repository.NewProductRepository(pool, logger)
+
+	count, err := repo.BulkInsert(ctx, next, *truncate)
+	switch err {
+	case nil:
+	case model.ErrProductExists:
+		return fmt.Errorf("catalog contains a product ID that already exists or is repeated; use -truncate to replace the catalog")
+	case model.ErrProductInUse:
+		return fmt.Errorf("existing products are referenced by orders and cannot be truncated")
+	default:
+		return fmt.Errorf("failed to load catalog %s: %w", path, err)
+	}
+
+	fmt.Printf("Loaded %d products from %s\n", count, path)
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"mini-kart/internal/model"
+)
+
+// client is a minimal mini-kart API client covering the smoke test calls.
+type client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// newClient creates an API client for the deployment at baseURL.
+func newClient(baseURL, apiKey string, timeout time.Duration) *client {
+	return &client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// apiError is returned for responses with an unexpected status code.
+type apiError struct {
+	Status int
+	Body   string
+}
+
+// Error implements error.
+func (e *apiError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Status, e.Body)
+}
+
+// Health checks the liveness endpoint.
+func (c *client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, http.StatusOK, nil)
+}
+
+// ListProducts returns the first page of the catalogue.
+func (c *client) ListProducts(ctx context.Context) ([]model.Product, error) {
+	var products []model.Product
+	if err := c.do(ctx, http.MethodGet, "/api/v1/products?limit=10", nil, http.StatusOK, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// CreateOrder places an order.
+func (c *client) CreateOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	var order model.OrderResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/orders", req, http.StatusCreated, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOrder fetches an order by ID.
+func (c *client) GetOrder(ctx context.Context, id string) (*model.OrderResponse, error) {
+	var order model.OrderResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/orders/"+id, nil, http.StatusOK, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// do sends a request, checks the status code and decodes the JSON response into out.
+func (c *client) do(ctx context.Context, method, path string, body any, expectedStatus int, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &apiError{Status: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// Command smoketest runs an end-to-end smoke test against a deployed mini-kart
+// environment and writes JUnit and JSON reports for deployment gates. It exits
+// non-zero when any step fails.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"mini-kart/internal/model"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	baseURL := flag.String("base-url", os.Getenv("SMOKE_BASE_URL"), "Base URL of the deployment, e.g. https://api.example.com")
+	apiKey := flag.String("api-key", os.Getenv("SMOKE_API_KEY"), "API key for the deployment")
+	productID := flag.String("product", os.Getenv("SMOKE_PRODUCT_ID"), "Product to order (default: first listed product)")
+	couponCode := flag.String("coupon", os.Getenv("SMOKE_COUPON_CODE"), "Valid coupon code; the coupon order step is skipped when empty")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout per request")
+	junitPath := flag.String("junit", "", "Write a JUnit XML report to this path")
+	jsonPath := flag.String("json", "", "Write a JSON report to this path")
+	flag.Parse()
+
+	if *baseURL == "" || *apiKey == "" {
+		return fmt.Errorf("base URL and API key are required")
+	}
+
+	st := &smokeTest{
+		client:     newClient(*baseURL, *apiKey, *timeout),
+		productID:  *productID,
+		couponCode: *couponCode,
+	}
+	rep := st.run(context.Background())
+	rep.BaseURL = *baseURL
+
+	for _, step := range rep.Steps {
+		fmt.Printf("%-7s %-28s %6dms %s\n", step.Status, step.Name, step.DurationMs, step.Message)
+	}
+
+	if *junitPath != "" {
+		if err := rep.writeJUnit(*junitPath); err != nil {
+			return err
+		}
+	}
+	if *jsonPath != "" {
+		if err := rep.writeJSON(*jsonPath); err != nil {
+			return err
+		}
+	}
+
+	if !rep.Passed {
+		return fmt.Errorf("%d of %d smoke test steps failed", rep.failures(), len(rep.Steps))
+	}
+
+	return nil
+}
+
+// smokeTest runs the smoke test sequence. Steps that depend on an earlier
+// failed step are skipped rather than failed.
+type smokeTest struct {
+	client     *client
+	productID  string
+	couponCode string
+}
+
+// run executes every step and returns the report.
+func (s *smokeTest) run(ctx context.Context) *report {
+	rep := &report{StartedAt: time.Now()}
+	var orderID string
+
+	healthy := s.step(rep, "health", "", func() error {
+		return s.client.Health(ctx)
+	})
+
+	listed := s.step(rep, "list products", skipUnless(healthy, "health check failed"), func() error {
+		products, err := s.client.ListProducts(ctx)
+		if err != nil {
+			return err
+		}
+		if s.productID == "" {
+			if len(products) == 0 {
+				return fmt.Errorf("catalogue is empty")
+			}
+			s.productID = products[0].ID
+		}
+		return nil
+	})
+
+	created := s.step(rep, "create order", skipUnless(listed, "no product to order"), func() error {
+		order, err := s.client.CreateOrder(ctx, s.orderRequest(nil))
+		if err != nil {
+			return err
+		}
+		orderID = order.ID.String()
+		return nil
+	})
+
+	couponSkip := skipUnless(listed, "no product to order")
+	if s.couponCode == "" {
+		couponSkip = "no coupon code configured"
+	}
+	s.step(rep, "create order with coupon", couponSkip, func() error {
+		_, err := s.client.CreateOrder(ctx, s.orderRequest(&s.couponCode))
+		return err
+	})
+
+	s.step(rep, "fetch order", skipUnless(created, "order was not created"), func() error {
+		order, err := s.client.GetOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.ID.String() != orderID {
+			return fmt.Errorf("fetched order %s, expected %s", order.ID, orderID)
+		}
+		if len(order.Items) != 1 || order.Items[0].ProductID != s.productID {
+			return fmt.Errorf("fetched order does not contain product %s", s.productID)
+		}
+		return nil
+	})
+
+	rep.Passed = rep.failures() == 0
+	return rep
+}
+
+// step runs fn and records its outcome, unless skipReason is set. It reports
+// whether the step passed.
+func (s *smokeTest) step(rep *report, name, skipReason string, fn func() error) bool {
+	if skipReason != "" {
+		rep.Steps = append(rep.Steps, stepResult{Name: name, Status: statusSkipped, Message: skipReason})
+		return false
+	}
+
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start)
+
+	result := stepResult{Name: name, Status: statusPassed, Duration: elapsed, DurationMs: elapsed.Milliseconds()}
+	if err != nil {
+		result.Status = statusFailed
+		result.Message = err.Error()
+	}
+	rep.Steps = append(rep.Steps, result)
+
+	return err == nil
+}
+
+// orderRequest builds a single-item order for the smoke test product.
+func (s *smokeTest) orderRequest(couponCode *string) *model.OrderRequest {
+	source := "web"
+	return &model.OrderRequest{
+		CouponCode: couponCode,
+		Source:     &source,
+		Items:      []model.OrderItemRequest{{ProductID: s.productID, Quantity: 1}},
+	}
+}
+
+// skipUnless returns reason when a prerequisite step did not pass.
+func skipUnless(passed bool, reason string) string {
+	if passed {
+		return ""
+	}
+	return reason
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the endpoints used by the smoke test.
+func fakeAPI(t *testing.T, rejectCoupons bool) *httptest.Server {
+	orderID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]model.Product{{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5)}})
+	})
+	mux.HandleFunc("POST /api/v1/orders", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("X-API-Key"))
+		var req model.OrderRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.CouponCode != nil && rejectCoupons {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error":"invalid promo code"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(model.OrderResponse{ID: orderID})
+	})
+	mux.HandleFunc("GET /api/v1/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(model.OrderResponse{
+			ID:    orderID,
+			Items: []model.OrderItem{{ProductID: "P001", Quantity: 1}},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSmokeTest_Run(t *testing.T) {
+	t.Run("All steps pass", func(t *testing.T) {
+		server := fakeAPI(t, false)
+		st := &smokeTest{client: newClient(server.URL, "test-key", time.Second), couponCode: "HAPPYHRS"}
+
+		rep := st.run(context.Background())
+
+		assert.True(t, rep.Passed)
+		require.Len(t, rep.Steps, 5)
+		for _, step := range rep.Steps {
+			assert.Equal(t, statusPassed, step.Status, step.Name)
+		}
+	})
+
+	t.Run("Coupon step skipped without a code", func(t *testing.T) {
+		server := fakeAPI(t, false)
+		st := &smokeTest{client: newClient(server.URL, "test-key", time.Second)}
+
+		rep := st.run(context.Background())
+
+		assert.True(t, rep.Passed)
+		assert.Equal(t, statusSkipped, rep.Steps[3].Status)
+		assert.Equal(t, 1, rep.skipped())
+	})
+
+	t.Run("Coupon rejection fails the run", func(t *testing.T) {
+		server := fakeAPI(t, true)
+		st := &smokeTest{client: newClient(server.URL, "test-key", time.Second), couponCode: "HAPPYHRS"}
+
+		rep := st.run(context.Background())
+
+		assert.False(t, rep.Passed)
+		assert.Equal(t, statusFailed, rep.Steps[3].Status)
+		assert.Contains(t, rep.Steps[3].Message, "422")
+		assert.Equal(t, statusPassed, rep.Steps[4].Status)
+	})
+
+	t.Run("Unreachable deployment skips dependent steps", func(t *testing.T) {
+		st := &smokeTest{client: newClient("http://127.0.0.1:1", "test-key", time.Second)}
+
+		rep := st.run(context.Background())
+
+		assert.False(t, rep.Passed)
+		assert.Equal(t, 1, rep.failures())
+		assert.Equal(t, statusSkipped, rep.Steps[1].Status)
+		assert.Equal(t, statusSkipped, rep.Steps[4].Status)
+	})
+}
+
+func TestReport_Write(t *testing.T) {
+	rep := &report{
+		BaseURL:   "https://api.example.com",
+		StartedAt: time.Now(),
+		Steps: []stepResult{
+			{Name: "health", Status: statusPassed, Duration: 12 * time.Millisecond, DurationMs: 12},
+			{Name: "create order", Status: statusFailed, Message: "unexpected status 500"},
+			{Name: "fetch order", Status: statusSkipped, Message: "order was not created"},
+		},
+	}
+	dir := t.TempDir()
+
+	junitPath := filepath.Join(dir, "smoke.xml")
+	require.NoError(t, rep.writeJUnit(junitPath))
+	data, err := os.ReadFile(junitPath)
+	require.NoError(t, err)
+
+	var suite junitTestSuite
+	require.NoError(t, xml.Unmarshal(data, &suite))
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	require.NotNil(t, suite.TestCases[1].Failure)
+	assert.Equal(t, "unexpected status 500", suite.TestCases[1].Failure.Message)
+
+	jsonPath := filepath.Join(dir, "smoke.json")
+	require.NoError(t, rep.writeJSON(jsonPath))
+	data, err = os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"durationMs": 12`)
+	assert.NotContains(t, string(data), "Duration\"")
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Step outcomes.
+const (
+	statusPassed  = "passed"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+// stepResult is the outcome of one smoke test step.
+type stepResult struct {
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	Duration   time.Duration `json:"-"`
+	DurationMs int64         `json:"durationMs"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// report collects the results of a smoke test run.
+type report struct {
+	BaseURL   string       `json:"baseUrl"`
+	StartedAt time.Time    `json:"startedAt"`
+	Steps     []stepResult `json:"steps"`
+	Passed    bool         `json:"passed"`
+}
+
+// failures returns the number of failed steps.
+func (r *report) failures() int {
+	count := 0
+	for _, step := range r.Steps {
+		if step.Status == statusFailed {
+			count++
+		}
+	}
+	return count
+}
+
+// skipped returns the number of skipped steps.
+func (r *report) skipped() int {
+	count := 0
+	for _, step := range r.Steps {
+		if step.Status == statusSkipped {
+			count++
+		}
+	}
+	return count
+}
+
+// junitTestSuite is the JUnit XML layout understood by CI systems.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes the report as a JUnit XML test suite.
+func (r *report) writeJUnit(path string) error {
+	suite := junitTestSuite{
+		Name:      "mini-kart smoke test",
+		Tests:     len(r.Steps),
+		Failures:  r.failures(),
+		Skipped:   r.skipped(),
+		Timestamp: r.StartedAt.UTC().Format(time.RFC3339),
+	}
+
+	var total time.Duration
+	for _, step := range r.Steps {
+		total += step.Duration
+		tc := junitTestCase{
+			Name:      step.Name,
+			ClassName: "smoketest",
+			Time:      seconds(step.Duration),
+		}
+		switch step.Status {
+		case statusFailed:
+			tc.Failure = &junitMessage{Message: step.Message}
+		case statusSkipped:
+			tc.Skipped = &junitMessage{Message: step.Message}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Time = seconds(total)
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+
+	return os.WriteFile(path, append([]byte(xml.Header), data...), 0o644)
+}
+
+// writeJSON writes the report as JSON.
+func (r *report) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// seconds formats a duration as fractional seconds for JUnit.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,158 @@
+// Package breaker implements a circuit breaker that fails calls fast while a
+// dependency is down, instead of letting every request wait out its timeout.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrOpen is returned without calling the dependency while the breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the breaker's current mode.
+type State int
+
+// Breaker states.
+const (
+	// StateClosed lets every call through and counts consecutive failures.
+	StateClosed State = iota
+
+	// StateOpen rejects calls with ErrOpen until the cooldown has passed.
+	StateOpen
+
+	// StateHalfOpen lets a single trial call through; its outcome closes or
+	// reopens the breaker.
+	StateHalfOpen
+)
+
+// String returns the state's name for logging.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Config holds circuit breaker configuration.
+type Config struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before allowing a trial call.
+	Cooldown time.Duration
+}
+
+// Breaker is a consecutive-failure circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	name   string
+	config Config
+	logger zerolog.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// New creates a closed breaker.
+func New(name string, config Config, logger zerolog.Logger) *Breaker {
+	return &Breaker{
+		name:   name,
+		config: config,
+		logger: logger.With().Str("component", "breaker").Str("breaker", name).Logger(),
+		now:    time.Now,
+	}
+}
+
+// Do calls fn unless the breaker is open, in which case it returns ErrOpen
+// immediately. Errors from fn count as failures, except cancellation by the
+// caller, which says nothing about the dependency's health.
+func (b *Breaker) Do(fn func() error) error {
+	if !b.allow() {
+		return ErrOpen
+	}
+
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// State returns the breaker's current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.Cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a call may proceed, moving an open breaker to
+// half-open once its cooldown has passed.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.config.Cooldown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.trial = true
+		return true
+	case StateHalfOpen:
+		// Only one trial call at a time; others fail fast until it finishes
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return true
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		// Release a half-open trial without judging the dependency
+		b.trial = false
+		return
+	}
+
+	if err == nil {
+		if b.state != StateClosed {
+			b.logger.Info().Msg("circuit breaker closed")
+		}
+		b.state = StateClosed
+		b.failures = 0
+		b.trial = false
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.config.FailureThreshold {
+		if b.state != StateOpen {
+			b.logger.Warn().Err(err).Int("failures", b.failures).Dur("cooldown", b.config.Cooldown).Msg("circuit breaker opened")
+		}
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.trial = false
+	}
+}
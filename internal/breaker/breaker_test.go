@@ -0,0 +1,101 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDown = errors.New("connection refused")
+
+// newTestBreaker creates a breaker whose clock the test controls.
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, *time.Time) {
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	b := New("database", Config{FailureThreshold: threshold, Cooldown: cooldown}, zerolog.Nop())
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail() error    { return errDown }
+func succeed() error { return nil }
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, 10*time.Second)
+
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, StateOpen, b.State())
+
+	called := false
+	err := b.Do(func() error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, ErrOpen, err)
+	assert.False(t, called)
+}
+
+func TestBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(2, 10*time.Second)
+
+	b.Do(fail)
+	b.Do(succeed)
+	b.Do(fail)
+
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	t.Run("Successful trial closes the breaker", func(t *testing.T) {
+		b, now := newTestBreaker(1, 10*time.Second)
+		b.Do(fail)
+
+		*now = now.Add(10 * time.Second)
+		assert.Equal(t, StateHalfOpen, b.State())
+
+		assert.NoError(t, b.Do(succeed))
+		assert.Equal(t, StateClosed, b.State())
+	})
+
+	t.Run("Failed trial reopens the breaker", func(t *testing.T) {
+		b, now := newTestBreaker(5, 10*time.Second)
+		for range 5 {
+			b.Do(fail)
+		}
+
+		*now = now.Add(10 * time.Second)
+		assert.Equal(t, errDown, b.Do(fail))
+		assert.Equal(t, StateOpen, b.State())
+		assert.Equal(t, ErrOpen, b.Do(succeed))
+	})
+
+	t.Run("Only one trial at a time", func(t *testing.T) {
+		b, now := newTestBreaker(1, 10*time.Second)
+		b.Do(fail)
+		*now = now.Add(10 * time.Second)
+
+		err := b.Do(func() error {
+			// A concurrent call while the trial is in flight fails fast
+			assert.Equal(t, ErrOpen, b.Do(succeed))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, StateClosed, b.State())
+	})
+}
+
+func TestBreaker_IgnoresCallerCancellation(t *testing.T) {
+	b, _ := newTestBreaker(1, 10*time.Second)
+
+	err := b.Do(func() error { return context.Canceled })
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StateClosed, b.State())
+}
@@ -0,0 +1,276 @@
+// Package cache keeps state shared by every API replica in Redis, so cached
+// products and rate limits behave the same whichever replica serves a request.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"mini-kart/internal/config"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// keyPrefix namespaces every key the API stores, so the Redis server can be
+// shared with other applications.
+const keyPrefix = "mini-kart:"
+
+// callTimeout bounds connecting to Redis and each call. Callers fall back to
+// the database or to local state when Redis fails, so failed calls are not
+// retried either: a slow cache is worse than a missing one.
+const callTimeout = 500 * time.Millisecond
+
+// ErrMiss is returned when a key is not cached.
+var ErrMiss = errors.New("cache miss")
+
+// incrScript counts a hit in a fixed window, starting the window's expiry on
+// its first hit, and returns the count and the window's remaining lifetime.
+var incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// takeScript takes ARGV[i] from the counter under KEYS[i] for every key, or
+// from none of them. It returns {1} once taken, {0, i} when counter i has too
+// little left and {-1, i} when counter i does not exist.
+var takeScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	local left = redis.call("GET", key)
+	if not left then
+		return {-1, i}
+	end
+	if tonumber(left) < tonumber(ARGV[i]) then
+		return {0, i}
+	end
+end
+for i, key in ipairs(KEYS) do
+	redis.call("DECRBY", key, ARGV[i])
+end
+return {1}
+`)
+
+// putBackScript adds ARGV[i] to the counter under KEYS[i], for counters that
+// still exist.
+var putBackScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if redis.call("EXISTS", key) == 1 then
+		redis.call("INCRBY", key, ARGV[i])
+	end
+end
+return 0
+`)
+
+// Client stores values in Redis for every API replica to share.
+type Client struct {
+	rdb *redis.Client
+}
+
+// New connects to the Redis server in cfg. Returns an error if the server
+// cannot be reached.
+func New(ctx context.Context, cfg config.RedisConfig, logger zerolog.Logger) (*Client, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:          cfg.Addr,
+		Password:      cfg.Password,
+		DB:            cfg.DB,
+		DialTimeout:   callTimeout,
+		DialerRetries: 1,
+		ReadTimeout:   callTimeout,
+		WriteTimeout:  callTimeout,
+		MaxRetries:    -1,
+	})
+	redis.SetLogger(redisLogger{logger: logger.With().Str("component", "redis").Logger()})
+
+	logger.Info().
+		Str("addr", cfg.Addr).
+		Int("db", cfg.DB).
+		Msg("connecting to redis")
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &Client{rdb: rdb}, nil
+}
+
+// redisLogger routes the Redis client's own logging, such as failures to
+// dial the server, through the API's logger.
+type redisLogger struct {
+	logger zerolog.Logger
+}
+
+// Printf logs a message from the Redis client.
+func (l redisLogger) Printf(ctx context.Context, format string, v ...any) {
+	l.logger.Warn().Msgf(format, v...)
+}
+
+// Get returns the value cached under key. Returns ErrMiss if there is none.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.rdb.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// GetMany returns the values cached under keys, in the same order, with nil
+// for keys that are not cached.
+func (c *Client) GetMany(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+
+	results, err := c.rdb.MGet(ctx, prefixed...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %d keys: %w", len(keys), err)
+	}
+
+	values := make([][]byte, len(results))
+	for i, result := range results {
+		if s, ok := result.(string); ok {
+			values[i] = []byte(s)
+		}
+	}
+	return values, nil
+}
+
+// Set caches value under key for ttl.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.rdb.Set(ctx, keyPrefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetMany caches each value under its key for ttl, in one round trip.
+func (c *Client) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, keyPrefix+key, value, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set %d keys: %w", len(values), err)
+	}
+	return nil
+}
+
+// Delete removes keys from the cache and returns how many were cached. Keys
+// that are not cached are ignored.
+func (c *Client) Delete(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+
+	deleted, err := c.rdb.Del(ctx, prefixed...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %d keys: %w", len(keys), err)
+	}
+	return deleted, nil
+}
+
+// Incr counts a hit on the counter under key, which is reset window after
+// its first hit. It returns the counter's value and how long until it resets.
+func (c *Client) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	result, err := incrScript.Run(ctx, c.rdb, []string{keyPrefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to increment %s: %w", key, err)
+	}
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// Take atomically takes amounts from the counters under their keys, which
+// never go below zero. It reports false, taking nothing, when a counter has
+// less left than its amount, and returns ErrMiss, taking nothing, when a
+// counter does not exist yet; create it with Seed and try again.
+func (c *Client) Take(ctx context.Context, amounts map[string]int64) (bool, error) {
+	if len(amounts) == 0 {
+		return true, nil
+	}
+
+	keys, args := counterArgs(amounts)
+	result, err := takeScript.Run(ctx, c.rdb, keys, args...).Int64Slice()
+	if err != nil {
+		return false, fmt.Errorf("failed to take from %d counters: %w", len(keys), err)
+	}
+	if result[0] < 0 {
+		return false, fmt.Errorf("counter %s: %w", keys[result[1]-1][len(keyPrefix):], ErrMiss)
+	}
+	return result[0] == 1, nil
+}
+
+// PutBack adds amounts taken by Take back to their counters. Counters
+// deleted since are not recreated.
+func (c *Client) PutBack(ctx context.Context, amounts map[string]int64) error {
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	keys, args := counterArgs(amounts)
+	if err := putBackScript.Run(ctx, c.rdb, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to put back %d counters: %w", len(keys), err)
+	}
+	return nil
+}
+
+// Seed creates the counter under key with value, unless it already exists,
+// and reports whether it was created. Counters do not expire.
+func (c *Client) Seed(ctx context.Context, key string, value int64) (bool, error) {
+	created, err := c.rdb.SetNX(ctx, keyPrefix+key, value, 0).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to seed %s: %w", key, err)
+	}
+	return created, nil
+}
+
+// counterArgs returns the prefixed keys of amounts, sorted so scripts see
+// them in a stable order, and their amounts in the same order.
+func counterArgs(amounts map[string]int64) ([]string, []any) {
+	keys := make([]string, 0, len(amounts))
+	for key := range amounts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]any, len(keys))
+	for i, key := range keys {
+		args[i] = amounts[key]
+		keys[i] = keyPrefix + key
+	}
+	return keys, args
+}
+
+// Ping checks the Redis server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+// Close closes the client's connections.
+func (c *Client) Close() error {
+	return c.rdb.Close()
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient connects a client to an in-memory Redis server.
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client, err := New(context.Background(), config.RedisConfig{Addr: server.Addr()}, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestNew_Unreachable(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	_, err := New(context.Background(), config.RedisConfig{Addr: addr}, zerolog.Nop())
+	assert.Error(t, err)
+}
+
+func TestClient_GetSetDelete(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.Get(ctx, "product:P001")
+	assert.Equal(t, ErrMiss, err)
+
+	require.NoError(t, client.Set(ctx, "product:P001", []byte(`{"id":"P001"}`), time.Minute))
+	value, err := client.Get(ctx, "product:P001")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"P001"}`, string(value))
+	assert.True(t, server.Exists("mini-kart:product:P001"))
+
+	require.NoError(t, client.SetMany(ctx, map[string][]byte{"product:P002": []byte("2"), "product:P003": []byte("3")}, time.Minute))
+	values, err := client.GetMany(ctx, []string{"product:P003", "product:P999", "product:P001"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("3"), nil, []byte(`{"id":"P001"}`)}, values)
+
+	deleted, err := client.Delete(ctx, "product:P001", "product:P999")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = client.Get(ctx, "product:P001")
+	assert.Equal(t, ErrMiss, err)
+
+	server.FastForward(time.Minute)
+	_, err = client.Get(ctx, "product:P002")
+	assert.Equal(t, ErrMiss, err)
+}
+
+func TestClient_Incr(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	count, ttl, err := client.Incr(ctx, "ratelimit:10.0.0.1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Minute, ttl)
+
+	server.FastForward(20 * time.Second)
+	count, ttl, err = client.Incr(ctx, "ratelimit:10.0.0.1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, 40*time.Second, ttl, "later hits must not extend the window")
+
+	server.FastForward(40 * time.Second)
+	count, _, err = client.Incr(ctx, "ratelimit:10.0.0.1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestClient_TakePutBack(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.Take(ctx, map[string]int64{"flashsale:P001": 1})
+	assert.ErrorIs(t, err, ErrMiss)
+
+	created, err := client.Seed(ctx, "flashsale:P001", 5)
+	require.NoError(t, err)
+	assert.True(t, created)
+	created, err = client.Seed(ctx, "flashsale:P001", 100)
+	require.NoError(t, err)
+	assert.False(t, created, "seeding must not reset an existing counter")
+	_, err = client.Seed(ctx, "flashsale:P002", 1)
+	require.NoError(t, err)
+
+	taken, err := client.Take(ctx, map[string]int64{"flashsale:P001": 3, "flashsale:P002": 1})
+	require.NoError(t, err)
+	assert.True(t, taken)
+
+	// Taking more than is left of one counter takes nothing from any
+	taken, err = client.Take(ctx, map[string]int64{"flashsale:P001": 1, "flashsale:P002": 1})
+	require.NoError(t, err)
+	assert.False(t, taken)
+	left, err := server.Get("mini-kart:flashsale:P001")
+	require.NoError(t, err)
+	assert.Equal(t, "2", left)
+
+	require.NoError(t, client.PutBack(ctx, map[string]int64{"flashsale:P002": 1, "flashsale:P003": 1}))
+	left, err = server.Get("mini-kart:flashsale:P002")
+	require.NoError(t, err)
+	assert.Equal(t, "1", left)
+	assert.False(t, server.Exists("mini-kart:flashsale:P003"), "putting back must not create counters")
+}
@@ -2,23 +2,79 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
+
+	"mini-kart/internal/logging"
+	"mini-kart/internal/model"
 )
 
 // Config holds all application configuration.
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
-	Auth     AuthConfig
-	S3       S3Config
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logger    LoggerConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Redis     RedisConfig
+	Quota     QuotaConfig
+	S3        S3Config
+	GCS       GCSConfig
+	Azure     AzureBlobConfig
+	Cache     CouponCacheConfig
+	Coupon    CouponConfig
+	Health    HealthConfig
+	Metrics   MetricsConfig
+	Order     OrderConfig
+	Snapshot  SnapshotConfig
+	Images    ProductImageConfig
+	Webhook   WebhookConfig
+	Saga      SagaConfig
+	Events    EventsConfig
+	Pricing   PricingConfig
+	TLS       TLSConfig
+	API       APIConfig
+	Public    PublicConfig
+	HTTP      HTTPClientConfig
+	Shadow    ShadowConfig
+	FlashSale FlashSaleConfig
+	Upsell    UpsellConfig
 }
 
 // ServerConfig holds server-related configuration.
 type ServerConfig struct {
 	Host string
 	Port int
+
+	// ReadTimeout bounds reading an entire request, including the body.
+	ReadTimeout time.Duration
+
+	// ReadHeaderTimeout bounds reading request headers. Zero uses ReadTimeout.
+	ReadHeaderTimeout time.Duration
+
+	// WriteTimeout bounds writing a response. Zero disables it, which long
+	// streaming responses may need.
+	WriteTimeout time.Duration
+
+	// IdleTimeout bounds how long a keep-alive connection waits for the next request.
+	IdleTimeout time.Duration
+
+	// MaxHeaderBytes limits the size of request headers. Zero uses the net/http default of 1 MB.
+	MaxHeaderBytes int
+
+	// MaxBodyBytes limits the size of request bodies, except product and
+	// order imports. Zero disables the limit.
+	MaxBodyBytes int64
+
+	// RequestTimeout bounds handling a request, except product and order
+	// imports. Zero disables it.
+	RequestTimeout time.Duration
 }
 
 // DatabaseConfig holds database-related configuration.
@@ -31,17 +87,165 @@ type DatabaseConfig struct {
 	MaxConnections  int
 	MinConnections  int
 	MaxConnLifetime int // seconds
+
+	// SSLMode is the libpq sslmode: disable, allow, prefer, require,
+	// verify-ca or verify-full. Empty uses the driver default, prefer.
+	SSLMode string
+
+	// SSLRootCert is the path of a PEM bundle of CAs trusted to sign the
+	// server certificate, e.g. the RDS CA bundle. Empty uses the system roots.
+	SSLRootCert string
+
+	// ApplicationName identifies connections in pg_stat_activity and server logs.
+	ApplicationName string
+
+	// SearchPath sets the schema search path of every connection. Empty
+	// uses the server default.
+	SearchPath string
+
+	// ConnectTimeout bounds establishing a connection, in seconds. Zero waits indefinitely.
+	ConnectTimeout int
+
+	// StartupTimeout is how long startup keeps retrying while the database
+	// is not reachable, in seconds. Zero fails on the first attempt.
+	StartupTimeout int
+
+	// Warmup opens MinConnections connections at startup instead of on demand.
+	Warmup bool
+
+	// BreakerFailureThreshold is the number of consecutive failed reads that
+	// trips the database circuit breaker. Zero disables the breaker.
+	BreakerFailureThreshold int
+
+	// BreakerCooldown is how long the breaker stays open before retrying the
+	// database, in seconds.
+	BreakerCooldown int
 }
 
 // LoggerConfig holds logger-related configuration.
 type LoggerConfig struct {
 	Level  string
 	Format string // "json" or "console"
+
+	// Sinks lists where logs are written: "stdout", "file", "syslog" and
+	// "http". Every sink gets every entry; none means stdout.
+	Sinks []string
+
+	// FilePath is the log file the file sink appends to. It is rotated
+	// once it reaches FileMaxSizeMB, keeping FileMaxBackups rotated files.
+	FilePath       string
+	FileMaxSizeMB  int
+	FileMaxBackups int
+
+	// SyslogAddress is the syslog daemon the syslog sink sends to, as
+	// "udp://host:514", "tcp://host:514" or "unixgram:///dev/log"; empty
+	// means the local daemon. Entries are tagged with SyslogTag.
+	SyslogAddress string
+	SyslogTag     string
+
+	// HTTPEndpoint is the collector the http sink posts batches of up to
+	// HTTPBatchSize entries to, at least every HTTPFlushInterval, as
+	// HTTPFormat ("ndjson" or "otlp").
+	HTTPEndpoint      string
+	HTTPFormat        string
+	HTTPBatchSize     int
+	HTTPFlushInterval time.Duration
 }
 
 // AuthConfig holds authentication configuration.
 type AuthConfig struct {
 	APIKey string
+
+	// AdminKeys lists admin-scoped API keys as "name:key" entries. Admin keys
+	// may act on behalf of customers through the X-On-Behalf-Of header, and
+	// only they may call the admin routes under /api/admin/. ADMIN_API_KEY
+	// adds a key named "admin".
+	AdminKeys []string
+
+	// AdminOnlyProductWrites restricts product writes under /api/products to
+	// admin keys, leaving the API key read-only on the catalogue.
+	AdminOnlyProductWrites bool
+
+	// TenantKeys lists tenant-scoped API keys as "tenant:key" entries.
+	// Callers using a tenant key only see their tenant's data.
+	TenantKeys []string
+}
+
+// AdminKeyNames maps each admin key to the name of the admin it belongs to.
+func (c AuthConfig) AdminKeyNames() map[string]string {
+	names := make(map[string]string, len(c.AdminKeys))
+	for _, entry := range c.AdminKeys {
+		if name, key, ok := strings.Cut(entry, ":"); ok {
+			names[key] = name
+		}
+	}
+	return names
+}
+
+// TenantKeyTenants maps each tenant key to the tenant it belongs to.
+func (c AuthConfig) TenantKeyTenants() map[string]string {
+	tenants := make(map[string]string, len(c.TenantKeys))
+	for _, entry := range c.TenantKeys {
+		if tenant, key, ok := strings.Cut(entry, ":"); ok {
+			tenants[key] = tenant
+		}
+	}
+	return tenants
+}
+
+// TenantIDs lists the tenants that have keys, each once.
+func (c AuthConfig) TenantIDs() []string {
+	var ids []string
+	seen := make(map[string]bool, len(c.TenantKeys))
+	for _, entry := range c.TenantKeys {
+		tenant, _, _ := strings.Cut(entry, ":")
+		if !seen[tenant] {
+			seen[tenant] = true
+			ids = append(ids, tenant)
+		}
+	}
+	return ids
+}
+
+// RateLimitConfig holds per-client request rate limiting configuration.
+type RateLimitConfig struct {
+	// Requests is the number of requests a client may make per window.
+	// Zero disables rate limiting.
+	Requests int
+
+	// Window is the rate limit window, in seconds.
+	Window int
+}
+
+// RedisConfig holds the Redis server shared by API replicas for cached
+// products and rate limit counters.
+type RedisConfig struct {
+	// Addr is the server's host:port. Empty disables Redis: products are
+	// always read from the database and each replica counts rate limits on
+	// its own.
+	Addr     string
+	Password string
+	DB       int
+
+	// ProductTTL is how long products stay cached, in seconds. Zero
+	// disables product caching.
+	ProductTTL int
+}
+
+// QuotaConfig holds monthly usage quota configuration.
+type QuotaConfig struct {
+	// MonthlyRequests and MonthlyOrders are the requests and created orders
+	// each caller may make per calendar month. Zero disables the quota.
+	MonthlyRequests int
+	MonthlyOrders   int
+
+	// Enforce rejects callers over a quota. Otherwise quotas only produce
+	// response headers and warnings.
+	Enforce bool
+
+	// WebhookURL receives warnings at 80% and 100% of a quota. Empty logs
+	// the warnings instead.
+	WebhookURL string
 }
 
 // S3Config holds AWS S3 configuration for coupon files.
@@ -50,6 +254,548 @@ type S3Config struct {
 	Bucket  string
 	Region  string
 	Prefix  string // Path prefix within bucket (e.g., "coupons/")
+
+	// PartSizeMB is the size of each ranged GET coupon files are downloaded
+	// in. Zero downloads each file in a single request.
+	PartSizeMB int
+
+	// DownloadConcurrency is the number of parts downloaded at once.
+	DownloadConcurrency int
+
+	// MaxAttempts is how many times a failed S3 request is tried.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry, in milliseconds. It
+	// doubles after each failed attempt, up to MaxRetryDelay.
+	RetryDelay    int
+	MaxRetryDelay int
+}
+
+// GCSConfig holds Google Cloud Storage configuration for coupon files.
+// Credentials come from Application Default Credentials.
+type GCSConfig struct {
+	Bucket string
+}
+
+// AzureBlobConfig holds Azure Blob Storage configuration for coupon files.
+// Credentials come from the default Azure credential chain.
+type AzureBlobConfig struct {
+	AccountURL string // e.g. https://account.blob.core.windows.net/
+	Container  string
+}
+
+// CouponCacheConfig holds local disk cache configuration for S3 coupon files.
+type CouponCacheConfig struct {
+	Dir      string // Empty disables the cache
+	MaxMB    int
+	MaxFiles int
+	MaxAge   int // seconds
+}
+
+// CouponConfig holds coupon loading configuration.
+type CouponConfig struct {
+	// Source selects where coupon codes are loaded from: "file", "storage"
+	// (cloud object storage, see StorageProvider), "db" or "index" (prebuilt
+	// coupon indexes, mapped rather than loaded). "s3" is shorthand for
+	// "storage" with the s3 provider. Defaults to "s3" when S3 is enabled and
+	// "file" otherwise.
+	Source string
+
+	// StorageProvider selects the object storage the "storage" source reads
+	// from: "s3", "gcs" or "azure".
+	StorageProvider string
+
+	// IndexDir holds the coupon indexes used by the "index" source. Empty
+	// looks for each index next to its coupon file.
+	IndexDir string
+
+	// Files lists the coupon files codes are looked up in. Empty uses the
+	// three default coupon bases.
+	Files []string
+
+	// MinMatchCount is the number of coupon files a code must appear in,
+	// counting each file's weight. Zero uses the validator's default of 2.
+	MinMatchCount int
+
+	// FileWeights lists "name:weight" entries giving a coupon file's matches
+	// extra weight, e.g. "couponbase1:2" so a primary base counts double.
+	// Files are named as coupon sets, without their directory and extension.
+	FileWeights []string
+
+	// MemoryLimitMB aborts coupon loading once process memory reaches this
+	// ceiling. Zero disables the watchdog.
+	MemoryLimitMB int
+
+	// MemoryCheckInterval is how often memory is sampled during a load, in milliseconds.
+	MemoryCheckInterval int
+
+	// SetType selects how coupon codes are held in memory: "map" (exact) or
+	// "bloom" (compact, with a small false-positive rate).
+	SetType string
+
+	// ExpectedCodes is the number of codes each coupon file is sized for.
+	ExpectedCodes int
+
+	// FalsePositiveRate is the per-file false-positive rate of "bloom" sets.
+	FalsePositiveRate float64
+
+	// CodePattern is the regular expression promo codes must match before
+	// they are looked up. Empty accepts any characters.
+	CodePattern string
+
+	// CaseInsensitive matches promo codes regardless of case.
+	CaseInsensitive bool
+
+	// DeltaDir holds delta files applied on top of the coupon files.
+	// Empty disables delta updates.
+	DeltaDir string
+
+	// DeltaInterval is how often DeltaDir is checked for new deltas, in seconds.
+	DeltaInterval int
+
+	// ValidationTimeout bounds a single promo code lookup, in milliseconds.
+	// Zero disables it.
+	ValidationTimeout int
+
+	// FailurePolicy decides what happens to orders whose promo code could
+	// not be checked because the lookup timed out or the coupon files are
+	// still loading: "fail-closed" rejects the order as retryable,
+	// "fail-open" accepts the code and flags the order with a coupon warning.
+	FailurePolicy string
+
+	// LoadInBackground starts the server before the coupon codes are loaded.
+	// Readiness is held back, and promo codes rejected as retryable, until
+	// they have.
+	LoadInBackground bool
+
+	// ArchiveDir holds archived copies of every coupon file uploaded, stored
+	// as <name>/<checksum>.gz, searched to check whether codes were valid at
+	// past times. Empty leaves archived files unread unless ArchivePrefix is set.
+	ArchiveDir string
+
+	// ArchivePrefix reads archived coupon files from the coupon object
+	// storage (see StorageProvider) under this key prefix instead of ArchiveDir.
+	ArchivePrefix string
+}
+
+// MatchWeights maps each weighted coupon file's set name to its weight.
+func (c CouponConfig) MatchWeights() map[string]int {
+	weights := make(map[string]int, len(c.FileWeights))
+	for _, entry := range c.FileWeights {
+		name, value, _ := strings.Cut(entry, ":")
+		if weight, err := strconv.Atoi(value); err == nil {
+			weights[name] = weight
+		}
+	}
+	return weights
+}
+
+// MetricsConfig holds operational metrics configuration.
+type MetricsConfig struct {
+	// CacheReportInterval is how often cache hit rates are logged and
+	// recorded for the last interval, in seconds. Zero disables interval
+	// reports; totals are still reported.
+	CacheReportInterval int
+
+	// OTLPEndpoint is the OTLP/HTTP collector OpenTelemetry metrics, such as
+	// coupon file loads, are exported to. Empty disables the export. The
+	// exporter reads it, and its other settings, from the standard
+	// OTEL_EXPORTER_OTLP_* variables itself.
+	OTLPEndpoint string
+
+	// OTLPInterval is how often OpenTelemetry metrics are exported, in seconds.
+	OTLPInterval int
+}
+
+// HealthConfig holds dependency health monitoring configuration.
+type HealthConfig struct {
+	ProbeInterval     int // seconds
+	ProbeTimeout      int // seconds
+	HistorySize       int
+	FailureThreshold  int
+	RecoveryThreshold int
+	FlapThreshold     int
+}
+
+// PricingConfig holds product pricing configuration.
+type PricingConfig struct {
+	// ApprovalThreshold is the percentage price change above which a second admin must approve.
+	ApprovalThreshold int
+
+	// Currency is the ISO 4217 code catalogue prices are expressed in.
+	Currency string
+
+	// ShippingFlatRateCents is the shipping charge applied when a delivery address is given.
+	ShippingFlatRateCents int
+
+	// ExchangeRates lists fixed rates from the catalogue currency as
+	// "code:rate" entries, e.g. "USD:0.66". Ignored when ExchangeRatesURL is
+	// set.
+	ExchangeRates []string
+
+	// ExchangeRatesURL is the endpoint rates are fetched from. Prices are
+	// only shown in other currencies when it or ExchangeRates is set.
+	ExchangeRatesURL string
+
+	// ExchangeRatesTTL is how long fetched rates are used before they are
+	// fetched again.
+	ExchangeRatesTTL time.Duration
+}
+
+// Rates maps each currency in ExchangeRates to its rate.
+func (c PricingConfig) Rates() map[string]float64 {
+	rates := make(map[string]float64, len(c.ExchangeRates))
+	for _, entry := range c.ExchangeRates {
+		code, value, _ := strings.Cut(entry, ":")
+		if rate, err := strconv.ParseFloat(value, 64); err == nil {
+			rates[strings.ToUpper(code)] = rate
+		}
+	}
+	return rates
+}
+
+// ConvertsCurrencies reports whether prices may be shown in currencies other
+// than the catalogue currency.
+func (c PricingConfig) ConvertsCurrencies() bool {
+	return c.ExchangeRatesURL != "" || len(c.ExchangeRates) > 0
+}
+
+// OrderConfig holds order-related configuration.
+type OrderConfig struct {
+	// AllowedSources lists the accepted order channels. Entries ending in ":*"
+	// accept any sub-channel (e.g. "marketplace:*" accepts "marketplace:amazon").
+	AllowedSources []string
+
+	// UnknownFields decides what happens to order request fields this server
+	// does not recognise: "preserve" stores them in the order's metadata,
+	// "reject" fails the request.
+	UnknownFields string
+
+	// MaxInFlight caps how many order creations run at once; requests beyond
+	// it are shed with 503 rather than queueing on the database pool.
+	// Zero disables the limit.
+	MaxInFlight int
+
+	// AsyncCallers lists the identity subjects whose orders are created
+	// asynchronously, returning 202 Accepted with an operation to poll.
+	// "*" makes every caller's orders asynchronous.
+	AsyncCallers []string
+
+	// AsyncTimeout bounds an asynchronous order creation, in seconds.
+	AsyncTimeout int
+
+	// PendingSLA and ConfirmedSLA are how long orders may stay pending or
+	// confirmed before they breach their SLA, in seconds. Zero stops
+	// tracking the status.
+	PendingSLA   int
+	ConfirmedSLA int
+
+	// SLAWarningPercent is the share of an SLA after which orders are
+	// listed as at risk.
+	SLAWarningPercent int
+
+	// SLACheckInterval is how often orders are checked for SLA breaches, in seconds.
+	SLACheckInterval int
+
+	// SLAWebhookURL receives SLA breach alerts. Empty writes them to the log.
+	SLAWebhookURL string
+
+	// ImportEnabled serves the historical order import, which stores orders
+	// without validating their coupons or prices. Enable it only while
+	// migrating from another platform.
+	ImportEnabled bool
+
+	// IdempotencyKeyTTL is how long an Idempotency-Key replays its order, in
+	// seconds. Zero keeps keys forever.
+	IdempotencyKeyTTL int
+
+	// IdempotencySweepInterval is how often expired idempotency keys are
+	// deleted, in seconds.
+	IdempotencySweepInterval int
+}
+
+// SnapshotConfig holds configuration for signed order snapshots, kept as
+// evidence for disputes and chargebacks.
+type SnapshotConfig struct {
+	// SigningKey signs snapshots with HMAC-SHA256. Empty disables snapshots.
+	SigningKey string
+
+	// Bucket and Region locate the S3 bucket snapshots are stored in. The
+	// bucket must have Object Lock enabled. Empty Bucket returns snapshots
+	// without storing them.
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every snapshot key (e.g. "snapshots/orders/").
+	Prefix string
+
+	// LockMode is the Object Lock retention mode, "COMPLIANCE" or "GOVERNANCE".
+	LockMode string
+
+	// RetentionDays is how long stored snapshots are locked.
+	RetentionDays int
+}
+
+// ProductImageConfig holds configuration for product images, uploaded by
+// clients straight to S3 with presigned URLs.
+type ProductImageConfig struct {
+	// Bucket and Region locate the S3 bucket images are stored in. Empty
+	// Bucket disables product image management.
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every image key (e.g. "images/products/").
+	Prefix string
+
+	// BaseURL is where stored images are served from, such as a CDN in front
+	// of the bucket. Empty serves images from the bucket itself.
+	BaseURL string
+
+	// UploadExpiry is how long presigned upload URLs are valid, in seconds.
+	UploadExpiry int
+
+	// MaxBytes is the largest image file that can be uploaded.
+	MaxBytes int
+}
+
+// WebhookConfig holds configuration for order webhooks, posted to downstream
+// systems such as fulfillment when orders are created or cancelled.
+type WebhookConfig struct {
+	// URLs are the endpoints every order event is posted to. Empty disables webhooks.
+	URLs []string
+
+	// Secret signs deliveries with HMAC-SHA256.
+	Secret string
+
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry. It doubles after each
+	// failed attempt, up to MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// PollInterval is how often the outbox is checked for due deliveries.
+	PollInterval time.Duration
+
+	// BatchSize is the number of deliveries sent at once.
+	BatchSize int
+
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+}
+
+// SagaConfig holds configuration for the side effects order creation has
+// on external systems, run as a saga and compensated if the order is not
+// stored.
+type SagaConfig struct {
+	// PaymentURL, StockURL and CouponURL are the endpoints of the payment,
+	// warehouse stock and coupon redemption services. Each step is put at
+	// and deleted from its endpoint followed by the order ID. Empty skips a
+	// step; sagas are disabled when all are empty.
+	PaymentURL string
+	StockURL   string
+	CouponURL  string
+
+	// Timeout is how long an order may take to be stored after its saga
+	// starts before the saga is compensated.
+	Timeout time.Duration
+
+	// MaxAttempts is how many times a saga's compensation is tried before
+	// it is marked failed.
+	MaxAttempts int
+
+	// RetryDelay is the delay before compensation is retried. It doubles
+	// after each failed attempt, up to MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// PollInterval is how often sagas due for compensation are looked for.
+	PollInterval time.Duration
+
+	// BatchSize is the number of sagas compensated at once.
+	BatchSize int
+}
+
+// Enabled reports whether any saga step is configured.
+func (c SagaConfig) Enabled() bool {
+	return c.PaymentURL != "" || c.StockURL != "" || c.CouponURL != ""
+}
+
+// EventsConfig holds configuration for publishing domain events to Kafka
+// for the analytics pipeline.
+type EventsConfig struct {
+	// KafkaBrokers are the bootstrap broker addresses. Empty disables events.
+	KafkaBrokers []string
+
+	// KafkaTopic receives every event.
+	KafkaTopic string
+
+	// KafkaTLS connects to the brokers over TLS.
+	KafkaTLS bool
+
+	// RelayInterval is how often the outbox is checked for unpublished events.
+	RelayInterval time.Duration
+
+	// RelayBatchSize is the number of events published at once.
+	RelayBatchSize int
+
+	// PublishTimeout bounds publishing a batch.
+	PublishTimeout time.Duration
+}
+
+// TLSConfig holds server TLS and mutual TLS configuration.
+type TLSConfig struct {
+	Enabled      bool
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // PEM bundle of CAs trusted to sign client certificates
+	ClientAuth   string // "none", "request" or "require"
+}
+
+// APIConfig holds API versioning and deprecation configuration.
+type APIConfig struct {
+	// UnversionedDeprecated is when the unversioned /api/* paths were deprecated.
+	// Zero leaves them undeprecated.
+	UnversionedDeprecated time.Time
+
+	// UnversionedSunset is when the unversioned /api/* paths will be removed.
+	UnversionedSunset time.Time
+
+	// DeprecationLink is an optional URL documenting the migration to versioned paths.
+	DeprecationLink string
+
+	// DocsUI serves Swagger UI for the OpenAPI document at /docs.
+	DocsUI bool
+}
+
+// PublicConfig holds configuration for the anonymous public catalogue.
+type PublicConfig struct {
+	// CacheMaxAge is how long browsers may cache public responses, in seconds.
+	CacheMaxAge int
+
+	// CDNMaxAge is how long shared caches such as a CDN may cache public
+	// responses, in seconds.
+	CDNMaxAge int
+
+	// CartSecret signs the cart tokens of anonymous storefront carts with
+	// HMAC-SHA256. Empty disables signed carts.
+	CartSecret string
+
+	// CartTTL is how long cart tokens are valid, in seconds.
+	CartTTL int
+}
+
+// HTTPClientConfig holds configuration for the shared client used for
+// outbound HTTP calls.
+type HTTPClientConfig struct {
+	// Timeout bounds a whole request, including reading the response body.
+	// Zero disables it.
+	Timeout time.Duration
+
+	// DialTimeout bounds establishing a TCP connection.
+	DialTimeout time.Duration
+
+	// KeepAlive is the interval between TCP keep-alive probes on open connections.
+	KeepAlive time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds waiting for response headers once the
+	// request is sent. Zero disables it.
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnTimeout is how long an idle pooled connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// MaxIdleConns limits idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections kept per host.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits connections per host, including active ones.
+	// Zero means no limit.
+	MaxConnsPerHost int
+}
+
+// ShadowConfig holds configuration for mirroring read traffic to a secondary
+// deployment, such as a refactored build, and comparing its responses.
+type ShadowConfig struct {
+	// TargetURL is the base URL of the secondary deployment. Empty disables
+	// shadowing.
+	TargetURL string
+
+	// Percent is the share of read requests mirrored, from 0 to 100.
+	Percent float64
+
+	// Timeout bounds each mirrored request.
+	Timeout time.Duration
+
+	// MaxConcurrent bounds the mirrored requests in flight.
+	MaxConcurrent int
+
+	// MaxBodyBytes bounds the response bodies compared.
+	MaxBodyBytes int
+
+	// IgnoreFields names JSON fields left out of comparisons, such as
+	// timestamps generated per response.
+	IgnoreFields []string
+}
+
+// FlashSaleConfig holds configuration for limiting orders of products on a
+// flash sale to the quantity allocated to it.
+type FlashSaleConfig struct {
+	// Enabled turns on flash sale reservations. Orders then reserve the
+	// quantities of products on a flash sale by locking their rows.
+	Enabled bool
+
+	// CounterProducts names hot products reserved on Redis counters rather
+	// than by locking their rows. Requires Redis.
+	CounterProducts []string
+
+	// ReconcileInterval is how often counter reservations are counted as sold.
+	ReconcileInterval time.Duration
+
+	// ReconcileBatchSize is the number of reservations counted at once.
+	ReconcileBatchSize int
+}
+
+// UpsellConfig holds configuration for the products recommended after an
+// order, such as in the post-purchase email.
+type UpsellConfig struct {
+	// DefaultLimit is the number of products recommended when the request
+	// does not set a limit, and MaxLimit the most it may ask for.
+	DefaultLimit int
+	MaxLimit     int
+
+	// PerCategoryLimit caps the products recommended from any one of the
+	// order's categories, so one category does not fill the list. Zero
+	// disables the cap.
+	PerCategoryLimit int
+
+	// SalesWindowDays is how many days of orders products are ranked by.
+	// Zero ranks by every order.
+	SalesWindowDays int
+}
+
+// ExportConfig holds configuration for the nightly order export to the data
+// warehouse.
+type ExportConfig struct {
+	// Bucket and Region locate the S3 bucket export files are written to.
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every export file key (e.g. "exports/orders/").
+	Prefix string
+
+	// BatchSize is the number of orders read from the database per query.
+	BatchSize int
+
+	// SafetyLag leaves orders updated this recently for the next run, so
+	// transactions still committing when the export starts are not skipped.
+	SafetyLag time.Duration
 }
 
 // Load loads configuration from environment variables.
@@ -58,32 +804,219 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnvAsInt("SERVER_PORT", 8080),
+
+			ReadTimeout:       time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 15)) * time.Second,
+			ReadHeaderTimeout: time.Duration(getEnvAsInt("SERVER_READ_HEADER_TIMEOUT", 5)) * time.Second,
+			WriteTimeout:      time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 15)) * time.Second,
+			IdleTimeout:       time.Duration(getEnvAsInt("SERVER_IDLE_TIMEOUT", 60)) * time.Second,
+			MaxHeaderBytes:    getEnvAsInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+			MaxBodyBytes:      int64(getEnvAsInt("SERVER_MAX_BODY_BYTES", 1<<20)),
+			RequestTimeout:    time.Duration(getEnvAsInt("SERVER_REQUEST_TIMEOUT", 10)) * time.Second,
 		},
-		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnvAsInt("DB_PORT", 5432),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", ""),
-			Database:        getEnv("DB_NAME", "minikart"),
-			MaxConnections:  getEnvAsInt("DB_MAX_CONNECTIONS", 25),
-			MinConnections:  getEnvAsInt("DB_MIN_CONNECTIONS", 5),
-			MaxConnLifetime: getEnvAsInt("DB_MAX_CONN_LIFETIME", 300),
-		},
+		Database: LoadDatabase(),
 		Logger: LoggerConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:             getEnv("LOG_LEVEL", "info"),
+			Format:            getEnv("LOG_FORMAT", "json"),
+			Sinks:             getEnvAsSlice("LOG_SINKS", []string{"stdout"}),
+			FilePath:          getEnv("LOG_FILE_PATH", ""),
+			FileMaxSizeMB:     getEnvAsInt("LOG_FILE_MAX_SIZE_MB", 100),
+			FileMaxBackups:    getEnvAsInt("LOG_FILE_MAX_BACKUPS", 5),
+			SyslogAddress:     getEnv("LOG_SYSLOG_ADDRESS", ""),
+			SyslogTag:         getEnv("LOG_SYSLOG_TAG", "mini-kart"),
+			HTTPEndpoint:      getEnv("LOG_HTTP_ENDPOINT", ""),
+			HTTPFormat:        getEnv("LOG_HTTP_FORMAT", logging.FormatNDJSON),
+			HTTPBatchSize:     getEnvAsInt("LOG_HTTP_BATCH_SIZE", 500),
+			HTTPFlushInterval: time.Duration(getEnvAsInt("LOG_HTTP_FLUSH_INTERVAL", 5)) * time.Second,
 		},
 		Auth: AuthConfig{
-			APIKey: getEnv("API_KEY", ""),
+			APIKey:                 getEnv("API_KEY", ""),
+			AdminKeys:              getEnvAsSlice("ADMIN_API_KEYS", nil),
+			AdminOnlyProductWrites: getEnvAsBool("PRODUCT_WRITES_ADMIN_ONLY", false),
+			TenantKeys:             getEnvAsSlice("TENANT_API_KEYS", nil),
+		},
+		RateLimit: RateLimitConfig{
+			Requests: getEnvAsInt("RATE_LIMIT_REQUESTS", 0),
+			Window:   getEnvAsInt("RATE_LIMIT_WINDOW", 60),
+		},
+		Redis: RedisConfig{
+			Addr:       getEnv("REDIS_ADDR", ""),
+			Password:   getEnv("REDIS_PASSWORD", ""),
+			DB:         getEnvAsInt("REDIS_DB", 0),
+			ProductTTL: getEnvAsInt("PRODUCT_CACHE_TTL", 60),
+		},
+		Quota: QuotaConfig{
+			MonthlyRequests: getEnvAsInt("QUOTA_MONTHLY_REQUESTS", 0),
+			MonthlyOrders:   getEnvAsInt("QUOTA_MONTHLY_ORDERS", 0),
+			Enforce:         getEnvAsBool("QUOTA_ENFORCE", false),
+			WebhookURL:      getEnv("QUOTA_WEBHOOK_URL", ""),
 		},
 		S3: S3Config{
 			Enabled: getEnvAsBool("S3_ENABLED", false),
 			Bucket:  getEnv("S3_BUCKET", ""),
 			Region:  getEnv("S3_REGION", "us-east-1"),
 			Prefix:  getEnv("S3_PREFIX", "coupons/"),
+
+			PartSizeMB:          getEnvAsInt("S3_PART_SIZE_MB", 16),
+			DownloadConcurrency: getEnvAsInt("S3_DOWNLOAD_CONCURRENCY", 4),
+			MaxAttempts:         getEnvAsInt("S3_MAX_ATTEMPTS", 4),
+			RetryDelay:          getEnvAsInt("S3_RETRY_DELAY_MS", 200),
+			MaxRetryDelay:       getEnvAsInt("S3_MAX_RETRY_DELAY_MS", 5000),
+		},
+		GCS: GCSConfig{
+			Bucket: getEnv("GCS_BUCKET", ""),
+		},
+		Azure: AzureBlobConfig{
+			AccountURL: getEnv("AZURE_STORAGE_ACCOUNT_URL", ""),
+			Container:  getEnv("AZURE_STORAGE_CONTAINER", ""),
+		},
+		Cache: CouponCacheConfig{
+			Dir:      getEnv("COUPON_CACHE_DIR", ""),
+			MaxMB:    getEnvAsInt("COUPON_CACHE_MAX_MB", 4096),
+			MaxFiles: getEnvAsInt("COUPON_CACHE_MAX_FILES", 10),
+			MaxAge:   getEnvAsInt("COUPON_CACHE_MAX_AGE", 86400),
+		},
+		Coupon: LoadCoupon(),
+		Health: HealthConfig{
+			ProbeInterval:     getEnvAsInt("HEALTH_PROBE_INTERVAL", 10),
+			ProbeTimeout:      getEnvAsInt("HEALTH_PROBE_TIMEOUT", 2),
+			HistorySize:       getEnvAsInt("HEALTH_HISTORY_SIZE", 60),
+			FailureThreshold:  getEnvAsInt("HEALTH_FAILURE_THRESHOLD", 3),
+			RecoveryThreshold: getEnvAsInt("HEALTH_RECOVERY_THRESHOLD", 2),
+			FlapThreshold:     getEnvAsInt("HEALTH_FLAP_THRESHOLD", 6),
+		},
+		Metrics: MetricsConfig{
+			CacheReportInterval: getEnvAsInt("CACHE_REPORT_INTERVAL", 300),
+			OTLPEndpoint:        getEnv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")),
+			OTLPInterval:        getEnvAsInt("OTEL_METRICS_EXPORT_INTERVAL", 60),
+		},
+		Order: OrderConfig{
+			AllowedSources: getEnvAsSlice("ORDER_SOURCES", []string{"web", "mobile", "pos", "marketplace:*"}),
+			UnknownFields:  getEnv("ORDER_UNKNOWN_FIELDS", "preserve"),
+			MaxInFlight:    getEnvAsInt("ORDER_MAX_IN_FLIGHT", 0),
+			AsyncCallers:   getEnvAsSlice("ORDER_ASYNC_CALLERS", nil),
+			AsyncTimeout:   getEnvAsInt("ORDER_ASYNC_TIMEOUT", 60),
+
+			PendingSLA:        getEnvAsInt("ORDER_SLA_PENDING", 3600),
+			ConfirmedSLA:      getEnvAsInt("ORDER_SLA_CONFIRMED", 172800),
+			SLAWarningPercent: getEnvAsInt("ORDER_SLA_WARNING_PERCENT", 80),
+			SLACheckInterval:  getEnvAsInt("ORDER_SLA_CHECK_INTERVAL", 60),
+			SLAWebhookURL:     getEnv("ORDER_SLA_WEBHOOK_URL", ""),
+
+			ImportEnabled: getEnvAsBool("ORDER_IMPORT_ENABLED", false),
+
+			IdempotencyKeyTTL:        getEnvAsInt("ORDER_IDEMPOTENCY_KEY_TTL", 86400),
+			IdempotencySweepInterval: getEnvAsInt("ORDER_IDEMPOTENCY_SWEEP_INTERVAL", 3600),
+		},
+		Snapshot: SnapshotConfig{
+			SigningKey:    getEnv("SNAPSHOT_SIGNING_KEY", ""),
+			Bucket:        getEnv("SNAPSHOT_S3_BUCKET", ""),
+			Region:        getEnv("SNAPSHOT_S3_REGION", getEnv("S3_REGION", "us-east-1")),
+			Prefix:        getEnv("SNAPSHOT_S3_PREFIX", "snapshots/orders/"),
+			LockMode:      getEnv("SNAPSHOT_LOCK_MODE", "COMPLIANCE"),
+			RetentionDays: getEnvAsInt("SNAPSHOT_RETENTION_DAYS", 2555),
+		},
+		Images: ProductImageConfig{
+			Bucket:       getEnv("PRODUCT_IMAGE_S3_BUCKET", ""),
+			Region:       getEnv("PRODUCT_IMAGE_S3_REGION", getEnv("S3_REGION", "us-east-1")),
+			Prefix:       getEnv("PRODUCT_IMAGE_S3_PREFIX", "images/products/"),
+			BaseURL:      getEnv("PRODUCT_IMAGE_BASE_URL", ""),
+			UploadExpiry: getEnvAsInt("PRODUCT_IMAGE_UPLOAD_EXPIRY", 900),
+			MaxBytes:     getEnvAsInt("PRODUCT_IMAGE_MAX_BYTES", 5*1024*1024),
+		},
+		Webhook: WebhookConfig{
+			URLs:          getEnvAsSlice("ORDER_WEBHOOK_URLS", nil),
+			Secret:        getEnv("ORDER_WEBHOOK_SECRET", ""),
+			MaxAttempts:   getEnvAsInt("ORDER_WEBHOOK_MAX_ATTEMPTS", 10),
+			RetryDelay:    time.Duration(getEnvAsInt("ORDER_WEBHOOK_RETRY_DELAY", 10)) * time.Second,
+			MaxRetryDelay: time.Duration(getEnvAsInt("ORDER_WEBHOOK_MAX_RETRY_DELAY", 3600)) * time.Second,
+			PollInterval:  time.Duration(getEnvAsInt("ORDER_WEBHOOK_POLL_INTERVAL", 5)) * time.Second,
+			BatchSize:     getEnvAsInt("ORDER_WEBHOOK_BATCH_SIZE", 50),
+			Timeout:       time.Duration(getEnvAsInt("ORDER_WEBHOOK_TIMEOUT", 10)) * time.Second,
+		},
+		Saga: SagaConfig{
+			PaymentURL:    getEnv("ORDER_SAGA_PAYMENT_URL", ""),
+			StockURL:      getEnv("ORDER_SAGA_STOCK_URL", ""),
+			CouponURL:     getEnv("ORDER_SAGA_COUPON_URL", ""),
+			Timeout:       time.Duration(getEnvAsInt("ORDER_SAGA_TIMEOUT", 300)) * time.Second,
+			MaxAttempts:   getEnvAsInt("ORDER_SAGA_MAX_ATTEMPTS", 10),
+			RetryDelay:    time.Duration(getEnvAsInt("ORDER_SAGA_RETRY_DELAY", 10)) * time.Second,
+			MaxRetryDelay: time.Duration(getEnvAsInt("ORDER_SAGA_MAX_RETRY_DELAY", 3600)) * time.Second,
+			PollInterval:  time.Duration(getEnvAsInt("ORDER_SAGA_POLL_INTERVAL", 10)) * time.Second,
+			BatchSize:     getEnvAsInt("ORDER_SAGA_BATCH_SIZE", 50),
+		},
+		Events: EventsConfig{
+			KafkaBrokers:   getEnvAsSlice("EVENTS_KAFKA_BROKERS", nil),
+			KafkaTopic:     getEnv("EVENTS_KAFKA_TOPIC", "mini-kart.events"),
+			KafkaTLS:       getEnvAsBool("EVENTS_KAFKA_TLS", false),
+			RelayInterval:  time.Duration(getEnvAsInt("EVENTS_RELAY_INTERVAL_MS", 1000)) * time.Millisecond,
+			RelayBatchSize: getEnvAsInt("EVENTS_RELAY_BATCH_SIZE", 100),
+			PublishTimeout: time.Duration(getEnvAsInt("EVENTS_PUBLISH_TIMEOUT", 10)) * time.Second,
+		},
+		Pricing: PricingConfig{
+			ApprovalThreshold:     getEnvAsInt("PRICE_APPROVAL_THRESHOLD", 20),
+			Currency:              getEnv("PRICING_CURRENCY", "AUD"),
+			ExchangeRates:         getEnvAsSlice("PRICING_EXCHANGE_RATES", nil),
+			ExchangeRatesURL:      getEnv("PRICING_EXCHANGE_RATES_URL", ""),
+			ExchangeRatesTTL:      time.Duration(getEnvAsInt("PRICING_EXCHANGE_RATES_TTL", 3600)) * time.Second,
+			ShippingFlatRateCents: getEnvAsInt("SHIPPING_FLAT_RATE_CENTS", 0),
+		},
+		TLS: TLSConfig{
+			Enabled:      getEnvAsBool("TLS_ENABLED", false),
+			CertFile:     getEnv("TLS_CERT_FILE", ""),
+			KeyFile:      getEnv("TLS_KEY_FILE", ""),
+			ClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+			ClientAuth:   getEnv("TLS_CLIENT_AUTH", "none"),
+		},
+		API: APIConfig{
+			UnversionedDeprecated: getEnvAsDate("API_UNVERSIONED_DEPRECATED"),
+			UnversionedSunset:     getEnvAsDate("API_UNVERSIONED_SUNSET"),
+			DeprecationLink:       getEnv("API_DEPRECATION_LINK", ""),
+			DocsUI:                getEnvAsBool("API_DOCS_UI", false),
+		},
+		Public: PublicConfig{
+			CacheMaxAge: getEnvAsInt("PUBLIC_CACHE_MAX_AGE", 60),
+			CDNMaxAge:   getEnvAsInt("PUBLIC_CDN_MAX_AGE", 300),
+			CartSecret:  getEnv("CART_TOKEN_SECRET", ""),
+			CartTTL:     getEnvAsInt("CART_TOKEN_TTL", 604800),
+		},
+		HTTP: HTTPClientConfig{
+			Timeout:               time.Duration(getEnvAsInt("HTTP_CLIENT_TIMEOUT", 30)) * time.Second,
+			DialTimeout:           time.Duration(getEnvAsInt("HTTP_CLIENT_DIAL_TIMEOUT", 5)) * time.Second,
+			KeepAlive:             time.Duration(getEnvAsInt("HTTP_CLIENT_KEEP_ALIVE", 30)) * time.Second,
+			TLSHandshakeTimeout:   time.Duration(getEnvAsInt("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", 5)) * time.Second,
+			ResponseHeaderTimeout: time.Duration(getEnvAsInt("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT", 10)) * time.Second,
+			IdleConnTimeout:       time.Duration(getEnvAsInt("HTTP_CLIENT_IDLE_CONN_TIMEOUT", 90)) * time.Second,
+			MaxIdleConns:          getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:   getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", 10),
+			MaxConnsPerHost:       getEnvAsInt("HTTP_CLIENT_MAX_CONNS_PER_HOST", 0),
+		},
+		Shadow: ShadowConfig{
+			TargetURL:     getEnv("SHADOW_TARGET_URL", ""),
+			Percent:       getEnvAsFloat("SHADOW_PERCENT", 10),
+			Timeout:       time.Duration(getEnvAsInt("SHADOW_TIMEOUT_MS", 5000)) * time.Millisecond,
+			MaxConcurrent: getEnvAsInt("SHADOW_MAX_CONCURRENT", 10),
+			MaxBodyBytes:  getEnvAsInt("SHADOW_MAX_BODY_BYTES", 1024*1024),
+			IgnoreFields:  getEnvAsSlice("SHADOW_IGNORE_FIELDS", nil),
+		},
+		FlashSale: FlashSaleConfig{
+			Enabled:            getEnvAsBool("FLASH_SALES_ENABLED", false),
+			CounterProducts:    getEnvAsSlice("FLASH_SALE_COUNTER_PRODUCTS", nil),
+			ReconcileInterval:  time.Duration(getEnvAsInt("FLASH_SALE_RECONCILE_INTERVAL_MS", 5000)) * time.Millisecond,
+			ReconcileBatchSize: getEnvAsInt("FLASH_SALE_RECONCILE_BATCH_SIZE", 500),
+		},
+		Upsell: UpsellConfig{
+			DefaultLimit:     getEnvAsInt("UPSELL_DEFAULT_LIMIT", 4),
+			MaxLimit:         getEnvAsInt("UPSELL_MAX_LIMIT", 20),
+			PerCategoryLimit: getEnvAsInt("UPSELL_PER_CATEGORY_LIMIT", 2),
+			SalesWindowDays:  getEnvAsInt("UPSELL_SALES_WINDOW_DAYS", 90),
 		},
 	}
 
+	// A single admin key may be given on its own, without a name
+	if key := getEnv("ADMIN_API_KEY", ""); key != "" {
+		cfg.Auth.AdminKeys = append(cfg.Auth.AdminKeys, "admin:"+key)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
@@ -91,12 +1024,107 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// LoadDatabase loads only the database configuration from environment
+// variables, for tools that need a connection but not the full API configuration.
+func LoadDatabase() DatabaseConfig {
+	return DatabaseConfig{
+		Host:            getEnv("DB_HOST", "localhost"),
+		Port:            getEnvAsInt("DB_PORT", 5432),
+		User:            getEnv("DB_USER", "postgres"),
+		Password:        getEnv("DB_PASSWORD", ""),
+		Database:        getEnv("DB_NAME", "minikart"),
+		MaxConnections:  getEnvAsInt("DB_MAX_CONNECTIONS", 25),
+		MinConnections:  getEnvAsInt("DB_MIN_CONNECTIONS", 5),
+		MaxConnLifetime: getEnvAsInt("DB_MAX_CONN_LIFETIME", 300),
+
+		SSLMode:         getEnv("DB_SSL_MODE", "disable"),
+		SSLRootCert:     getEnv("DB_SSL_ROOT_CERT", ""),
+		ApplicationName: getEnv("DB_APPLICATION_NAME", "mini-kart"),
+		SearchPath:      getEnv("DB_SEARCH_PATH", ""),
+		ConnectTimeout:  getEnvAsInt("DB_CONNECT_TIMEOUT", 10),
+		StartupTimeout:  getEnvAsInt("DB_STARTUP_TIMEOUT", 60),
+		Warmup:          getEnvAsBool("DB_WARMUP", false),
+
+		BreakerFailureThreshold: getEnvAsInt("DB_BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerCooldown:         getEnvAsInt("DB_BREAKER_COOLDOWN", 10),
+	}
+}
+
+// LoadCoupon loads only the coupon configuration from environment variables,
+// for tools that validate promo codes like the API does.
+func LoadCoupon() CouponConfig {
+	return CouponConfig{
+		Source:              getEnv("COUPON_SOURCE", defaultCouponSource()),
+		StorageProvider:     getEnv("COUPON_STORAGE_PROVIDER", "s3"),
+		IndexDir:            getEnv("COUPON_INDEX_DIR", ""),
+		Files:               getEnvAsSlice("COUPON_FILES", nil),
+		MinMatchCount:       getEnvAsInt("COUPON_MIN_MATCH_COUNT", 2),
+		FileWeights:         getEnvAsSlice("COUPON_FILE_WEIGHTS", nil),
+		MemoryLimitMB:       getEnvAsInt("COUPON_MEMORY_LIMIT_MB", 0),
+		MemoryCheckInterval: getEnvAsInt("COUPON_MEMORY_CHECK_INTERVAL_MS", 250),
+		SetType:             getEnv("COUPON_SET_TYPE", "map"),
+		ExpectedCodes:       getEnvAsInt("COUPON_EXPECTED_CODES", 100_000_000),
+		FalsePositiveRate:   getEnvAsFloat("COUPON_FALSE_POSITIVE_RATE", 0.001),
+		CodePattern:         getEnv("COUPON_CODE_PATTERN", `^[A-Za-z0-9]*$`),
+		CaseInsensitive:     getEnvAsBool("COUPON_CASE_INSENSITIVE", false),
+		DeltaDir:            getEnv("COUPON_DELTA_DIR", ""),
+		DeltaInterval:       getEnvAsInt("COUPON_DELTA_INTERVAL", 300),
+		ValidationTimeout:   getEnvAsInt("COUPON_VALIDATION_TIMEOUT_MS", 50),
+		FailurePolicy:       getEnv("COUPON_FAILURE_POLICY", defaultCouponFailurePolicy()),
+		LoadInBackground:    getEnvAsBool("COUPON_LOAD_IN_BACKGROUND", true),
+		ArchiveDir:          getEnv("COUPON_ARCHIVE_DIR", ""),
+		ArchivePrefix:       getEnv("COUPON_ARCHIVE_PREFIX", ""),
+	}
+}
+
+// LoadExport loads only the order export configuration from environment
+// variables, for the order export command.
+func LoadExport() ExportConfig {
+	return ExportConfig{
+		Bucket:    getEnv("EXPORT_S3_BUCKET", ""),
+		Region:    getEnv("EXPORT_S3_REGION", getEnv("S3_REGION", "us-east-1")),
+		Prefix:    getEnv("EXPORT_S3_PREFIX", "exports/orders/"),
+		BatchSize: getEnvAsInt("EXPORT_BATCH_SIZE", 1000),
+		SafetyLag: time.Duration(getEnvAsInt("EXPORT_SAFETY_LAG", 300)) * time.Second,
+	}
+}
+
 // Validate validates the configuration.
 func (c *Config) Validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return fmt.Errorf("server timeouts must not be negative")
+	}
+
+	if c.Server.ReadTimeout > 0 && c.Server.ReadHeaderTimeout > c.Server.ReadTimeout {
+		return fmt.Errorf("server read header timeout cannot exceed read timeout")
+	}
+
+	if c.Server.MaxHeaderBytes < 0 {
+		return fmt.Errorf("server max header bytes must not be negative")
+	}
+
+	if c.Server.MaxBodyBytes < 0 || c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server max body bytes and request timeout must not be negative")
+	}
+
+	// A request timing out must still have time to be answered with 504
+	if c.Server.RequestTimeout > 0 && c.Server.WriteTimeout > 0 && c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		return fmt.Errorf("server request timeout must be shorter than the write timeout")
+	}
+
+	if c.HTTP.Timeout < 0 || c.HTTP.DialTimeout < 0 || c.HTTP.KeepAlive < 0 || c.HTTP.TLSHandshakeTimeout < 0 ||
+		c.HTTP.ResponseHeaderTimeout < 0 || c.HTTP.IdleConnTimeout < 0 {
+		return fmt.Errorf("HTTP client timeouts must not be negative")
+	}
+
+	if c.HTTP.MaxIdleConns < 0 || c.HTTP.MaxIdleConnsPerHost < 0 || c.HTTP.MaxConnsPerHost < 0 {
+		return fmt.Errorf("HTTP client connection limits must not be negative")
+	}
+
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
@@ -125,18 +1153,65 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("database min connections cannot exceed max connections")
 	}
 
+	switch c.Database.SSLMode {
+	case "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		return fmt.Errorf("invalid database SSL mode: %s (must be disable, allow, prefer, require, verify-ca or verify-full)", c.Database.SSLMode)
+	}
+
+	if c.Database.SSLRootCert != "" && c.Database.SSLMode == "disable" {
+		return fmt.Errorf("database SSL root certificate requires an SSL mode other than disable")
+	}
+
+	if c.Database.ConnectTimeout < 0 {
+		return fmt.Errorf("database connect timeout must not be negative")
+	}
+
+	if c.Database.StartupTimeout < 0 {
+		return fmt.Errorf("database startup timeout must not be negative")
+	}
+
+	if c.Database.BreakerFailureThreshold < 0 {
+		return fmt.Errorf("database breaker failure threshold must not be negative")
+	}
+
+	if c.Database.BreakerFailureThreshold > 0 && c.Database.BreakerCooldown < 1 {
+		return fmt.Errorf("database breaker cooldown must be at least 1 second")
+	}
+
 	if c.Auth.APIKey == "" {
 		return fmt.Errorf("API key is required")
 	}
 
-	validLogLevels := map[string]bool{
-		"debug": true,
-		"info":  true,
-		"warn":  true,
-		"error": true,
+	// Every key identifies one caller
+	keys := make(map[string]bool, len(c.Auth.AdminKeys)+len(c.Auth.TenantKeys))
+	for _, entry := range c.Auth.AdminKeys {
+		name, key, ok := strings.Cut(entry, ":")
+		if !ok || name == "" || key == "" {
+			return fmt.Errorf("invalid admin API key entry (must be name:key)")
+		}
+		if key == c.Auth.APIKey || keys[key] {
+			return fmt.Errorf("admin API keys must be unique and differ from the API key")
+		}
+		keys[key] = true
+	}
+
+	if c.Auth.AdminOnlyProductWrites && len(c.Auth.AdminKeys) == 0 {
+		return fmt.Errorf("admin-only product writes require at least one admin API key")
+	}
+
+	for _, entry := range c.Auth.TenantKeys {
+		tenant, key, ok := strings.Cut(entry, ":")
+		if !ok || !model.ValidTenantID(tenant) || key == "" {
+			return fmt.Errorf("invalid tenant API key entry (must be tenant:key with a lowercase tenant ID)")
+		}
+		if key == c.Auth.APIKey || keys[key] {
+			return fmt.Errorf("tenant API keys must be unique and differ from the API key and admin keys")
+		}
+		keys[key] = true
 	}
 
-	if !validLogLevels[c.Logger.Level] {
+	if _, err := logging.ParseLevel(c.Logger.Level); err != nil {
 		return fmt.Errorf("invalid log level: %s (must be debug, info, warn, or error)", c.Logger.Level)
 	}
 
@@ -144,6 +1219,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log format: %s (must be json or console)", c.Logger.Format)
 	}
 
+	if err := c.validateLogSinks(); err != nil {
+		return err
+	}
+
 	if c.S3.Enabled {
 		if c.S3.Bucket == "" {
 			return fmt.Errorf("S3 bucket is required when S3 is enabled")
@@ -151,21 +1230,489 @@ func (c *Config) Validate() error {
 		if c.S3.Region == "" {
 			return fmt.Errorf("S3 region is required when S3 is enabled")
 		}
+		if c.S3.PartSizeMB < 0 {
+			return fmt.Errorf("S3 part size must not be negative")
+		}
+		if c.S3.DownloadConcurrency < 1 {
+			return fmt.Errorf("S3 download concurrency must be at least 1")
+		}
+		if c.S3.MaxAttempts < 1 {
+			return fmt.Errorf("S3 max attempts must be at least 1")
+		}
+		if c.S3.RetryDelay < 0 || c.S3.MaxRetryDelay < 0 {
+			return fmt.Errorf("S3 retry delays must not be negative")
+		}
+	}
+
+	if c.Order.UnknownFields != "" && c.Order.UnknownFields != "preserve" && c.Order.UnknownFields != "reject" {
+		return fmt.Errorf("invalid order unknown fields mode: %s (must be preserve or reject)", c.Order.UnknownFields)
+	}
+
+	if c.Order.MaxInFlight < 0 {
+		return fmt.Errorf("order max in-flight must not be negative")
+	}
+
+	if len(c.Order.AsyncCallers) > 0 && c.Order.AsyncTimeout < 1 {
+		return fmt.Errorf("order async timeout must be at least 1 second")
+	}
+
+	if c.Order.PendingSLA < 0 || c.Order.ConfirmedSLA < 0 {
+		return fmt.Errorf("order SLAs must not be negative")
+	}
+
+	if c.Order.PendingSLA > 0 || c.Order.ConfirmedSLA > 0 {
+		if c.Order.SLAWarningPercent < 1 || c.Order.SLAWarningPercent > 100 {
+			return fmt.Errorf("order SLA warning percent must be between 1 and 100")
+		}
+		if c.Order.SLACheckInterval < 1 {
+			return fmt.Errorf("order SLA check interval must be at least 1 second")
+		}
+	}
+
+	if c.Order.IdempotencyKeyTTL < 0 {
+		return fmt.Errorf("order idempotency key TTL must not be negative")
+	}
+	if c.Order.IdempotencyKeyTTL > 0 && c.Order.IdempotencySweepInterval < 1 {
+		return fmt.Errorf("order idempotency sweep interval must be at least 1 second")
+	}
+
+	if c.Order.SLAWebhookURL != "" {
+		if u, err := url.Parse(c.Order.SLAWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid order SLA webhook URL: %s (must be an http or https URL)", c.Order.SLAWebhookURL)
+		}
+	}
+
+	if c.Snapshot.Bucket != "" {
+		if c.Snapshot.SigningKey == "" {
+			return fmt.Errorf("snapshot signing key is required when a snapshot bucket is set")
+		}
+		if c.Snapshot.Region == "" {
+			return fmt.Errorf("snapshot S3 region is required when a snapshot bucket is set")
+		}
+		if c.Snapshot.LockMode != "COMPLIANCE" && c.Snapshot.LockMode != "GOVERNANCE" {
+			return fmt.Errorf("invalid snapshot lock mode: %s (must be COMPLIANCE or GOVERNANCE)", c.Snapshot.LockMode)
+		}
+		if c.Snapshot.RetentionDays < 1 {
+			return fmt.Errorf("snapshot retention must be at least 1 day")
+		}
+	}
+
+	if c.Images.Bucket != "" {
+		if c.Images.Region == "" {
+			return fmt.Errorf("product image S3 region is required when a product image bucket is set")
+		}
+		if c.Images.BaseURL != "" {
+			if u, err := url.Parse(c.Images.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid product image base URL: %s (must be an http or https URL)", c.Images.BaseURL)
+			}
+		}
+		// S3 refuses presigned URLs valid for more than 7 days
+		if c.Images.UploadExpiry < 1 || c.Images.UploadExpiry > 604800 {
+			return fmt.Errorf("product image upload expiry must be between 1 and 604800 seconds")
+		}
+		if c.Images.MaxBytes < 1 {
+			return fmt.Errorf("product image max bytes must be positive")
+		}
+	}
+
+	if len(c.Webhook.URLs) > 0 {
+		if c.Webhook.Secret == "" {
+			return fmt.Errorf("order webhook secret is required when webhook URLs are set")
+		}
+		for _, endpoint := range c.Webhook.URLs {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid order webhook URL: %s (must be an http or https URL)", endpoint)
+			}
+		}
+		if c.Webhook.MaxAttempts < 1 {
+			return fmt.Errorf("order webhook max attempts must be at least 1")
+		}
+		if c.Webhook.RetryDelay < time.Second || c.Webhook.MaxRetryDelay < c.Webhook.RetryDelay {
+			return fmt.Errorf("order webhook retry delay must be at least 1 second and not exceed the max retry delay")
+		}
+		if c.Webhook.PollInterval < time.Second || c.Webhook.Timeout < time.Second {
+			return fmt.Errorf("order webhook poll interval and timeout must be at least 1 second")
+		}
+		if c.Webhook.BatchSize < 1 {
+			return fmt.Errorf("order webhook batch size must be at least 1")
+		}
+	}
+
+	if c.Saga.Enabled() {
+		for _, endpoint := range []string{c.Saga.PaymentURL, c.Saga.StockURL, c.Saga.CouponURL} {
+			if endpoint == "" {
+				continue
+			}
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid order saga URL: %s (must be an http or https URL)", endpoint)
+			}
+		}
+		if c.Saga.Timeout < time.Second {
+			return fmt.Errorf("order saga timeout must be at least 1 second")
+		}
+		if c.Saga.MaxAttempts < 1 {
+			return fmt.Errorf("order saga max attempts must be at least 1")
+		}
+		if c.Saga.RetryDelay < time.Second || c.Saga.MaxRetryDelay < c.Saga.RetryDelay {
+			return fmt.Errorf("order saga retry delay must be at least 1 second and not exceed the max retry delay")
+		}
+		if c.Saga.PollInterval < time.Second {
+			return fmt.Errorf("order saga poll interval must be at least 1 second")
+		}
+		if c.Saga.BatchSize < 1 {
+			return fmt.Errorf("order saga batch size must be at least 1")
+		}
+	}
+
+	if len(c.Events.KafkaBrokers) > 0 {
+		if c.Events.KafkaTopic == "" {
+			return fmt.Errorf("events Kafka topic is required when Kafka brokers are set")
+		}
+		if c.Events.RelayInterval < time.Millisecond {
+			return fmt.Errorf("events relay interval must be at least 1 millisecond")
+		}
+		if c.Events.RelayBatchSize < 1 {
+			return fmt.Errorf("events relay batch size must be at least 1")
+		}
+		if c.Events.PublishTimeout < time.Second {
+			return fmt.Errorf("events publish timeout must be at least 1 second")
+		}
+	}
+
+	if c.Public.CacheMaxAge < 0 || c.Public.CDNMaxAge < 0 {
+		return fmt.Errorf("public cache max ages must not be negative")
+	}
+
+	if c.Public.CartSecret != "" && c.Public.CartTTL < 1 {
+		return fmt.Errorf("cart token TTL must be at least 1 second")
+	}
+
+	if c.Shadow.TargetURL != "" {
+		if u, err := url.Parse(c.Shadow.TargetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid shadow target URL: %s (must be an http or https URL)", c.Shadow.TargetURL)
+		}
+		if c.Shadow.Percent <= 0 || c.Shadow.Percent > 100 {
+			return fmt.Errorf("shadow percent must be greater than 0 and at most 100")
+		}
+		if c.Shadow.Timeout < time.Millisecond {
+			return fmt.Errorf("shadow timeout must be at least 1 millisecond")
+		}
+		if c.Shadow.MaxConcurrent < 1 {
+			return fmt.Errorf("shadow max concurrent requests must be at least 1")
+		}
+		if c.Shadow.MaxBodyBytes < 1 {
+			return fmt.Errorf("shadow max body bytes must be at least 1")
+		}
+	}
+
+	if c.FlashSale.Enabled {
+		if len(c.FlashSale.CounterProducts) > 0 && c.Redis.Addr == "" {
+			return fmt.Errorf("flash sale counter products require REDIS_ADDR")
+		}
+		if c.FlashSale.ReconcileInterval < time.Millisecond {
+			return fmt.Errorf("flash sale reconcile interval must be at least 1 millisecond")
+		}
+		if c.FlashSale.ReconcileBatchSize < 1 {
+			return fmt.Errorf("flash sale reconcile batch size must be at least 1")
+		}
+	} else if len(c.FlashSale.CounterProducts) > 0 {
+		return fmt.Errorf("flash sale counter products require FLASH_SALES_ENABLED")
+	}
+
+	if c.Pricing.ApprovalThreshold < 0 {
+		return fmt.Errorf("price approval threshold must not be negative")
+	}
+
+	if c.Cache.Dir != "" {
+		if c.Cache.MaxMB < 1 {
+			return fmt.Errorf("coupon cache max size must be at least 1 MB")
+		}
+		if c.Cache.MaxFiles < 1 {
+			return fmt.Errorf("coupon cache max files must be at least 1")
+		}
+		if c.Cache.MaxAge < 1 {
+			return fmt.Errorf("coupon cache max age must be at least 1 second")
+		}
+	}
+
+	if c.Coupon.MemoryLimitMB < 0 {
+		return fmt.Errorf("coupon memory limit must not be negative")
+	}
+
+	if c.Coupon.MemoryLimitMB > 0 && c.Coupon.MemoryCheckInterval < 1 {
+		return fmt.Errorf("coupon memory check interval must be at least 1 millisecond")
+	}
+
+	switch c.Coupon.Source {
+	case "", "file", "db", "index":
+	case "s3":
+		if provider := c.Coupon.StorageProvider; provider != "" && provider != "s3" {
+			return fmt.Errorf("coupon source s3 cannot use storage provider %s (use the storage source)", c.Coupon.StorageProvider)
+		}
+		if !c.S3.Enabled {
+			return fmt.Errorf("S3 must be enabled when the coupon source is s3")
+		}
+	case "storage":
+		if err := c.validateCouponStorage(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid coupon source: %s (must be file, storage, s3, db, or index)", c.Coupon.Source)
+	}
+
+	switch c.Coupon.SetType {
+	case "", "map":
+	case "bloom":
+		if c.Coupon.FalsePositiveRate <= 0 || c.Coupon.FalsePositiveRate >= 1 {
+			return fmt.Errorf("coupon false-positive rate must be between 0 and 1")
+		}
+	default:
+		return fmt.Errorf("invalid coupon set type: %s (must be map or bloom)", c.Coupon.SetType)
+	}
+
+	if c.Coupon.MinMatchCount < 0 {
+		return fmt.Errorf("coupon min match count must not be negative")
+	}
+
+	for _, entry := range c.Coupon.FileWeights {
+		name, value, ok := strings.Cut(entry, ":")
+		weight, err := strconv.Atoi(value)
+		if !ok || name == "" || err != nil || weight < 1 {
+			return fmt.Errorf("invalid coupon file weight: %s (must be name:weight with a positive weight)", entry)
+		}
+	}
+
+	if _, err := regexp.Compile(c.Coupon.CodePattern); err != nil {
+		return fmt.Errorf("invalid coupon code pattern: %w", err)
+	}
+
+	if c.Coupon.DeltaDir != "" && c.Coupon.DeltaInterval < 1 {
+		return fmt.Errorf("coupon delta interval must be at least 1 second")
+	}
+
+	if p := c.Coupon.FailurePolicy; p != "" && p != "fail-closed" && p != "fail-open" {
+		return fmt.Errorf("invalid coupon failure policy: %s (must be fail-closed or fail-open)", p)
+	}
+
+	if c.Coupon.ValidationTimeout < 0 {
+		return fmt.Errorf("coupon validation timeout must not be negative")
+	}
+
+	if c.Coupon.ArchivePrefix != "" {
+		if c.Coupon.ArchiveDir != "" {
+			return fmt.Errorf("coupon archive directory and prefix cannot both be set")
+		}
+		if err := c.validateCouponStorage(); err != nil {
+			return err
+		}
+	}
+
+	if c.TLS.Enabled {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			return fmt.Errorf("TLS certificate and key files are required when TLS is enabled")
+		}
+		switch c.TLS.ClientAuth {
+		case "none":
+		case "request", "require":
+			if c.TLS.ClientCAFile == "" {
+				return fmt.Errorf("TLS client CA file is required when client auth is %s", c.TLS.ClientAuth)
+			}
+		default:
+			return fmt.Errorf("invalid TLS client auth: %s (must be none, request, or require)", c.TLS.ClientAuth)
+		}
+	}
+
+	if c.Health.ProbeInterval < 1 || c.Health.ProbeTimeout < 1 {
+		return fmt.Errorf("health probe interval and timeout must be at least 1 second")
+	}
+
+	if c.Health.HistorySize < 1 {
+		return fmt.Errorf("health history size must be at least 1")
+	}
+
+	if c.Health.FailureThreshold < 1 || c.Health.RecoveryThreshold < 1 || c.Health.FlapThreshold < 1 {
+		return fmt.Errorf("health failure, recovery and flap thresholds must be at least 1")
+	}
+
+	if c.RateLimit.Requests < 0 {
+		return fmt.Errorf("rate limit requests must not be negative")
+	}
+
+	if c.RateLimit.Requests > 0 && c.RateLimit.Window < 1 {
+		return fmt.Errorf("rate limit window must be at least 1 second")
+	}
+
+	if c.Metrics.CacheReportInterval < 0 {
+		return fmt.Errorf("cache report interval must not be negative")
+	}
+
+	if c.Metrics.OTLPEndpoint != "" && c.Metrics.OTLPInterval < 1 {
+		return fmt.Errorf("metrics export interval must be at least 1 second")
+	}
+
+	if c.Redis.DB < 0 {
+		return fmt.Errorf("redis database must not be negative")
+	}
+
+	if c.Redis.ProductTTL < 0 {
+		return fmt.Errorf("product cache TTL must not be negative")
+	}
+
+	if c.Quota.MonthlyRequests < 0 || c.Quota.MonthlyOrders < 0 {
+		return fmt.Errorf("monthly quotas must not be negative")
+	}
+
+	if c.Quota.WebhookURL != "" {
+		if u, err := url.Parse(c.Quota.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid quota webhook URL: %s (must be an http or https URL)", c.Quota.WebhookURL)
+		}
+	}
+
+	if len(c.Pricing.Currency) != 3 {
+		return fmt.Errorf("invalid pricing currency: %s (must be a 3-letter ISO 4217 code)", c.Pricing.Currency)
+	}
+
+	if c.Pricing.ShippingFlatRateCents < 0 {
+		return fmt.Errorf("shipping flat rate must not be negative")
+	}
+
+	for _, entry := range c.Pricing.ExchangeRates {
+		code, value, ok := strings.Cut(entry, ":")
+		rate, err := strconv.ParseFloat(value, 64)
+		if !ok || len(code) != 3 || err != nil || !(rate > 0) || math.IsInf(rate, 0) {
+			return fmt.Errorf("invalid exchange rate: %s (must be code:rate with a 3-letter ISO 4217 code and a positive rate)", entry)
+		}
+	}
+
+	if c.Pricing.ExchangeRatesURL != "" {
+		if u, err := url.Parse(c.Pricing.ExchangeRatesURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid exchange rates URL: %s", c.Pricing.ExchangeRatesURL)
+		}
+	}
+
+	if c.Pricing.ConvertsCurrencies() && c.Pricing.ExchangeRatesTTL <= 0 {
+		return fmt.Errorf("exchange rates TTL must be positive")
+	}
+
+	if !c.API.UnversionedSunset.IsZero() {
+		if c.API.UnversionedDeprecated.IsZero() {
+			return fmt.Errorf("API sunset date requires a deprecation date")
+		}
+		if !c.API.UnversionedSunset.After(c.API.UnversionedDeprecated) {
+			return fmt.Errorf("API sunset date must be after the deprecation date")
+		}
+	}
+
+	if c.Upsell.DefaultLimit < 1 || c.Upsell.MaxLimit < c.Upsell.DefaultLimit {
+		return fmt.Errorf("upsell default limit must be at least 1 and no more than the max limit")
+	}
+	if c.Upsell.PerCategoryLimit < 0 || c.Upsell.SalesWindowDays < 0 {
+		return fmt.Errorf("upsell per-category limit and sales window must not be negative")
+	}
+
+	return nil
+}
+
+// Validate validates the order export configuration.
+func (c *ExportConfig) Validate() error {
+	if c.Bucket == "" {
+		return fmt.Errorf("export S3 bucket is required")
+	}
+
+	if c.BatchSize < 1 {
+		return fmt.Errorf("export batch size must be at least 1")
+	}
+
+	if c.SafetyLag < 0 {
+		return fmt.Errorf("export safety lag must not be negative")
 	}
 
 	return nil
 }
 
-// ConnectionString returns the PostgreSQL connection string.
+// ConnectionString returns the PostgreSQL connection string. Credentials
+// and options are escaped, so they may contain any characters.
 func (c *DatabaseConfig) ConnectionString() string {
-	return fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		c.User,
-		c.Password,
-		c.Host,
-		c.Port,
-		c.Database,
-	)
+	query := url.Values{}
+	if c.SSLMode != "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	if c.SSLRootCert != "" {
+		query.Set("sslrootcert", c.SSLRootCert)
+	}
+	if c.ApplicationName != "" {
+		query.Set("application_name", c.ApplicationName)
+	}
+	if c.SearchPath != "" {
+		query.Set("search_path", c.SearchPath)
+	}
+	if c.ConnectTimeout > 0 {
+		query.Set("connect_timeout", strconv.Itoa(c.ConnectTimeout))
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.Database,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// validateCouponStorage checks the object storage provider the storage coupon
+// source reads from is configured.
+func (c *Config) validateCouponStorage() error {
+	switch c.Coupon.StorageProvider {
+	case "", "s3":
+		if !c.S3.Enabled {
+			return fmt.Errorf("S3 must be enabled when the coupon storage provider is s3")
+		}
+	case "gcs":
+		if c.GCS.Bucket == "" {
+			return fmt.Errorf("GCS bucket is required when the coupon storage provider is gcs")
+		}
+	case "azure":
+		if c.Azure.AccountURL == "" || c.Azure.Container == "" {
+			return fmt.Errorf("Azure storage account URL and container are required when the coupon storage provider is azure")
+		}
+	default:
+		return fmt.Errorf("invalid coupon storage provider: %s (must be s3, gcs, or azure)", c.Coupon.StorageProvider)
+	}
+	return nil
+}
+
+// validateLogSinks checks that every configured log sink has what it needs.
+func (c *Config) validateLogSinks() error {
+	l := c.Logger
+	for _, sink := range l.Sinks {
+		switch sink {
+		case "stdout", "syslog":
+		case "file":
+			if l.FilePath == "" {
+				return fmt.Errorf("log file path is required when the file log sink is enabled")
+			}
+			if l.FileMaxSizeMB <= 0 || l.FileMaxBackups < 0 {
+				return fmt.Errorf("log file max size must be positive and max backups must not be negative")
+			}
+		case "http":
+			if l.HTTPEndpoint == "" {
+				return fmt.Errorf("log HTTP endpoint is required when the http log sink is enabled")
+			}
+			if u, err := url.Parse(l.HTTPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid log HTTP endpoint: %s", l.HTTPEndpoint)
+			}
+			if l.HTTPFormat != logging.FormatNDJSON && l.HTTPFormat != logging.FormatOTLP {
+				return fmt.Errorf("invalid log HTTP format: %s (must be ndjson or otlp)", l.HTTPFormat)
+			}
+			if l.HTTPBatchSize <= 0 || l.HTTPFlushInterval <= 0 {
+				return fmt.Errorf("log HTTP batch size and flush interval must be positive")
+			}
+		default:
+			return fmt.Errorf("invalid log sink: %s (must be stdout, file, syslog or http)", sink)
+		}
+	}
+	return nil
 }
 
 // Address returns the server address.
@@ -173,6 +1720,23 @@ func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// defaultCouponSource keeps deployments that only set S3_ENABLED loading from S3.
+func defaultCouponSource() string {
+	if getEnvAsBool("S3_ENABLED", false) {
+		return "s3"
+	}
+	return "file"
+}
+
+// defaultCouponFailurePolicy keeps deployments that only set
+// COUPON_VALIDATION_FAIL_OPEN accepting codes that could not be checked.
+func defaultCouponFailurePolicy() string {
+	if getEnvAsBool("COUPON_VALIDATION_FAIL_OPEN", false) {
+		return "fail-open"
+	}
+	return "fail-closed"
+}
+
 // getEnv retrieves an environment variable or returns a default value.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -191,6 +1755,32 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat retrieves an environment variable as a float or returns a default value.
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsSlice retrieves a comma-separated environment variable as a slice or returns a default value.
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
 // getEnvAsBool retrieves an environment variable as a boolean or returns a default value.
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
@@ -200,3 +1790,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsDate retrieves an environment variable as a YYYY-MM-DD date in UTC,
+// or returns the zero time if it is unset or invalid.
+func getEnvAsDate(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if date, err := time.Parse(time.DateOnly, value); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
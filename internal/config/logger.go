@@ -1,41 +1,115 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"time"
 
+	"mini-kart/internal/logging"
+
 	"github.com/rs/zerolog"
 )
 
-// NewLogger creates a new logger based on the configuration.
-func NewLogger(cfg LoggerConfig) zerolog.Logger {
-	// Set log level
-	var level zerolog.Level
-	switch cfg.Level {
-	case "debug":
-		level = zerolog.DebugLevel
-	case "info":
-		level = zerolog.InfoLevel
-	case "warn":
-		level = zerolog.WarnLevel
-	case "error":
-		level = zerolog.ErrorLevel
-	default:
+// NewLogger creates a new logger based on the configuration, writing to every
+// configured sink. The returned closer flushes and closes the sinks, and must
+// be closed once the logger is no longer used.
+func NewLogger(cfg LoggerConfig) (zerolog.Logger, io.Closer, error) {
+	// Validate rejects unknown levels, so only a config that skipped it
+	// falls back to info
+	level, err := logging.ParseLevel(cfg.Level)
+	if err != nil {
 		level = zerolog.InfoLevel
 	}
 
 	zerolog.SetGlobalLevel(level)
 
-	// Configure output format
-	var logger zerolog.Logger
-	if cfg.Format == "console" {
-		logger = zerolog.New(zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: time.RFC3339,
-		}).With().Timestamp().Logger()
-	} else {
-		logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	sinks, err := openLogSinks(cfg)
+	if err != nil {
+		return zerolog.Nop(), nil, err
+	}
+
+	writers := make([]io.Writer, 0, len(sinks.sinks)+1)
+	if sinks.stdout {
+		// Configure output format; the other sinks always get JSON
+		if cfg.Format == "console" {
+			writers = append(writers, zerolog.ConsoleWriter{
+				Out:        os.Stdout,
+				TimeFormat: time.RFC3339,
+			})
+		} else {
+			writers = append(writers, os.Stdout)
+		}
+	}
+	for _, sink := range sinks.sinks {
+		writers = append(writers, sink)
+	}
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(writers...)).With().Timestamp().Logger()
+
+	return logger, sinks, nil
+}
+
+// logSinks holds the sinks a logger writes to besides stdout.
+type logSinks struct {
+	stdout bool
+	sinks  []logging.Sink
+}
+
+// openLogSinks opens the configured sinks, closing those already open if
+// one fails.
+func openLogSinks(cfg LoggerConfig) (*logSinks, error) {
+	s := &logSinks{stdout: len(cfg.Sinks) == 0}
+
+	for _, name := range cfg.Sinks {
+		var sink logging.Sink
+		switch name {
+		case "stdout":
+			s.stdout = true
+			continue
+		case "file":
+			file, err := logging.OpenRotatingFile(cfg.FilePath, int64(cfg.FileMaxSizeMB)<<20, cfg.FileMaxBackups)
+			if err != nil {
+				s.Close()
+				return nil, err
+			}
+			sink = file
+		case "syslog":
+			conn, err := logging.DialSyslog(cfg.SyslogAddress, cfg.SyslogTag)
+			if err != nil {
+				s.Close()
+				return nil, err
+			}
+			sink = conn
+		case "http":
+			sink = logging.NewHTTPSink(logging.HTTPSinkConfig{
+				Endpoint:      cfg.HTTPEndpoint,
+				Format:        cfg.HTTPFormat,
+				BatchSize:     cfg.HTTPBatchSize,
+				FlushInterval: cfg.HTTPFlushInterval,
+				ServiceName:   "mini-kart",
+				Client:        &http.Client{Timeout: 10 * time.Second},
+			})
+		default:
+			s.Close()
+			return nil, fmt.Errorf("invalid log sink: %s", name)
+		}
+		s.sinks = append(s.sinks, sink)
 	}
 
-	return logger
+	return s, nil
+}
+
+// Close flushes and closes the sinks.
+func (s *logSinks) Close() error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.sinks = nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger_FileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+
+	logger, closer, err := NewLogger(LoggerConfig{
+		Level:          "info",
+		Format:         "console",
+		Sinks:          []string{"file"},
+		FilePath:       path,
+		FileMaxSizeMB:  1,
+		FileMaxBackups: 1,
+	})
+	require.NoError(t, err)
+
+	logger.Debug().Msg("filtered")
+	logger.Info().Str("order_id", "o-1").Msg("order created")
+	require.NoError(t, closer.Close())
+
+	// The console format only applies to stdout; files always get JSON
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\{"level":"info","order_id":"o-1","time":"[^"]+","message":"order created"\}\n$`, string(data))
+}
+
+func TestNewLogger_SinkError(t *testing.T) {
+	_, _, err := NewLogger(LoggerConfig{
+		Level:         "info",
+		Format:        "json",
+		Sinks:         []string{"file"},
+		FilePath:      filepath.Join(t.TempDir(), "missing", "api.log"),
+		FileMaxSizeMB: 1,
+	})
+	assert.ErrorContains(t, err, "failed to open log file")
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in the redacted configuration.
+const redacted = "[REDACTED]"
+
+// Redacted returns the configuration as nested maps keyed by field name, for
+// admins to check what a running instance was configured with. Keys, secrets
+// and passwords are replaced by [REDACTED] when set, keeping the name of
+// "name:key" entries, and URLs are cut down to their scheme and host, since
+// their credentials, paths and queries may carry tokens.
+func (c *Config) Redacted() map[string]any {
+	return redactStruct(reflect.ValueOf(*c))
+}
+
+// redactStruct redacts the exported fields of a struct.
+func redactStruct(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		out[field.Name] = redactField(field.Name, v.Field(i))
+	}
+	return out
+}
+
+// redactField redacts the value of the field called name.
+func redactField(name string, v reflect.Value) any {
+	switch value := v.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case time.Time:
+		return value
+	case string:
+		return redactString(name, value)
+	case []string:
+		out := make([]string, len(value))
+		for i, entry := range value {
+			// Keep the names of "name:key" entries, such as admin keys
+			if entryName, _, ok := strings.Cut(entry, ":"); ok && isSecretField(name) {
+				out[i] = entryName + ":" + redacted
+				continue
+			}
+			out[i] = redactString(name, entry)
+		}
+		return out
+	}
+
+	if v.Kind() == reflect.Struct {
+		return redactStruct(v)
+	}
+	return v.Interface()
+}
+
+// redactString redacts a string field called name, or an entry of one.
+func redactString(name, value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch {
+	case isSecretField(name):
+		return redacted
+	case strings.Contains(name, "URL") || strings.Contains(name, "Endpoint"):
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" {
+			return redacted
+		}
+		return u.Scheme + "://" + u.Host
+	}
+	return value
+}
+
+// isSecretField reports whether the field called name holds secrets.
+func isSecretField(name string) bool {
+	return strings.HasSuffix(name, "Key") || strings.HasSuffix(name, "Keys") ||
+		strings.Contains(name, "Secret") || strings.Contains(name, "Password") || strings.Contains(name, "Token")
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// NewServerTLSConfig creates a server TLS configuration based on the configuration.
+// When a client CA bundle is configured, client certificates are verified against
+// it so callers can authenticate with mutual TLS.
+func NewServerTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ClientAuth:   tls.NoClientCert,
+	}
+
+	if cfg.ClientAuth == "none" || cfg.ClientAuth == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("client CA bundle %s contains no valid certificates", cfg.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+
+	switch cfg.ClientAuth {
+	case "request":
+		// Verify certificates when presented; API key auth remains available
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid TLS client auth: %s", cfg.ClientAuth)
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate generates a self-signed certificate and key in dir.
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, "server")
+	caFile, _ := writeTestCertificate(t, dir, "client-ca")
+
+	invalidCA := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCA, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name               string
+		cfg                TLSConfig
+		expectError        bool
+		expectedClientAuth tls.ClientAuthType
+		expectClientCAs    bool
+	}{
+		{
+			name:               "Server TLS only",
+			cfg:                TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientAuth: "none"},
+			expectedClientAuth: tls.NoClientCert,
+		},
+		{
+			name:               "Optional client certificates",
+			cfg:                TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: "request"},
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+			expectClientCAs:    true,
+		},
+		{
+			name:               "Required client certificates",
+			cfg:                TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: "require"},
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+			expectClientCAs:    true,
+		},
+		{
+			name:        "Missing certificate",
+			cfg:         TLSConfig{Enabled: true, CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile},
+			expectError: true,
+		},
+		{
+			name:        "Invalid client CA bundle",
+			cfg:         TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: invalidCA, ClientAuth: "require"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := NewServerTLSConfig(tt.cfg)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, tlsConfig)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedClientAuth, tlsConfig.ClientAuth)
+			assert.Equal(t, tt.expectClientCAs, tlsConfig.ClientCAs != nil)
+			assert.Len(t, tlsConfig.Certificates, 1)
+		})
+	}
+}
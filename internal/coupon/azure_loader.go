@@ -0,0 +1,90 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/rs/zerolog"
+)
+
+// azureBodyRetries is how many times a dropped blob download is resumed from
+// where it stopped before the read fails.
+const azureBodyRetries = 3
+
+// azureBlobSource reads coupon files from an Azure Blob Storage container.
+type azureBlobSource struct {
+	client    *azblob.Client
+	container string
+}
+
+// NewAzureBlobLoader creates a new Azure Blob Storage coupon loader for the
+// container in the storage account at accountURL, e.g.
+// https://account.blob.core.windows.net/. Credentials are resolved through
+// the default Azure credential chain: environment variables, workload
+// identity, then managed identity. A nil httpClient uses the Azure SDK's
+// default client.
+func NewAzureBlobLoader(ctx context.Context, accountURL, container string, httpClient *http.Client, logger zerolog.Logger) (Loader, error) {
+	return newAzureBlobLoader(ctx, accountURL, container, httpClient, logger)
+}
+
+// NewAzureBlobSource creates an ObjectSource reading raw coupon files from
+// Azure Blob Storage. A nil httpClient uses the Azure SDK's default client.
+func NewAzureBlobSource(ctx context.Context, accountURL, container string, httpClient *http.Client, logger zerolog.Logger) (ObjectSource, error) {
+	return newAzureBlobLoader(ctx, accountURL, container, httpClient, logger)
+}
+
+// newAzureBlobLoader creates the Azure client shared by NewAzureBlobLoader and
+// NewAzureBlobSource.
+func newAzureBlobLoader(ctx context.Context, accountURL, container string, httpClient *http.Client, logger zerolog.Logger) (*storageLoader, error) {
+	logger = logger.With().Str("component", "azure-coupon-loader").Logger()
+
+	var options policy.ClientOptions
+	if httpClient != nil {
+		options.Transport = httpClient
+	}
+
+	credential, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: options})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load Azure credentials")
+		return nil, fmt.Errorf("failed to load Azure credentials: %w", err)
+	}
+
+	client, err := azblob.NewClient(accountURL, credential, &azblob.ClientOptions{ClientOptions: options})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create Azure Blob Storage client")
+		return nil, fmt.Errorf("failed to create Azure Blob Storage client: %w", err)
+	}
+
+	logger.Info().
+		Str("account_url", accountURL).
+		Str("container", container).
+		Msg("Azure Blob Storage loader initialised")
+
+	return newAzureBlobStorageLoader(client, container, logger), nil
+}
+
+// newAzureBlobStorageLoader wraps an Azure client in a storageLoader.
+func newAzureBlobStorageLoader(client *azblob.Client, container string, logger zerolog.Logger) *storageLoader {
+	return &storageLoader{
+		source:    &azureBlobSource{client: client, container: container},
+		provider:  "Azure",
+		container: container,
+		logger:    logger,
+	}
+}
+
+// Open returns the raw body of a blob. Requests are retried by the client,
+// and a download dropped part way through resumes from where it stopped.
+func (s *azureBlobSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.client.DownloadStream(ctx, s.container, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob from Azure (container=%s, key=%s): %w", s.container, key, err)
+	}
+	return resp.NewRetryReader(ctx, &blob.RetryReaderOptions{MaxRetries: azureBodyRetries}), nil
+}
@@ -0,0 +1,87 @@
+package coupon
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// bloomCouponSet implements CouponSet using a Bloom filter. Lookups never miss
+// a code that was added, but may report a code that was not added with
+// probability close to the configured false-positive rate.
+type bloomCouponSet struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	k      int    // number of hash functions
+	seed1  maphash.Seed
+	seed2  maphash.Seed
+	counts int
+}
+
+// NewBloomCouponSet creates a Bloom filter sized to hold capacity codes with
+// the given false-positive rate. Memory use is roughly
+// -capacity*ln(rate)/ln(2)^2 bits, e.g. about 1.8 bytes per code at 0.1%.
+func NewBloomCouponSet(capacity int, falsePositiveRate float64) CouponSet {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.001
+	}
+
+	n := float64(capacity)
+	m := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := int(math.Round(float64(m) / n * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomCouponSet{
+		bits:  make([]uint64, m/64),
+		m:     m,
+		k:     k,
+		seed1: maphash.MakeSeed(),
+		seed2: maphash.MakeSeed(),
+	}
+}
+
+// Contains reports whether a coupon code may be in the set.
+func (s *bloomCouponSet) Contains(code string) bool {
+	h1, h2 := s.hashes(code)
+	for i := 0; i < s.k; i++ {
+		bit := (h1 + uint64(i)*h2) % s.m
+		if s.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the number of distinct codes added. Codes that collide with a
+// false positive when added are not counted, so the size may be slightly low.
+func (s *bloomCouponSet) Size() int {
+	return s.counts
+}
+
+// Add adds a coupon code to the set.
+func (s *bloomCouponSet) Add(code string) {
+	h1, h2 := s.hashes(code)
+	added := false
+	for i := 0; i < s.k; i++ {
+		bit := (h1 + uint64(i)*h2) % s.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if s.bits[word]&mask == 0 {
+			s.bits[word] |= mask
+			added = true
+		}
+	}
+	if added {
+		s.counts++
+	}
+}
+
+// hashes returns the two base hashes combined to derive the k bit positions.
+func (s *bloomCouponSet) hashes(code string) (uint64, uint64) {
+	// An odd step keeps the k positions distinct
+	return maphash.String(s.seed1, code), maphash.String(s.seed2, code) | 1
+}
@@ -0,0 +1,50 @@
+package coupon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomCouponSet_NoFalseNegatives(t *testing.T) {
+	set := NewBloomCouponSet(10_000, 0.01).(*bloomCouponSet)
+
+	for i := 0; i < 10_000; i++ {
+		set.Add(fmt.Sprintf("CODE%06d", i))
+	}
+
+	for i := 0; i < 10_000; i++ {
+		assert.True(t, set.Contains(fmt.Sprintf("CODE%06d", i)))
+	}
+	assert.InDelta(t, 10_000, set.Size(), 200)
+}
+
+func TestBloomCouponSet_FalsePositiveRate(t *testing.T) {
+	set := NewBloomCouponSet(10_000, 0.01).(*bloomCouponSet)
+	for i := 0; i < 10_000; i++ {
+		set.Add(fmt.Sprintf("CODE%06d", i))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 100_000; i++ {
+		if set.Contains(fmt.Sprintf("MISS%06d", i)) {
+			falsePositives++
+		}
+	}
+
+	// Allow generous slack over the 1% target
+	assert.Less(t, falsePositives, 2_000)
+}
+
+func TestBloomCouponSet_Sizing(t *testing.T) {
+	set := NewBloomCouponSet(1_000_000, 0.001).(*bloomCouponSet)
+
+	// About 14.4 bits per code at 0.1%
+	assert.InDelta(t, 1_800_000, len(set.bits)*8, 10_000)
+	assert.Equal(t, 10, set.k)
+
+	set.Add("DUPLICATE")
+	set.Add("DUPLICATE")
+	assert.Equal(t, 1, set.Size())
+}
@@ -0,0 +1,326 @@
+package coupon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+)
+
+// cacheIndexFile is the name of the cache index within the cache directory.
+const cacheIndexFile = "index.json"
+
+// FileCacheConfig holds configuration for the local coupon file cache.
+type FileCacheConfig struct {
+	// Dir is the directory holding cached files.
+	Dir string
+
+	// MaxBytes is the maximum total size of cached files. Zero disables the limit.
+	MaxBytes int64
+
+	// MaxFiles is the maximum number of cached files. Zero disables the limit.
+	MaxFiles int
+
+	// MaxAge is how long a cached file is considered fresh after download.
+	MaxAge time.Duration
+
+	// Metrics is an optional registry for the cache's standard metrics,
+	// reported as the coupon_file cache.
+	Metrics *metrics.Registry
+}
+
+// cacheEntry describes a cached remote file.
+type cacheEntry struct {
+	Key       string    `json:"key"`
+	Checksum  string    `json:"checksum"`
+	Size      int64     `json:"size"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// FileCache stores downloaded coupon files on local disk under checksum-based
+// names and evicts the least recently used files when limits are exceeded.
+type FileCache struct {
+	config   FileCacheConfig
+	counters metrics.CacheCounters
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	logger   zerolog.Logger
+}
+
+// NewFileCache creates a file cache in the configured directory, loading any
+// existing index so cached files survive restarts.
+func NewFileCache(config FileCacheConfig, logger zerolog.Logger) (*FileCache, error) {
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create coupon cache directory %s: %w", config.Dir, err)
+	}
+
+	c := &FileCache{
+		config:   config,
+		counters: config.Metrics.Cache("coupon_file"),
+		entries:  make(map[string]*cacheEntry),
+		logger:   logger.With().Str("component", "coupon-cache").Logger(),
+	}
+
+	data, err := os.ReadFile(filepath.Join(config.Dir, cacheIndexFile))
+	switch {
+	case err == nil:
+		var entries []*cacheEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			c.logger.Warn().Err(err).Msg("coupon cache index is corrupt, starting empty")
+		}
+		for _, e := range entries {
+			if _, statErr := os.Stat(c.path(e.Checksum)); statErr == nil {
+				c.entries[e.Key] = e
+			}
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read coupon cache index: %w", err)
+	}
+
+	c.logger.Info().
+		Str("dir", config.Dir).
+		Int("entries", len(c.entries)).
+		Msg("coupon cache initialised")
+
+	return c, nil
+}
+
+// Lookup returns the local path of a cached key and whether it is still
+// fresh. Only fresh files count as hits, since stale ones are downloaded again.
+func (c *FileCache) Lookup(key string) (path string, fresh bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		c.counters.Misses.Inc()
+		return "", false, false
+	}
+
+	entry.LastUsed = time.Now()
+	fresh = c.config.MaxAge <= 0 || time.Since(entry.FetchedAt) < c.config.MaxAge
+	if fresh {
+		c.counters.Hits.Inc()
+	} else {
+		c.counters.Misses.Inc()
+	}
+
+	return c.path(entry.Checksum), fresh, true
+}
+
+// Store copies the reader into the cache under its SHA-256 checksum, records it
+// for key and applies the retention policy. It returns the cached file path.
+func (c *FileCache) Store(key string, r io.Reader) (string, error) {
+	tmp, err := os.CreateTemp(c.config.Dir, "download-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write cache file for %s: %w", key, err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	path := c.path(checksum)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to move cache file into place: %w", err)
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &cacheEntry{
+		Key:       key,
+		Checksum:  checksum,
+		Size:      size,
+		FetchedAt: now,
+		LastUsed:  now,
+	}
+	c.cleanupLocked()
+
+	if err := c.saveIndexLocked(); err != nil {
+		return "", err
+	}
+
+	c.logger.Info().
+		Str("key", key).
+		Str("checksum", checksum).
+		Int64("size", size).
+		Msg("coupon file cached")
+
+	return path, nil
+}
+
+// Flush persists the cache index so that recency recorded by Lookup survives
+// a restart. Store persists the index itself; Flush is called at shutdown.
+func (c *FileCache) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.saveIndexLocked()
+}
+
+// cleanupLocked evicts least recently used entries until the cache is within
+// its limits and removes files no longer referenced by any entry.
+func (c *FileCache) cleanupLocked() {
+	entries := make([]*cacheEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	for len(entries) > 1 &&
+		((c.config.MaxFiles > 0 && len(entries) > c.config.MaxFiles) ||
+			(c.config.MaxBytes > 0 && total > c.config.MaxBytes)) {
+		evicted := entries[0]
+		entries = entries[1:]
+		total -= evicted.Size
+		delete(c.entries, evicted.Key)
+		c.counters.Evictions.Inc()
+
+		c.logger.Info().
+			Str("key", evicted.Key).
+			Str("checksum", evicted.Checksum).
+			Msg("evicting coupon file from cache")
+	}
+
+	referenced := make(map[string]bool, len(c.entries))
+	for _, e := range c.entries {
+		referenced[e.Checksum+".gz"] = true
+	}
+
+	files, err := os.ReadDir(c.config.Dir)
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("failed to list coupon cache directory")
+		return
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".gz") && !referenced[f.Name()] {
+			if err := os.Remove(filepath.Join(c.config.Dir, f.Name())); err != nil {
+				c.logger.Warn().Err(err).Str("file", f.Name()).Msg("failed to remove unreferenced cache file")
+			}
+		}
+	}
+}
+
+// saveIndexLocked persists the cache index atomically.
+func (c *FileCache) saveIndexLocked() error {
+	entries := make([]*cacheEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode coupon cache index: %w", err)
+	}
+
+	tmpPath := filepath.Join(c.config.Dir, cacheIndexFile+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write coupon cache index: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(c.config.Dir, cacheIndexFile)); err != nil {
+		return fmt.Errorf("failed to replace coupon cache index: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the cache file path for a checksum.
+func (c *FileCache) path(checksum string) string {
+	return filepath.Join(c.config.Dir, checksum+".gz")
+}
+
+// cachingLoader implements Loader by serving coupon files from a local cache,
+// downloading from remote storage only when the cached copy is missing or stale.
+type cachingLoader struct {
+	source     ObjectSource
+	cache      *FileCache
+	fileLoader Loader
+	logger     zerolog.Logger
+}
+
+// NewCachingLoader creates a loader that caches remote coupon files on local disk.
+// fileLoader is used to parse cached files.
+func NewCachingLoader(source ObjectSource, cache *FileCache, fileLoader Loader, logger zerolog.Logger) Loader {
+	return &cachingLoader{
+		source:     source,
+		cache:      cache,
+		fileLoader: fileLoader,
+		logger:     logger.With().Str("component", "caching-coupon-loader").Logger(),
+	}
+}
+
+// Load returns the coupon set for key, preferring a fresh cached copy.
+// If the remote download fails, a stale cached copy is used when available.
+func (l *cachingLoader) Load(ctx context.Context, key string) (CouponSet, error) {
+	cachedPath, fresh, cached := l.cache.Lookup(key)
+	if cached && fresh {
+		l.logger.Info().Str("key", key).Str("path", cachedPath).Msg("loading coupon file from local cache")
+		return l.fileLoader.Load(ctx, cachedPath)
+	}
+
+	path, err := l.download(ctx, key)
+	if err != nil {
+		if cached {
+			l.logger.Warn().
+				Err(err).
+				Str("key", key).
+				Str("path", cachedPath).
+				Msg("failed to refresh coupon file, using stale cached copy")
+			return l.fileLoader.Load(ctx, cachedPath)
+		}
+		return nil, err
+	}
+
+	return l.fileLoader.Load(ctx, path)
+}
+
+// download fetches key from remote storage into the cache.
+func (l *cachingLoader) download(ctx context.Context, key string) (string, error) {
+	l.logger.Info().Str("key", key).Msg("downloading coupon file into local cache")
+
+	body, err := l.source.Open(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to open remote coupon file %s: %w", key, err)
+	}
+	defer body.Close()
+
+	path, err := l.cache.Store(key, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to cache coupon file %s: %w", key, err)
+	}
+
+	return path, nil
+}
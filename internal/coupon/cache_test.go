@@ -0,0 +1,262 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSource is a mock implementation of the ObjectSource interface for testing.
+type mockSource struct {
+	openFunc func(ctx context.Context, key string) (io.ReadCloser, error)
+	calls    int
+}
+
+func (m *mockSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.calls++
+	if m.openFunc != nil {
+		return m.openFunc(ctx, key)
+	}
+	return nil, errors.New("not implemented")
+}
+
+// contentLoader returns a loader that builds a set from the raw lines of a file.
+func contentLoader(t *testing.T) *mockLoader {
+	return &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			data, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			set := NewMapCouponSet(10)
+			for _, line := range strings.Fields(string(data)) {
+				set.(*mapCouponSet).Add(line)
+			}
+			return set, nil
+		},
+	}
+}
+
+func TestFileCache_StoreAndLookup(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(FileCacheConfig{Dir: dir, MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	_, _, ok := cache.Lookup("coupons/a.gz")
+	assert.False(t, ok)
+
+	path, err := cache.Store("coupons/a.gz", strings.NewReader("CODE1234"))
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.Len(t, strings.TrimSuffix(filepath.Base(path), ".gz"), 64, "file should be named by its SHA-256 checksum")
+
+	cachedPath, fresh, ok := cache.Lookup("coupons/a.gz")
+	assert.True(t, ok)
+	assert.True(t, fresh)
+	assert.Equal(t, path, cachedPath)
+}
+
+func TestFileCache_Stale(t *testing.T) {
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	_, err = cache.Store("a.gz", strings.NewReader("CODE1234"))
+	require.NoError(t, err)
+	cache.entries["a.gz"].FetchedAt = time.Now().Add(-2 * time.Hour)
+
+	_, fresh, ok := cache.Lookup("a.gz")
+	assert.True(t, ok)
+	assert.False(t, fresh)
+}
+
+func TestFileCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(FileCacheConfig{Dir: dir, MaxFiles: 2}, zerolog.Nop())
+	require.NoError(t, err)
+
+	pathA, err := cache.Store("a.gz", strings.NewReader("AAAAAAAA"))
+	require.NoError(t, err)
+	_, err = cache.Store("b.gz", strings.NewReader("BBBBBBBB"))
+	require.NoError(t, err)
+
+	// Use a so that b becomes the least recently used entry
+	cache.entries["b.gz"].LastUsed = time.Now().Add(-time.Minute)
+	cache.Lookup("a.gz")
+
+	_, err = cache.Store("c.gz", strings.NewReader("CCCCCCCC"))
+	require.NoError(t, err)
+
+	_, _, ok := cache.Lookup("b.gz")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, _, ok = cache.Lookup("a.gz")
+	assert.True(t, ok)
+	_, _, ok = cache.Lookup("c.gz")
+	assert.True(t, ok)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.gz"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "evicted file should be removed from disk")
+	assert.FileExists(t, pathA)
+}
+
+func TestFileCache_Metrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxFiles: 1, MaxAge: time.Hour, Metrics: registry}, zerolog.Nop())
+	require.NoError(t, err)
+
+	cache.Lookup("a.gz")
+	_, err = cache.Store("a.gz", strings.NewReader("AAAAAAAA"))
+	require.NoError(t, err)
+	cache.Lookup("a.gz")
+
+	cache.entries["a.gz"].FetchedAt = time.Now().Add(-2 * time.Hour)
+	cache.Lookup("a.gz")
+
+	_, err = cache.Store("b.gz", strings.NewReader("BBBBBBBB"))
+	require.NoError(t, err)
+
+	counters := registry.Cache("coupon_file")
+	assert.Equal(t, int64(1), counters.Hits.Value())
+	assert.Equal(t, int64(2), counters.Misses.Value(), "missing and stale files are misses")
+	assert.Equal(t, int64(1), counters.Evictions.Value())
+}
+
+func TestFileCache_EvictsBySize(t *testing.T) {
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxBytes: 12}, zerolog.Nop())
+	require.NoError(t, err)
+
+	_, err = cache.Store("a.gz", strings.NewReader("AAAAAAAA"))
+	require.NoError(t, err)
+	cache.entries["a.gz"].LastUsed = time.Now().Add(-time.Minute)
+	_, err = cache.Store("b.gz", strings.NewReader("BBBBBBBB"))
+	require.NoError(t, err)
+
+	_, _, ok := cache.Lookup("a.gz")
+	assert.False(t, ok)
+	_, _, ok = cache.Lookup("b.gz")
+	assert.True(t, ok)
+}
+
+func TestFileCache_PersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(FileCacheConfig{Dir: dir, MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	path, err := cache.Store("a.gz", strings.NewReader("CODE1234"))
+	require.NoError(t, err)
+
+	reopened, err := NewFileCache(FileCacheConfig{Dir: dir, MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	cachedPath, fresh, ok := reopened.Lookup("a.gz")
+	assert.True(t, ok)
+	assert.True(t, fresh)
+	assert.Equal(t, path, cachedPath)
+}
+
+func TestFileCache_FlushPersistsLastUsed(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(FileCacheConfig{Dir: dir}, zerolog.Nop())
+	require.NoError(t, err)
+
+	_, err = cache.Store("a.gz", strings.NewReader("CODE1234"))
+	require.NoError(t, err)
+	cache.entries["a.gz"].LastUsed = time.Now().Add(-time.Hour)
+
+	cache.Lookup("a.gz")
+	lastUsed := cache.entries["a.gz"].LastUsed
+	require.NoError(t, cache.Flush())
+
+	reopened, err := NewFileCache(FileCacheConfig{Dir: dir}, zerolog.Nop())
+	require.NoError(t, err)
+	assert.True(t, reopened.entries["a.gz"].LastUsed.Equal(lastUsed))
+}
+
+func TestCachingLoader_DownloadsOnceWhileFresh(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	source := &mockSource{
+		openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			assert.Equal(t, "coupons/a.gz", key)
+			return io.NopCloser(strings.NewReader("S3CODE123\n")), nil
+		},
+	}
+	loader := NewCachingLoader(source, cache, contentLoader(t), zerolog.Nop())
+
+	for i := 0; i < 2; i++ {
+		set, err := loader.Load(ctx, "coupons/a.gz")
+		require.NoError(t, err)
+		assert.True(t, set.Contains("S3CODE123"))
+	}
+	assert.Equal(t, 1, source.calls, "fresh cached copy should not be downloaded again")
+}
+
+func TestCachingLoader_RefreshesStaleEntry(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+	_, err = cache.Store("a.gz", strings.NewReader("OLDCODE123"))
+	require.NoError(t, err)
+	cache.entries["a.gz"].FetchedAt = time.Now().Add(-2 * time.Hour)
+
+	source := &mockSource{
+		openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("NEWCODE123")), nil
+		},
+	}
+	loader := NewCachingLoader(source, cache, contentLoader(t), zerolog.Nop())
+
+	set, err := loader.Load(ctx, "a.gz")
+	require.NoError(t, err)
+	assert.True(t, set.Contains("NEWCODE123"))
+	assert.False(t, set.Contains("OLDCODE123"))
+	assert.Equal(t, 1, source.calls)
+}
+
+func TestCachingLoader_UsesStaleCopyWhenSourceFails(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+	_, err = cache.Store("a.gz", strings.NewReader("OLDCODE123"))
+	require.NoError(t, err)
+	cache.entries["a.gz"].FetchedAt = time.Now().Add(-2 * time.Hour)
+
+	source := &mockSource{
+		openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return nil, errors.New("S3 unavailable")
+		},
+	}
+	loader := NewCachingLoader(source, cache, contentLoader(t), zerolog.Nop())
+
+	set, err := loader.Load(ctx, "a.gz")
+	require.NoError(t, err)
+	assert.True(t, set.Contains("OLDCODE123"))
+}
+
+func TestCachingLoader_SourceFailsWithoutCache(t *testing.T) {
+	cache, err := NewFileCache(FileCacheConfig{Dir: t.TempDir(), MaxAge: time.Hour}, zerolog.Nop())
+	require.NoError(t, err)
+
+	source := &mockSource{
+		openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return nil, errors.New("S3 unavailable")
+		},
+	}
+	loader := NewCachingLoader(source, cache, contentLoader(t), zerolog.Nop())
+
+	set, err := loader.Load(context.Background(), "a.gz")
+	assert.Error(t, err)
+	assert.Nil(t, set)
+	assert.Contains(t, err.Error(), "S3 unavailable")
+}
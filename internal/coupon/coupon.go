@@ -2,20 +2,116 @@ package coupon
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
 )
 
-// Validator defines the interface for promo code validation.
-type Validator interface {
+// CodeValidator defines the interface for promo code validation.
+type CodeValidator interface {
 	// Validate checks if a promo code is valid.
 	// A valid promo code must:
+	// - Contain only characters allowed by the code pattern
 	// - Be between 8 and 10 characters in length
 	// - Appear in at least 2 out of 3 coupon files
 	Validate(ctx context.Context, promoCode string) error
 
+	// ValidateAndResolve validates a promo code and returns the discount it
+	// grants, or nil when it grants none. Expired codes fail with
+	// model.ErrCouponExpired.
+	ValidateAndResolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error)
+
+	// Resolve returns the discount a promo code grants, or nil when it
+	// grants none, without checking the coupon files, e.g. to honour a code
+	// that could not be validated. Expired codes fail with
+	// model.ErrCouponExpired.
+	Resolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error)
+
+	// CheckEligibility checks a resolved discount may be applied to an order
+	// placed by the customer, nil when the order has none. Returns
+	// model.ErrCouponFirstOrderOnly, model.ErrCouponNotForCustomer or
+	// model.ErrCouponSegment when the discount is restricted to other
+	// customers.
+	CheckEligibility(ctx context.Context, discount *model.CouponDiscount, customerID *uuid.UUID) error
+
+	// Check validates a promo code like Validate, reporting the outcome and
+	// how many coupon files contained the code instead of an error.
+	Check(ctx context.Context, promoCode string) model.CouponValidation
+
+	// Normalize returns the canonical form of a promo code, the form it is
+	// matched and redeemed in. Codes differing only in case share one form
+	// when the validator matches case-insensitively.
+	Normalize(promoCode string) string
+}
+
+// Lifecycle defines the interface for managing the coupon data behind a validator.
+type Lifecycle interface {
+	// Reload reloads the coupon data. The previous data keeps serving
+	// validations until the reload succeeds.
+	Reload(ctx context.Context) error
+
+	// ApplyDeltas applies coupon deltas published since the last load.
+	// Returns an error wrapping ErrDeltaSequenceGap when a full Reload is
+	// required to recover.
+	ApplyDeltas(ctx context.Context) error
+
+	// Status reports the coupon data currently loaded.
+	Status() Status
+
+	// Ready returns nil once the coupon data has loaded, otherwise why
+	// validations cannot be served yet.
+	Ready() error
+
 	// Close releases resources held by the validator.
 	Close() error
 }
 
+// Validator combines promo code validation with lifecycle management.
+// Consumers that only validate codes should depend on CodeValidator.
+type Validator interface {
+	CodeValidator
+	Lifecycle
+}
+
+// Status describes the coupon data loaded by a validator.
+type Status struct {
+	// SetType is the coupon set implementation in use.
+	SetType string `json:"setType"`
+
+	// Loading is true until coupon files loading in the background have loaded.
+	Loading bool `json:"loading,omitempty"`
+
+	// LoadedAt is when the coupon files were last loaded in full. Zero once
+	// the validator is closed.
+	LoadedAt time.Time `json:"loadedAt"`
+
+	// UpdatedAt is when deltas were last applied since the full load, if ever.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+
+	// Generation identifies the full load in the coupon file registry, zero
+	// when loads are not recorded or recording it failed.
+	Generation int64 `json:"generation,omitempty"`
+
+	Sets         []SetStatus `json:"sets"`
+	TotalCoupons int         `json:"totalCoupons"`
+}
+
+// SetStatus describes one loaded coupon set.
+type SetStatus struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+
+	// Sequence is the last delta applied to the set, or its base sequence.
+	Sequence uint64 `json:"sequence"`
+
+	// Checksum is the SHA-256 of the file the set was loaded from, empty
+	// for sets not read from a file.
+	Checksum string `json:"checksum,omitempty"`
+}
+
 // CouponSet represents a set of coupon codes for fast lookup.
 type CouponSet interface {
 	// Contains checks if a coupon code exists in the set.
@@ -30,3 +126,74 @@ type Loader interface {
 	// Load reads a gzipped coupon file and returns a CouponSet.
 	Load(ctx context.Context, filePath string) (CouponSet, error)
 }
+
+// ObjectSource defines the interface for opening raw remote coupon files.
+type ObjectSource interface {
+	// Open returns a reader for the object stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// CodeSource defines the interface for streaming coupon codes from a store
+// other than files, such as the database.
+type CodeSource interface {
+	// StreamCodes calls fn for every code in the named coupon set.
+	StreamCodes(ctx context.Context, set string, fn func(code string) error) error
+}
+
+// MetadataSource defines the interface for looking up the discount a coupon
+// code grants: its type and value, expiry and minimum basket value.
+type MetadataSource interface {
+	// GetByCode returns the discount for a code, or nil when it has none.
+	GetByCode(ctx context.Context, code string) (*model.CouponDiscount, error)
+}
+
+// EligibilityChecker defines the interface for checking who may redeem a
+// discount: specific customers, customers in given segments, or customers
+// placing their first order.
+type EligibilityChecker interface {
+	// CheckEligibility checks the customer, nil when the order has none, may
+	// redeem a restricted discount.
+	CheckEligibility(ctx context.Context, discount *model.CouponDiscount, customerID *uuid.UUID) error
+}
+
+// CustomerSource defines the interface for looking up the customers placing
+// orders.
+type CustomerSource interface {
+	// GetByID returns a customer, or nil when the customer does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error)
+
+	// HasOrders reports whether the customer has placed an order that was
+	// not cancelled.
+	HasOrders(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// DeltaSource defines the interface for reading incremental coupon updates.
+type DeltaSource interface {
+	// BaseSequence returns the sequence of the last delta already included in
+	// the named set's base snapshot.
+	BaseSequence(ctx context.Context, name string) (uint64, error)
+
+	// Deltas returns the named set's deltas with a sequence greater than
+	// after, in ascending sequence order.
+	Deltas(ctx context.Context, name string, after uint64) ([]Delta, error)
+}
+
+// GenerationRecorder defines the interface for recording which coupon files
+// each full load of a validator consumed, so the codes accepted at any time
+// can be traced back to the files they came from.
+type GenerationRecorder interface {
+	// RecordGeneration records a completed load, setting its ID, and retires
+	// the generation the same instance loaded before it.
+	RecordGeneration(ctx context.Context, generation *model.CouponGeneration) error
+
+	// RetireGeneration records that a generation stopped serving validations.
+	RetireGeneration(ctx context.Context, id int64, retiredAt time.Time) error
+}
+
+// HistoryChecker defines the interface for checking whether promo codes were
+// valid at past times, e.g. to resolve disputes about refused codes.
+type HistoryChecker interface {
+	// CheckAt reports whether a promo code was valid at the given time in
+	// each of the generations serving validations then.
+	CheckAt(ctx context.Context, promoCode string, at time.Time, generations []model.CouponGeneration) (*model.CouponValidityAt, error)
+}
@@ -0,0 +1,71 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// dbLoader implements Loader by reading coupon sets from a CodeSource.
+type dbLoader struct {
+	source CodeSource
+	logger zerolog.Logger
+}
+
+// NewDBLoader creates a loader that reads coupon codes from the database.
+// Configured file paths are mapped to coupon set names with SetName, so the
+// validator configuration is shared with file and S3 deployments.
+func NewDBLoader(source CodeSource, logger zerolog.Logger) Loader {
+	return &dbLoader{
+		source: source,
+		logger: logger.With().Str("component", "db-coupon-loader").Logger(),
+	}
+}
+
+// SetName returns the coupon set name for a coupon file path,
+// e.g. "data/coupons/couponbase1.gz" becomes "couponbase1".
+func SetName(filePath string) string {
+	return strings.TrimSuffix(path.Base(filePath), ".gz")
+}
+
+// Load reads the coupon set named after filePath and returns a CouponSet.
+func (l *dbLoader) Load(ctx context.Context, filePath string) (CouponSet, error) {
+	name := SetName(filePath)
+	l.logger.Info().Str("coupon_set", name).Msg("loading coupon set from database")
+
+	set := newCouponSet(ctx)
+	stats := loadStatsFrom(ctx)
+	count := 0
+	err := l.source.StreamCodes(ctx, name, func(code string) error {
+		// Check context cancellation periodically
+		if count%1_000_000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		set.Add(code)
+		count++
+		stats.lines++
+		stats.bytes += int64(len(code))
+		return nil
+	})
+	if err != nil {
+		l.logger.Error().Err(err).Str("coupon_set", name).Msg("failed to load coupon set")
+		return nil, fmt.Errorf("failed to load coupon set %s: %w", name, err)
+	}
+
+	// An empty set almost always means the set was never imported
+	if count == 0 {
+		return nil, fmt.Errorf("coupon set %s is empty", name)
+	}
+
+	l.logger.Info().
+		Str("coupon_set", name).
+		Int("coupons_loaded", set.Size()).
+		Msg("coupon set loaded successfully from database")
+
+	return set, nil
+}
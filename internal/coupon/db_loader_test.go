@@ -0,0 +1,71 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCodeSource is an in-memory CodeSource keyed by set name.
+type mockCodeSource struct {
+	sets map[string][]string
+	err  error
+}
+
+func (m *mockCodeSource) StreamCodes(ctx context.Context, set string, fn func(code string) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, code := range m.sets[set] {
+		if err := fn(code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSetName(t *testing.T) {
+	assert.Equal(t, "couponbase1", SetName("data/coupons/couponbase1.gz"))
+	assert.Equal(t, "couponbase2", SetName("couponbase2"))
+}
+
+func TestDBLoader_Load(t *testing.T) {
+	ctx := withSetFactory(context.Background(), func() couponSetBuilder {
+		return NewMapCouponSet(10).(*mapCouponSet)
+	})
+	source := &mockCodeSource{sets: map[string][]string{
+		"couponbase1": {"HAPPYHRS", "FIFTYOFF"},
+	}}
+	loader := NewDBLoader(source, zerolog.Nop())
+
+	t.Run("Loads set named after file", func(t *testing.T) {
+		set, err := loader.Load(ctx, "data/coupons/couponbase1.gz")
+		require.NoError(t, err)
+		assert.Equal(t, 2, set.Size())
+		assert.True(t, set.Contains("HAPPYHRS"))
+	})
+
+	t.Run("Empty set is an error", func(t *testing.T) {
+		_, err := loader.Load(ctx, "data/coupons/couponbase2.gz")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "coupon set couponbase2 is empty")
+	})
+
+	t.Run("Source error", func(t *testing.T) {
+		failing := NewDBLoader(&mockCodeSource{err: errors.New("connection refused")}, zerolog.Nop())
+		_, err := failing.Load(ctx, "couponbase1.gz")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := loader.Load(cancelled, "couponbase1.gz")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,243 @@
+package coupon
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrDeltaSequenceGap is returned when coupon deltas cannot be applied in
+// order, either because a delta is missing or because a newer base snapshot
+// was published. A full reload is required to recover.
+var ErrDeltaSequenceGap = errors.New("coupon delta sequence gap")
+
+// Delta lists the codes added to and removed from a coupon set by one update.
+type Delta struct {
+	// Sequence orders deltas for a coupon set. Deltas are applied strictly
+	// in sequence, starting right after the base snapshot's sequence.
+	Sequence uint64
+
+	Added   []string
+	Removed []string
+}
+
+// dirDeltaSource implements DeltaSource by reading delta files from a directory.
+type dirDeltaSource struct {
+	dir    string
+	logger zerolog.Logger
+}
+
+// NewDirDeltaSource creates a delta source reading files from dir. For a
+// coupon set named "couponbase1":
+//
+//   - couponbase1.base holds the sequence of the last delta already folded
+//     into the base snapshot. A missing file means zero.
+//   - couponbase1.<sequence>.delta.gz are gzipped delta files with one
+//     "+CODE" or "-CODE" line per added or removed code.
+func NewDirDeltaSource(dir string, logger zerolog.Logger) DeltaSource {
+	return &dirDeltaSource{
+		dir:    dir,
+		logger: logger.With().Str("component", "coupon-delta-source").Logger(),
+	}
+}
+
+// BaseSequence returns the sequence recorded for the named base snapshot.
+func (s *dirDeltaSource) BaseSequence(ctx context.Context, name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, name+".base"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read base sequence for %s: %w", name, err)
+	}
+
+	seq, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid base sequence for %s: %w", name, err)
+	}
+	return seq, nil
+}
+
+// Deltas returns the named set's deltas with a sequence greater than after,
+// in ascending sequence order.
+func (s *dirDeltaSource) Deltas(ctx context.Context, name string, after uint64) ([]Delta, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, name+".*.delta.gz"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deltas for %s: %w", name, err)
+	}
+
+	type deltaFile struct {
+		seq  uint64
+		path string
+	}
+
+	files := make([]deltaFile, 0, len(paths))
+	for _, path := range paths {
+		middle := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), name+"."), ".delta.gz")
+		seq, err := strconv.ParseUint(middle, 10, 64)
+		if err != nil {
+			// Another set's files can match the glob, e.g. "couponbase1.x" for "couponbase1"
+			continue
+		}
+		if seq > after {
+			files = append(files, deltaFile{seq: seq, path: path})
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+
+	deltas := make([]Delta, 0, len(files))
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		delta, err := readDeltaFile(file.path)
+		if err != nil {
+			s.logger.Error().Err(err).Str("file", file.path).Msg("failed to read coupon delta")
+			return nil, err
+		}
+		delta.Sequence = file.seq
+		deltas = append(deltas, delta)
+	}
+
+	return deltas, nil
+}
+
+// readDeltaFile parses a gzipped delta file.
+func readDeltaFile(path string) (Delta, error) {
+	var delta Delta
+
+	file, err := os.Open(path)
+	if err != nil {
+		return delta, fmt.Errorf("failed to open coupon delta %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return delta, fmt.Errorf("failed to create gzip reader for %s: %w", path, err)
+	}
+	defer gzipReader.Close()
+
+	scanner := bufio.NewScanner(gzipReader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		switch text[0] {
+		case '+':
+			delta.Added = append(delta.Added, text[1:])
+		case '-':
+			delta.Removed = append(delta.Removed, text[1:])
+		default:
+			return delta, fmt.Errorf("coupon delta %s line %d: expected +CODE or -CODE", path, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return delta, fmt.Errorf("error reading coupon delta %s: %w", path, err)
+	}
+
+	return delta, nil
+}
+
+// deltaCouponSet implements CouponSet by overlaying added and removed codes on
+// a base set. The base set is never modified, so it can keep serving lookups
+// while a new overlay is built.
+type deltaCouponSet struct {
+	base    CouponSet
+	added   map[string]struct{}
+	removed map[string]struct{}
+}
+
+// Contains checks if a coupon code exists in the set.
+func (s *deltaCouponSet) Contains(code string) bool {
+	if _, ok := s.added[code]; ok {
+		return true
+	}
+	if _, ok := s.removed[code]; ok {
+		return false
+	}
+	return s.base.Contains(code)
+}
+
+// Size returns the number of coupons in the set.
+func (s *deltaCouponSet) Size() int {
+	return s.base.Size() + len(s.added) - len(s.removed)
+}
+
+// applyDelta returns a new set with the delta applied on top of set. The
+// given set is left untouched.
+func applyDelta(set CouponSet, delta Delta) CouponSet {
+	next := &deltaCouponSet{base: set}
+	if prev, ok := set.(*deltaCouponSet); ok {
+		next.base = prev.base
+		next.added = make(map[string]struct{}, len(prev.added)+len(delta.Added))
+		next.removed = make(map[string]struct{}, len(prev.removed)+len(delta.Removed))
+		for code := range prev.added {
+			next.added[code] = struct{}{}
+		}
+		for code := range prev.removed {
+			next.removed[code] = struct{}{}
+		}
+	} else {
+		next.added = make(map[string]struct{}, len(delta.Added))
+		next.removed = make(map[string]struct{}, len(delta.Removed))
+	}
+
+	for _, code := range delta.Removed {
+		delete(next.added, code)
+		if next.base.Contains(code) {
+			next.removed[code] = struct{}{}
+		}
+	}
+	for _, code := range delta.Added {
+		delete(next.removed, code)
+		if !next.base.Contains(code) {
+			next.added[code] = struct{}{}
+		}
+	}
+
+	return next
+}
+
+// RunDeltaUpdates applies coupon deltas every interval until ctx is cancelled.
+// When deltas cannot be applied in sequence, the coupon files are reloaded in
+// full. Failed updates are logged and retried at the next interval.
+func RunDeltaUpdates(ctx context.Context, lifecycle Lifecycle, interval time.Duration, logger zerolog.Logger) {
+	logger = logger.With().Str("component", "coupon-delta-updates").Logger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := lifecycle.ApplyDeltas(ctx)
+			if errors.Is(err, ErrDeltaSequenceGap) {
+				logger.Warn().Err(err).Msg("coupon deltas out of sequence, reloading coupon files")
+				err = lifecycle.Reload(ctx)
+			}
+			if err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Msg("coupon delta update failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"mini-kart/internal/fixtures"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDeltaFile writes a gzipped delta file with the given lines into dir.
+func writeDeltaFile(t *testing.T, dir, name string, lines ...string) {
+	fixtures.NewCouponFile(t, name).Dir(dir).Codes(lines...).Write()
+}
+
+// memDeltaSource is an in-memory DeltaSource for tests.
+type memDeltaSource struct {
+	mu     sync.Mutex
+	base   map[string]uint64
+	deltas map[string][]Delta
+}
+
+func (s *memDeltaSource) BaseSequence(ctx context.Context, name string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.base[name], nil
+}
+
+func (s *memDeltaSource) Deltas(ctx context.Context, name string, after uint64) ([]Delta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deltas []Delta
+	for _, d := range s.deltas[name] {
+		if d.Sequence > after {
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas, nil
+}
+
+func (s *memDeltaSource) publish(name string, delta Delta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deltas[name] = append(s.deltas[name], delta)
+}
+
+func TestDirDeltaSource(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	writeDeltaFile(t, dir, "couponbase1.2.delta.gz", "+NEWCODE2", "-OLDCODE1")
+	writeDeltaFile(t, dir, "couponbase1.1.delta.gz", "+NEWCODE1", "")
+	writeDeltaFile(t, dir, "couponbase1.10.delta.gz", "+NEWCODE10")
+	writeDeltaFile(t, dir, "couponbase1.extra.1.delta.gz", "+OTHERSET")
+	writeDeltaFile(t, dir, "couponbase2.1.delta.gz", "+OTHERSET")
+
+	source := NewDirDeltaSource(dir, zerolog.Nop())
+
+	t.Run("Missing base file means sequence zero", func(t *testing.T) {
+		seq, err := source.BaseSequence(ctx, "couponbase1")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), seq)
+	})
+
+	t.Run("Reads base sequence", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "couponbase2.base"), []byte("41\n"), 0o644))
+
+		seq, err := source.BaseSequence(ctx, "couponbase2")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(41), seq)
+	})
+
+	t.Run("Returns deltas in sequence order", func(t *testing.T) {
+		deltas, err := source.Deltas(ctx, "couponbase1", 0)
+		require.NoError(t, err)
+		require.Len(t, deltas, 3)
+
+		assert.Equal(t, uint64(1), deltas[0].Sequence)
+		assert.Equal(t, []string{"NEWCODE1"}, deltas[0].Added)
+		assert.Equal(t, uint64(2), deltas[1].Sequence)
+		assert.Equal(t, []string{"NEWCODE2"}, deltas[1].Added)
+		assert.Equal(t, []string{"OLDCODE1"}, deltas[1].Removed)
+		assert.Equal(t, uint64(10), deltas[2].Sequence)
+	})
+
+	t.Run("Skips deltas already applied", func(t *testing.T) {
+		deltas, err := source.Deltas(ctx, "couponbase1", 2)
+		require.NoError(t, err)
+		require.Len(t, deltas, 1)
+		assert.Equal(t, uint64(10), deltas[0].Sequence)
+	})
+
+	t.Run("Rejects malformed lines", func(t *testing.T) {
+		writeDeltaFile(t, dir, "couponbase3.1.delta.gz", "+GOODCODE1", "BADLINE")
+
+		_, err := source.Deltas(ctx, "couponbase3", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := NewMapCouponSet(3).(*mapCouponSet)
+	base.Add("BASECODE1")
+	base.Add("BASECODE2")
+	base.Add("BASECODE3")
+
+	first := applyDelta(base, Delta{Sequence: 1, Added: []string{"NEWCODE1"}, Removed: []string{"BASECODE1"}})
+	second := applyDelta(first, Delta{Sequence: 2, Added: []string{"BASECODE1", "NEWCODE2"}, Removed: []string{"NEWCODE1", "BASECODE2"}})
+
+	// Earlier sets are left untouched so they can keep serving lookups
+	assert.True(t, base.Contains("BASECODE1"))
+	assert.False(t, base.Contains("NEWCODE1"))
+	assert.Equal(t, 3, base.Size())
+
+	assert.False(t, first.Contains("BASECODE1"))
+	assert.True(t, first.Contains("NEWCODE1"))
+	assert.Equal(t, 3, first.Size())
+
+	assert.True(t, second.Contains("BASECODE1"))
+	assert.False(t, second.Contains("BASECODE2"))
+	assert.True(t, second.Contains("BASECODE3"))
+	assert.False(t, second.Contains("NEWCODE1"))
+	assert.True(t, second.Contains("NEWCODE2"))
+	assert.Equal(t, 3, second.Size())
+}
+
+func TestValidator_ApplyDeltas(t *testing.T) {
+	ctx := context.Background()
+
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			set := NewMapCouponSet(2).(*mapCouponSet)
+			set.Add("BASECODE1")
+			set.Add("RETIRED99")
+			return set, nil
+		},
+	}
+
+	newValidator := func(t *testing.T, deltas *memDeltaSource) Validator {
+		config := &ValidatorConfig{
+			FilePaths:     []string{"data/coupons/couponbase1.gz", "data/coupons/couponbase2.gz"},
+			MinMatchCount: 2,
+			Deltas:        deltas,
+		}
+		v, err := NewValidator(ctx, config, loader, zerolog.Nop())
+		require.NoError(t, err)
+		t.Cleanup(func() { v.Close() })
+		return v
+	}
+
+	t.Run("Applies deltas published at load time", func(t *testing.T) {
+		deltas := &memDeltaSource{
+			base: map[string]uint64{"couponbase1": 4, "couponbase2": 7},
+			deltas: map[string][]Delta{
+				"couponbase1": {{Sequence: 5, Added: []string{"DELTACODE1"}}},
+				"couponbase2": {{Sequence: 8, Added: []string{"DELTACODE1"}}},
+			},
+		}
+		v := newValidator(t, deltas)
+
+		assert.NoError(t, v.Validate(ctx, "DELTACODE1"))
+	})
+
+	t.Run("Applies new deltas incrementally", func(t *testing.T) {
+		deltas := &memDeltaSource{base: map[string]uint64{}, deltas: map[string][]Delta{}}
+		v := newValidator(t, deltas)
+		require.NoError(t, v.Validate(ctx, "RETIRED99"))
+		assert.Nil(t, v.Status().UpdatedAt)
+
+		deltas.publish("couponbase1", Delta{Sequence: 1, Added: []string{"DELTACODE2"}, Removed: []string{"RETIRED99"}})
+		deltas.publish("couponbase2", Delta{Sequence: 1, Added: []string{"DELTACODE2"}})
+		require.NoError(t, v.ApplyDeltas(ctx))
+
+		assert.NoError(t, v.Validate(ctx, "DELTACODE2"))
+		assert.Equal(t, model.ErrInvalidPromoCode, v.Validate(ctx, "RETIRED99"))
+
+		status := v.Status()
+		assert.NotNil(t, status.UpdatedAt)
+		assert.Equal(t, []SetStatus{
+			{Name: "couponbase1", Size: 2, Sequence: 1},
+			{Name: "couponbase2", Size: 3, Sequence: 1},
+		}, status.Sets)
+		assert.Equal(t, 5, status.TotalCoupons)
+
+		// Nothing new to apply
+		assert.NoError(t, v.ApplyDeltas(ctx))
+	})
+
+	t.Run("Detects a missed delta", func(t *testing.T) {
+		deltas := &memDeltaSource{base: map[string]uint64{}, deltas: map[string][]Delta{}}
+		v := newValidator(t, deltas)
+
+		deltas.publish("couponbase1", Delta{Sequence: 2, Added: []string{"DELTACODE3"}})
+		err := v.ApplyDeltas(ctx)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDeltaSequenceGap))
+		assert.Contains(t, err.Error(), "expected delta 1, found 2")
+	})
+
+	t.Run("Detects a newer base snapshot", func(t *testing.T) {
+		deltas := &memDeltaSource{base: map[string]uint64{}, deltas: map[string][]Delta{}}
+		v := newValidator(t, deltas)
+
+		deltas.mu.Lock()
+		deltas.base["couponbase2"] = 3
+		deltas.mu.Unlock()
+		err := v.ApplyDeltas(ctx)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrDeltaSequenceGap))
+
+		// A full reload starts from the new base snapshot
+		require.NoError(t, v.Reload(ctx))
+		assert.NoError(t, v.ApplyDeltas(ctx))
+	})
+}
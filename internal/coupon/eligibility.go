@@ -0,0 +1,99 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// eligibilityChecker implements EligibilityChecker by looking up the
+// ordering customer in a CustomerSource.
+type eligibilityChecker struct {
+	customers CustomerSource
+	logger    zerolog.Logger
+}
+
+// NewEligibilityChecker creates a checker of the customers allowed to redeem
+// restricted discounts. A discount's restrictions must all be met: the
+// customer must be one of its CustomerIDs, belong to one of its Segments,
+// and place their first order when it is FirstOrderOnly. Orders placed
+// without a customer cannot redeem restricted discounts.
+func NewEligibilityChecker(customers CustomerSource, logger zerolog.Logger) EligibilityChecker {
+	return &eligibilityChecker{
+		customers: customers,
+		logger:    logger.With().Str("component", "coupon-eligibility").Logger(),
+	}
+}
+
+// CheckEligibility checks the customer may redeem the discount, returning the
+// domain error of the first restriction they fail.
+func (c *eligibilityChecker) CheckEligibility(ctx context.Context, discount *model.CouponDiscount, customerID *uuid.UUID) error {
+	if !discount.Restricted() {
+		return nil
+	}
+	if customerID == nil {
+		return restrictionError(discount)
+	}
+
+	if len(discount.CustomerIDs) > 0 && !slices.Contains(discount.CustomerIDs, *customerID) {
+		c.logger.Debug().
+			Str("coupon_code", discount.Code).
+			Str("customer_id", customerID.String()).
+			Msg("promo code not issued to customer")
+		return model.ErrCouponNotForCustomer
+	}
+
+	if len(discount.Segments) > 0 {
+		customer, err := c.customers.GetByID(ctx, *customerID)
+		if err != nil {
+			c.logger.Error().Err(err).Str("customer_id", customerID.String()).Msg("failed to look up customer")
+			return fmt.Errorf("failed to look up customer: %w", err)
+		}
+		if customer == nil {
+			return model.ErrCustomerNotFound
+		}
+		if !customer.InSegment(discount.Segments) {
+			c.logger.Debug().
+				Str("coupon_code", discount.Code).
+				Str("customer_id", customerID.String()).
+				Strs("segments", discount.Segments).
+				Msg("customer not in promo code segments")
+			return model.ErrCouponSegment
+		}
+	}
+
+	if discount.FirstOrderOnly {
+		hasOrders, err := c.customers.HasOrders(ctx, *customerID)
+		if err != nil {
+			c.logger.Error().Err(err).Str("customer_id", customerID.String()).Msg("failed to look up customer orders")
+			return fmt.Errorf("failed to look up customer orders: %w", err)
+		}
+		if hasOrders {
+			c.logger.Debug().
+				Str("coupon_code", discount.Code).
+				Str("customer_id", customerID.String()).
+				Msg("promo code only valid on first order")
+			return model.ErrCouponFirstOrderOnly
+		}
+	}
+
+	return nil
+}
+
+// restrictionError returns the error refusing a restricted discount to an
+// order whose customer cannot be checked.
+func restrictionError(discount *model.CouponDiscount) error {
+	switch {
+	case discount.FirstOrderOnly:
+		return model.ErrCouponFirstOrderOnly
+	case len(discount.CustomerIDs) > 0:
+		return model.ErrCouponNotForCustomer
+	default:
+		return model.ErrCouponSegment
+	}
+}
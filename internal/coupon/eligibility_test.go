@@ -0,0 +1,156 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCustomers serves customers and their order history from maps.
+type mapCustomers struct {
+	customers map[uuid.UUID]*model.Customer
+	ordered   map[uuid.UUID]bool
+	err       error
+}
+
+func (m mapCustomers) GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	return m.customers[id], m.err
+}
+
+func (m mapCustomers) HasOrders(ctx context.Context, id uuid.UUID) (bool, error) {
+	return m.ordered[id], m.err
+}
+
+func TestEligibilityChecker_CheckEligibility(t *testing.T) {
+	ctx := context.Background()
+
+	newcomer := &model.Customer{ID: uuid.New(), Segments: []string{"vip"}}
+	regular := &model.Customer{ID: uuid.New(), Segments: []string{"staff"}}
+	unknown := uuid.New()
+	customers := mapCustomers{
+		customers: map[uuid.UUID]*model.Customer{newcomer.ID: newcomer, regular.ID: regular},
+		ordered:   map[uuid.UUID]bool{regular.ID: true},
+	}
+
+	percentOff := 15.0
+	tests := []struct {
+		name       string
+		discount   *model.CouponDiscount
+		customerID *uuid.UUID
+		expected   error
+	}{
+		{
+			name:       "Unrestricted discount",
+			discount:   &model.CouponDiscount{Code: "TENPCT123", PercentOff: &percentOff},
+			customerID: nil,
+		},
+		{
+			name:     "No discount",
+			discount: nil,
+		},
+		{
+			name:       "First order",
+			discount:   &model.CouponDiscount{Code: "WELCOME15", PercentOff: &percentOff, FirstOrderOnly: true},
+			customerID: &newcomer.ID,
+		},
+		{
+			name:       "Repeat order",
+			discount:   &model.CouponDiscount{Code: "WELCOME15", PercentOff: &percentOff, FirstOrderOnly: true},
+			customerID: &regular.ID,
+			expected:   model.ErrCouponFirstOrderOnly,
+		},
+		{
+			name:     "First-order-only without a customer",
+			discount: &model.CouponDiscount{Code: "WELCOME15", PercentOff: &percentOff, FirstOrderOnly: true},
+			expected: model.ErrCouponFirstOrderOnly,
+		},
+		{
+			name:       "Issued to the customer",
+			discount:   &model.CouponDiscount{Code: "STAFF30X", PercentOff: &percentOff, CustomerIDs: []uuid.UUID{uuid.New(), regular.ID}},
+			customerID: &regular.ID,
+		},
+		{
+			name:       "Issued to another customer",
+			discount:   &model.CouponDiscount{Code: "STAFF30X", PercentOff: &percentOff, CustomerIDs: []uuid.UUID{regular.ID}},
+			customerID: &newcomer.ID,
+			expected:   model.ErrCouponNotForCustomer,
+		},
+		{
+			name:     "Issued to a customer without a customer",
+			discount: &model.CouponDiscount{Code: "STAFF30X", PercentOff: &percentOff, CustomerIDs: []uuid.UUID{regular.ID}},
+			expected: model.ErrCouponNotForCustomer,
+		},
+		{
+			name:       "Customer in a segment",
+			discount:   &model.CouponDiscount{Code: "VIPONLY20", PercentOff: &percentOff, Segments: []string{"VIP", "partner"}},
+			customerID: &newcomer.ID,
+		},
+		{
+			name:       "Customer outside the segments",
+			discount:   &model.CouponDiscount{Code: "VIPONLY20", PercentOff: &percentOff, Segments: []string{"vip"}},
+			customerID: &regular.ID,
+			expected:   model.ErrCouponSegment,
+		},
+		{
+			name:       "Unknown customer",
+			discount:   &model.CouponDiscount{Code: "VIPONLY20", PercentOff: &percentOff, Segments: []string{"vip"}},
+			customerID: &unknown,
+			expected:   model.ErrCustomerNotFound,
+		},
+		{
+			name:       "Every restriction must be met",
+			discount:   &model.CouponDiscount{Code: "STAFFWELC", PercentOff: &percentOff, Segments: []string{"staff"}, FirstOrderOnly: true},
+			customerID: &regular.ID,
+			expected:   model.ErrCouponFirstOrderOnly,
+		},
+	}
+
+	checker := NewEligibilityChecker(customers, zerolog.Nop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, checker.CheckEligibility(ctx, tt.discount, tt.customerID))
+		})
+	}
+
+	t.Run("Customer lookup fails", func(t *testing.T) {
+		failing := NewEligibilityChecker(mapCustomers{err: errors.New("connection reset")}, zerolog.Nop())
+		discount := &model.CouponDiscount{Code: "WELCOME15", PercentOff: &percentOff, FirstOrderOnly: true}
+
+		err := failing.CheckEligibility(ctx, discount, &newcomer.ID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset")
+	})
+}
+
+func TestValidator_CheckEligibility(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	percentOff := 15.0
+	restricted := &model.CouponDiscount{Code: "VIPONLY20", PercentOff: &percentOff, Segments: []string{"vip"}}
+
+	t.Run("Unrestricted discounts need no checker", func(t *testing.T) {
+		v := &validator{config: &ValidatorConfig{}}
+		assert.NoError(t, v.CheckEligibility(ctx, &model.CouponDiscount{Code: "TENPCT123", PercentOff: &percentOff}, &customerID))
+	})
+
+	t.Run("Restricted discounts are refused without a checker", func(t *testing.T) {
+		v := &validator{config: &ValidatorConfig{}}
+		assert.Equal(t, model.ErrCouponSegment, v.CheckEligibility(ctx, restricted, &customerID))
+	})
+
+	t.Run("Restricted discounts are checked", func(t *testing.T) {
+		customers := mapCustomers{customers: map[uuid.UUID]*model.Customer{
+			customerID: {ID: customerID, Segments: []string{"vip"}},
+		}}
+		v := &validator{config: &ValidatorConfig{Eligibility: NewEligibilityChecker(customers, zerolog.Nop())}}
+		assert.NoError(t, v.CheckEligibility(ctx, restricted, &customerID))
+	})
+}
@@ -0,0 +1,64 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"github.com/rs/zerolog"
+	"google.golang.org/api/option"
+)
+
+// gcsSource reads coupon files from a Google Cloud Storage bucket.
+type gcsSource struct {
+	bucket *storage.BucketHandle
+	name   string
+}
+
+// NewGCSLoader creates a new Google Cloud Storage coupon loader. Credentials
+// are resolved through Application Default Credentials, e.g. the workload
+// identity of the pod or GOOGLE_APPLICATION_CREDENTIALS.
+func NewGCSLoader(ctx context.Context, bucket string, logger zerolog.Logger) (Loader, error) {
+	return newGCSLoader(ctx, bucket, logger)
+}
+
+// NewGCSSource creates an ObjectSource reading raw coupon files from Google
+// Cloud Storage.
+func NewGCSSource(ctx context.Context, bucket string, logger zerolog.Logger) (ObjectSource, error) {
+	return newGCSLoader(ctx, bucket, logger)
+}
+
+// newGCSLoader creates the GCS client shared by NewGCSLoader and NewGCSSource.
+func newGCSLoader(ctx context.Context, bucket string, logger zerolog.Logger, opts ...option.ClientOption) (*storageLoader, error) {
+	logger = logger.With().Str("component", "gcs-coupon-loader").Logger()
+
+	// The client authenticates its own transport, so it cannot share the
+	// pooled HTTP client S3 uses
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create GCS client")
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	logger.Info().
+		Str("bucket", bucket).
+		Msg("GCS loader initialised")
+
+	return &storageLoader{
+		source:    &gcsSource{bucket: client.Bucket(bucket), name: bucket},
+		provider:  "GCS",
+		container: bucket,
+		logger:    logger,
+	}, nil
+}
+
+// Open returns the raw body of a GCS object. The client retries failed
+// requests with exponential backoff.
+func (s *gcsSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from GCS (bucket=%s, key=%s): %w", s.name, key, err)
+	}
+	return reader, nil
+}
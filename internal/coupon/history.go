@@ -0,0 +1,305 @@
+package coupon
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// ArchiveKey returns the key the archived copy of a coupon set's file is
+// stored under, relative to the archive's prefix: the set name and the
+// file's SHA-256 checksum, e.g. couponbase1/<checksum>.gz. Archiving every
+// uploaded file under its checksum keeps past files readable after the file
+// the validator loads from is replaced.
+func ArchiveKey(name, checksum string) string {
+	return name + "/" + checksum + ".gz"
+}
+
+// dirSource implements ObjectSource for files in a local directory.
+type dirSource struct {
+	dir string
+}
+
+// NewDirSource creates an ObjectSource reading keys as slash-separated paths
+// relative to dir, e.g. coupon files archived on a mounted volume.
+func NewDirSource(dir string) ObjectSource {
+	return &dirSource{dir: dir}
+}
+
+// Open opens the file stored under key.
+func (s *dirSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+}
+
+// History checks whether promo codes were valid at past times by searching
+// archived copies of the coupon files each generation serving validations
+// then had loaded. It applies the validator's current code pattern, file
+// weights, minimum match count and case sensitivity, which may differ from
+// those in force at the time, and cannot see deltas applied on top of a
+// generation.
+type History struct {
+	archive         ObjectSource // nil when coupon files are not archived
+	prefix          string
+	format          *regexp.Regexp
+	weights         map[string]int
+	minMatches      int
+	caseInsensitive bool
+	metadata        MetadataSource
+	logger          zerolog.Logger
+}
+
+// NewHistory creates a point-in-time checker applying the rules of the given
+// validator configuration. Archived coupon files are read from archive under
+// prefix followed by their ArchiveKey; a nil archive leaves every file
+// unchecked.
+func NewHistory(config *ValidatorConfig, archive ObjectSource, prefix string, logger zerolog.Logger) (*History, error) {
+	if config == nil {
+		config = DefaultValidatorConfig()
+	}
+
+	_, minMatches, err := config.matchWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{
+		archive:         archive,
+		prefix:          prefix,
+		weights:         config.Weights,
+		minMatches:      minMatches,
+		caseInsensitive: config.CaseInsensitive,
+		metadata:        config.Metadata,
+		logger:          logger.With().Str("component", "coupon-history").Logger(),
+	}
+
+	if config.CodePattern != "" {
+		h.format, err = regexp.Compile(config.CodePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coupon code pattern %q: %w", config.CodePattern, err)
+		}
+	}
+
+	return h, nil
+}
+
+// CheckAt reports whether a promo code was valid at the given time in each
+// of the generations serving validations then. Each archived file is read in
+// full and its checksum verified, so a check can take as long as loading the
+// files. Codes accepted by the coupon files are refused if their discount
+// had expired by then, going by the expiry the discount has now.
+func (h *History) CheckAt(ctx context.Context, promoCode string, at time.Time, generations []model.CouponGeneration) (*model.CouponValidityAt, error) {
+	code := promoCode
+	if h.caseInsensitive {
+		code = strings.ToUpper(code)
+	}
+
+	result := &model.CouponValidityAt{
+		Code:          promoCode,
+		At:            at,
+		MinMatchCount: h.minMatches,
+		Generations:   []model.CouponGenerationValidity{},
+	}
+
+	// Codes the validator refuses without searching the files
+	if h.format != nil && !h.format.MatchString(code) {
+		result.Verdict, result.Reason = model.CouponVerdictInvalid, model.ErrInvalidPromoFormat.Code
+		return result, nil
+	}
+	if len(code) < 8 || len(code) > 10 {
+		result.Verdict, result.Reason = model.CouponVerdictInvalid, model.ErrInvalidPromoLength.Code
+		return result, nil
+	}
+
+	// Search each archived file once, however many generations loaded it
+	searched := make(map[string]*bool)
+	for _, generation := range generations {
+		validity := model.CouponGenerationValidity{
+			Generation: generation.ID,
+			Instance:   generation.Instance,
+			LoadedAt:   generation.LoadedAt,
+			RetiredAt:  generation.RetiredAt,
+			Files:      make([]model.CouponFileSearch, 0, len(generation.Files)),
+		}
+
+		unchecked := 0
+		for _, file := range generation.Files {
+			search := model.CouponFileSearch{
+				Name:     file.Name,
+				Checksum: file.Checksum,
+				Weight:   h.weight(file.Name),
+			}
+
+			if file.Checksum != "" {
+				key := ArchiveKey(file.Name, file.Checksum)
+				found, ok := searched[key]
+				if !ok {
+					var err error
+					found, err = h.search(ctx, key, file.Checksum, code)
+					if err != nil {
+						return nil, err
+					}
+					searched[key] = found
+				}
+				search.Found = found
+			}
+
+			switch {
+			case search.Found == nil:
+				unchecked += search.Weight
+			case *search.Found:
+				validity.MatchCount += search.Weight
+			}
+			validity.Files = append(validity.Files, search)
+		}
+
+		switch {
+		case validity.MatchCount >= h.minMatches:
+			valid := true
+			validity.Valid = &valid
+		case validity.MatchCount+unchecked < h.minMatches:
+			valid := false
+			validity.Valid = &valid
+		}
+		result.Generations = append(result.Generations, validity)
+	}
+
+	result.Verdict = verdict(result.Generations)
+	if result.Verdict == model.CouponVerdictInvalid {
+		result.Reason = model.ErrInvalidPromoCode.Code
+		return result, nil
+	}
+
+	if h.metadata != nil {
+		discount, err := h.metadata.GetByCode(ctx, code)
+		if err != nil {
+			h.logger.Error().Err(err).Msg("failed to resolve promo code discount")
+			return nil, fmt.Errorf("failed to resolve promo code discount: %w", err)
+		}
+		if discount != nil && discount.ExpiresAt != nil {
+			result.ExpiresAt = discount.ExpiresAt
+			if discount.Expired(at) {
+				result.Verdict, result.Reason = model.CouponVerdictInvalid, model.ErrCouponExpired.Code
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// weight returns how much a match in the named coupon set counts.
+func (h *History) weight(name string) int {
+	if weight, ok := h.weights[name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// search reports whether the archived file stored under key contains the
+// code, or nil when the file cannot be read or does not match its checksum.
+// Only the context ending fails the search.
+func (h *History) search(ctx context.Context, key, checksum, code string) (*bool, error) {
+	if h.archive == nil {
+		return nil, nil
+	}
+
+	key = h.prefix + key
+	body, err := h.archive.Open(ctx, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		h.logger.Warn().Err(err).Str("key", key).Msg("archived coupon file unavailable")
+		return nil, nil
+	}
+	defer body.Close()
+
+	// Checksum every byte of the file, including any gzip does not read
+	hash := sha256.New()
+	stored := io.TeeReader(body, hash)
+
+	gzipReader, err := gzip.NewReader(stored)
+	if err != nil {
+		h.logger.Warn().Err(err).Str("key", key).Msg("failed to create gzip reader for archived coupon file")
+		return nil, nil
+	}
+	defer gzipReader.Close()
+
+	scanner := bufio.NewScanner(gzipReader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	found := false
+	for lines := 0; scanner.Scan(); lines++ {
+		// Check context cancellation periodically
+		if lines%1_000_000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == code || (h.caseInsensitive && strings.EqualFold(line, code)) {
+			found = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		h.logger.Warn().Err(err).Str("key", key).Msg("error reading archived coupon file")
+		return nil, nil
+	}
+	if _, err := io.Copy(io.Discard, stored); err != nil {
+		h.logger.Warn().Err(err).Str("key", key).Msg("error reading archived coupon file")
+		return nil, nil
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != checksum {
+		h.logger.Warn().
+			Str("key", key).
+			Str("checksum", sum).
+			Msg("archived coupon file does not match its recorded checksum")
+		return nil, nil
+	}
+
+	return &found, nil
+}
+
+// verdict combines the outcomes of the generations serving validations at
+// one time.
+func verdict(generations []model.CouponGenerationValidity) string {
+	valid, invalid, unknown := 0, 0, 0
+	for _, generation := range generations {
+		switch {
+		case generation.Valid == nil:
+			unknown++
+		case *generation.Valid:
+			valid++
+		default:
+			invalid++
+		}
+	}
+
+	switch {
+	case valid > 0 && invalid > 0:
+		return model.CouponVerdictMixed
+	case unknown > 0 || len(generations) == 0:
+		return model.CouponVerdictUnknown
+	case valid > 0:
+		return model.CouponVerdictValid
+	default:
+		return model.CouponVerdictInvalid
+	}
+}
@@ -0,0 +1,200 @@
+package coupon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-kart/internal/fixtures"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveCouponFile writes a coupon file into the archive in dir under its
+// ArchiveKey and returns its checksum.
+func archiveCouponFile(t *testing.T, dir, name string, codes ...string) string {
+	t.Helper()
+	path := fixtures.NewCouponFile(t, name+".gz").Codes(codes...).Write()
+	checksum := sha256File(t, path)
+
+	key := filepath.Join(dir, filepath.FromSlash(ArchiveKey(name, checksum)))
+	require.NoError(t, os.MkdirAll(filepath.Dir(key), 0o755))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(key, data, 0o644))
+	return checksum
+}
+
+// generationOf returns a generation that loaded the given files, each given
+// as a set name and checksum.
+func generationOf(id int64, files ...string) model.CouponGeneration {
+	generation := model.CouponGeneration{ID: id, Instance: "api-1", LoadedAt: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)}
+	for i := 0; i < len(files); i += 2 {
+		generation.Files = append(generation.Files, model.CouponGenerationFile{Name: files[i], Path: files[i] + ".gz", Checksum: files[i+1]})
+	}
+	return generation
+}
+
+func TestHistory_CheckAt(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	base1 := archiveCouponFile(t, dir, "couponbase1", "SUMMER2025", "WINTER2025")
+	base2 := archiveCouponFile(t, dir, "couponbase2", "SUMMER2025")
+	base3 := archiveCouponFile(t, dir, "couponbase3", "WINTER2025")
+	base2Old := archiveCouponFile(t, dir, "couponbase2", "WINTER2025")
+	missing := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	current := generationOf(2, "couponbase1", base1, "couponbase2", base2, "couponbase3", base3)
+	previous := generationOf(1, "couponbase1", base1, "couponbase2", base2Old, "couponbase3", base3)
+
+	history, err := NewHistory(DefaultValidatorConfig(), NewDirSource(dir), "", zerolog.Nop())
+	require.NoError(t, err)
+
+	t.Run("Valid in every generation", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "WINTER2025", at, []model.CouponGeneration{current, previous})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictValid, result.Verdict)
+		assert.Empty(t, result.Reason)
+		assert.Equal(t, 2, result.MinMatchCount)
+		require.Len(t, result.Generations, 2)
+		assert.Equal(t, 2, result.Generations[0].MatchCount)
+		assert.Equal(t, 3, result.Generations[1].MatchCount)
+		require.Len(t, result.Generations[0].Files, 3)
+		assert.Equal(t, true, *result.Generations[0].Files[0].Found)
+		assert.Equal(t, false, *result.Generations[0].Files[1].Found)
+	})
+
+	t.Run("Not found in enough files", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "AUTUMN2025", at, []model.CouponGeneration{current})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictInvalid, result.Verdict)
+		assert.Equal(t, model.ErrCodeInvalidPromoCode, result.Reason)
+		assert.Equal(t, false, *result.Generations[0].Valid)
+	})
+
+	t.Run("Generations disagree", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "SUMMER2025", at, []model.CouponGeneration{current, previous})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictMixed, result.Verdict)
+		assert.Equal(t, true, *result.Generations[0].Valid)
+		assert.Equal(t, false, *result.Generations[1].Valid)
+	})
+
+	t.Run("Missing archive decides nothing", func(t *testing.T) {
+		generation := generationOf(3, "couponbase1", base1, "couponbase2", missing, "couponbase3", base3)
+
+		result, err := history.CheckAt(ctx, "SUMMER2025", at, []model.CouponGeneration{generation})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictUnknown, result.Verdict)
+		assert.Nil(t, result.Generations[0].Valid)
+		assert.Nil(t, result.Generations[0].Files[1].Found)
+		assert.Equal(t, 1, result.Generations[0].MatchCount)
+	})
+
+	t.Run("Found in enough archived files", func(t *testing.T) {
+		generation := generationOf(3, "couponbase1", base1, "couponbase2", missing, "couponbase3", base3)
+
+		result, err := history.CheckAt(ctx, "WINTER2025", at, []model.CouponGeneration{generation})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictValid, result.Verdict)
+	})
+
+	t.Run("Archived copy does not match its checksum", func(t *testing.T) {
+		tampered := archiveCouponFile(t, dir, "couponbase9", "SUMMER2025")
+		other := archiveCouponFile(t, dir, "couponbase8", "SUMMER2025")
+		data, err := os.ReadFile(filepath.Join(dir, "couponbase8", other+".gz"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "couponbase9", tampered+".gz"), append(data, 0), 0o644))
+
+		result, err := history.CheckAt(ctx, "SUMMER2025", at, []model.CouponGeneration{generationOf(4, "couponbase9", tampered, "couponbase2", base2)})
+		require.NoError(t, err)
+
+		assert.Nil(t, result.Generations[0].Files[0].Found)
+		assert.Equal(t, model.CouponVerdictUnknown, result.Verdict)
+	})
+
+	t.Run("Sets not read from a file are unchecked", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "SUMMER2025", at, []model.CouponGeneration{generationOf(5, "couponbase1", "", "couponbase2", base2)})
+		require.NoError(t, err)
+
+		assert.Nil(t, result.Generations[0].Files[0].Found)
+		assert.Equal(t, model.CouponVerdictUnknown, result.Verdict)
+	})
+
+	t.Run("No generation serving", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "SUMMER2025", at, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictUnknown, result.Verdict)
+		assert.Empty(t, result.Generations)
+	})
+
+	t.Run("Invalid length refused without searching", func(t *testing.T) {
+		result, err := history.CheckAt(ctx, "SHORT", at, []model.CouponGeneration{current})
+		require.NoError(t, err)
+
+		assert.Equal(t, model.CouponVerdictInvalid, result.Verdict)
+		assert.Equal(t, model.ErrCodeInvalidPromoLength, result.Reason)
+		assert.Empty(t, result.Generations)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := history.CheckAt(cancelled, "SUMMER2025", at, []model.CouponGeneration{current})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestHistory_CheckAt_Expiry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	base1 := archiveCouponFile(t, dir, "couponbase1", "summer2025")
+	base2 := archiveCouponFile(t, dir, "couponbase2", "SUMMER2025")
+	generation := generationOf(1, "couponbase1", base1, "couponbase2", base2)
+
+	expiresAt := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	config := DefaultValidatorConfig()
+	config.CaseInsensitive = true
+	config.Metadata = mapMetadata{discounts: map[string]*model.CouponDiscount{
+		"SUMMER2025": {Code: "SUMMER2025", ExpiresAt: &expiresAt},
+	}}
+
+	history, err := NewHistory(config, NewDirSource(dir), "", zerolog.Nop())
+	require.NoError(t, err)
+
+	result, err := history.CheckAt(ctx, "Summer2025", expiresAt.Add(-time.Hour), []model.CouponGeneration{generation})
+	require.NoError(t, err)
+	assert.Equal(t, model.CouponVerdictValid, result.Verdict)
+	assert.Equal(t, "Summer2025", result.Code)
+	assert.Equal(t, expiresAt, *result.ExpiresAt)
+
+	result, err = history.CheckAt(ctx, "SUMMER2025", expiresAt, []model.CouponGeneration{generation})
+	require.NoError(t, err)
+	assert.Equal(t, model.CouponVerdictInvalid, result.Verdict)
+	assert.Equal(t, model.ErrCodeCouponExpired, result.Reason)
+}
+
+func TestHistory_WithoutArchive(t *testing.T) {
+	history, err := NewHistory(nil, nil, "", zerolog.Nop())
+	require.NoError(t, err)
+
+	result, err := history.CheckAt(context.Background(), "SUMMER2025", time.Now(), []model.CouponGeneration{generationOf(1, "couponbase1", "abc", "couponbase2", "def")})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.CouponVerdictUnknown, result.Verdict)
+	assert.Nil(t, result.Generations[0].Files[0].Found)
+}
@@ -0,0 +1,350 @@
+package coupon
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Coupon index layout. An index holds a coupon file's distinct codes as
+// fixed-width records in ascending byte order, each padded with zero bytes to
+// the width of the longest code, after a little-endian header:
+//
+//	magic  [8]byte  "MKCIDX01"
+//	width  uint32   record width in bytes
+//	_      uint32   reserved, zero
+//	count  uint64   number of records
+//
+// Fixed-width records need no offset table, so the file is exactly
+// indexHeaderSize + width*count bytes and can be searched in place once mapped.
+const (
+	indexMagic      = "MKCIDX01"
+	indexHeaderSize = 24
+)
+
+// IndexExt is the file extension of coupon indexes.
+const IndexExt = ".idx"
+
+// IndexPath returns where the index of a coupon file is kept: next to the
+// file when dir is empty, otherwise in dir, named after the coupon set,
+// e.g. "data/coupons/couponbase1.gz" becomes "data/coupons/couponbase1.idx".
+func IndexPath(dir, filePath string) string {
+	if dir == "" {
+		dir = filepath.Dir(filePath)
+	}
+	return filepath.Join(dir, SetName(filePath)+IndexExt)
+}
+
+// BuildIndex reads a gzipped coupon file and writes its codes to dstPath as a
+// sorted coupon index, returning the number of distinct codes. The file is
+// read twice, once to size the records and once to fill them, so memory use
+// is about the size of the finished index. The index is written to a
+// temporary file and renamed into place, so a server that has the previous
+// index mapped keeps reading it undisturbed. With foldCase set codes are
+// indexed upper-cased, as validators matching case-insensitively look them up.
+func BuildIndex(ctx context.Context, srcPath, dstPath string, foldCase bool) (int, error) {
+	scan := func(fn func(code string) error) error {
+		return scanCouponFile(ctx, srcPath, func(code string) error {
+			if foldCase {
+				code = strings.ToUpper(code)
+			}
+			return fn(code)
+		})
+	}
+
+	count, width := 0, 0
+	err := scan(func(code string) error {
+		if strings.IndexByte(code, 0) >= 0 {
+			return fmt.Errorf("coupon code %q contains a NUL byte", code)
+		}
+		count++
+		width = max(width, len(code))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("coupon file %s is empty", srcPath)
+	}
+
+	records := indexRecords{data: make([]byte, count*width), width: width, tmp: make([]byte, width)}
+	i := 0
+	err = scan(func(code string) error {
+		if i == count {
+			return fmt.Errorf("coupon file %s changed while indexing", srcPath)
+		}
+		copy(records.data[i*width:], code)
+		i++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if i != count {
+		return 0, fmt.Errorf("coupon file %s changed while indexing", srcPath)
+	}
+
+	sort.Sort(records)
+	count = records.dedupe()
+
+	if err := writeIndex(dstPath, records.data[:count*width], width, count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// scanCouponFile calls fn with every non-empty line of a gzipped coupon file.
+func scanCouponFile(ctx context.Context, path string, fn func(code string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open coupon file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader for %s: %w", path, err)
+	}
+	defer gzipReader.Close()
+
+	scanner := bufio.NewScanner(gzipReader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lines := 0
+	for scanner.Scan() {
+		// Check context cancellation periodically
+		if lines%1_000_000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		lines++
+
+		code := strings.TrimSpace(scanner.Text())
+		if code == "" {
+			continue
+		}
+		if err := fn(code); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading coupon file %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeIndex writes an index header and its records to path atomically.
+func writeIndex(path string, data []byte, width, count int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create coupon index %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	header := make([]byte, indexHeaderSize)
+	copy(header, indexMagic)
+	binary.LittleEndian.PutUint32(header[8:], uint32(width))
+	binary.LittleEndian.PutUint64(header[16:], uint64(count))
+
+	w := bufio.NewWriterSize(tmp, 1<<20)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write coupon index %s: %w", path, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write coupon index %s: %w", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write coupon index %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync coupon index %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close coupon index %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move coupon index into place at %s: %w", path, err)
+	}
+	return nil
+}
+
+// indexRecords sorts fixed-width records in place.
+type indexRecords struct {
+	data  []byte
+	width int
+	tmp   []byte
+}
+
+func (r indexRecords) Len() int {
+	return len(r.data) / r.width
+}
+
+func (r indexRecords) Less(i, j int) bool {
+	return bytes.Compare(r.record(i), r.record(j)) < 0
+}
+
+func (r indexRecords) Swap(i, j int) {
+	copy(r.tmp, r.record(i))
+	copy(r.record(i), r.record(j))
+	copy(r.record(j), r.tmp)
+}
+
+func (r indexRecords) record(i int) []byte {
+	return r.data[i*r.width : (i+1)*r.width]
+}
+
+// dedupe drops repeated records from the sorted data and returns how many
+// distinct records remain at its start.
+func (r indexRecords) dedupe() int {
+	n := r.Len()
+	if n == 0 {
+		return 0
+	}
+	kept := 1
+	for i := 1; i < n; i++ {
+		if !bytes.Equal(r.record(i), r.record(kept-1)) {
+			copy(r.record(kept), r.record(i))
+			kept++
+		}
+	}
+	return kept
+}
+
+// indexCouponSet implements CouponSet by binary-searching a coupon index
+// mapped into memory. Opening it costs no more than mapping the file, and
+// the operating system pages records in as lookups touch them, so the
+// process heap stays small however many codes the index holds.
+type indexCouponSet struct {
+	data  []byte // records, after the header
+	width int
+	count int
+}
+
+// OpenIndex maps the coupon index at path. The mapping is released once the
+// set is no longer referenced.
+func OpenIndex(path string) (CouponSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coupon index %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat coupon index %s: %w", path, err)
+	}
+	if info.Size() < indexHeaderSize {
+		return nil, fmt.Errorf("coupon index %s is truncated", path)
+	}
+
+	data, unmap, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to map coupon index %s: %w", path, err)
+	}
+
+	set, err := parseIndex(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("invalid coupon index %s: %w", path, err)
+	}
+
+	runtime.AddCleanup(set, func(unmap func() error) { unmap() }, unmap)
+	return set, nil
+}
+
+// parseIndex checks an index header against the data length and returns a
+// set over its records.
+func parseIndex(data []byte) (*indexCouponSet, error) {
+	if string(data[:len(indexMagic)]) != indexMagic {
+		return nil, errors.New("not a coupon index")
+	}
+
+	width := int(binary.LittleEndian.Uint32(data[8:]))
+	count := binary.LittleEndian.Uint64(data[16:])
+	if width < 1 {
+		return nil, fmt.Errorf("invalid record width %d", width)
+	}
+	if uint64(len(data)-indexHeaderSize) != count*uint64(width) {
+		return nil, fmt.Errorf("expected %d records of %d bytes, file holds %d bytes", count, width, len(data)-indexHeaderSize)
+	}
+
+	return &indexCouponSet{data: data[indexHeaderSize:], width: width, count: int(count)}, nil
+}
+
+// Contains checks if a coupon code exists in the set.
+func (s *indexCouponSet) Contains(code string) bool {
+	if len(code) == 0 || len(code) > s.width {
+		return false
+	}
+
+	i := sort.Search(s.count, func(i int) bool {
+		return string(s.code(i)) >= code
+	})
+	return i < s.count && string(s.code(i)) == code
+}
+
+// Size returns the number of coupons in the set.
+func (s *indexCouponSet) Size() int {
+	return s.count
+}
+
+// code returns record i without its padding.
+func (s *indexCouponSet) code(i int) []byte {
+	record := s.data[i*s.width : (i+1)*s.width]
+	if end := bytes.IndexByte(record, 0); end >= 0 {
+		return record[:end]
+	}
+	return record
+}
+
+// indexLoader implements Loader by mapping prebuilt coupon indexes.
+type indexLoader struct {
+	dir    string
+	logger zerolog.Logger
+}
+
+// NewIndexLoader creates a loader that maps the coupon index of each coupon
+// file instead of reading the file itself, see IndexPath. Indexes are built
+// ahead of time with BuildIndex (cmd/coupon-index). The validator's set type
+// does not apply, since lookups search the index directly.
+func NewIndexLoader(dir string, logger zerolog.Logger) Loader {
+	return &indexLoader{
+		dir:    dir,
+		logger: logger.With().Str("component", "coupon-index-loader").Logger(),
+	}
+}
+
+// Load maps the index built for filePath.
+func (l *indexLoader) Load(ctx context.Context, filePath string) (CouponSet, error) {
+	path := IndexPath(l.dir, filePath)
+	l.logger.Info().Str("index", path).Msg("mapping coupon index")
+
+	set, err := OpenIndex(path)
+	if err != nil {
+		l.logger.Error().Err(err).Str("index", path).Msg("failed to map coupon index")
+		return nil, err
+	}
+
+	l.logger.Info().
+		Str("index", path).
+		Int("coupons_loaded", set.Size()).
+		Msg("coupon index mapped successfully")
+
+	return set, nil
+}
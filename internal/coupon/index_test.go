@@ -0,0 +1,127 @@
+package coupon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIndex(t *testing.T) {
+	ctx := context.Background()
+	src := createTestCouponFile(t, "couponbase1.gz", []string{
+		"WAFFLE2024", "  HAPPYHRS ", "", "ABCDEFGH", "HAPPYHRS", "ZZZZZZZZ", "ABCDEFGHI",
+	})
+	dst := IndexPath(t.TempDir(), src)
+	assert.Equal(t, "couponbase1.idx", filepath.Base(dst))
+
+	count, err := BuildIndex(ctx, src, dst, false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	set, err := OpenIndex(dst)
+	require.NoError(t, err)
+	assert.Equal(t, 5, set.Size())
+
+	for _, code := range []string{"ABCDEFGH", "ABCDEFGHI", "HAPPYHRS", "WAFFLE2024", "ZZZZZZZZ"} {
+		assert.True(t, set.Contains(code), code)
+	}
+	for _, code := range []string{"", "ABCDEFG", "ABCDEFGHIJ", "AAAAAAAA", "ZZZZZZZZZ", "WAFFLE20245", "happyhrs"} {
+		assert.False(t, set.Contains(code), code)
+	}
+
+	// Lookups search the mapping in place
+	allocs := testing.AllocsPerRun(100, func() { set.Contains("HAPPYHRS") })
+	assert.Zero(t, allocs)
+}
+
+func TestBuildIndex_FoldCase(t *testing.T) {
+	src := createTestCouponFile(t, "couponbase1.gz", []string{"HappyHrs", "HAPPYHRS", "waffle2024"})
+	dst := IndexPath(t.TempDir(), src)
+
+	count, err := BuildIndex(context.Background(), src, dst, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	set, err := OpenIndex(dst)
+	require.NoError(t, err)
+	assert.True(t, set.Contains("HAPPYHRS"))
+	assert.True(t, set.Contains("WAFFLE2024"))
+	assert.False(t, set.Contains("waffle2024"))
+}
+
+func TestBuildIndex_EmptyFile(t *testing.T) {
+	src := createTestCouponFile(t, "empty.gz", []string{"", " "})
+
+	_, err := BuildIndex(context.Background(), src, filepath.Join(t.TempDir(), "empty.idx"), false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is empty")
+}
+
+func TestOpenIndex_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	src := createTestCouponFile(t, "couponbase1.gz", []string{"HAPPYHRS", "WAFFLE2024"})
+	valid := filepath.Join(dir, "valid.idx")
+	_, err := BuildIndex(context.Background(), src, valid, false)
+	require.NoError(t, err)
+	data, err := os.ReadFile(valid)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		errorMsg string
+	}{
+		{name: "Too short for a header", data: data[:10], errorMsg: "truncated"},
+		{name: "Not an index", data: append([]byte("NOTANIDX"), data[8:]...), errorMsg: "not a coupon index"},
+		{name: "Records cut off", data: data[:len(data)-3], errorMsg: "expected 2 records of 10 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "broken.idx")
+			require.NoError(t, os.WriteFile(path, tt.data, 0o644))
+
+			set, err := OpenIndex(path)
+
+			require.Error(t, err)
+			assert.Nil(t, set)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	_, err = OpenIndex(filepath.Join(dir, "missing.idx"))
+	assert.Error(t, err)
+}
+
+func TestIndexLoader_Load(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var paths []string
+	for _, name := range []string{"couponbase1.gz", "couponbase2.gz"} {
+		src := createTestCouponFile(t, name, []string{"HAPPYHRS", "WAFFLE2024"})
+		_, err := BuildIndex(ctx, src, IndexPath(dir, src), false)
+		require.NoError(t, err)
+		paths = append(paths, filepath.Join("data/coupons", name))
+	}
+
+	validator, err := NewValidator(ctx, &ValidatorConfig{
+		FilePaths:     paths,
+		MinMatchCount: 2,
+	}, NewIndexLoader(dir, zerolog.Nop()), zerolog.Nop())
+	require.NoError(t, err)
+	defer validator.Close()
+
+	assert.NoError(t, validator.Validate(ctx, "HAPPYHRS"))
+	assert.Error(t, validator.Validate(ctx, "UNKNOWN12"))
+	assert.Equal(t, 4, validator.Status().TotalCoupons)
+
+	_, err = NewIndexLoader(t.TempDir(), zerolog.Nop()).Load(ctx, "data/coupons/couponbase1.gz")
+	assert.Error(t, err)
+}
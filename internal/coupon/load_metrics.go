@@ -0,0 +1,152 @@
+package coupon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of the coupon load metrics.
+const meterName = "mini-kart/internal/coupon"
+
+// OpenTelemetry metrics recorded for each coupon file every time the coupon
+// files are loaded or reloaded, with the file's coupon.set name (see SetName)
+// and the load's outcome, "success" or "error". Sudden changes show partner
+// files growing or changing format before they slow down startups.
+const (
+	MetricLoadDuration = "coupon.file.load.duration"
+	MetricLoadBytes    = "coupon.file.load.bytes"
+	MetricLoadLines    = "coupon.file.load.lines"
+	MetricSetSize      = "coupon.file.set.size"
+)
+
+// loadMetrics holds the instruments the validator records coupon file loads
+// with.
+type loadMetrics struct {
+	duration metric.Float64Histogram
+	bytes    metric.Int64Histogram
+	lines    metric.Int64Histogram
+	size     metric.Int64Gauge
+}
+
+// newLoadMetrics creates the coupon load instruments from provider, or from
+// the global meter provider when provider is nil.
+func newLoadMetrics(provider metric.MeterProvider) (*loadMetrics, error) {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	meter := provider.Meter(meterName)
+
+	var m loadMetrics
+	var err error
+	if m.duration, err = meter.Float64Histogram(MetricLoadDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Time taken to load a coupon file")); err != nil {
+		return nil, fmt.Errorf("failed to create %s instrument: %w", MetricLoadDuration, err)
+	}
+	if m.bytes, err = meter.Int64Histogram(MetricLoadBytes,
+		metric.WithUnit("By"),
+		metric.WithDescription("Bytes read from a coupon file as stored, before decompression")); err != nil {
+		return nil, fmt.Errorf("failed to create %s instrument: %w", MetricLoadBytes, err)
+	}
+	if m.lines, err = meter.Int64Histogram(MetricLoadLines,
+		metric.WithUnit("{line}"),
+		metric.WithDescription("Coupon codes parsed from a coupon file")); err != nil {
+		return nil, fmt.Errorf("failed to create %s instrument: %w", MetricLoadLines, err)
+	}
+	if m.size, err = meter.Int64Gauge(MetricSetSize,
+		metric.WithUnit("{code}"),
+		metric.WithDescription("Coupon codes in the set loaded from a coupon file")); err != nil {
+		return nil, fmt.Errorf("failed to create %s instrument: %w", MetricSetSize, err)
+	}
+	return &m, nil
+}
+
+// record records the load of one coupon file. The set size is only recorded
+// for successful loads, so a failed reload leaves the last size in place.
+func (m *loadMetrics) record(ctx context.Context, filePath string, elapsed time.Duration, stats *loadStats, set CouponSet, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	name := attribute.String("coupon.set", SetName(filePath))
+	attrs := metric.WithAttributes(name, attribute.String("outcome", outcome))
+
+	m.duration.Record(ctx, elapsed.Seconds(), attrs)
+	if stats.bytes > 0 {
+		m.bytes.Record(ctx, stats.bytes, attrs)
+	}
+	if stats.lines > 0 {
+		m.lines.Record(ctx, stats.lines, attrs)
+	}
+	if err == nil {
+		m.size.Record(ctx, int64(set.Size()), metric.WithAttributes(name))
+	}
+}
+
+// loadStats collects what a loader read while loading one coupon file,
+// including any attempts a loader fell back from. Loaders that read coupon
+// codes record into the stats in their context, see loadStatsFrom; loaders
+// that read no codes, such as the index loader, leave them empty.
+type loadStats struct {
+	bytes int64
+	lines int64
+
+	// hash checksums the file last read, nil until a loader reads one
+	hash hash.Hash
+}
+
+// loadStatsKey is the context key of a load's stats.
+type loadStatsKey struct{}
+
+// withLoadStats returns a context loaders record the load's stats into.
+func withLoadStats(ctx context.Context, stats *loadStats) context.Context {
+	return context.WithValue(ctx, loadStatsKey{}, stats)
+}
+
+// loadStatsFrom returns the stats to record a load into. Loads outside the
+// validator record into stats that are discarded.
+func loadStatsFrom(ctx context.Context) *loadStats {
+	if stats, ok := ctx.Value(loadStatsKey{}).(*loadStats); ok {
+		return stats
+	}
+	return &loadStats{}
+}
+
+// countBytes returns a reader that records the bytes read from r and
+// checksums them. Each call starts a new checksum, so a load that falls back
+// to another copy of the file reports the checksum of the copy it loaded.
+func (s *loadStats) countBytes(r io.Reader) io.Reader {
+	s.hash = sha256.New()
+	return &countingReader{r: io.TeeReader(r, s.hash), n: &s.bytes}
+}
+
+// checksum returns the hex-encoded SHA-256 of the file as stored, or "" when
+// the loader read no file bytes, e.g. an index or the database.
+func (s *loadStats) checksum() string {
+	if s.hash == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.hash.Sum(nil))
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+// Read reads from the underlying reader, counting the bytes read.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
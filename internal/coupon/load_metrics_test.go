@@ -0,0 +1,128 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectMetric returns the named metric collected by reader.
+func collectMetric(t *testing.T, reader sdkmetric.Reader, name string) metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("metric %s was not recorded", name)
+	return nil
+}
+
+// histogramPoint returns the histogram data point with the given attributes.
+func histogramPoint[N int64 | float64](t *testing.T, data metricdata.Aggregation, attrs ...attribute.KeyValue) metricdata.HistogramDataPoint[N] {
+	t.Helper()
+
+	histogram, ok := data.(metricdata.Histogram[N])
+	require.True(t, ok, "not a histogram: %T", data)
+	want := attribute.NewSet(attrs...)
+	for _, point := range histogram.DataPoints {
+		if point.Attributes.Equals(&want) {
+			return point
+		}
+	}
+	t.Fatalf("no data point with attributes %v", attrs)
+	return metricdata.HistogramDataPoint[N]{}
+}
+
+func TestValidator_LoadMetrics(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	file1 := createTestCouponFile(t, "couponbase1.gz", []string{"VALIDCODE1", "", "VALIDCODE2", "COMMON123"})
+	file2 := createTestCouponFile(t, "couponbase2.gz", []string{"VALIDCODE2"})
+
+	failReload := false
+	fileLoader := NewFileLoader(logger)
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			if failReload {
+				return nil, errors.New("file unavailable")
+			}
+			return fileLoader.Load(ctx, filePath)
+		},
+	}
+
+	config := &ValidatorConfig{
+		FilePaths:       []string{file1, file2},
+		MinMatchCount:   2,
+		ExpectedCoupons: 10,
+		MeterProvider:   provider,
+	}
+
+	validator, err := NewValidator(ctx, config, loader, logger)
+	require.NoError(t, err)
+	defer validator.Close()
+
+	base1 := attribute.String("coupon.set", "couponbase1")
+	base2 := attribute.String("coupon.set", "couponbase2")
+	success := attribute.String("outcome", "success")
+
+	t.Run("Lines parsed per file", func(t *testing.T) {
+		data := collectMetric(t, reader, MetricLoadLines)
+		assert.Equal(t, int64(3), histogramPoint[int64](t, data, base1, success).Sum)
+		assert.Equal(t, int64(1), histogramPoint[int64](t, data, base2, success).Sum)
+	})
+
+	t.Run("Bytes read per file", func(t *testing.T) {
+		data := collectMetric(t, reader, MetricLoadBytes)
+		assert.Positive(t, histogramPoint[int64](t, data, base1, success).Sum)
+		assert.Positive(t, histogramPoint[int64](t, data, base2, success).Sum)
+	})
+
+	t.Run("Duration per file", func(t *testing.T) {
+		data := collectMetric(t, reader, MetricLoadDuration)
+		assert.Equal(t, uint64(1), histogramPoint[float64](t, data, base1, success).Count)
+		assert.Equal(t, uint64(1), histogramPoint[float64](t, data, base2, success).Count)
+	})
+
+	t.Run("Set size per file", func(t *testing.T) {
+		gauge, ok := collectMetric(t, reader, MetricSetSize).(metricdata.Gauge[int64])
+		require.True(t, ok)
+		sizes := make(map[string]int64)
+		for _, point := range gauge.DataPoints {
+			name, _ := point.Attributes.Value("coupon.set")
+			sizes[name.AsString()] = point.Value
+		}
+		assert.Equal(t, map[string]int64{"couponbase1": 3, "couponbase2": 1}, sizes)
+	})
+
+	t.Run("Reloads are recorded", func(t *testing.T) {
+		require.NoError(t, validator.Reload(ctx))
+
+		data := collectMetric(t, reader, MetricLoadDuration)
+		assert.Equal(t, uint64(2), histogramPoint[float64](t, data, base1, success).Count)
+	})
+
+	t.Run("Failed loads are recorded as errors", func(t *testing.T) {
+		failReload = true
+		require.Error(t, validator.Reload(ctx))
+
+		data := collectMetric(t, reader, MetricLoadDuration)
+		assert.Equal(t, uint64(1), histogramPoint[float64](t, data, base1, attribute.String("outcome", "error")).Count)
+	})
+}
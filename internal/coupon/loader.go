@@ -36,17 +36,17 @@ func (l *fileLoader) Load(ctx context.Context, filePath string) (CouponSet, erro
 	}
 	defer file.Close()
 
-	// Create gzip reader
-	gzipReader, err := gzip.NewReader(file)
+	// Create gzip reader, counting the compressed bytes read
+	stats := loadStatsFrom(ctx)
+	gzipReader, err := gzip.NewReader(stats.countBytes(file))
 	if err != nil {
 		l.logger.Error().Err(err).Str("file", filePath).Msg("failed to create gzip reader")
 		return nil, fmt.Errorf("failed to create gzip reader for %s: %w", filePath, err)
 	}
 	defer gzipReader.Close()
 
-	// Create coupon set with estimated capacity
-	// For a 1GB file with 100M codes, pre-allocate to reduce reallocations
-	set := NewMapCouponSet(100_000_000).(*mapCouponSet)
+	// Create the coupon set configured by the validator
+	set := newCouponSet(ctx)
 
 	// Read line by line
 	scanner := bufio.NewScanner(gzipReader)
@@ -69,6 +69,7 @@ func (l *fileLoader) Load(ctx context.Context, filePath string) (CouponSet, erro
 		if line != "" {
 			set.Add(line)
 			lineCount++
+			stats.lines++
 		}
 	}
 
@@ -1,13 +1,14 @@
 package coupon
 
 import (
-	"compress/gzip"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"mini-kart/internal/fixtures"
+
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,22 +16,7 @@ import (
 
 // createTestCouponFile creates a gzipped test coupon file.
 func createTestCouponFile(t *testing.T, filename string, coupons []string) string {
-	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, filename)
-
-	file, err := os.Create(filePath)
-	require.NoError(t, err)
-	defer file.Close()
-
-	gzipWriter := gzip.NewWriter(file)
-	defer gzipWriter.Close()
-
-	for _, coupon := range coupons {
-		_, err := gzipWriter.Write([]byte(coupon + "\n"))
-		require.NoError(t, err)
-	}
-
-	return filePath
+	return fixtures.NewCouponFile(t, filename).Codes(coupons...).Write()
 }
 
 func TestFileLoader_Load_Success(t *testing.T) {
@@ -0,0 +1,174 @@
+package coupon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrMemoryLimitExceeded is returned when a coupon load is aborted because the
+// process reached the configured memory ceiling.
+var ErrMemoryLimitExceeded = errors.New("coupon loading exceeded memory limit")
+
+// MemoryUsage is a point-in-time reading of process memory.
+type MemoryUsage struct {
+	// Heap is the number of bytes held by live and unswept heap objects.
+	Heap uint64
+
+	// RSS is the resident set size of the process. Zero when the platform
+	// does not expose it.
+	RSS uint64
+}
+
+// Peak returns the larger of the heap and resident set size.
+func (u MemoryUsage) Peak() uint64 {
+	if u.RSS > u.Heap {
+		return u.RSS
+	}
+	return u.Heap
+}
+
+// MemoryLimitError describes a coupon load aborted by the memory watchdog.
+type MemoryLimitError struct {
+	Path  string
+	Usage MemoryUsage
+	Limit uint64
+}
+
+// Error implements error.
+func (e *MemoryLimitError) Error() string {
+	return fmt.Sprintf("%s: loading %s used %d bytes (heap=%d, rss=%d), limit is %d bytes",
+		ErrMemoryLimitExceeded, e.Path, e.Usage.Peak(), e.Usage.Heap, e.Usage.RSS, e.Limit)
+}
+
+// Unwrap allows errors.Is(err, ErrMemoryLimitExceeded).
+func (e *MemoryLimitError) Unwrap() error {
+	return ErrMemoryLimitExceeded
+}
+
+// memoryGuardedLoader implements Loader by sampling process memory while a
+// wrapped loader runs and cancelling the load when the ceiling is reached.
+type memoryGuardedLoader struct {
+	next     Loader
+	limit    uint64
+	interval time.Duration
+	usage    func() MemoryUsage
+	logger   zerolog.Logger
+}
+
+// NewMemoryGuardedLoader wraps a loader with a memory watchdog. The process
+// heap and RSS are sampled every interval during a load; once either reaches
+// limitBytes the load is cancelled and a *MemoryLimitError is returned, so the
+// failure is reported instead of the process being OOM-killed mid-load.
+func NewMemoryGuardedLoader(next Loader, limitBytes uint64, interval time.Duration, logger zerolog.Logger) Loader {
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+
+	return &memoryGuardedLoader{
+		next:     next,
+		limit:    limitBytes,
+		interval: interval,
+		usage:    readMemoryUsage,
+		logger:   logger.With().Str("component", "coupon-memory-watchdog").Logger(),
+	}
+}
+
+// Load runs the wrapped loader under the memory watchdog.
+func (l *memoryGuardedLoader) Load(ctx context.Context, filePath string) (CouponSet, error) {
+	// Don't start a load that has no headroom left
+	if usage := l.usage(); usage.Peak() >= l.limit {
+		return nil, l.abort(filePath, usage)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if usage := l.usage(); usage.Peak() >= l.limit {
+					cancel(&MemoryLimitError{Path: filePath, Usage: usage, Limit: l.limit})
+					return
+				}
+			}
+		}
+	}()
+
+	set, err := l.next.Load(ctx, filePath)
+
+	var limitErr *MemoryLimitError
+	if errors.As(context.Cause(ctx), &limitErr) {
+		// Hand the partially loaded set's memory back before reporting
+		debug.FreeOSMemory()
+		return nil, l.abort(filePath, limitErr.Usage)
+	}
+
+	return set, err
+}
+
+// abort logs the memory diagnostics for an aborted load and returns its error.
+func (l *memoryGuardedLoader) abort(filePath string, usage MemoryUsage) error {
+	l.logger.Error().
+		Str("file", filePath).
+		Uint64("heap_bytes", usage.Heap).
+		Uint64("rss_bytes", usage.RSS).
+		Uint64("limit_bytes", l.limit).
+		Msg("coupon loading aborted: memory limit reached")
+
+	return &MemoryLimitError{Path: filePath, Usage: usage, Limit: l.limit}
+}
+
+// readMemoryUsage samples the Go heap and, where available, the process RSS.
+func readMemoryUsage() MemoryUsage {
+	samples := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(samples)
+
+	usage := MemoryUsage{RSS: readRSS()}
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		usage.Heap = samples[0].Value.Uint64()
+	}
+	return usage
+}
+
+// readRSS returns the resident set size from /proc, or zero if unavailable.
+func readRSS() uint64 {
+	file, err := os.Open("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	// statm reports sizes in pages: total, resident, ...
+	scanner := bufio.NewScanner(file)
+	scanner.Split(bufio.ScanWords)
+	if !scanner.Scan() || !scanner.Scan() {
+		return 0
+	}
+
+	pages, err := strconv.ParseUint(strings.TrimSpace(scanner.Text()), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
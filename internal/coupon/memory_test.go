@@ -0,0 +1,95 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryGuardedLoader(t *testing.T) {
+	const limit = 1 << 30
+
+	t.Run("Load within limit", func(t *testing.T) {
+		next := &mockLoader{
+			loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+				return NewMapCouponSet(1), nil
+			},
+		}
+		loader := NewMemoryGuardedLoader(next, limit, time.Millisecond, zerolog.Nop()).(*memoryGuardedLoader)
+		loader.usage = func() MemoryUsage { return MemoryUsage{Heap: limit / 2} }
+
+		set, err := loader.Load(context.Background(), "couponbase1.gz")
+		require.NoError(t, err)
+		assert.NotNil(t, set)
+	})
+
+	t.Run("Aborts when limit is reached mid-load", func(t *testing.T) {
+		next := &mockLoader{
+			loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+				// Simulate a long load that honours cancellation
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+		var samples atomic.Int32
+		loader := NewMemoryGuardedLoader(next, limit, time.Millisecond, zerolog.Nop()).(*memoryGuardedLoader)
+		loader.usage = func() MemoryUsage {
+			if samples.Add(1) < 3 {
+				return MemoryUsage{Heap: limit / 2}
+			}
+			return MemoryUsage{Heap: limit / 2, RSS: limit + 1}
+		}
+
+		set, err := loader.Load(context.Background(), "couponbase1.gz")
+		require.Error(t, err)
+		assert.Nil(t, set)
+		assert.True(t, errors.Is(err, ErrMemoryLimitExceeded))
+
+		var limitErr *MemoryLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, "couponbase1.gz", limitErr.Path)
+		assert.Equal(t, uint64(limit+1), limitErr.Usage.RSS)
+	})
+
+	t.Run("Refuses to start without headroom", func(t *testing.T) {
+		called := false
+		next := &mockLoader{
+			loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+				called = true
+				return NewMapCouponSet(1), nil
+			},
+		}
+		loader := NewMemoryGuardedLoader(next, limit, time.Millisecond, zerolog.Nop()).(*memoryGuardedLoader)
+		loader.usage = func() MemoryUsage { return MemoryUsage{Heap: limit} }
+
+		_, err := loader.Load(context.Background(), "couponbase1.gz")
+		assert.True(t, errors.Is(err, ErrMemoryLimitExceeded))
+		assert.False(t, called)
+	})
+
+	t.Run("Loader errors pass through", func(t *testing.T) {
+		loadErr := errors.New("file not found")
+		next := &mockLoader{
+			loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+				return nil, loadErr
+			},
+		}
+		loader := NewMemoryGuardedLoader(next, limit, time.Millisecond, zerolog.Nop()).(*memoryGuardedLoader)
+		loader.usage = func() MemoryUsage { return MemoryUsage{} }
+
+		_, err := loader.Load(context.Background(), "couponbase1.gz")
+		assert.Equal(t, loadErr, err)
+	})
+}
+
+func TestReadMemoryUsage(t *testing.T) {
+	usage := readMemoryUsage()
+	assert.NotZero(t, usage.Heap)
+	assert.GreaterOrEqual(t, usage.Peak(), usage.Heap)
+}
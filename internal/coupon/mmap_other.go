@@ -0,0 +1,18 @@
+//go:build !unix
+
+package coupon
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads size bytes of file into memory on platforms without mmap.
+// Lookups work the same, but opening costs a full read of the index.
+func mapFile(file *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,363 @@
+package coupon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3 download metrics, labelled by object key. Dividing the bytes by the
+// milliseconds gives the download bandwidth.
+const (
+	MetricS3DownloadBytes    = "coupon_s3_download_bytes_total"
+	MetricS3DownloadMillis   = "coupon_s3_download_milliseconds_total"
+	MetricS3DownloadRetries  = "coupon_s3_download_retries_total"
+	MetricS3DownloadFailures = "coupon_s3_download_failures_total"
+)
+
+// S3DownloadConfig configures how coupon files are downloaded from S3.
+type S3DownloadConfig struct {
+	// PartSize is the number of bytes fetched by each ranged GET. Parts are
+	// downloaded concurrently and retried on their own, so a dropped
+	// connection costs one part rather than the whole file. Zero downloads
+	// each object in a single request.
+	PartSize int64
+
+	// Concurrency is the number of parts downloaded ahead of the reader.
+	// At most this many parts are held in memory. Default: 1
+	Concurrency int
+
+	// MaxAttempts is how many times a request is tried before the download
+	// fails. Client errors such as a missing object are not retried.
+	// Default: 1
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry. It doubles after each
+	// failed attempt, up to MaxRetryDelay when that is set.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// Metrics is an optional registry for the MetricS3Download* counters.
+	Metrics *metrics.Registry
+}
+
+// s3GetObjectAPI is the part of the S3 client used to download objects.
+type s3GetObjectAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// download opens an S3 object for reading, in parts when a part size is
+// configured.
+func (l *s3Loader) download(ctx context.Context, key string) (io.ReadCloser, error) {
+	stats := &downloadStats{loader: l, key: key, start: time.Now()}
+
+	if l.config.PartSize <= 0 {
+		var body io.ReadCloser
+		err := l.retry(ctx, key, func() error {
+			result, err := l.client.GetObject(ctx, &s3.GetObjectInput{
+				Bucket: aws.String(l.bucket),
+				Key:    aws.String(key),
+			})
+			if err != nil {
+				return err
+			}
+			body = result.Body
+			return nil
+		})
+		if err != nil {
+			l.counter(MetricS3DownloadFailures, key).Inc()
+			return nil, fmt.Errorf("failed to get object from S3 (bucket=%s, key=%s): %w", l.bucket, key, err)
+		}
+		return &streamReader{body: body, stats: stats}, nil
+	}
+
+	// The first part also reveals the object's size
+	first, size, err := l.getPart(ctx, key, 0)
+	if err != nil {
+		l.counter(MetricS3DownloadFailures, key).Inc()
+		return nil, err
+	}
+	stats.add(int64(len(first)))
+
+	return newPartReader(ctx, l, key, first, size, stats), nil
+}
+
+// getPart downloads the part of an object starting at offset and returns it
+// with the object's total size.
+func (l *s3Loader) getPart(ctx context.Context, key string, offset int64) ([]byte, int64, error) {
+	var part []byte
+	var size int64
+	err := l.retry(ctx, key, func() error {
+		result, err := l.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(l.bucket),
+			Key:    aws.String(key),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+l.config.PartSize-1)),
+		})
+		if err != nil {
+			return err
+		}
+		defer result.Body.Close()
+
+		size, err = objectSize(aws.ToString(result.ContentRange))
+		if err != nil {
+			return err
+		}
+
+		buf := bytes.NewBuffer(make([]byte, 0, min(l.config.PartSize, size-offset)))
+		if _, err := buf.ReadFrom(result.Body); err != nil {
+			return err
+		}
+		if want := min(l.config.PartSize, size-offset); int64(buf.Len()) != want {
+			return fmt.Errorf("%w: got %d of %d bytes", io.ErrUnexpectedEOF, buf.Len(), want)
+		}
+		part = buf.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get bytes %d+ of S3 object (bucket=%s, key=%s): %w", offset, l.bucket, key, err)
+	}
+	return part, size, nil
+}
+
+// objectSize parses the total size from a Content-Range header such as
+// "bytes 0-1023/4096".
+func objectSize(contentRange string) (int64, error) {
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok || !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, fmt.Errorf("unexpected content range %q", contentRange)
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected content range %q", contentRange)
+	}
+	return size, nil
+}
+
+// retry calls fn until it succeeds, fails with an error that is not worth
+// retrying, or has been tried MaxAttempts times, backing off between attempts.
+func (l *s3Loader) retry(ctx context.Context, key string, fn func() error) error {
+	delay := l.config.RetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= l.config.MaxAttempts || !retryableS3Error(err) || ctx.Err() != nil {
+			return err
+		}
+
+		l.counter(MetricS3DownloadRetries, key).Inc()
+		l.logger.Warn().
+			Err(err).
+			Str("key", key).
+			Int("attempt", attempt).
+			Dur("retry_in", delay).
+			Msg("S3 request failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if l.config.MaxRetryDelay > 0 {
+			delay = min(delay, l.config.MaxRetryDelay)
+		}
+	}
+}
+
+// retryableS3Error reports whether a failed S3 request may succeed if tried
+// again: server errors, throttling, and failures to connect or read the body.
+func retryableS3Error(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		status := statusErr.HTTPStatusCode()
+		return status >= 500 || status == http.StatusTooManyRequests || status == http.StatusRequestTimeout
+	}
+	return true
+}
+
+// counter returns a download counter for key, or a throwaway counter when
+// no metrics registry is configured.
+func (l *s3Loader) counter(name, key string) *metrics.Counter {
+	if l.config.Metrics == nil {
+		return &metrics.Counter{}
+	}
+	return l.config.Metrics.Counter(name, "key", key)
+}
+
+// downloadStats tallies the bytes downloaded for one object and reports them
+// once the download is closed.
+type downloadStats struct {
+	loader *s3Loader
+	key    string
+	start  time.Time
+
+	mu    sync.Mutex
+	bytes int64
+}
+
+func (s *downloadStats) add(n int64) {
+	s.mu.Lock()
+	s.bytes += n
+	s.mu.Unlock()
+}
+
+// finish records the download metrics and logs the bandwidth achieved.
+func (s *downloadStats) finish() {
+	s.mu.Lock()
+	downloaded := s.bytes
+	s.mu.Unlock()
+
+	elapsed := time.Since(s.start)
+	s.loader.counter(MetricS3DownloadBytes, s.key).Add(downloaded)
+	s.loader.counter(MetricS3DownloadMillis, s.key).Add(elapsed.Milliseconds())
+
+	mbps := 0.0
+	if elapsed > 0 {
+		mbps = float64(downloaded) / (1 << 20) / elapsed.Seconds()
+	}
+	s.loader.logger.Info().
+		Str("key", s.key).
+		Int64("bytes", downloaded).
+		Dur("elapsed", elapsed).
+		Float64("mb_per_second", mbps).
+		Msg("S3 object downloaded")
+}
+
+// streamReader reads an object downloaded in a single request.
+type streamReader struct {
+	body  io.ReadCloser
+	stats *downloadStats
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.stats.add(int64(n))
+	return n, err
+}
+
+func (r *streamReader) Close() error {
+	r.stats.finish()
+	return r.body.Close()
+}
+
+// partResult is a downloaded part or why it could not be downloaded.
+type partResult struct {
+	data []byte
+	err  error
+}
+
+// partReader reads an object part by part, downloading up to Concurrency
+// parts ahead of the reader.
+type partReader struct {
+	parts   []chan partResult // one per part after the first, in order
+	current []byte
+	next    int
+	err     error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	slots  chan struct{} // one per part downloaded but not yet read
+	wg     sync.WaitGroup
+	stats  *downloadStats
+	closed bool
+}
+
+// newPartReader starts downloading the parts of a size byte object that
+// follow first.
+func newPartReader(ctx context.Context, l *s3Loader, key string, first []byte, size int64, stats *downloadStats) *partReader {
+	ctx, cancel := context.WithCancel(ctx)
+
+	count := int((size - int64(len(first)) + l.config.PartSize - 1) / l.config.PartSize)
+	r := &partReader{
+		parts:   make([]chan partResult, count),
+		current: first,
+		ctx:     ctx,
+		cancel:  cancel,
+		slots:   make(chan struct{}, max(l.config.Concurrency, 1)),
+		stats:   stats,
+	}
+	for i := range r.parts {
+		r.parts[i] = make(chan partResult, 1)
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for i := range r.parts {
+			select {
+			case r.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			offset := int64(len(first)) + int64(i)*l.config.PartSize
+			r.wg.Add(1)
+			go func(i int) {
+				defer r.wg.Done()
+				data, _, err := l.getPart(ctx, key, offset)
+				if err == nil {
+					stats.add(int64(len(data)))
+				}
+				r.parts[i] <- partResult{data: data, err: err}
+			}(i)
+		}
+	}()
+
+	return r
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.next == len(r.parts) {
+			return 0, io.EOF
+		}
+
+		select {
+		case result := <-r.parts[r.next]:
+			<-r.slots
+			r.next++
+			r.current, r.err = result.data, result.err
+			if r.err != nil {
+				r.stats.loader.counter(MetricS3DownloadFailures, r.stats.key).Inc()
+			}
+		case <-r.ctx.Done():
+			r.err = r.ctx.Err()
+		}
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+// Close stops downloading parts that have not been read.
+func (r *partReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	r.cancel()
+	r.wg.Wait()
+	r.stats.finish()
+	return nil
+}
@@ -0,0 +1,217 @@
+package coupon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusError is an S3 error response with an HTTP status code.
+type statusError int
+
+func (e statusError) Error() string       { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatusCode() int { return int(e) }
+
+// fakeS3 serves one object, honouring byte ranges. fail is called before
+// each request and may fail it; truncate cuts the next n bodies short.
+type fakeS3 struct {
+	data []byte
+	fail func(rangeHeader string) error
+
+	mu       sync.Mutex
+	requests []string
+	truncate int
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	rangeHeader := aws.ToString(params.Range)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, rangeHeader)
+	truncate := f.truncate > 0
+	if truncate {
+		f.truncate--
+	}
+	f.mu.Unlock()
+
+	if f.fail != nil {
+		if err := f.fail(rangeHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	if rangeHeader == "" {
+		return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(f.data))}, nil
+	}
+
+	var start, end int64
+	if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end); err != nil {
+		return nil, err
+	}
+	end = min(end, int64(len(f.data))-1)
+	body := f.data[start : end+1]
+	if truncate {
+		body = body[:len(body)/2]
+	}
+
+	return &s3.GetObjectOutput{
+		Body:         io.NopCloser(bytes.NewReader(body)),
+		ContentRange: aws.String(fmt.Sprintf("bytes %d-%d/%d", start, end, len(f.data))),
+	}, nil
+}
+
+func newTestS3Loader(client s3GetObjectAPI, config S3DownloadConfig) *s3Loader {
+	return &s3Loader{client: client, bucket: "coupons", config: config, logger: zerolog.Nop()}
+}
+
+func TestS3Loader_RangedDownload(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 25)
+	client := &fakeS3{data: data}
+	registry := metrics.NewRegistry()
+	loader := newTestS3Loader(client, S3DownloadConfig{PartSize: 32, Concurrency: 3, MaxAttempts: 1, Metrics: registry})
+
+	body, err := loader.Open(ctx, "coupons/couponbase1.gz")
+	require.NoError(t, err)
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+
+	assert.Equal(t, data, got)
+	assert.Len(t, client.requests, 8)
+	assert.Equal(t, int64(len(data)), registry.Counter(MetricS3DownloadBytes, "key", "coupons/couponbase1.gz").Value())
+}
+
+func TestS3Loader_RetriesFailedParts(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("ABCDEFGH"), 16)
+
+	var mu sync.Mutex
+	failures := map[string]int{"bytes=32-63": 2}
+	client := &fakeS3{data: data, fail: func(rangeHeader string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures[rangeHeader] > 0 {
+			failures[rangeHeader]--
+			return statusError(http.StatusServiceUnavailable)
+		}
+		return nil
+	}}
+	client.truncate = 1 // the first part's body is cut off once
+
+	registry := metrics.NewRegistry()
+	loader := newTestS3Loader(client, S3DownloadConfig{PartSize: 32, Concurrency: 2, MaxAttempts: 3, Metrics: registry})
+
+	body, err := loader.Open(ctx, "couponbase1.gz")
+	require.NoError(t, err)
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	body.Close()
+
+	assert.Equal(t, data, got)
+	assert.Equal(t, int64(3), registry.Counter(MetricS3DownloadRetries, "key", "couponbase1.gz").Value())
+}
+
+func TestS3Loader_DownloadFailures(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("ABCDEFGH"), 16)
+
+	tests := []struct {
+		name             string
+		config           S3DownloadConfig
+		err              error
+		expectedRequests int
+	}{
+		{
+			name:             "Client errors are not retried",
+			config:           S3DownloadConfig{PartSize: 32, MaxAttempts: 3},
+			err:              statusError(http.StatusNotFound),
+			expectedRequests: 1,
+		},
+		{
+			name:             "Server errors are retried up to the limit",
+			config:           S3DownloadConfig{MaxAttempts: 3},
+			err:              statusError(http.StatusInternalServerError),
+			expectedRequests: 3,
+		},
+		{
+			name:             "Connection errors are retried",
+			config:           S3DownloadConfig{PartSize: 32, MaxAttempts: 2},
+			err:              errors.New("connection reset by peer"),
+			expectedRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeS3{data: data, fail: func(string) error { return tt.err }}
+			registry := metrics.NewRegistry()
+			tt.config.Metrics = registry
+			loader := newTestS3Loader(client, tt.config)
+
+			_, err := loader.Open(ctx, "couponbase1.gz")
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Len(t, client.requests, tt.expectedRequests)
+			assert.Equal(t, int64(1), registry.Counter(MetricS3DownloadFailures, "key", "couponbase1.gz").Value())
+		})
+	}
+
+	t.Run("A part failing mid-download fails the read", func(t *testing.T) {
+		client := &fakeS3{data: data, fail: func(rangeHeader string) error {
+			if rangeHeader == "bytes=64-95" {
+				return statusError(http.StatusForbidden)
+			}
+			return nil
+		}}
+		loader := newTestS3Loader(client, S3DownloadConfig{PartSize: 32, Concurrency: 2, MaxAttempts: 3})
+
+		body, err := loader.Open(ctx, "couponbase1.gz")
+		require.NoError(t, err)
+		defer body.Close()
+
+		_, err = io.ReadAll(body)
+		assert.ErrorIs(t, err, statusError(http.StatusForbidden))
+	})
+}
+
+func TestS3Loader_Load_Ranged(t *testing.T) {
+	src := createTestCouponFile(t, "couponbase1.gz", []string{"HAPPYHRS", "WAFFLE2024", "ABCDEFGH"})
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+
+	loader := newTestS3Loader(&fakeS3{data: data}, S3DownloadConfig{PartSize: 16, Concurrency: 2, MaxAttempts: 1})
+	ctx := withSetFactory(context.Background(), func() couponSetBuilder { return NewMapCouponSet(4).(*mapCouponSet) })
+
+	set, err := loader.Load(ctx, "couponbase1.gz")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, set.Size())
+	assert.True(t, set.Contains("WAFFLE2024"))
+}
+
+func TestObjectSize(t *testing.T) {
+	size, err := objectSize("bytes 0-1023/4096")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), size)
+
+	for _, contentRange := range []string{"", "bytes 0-1023/*", "0-1023/4096"} {
+		_, err := objectSize(contentRange)
+		assert.Error(t, err, contentRange)
+	}
+}
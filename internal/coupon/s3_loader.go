@@ -1,13 +1,11 @@
 package coupon
 
 import (
-	"bufio"
-	"compress/gzip"
 	"context"
 	"fmt"
-	"strings"
+	"io"
+	"net/http"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/rs/zerolog"
@@ -15,17 +13,34 @@ import (
 
 // s3Loader implements Loader for reading gzipped coupon files from AWS S3.
 type s3Loader struct {
-	client *s3.Client
+	client s3GetObjectAPI
 	bucket string
+	config S3DownloadConfig
 	logger zerolog.Logger
 }
 
-// NewS3Loader creates a new S3-based coupon loader.
-func NewS3Loader(ctx context.Context, bucket, region string, logger zerolog.Logger) (Loader, error) {
+// NewS3Loader creates a new S3-based coupon loader. A nil httpClient uses the
+// AWS SDK's default client.
+func NewS3Loader(ctx context.Context, bucket, region string, httpClient *http.Client, download S3DownloadConfig, logger zerolog.Logger) (Loader, error) {
+	return newS3Loader(ctx, bucket, region, httpClient, download, logger)
+}
+
+// NewS3Source creates an ObjectSource reading raw coupon files from S3. A nil
+// httpClient uses the AWS SDK's default client.
+func NewS3Source(ctx context.Context, bucket, region string, httpClient *http.Client, download S3DownloadConfig, logger zerolog.Logger) (ObjectSource, error) {
+	return newS3Loader(ctx, bucket, region, httpClient, download, logger)
+}
+
+// newS3Loader creates the S3 client shared by NewS3Loader and NewS3Source.
+func newS3Loader(ctx context.Context, bucket, region string, httpClient *http.Client, download S3DownloadConfig, logger zerolog.Logger) (*s3Loader, error) {
 	logger = logger.With().Str("component", "s3-coupon-loader").Logger()
 
 	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if httpClient != nil {
+		opts = append(opts, config.WithHTTPClient(httpClient))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to load AWS configuration")
 		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
@@ -37,11 +52,14 @@ func NewS3Loader(ctx context.Context, bucket, region string, logger zerolog.Logg
 	logger.Info().
 		Str("bucket", bucket).
 		Str("region", region).
+		Int64("part_size", download.PartSize).
+		Int("max_attempts", download.MaxAttempts).
 		Msg("S3 loader initialised")
 
 	return &s3Loader{
 		client: client,
 		bucket: bucket,
+		config: download,
 		logger: logger,
 	}, nil
 }
@@ -49,84 +67,13 @@ func NewS3Loader(ctx context.Context, bucket, region string, logger zerolog.Logg
 // Load reads a gzipped coupon file from S3 and returns a CouponSet.
 // The key parameter should be the full S3 key (including any prefix).
 func (l *s3Loader) Load(ctx context.Context, key string) (CouponSet, error) {
-	l.logger.Info().
-		Str("bucket", l.bucket).
-		Str("key", key).
-		Msg("loading coupon file from S3")
-
-	// Get object from S3
-	result, err := l.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(l.bucket),
-		Key:    aws.String(key),
-	})
-	if err != nil {
-		l.logger.Error().
-			Err(err).
-			Str("bucket", l.bucket).
-			Str("key", key).
-			Msg("failed to get object from S3")
-		return nil, fmt.Errorf("failed to get object from S3 (bucket=%s, key=%s): %w", l.bucket, key, err)
-	}
-	defer result.Body.Close()
-
-	// Create gzip reader
-	gzipReader, err := gzip.NewReader(result.Body)
-	if err != nil {
-		l.logger.Error().
-			Err(err).
-			Str("bucket", l.bucket).
-			Str("key", key).
-			Msg("failed to create gzip reader")
-		return nil, fmt.Errorf("failed to create gzip reader for S3 object %s: %w", key, err)
-	}
-	defer gzipReader.Close()
-
-	// Create coupon set with estimated capacity
-	set := NewMapCouponSet(100_000_000).(*mapCouponSet)
-
-	// Read line by line
-	scanner := bufio.NewScanner(gzipReader)
-	// Set larger buffer for better performance with big files
-	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
-
-	lineCount := 0
-	for scanner.Scan() {
-		// Check context cancellation periodically
-		if lineCount%1_000_000 == 0 {
-			select {
-			case <-ctx.Done():
-				l.logger.Warn().
-					Str("bucket", l.bucket).
-					Str("key", key).
-					Msg("coupon loading cancelled")
-				return nil, ctx.Err()
-			default:
-			}
-		}
-
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			set.Add(line)
-			lineCount++
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		l.logger.Error().
-			Err(err).
-			Str("bucket", l.bucket).
-			Str("key", key).
-			Msg("error reading coupon file from S3")
-		return nil, fmt.Errorf("error reading coupon file from S3 %s: %w", key, err)
-	}
-
-	l.logger.Info().
-		Str("bucket", l.bucket).
-		Str("key", key).
-		Int("coupons_loaded", set.Size()).
-		Msg("coupon file loaded successfully from S3")
+	loader := &storageLoader{source: l, provider: "S3", container: l.bucket, logger: l.logger}
+	return loader.Load(ctx, key)
+}
 
-	return set, nil
+// Open returns the raw body of an S3 object.
+func (l *s3Loader) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return l.download(ctx, key)
 }
 
 // FallbackLoader implements a loader that tries S3 first, then falls back to local file system.
@@ -1,5 +1,10 @@
 package coupon
 
+import (
+	"context"
+	"strings"
+)
+
 // mapCouponSet implements CouponSet using a map for O(1) lookups.
 type mapCouponSet struct {
 	coupons map[string]struct{}
@@ -27,3 +32,56 @@ func (s *mapCouponSet) Size() int {
 func (s *mapCouponSet) Add(code string) {
 	s.coupons[code] = struct{}{}
 }
+
+// Coupon set implementations selectable through ValidatorConfig.SetType.
+const (
+	// SetTypeMap stores every code exactly. Memory grows with the number of codes.
+	SetTypeMap = "map"
+
+	// SetTypeBloom stores codes in a Bloom filter with a fixed memory footprint
+	// and a configurable false-positive rate.
+	SetTypeBloom = "bloom"
+)
+
+// defaultSetCapacity is the number of codes a coupon file is expected to hold.
+// For a 1GB file with 100M codes, pre-allocating avoids repeated reallocation.
+const defaultSetCapacity = 100_000_000
+
+// couponSetBuilder is a CouponSet that loaders can populate.
+type couponSetBuilder interface {
+	CouponSet
+	Add(code string)
+}
+
+// setFactory creates an empty coupon set for a loader to fill.
+type setFactory func() couponSetBuilder
+
+// setFactoryKey is the context key carrying the validator's set factory to loaders.
+type setFactoryKey struct{}
+
+// withSetFactory returns a context instructing loaders how to build coupon sets.
+// Passing the factory through the context lets it reach the innermost loader
+// through any wrapping loaders (fallback, cache, memory watchdog).
+func withSetFactory(ctx context.Context, factory setFactory) context.Context {
+	return context.WithValue(ctx, setFactoryKey{}, factory)
+}
+
+// newCouponSet creates an empty coupon set using the factory in the context,
+// defaulting to a map-based set.
+func newCouponSet(ctx context.Context) couponSetBuilder {
+	if factory, ok := ctx.Value(setFactoryKey{}).(setFactory); ok && factory != nil {
+		return factory()
+	}
+	return NewMapCouponSet(defaultSetCapacity).(*mapCouponSet)
+}
+
+// foldingCouponSet upper-cases codes as they are added, for validators that
+// match codes case-insensitively.
+type foldingCouponSet struct {
+	couponSetBuilder
+}
+
+// Add adds the upper-case form of a coupon code to the set.
+func (s foldingCouponSet) Add(code string) {
+	s.couponSetBuilder.Add(strings.ToUpper(code))
+}
@@ -0,0 +1,119 @@
+package coupon
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Storage providers coupon files can be loaded from.
+const (
+	StorageProviderS3    = "s3"
+	StorageProviderGCS   = "gcs"
+	StorageProviderAzure = "azure"
+)
+
+// storageLoader implements Loader for gzipped coupon files held in cloud
+// object storage. It reads objects through the provider's ObjectSource, so
+// every provider loads files the same way.
+type storageLoader struct {
+	source    ObjectSource
+	provider  string // Provider name used in logs and errors, e.g. "GCS"
+	container string // Bucket or container the source reads from
+	logger    zerolog.Logger
+}
+
+// Load reads a gzipped coupon file from object storage and returns a
+// CouponSet. The key parameter should be the full object key (including
+// any prefix).
+func (l *storageLoader) Load(ctx context.Context, key string) (CouponSet, error) {
+	l.logger.Info().
+		Str("provider", l.provider).
+		Str("bucket", l.container).
+		Str("key", key).
+		Msg("loading coupon file from object storage")
+
+	// Get object from storage
+	body, err := l.source.Open(ctx, key)
+	if err != nil {
+		l.logger.Error().
+			Err(err).
+			Str("bucket", l.container).
+			Str("key", key).
+			Msg("failed to get object from storage")
+		return nil, err
+	}
+	defer body.Close()
+
+	// Create gzip reader, counting the compressed bytes read
+	stats := loadStatsFrom(ctx)
+	gzipReader, err := gzip.NewReader(stats.countBytes(body))
+	if err != nil {
+		l.logger.Error().
+			Err(err).
+			Str("bucket", l.container).
+			Str("key", key).
+			Msg("failed to create gzip reader")
+		return nil, fmt.Errorf("failed to create gzip reader for %s object %s: %w", l.provider, key, err)
+	}
+	defer gzipReader.Close()
+
+	// Create the coupon set configured by the validator
+	set := newCouponSet(ctx)
+
+	// Read line by line
+	scanner := bufio.NewScanner(gzipReader)
+	// Set larger buffer for better performance with big files
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineCount := 0
+	for scanner.Scan() {
+		// Check context cancellation periodically
+		if lineCount%1_000_000 == 0 {
+			select {
+			case <-ctx.Done():
+				l.logger.Warn().
+					Str("bucket", l.container).
+					Str("key", key).
+					Msg("coupon loading cancelled")
+				return nil, ctx.Err()
+			default:
+			}
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			set.Add(line)
+			lineCount++
+			stats.lines++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		l.logger.Error().
+			Err(err).
+			Str("bucket", l.container).
+			Str("key", key).
+			Msg("error reading coupon file from storage")
+		return nil, fmt.Errorf("error reading coupon file from %s %s: %w", l.provider, key, err)
+	}
+
+	l.logger.Info().
+		Str("provider", l.provider).
+		Str("bucket", l.container).
+		Str("key", key).
+		Int("coupons_loaded", set.Size()).
+		Msg("coupon file loaded successfully from object storage")
+
+	return set, nil
+}
+
+// Open returns the raw body of an object.
+func (l *storageLoader) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return l.source.Open(ctx, key)
+}
@@ -0,0 +1,95 @@
+package coupon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageLoader_Load(t *testing.T) {
+	src := createTestCouponFile(t, "couponbase1.gz", []string{"HAPPYHRS", "WAFFLE2024", "ABCDEFGH"})
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		openFunc    func(ctx context.Context, key string) (io.ReadCloser, error)
+		expectError string
+	}{
+		{
+			name: "Success",
+			openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+				assert.Equal(t, "coupons/couponbase1.gz", key)
+				return io.NopCloser(bytes.NewReader(data)), nil
+			},
+		},
+		{
+			name: "Object cannot be opened",
+			openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+				return nil, errors.New("bucket not found")
+			},
+			expectError: "bucket not found",
+		},
+		{
+			name: "Object is not gzipped",
+			openFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader([]byte("HAPPYHRS\n"))), nil
+			},
+			expectError: "failed to create gzip reader for GCS object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := &storageLoader{source: &mockSource{openFunc: tt.openFunc}, provider: "GCS", container: "coupons", logger: zerolog.Nop()}
+			ctx := withSetFactory(context.Background(), func() couponSetBuilder { return NewMapCouponSet(4).(*mapCouponSet) })
+
+			set, err := loader.Load(ctx, "coupons/couponbase1.gz")
+
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 3, set.Size())
+			assert.True(t, set.Contains("WAFFLE2024"))
+		})
+	}
+}
+
+func TestAzureBlobSource_Open(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coupons/couponbase1.gz" {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "8")
+		w.Write([]byte("HAPPYHRS"))
+	}))
+	defer server.Close()
+
+	client, err := azblob.NewClientWithNoCredential(server.URL+"/", nil)
+	require.NoError(t, err)
+	loader := newAzureBlobStorageLoader(client, "coupons", zerolog.Nop())
+
+	body, err := loader.Open(context.Background(), "couponbase1.gz")
+	require.NoError(t, err)
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "HAPPYHRS", string(got))
+
+	_, err = loader.Open(context.Background(), "missing.gz")
+	assert.ErrorContains(t, err, "failed to get blob from Azure (container=coupons, key=missing.gz)")
+}
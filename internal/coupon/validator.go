@@ -2,31 +2,231 @@ package coupon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"mini-kart/internal/model"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // validator implements Validator with concurrent coupon file lookups.
 type validator struct {
+	config  *ValidatorConfig
+	loader  Loader
+	newSet  setFactory
+	format  *regexp.Regexp // nil accepts any characters
+	metrics *loadMetrics
+	logger  zerolog.Logger
+
+	// weights holds the weight of each file in config.FilePaths, and
+	// minMatches the total weight a code's matches must reach
+	weights    []int
+	minMatches int
+
+	// updateMu serialises Reload and ApplyDeltas
+	updateMu sync.Mutex
+
+	// mu guards couponSets, which are read-only once loaded and swapped as a
+	// whole on reload, sequences, the last delta applied to each set, the
+	// checksums of the files they were loaded from and the generation that
+	// loaded them, the times they were loaded and last updated, and the
+	// outcome of the background load, if any
+	mu         sync.RWMutex
 	couponSets []CouponSet
-	logger     zerolog.Logger
-	// No mutex needed - coupon sets are read-only after initialization
+	sequences  []uint64
+	checksums  []string
+	generation int64
+	loadedAt   time.Time
+	updatedAt  time.Time
+	loading    bool
+	loadErr    error
+
+	// stopLoad cancels the background load and loadDone is closed once it
+	// has finished; both are nil when loading in the foreground
+	stopLoad context.CancelFunc
+	loadDone chan struct{}
 }
 
+// retireTimeout bounds recording the retirement of the validator's
+// generation when it is closed.
+const retireTimeout = 5 * time.Second
+
+// Background load retry delays. The delay doubles after each failed attempt.
+var (
+	minLoadRetryDelay = time.Second
+	maxLoadRetryDelay = time.Minute
+)
+
 // ValidatorConfig holds configuration for the coupon validator.
 type ValidatorConfig struct {
 	// FilePaths is the list of coupon file paths to load.
 	FilePaths []string
 
 	// MinMatchCount is the minimum number of files a code must appear in.
-	// Default: 2
+	// With Weights, it is the minimum total weight of the files the code
+	// appears in. Zero uses the default. Default: 2
 	MinMatchCount int
+
+	// Weights optionally sets how much a match in each file counts towards
+	// MinMatchCount, keyed by set name (see SetName), e.g. 2 so a primary
+	// coupon base counts double. Files without a weight count once.
+	Weights map[string]int
+
+	// SetType selects the coupon set implementation: SetTypeMap (exact) or
+	// SetTypeBloom (compact, with false positives). Empty means SetTypeMap.
+	SetType string
+
+	// ExpectedCoupons is the number of codes each file is sized for.
+	// Zero uses 100M.
+	ExpectedCoupons int
+
+	// FalsePositiveRate is the per-file false-positive probability of Bloom
+	// filter sets. A code must still match MinMatchCount files, so the chance
+	// of accepting an unknown code is much lower. Default: 0.001
+	FalsePositiveRate float64
+
+	// CodePattern is a regular expression every promo code must match before
+	// its length is checked or the coupon files are searched. Empty accepts
+	// any characters. Default: ^[A-Za-z0-9]*$
+	CodePattern string
+
+	// Deltas is an optional source of delta files applied on top of each
+	// loaded coupon file, so small daily updates don't require reloading
+	// the base files. Sets are named after their file with SetName.
+	Deltas DeltaSource
+
+	// Timeout bounds the coupon file lookup of a single validation. Lookups
+	// that overrun fail with model.ErrCouponValidationTimeout. Zero disables it.
+	Timeout time.Duration
+
+	// FailOpen accepts codes whose lookup timed out instead of failing them.
+	// Only suitable for low-risk campaigns.
+	FailOpen bool
+
+	// Metadata is an optional source of the discounts codes grant, resolved
+	// by ValidateAndResolve. Without it valid codes grant no discount.
+	Metadata MetadataSource
+
+	// Eligibility is an optional checker of the customers allowed to redeem
+	// discounts restricted to some customers. Without it restricted
+	// discounts are refused.
+	Eligibility EligibilityChecker
+
+	// LoadInBackground makes NewValidator return before the coupon files are
+	// loaded, so the server can start serving while multi-gigabyte files load.
+	// Until they have, validations fail with model.ErrCouponsLoading and Ready
+	// reports an error. A failed load is retried until it succeeds or the
+	// context given to NewValidator is cancelled.
+	LoadInBackground bool
+
+	// CaseInsensitive matches promo codes regardless of case. Codes are
+	// upper-cased as the coupon files and deltas are loaded and before each
+	// lookup. Coupon indexes must have been built with upper-cased codes.
+	CaseInsensitive bool
+
+	// MeterProvider records the Metric* load metrics of each coupon file.
+	// Nil uses the global OpenTelemetry meter provider.
+	MeterProvider metric.MeterProvider
+
+	// Generations optionally records each full load of the coupon files as
+	// a generation of Instance, with the checksum of every file it loaded.
+	// Deltas applied on top of a generation are not recorded.
+	Generations GenerationRecorder
+
+	// Instance identifies the validator in recorded generations, e.g. the
+	// host name.
+	Instance string
+}
+
+// setFactory returns the factory for the configured coupon set implementation.
+func (c *ValidatorConfig) setFactory() (setFactory, error) {
+	capacity := c.ExpectedCoupons
+	if capacity <= 0 {
+		capacity = defaultSetCapacity
+	}
+
+	var factory setFactory
+	switch c.SetType {
+	case "", SetTypeMap:
+		factory = func() couponSetBuilder {
+			return NewMapCouponSet(capacity).(*mapCouponSet)
+		}
+	case SetTypeBloom:
+		if c.FalsePositiveRate < 0 || c.FalsePositiveRate >= 1 {
+			return nil, fmt.Errorf("invalid coupon false-positive rate %v: must be between 0 and 1", c.FalsePositiveRate)
+		}
+		factory = func() couponSetBuilder {
+			return NewBloomCouponSet(capacity, c.FalsePositiveRate).(*bloomCouponSet)
+		}
+	default:
+		return nil, fmt.Errorf("invalid coupon set type %q: must be %s or %s", c.SetType, SetTypeMap, SetTypeBloom)
+	}
+
+	if c.CaseInsensitive {
+		exact := factory
+		factory = func() couponSetBuilder {
+			return foldingCouponSet{exact()}
+		}
+	}
+	return factory, nil
 }
 
+// defaultMinMatchCount is the number of files a code must appear in when
+// MinMatchCount is not set.
+const defaultMinMatchCount = 2
+
+// matchWeights returns the weight of each file in FilePaths and the total
+// weight a code's matches must reach. It fails when a weight is not positive
+// or names no configured file, or when no code could ever reach the minimum.
+func (c *ValidatorConfig) matchWeights() ([]int, int, error) {
+	minMatches := c.MinMatchCount
+	if minMatches == 0 {
+		minMatches = defaultMinMatchCount
+	}
+	if minMatches < 0 {
+		return nil, 0, fmt.Errorf("invalid coupon min match count %d: must be positive", c.MinMatchCount)
+	}
+
+	weights := make([]int, len(c.FilePaths))
+	names := make(map[string]bool, len(c.FilePaths))
+	total := 0
+	for i, path := range c.FilePaths {
+		name := SetName(path)
+		names[name] = true
+
+		weights[i] = 1
+		if weight, ok := c.Weights[name]; ok {
+			weights[i] = weight
+		}
+		total += weights[i]
+	}
+
+	for name, weight := range c.Weights {
+		if !names[name] {
+			return nil, 0, fmt.Errorf("invalid coupon file weight for %q: no coupon file has that name", name)
+		}
+		if weight <= 0 {
+			return nil, 0, fmt.Errorf("invalid coupon file weight %d for %q: must be positive", weight, name)
+		}
+	}
+
+	if minMatches > total {
+		return nil, 0, fmt.Errorf("coupon min match count %d exceeds the total weight %d of the coupon files", minMatches, total)
+	}
+	return weights, minMatches, nil
+}
+
+// DefaultCodePattern accepts ASCII letters and digits. The empty string is
+// allowed through so it is reported as a length error.
+const DefaultCodePattern = `^[A-Za-z0-9]*$`
+
 // DefaultValidatorConfig returns the default validator configuration.
 func DefaultValidatorConfig() *ValidatorConfig {
 	return &ValidatorConfig{
@@ -35,12 +235,16 @@ func DefaultValidatorConfig() *ValidatorConfig {
 			"data/coupons/couponbase2.gz",
 			"data/coupons/couponbase3.gz",
 		},
-		MinMatchCount: 2,
+		MinMatchCount:     2,
+		SetType:           SetTypeMap,
+		FalsePositiveRate: 0.001,
+		CodePattern:       DefaultCodePattern,
 	}
 }
 
 // NewValidator creates a new coupon validator.
-// It loads all coupon files at initialization time.
+// It loads all coupon files at initialization time, or starts loading them
+// in the background when config.LoadInBackground is set.
 func NewValidator(ctx context.Context, config *ValidatorConfig, loader Loader, logger zerolog.Logger) (Validator, error) {
 	if config == nil {
 		config = DefaultValidatorConfig()
@@ -51,33 +255,206 @@ func NewValidator(ctx context.Context, config *ValidatorConfig, loader Loader, l
 	logger.Info().
 		Int("file_count", len(config.FilePaths)).
 		Int("min_match_count", config.MinMatchCount).
+		Interface("weights", config.Weights).
+		Str("set_type", config.SetType).
+		Bool("case_insensitive", config.CaseInsensitive).
 		Msg("initialising coupon validator")
 
+	newSet, err := config.setFactory()
+	if err != nil {
+		return nil, err
+	}
+
 	v := &validator{
-		couponSets: make([]CouponSet, 0, len(config.FilePaths)),
-		logger:     logger,
+		config: config,
+		loader: loader,
+		newSet: newSet,
+		logger: logger,
+	}
+
+	v.metrics, err = newLoadMetrics(config.MeterProvider)
+	if err != nil {
+		return nil, err
 	}
 
+	if config.CodePattern != "" {
+		v.format, err = regexp.Compile(config.CodePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coupon code pattern %q: %w", config.CodePattern, err)
+		}
+	}
+
+	v.weights, v.minMatches, err = config.matchWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.LoadInBackground {
+		// Hold off reloads and delta updates until the first load completes
+		v.updateMu.Lock()
+		v.loading = true
+
+		loadCtx, stop := context.WithCancel(ctx)
+		v.stopLoad = stop
+		v.loadDone = make(chan struct{})
+		go func() {
+			defer close(v.loadDone)
+			defer v.updateMu.Unlock()
+			v.loadInBackground(loadCtx)
+		}()
+
+		logger.Info().Msg("loading coupon files in the background")
+		return v, nil
+	}
+
+	sets, sequences, checksums, err := v.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.couponSets = sets
+	v.sequences = sequences
+	v.checksums = checksums
+	v.loadedAt = time.Now()
+	v.generation = v.recordGeneration(ctx, sets, checksums, v.loadedAt)
+
+	logger.Info().
+		Int("total_coupons", totalSize(sets)).
+		Msg("coupon validator initialised successfully")
+
+	return v, nil
+}
+
+// load loads every coupon file and applies the deltas published since,
+// returning the sets with their sequences and file checksums.
+func (v *validator) load(ctx context.Context) ([]CouponSet, []uint64, []string, error) {
+	sets, checksums, err := v.loadSets(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sets, sequences, _, err := v.catchUp(ctx, sets, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return sets, sequences, checksums, nil
+}
+
+// recordGeneration records a full load of the coupon files with the
+// configured recorder, returning its generation or zero if it was not
+// recorded. Failing to record a load does not fail it.
+func (v *validator) recordGeneration(ctx context.Context, sets []CouponSet, checksums []string, loadedAt time.Time) int64 {
+	if v.config.Generations == nil {
+		return 0
+	}
+
+	generation := &model.CouponGeneration{
+		Instance: v.config.Instance,
+		LoadedAt: loadedAt,
+		Files:    make([]model.CouponGenerationFile, len(sets)),
+	}
+	for i, set := range sets {
+		generation.Files[i] = model.CouponGenerationFile{
+			Name:     SetName(v.config.FilePaths[i]),
+			Path:     v.config.FilePaths[i],
+			Checksum: checksums[i],
+			Codes:    set.Size(),
+		}
+	}
+
+	if err := v.config.Generations.RecordGeneration(ctx, generation); err != nil {
+		v.logger.Error().Err(err).Msg("failed to record coupon file generation")
+		return 0
+	}
+
+	v.logger.Info().Int64("generation", generation.ID).Msg("coupon file generation recorded")
+
+	return generation.ID
+}
+
+// loadInBackground loads the coupon files, retrying with backoff until a
+// load succeeds or ctx is cancelled. The caller holds updateMu.
+func (v *validator) loadInBackground(ctx context.Context) {
+	start := time.Now()
+	delay := minLoadRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		sets, sequences, checksums, err := v.load(ctx)
+		if err == nil {
+			loadedAt := time.Now()
+			generation := v.recordGeneration(ctx, sets, checksums, loadedAt)
+
+			v.mu.Lock()
+			v.couponSets = sets
+			v.sequences = sequences
+			v.checksums = checksums
+			v.generation = generation
+			v.loadedAt = loadedAt
+			v.loading = false
+			v.loadErr = nil
+			v.mu.Unlock()
+
+			v.logger.Info().
+				Int("total_coupons", totalSize(sets)).
+				Int("attempts", attempt).
+				Dur("elapsed", time.Since(start)).
+				Msg("coupon validator initialised successfully")
+			return
+		}
+
+		v.mu.Lock()
+		v.loadErr = err
+		v.mu.Unlock()
+
+		if ctx.Err() != nil {
+			v.logger.Warn().Err(err).Msg("background coupon load stopped")
+			return
+		}
+
+		v.logger.Error().
+			Err(err).
+			Int("attempt", attempt).
+			Dur("retry_in", delay).
+			Msg("background coupon load failed")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxLoadRetryDelay)
+	}
+}
+
+// loadSets loads all configured coupon files concurrently, returning the
+// sets with the checksums of their files.
+func (v *validator) loadSets(ctx context.Context) ([]CouponSet, []string, error) {
+	ctx = withSetFactory(ctx, v.newSet)
+
 	// Load all coupon files concurrently
 	type loadResult struct {
-		index int
-		set   CouponSet
-		err   error
+		index    int
+		set      CouponSet
+		checksum string
+		err      error
 	}
 
-	resultChan := make(chan loadResult, len(config.FilePaths))
+	resultChan := make(chan loadResult, len(v.config.FilePaths))
 	var wg sync.WaitGroup
 
-	for i, filePath := range config.FilePaths {
+	for i, filePath := range v.config.FilePaths {
 		wg.Add(1)
 		go func(index int, path string) {
 			defer wg.Done()
 
-			set, err := loader.Load(ctx, path)
+			stats := &loadStats{}
+			start := time.Now()
+			set, err := v.loader.Load(withLoadStats(ctx, stats), path)
+			v.metrics.record(ctx, path, time.Since(start), stats, set, err)
+
 			resultChan <- loadResult{
-				index: index,
-				set:   set,
-				err:   err,
+				index:    index,
+				set:      set,
+				checksum: stats.checksum(),
+				err:      err,
 			}
 		}(i, filePath)
 	}
@@ -87,62 +464,218 @@ func NewValidator(ctx context.Context, config *ValidatorConfig, loader Loader, l
 	close(resultChan)
 
 	// Collect results in order
-	results := make([]loadResult, len(config.FilePaths))
+	results := make([]loadResult, len(v.config.FilePaths))
 	for result := range resultChan {
 		results[result.index] = result
 	}
 
 	// Check for errors and populate coupon sets
+	sets := make([]CouponSet, 0, len(v.config.FilePaths))
+	checksums := make([]string, 0, len(v.config.FilePaths))
 	for i, result := range results {
 		if result.err != nil {
-			logger.Error().
+			v.logger.Error().
 				Err(result.err).
-				Str("file", config.FilePaths[i]).
+				Str("file", v.config.FilePaths[i]).
 				Msg("failed to load coupon file")
-			return nil, fmt.Errorf("failed to load coupon file %s: %w", config.FilePaths[i], result.err)
+			return nil, nil, fmt.Errorf("failed to load coupon file %s: %w", v.config.FilePaths[i], result.err)
 		}
-		v.couponSets = append(v.couponSets, result.set)
-		logger.Info().
-			Str("file", config.FilePaths[i]).
+		sets = append(sets, result.set)
+		checksums = append(checksums, result.checksum)
+		v.logger.Info().
+			Str("file", v.config.FilePaths[i]).
 			Int("size", result.set.Size()).
+			Str("checksum", result.checksum).
 			Msg("coupon file loaded")
 	}
 
-	totalCoupons := 0
-	for _, set := range v.couponSets {
-		totalCoupons += set.Size()
+	return sets, checksums, nil
+}
+
+// catchUp applies every delta published after each set's sequence and returns
+// the updated sets, their sequences and the number of deltas applied. A nil
+// sequences slice means the sets were just loaded from their base snapshots.
+// The given sets are left untouched.
+func (v *validator) catchUp(ctx context.Context, sets []CouponSet, sequences []uint64) ([]CouponSet, []uint64, int, error) {
+	if v.config.Deltas == nil {
+		return sets, sequences, 0, nil
 	}
 
-	logger.Info().
-		Int("total_coupons", totalCoupons).
-		Msg("coupon validator initialised successfully")
+	nextSets := make([]CouponSet, len(sets))
+	nextSequences := make([]uint64, len(sets))
+	applied := 0
 
-	return v, nil
+	for i, set := range sets {
+		name := SetName(v.config.FilePaths[i])
+
+		base, err := v.config.Deltas.BaseSequence(ctx, name)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to read coupon base sequence for %s: %w", name, err)
+		}
+
+		after := base
+		if sequences != nil {
+			after = sequences[i]
+			if base > after {
+				return nil, nil, 0, fmt.Errorf("%w: %s has a newer base snapshot at sequence %d, applied through %d",
+					ErrDeltaSequenceGap, name, base, after)
+			}
+		}
+
+		deltas, err := v.config.Deltas.Deltas(ctx, name, after)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to read coupon deltas for %s: %w", name, err)
+		}
+
+		for _, delta := range deltas {
+			if delta.Sequence != after+1 {
+				return nil, nil, 0, fmt.Errorf("%w: %s expected delta %d, found %d",
+					ErrDeltaSequenceGap, name, after+1, delta.Sequence)
+			}
+			set = applyDelta(set, v.normalizeDelta(delta))
+			after = delta.Sequence
+			applied++
+
+			v.logger.Info().
+				Str("coupon_set", name).
+				Uint64("sequence", delta.Sequence).
+				Int("added", len(delta.Added)).
+				Int("removed", len(delta.Removed)).
+				Msg("coupon delta applied")
+		}
+
+		nextSets[i] = set
+		nextSequences[i] = after
+	}
+
+	return nextSets, nextSequences, applied, nil
+}
+
+// normalizeDelta returns delta with its codes normalized like the coupon
+// files they apply to.
+func (v *validator) normalizeDelta(delta Delta) Delta {
+	if !v.config.CaseInsensitive {
+		return delta
+	}
+
+	normalized := Delta{
+		Sequence: delta.Sequence,
+		Added:    make([]string, len(delta.Added)),
+		Removed:  make([]string, len(delta.Removed)),
+	}
+	for i, code := range delta.Added {
+		normalized.Added[i] = strings.ToUpper(code)
+	}
+	for i, code := range delta.Removed {
+		normalized.Removed[i] = strings.ToUpper(code)
+	}
+	return normalized
+}
+
+// totalSize returns the combined number of coupons across sets.
+func totalSize(sets []CouponSet) int {
+	total := 0
+	for _, set := range sets {
+		total += set.Size()
+	}
+	return total
+}
+
+// Normalize returns the form promo codes are matched in: upper case when
+// matching case-insensitively, otherwise the code as given.
+func (v *validator) Normalize(promoCode string) string {
+	if v.config.CaseInsensitive {
+		return strings.ToUpper(promoCode)
+	}
+	return promoCode
 }
 
 // Validate checks if a promo code is valid.
 // A valid promo code must:
+// - Contain only characters allowed by the code pattern
 // - Be between 8 and 10 characters in length
-// - Appear in at least 2 out of 3 coupon files
+// - Appear in at least MinMatchCount coupon files, counting each file's weight
 func (v *validator) Validate(ctx context.Context, promoCode string) error {
-	// Validate length first (cheap check)
+	_, err := v.lookup(ctx, promoCode)
+	return err
+}
+
+// Check validates a promo code and reports the outcome with its match count,
+// the total weight of the coupon files found to contain it.
+func (v *validator) Check(ctx context.Context, promoCode string) model.CouponValidation {
+	matchCount, err := v.lookup(ctx, promoCode)
+
+	result := model.CouponValidation{Code: promoCode, Valid: err == nil, MatchCount: matchCount}
+	if domainErr, ok := err.(*model.DomainError); ok {
+		result.Reason = domainErr.Code
+	}
+	return result
+}
+
+// lookup checks a promo code's format and length, then counts the coupon
+// files containing it. It returns the match count and why the code is
+// invalid, if it is.
+func (v *validator) lookup(ctx context.Context, promoCode string) (int, error) {
+	promoCode = v.Normalize(promoCode)
+
+	// Reject garbage before anything else; the code itself is not logged
+	if v.format != nil && !v.format.MatchString(promoCode) {
+		v.logger.Debug().
+			Int("length", len(promoCode)).
+			Msg("promo code format invalid")
+		return 0, model.ErrInvalidPromoFormat
+	}
+
+	// Validate length next (cheap check)
 	if len(promoCode) < 8 || len(promoCode) > 10 {
 		v.logger.Debug().
 			Str("promo_code", promoCode).
 			Int("length", len(promoCode)).
 			Msg("promo code length invalid")
-		return model.ErrInvalidPromoLength
+		return 0, model.ErrInvalidPromoLength
+	}
+
+	v.mu.RLock()
+	sets, loading := v.couponSets, v.loading
+	v.mu.RUnlock()
+
+	if loading {
+		return 0, model.ErrCouponsLoading
+	}
+
+	lookupCtx := ctx
+	if v.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		lookupCtx, cancel = context.WithTimeout(ctx, v.config.Timeout)
+		defer cancel()
 	}
 
 	// Check presence in coupon files concurrently with early termination
-	matchCount := v.countMatches(ctx, promoCode)
+	matchCount := countMatches(lookupCtx, sets, v.weights, v.minMatches, promoCode)
+
+	// A lookup cut short by the timeout, rather than by the caller, is
+	// reported separately so checkout can retry instead of rejecting the code
+	if matchCount < v.minMatches && ctx.Err() == nil && lookupCtx.Err() != nil {
+		if v.config.FailOpen {
+			v.logger.Warn().
+				Dur("timeout", v.config.Timeout).
+				Int("match_count", matchCount).
+				Msg("promo code validation timed out, accepting code")
+			return matchCount, nil
+		}
+		v.logger.Warn().
+			Dur("timeout", v.config.Timeout).
+			Int("match_count", matchCount).
+			Msg("promo code validation timed out")
+		return matchCount, model.ErrCouponValidationTimeout
+	}
 
-	if matchCount < 2 {
+	if matchCount < v.minMatches {
 		v.logger.Debug().
 			Str("promo_code", promoCode).
 			Int("match_count", matchCount).
 			Msg("promo code not found in sufficient files")
-		return model.ErrInvalidPromoCode
+		return matchCount, model.ErrInvalidPromoCode
 	}
 
 	v.logger.Debug().
@@ -150,21 +683,75 @@ func (v *validator) Validate(ctx context.Context, promoCode string) error {
 		Int("match_count", matchCount).
 		Msg("promo code validated successfully")
 
-	return nil
+	return matchCount, nil
+}
+
+// ValidateAndResolve validates a promo code and resolves the discount it
+// grants from the configured metadata source.
+func (v *validator) ValidateAndResolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error) {
+	if err := v.Validate(ctx, promoCode); err != nil {
+		return nil, err
+	}
+	return v.Resolve(ctx, promoCode)
+}
+
+// Resolve resolves the discount a promo code grants from the configured
+// metadata source without checking the coupon files.
+func (v *validator) Resolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error) {
+	if v.config.Metadata == nil {
+		return nil, nil
+	}
+
+	discount, err := v.config.Metadata.GetByCode(ctx, v.Normalize(promoCode))
+	if err != nil {
+		v.logger.Error().Err(err).Msg("failed to resolve promo code discount")
+		return nil, fmt.Errorf("failed to resolve promo code discount: %w", err)
+	}
+	if discount != nil && discount.Expired(time.Now()) {
+		v.logger.Debug().
+			Str("promo_code", promoCode).
+			Time("expires_at", *discount.ExpiresAt).
+			Msg("promo code expired")
+		return nil, model.ErrCouponExpired
+	}
+
+	return discount, nil
+}
+
+// CheckEligibility checks a discount may be applied to an order placed by the
+// customer. Discounts open to every customer need no checker.
+func (v *validator) CheckEligibility(ctx context.Context, discount *model.CouponDiscount, customerID *uuid.UUID) error {
+	if !discount.Restricted() {
+		return nil
+	}
+	if v.config.Eligibility == nil {
+		return restrictionError(discount)
+	}
+	return v.config.Eligibility.CheckEligibility(ctx, discount, customerID)
 }
 
-// countMatches counts how many coupon files contain the given promo code.
-// Uses worker pool pattern with early termination when 2 matches are found.
-func (v *validator) countMatches(ctx context.Context, promoCode string) int {
+// countMatches sums the weights of the coupon files containing the given
+// promo code. Uses worker pool pattern with early termination once the sum
+// reaches required, or can no longer reach it.
+func countMatches(ctx context.Context, sets []CouponSet, weights []int, required int, promoCode string) int {
 	// Use buffered channel to prevent goroutine leaks on early termination
-	resultChan := make(chan bool, len(v.couponSets))
+	type matchResult struct {
+		weight int
+		found  bool
+	}
+	resultChan := make(chan matchResult, len(sets))
 	doneChan := make(chan struct{})
 	defer close(doneChan)
 
+	remaining := 0
+	for _, weight := range weights {
+		remaining += weight
+	}
+
 	// Launch workers for each coupon set
 	// Workers will exit early if doneChan is closed
-	for _, set := range v.couponSets {
-		go func(s CouponSet) {
+	for i, set := range sets {
+		go func(s CouponSet, weight int) {
 			// Check if we should exit early
 			select {
 			case <-doneChan:
@@ -178,33 +765,33 @@ func (v *validator) countMatches(ctx context.Context, promoCode string) int {
 
 			// Try to send result, but exit if done or context cancelled
 			select {
-			case resultChan <- found:
+			case resultChan <- matchResult{weight: weight, found: found}:
 			case <-doneChan:
 				return
 			case <-ctx.Done():
 				return
 			}
-		}(set)
+		}(set, weights[i])
 	}
 
 	// Count matches with early termination
 	matches := 0
 	checked := 0
 
-	for checked < len(v.couponSets) {
+	for checked < len(sets) {
 		select {
-		case found := <-resultChan:
+		case result := <-resultChan:
 			checked++
-			if found {
-				matches++
-				// Early termination: if we have 2 matches, we're done
-				if matches >= 2 {
+			remaining -= result.weight
+			if result.found {
+				matches += result.weight
+				// Early termination: if we have enough matches, we're done
+				if matches >= required {
 					return matches
 				}
 			}
-			// Early termination: if we can't possibly get 2 matches, exit
-			remaining := len(v.couponSets) - checked
-			if matches+remaining < 2 {
+			// Early termination: if we can't possibly get enough matches, exit
+			if matches+remaining < required {
 				return matches
 			}
 		case <-ctx.Done():
@@ -215,10 +802,150 @@ func (v *validator) countMatches(ctx context.Context, promoCode string) int {
 	return matches
 }
 
+// Reload reloads all coupon files and swaps them in once every file has loaded.
+// On failure the previously loaded sets remain in use.
+func (v *validator) Reload(ctx context.Context) error {
+	v.updateMu.Lock()
+	defer v.updateMu.Unlock()
+
+	v.logger.Info().Int("file_count", len(v.config.FilePaths)).Msg("reloading coupon files")
+
+	sets, sequences, checksums, err := v.load(ctx)
+	if err != nil {
+		return err
+	}
+	loadedAt := time.Now()
+	generation := v.recordGeneration(ctx, sets, checksums, loadedAt)
+
+	v.mu.Lock()
+	v.couponSets = sets
+	v.sequences = sequences
+	v.checksums = checksums
+	v.generation = generation
+	v.loadedAt = loadedAt
+	v.updatedAt = time.Time{}
+	v.loading = false
+	v.loadErr = nil
+	v.mu.Unlock()
+
+	v.logger.Info().
+		Int("total_coupons", totalSize(sets)).
+		Msg("coupon files reloaded")
+
+	return nil
+}
+
+// ApplyDeltas applies deltas published since the last load or delta update
+// and swaps in the updated sets. On failure the current sets remain in use; an
+// error wrapping ErrDeltaSequenceGap means a full Reload is required.
+func (v *validator) ApplyDeltas(ctx context.Context) error {
+	v.updateMu.Lock()
+	defer v.updateMu.Unlock()
+
+	v.mu.RLock()
+	sets, sequences := v.couponSets, v.sequences
+	v.mu.RUnlock()
+
+	sets, sequences, applied, err := v.catchUp(ctx, sets, sequences)
+	if err != nil {
+		return err
+	}
+	if applied == 0 {
+		return nil
+	}
+
+	v.mu.Lock()
+	v.couponSets = sets
+	v.sequences = sequences
+	v.updatedAt = time.Now()
+	v.mu.Unlock()
+
+	v.logger.Info().
+		Int("deltas_applied", applied).
+		Int("total_coupons", totalSize(sets)).
+		Msg("coupon deltas applied")
+
+	return nil
+}
+
+// Status reports the coupon data currently loaded.
+func (v *validator) Status() Status {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	status := Status{
+		SetType:      v.config.SetType,
+		Loading:      v.loading,
+		LoadedAt:     v.loadedAt,
+		Generation:   v.generation,
+		Sets:         make([]SetStatus, len(v.couponSets)),
+		TotalCoupons: totalSize(v.couponSets),
+	}
+	if status.SetType == "" {
+		status.SetType = SetTypeMap
+	}
+	if !v.updatedAt.IsZero() {
+		updatedAt := v.updatedAt
+		status.UpdatedAt = &updatedAt
+	}
+
+	for i, set := range v.couponSets {
+		status.Sets[i] = SetStatus{
+			Name: SetName(v.config.FilePaths[i]),
+			Size: set.Size(),
+		}
+		if v.sequences != nil {
+			status.Sets[i].Sequence = v.sequences[i]
+		}
+		if v.checksums != nil {
+			status.Sets[i].Checksum = v.checksums[i]
+		}
+	}
+
+	return status
+}
+
+// Ready reports whether the coupon files have loaded.
+func (v *validator) Ready() error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if !v.loading {
+		return nil
+	}
+	if v.loadErr != nil {
+		return fmt.Errorf("coupon files failed to load, retrying: %w", v.loadErr)
+	}
+	return errors.New("coupon files are loading")
+}
+
 // Close releases resources held by the validator.
 func (v *validator) Close() error {
+	// Abandon a background load still in progress
+	if v.stopLoad != nil {
+		v.stopLoad()
+		<-v.loadDone
+	}
+
 	// Clear coupon sets to allow GC to reclaim memory
+	v.mu.Lock()
+	generation := v.generation
 	v.couponSets = nil
+	v.checksums = nil
+	v.generation = 0
+	v.loadedAt = time.Time{}
+	v.updatedAt = time.Time{}
+	v.mu.Unlock()
+
+	// The instance stops serving its generation; registry audits would
+	// otherwise count it as in use until the instance loads another
+	if generation != 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), retireTimeout)
+		defer cancel()
+		if err := v.config.Generations.RetireGeneration(ctx, generation, time.Now()); err != nil {
+			v.logger.Error().Err(err).Int64("generation", generation).Msg("failed to retire coupon file generation")
+		}
+	}
 
 	v.logger.Info().Msg("coupon validator closed")
 
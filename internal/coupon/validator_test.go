@@ -2,7 +2,13 @@ package coupon
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"mini-kart/internal/model"
 
@@ -20,6 +26,7 @@ func TestDefaultValidatorConfig(t *testing.T) {
 	assert.Equal(t, "data/coupons/couponbase1.gz", config.FilePaths[0])
 	assert.Equal(t, "data/coupons/couponbase2.gz", config.FilePaths[1])
 	assert.Equal(t, "data/coupons/couponbase3.gz", config.FilePaths[2])
+	assert.Equal(t, DefaultCodePattern, config.CodePattern)
 }
 
 func TestNewValidator_Success(t *testing.T) {
@@ -48,6 +55,63 @@ func TestNewValidator_Success(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewValidator_BloomSet(t *testing.T) {
+	logger := zerolog.Nop()
+
+	file1 := createTestCouponFile(t, "coupon1.gz", []string{"VALIDCODE1", "COMMON123"})
+	file2 := createTestCouponFile(t, "coupon2.gz", []string{"VALIDCODE2", "COMMON123"})
+	file3 := createTestCouponFile(t, "coupon3.gz", []string{"VALIDCODE3"})
+
+	config := &ValidatorConfig{
+		FilePaths:         []string{file1, file2, file3},
+		MinMatchCount:     2,
+		SetType:           SetTypeBloom,
+		ExpectedCoupons:   1_000,
+		FalsePositiveRate: 0.001,
+	}
+
+	ctx := context.Background()
+	validator, err := NewValidator(ctx, config, NewFileLoader(logger), logger)
+	require.NoError(t, err)
+	defer validator.Close()
+
+	assert.NoError(t, validator.Validate(ctx, "COMMON123"))
+	assert.Equal(t, model.ErrInvalidPromoCode, validator.Validate(ctx, "VALIDCODE1"))
+	assert.Equal(t, model.ErrInvalidPromoCode, validator.Validate(ctx, "UNKNOWN99"))
+}
+
+func TestNewValidator_InvalidSetType(t *testing.T) {
+	config := &ValidatorConfig{
+		FilePaths:     []string{"coupon1.gz"},
+		MinMatchCount: 2,
+		SetType:       "trie",
+	}
+
+	validator, err := NewValidator(context.Background(), config, &mockLoader{}, zerolog.Nop())
+
+	require.Error(t, err)
+	assert.Nil(t, validator)
+	assert.Contains(t, err.Error(), "invalid coupon set type")
+}
+
+func TestNewValidator_InvalidCodePattern(t *testing.T) {
+	config := &ValidatorConfig{
+		FilePaths:     []string{"coupon1.gz"},
+		MinMatchCount: 2,
+		CodePattern:   "^[A-Z",
+	}
+
+	validator, err := NewValidator(context.Background(), config, &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			return NewMapCouponSet(1), nil
+		},
+	}, zerolog.Nop())
+
+	require.Error(t, err)
+	assert.Nil(t, validator)
+	assert.Contains(t, err.Error(), "invalid coupon code pattern")
+}
+
 func TestNewValidator_FileLoadError(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -66,6 +130,87 @@ func TestNewValidator_FileLoadError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to load coupon file")
 }
 
+func TestNewValidator_LoadInBackground(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	defer func(delay time.Duration) { minLoadRetryDelay = delay }(minLoadRetryDelay)
+	minLoadRetryDelay = time.Millisecond
+
+	// The first attempt fails, the second waits for release
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			if loads.Add(1) <= 3 {
+				return nil, errors.New("file unavailable")
+			}
+			<-release
+			set := NewMapCouponSet(10)
+			set.(*mapCouponSet).Add("HAPPYHRS")
+			return set, nil
+		},
+	}
+
+	config := &ValidatorConfig{
+		FilePaths:        []string{"a.gz", "b.gz", "c.gz"},
+		MinMatchCount:    2,
+		LoadInBackground: true,
+	}
+
+	validator, err := NewValidator(ctx, config, loader, logger)
+	require.NoError(t, err)
+	defer validator.Close()
+
+	assert.Equal(t, model.ErrCouponsLoading, validator.Validate(ctx, "HAPPYHRS"))
+	assert.Equal(t, model.ErrCodeCouponsLoading, validator.Check(ctx, "HAPPYHRS").Reason)
+	assert.Error(t, validator.Ready())
+	assert.True(t, validator.Status().Loading)
+
+	// Format errors need no coupon data
+	assert.Equal(t, model.ErrInvalidPromoLength, validator.Validate(ctx, "SHORT"))
+
+	require.Eventually(t, func() bool { return loads.Load() == 6 }, time.Second, time.Millisecond)
+	assert.ErrorContains(t, validator.Ready(), "file unavailable")
+	close(release)
+
+	require.Eventually(t, func() bool { return validator.Ready() == nil }, time.Second, time.Millisecond)
+	assert.NoError(t, validator.Validate(ctx, "HAPPYHRS"))
+	assert.False(t, validator.Status().Loading)
+	assert.Equal(t, 3, validator.Status().TotalCoupons)
+}
+
+func TestNewValidator_LoadInBackground_Close(t *testing.T) {
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	config := &ValidatorConfig{
+		FilePaths:        []string{"a.gz", "b.gz", "c.gz"},
+		MinMatchCount:    2,
+		LoadInBackground: true,
+	}
+
+	validator, err := NewValidator(context.Background(), config, loader, zerolog.Nop())
+	require.NoError(t, err)
+
+	// Close abandons the load rather than waiting for it
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, validator.Close())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the background load")
+	}
+}
+
 func TestValidator_Validate_ValidCode(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -178,6 +323,57 @@ func TestValidator_Validate_InvalidLength(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_InvalidFormat(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// The codes are present in every file, so only the format check can reject them
+	codes := []string{"CODE'--12", "PROMO 2025", "ÜBERCODE1", "CODE;DROP"}
+	file1 := createTestCouponFile(t, "coupon1.gz", codes)
+	file2 := createTestCouponFile(t, "coupon2.gz", codes)
+
+	config := &ValidatorConfig{
+		FilePaths:       []string{file1, file2},
+		MinMatchCount:   2,
+		ExpectedCoupons: len(codes),
+		CodePattern:     DefaultCodePattern,
+	}
+
+	ctx := context.Background()
+	validator, err := NewValidator(ctx, config, NewFileLoader(logger), logger)
+	require.NoError(t, err)
+	defer validator.Close()
+
+	for _, code := range codes {
+		t.Run(code, func(t *testing.T) {
+			assert.Equal(t, model.ErrInvalidPromoFormat, validator.Validate(ctx, code))
+		})
+	}
+
+	// Format is checked before length
+	assert.Equal(t, model.ErrInvalidPromoFormat, validator.Validate(ctx, "<script>alert(1)</script>"))
+	assert.Equal(t, model.ErrInvalidPromoLength, validator.Validate(ctx, "SHORT"))
+}
+
+func TestValidator_Validate_NoCodePattern(t *testing.T) {
+	logger := zerolog.Nop()
+
+	file1 := createTestCouponFile(t, "coupon1.gz", []string{"CODE-2025"})
+	file2 := createTestCouponFile(t, "coupon2.gz", []string{"CODE-2025"})
+
+	config := &ValidatorConfig{
+		FilePaths:       []string{file1, file2},
+		MinMatchCount:   2,
+		ExpectedCoupons: 1,
+	}
+
+	ctx := context.Background()
+	validator, err := NewValidator(ctx, config, NewFileLoader(logger), logger)
+	require.NoError(t, err)
+	defer validator.Close()
+
+	assert.NoError(t, validator.Validate(ctx, "CODE-2025"))
+}
+
 func TestValidator_Validate_ValidLength(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -334,6 +530,127 @@ func TestValidator_Validate_ExactlyTwoFiles(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestValidator_Validate_MatchWeights(t *testing.T) {
+	ctx := context.Background()
+
+	// PRIMARY01 is only in the primary file, BOTHBASE1 in two secondary
+	// files and EVERYFILE in all four
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			set := NewMapCouponSet(3).(*mapCouponSet)
+			set.Add("EVERYFILE")
+			switch filePath {
+			case "primary.gz":
+				set.Add("PRIMARY01")
+			case "coupon1.gz", "coupon2.gz":
+				set.Add("BOTHBASE1")
+			}
+			return set, nil
+		},
+	}
+
+	tests := []struct {
+		name          string
+		minMatchCount int
+		weights       map[string]int
+		valid         []string
+		invalid       []string
+	}{
+		{
+			name:    "Default of two files",
+			valid:   []string{"EVERYFILE", "BOTHBASE1"},
+			invalid: []string{"PRIMARY01"},
+		},
+		{
+			name:          "Any one file",
+			minMatchCount: 1,
+			valid:         []string{"EVERYFILE", "BOTHBASE1", "PRIMARY01"},
+		},
+		{
+			name:          "Three files",
+			minMatchCount: 3,
+			valid:         []string{"EVERYFILE"},
+			invalid:       []string{"BOTHBASE1", "PRIMARY01"},
+		},
+		{
+			name:          "Primary file counts double",
+			minMatchCount: 2,
+			weights:       map[string]int{"primary": 2},
+			valid:         []string{"EVERYFILE", "BOTHBASE1", "PRIMARY01"},
+		},
+		{
+			name:          "Primary file and one other",
+			minMatchCount: 3,
+			weights:       map[string]int{"primary": 2},
+			valid:         []string{"EVERYFILE"},
+			invalid:       []string{"BOTHBASE1", "PRIMARY01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := NewValidator(ctx, &ValidatorConfig{
+				FilePaths:     []string{"primary.gz", "coupon1.gz", "coupon2.gz", "coupon3.gz"},
+				MinMatchCount: tt.minMatchCount,
+				Weights:       tt.weights,
+			}, loader, zerolog.Nop())
+			require.NoError(t, err)
+			defer validator.Close()
+
+			for _, code := range tt.valid {
+				assert.NoError(t, validator.Validate(ctx, code), code)
+			}
+			for _, code := range tt.invalid {
+				assert.Equal(t, model.ErrInvalidPromoCode, validator.Validate(ctx, code), code)
+			}
+		})
+	}
+}
+
+func TestNewValidator_InvalidMatchWeights(t *testing.T) {
+	tests := []struct {
+		name          string
+		minMatchCount int
+		weights       map[string]int
+		expectedError string
+	}{
+		{
+			name:          "Negative min match count",
+			minMatchCount: -1,
+			expectedError: "invalid coupon min match count -1",
+		},
+		{
+			name:          "Min match count above total weight",
+			minMatchCount: 3,
+			expectedError: "coupon min match count 3 exceeds the total weight 2",
+		},
+		{
+			name:          "Weight for unknown file",
+			weights:       map[string]int{"coupon9": 2},
+			expectedError: `invalid coupon file weight for "coupon9"`,
+		},
+		{
+			name:          "Zero weight",
+			weights:       map[string]int{"coupon1": 0},
+			expectedError: `invalid coupon file weight 0 for "coupon1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := NewValidator(context.Background(), &ValidatorConfig{
+				FilePaths:     []string{"coupon1.gz", "coupon2.gz"},
+				MinMatchCount: tt.minMatchCount,
+				Weights:       tt.weights,
+			}, &mockLoader{}, zerolog.Nop())
+
+			require.Error(t, err)
+			assert.Nil(t, validator)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
 func TestValidator_Validate_CaseSensitive(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -363,6 +680,268 @@ func TestValidator_Validate_CaseSensitive(t *testing.T) {
 	assert.Equal(t, model.ErrInvalidPromoCode, err)
 }
 
+func TestValidator_Validate_CaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			set := newCouponSet(ctx)
+			set.Add("MixedCase1")
+			set.Add("RETIRED99")
+			return set, nil
+		},
+	}
+	deltas := &memDeltaSource{base: map[string]uint64{}, deltas: map[string][]Delta{}}
+	amountOff := model.NewMoney(5)
+	config := &ValidatorConfig{
+		FilePaths:       []string{"data/coupons/couponbase1.gz", "data/coupons/couponbase2.gz"},
+		MinMatchCount:   2,
+		ExpectedCoupons: 4,
+		CodePattern:     DefaultCodePattern,
+		Deltas:          deltas,
+		Metadata: mapMetadata{discounts: map[string]*model.CouponDiscount{
+			"MIXEDCASE1": {Code: "MIXEDCASE1", AmountOff: &amountOff},
+		}},
+		CaseInsensitive: true,
+	}
+
+	v, err := NewValidator(ctx, config, loader, zerolog.Nop())
+	require.NoError(t, err)
+	defer v.Close()
+
+	for _, code := range []string{"MIXEDCASE1", "mixedcase1", "MixedCase1", "retired99"} {
+		assert.NoError(t, v.Validate(ctx, code), code)
+	}
+	assert.Equal(t, "MIXEDCASE1", v.Normalize("mixedCase1"))
+
+	// Discounts are resolved by the normalized code
+	discount, err := v.ValidateAndResolve(ctx, "mixedcase1")
+	require.NoError(t, err)
+	require.NotNil(t, discount)
+	assert.Equal(t, "MIXEDCASE1", discount.Code)
+
+	// Delta codes are normalized too
+	for _, name := range []string{"couponbase1", "couponbase2"} {
+		deltas.publish(name, Delta{Sequence: 1, Added: []string{"deltacode1"}, Removed: []string{"Retired99"}})
+	}
+	require.NoError(t, v.ApplyDeltas(ctx))
+
+	assert.NoError(t, v.Validate(ctx, "DeltaCode1"))
+	assert.Equal(t, model.ErrInvalidPromoCode, v.Validate(ctx, "RETIRED99"))
+}
+
+// slowCouponSet contains every code but takes delay to answer.
+type slowCouponSet struct {
+	delay time.Duration
+}
+
+func (s slowCouponSet) Contains(code string) bool {
+	time.Sleep(s.delay)
+	return true
+}
+
+func (s slowCouponSet) Size() int { return 1 }
+
+func TestValidator_Validate_Timeout(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			return slowCouponSet{delay: 200 * time.Millisecond}, nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		failOpen bool
+		want     error
+	}{
+		{
+			name: "Fails closed by default",
+			want: model.ErrCouponValidationTimeout,
+		},
+		{
+			name:     "Accepts the code when failing open",
+			failOpen: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ValidatorConfig{
+				FilePaths:     []string{"coupon1.gz", "coupon2.gz", "coupon3.gz"},
+				MinMatchCount: 2,
+				Timeout:       10 * time.Millisecond,
+				FailOpen:      tt.failOpen,
+			}
+
+			validator, err := NewValidator(ctx, config, loader, logger)
+			require.NoError(t, err)
+			defer validator.Close()
+
+			start := time.Now()
+			err = validator.Validate(ctx, "SLOWCODE1")
+			assert.Equal(t, tt.want, err)
+			assert.Less(t, time.Since(start), 200*time.Millisecond)
+		})
+	}
+
+	t.Run("Caller cancellation is not a timeout", func(t *testing.T) {
+		config := &ValidatorConfig{
+			FilePaths:     []string{"coupon1.gz", "coupon2.gz", "coupon3.gz"},
+			MinMatchCount: 2,
+			Timeout:       time.Second,
+		}
+
+		validator, err := NewValidator(ctx, config, loader, logger)
+		require.NoError(t, err)
+		defer validator.Close()
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.Equal(t, model.ErrInvalidPromoCode, validator.Validate(cancelled, "SLOWCODE1"))
+	})
+}
+
+// mapMetadata serves coupon discounts from a map.
+type mapMetadata struct {
+	discounts map[string]*model.CouponDiscount
+	err       error
+}
+
+func (m mapMetadata) GetByCode(ctx context.Context, code string) (*model.CouponDiscount, error) {
+	return m.discounts[code], m.err
+}
+
+func TestValidator_ValidateAndResolve(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			set := NewMapCouponSet(3).(*mapCouponSet)
+			for _, code := range []string{"TENOFF123", "EXPIRED12", "NODISCOUNT"} {
+				set.Add(code)
+			}
+			return set, nil
+		},
+	}
+
+	amountOff := model.NewMoney(10)
+	expired := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	metadata := mapMetadata{discounts: map[string]*model.CouponDiscount{
+		"TENOFF123": {Code: "TENOFF123", AmountOff: &amountOff, ExpiresAt: &later, MinSubtotal: model.NewMoney(50)},
+		"EXPIRED12": {Code: "EXPIRED12", AmountOff: &amountOff, ExpiresAt: &expired},
+	}}
+
+	newValidator := func(t *testing.T, metadata MetadataSource) Validator {
+		validator, err := NewValidator(ctx, &ValidatorConfig{
+			FilePaths:     []string{"coupon1.gz", "coupon2.gz"},
+			MinMatchCount: 2,
+			Metadata:      metadata,
+		}, loader, logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { validator.Close() })
+		return validator
+	}
+
+	t.Run("Resolves discount", func(t *testing.T) {
+		discount, err := newValidator(t, metadata).ValidateAndResolve(ctx, "TENOFF123")
+		require.NoError(t, err)
+		assert.Equal(t, metadata.discounts["TENOFF123"], discount)
+	})
+
+	t.Run("Code without a discount", func(t *testing.T) {
+		discount, err := newValidator(t, metadata).ValidateAndResolve(ctx, "NODISCOUNT")
+		require.NoError(t, err)
+		assert.Nil(t, discount)
+	})
+
+	t.Run("Expired code", func(t *testing.T) {
+		_, err := newValidator(t, metadata).ValidateAndResolve(ctx, "EXPIRED12")
+		assert.Equal(t, model.ErrCouponExpired, err)
+	})
+
+	t.Run("Invalid code is not resolved", func(t *testing.T) {
+		_, err := newValidator(t, mapMetadata{err: errors.New("unexpected lookup")}).ValidateAndResolve(ctx, "UNKNOWN12")
+		assert.Equal(t, model.ErrInvalidPromoCode, err)
+	})
+
+	t.Run("Metadata lookup fails", func(t *testing.T) {
+		_, err := newValidator(t, mapMetadata{err: errors.New("connection reset")}).ValidateAndResolve(ctx, "TENOFF123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset")
+	})
+
+	t.Run("No metadata source", func(t *testing.T) {
+		discount, err := newValidator(t, nil).ValidateAndResolve(ctx, "TENOFF123")
+		require.NoError(t, err)
+		assert.Nil(t, discount)
+	})
+
+	t.Run("Resolve skips the coupon files", func(t *testing.T) {
+		unlisted := mapMetadata{discounts: map[string]*model.CouponDiscount{
+			"UNLISTED1": {Code: "UNLISTED1", AmountOff: &amountOff},
+			"EXPIRED12": metadata.discounts["EXPIRED12"],
+		}}
+		validator := newValidator(t, unlisted)
+
+		discount, err := validator.Resolve(ctx, "UNLISTED1")
+		require.NoError(t, err)
+		assert.Equal(t, unlisted.discounts["UNLISTED1"], discount)
+
+		_, err = validator.Resolve(ctx, "EXPIRED12")
+		assert.Equal(t, model.ErrCouponExpired, err)
+	})
+}
+
+func TestValidator_Check(t *testing.T) {
+	ctx := context.Background()
+
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			set := NewMapCouponSet(2).(*mapCouponSet)
+			set.Add("BOTHFILES")
+			if filePath == "coupon1.gz" {
+				set.Add("ONEFILE12")
+			}
+			return set, nil
+		},
+	}
+
+	validator, err := NewValidator(ctx, &ValidatorConfig{
+		FilePaths:     []string{"coupon1.gz", "coupon2.gz", "coupon3.gz"},
+		MinMatchCount: 2,
+		CodePattern:   DefaultCodePattern,
+	}, loader, zerolog.Nop())
+	require.NoError(t, err)
+	defer validator.Close()
+
+	tests := []struct {
+		code     string
+		expected model.CouponValidation
+	}{
+		{"BOTHFILES", model.CouponValidation{Code: "BOTHFILES", Valid: true, MatchCount: 2}},
+		{"SHORT", model.CouponValidation{Code: "SHORT", Reason: model.ErrCodeInvalidPromoLength}},
+		{"BAD-CODE1", model.CouponValidation{Code: "BAD-CODE1", Reason: model.ErrCodeInvalidPromoFormat}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validator.Check(ctx, tt.code))
+		})
+	}
+
+	// The lookup may stop before the one matching file reports
+	result := validator.Check(ctx, "ONEFILE12")
+	assert.False(t, result.Valid)
+	assert.Equal(t, model.ErrCodeInvalidPromoCode, result.Reason)
+	assert.LessOrEqual(t, result.MatchCount, 1)
+}
+
 func TestValidator_Close(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -384,3 +963,147 @@ func TestValidator_Close(t *testing.T) {
 	err = validator.Close()
 	assert.NoError(t, err)
 }
+
+func TestValidator_Reload(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	generation := 0
+	failReload := false
+	loader := &mockLoader{
+		loadFunc: func(ctx context.Context, filePath string) (CouponSet, error) {
+			if failReload {
+				return nil, errors.New("file unavailable")
+			}
+			set := NewMapCouponSet(10)
+			if generation == 0 {
+				set.(*mapCouponSet).Add("OLDCODE123")
+			} else {
+				set.(*mapCouponSet).Add("NEWCODE123")
+			}
+			return set, nil
+		},
+	}
+
+	config := &ValidatorConfig{
+		FilePaths:     []string{"a.gz", "b.gz", "c.gz"},
+		MinMatchCount: 2,
+	}
+
+	validator, err := NewValidator(ctx, config, loader, logger)
+	require.NoError(t, err)
+	assert.NoError(t, validator.Validate(ctx, "OLDCODE123"))
+
+	// Successful reload swaps in the new coupon sets
+	generation = 1
+	require.NoError(t, validator.Reload(ctx))
+	assert.NoError(t, validator.Validate(ctx, "NEWCODE123"))
+	assert.Equal(t, model.ErrInvalidPromoCode, validator.Validate(ctx, "OLDCODE123"))
+
+	// Failed reload keeps serving the previous sets
+	failReload = true
+	assert.Error(t, validator.Reload(ctx))
+	assert.NoError(t, validator.Validate(ctx, "NEWCODE123"))
+}
+
+type recordedGenerations struct {
+	generations []model.CouponGeneration
+	retired     map[int64]time.Time
+	err         error
+}
+
+func (r *recordedGenerations) RecordGeneration(ctx context.Context, generation *model.CouponGeneration) error {
+	if r.err != nil {
+		return r.err
+	}
+	generation.ID = int64(len(r.generations) + 1)
+	r.generations = append(r.generations, *generation)
+	return nil
+}
+
+func (r *recordedGenerations) RetireGeneration(ctx context.Context, id int64, retiredAt time.Time) error {
+	if r.retired == nil {
+		r.retired = make(map[int64]time.Time)
+	}
+	r.retired[id] = retiredAt
+	return nil
+}
+
+func TestValidator_RecordsGenerations(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	file1 := createTestCouponFile(t, "coupon1.gz", []string{"CODE0001", "CODE0002"})
+	file2 := createTestCouponFile(t, "coupon2.gz", []string{"CODE0001"})
+	file3 := createTestCouponFile(t, "coupon3.gz", []string{"CODE0002"})
+
+	recorder := &recordedGenerations{}
+	config := &ValidatorConfig{
+		FilePaths:       []string{file1, file2, file3},
+		MinMatchCount:   2,
+		ExpectedCoupons: 10,
+		Generations:     recorder,
+		Instance:        "api-1",
+	}
+
+	validator, err := NewValidator(ctx, config, NewFileLoader(logger), logger)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.generations, 1)
+	first := recorder.generations[0]
+	assert.Equal(t, "api-1", first.Instance)
+	require.Len(t, first.Files, 3)
+	assert.Equal(t, "coupon1", first.Files[0].Name)
+	assert.Equal(t, file1, first.Files[0].Path)
+	assert.Equal(t, 2, first.Files[0].Codes)
+	assert.Equal(t, sha256File(t, file1), first.Files[0].Checksum, "checksum should cover the file as stored")
+	assert.Equal(t, sha256File(t, file3), first.Files[2].Checksum)
+
+	status := validator.Status()
+	assert.Equal(t, int64(1), status.Generation)
+	assert.Equal(t, first.Files[1].Checksum, status.Sets[1].Checksum)
+
+	// Each reload is a new generation
+	require.NoError(t, validator.Reload(ctx))
+	require.Len(t, recorder.generations, 2)
+	assert.Equal(t, int64(2), validator.Status().Generation)
+
+	// Closing retires the generation being served
+	require.NoError(t, validator.Close())
+	assert.Contains(t, recorder.retired, int64(2))
+	assert.NotContains(t, recorder.retired, int64(1))
+}
+
+func TestValidator_RecordGenerationFailure(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	file1 := createTestCouponFile(t, "coupon1.gz", []string{"CODE0001"})
+	file2 := createTestCouponFile(t, "coupon2.gz", []string{"CODE0001"})
+
+	recorder := &recordedGenerations{err: errors.New("database unavailable")}
+	config := &ValidatorConfig{
+		FilePaths:       []string{file1, file2},
+		MinMatchCount:   2,
+		ExpectedCoupons: 10,
+		Generations:     recorder,
+	}
+
+	// Loads succeed without being recorded
+	validator, err := NewValidator(ctx, config, NewFileLoader(logger), logger)
+	require.NoError(t, err)
+	assert.NoError(t, validator.Validate(ctx, "CODE0001"))
+	assert.Zero(t, validator.Status().Generation)
+
+	require.NoError(t, validator.Close())
+	assert.Empty(t, recorder.retired)
+}
+
+// sha256File returns the hex-encoded SHA-256 of the file at path.
+func sha256File(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
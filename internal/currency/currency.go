@@ -0,0 +1,113 @@
+// Package currency fetches the exchange rates catalogue prices are converted
+// into other currencies with.
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+
+	"github.com/rs/zerolog"
+)
+
+// maxRatesBytes caps the size of a rates response.
+const maxRatesBytes = 1 << 20
+
+// RateSource provides exchange rates.
+type RateSource interface {
+	// Rates returns how much of each currency one unit of base buys, keyed
+	// by ISO 4217 code.
+	Rates(ctx context.Context, base string) (map[string]float64, error)
+}
+
+// staticRates implements RateSource with fixed rates.
+type staticRates struct {
+	rates map[string]float64
+}
+
+// NewStaticRates creates a source returning the given rates, which are
+// relative to the catalogue currency, whatever base is asked for.
+func NewStaticRates(rates map[string]float64) RateSource {
+	return &staticRates{rates: maps.Clone(rates)}
+}
+
+// Rates returns a copy of the configured rates.
+func (s *staticRates) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	return maps.Clone(s.rates), nil
+}
+
+// httpRates implements RateSource by fetching rates from an HTTP endpoint.
+type httpRates struct {
+	url    string
+	client *http.Client
+	logger zerolog.Logger
+}
+
+// NewHTTPRates creates a source fetching rates from endpoint, which is sent
+// the base currency as the base query parameter and must respond with a JSON
+// object such as {"base":"AUD","rates":{"USD":0.66,"EUR":0.61}}.
+func NewHTTPRates(endpoint string, client *http.Client, logger zerolog.Logger) RateSource {
+	return &httpRates{
+		url:    endpoint,
+		client: client,
+		logger: logger.With().Str("component", "exchange_rates").Logger(),
+	}
+}
+
+// ratesResponse is the body of a rates response.
+type ratesResponse struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Rates fetches the rates for base.
+func (s *httpRates) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	endpoint, err := url.Parse(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rates URL: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("base", base)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rates request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRatesBytes))
+		return nil, fmt.Errorf("exchange rates request failed with status %d", resp.StatusCode)
+	}
+
+	var body ratesResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRatesBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+	if body.Base != base {
+		return nil, fmt.Errorf("exchange rates are for %q, not %q", body.Base, base)
+	}
+
+	// A rate that is not positive would zero out or flip prices
+	for code, rate := range body.Rates {
+		if rate <= 0 {
+			s.logger.Warn().Str("currency", code).Float64("rate", rate).Msg("ignoring invalid exchange rate")
+			delete(body.Rates, code)
+		}
+	}
+
+	s.logger.Debug().Str("base", base).Int("currencies", len(body.Rates)).Msg("fetched exchange rates")
+
+	return body.Rates, nil
+}
@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticRates(t *testing.T) {
+	configured := map[string]float64{"USD": 0.66}
+	source := NewStaticRates(configured)
+
+	rates, err := source.Rates(context.Background(), "AUD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 0.66}, rates)
+
+	// Neither the caller's map nor the returned one alias the source's rates
+	configured["USD"] = 1
+	rates["EUR"] = 0.61
+	rates, err = source.Rates(context.Background(), "AUD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 0.66}, rates)
+}
+
+func TestHTTPRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		expectedRates map[string]float64
+		expectedErr   string
+	}{
+		{
+			name:          "Fetches rates",
+			status:        http.StatusOK,
+			body:          `{"base":"AUD","rates":{"USD":0.66,"EUR":0.61}}`,
+			expectedRates: map[string]float64{"USD": 0.66, "EUR": 0.61},
+		},
+		{
+			name:          "Drops invalid rates",
+			status:        http.StatusOK,
+			body:          `{"base":"AUD","rates":{"USD":0.66,"EUR":0,"GBP":-1}}`,
+			expectedRates: map[string]float64{"USD": 0.66},
+		},
+		{
+			name:        "Rates for another base",
+			status:      http.StatusOK,
+			body:        `{"base":"USD","rates":{"AUD":1.52}}`,
+			expectedErr: `exchange rates are for "USD", not "AUD"`,
+		},
+		{
+			name:        "Error status",
+			status:      http.StatusBadGateway,
+			body:        `upstream unavailable`,
+			expectedErr: "exchange rates request failed with status 502",
+		},
+		{
+			name:        "Malformed body",
+			status:      http.StatusOK,
+			body:        `{"base":`,
+			expectedErr: "failed to decode exchange rates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "AUD", r.URL.Query().Get("base"))
+				assert.Equal(t, "v1", r.URL.Query().Get("version"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			source := NewHTTPRates(server.URL+"/rates?version=v1", server.Client(), zerolog.Nop())
+			rates, err := source.Rates(context.Background(), "AUD")
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Nil(t, rates)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedRates, rates)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"mini-kart/internal/config"
@@ -11,7 +13,16 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// NewPool creates a new PostgreSQL connection pool.
+// Delays between attempts to reach the database at startup. The delay
+// doubles after each failed attempt, up to maxStartupRetryDelay.
+const (
+	startupRetryDelay    = 500 * time.Millisecond
+	maxStartupRetryDelay = 5 * time.Second
+)
+
+// NewPool creates a new PostgreSQL connection pool. When the database is not
+// reachable yet, e.g. while its container is still starting, connecting is
+// retried with backoff for up to the configured startup timeout.
 func NewPool(ctx context.Context, cfg config.DatabaseConfig, logger zerolog.Logger) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(cfg.ConnectionString())
 	if err != nil {
@@ -39,12 +50,79 @@ func NewPool(ctx context.Context, cfg config.DatabaseConfig, logger zerolog.Logg
 	}
 
 	// Verify connection
-	if err := pool.Ping(ctx); err != nil {
+	if err := ping(ctx, pool, time.Duration(cfg.StartupTimeout)*time.Second, logger); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
+	}
+
+	if cfg.Warmup {
+		start := time.Now()
+		if err := warmUp(ctx, pool, cfg.MinConnections); err != nil {
+			// The pool still opens connections on demand
+			logger.Warn().Err(err).Msg("failed to warm up database connection pool")
+		} else {
+			logger.Info().
+				Int("connections", cfg.MinConnections).
+				Dur("duration", time.Since(start)).
+				Msg("database connection pool warmed up")
+		}
 	}
 
 	logger.Info().Msg("database connection pool created successfully")
 
 	return pool, nil
 }
+
+// ping checks the database is reachable, retrying with backoff until timeout
+// has passed. A zero timeout fails on the first unsuccessful attempt.
+func ping(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration, logger zerolog.Logger) error {
+	deadline := time.Now().Add(timeout)
+	delay := startupRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("failed to ping database after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn().
+			Err(err).
+			Int("attempt", attempt).
+			Dur("retry_in", delay).
+			Msg("database not reachable yet, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to ping database: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxStartupRetryDelay)
+	}
+}
+
+// warmUp opens n connections up front, so the first requests after startup
+// don't wait for connection and TLS handshakes.
+func warmUp(ctx context.Context, pool *pgxpool.Pool, n int) error {
+	// Hold every connection until all are acquired, so each is a distinct one
+	conns := make([]*pgxpool.Conn, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			conns[i], errs[i] = pool.Acquire(ctx)
+		})
+	}
+	wg.Wait()
+
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Release()
+		}
+	}
+
+	return errors.Join(errs...)
+}
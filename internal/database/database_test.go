@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"mini-kart/internal/config"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unusedPort returns a local port nothing is listening on.
+func unusedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
+func TestNewPool_RetriesUntilStartupTimeout(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Host:           "127.0.0.1",
+		Port:           unusedPort(t),
+		User:           "postgres",
+		Database:       "minikart",
+		MaxConnections: 2,
+		MinConnections: 1,
+		SSLMode:        "disable",
+		ConnectTimeout: 1,
+	}
+
+	start := time.Now()
+	_, err := NewPool(context.Background(), cfg, zerolog.Nop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to ping database after 1 attempts")
+
+	cfg.StartupTimeout = 1
+	_, err = NewPool(context.Background(), cfg, zerolog.Nop())
+	require.Error(t, err)
+	// Attempts at 0s and 0.5s; the next at 1.5s is past the timeout
+	assert.Contains(t, err.Error(), "failed to ping database after 2 attempts")
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestNewPool_StopsRetryingWhenCancelled(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Host:           "127.0.0.1",
+		Port:           unusedPort(t),
+		User:           "postgres",
+		Database:       "minikart",
+		MaxConnections: 2,
+		MinConnections: 1,
+		SSLMode:        "disable",
+		StartupTimeout: 60,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := NewPool(ctx, cfg, zerolog.Nop())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,71 @@
+// Package events publishes domain events to Kafka for the analytics pipeline.
+// Events are appended to the event outbox in the transaction of the change
+// they describe and published by a background relay, so an event is
+// published if and only if its change commits.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// Aggregate types events are published for.
+const (
+	AggregateOrder = "order"
+)
+
+// Order event types.
+const (
+	TypeOrderCreated       = "order.created"
+	TypeOrderStatusChanged = "order.status_changed"
+)
+
+// Envelope is the JSON value of every published event. ID is unique per
+// event, so consumers can discard events delivered more than once.
+type Envelope struct {
+	ID            uuid.UUID `json:"id"`
+	Type          string    `json:"type"`
+	AggregateType string    `json:"aggregateType"`
+	AggregateID   uuid.UUID `json:"aggregateId"`
+	OccurredAt    time.Time `json:"occurredAt"`
+	Data          any       `json:"data"`
+}
+
+// OrderStatusChanged is the data of an order.status_changed event.
+type OrderStatusChanged struct {
+	OrderID        uuid.UUID         `json:"orderId"`
+	Status         model.OrderStatus `json:"status"`
+	PreviousStatus model.OrderStatus `json:"previousStatus"`
+}
+
+// New builds an outbox event of the given type about an aggregate, with data
+// encoded in its envelope.
+func New(eventType, aggregateType string, aggregateID uuid.UUID, data any) (model.OutboxEvent, error) {
+	envelope := Envelope{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    time.Now().UTC(),
+		Data:          data,
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return model.OutboxEvent{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return model.OutboxEvent{
+		ID:            envelope.ID,
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       payload,
+		CreatedAt:     envelope.OccurredAt,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	orderID := uuid.New()
+	data := OrderStatusChanged{OrderID: orderID, Status: model.OrderStatusCancelled, PreviousStatus: model.OrderStatusPending}
+
+	event, err := New(TypeOrderStatusChanged, AggregateOrder, orderID, data)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, event.ID)
+	assert.Equal(t, TypeOrderStatusChanged, event.Type)
+	assert.Equal(t, AggregateOrder, event.AggregateType)
+	assert.Equal(t, orderID, event.AggregateID)
+	assert.False(t, event.CreatedAt.IsZero())
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(event.Payload, &envelope))
+	assert.Equal(t, map[string]any{
+		"id":            event.ID.String(),
+		"type":          "order.status_changed",
+		"aggregateType": "order",
+		"aggregateId":   orderID.String(),
+		"occurredAt":    event.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
+		"data": map[string]any{
+			"orderId":        orderID.String(),
+			"status":         "cancelled",
+			"previousStatus": "pending",
+		},
+	}, envelope)
+}
+
+func TestNew_UnencodableData(t *testing.T) {
+	_, err := New(TypeOrderCreated, AggregateOrder, uuid.New(), make(chan int))
+	assert.ErrorContains(t, err, "failed to encode order.created event")
+}
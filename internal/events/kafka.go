@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka message headers set on every published event.
+const (
+	HeaderEventID   = "event-id"
+	HeaderEventType = "event-type"
+)
+
+// Publisher delivers outbox events to a message broker.
+type Publisher interface {
+	// Publish delivers events in order, returning once the broker has
+	// acknowledged all of them.
+	Publish(ctx context.Context, events []model.OutboxEvent) error
+
+	// Close flushes and releases the publisher's connections.
+	Close() error
+}
+
+// KafkaConfig holds Kafka publisher configuration.
+type KafkaConfig struct {
+	// Brokers are the bootstrap broker addresses, e.g. "b-1.msk:9094".
+	Brokers []string
+
+	// Topic receives every event.
+	Topic string
+
+	// TLS connects to the brokers over TLS.
+	TLS bool
+}
+
+// kafkaPublisher implements Publisher using a Kafka producer.
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher writing events to a Kafka topic.
+// Events are keyed by aggregate ID, so the events of one order land on the
+// same partition and are consumed in order.
+func NewKafkaPublisher(config KafkaConfig) Publisher {
+	transport := &kafka.Transport{}
+	if config.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(config.Brokers...),
+			Topic:        config.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// The relay publishes whole batches at once; don't wait for more
+			BatchTimeout: 10 * time.Millisecond,
+			Transport:    transport,
+		},
+	}
+}
+
+// Publish writes events to the topic and waits for every in-sync replica to
+// acknowledge them.
+func (p *kafkaPublisher) Publish(ctx context.Context, events []model.OutboxEvent) error {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		messages[i] = kafka.Message{
+			Key:   []byte(event.AggregateID.String()),
+			Value: event.Payload,
+			Time:  event.CreatedAt,
+			Headers: []kafka.Header{
+				{Key: HeaderEventID, Value: []byte(event.ID.String())},
+				{Key: HeaderEventType, Value: []byte(event.Type)},
+			},
+		}
+	}
+
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to publish events to kafka: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes pending writes and closes the producer.
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// RelayConfig holds event relay configuration.
+type RelayConfig struct {
+	// BatchSize is the number of events published at once.
+	BatchSize int
+
+	// Interval is how often the outbox is checked for unpublished events.
+	Interval time.Duration
+
+	// Timeout bounds publishing a batch.
+	Timeout time.Duration
+}
+
+// Relay publishes events from the outbox in the order they were appended.
+// Several relays may share an outbox; only one publishes at a time. A batch
+// that fails to publish is retried on the next check, so events are published
+// at least once.
+type Relay struct {
+	outbox    repository.EventOutboxRepository
+	publisher Publisher
+	config    RelayConfig
+	logger    zerolog.Logger
+}
+
+// NewRelay creates an event relay.
+func NewRelay(outbox repository.EventOutboxRepository, publisher Publisher, config RelayConfig, logger zerolog.Logger) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		config:    config,
+		logger:    logger.With().Str("component", "event-relay").Logger(),
+	}
+}
+
+// Run publishes outbox events every interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes batches until the outbox has no more unpublished events.
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.RelayPending(ctx)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to relay events")
+			return
+		}
+		if published < r.config.BatchSize {
+			return
+		}
+	}
+}
+
+// RelayPending publishes one batch of unpublished events, returning how many
+// were published.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published, err := r.outbox.PublishPending(ctx, r.config.BatchSize, func(ctx context.Context, events []model.OutboxEvent) error {
+		ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+		return r.publisher.Publish(ctx, events)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if published > 0 {
+		r.logger.Debug().Int("events", published).Msg("events published")
+	}
+
+	return published, nil
+}
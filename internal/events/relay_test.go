@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutbox is an in-memory event outbox.
+type fakeOutbox struct {
+	pending   []model.OutboxEvent
+	published []model.OutboxEvent
+}
+
+func (o *fakeOutbox) Append(ctx context.Context, tx pgx.Tx, events []model.OutboxEvent) error {
+	o.pending = append(o.pending, events...)
+	return nil
+}
+
+func (o *fakeOutbox) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error) {
+	batch := o.pending[:min(limit, len(o.pending))]
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := publish(ctx, batch); err != nil {
+		return 0, err
+	}
+	o.published = append(o.published, batch...)
+	o.pending = o.pending[len(batch):]
+	return len(batch), nil
+}
+
+// fakePublisher records published batches, failing while err is set.
+type fakePublisher struct {
+	batches  [][]model.OutboxEvent
+	deadline bool
+	err      error
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, events []model.OutboxEvent) error {
+	_, p.deadline = ctx.Deadline()
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, events)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+func testEvents(n int) []model.OutboxEvent {
+	events := make([]model.OutboxEvent, n)
+	for i := range events {
+		events[i] = model.OutboxEvent{ID: uuid.New(), Type: TypeOrderCreated, AggregateType: AggregateOrder, AggregateID: uuid.New()}
+	}
+	return events
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	pending := testEvents(3)
+	outbox := &fakeOutbox{pending: pending}
+	publisher := &fakePublisher{}
+	relay := NewRelay(outbox, publisher, RelayConfig{BatchSize: 2, Interval: time.Second, Timeout: time.Second}, zerolog.Nop())
+
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.True(t, publisher.deadline)
+
+	published, err = relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	assert.Equal(t, [][]model.OutboxEvent{pending[:2], pending[2:]}, publisher.batches)
+	assert.Equal(t, pending, outbox.published)
+}
+
+func TestRelay_RelayPending_PublishFails(t *testing.T) {
+	outbox := &fakeOutbox{pending: testEvents(2)}
+	publisher := &fakePublisher{err: errors.New("broker unavailable")}
+	relay := NewRelay(outbox, publisher, RelayConfig{BatchSize: 10, Interval: time.Second, Timeout: time.Second}, zerolog.Nop())
+
+	_, err := relay.RelayPending(context.Background())
+	assert.EqualError(t, err, "broker unavailable")
+
+	// Events stay in the outbox for the next attempt
+	assert.Len(t, outbox.pending, 2)
+	assert.Empty(t, outbox.published)
+}
+
+func TestRelay_Drain(t *testing.T) {
+	outbox := &fakeOutbox{pending: testEvents(5)}
+	publisher := &fakePublisher{}
+	relay := NewRelay(outbox, publisher, RelayConfig{BatchSize: 2, Interval: time.Hour, Timeout: time.Second}, zerolog.Nop())
+
+	relay.drain(context.Background())
+
+	assert.Len(t, publisher.batches, 3)
+	assert.Empty(t, outbox.pending)
+}
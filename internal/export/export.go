@@ -0,0 +1,299 @@
+// Package export writes incremental order extracts to S3 as Parquet files for
+// the data warehouse, so analytics no longer query the production database.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+)
+
+// watermarkName identifies the order export in the export_watermarks table.
+const watermarkName = "orders"
+
+// partitionLayout formats the update date partition of an export file.
+const partitionLayout = "2006-01-02"
+
+// runLayout formats the run's upper bound into export file names.
+const runLayout = "20060102T150405Z"
+
+// Uploader stores finished export files.
+type Uploader interface {
+	// Upload writes body to key, replacing any existing object.
+	Upload(ctx context.Context, key string, body io.ReadSeeker) error
+}
+
+// Config holds order export configuration.
+type Config struct {
+	// Prefix is prepended to every file key.
+	Prefix string
+
+	// BatchSize is the number of orders read per query.
+	BatchSize int
+
+	// SafetyLag leaves orders updated this recently for the next run.
+	SafetyLag time.Duration
+}
+
+// Result summarises an export run.
+type Result struct {
+	// From and To bound the update times exported, To exclusive.
+	From time.Time
+	To   time.Time
+
+	// Orders is the number of orders written.
+	Orders int
+
+	// Files lists the keys written, one per update date.
+	Files []string
+}
+
+// Exporter extracts orders changed since the last run and uploads them as
+// Parquet files partitioned by update date.
+type Exporter struct {
+	repo     repository.OrderExportRepository
+	uploader Uploader
+	config   Config
+	logger   zerolog.Logger
+	now      func() time.Time
+}
+
+// NewExporter creates an order exporter.
+func NewExporter(repo repository.OrderExportRepository, uploader Uploader, config Config, logger zerolog.Logger) *Exporter {
+	return &Exporter{
+		repo:     repo,
+		uploader: uploader,
+		config:   config,
+		logger:   logger.With().Str("component", "order-export").Logger(),
+		now:      time.Now,
+	}
+}
+
+// Run exports orders updated since the watermark and advances it once every
+// file is uploaded. The first run exports every order. A failed run leaves
+// the watermark unchanged, so the next run repeats it; the warehouse keeps
+// the row with the latest updated_at per order ID.
+func (e *Exporter) Run(ctx context.Context) (*Result, error) {
+	watermark, err := e.repo.GetWatermark(ctx, watermarkName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		To: e.now().Add(-e.config.SafetyLag).UTC().Truncate(time.Microsecond),
+	}
+	if watermark != nil {
+		result.From = watermark.UTC()
+	}
+
+	if !result.To.After(result.From) {
+		e.logger.Info().Time("watermark", result.From).Msg("no new orders to export")
+		return result, nil
+	}
+
+	var part *partition
+	defer func() {
+		if part != nil {
+			part.discard()
+		}
+	}()
+
+	cursor := model.ExportCursor{UpdatedAt: result.From}
+	for {
+		orders, err := e.repo.ListUpdated(ctx, cursor, result.To, e.config.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			date := order.UpdatedAt.UTC().Format(partitionLayout)
+			if part != nil && part.date != date {
+				if err := e.upload(ctx, part, result); err != nil {
+					return nil, err
+				}
+				part = nil
+			}
+			if part == nil {
+				if part, err = newPartition(date); err != nil {
+					return nil, err
+				}
+			}
+			if err := part.write(order); err != nil {
+				return nil, err
+			}
+			result.Orders++
+		}
+
+		if len(orders) < e.config.BatchSize {
+			break
+		}
+		last := orders[len(orders)-1]
+		cursor = model.ExportCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+
+	if part != nil {
+		if err := e.upload(ctx, part, result); err != nil {
+			return nil, err
+		}
+		part = nil
+	}
+
+	if err := e.repo.SetWatermark(ctx, watermarkName, result.To); err != nil {
+		return nil, err
+	}
+
+	e.logger.Info().
+		Time("from", result.From).
+		Time("to", result.To).
+		Int("orders", result.Orders).
+		Int("files", len(result.Files)).
+		Msg("order export completed")
+
+	return result, nil
+}
+
+// upload finishes a partition file, uploads it and removes the local copy.
+func (e *Exporter) upload(ctx context.Context, part *partition, result *Result) error {
+	defer part.discard()
+
+	if err := part.close(); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%supdated_date=%s/orders-%s.parquet", e.config.Prefix, part.date, result.To.Format(runLayout))
+	if err := e.uploader.Upload(ctx, key, part.file); err != nil {
+		e.logger.Error().Err(err).Str("key", key).Msg("failed to upload export file")
+		return fmt.Errorf("failed to upload export file %s: %w", key, err)
+	}
+
+	e.logger.Debug().Str("key", key).Int("orders", part.rows).Msg("export file uploaded")
+	result.Files = append(result.Files, key)
+	return nil
+}
+
+// orderRow is the Parquet schema of an exported order. Items are nested, so
+// each row is a complete order.
+type orderRow struct {
+	ID         string    `parquet:"id"`
+	CouponCode *string   `parquet:"coupon_code,optional"`
+	Source     *string   `parquet:"source,optional"`
+	Status     string    `parquet:"status"`
+	Subtotal   *float64  `parquet:"subtotal,optional"`
+	Discount   *float64  `parquet:"discount,optional"`
+	Total      *float64  `parquet:"total,optional"`
+	CreatedAt  time.Time `parquet:"created_at,timestamp(microsecond)"`
+	UpdatedAt  time.Time `parquet:"updated_at,timestamp(microsecond)"`
+	Items      []itemRow `parquet:"items,list"`
+}
+
+// itemRow is the Parquet schema of an exported order item. Product fields
+// come from the snapshot taken when the order was placed.
+type itemRow struct {
+	ID                string   `parquet:"id"`
+	ProductID         string   `parquet:"product_id"`
+	Quantity          int32    `parquet:"quantity"`
+	FulfilledQuantity int32    `parquet:"fulfilled_quantity"`
+	ProductName       *string  `parquet:"product_name,optional"`
+	ProductCategory   *string  `parquet:"product_category,optional"`
+	UnitPrice         *float64 `parquet:"unit_price,optional"`
+}
+
+// newOrderRow converts an exported order to its Parquet row.
+func newOrderRow(order model.ExportedOrder) orderRow {
+	row := orderRow{
+		ID:         order.ID.String(),
+		CouponCode: order.CouponCode,
+		Source:     order.Source,
+		Status:     string(order.Status),
+		Subtotal:   amount(order.Subtotal),
+		Discount:   amount(order.Discount),
+		Total:      amount(order.Total),
+		CreatedAt:  order.CreatedAt.UTC(),
+		UpdatedAt:  order.UpdatedAt.UTC(),
+		Items:      make([]itemRow, 0, len(order.Items)),
+	}
+
+	for _, item := range order.Items {
+		itemRow := itemRow{
+			ID:                item.ID.String(),
+			ProductID:         item.ProductID,
+			Quantity:          int32(item.Quantity),
+			FulfilledQuantity: int32(item.FulfilledQuantity),
+		}
+		if item.Product != nil {
+			itemRow.ProductName = &item.Product.Name
+			itemRow.ProductCategory = &item.Product.Category
+			itemRow.UnitPrice = amount(&item.Product.Price)
+		}
+		row.Items = append(row.Items, itemRow)
+	}
+
+	return row
+}
+
+// amount returns a recorded amount as a Parquet double, or nil when it was
+// not recorded.
+func amount(m *model.Money) *float64 {
+	if m == nil {
+		return nil
+	}
+	f := m.Float64()
+	return &f
+}
+
+// partition is a Parquet file being written for one update date. Files are
+// staged on local disk so large extracts are not held in memory.
+type partition struct {
+	date   string
+	file   *os.File
+	writer *parquet.GenericWriter[orderRow]
+	rows   int
+}
+
+// newPartition creates a staging file for the update date.
+func newPartition(date string) (*partition, error) {
+	file, err := os.CreateTemp("", "orders-"+date+"-*.parquet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	return &partition{
+		date:   date,
+		file:   file,
+		writer: parquet.NewGenericWriter[orderRow](file, parquet.Compression(&parquet.Snappy)),
+	}, nil
+}
+
+// write appends an order to the partition.
+func (p *partition) write(order model.ExportedOrder) error {
+	if _, err := p.writer.Write([]orderRow{newOrderRow(order)}); err != nil {
+		return fmt.Errorf("failed to write order %s: %w", order.ID, err)
+	}
+	p.rows++
+	return nil
+}
+
+// close finishes the Parquet file and rewinds it for uploading.
+func (p *partition) close() error {
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish export file: %w", err)
+	}
+	if _, err := p.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind export file: %w", err)
+	}
+	return nil
+}
+
+// discard removes the staging file.
+func (p *partition) discard() {
+	p.file.Close()
+	os.Remove(p.file.Name())
+}
@@ -0,0 +1,198 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository serves orders from memory, honouring the cursor and window.
+type memoryRepository struct {
+	orders    []model.ExportedOrder
+	watermark *time.Time
+}
+
+func (r *memoryRepository) ListUpdated(ctx context.Context, after model.ExportCursor, until time.Time, limit int) ([]model.ExportedOrder, error) {
+	var result []model.ExportedOrder
+	for _, o := range r.orders {
+		if !o.UpdatedAt.Before(until) {
+			continue
+		}
+		if o.UpdatedAt.Before(after.UpdatedAt) || (o.UpdatedAt.Equal(after.UpdatedAt) && o.ID.String() <= after.ID.String()) {
+			continue
+		}
+		result = append(result, o)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetWatermark(ctx context.Context, name string) (*time.Time, error) {
+	return r.watermark, nil
+}
+
+func (r *memoryRepository) SetWatermark(ctx context.Context, name string, watermark time.Time) error {
+	r.watermark = &watermark
+	return nil
+}
+
+// memoryUploader keeps uploaded files in memory.
+type memoryUploader struct {
+	files map[string][]byte
+	err   error
+}
+
+func (u *memoryUploader) Upload(ctx context.Context, key string, body io.ReadSeeker) error {
+	if u.err != nil {
+		return u.err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	u.files[key] = data
+	return nil
+}
+
+// readRows decodes an uploaded Parquet file.
+func readRows(t *testing.T, data []byte) []orderRow {
+	rows, err := parquet.Read[orderRow](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return rows
+}
+
+// testOrders returns orders updated on two days, sorted as the repository reads them.
+func testOrders() []model.ExportedOrder {
+	day1 := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	web := "web"
+	total := model.NewMoney(18.50)
+
+	return []model.ExportedOrder{
+		{
+			Order: model.Order{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Source: &web, Status: model.OrderStatusPending, Total: &total, CreatedAt: day1, UpdatedAt: day1},
+			Items: []model.OrderItem{
+				{ID: uuid.New(), ProductID: "P001", Quantity: 2, Product: &model.ProductSnapshot{Name: "Chicken Waffle", Category: "Waffle", Price: model.NewMoney(9.25)}},
+			},
+		},
+		{
+			Order: model.Order{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Status: model.OrderStatusConfirmed, CreatedAt: day1, UpdatedAt: day1.Add(time.Hour)},
+		},
+		{
+			Order: model.Order{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), Status: model.OrderStatusFulfilled, CreatedAt: day1, UpdatedAt: day2},
+			Items: []model.OrderItem{
+				{ID: uuid.New(), ProductID: "P002", Quantity: 1, FulfilledQuantity: 1},
+			},
+		},
+	}
+}
+
+func newTestExporter(repo *memoryRepository, uploader *memoryUploader, now time.Time) *Exporter {
+	e := NewExporter(repo, uploader, Config{Prefix: "exports/orders/", BatchSize: 2, SafetyLag: 5 * time.Minute}, zerolog.Nop())
+	e.now = func() time.Time { return now }
+	return e
+}
+
+func TestExporter_Run(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 3, 2, 0, 0, 0, time.UTC)
+	repo := &memoryRepository{orders: testOrders()}
+	uploader := &memoryUploader{files: make(map[string][]byte)}
+
+	result, err := newTestExporter(repo, uploader, now).Run(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Orders)
+	assert.Equal(t, []string{
+		"exports/orders/updated_date=2025-12-01/orders-20251203T015500Z.parquet",
+		"exports/orders/updated_date=2025-12-02/orders-20251203T015500Z.parquet",
+	}, result.Files)
+	require.NotNil(t, repo.watermark)
+	assert.Equal(t, now.Add(-5*time.Minute), *repo.watermark)
+
+	day1 := readRows(t, uploader.files[result.Files[0]])
+	require.Len(t, day1, 2)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", day1[0].ID)
+	assert.Equal(t, "web", *day1[0].Source)
+	assert.Nil(t, day1[0].CouponCode)
+	assert.Equal(t, 18.50, *day1[0].Total)
+	require.Len(t, day1[0].Items, 1)
+	assert.Equal(t, "Chicken Waffle", *day1[0].Items[0].ProductName)
+	assert.Equal(t, 9.25, *day1[0].Items[0].UnitPrice)
+	assert.Empty(t, day1[1].Items)
+
+	day2 := readRows(t, uploader.files[result.Files[1]])
+	require.Len(t, day2, 1)
+	assert.Equal(t, "fulfilled", day2[0].Status)
+	assert.Equal(t, int32(1), day2[0].Items[0].FulfilledQuantity)
+	assert.Nil(t, day2[0].Items[0].ProductName)
+}
+
+func TestExporter_Run_Incremental(t *testing.T) {
+	ctx := context.Background()
+	orders := testOrders()
+	watermark := orders[1].UpdatedAt
+	repo := &memoryRepository{orders: orders, watermark: &watermark}
+	uploader := &memoryUploader{files: make(map[string][]byte)}
+
+	result, err := newTestExporter(repo, uploader, time.Date(2025, 12, 3, 2, 0, 0, 0, time.UTC)).Run(ctx)
+	require.NoError(t, err)
+
+	// The order updated exactly at the watermark was not in the previous window
+	assert.Equal(t, 2, result.Orders)
+	assert.Equal(t, watermark, result.From)
+}
+
+func TestExporter_Run_SafetyLag(t *testing.T) {
+	ctx := context.Background()
+	orders := testOrders()
+	repo := &memoryRepository{orders: orders}
+	uploader := &memoryUploader{files: make(map[string][]byte)}
+
+	// The last order was updated within the safety lag, so it waits for the next run
+	now := orders[2].UpdatedAt.Add(time.Minute)
+	result, err := newTestExporter(repo, uploader, now).Run(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Orders)
+	assert.Len(t, result.Files, 1)
+}
+
+func TestExporter_Run_UploadFailureKeepsWatermark(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryRepository{orders: testOrders()}
+	uploader := &memoryUploader{err: errors.New("access denied")}
+
+	_, err := newTestExporter(repo, uploader, time.Date(2025, 12, 3, 2, 0, 0, 0, time.UTC)).Run(ctx)
+
+	require.Error(t, err)
+	assert.Nil(t, repo.watermark)
+}
+
+func TestExporter_Run_NothingToExport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 3, 2, 0, 0, 0, time.UTC)
+	watermark := now
+	repo := &memoryRepository{orders: testOrders(), watermark: &watermark}
+	uploader := &memoryUploader{files: make(map[string][]byte)}
+
+	result, err := newTestExporter(repo, uploader, now).Run(ctx)
+	require.NoError(t, err)
+
+	assert.Zero(t, result.Orders)
+	assert.Empty(t, uploader.files)
+	assert.Equal(t, now, *repo.watermark)
+}
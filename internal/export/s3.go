@@ -0,0 +1,58 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rs/zerolog"
+)
+
+// s3Uploader implements Uploader for an AWS S3 bucket.
+type s3Uploader struct {
+	client *s3.Client
+	bucket string
+	logger zerolog.Logger
+}
+
+// NewS3Uploader creates an Uploader writing to an S3 bucket. A nil httpClient
+// uses the AWS SDK's default client.
+func NewS3Uploader(ctx context.Context, bucket, region string, httpClient *http.Client, logger zerolog.Logger) (Uploader, error) {
+	logger = logger.With().Str("component", "s3-export-uploader").Logger()
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if httpClient != nil {
+		opts = append(opts, config.WithHTTPClient(httpClient))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load AWS configuration")
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return &s3Uploader{
+		client: s3.NewFromConfig(cfg),
+		bucket: bucket,
+		logger: logger,
+	}, nil
+}
+
+// Upload writes body to key in the bucket.
+func (u *s3Uploader) Upload(ctx context.Context, key string, body io.ReadSeeker) error {
+	_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(u.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String("application/vnd.apache.parquet"),
+	})
+	if err != nil {
+		u.logger.Error().Err(err).Str("bucket", u.bucket).Str("key", key).Msg("failed to put object")
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/rs/zerolog"
+)
+
+// SnapshotArchive stores order snapshots so they cannot be altered or
+// deleted while they may be needed as evidence.
+type SnapshotArchive interface {
+	// Archive writes body to key and returns where it is stored and how
+	// long it is retained.
+	Archive(ctx context.Context, key string, body []byte) (*model.SnapshotStorage, error)
+}
+
+// SnapshotArchiveConfig holds S3 snapshot archive configuration.
+type SnapshotArchiveConfig struct {
+	// Bucket and Region locate the S3 bucket snapshots are written to. The
+	// bucket must have Object Lock enabled.
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every snapshot key.
+	Prefix string
+
+	// LockMode is the Object Lock retention mode, "COMPLIANCE" or "GOVERNANCE".
+	LockMode string
+
+	// Retention is how long each snapshot is locked after it is written.
+	Retention time.Duration
+}
+
+// s3SnapshotArchive implements SnapshotArchive for an S3 bucket with Object Lock.
+type s3SnapshotArchive struct {
+	client *s3.Client
+	config SnapshotArchiveConfig
+	logger zerolog.Logger
+	now    func() time.Time
+}
+
+// NewS3SnapshotArchive creates a SnapshotArchive writing to an S3 bucket
+// under Object Lock. A nil httpClient uses the AWS SDK's default client.
+func NewS3SnapshotArchive(ctx context.Context, cfg SnapshotArchiveConfig, httpClient *http.Client, logger zerolog.Logger) (SnapshotArchive, error) {
+	logger = logger.With().Str("component", "s3-snapshot-archive").Logger()
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}
+	if httpClient != nil {
+		opts = append(opts, config.WithHTTPClient(httpClient))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load AWS configuration")
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return &s3SnapshotArchive{
+		client: s3.NewFromConfig(awsCfg),
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+	}, nil
+}
+
+// Archive writes body to key in the bucket with an Object Lock retention
+// period. S3 requires a checksum on locked uploads, so a SHA-256 checksum is
+// sent with the object.
+func (a *s3SnapshotArchive) Archive(ctx context.Context, key string, body []byte) (*model.SnapshotStorage, error) {
+	key = a.config.Prefix + key
+	retainUntil := a.now().Add(a.config.Retention).UTC().Truncate(time.Second)
+
+	out, err := a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:                    aws.String(a.config.Bucket),
+		Key:                       aws.String(key),
+		Body:                      bytes.NewReader(body),
+		ContentType:               aws.String("application/json"),
+		ChecksumAlgorithm:         types.ChecksumAlgorithmSha256,
+		ObjectLockMode:            types.ObjectLockMode(a.config.LockMode),
+		ObjectLockRetainUntilDate: aws.Time(retainUntil),
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Str("bucket", a.config.Bucket).Str("key", key).Msg("failed to put snapshot object")
+		return nil, fmt.Errorf("failed to put snapshot object %s: %w", key, err)
+	}
+
+	return &model.SnapshotStorage{
+		Location:    fmt.Sprintf("s3://%s/%s", a.config.Bucket, key),
+		VersionID:   aws.ToString(out.VersionId),
+		LockMode:    a.config.LockMode,
+		RetainUntil: retainUntil,
+	}, nil
+}
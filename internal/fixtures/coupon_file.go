@@ -0,0 +1,69 @@
+package fixtures
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CouponFileBuilder writes a gzipped coupon file, one code per line, as
+// partners deliver them.
+type CouponFileBuilder struct {
+	t     testing.TB
+	dir   string
+	name  string
+	codes []string
+}
+
+// NewCouponFile starts a coupon file with the given name, e.g.
+// "couponbase1.gz", in a temporary directory removed when the test ends.
+func NewCouponFile(t testing.TB, name string) *CouponFileBuilder {
+	return &CouponFileBuilder{t: t, name: name}
+}
+
+// Dir writes the file into dir instead of a temporary directory, e.g. to
+// keep several coupon files together.
+func (b *CouponFileBuilder) Dir(dir string) *CouponFileBuilder {
+	b.dir = dir
+	return b
+}
+
+// Codes adds lines to the file. Lines are written as given, so blank or
+// malformed lines can be written too.
+func (b *CouponFileBuilder) Codes(codes ...string) *CouponFileBuilder {
+	b.codes = append(b.codes, codes...)
+	return b
+}
+
+// Write writes the file and returns its path. The test fails if the file
+// cannot be written.
+func (b *CouponFileBuilder) Write() string {
+	b.t.Helper()
+
+	dir := b.dir
+	if dir == "" {
+		dir = b.t.TempDir()
+	}
+	path := filepath.Join(dir, b.name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		b.t.Fatalf("failed to create coupon file: %v", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	var content string
+	if len(b.codes) > 0 {
+		content = strings.Join(b.codes, "\n") + "\n"
+	}
+	if _, err := gzipWriter.Write([]byte(content)); err != nil {
+		b.t.Fatalf("failed to write coupon file: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		b.t.Fatalf("failed to write coupon file: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,5 @@
+// Package fixtures builds the products, orders and coupon files used by unit,
+// repository and integration tests. Builders start from consistent, valid
+// defaults, so a test only sets the fields it is about and every package
+// describes the same catalogue the same way.
+package fixtures
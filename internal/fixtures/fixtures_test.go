@@ -0,0 +1,150 @@
+package fixtures
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductBuilder(t *testing.T) {
+	t.Run("Catalogue defaults", func(t *testing.T) {
+		assert.Equal(t, []model.Product{
+			{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1"},
+			{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat2"},
+		}, Products(2))
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		createdAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+		endsAt := createdAt.Add(24 * time.Hour)
+
+		product := NewProduct(3).
+			ID("P100").
+			Name("Waffle").
+			Price(6.5).
+			Category("Waffle").
+			CreatedAt(createdAt).
+			Metadata("allergens", []string{"gluten"}).
+			Sale(5, nil, &endsAt).
+			Build()
+
+		assert.Equal(t, model.Product{
+			ID:        "P100",
+			Name:      "Waffle",
+			Price:     model.NewMoney(6.5),
+			Category:  "Waffle",
+			CreatedAt: createdAt,
+			Metadata:  map[string]any{"allergens": []string{"gluten"}},
+			Sale:      &model.ProductSale{Price: model.NewMoney(5), EndsAt: &endsAt},
+		}, product)
+	})
+
+	t.Run("Built products do not share metadata", func(t *testing.T) {
+		builder := NewProduct(1).Metadata("weight", "500g")
+		first := builder.Build()
+		first.Metadata["weight"] = "1kg"
+
+		assert.Equal(t, "500g", builder.Ptr().Metadata["weight"])
+	})
+}
+
+func TestOrderBuilder(t *testing.T) {
+	createdAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	customerID := uuid.New()
+	waffle := NewProduct(1).Name("Waffle").Sale(7.5, nil, nil).Build()
+
+	builder := NewOrder().
+		Customer(customerID).
+		Coupon("HAPPYHRS").
+		Source("web").
+		CreatedAt(createdAt).
+		ProductItem(waffle, 2).
+		Item("P002", 1)
+
+	order, items := builder.Build()
+
+	assert.NotEqual(t, uuid.Nil, order.ID)
+	assert.Equal(t, model.OrderStatusPending, order.Status)
+	assert.Equal(t, &customerID, order.CustomerID)
+	assert.Equal(t, "HAPPYHRS", *order.CouponCode)
+	assert.Equal(t, "web", *order.Source)
+	assert.Equal(t, createdAt, order.CreatedAt)
+	assert.Equal(t, createdAt, order.UpdatedAt)
+
+	require.Len(t, items, 2)
+	for _, item := range items {
+		assert.NotEqual(t, uuid.Nil, item.ID)
+		assert.Equal(t, order.ID, item.OrderID)
+	}
+	assert.Equal(t, &model.ProductSnapshot{Name: "Waffle", Category: "Cat1", Price: model.NewMoney(7.5), OnSale: true}, items[0].Product)
+	assert.Equal(t, "P002", items[1].ProductID)
+	assert.Nil(t, items[1].Product)
+
+	t.Run("Changing the ID moves the items", func(t *testing.T) {
+		id := uuid.New()
+		order, items := NewOrder().Item("P001", 1).ID(id).Build()
+
+		assert.Equal(t, id, order.ID)
+		assert.Equal(t, id, items[0].OrderID)
+	})
+
+	t.Run("Request places the order", func(t *testing.T) {
+		assert.Equal(t, &model.OrderRequest{
+			CustomerID: &customerID,
+			CouponCode: order.CouponCode,
+			Source:     order.Source,
+			Items: []model.OrderItemRequest{
+				{ProductID: "P001", Quantity: 2},
+				{ProductID: "P002", Quantity: 1},
+			},
+		}, builder.Request())
+	})
+}
+
+func TestCouponFileBuilder(t *testing.T) {
+	readLines := func(t *testing.T, path string) []string {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		gzipReader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+
+		var lines []string
+		scanner := bufio.NewScanner(gzipReader)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.NoError(t, scanner.Err())
+		return lines
+	}
+
+	t.Run("Gzipped codes in a temporary directory", func(t *testing.T) {
+		path := NewCouponFile(t, "couponbase1.gz").Codes("HAPPYHRS", "", "FIFTYOFF").Codes("SUPER100").Write()
+
+		assert.Equal(t, "couponbase1.gz", filepath.Base(path))
+		assert.Equal(t, []string{"HAPPYHRS", "", "FIFTYOFF", "SUPER100"}, readLines(t, path))
+	})
+
+	t.Run("Given directory", func(t *testing.T) {
+		dir := t.TempDir()
+		path := NewCouponFile(t, "couponbase2.gz").Dir(dir).Codes("HAPPYHRS").Write()
+
+		assert.Equal(t, filepath.Join(dir, "couponbase2.gz"), path)
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		path := NewCouponFile(t, "couponbase3.gz").Write()
+
+		assert.Empty(t, readLines(t, path))
+	})
+}
@@ -0,0 +1,122 @@
+package fixtures
+
+import (
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// OrderBuilder builds a model.Order with its items, or the
+// model.OrderRequest that places it.
+type OrderBuilder struct {
+	order model.Order
+	items []model.OrderItem
+}
+
+// NewOrder starts a pending, anonymous order without a coupon, created now.
+func NewOrder() *OrderBuilder {
+	now := time.Now()
+	return &OrderBuilder{order: model.Order{
+		ID:        uuid.New(),
+		Status:    model.OrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}}
+}
+
+// ID sets the order's ID.
+func (b *OrderBuilder) ID(id uuid.UUID) *OrderBuilder {
+	b.order.ID = id
+	for i := range b.items {
+		b.items[i].OrderID = id
+	}
+	return b
+}
+
+// Customer attaches the order to a customer.
+func (b *OrderBuilder) Customer(id uuid.UUID) *OrderBuilder {
+	b.order.CustomerID = &id
+	return b
+}
+
+// Coupon sets the coupon code the order is placed with.
+func (b *OrderBuilder) Coupon(code string) *OrderBuilder {
+	b.order.CouponCode = &code
+	return b
+}
+
+// Source sets the channel the order is placed through.
+func (b *OrderBuilder) Source(source string) *OrderBuilder {
+	b.order.Source = &source
+	return b
+}
+
+// Status sets the order's status.
+func (b *OrderBuilder) Status(status model.OrderStatus) *OrderBuilder {
+	b.order.Status = status
+	return b
+}
+
+// CreatedAt sets when the order was created and last updated.
+func (b *OrderBuilder) CreatedAt(t time.Time) *OrderBuilder {
+	b.order.CreatedAt = t
+	b.order.UpdatedAt = t
+	return b
+}
+
+// Totals sets the order's priced subtotal, discount and total.
+func (b *OrderBuilder) Totals(subtotal, discount, total float64) *OrderBuilder {
+	amounts := []model.Money{model.NewMoney(subtotal), model.NewMoney(discount), model.NewMoney(total)}
+	b.order.Subtotal = &amounts[0]
+	b.order.Discount = &amounts[1]
+	b.order.Total = &amounts[2]
+	return b
+}
+
+// Item adds a line of quantity units of a product to the order.
+func (b *OrderBuilder) Item(productID string, quantity int) *OrderBuilder {
+	b.items = append(b.items, model.OrderItem{
+		ID:        uuid.New(),
+		OrderID:   b.order.ID,
+		ProductID: productID,
+		Quantity:  quantity,
+	})
+	return b
+}
+
+// ProductItem adds a line of quantity units of product to the order, with a
+// snapshot of the product as it was when the order was created.
+func (b *OrderBuilder) ProductItem(product model.Product, quantity int) *OrderBuilder {
+	b.Item(product.ID, quantity)
+	b.items[len(b.items)-1].Product = product.SnapshotAt(b.order.CreatedAt)
+	return b
+}
+
+// Build returns the order and its items.
+func (b *OrderBuilder) Build() (*model.Order, []model.OrderItem) {
+	order := b.order
+	return &order, append([]model.OrderItem(nil), b.items...)
+}
+
+// Order returns the order without its items.
+func (b *OrderBuilder) Order() *model.Order {
+	order, _ := b.Build()
+	return order
+}
+
+// Request returns the request that places the order: its customer, coupon,
+// source and items.
+func (b *OrderBuilder) Request() *model.OrderRequest {
+	req := &model.OrderRequest{
+		CustomerID: b.order.CustomerID,
+		CouponCode: b.order.CouponCode,
+		Source:     b.order.Source,
+		Items:      make([]model.OrderItemRequest, len(b.items)),
+	}
+	for i, item := range b.items {
+		req.Items[i] = model.OrderItemRequest{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	return req
+}
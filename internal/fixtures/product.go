@@ -0,0 +1,95 @@
+package fixtures
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"mini-kart/internal/model"
+)
+
+// ProductBuilder builds a model.Product.
+type ProductBuilder struct {
+	product model.Product
+}
+
+// NewProduct starts the nth product of the test catalogue: ID "P00n", named
+// "Product n", priced n × 10.00 in category "Catn". Product 1 is
+// {ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1"}.
+func NewProduct(n int) *ProductBuilder {
+	return &ProductBuilder{product: model.Product{
+		ID:       fmt.Sprintf("P%03d", n),
+		Name:     fmt.Sprintf("Product %d", n),
+		Price:    model.MoneyFromCents(int64(n) * 1000),
+		Category: fmt.Sprintf("Cat%d", n),
+	}}
+}
+
+// Products builds the first n products of the test catalogue.
+func Products(n int) []model.Product {
+	products := make([]model.Product, n)
+	for i := range products {
+		products[i] = NewProduct(i + 1).Build()
+	}
+	return products
+}
+
+// ID sets the product's ID.
+func (b *ProductBuilder) ID(id string) *ProductBuilder {
+	b.product.ID = id
+	return b
+}
+
+// Name sets the product's name.
+func (b *ProductBuilder) Name(name string) *ProductBuilder {
+	b.product.Name = name
+	return b
+}
+
+// Price sets the product's regular price.
+func (b *ProductBuilder) Price(price float64) *ProductBuilder {
+	b.product.Price = model.NewMoney(price)
+	return b
+}
+
+// Category sets the product's category.
+func (b *ProductBuilder) Category(category string) *ProductBuilder {
+	b.product.Category = category
+	return b
+}
+
+// CreatedAt sets when the product was created.
+func (b *ProductBuilder) CreatedAt(t time.Time) *ProductBuilder {
+	b.product.CreatedAt = t
+	return b
+}
+
+// Metadata sets a descriptive attribute of the product.
+func (b *ProductBuilder) Metadata(key string, value any) *ProductBuilder {
+	if b.product.Metadata == nil {
+		b.product.Metadata = make(map[string]any)
+	}
+	b.product.Metadata[key] = value
+	return b
+}
+
+// Sale schedules a sale at price between startsAt and endsAt, either of
+// which may be nil.
+func (b *ProductBuilder) Sale(price float64, startsAt, endsAt *time.Time) *ProductBuilder {
+	b.product.Sale = &model.ProductSale{Price: model.NewMoney(price), StartsAt: startsAt, EndsAt: endsAt}
+	return b
+}
+
+// Build returns the product. EffectivePrice and OnSale are left for
+// model.Product.WithEffectivePrice, as repositories leave them.
+func (b *ProductBuilder) Build() model.Product {
+	product := b.product
+	product.Metadata = maps.Clone(b.product.Metadata)
+	return product
+}
+
+// Ptr returns a pointer to a built product.
+func (b *ProductBuilder) Ptr() *model.Product {
+	product := b.Build()
+	return &product
+}
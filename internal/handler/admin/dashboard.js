@@ -0,0 +1,125 @@
+"use strict";
+
+// The admin key is kept for the browser tab only. The dashboard's data is
+// served from the admin route group, which only admin keys may call.
+const keyStorage = "mini-kart-api-key";
+const summaryURL = "/api/v1/admin/dashboard";
+
+const $ = (id) => document.getElementById(id);
+
+function showError(message) {
+  $("error").textContent = message;
+  $("error").hidden = !message;
+}
+
+function cell(row, text) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  row.appendChild(td);
+}
+
+function fillTable(id, rows, columns) {
+  const body = $(id);
+  body.replaceChildren();
+  for (const item of rows) {
+    const row = document.createElement("tr");
+    for (const column of columns) {
+      cell(row, column(item));
+    }
+    body.appendChild(row);
+  }
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "-";
+}
+
+function render(data) {
+  $("generated").textContent = "Updated " + formatTime(data.generatedAt);
+  $("products").textContent = data.products;
+  $("orders").textContent = data.orders;
+  $("coupons").textContent = data.coupons.totalCoupons.toLocaleString();
+
+  let state = data.coupons.sets.length > 0
+    ? "Loaded " + formatTime(data.coupons.loadedAt) + " using " + data.coupons.setType + " sets"
+    : "Not loaded";
+  if (data.coupons.updatedAt) {
+    state += ", deltas applied " + formatTime(data.coupons.updatedAt);
+  }
+  $("coupon-state").textContent = state;
+
+  fillTable("coupon-sets", data.coupons.sets, [
+    (s) => s.name,
+    (s) => s.size.toLocaleString(),
+    (s) => s.sequence,
+  ]);
+  fillTable("recent-orders", data.recentOrders, [
+    (o) => o.id,
+    (o) => o.source || "unattributed",
+    (o) => o.couponCode || "-",
+    (o) => (o.total === undefined ? "-" : o.total.toFixed(2)),
+    (o) => formatTime(o.createdAt),
+  ]);
+  fillTable("order-sources", data.orderSources, [
+    (s) => s.source,
+    (s) => s.orders,
+  ]);
+  fillTable("metrics", data.metrics, [
+    (m) => m.name,
+    (m) => Object.entries(m.labels || {}).map(([k, v]) => k + "=" + v).join(", "),
+    (m) => m.value,
+  ]);
+}
+
+async function load() {
+  const headers = {};
+  const key = sessionStorage.getItem(keyStorage);
+  if (key) {
+    headers["X-API-Key"] = key;
+  }
+
+  let response;
+  try {
+    response = await fetch(summaryURL, { headers });
+  } catch (err) {
+    showError("Failed to reach the API: " + err.message);
+    return;
+  }
+
+  if (response.status === 401 || response.status === 403) {
+    sessionStorage.removeItem(keyStorage);
+    $("dashboard").hidden = true;
+    $("sign-in").hidden = false;
+    if (response.status === 403) {
+      showError("The dashboard needs an admin API key.");
+    } else {
+      showError(key ? "The API key was rejected." : "");
+    }
+    return;
+  }
+  if (!response.ok) {
+    showError("Failed to load the dashboard (HTTP " + response.status + ").");
+    return;
+  }
+
+  showError("");
+  $("sign-in").hidden = true;
+  $("dashboard").hidden = false;
+  render(await response.json());
+}
+
+$("sign-in").addEventListener("submit", (event) => {
+  event.preventDefault();
+  sessionStorage.setItem(keyStorage, $("api-key").value);
+  $("api-key").value = "";
+  load();
+});
+
+$("refresh").addEventListener("click", load);
+
+$("sign-out").addEventListener("click", () => {
+  sessionStorage.removeItem(keyStorage);
+  load();
+});
+
+load();
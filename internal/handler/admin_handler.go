@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// dashboardRecentOrders is the number of recent orders shown on the dashboard.
+const dashboardRecentOrders = 10
+
+// adminAssets holds the admin dashboard page, which loads its data from
+// GET /api/admin/dashboard.
+//
+//go:embed admin
+var adminAssets embed.FS
+
+// CouponStatusReporter reports the coupon data loaded by the validator.
+type CouponStatusReporter interface {
+	Status() coupon.Status
+}
+
+// AdminHandler serves the admin dashboard and its data.
+type AdminHandler struct {
+	productService service.ProductService
+	orderService   service.OrderService
+	coupons        CouponStatusReporter
+	registry       *metrics.Registry
+	assets         http.Handler
+	logger         zerolog.Logger
+}
+
+// NewAdminHandler creates a new admin dashboard handler.
+func NewAdminHandler(
+	productService service.ProductService,
+	orderService service.OrderService,
+	coupons CouponStatusReporter,
+	registry *metrics.Registry,
+	logger zerolog.Logger,
+) *AdminHandler {
+	// The embedded directory always exists, so Sub cannot fail
+	assets, _ := fs.Sub(adminAssets, "admin")
+
+	return &AdminHandler{
+		productService: productService,
+		orderService:   orderService,
+		coupons:        coupons,
+		registry:       registry,
+		assets:         http.StripPrefix("/admin/", http.FileServerFS(assets)),
+		logger:         logger.With().Str("handler", "admin").Logger(),
+	}
+}
+
+// DashboardResponse represents the response payload for the admin dashboard.
+type DashboardResponse struct {
+	GeneratedAt  time.Time           `json:"generatedAt"`
+	Products     int                 `json:"products"`
+	Orders       int                 `json:"orders"`
+	OrderSources []model.SourceCount `json:"orderSources"`
+	Coupons      coupon.Status       `json:"coupons"`
+	Metrics      []metrics.Sample    `json:"metrics"`
+	RecentOrders []model.Order       `json:"recentOrders"`
+}
+
+// Dashboard handles GET /admin requests by serving the dashboard page.
+// The page holds no data itself; it authenticates to the dashboard API.
+func (h *AdminHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	if r.URL.Path == "/admin" {
+		http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+		return
+	}
+
+	w.Header().Set("Content-Security-Policy", "default-src 'self'")
+	w.Header().Set("X-Frame-Options", "DENY")
+	h.assets.ServeHTTP(w, r)
+}
+
+// Summary handles GET /api/admin/dashboard requests.
+func (h *AdminHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	ctx := r.Context()
+
+	_, products, err := h.productService.GetAll(ctx, model.ProductFilter{Limit: 1})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve product count", h.logger)
+		return
+	}
+
+	orders, page, err := h.orderService.List(ctx, model.OrderFilter{Limit: dashboardRecentOrders})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve orders", h.logger)
+		return
+	}
+
+	sources, err := h.orderService.CountBySource(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve order analytics", h.logger)
+		return
+	}
+
+	if orders == nil {
+		orders = []model.Order{}
+	}
+	if sources == nil {
+		sources = []model.SourceCount{}
+	}
+
+	writeJSON(w, http.StatusOK, DashboardResponse{
+		GeneratedAt:  time.Now().UTC(),
+		Products:     products.Total,
+		Orders:       page.Total,
+		OrderSources: sources,
+		Coupons:      h.coupons.Status(),
+		Metrics:      h.registry.Snapshot(),
+		RecentOrders: orders,
+	})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCouponStatus returns a fixed coupon status.
+type stubCouponStatus coupon.Status
+
+func (s stubCouponStatus) Status() coupon.Status {
+	return coupon.Status(s)
+}
+
+func TestAdminHandler_Dashboard(t *testing.T) {
+	logger := zerolog.Nop()
+	h := NewAdminHandler(new(MockProductService), new(MockOrderService), stubCouponStatus{}, metrics.NewRegistry(), logger)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Page",
+			method:         http.MethodGet,
+			path:           "/admin/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "<title>mini-kart admin</title>",
+		},
+		{
+			name:           "Script",
+			method:         http.MethodGet,
+			path:           "/admin/dashboard.js",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/api/v1/admin/dashboard",
+		},
+		{
+			name:           "Redirects to trailing slash",
+			method:         http.MethodGet,
+			path:           "/admin",
+			expectedStatus: http.StatusMovedPermanently,
+		},
+		{
+			name:           "Unknown asset",
+			method:         http.MethodGet,
+			path:           "/admin/missing.js",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			path:           "/admin/",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.Dashboard(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+				assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Summary(t *testing.T) {
+	logger := zerolog.Nop()
+	loadedAt := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	coupons := stubCouponStatus{
+		SetType:      coupon.SetTypeMap,
+		LoadedAt:     loadedAt,
+		Sets:         []coupon.SetStatus{{Name: "couponbase1", Size: 3}},
+		TotalCoupons: 3,
+	}
+	recentFilter := model.OrderFilter{Limit: dashboardRecentOrders}
+
+	t.Run("Success", func(t *testing.T) {
+		productService := new(MockProductService)
+		orderService := new(MockOrderService)
+		registry := metrics.NewRegistry()
+		registry.Counter("deprecated_requests_total", "route", "/api/").Add(2)
+
+		orders := []model.Order{{ID: uuid.New(), CreatedAt: loadedAt, UpdatedAt: loadedAt}}
+		productService.On("GetAll", mock.Anything, model.ProductFilter{Limit: 1}).Return([]model.Product{}, model.Page{Limit: 1, Total: 7}, nil)
+		orderService.On("List", mock.Anything, recentFilter).Return(orders, model.Page{Limit: 10, Total: 31}, nil)
+		orderService.On("CountBySource", mock.Anything).Return([]model.SourceCount{{Source: "web", Orders: 31}}, nil)
+
+		h := NewAdminHandler(productService, orderService, coupons, registry, logger)
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dashboard", nil)
+		w := httptest.NewRecorder()
+
+		h.Summary(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp DashboardResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, 7, resp.Products)
+		assert.Equal(t, 31, resp.Orders)
+		assert.Equal(t, []model.SourceCount{{Source: "web", Orders: 31}}, resp.OrderSources)
+		assert.Equal(t, coupon.Status(coupons), resp.Coupons)
+		require.Len(t, resp.RecentOrders, 1)
+		assert.Equal(t, orders[0].ID, resp.RecentOrders[0].ID)
+		require.Len(t, resp.Metrics, 1)
+		assert.Equal(t, int64(2), resp.Metrics[0].Value)
+	})
+
+	t.Run("Order lookup fails", func(t *testing.T) {
+		productService := new(MockProductService)
+		orderService := new(MockOrderService)
+
+		productService.On("GetAll", mock.Anything, model.ProductFilter{Limit: 1}).Return([]model.Product{}, model.Page{Limit: 1}, nil)
+		orderService.On("List", mock.Anything, recentFilter).Return(nil, model.Page{}, errors.New("database error"))
+
+		h := NewAdminHandler(productService, orderService, coupons, metrics.NewRegistry(), logger)
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dashboard", nil)
+		w := httptest.NewRecorder()
+
+		h.Summary(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		orderService.AssertNotCalled(t, "CountBySource", mock.Anything)
+	})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// CartHandler serves session-less carts for anonymous storefront users. The
+// cart lives in a signed token the client keeps and submits at checkout,
+// so nothing is stored per visitor.
+type CartHandler struct {
+	service service.CartService
+	logger  zerolog.Logger
+}
+
+// NewCartHandler creates a new cart handler.
+func NewCartHandler(service service.CartService, logger zerolog.Logger) *CartHandler {
+	return &CartHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "cart").Logger(),
+	}
+}
+
+// Cart handles GET and POST /public/cart requests. POST signs the cart in
+// the body into a token; GET restores the cart of the ?token= parameter.
+// Both respond with the cart priced at current catalogue prices.
+func (h *CartHandler) Cart(w http.ResponseWriter, r *http.Request) {
+	// Carts are per visitor, so no shared cache may keep them
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Add("Vary", middleware.TenantHeader)
+
+	var (
+		cart *model.SignedCart
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			writeError(w, http.StatusBadRequest, "token is required", h.logger)
+			return
+		}
+		cart, err = h.service.GetCart(r.Context(), token)
+	case http.MethodPost:
+		var req model.CartRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+		cart, err = h.service.SignCart(r.Context(), &req)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	if err != nil {
+		switch err {
+		case model.ErrInvalidCart, model.ErrInvalidCartToken, model.ErrCartExpired:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		default:
+			if writeUnavailable(w, err, h.logger) {
+				return
+			}
+			status, message := previewErrorStatus(err)
+			writeError(w, status, message, h.logger)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cart)
+}
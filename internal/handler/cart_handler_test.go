@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCartService is a mock implementation of CartService.
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) SignCart(ctx context.Context, req *model.CartRequest) (*model.SignedCart, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignedCart), args.Error(1)
+}
+
+func (m *MockCartService) GetCart(ctx context.Context, token string) (*model.SignedCart, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignedCart), args.Error(1)
+}
+
+func (m *MockCartService) OpenCart(ctx context.Context, token string) (*model.Cart, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cart), args.Error(1)
+}
+
+func TestCartHandler(t *testing.T) {
+	signed := &model.SignedCart{
+		Token:   "eyJ0aWQiOiJkZWZhdWx0In0.c2ln",
+		Items:   []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+		Pricing: &model.PriceBreakdown{Total: model.NewMoney(20.00)},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockCartService)
+		expectedStatus int
+	}{
+		{
+			name:   "Sign cart",
+			method: http.MethodPost,
+			path:   "/public/cart",
+			body:   `{"items":[{"productId":"P001","quantity":2}]}`,
+			setupMock: func(m *MockCartService) {
+				m.On("SignCart", mock.Anything, &model.CartRequest{Items: signed.Items}).Return(signed, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Sign invalid cart",
+			method: http.MethodPost,
+			path:   "/public/cart",
+			body:   `{"items":[]}`,
+			setupMock: func(m *MockCartService) {
+				m.On("SignCart", mock.Anything, mock.Anything).Return(nil, model.ErrInvalidCart)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Sign cart with unknown products",
+			method: http.MethodPost,
+			path:   "/public/cart",
+			body:   `{"items":[{"productId":"P999","quantity":1}]}`,
+			setupMock: func(m *MockCartService) {
+				m.On("SignCart", mock.Anything, mock.Anything).Return(nil, model.ErrProductNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Restore cart",
+			method: http.MethodGet,
+			path:   "/public/cart?token=" + signed.Token,
+			setupMock: func(m *MockCartService) {
+				m.On("GetCart", mock.Anything, signed.Token).Return(signed, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Restore expired cart",
+			method: http.MethodGet,
+			path:   "/public/cart?token=" + signed.Token,
+			setupMock: func(m *MockCartService) {
+				m.On("GetCart", mock.Anything, signed.Token).Return(nil, model.ErrCartExpired)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Restore cart whose coupon was exhausted",
+			method: http.MethodGet,
+			path:   "/public/cart?token=" + signed.Token,
+			setupMock: func(m *MockCartService) {
+				m.On("GetCart", mock.Anything, signed.Token).Return(nil, model.ErrCouponFirstOrderOnly)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Pricing fails",
+			method: http.MethodGet,
+			path:   "/public/cart?token=" + signed.Token,
+			setupMock: func(m *MockCartService) {
+				m.On("GetCart", mock.Anything, signed.Token).Return(nil, errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Restore without a token",
+			method:         http.MethodGet,
+			path:           "/public/cart",
+			setupMock:      func(m *MockCartService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           "/public/cart",
+			body:           `{`,
+			setupMock:      func(m *MockCartService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/public/cart",
+			setupMock:      func(m *MockCartService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockCartService)
+			tt.setupMock(svc)
+			h := NewCartHandler(svc, zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Cart(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			svc.AssertExpectations(t)
+		})
+	}
+}
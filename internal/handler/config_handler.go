@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// ConfigHandler serves the configuration the service was started with, so
+// admins can check a running instance without shell access to it.
+type ConfigHandler struct {
+	config map[string]any
+	logger zerolog.Logger
+}
+
+// NewConfigHandler creates a new config handler serving config, which must
+// already have its secrets redacted.
+func NewConfigHandler(config map[string]any, logger zerolog.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		config: config,
+		logger: logger.With().Str("handler", "config").Logger(),
+	}
+}
+
+// Config handles GET /api/admin/config requests.
+func (h *ConfigHandler) Config(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, h.config)
+}
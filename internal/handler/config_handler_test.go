@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler_Config(t *testing.T) {
+	h := NewConfigHandler(map[string]any{"Server": map[string]any{"Port": 8080}}, zerolog.Nop())
+
+	w := httptest.NewRecorder()
+	h.Config(w, httptest.NewRequest(http.MethodGet, "/api/admin/config", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	var config map[string]map[string]int
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&config))
+	assert.Equal(t, 8080, config["Server"]["Port"])
+
+	w = httptest.NewRecorder()
+	h.Config(w, httptest.NewRequest(http.MethodPut, "/api/admin/config", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
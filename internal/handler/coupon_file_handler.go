@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// CouponFileHandler handles coupon file registry HTTP requests, which record
+// coupon file uploads, trace the files the validator loaded at any time back
+// to them and check whether codes were valid at past times. The registry is
+// managed with admin keys only.
+type CouponFileHandler struct {
+	service service.CouponFileService
+	logger  zerolog.Logger
+}
+
+// NewCouponFileHandler creates a new coupon file registry handler.
+func NewCouponFileHandler(service service.CouponFileService, logger zerolog.Logger) *CouponFileHandler {
+	return &CouponFileHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "coupon_file").Logger(),
+	}
+}
+
+// CouponGenerationsResponse represents the response payload for the coupon
+// file generations in use over a period.
+type CouponGenerationsResponse struct {
+	From        time.Time                `json:"from"`
+	To          time.Time                `json:"to"`
+	Generations []model.CouponGeneration `json:"generations"`
+}
+
+// Files handles GET and POST /api/admin/coupon-files requests. GET lists
+// registered uploads, optionally of one coupon set given by the name query
+// parameter; POST registers an upload.
+func (h *CouponFileHandler) Files(w http.ResponseWriter, r *http.Request) {
+	identity, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		files, err := h.service.ListFiles(r.Context(), r.URL.Query().Get("name"))
+		if err != nil {
+			if writeUnavailable(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to retrieve coupon files", h.logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, files)
+	case http.MethodPost:
+		var req model.CouponFileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		file, err := h.service.RegisterFile(r.Context(), &req, identity.Subject)
+		if err != nil {
+			switch err {
+			case model.ErrInvalidCouponFile:
+				writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+			case model.ErrCouponFileExists:
+				writeError(w, http.StatusConflict, "coupon file already registered", h.logger)
+			default:
+				if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+					return
+				}
+				writeError(w, http.StatusInternalServerError, "failed to register coupon file", h.logger)
+			}
+			return
+		}
+		writeJSON(w, http.StatusCreated, file)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// Generations handles GET /api/admin/coupon-files/generations requests,
+// listing the generations of coupon files serving validations on the UTC day
+// given by the date query parameter (YYYY-MM-DD), at the RFC 3339 time given
+// by the at parameter, or now.
+func (h *CouponFileHandler) Generations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r); !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+	from := time.Now().UTC()
+	to := from
+	switch {
+	case query.Get("date") != "" && query.Get("at") != "":
+		writeError(w, http.StatusBadRequest, "date and at cannot be combined", h.logger)
+		return
+	case query.Get("date") != "":
+		day, err := time.Parse(time.DateOnly, query.Get("date"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "date must be formatted YYYY-MM-DD", h.logger)
+			return
+		}
+		from, to = day, day.AddDate(0, 0, 1).Add(-time.Microsecond)
+	case query.Get("at") != "":
+		at, err := time.Parse(time.RFC3339, query.Get("at"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "at must be an RFC 3339 time", h.logger)
+			return
+		}
+		from, to = at.UTC(), at.UTC()
+	}
+
+	generations, err := h.service.Generations(r.Context(), from, to)
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve coupon file generations", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CouponGenerationsResponse{
+		From:        from,
+		To:          to,
+		Generations: generations,
+	})
+}
+
+// Validity handles GET /api/admin/coupon-files/validity requests, checking
+// whether the promo code given by the code query parameter was valid at the
+// RFC 3339 time given by the at parameter, e.g. to resolve a customer's
+// dispute about a refused code. The code is searched for in archived copies
+// of the coupon files in use then, so the check can take a while.
+func (h *CouponFileHandler) Validity(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r); !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+	code := query.Get("code")
+	if code == "" {
+		writeError(w, http.StatusBadRequest, "code is required", h.logger)
+		return
+	}
+	at, err := time.Parse(time.RFC3339, query.Get("at"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "at must be an RFC 3339 time", h.logger)
+		return
+	}
+	if at.After(time.Now()) {
+		writeError(w, http.StatusBadRequest, "at cannot be in the future", h.logger)
+		return
+	}
+
+	result, err := h.service.ValidityAt(r.Context(), code, at.UTC())
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to check coupon validity", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// authorize rejects callers not using an admin key, returning the caller's
+// identity and whether the request may proceed.
+func (h *CouponFileHandler) authorize(w http.ResponseWriter, r *http.Request) (middleware.Identity, bool) {
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		writeError(w, http.StatusForbidden, "the coupon file registry is managed with admin keys only", h.logger)
+		return middleware.Identity{}, false
+	}
+	return identity, true
+}
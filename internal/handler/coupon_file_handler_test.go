@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCouponFileService is a mock implementation of CouponFileService.
+type MockCouponFileService struct {
+	mock.Mock
+}
+
+func (m *MockCouponFileService) RegisterFile(ctx context.Context, req *model.CouponFileRequest, registeredBy string) (*model.CouponFile, error) {
+	args := m.Called(ctx, req, registeredBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponFile), args.Error(1)
+}
+
+func (m *MockCouponFileService) ListFiles(ctx context.Context, name string) ([]model.CouponFile, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CouponFile), args.Error(1)
+}
+
+func (m *MockCouponFileService) Generations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CouponGeneration), args.Error(1)
+}
+
+func (m *MockCouponFileService) ValidityAt(ctx context.Context, promoCode string, at time.Time) (*model.CouponValidityAt, error) {
+	args := m.Called(ctx, promoCode, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponValidityAt), args.Error(1)
+}
+
+func TestCouponFileHandler(t *testing.T) {
+	admin := middleware.Identity{Subject: "admin:support", Method: middleware.AuthMethodAdminKey}
+	checksum := strings.Repeat("ab", 32)
+	file := &model.CouponFile{Name: "couponbase1", Source: "s3://coupons/couponbase1.gz", Checksum: checksum}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2026, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		identity       *middleware.Identity
+		setupMock      func(*MockCouponFileService)
+		expectedStatus int
+	}{
+		{
+			name:     "List coupon files",
+			method:   http.MethodGet,
+			path:     "/api/admin/coupon-files?name=couponbase1",
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("ListFiles", mock.Anything, "couponbase1").Return([]model.CouponFile{*file}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Register coupon file",
+			method:   http.MethodPost,
+			path:     "/api/admin/coupon-files",
+			body:     `{"name":"couponbase1","source":"s3://coupons/couponbase1.gz","checksum":"` + checksum + `"}`,
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("RegisterFile", mock.Anything, mock.AnythingOfType("*model.CouponFileRequest"), "admin:support").Return(file, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:     "Register coupon file twice",
+			method:   http.MethodPost,
+			path:     "/api/admin/coupon-files",
+			body:     `{"name":"couponbase1","source":"s3://coupons/couponbase1.gz","checksum":"` + checksum + `"}`,
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("RegisterFile", mock.Anything, mock.Anything, mock.Anything).Return(nil, model.ErrCouponFileExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:     "Register invalid coupon file",
+			method:   http.MethodPost,
+			path:     "/api/admin/coupon-files",
+			body:     `{"name":"couponbase1","checksum":"abc"}`,
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("RegisterFile", mock.Anything, mock.Anything, mock.Anything).Return(nil, model.ErrInvalidCouponFile)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           "/api/admin/coupon-files",
+			body:           `{`,
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Generations during a day",
+			method:   http.MethodGet,
+			path:     "/api/admin/coupon-files/generations?date=2026-03-01",
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("Generations", mock.Anything, day, day.Add(24*time.Hour-time.Microsecond)).Return([]model.CouponGeneration{{ID: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Generations at a time",
+			method:   http.MethodGet,
+			path:     "/api/admin/coupon-files/generations?at=2026-03-01T16:30:00%2B02:00",
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("Generations", mock.Anything, at, at).Return([]model.CouponGeneration{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Malformed date",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/generations?date=01/03/2026",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Date and time combined",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/generations?date=2026-03-01&at=2026-03-01T12:00:00Z",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Validity at a time",
+			method:   http.MethodGet,
+			path:     "/api/admin/coupon-files/validity?code=SUMMER2025&at=2026-03-01T16:30:00%2B02:00",
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("ValidityAt", mock.Anything, "SUMMER2025", at).Return(&model.CouponValidityAt{Code: "SUMMER2025", At: at, Verdict: model.CouponVerdictValid}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Validity without a code",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/validity?at=2026-03-01T12:00:00Z",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Validity without a time",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/validity?code=SUMMER2025",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Validity in the future",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/validity?code=SUMMER2025&at=2999-01-01T00:00:00Z",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Validity check fails",
+			method:   http.MethodGet,
+			path:     "/api/admin/coupon-files/validity?code=SUMMER2025&at=2026-03-01T12:00:00Z",
+			identity: &admin,
+			setupMock: func(m *MockCouponFileService) {
+				m.On("ValidityAt", mock.Anything, "SUMMER2025", mock.Anything).Return(nil, errors.New("archive unreachable"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "API key cannot check validity",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files/validity?code=SUMMER2025&at=2026-03-01T12:00:00Z",
+			identity:       &middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin acting on behalf of a customer cannot manage coupon files",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files",
+			identity:       &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key cannot manage coupon files",
+			method:         http.MethodGet,
+			path:           "/api/admin/coupon-files",
+			identity:       &middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/admin/coupon-files",
+			identity:       &admin,
+			setupMock:      func(m *MockCouponFileService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockCouponFileService)
+			tt.setupMock(svc)
+			h := NewCouponFileHandler(svc, zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			switch {
+			case strings.HasPrefix(tt.path, "/api/admin/coupon-files/generations"):
+				h.Generations(w, req)
+			case strings.HasPrefix(tt.path, "/api/admin/coupon-files/validity"):
+				h.Validity(w, req)
+			default:
+				h.Files(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCouponFileHandler_GenerationsResponse(t *testing.T) {
+	svc := new(MockCouponFileService)
+	svc.On("Generations", mock.Anything, mock.Anything, mock.Anything).Return([]model.CouponGeneration{{ID: 3, Instance: "api-1"}}, nil)
+	h := NewCouponFileHandler(svc, zerolog.Nop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/coupon-files/generations?date=2026-03-01", nil)
+	req = req.WithContext(middleware.WithIdentity(req.Context(), middleware.Identity{Subject: "admin:support", Method: middleware.AuthMethodAdminKey}))
+	w := httptest.NewRecorder()
+
+	h.Generations(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp CouponGenerationsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2026-03-01", resp.From.Format(time.DateOnly))
+	assert.Equal(t, "2026-03-01", resp.To.Format(time.DateOnly))
+	require.Len(t, resp.Generations, 1)
+	assert.Equal(t, "api-1", resp.Generations[0].Instance)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"mini-kart/internal/coupon"
+
+	"github.com/rs/zerolog"
+)
+
+// CouponReloader reloads the coupon data loaded by the validator.
+type CouponReloader interface {
+	CouponStatusReporter
+	Reload(ctx context.Context) error
+}
+
+// CouponReloadStatus reports the coupon data loaded by the validator and
+// whether a reload is running.
+type CouponReloadStatus struct {
+	Reloading bool          `json:"reloading"`
+	Coupons   coupon.Status `json:"coupons"`
+}
+
+// CouponReloadHandler lets admins reload the coupon files without
+// restarting the service, such as after uploading a corrected file.
+type CouponReloadHandler struct {
+	coupons   CouponReloader
+	reloading atomic.Bool
+	logger    zerolog.Logger
+}
+
+// NewCouponReloadHandler creates a new coupon reload handler.
+func NewCouponReloadHandler(coupons CouponReloader, logger zerolog.Logger) *CouponReloadHandler {
+	return &CouponReloadHandler{
+		coupons: coupons,
+		logger:  logger.With().Str("handler", "coupon_reload").Logger(),
+	}
+}
+
+// Status handles GET /api/admin/coupons requests.
+func (h *CouponReloadHandler) Status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.status())
+}
+
+// Reload handles POST /api/admin/coupons/reload requests. Loading the
+// coupon files can outlast the request, so it runs in the background and the
+// request is answered with 202 Accepted; the previous coupon data keeps
+// serving validations until it completes. Only one reload runs at a time.
+func (h *CouponReloadHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	if !h.reloading.CompareAndSwap(false, true) {
+		writeError(w, http.StatusConflict, "a coupon reload is already running", h.logger)
+		return
+	}
+
+	caller, _ := adminFromRequest(r)
+	h.logger.Warn().Str("caller", caller).Msg("coupon reload requested")
+
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		defer h.reloading.Store(false)
+
+		start := time.Now()
+		if err := h.coupons.Reload(ctx); err != nil {
+			h.logger.Error().Err(err).Str("caller", caller).Msg("coupon reload failed, previous coupon data still in use")
+			return
+		}
+		h.logger.Info().Str("caller", caller).Dur("duration", time.Since(start)).Msg("coupon reload completed")
+	}()
+
+	writeJSON(w, http.StatusAccepted, h.status())
+}
+
+// status reports the loaded coupon data.
+func (h *CouponReloadHandler) status() CouponReloadStatus {
+	return CouponReloadStatus{
+		Reloading: h.reloading.Load(),
+		Coupons:   h.coupons.Status(),
+	}
+}
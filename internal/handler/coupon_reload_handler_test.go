@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/coupon"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCouponReloader reports a fixed status and signals each reload on
+// started, blocking it until release is closed.
+type stubCouponReloader struct {
+	stubCouponStatus
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (s *stubCouponReloader) Reload(ctx context.Context) error {
+	s.started <- struct{}{}
+	<-s.release
+	return s.err
+}
+
+func newStubCouponReloader(err error) *stubCouponReloader {
+	return &stubCouponReloader{
+		stubCouponStatus: stubCouponStatus{SetType: "map", TotalCoupons: 3},
+		started:          make(chan struct{}, 1),
+		release:          make(chan struct{}),
+		err:              err,
+	}
+}
+
+func TestCouponReloadHandler_Status(t *testing.T) {
+	h := NewCouponReloadHandler(newStubCouponReloader(nil), zerolog.Nop())
+
+	w := httptest.NewRecorder()
+	h.Status(w, httptest.NewRequest(http.MethodGet, "/api/admin/coupons", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var status CouponReloadStatus
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.False(t, status.Reloading)
+	assert.Equal(t, coupon.Status{SetType: "map", TotalCoupons: 3}, status.Coupons)
+
+	w = httptest.NewRecorder()
+	h.Status(w, httptest.NewRequest(http.MethodPost, "/api/admin/coupons", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestCouponReloadHandler_Reload(t *testing.T) {
+	for _, reloadErr := range []error{nil, errors.New("coupon file not found")} {
+		reloader := newStubCouponReloader(reloadErr)
+		h := NewCouponReloadHandler(reloader, zerolog.Nop())
+
+		w := httptest.NewRecorder()
+		h.Reload(w, httptest.NewRequest(http.MethodPost, "/api/admin/coupons/reload", nil))
+		require.Equal(t, http.StatusAccepted, w.Code)
+		<-reloader.started
+
+		// A second reload is refused while the first runs
+		w = httptest.NewRecorder()
+		h.Reload(w, httptest.NewRequest(http.MethodPost, "/api/admin/coupons/reload", nil))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.True(t, h.status().Reloading)
+
+		// Once it completes, successfully or not, reloads are accepted again
+		close(reloader.release)
+		assert.Eventually(t, func() bool { return !h.status().Reloading }, time.Second, time.Millisecond)
+	}
+
+	h := NewCouponReloadHandler(newStubCouponReloader(nil), zerolog.Nop())
+	w := httptest.NewRecorder()
+	h.Reload(w, httptest.NewRequest(http.MethodGet, "/api/admin/coupons/reload", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// AcceptCurrencyHeader asks for prices in an ISO 4217 currency other than the
+// catalogue currency. The currency query parameter takes precedence over it.
+const AcceptCurrencyHeader = "Accept-Currency"
+
+// priceConversion is the conversion of catalogue prices a request asked for.
+// The zero value converts nothing.
+type priceConversion struct {
+	currency string
+	rate     float64
+}
+
+// requested reports whether prices are to be converted.
+func (c priceConversion) requested() bool {
+	return c.currency != ""
+}
+
+// convertPrices reads the currency a request asks prices to be shown in and
+// looks up its rate. Requests naming no currency, and every request when
+// currencies is nil, get the zero conversion. Reports false when an error
+// response was written.
+func convertPrices(w http.ResponseWriter, r *http.Request, currencies service.CurrencyService, logger zerolog.Logger) (priceConversion, bool) {
+	if currencies == nil {
+		return priceConversion{}, true
+	}
+	w.Header().Add("Vary", AcceptCurrencyHeader)
+
+	code := r.URL.Query().Get("currency")
+	if code == "" {
+		code = r.Header.Get(AcceptCurrencyHeader)
+	}
+	code = model.NormalizeCurrency(code)
+	if code == "" {
+		return priceConversion{}, true
+	}
+
+	rate, err := currencies.Rate(r.Context(), code)
+	if err != nil {
+		switch err {
+		case model.ErrUnsupportedCurrency:
+			writeError(w, http.StatusBadRequest, "unsupported currency", logger)
+		case model.ErrRatesUnavailable:
+			writeError(w, http.StatusServiceUnavailable, "exchange rates are temporarily unavailable", logger)
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to convert prices", logger)
+		}
+		return priceConversion{}, false
+	}
+
+	return priceConversion{currency: code, rate: rate}, true
+}
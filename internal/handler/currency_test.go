@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCurrencyService is a mock implementation of CurrencyService.
+type MockCurrencyService struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyService) Rate(ctx context.Context, code string) (float64, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func TestProductHandler_GetByID_Currency(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		path           string
+		header         string
+		rate           float64
+		rateErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Query parameter",
+			path:           "/api/products/P001?currency=usd",
+			rate:           0.66,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"price":8.25,"category":"Cat1","currency":"USD"`,
+		},
+		{
+			name:           "Accept-Currency header",
+			path:           "/api/products/P001",
+			header:         "USD",
+			rate:           0.66,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"currency":"USD"`,
+		},
+		{
+			name:           "Query parameter takes precedence",
+			path:           "/api/products/P001?currency=USD",
+			header:         "EUR",
+			rate:           0.66,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"currency":"USD"`,
+		},
+		{
+			name:           "Unsupported currency",
+			path:           "/api/products/P001?currency=USD",
+			rateErr:        model.ErrUnsupportedCurrency,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unsupported currency",
+		},
+		{
+			name:           "Rates unavailable",
+			path:           "/api/products/P001?currency=USD",
+			rateErr:        model.ErrRatesUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "exchange rates are temporarily unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			mockCurrencies := new(MockCurrencyService)
+			product := &model.Product{ID: "P001", Name: "Product 1", Price: model.NewMoney(12.5), Category: "Cat1", Currency: "AUD", EffectivePrice: model.NewMoney(12.5)}
+			mockService.On("GetByID", mock.Anything, "P001").Return(product, nil).Maybe()
+			mockCurrencies.On("Rate", mock.Anything, "USD").Return(tt.rate, tt.rateErr)
+
+			h := NewProductHandler(mockService, logger, WithProductCurrencies(mockCurrencies))
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(AcceptCurrencyHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			h.GetByID(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			assert.Equal(t, AcceptCurrencyHeader, w.Header().Get("Vary"))
+			if tt.rateErr != nil {
+				mockService.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+
+	t.Run("Ignores currency without conversion enabled", func(t *testing.T) {
+		mockService := new(MockProductService)
+		product := &model.Product{ID: "P001", Name: "Product 1", Price: model.NewMoney(12.5), Category: "Cat1", Currency: "AUD"}
+		mockService.On("GetByID", mock.Anything, "P001").Return(product, nil)
+
+		h := NewProductHandler(mockService, logger)
+		req := httptest.NewRequest(http.MethodGet, "/api/products/P001?currency=USD", nil)
+		w := httptest.NewRecorder()
+
+		h.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"price":12.5`)
+		assert.Contains(t, w.Body.String(), `"currency":"AUD"`)
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+}
+
+func TestProductHandler_GetAll_Currency(t *testing.T) {
+	mockService := new(MockProductService)
+	mockCurrencies := new(MockCurrencyService)
+	products := []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(1000), Category: "Cat1", EffectivePrice: model.NewMoney(1000)},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(250.5), Category: "Cat1", EffectivePrice: model.NewMoney(200), OnSale: true},
+	}
+	mockService.On("GetAll", mock.Anything, mock.Anything).Return(products, model.Page{Limit: 10, Total: 2}, nil)
+	mockCurrencies.On("Rate", mock.Anything, "JPY").Return(96.123, nil)
+
+	h := NewProductHandler(mockService, zerolog.Nop(), WithProductCurrencies(mockCurrencies))
+	req := httptest.NewRequest(http.MethodGet, "/api/products?currency=JPY", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAll(w, req)
+
+	// Yen have no minor unit, so converted prices are whole
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"price":96123,"category":"Cat1","currency":"JPY","effectivePrice":96123`)
+	assert.Contains(t, w.Body.String(), `"price":24079,"category":"Cat1","currency":"JPY","effectivePrice":19225`)
+}
+
+func TestOrderHandler_Currency(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	subtotal, discount, total := model.NewMoney(20), model.NewMoney(2), model.NewMoney(18)
+
+	t.Run("Get converts items, products and totals", func(t *testing.T) {
+		mockService := new(MockOrderService)
+		mockCurrencies := new(MockCurrencyService)
+		mockService.On("GetByID", mock.Anything, orderID).Return(&model.OrderResponse{
+			ID: orderID,
+			Items: []model.OrderItem{
+				{ID: uuid.New(), ProductID: "P001", Quantity: 2, Product: &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(10)}},
+			},
+			Products: []model.Product{{ID: "P001", Name: "Product 1", Price: model.NewMoney(10), Category: "Cat1", EffectivePrice: model.NewMoney(10)}},
+			Subtotal: &subtotal,
+			Discount: &discount,
+			Total:    &total,
+		}, nil)
+		mockCurrencies.On("Rate", mock.Anything, "EUR").Return(0.6, nil)
+
+		h := NewOrderHandler(mockService, logger, WithOrderCurrencies(mockCurrencies))
+		req := httptest.NewRequest(http.MethodGet, "/api/orders/"+orderID.String(), nil)
+		req.Header.Set(AcceptCurrencyHeader, "EUR")
+		w := httptest.NewRecorder()
+
+		h.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `"product":{"name":"Product 1","category":"Cat1","price":6}`)
+		assert.Contains(t, body, `"price":6,"category":"Cat1","currency":"EUR"`)
+		assert.Contains(t, body, `"subtotal":12,"discount":1.2,"total":10.8`)
+		assert.Contains(t, body, `"currency":"EUR"}`)
+	})
+
+	t.Run("List converts totals", func(t *testing.T) {
+		mockService := new(MockOrderService)
+		mockCurrencies := new(MockCurrencyService)
+		mockService.On("List", mock.Anything, mock.Anything).
+			Return([]model.Order{{ID: orderID, Status: model.OrderStatusPending, Total: &total}}, model.Page{Limit: 10, Total: 1}, nil)
+		mockCurrencies.On("Rate", mock.Anything, "EUR").Return(0.6, nil)
+
+		h := NewOrderHandler(mockService, logger, WithOrderCurrencies(mockCurrencies))
+		req := httptest.NewRequest(http.MethodGet, "/api/orders?currency=EUR", nil)
+		w := httptest.NewRecorder()
+
+		h.List(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":10.8`)
+		assert.Contains(t, w.Body.String(), `"currency":"EUR"`)
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// CustomerHandler handles customer registration, lookup and order history
+// HTTP requests.
+type CustomerHandler struct {
+	service service.CustomerService
+	logger  zerolog.Logger
+}
+
+// NewCustomerHandler creates a new customer handler.
+func NewCustomerHandler(service service.CustomerService, logger zerolog.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "customer").Logger(),
+	}
+}
+
+// Customers handles POST /api/customers requests, registering a customer,
+// and GET /api/customers?email= requests, looking one up by email.
+func (h *CustomerHandler) Customers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeError(w, http.StatusBadRequest, "email parameter is required", h.logger)
+			return
+		}
+
+		customer, err := h.service.FindCustomer(r.Context(), email)
+		if err != nil {
+			h.writeCustomerError(w, err, "failed to retrieve customer")
+			return
+		}
+		writeJSON(w, http.StatusOK, customer)
+	case http.MethodPost:
+		var req model.CustomerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		customer, err := h.service.RegisterCustomer(r.Context(), &req)
+		if err != nil {
+			h.writeCustomerError(w, err, "failed to register customer")
+			return
+		}
+		writeJSON(w, http.StatusCreated, customer)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// Customer handles GET /api/customers/{id} requests.
+func (h *CustomerHandler) Customer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	customerID, ok := h.customerID(w, r, "")
+	if !ok {
+		return
+	}
+
+	customer, err := h.service.GetCustomer(r.Context(), customerID)
+	if err != nil {
+		h.writeCustomerError(w, err, "failed to retrieve customer")
+		return
+	}
+	writeJSON(w, http.StatusOK, customer)
+}
+
+// Orders handles GET /api/customers/{id}/orders requests, listing the
+// customer's orders newest first with the same pagination as GET /api/orders.
+func (h *CustomerHandler) Orders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	customerID, ok := h.customerID(w, r, "/orders")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := model.OrderFilter{Limit: 10} // default
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid limit parameter", h.logger)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid offset parameter", h.logger)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	profile, ok := negotiate(w, r, listProfileVersions, h.logger)
+	if !ok {
+		return
+	}
+
+	orders, page, err := h.service.ListOrders(r.Context(), customerID, filter)
+	if err != nil {
+		h.writeCustomerError(w, err, "failed to retrieve orders")
+		return
+	}
+
+	writeList(w, r, profile, page, orders)
+}
+
+// customerID extracts the customer ID from /api/customers/{id}{suffix},
+// writing an error response if it is missing or malformed.
+func (h *CustomerHandler) customerID(w http.ResponseWriter, r *http.Request, suffix string) (uuid.UUID, bool) {
+	customerIDStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/customers/"), suffix)
+	if customerIDStr == "" || strings.Contains(customerIDStr, "/") {
+		writeError(w, http.StatusBadRequest, "customer ID is required", h.logger)
+		return uuid.Nil, false
+	}
+
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid customer ID format", h.logger)
+		return uuid.Nil, false
+	}
+
+	return customerID, true
+}
+
+// writeCustomerError maps customer domain errors to HTTP responses.
+func (h *CustomerHandler) writeCustomerError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case model.ErrInvalidCustomer:
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+	case model.ErrCustomerNotFound:
+		writeError(w, http.StatusNotFound, "customer not found", h.logger)
+	case model.ErrCustomerExists:
+		writeError(w, http.StatusConflict, "a customer with this email is already registered", h.logger)
+	default:
+		if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, fallback, h.logger)
+	}
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCustomerService is a mock implementation of CustomerService.
+type MockCustomerService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerService) RegisterCustomer(ctx context.Context, req *model.CustomerRequest) (*model.Customer, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) GetCustomer(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) FindCustomer(ctx context.Context, email string) (*model.Customer, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) ListOrders(ctx context.Context, id uuid.UUID, filter model.OrderFilter) ([]model.Order, model.Page, error) {
+	args := m.Called(ctx, id, filter)
+	if args.Get(0) == nil {
+		return nil, model.Page{}, args.Error(2)
+	}
+	return args.Get(0).([]model.Order), args.Get(1).(model.Page), args.Error(2)
+}
+
+func TestCustomerHandler(t *testing.T) {
+	ada := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}
+	adaPath := "/api/customers/" + ada.ID.String()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		accept         string
+		setupMock      func(*MockCustomerService)
+		expectedStatus int
+		expectedTotal  string
+	}{
+		{
+			name:   "Register customer",
+			method: http.MethodPost,
+			path:   "/api/customers",
+			body:   `{"email":"ada@example.com","name":"Ada"}`,
+			setupMock: func(m *MockCustomerService) {
+				m.On("RegisterCustomer", mock.Anything, &model.CustomerRequest{Email: "ada@example.com", Name: "Ada"}).Return(ada, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Register existing email",
+			method: http.MethodPost,
+			path:   "/api/customers",
+			body:   `{"email":"ada@example.com","name":"Ada"}`,
+			setupMock: func(m *MockCustomerService) {
+				m.On("RegisterCustomer", mock.Anything, mock.Anything).Return(nil, model.ErrCustomerExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Register invalid customer",
+			method: http.MethodPost,
+			path:   "/api/customers",
+			body:   `{"email":"ada","name":"Ada"}`,
+			setupMock: func(m *MockCustomerService) {
+				m.On("RegisterCustomer", mock.Anything, mock.Anything).Return(nil, model.ErrInvalidCustomer)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           "/api/customers",
+			body:           `{`,
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Find customer by email",
+			method: http.MethodGet,
+			path:   "/api/customers?email=ada@example.com",
+			setupMock: func(m *MockCustomerService) {
+				m.On("FindCustomer", mock.Anything, "ada@example.com").Return(ada, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Find customer without email",
+			method:         http.MethodGet,
+			path:           "/api/customers",
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Get customer",
+			method: http.MethodGet,
+			path:   adaPath,
+			setupMock: func(m *MockCustomerService) {
+				m.On("GetCustomer", mock.Anything, ada.ID).Return(ada, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Get unknown customer",
+			method: http.MethodGet,
+			path:   adaPath,
+			setupMock: func(m *MockCustomerService) {
+				m.On("GetCustomer", mock.Anything, ada.ID).Return(nil, model.ErrCustomerNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Malformed customer ID",
+			method:         http.MethodGet,
+			path:           "/api/customers/ada",
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "List customer orders",
+			method: http.MethodGet,
+			path:   adaPath + "/orders?limit=5&offset=5",
+			setupMock: func(m *MockCustomerService) {
+				m.On("ListOrders", mock.Anything, ada.ID, model.OrderFilter{Limit: 5, Offset: 5}).
+					Return([]model.Order{{ID: uuid.New(), CustomerID: &ada.ID}}, model.Page{Limit: 5, Offset: 5, Total: 6}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTotal:  "6",
+		},
+		{
+			name:   "List unknown customer's orders",
+			method: http.MethodGet,
+			path:   adaPath + "/orders",
+			setupMock: func(m *MockCustomerService) {
+				m.On("ListOrders", mock.Anything, ada.ID, model.OrderFilter{Limit: 10}).Return(nil, model.Page{}, model.ErrCustomerNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unsupported profile",
+			method:         http.MethodGet,
+			path:           adaPath + "/orders",
+			accept:         "application/vnd.minikart.v7+json",
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "Invalid limit",
+			method:         http.MethodGet,
+			path:           adaPath + "/orders?limit=ten",
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           adaPath,
+			setupMock:      func(m *MockCustomerService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockCustomerService)
+			tt.setupMock(svc)
+			h := NewCustomerHandler(svc, zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			switch {
+			case req.URL.Path == "/api/customers":
+				h.Customers(w, req)
+			case strings.HasSuffix(req.URL.Path, "/orders"):
+				h.Orders(w, req)
+			default:
+				h.Customer(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedTotal != "" {
+				assert.Equal(t, tt.expectedTotal, w.Header().Get("X-Total-Count"))
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+}
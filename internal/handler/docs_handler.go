@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-kart/internal/openapi"
+
+	"github.com/rs/zerolog"
+)
+
+// swaggerUIVersion pins the Swagger UI release the docs page loads.
+const swaggerUIVersion = "5.17.14"
+
+// swaggerUIPage renders Swagger UI for the OpenAPI document at /openapi.json.
+// Swagger UI is loaded from a CDN rather than served by the API.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>mini-kart API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves the API's OpenAPI document and a Swagger UI page for it.
+type DocsHandler struct {
+	document *openapi.Document
+	logger   zerolog.Logger
+}
+
+// NewDocsHandler creates a new API documentation handler.
+func NewDocsHandler(document *openapi.Document, logger zerolog.Logger) *DocsHandler {
+	return &DocsHandler{
+		document: document,
+		logger:   logger.With().Str("handler", "docs").Logger(),
+	}
+}
+
+// Spec handles GET /openapi.json requests.
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.document)
+}
+
+// UI handles GET /docs requests by serving the Swagger UI page.
+func (h *DocsHandler) UI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	header.Set("Content-Security-Policy",
+		"default-src 'self'; script-src 'unsafe-inline' https://unpkg.com; style-src https://unpkg.com; img-src 'self' data:")
+	header.Set("X-Frame-Options", "DENY")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write([]byte(swaggerUIPage))
+	}
+}
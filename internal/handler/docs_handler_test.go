@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/openapi"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsHandler(t *testing.T) {
+	document := openapi.Generate(openapi.Info{Title: "test", Version: "1.0.0"}, []openapi.Route{
+		{Method: http.MethodGet, Path: "/api/v1/products", Operation: "listProducts", Responses: map[int]any{http.StatusOK: nil}},
+	}, ErrorResponse{})
+
+	h := NewDocsHandler(document, zerolog.Nop())
+
+	t.Run("Spec returns the document", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		w := httptest.NewRecorder()
+
+		h.Spec(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var got openapi.Document
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		assert.Equal(t, openapi.Version, got.OpenAPI)
+		assert.Equal(t, "listProducts", got.Paths["/api/v1/products"]["get"].OperationID)
+	})
+
+	t.Run("UI serves the Swagger UI page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+		w := httptest.NewRecorder()
+
+		h.UI(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+		assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		for _, serve := range []http.HandlerFunc{h.Spec, h.UI} {
+			req := httptest.NewRequest(http.MethodPost, "/docs", nil)
+			w := httptest.NewRecorder()
+
+			serve(w, req)
+
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// FlashSaleHandler handles flash sale management HTTP requests.
+type FlashSaleHandler struct {
+	service service.FlashSaleService
+	logger  zerolog.Logger
+}
+
+// NewFlashSaleHandler creates a new flash sale handler.
+func NewFlashSaleHandler(service service.FlashSaleService, logger zerolog.Logger) *FlashSaleHandler {
+	return &FlashSaleHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "flash_sale").Logger(),
+	}
+}
+
+// FlashSales handles GET /api/admin/flash-sales requests.
+func (h *FlashSaleHandler) FlashSales(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	flashSales, err := h.service.ListFlashSales(r.Context())
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve flash sales", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, flashSales)
+}
+
+// FlashSale handles PUT and DELETE /api/admin/flash-sales/{productId}
+// requests. PUT allocates quantity of the product to its flash sale, keeping
+// what was already sold; DELETE ends the sale, lifting the limit on orders.
+func (h *FlashSaleHandler) FlashSale(w http.ResponseWriter, r *http.Request) {
+	productID := strings.TrimPrefix(r.URL.Path, "/api/admin/flash-sales/")
+	if productID == "" || strings.Contains(productID, "/") {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	actor, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var req model.FlashSaleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		flashSale, err := h.service.SetFlashSale(r.Context(), productID, &req, actor)
+		if err != nil {
+			h.writeFlashSaleError(w, err, "failed to set flash sale")
+			return
+		}
+		writeJSON(w, http.StatusOK, flashSale)
+	case http.MethodDelete:
+		if err := h.service.EndFlashSale(r.Context(), productID, actor); err != nil {
+			h.writeFlashSaleError(w, err, "failed to end flash sale")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// writeFlashSaleError maps flash sale domain errors to HTTP responses.
+func (h *FlashSaleHandler) writeFlashSaleError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case model.ErrInvalidFlashSale:
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+	case model.ErrProductNotFound:
+		writeError(w, http.StatusNotFound, "product not found", h.logger)
+	case model.ErrFlashSaleNotFound:
+		writeError(w, http.StatusNotFound, "product is not on a flash sale", h.logger)
+	default:
+		if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, fallback, h.logger)
+	}
+}
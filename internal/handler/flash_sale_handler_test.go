@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFlashSaleService is a mock implementation of FlashSaleService.
+type MockFlashSaleService struct {
+	mock.Mock
+}
+
+func (m *MockFlashSaleService) ListFlashSales(ctx context.Context) ([]model.FlashSale, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleService) SetFlashSale(ctx context.Context, productID string, req *model.FlashSaleRequest, actor string) (*model.FlashSale, error) {
+	args := m.Called(ctx, productID, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleService) EndFlashSale(ctx context.Context, productID, actor string) error {
+	args := m.Called(ctx, productID, actor)
+	return args.Error(0)
+}
+
+func (m *MockFlashSaleService) Reconcile(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func TestFlashSaleHandler_FlashSales(t *testing.T) {
+	logger := zerolog.Nop()
+
+	mockService := new(MockFlashSaleService)
+	mockService.On("ListFlashSales", mock.Anything).Return([]model.FlashSale{
+		{ProductID: "P001", Quantity: 100, Sold: 40, Pending: 10},
+	}, nil)
+
+	h := NewFlashSaleHandler(mockService, logger)
+	w := httptest.NewRecorder()
+	h.FlashSales(w, httptest.NewRequest(http.MethodGet, "/api/admin/flash-sales", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body []map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "P001", body[0]["productId"])
+	assert.Equal(t, 10.0, body[0]["pending"])
+
+	w = httptest.NewRecorder()
+	h.FlashSales(w, httptest.NewRequest(http.MethodPost, "/api/admin/flash-sales", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestFlashSaleHandler_FlashSale(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		admin          string
+		mockReturn     *model.FlashSale
+		mockError      error
+		expectSet      bool
+		expectEnd      bool
+		expectedStatus int
+	}{
+		{
+			name:           "Set flash sale",
+			method:         http.MethodPut,
+			path:           "/api/admin/flash-sales/P001",
+			body:           `{"quantity": 100}`,
+			admin:          "admin-1",
+			mockReturn:     &model.FlashSale{ProductID: "P001", Quantity: 100},
+			expectSet:      true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid quantity",
+			method:         http.MethodPut,
+			path:           "/api/admin/flash-sales/P001",
+			body:           `{"quantity": -1}`,
+			admin:          "admin-1",
+			mockError:      model.ErrInvalidFlashSale,
+			expectSet:      true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown product",
+			method:         http.MethodPut,
+			path:           "/api/admin/flash-sales/P001",
+			body:           `{"quantity": 100}`,
+			admin:          "admin-1",
+			mockError:      model.ErrProductNotFound,
+			expectSet:      true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid body",
+			method:         http.MethodPut,
+			path:           "/api/admin/flash-sales/P001",
+			body:           `{`,
+			admin:          "admin-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "End flash sale",
+			method:         http.MethodDelete,
+			path:           "/api/admin/flash-sales/P001",
+			admin:          "admin-1",
+			expectEnd:      true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Not on a flash sale",
+			method:         http.MethodDelete,
+			path:           "/api/admin/flash-sales/P001",
+			admin:          "admin-1",
+			mockError:      model.ErrFlashSaleNotFound,
+			expectEnd:      true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodDelete,
+			path:           "/api/admin/flash-sales/P001",
+			admin:          "admin-1",
+			mockError:      errors.New("database error"),
+			expectEnd:      true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing admin identity",
+			method:         http.MethodDelete,
+			path:           "/api/admin/flash-sales/P001",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing product ID",
+			method:         http.MethodDelete,
+			path:           "/api/admin/flash-sales/",
+			admin:          "admin-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/admin/flash-sales/P001",
+			admin:          "admin-1",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFlashSaleService)
+			if tt.expectSet {
+				mockService.On("SetFlashSale", mock.Anything, "P001", mock.AnythingOfType("*model.FlashSaleRequest"), tt.admin).
+					Return(tt.mockReturn, tt.mockError)
+			}
+			if tt.expectEnd {
+				mockService.On("EndFlashSale", mock.Anything, "P001", tt.admin).Return(tt.mockError)
+			}
+
+			h := NewFlashSaleHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			w := httptest.NewRecorder()
+
+			h.FlashSale(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectSet {
+				mockService.AssertNotCalled(t, "SetFlashSale", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if !tt.expectEnd {
+				mockService.AssertNotCalled(t, "EndFlashSale", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateGolden rewrites the golden files with the responses handlers send,
+// e.g. go test ./internal/handler -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite golden response files")
+
+// goldenDir holds the golden files, one directory per test.
+const goldenDir = "testdata/golden"
+
+// goldenUUID matches UUIDs anywhere in a string.
+var goldenUUID = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// goldenCase is one request of a golden response test.
+type goldenCase struct {
+	name   string
+	method string
+	target string
+	body   string
+	header http.Header
+
+	// serve returns the handler under test, with any service mocks the
+	// request needs set up.
+	serve func(t *testing.T) http.HandlerFunc
+}
+
+// goldenResponse is a response as snapshotted in a golden file.
+type goldenResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   any               `json:"body,omitempty"`
+}
+
+// runGolden serves each case and compares the response, with IDs and
+// timestamps normalized, to the case's golden file. Run with -update to
+// write the golden files after an intended change to a response.
+func runGolden(t *testing.T, cases []goldenCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, tc.target, body)
+			for name, values := range tc.header {
+				req.Header[name] = values
+			}
+			w := httptest.NewRecorder()
+
+			tc.serve(t)(w, req)
+
+			got := snapshot(t, w)
+			path := filepath.Join(goldenDir, goldenName(t.Name())+".json")
+
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "missing golden file, run the test with -update to create it")
+			assert.Equal(t, string(want), string(got), "response differs from %s", path)
+		})
+	}
+}
+
+// snapshot renders a recorded response as golden file contents.
+func snapshot(t *testing.T, w *httptest.ResponseRecorder) []byte {
+	t.Helper()
+
+	n := &goldenNormalizer{ids: make(map[string]string)}
+	resp := goldenResponse{Status: w.Code}
+
+	// Normalize the body before the headers, so IDs are numbered in the order
+	// a reader meets them
+	if w.Body.Len() > 0 {
+		if contentType := w.Header().Get("Content-Type"); strings.HasPrefix(contentType, "application/json") || strings.HasSuffix(contentType, "+json") {
+			decoder := json.NewDecoder(bytes.NewReader(w.Body.Bytes()))
+			decoder.UseNumber()
+			var body any
+			require.NoError(t, decoder.Decode(&body), "response body is not JSON")
+			resp.Body = n.value(body)
+		} else {
+			resp.Body = n.string(w.Body.String())
+		}
+	}
+
+	names := make([]string, 0, len(w.Header()))
+	for name := range w.Header() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if resp.Header == nil {
+			resp.Header = make(map[string]string)
+		}
+		resp.Header[name] = n.string(strings.Join(w.Header().Values(name), ", "))
+	}
+
+	// Keep the placeholders readable rather than escaping their angle brackets
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	require.NoError(t, encoder.Encode(resp))
+	return data.Bytes()
+}
+
+// goldenNormalizer replaces values that differ between runs with stable
+// placeholders. Each distinct UUID gets its own numbered placeholder, so a
+// snapshot still shows which IDs refer to the same thing.
+type goldenNormalizer struct {
+	ids map[string]string
+}
+
+// value normalizes a decoded JSON value. Object keys are visited in sorted
+// order, so placeholders are numbered the same way on every run.
+func (n *goldenNormalizer) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v[key] = n.value(v[key])
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = n.value(v[i])
+		}
+		return v
+	case string:
+		return n.string(v)
+	default:
+		return v
+	}
+}
+
+// string normalizes timestamps and UUIDs in s.
+func (n *goldenNormalizer) string(s string) string {
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return "<timestamp>"
+	}
+	if _, err := http.ParseTime(s); err == nil {
+		return "<timestamp>"
+	}
+	return goldenUUID.ReplaceAllStringFunc(s, func(id string) string {
+		id = strings.ToLower(id)
+		placeholder, ok := n.ids[id]
+		if !ok {
+			placeholder = fmt.Sprintf("<uuid-%d>", len(n.ids)+1)
+			n.ids[id] = placeholder
+		}
+		return placeholder
+	})
+}
+
+// goldenName turns a test name into a golden file path, e.g.
+// "TestOrderHandler_Golden/Get order" into "TestOrderHandler_Golden/get_order".
+func goldenName(name string) string {
+	test, sub, _ := strings.Cut(name, "/")
+	sub = strings.ToLower(sub)
+	sub = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '/':
+			return r
+		default:
+			return '_'
+		}
+	}, sub)
+	return filepath.Join(test, sub)
+}
+
+func TestGoldenNormalizer(t *testing.T) {
+	n := &goldenNormalizer{ids: make(map[string]string)}
+
+	got := n.value(map[string]any{
+		"id":        "6F9619FF-8B86-D011-B42D-00C04FC964FF",
+		"createdAt": "2026-10-16T09:30:00.123456789+10:00",
+		"items": []any{
+			map[string]any{"orderId": "6f9619ff-8b86-d011-b42d-00c04fc964ff", "productId": "P001"},
+			map[string]any{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "quantity": json.Number("2")},
+		},
+		"location": "/api/orders/1b4e28ba-2fa1-11d2-883f-0016d3cca427/status",
+	})
+
+	assert.Equal(t, map[string]any{
+		"id":        "<uuid-1>",
+		"createdAt": "<timestamp>",
+		"items": []any{
+			map[string]any{"orderId": "<uuid-1>", "productId": "P001"},
+			map[string]any{"id": "<uuid-2>", "quantity": json.Number("2")},
+		},
+		"location": "/api/orders/<uuid-2>/status",
+	}, got)
+	assert.Equal(t, "<timestamp>", n.string("Fri, 16 Oct 2026 09:30:00 GMT"))
+	assert.Equal(t, "not a timestamp", n.string("not a timestamp"))
+}
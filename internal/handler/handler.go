@@ -1,29 +1,258 @@
 package handler
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
-// ErrorResponse represents an error response.
+// ErrorResponse represents an error response. CorrelationID echoes the
+// request's X-Request-ID so clients can quote it when reporting problems.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error         string `json:"error"`
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// BatchResponse is the response of batch endpoints, which process each entry
+// independently so some may succeed while others fail. Entries lists the
+// failed entries; every entry not listed succeeded.
+type BatchResponse struct {
+	Succeeded int64        `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Entries   []BatchEntry `json:"entries"`
+}
+
+// BatchEntry reports a failed batch entry with the status and error code it
+// would have received as a request of its own. Index locates the entry in
+// the request: its position in a JSON array, or its line in a CSV upload.
+type BatchEntry struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Error  string `json:"error"`
+}
+
+// writeBatch writes a batch response: 200 OK when every entry succeeded and
+// 207 Multi-Status when any failed, so clients know to read the entries.
+func writeBatch(w http.ResponseWriter, succeeded int64, failed []BatchEntry) {
+	if failed == nil {
+		failed = []BatchEntry{}
+	}
+
+	status := http.StatusOK
+	if len(failed) > 0 {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, BatchResponse{Succeeded: succeeded, Failed: len(failed), Entries: failed})
+}
+
+// batchEntryStatus returns the HTTP status for a batch entry refused with
+// the given error code.
+func batchEntryStatus(code string) int {
+	switch code {
+	case model.ErrCodeProductExists, model.ErrCodeProductInUse, model.ErrCodeOrderImported:
+		return http.StatusConflict
+	case model.ErrCodeProductNotFound, model.ErrCodeOrderNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// formFile returns the part of a multipart/form-data body named field, or
+// nil if the body is not multipart or has no such part. The part is read
+// straight from the body, so uploads are never buffered.
+func formFile(r *http.Request, field string) io.Reader {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil
+		}
+		if part.FormName() == field {
+			return part
+		}
+	}
 }
 
 // writeJSON writes a JSON response with the given status code.
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	writeJSONAs(w, status, "application/json", data)
+}
+
+// writeJSONAs writes a JSON response labelled with the given media type,
+// such as a negotiated profile.
+// The body is encoded into a pooled buffer before anything is written, so a
+// value that cannot be encoded becomes a 500 rather than a truncated body.
+func writeJSONAs(w http.ResponseWriter, status int, contentType string, data interface{}) {
+	buf := responseBuffers.Get().(*bytes.Buffer)
+	defer putResponseBuffer(buf)
+
+	if err := encodeJSON(buf, data); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, `{"error":"failed to encode response"}`+"\n")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		// Log the error but don't expose it to the client
+	_, _ = w.Write(buf.Bytes())
+}
+
+// maxPooledResponseBuffer caps the buffers returned to responseBuffers, so
+// one large export does not pin its memory for the life of the process.
+const maxPooledResponseBuffer = 1 << 20
+
+// responseBuffers pools the buffers responses are encoded into, saving a
+// buffer allocation and its regrowth on every request.
+var responseBuffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// putResponseBuffer returns buf to responseBuffers unless it grew too large.
+func putResponseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledResponseBuffer {
 		return
 	}
+	buf.Reset()
+	responseBuffers.Put(buf)
 }
 
 // writeError writes an error response with the given status code and message.
+// The correlation ID is read back from the X-Request-ID response header set by
+// middleware.RequestID, so handlers need not pass the request through.
 func writeError(w http.ResponseWriter, status int, message string, logger zerolog.Logger) {
-	logger.Error().Str("error", message).Int("status", status).Msg("handler error")
-	writeJSON(w, status, ErrorResponse{Error: message})
+	requestID := w.Header().Get(middleware.RequestIDHeader)
+	logger.Error().Str("request_id", requestID).Str("error", message).Int("status", status).Msg("handler error")
+	writeJSON(w, status, ErrorResponse{Error: message, CorrelationID: requestID})
+}
+
+// writeUnavailable writes a 503 response and reports true when err means the
+// database circuit breaker is rejecting reads.
+func writeUnavailable(w http.ResponseWriter, err error, logger zerolog.Logger) bool {
+	if !errors.Is(err, model.ErrDatabaseUnavailable) {
+		return false
+	}
+	writeError(w, http.StatusServiceUnavailable, "service temporarily unavailable", logger)
+	return true
+}
+
+// constraintStatuses maps the domain errors repositories report for
+// database constraint violations to response statuses. A request naming a
+// missing record or carrying a value the schema refuses is the client's to
+// fix; one clashing with existing records conflicts with the current state.
+var constraintStatuses = map[string]int{
+	model.ErrCodeReferenceNotFound:   http.StatusBadRequest,
+	model.ErrCodeProductNotFound:     http.StatusBadRequest,
+	model.ErrCodeCustomerNotFound:    http.StatusBadRequest,
+	model.ErrCodeTenantNotFound:      http.StatusBadRequest,
+	model.ErrCodeOrderItemNotFound:   http.StatusBadRequest,
+	model.ErrCodeConstraintViolation: http.StatusBadRequest,
+	model.ErrCodeInvalidPrice:        http.StatusBadRequest,
+	model.ErrCodeInvalidSale:         http.StatusBadRequest,
+	model.ErrCodeInvalidQuantity:     http.StatusBadRequest,
+	model.ErrCodeInvalidShipment:     http.StatusBadRequest,
+	model.ErrCodeInvalidCouponFile:   http.StatusBadRequest,
+	model.ErrCodeDuplicateRecord:     http.StatusConflict,
+	model.ErrCodeRecordInUse:         http.StatusConflict,
+	model.ErrCodeProductExists:       http.StatusConflict,
+	model.ErrCodeProductInUse:        http.StatusConflict,
+	model.ErrCodeCustomerExists:      http.StatusConflict,
+	model.ErrCodeTenantExists:        http.StatusConflict,
+	model.ErrCodeOrderImported:       http.StatusConflict,
+	model.ErrCodeCouponFileExists:    http.StatusConflict,
+	model.ErrCodeOverFulfillment:     http.StatusConflict,
+	model.ErrCodeImageAttached:       http.StatusConflict,
+}
+
+// writeConstraintViolation writes a 4xx response with the error's message
+// and reports true when err carries the domain error a repository classified
+// a constraint violation as, such as an order item for a product deleted
+// while the order was placed. Write handlers call it before answering 500.
+func writeConstraintViolation(w http.ResponseWriter, err error, logger zerolog.Logger) bool {
+	var domainErr *model.DomainError
+	if !errors.As(err, &domainErr) {
+		return false
+	}
+	status, ok := constraintStatuses[domainErr.Code]
+	if !ok {
+		return false
+	}
+	writeError(w, status, domainErr.Message, logger)
+	return true
+}
+
+// writePageHeaders describes a paginated list response in headers: the total
+// item count in X-Total-Count and the adjacent pages in a Link header with
+// "next" and "prev" relations.
+func writePageHeaders(w http.ResponseWriter, r *http.Request, page model.Page) {
+	header := w.Header()
+	header.Set("X-Total-Count", strconv.Itoa(page.Total))
+
+	// Link to the path the client requested, which may be versioned even
+	// though the router hands handlers the unversioned path
+	requested, err := url.ParseRequestURI(r.RequestURI)
+	if err != nil {
+		requested = r.URL
+	}
+
+	if page.Limit > 0 && page.Offset+page.Limit < page.Total {
+		header.Add("Link", pageLink(requested, page.Limit, page.Offset+page.Limit, "next"))
+	}
+	if page.Offset > 0 {
+		header.Add("Link", pageLink(requested, page.Limit, max(page.Offset-page.Limit, 0), "prev"))
+	}
+
+	header.Add("Access-Control-Expose-Headers", "X-Total-Count, Link")
+}
+
+// pageLink formats a Link header value pointing at the given page of the
+// requested URL, keeping its other query parameters.
+func pageLink(requested *url.URL, limit, offset int, rel string) string {
+	query := requested.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	link := url.URL{Path: requested.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel)
+}
+
+// orderIDFromPath extracts the order ID from /api/orders/{id}{suffix} or
+// /api/admin/orders/{id}{suffix}, writing an error response if it is missing
+// or malformed.
+func orderIDFromPath(w http.ResponseWriter, r *http.Request, suffix string, logger zerolog.Logger) (uuid.UUID, bool) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/api/admin/orders/")
+	if !ok {
+		path = strings.TrimPrefix(r.URL.Path, "/api/orders/")
+	}
+	orderIDStr := strings.TrimSuffix(path, suffix)
+	if orderIDStr == "" || strings.Contains(orderIDStr, "/") {
+		writeError(w, http.StatusBadRequest, "order ID is required", logger)
+		return uuid.Nil, false
+	}
+
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order ID format", logger)
+		return uuid.Nil, false
+	}
+
+	return orderID, true
 }
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/fixtures"
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	t.Run("Writes the body with status and content type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeJSONAs(w, http.StatusCreated, "application/vnd.mini-kart.v2+json", map[string]string{"id": "P001"})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "application/vnd.mini-kart.v2+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":\"P001\"}\n", w.Body.String())
+	})
+
+	t.Run("Unencodable value", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeJSON(w, http.StatusOK, map[string]float64{"price": math.NaN()})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"failed to encode response"}`, w.Body.String())
+	})
+
+	t.Run("Omits zero fields tagged omitzero", func(t *testing.T) {
+		// Holds under every serializer build tag
+		w := httptest.NewRecorder()
+
+		writeJSON(w, http.StatusOK, model.Product{ID: "P001"})
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.NotContains(t, body, "createdAt")
+		assert.Contains(t, body, "effectivePrice")
+	})
+
+	t.Run("Pooled buffers start empty", func(t *testing.T) {
+		for _, id := range []string{"P001", "P2"} {
+			w := httptest.NewRecorder()
+			writeJSON(w, http.StatusOK, map[string]string{"id": id})
+			assert.Equal(t, "{\"id\":\""+id+"\"}\n", w.Body.String())
+		}
+	})
+}
+
+// discardResponseWriter is an http.ResponseWriter that throws the body away,
+// so benchmarks measure encoding rather than a recorder's buffer.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(p []byte) (int, error) { return io.Discard.Write(p) }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+// benchmarkWriteJSON measures writing data as a response. Run with
+// -tags jsoniter to compare jsoniter against encoding/json.
+func benchmarkWriteJSON(b *testing.B, data interface{}) {
+	w := &discardResponseWriter{header: http.Header{}}
+	b.ReportAllocs()
+	for b.Loop() {
+		writeJSON(w, http.StatusOK, data)
+	}
+}
+
+func BenchmarkWriteJSON_ProductList(b *testing.B) {
+	products := fixtures.Products(1000)
+	for i := range products {
+		products[i].EffectivePrice = products[i].Price
+	}
+	benchmarkWriteJSON(b, products)
+}
+
+func BenchmarkWriteJSON_Order(b *testing.B) {
+	builder := fixtures.NewOrder().Coupon("HAPPYHRS").Source("web")
+	products := fixtures.Products(50)
+	for _, product := range products {
+		builder.ProductItem(product, 2)
+	}
+	order, items := builder.Build()
+
+	benchmarkWriteJSON(b, &model.OrderResponse{
+		ID:                order.ID,
+		Status:            order.Status,
+		FulfillmentStatus: model.FulfillmentStatusUnfulfilled,
+		Items:             items,
+		Products:          products,
+	})
+}
+
+func TestWriteError_CorrelationID(t *testing.T) {
+	logger := zerolog.Nop()
+
+	t.Run("Includes request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		w.Header().Set(middleware.RequestIDHeader, "req-123")
+
+		writeError(w, http.StatusNotFound, "order not found", logger)
+
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "order not found", resp.Error)
+		assert.Equal(t, "req-123", resp.CorrelationID)
+	})
+
+	t.Run("Omitted without request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeError(w, http.StatusBadRequest, "invalid request body", logger)
+
+		var resp map[string]any
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.NotContains(t, resp, "correlationId")
+	})
+}
+
+func TestWriteConstraintViolation(t *testing.T) {
+	logger := zerolog.Nop()
+	violation := func(domainErr error) error {
+		return fmt.Errorf("failed to insert: %w", errors.Join(errors.New("constraint violation"), domainErr))
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "Missing referenced record", err: violation(model.ErrProductNotFound), expectedStatus: http.StatusBadRequest, expectedError: model.ErrProductNotFound.Message},
+		{name: "Refused value", err: violation(model.ErrConstraintViolation), expectedStatus: http.StatusBadRequest, expectedError: model.ErrConstraintViolation.Message},
+		{name: "Duplicate record", err: violation(model.ErrDuplicateRecord), expectedStatus: http.StatusConflict, expectedError: model.ErrDuplicateRecord.Message},
+		{name: "Record in use", err: violation(model.ErrRecordInUse), expectedStatus: http.StatusConflict, expectedError: model.ErrRecordInUse.Message},
+		{name: "Domain error of no constraint", err: violation(model.ErrOrderStepFailed)},
+		{name: "Other error", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			written := writeConstraintViolation(w, tt.err, logger)
+
+			assert.Equal(t, tt.expectedStatus != 0, written)
+			if !written {
+				assert.Empty(t, w.Body.String())
+				return
+			}
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedError, resp.Error)
+		})
+	}
+}
+
+func TestWriteBatch(t *testing.T) {
+	t.Run("All entries succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeBatch(w, 3, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"succeeded":3,"failed":0,"entries":[]}`, w.Body.String())
+	})
+
+	t.Run("Some entries failed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeBatch(w, 0, []BatchEntry{{Index: 2, ID: "P001", Status: http.StatusConflict, Code: "PRODUCT_ALREADY_EXISTS", Error: "exists"}})
+
+		assert.Equal(t, http.StatusMultiStatus, w.Code)
+		var resp BatchResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Zero(t, resp.Succeeded)
+		assert.Equal(t, 1, resp.Failed)
+		assert.Equal(t, http.StatusConflict, resp.Entries[0].Status)
+	})
+}
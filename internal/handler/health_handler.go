@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-kart/internal/health"
+
+	"github.com/rs/zerolog"
+)
+
+// HealthHandler handles dependency health and readiness HTTP requests.
+type HealthHandler struct {
+	monitor *health.Monitor
+	logger  zerolog.Logger
+}
+
+// NewHealthHandler creates a new health handler.
+func NewHealthHandler(monitor *health.Monitor, logger zerolog.Logger) *HealthHandler {
+	return &HealthHandler{
+		monitor: monitor,
+		logger:  logger.With().Str("handler", "health").Logger(),
+	}
+}
+
+// ReadinessResponse represents the response payload for readiness checks.
+type ReadinessResponse struct {
+	Status       string          `json:"status"`
+	Dependencies []health.Status `json:"dependencies"`
+}
+
+// HealthHistoryResponse represents the response payload for the health history.
+type HealthHistoryResponse struct {
+	Ready        bool            `json:"ready"`
+	Dependencies []health.Status `json:"dependencies"`
+}
+
+// Ready handles GET /ready requests.
+// Readiness is dampened by the monitor so brief dependency blips don't flip it.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	resp := ReadinessResponse{
+		Status:       "ready",
+		Dependencies: h.monitor.Statuses(false),
+	}
+
+	status := http.StatusOK
+	if !h.monitor.Ready() {
+		resp.Status = "not ready"
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, resp)
+}
+
+// History handles GET /api/admin/health/history requests.
+func (h *HealthHandler) History(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, HealthHistoryResponse{
+		Ready:        h.monitor.Ready(),
+		Dependencies: h.monitor.Statuses(true),
+	})
+}
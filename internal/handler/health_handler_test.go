@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/health"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticChecker is a health.Checker that always returns the same result.
+type staticChecker struct {
+	err error
+}
+
+func (c *staticChecker) Name() string                    { return "database" }
+func (c *staticChecker) Check(ctx context.Context) error { return c.err }
+
+func newTestMonitor(err error) *health.Monitor {
+	config := &health.MonitorConfig{
+		Interval:          time.Second,
+		Timeout:           time.Second,
+		HistorySize:       10,
+		FailureThreshold:  1,
+		RecoveryThreshold: 1,
+		FlapThreshold:     5,
+	}
+	monitor := health.NewMonitor(config, zerolog.Nop(), &staticChecker{err: err})
+	monitor.ProbeOnce(context.Background())
+	return monitor
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		method         string
+		probeErr       error
+		expectedStatus int
+	}{
+		{
+			name:           "Ready",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Not ready",
+			method:         http.MethodGet,
+			probeErr:       errors.New("connection refused"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(newTestMonitor(tt.probeErr), logger)
+
+			req := httptest.NewRequest(tt.method, "/ready", nil)
+			w := httptest.NewRecorder()
+
+			handler.Ready(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHealthHandler_History(t *testing.T) {
+	logger := zerolog.Nop()
+	handler := NewHealthHandler(newTestMonitor(nil), logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/health/history", nil)
+	w := httptest.NewRecorder()
+
+	handler.History(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Ready        bool            `json:"ready"`
+		Dependencies []health.Status `json:"dependencies"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp.Ready)
+	require.Len(t, resp.Dependencies, 1)
+	assert.Equal(t, "database", resp.Dependencies[0].Dependency)
+	assert.Len(t, resp.Dependencies[0].History, 1)
+}
@@ -0,0 +1,83 @@
+//go:build jsoniter
+
+package handler
+
+import (
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// jsonAPI encodes responses as encoding/json would: same field order, HTML
+// escaping and Marshaler support. jsoniter predates omitzero, so fields
+// tagged with it are omitted by omitZeroExtension.
+var jsonAPI = func() jsoniter.API {
+	api := jsoniter.Config{
+		EscapeHTML:             true,
+		SortMapKeys:            true,
+		ValidateJsonRawMessage: true,
+	}.Froze()
+	api.RegisterExtension(&omitZeroExtension{})
+	return api
+}()
+
+// encodeJSON writes data to w as JSON followed by a newline, using jsoniter.
+// See the README's response encoding benchmarks before building with it.
+func encodeJSON(w io.Writer, data interface{}) error {
+	return jsonAPI.NewEncoder(w).Encode(data)
+}
+
+// omitZeroExtension omits struct fields tagged omitzero when they hold their
+// zero value, as encoding/json does. The field's tag gains omitempty, which
+// jsoniter does honour, and its encoder reports emptiness by the value's
+// IsZero method or, without one, by the type's zero value.
+type omitZeroExtension struct {
+	jsoniter.DummyExtension
+}
+
+// UpdateStructDescriptor rewrites the bindings of omitzero fields.
+func (*omitZeroExtension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
+	for _, binding := range desc.Fields {
+		tag := binding.Field.Tag().Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if !slices.Contains(strings.Split(opts, ","), "omitzero") {
+			continue
+		}
+		binding.Field = omitZeroField{
+			StructField: binding.Field,
+			tag:         reflect.StructTag(`json:"` + name + "," + opts + `,omitempty"`),
+		}
+		binding.Encoder = omitZeroEncoder{ValEncoder: binding.Encoder, typ: binding.Field.Type().Type1()}
+	}
+}
+
+// omitZeroField is a struct field whose tag also asks for omitempty.
+type omitZeroField struct {
+	reflect2.StructField
+	tag reflect.StructTag
+}
+
+// Tag returns the field's tag with omitempty added.
+func (f omitZeroField) Tag() reflect.StructTag {
+	return f.tag
+}
+
+// omitZeroEncoder reports a field empty when it holds its zero value.
+type omitZeroEncoder struct {
+	jsoniter.ValEncoder
+	typ reflect.Type
+}
+
+// IsEmpty reports whether the value at ptr is zero.
+func (e omitZeroEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	v := reflect.NewAt(e.typ, ptr).Elem()
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,14 @@
+//go:build !jsoniter
+
+package handler
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// encodeJSON writes data to w as JSON followed by a newline, using
+// encoding/json. Build with the jsoniter tag to use jsoniter instead.
+func encodeJSON(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
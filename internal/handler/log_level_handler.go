@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"mini-kart/internal/logging"
+
+	"github.com/rs/zerolog"
+)
+
+// LogLevelRequest changes the minimum level the service logs.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelResponse reports the minimum level the service logs.
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// LogLevelHandler handles runtime log level HTTP requests.
+type LogLevelHandler struct {
+	logger zerolog.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler.
+func NewLogLevelHandler(logger zerolog.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		logger: logger.With().Str("handler", "log_level").Logger(),
+	}
+}
+
+// LogLevel handles GET and PUT /api/admin/log-level requests. The level
+// applies until it is changed again or the service restarts.
+func (h *LogLevelHandler) LogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, LogLevelResponse{Level: logging.Level()})
+	case http.MethodPut:
+		var req LogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		previous := logging.Level()
+		if err := logging.SetLevel(req.Level); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+			return
+		}
+
+		// Logged at warn so the change is recorded at any level
+		caller, _ := adminFromRequest(r)
+		h.logger.Warn().
+			Str("previous", previous).
+			Str("level", req.Level).
+			Str("caller", caller).
+			Msg("log level changed")
+
+		writeJSON(w, http.StatusOK, LogLevelResponse{Level: logging.Level()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
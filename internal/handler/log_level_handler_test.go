@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/logging"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelHandler_LogLevel(t *testing.T) {
+	level := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedLevel  string
+	}{
+		{
+			name:           "Get level",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedLevel:  "info",
+		},
+		{
+			name:           "Set level",
+			method:         http.MethodPut,
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expectedLevel:  "debug",
+		},
+		{
+			name:           "Invalid level",
+			method:         http.MethodPut,
+			body:           `{"level":"verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedLevel:  "info",
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPut,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedLevel:  "info",
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedLevel:  "info",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, logging.SetLevel("info"))
+			h := NewLogLevelHandler(zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, "/api/admin/log-level", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.LogLevel(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLevel, logging.Level())
+			if tt.expectedStatus == http.StatusOK {
+				var resp LogLevelResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedLevel, resp.Level)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+)
+
+// MetricsHandler handles operational metrics HTTP requests.
+type MetricsHandler struct {
+	registry *metrics.Registry
+	caches   *metrics.CacheReporter
+	logger   zerolog.Logger
+}
+
+// NewMetricsHandler creates a new metrics handler. caches reports the hit
+// rates of the caches counted in registry.
+func NewMetricsHandler(registry *metrics.Registry, caches *metrics.CacheReporter, logger zerolog.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+		caches:   caches,
+		logger:   logger.With().Str("handler", "metrics").Logger(),
+	}
+}
+
+// Metrics handles GET /api/admin/metrics requests.
+func (h *MetricsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.registry.Snapshot())
+}
+
+// Caches handles GET /api/admin/metrics/caches requests, reporting each
+// cache's hits, misses, evictions and hit rate.
+func (h *MetricsHandler) Caches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.caches.Stats())
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_Metrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("deprecated_requests_total", "endpoint", "/api/orders").Add(3)
+
+	h := NewMetricsHandler(registry, metrics.NewCacheReporter(registry, time.Minute, zerolog.Nop()), zerolog.Nop())
+
+	t.Run("Returns counter snapshot", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/metrics", nil)
+		w := httptest.NewRecorder()
+
+		h.Metrics(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var samples []metrics.Sample
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&samples))
+		require.Len(t, samples, 1)
+		assert.Equal(t, int64(3), samples[0].Value)
+		assert.Equal(t, "/api/orders", samples[0].Labels["endpoint"])
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/metrics", nil)
+		w := httptest.NewRecorder()
+
+		h.Metrics(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestMetricsHandler_Caches(t *testing.T) {
+	registry := metrics.NewRegistry()
+	product := registry.Cache("product")
+	product.Hits.Add(3)
+	product.Misses.Inc()
+
+	h := NewMetricsHandler(registry, metrics.NewCacheReporter(registry, time.Minute, zerolog.Nop()), zerolog.Nop())
+
+	t.Run("Returns cache stats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/metrics/caches", nil)
+		w := httptest.NewRecorder()
+
+		h.Caches(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"name":"product","hits":3,"misses":1,"evictions":0,"hitRate":0.75}]`, w.Body.String())
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/metrics/caches", nil)
+		w := httptest.NewRecorder()
+
+		h.Caches(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// Response profiles let clients move to a new representation of an endpoint
+// one endpoint at a time, by asking for it in the Accept header, instead of
+// switching every call to a new URL version at once.
+const (
+	// profilePrefix and profileSuffix surround the version in a profile's
+	// media type, e.g. application/vnd.minikart.v2+json.
+	profilePrefix = "application/vnd.minikart.v"
+	profileSuffix = "+json"
+
+	// defaultProfileVersion is the representation served to clients that
+	// do not ask for a profile.
+	defaultProfileVersion = 1
+)
+
+// listProfileVersions are the representations of paginated lists: version 1
+// is a bare JSON array and version 2 a PageResponse envelope.
+var listProfileVersions = []int{1, 2}
+
+// PageResponse is the version 2 representation of a paginated list, which
+// carries the page in the body as well as in the pagination headers.
+type PageResponse[T any] struct {
+	Items []T      `json:"items"`
+	Page  PageInfo `json:"page"`
+}
+
+// PageInfo describes the page of a PageResponse.
+type PageInfo struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+
+	// Total is the number of items in the collection across all pages.
+	Total int `json:"total"`
+}
+
+// profile is a negotiated response representation.
+type profile struct {
+	version int
+
+	// contentType is the media type the response is labelled with: the
+	// profile's own media type when the client asked for it by name, and
+	// application/json otherwise.
+	contentType string
+}
+
+// ProfileMediaType returns the media type clients ask for a version of an
+// endpoint's representation with.
+func ProfileMediaType(version int) string {
+	return profilePrefix + strconv.Itoa(version) + profileSuffix
+}
+
+// parseProfile returns the version named by a profile media type, or false
+// if mediaType is not a profile.
+func parseProfile(mediaType string) (int, bool) {
+	rest, ok := strings.CutPrefix(mediaType, profilePrefix)
+	if !ok {
+		return 0, false
+	}
+	rest, ok = strings.CutSuffix(rest, profileSuffix)
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.Atoi(rest)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// negotiate selects the representation of the response from the request's
+// Accept header, among the supported profile versions. The most preferred
+// acceptable profile wins, with profiles named explicitly taking precedence
+// over application/json and wildcards of the same quality, which select the
+// default version. Requests without an Accept header, or accepting none of
+// the media types the API serves, get the default version as before.
+//
+// When the client names only profiles that are not supported, negotiate
+// writes a 406 response listing the supported ones and returns false.
+func negotiate(w http.ResponseWriter, r *http.Request, versions []int, logger zerolog.Logger) (profile, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	supported := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		supported[v] = true
+	}
+
+	best := profile{version: defaultProfileVersion, contentType: "application/json"}
+	bestQuality, bestNamed := -1.0, false
+	namedUnsupported := false
+
+	for _, accept := range r.Header.Values("Accept") {
+		for _, entry := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			if quality <= 0 {
+				continue
+			}
+
+			candidate, named := profile{version: defaultProfileVersion, contentType: "application/json"}, false
+			if version, ok := parseProfile(mediaType); ok {
+				if !supported[version] {
+					namedUnsupported = true
+					continue
+				}
+				candidate, named = profile{version: version, contentType: mediaType}, true
+			} else if mediaType != "application/json" && mediaType != "application/*" && mediaType != "*/*" {
+				continue
+			}
+
+			if quality > bestQuality || (quality == bestQuality && named && !bestNamed) {
+				best, bestQuality, bestNamed = candidate, quality, named
+			}
+		}
+	}
+
+	if bestQuality < 0 && namedUnsupported {
+		names := make([]string, len(versions))
+		for i, v := range versions {
+			names[i] = ProfileMediaType(v)
+		}
+		writeError(w, http.StatusNotAcceptable, fmt.Sprintf("supported profiles are %s", strings.Join(names, ", ")), logger)
+		return profile{}, false
+	}
+
+	return best, true
+}
+
+// writeList writes a page of items in the negotiated list representation.
+// The pagination headers are written in every version.
+func writeList[T any](w http.ResponseWriter, r *http.Request, p profile, page model.Page, items []T) {
+	if items == nil {
+		items = []T{}
+	}
+
+	writePageHeaders(w, r, page)
+
+	switch p.version {
+	case 2:
+		writeJSONAs(w, http.StatusOK, p.contentType, PageResponse[T]{
+			Items: items,
+			Page:  PageInfo{Limit: page.Limit, Offset: page.Offset, Total: page.Total},
+		})
+	default:
+		writeJSONAs(w, http.StatusOK, p.contentType, items)
+	}
+}
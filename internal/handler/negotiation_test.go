@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name            string
+		accept          []string
+		wantVersion     int
+		wantContentType string
+	}{
+		{
+			name:            "No Accept header",
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "Plain JSON",
+			accept:          []string{"application/json"},
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "Profile by name",
+			accept:          []string{"application/vnd.minikart.v2+json"},
+			wantVersion:     2,
+			wantContentType: "application/vnd.minikart.v2+json",
+		},
+		{
+			name:            "Default profile by name",
+			accept:          []string{"application/vnd.minikart.v1+json"},
+			wantVersion:     1,
+			wantContentType: "application/vnd.minikart.v1+json",
+		},
+		{
+			name:            "Profile preferred over wildcard of the same quality",
+			accept:          []string{"*/*, application/vnd.minikart.v2+json"},
+			wantVersion:     2,
+			wantContentType: "application/vnd.minikart.v2+json",
+		},
+		{
+			name:            "Higher quality wins",
+			accept:          []string{"application/vnd.minikart.v2+json;q=0.5, application/json"},
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "Unsupported profile falls back to JSON",
+			accept:          []string{"application/vnd.minikart.v9+json, application/json;q=0.1"},
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "Rejected profile is skipped",
+			accept:          []string{"application/vnd.minikart.v2+json;q=0, */*"},
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "Several Accept headers",
+			accept:          []string{"text/html", "application/vnd.minikart.v2+json"},
+			wantVersion:     2,
+			wantContentType: "application/vnd.minikart.v2+json",
+		},
+		{
+			name:            "Unrelated media types keep the default",
+			accept:          []string{"text/html"},
+			wantVersion:     1,
+			wantContentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+			req.Header["Accept"] = tt.accept
+			w := httptest.NewRecorder()
+
+			got, ok := negotiate(w, req, listProfileVersions, zerolog.Nop())
+
+			require.True(t, ok)
+			assert.Equal(t, tt.wantVersion, got.version)
+			assert.Equal(t, tt.wantContentType, got.contentType)
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		})
+	}
+
+	t.Run("Only unsupported profiles", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		req.Header.Set("Accept", "application/vnd.minikart.v3+json")
+		w := httptest.NewRecorder()
+
+		_, ok := negotiate(w, req, listProfileVersions, zerolog.Nop())
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "supported profiles are application/vnd.minikart.v1+json, application/vnd.minikart.v2+json", resp.Error)
+	})
+}
+
+func TestWriteList(t *testing.T) {
+	page := model.Page{Limit: 2, Offset: 2, Total: 5}
+
+	t.Run("Version 1 is a bare array", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeList(w, httptest.NewRequest(http.MethodGet, "/api/orders", nil),
+			profile{version: 1, contentType: "application/json"}, page, []string{"a", "b"})
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+		assert.JSONEq(t, `["a","b"]`, w.Body.String())
+	})
+
+	t.Run("Version 2 is an envelope", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		writeList(w, httptest.NewRequest(http.MethodGet, "/api/orders", nil),
+			profile{version: 2, contentType: ProfileMediaType(2)}, page, []string(nil))
+
+		assert.Equal(t, "application/vnd.minikart.v2+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+		assert.JSONEq(t, `{"items":[],"page":{"limit":2,"offset":2,"total":5}}`, w.Body.String())
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// operationRetryAfter is the Retry-After, in seconds, suggesting when to
+// poll a pending operation again.
+const operationRetryAfter = "1"
+
+// OperationHandler handles asynchronous order operation HTTP requests.
+type OperationHandler struct {
+	service service.OperationService
+	logger  zerolog.Logger
+}
+
+// NewOperationHandler creates a new operation handler.
+func NewOperationHandler(service service.OperationService, logger zerolog.Logger) *OperationHandler {
+	return &OperationHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "operation").Logger(),
+	}
+}
+
+// Get handles GET /api/operations/{id} requests. Pending operations carry a
+// Retry-After header saying when to poll again; succeeded operations link to
+// the created order in a Location header.
+func (h *OperationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	operationIDStr := strings.TrimPrefix(r.URL.Path, "/api/operations/")
+	if operationIDStr == "" {
+		writeError(w, http.StatusBadRequest, "operation ID is required", h.logger)
+		return
+	}
+
+	operationID, err := uuid.Parse(operationIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid operation ID format", h.logger)
+		return
+	}
+
+	op, err := h.service.GetOperation(r.Context(), operationID)
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve operation", h.logger)
+		return
+	}
+	if op == nil {
+		writeError(w, http.StatusNotFound, "operation not found", h.logger)
+		return
+	}
+
+	if !op.Done() {
+		w.Header().Set("Retry-After", operationRetryAfter)
+	} else if op.OrderID != nil {
+		w.Header().Set("Location", "/api/orders/"+op.OrderID.String())
+	}
+	writeJSON(w, http.StatusOK, op)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOperationService is a mock implementation of OperationService.
+type MockOperationService struct {
+	mock.Mock
+}
+
+func (m *MockOperationService) SubmitOrder(ctx context.Context, req *model.OrderRequest) (*model.Operation, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Operation), args.Error(1)
+}
+
+func (m *MockOperationService) GetOperation(ctx context.Context, id uuid.UUID) (*model.Operation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Operation), args.Error(1)
+}
+
+func (m *MockOperationService) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestOperationHandler_Get(t *testing.T) {
+	operationID := uuid.New()
+	orderID := uuid.New()
+	path := "/api/operations/" + operationID.String()
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		mockReturn         *model.Operation
+		mockError          error
+		expectedStatus     int
+		expectedRetryAfter string
+		expectedLocation   string
+		expectService      bool
+	}{
+		{
+			name:               "Pending",
+			method:             http.MethodGet,
+			path:               path,
+			mockReturn:         &model.Operation{ID: operationID, Status: model.OperationStatusPending},
+			expectedStatus:     http.StatusOK,
+			expectedRetryAfter: "1",
+			expectService:      true,
+		},
+		{
+			name:             "Succeeded",
+			method:           http.MethodGet,
+			path:             path,
+			mockReturn:       &model.Operation{ID: operationID, Status: model.OperationStatusSucceeded, OrderID: &orderID},
+			expectedStatus:   http.StatusOK,
+			expectedLocation: "/api/orders/" + orderID.String(),
+			expectService:    true,
+		},
+		{
+			name:   "Failed",
+			method: http.MethodGet,
+			path:   path,
+			mockReturn: &model.Operation{
+				ID:     operationID,
+				Status: model.OperationStatusFailed,
+				Error:  model.NewOperationError(model.ErrInvalidPromoCode),
+			},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "Not found",
+			method:         http.MethodGet,
+			path:           path,
+			expectedStatus: http.StatusNotFound,
+			expectService:  true,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			path:           path,
+			mockError:      model.ErrDatabaseUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			path:           path,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectService:  true,
+		},
+		{
+			name:           "Invalid UUID format",
+			method:         http.MethodGet,
+			path:           "/api/operations/invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			path:           path,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOperationService)
+			handler := NewOperationHandler(mockService, zerolog.Nop())
+
+			if tt.expectService {
+				mockService.On("GetOperation", mock.Anything, operationID).Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.Get(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+
+			if tt.expectedStatus == http.StatusOK {
+				var op model.Operation
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&op))
+				assert.Equal(t, *tt.mockReturn, op)
+			}
+
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetOperation", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"mini-kart/internal/middleware"
 	"mini-kart/internal/model"
 	"mini-kart/internal/service"
 
@@ -12,18 +14,86 @@ import (
 	"github.com/rs/zerolog"
 )
 
+const (
+	// idempotencyKeyHeader carries a client-chosen key that makes order creation safe to retry.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// maxIdempotencyKeyLength bounds the keys stored per order.
+	maxIdempotencyKeyLength = 255
+
+	// overloadRetryAfter is the Retry-After, in seconds, sent when order
+	// creation is shed because too many are already in flight.
+	overloadRetryAfter = "1"
+)
+
 // OrderHandler handles order-related HTTP requests.
 type OrderHandler struct {
-	service service.OrderService
-	logger  zerolog.Logger
+	service    service.OrderService
+	inFlight   chan struct{}
+	operations service.OperationService
+	async      map[string]bool // callers whose orders are created asynchronously
+	currencies service.CurrencyService
+	carts      service.CartService
+	logger     zerolog.Logger
+}
+
+// OrderHandlerOption configures optional OrderHandler behaviour.
+type OrderHandlerOption func(*OrderHandler)
+
+// WithMaxInFlight caps concurrent order creations at limit. Requests beyond
+// it fail fast with 503 and Retry-After instead of waiting for a database
+// connection. A limit of zero or less leaves creation unbounded.
+func WithMaxInFlight(limit int) OrderHandlerOption {
+	return func(h *OrderHandler) {
+		if limit > 0 {
+			h.inFlight = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithAsyncOrders creates the orders of the given callers asynchronously:
+// POST /api/orders responds 202 Accepted with an operation to poll at
+// GET /api/operations/{id} instead of waiting for the order to be created.
+// Callers are identity subjects, e.g. "api-key", "admin:<name>" or a client
+// certificate identity; "*" makes every caller's orders asynchronous.
+func WithAsyncOrders(operations service.OperationService, callers []string) OrderHandlerOption {
+	return func(h *OrderHandler) {
+		h.operations = operations
+		h.async = make(map[string]bool, len(callers))
+		for _, caller := range callers {
+			h.async[caller] = true
+		}
+	}
+}
+
+// WithOrderCurrencies enables the ?currency= parameter and Accept-Currency
+// header on order reads, showing prices converted into that currency at
+// today's rate. Without it both are ignored.
+func WithOrderCurrencies(currencies service.CurrencyService) OrderHandlerOption {
+	return func(h *OrderHandler) {
+		h.currencies = currencies
+	}
+}
+
+// WithCartCheckout accepts a cartToken in order requests, placing the order
+// from the signed storefront cart it carries. Without it requests with a
+// cart token are rejected.
+func WithCartCheckout(carts service.CartService) OrderHandlerOption {
+	return func(h *OrderHandler) {
+		h.carts = carts
+	}
 }
 
 // NewOrderHandler creates a new order handler.
-func NewOrderHandler(service service.OrderService, logger zerolog.Logger) *OrderHandler {
-	return &OrderHandler{
+func NewOrderHandler(service service.OrderService, logger zerolog.Logger, opts ...OrderHandlerOption) *OrderHandler {
+	h := &OrderHandler{
 		service: service,
 		logger:  logger.With().Str("handler", "order").Logger(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Create handles POST /api/orders requests.
@@ -33,12 +103,44 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.inFlight != nil {
+		select {
+		case h.inFlight <- struct{}{}:
+			defer func() { <-h.inFlight }()
+		default:
+			h.logger.Warn().Int("limit", cap(h.inFlight)).Msg("order creation shed: too many in flight")
+			w.Header().Set("Retry-After", overloadRetryAfter)
+			writeError(w, http.StatusServiceUnavailable, "too many orders in progress, retry shortly", h.logger)
+			return
+		}
+	}
+
 	var req model.OrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
 		return
 	}
 
+	req.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		writeError(w, http.StatusBadRequest, "idempotency key must be at most 255 characters", h.logger)
+		return
+	}
+
+	if req.CartToken != "" && !h.openCart(w, r, &req) {
+		return
+	}
+
+	if identity, ok := middleware.IdentityFromContext(r.Context()); ok {
+		req.Caller = identity.Subject
+		req.Delegation = delegationFromIdentity(identity)
+	}
+
+	if h.operations != nil && (h.async["*"] || h.async[req.Caller]) {
+		h.submit(w, r, &req)
+		return
+	}
+
 	order, err := h.service.CreateOrder(r.Context(), &req)
 	if err != nil {
 		// Determine appropriate status code based on error type
@@ -49,13 +151,76 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		case model.ErrInvalidPromoCode:
 			status = http.StatusBadRequest
 			message = "invalid promo code"
+		case model.ErrInvalidPromoFormat:
+			status = http.StatusBadRequest
+			message = "invalid promo code format"
+		case model.ErrCouponNotApplicable:
+			status = http.StatusBadRequest
+			message = "promo code does not apply to any items in the order"
+		case model.ErrCouponExpired:
+			status = http.StatusBadRequest
+			message = "promo code has expired"
+		case model.ErrCouponMinSubtotal:
+			status = http.StatusBadRequest
+			message = "order subtotal is below the promo code's minimum"
+		case model.ErrCouponFirstOrderOnly:
+			status = http.StatusConflict
+			message = "promo code is only valid on a customer's first order"
+		case model.ErrCouponNotForCustomer:
+			status = http.StatusConflict
+			message = "promo code is not valid for this customer"
+		case model.ErrCouponSegment:
+			status = http.StatusConflict
+			message = "promo code is only valid for customers in its segments"
+		case model.ErrCouponValidationTimeout:
+			status = http.StatusServiceUnavailable
+			message = "promo code validation timed out, please retry"
+		case model.ErrCouponsLoading:
+			status = http.StatusServiceUnavailable
+			message = "promo codes are still loading, please retry"
 		case model.ErrProductNotFound:
 			status = http.StatusBadRequest
 			message = "one or more products not found"
+		case model.ErrCustomerNotFound:
+			status = http.StatusBadRequest
+			message = "customer not found"
 		case model.ErrInvalidQuantity:
 			status = http.StatusBadRequest
 			message = "invalid quantity"
+		case model.ErrInvalidOrderSource:
+			status = http.StatusBadRequest
+			message = "invalid order source"
+		case model.ErrInvalidShippingAddress:
+			status = http.StatusBadRequest
+			message = "shipping address needs a name, first line, city, postal code and two-letter country code"
+		case model.ErrUnknownOrderFields:
+			status = http.StatusBadRequest
+			message = "unknown fields: " + strings.Join(req.UnknownFields(), ", ")
+		case model.ErrCouponRedemptionLimit:
+			status = http.StatusConflict
+			message = "promo code has reached its redemption limit"
+		case model.ErrCouponCallerLimit:
+			status = http.StatusConflict
+			message = "promo code has already been redeemed by this caller"
+		case model.ErrIdempotencyConflict:
+			status = http.StatusUnprocessableEntity
+			message = "idempotency key was already used with a different request"
+		case model.ErrPaymentDeclined:
+			status = http.StatusPaymentRequired
+			message = "payment was declined"
+		case model.ErrInsufficientStock:
+			status = http.StatusConflict
+			message = "one or more items are out of stock"
+		case model.ErrCouponRejected:
+			status = http.StatusConflict
+			message = "promo code could not be redeemed"
+		case model.ErrOrderStepFailed:
+			status = http.StatusServiceUnavailable
+			message = "payment, stock or promotion service is unavailable, please retry"
 		default:
+			if writeConstraintViolation(w, err, h.logger) {
+				return
+			}
 			if strings.Contains(err.Error(), "required") ||
 				strings.Contains(err.Error(), "must contain") ||
 				strings.Contains(err.Error(), "nil") {
@@ -68,9 +233,58 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if order.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
 	writeJSON(w, http.StatusCreated, order)
 }
 
+// openCart fills in the items and promo code of an order placed from a cart
+// token with the cart's. It writes an error response and returns false when
+// the token cannot be used.
+func (h *OrderHandler) openCart(w http.ResponseWriter, r *http.Request, req *model.OrderRequest) bool {
+	if h.carts == nil {
+		writeError(w, http.StatusBadRequest, "cart tokens are not enabled", h.logger)
+		return false
+	}
+	if len(req.Items) > 0 || req.CouponCode != nil {
+		writeError(w, http.StatusBadRequest, model.ErrCartCheckout.Error(), h.logger)
+		return false
+	}
+
+	cart, err := h.carts.OpenCart(r.Context(), req.CartToken)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidCartToken, model.ErrCartExpired:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to open cart", h.logger)
+		}
+		return false
+	}
+
+	req.Items = cart.Items
+	req.CouponCode = cart.CouponCode
+	return true
+}
+
+// submit accepts an order for asynchronous creation, responding 202 Accepted
+// with the operation tracking it and its URL in the Location header.
+func (h *OrderHandler) submit(w http.ResponseWriter, r *http.Request, req *model.OrderRequest) {
+	op, err := h.operations.SubmitOrder(r.Context(), req)
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to submit order", h.logger)
+		return
+	}
+
+	w.Header().Set("Location", "/api/operations/"+op.ID.String())
+	w.Header().Set("Retry-After", operationRetryAfter)
+	writeJSON(w, http.StatusAccepted, op)
+}
+
 // GetByID handles GET /api/orders/{id} requests.
 func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -98,8 +312,16 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
+		return
+	}
+
 	order, err := h.service.GetByID(r.Context(), orderID)
 	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "failed to retrieve order", h.logger)
 		return
 	}
@@ -109,5 +331,277 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if conversion.requested() {
+		*order = order.InCurrency(conversion.currency, conversion.rate)
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
+
+// GetByRef handles GET /api/orders/by-ref/{ref} requests, returning the
+// order created with the Idempotency-Key ref or imported with the legacy
+// reference ref.
+func (h *OrderHandler) GetByRef(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	// Expecting path: /api/orders/by-ref/{ref}. References may contain
+	// slashes, so the rest of the path is the reference
+	ref := strings.TrimPrefix(r.URL.Path, "/api/orders/by-ref/")
+	if ref == r.URL.Path || strings.TrimSpace(ref) == "" {
+		writeError(w, http.StatusBadRequest, "order reference is required", h.logger)
+		return
+	}
+
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetByRef(r.Context(), ref)
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve order", h.logger)
+		return
+	}
+
+	if order == nil {
+		writeError(w, http.StatusNotFound, "order not found", h.logger)
+		return
+	}
+
+	if conversion.requested() {
+		*order = order.InCurrency(conversion.currency, conversion.rate)
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
+
+// UpdateStatus handles PATCH /api/orders/{id}/status requests.
+func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderIDStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/orders/"), "/status")
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order ID format", h.logger)
+		return
+	}
+
+	var req model.OrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	var delegation *model.Delegation
+	if identity, ok := middleware.IdentityFromContext(r.Context()); ok {
+		delegation = delegationFromIdentity(identity)
+	}
+
+	order, err := h.service.UpdateStatus(r.Context(), orderID, req.Status, delegation)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "failed to update order status"
+
+		switch err {
+		case model.ErrInvalidOrderStatus:
+			status = http.StatusBadRequest
+			message = "status must be pending, confirmed, cancelled or fulfilled"
+		case model.ErrOrderNotFound:
+			status = http.StatusNotFound
+			message = "order not found"
+		case model.ErrStatusTransition:
+			status = http.StatusConflict
+			message = "order cannot move from its current status to " + string(req.Status)
+		}
+
+		writeError(w, status, message, h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
+
+// OverrideStatus handles POST /api/admin/orders/{id}/status requests,
+// moving an order to any status regardless of the order status lifecycle.
+func (h *OrderHandler) OverrideStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderIDStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/orders/"), "/status")
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order ID format", h.logger)
+		return
+	}
+
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		writeError(w, http.StatusForbidden, "status overrides require an admin key", h.logger)
+		return
+	}
+	admin := identity.Subject
+
+	var req model.StatusOverride
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	order, err := h.service.OverrideStatus(r.Context(), orderID, &req, admin)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidStatusOverride:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		case model.ErrOrderNotFound:
+			writeError(w, http.StatusNotFound, "order not found", h.logger)
+		case model.ErrStatusTransition:
+			writeError(w, http.StatusConflict, "order status changed while it was being overridden; retry", h.logger)
+		default:
+			if writeUnavailable(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to override order status", h.logger)
+		}
+		return
+	}
+
 	writeJSON(w, http.StatusOK, order)
 }
+
+// delegationFromIdentity returns the delegation of an admin acting on behalf
+// of a customer, or nil when the caller is acting for itself.
+func delegationFromIdentity(identity middleware.Identity) *model.Delegation {
+	if identity.Actor == "" {
+		return nil
+	}
+	return &model.Delegation{Admin: identity.Actor, Customer: identity.Subject}
+}
+
+// List handles GET /api/orders requests with source filtering and pagination.
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	filter, ok := h.orderFilter(w, r)
+	if !ok {
+		return
+	}
+
+	h.list(w, r, filter)
+}
+
+// Search handles GET /api/admin/orders requests, finding the orders that
+// contain a product, e.g. for a recall.
+func (h *OrderHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	filter, ok := h.orderFilter(w, r)
+	if !ok {
+		return
+	}
+
+	filter.ProductID = r.URL.Query().Get("productId")
+	if filter.ProductID == "" {
+		writeError(w, http.StatusBadRequest, "productId parameter is required", h.logger)
+		return
+	}
+
+	h.list(w, r, filter)
+}
+
+// orderFilter parses the source and pagination query parameters, writing an
+// error response if they are malformed.
+func (h *OrderHandler) orderFilter(w http.ResponseWriter, r *http.Request) (model.OrderFilter, bool) {
+	query := r.URL.Query()
+	filter := model.OrderFilter{
+		Source: query.Get("source"),
+		Limit:  10, // default
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid limit parameter", h.logger)
+			return model.OrderFilter{}, false
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid offset parameter", h.logger)
+			return model.OrderFilter{}, false
+		}
+		filter.Offset = offset
+	}
+
+	return filter, true
+}
+
+// list writes the page of orders matching the filter, in the list
+// representation negotiated with the Accept header.
+func (h *OrderHandler) list(w http.ResponseWriter, r *http.Request, filter model.OrderFilter) {
+	profile, ok := negotiate(w, r, listProfileVersions, h.logger)
+	if !ok {
+		return
+	}
+
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
+		return
+	}
+
+	orders, page, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve orders", h.logger)
+		return
+	}
+
+	if conversion.requested() {
+		for i := range orders {
+			orders[i] = orders[i].InCurrency(conversion.currency, conversion.rate)
+		}
+	}
+
+	writeList(w, r, profile, page, orders)
+}
+
+// CountBySource handles GET /api/admin/analytics/orders-by-source requests.
+func (h *OrderHandler) CountBySource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	counts, err := h.service.CountBySource(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve order analytics", h.logger)
+		return
+	}
+
+	if counts == nil {
+		counts = []model.SourceCount{}
+	}
+
+	writeJSON(w, http.StatusOK, counts)
+}
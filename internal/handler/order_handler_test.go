@@ -5,11 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"mini-kart/internal/middleware"
 	"mini-kart/internal/model"
 
 	"github.com/google/uuid"
@@ -40,6 +43,46 @@ func (m *MockOrderService) GetByID(ctx context.Context, id uuid.UUID) (*model.Or
 	return args.Get(0).(*model.OrderResponse), args.Error(1)
 }
 
+func (m *MockOrderService) GetByRef(ctx context.Context, ref string) (*model.OrderResponse, error) {
+	args := m.Called(ctx, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) List(ctx context.Context, filter model.OrderFilter) ([]model.Order, model.Page, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, model.Page{}, args.Error(2)
+	}
+	return args.Get(0).([]model.Order), args.Get(1).(model.Page), args.Error(2)
+}
+
+func (m *MockOrderService) UpdateStatus(ctx context.Context, id uuid.UUID, status model.OrderStatus, delegation *model.Delegation) (*model.Order, error) {
+	args := m.Called(ctx, id, status, delegation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func (m *MockOrderService) OverrideStatus(ctx context.Context, id uuid.UUID, override *model.StatusOverride, admin string) (*model.Order, error) {
+	args := m.Called(ctx, id, override, admin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func (m *MockOrderService) CountBySource(ctx context.Context) ([]model.SourceCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SourceCount), args.Error(1)
+}
+
 func TestOrderHandler_Create(t *testing.T) {
 	logger := zerolog.Nop()
 
@@ -50,7 +93,7 @@ func TestOrderHandler_Create(t *testing.T) {
 			{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2},
 		},
 		Products: []model.Product{
-			{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
+			{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(10.00)},
 		},
 	}
 
@@ -103,6 +146,213 @@ func TestOrderHandler_Create(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectService:  true,
 		},
+		{
+			name:   "Customer not found",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CustomerID: func() *uuid.UUID { id := uuid.New(); return &id }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCustomerNotFound,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:   "Invalid order source",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				Source: func() *string { s := "fax"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrInvalidOrderSource,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:   "Coupon redemption limit reached",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "LIMITED123"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponRedemptionLimit,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Coupon already redeemed by caller",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "WELCOME10"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponCallerLimit,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Promo code does not apply to items",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "WAFFLE20"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P002", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponNotApplicable,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:   "Expired promo code",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "SUMMER123"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponExpired,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:   "Promo code minimum not met",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "TENOFF50"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponMinSubtotal,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:   "First-order-only promo code",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "WELCOME15"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponFirstOrderOnly,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Promo code issued to another customer",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "STAFF30X"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponNotForCustomer,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Promo code for another customer segment",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "VIPONLY20"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponSegment,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Promo code validation timeout",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "HAPPYHRS"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponValidationTimeout,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+		},
+		{
+			name:   "Promo codes still loading",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				CouponCode: func() *string { s := "HAPPYHRS"; return &s }(),
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrCouponsLoading,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+		},
+		{
+			name:   "Payment declined",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrPaymentDeclined,
+			expectedStatus: http.StatusPaymentRequired,
+			expectService:  true,
+		},
+		{
+			name:   "Out of stock",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrInsufficientStock,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:   "Order step unavailable",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      model.ErrOrderStepFailed,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+		},
 		{
 			name:   "Invalid quantity",
 			method: http.MethodPost,
@@ -116,6 +366,19 @@ func TestOrderHandler_Create(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectService:  true,
 		},
+		{
+			name:   "Product deleted while ordering",
+			method: http.MethodPost,
+			requestBody: &model.OrderRequest{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			},
+			mockReturn:     nil,
+			mockError:      fmt.Errorf("failed to create order items: %w", errors.Join(errors.New("foreign key violation"), model.ErrProductNotFound)),
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
 		{
 			name:   "Validation error - required field",
 			method: http.MethodPost,
@@ -196,53 +459,338 @@ func TestOrderHandler_Create(t *testing.T) {
 	}
 }
 
-func TestOrderHandler_GetByID(t *testing.T) {
+func TestOrderHandler_Create_UnknownFields(t *testing.T) {
 	logger := zerolog.Nop()
+	body := `{"items":[{"productId":"P001","quantity":1}],"giftWrap":true,"deliveryWindow":"am"}`
 
+	mockService := new(MockOrderService)
+	mockService.On("CreateOrder", mock.Anything, mock.MatchedBy(func(req *model.OrderRequest) bool {
+		return len(req.Items) == 1 && string(req.Metadata["giftWrap"]) == "true"
+	})).Return(nil, model.ErrUnknownOrderFields)
+
+	h := NewOrderHandler(mockService, logger)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown fields: deliveryWindow, giftWrap")
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_Create_Idempotency(t *testing.T) {
+	logger := zerolog.Nop()
+	body := `{"items":[{"productId":"P001","quantity":1}]}`
 	orderID := uuid.New()
-	testResponse := &model.OrderResponse{
-		ID: orderID,
-		Items: []model.OrderItem{
-			{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2},
+
+	t.Run("Passes the key and flags replays", func(t *testing.T) {
+		mockService := new(MockOrderService)
+		mockService.On("CreateOrder", mock.Anything, mock.MatchedBy(func(req *model.OrderRequest) bool {
+			return req.IdempotencyKey == "checkout-7f3a"
+		})).Return(&model.OrderResponse{ID: orderID, Replayed: true}, nil)
+
+		h := NewOrderHandler(mockService, logger)
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+		req.Header.Set("Idempotency-Key", "checkout-7f3a")
+		w := httptest.NewRecorder()
+
+		h.Create(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+		assert.Contains(t, w.Body.String(), orderID.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Key reused for a different request", func(t *testing.T) {
+		mockService := new(MockOrderService)
+		mockService.On("CreateOrder", mock.Anything, mock.Anything).Return(nil, model.ErrIdempotencyConflict)
+
+		h := NewOrderHandler(mockService, logger)
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+		req.Header.Set("Idempotency-Key", "checkout-7f3a")
+		w := httptest.NewRecorder()
+
+		h.Create(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Key too long", func(t *testing.T) {
+		mockService := new(MockOrderService)
+
+		h := NewOrderHandler(mockService, logger)
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+		req.Header.Set("Idempotency-Key", string(bytes.Repeat([]byte("k"), 256)))
+		w := httptest.NewRecorder()
+
+		h.Create(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderHandler_Create_Caller(t *testing.T) {
+	mockService := new(MockOrderService)
+	mockService.On("CreateOrder", mock.Anything, mock.MatchedBy(func(req *model.OrderRequest) bool {
+		return req.Caller == "checkout-service"
+	})).Return(&model.OrderResponse{ID: uuid.New()}, nil)
+
+	h := NewOrderHandler(mockService, zerolog.Nop())
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(`{"items":[{"productId":"P001","quantity":1}]}`))
+	req = req.WithContext(middleware.WithIdentity(req.Context(), middleware.Identity{Subject: "checkout-service"}))
+	w := httptest.NewRecorder()
+
+	h.Create(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_Create_CartCheckout(t *testing.T) {
+	logger := zerolog.Nop()
+	code := "SUMMER2025"
+	cart := &model.Cart{Items: []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}}, CouponCode: &code}
+
+	t.Run("Items and promo code taken from the cart", func(t *testing.T) {
+		carts := new(MockCartService)
+		carts.On("OpenCart", mock.Anything, "cart-token").Return(cart, nil)
+		mockService := new(MockOrderService)
+		mockService.On("CreateOrder", mock.Anything, mock.MatchedBy(func(req *model.OrderRequest) bool {
+			return len(req.Items) == 1 && req.Items[0].Quantity == 2 && req.CouponCode == &code
+		})).Return(&model.OrderResponse{ID: uuid.New()}, nil)
+
+		h := NewOrderHandler(mockService, logger, WithCartCheckout(carts))
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(`{"cartToken":"cart-token"}`))
+		w := httptest.NewRecorder()
+
+		h.Create(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		carts  func() *MockCartService
+		reason string
+	}{
+		{
+			name: "Expired cart",
+			body: `{"cartToken":"cart-token"}`,
+			carts: func() *MockCartService {
+				m := new(MockCartService)
+				m.On("OpenCart", mock.Anything, "cart-token").Return(nil, model.ErrCartExpired)
+				return m
+			},
+			reason: "expired",
 		},
-		Products: []model.Product{
-			{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
+		{
+			name:   "Items alongside the cart",
+			body:   `{"cartToken":"cart-token","items":[{"productId":"P002","quantity":1}]}`,
+			carts:  func() *MockCartService { return new(MockCartService) },
+			reason: "take their items",
+		},
+		{
+			name:   "Cart tokens disabled",
+			body:   `{"cartToken":"cart-token"}`,
+			carts:  func() *MockCartService { return nil },
+			reason: "not enabled",
 		},
 	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			var opts []OrderHandlerOption
+			if carts := tt.carts(); carts != nil {
+				opts = append(opts, WithCartCheckout(carts))
+			}
+
+			h := NewOrderHandler(mockService, logger, opts...)
+			req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Create(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.reason)
+			mockService.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOrderHandler_Create_Async(t *testing.T) {
+	body := `{"items":[{"productId":"P001","quantity":1}]}`
+	operation := &model.Operation{ID: uuid.New(), Status: model.OperationStatusPending}
+
 	tests := []struct {
 		name           string
-		method         string
-		path           string
-		mockReturn     *model.OrderResponse
-		mockError      error
+		callers        []string
+		caller         string
+		submitError    error
 		expectedStatus int
-		expectService  bool
-		orderID        uuid.UUID
+		expectSubmit   bool
 	}{
 		{
-			name:           "Success",
-			method:         http.MethodGet,
-			path:           "/api/orders/" + orderID.String(),
-			mockReturn:     testResponse,
-			mockError:      nil,
-			expectedStatus: http.StatusOK,
-			expectService:  true,
-			orderID:        orderID,
+			name:           "Listed caller is accepted asynchronously",
+			callers:        []string{"batch-importer"},
+			caller:         "batch-importer",
+			expectedStatus: http.StatusAccepted,
+			expectSubmit:   true,
 		},
 		{
-			name:           "Order not found - service returns nil",
-			method:         http.MethodGet,
-			path:           "/api/orders/" + uuid.New().String(),
-			mockReturn:     nil,
-			mockError:      nil,
-			expectedStatus: http.StatusNotFound,
-			expectService:  true,
-			orderID:        uuid.New(),
+			name:           "Wildcard accepts every caller asynchronously",
+			callers:        []string{"*"},
+			caller:         "checkout-service",
+			expectedStatus: http.StatusAccepted,
+			expectSubmit:   true,
 		},
 		{
-			name:           "Order not found - service returns error",
-			method:         http.MethodGet,
+			name:           "Other callers are served synchronously",
+			callers:        []string{"batch-importer"},
+			caller:         "checkout-service",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Operation cannot be recorded",
+			callers:        []string{"batch-importer"},
+			caller:         "batch-importer",
+			submitError:    errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectSubmit:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			mockOperations := new(MockOperationService)
+			if tt.expectSubmit {
+				var result *model.Operation
+				if tt.submitError == nil {
+					result = operation
+				}
+				mockOperations.On("SubmitOrder", mock.Anything, mock.MatchedBy(func(req *model.OrderRequest) bool {
+					return req.Caller == tt.caller
+				})).Return(result, tt.submitError)
+			} else {
+				mockService.On("CreateOrder", mock.Anything, mock.Anything).Return(&model.OrderResponse{ID: uuid.New()}, nil)
+			}
+
+			h := NewOrderHandler(mockService, zerolog.Nop(), WithAsyncOrders(mockOperations, tt.callers))
+			req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body))
+			req = req.WithContext(middleware.WithIdentity(req.Context(), middleware.Identity{Subject: tt.caller}))
+			w := httptest.NewRecorder()
+
+			h.Create(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				assert.Equal(t, "/api/operations/"+operation.ID.String(), w.Header().Get("Location"))
+				assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+				var got model.Operation
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, operation.ID, got.ID)
+				assert.Equal(t, model.OperationStatusPending, got.Status)
+			}
+			mockService.AssertExpectations(t)
+			mockOperations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderHandler_Create_MaxInFlight(t *testing.T) {
+	body := `{"items":[{"productId":"P001","quantity":1}]}`
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	mockService := new(MockOrderService)
+	mockService.On("CreateOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		entered <- struct{}{}
+		<-release
+	}).Return(&model.OrderResponse{ID: uuid.New()}, nil)
+
+	h := NewOrderHandler(mockService, zerolog.Nop(), WithMaxInFlight(1))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Create(first, httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body)))
+	}()
+	<-entered
+
+	// The only slot is taken, so the next order is shed
+	shed := httptest.NewRecorder()
+	h.Create(shed, httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusServiceUnavailable, shed.Code)
+	assert.Equal(t, "1", shed.Header().Get("Retry-After"))
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	// The slot is released once the first order completes
+	w := httptest.NewRecorder()
+	go func() { <-entered }()
+	h.Create(w, httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertNumberOfCalls(t, "CreateOrder", 2)
+}
+
+func TestOrderHandler_GetByID(t *testing.T) {
+	logger := zerolog.Nop()
+
+	orderID := uuid.New()
+	testResponse := &model.OrderResponse{
+		ID: orderID,
+		Items: []model.OrderItem{
+			{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2},
+		},
+		Products: []model.Product{
+			{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(10.00)},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		mockReturn     *model.OrderResponse
+		mockError      error
+		expectedStatus int
+		expectService  bool
+		orderID        uuid.UUID
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String(),
+			mockReturn:     testResponse,
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+			orderID:        orderID,
+		},
+		{
+			name:           "Order not found - service returns nil",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + uuid.New().String(),
+			mockReturn:     nil,
+			mockError:      nil,
+			expectedStatus: http.StatusNotFound,
+			expectService:  true,
+			orderID:        uuid.New(),
+		},
+		{
+			name:           "Order not found - service returns error",
+			method:         http.MethodGet,
 			path:           "/api/orders/" + uuid.New().String(),
 			mockReturn:     nil,
 			mockError:      errors.New("order not found"),
@@ -302,3 +850,729 @@ func TestOrderHandler_GetByID(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderHandler_GetByRef(t *testing.T) {
+	logger := zerolog.Nop()
+
+	order := &model.OrderResponse{ID: uuid.New(), Status: model.OrderStatusPending}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedRef    string
+		mockReturn     *model.OrderResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "Order created with an idempotency key",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/checkout-7f3a",
+			expectedRef:    "checkout-7f3a",
+			mockReturn:     order,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Reference with an escaped slash",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/legacy%2F1001",
+			expectedRef:    "legacy/1001",
+			mockReturn:     order,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown reference",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/checkout-404",
+			expectedRef:    "checkout-404",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Lookup fails",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/checkout-7f3a",
+			expectedRef:    "checkout-7f3a",
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/checkout-7f3a",
+			expectedRef:    "checkout-7f3a",
+			mockError:      fmt.Errorf("failed to look up order reference: %w", model.ErrDatabaseUnavailable),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Missing reference",
+			method:         http.MethodGet,
+			path:           "/api/orders/by-ref/",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/orders/by-ref/checkout-7f3a",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			if tt.expectedRef != "" {
+				mockService.On("GetByRef", mock.Anything, tt.expectedRef).Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetByRef(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp model.OrderResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, order.ID, resp.ID)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderHandler_UpdateStatus(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/orders/" + orderID.String() + "/status"
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		identity           *middleware.Identity
+		mockReturn         *model.Order
+		mockError          error
+		expectedStatus     int
+		expectService      bool
+		expectedDelegation *model.Delegation
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			mockReturn:     &model.Order{ID: orderID, Status: model.OrderStatusCancelled},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:               "Admin on behalf of customer",
+			method:             http.MethodPatch,
+			path:               path,
+			body:               `{"status":"cancelled"}`,
+			identity:           &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			mockReturn:         &model.Order{ID: orderID, Status: model.OrderStatusCancelled},
+			expectedStatus:     http.StatusOK,
+			expectService:      true,
+			expectedDelegation: &model.Delegation{Admin: "admin:support", Customer: "customer-42"},
+		},
+		{
+			name:           "Invalid status",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			mockError:      model.ErrInvalidOrderStatus,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			mockError:      model.ErrOrderNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectService:  true,
+		},
+		{
+			name:           "Transition not allowed",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			mockError:      model.ErrStatusTransition,
+			expectedStatus: http.StatusConflict,
+			expectService:  true,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectService:  true,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPatch,
+			path:           path,
+			body:           `{"status":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid UUID format",
+			method:         http.MethodPatch,
+			path:           "/api/orders/invalid-uuid/status",
+			body:           `{"status":"cancelled"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"cancelled"}`,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			if tt.expectService {
+				mockService.On("UpdateStatus", mock.Anything, orderID, model.OrderStatusCancelled, tt.expectedDelegation).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			handler.UpdateStatus(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var order model.Order
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&order))
+				assert.Equal(t, model.OrderStatusCancelled, order.Status)
+			}
+
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_OverrideStatus(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/admin/orders/" + orderID.String() + "/status"
+	admin := &middleware.Identity{Subject: "admin:ops", Method: middleware.AuthMethodAdminKey}
+	override := &model.StatusOverride{Status: model.OrderStatusPending, Reason: "cancelled by mistake"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		identity       *middleware.Identity
+		expectedAdmin  string
+		mockReturn     *model.Order
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       admin,
+			expectedAdmin:  "admin:ops",
+			mockReturn:     &model.Order{ID: orderID, Status: model.OrderStatusPending},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Admin on behalf of customer cannot override",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Invalid override",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       admin,
+			expectedAdmin:  "admin:ops",
+			mockError:      model.ErrInvalidStatusOverride,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       admin,
+			expectedAdmin:  "admin:ops",
+			mockError:      model.ErrOrderNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Concurrent status change",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       admin,
+			expectedAdmin:  "admin:ops",
+			mockError:      model.ErrStatusTransition,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "API key",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       &middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"status":`,
+			identity:       admin,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid UUID format",
+			method:         http.MethodPost,
+			path:           "/api/admin/orders/invalid-uuid/status",
+			body:           `{"status":"pending","reason":"cancelled by mistake"}`,
+			identity:       admin,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPatch,
+			path:           path,
+			identity:       admin,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			if tt.expectedAdmin != "" {
+				mockService.On("OverrideStatus", mock.Anything, orderID, override, tt.expectedAdmin).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			handler.OverrideStatus(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedAdmin != "" {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "OverrideStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_List(t *testing.T) {
+	logger := zerolog.Nop()
+
+	web := "web"
+	orders := []model.Order{
+		{ID: uuid.New(), Source: &web, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		expectedFilter model.OrderFilter
+		mockError      error
+		expectedStatus int
+		expectService  bool
+	}{
+		{
+			name:           "Success with defaults",
+			method:         http.MethodGet,
+			expectedFilter: model.OrderFilter{Limit: 10},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "Success with source filter",
+			method:         http.MethodGet,
+			query:          "?source=web&limit=5&offset=10",
+			expectedFilter: model.OrderFilter{Source: "web", Limit: 5, Offset: 10},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "Product filter is admin only",
+			method:         http.MethodGet,
+			query:          "?productId=P001",
+			expectedFilter: model.OrderFilter{Limit: 10},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "Invalid limit",
+			method:         http.MethodGet,
+			query:          "?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid offset",
+			method:         http.MethodGet,
+			query:          "?offset=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			expectedFilter: model.OrderFilter{Limit: 10},
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectService:  true,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			expectedFilter: model.OrderFilter{Limit: 10},
+			mockError:      fmt.Errorf("failed to list orders: %w", model.ErrDatabaseUnavailable),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			if tt.expectService {
+				if tt.mockError != nil {
+					mockService.On("List", mock.Anything, tt.expectedFilter).Return(nil, model.Page{}, tt.mockError)
+				} else {
+					page := model.Page{Limit: tt.expectedFilter.Limit, Offset: tt.expectedFilter.Offset, Total: len(orders)}
+					mockService.On("List", mock.Anything, tt.expectedFilter).Return(orders, page, nil)
+				}
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/orders"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "List")
+			}
+		})
+	}
+}
+
+func TestOrderHandler_List_PageHeaders(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name          string
+		target        string
+		page          model.Page
+		expectedLinks []string
+	}{
+		{
+			name:          "First page",
+			target:        "/api/orders?source=web&limit=5",
+			page:          model.Page{Limit: 5, Offset: 0, Total: 12},
+			expectedLinks: []string{`</api/orders?limit=5&offset=5&source=web>; rel="next"`},
+		},
+		{
+			name:   "Middle page of versioned path",
+			target: "/api/v1/orders?source=web&limit=5&offset=5",
+			page:   model.Page{Limit: 5, Offset: 5, Total: 12},
+			expectedLinks: []string{
+				`</api/v1/orders?limit=5&offset=10&source=web>; rel="next"`,
+				`</api/v1/orders?limit=5&offset=0&source=web>; rel="prev"`,
+			},
+		},
+		{
+			name:          "Last page",
+			target:        "/api/orders?source=web&limit=5&offset=10",
+			page:          model.Page{Limit: 5, Offset: 10, Total: 12},
+			expectedLinks: []string{`</api/orders?limit=5&offset=5&source=web>; rel="prev"`},
+		},
+		{
+			name:   "Single page",
+			target: "/api/orders?source=web&limit=5",
+			page:   model.Page{Limit: 5, Offset: 0, Total: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			filter := model.OrderFilter{Source: "web", Limit: tt.page.Limit, Offset: tt.page.Offset}
+			mockService.On("List", mock.Anything, filter).Return([]model.Order{}, tt.page, nil)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, strconv.Itoa(tt.page.Total), w.Header().Get("X-Total-Count"))
+			assert.Equal(t, tt.expectedLinks, w.Header().Values("Link"))
+		})
+	}
+}
+
+func TestOrderHandler_Search(t *testing.T) {
+	logger := zerolog.Nop()
+
+	orders := []model.Order{
+		{ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		expectedFilter model.OrderFilter
+		mockError      error
+		expectedStatus int
+		expectService  bool
+	}{
+		{
+			name:           "Orders containing product",
+			method:         http.MethodGet,
+			query:          "?productId=P001",
+			expectedFilter: model.OrderFilter{ProductID: "P001", Limit: 10},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "With source and pagination",
+			method:         http.MethodGet,
+			query:          "?productId=P001&source=web&limit=50&offset=50",
+			expectedFilter: model.OrderFilter{ProductID: "P001", Source: "web", Limit: 50, Offset: 50},
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+		},
+		{
+			name:           "Missing product ID",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid limit",
+			method:         http.MethodGet,
+			query:          "?productId=P001&limit=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			query:          "?productId=P001",
+			expectedFilter: model.OrderFilter{ProductID: "P001", Limit: 10},
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectService:  true,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			query:          "?productId=P001",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService, logger)
+
+			if tt.expectService {
+				if tt.mockError != nil {
+					mockService.On("List", mock.Anything, tt.expectedFilter).Return(nil, model.Page{}, tt.mockError)
+				} else {
+					page := model.Page{Limit: tt.expectedFilter.Limit, Offset: tt.expectedFilter.Offset, Total: len(orders)}
+					mockService.On("List", mock.Anything, tt.expectedFilter).Return(orders, page, nil)
+				}
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/admin/orders"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.Search(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "List")
+			}
+		})
+	}
+}
+
+func TestOrderHandler_CountBySource(t *testing.T) {
+	logger := zerolog.Nop()
+
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService, logger)
+
+	counts := []model.SourceCount{{Source: "web", Orders: 4}}
+	mockService.On("CountBySource", mock.Anything).Return(counts, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/analytics/orders-by-source", nil)
+	w := httptest.NewRecorder()
+
+	handler.CountBySource(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result []model.SourceCount
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	assert.Equal(t, counts, result)
+}
+
+func TestOrderHandler_Golden(t *testing.T) {
+	logger := zerolog.Nop()
+
+	orderID := uuid.New()
+	web := "web"
+	order := model.Order{
+		ID:        orderID,
+		Status:    model.OrderStatusPending,
+		Source:    &web,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	response := &model.OrderResponse{
+		ID:     orderID,
+		Status: model.OrderStatusPending,
+		Items: []model.OrderItem{
+			{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2},
+		},
+		Products: []model.Product{
+			{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(10.00)},
+		},
+	}
+
+	// serve returns an order handler whose service expects a single call
+	serve := func(method string, args []any, returns ...any) func(t *testing.T) http.HandlerFunc {
+		return func(t *testing.T) http.HandlerFunc {
+			mockService := new(MockOrderService)
+			mockService.On(method, args...).Return(returns...)
+			t.Cleanup(func() { mockService.AssertExpectations(t) })
+
+			h := NewOrderHandler(mockService, logger)
+			switch method {
+			case "CreateOrder":
+				return h.Create
+			case "GetByID":
+				return h.GetByID
+			case "List":
+				return h.List
+			default:
+				return h.UpdateStatus
+			}
+		}
+	}
+
+	runGolden(t, []goldenCase{
+		{
+			name:   "Create order",
+			method: http.MethodPost,
+			target: "/api/orders",
+			body:   `{"items":[{"productId":"P001","quantity":2}]}`,
+			serve:  serve("CreateOrder", []any{mock.Anything, mock.Anything}, response, nil),
+		},
+		{
+			name:   "Create order with invalid promo code",
+			method: http.MethodPost,
+			target: "/api/orders",
+			body:   `{"couponCode":"INVALID","items":[{"productId":"P001","quantity":2}]}`,
+			serve:  serve("CreateOrder", []any{mock.Anything, mock.Anything}, nil, model.ErrInvalidPromoCode),
+		},
+		{
+			name:   "Get order",
+			method: http.MethodGet,
+			target: "/api/orders/" + orderID.String(),
+			serve:  serve("GetByID", []any{mock.Anything, orderID}, response, nil),
+		},
+		{
+			name:   "Get missing order",
+			method: http.MethodGet,
+			target: "/api/orders/" + orderID.String(),
+			serve:  serve("GetByID", []any{mock.Anything, orderID}, nil, nil),
+		},
+		{
+			name:   "Get order with invalid ID",
+			method: http.MethodGet,
+			target: "/api/orders/invalid-uuid",
+			serve: func(t *testing.T) http.HandlerFunc {
+				return NewOrderHandler(new(MockOrderService), logger).GetByID
+			},
+		},
+		{
+			name:   "List orders",
+			method: http.MethodGet,
+			target: "/api/orders?limit=1",
+			serve: serve("List", []any{mock.Anything, model.OrderFilter{Limit: 1}},
+				[]model.Order{order}, model.Page{Limit: 1, Total: 3}, nil),
+		},
+		{
+			name:   "Update order status",
+			method: http.MethodPatch,
+			target: "/api/orders/" + orderID.String() + "/status",
+			body:   `{"status":"cancelled"}`,
+			serve: serve("UpdateStatus", []any{mock.Anything, orderID, model.OrderStatusCancelled, (*model.Delegation)(nil)},
+				&model.Order{ID: orderID, Status: model.OrderStatusCancelled, CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil),
+		},
+		{
+			name:   "Update order status with invalid transition",
+			method: http.MethodPatch,
+			target: "/api/orders/" + orderID.String() + "/status",
+			body:   `{"status":"cancelled"}`,
+			serve: serve("UpdateStatus", []any{mock.Anything, orderID, model.OrderStatusCancelled, (*model.Delegation)(nil)},
+				nil, model.ErrStatusTransition),
+		},
+	})
+}
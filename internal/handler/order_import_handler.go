@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// OrderImportHandler handles the migration of historical orders. Imports
+// skip coupon validation and pricing, so they are limited to admin keys.
+type OrderImportHandler struct {
+	service service.OrderImportService
+	logger  zerolog.Logger
+}
+
+// NewOrderImportHandler creates a new order import handler.
+func NewOrderImportHandler(service service.OrderImportService, logger zerolog.Logger) *OrderImportHandler {
+	return &OrderImportHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "order_import").Logger(),
+	}
+}
+
+// Import handles POST /api/admin/orders/import requests. The file arrives as
+// the "file" field of a multipart form, in the format named by the format
+// query parameter: csv (the default) or jsonl. Orders that were not imported
+// are reported as batch entries indexed by line number.
+func (h *OrderImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		writeError(w, http.StatusForbidden, "orders are imported with admin keys only", h.logger)
+		return
+	}
+
+	format := model.OrderImportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = model.OrderImportCSV
+	}
+	if !format.Valid() {
+		writeError(w, http.StatusBadRequest, "format must be csv or jsonl", h.logger)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	file := formFile(r, "file")
+	if file == nil {
+		writeError(w, http.StatusBadRequest, "multipart/form-data body with a file field is required", h.logger)
+		return
+	}
+
+	result, err := h.service.ImportOrders(r.Context(), file, format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case err == model.ErrInvalidOrderImport:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		case errors.As(err, &tooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, "import file is too large", h.logger)
+		default:
+			if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to import orders", h.logger)
+		}
+		return
+	}
+
+	failed := make([]BatchEntry, len(result.Errors))
+	for i, e := range result.Errors {
+		failed[i] = BatchEntry{Index: e.Row, ID: e.Ref, Status: batchEntryStatus(e.Code), Code: e.Code, Error: e.Error}
+	}
+	writeBatch(w, result.Imported, failed)
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderImportService is a mock implementation of OrderImportService.
+type MockOrderImportService struct {
+	mock.Mock
+}
+
+func (m *MockOrderImportService) ImportOrders(ctx context.Context, r io.Reader, format model.OrderImportFormat) (*model.OrderImportResult, error) {
+	args := m.Called(ctx, r, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderImportResult), args.Error(1)
+}
+
+func TestOrderImportHandler_Import(t *testing.T) {
+	logger := zerolog.Nop()
+	input := "order_ref,created_at,product_id,quantity,unit_price\nL-1,2024-05-01T10:00:00Z,P001,1,10\n"
+	admin := &middleware.Identity{Subject: "ops", Method: middleware.AuthMethodAdminKey}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		identity       *middleware.Identity
+		contentType    string
+		expectedFormat model.OrderImportFormat
+		mockReturn     *model.OrderImportResult
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "All orders imported",
+			method:         http.MethodPost,
+			identity:       admin,
+			expectedFormat: model.OrderImportCSV,
+			mockReturn:     &model.OrderImportResult{Imported: 3, Errors: []model.OrderImportError{}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"succeeded":3,"failed":0,"entries":[]}`,
+		},
+		{
+			name:           "Some orders rejected",
+			method:         http.MethodPost,
+			query:          "?format=jsonl",
+			identity:       admin,
+			expectedFormat: model.OrderImportJSONL,
+			mockReturn: &model.OrderImportResult{
+				Imported: 1,
+				Errors: []model.OrderImportError{
+					{Row: 2, Ref: "L-2", Code: model.ErrCodeProductNotFound, Error: "Product not found"},
+					{Row: 3, Ref: "L-1", Code: model.ErrCodeOrderImported, Error: model.ErrOrderImported.Message},
+				},
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expectedBody: fmt.Sprintf(`{"succeeded":1,"failed":2,"entries":[
+				{"index":2,"id":"L-2","status":404,"code":"PRODUCT_NOT_FOUND","error":"Product not found"},
+				{"index":3,"id":"L-1","status":409,"code":"ORDER_ALREADY_IMPORTED","error":%q}
+			]}`, model.ErrOrderImported.Message),
+		},
+		{
+			name:           "Invalid header",
+			method:         http.MethodPost,
+			identity:       admin,
+			expectedFormat: model.OrderImportCSV,
+			mockError:      model.ErrInvalidOrderImport,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Upload too large",
+			method:         http.MethodPost,
+			identity:       admin,
+			expectedFormat: model.OrderImportCSV,
+			mockError:      fmt.Errorf("failed to read order import: %w", &http.MaxBytesError{Limit: maxImportBytes}),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodPost,
+			identity:       admin,
+			expectedFormat: model.OrderImportCSV,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Unknown format",
+			method:         http.MethodPost,
+			query:          "?format=xml",
+			identity:       admin,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not multipart",
+			method:         http.MethodPost,
+			identity:       admin,
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Tenant key",
+			method:         http.MethodPost,
+			identity:       &middleware.Identity{Subject: "brand-a", Method: middleware.AuthMethodTenantKey},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin acting on behalf of a customer",
+			method:         http.MethodPost,
+			identity:       &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "No identity",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Wrong method",
+			method:         http.MethodGet,
+			identity:       admin,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderImportService)
+			if tt.expectedFormat != "" {
+				mockService.On("ImportOrders", mock.Anything, mock.Anything, tt.expectedFormat).
+					Run(func(args mock.Arguments) {
+						data, err := io.ReadAll(args.Get(1).(io.Reader))
+						require.NoError(t, err)
+						assert.Equal(t, input, string(data))
+					}).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			body, contentType := multipartBody(t, "file", input)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+
+			h := NewOrderImportHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, "/api/admin/orders/import"+tt.query, body)
+			req.Header.Set("Content-Type", contentType)
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			h.Import(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockReturn != nil {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedFormat == "" {
+				mockService.AssertNotCalled(t, "ImportOrders", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
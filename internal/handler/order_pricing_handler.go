@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// OrderPricingHandler handles repricing of orders edited before fulfillment.
+type OrderPricingHandler struct {
+	service service.OrderPricingService
+	logger  zerolog.Logger
+}
+
+// NewOrderPricingHandler creates a new order pricing handler.
+func NewOrderPricingHandler(service service.OrderPricingService, logger zerolog.Logger) *OrderPricingHandler {
+	return &OrderPricingHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "order_pricing").Logger(),
+	}
+}
+
+// Recalculate handles POST /api/orders/{id}/recalculate requests. The order
+// is repriced against current prices and coupon rules; a changed result is
+// stored as a new pricing version recording the authenticated caller.
+func (h *OrderPricingHandler) Recalculate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/recalculate", h.logger)
+	if !ok {
+		return
+	}
+
+	actor, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Recalculate(r.Context(), orderID, actor)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "failed to recalculate order pricing"
+
+		switch err {
+		case model.ErrOrderNotFound:
+			status = http.StatusNotFound
+			message = "order not found"
+		case model.ErrOrderCancelled:
+			status = http.StatusConflict
+			message = "cancelled orders cannot be repriced"
+		case model.ErrFulfillmentStarted:
+			status = http.StatusConflict
+			message = "orders can only be repriced before fulfillment starts"
+		case model.ErrPricingConflict:
+			status = http.StatusConflict
+			message = "order pricing was changed by another request, please retry"
+		case model.ErrProductNotFound:
+			status = http.StatusConflict
+			message = "one or more ordered products are no longer in the catalogue"
+		default:
+			if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+		}
+
+		writeError(w, status, message, h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderPricingService is a mock implementation of OrderPricingService.
+type MockOrderPricingService struct {
+	mock.Mock
+}
+
+func (m *MockOrderPricingService) Recalculate(ctx context.Context, orderID uuid.UUID, actor string) (*model.OrderRepricing, error) {
+	args := m.Called(ctx, orderID, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderRepricing), args.Error(1)
+}
+
+func TestOrderPricingHandler_Recalculate(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/orders/" + orderID.String() + "/recalculate"
+
+	repricing := &model.OrderRepricing{
+		OrderID: orderID,
+		Version: 2,
+		Changed: true,
+		Pricing: &model.PriceBreakdown{Currency: "AUD", Subtotal: model.NewMoney(24.00), Total: model.NewMoney(24.00)},
+		Diff: model.PricingDiff{
+			Subtotal: model.NewMoney(4.00),
+			Total:    model.NewMoney(4.00),
+			Lines:    []model.PriceLineDiff{{ProductID: "P001", PreviousQuantity: 2, Quantity: 2, PreviousUnitPrice: model.NewMoney(10.00), UnitPrice: model.NewMoney(12.00)}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		admin          string
+		identity       *middleware.Identity
+		mockReturn     *model.OrderRepricing
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Repriced",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockReturn:     repricing,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      model.ErrOrderNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Fulfillment started",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      model.ErrFulfillmentStarted,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Cancelled order",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      model.ErrOrderCancelled,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Concurrent recalculation",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      model.ErrPricingConflict,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Product no longer in the catalogue",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      model.ErrProductNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin-1",
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing admin identity",
+			method:         http.MethodPost,
+			path:           path,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Client certificate is not an admin",
+			method:         http.MethodPost,
+			path:           path,
+			identity:       &middleware.Identity{Subject: "spiffe://cluster/ns/support", Method: middleware.AuthMethodMTLS},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Invalid order ID",
+			method:         http.MethodPost,
+			path:           "/api/orders/not-a-uuid/recalculate",
+			admin:          "admin-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           path,
+			admin:          "admin-1",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockOrderPricingService)
+			if tt.expectService {
+				mockService.On("Recalculate", mock.Anything, orderID, tt.admin).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewOrderPricingHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			h.Recalculate(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "Recalculate", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+
+			if tt.expectedStatus == http.StatusOK {
+				var body map[string]any
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, 2.0, body["version"])
+				assert.Equal(t, true, body["changed"])
+				diff := body["diff"].(map[string]any)
+				assert.Equal(t, 4.0, diff["total"])
+				assert.Len(t, diff["lines"], 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// PriceChangeHandler handles product price change and approval HTTP requests.
+type PriceChangeHandler struct {
+	service service.PriceChangeService
+	logger  zerolog.Logger
+}
+
+// NewPriceChangeHandler creates a new price change handler.
+func NewPriceChangeHandler(service service.PriceChangeService, logger zerolog.Logger) *PriceChangeHandler {
+	return &PriceChangeHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "price_change").Logger(),
+	}
+}
+
+// UpdatePrice handles PUT /api/admin/products/{id}/price requests.
+// Responds 200 when the change was applied and 202 when it awaits approval.
+func (h *PriceChangeHandler) UpdatePrice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	// Expecting path: /api/admin/products/{id}/price
+	productID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/products/"), "/price")
+	if productID == "" || strings.Contains(productID, "/") {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	admin, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var req model.PriceUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+	if req.Price == nil {
+		writeError(w, http.StatusBadRequest, "price is required", h.logger)
+		return
+	}
+
+	change, err := h.service.RequestPriceChange(r.Context(), productID, *req.Price, admin)
+	if err != nil {
+		h.writePriceChangeError(w, err, "failed to update price")
+		return
+	}
+
+	status := http.StatusOK
+	if change.Status == model.PriceChangeStatusPending {
+		status = http.StatusAccepted
+	}
+
+	writeJSON(w, status, change)
+}
+
+// ListPending handles GET /api/admin/price-changes requests.
+func (h *PriceChangeHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	changes, err := h.service.ListPending(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve price changes", h.logger)
+		return
+	}
+
+	if changes == nil {
+		changes = []model.PriceChange{}
+	}
+
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// Decide handles POST /api/admin/price-changes/{id}/approve and
+// POST /api/admin/price-changes/{id}/reject requests.
+func (h *PriceChangeHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	// Expecting path: /api/admin/price-changes/{id}/{action}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/admin/price-changes/"), "/")
+	if len(parts) != 2 {
+		writeError(w, http.StatusNotFound, "not found", h.logger)
+		return
+	}
+
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid price change ID format", h.logger)
+		return
+	}
+
+	admin, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var change *model.PriceChange
+	switch parts[1] {
+	case "approve":
+		change, err = h.service.Approve(r.Context(), id, admin)
+	case "reject":
+		change, err = h.service.Reject(r.Context(), id, admin)
+	default:
+		writeError(w, http.StatusNotFound, "not found", h.logger)
+		return
+	}
+	if err != nil {
+		h.writePriceChangeError(w, err, "failed to decide price change")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, change)
+}
+
+// writePriceChangeError maps price change domain errors to HTTP responses.
+func (h *PriceChangeHandler) writePriceChangeError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case model.ErrInvalidPrice:
+		status = http.StatusBadRequest
+		message = "invalid price"
+	case model.ErrProductNotFound:
+		status = http.StatusNotFound
+		message = "product not found"
+	case model.ErrPriceChangeNotFound:
+		status = http.StatusNotFound
+		message = "price change not found"
+	case model.ErrPriceChangePending:
+		status = http.StatusConflict
+		message = "product already has a price change awaiting approval"
+	case model.ErrPriceChangeNotPending:
+		status = http.StatusConflict
+		message = "price change has already been decided"
+	case model.ErrSelfApproval:
+		status = http.StatusForbidden
+		message = "price changes must be approved by a different admin"
+	default:
+		if writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+	}
+
+	writeError(w, status, message, h.logger)
+}
+
+// adminFromRequest returns the subject of the admin making the request:
+// callers authenticated with an admin-scoped key, each identifying one admin.
+// This matches middleware.AdminScope, so handlers outside the admin route
+// group accept the same admins as those inside it. The shared client API
+// key, tenant keys and client certificates are not admins. Nor is
+// an admin acting on behalf of a customer, who is limited to what the
+// customer may do.
+func adminFromRequest(r *http.Request) (string, bool) {
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		return "", false
+	}
+	if identity.Subject == "" {
+		return "", false
+	}
+	return identity.Subject, true
+}
+
+// requireAdmin returns the admin making the request, or writes 401
+// Unauthorized for requests without an identity and 403 Forbidden for
+// callers that are not admins.
+func requireAdmin(w http.ResponseWriter, r *http.Request, logger zerolog.Logger) (string, bool) {
+	if admin, ok := adminFromRequest(r); ok {
+		return admin, true
+	}
+	if identity, ok := middleware.IdentityFromContext(r.Context()); ok && identity.Subject != "" {
+		writeError(w, http.StatusForbidden, "an admin key is required", logger)
+		return "", false
+	}
+	writeError(w, http.StatusUnauthorized, "admin identity is required", logger)
+	return "", false
+}
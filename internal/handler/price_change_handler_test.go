@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPriceChangeService is a mock implementation of PriceChangeService.
+type MockPriceChangeService struct {
+	mock.Mock
+}
+
+func (m *MockPriceChangeService) RequestPriceChange(ctx context.Context, productID string, newPrice model.Money, requestedBy string) (*model.PriceChange, error) {
+	args := m.Called(ctx, productID, newPrice, requestedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeService) RequestSale(ctx context.Context, productID string, sale model.ProductSale, requestedBy string) (*model.PriceChange, error) {
+	args := m.Called(ctx, productID, sale, requestedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeService) ListPending(ctx context.Context) ([]model.PriceChange, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeService) Approve(ctx context.Context, id uuid.UUID, approvedBy string) (*model.PriceChange, error) {
+	args := m.Called(ctx, id, approvedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeService) Reject(ctx context.Context, id uuid.UUID, rejectedBy string) (*model.PriceChange, error) {
+	args := m.Called(ctx, id, rejectedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+// withAdmin attaches an authenticated admin identity to the request.
+func withAdmin(req *http.Request, subject string) *http.Request {
+	ctx := middleware.WithIdentity(req.Context(), middleware.Identity{Subject: subject, Method: middleware.AuthMethodAdminKey})
+	return req.WithContext(ctx)
+}
+
+func TestPriceChangeHandler_UpdatePrice(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		admin          string
+		mockReturn     *model.PriceChange
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Applied immediately",
+			path:           "/api/admin/products/P001/price",
+			body:           `{"price": 11.5}`,
+			admin:          "admin-a",
+			mockReturn:     &model.PriceChange{ProductID: "P001", Status: model.PriceChangeStatusApplied},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Awaiting approval",
+			path:           "/api/admin/products/P001/price",
+			body:           `{"price": 11.5}`,
+			admin:          "admin-a",
+			mockReturn:     &model.PriceChange{ProductID: "P001", Status: model.PriceChangeStatusPending},
+			expectService:  true,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "Already pending",
+			path:           "/api/admin/products/P001/price",
+			body:           `{"price": 11.5}`,
+			admin:          "admin-a",
+			mockError:      model.ErrPriceChangePending,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Product not found",
+			path:           "/api/admin/products/P001/price",
+			body:           `{"price": 11.5}`,
+			admin:          "admin-a",
+			mockError:      model.ErrProductNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing price",
+			path:           "/api/admin/products/P001/price",
+			body:           `{}`,
+			admin:          "admin-a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing identity",
+			path:           "/api/admin/products/P001/price",
+			body:           `{"price": 11.5}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPriceChangeService)
+			h := NewPriceChangeHandler(mockService, logger)
+
+			if tt.expectService {
+				mockService.On("RequestPriceChange", mock.Anything, "P001", model.NewMoney(11.5), tt.admin).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, tt.path, bytes.NewBufferString(tt.body))
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			w := httptest.NewRecorder()
+
+			h.UpdatePrice(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceChangeHandler_Decide(t *testing.T) {
+	logger := zerolog.Nop()
+	id := uuid.New()
+	decidedBy := "admin-b"
+
+	tests := []struct {
+		name           string
+		path           string
+		mockMethod     string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "Approve",
+			path:           "/api/admin/price-changes/" + id.String() + "/approve",
+			mockMethod:     "Approve",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Reject",
+			path:           "/api/admin/price-changes/" + id.String() + "/reject",
+			mockMethod:     "Reject",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Self approval",
+			path:           "/api/admin/price-changes/" + id.String() + "/approve",
+			mockMethod:     "Approve",
+			mockError:      model.ErrSelfApproval,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Already decided",
+			path:           "/api/admin/price-changes/" + id.String() + "/approve",
+			mockMethod:     "Approve",
+			mockError:      model.ErrPriceChangeNotPending,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Unknown action",
+			path:           "/api/admin/price-changes/" + id.String() + "/cancel",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID",
+			path:           "/api/admin/price-changes/not-a-uuid/approve",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPriceChangeService)
+			h := NewPriceChangeHandler(mockService, logger)
+
+			if tt.mockMethod != "" {
+				if tt.mockError != nil {
+					mockService.On(tt.mockMethod, mock.Anything, id, decidedBy).Return(nil, tt.mockError)
+				} else {
+					mockService.On(tt.mockMethod, mock.Anything, id, decidedBy).
+						Return(&model.PriceChange{ID: id, DecidedBy: &decidedBy}, nil)
+				}
+			}
+
+			req := withAdmin(httptest.NewRequest(http.MethodPost, tt.path, nil), decidedBy)
+			w := httptest.NewRecorder()
+
+			h.Decide(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceChangeHandler_AdminIdentity(t *testing.T) {
+	logger := zerolog.Nop()
+	id := uuid.New()
+	approvePath := "/api/admin/price-changes/" + id.String() + "/approve"
+
+	tests := []struct {
+		name           string
+		identity       middleware.Identity
+		expectDecider  string
+		expectedStatus int
+	}{
+		{
+			name:           "Shared API key",
+			identity:       middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Tenant key",
+			identity:       middleware.Identity{Subject: "tenant:acme", Method: middleware.AuthMethodTenantKey, Tenant: "acme"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Client certificate",
+			identity:       middleware.Identity{Subject: "spiffe://cluster/ns/support", Method: middleware.AuthMethodMTLS},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Second admin key",
+			identity:       middleware.Identity{Subject: "admin:bob", Method: middleware.AuthMethodAdminKey},
+			expectDecider:  "admin:bob",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Admin acting on behalf of a customer",
+			identity:       middleware.Identity{Subject: "customer-1", Method: middleware.AuthMethodImpersonated, Actor: "admin:bob"},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPriceChangeService)
+			h := NewPriceChangeHandler(mockService, logger)
+
+			if tt.expectDecider != "" {
+				mockService.On("Approve", mock.Anything, id, tt.expectDecider).
+					Return(&model.PriceChange{ID: id, DecidedBy: &tt.expectDecider}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, approvePath, nil)
+			req = req.WithContext(middleware.WithIdentity(req.Context(), tt.identity))
+			w := httptest.NewRecorder()
+
+			h.Decide(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+
+			// Nor may they request price changes
+			if tt.expectDecider == "" {
+				req = httptest.NewRequest(http.MethodPut, "/api/admin/products/P001/price", bytes.NewBufferString(`{"price": 11.5}`))
+				req = req.WithContext(middleware.WithIdentity(req.Context(), tt.identity))
+				w = httptest.NewRecorder()
+
+				h.UpdatePrice(w, req)
+
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				mockService.AssertNotCalled(t, "RequestPriceChange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
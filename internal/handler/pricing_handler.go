@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// PricingHandler handles pricing-related HTTP requests.
+type PricingHandler struct {
+	service service.PricingService
+	logger  zerolog.Logger
+}
+
+// NewPricingHandler creates a new pricing handler.
+func NewPricingHandler(service service.PricingService, logger zerolog.Logger) *PricingHandler {
+	return &PricingHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "pricing").Logger(),
+	}
+}
+
+// Preview handles POST /api/pricing/preview requests.
+// It prices a cart-like payload without creating an order.
+func (h *PricingHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req model.PricingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	breakdown, err := h.service.Preview(r.Context(), &req)
+	if err != nil {
+		status, message := previewErrorStatus(err)
+		writeError(w, status, message, h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, breakdown)
+}
+
+// previewErrorStatus maps an error pricing a cart-like request to an HTTP
+// status and message.
+func previewErrorStatus(err error) (int, string) {
+	status := http.StatusInternalServerError
+	message := "failed to compute price preview"
+
+	switch err {
+	case model.ErrInvalidPromoCode:
+		status = http.StatusBadRequest
+		message = "invalid promo code"
+	case model.ErrInvalidPromoFormat:
+		status = http.StatusBadRequest
+		message = "invalid promo code format"
+	case model.ErrInvalidPromoLength:
+		status = http.StatusBadRequest
+		message = "invalid promo code length"
+	case model.ErrCouponNotApplicable:
+		status = http.StatusBadRequest
+		message = "promo code does not apply to any items in the order"
+	case model.ErrCouponExpired:
+		status = http.StatusBadRequest
+		message = "promo code has expired"
+	case model.ErrCouponMinSubtotal:
+		status = http.StatusBadRequest
+		message = "order subtotal is below the promo code's minimum"
+	case model.ErrCouponFirstOrderOnly:
+		status = http.StatusConflict
+		message = "promo code is only valid on a customer's first order"
+	case model.ErrCouponNotForCustomer:
+		status = http.StatusConflict
+		message = "promo code is not valid for this customer"
+	case model.ErrCouponSegment:
+		status = http.StatusConflict
+		message = "promo code is only valid for customers in its segments"
+	case model.ErrCouponValidationTimeout:
+		status = http.StatusServiceUnavailable
+		message = "promo code validation timed out, please retry"
+	case model.ErrCouponsLoading:
+		status = http.StatusServiceUnavailable
+		message = "promo codes are still loading, please retry"
+	case model.ErrProductNotFound:
+		status = http.StatusBadRequest
+		message = "one or more products not found"
+	case model.ErrInvalidQuantity:
+		status = http.StatusBadRequest
+		message = "invalid quantity"
+	case model.ErrUnsupportedCurrency:
+		status = http.StatusBadRequest
+		message = "unsupported currency"
+	case model.ErrInvalidAddress:
+		status = http.StatusBadRequest
+		message = "address country is required"
+	default:
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "must contain") ||
+			strings.Contains(err.Error(), "nil") {
+			status = http.StatusBadRequest
+			message = err.Error()
+		}
+	}
+
+	return status, message
+}
+
+// ValidateCoupon handles POST /api/coupons/validate requests.
+// It checks a promo code on its own so clients can flag bad codes before
+// checkout. Invalid codes are reported in the body with a 200 status.
+func (h *PricingHandler) ValidateCoupon(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req model.CouponValidationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	if strings.TrimSpace(req.Code) == "" {
+		writeError(w, http.StatusBadRequest, "coupon code is required", h.logger)
+		return
+	}
+
+	result, err := h.service.ValidateCoupon(r.Context(), req.Code)
+	if err != nil {
+		if err == model.ErrCouponsLoading {
+			writeError(w, http.StatusServiceUnavailable, "promo codes are still loading, please retry", h.logger)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to validate coupon code", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
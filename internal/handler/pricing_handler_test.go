@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPricingService is a mock implementation of PricingService.
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) Preview(ctx context.Context, req *model.PricingRequest) (*model.PriceBreakdown, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceBreakdown), args.Error(1)
+}
+
+func (m *MockPricingService) PreviewProductCoupon(ctx context.Context, product *model.Product, code string) (*model.ProductCouponPreview, error) {
+	args := m.Called(ctx, product, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductCouponPreview), args.Error(1)
+}
+
+func (m *MockPricingService) ValidateCoupon(ctx context.Context, code string) (*model.CouponValidation, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponValidation), args.Error(1)
+}
+
+func TestPricingHandler_Preview(t *testing.T) {
+	logger := zerolog.Nop()
+
+	breakdown := &model.PriceBreakdown{
+		Currency: "AUD",
+		Lines: []model.PriceLine{
+			{ProductID: "P001", Name: "Product 1", Quantity: 2, UnitPrice: model.NewMoney(10.00), LineTotal: model.NewMoney(20.00)},
+		},
+		Subtotal: model.NewMoney(20.00),
+		Total:    model.NewMoney(20.00),
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		mockReturn     *model.PriceBreakdown
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"currency":"AUD"}`,
+			mockReturn:     breakdown,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid promo code",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"BADCODE1"}`,
+			mockError:      model.ErrInvalidPromoCode,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid promo code format",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"CODE'--1"}`,
+			mockError:      model.ErrInvalidPromoFormat,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Promo code does not apply to items",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"WAFFLE20"}`,
+			mockError:      model.ErrCouponNotApplicable,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Expired promo code",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"SUMMER123"}`,
+			mockError:      model.ErrCouponExpired,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Promo code minimum not met",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"TENOFF50"}`,
+			mockError:      model.ErrCouponMinSubtotal,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "First-order-only promo code",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"WELCOME15"}`,
+			mockError:      model.ErrCouponFirstOrderOnly,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Customer segment promo code",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"VIPONLY20"}`,
+			mockError:      model.ErrCouponSegment,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Promo code validation timeout",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"couponCode":"HAPPYHRS"}`,
+			mockError:      model.ErrCouponValidationTimeout,
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Unsupported currency",
+			method:         http.MethodPost,
+			body:           `{"items":[{"productId":"P001","quantity":2}],"currency":"EUR"}`,
+			mockError:      model.ErrUnsupportedCurrency,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Wrong method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPricingService)
+			h := NewPricingHandler(mockService, logger)
+
+			if tt.expectService {
+				mockService.On("Preview", mock.Anything, mock.AnythingOfType("*model.PricingRequest")).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/pricing/preview", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Preview(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got model.PriceBreakdown
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, *breakdown, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingHandler_ValidateCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		mockReturn     *model.CouponValidation
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Valid code",
+			method:         http.MethodPost,
+			body:           `{"code":"HAPPYHRS"}`,
+			mockReturn:     &model.CouponValidation{Code: "HAPPYHRS", Valid: true, MatchCount: 2},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid code is reported, not failed",
+			method:         http.MethodPost,
+			body:           `{"code":"HAPPYHRS"}`,
+			mockReturn:     &model.CouponValidation{Code: "HAPPYHRS", Reason: model.ErrCodeInvalidPromoCode, MatchCount: 1},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Promo codes still loading",
+			method:         http.MethodPost,
+			body:           `{"code":"HAPPYHRS"}`,
+			mockError:      model.ErrCouponsLoading,
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Service failure",
+			method:         http.MethodPost,
+			body:           `{"code":"HAPPYHRS"}`,
+			mockError:      errors.New("boom"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing code",
+			method:         http.MethodPost,
+			body:           `{"code":" "}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Wrong method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPricingService)
+			h := NewPricingHandler(mockService, logger)
+
+			if tt.expectService {
+				if tt.mockError != nil {
+					mockService.On("ValidateCoupon", mock.Anything, "HAPPYHRS").Return(nil, tt.mockError)
+				} else {
+					mockService.On("ValidateCoupon", mock.Anything, "HAPPYHRS").Return(tt.mockReturn, nil)
+				}
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/coupons/validate", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ValidateCoupon(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got model.CouponValidation
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, *tt.mockReturn, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,92 +1,173 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
+	"mini-kart/internal/model"
 	"mini-kart/internal/service"
 
 	"github.com/rs/zerolog"
 )
 
+// maxImportBytes caps the size of a product or order import upload.
+const maxImportBytes = 32 << 20
+
 // ProductHandler handles product-related HTTP requests.
 type ProductHandler struct {
-	service service.ProductService
-	logger  zerolog.Logger
+	service      service.ProductService
+	pricing      service.PricingService
+	currencies   service.CurrencyService
+	priceChanges service.PriceChangeService
+	logger       zerolog.Logger
+}
+
+// ProductHandlerOption configures optional ProductHandler behaviour.
+type ProductHandlerOption func(*ProductHandler)
+
+// WithCouponPreviews enables the ?couponCode= preview on GET /api/products/{id}.
+// Without it the parameter is ignored.
+func WithCouponPreviews(pricing service.PricingService) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.pricing = pricing
+	}
+}
+
+// WithProductCurrencies enables the ?currency= parameter and Accept-Currency
+// header on product reads, showing prices converted into that currency.
+// Without it both are ignored.
+func WithProductCurrencies(currencies service.CurrencyService) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.currencies = currencies
+	}
+}
+
+// WithSaleApprovals schedules sales through the price change workflow, so a
+// sale discounting the price by more than the approval threshold waits for a
+// second admin. Without it sales are scheduled directly.
+func WithSaleApprovals(priceChanges service.PriceChangeService) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.priceChanges = priceChanges
+	}
 }
 
 // NewProductHandler creates a new product handler.
-func NewProductHandler(service service.ProductService, logger zerolog.Logger) *ProductHandler {
-	return &ProductHandler{
+func NewProductHandler(service service.ProductService, logger zerolog.Logger, opts ...ProductHandlerOption) *ProductHandler {
+	h := &ProductHandler{
 		service: service,
 		logger:  logger.With().Str("handler", "product").Logger(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// GetAll handles GET /api/products requests with pagination.
+// GetAll handles GET /api/products requests with category filtering, sorting
+// and pagination, in the list representation negotiated with the Accept
+// header.
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
 		return
 	}
 
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		return
+	}
 
-	limit := 10 // default
-	if limitStr != "" {
-		var err error
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid limit parameter", h.logger)
+	profile, ok := negotiate(w, r, listProfileVersions, h.logger)
+	if !ok {
+		return
+	}
+
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
+		return
+	}
+
+	products, page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		if err == model.ErrInvalidProductSort {
+			writeError(w, http.StatusBadRequest, "sort must be name, price or created_at and order asc or desc", h.logger)
 			return
 		}
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve products", h.logger)
+		return
+	}
+
+	if conversion.requested() {
+		for i := range products {
+			products[i] = products[i].InCurrency(conversion.currency, conversion.rate)
+		}
+	}
+
+	writeList(w, r, profile, page, products)
+}
+
+// parseProductFilter reads the category, sort and pagination query
+// parameters shared by the product listings.
+func parseProductFilter(query url.Values) (model.ProductFilter, error) {
+	filter := model.ProductFilter{
+		Category: query.Get("category"),
+		Sort:     model.ProductSort(query.Get("sort")),
+		Order:    model.SortOrder(query.Get("order")),
+		Limit:    10, // default
 	}
 
-	offset := 0 // default
-	if offsetStr != "" {
-		var err error
-		offset, err = strconv.Atoi(offsetStr)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid offset parameter", h.logger)
-			return
+			return filter, errors.New("invalid limit parameter")
 		}
+		filter.Limit = limit
 	}
 
-	products, err := h.service.GetAll(r.Context(), limit, offset)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to retrieve products", h.logger)
-		return
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			return filter, errors.New("invalid offset parameter")
+		}
+		filter.Offset = offset
 	}
 
-	writeJSON(w, http.StatusOK, products)
+	return filter, nil
 }
 
-// GetByID handles GET /api/products/{id} requests.
+// GetByID handles GET /api/products/{id} and /api/admin/products/{id}
+// requests. An optional couponCode query parameter adds a preview of the
+// product's price with that code.
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
 		return
 	}
 
-	// Extract product ID from path
-	// Expecting path: /api/products/{id}
-	// Simple extraction without routing library
-	path := r.URL.Path
-	if len(path) < len("/api/products/") {
+	productID, ok := productIDFromPath(r.URL.Path)
+	if !ok {
 		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
 		return
 	}
-	productID := path[len("/api/products/"):]
 
-	if productID == "" {
-		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
 		return
 	}
 
 	product, err := h.service.GetByID(r.Context(), productID)
 	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
 		writeError(w, http.StatusNotFound, "product not found", h.logger)
 		return
 	}
@@ -96,5 +177,340 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	code := strings.TrimSpace(r.URL.Query().Get("couponCode"))
+	if code == "" || h.pricing == nil {
+		if conversion.requested() {
+			*product = product.InCurrency(conversion.currency, conversion.rate)
+		}
+		writeJSON(w, http.StatusOK, product)
+		return
+	}
+
+	// The preview is decoration for the product page, so a coupon that
+	// cannot be evaluated right now leaves it off rather than failing the request
+	detail := model.ProductDetail{Product: *product}
+	preview, err := h.pricing.PreviewProductCoupon(r.Context(), product, code)
+	if err != nil {
+		h.logger.Warn().Err(err).Str("product_id", productID).Msg("omitting coupon preview")
+	} else {
+		detail.CouponPreview = preview
+	}
+
+	// Coupons are evaluated in the catalogue currency, so the preview is
+	// converted with the product
+	if conversion.requested() {
+		detail.Product = detail.Product.InCurrency(conversion.currency, conversion.rate)
+		if detail.CouponPreview != nil {
+			converted := detail.CouponPreview.InCurrency(conversion.currency, conversion.rate)
+			detail.CouponPreview = &converted
+		}
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// Compare handles GET /api/products/compare?ids=a,b,c requests, setting the
+// products side by side with one row per attribute.
+func (h *ProductHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var ids []string
+	if raw := r.URL.Query().Get("ids"); raw != "" {
+		ids = strings.Split(raw, ",")
+	}
+
+	conversion, ok := convertPrices(w, r, h.currencies, h.logger)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service.Compare(r.Context(), ids)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidComparison:
+			writeError(w, http.StatusBadRequest, "between 2 and 4 distinct product IDs are required", h.logger)
+		case model.ErrProductNotFound:
+			writeError(w, http.StatusNotFound, "one or more products not found", h.logger)
+		default:
+			if writeUnavailable(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to compare products", h.logger)
+		}
+		return
+	}
+
+	if conversion.requested() {
+		*comparison = comparison.InCurrency(conversion.currency, conversion.rate)
+	}
+
+	writeJSON(w, http.StatusOK, comparison)
+}
+
+// Archive handles POST /api/admin/products/archive requests.
+// Responds 409 with the conflicts per product ID when nothing was archived.
+func (h *ProductHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	admin, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var req model.ProductArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	result, err := h.service.ArchiveProducts(r.Context(), req.ProductIDs, admin)
+	if err != nil {
+		switch err {
+		case model.ErrProductArchiveConflict:
+			writeJSON(w, http.StatusConflict, result)
+		case model.ErrInvalidProductIDs:
+			writeError(w, http.StatusBadRequest, "between 1 and 100 non-empty product IDs are required", h.logger)
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to archive products", h.logger)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Create handles POST /api/products and /api/admin/products requests.
+func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	var req model.ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	product, err := h.service.CreateProduct(r.Context(), &req)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidProduct:
+			writeError(w, http.StatusBadRequest, "id, name, category and a non-negative price are required", h.logger)
+		case model.ErrProductExists:
+			writeError(w, http.StatusConflict, "product already exists", h.logger)
+		case model.ErrUnsupportedCurrency:
+			writeError(w, http.StatusBadRequest, "products must be priced in the catalogue currency", h.logger)
+		default:
+			if writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to create product", h.logger)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, product)
+}
+
+// Import handles POST /api/products/import and /api/admin/products/import
+// requests. The CSV arrives as the "file" field of a multipart form and is
+// streamed to the service without being buffered. Rows that were not
+// imported are reported as batch entries indexed by line number.
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	file := formFile(r, "file")
+	if file == nil {
+		writeError(w, http.StatusBadRequest, "multipart/form-data body with a CSV file field is required", h.logger)
+		return
+	}
+
+	result, err := h.service.ImportProducts(r.Context(), file)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case err == model.ErrInvalidProductImport:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		case errors.As(err, &tooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, "import file is too large", h.logger)
+		default:
+			if writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to import products", h.logger)
+		}
+		return
+	}
+
+	failed := make([]BatchEntry, len(result.Errors))
+	for i, e := range result.Errors {
+		failed[i] = BatchEntry{Index: e.Row, ID: e.ProductID, Status: batchEntryStatus(e.Code), Code: e.Code, Error: e.Error}
+	}
+	writeBatch(w, result.Imported, failed)
+}
+
+// Update handles PUT /api/products/{id} and /api/admin/products/{id} requests.
+func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	productID, ok := productIDFromPath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	var req model.ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	product, err := h.service.UpdateProduct(r.Context(), productID, &req)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidProduct:
+			writeError(w, http.StatusBadRequest, "name and category are required", h.logger)
+		case model.ErrPriceUpdateNotAllowed:
+			writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		case model.ErrProductNotFound:
+			writeError(w, http.StatusNotFound, "product not found", h.logger)
+		default:
+			if writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to update product", h.logger)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+// Sale handles PUT and DELETE /api/admin/products/{id}/sale requests. PUT
+// schedules a sale, replacing any the product has, and DELETE removes it.
+// With sale approvals, PUT responds 202 with the pending price change when
+// the sale awaits approval.
+func (h *ProductHandler) Sale(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	// Expecting path: /api/admin/products/{id}/sale
+	productID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/products/"), "/sale")
+	if productID == "" || strings.Contains(productID, "/") {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	admin, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var sale *model.ProductSale
+	if r.Method == http.MethodPut {
+		sale = new(model.ProductSale)
+		if err := json.NewDecoder(r.Body).Decode(sale); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+	}
+
+	var product *model.Product
+	var err error
+	if sale != nil && h.priceChanges != nil {
+		var change *model.PriceChange
+		if change, err = h.priceChanges.RequestSale(r.Context(), productID, *sale, admin); err == nil {
+			if change.Status == model.PriceChangeStatusPending {
+				writeJSON(w, http.StatusAccepted, change)
+				return
+			}
+			product, err = h.service.GetByID(r.Context(), productID)
+		}
+	} else {
+		product, err = h.service.SetSale(r.Context(), productID, sale)
+	}
+	if err != nil {
+		h.writeSaleError(w, err)
+		return
+	}
+
 	writeJSON(w, http.StatusOK, product)
 }
+
+// writeSaleError maps an error scheduling or removing a sale to a response.
+func (h *ProductHandler) writeSaleError(w http.ResponseWriter, err error) {
+	switch err {
+	case model.ErrInvalidSale:
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+	case model.ErrProductNotFound:
+		writeError(w, http.StatusNotFound, "product not found", h.logger)
+	case model.ErrPriceChangePending:
+		writeError(w, http.StatusConflict, "product already has a price change awaiting approval", h.logger)
+	default:
+		if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to set product sale", h.logger)
+	}
+}
+
+// Delete handles DELETE /api/products/{id} and /api/admin/products/{id}
+// requests.
+func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	productID, ok := productIDFromPath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	if err := h.service.DeleteProduct(r.Context(), productID); err != nil {
+		switch err {
+		case model.ErrProductNotFound:
+			writeError(w, http.StatusNotFound, "product not found", h.logger)
+		case model.ErrProductInUse:
+			writeError(w, http.StatusConflict, err.Error(), h.logger)
+		default:
+			if writeConstraintViolation(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to delete product", h.logger)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// productIDFromPath extracts the product ID from /api/products/{id} or
+// /api/admin/products/{id}.
+func productIDFromPath(path string) (string, bool) {
+	id, ok := strings.CutPrefix(path, "/api/products/")
+	if !ok {
+		id, ok = strings.CutPrefix(path, "/api/admin/products/")
+	}
+	if !ok || id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+	return id, true
+}
@@ -1,18 +1,26 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"mini-kart/internal/fixtures"
 	"mini-kart/internal/model"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockProductService is a mock implementation of ProductService.
@@ -20,12 +28,12 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) GetAll(ctx context.Context, limit, offset int) ([]model.Product, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockProductService) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, model.Page, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, model.Page{}, args.Error(2)
 	}
-	return args.Get(0).([]model.Product), args.Error(1)
+	return args.Get(0).([]model.Product), args.Get(1).(model.Page), args.Error(2)
 }
 
 func (m *MockProductService) GetByID(ctx context.Context, id string) (*model.Product, error) {
@@ -44,13 +52,63 @@ func (m *MockProductService) GetByIDs(ctx context.Context, ids []string) ([]mode
 	return args.Get(0).([]model.Product), args.Error(1)
 }
 
+func (m *MockProductService) ArchiveProducts(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error) {
+	args := m.Called(ctx, ids, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductArchiveResult), args.Error(1)
+}
+
+func (m *MockProductService) Compare(ctx context.Context, ids []string) (*model.ProductComparison, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductComparison), args.Error(1)
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, req *model.ProductRequest) (*model.Product, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductService) UpdateProduct(ctx context.Context, id string, req *model.ProductRequest) (*model.Product, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductService) SetSale(ctx context.Context, id string, sale *model.ProductSale) (*model.Product, error) {
+	args := m.Called(ctx, id, sale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductService) DeleteProduct(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProductService) ImportProducts(ctx context.Context, r io.Reader) (*model.ProductImportResult, error) {
+	args := m.Called(ctx, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductImportResult), args.Error(1)
+}
+
 func TestProductHandler_GetAll(t *testing.T) {
 	logger := zerolog.Nop()
 
-	testProducts := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-		{ID: "P002", Name: "Product 2", Price: 20.00, Category: "Cat2", CreatedAt: time.Now()},
-	}
+	testProducts := fixtures.Products(2)
 
 	tests := []struct {
 		name           string
@@ -60,8 +118,7 @@ func TestProductHandler_GetAll(t *testing.T) {
 		mockError      error
 		expectedStatus int
 		expectService  bool
-		limit          int
-		offset         int
+		expectedFilter model.ProductFilter
 	}{
 		{
 			name:           "Success with default pagination",
@@ -71,8 +128,7 @@ func TestProductHandler_GetAll(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 			expectService:  true,
-			limit:          10,
-			offset:         0,
+			expectedFilter: model.ProductFilter{Limit: 10},
 		},
 		{
 			name:           "Success with custom pagination",
@@ -82,8 +138,32 @@ func TestProductHandler_GetAll(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 			expectService:  true,
-			limit:          5,
-			offset:         10,
+			expectedFilter: model.ProductFilter{Limit: 5, Offset: 10},
+		},
+		{
+			name:           "Success with category and sort",
+			method:         http.MethodGet,
+			queryParams:    "?category=Cat1&sort=price&order=desc",
+			mockReturn:     testProducts[:1],
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{
+				Category: "Cat1",
+				Sort:     model.ProductSortPrice,
+				Order:    model.SortDesc,
+				Limit:    10,
+			},
+		},
+		{
+			name:           "Invalid sort",
+			method:         http.MethodGet,
+			queryParams:    "?sort=rating",
+			mockReturn:     nil,
+			mockError:      model.ErrInvalidProductSort,
+			expectedStatus: http.StatusBadRequest,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{Sort: "rating", Limit: 10},
 		},
 		{
 			name:           "Invalid limit parameter",
@@ -111,8 +191,17 @@ func TestProductHandler_GetAll(t *testing.T) {
 			mockError:      errors.New("database error"),
 			expectedStatus: http.StatusInternalServerError,
 			expectService:  true,
-			limit:          10,
-			offset:         0,
+			expectedFilter: model.ProductFilter{Limit: 10},
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			queryParams:    "",
+			mockReturn:     nil,
+			mockError:      fmt.Errorf("failed to get products: %w", model.ErrDatabaseUnavailable),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{Limit: 10},
 		},
 		{
 			name:           "Method not allowed",
@@ -131,8 +220,9 @@ func TestProductHandler_GetAll(t *testing.T) {
 			handler := NewProductHandler(mockService, logger)
 
 			if tt.expectService {
-				mockService.On("GetAll", mock.Anything, tt.limit, tt.offset).
-					Return(tt.mockReturn, tt.mockError)
+				page := model.Page{Limit: tt.expectedFilter.Limit, Offset: tt.expectedFilter.Offset, Total: len(tt.mockReturn)}
+				mockService.On("GetAll", mock.Anything, tt.expectedFilter).
+					Return(tt.mockReturn, page, tt.mockError)
 			}
 
 			req := httptest.NewRequest(tt.method, "/api/products"+tt.queryParams, nil)
@@ -155,7 +245,7 @@ func TestProductHandler_GetByID(t *testing.T) {
 	testProduct := &model.Product{
 		ID:        "P001",
 		Name:      "Product 1",
-		Price:     10.00,
+		Price:     model.NewMoney(10.00),
 		Category:  "Cat1",
 		CreatedAt: time.Now(),
 	}
@@ -180,6 +270,16 @@ func TestProductHandler_GetByID(t *testing.T) {
 			expectService:  true,
 			productID:      "P001",
 		},
+		{
+			name:           "Success under the admin route group",
+			method:         http.MethodGet,
+			path:           "/api/admin/products/P001",
+			mockReturn:     testProduct,
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+			productID:      "P001",
+		},
 		{
 			name:           "Product not found - service returns nil",
 			method:         http.MethodGet,
@@ -243,3 +343,844 @@ func TestProductHandler_GetByID(t *testing.T) {
 		})
 	}
 }
+
+func TestProductHandler_GetByID_CouponPreview(t *testing.T) {
+	logger := zerolog.Nop()
+	product := fixtures.NewProduct(1).Ptr()
+
+	t.Run("Adds preview", func(t *testing.T) {
+		mockService := new(MockProductService)
+		mockPricing := new(MockPricingService)
+		mockService.On("GetByID", mock.Anything, "P001").Return(product, nil)
+		mockPricing.On("PreviewProductCoupon", mock.Anything, product, "TENOFF123").
+			Return(&model.ProductCouponPreview{Code: "TENOFF123", Applicable: true, Price: model.NewMoney(10), Discount: model.NewMoney(1), DiscountedPrice: model.NewMoney(9)}, nil)
+
+		h := NewProductHandler(mockService, logger, WithCouponPreviews(mockPricing))
+		req := httptest.NewRequest(http.MethodGet, "/api/products/P001?couponCode=TENOFF123", nil)
+		w := httptest.NewRecorder()
+
+		h.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"P001"`)
+		assert.Contains(t, w.Body.String(), `"couponPreview":{"code":"TENOFF123","applicable":true,"price":10,"discount":1,"discountedPrice":9}`)
+	})
+
+	t.Run("Omits preview when the coupon cannot be evaluated", func(t *testing.T) {
+		mockService := new(MockProductService)
+		mockPricing := new(MockPricingService)
+		mockService.On("GetByID", mock.Anything, "P001").Return(product, nil)
+		mockPricing.On("PreviewProductCoupon", mock.Anything, product, "TENOFF123").
+			Return(nil, model.ErrCouponValidationTimeout)
+
+		h := NewProductHandler(mockService, logger, WithCouponPreviews(mockPricing))
+		req := httptest.NewRequest(http.MethodGet, "/api/products/P001?couponCode=TENOFF123", nil)
+		w := httptest.NewRecorder()
+
+		h.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"P001"`)
+		assert.NotContains(t, w.Body.String(), "couponPreview")
+	})
+
+	t.Run("Ignores code without previews enabled", func(t *testing.T) {
+		mockService := new(MockProductService)
+		mockService.On("GetByID", mock.Anything, "P001").Return(product, nil)
+
+		h := NewProductHandler(mockService, logger)
+		req := httptest.NewRequest(http.MethodGet, "/api/products/P001?couponCode=TENOFF123", nil)
+		w := httptest.NewRecorder()
+
+		h.GetByID(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "couponPreview")
+	})
+}
+
+func TestProductHandler_Archive(t *testing.T) {
+	logger := zerolog.Nop()
+
+	conflicts := &model.ProductArchiveResult{
+		Archived: []string{},
+		Conflicts: []model.ProductArchiveConflict{
+			{ProductID: "P002", Reason: model.ArchiveConflictNotFound},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		admin          string
+		mockResult     *model.ProductArchiveResult
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPost,
+			body:           `{"productIds":["P001"]}`,
+			admin:          "admin-a",
+			mockResult:     &model.ProductArchiveResult{Archived: []string{"P001"}, Conflicts: []model.ProductArchiveConflict{}},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Conflicts",
+			method:         http.MethodPost,
+			body:           `{"productIds":["P001","P002"]}`,
+			admin:          "admin-a",
+			mockResult:     conflicts,
+			mockError:      model.ErrProductArchiveConflict,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid product IDs",
+			method:         http.MethodPost,
+			body:           `{"productIds":[]}`,
+			admin:          "admin-a",
+			mockError:      model.ErrInvalidProductIDs,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodPost,
+			body:           `{"productIds":["P001"]}`,
+			admin:          "admin-a",
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing identity",
+			method:         http.MethodPost,
+			body:           `{"productIds":["P001"]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			body:           `{`,
+			admin:          "admin-a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			admin:          "admin-a",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				mockService.On("ArchiveProducts", mock.Anything, mock.Anything, tt.admin).Return(tt.mockResult, tt.mockError)
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, "/api/admin/products/archive", strings.NewReader(tt.body))
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			w := httptest.NewRecorder()
+
+			h.Archive(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusConflict {
+				assert.Contains(t, w.Body.String(), model.ArchiveConflictNotFound)
+			}
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "ArchiveProducts", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Compare(t *testing.T) {
+	logger := zerolog.Nop()
+
+	comparison := &model.ProductComparison{
+		Products: []model.Product{{ID: "P001"}, {ID: "P002"}},
+		Attributes: []model.ComparisonAttribute{
+			{Name: "weight", Values: []any{"500g", nil}, Differs: true},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		expectedIDs    []string
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodGet,
+			query:          "?ids=P001,P002",
+			expectedIDs:    []string{"P001", "P002"},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing IDs",
+			method:         http.MethodGet,
+			mockError:      model.ErrInvalidComparison,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown product",
+			method:         http.MethodGet,
+			query:          "?ids=P001,P999",
+			expectedIDs:    []string{"P001", "P999"},
+			mockError:      model.ErrProductNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			query:          "?ids=P001,P002",
+			expectedIDs:    []string{"P001", "P002"},
+			mockError:      fmt.Errorf("failed to get products: %w", model.ErrDatabaseUnavailable),
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			query:          "?ids=P001,P002",
+			expectedIDs:    []string{"P001", "P002"},
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				var result *model.ProductComparison
+				if tt.mockError == nil {
+					result = comparison
+				}
+				mockService.On("Compare", mock.Anything, tt.expectedIDs).Return(result, tt.mockError)
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, "/api/products/compare"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.Compare(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var body model.ProductComparison
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, comparison.Attributes, body.Attributes)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Create(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockReturn     *model.Product
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			body:           `{"id":"P100","name":"Waffle","price":6.5,"category":"Waffle"}`,
+			mockReturn:     &model.Product{ID: "P100", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle"},
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Duplicate",
+			body:           `{"id":"P001","name":"Waffle","price":6.5,"category":"Waffle"}`,
+			mockError:      model.ErrProductExists,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid product",
+			body:           `{"id":"P100"}`,
+			mockError:      model.ErrInvalidProduct,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				mockService.On("CreateProduct", mock.Anything, mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(http.MethodPost, "/api/products", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Create(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// multipartBody builds a multipart form with a single file field.
+func multipartBody(t *testing.T, field, content string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "products.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestProductHandler_Import(t *testing.T) {
+	logger := zerolog.Nop()
+	input := "id,name,price,category\nP100,Waffle,6.50,Waffle\n"
+
+	tests := []struct {
+		name           string
+		method         string
+		field          string
+		contentType    string
+		mockReturn     *model.ProductImportResult
+		mockError      error
+		expectService  bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "All rows imported",
+			method: http.MethodPost,
+			field:  "file",
+			mockReturn: &model.ProductImportResult{
+				Imported: 2,
+				Errors:   []model.ProductImportError{},
+			},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"succeeded":2,"failed":0,"entries":[]}`,
+		},
+		{
+			name:   "Some rows rejected",
+			method: http.MethodPost,
+			field:  "file",
+			mockReturn: &model.ProductImportResult{
+				Imported: 1,
+				Errors: []model.ProductImportError{
+					{Row: 3, ProductID: "P101", Code: model.ErrCodeInvalidProduct, Error: "price must be a number"},
+					{Row: 4, ProductID: "P001", Code: model.ErrCodeProductExists, Error: "A product with this ID already exists"},
+				},
+			},
+			expectService:  true,
+			expectedStatus: http.StatusMultiStatus,
+			expectedBody: `{"succeeded":1,"failed":2,"entries":[
+				{"index":3,"id":"P101","status":400,"code":"INVALID_PRODUCT","error":"price must be a number"},
+				{"index":4,"id":"P001","status":409,"code":"PRODUCT_ALREADY_EXISTS","error":"A product with this ID already exists"}
+			]}`,
+		},
+		{
+			name:           "Invalid header",
+			method:         http.MethodPost,
+			field:          "file",
+			mockError:      model.ErrInvalidProductImport,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Upload too large",
+			method:         http.MethodPost,
+			field:          "file",
+			mockError:      fmt.Errorf("failed to import products: %w", &http.MaxBytesError{Limit: maxImportBytes}),
+			expectService:  true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodPost,
+			field:          "file",
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing file field",
+			method:         http.MethodPost,
+			field:          "upload",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not multipart",
+			method:         http.MethodPost,
+			field:          "file",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Wrong method",
+			method:         http.MethodGet,
+			field:          "file",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				mockService.On("ImportProducts", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						data, err := io.ReadAll(args.Get(1).(io.Reader))
+						require.NoError(t, err)
+						assert.Equal(t, input, string(data))
+					}).
+					Return(tt.mockReturn, tt.mockError)
+			}
+
+			body, contentType := multipartBody(t, tt.field, input)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, "/api/products/import", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+
+			h.Import(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockReturn != nil {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "ImportProducts", mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Update(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockReturn     *model.Product
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			path:           "/api/products/P001",
+			body:           `{"name":"Belgian Waffle","category":"Dessert"}`,
+			mockReturn:     &model.Product{ID: "P001", Name: "Belgian Waffle", Price: model.NewMoney(6.5), Category: "Dessert"},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Price change rejected",
+			path:           "/api/products/P001",
+			body:           `{"name":"Belgian Waffle","price":9,"category":"Dessert"}`,
+			mockError:      model.ErrPriceUpdateNotAllowed,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not found",
+			path:           "/api/products/P001",
+			body:           `{"name":"Belgian Waffle","category":"Dessert"}`,
+			mockError:      model.ErrProductNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing ID",
+			path:           "/api/products/",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				mockService.On("UpdateProduct", mock.Anything, "P001", mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Update(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Sale(t *testing.T) {
+	logger := zerolog.Nop()
+	onSale := &model.Product{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle",
+		Sale: &model.ProductSale{Price: model.NewMoney(5)}, EffectivePrice: model.NewMoney(5), OnSale: true}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		admin          string
+		expectedSale   *model.ProductSale
+		mockReturn     *model.Product
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Schedule sale",
+			method:         http.MethodPut,
+			path:           "/api/admin/products/P001/sale",
+			body:           `{"price":5}`,
+			admin:          "admin-a",
+			expectedSale:   &model.ProductSale{Price: model.NewMoney(5)},
+			mockReturn:     onSale,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Remove sale",
+			method:         http.MethodDelete,
+			path:           "/api/admin/products/P001/sale",
+			admin:          "admin-a",
+			mockReturn:     &model.Product{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle", EffectivePrice: model.NewMoney(6.5)},
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid sale",
+			method:         http.MethodPut,
+			path:           "/api/admin/products/P001/sale",
+			body:           `{"price":9}`,
+			admin:          "admin-a",
+			expectedSale:   &model.ProductSale{Price: model.NewMoney(9)},
+			mockError:      model.ErrInvalidSale,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not found",
+			method:         http.MethodPut,
+			path:           "/api/admin/products/P001/sale",
+			body:           `{"price":5}`,
+			admin:          "admin-a",
+			expectedSale:   &model.ProductSale{Price: model.NewMoney(5)},
+			mockError:      model.ErrProductNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing identity",
+			method:         http.MethodPut,
+			path:           "/api/admin/products/P001/sale",
+			body:           `{"price":5}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPut,
+			path:           "/api/admin/products/P001/sale",
+			body:           `{`,
+			admin:          "admin-a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing ID",
+			method:         http.MethodPut,
+			path:           "/api/admin/products//sale",
+			body:           `{"price":5}`,
+			admin:          "admin-a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/admin/products/P001/sale",
+			admin:          "admin-a",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			if tt.expectService {
+				mockService.On("SetSale", mock.Anything, "P001", tt.expectedSale).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			w := httptest.NewRecorder()
+
+			h.Sale(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "SetSale", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Sale_Approvals(t *testing.T) {
+	logger := zerolog.Nop()
+	onSale := &model.Product{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle",
+		Sale: &model.ProductSale{Price: model.NewMoney(6)}, EffectivePrice: model.NewMoney(6), OnSale: true}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedSale   model.ProductSale
+		mockChange     *model.PriceChange
+		mockError      error
+		expectProduct  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Small discount is applied",
+			body:           `{"price":6}`,
+			expectedSale:   model.ProductSale{Price: model.NewMoney(6)},
+			mockChange:     &model.PriceChange{ProductID: "P001", Status: model.PriceChangeStatusApplied},
+			expectProduct:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Large discount awaits approval",
+			body:           `{"price":1}`,
+			expectedSale:   model.ProductSale{Price: model.NewMoney(1)},
+			mockChange:     &model.PriceChange{ProductID: "P001", Status: model.PriceChangeStatusPending},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "Change already pending",
+			body:           `{"price":1}`,
+			expectedSale:   model.ProductSale{Price: model.NewMoney(1)},
+			mockError:      model.ErrPriceChangePending,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid sale",
+			body:           `{"price":9}`,
+			expectedSale:   model.ProductSale{Price: model.NewMoney(9)},
+			mockError:      model.ErrInvalidSale,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			mockPriceChanges := new(MockPriceChangeService)
+			mockPriceChanges.On("RequestSale", mock.Anything, "P001", tt.expectedSale, "admin-a").Return(tt.mockChange, tt.mockError)
+			if tt.expectProduct {
+				mockService.On("GetByID", mock.Anything, "P001").Return(onSale, nil)
+			}
+
+			h := NewProductHandler(mockService, logger, WithSaleApprovals(mockPriceChanges))
+			req := withAdmin(httptest.NewRequest(http.MethodPut, "/api/admin/products/P001/sale", strings.NewReader(tt.body)), "admin-a")
+			w := httptest.NewRecorder()
+
+			h.Sale(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				var change model.PriceChange
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&change))
+				assert.Equal(t, model.PriceChangeStatusPending, change.Status)
+			}
+			mockService.AssertNotCalled(t, "SetSale", mock.Anything, mock.Anything, mock.Anything)
+			mockService.AssertExpectations(t)
+			mockPriceChanges.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Removing a sale needs no approval", func(t *testing.T) {
+		mockService := new(MockProductService)
+		mockPriceChanges := new(MockPriceChangeService)
+		mockService.On("SetSale", mock.Anything, "P001", (*model.ProductSale)(nil)).Return(&model.Product{ID: "P001"}, nil)
+
+		h := NewProductHandler(mockService, logger, WithSaleApprovals(mockPriceChanges))
+		w := httptest.NewRecorder()
+
+		h.Sale(w, withAdmin(httptest.NewRequest(http.MethodDelete, "/api/admin/products/P001/sale", nil), "admin-a"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+		mockPriceChanges.AssertNotCalled(t, "RequestSale", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductHandler_Delete(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusNoContent},
+		{name: "In use", mockError: model.ErrProductInUse, expectedStatus: http.StatusConflict},
+		{name: "Not found", mockError: model.ErrProductNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Referenced by another record", mockError: fmt.Errorf("failed to delete product: %w", errors.Join(errors.New("foreign key violation"), model.ErrRecordInUse)), expectedStatus: http.StatusConflict},
+		{name: "Service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			mockService.On("DeleteProduct", mock.Anything, "P001").Return(tt.mockError)
+
+			h := NewProductHandler(mockService, logger)
+			req := httptest.NewRequest(http.MethodDelete, "/api/products/P001", nil)
+			w := httptest.NewRecorder()
+
+			h.Delete(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Golden(t *testing.T) {
+	logger := zerolog.Nop()
+
+	products := []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(10.00)},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: time.Now(),
+			Sale: &model.ProductSale{Price: model.NewMoney(15.00)}, EffectivePrice: model.NewMoney(15.00), OnSale: true},
+	}
+
+	// serve returns a product handler whose service expects a single call
+	serve := func(method string, args []any, returns ...any) func(t *testing.T) http.HandlerFunc {
+		return func(t *testing.T) http.HandlerFunc {
+			mockService := new(MockProductService)
+			mockService.On(method, args...).Return(returns...)
+			t.Cleanup(func() { mockService.AssertExpectations(t) })
+
+			h := NewProductHandler(mockService, logger)
+			switch method {
+			case "GetAll":
+				return h.GetAll
+			case "GetByID":
+				return h.GetByID
+			default:
+				return h.Create
+			}
+		}
+	}
+
+	runGolden(t, []goldenCase{
+		{
+			name:   "List products",
+			method: http.MethodGet,
+			target: "/api/products?limit=2",
+			serve: serve("GetAll", []any{mock.Anything, model.ProductFilter{Limit: 2}},
+				products, model.Page{Limit: 2, Total: 5}, nil),
+		},
+		{
+			name:   "List products with v2 profile",
+			method: http.MethodGet,
+			target: "/api/products?limit=2",
+			header: http.Header{"Accept": {"application/vnd.minikart.v2+json"}},
+			serve: serve("GetAll", []any{mock.Anything, model.ProductFilter{Limit: 2}},
+				products, model.Page{Limit: 2, Total: 5}, nil),
+		},
+		{
+			name:   "List products with invalid limit",
+			method: http.MethodGet,
+			target: "/api/products?limit=invalid",
+			serve: func(t *testing.T) http.HandlerFunc {
+				return NewProductHandler(new(MockProductService), logger).GetAll
+			},
+		},
+		{
+			name:   "Get product",
+			method: http.MethodGet,
+			target: "/api/products/P001",
+			serve:  serve("GetByID", []any{mock.Anything, "P001"}, &products[0], nil),
+		},
+		{
+			name:   "Get missing product",
+			method: http.MethodGet,
+			target: "/api/products/P999",
+			serve:  serve("GetByID", []any{mock.Anything, "P999"}, nil, model.ErrProductNotFound),
+		},
+		{
+			name:   "Create product",
+			method: http.MethodPost,
+			target: "/api/products",
+			body:   `{"id":"P100","name":"Waffle","price":6.5,"category":"Waffle"}`,
+			serve: serve("CreateProduct", []any{mock.Anything, mock.Anything},
+				&model.Product{ID: "P100", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(6.5)}, nil),
+		},
+		{
+			name:   "Create duplicate product",
+			method: http.MethodPost,
+			target: "/api/products",
+			body:   `{"id":"P001","name":"Waffle","price":6.5,"category":"Waffle"}`,
+			serve:  serve("CreateProduct", []any{mock.Anything, mock.Anything}, nil, model.ErrProductExists),
+		},
+	})
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ProductImageHandler handles product image HTTP requests. Images are
+// uploaded straight to storage with presigned URLs from the uploads
+// endpoint, then attached to their product. Images are managed with admin
+// keys only.
+type ProductImageHandler struct {
+	service service.ProductImageService
+	logger  zerolog.Logger
+}
+
+// NewProductImageHandler creates a new product image handler.
+func NewProductImageHandler(service service.ProductImageService, logger zerolog.Logger) *ProductImageHandler {
+	return &ProductImageHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "product_image").Logger(),
+	}
+}
+
+// Images handles GET and POST /api/admin/products/{id}/images requests. GET
+// lists the product's images in order; POST attaches an uploaded image after
+// them.
+func (h *ProductImageHandler) Images(w http.ResponseWriter, r *http.Request) {
+	identity, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	productID, rest, ok := productImagePath(r.URL.Path)
+	if !ok || rest != "" {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		images, err := h.service.ListImages(r.Context(), productID)
+		if err != nil {
+			h.writeImageError(w, err, "failed to retrieve product images")
+			return
+		}
+		writeJSON(w, http.StatusOK, images)
+	case http.MethodPost:
+		var req model.ProductImageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		image, err := h.service.AttachImage(r.Context(), productID, &req, identity.Subject)
+		if err != nil {
+			h.writeImageError(w, err, "failed to attach product image")
+			return
+		}
+		writeJSON(w, http.StatusCreated, image)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// Uploads handles POST /api/admin/products/{id}/images/uploads requests,
+// responding with a presigned URL to upload one image file to.
+func (h *ProductImageHandler) Uploads(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r); !ok {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	productID, rest, ok := productImagePath(r.URL.Path)
+	if !ok || rest != "uploads" {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	var req model.ImageUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	upload, err := h.service.CreateUpload(r.Context(), productID, &req)
+	if err != nil {
+		h.writeImageError(w, err, "failed to create image upload")
+		return
+	}
+	writeJSON(w, http.StatusCreated, upload)
+}
+
+// Order handles PUT /api/admin/products/{id}/images/order requests, which
+// list every image of the product in the order to show them.
+func (h *ProductImageHandler) Order(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r); !ok {
+		return
+	}
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	productID, rest, ok := productImagePath(r.URL.Path)
+	if !ok || rest != "order" {
+		writeError(w, http.StatusBadRequest, "product ID is required", h.logger)
+		return
+	}
+
+	var req model.ImageOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	images, err := h.service.ReorderImages(r.Context(), productID, req.ImageIDs)
+	if err != nil {
+		h.writeImageError(w, err, "failed to reorder product images")
+		return
+	}
+	writeJSON(w, http.StatusOK, images)
+}
+
+// Image handles DELETE /api/admin/products/{id}/images/{imageId} requests.
+func (h *ProductImageHandler) Image(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authorize(w, r); !ok {
+		return
+	}
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	productID, rest, ok := productImagePath(r.URL.Path)
+	if !ok || rest == "" {
+		writeError(w, http.StatusBadRequest, "product ID and image ID are required", h.logger)
+		return
+	}
+	imageID, err := uuid.Parse(rest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid image ID format", h.logger)
+		return
+	}
+
+	if err := h.service.DeleteImage(r.Context(), productID, imageID); err != nil {
+		h.writeImageError(w, err, "failed to delete product image")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorize writes a 403 response unless the caller authenticated with an
+// admin key.
+func (h *ProductImageHandler) authorize(w http.ResponseWriter, r *http.Request) (middleware.Identity, bool) {
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		writeError(w, http.StatusForbidden, "product images are managed with admin keys only", h.logger)
+		return middleware.Identity{}, false
+	}
+	return identity, true
+}
+
+// writeImageError maps product image domain errors to HTTP responses.
+func (h *ProductImageHandler) writeImageError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case model.ErrInvalidImage, model.ErrImageNotUploaded, model.ErrInvalidImageOrder:
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+	case model.ErrProductNotFound:
+		writeError(w, http.StatusNotFound, "product not found", h.logger)
+	case model.ErrImageNotFound:
+		writeError(w, http.StatusNotFound, "product image not found", h.logger)
+	case model.ErrImageAttached, model.ErrTooManyImages:
+		writeError(w, http.StatusConflict, err.Error(), h.logger)
+	default:
+		if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, fallback, h.logger)
+	}
+}
+
+// productImagePath splits a /api/admin/products/{id}/images path into the
+// product ID and whatever follows /images/.
+func productImagePath(path string) (productID, rest string, ok bool) {
+	id, after, found := strings.Cut(strings.TrimPrefix(path, "/api/admin/products/"), "/images")
+	if !found || id == "" || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	if after != "" && !strings.HasPrefix(after, "/") {
+		return "", "", false
+	}
+	rest = strings.TrimPrefix(after, "/")
+	if strings.Contains(rest, "/") {
+		return "", "", false
+	}
+	return id, rest, true
+}
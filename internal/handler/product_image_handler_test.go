@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProductImageService is a mock implementation of ProductImageService.
+type MockProductImageService struct {
+	mock.Mock
+}
+
+func (m *MockProductImageService) CreateUpload(ctx context.Context, productID string, req *model.ImageUploadRequest) (*model.ImageUpload, error) {
+	args := m.Called(ctx, productID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageUpload), args.Error(1)
+}
+
+func (m *MockProductImageService) AttachImage(ctx context.Context, productID string, req *model.ProductImageRequest, actor string) (*model.ProductImage, error) {
+	args := m.Called(ctx, productID, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageService) ListImages(ctx context.Context, productID string) ([]model.ProductImage, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageService) ReorderImages(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error) {
+	args := m.Called(ctx, productID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageService) DeleteImage(ctx context.Context, productID string, id uuid.UUID) error {
+	args := m.Called(ctx, productID, id)
+	return args.Error(0)
+}
+
+func TestProductImageHandler(t *testing.T) {
+	admin := middleware.Identity{Subject: "admin:catalogue", Method: middleware.AuthMethodAdminKey}
+	imageID := uuid.MustParse("7f7a3b5e-7c1e-4d7a-9c55-0a4c8f1b2d3e")
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		identity       *middleware.Identity
+		setupMock      func(*MockProductImageService)
+		expectedStatus int
+	}{
+		{
+			name:     "List images",
+			method:   http.MethodGet,
+			path:     "/api/admin/products/P001/images",
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("ListImages", mock.Anything, "P001").Return([]model.ProductImage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "List images of a missing product",
+			method:   http.MethodGet,
+			path:     "/api/admin/products/P999/images",
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("ListImages", mock.Anything, "P999").Return(nil, model.ErrProductNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "Attach image",
+			method:   http.MethodPost,
+			path:     "/api/admin/products/P001/images",
+			body:     `{"key":"P001/a.jpg","altText":"Front"}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("AttachImage", mock.Anything, "P001", &model.ProductImageRequest{Key: "P001/a.jpg", AltText: "Front"}, "admin:catalogue").
+					Return(&model.ProductImage{ID: imageID, ProductID: "P001", Key: "P001/a.jpg"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:     "Attach image never uploaded",
+			method:   http.MethodPost,
+			path:     "/api/admin/products/P001/images",
+			body:     `{"key":"P001/a.jpg"}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("AttachImage", mock.Anything, "P001", mock.Anything, mock.Anything).Return(nil, model.ErrImageNotUploaded)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Attach image past the limit",
+			method:   http.MethodPost,
+			path:     "/api/admin/products/P001/images",
+			body:     `{"key":"P001/a.jpg"}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("AttachImage", mock.Anything, "P001", mock.Anything, mock.Anything).Return(nil, model.ErrTooManyImages)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:     "Create upload",
+			method:   http.MethodPost,
+			path:     "/api/admin/products/P001/images/uploads",
+			body:     `{"contentType":"image/jpeg","sizeBytes":2048}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("CreateUpload", mock.Anything, "P001", &model.ImageUploadRequest{ContentType: "image/jpeg", SizeBytes: 2048}).
+					Return(&model.ImageUpload{Key: "P001/a.jpg", Method: http.MethodPut}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:     "Create upload of an unsupported type",
+			method:   http.MethodPost,
+			path:     "/api/admin/products/P001/images/uploads",
+			body:     `{"contentType":"application/pdf","sizeBytes":2048}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("CreateUpload", mock.Anything, "P001", mock.Anything).Return(nil, model.ErrInvalidImage)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Reorder images",
+			method:   http.MethodPut,
+			path:     "/api/admin/products/P001/images/order",
+			body:     `{"imageIds":["` + imageID.String() + `"]}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("ReorderImages", mock.Anything, "P001", []uuid.UUID{imageID}).Return([]model.ProductImage{{ID: imageID}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Reorder without every image",
+			method:   http.MethodPut,
+			path:     "/api/admin/products/P001/images/order",
+			body:     `{"imageIds":[]}`,
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("ReorderImages", mock.Anything, "P001", []uuid.UUID{}).Return(nil, model.ErrInvalidImageOrder)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Delete image",
+			method:   http.MethodDelete,
+			path:     "/api/admin/products/P001/images/" + imageID.String(),
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("DeleteImage", mock.Anything, "P001", imageID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "Delete missing image",
+			method:   http.MethodDelete,
+			path:     "/api/admin/products/P001/images/" + imageID.String(),
+			identity: &admin,
+			setupMock: func(m *MockProductImageService) {
+				m.On("DeleteImage", mock.Anything, "P001", imageID).Return(model.ErrImageNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Delete with a malformed image ID",
+			method:         http.MethodDelete,
+			path:           "/api/admin/products/P001/images/front",
+			identity:       &admin,
+			setupMock:      func(m *MockProductImageService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           "/api/admin/products/P001/images",
+			body:           `{`,
+			identity:       &admin,
+			setupMock:      func(m *MockProductImageService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Admin acting on behalf of a customer cannot manage images",
+			method:         http.MethodGet,
+			path:           "/api/admin/products/P001/images",
+			identity:       &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			setupMock:      func(m *MockProductImageService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key cannot manage images",
+			method:         http.MethodGet,
+			path:           "/api/admin/products/P001/images",
+			identity:       &middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			setupMock:      func(m *MockProductImageService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/admin/products/P001/images/uploads",
+			identity:       &admin,
+			setupMock:      func(m *MockProductImageService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockProductImageService)
+			tt.setupMock(svc)
+			h := NewProductImageHandler(svc, zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			switch {
+			case strings.HasSuffix(tt.path, "/images"):
+				h.Images(w, req)
+			case strings.HasSuffix(tt.path, "/images/uploads"):
+				h.Uploads(w, req)
+			case strings.HasSuffix(tt.path, "/images/order"):
+				h.Order(w, req)
+			default:
+				h.Image(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductImagePath(t *testing.T) {
+	tests := []struct {
+		path      string
+		productID string
+		rest      string
+		ok        bool
+	}{
+		{path: "/api/admin/products/P001/images", productID: "P001", ok: true},
+		{path: "/api/admin/products/P001/images/order", productID: "P001", rest: "order", ok: true},
+		{path: "/api/admin/products//images"},
+		{path: "/api/admin/products/P001/imagesx"},
+		{path: "/api/admin/products/P001/images/a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			productID, rest, ok := productImagePath(tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.productID, productID)
+			assert.Equal(t, tt.rest, rest)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// PublicHandler serves the anonymous, read-only catalogue embedded by the
+// marketing site. Responses carry only public product fields and are meant
+// to be cached by browsers and a CDN in front of the API.
+type PublicHandler struct {
+	service      service.ProductService
+	cacheControl string
+	logger       zerolog.Logger
+}
+
+// NewPublicHandler creates a new public catalogue handler. Browsers may cache
+// responses for maxAge and shared caches such as a CDN for cdnMaxAge, after
+// which a CDN may keep serving the stale copy for another cdnMaxAge while it
+// revalidates.
+func NewPublicHandler(service service.ProductService, maxAge, cdnMaxAge time.Duration, logger zerolog.Logger) *PublicHandler {
+	return &PublicHandler{
+		service: service,
+		cacheControl: fmt.Sprintf("public, max-age=%d, s-maxage=%d, stale-while-revalidate=%d",
+			int(maxAge.Seconds()), int(cdnMaxAge.Seconds()), int(cdnMaxAge.Seconds())),
+		logger: logger.With().Str("handler", "public").Logger(),
+	}
+}
+
+// Products handles GET /public/products requests. It accepts the same
+// category, sort and pagination parameters as GET /api/products. Responses
+// carry an ETag, so revalidating a cached copy that is still current costs
+// a 304 without a body.
+func (h *PublicHandler) Products(w http.ResponseWriter, r *http.Request) {
+	// Errors must not be cached; a successful response replaces this
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+		return
+	}
+
+	products, page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		if err == model.ErrInvalidProductSort {
+			writeError(w, http.StatusBadRequest, "sort must be name, price or created_at and order asc or desc", h.logger)
+			return
+		}
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve products", h.logger)
+		return
+	}
+
+	public := make([]model.PublicProduct, len(products))
+	for i, p := range products {
+		public[i] = p.Public()
+	}
+
+	body, err := json.Marshal(public)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve products", h.logger)
+		return
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	writePageHeaders(w, r, page)
+	header := w.Header()
+	header.Set("Cache-Control", h.cacheControl)
+	header.Set("ETag", etag)
+	// Each tenant has its own catalogue
+	header.Add("Vary", middleware.TenantHeader)
+	header.Add("Access-Control-Expose-Headers", "ETag")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag,
+// comparing weakly as RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicHandler_Products(t *testing.T) {
+	logger := zerolog.Nop()
+
+	testProducts := []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(), EffectivePrice: model.NewMoney(10.00)},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: time.Now(),
+			Sale: &model.ProductSale{Price: model.NewMoney(15.00)}, EffectivePrice: model.NewMoney(15.00), OnSale: true},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		queryParams    string
+		mockError      error
+		expectedStatus int
+		expectService  bool
+		expectedFilter model.ProductFilter
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodGet,
+			queryParams:    "?category=Cat1&sort=price&limit=5",
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{Category: "Cat1", Sort: model.ProductSortPrice, Limit: 5},
+		},
+		{
+			name:           "Head request",
+			method:         http.MethodHead,
+			expectedStatus: http.StatusOK,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{Limit: 10},
+		},
+		{
+			name:           "Invalid limit parameter",
+			method:         http.MethodGet,
+			queryParams:    "?limit=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectService:  false,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectService:  true,
+			expectedFilter: model.ProductFilter{Limit: 10},
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectService:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewPublicHandler(mockService, time.Minute, 5*time.Minute, logger)
+
+			if tt.expectService {
+				page := model.Page{Limit: tt.expectedFilter.Limit, Total: len(testProducts)}
+				mockService.On("GetAll", mock.Anything, tt.expectedFilter).
+					Return(testProducts, page, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, "/public/products"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			handler.Products(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Empty(t, w.Header().Get("ETag"))
+				return
+			}
+
+			assert.Equal(t, "public, max-age=60, s-maxage=300, stale-while-revalidate=300", w.Header().Get("Cache-Control"))
+			assert.NotEmpty(t, w.Header().Get("ETag"))
+			assert.Equal(t, "X-Tenant-ID", w.Header().Get("Vary"))
+			assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+
+			// Only public fields are exposed
+			var products []map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+			require.Len(t, products, 2)
+			assert.Equal(t, map[string]any{"id": "P001", "name": "Product 1", "price": 10.0, "effectivePrice": 10.0, "onSale": false, "category": "Cat1"}, products[0])
+			assert.Equal(t, map[string]any{"id": "P002", "name": "Product 2", "price": 20.0, "effectivePrice": 15.0, "onSale": true, "category": "Cat2"}, products[1])
+		})
+	}
+}
+
+func TestPublicHandler_Products_Revalidation(t *testing.T) {
+	mockService := new(MockProductService)
+	mockService.On("GetAll", mock.Anything, mock.Anything).
+		Return([]model.Product{{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1"}}, model.Page{Limit: 10, Total: 1}, nil)
+	handler := NewPublicHandler(mockService, time.Minute, 5*time.Minute, zerolog.Nop())
+
+	w := httptest.NewRecorder()
+	handler.Products(w, httptest.NewRequest(http.MethodGet, "/public/products", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{name: "Current ETag", ifNoneMatch: etag, expectedStatus: http.StatusNotModified},
+		{name: "Weak current ETag in a list", ifNoneMatch: `"stale", W/` + etag, expectedStatus: http.StatusNotModified},
+		{name: "Any", ifNoneMatch: "*", expectedStatus: http.StatusNotModified},
+		{name: "Stale ETag", ifNoneMatch: `"stale"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/public/products", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			w := httptest.NewRecorder()
+
+			handler.Products(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Contains(t, w.Header().Get("Cache-Control"), "public")
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
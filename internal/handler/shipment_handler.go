@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ShipmentHandler handles order shipment and fulfillment event HTTP requests.
+type ShipmentHandler struct {
+	service service.ShipmentService
+	logger  zerolog.Logger
+}
+
+// NewShipmentHandler creates a new shipment handler.
+func NewShipmentHandler(service service.ShipmentService, logger zerolog.Logger) *ShipmentHandler {
+	return &ShipmentHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "shipment").Logger(),
+	}
+}
+
+// Shipments handles POST and GET /api/orders/{id}/shipments requests.
+func (h *ShipmentHandler) Shipments(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := orderIDFromPath(w, r, "/shipments", h.logger)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req model.ShipmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		shipment, err := h.service.CreateShipment(r.Context(), orderID, &req)
+		if err != nil {
+			h.writeShipmentError(w, err, "failed to create shipment")
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, shipment)
+	case http.MethodGet:
+		shipments, err := h.service.ListShipments(r.Context(), orderID)
+		if err != nil {
+			h.writeShipmentError(w, err, "failed to retrieve shipments")
+			return
+		}
+
+		if shipments == nil {
+			shipments = []model.Shipment{}
+		}
+
+		writeJSON(w, http.StatusOK, shipments)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// Ship handles POST /api/orders/{id}/ship requests, shipping every
+// unfulfilled item of the order in one shipment. The body is optional.
+func (h *ShipmentHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/ship", h.logger)
+	if !ok {
+		return
+	}
+
+	var req model.ShipRemainingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	shipment, err := h.service.ShipRemaining(r.Context(), orderID, &req)
+	if err != nil {
+		h.writeShipmentError(w, err, "failed to ship order")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, shipment)
+}
+
+// Tracking handles PUT /api/orders/{id}/shipments/{shipmentId}/tracking
+// requests.
+func (h *ShipmentHandler) Tracking(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/orders/"), "/tracking")
+	orderIDStr, shipmentIDStr, _ := strings.Cut(path, "/shipments/")
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order ID format", h.logger)
+		return
+	}
+	shipmentID, err := uuid.Parse(shipmentIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid shipment ID format", h.logger)
+		return
+	}
+
+	var req model.TrackingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	shipment, err := h.service.UpdateTracking(r.Context(), orderID, shipmentID, &req)
+	if err != nil {
+		h.writeShipmentError(w, err, "failed to update shipment tracking")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shipment)
+}
+
+// Events handles GET /api/orders/{id}/events requests.
+func (h *ShipmentHandler) Events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/events", h.logger)
+	if !ok {
+		return
+	}
+
+	events, err := h.service.ListEvents(r.Context(), orderID)
+	if err != nil {
+		h.writeShipmentError(w, err, "failed to retrieve order events")
+		return
+	}
+
+	if events == nil {
+		events = []model.OrderEvent{}
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// writeShipmentError maps shipment domain errors to HTTP responses.
+func (h *ShipmentHandler) writeShipmentError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case model.ErrOrderNotFound:
+		status = http.StatusNotFound
+		message = "order not found"
+	case model.ErrInvalidShipment:
+		status = http.StatusBadRequest
+		message = "shipment must list each order item once with a positive quantity"
+	case model.ErrOrderItemNotFound:
+		status = http.StatusBadRequest
+		message = "one or more items do not belong to the order"
+	case model.ErrOverFulfillment:
+		status = http.StatusConflict
+		message = "shipped quantity exceeds the unfulfilled quantity"
+	case model.ErrOrderCancelled:
+		status = http.StatusConflict
+		message = "cancelled orders cannot be shipped"
+	case model.ErrOrderShipped:
+		status = http.StatusConflict
+		message = "every item of the order has already been shipped"
+	case model.ErrShipmentNotFound:
+		status = http.StatusNotFound
+		message = "shipment not found"
+	case model.ErrInvalidTracking:
+		status = http.StatusBadRequest
+		message = "tracking number is required, and tracking number and carrier must be at most 100 characters"
+	default:
+		if writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+	}
+
+	writeError(w, status, message, h.logger)
+}
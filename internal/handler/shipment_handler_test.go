@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockShipmentService is a mock implementation of ShipmentService.
+type MockShipmentService struct {
+	mock.Mock
+}
+
+func (m *MockShipmentService) CreateShipment(ctx context.Context, orderID uuid.UUID, req *model.ShipmentRequest) (*model.Shipment, error) {
+	args := m.Called(ctx, orderID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) ShipRemaining(ctx context.Context, orderID uuid.UUID, req *model.ShipRemainingRequest) (*model.Shipment, error) {
+	args := m.Called(ctx, orderID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, req *model.TrackingRequest) (*model.Shipment, error) {
+	args := m.Called(ctx, orderID, shipmentID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) ListShipments(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OrderEvent), args.Error(1)
+}
+
+func TestShipmentHandler_Create(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	itemID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockReturn     *model.Shipment
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{"carrier":"AusPost","items":[{"orderItemId":"` + itemID.String() + `","quantity":1}]}`,
+			mockReturn:     &model.Shipment{ID: uuid.New(), OrderID: orderID},
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Order not found",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{"items":[{"orderItemId":"` + itemID.String() + `","quantity":1}]}`,
+			mockError:      model.ErrOrderNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Exceeds unfulfilled quantity",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{"items":[{"orderItemId":"` + itemID.String() + `","quantity":5}]}`,
+			mockError:      model.ErrOverFulfillment,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Cancelled order",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{"items":[{"orderItemId":"` + itemID.String() + `","quantity":1}]}`,
+			mockError:      model.ErrOrderCancelled,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid shipment",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{"items":[]}`,
+			mockError:      model.ErrInvalidShipment,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid order ID",
+			path:           "/api/orders/not-a-uuid/shipments",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			path:           "/api/orders/" + orderID.String() + "/shipments",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockShipmentService)
+			if tt.expectService {
+				mockService.On("CreateShipment", mock.Anything, orderID, mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewShipmentHandler(mockService, logger)
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Shipments(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "CreateShipment", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShipmentHandler_List(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	shipmentID := uuid.New()
+
+	mockService := new(MockShipmentService)
+	mockService.On("ListShipments", mock.Anything, orderID).Return([]model.Shipment{{ID: shipmentID, OrderID: orderID}}, nil)
+	mockService.On("ListEvents", mock.Anything, orderID).Return(nil, model.ErrOrderNotFound)
+
+	h := NewShipmentHandler(mockService, logger)
+
+	t.Run("Shipments", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/orders/"+orderID.String()+"/shipments", nil)
+		w := httptest.NewRecorder()
+
+		h.Shipments(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var shipments []model.Shipment
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&shipments))
+		require.Len(t, shipments, 1)
+		assert.Equal(t, shipmentID, shipments[0].ID)
+	})
+
+	t.Run("Events for missing order", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/orders/"+orderID.String()+"/events", nil)
+		w := httptest.NewRecorder()
+
+		h.Events(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/orders/"+orderID.String()+"/shipments", nil)
+		w := httptest.NewRecorder()
+
+		h.Shipments(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestShipmentHandler_Ship(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/orders/" + orderID.String() + "/ship"
+
+	tests := []struct {
+		name           string
+		body           string
+		mockReturn     *model.Shipment
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Ships with tracking",
+			body:           `{"carrier":"AusPost","trackingNumber":"AP123456"}`,
+			mockReturn:     &model.Shipment{ID: uuid.New(), OrderID: orderID},
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Without a body",
+			mockReturn:     &model.Shipment{ID: uuid.New(), OrderID: orderID},
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Already shipped",
+			mockError:      model.ErrOrderShipped,
+			expectService:  true,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockShipmentService)
+			if tt.expectService {
+				mockService.On("ShipRemaining", mock.Anything, orderID, mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewShipmentHandler(mockService, logger)
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Ship(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShipmentHandler_Tracking(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	shipmentID := uuid.New()
+	path := "/api/orders/" + orderID.String() + "/shipments/" + shipmentID.String() + "/tracking"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPut,
+			path:           path,
+			body:           `{"trackingNumber":"AP123456"}`,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown shipment",
+			method:         http.MethodPut,
+			path:           path,
+			body:           `{"trackingNumber":"AP123456"}`,
+			mockError:      model.ErrShipmentNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid tracking",
+			method:         http.MethodPut,
+			path:           path,
+			body:           `{"trackingNumber":""}`,
+			mockError:      model.ErrInvalidTracking,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid shipment ID",
+			method:         http.MethodPut,
+			path:           "/api/orders/" + orderID.String() + "/shipments/not-a-uuid/tracking",
+			body:           `{"trackingNumber":"AP123456"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           path,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockShipmentService)
+			if tt.expectService {
+				var shipment *model.Shipment
+				if tt.mockError == nil {
+					shipment = &model.Shipment{ID: shipmentID, OrderID: orderID}
+				}
+				mockService.On("UpdateTracking", mock.Anything, orderID, shipmentID, mock.Anything).Return(shipment, tt.mockError)
+			}
+
+			h := NewShipmentHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Tracking(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// maxAtRiskLimit bounds the number of at-risk orders listed at once.
+const maxAtRiskLimit = 500
+
+// SLAHandler handles order SLA HTTP requests.
+type SLAHandler struct {
+	service service.SLAService
+	logger  zerolog.Logger
+}
+
+// NewSLAHandler creates a new order SLA handler.
+func NewSLAHandler(service service.SLAService, logger zerolog.Logger) *SLAHandler {
+	return &SLAHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "sla").Logger(),
+	}
+}
+
+// AtRisk handles GET /api/admin/orders/at-risk requests, listing orders that
+// are close to or past their status's SLA. The optional status parameter
+// restricts the list to one status.
+func (h *SLAHandler) AtRisk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 100 // default
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxAtRiskLimit {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500", h.logger)
+			return
+		}
+		limit = parsed
+	}
+
+	orders, err := h.service.AtRisk(r.Context(), model.OrderStatus(query.Get("status")), limit)
+	if err == model.ErrInvalidOrderStatus {
+		writeError(w, http.StatusBadRequest, "status must be pending, confirmed, cancelled or fulfilled", h.logger)
+		return
+	}
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve at-risk orders", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
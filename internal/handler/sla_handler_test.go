@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSLAService is a mock implementation of SLAService.
+type MockSLAService struct {
+	mock.Mock
+}
+
+func (m *MockSLAService) AtRisk(ctx context.Context, status model.OrderStatus, limit int) ([]model.AtRiskOrder, error) {
+	args := m.Called(ctx, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AtRiskOrder), args.Error(1)
+}
+
+func (m *MockSLAService) CheckBreaches(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestSLAHandler_AtRisk(t *testing.T) {
+	orders := []model.AtRiskOrder{
+		{ID: uuid.New(), Status: model.OrderStatusPending, TimeInStatusSeconds: 4000, SLASeconds: 3600, Breached: true},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		expectStatus   model.OrderStatus
+		expectLimit    int
+		mockReturn     []model.AtRiskOrder
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Default limit",
+			method:         http.MethodGet,
+			expectLimit:    100,
+			mockReturn:     orders,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Status and limit",
+			method:         http.MethodGet,
+			query:          "?status=pending&limit=20",
+			expectStatus:   model.OrderStatusPending,
+			expectLimit:    20,
+			mockReturn:     orders,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid status",
+			method:         http.MethodGet,
+			query:          "?status=stuck",
+			expectStatus:   "stuck",
+			expectLimit:    100,
+			mockError:      model.ErrInvalidOrderStatus,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Limit too large",
+			method:         http.MethodGet,
+			query:          "?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			expectLimit:    100,
+			mockError:      model.ErrDatabaseUnavailable,
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			expectLimit:    100,
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSLAService)
+			handler := NewSLAHandler(mockService, zerolog.Nop())
+
+			if tt.expectService {
+				mockService.On("AtRisk", mock.Anything, tt.expectStatus, tt.expectLimit).Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/admin/orders/at-risk"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.AtRisk(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.AtRiskOrder
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, orders[0].ID, got[0].ID)
+				assert.True(t, got[0].Breached)
+			}
+
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "AtRisk", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
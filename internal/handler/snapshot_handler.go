@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// SnapshotHandler handles signed order snapshot HTTP requests.
+type SnapshotHandler struct {
+	service service.SnapshotService
+	logger  zerolog.Logger
+}
+
+// NewSnapshotHandler creates a new order snapshot handler.
+func NewSnapshotHandler(service service.SnapshotService, logger zerolog.Logger) *SnapshotHandler {
+	return &SnapshotHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "snapshot").Logger(),
+	}
+}
+
+// Create handles POST /api/admin/orders/{id}/snapshots requests. The
+// authenticated caller is recorded as the snapshot's taker.
+func (h *SnapshotHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	// Expecting path: /api/admin/orders/{id}/snapshots
+	orderIDStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/orders/"), "/snapshots")
+	if orderIDStr == "" || strings.Contains(orderIDStr, "/") {
+		writeError(w, http.StatusBadRequest, "order ID is required", h.logger)
+		return
+	}
+
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order ID format", h.logger)
+		return
+	}
+
+	admin, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.service.CreateSnapshot(r.Context(), orderID, admin)
+	if err == model.ErrOrderNotFound {
+		writeError(w, http.StatusNotFound, "order not found", h.logger)
+		return
+	}
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to create order snapshot", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, snapshot)
+}
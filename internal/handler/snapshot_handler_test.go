@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSnapshotService is a mock implementation of SnapshotService.
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) CreateSnapshot(ctx context.Context, orderID uuid.UUID, actor string) (*model.SignedSnapshot, error) {
+	args := m.Called(ctx, orderID, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignedSnapshot), args.Error(1)
+}
+
+func TestSnapshotHandler_Create(t *testing.T) {
+	orderID := uuid.New()
+	path := "/api/admin/orders/" + orderID.String() + "/snapshots"
+	signed := &model.SignedSnapshot{
+		Snapshot:           json.RawMessage(`{"orderId":"` + orderID.String() + `"}`),
+		SHA256:             "abc123",
+		Signature:          "def456",
+		SignatureAlgorithm: model.SnapshotSignatureAlgorithm,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		admin          string
+		mockReturn     *model.SignedSnapshot
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Snapshot created",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin:alice",
+			mockReturn:     signed,
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin:alice",
+			mockError:      model.ErrOrderNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin:alice",
+			mockError:      model.ErrDatabaseUnavailable,
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Archive fails",
+			method:         http.MethodPost,
+			path:           path,
+			admin:          "admin:alice",
+			mockError:      errors.New("access denied"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Missing admin identity",
+			method:         http.MethodPost,
+			path:           path,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid order ID",
+			method:         http.MethodPost,
+			path:           "/api/admin/orders/invalid-uuid/snapshots",
+			admin:          "admin:alice",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           path,
+			admin:          "admin:alice",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSnapshotService)
+			handler := NewSnapshotHandler(mockService, zerolog.Nop())
+
+			if tt.expectService {
+				mockService.On("CreateSnapshot", mock.Anything, orderID, tt.admin).Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.admin != "" {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), middleware.Identity{Subject: tt.admin, Method: middleware.AuthMethodAdminKey}))
+			}
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var got model.SignedSnapshot
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.JSONEq(t, string(signed.Snapshot), string(got.Snapshot))
+				assert.Equal(t, signed.SHA256, got.SHA256)
+				assert.Equal(t, signed.Signature, got.Signature)
+			}
+
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "CreateSnapshot", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// TenantHandler handles tenant management HTTP requests. Tenants are managed
+// with admin keys only, since tenant keys and the API_KEY are tied to a
+// single storefront.
+type TenantHandler struct {
+	service service.TenantService
+	logger  zerolog.Logger
+}
+
+// NewTenantHandler creates a new tenant handler.
+func NewTenantHandler(service service.TenantService, logger zerolog.Logger) *TenantHandler {
+	return &TenantHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "tenant").Logger(),
+	}
+}
+
+// Tenants handles GET and POST /api/admin/tenants requests.
+func (h *TenantHandler) Tenants(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		tenants, err := h.service.ListTenants(r.Context())
+		if err != nil {
+			if writeUnavailable(w, err, h.logger) {
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to retrieve tenants", h.logger)
+			return
+		}
+		writeJSON(w, http.StatusOK, tenants)
+	case http.MethodPost:
+		var req model.TenantRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		tenant, err := h.service.CreateTenant(r.Context(), &req)
+		if err != nil {
+			h.writeTenantError(w, err, "failed to create tenant")
+			return
+		}
+		writeJSON(w, http.StatusCreated, tenant)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// Tenant handles GET and PUT /api/admin/tenants/{id} requests. PUT renames
+// the tenant; its ID cannot change.
+func (h *TenantHandler) Tenant(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	tenantID := strings.TrimPrefix(r.URL.Path, "/api/admin/tenants/")
+	if tenantID == "" || strings.Contains(tenantID, "/") {
+		writeError(w, http.StatusBadRequest, "tenant ID is required", h.logger)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		tenant, err := h.service.GetTenant(r.Context(), tenantID)
+		if err != nil {
+			h.writeTenantError(w, err, "failed to retrieve tenant")
+			return
+		}
+		writeJSON(w, http.StatusOK, tenant)
+	case http.MethodPut:
+		var req model.TenantRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+			return
+		}
+
+		tenant, err := h.service.RenameTenant(r.Context(), tenantID, req.Name)
+		if err != nil {
+			h.writeTenantError(w, err, "failed to rename tenant")
+			return
+		}
+		writeJSON(w, http.StatusOK, tenant)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+	}
+}
+
+// authorize rejects callers not using an admin key, reporting whether the
+// request may proceed.
+func (h *TenantHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok || identity.Method != middleware.AuthMethodAdminKey {
+		writeError(w, http.StatusForbidden, "tenants are managed with admin keys only", h.logger)
+		return false
+	}
+	return true
+}
+
+// writeTenantError maps tenant domain errors to HTTP responses.
+func (h *TenantHandler) writeTenantError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case model.ErrInvalidTenant:
+		writeError(w, http.StatusBadRequest, err.Error(), h.logger)
+	case model.ErrTenantNotFound:
+		writeError(w, http.StatusNotFound, "tenant not found", h.logger)
+	case model.ErrTenantExists:
+		writeError(w, http.StatusConflict, "tenant already exists", h.logger)
+	default:
+		if writeUnavailable(w, err, h.logger) || writeConstraintViolation(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, fallback, h.logger)
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantService is a mock implementation of TenantService.
+type MockTenantService struct {
+	mock.Mock
+}
+
+func (m *MockTenantService) ListTenants(ctx context.Context) ([]model.Tenant, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantService) GetTenant(ctx context.Context, id string) (*model.Tenant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantService) CreateTenant(ctx context.Context, req *model.TenantRequest) (*model.Tenant, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantService) RenameTenant(ctx context.Context, id, name string) (*model.Tenant, error) {
+	args := m.Called(ctx, id, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tenant), args.Error(1)
+}
+
+func TestTenantHandler(t *testing.T) {
+	admin := middleware.Identity{Subject: "admin:support", Method: middleware.AuthMethodAdminKey}
+	acme := &model.Tenant{ID: "acme", Name: "Acme"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		identity       *middleware.Identity
+		setupMock      func(*MockTenantService)
+		expectedStatus int
+	}{
+		{
+			name:     "List tenants",
+			method:   http.MethodGet,
+			path:     "/api/admin/tenants",
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("ListTenants", mock.Anything).Return([]model.Tenant{*acme}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Create tenant",
+			method:   http.MethodPost,
+			path:     "/api/admin/tenants",
+			body:     `{"id":"acme","name":"Acme"}`,
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("CreateTenant", mock.Anything, &model.TenantRequest{ID: "acme", Name: "Acme"}).Return(acme, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:     "Create existing tenant",
+			method:   http.MethodPost,
+			path:     "/api/admin/tenants",
+			body:     `{"id":"acme","name":"Acme"}`,
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("CreateTenant", mock.Anything, mock.Anything).Return(nil, model.ErrTenantExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:     "Create invalid tenant",
+			method:   http.MethodPost,
+			path:     "/api/admin/tenants",
+			body:     `{"id":"Acme","name":"Acme"}`,
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("CreateTenant", mock.Anything, mock.Anything).Return(nil, model.ErrInvalidTenant)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           "/api/admin/tenants",
+			body:           `{`,
+			identity:       &admin,
+			setupMock:      func(m *MockTenantService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "Get tenant",
+			method:   http.MethodGet,
+			path:     "/api/admin/tenants/acme",
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("GetTenant", mock.Anything, "acme").Return(acme, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Get unknown tenant",
+			method:   http.MethodGet,
+			path:     "/api/admin/tenants/globex",
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("GetTenant", mock.Anything, "globex").Return(nil, model.ErrTenantNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "Rename tenant",
+			method:   http.MethodPut,
+			path:     "/api/admin/tenants/acme",
+			body:     `{"name":"Acme Outlet"}`,
+			identity: &admin,
+			setupMock: func(m *MockTenantService) {
+				m.On("RenameTenant", mock.Anything, "acme", "Acme Outlet").Return(&model.Tenant{ID: "acme", Name: "Acme Outlet"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Tenant key cannot manage tenants",
+			method:         http.MethodGet,
+			path:           "/api/admin/tenants",
+			identity:       &middleware.Identity{Subject: "tenant:acme", Method: middleware.AuthMethodTenantKey, Tenant: "acme"},
+			setupMock:      func(m *MockTenantService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin acting on behalf of a customer cannot manage tenants",
+			method:         http.MethodGet,
+			path:           "/api/admin/tenants",
+			identity:       &middleware.Identity{Subject: "customer-42", Method: middleware.AuthMethodImpersonated, Actor: "admin:support"},
+			setupMock:      func(m *MockTenantService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key cannot manage tenants",
+			method:         http.MethodPost,
+			path:           "/api/admin/tenants",
+			body:           `{"id":"acme","name":"Acme"}`,
+			identity:       &middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey},
+			setupMock:      func(m *MockTenantService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/admin/tenants/acme",
+			identity:       &admin,
+			setupMock:      func(m *MockTenantService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockTenantService)
+			tt.setupMock(svc)
+			h := NewTenantHandler(svc, zerolog.Nop())
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.identity != nil {
+				req = req.WithContext(middleware.WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			if tt.path == "/api/admin/tenants" {
+				h.Tenants(w, req)
+			} else {
+				h.Tenant(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
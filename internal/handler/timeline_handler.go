@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// TimelineHandler handles order note and timeline HTTP requests for support agents.
+type TimelineHandler struct {
+	service service.TimelineService
+	logger  zerolog.Logger
+}
+
+// NewTimelineHandler creates a new order timeline handler.
+func NewTimelineHandler(service service.TimelineService, logger zerolog.Logger) *TimelineHandler {
+	return &TimelineHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "timeline").Logger(),
+	}
+}
+
+// Notes handles POST /api/admin/orders/{id}/notes requests. Notes are
+// internal, so only admins may add them, and record the admin as their author.
+func (h *TimelineHandler) Notes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/notes", h.logger)
+	if !ok {
+		return
+	}
+
+	author, ok := requireAdmin(w, r, h.logger)
+	if !ok {
+		return
+	}
+
+	var req model.OrderNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		return
+	}
+
+	note, err := h.service.AddNote(r.Context(), orderID, author, &req)
+	if err != nil {
+		h.writeTimelineError(w, err, "failed to add order note")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, note)
+}
+
+// Timeline handles GET /api/admin/orders/{id}/timeline requests. The
+// timeline includes internal notes, so only admins may read it.
+func (h *TimelineHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/timeline", h.logger)
+	if !ok {
+		return
+	}
+
+	if _, ok := requireAdmin(w, r, h.logger); !ok {
+		return
+	}
+
+	timeline, err := h.service.Timeline(r.Context(), orderID)
+	if err != nil {
+		h.writeTimelineError(w, err, "failed to retrieve order timeline")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, timeline)
+}
+
+// writeTimelineError maps timeline domain errors to HTTP responses.
+func (h *TimelineHandler) writeTimelineError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case model.ErrOrderNotFound:
+		status = http.StatusNotFound
+		message = "order not found"
+	case model.ErrInvalidNote:
+		status = http.StatusBadRequest
+		message = "note body is required and must be at most 2000 characters"
+	}
+
+	writeError(w, status, message, h.logger)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTimelineService is a mock implementation of TimelineService.
+type MockTimelineService struct {
+	mock.Mock
+}
+
+func (m *MockTimelineService) AddNote(ctx context.Context, orderID uuid.UUID, author string, req *model.OrderNoteRequest) (*model.OrderNote, error) {
+	args := m.Called(ctx, orderID, author, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderNote), args.Error(1)
+}
+
+func (m *MockTimelineService) Timeline(ctx context.Context, orderID uuid.UUID) ([]model.TimelineEntry, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TimelineEntry), args.Error(1)
+}
+
+func TestTimelineHandler_Notes(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/admin/orders/" + orderID.String() + "/notes"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		admin          string
+		mockReturn     *model.OrderNote
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Note added",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"body":"Customer called about delivery"}`,
+			admin:          "agent-7",
+			mockReturn:     &model.OrderNote{ID: uuid.New(), OrderID: orderID, Author: "agent-7", Body: "Customer called about delivery"},
+			expectService:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid note",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"body":""}`,
+			admin:          "agent-7",
+			mockError:      model.ErrInvalidNote,
+			expectService:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"body":"Refund requested"}`,
+			admin:          "agent-7",
+			mockError:      model.ErrOrderNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing admin identity",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{"body":"Refund requested"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid JSON",
+			method:         http.MethodPost,
+			path:           path,
+			body:           `{`,
+			admin:          "agent-7",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid order ID",
+			method:         http.MethodPost,
+			path:           "/api/admin/orders/not-a-uuid/notes",
+			body:           `{"body":"Refund requested"}`,
+			admin:          "agent-7",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodGet,
+			path:           path,
+			admin:          "agent-7",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTimelineService)
+			if tt.expectService {
+				mockService.On("AddNote", mock.Anything, orderID, tt.admin, mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
+
+			h := NewTimelineHandler(mockService, logger)
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.admin != "" {
+				req = withAdmin(req, tt.admin)
+			}
+			w := httptest.NewRecorder()
+
+			h.Notes(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if !tt.expectService {
+				mockService.AssertNotCalled(t, "AddNote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimelineHandler_Timeline(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	path := "/api/admin/orders/" + orderID.String() + "/timeline"
+
+	t.Run("Success", func(t *testing.T) {
+		created := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+		timeline := []model.TimelineEntry{
+			{Type: model.TimelineOrderCreated, At: created},
+			{Type: model.TimelineNoteAdded, At: created.Add(time.Hour), Note: &model.OrderNote{Body: "Called customer"}},
+		}
+
+		mockService := new(MockTimelineService)
+		mockService.On("Timeline", mock.Anything, orderID).Return(timeline, nil)
+
+		h := NewTimelineHandler(mockService, logger)
+		w := httptest.NewRecorder()
+		h.Timeline(w, withAdmin(httptest.NewRequest(http.MethodGet, path, nil), "agent-7"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var entries []map[string]any
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "order.created", entries[0]["type"])
+		assert.NotContains(t, entries[0], "note")
+		assert.Equal(t, "note.added", entries[1]["type"])
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mockService := new(MockTimelineService)
+		mockService.On("Timeline", mock.Anything, orderID).Return(nil, model.ErrOrderNotFound)
+
+		h := NewTimelineHandler(mockService, logger)
+		w := httptest.NewRecorder()
+		h.Timeline(w, withAdmin(httptest.NewRequest(http.MethodGet, path, nil), "agent-7"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService := new(MockTimelineService)
+		mockService.On("Timeline", mock.Anything, orderID).Return(nil, errors.New("database error"))
+
+		h := NewTimelineHandler(mockService, logger)
+		w := httptest.NewRecorder()
+		h.Timeline(w, withAdmin(httptest.NewRequest(http.MethodGet, path, nil), "agent-7"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Method not allowed", func(t *testing.T) {
+		h := NewTimelineHandler(new(MockTimelineService), logger)
+		w := httptest.NewRecorder()
+		h.Timeline(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestTimelineHandler_RequiresAdmin(t *testing.T) {
+	logger := zerolog.Nop()
+	orderID := uuid.New()
+	apiKey := middleware.Identity{Subject: "api-key", Method: middleware.AuthMethodAPIKey}
+
+	mockService := new(MockTimelineService)
+	h := NewTimelineHandler(mockService, logger)
+
+	t.Run("Shared API key cannot add notes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/"+orderID.String()+"/notes", bytes.NewBufferString(`{"body":"Refund requested"}`))
+		req = req.WithContext(middleware.WithIdentity(req.Context(), apiKey))
+		w := httptest.NewRecorder()
+
+		h.Notes(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Shared API key cannot read the timeline", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/orders/"+orderID.String()+"/timeline", nil)
+		req = req.WithContext(middleware.WithIdentity(req.Context(), apiKey))
+		w := httptest.NewRecorder()
+
+		h.Timeline(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	mockService.AssertNotCalled(t, "AddNote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "Timeline", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+// UpsellHandler handles order upsell HTTP requests.
+type UpsellHandler struct {
+	service service.UpsellService
+	logger  zerolog.Logger
+}
+
+// NewUpsellHandler creates a new order upsell handler.
+func NewUpsellHandler(service service.UpsellService, logger zerolog.Logger) *UpsellHandler {
+	return &UpsellHandler{
+		service: service,
+		logger:  logger.With().Str("handler", "upsell").Logger(),
+	}
+}
+
+// Upsell handles GET /api/orders/{id}/upsell requests, listing best sellers
+// from the order's categories that the order does not contain. The optional
+// limit parameter sets how many are listed, up to the configured maximum.
+func (h *UpsellHandler) Upsell(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", h.logger)
+		return
+	}
+
+	orderID, ok := orderIDFromPath(w, r, "/upsell", h.logger)
+	if !ok {
+		return
+	}
+
+	limit := 0 // the configured default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer", h.logger)
+			return
+		}
+		limit = parsed
+	}
+
+	products, err := h.service.Upsell(r.Context(), orderID, limit)
+	if err == model.ErrOrderNotFound {
+		writeError(w, http.StatusNotFound, "order not found", h.logger)
+		return
+	}
+	if err != nil {
+		if writeUnavailable(w, err, h.logger) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to retrieve upsell products", h.logger)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, products)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUpsellService is a mock implementation of UpsellService.
+type MockUpsellService struct {
+	mock.Mock
+}
+
+func (m *MockUpsellService) Upsell(ctx context.Context, orderID uuid.UUID, limit int) ([]model.UpsellProduct, error) {
+	args := m.Called(ctx, orderID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UpsellProduct), args.Error(1)
+}
+
+func TestUpsellHandler_Upsell(t *testing.T) {
+	orderID := uuid.New()
+	products := []model.UpsellProduct{
+		{
+			Product:   model.Product{ID: "P002", Name: "Belgian Waffle", Price: model.NewMoney(9.99), EffectivePrice: model.NewMoney(9.99), Category: "Waffle"},
+			UnitsSold: 5,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectLimit    int
+		mockReturn     []model.UpsellProduct
+		mockError      error
+		expectService  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Default limit",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell",
+			mockReturn:     products,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Requested limit",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell?limit=8",
+			expectLimit:    8,
+			mockReturn:     products,
+			expectService:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid limit",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid order ID",
+			method:         http.MethodGet,
+			path:           "/api/orders/not-a-uuid/upsell",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Order not found",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell",
+			mockError:      model.ErrOrderNotFound,
+			expectService:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Database unavailable",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell",
+			mockError:      model.ErrDatabaseUnavailable,
+			expectService:  true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "Service error",
+			method:         http.MethodGet,
+			path:           "/api/orders/" + orderID.String() + "/upsell",
+			mockError:      errors.New("database error"),
+			expectService:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPost,
+			path:           "/api/orders/" + orderID.String() + "/upsell",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUpsellService)
+			handler := NewUpsellHandler(mockService, zerolog.Nop())
+
+			if tt.expectService {
+				mockService.On("Upsell", mock.Anything, orderID, tt.expectLimit).Return(tt.mockReturn, tt.mockError)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.Upsell(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got []model.UpsellProduct
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				require.Len(t, got, 1)
+				assert.Equal(t, "P002", got[0].ID)
+				assert.Equal(t, int64(5), got[0].UnitsSold)
+			}
+
+			if tt.expectService {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Upsell", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Checker defines the interface for a dependency health probe.
+type Checker interface {
+	// Name returns the dependency name used in reports (e.g. "database").
+	Name() string
+
+	// Check probes the dependency and returns an error if it is unhealthy.
+	Check(ctx context.Context) error
+}
+
+// Pinger is implemented by dependencies that support a connectivity ping,
+// such as *pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// StartupCheck reports whether a component started in the background, such
+// as coupon data still loading, has finished starting: nil once it has,
+// otherwise why it is not ready yet.
+type StartupCheck func() error
+
+// Result represents the outcome of a single health probe.
+type Result struct {
+	Dependency string        `json:"dependency"`
+	Healthy    bool          `json:"healthy"`
+	Error      string        `json:"error,omitempty"`
+	Latency    time.Duration `json:"latencyNs"`
+	CheckedAt  time.Time     `json:"checkedAt"`
+}
+
+// Status represents the dampened readiness state of a dependency.
+type Status struct {
+	Dependency  string    `json:"dependency"`
+	Ready       bool      `json:"ready"`
+	Flapping    bool      `json:"flapping"`
+	Transitions int       `json:"transitions"`
+	ChangedAt   time.Time `json:"changedAt"`
+	Error       string    `json:"error,omitempty"` // why a starting component is not ready yet
+	History     []Result  `json:"history,omitempty"`
+}
+
+// pingChecker adapts a Pinger to the Checker interface.
+type pingChecker struct {
+	name   string
+	pinger Pinger
+}
+
+// NewPingChecker creates a checker that pings the given dependency.
+func NewPingChecker(name string, pinger Pinger) Checker {
+	return &pingChecker{
+		name:   name,
+		pinger: pinger,
+	}
+}
+
+// Name returns the dependency name.
+func (c *pingChecker) Name() string {
+	return c.name
+}
+
+// Check pings the dependency.
+func (c *pingChecker) Check(ctx context.Context) error {
+	return c.pinger.Ping(ctx)
+}
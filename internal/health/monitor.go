@@ -0,0 +1,300 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// MonitorConfig holds configuration for the health monitor.
+type MonitorConfig struct {
+	// Interval is the time between probe rounds.
+	Interval time.Duration
+
+	// Timeout bounds each individual probe.
+	Timeout time.Duration
+
+	// HistorySize is the number of probe results retained per dependency.
+	HistorySize int
+
+	// FailureThreshold is the number of consecutive failed probes required
+	// before a ready dependency is reported as not ready.
+	FailureThreshold int
+
+	// RecoveryThreshold is the number of consecutive successful probes required
+	// before a not-ready dependency is reported as ready again.
+	RecoveryThreshold int
+
+	// FlapThreshold is the number of raw healthy/unhealthy transitions within the
+	// history window at which a dependency is considered flapping. While flapping,
+	// the failure and recovery thresholds are doubled.
+	FlapThreshold int
+}
+
+// DefaultMonitorConfig returns the default monitor configuration.
+func DefaultMonitorConfig() *MonitorConfig {
+	return &MonitorConfig{
+		Interval:          10 * time.Second,
+		Timeout:           2 * time.Second,
+		HistorySize:       60,
+		FailureThreshold:  3,
+		RecoveryThreshold: 2,
+		FlapThreshold:     6,
+	}
+}
+
+// dependencyState tracks probe history and dampened readiness for one dependency.
+type dependencyState struct {
+	history   []Result
+	next      int
+	count     int
+	ready     bool
+	changedAt time.Time
+	// streak counts consecutive results that disagree with the current ready state.
+	streak int
+}
+
+// startup tracks a component that holds readiness back until it has started.
+type startup struct {
+	name      string
+	check     StartupCheck
+	createdAt time.Time
+
+	mu        sync.Mutex
+	startedAt time.Time // zero until check first passes
+}
+
+// status runs the check until it first passes and reports whether the
+// component has started, when it last changed and why it has not started.
+func (s *startup) status() (bool, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.startedAt.IsZero() {
+		return true, s.startedAt, nil
+	}
+	if err := s.check(); err != nil {
+		return false, s.createdAt, err
+	}
+	s.startedAt = time.Now()
+	return true, s.startedAt, nil
+}
+
+// Monitor periodically probes dependencies, keeps a ring buffer of results and
+// derives a dampened readiness state that ignores brief blips.
+type Monitor struct {
+	config   *MonitorConfig
+	checkers []Checker
+	mu       sync.RWMutex
+	states   map[string]*dependencyState
+	startups []*startup
+	logger   zerolog.Logger
+}
+
+// NewMonitor creates a new health monitor for the given checkers.
+// Dependencies start as ready because startup has already verified connectivity.
+func NewMonitor(config *MonitorConfig, logger zerolog.Logger, checkers ...Checker) *Monitor {
+	if config == nil {
+		config = DefaultMonitorConfig()
+	}
+	if config.HistorySize < 1 {
+		config.HistorySize = 1
+	}
+
+	now := time.Now()
+	states := make(map[string]*dependencyState, len(checkers))
+	for _, c := range checkers {
+		states[c.Name()] = &dependencyState{
+			history:   make([]Result, config.HistorySize),
+			ready:     true,
+			changedAt: now,
+		}
+	}
+
+	return &Monitor{
+		config:   config,
+		checkers: checkers,
+		states:   states,
+		logger:   logger.With().Str("component", "health-monitor").Logger(),
+	}
+}
+
+// WaitFor holds readiness back until check reports that the named component
+// has started. Unlike probed dependencies it is not dampened: the component
+// counts as ready as soon as check passes, and stays ready. WaitFor must be
+// called before the monitor is in use.
+func (m *Monitor) WaitFor(name string, check StartupCheck) {
+	m.startups = append(m.startups, &startup{name: name, check: check, createdAt: time.Now()})
+}
+
+// Run probes all dependencies every interval until the context is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	m.ProbeOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.ProbeOnce(ctx)
+		}
+	}
+}
+
+// ProbeOnce runs a single round of probes against all dependencies.
+func (m *Monitor) ProbeOnce(ctx context.Context) {
+	for _, c := range m.checkers {
+		probeCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+		start := time.Now()
+		err := c.Check(probeCtx)
+		cancel()
+
+		result := Result{
+			Dependency: c.Name(),
+			Healthy:    err == nil,
+			Latency:    time.Since(start),
+			CheckedAt:  start,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		m.record(result)
+	}
+}
+
+// record appends a result to the ring buffer and updates the dampened state.
+func (m *Monitor) record(result Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[result.Dependency]
+	if !ok {
+		return
+	}
+
+	state.history[state.next] = result
+	state.next = (state.next + 1) % len(state.history)
+	if state.count < len(state.history) {
+		state.count++
+	}
+
+	if result.Healthy == state.ready {
+		state.streak = 0
+		return
+	}
+	state.streak++
+
+	threshold := m.config.RecoveryThreshold
+	if state.ready {
+		threshold = m.config.FailureThreshold
+	}
+	if m.transitions(state) >= m.config.FlapThreshold {
+		threshold *= 2
+	}
+
+	if state.streak < threshold {
+		return
+	}
+
+	state.ready = result.Healthy
+	state.changedAt = result.CheckedAt
+	state.streak = 0
+
+	event := m.logger.Warn()
+	if state.ready {
+		event = m.logger.Info()
+	}
+	event.
+		Str("dependency", result.Dependency).
+		Bool("ready", state.ready).
+		Msg("dependency readiness changed")
+}
+
+// ordered returns the retained results for a state from oldest to newest.
+func (m *Monitor) ordered(state *dependencyState) []Result {
+	results := make([]Result, 0, state.count)
+	start := (state.next - state.count + len(state.history)) % len(state.history)
+	for i := 0; i < state.count; i++ {
+		results = append(results, state.history[(start+i)%len(state.history)])
+	}
+	return results
+}
+
+// transitions counts raw healthy/unhealthy changes within the retained history.
+func (m *Monitor) transitions(state *dependencyState) int {
+	results := m.ordered(state)
+	count := 0
+	for i := 1; i < len(results); i++ {
+		if results[i].Healthy != results[i-1].Healthy {
+			count++
+		}
+	}
+	return count
+}
+
+// Ready reports whether every monitored dependency is ready.
+func (m *Monitor) Ready() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, state := range m.states {
+		if !state.ready {
+			return false
+		}
+	}
+	for _, s := range m.startups {
+		if started, _, _ := s.status(); !started {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns the dampened status of every dependency, sorted by name.
+// When includeHistory is true, the retained probe results are included.
+func (m *Monitor) Statuses(includeHistory bool) []Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(m.states))
+	for name, state := range m.states {
+		transitions := m.transitions(state)
+		status := Status{
+			Dependency:  name,
+			Ready:       state.ready,
+			Flapping:    transitions >= m.config.FlapThreshold,
+			Transitions: transitions,
+			ChangedAt:   state.changedAt,
+		}
+		if includeHistory {
+			status.History = m.ordered(state)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, s := range m.startups {
+		started, changedAt, err := s.status()
+		status := Status{
+			Dependency: s.name,
+			Ready:      started,
+			ChangedAt:  changedAt,
+		}
+		if err != nil {
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Dependency < statuses[j].Dependency
+	})
+
+	return statuses
+}
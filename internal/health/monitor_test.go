@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubChecker returns queued results in order, repeating the last one.
+type stubChecker struct {
+	name    string
+	results []error
+	calls   int
+}
+
+func (c *stubChecker) Name() string {
+	return c.name
+}
+
+func (c *stubChecker) Check(ctx context.Context) error {
+	idx := c.calls
+	if idx >= len(c.results) {
+		idx = len(c.results) - 1
+	}
+	c.calls++
+	return c.results[idx]
+}
+
+func testMonitorConfig() *MonitorConfig {
+	return &MonitorConfig{
+		Interval:          time.Second,
+		Timeout:           time.Second,
+		HistorySize:       5,
+		FailureThreshold:  2,
+		RecoveryThreshold: 2,
+		FlapThreshold:     3,
+	}
+}
+
+func TestMonitor_DampensBriefFailure(t *testing.T) {
+	errDown := errors.New("connection refused")
+	checker := &stubChecker{name: "database", results: []error{nil, errDown, nil}}
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(), checker)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		monitor.ProbeOnce(ctx)
+		assert.True(t, monitor.Ready(), "single failure should not flip readiness (probe %d)", i)
+	}
+}
+
+func TestMonitor_TransitionsAfterThreshold(t *testing.T) {
+	errDown := errors.New("connection refused")
+	checker := &stubChecker{name: "database", results: []error{errDown, errDown, nil, nil}}
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(), checker)
+	ctx := context.Background()
+
+	monitor.ProbeOnce(ctx)
+	assert.True(t, monitor.Ready())
+
+	monitor.ProbeOnce(ctx)
+	assert.False(t, monitor.Ready())
+
+	monitor.ProbeOnce(ctx)
+	assert.False(t, monitor.Ready())
+
+	monitor.ProbeOnce(ctx)
+	assert.True(t, monitor.Ready())
+}
+
+func TestMonitor_FlappingDoublesThreshold(t *testing.T) {
+	errDown := errors.New("connection refused")
+	checker := &stubChecker{
+		name:    "database",
+		results: []error{nil, errDown, nil, errDown, errDown, errDown},
+	}
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(), checker)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		monitor.ProbeOnce(ctx)
+	}
+
+	statuses := monitor.Statuses(false)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Flapping)
+	// Two consecutive failures would normally flip readiness, but flapping doubles it
+	assert.True(t, monitor.Ready())
+}
+
+func TestMonitor_HistoryRingBuffer(t *testing.T) {
+	errDown := errors.New("connection refused")
+	results := []error{nil, nil, errDown, nil, nil, nil, errDown}
+	checker := &stubChecker{name: "database", results: results}
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(), checker)
+	ctx := context.Background()
+
+	for range results {
+		monitor.ProbeOnce(ctx)
+	}
+
+	statuses := monitor.Statuses(true)
+	require.Len(t, statuses, 1)
+	history := statuses[0].History
+	require.Len(t, history, 5, "history should be capped at the configured size")
+
+	// Oldest retained result is the third probe; newest is the last
+	assert.False(t, history[0].Healthy)
+	assert.Equal(t, "connection refused", history[0].Error)
+	assert.False(t, history[4].Healthy)
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].CheckedAt.Before(history[i-1].CheckedAt))
+	}
+}
+
+func TestMonitor_StatusesSortedByDependency(t *testing.T) {
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(),
+		&stubChecker{name: "redis", results: []error{nil}},
+		&stubChecker{name: "database", results: []error{nil}},
+	)
+
+	statuses := monitor.Statuses(false)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "database", statuses[0].Dependency)
+	assert.Equal(t, "redis", statuses[1].Dependency)
+	assert.Nil(t, statuses[0].History)
+}
+
+func TestMonitor_WaitFor(t *testing.T) {
+	monitor := NewMonitor(testMonitorConfig(), zerolog.Nop(), &stubChecker{name: "database", results: []error{nil}})
+
+	loadErr := errors.New("coupon files are loading")
+	monitor.WaitFor("coupons", func() error { return loadErr })
+
+	assert.False(t, monitor.Ready())
+	statuses := monitor.Statuses(false)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "coupons", statuses[0].Dependency)
+	assert.False(t, statuses[0].Ready)
+	assert.Equal(t, "coupon files are loading", statuses[0].Error)
+
+	// Ready without dampening once started, and stays ready
+	loadErr = nil
+	assert.True(t, monitor.Ready())
+	loadErr = errors.New("unloaded")
+	assert.True(t, monitor.Ready())
+	statuses = monitor.Statuses(false)
+	assert.True(t, statuses[0].Ready)
+	assert.Empty(t, statuses[0].Error)
+}
+
+func TestMonitor_Run_StopsOnCancel(t *testing.T) {
+	checker := &stubChecker{name: "database", results: []error{nil}}
+	config := testMonitorConfig()
+	config.Interval = 10 * time.Millisecond
+	monitor := NewMonitor(config, zerolog.Nop(), checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(35 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not stop after context cancellation")
+	}
+
+	assert.GreaterOrEqual(t, len(monitor.Statuses(true)[0].History), 2)
+}
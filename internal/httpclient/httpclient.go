@@ -0,0 +1,45 @@
+// Package httpclient builds the HTTP client shared by every outbound call, so
+// timeouts, connection pooling and proxy settings are configured in one place
+// instead of each caller falling back to http.DefaultClient.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+
+	"mini-kart/internal/config"
+)
+
+// New creates an HTTP client from the given configuration. Proxies are taken
+// from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+//
+// The client should be created once and shared: its transport pools
+// keep-alive connections, which only pays off when requests reuse it.
+func New(cfg config.HTTPClientConfig) *http.Client {
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: NewTransport(cfg),
+	}
+}
+
+// NewTransport creates the pooled transport used by New. Callers that stream
+// large response bodies can wrap it in a client without an overall timeout
+// and rely on the dial, handshake and response header timeouts instead.
+func NewTransport(cfg config.HTTPClientConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+	}
+}
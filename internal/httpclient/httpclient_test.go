@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mini-kart/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() config.HTTPClientConfig {
+	return config.HTTPClientConfig{
+		Timeout:               30 * time.Second,
+		DialTimeout:           5 * time.Second,
+		KeepAlive:             30 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		MaxConnsPerHost:       50,
+	}
+}
+
+func TestNew(t *testing.T) {
+	cfg := testConfig()
+
+	client := New(cfg)
+
+	assert.Equal(t, cfg.Timeout, client.Timeout)
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, cfg.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	assert.Equal(t, cfg.ResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	assert.Equal(t, cfg.IdleConnTimeout, transport.IdleConnTimeout)
+	assert.Equal(t, cfg.MaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, cfg.MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, cfg.MaxConnsPerHost, transport.MaxConnsPerHost)
+	assert.NotNil(t, transport.Proxy)
+	assert.NotNil(t, transport.DialContext)
+}
+
+func TestNew_ReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := New(testConfig())
+
+	for range 3 {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	assert.Equal(t, int32(1), connections.Load())
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// RotatingFile is a Sink appending to a log file that is rotated
+// once it reaches a maximum size, so deployments without logrotate do not
+// fill their disks. The rotated files are kept as path.1 (the newest) to
+// path.N.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it if
+// needed. Once a write would take the file past maxSize bytes it is rotated,
+// keeping at most maxBackups rotated files; with none it is truncated.
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// its maximum size. An entry larger than the maximum gets a file to itself.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// WriteLevel appends p to the file; files have no levels.
+func (f *RotatingFile) WriteLevel(_ zerolog.Level, p []byte) (int, error) {
+	return f.Write(p)
+}
+
+// Close closes the file. Later writes fail with os.ErrClosed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the file for appending and records its current size.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, moves the
+// current file to path.1 and starts a new one.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	if f.maxBackups > 0 {
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			err := os.Rename(f.backup(i), f.backup(i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate log file: %w", err)
+			}
+		}
+		if err := os.Rename(f.path, f.backup(1)); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	} else if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to truncate log file: %w", err)
+	}
+
+	return f.open()
+}
+
+// backup returns the path of the nth newest rotated file.
+func (f *RotatingFile) backup(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
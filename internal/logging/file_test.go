@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackups int
+		entries    []string
+		expected   map[string]string
+	}{
+		{
+			name:       "Below max size",
+			maxBackups: 2,
+			entries:    []string{"aaaa\n", "bbbb\n"},
+			expected:   map[string]string{"app.log": "aaaa\nbbbb\n"},
+		},
+		{
+			name:       "Rotates and keeps backups",
+			maxBackups: 2,
+			entries:    []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n"},
+			expected: map[string]string{
+				"app.log":   "eeee\n",
+				"app.log.1": "cccc\ndddd\n",
+				"app.log.2": "aaaa\nbbbb\n",
+			},
+		},
+		{
+			name:       "Drops oldest backup",
+			maxBackups: 1,
+			entries:    []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n"},
+			expected: map[string]string{
+				"app.log":   "eeee\n",
+				"app.log.1": "cccc\ndddd\n",
+			},
+		},
+		{
+			name:       "No backups truncates",
+			maxBackups: 0,
+			entries:    []string{"aaaa\n", "bbbb\n", "cccc\n"},
+			expected:   map[string]string{"app.log": "cccc\n"},
+		},
+		{
+			name:       "Oversized entry gets its own file",
+			maxBackups: 1,
+			entries:    []string{"aa\n", "bbbbbbbbbbbbbbbb\n"},
+			expected: map[string]string{
+				"app.log":   "bbbbbbbbbbbbbbbb\n",
+				"app.log.1": "aa\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			f, err := OpenRotatingFile(filepath.Join(dir, "app.log"), 10, tt.maxBackups)
+			require.NoError(t, err)
+
+			for _, entry := range tt.entries {
+				n, err := f.Write([]byte(entry))
+				require.NoError(t, err)
+				assert.Equal(t, len(entry), n)
+			}
+			require.NoError(t, f.Close())
+
+			files, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			actual := make(map[string]string, len(files))
+			for _, file := range files {
+				data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+				require.NoError(t, err)
+				actual[file.Name()] = string(data)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRotatingFile_AppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("aaaaaaaa\n"), 0o644))
+
+	f, err := OpenRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("bbbb\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The existing file's size counts towards the maximum
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "aaaaaaaa\n", string(data))
+
+	_, err = f.Write([]byte("cccc\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
@@ -0,0 +1,199 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink is a destination logs are shipped to. Close flushes any buffered
+// entries and releases the sink's files or connections.
+type Sink interface {
+	zerolog.LevelWriter
+	io.Closer
+}
+
+// HTTP sink body formats.
+const (
+	// FormatNDJSON posts batches as newline-delimited JSON, one entry per
+	// line, as accepted by collectors such as Vector and Fluent Bit.
+	FormatNDJSON = "ndjson"
+
+	// FormatOTLP posts batches as OTLP/HTTP JSON log records, as accepted
+	// by OpenTelemetry collectors on /v1/logs.
+	FormatOTLP = "otlp"
+)
+
+// HTTPSinkConfig configures an HTTPSink.
+type HTTPSinkConfig struct {
+	// Endpoint is the URL batches are posted to.
+	Endpoint string
+
+	// Format is FormatNDJSON or FormatOTLP.
+	Format string
+
+	// BatchSize is the most entries posted at once. Up to four batches are
+	// buffered while a post is in flight; later entries are dropped.
+	BatchSize int
+
+	// FlushInterval is the longest an entry waits before it is posted.
+	FlushInterval time.Duration
+
+	// ServiceName identifies the service in OTLP batches.
+	ServiceName string
+
+	// Client posts the batches.
+	Client *http.Client
+}
+
+// HTTPSink is a Sink posting JSON log entries to an HTTP collector in
+// batches, so deployments without a log collecting sidecar can ship logs
+// off-host. Writes never block on the network: when the collector falls
+// behind, entries are dropped and counted instead of slowing requests down.
+// Failures are reported on stderr, as logging them would feed the sink.
+type HTTPSink struct {
+	cfg     HTTPSinkConfig
+	entries chan []byte
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewHTTPSink creates an HTTP sink and starts posting its batches.
+func NewHTTPSink(cfg HTTPSinkConfig) *HTTPSink {
+	s := &HTTPSink{
+		cfg:     cfg,
+		entries: make(chan []byte, 4*cfg.BatchSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write queues an entry to be posted.
+func (s *HTTPSink) Write(p []byte) (int, error) {
+	entry := bytes.TrimRight(p, "\n")
+	if len(entry) == 0 {
+		return len(p), nil
+	}
+
+	select {
+	case s.entries <- bytes.Clone(entry):
+	default:
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// WriteLevel queues an entry to be posted; the level is read from the entry.
+func (s *HTTPSink) WriteLevel(_ zerolog.Level, p []byte) (int, error) {
+	return s.Write(p)
+}
+
+// Close posts the entries queued so far and stops the sink. Entries written
+// after Close are dropped.
+func (s *HTTPSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		<-s.stopped
+	})
+	return nil
+}
+
+// run posts a batch whenever it fills or the flush interval passes.
+func (s *HTTPSink) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.cfg.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.post(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.done:
+			for {
+				select {
+				case entry := <-s.entries:
+					batch = append(batch, entry)
+					if len(batch) >= s.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// post sends a batch to the collector.
+func (s *HTTPSink) post(batch [][]byte) {
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		fmt.Fprintf(os.Stderr, "log sink: dropped %d log entries while the collector was behind\n", dropped)
+	}
+
+	var body []byte
+	contentType := "application/x-ndjson"
+	if s.cfg.Format == FormatOTLP {
+		body = otlpLogs(s.cfg.ServiceName, batch)
+		contentType = "application/json"
+	} else {
+		body = append(bytes.Join(batch, []byte("\n")), '\n')
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "log sink: failed to ship %d log entries: %v\n", len(batch), err)
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "log sink: failed to ship %d log entries: %v\n", len(batch), err)
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		fmt.Fprintf(os.Stderr, "log sink: collector rejected %d log entries with status %d\n", len(batch), resp.StatusCode)
+	}
+}
+
+// timeout bounds each post, so a hung collector cannot hold up Close.
+func (s *HTTPSink) timeout() time.Duration {
+	if s.cfg.Client.Timeout > 0 {
+		return s.cfg.Client.Timeout
+	}
+	return 10 * time.Second
+}
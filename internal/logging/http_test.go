@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector records the batches posted to it.
+type collector struct {
+	mu           sync.Mutex
+	bodies       []string
+	contentTypes []string
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.bodies = append(c.bodies, string(body))
+		c.contentTypes = append(c.contentTypes, r.Header.Get("Content-Type"))
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func (c *collector) posted() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.bodies...)
+}
+
+func TestHTTPSink_NDJSON(t *testing.T) {
+	c, server := newCollector(t)
+	sink := NewHTTPSink(HTTPSinkConfig{
+		Endpoint:      server.URL,
+		Format:        FormatNDJSON,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Client:        server.Client(),
+	})
+
+	logger := zerolog.New(sink)
+	logger.Info().Str("order_id", "o-1").Msg("order created")
+	logger.Warn().Msg("stock low")
+	logger.Error().Msg("payment failed")
+
+	// Close flushes the partial batch
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, []string{
+		`{"level":"info","order_id":"o-1","message":"order created"}` + "\n" +
+			`{"level":"warn","message":"stock low"}` + "\n",
+		`{"level":"error","message":"payment failed"}` + "\n",
+	}, c.posted())
+	assert.Equal(t, []string{"application/x-ndjson", "application/x-ndjson"}, c.contentTypes)
+
+	// Entries written after Close are dropped
+	logger.Info().Msg("too late")
+	require.NoError(t, sink.Close())
+	assert.Len(t, c.posted(), 2)
+}
+
+func TestHTTPSink_FlushInterval(t *testing.T) {
+	c, server := newCollector(t)
+	sink := NewHTTPSink(HTTPSinkConfig{
+		Endpoint:      server.URL,
+		Format:        FormatNDJSON,
+		BatchSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+		Client:        server.Client(),
+	})
+	defer sink.Close()
+
+	_, err := sink.Write([]byte(`{"message":"tick"}` + "\n"))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(c.posted()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestHTTPSink_OTLP(t *testing.T) {
+	c, server := newCollector(t)
+	sink := NewHTTPSink(HTTPSinkConfig{
+		Endpoint:      server.URL,
+		Format:        FormatOTLP,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		ServiceName:   "mini-kart",
+		Client:        server.Client(),
+	})
+
+	_, err := sink.Write([]byte(`{"level":"warn","time":"2024-05-01T10:00:00Z","order_id":"o-1","items":3,"total":12.5,"paid":true,"tags":["a"],"message":"stock low"}` + "\n"))
+	require.NoError(t, err)
+	_, err = sink.Write([]byte("not json\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	bodies := c.posted()
+	require.Len(t, bodies, 1)
+	assert.Equal(t, "application/json", c.contentTypes[0])
+
+	var payload struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []otlpKeyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []otlpLogRecord `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(bodies[0]), &payload))
+	require.Len(t, payload.ResourceLogs, 1)
+	assert.Equal(t, []otlpKeyValue{{Key: "service.name", Value: map[string]any{"stringValue": "mini-kart"}}},
+		payload.ResourceLogs[0].Resource.Attributes)
+
+	records := payload.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+
+	assert.Equal(t, otlpLogRecord{
+		TimeUnixNano:   "1714557600000000000",
+		SeverityNumber: 13,
+		SeverityText:   "warn",
+		Body:           map[string]any{"stringValue": "stock low"},
+		Attributes: []otlpKeyValue{
+			{Key: "items", Value: map[string]any{"intValue": "3"}},
+			{Key: "order_id", Value: map[string]any{"stringValue": "o-1"}},
+			{Key: "paid", Value: map[string]any{"boolValue": true}},
+			{Key: "tags", Value: map[string]any{"stringValue": `["a"]`}},
+			{Key: "total", Value: map[string]any{"doubleValue": 12.5}},
+		},
+	}, records[0])
+
+	assert.Equal(t, map[string]any{"stringValue": "not json"}, records[1].Body)
+	assert.Zero(t, records[1].SeverityNumber)
+	assert.NotEmpty(t, records[1].TimeUnixNano)
+}
+
+func TestHTTPSink_DropsWhenBehind(t *testing.T) {
+	release := make(chan struct{})
+	var posts sync.WaitGroup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Done()
+		<-release
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(HTTPSinkConfig{
+		Endpoint:      server.URL,
+		Format:        FormatNDJSON,
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		Client:        server.Client(),
+	})
+
+	// The first entry's post hangs, leaving room for four more
+	posts.Add(1)
+	_, _ = sink.Write([]byte(`{"n":0}`))
+	posts.Wait()
+
+	for i := 0; i < 10; i++ {
+		n, err := sink.Write([]byte(`{"n":1}` + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, len(`{"n":1}`)+1, n)
+	}
+	assert.Equal(t, int64(6), sink.dropped.Load())
+
+	posts.Add(4)
+	close(release)
+	require.NoError(t, sink.Close())
+}
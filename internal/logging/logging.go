@@ -0,0 +1,48 @@
+// Package logging bridges the service's zerolog loggers and log/slog, so
+// embedders of the service packages can bring their own slog logger,
+// changes the log level while the service runs, and provides the file,
+// syslog and HTTP sinks logs are shipped to.
+package logging
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrInvalidLevel is returned for level names other than debug, info, warn
+// and error.
+var ErrInvalidLevel = errors.New("log level must be debug, info, warn or error")
+
+// levels maps the accepted level names to zerolog levels.
+var levels = map[string]zerolog.Level{
+	"debug": zerolog.DebugLevel,
+	"info":  zerolog.InfoLevel,
+	"warn":  zerolog.WarnLevel,
+	"error": zerolog.ErrorLevel,
+}
+
+// ParseLevel returns the zerolog level named name.
+func ParseLevel(name string) (zerolog.Level, error) {
+	level, ok := levels[name]
+	if !ok {
+		return zerolog.NoLevel, ErrInvalidLevel
+	}
+	return level, nil
+}
+
+// SetLevel changes the minimum level logged by every logger, including those
+// adapted to and from slog. It is safe to call while the service is running.
+func SetLevel(name string) error {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+// Level returns the name of the minimum level currently logged.
+func Level() string {
+	return zerolog.GlobalLevel().String()
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreLevel resets the global level when the test ends.
+func restoreLevel(t *testing.T) {
+	level := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected zerolog.Level
+		err      error
+	}{
+		{name: "debug", expected: zerolog.DebugLevel},
+		{name: "info", expected: zerolog.InfoLevel},
+		{name: "warn", expected: zerolog.WarnLevel},
+		{name: "error", expected: zerolog.ErrorLevel},
+		{name: "trace", expected: zerolog.NoLevel, err: ErrInvalidLevel},
+		{name: "", expected: zerolog.NoLevel, err: ErrInvalidLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.name)
+			assert.Equal(t, tt.expected, level)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	restoreLevel(t)
+
+	require.NoError(t, SetLevel("warn"))
+	assert.Equal(t, "warn", Level())
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	assert.ErrorIs(t, SetLevel("verbose"), ErrInvalidLevel)
+	assert.Equal(t, "warn", Level())
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// otlpSeverities maps zerolog levels to OTLP severity numbers.
+var otlpSeverities = map[string]int{
+	zerolog.LevelTraceValue: 1,
+	zerolog.LevelDebugValue: 5,
+	zerolog.LevelInfoValue:  9,
+	zerolog.LevelWarnValue:  13,
+	zerolog.LevelErrorValue: 17,
+	zerolog.LevelFatalValue: 21,
+	zerolog.LevelPanicValue: 21,
+}
+
+// otlpKeyValue is an OTLP attribute.
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+// otlpLogRecord is an OTLP log record in its JSON encoding.
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber,omitempty"`
+	SeverityText   string         `json:"severityText,omitempty"`
+	Body           map[string]any `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+// otlpLogs encodes zerolog JSON entries as an OTLP/HTTP JSON logs request.
+// The message becomes the record's body, the level its severity and the
+// other fields its attributes. Entries that are not JSON objects become the
+// body as they are.
+func otlpLogs(serviceName string, entries [][]byte) []byte {
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, otlpRecord(entry))
+	}
+
+	// Marshalling these types cannot fail
+	body, _ := json.Marshal(map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": []otlpKeyValue{{Key: "service.name", Value: otlpValue(serviceName)}},
+			},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": "mini-kart/internal/logging"},
+				"logRecords": records,
+			}},
+		}},
+	})
+	return body
+}
+
+// otlpRecord converts a zerolog JSON entry to an OTLP log record.
+func otlpRecord(entry []byte) otlpLogRecord {
+	record := otlpLogRecord{TimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10)}
+
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(entry))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		record.Body = otlpValue(string(entry))
+		return record
+	}
+
+	if message, ok := fields[zerolog.MessageFieldName].(string); ok {
+		record.Body = otlpValue(message)
+		delete(fields, zerolog.MessageFieldName)
+	} else {
+		record.Body = otlpValue("")
+	}
+
+	if level, ok := fields[zerolog.LevelFieldName].(string); ok {
+		record.SeverityText = level
+		record.SeverityNumber = otlpSeverities[level]
+		delete(fields, zerolog.LevelFieldName)
+	}
+
+	if ts, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if at, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			record.TimeUnixNano = strconv.FormatInt(at.UnixNano(), 10)
+			delete(fields, zerolog.TimestampFieldName)
+		}
+	}
+
+	for key, value := range fields {
+		record.Attributes = append(record.Attributes, otlpKeyValue{Key: key, Value: otlpValue(value)})
+	}
+	// Map iteration is random, so sort the attributes to keep encodings stable
+	slices.SortFunc(record.Attributes, func(a, b otlpKeyValue) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return record
+}
+
+// otlpValue encodes a decoded JSON value as an OTLP AnyValue. Objects and
+// arrays are kept as their JSON text.
+func otlpValue(value any) map[string]any {
+	switch v := value.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			// OTLP JSON encodes 64-bit integers as strings
+			return map[string]any{"intValue": strconv.FormatInt(i, 10)}
+		}
+		f, _ := v.Float64()
+		return map[string]any{"doubleValue": f}
+	default:
+		text, _ := json.Marshal(v)
+		return map[string]any{"stringValue": string(text)}
+	}
+}
@@ -0,0 +1,241 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Slog returns a slog logger writing through logger, for code written
+// against log/slog. Records below the level set by SetLevel are dropped.
+func Slog(logger zerolog.Logger) *slog.Logger {
+	return slog.New(&zerologHandler{logger: logger})
+}
+
+// FromSlog returns a zerolog logger writing through handler, for embedders
+// passing their own slog logger's handler to the service constructors.
+// Fields added with zerolog become the records' attributes.
+func FromSlog(handler slog.Handler) zerolog.Logger {
+	return zerolog.New(&slogWriter{handler: handler})
+}
+
+// zerologHandler is a slog.Handler writing to a zerolog logger.
+type zerologHandler struct {
+	logger zerolog.Logger
+
+	// groups are the open groups, outermost first
+	groups []string
+
+	// grouped holds attributes added inside groups, which zerolog cannot
+	// nest, until a record is written
+	grouped []groupedAttr
+}
+
+// groupedAttr is an attribute added inside groups.
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// Enabled reports whether the logger and the global level allow level.
+func (h *zerologHandler) Enabled(_ context.Context, level slog.Level) bool {
+	zl := zerologLevel(level)
+	return zl >= zerolog.GlobalLevel() && zl >= h.logger.GetLevel()
+}
+
+// Handle writes a record as a zerolog event.
+func (h *zerologHandler) Handle(_ context.Context, record slog.Record) error {
+	event := h.logger.WithLevel(zerologLevel(record.Level))
+	if event == nil {
+		return nil
+	}
+
+	fields := make(map[string]any)
+	for _, g := range h.grouped {
+		addAttr(fields, g.groups, g.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.groups, attr)
+		return true
+	})
+
+	event.Fields(fields).Msg(record.Message)
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *zerologHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	if len(h.groups) == 0 {
+		fields := make(map[string]any)
+		for _, attr := range attrs {
+			addAttr(fields, nil, attr)
+		}
+		clone.logger = h.logger.With().Fields(fields).Logger()
+		return &clone
+	}
+
+	clone.grouped = slices.Clone(h.grouped)
+	for _, attr := range attrs {
+		clone.grouped = append(clone.grouped, groupedAttr{groups: h.groups, attr: attr})
+	}
+	return &clone
+}
+
+// WithGroup returns a handler nesting later attributes under name.
+func (h *zerologHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(slices.Clone(h.groups), name)
+	return &clone
+}
+
+// addAttr adds attr to fields, nested under groups.
+func addAttr(fields map[string]any, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+		// Attributes of a group without a key are inlined
+		if attr.Key != "" {
+			groups = append(slices.Clone(groups), attr.Key)
+		}
+		for _, member := range members {
+			addAttr(fields, groups, member)
+		}
+		return
+	}
+
+	for _, group := range groups {
+		nested, ok := fields[group].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			fields[group] = nested
+		}
+		fields = nested
+	}
+
+	switch value := attr.Value.Any().(type) {
+	case error:
+		fields[attr.Key] = value.Error()
+	default:
+		fields[attr.Key] = value
+	}
+}
+
+// zerologLevel maps a slog level to the nearest zerolog level.
+func zerologLevel(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return zerolog.TraceLevel
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+// slogLevel maps a zerolog level to a slog level.
+func slogLevel(level zerolog.Level) slog.Level {
+	switch level {
+	case zerolog.TraceLevel:
+		return slog.LevelDebug - 4
+	case zerolog.DebugLevel:
+		return slog.LevelDebug
+	case zerolog.WarnLevel:
+		return slog.LevelWarn
+	case zerolog.ErrorLevel:
+		return slog.LevelError
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// slogWriter is a zerolog writer passing events to a slog.Handler.
+type slogWriter struct {
+	handler slog.Handler
+}
+
+// Write passes an event without a level to the handler at info level.
+func (w *slogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel decodes a zerolog JSON event and passes it to the handler as a
+// record, with the event's fields as attributes in key order.
+func (w *slogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	ctx := context.Background()
+	if !w.handler.Enabled(ctx, slogLevel(level)) {
+		return len(p), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return 0, err
+	}
+
+	message, _ := fields[zerolog.MessageFieldName].(string)
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, zerolog.LevelFieldName)
+
+	recordTime := time.Now()
+	if ts, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if parsed, err := time.Parse(zerolog.TimeFieldFormat, ts); err == nil {
+			recordTime = parsed
+		}
+		delete(fields, zerolog.TimestampFieldName)
+	}
+
+	record := slog.NewRecord(recordTime, slogLevel(level), message, 0)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record.AddAttrs(slog.Any(key, number(fields[key])))
+	}
+
+	if err := w.handler.Handle(ctx, record); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// number converts a decoded JSON number to an int64 or float64, leaving
+// other values as they are.
+func number(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeLines decodes each line written to buf as a JSON object.
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var line map[string]any
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSlog(t *testing.T) {
+	restoreLevel(t)
+	require.NoError(t, SetLevel("debug"))
+
+	var buf bytes.Buffer
+	logger := Slog(zerolog.New(&buf).With().Str("component", "test").Logger())
+
+	t.Run("Writes records with attributes", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("order created", "order_id", "O1", "items", 2, "err", errors.New("boom"))
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, map[string]any{
+			"level":     "info",
+			"component": "test",
+			"order_id":  "O1",
+			"items":     float64(2),
+			"err":       "boom",
+			"message":   "order created",
+		}, lines[0])
+	})
+
+	t.Run("Nests groups", func(t *testing.T) {
+		buf.Reset()
+		logger.With("request", "R1").
+			WithGroup("order").With("id", "O1").
+			WithGroup("item").
+			Warn("low stock", "sku", "P001", slog.Group("stock", "left", 1), slog.Group("empty"))
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "warn", lines[0]["level"])
+		assert.Equal(t, "R1", lines[0]["request"])
+		assert.Equal(t, map[string]any{
+			"id": "O1",
+			"item": map[string]any{
+				"sku":   "P001",
+				"stock": map[string]any{"left": float64(1)},
+			},
+		}, lines[0]["order"])
+	})
+
+	t.Run("Follows the global level", func(t *testing.T) {
+		buf.Reset()
+		require.NoError(t, SetLevel("warn"))
+		t.Cleanup(func() { _ = SetLevel("debug") })
+
+		logger.Info("dropped")
+		logger.Error("kept")
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "kept", lines[0]["message"])
+		assert.False(t, logger.Enabled(t.Context(), slog.LevelInfo))
+	})
+}
+
+func TestFromSlog(t *testing.T) {
+	restoreLevel(t)
+	require.NoError(t, SetLevel("debug"))
+
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := FromSlog(handler).With().Str("handler", "orders").Logger()
+
+	t.Run("Passes events as records", func(t *testing.T) {
+		buf.Reset()
+		logger.Warn().Int("status", 409).Dur("latency", 1500*time.Millisecond).Msg("conflict")
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "WARN", lines[0]["level"])
+		assert.Equal(t, "conflict", lines[0]["msg"])
+		assert.Equal(t, "orders", lines[0]["handler"])
+		assert.Equal(t, float64(409), lines[0]["status"])
+		assert.Equal(t, float64(1500), lines[0]["latency"])
+		assert.NotContains(t, lines[0], "message")
+	})
+
+	t.Run("Uses the handler's level", func(t *testing.T) {
+		buf.Reset()
+		logger.Debug().Msg("dropped")
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("Uses the event's timestamp", func(t *testing.T) {
+		buf.Reset()
+		timestamped := logger.With().Timestamp().Logger()
+		timestamped.Error().Msg("failed")
+
+		lines := decodeLines(t, &buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "ERROR", lines[0]["level"])
+		logged, err := time.Parse(time.RFC3339Nano, lines[0]["time"].(string))
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), logged, time.Minute)
+	})
+}
@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logging
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// DialSyslog reports that syslog is not available on this platform.
+func DialSyslog(address, tag string) (Sink, error) {
+	return nil, fmt.Errorf("syslog is not supported on %s", runtime.GOOS)
+}
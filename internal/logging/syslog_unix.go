@@ -0,0 +1,50 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"fmt"
+	"log/syslog"
+	"net/url"
+
+	"github.com/rs/zerolog"
+)
+
+// syslogSink writes entries to a syslog daemon at their level's severity.
+type syslogSink struct {
+	zerolog.LevelWriter
+	conn *syslog.Writer
+}
+
+// Close closes the connection to the daemon.
+func (s *syslogSink) Close() error {
+	return s.conn.Close()
+}
+
+// DialSyslog connects to the syslog daemon at address, given as
+// "udp://host:514", "tcp://host:514" or "unixgram:///dev/log", or to the
+// local daemon when address is empty. Entries are sent from the daemon
+// facility, tagged with tag, at the syslog severity of their level.
+func DialSyslog(address, tag string) (Sink, error) {
+	var network, raddr string
+	if address != "" {
+		u, err := url.Parse(address)
+		if err != nil || u.Scheme == "" {
+			return nil, fmt.Errorf("invalid syslog address %q", address)
+		}
+		network, raddr = u.Scheme, u.Host
+		if raddr == "" {
+			raddr = u.Path
+		}
+		if raddr == "" {
+			return nil, fmt.Errorf("invalid syslog address %q", address)
+		}
+	}
+
+	conn, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+
+	return &syslogSink{LevelWriter: zerolog.SyslogLevelWriter(conn), conn: conn}, nil
+}
@@ -0,0 +1,42 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := DialSyslog("udp://"+conn.LocalAddr().String(), "mini-kart")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	logger := zerolog.New(sink)
+	logger.Warn().Msg("stock low")
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	// Warnings are sent at the warning severity of the daemon facility
+	message := string(buf[:n])
+	assert.Contains(t, message, "<28>")
+	assert.Contains(t, message, "mini-kart")
+	assert.Contains(t, message, `{"level":"warn","message":"stock low"}`)
+}
+
+func TestDialSyslog_InvalidAddress(t *testing.T) {
+	_, err := DialSyslog("localhost:514", "mini-kart")
+	assert.ErrorContains(t, err, "invalid syslog address")
+}
@@ -0,0 +1,179 @@
+// Package media stores product image files in S3. Clients upload images
+// straight to the bucket with presigned URLs, so image bytes never pass
+// through the API.
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/rs/zerolog"
+)
+
+// ImageStore stores product image files under keys.
+type ImageStore interface {
+	// PresignUpload returns a presigned upload of a file of the content type
+	// and exact size to key.
+	PresignUpload(ctx context.Context, key, contentType string, sizeBytes int64) (*model.ImageUpload, error)
+
+	// Stat describes the file stored under key. Returns nil when there is none.
+	Stat(ctx context.Context, key string) (*model.ImageObject, error)
+
+	// Delete removes the file stored under key, if any.
+	Delete(ctx context.Context, key string) error
+
+	// URL returns the URL the file stored under key is served from.
+	URL(key string) string
+}
+
+// S3ImageStoreConfig holds S3 product image storage configuration.
+type S3ImageStoreConfig struct {
+	// Bucket and Region locate the S3 bucket images are stored in.
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every image key.
+	Prefix string
+
+	// BaseURL is where stored images are served from, such as a CDN in
+	// front of the bucket, followed by their prefixed key. Empty serves
+	// images from the bucket's virtual-hosted URL.
+	BaseURL string
+
+	// UploadExpiry is how long presigned upload URLs are valid.
+	UploadExpiry time.Duration
+}
+
+// s3ImageStore implements ImageStore for an S3 bucket.
+type s3ImageStore struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	config  S3ImageStoreConfig
+	logger  zerolog.Logger
+	now     func() time.Time
+}
+
+// NewS3ImageStore creates an ImageStore keeping images in an S3 bucket. A nil
+// httpClient uses the AWS SDK's default client.
+func NewS3ImageStore(ctx context.Context, cfg S3ImageStoreConfig, httpClient *http.Client, logger zerolog.Logger) (ImageStore, error) {
+	logger = logger.With().Str("component", "s3-image-store").Logger()
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}
+	if httpClient != nil {
+		opts = append(opts, config.WithHTTPClient(httpClient))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load AWS configuration")
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return newS3ImageStore(s3.NewFromConfig(awsCfg), cfg, logger), nil
+}
+
+// newS3ImageStore creates an ImageStore using client.
+func newS3ImageStore(client *s3.Client, cfg S3ImageStoreConfig, logger zerolog.Logger) *s3ImageStore {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", cfg.Bucket, cfg.Region)
+	}
+	if !strings.HasSuffix(cfg.BaseURL, "/") {
+		cfg.BaseURL += "/"
+	}
+
+	return &s3ImageStore{
+		client:  client,
+		presign: s3.NewPresignClient(client),
+		config:  cfg,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// PresignUpload presigns a PUT of key. The content type and length are
+// signed, so S3 refuses an upload of any other type or size.
+func (s *s3ImageStore) PresignUpload(ctx context.Context, key, contentType string, sizeBytes int64) (*model.ImageUpload, error) {
+	expiresAt := s.now().Add(s.config.UploadExpiry).UTC().Truncate(time.Second)
+
+	req, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.config.Bucket),
+		Key:           aws.String(s.config.Prefix + key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(sizeBytes),
+	}, s3.WithPresignExpires(s.config.UploadExpiry))
+	if err != nil {
+		s.logger.Error().Err(err).Str("bucket", s.config.Bucket).Str("key", key).Msg("failed to presign image upload")
+		return nil, fmt.Errorf("failed to presign image upload %s: %w", key, err)
+	}
+
+	// Clients send every signed header but Host, which their HTTP client sets
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name, values := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return &model.ImageUpload{
+		Key:       key,
+		URL:       req.URL,
+		Method:    req.Method,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Stat describes the object stored under key.
+func (s *s3ImageStore) Stat(ctx context.Context, key string) (*model.ImageObject, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		s.logger.Error().Err(err).Str("bucket", s.config.Bucket).Str("key", key).Msg("failed to head image object")
+		return nil, fmt.Errorf("failed to head image object %s: %w", key, err)
+	}
+
+	return &model.ImageObject{
+		ContentType: aws.ToString(out.ContentType),
+		SizeBytes:   aws.ToInt64(out.ContentLength),
+	}, nil
+}
+
+// Delete removes the object stored under key. Deleting a missing object succeeds.
+func (s *s3ImageStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("bucket", s.config.Bucket).Str("key", key).Msg("failed to delete image object")
+		return fmt.Errorf("failed to delete image object %s: %w", key, err)
+	}
+	return nil
+}
+
+// URL returns BaseURL followed by the prefixed key, each of its segments
+// escaped.
+func (s *s3ImageStore) URL(key string) string {
+	segments := strings.Split(s.config.Prefix+key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.config.BaseURL + strings.Join(segments, "/")
+}
@@ -0,0 +1,90 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore returns a store for the images bucket served by endpoint.
+func testStore(t *testing.T, endpoint string, cfg S3ImageStoreConfig) *s3ImageStore {
+	t.Helper()
+	client := s3.New(s3.Options{
+		Region:       "ap-southeast-2",
+		BaseEndpoint: aws.String(endpoint),
+		UsePathStyle: true,
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+		}),
+	})
+	cfg.Bucket, cfg.Region = "images", "ap-southeast-2"
+	return newS3ImageStore(client, cfg, zerolog.Nop())
+}
+
+func TestS3ImageStore_PresignUpload(t *testing.T) {
+	store := testStore(t, "https://s3.example.com", S3ImageStoreConfig{Prefix: "products/", UploadExpiry: 15 * time.Minute})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	upload, err := store.PresignUpload(context.Background(), "P001/photo.jpg", "image/jpeg", 2048)
+	require.NoError(t, err)
+
+	assert.Equal(t, "P001/photo.jpg", upload.Key)
+	assert.Equal(t, http.MethodPut, upload.Method)
+	assert.Equal(t, now.Add(15*time.Minute), upload.ExpiresAt)
+	assert.Equal(t, "image/jpeg", upload.Headers["Content-Type"])
+	assert.Equal(t, "2048", upload.Headers["Content-Length"])
+	assert.NotContains(t, upload.Headers, "Host")
+
+	u, err := url.Parse(upload.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/images/products/P001/photo.jpg", u.Path)
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.Contains(t, u.Query().Get("X-Amz-SignedHeaders"), "content-type")
+}
+
+func TestS3ImageStore_Stat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		if r.URL.Path != "/images/products/P001/photo.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Length", "2048")
+	}))
+	defer server.Close()
+
+	store := testStore(t, server.URL, S3ImageStoreConfig{Prefix: "products/"})
+
+	object, err := store.Stat(context.Background(), "P001/photo.jpg")
+	require.NoError(t, err)
+	require.NotNil(t, object)
+	assert.Equal(t, "image/jpeg", object.ContentType)
+	assert.Equal(t, int64(2048), object.SizeBytes)
+
+	object, err = store.Stat(context.Background(), "P001/missing.jpg")
+	require.NoError(t, err)
+	assert.Nil(t, object)
+}
+
+func TestS3ImageStore_URL(t *testing.T) {
+	t.Run("Bucket URL", func(t *testing.T) {
+		store := testStore(t, "https://s3.example.com", S3ImageStoreConfig{Prefix: "products/"})
+		assert.Equal(t, "https://images.s3.ap-southeast-2.amazonaws.com/products/P%20001/photo.jpg", store.URL("P 001/photo.jpg"))
+	})
+
+	t.Run("CDN URL", func(t *testing.T) {
+		store := testStore(t, "https://s3.example.com", S3ImageStoreConfig{BaseURL: "https://cdn.example.com/media"})
+		assert.Equal(t, "https://cdn.example.com/media/P001/photo.jpg", store.URL("P001/photo.jpg"))
+	})
+}
@@ -0,0 +1,203 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Standard cache metrics, labelled with the cache's name. Every cache counts
+// lookups it answered itself (hits), lookups it passed on to the slower
+// source it caches (misses), and entries it removed before they would have
+// expired, e.g. because their source changed or the cache was full
+// (evictions).
+const (
+	MetricCacheHits      = "cache_hits_total"
+	MetricCacheMisses    = "cache_misses_total"
+	MetricCacheEvictions = "cache_evictions_total"
+)
+
+// CacheCounters are the standard counters of a cache.
+type CacheCounters struct {
+	Hits      *Counter
+	Misses    *Counter
+	Evictions *Counter
+}
+
+// Cache returns the standard counters of the named cache, registering them
+// so the cache is reported before its first lookup. A nil registry returns
+// counters that are not reported anywhere.
+func (r *Registry) Cache(name string) CacheCounters {
+	if r == nil {
+		return CacheCounters{Hits: &Counter{}, Misses: &Counter{}, Evictions: &Counter{}}
+	}
+	return CacheCounters{
+		Hits:      r.Counter(MetricCacheHits, "cache", name),
+		Misses:    r.Counter(MetricCacheMisses, "cache", name),
+		Evictions: r.Counter(MetricCacheEvictions, "cache", name),
+	}
+}
+
+// CacheCounts are a cache's hits, misses and evictions over some period.
+type CacheCounts struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+
+	// HitRate is the share of lookups that were hits, from 0 to 1. It is 0
+	// when there were no lookups.
+	HitRate float64 `json:"hitRate"`
+}
+
+// newCacheCounts returns counts with their hit rate.
+func newCacheCounts(hits, misses, evictions int64) CacheCounts {
+	counts := CacheCounts{Hits: hits, Misses: misses, Evictions: evictions}
+	if lookups := hits + misses; lookups > 0 {
+		counts.HitRate = float64(hits) / float64(lookups)
+	}
+	return counts
+}
+
+// CacheStats reports a cache's counts since the process started and over
+// the last reporting interval.
+type CacheStats struct {
+	Name string `json:"name"`
+	CacheCounts
+
+	// Interval holds the counts of the last completed reporting interval,
+	// which is nil until the first one completes.
+	Interval *CacheInterval `json:"interval,omitempty"`
+}
+
+// CacheInterval is a cache's counts over one reporting interval.
+type CacheInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	CacheCounts
+}
+
+// CacheReporter reports the standard metrics of every cache in a registry.
+// On each tick of its interval it records and logs each cache's counts since
+// the previous tick, so hit rates reflect recent traffic rather than being
+// dominated by the totals since start-up.
+type CacheReporter struct {
+	registry *Registry
+	interval time.Duration
+	logger   zerolog.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	lastTick  time.Time
+	last      map[string]CacheCounts
+	intervals map[string]CacheInterval
+}
+
+// NewCacheReporter creates a reporter for the caches counted in registry,
+// reporting on each interval.
+func NewCacheReporter(registry *Registry, interval time.Duration, logger zerolog.Logger) *CacheReporter {
+	return &CacheReporter{
+		registry:  registry,
+		interval:  interval,
+		logger:    logger.With().Str("component", "cache_reporter").Logger(),
+		now:       time.Now,
+		lastTick:  time.Now(),
+		last:      make(map[string]CacheCounts),
+		intervals: make(map[string]CacheInterval),
+	}
+}
+
+// Run reports on every interval until ctx is done.
+func (r *CacheReporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// tick closes the current reporting interval, recording and logging each
+// cache's counts over it.
+func (r *CacheReporter) tick() {
+	totals := r.totals()
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, total := range totals {
+		previous := r.last[name]
+		counts := newCacheCounts(total.Hits-previous.Hits, total.Misses-previous.Misses, total.Evictions-previous.Evictions)
+		r.intervals[name] = CacheInterval{Start: r.lastTick, End: now, CacheCounts: counts}
+		r.last[name] = total
+
+		r.logger.Info().
+			Str("cache", name).
+			Int64("hits", counts.Hits).
+			Int64("misses", counts.Misses).
+			Int64("evictions", counts.Evictions).
+			Float64("hit_rate", counts.HitRate).
+			Dur("interval", now.Sub(r.lastTick)).
+			Msg("cache stats")
+	}
+	r.lastTick = now
+}
+
+// Stats returns every cache's counts, ordered by name.
+func (r *CacheReporter) Stats() []CacheStats {
+	totals := r.totals()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]CacheStats, 0, len(totals))
+	for name, total := range totals {
+		s := CacheStats{Name: name, CacheCounts: total}
+		if interval, ok := r.intervals[name]; ok {
+			s.Interval = &interval
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// totals returns each cache's counts since the process started.
+func (r *CacheReporter) totals() map[string]CacheCounts {
+	raw := make(map[string]*[3]int64)
+	for _, sample := range r.registry.Snapshot() {
+		var i int
+		switch sample.Name {
+		case MetricCacheHits:
+			i = 0
+		case MetricCacheMisses:
+			i = 1
+		case MetricCacheEvictions:
+			i = 2
+		default:
+			continue
+		}
+
+		name := sample.Labels["cache"]
+		if raw[name] == nil {
+			raw[name] = &[3]int64{}
+		}
+		raw[name][i] = sample.Value
+	}
+
+	totals := make(map[string]CacheCounts, len(raw))
+	for name, values := range raw {
+		totals[name] = newCacheCounts(values[0], values[1], values[2])
+	}
+	return totals
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Cache(t *testing.T) {
+	r := NewRegistry()
+	product := r.Cache("product")
+	assert.Same(t, product.Hits, r.Counter(MetricCacheHits, "cache", "product"))
+
+	// Counters are reported before they are used
+	require.Len(t, r.Snapshot(), 3)
+
+	var unregistered *Registry
+	unregistered.Cache("product").Hits.Inc()
+}
+
+func TestCacheReporter(t *testing.T) {
+	r := NewRegistry()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	reporter := NewCacheReporter(r, time.Minute, zerolog.Nop())
+	reporter.now = func() time.Time { return now }
+	reporter.lastTick = start
+
+	product, files := r.Cache("product"), r.Cache("coupon_file")
+	product.Hits.Add(3)
+	product.Misses.Inc()
+	files.Misses.Inc()
+	files.Evictions.Inc()
+
+	stats := reporter.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, CacheStats{Name: "coupon_file", CacheCounts: CacheCounts{Misses: 1, Evictions: 1}}, stats[0])
+	assert.Equal(t, CacheStats{Name: "product", CacheCounts: CacheCounts{Hits: 3, Misses: 1, HitRate: 0.75}}, stats[1])
+
+	now = start.Add(time.Minute)
+	reporter.tick()
+	product.Misses.Add(4)
+	now = start.Add(2 * time.Minute)
+	reporter.tick()
+
+	stats = reporter.Stats()
+	assert.Equal(t, CacheCounts{Hits: 3, Misses: 5, HitRate: 0.375}, stats[1].CacheCounts)
+	assert.Equal(t, &CacheInterval{
+		Start:       start.Add(time.Minute),
+		End:         start.Add(2 * time.Minute),
+		CacheCounts: CacheCounts{Misses: 4},
+	}, stats[1].Interval, "the interval only counts lookups since the previous tick")
+	assert.Equal(t, CacheCounts{}, stats[0].Interval.CacheCounts)
+}
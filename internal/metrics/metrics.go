@@ -0,0 +1,144 @@
+// Package metrics provides lightweight in-process counters for operational
+// reporting. Counters are identified by a name and an optional set of labels
+// and are exposed as a snapshot through the admin API.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a monotonically increasing value that is safe for concurrent use.
+type Counter struct {
+	value atomic.Int64
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increments the counter by n. Negative values are ignored.
+func (c *Counter) Add(n int64) {
+	if n > 0 {
+		c.value.Add(n)
+	}
+}
+
+// Value returns the current counter value.
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+// Sample is a point-in-time reading of a counter.
+type Sample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  int64             `json:"value"`
+}
+
+// entry is a registered counter with its identity.
+type entry struct {
+	name    string
+	labels  map[string]string
+	counter *Counter
+}
+
+// Registry holds named counters. The zero value is not usable; create
+// registries with NewRegistry.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// NewRegistry creates an empty metrics registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Counter returns the counter with the given name and labels, creating it on
+// first use. Labels are given as alternating key/value pairs; a trailing key
+// without a value is ignored.
+func (r *Registry) Counter(name string, labels ...string) *Counter {
+	labelMap := make(map[string]string, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		labelMap[labels[i]] = labels[i+1]
+	}
+	key := seriesKey(name, labelMap)
+
+	r.mu.RLock()
+	e, ok := r.entries[key]
+	r.mu.RUnlock()
+	if ok {
+		return e.counter
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[key]; ok {
+		return e.counter
+	}
+
+	e = &entry{name: name, labels: labelMap, counter: &Counter{}}
+	r.entries[key] = e
+	return e.counter
+}
+
+// Snapshot returns the current value of every counter, ordered by name and labels.
+func (r *Registry) Snapshot() []Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.entries))
+	for key := range r.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		e := r.entries[key]
+		sample := Sample{Name: e.name, Value: e.counter.Value()}
+		if len(e.labels) > 0 {
+			sample.Labels = make(map[string]string, len(e.labels))
+			for k, v := range e.labels {
+				sample.Labels[k] = v
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+// seriesKey builds a stable identifier for a counter from its name and labels.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Counter(t *testing.T) {
+	r := NewRegistry()
+
+	a := r.Counter("requests_total", "route", "/api/", "method", "GET")
+	b := r.Counter("requests_total", "method", "GET", "route", "/api/")
+	assert.Same(t, a, b, "label order must not create a new series")
+
+	a.Inc()
+	b.Add(2)
+	b.Add(-5)
+	assert.Equal(t, int64(3), a.Value())
+
+	other := r.Counter("requests_total", "route", "/api/v1/", "method", "GET")
+	assert.NotSame(t, a, other)
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("b_total").Inc()
+	r.Counter("a_total", "route", "/api/").Add(4)
+
+	samples := r.Snapshot()
+	require.Len(t, samples, 2)
+	assert.Equal(t, "a_total", samples[0].Name)
+	assert.Equal(t, map[string]string{"route": "/api/"}, samples[0].Labels)
+	assert.Equal(t, int64(4), samples[0].Value)
+	assert.Equal(t, "b_total", samples[1].Name)
+	assert.Nil(t, samples[1].Labels)
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Counter("hits_total", "cache", "product").Inc()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(50), r.Counter("hits_total", "cache", "product").Value())
+}
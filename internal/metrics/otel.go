@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// serviceName identifies the API in exported OpenTelemetry metrics, unless
+// overridden by OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES.
+const serviceName = "mini-kart"
+
+// NewOTLPMeterProvider creates an OpenTelemetry meter provider that exports
+// metrics every interval to the OTLP/HTTP collector configured by the
+// standard OTEL_EXPORTER_OTLP_* environment variables, which also set its
+// headers, TLS and compression. Shut the provider down to export the metrics
+// recorded since the last export.
+func NewOTLPMeterProvider(ctx context.Context, interval time.Duration) (*sdkmetric.MeterProvider, error) {
+	exporter, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe metrics resource: %w", err)
+	}
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+	), nil
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOTLPMeterProvider(t *testing.T) {
+	var exports atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/metrics" {
+			exports.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+
+	ctx := context.Background()
+	provider, err := NewOTLPMeterProvider(ctx, time.Hour)
+	require.NoError(t, err)
+
+	counter, err := provider.Meter("test").Int64Counter("test.loads")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	// Shutting down exports what was recorded since the last export
+	require.NoError(t, provider.Shutdown(ctx))
+	assert.Equal(t, int32(1), exports.Load())
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// AdminScope restricts the admin route group to callers authenticated with an
+// admin-scoped key, so a leaked client API_KEY, tenant key or client
+// certificate cannot reach high-privilege operations. isAdminRoute reports
+// whether a request belongs to the group; other callers of those routes are
+// rejected with 403 Forbidden. Requests outside the group pass through.
+func AdminScope(isAdminRoute func(*http.Request) bool, logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAdminRoute(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, ok := IdentityFromContext(r.Context())
+			if !ok || identity.Method != AuthMethodAdminKey {
+				logger.Warn().
+					Str("subject", identity.Subject).
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Msg("admin route requested without an admin key")
+				http.Error(w, "forbidden: admin key required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminScope(t *testing.T) {
+	logger := zerolog.Nop()
+	isAdminRoute := func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, "/api/admin/")
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		identity       *Identity
+		expectedStatus int
+	}{
+		{
+			name:           "Admin key reaches admin route",
+			path:           "/api/admin/config",
+			identity:       &Identity{Subject: "admin:ops", Method: AuthMethodAdminKey},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Impersonated customer cannot reach admin route",
+			path:           "/api/admin/orders",
+			identity:       &Identity{Subject: "customer-42", Method: AuthMethodImpersonated, Actor: "admin:support"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key cannot reach admin route",
+			path:           "/api/admin/config",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Tenant key cannot reach admin route",
+			path:           "/api/admin/config",
+			identity:       &Identity{Subject: "tenant:acme", Method: AuthMethodTenantKey, Tenant: "acme"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Client certificate cannot reach admin route",
+			path:           "/api/admin/config",
+			identity:       &Identity{Subject: "spiffe://cluster/ns/web", Method: AuthMethodMTLS},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Anonymous caller cannot reach admin route",
+			path:           "/api/admin/config",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key reaches client route",
+			path:           "/api/orders",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.identity != nil {
+				req = req.WithContext(WithIdentity(req.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+
+			AdminScope(isAdminRoute, logger)(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
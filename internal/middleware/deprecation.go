@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+)
+
+// MetricDeprecatedRequests counts requests served by deprecated routes.
+const MetricDeprecatedRequests = "deprecated_requests_total"
+
+// DeprecationPolicy describes when a route was deprecated and when it will be removed.
+type DeprecationPolicy struct {
+	// Deprecated is when the route was deprecated. Required.
+	Deprecated time.Time
+
+	// Sunset is when the route will stop responding. Zero means not yet scheduled.
+	Sunset time.Time
+
+	// Successor is the path prefix that replaces the deprecated prefix, e.g. "/api/v1/".
+	Successor string
+
+	// Link is an optional URL documenting the deprecation and migration steps.
+	Link string
+}
+
+// Route is a path prefix registered in a RouteRegistry.
+type Route struct {
+	// Prefix is the URL path prefix the route covers, e.g. "/api/".
+	Prefix string
+
+	// Deprecation marks the route as deprecated. Nil means the route is current.
+	Deprecation *DeprecationPolicy
+}
+
+// RouteRegistry records which path prefixes are current and which are deprecated.
+// Lookups match the longest registered prefix, so a current "/api/v1/" route
+// can be carved out of a deprecated "/api/" route.
+type RouteRegistry struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRouteRegistry creates an empty route registry.
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{}
+}
+
+// Register adds a route, replacing any route with the same prefix.
+func (r *RouteRegistry) Register(route Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.routes {
+		if r.routes[i].Prefix == route.Prefix {
+			r.routes[i] = route
+			return
+		}
+	}
+
+	r.routes = append(r.routes, route)
+	sort.Slice(r.routes, func(i, j int) bool {
+		return len(r.routes[i].Prefix) > len(r.routes[j].Prefix)
+	})
+}
+
+// Lookup returns the route with the longest prefix matching the path.
+func (r *RouteRegistry) Lookup(path string) (Route, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, route := range r.routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route, true
+		}
+	}
+
+	return Route{}, false
+}
+
+// Routes returns the registered routes, longest prefix first.
+func (r *RouteRegistry) Routes() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]Route, len(r.routes))
+	copy(routes, r.routes)
+	return routes
+}
+
+// Deprecation attaches Deprecation, Sunset and Link headers to responses from
+// deprecated routes and counts their usage per endpoint, so callers can be
+// migrated before the routes are retired.
+func Deprecation(registry *RouteRegistry, counters *metrics.Registry, logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, ok := registry.Lookup(r.URL.Path)
+			if !ok || route.Deprecation == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			policy := route.Deprecation
+			header := w.Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", policy.Deprecated.Unix()))
+			if !policy.Sunset.IsZero() {
+				header.Set("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if policy.Successor != "" {
+				successor := policy.Successor + strings.TrimPrefix(r.URL.Path, route.Prefix)
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			}
+			if policy.Link != "" {
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, policy.Link))
+			}
+			header.Add("Access-Control-Expose-Headers", "Deprecation, Sunset, Link")
+
+			endpoint := deprecatedEndpoint(r.URL.Path, route.Prefix)
+			counters.Counter(MetricDeprecatedRequests, "route", route.Prefix, "endpoint", endpoint, "method", r.Method).Inc()
+
+			logger.Debug().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Str("endpoint", endpoint).
+				Msg("deprecated route requested")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// deprecatedEndpoint reduces a path to the route prefix and its first segment,
+// e.g. "/api/orders/{id}" becomes "/api/orders", keeping metric cardinality
+// independent of resource IDs.
+func deprecatedEndpoint(path, prefix string) string {
+	rest := strings.TrimPrefix(path, prefix)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + rest
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteRegistry_Lookup(t *testing.T) {
+	registry := NewRouteRegistry()
+	registry.Register(Route{Prefix: "/api/", Deprecation: &DeprecationPolicy{Deprecated: time.Now()}})
+	registry.Register(Route{Prefix: "/api/v1/"})
+
+	route, ok := registry.Lookup("/api/v1/orders")
+	require.True(t, ok)
+	assert.Equal(t, "/api/v1/", route.Prefix)
+	assert.Nil(t, route.Deprecation)
+
+	route, ok = registry.Lookup("/api/orders")
+	require.True(t, ok)
+	assert.Equal(t, "/api/", route.Prefix)
+	assert.NotNil(t, route.Deprecation)
+
+	_, ok = registry.Lookup("/health")
+	assert.False(t, ok)
+
+	assert.Len(t, registry.Routes(), 2)
+}
+
+func TestDeprecation(t *testing.T) {
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	registry := NewRouteRegistry()
+	registry.Register(Route{
+		Prefix: "/api/",
+		Deprecation: &DeprecationPolicy{
+			Deprecated: deprecated,
+			Sunset:     sunset,
+			Successor:  "/api/v1/",
+			Link:       "https://docs.example.com/api-versioning",
+		},
+	})
+	registry.Register(Route{Prefix: "/api/v1/"})
+
+	tests := []struct {
+		name             string
+		path             string
+		expectDeprecated bool
+		expectedEndpoint string
+		expectedLinks    []string
+	}{
+		{
+			name:             "Unversioned route is deprecated",
+			path:             "/api/orders/550e8400-e29b-41d4-a716-446655440000",
+			expectDeprecated: true,
+			expectedEndpoint: "/api/orders",
+			expectedLinks: []string{
+				`</api/v1/orders/550e8400-e29b-41d4-a716-446655440000>; rel="successor-version"`,
+				`<https://docs.example.com/api-versioning>; rel="deprecation"; type="text/html"`,
+			},
+		},
+		{
+			name: "Versioned route is current",
+			path: "/api/v1/orders",
+		},
+		{
+			name: "Unregistered route",
+			path: "/health",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counters := metrics.NewRegistry()
+			handlerCalled := false
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := Deprecation(registry, counters, zerolog.Nop())(testHandler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.True(t, handlerCalled)
+			if !tt.expectDeprecated {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+				assert.Empty(t, w.Header().Get("Sunset"))
+				assert.Empty(t, counters.Snapshot())
+				return
+			}
+
+			assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+			assert.Equal(t, "Thu, 31 Dec 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+			assert.Equal(t, tt.expectedLinks, w.Header().Values("Link"))
+
+			samples := counters.Snapshot()
+			require.Len(t, samples, 1)
+			assert.Equal(t, MetricDeprecatedRequests, samples[0].Name)
+			assert.Equal(t, tt.expectedEndpoint, samples[0].Labels["endpoint"])
+			assert.Equal(t, int64(1), samples[0].Value)
+		})
+	}
+}
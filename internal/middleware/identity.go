@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// Authentication methods recorded on an Identity.
+const (
+	AuthMethodAPIKey    = "api_key"
+	AuthMethodAdminKey  = "admin_key"
+	AuthMethodTenantKey = "tenant_key"
+	AuthMethodMTLS      = "mtls"
+
+	// AuthMethodImpersonated marks a customer identity assumed by an admin
+	// through the X-On-Behalf-Of header. It grants only what the customer
+	// could do; the admin is recorded as the identity's Actor.
+	AuthMethodImpersonated = "impersonated"
+)
+
+// OnBehalfOfHeader names the customer an admin-scoped key is acting for.
+const OnBehalfOfHeader = "X-On-Behalf-Of"
+
+// Identity represents the authenticated caller of a request.
+type Identity struct {
+	// Subject identifies the caller, e.g. a client certificate's SPIFFE ID or common name.
+	Subject string
+
+	// Method is the authentication method that established the identity.
+	Method string
+
+	// Actor is the admin acting on behalf of Subject through the
+	// X-On-Behalf-Of header. It is empty when callers act for themselves.
+	Actor string
+
+	// Tenant is the tenant a tenant-scoped key belongs to. It is empty for
+	// callers not tied to a tenant.
+	Tenant string
+}
+
+// identityKey is the context key for the request identity.
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the given identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity stored in ctx, if any.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// ClientCertIdentity extracts the caller identity from a verified TLS client
+// certificate and stores it in the request context. Requests without a
+// verified certificate pass through unchanged.
+func ClientCertIdentity(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			leaf := r.TLS.VerifiedChains[0][0]
+
+			// Prefer a URI SAN (e.g. spiffe://cluster/ns/svc) over the common name
+			subject := leaf.Subject.CommonName
+			if len(leaf.URIs) > 0 {
+				subject = leaf.URIs[0].String()
+			}
+
+			if subject == "" {
+				logger.Warn().Str("path", r.URL.Path).Msg("client certificate has no usable identity")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := WithIdentity(r.Context(), Identity{
+				Subject: subject,
+				Method:  AuthMethodMTLS,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OnBehalfOf lets callers authenticated with an admin-scoped key act on behalf
+// of the customer named in the X-On-Behalf-Of header, so support tools use the
+// same API paths as customers. The request's identity becomes the customer's,
+// authenticated as AuthMethodImpersonated with the admin recorded as its
+// Actor, so admin-only handlers reject it. Other callers sending the header
+// are rejected with 403 Forbidden.
+func OnBehalfOf(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			customer := r.Header.Get(OnBehalfOfHeader)
+			if customer == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, ok := IdentityFromContext(r.Context())
+			if !ok || identity.Method != AuthMethodAdminKey {
+				logger.Warn().
+					Str("subject", identity.Subject).
+					Str("path", r.URL.Path).
+					Msg("on-behalf-of request without an admin key")
+				http.Error(w, "forbidden: only admin keys may act on behalf of customers", http.StatusForbidden)
+				return
+			}
+
+			// Customer identifiers follow the same rules as request IDs
+			if !validRequestID(customer) {
+				http.Error(w, "invalid "+OnBehalfOfHeader+" header", http.StatusBadRequest)
+				return
+			}
+
+			logger.Info().
+				Str("actor", identity.Subject).
+				Str("on_behalf_of", customer).
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Msg("admin acting on behalf of customer")
+
+			ctx := WithIdentity(r.Context(), Identity{
+				Subject: customer,
+				Method:  AuthMethodImpersonated,
+				Actor:   identity.Subject,
+				Tenant:  identity.Tenant,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
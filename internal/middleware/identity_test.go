@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertIdentity(t *testing.T) {
+	logger := zerolog.Nop()
+
+	spiffeID, err := url.Parse("spiffe://cluster.local/ns/payments/sa/checkout")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		tlsState        *tls.ConnectionState
+		expectIdentity  bool
+		expectedSubject string
+	}{
+		{
+			name:           "Plain HTTP request",
+			tlsState:       nil,
+			expectIdentity: false,
+		},
+		{
+			name:           "TLS without client certificate",
+			tlsState:       &tls.ConnectionState{},
+			expectIdentity: false,
+		},
+		{
+			name: "Client certificate with common name",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+				{Subject: pkix.Name{CommonName: "inventory-service"}},
+			}}},
+			expectIdentity:  true,
+			expectedSubject: "inventory-service",
+		},
+		{
+			name: "Client certificate with URI SAN preferred",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+				{Subject: pkix.Name{CommonName: "checkout"}, URIs: []*url.URL{spiffeID}},
+			}}},
+			expectIdentity:  true,
+			expectedSubject: "spiffe://cluster.local/ns/payments/sa/checkout",
+		},
+		{
+			name: "Client certificate without identity",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+				{Subject: pkix.Name{}},
+			}}},
+			expectIdentity: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var identity Identity
+			var found bool
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity, found = IdentityFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			req.TLS = tt.tlsState
+			w := httptest.NewRecorder()
+
+			ClientCertIdentity(logger)(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectIdentity, found)
+			if tt.expectIdentity {
+				assert.Equal(t, tt.expectedSubject, identity.Subject)
+				assert.Equal(t, AuthMethodMTLS, identity.Method)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_IdentityHandling(t *testing.T) {
+	logger := zerolog.Nop()
+
+	t.Run("mTLS identity bypasses API key", func(t *testing.T) {
+		handlerCalled := false
+		testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req = req.WithContext(WithIdentity(req.Context(), Identity{Subject: "svc", Method: AuthMethodMTLS}))
+		w := httptest.NewRecorder()
+
+		APIKeyAuth("secret", nil, nil, logger)(testHandler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, handlerCalled)
+	})
+
+	t.Run("Valid API key sets identity", func(t *testing.T) {
+		var identity Identity
+		testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ = IdentityFromContext(r.Context())
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.Header.Set("X-API-Key", "secret")
+		w := httptest.NewRecorder()
+
+		APIKeyAuth("secret", nil, nil, logger)(testHandler).ServeHTTP(w, req)
+
+		assert.Equal(t, AuthMethodAPIKey, identity.Method)
+	})
+	t.Run("Admin key sets admin identity", func(t *testing.T) {
+		var identity Identity
+		testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ = IdentityFromContext(r.Context())
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.Header.Set("X-API-Key", "admin-secret")
+		w := httptest.NewRecorder()
+
+		APIKeyAuth("secret", map[string]string{"admin-secret": "support"}, nil, logger)(testHandler).ServeHTTP(w, req)
+
+		assert.Equal(t, Identity{Subject: "admin:support", Method: AuthMethodAdminKey}, identity)
+	})
+
+	t.Run("Tenant key sets tenant identity", func(t *testing.T) {
+		var identity Identity
+		testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ = IdentityFromContext(r.Context())
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		req.Header.Set("X-API-Key", "acme-secret")
+		w := httptest.NewRecorder()
+
+		APIKeyAuth("secret", nil, map[string]string{"acme-secret": "acme"}, logger)(testHandler).ServeHTTP(w, req)
+
+		assert.Equal(t, Identity{Subject: "tenant:acme", Method: AuthMethodTenantKey, Tenant: "acme"}, identity)
+	})
+}
+
+func TestOnBehalfOf(t *testing.T) {
+	logger := zerolog.Nop()
+	admin := Identity{Subject: "admin:support", Method: AuthMethodAdminKey}
+
+	tests := []struct {
+		name             string
+		identity         *Identity
+		onBehalfOf       string
+		expectedStatus   int
+		expectedIdentity Identity
+	}{
+		{
+			name:             "Admin acts on behalf of customer",
+			identity:         &admin,
+			onBehalfOf:       "customer-42",
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: Identity{Subject: "customer-42", Method: AuthMethodImpersonated, Actor: "admin:support"},
+		},
+		{
+			name:             "Request without header keeps identity",
+			identity:         &admin,
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: admin,
+		},
+		{
+			name:           "Impersonated customer cannot act on behalf of another",
+			identity:       &Identity{Subject: "customer-42", Method: AuthMethodImpersonated, Actor: "admin:support"},
+			onBehalfOf:     "customer-7",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key cannot act on behalf of customer",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			onBehalfOf:     "customer-42",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Anonymous caller cannot act on behalf of customer",
+			onBehalfOf:     "customer-42",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Invalid customer",
+			identity:       &admin,
+			onBehalfOf:     "customer 42",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var identity Identity
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity, _ = IdentityFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/orders", nil)
+			if tt.identity != nil {
+				req = req.WithContext(WithIdentity(req.Context(), *tt.identity))
+			}
+			if tt.onBehalfOf != "" {
+				req.Header.Set(OnBehalfOfHeader, tt.onBehalfOf)
+			}
+			w := httptest.NewRecorder()
+
+			OnBehalfOf(logger)(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedIdentity, identity)
+		})
+	}
+}
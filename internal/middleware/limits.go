@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// BodyLimit caps request bodies at maxBytes, answering larger requests with
+// 413 Request Entity Too Large. Requests declaring a larger Content-Length
+// are refused before the handler runs; otherwise the handler's response is
+// replaced once it has read past the limit, whatever error it reports for
+// the cut-off body. Requests exempt reports true for, such as file imports,
+// are left to their handler's own limit.
+func BodyLimit(maxBytes int64, exempt func(r *http.Request) bool, logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || exempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			message := "request body exceeds " + strconv.FormatInt(maxBytes, 10) + " bytes"
+			if r.ContentLength > maxBytes {
+				logBodyLimit(logger, r, maxBytes)
+				// Ask the client to stop sending the rest of the body
+				w.Header().Set("Connection", "close")
+				writeError(w, r, http.StatusRequestEntityTooLarge, message)
+				return
+			}
+
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+			r.Body = body
+			next.ServeHTTP(&replacingWriter{
+				ResponseWriter: w,
+				request:        r,
+				replace: func(status int) (int, string) {
+					if !body.exceeded.Load() || status < http.StatusBadRequest {
+						return 0, ""
+					}
+					logBodyLimit(logger, r, maxBytes)
+					return http.StatusRequestEntityTooLarge, message
+				},
+			}, r)
+		})
+	}
+}
+
+// logBodyLimit logs a request refused for the size of its body.
+func logBodyLimit(logger zerolog.Logger, r *http.Request, maxBytes int64) {
+	requestID, _ := RequestIDFromContext(r.Context())
+	logger.Warn().
+		Str("request_id", requestID).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Int64("content_length", r.ContentLength).
+		Int64("max_bytes", maxBytes).
+		Msg("request body too large")
+}
+
+// limitedBody records whether a request body was read past its limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded atomic.Bool
+}
+
+// Read reads from the body, recording when the limit is reached.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded.Store(true)
+	}
+	return n, err
+}
+
+// Timeout bounds each request's context to timeout, answering requests whose
+// deadline passed with 504 Gateway Timeout. Handlers are not interrupted:
+// database queries and outbound calls made with the request context fail
+// once the deadline passes, and the handler's error response, or a missing
+// one, is replaced. Successful responses are kept, even when they finish
+// after the deadline, since the work they report was done. Requests exempt
+// reports true for, such as file imports, are not bounded.
+func Timeout(timeout time.Duration, exempt func(r *http.Request) bool, logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			rw := &replacingWriter{
+				ResponseWriter: w,
+				request:        r,
+				replace: func(status int) (int, string) {
+					if status < http.StatusInternalServerError || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+						return 0, ""
+					}
+					requestID, _ := RequestIDFromContext(ctx)
+					logger.Warn().
+						Str("request_id", requestID).
+						Str("method", r.Method).
+						Str("path", r.URL.Path).
+						Dur("timeout", timeout).
+						Msg("request timed out")
+					return http.StatusGatewayTimeout, "request did not complete within " + timeout.String()
+				},
+			}
+
+			next.ServeHTTP(rw, r)
+
+			if !rw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				rw.WriteHeader(http.StatusGatewayTimeout)
+			}
+		})
+	}
+}
+
+// replacingWriter replaces the handler's response with an error response
+// when replace returns a status for the status the handler writes. The
+// handler's body is then discarded.
+type replacingWriter struct {
+	http.ResponseWriter
+	request     *http.Request
+	replace     func(status int) (int, string)
+	wroteHeader bool
+	replaced    bool
+}
+
+// WriteHeader writes the handler's status, or the replacing error response.
+func (w *replacingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if code, message := w.replace(status); code != 0 {
+		w.replaced = true
+		writeError(w.ResponseWriter, w.request, code, message)
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the handler's body, unless its response was replaced.
+func (w *replacingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the client's writer to http.ResponseController.
+func (w *replacingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writeError writes a JSON error response in the handlers' error format,
+// carrying the request's correlation ID.
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	requestID, _ := RequestIDFromContext(r.Context())
+
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error         string `json:"error"`
+		CorrelationID string `json:"correlationId,omitempty"`
+	}{Error: message, CorrelationID: requestID})
+}
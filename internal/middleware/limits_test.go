@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isImport exempts the test import route from request limits.
+func isImport(r *http.Request) bool {
+	return r.URL.Path == "/api/import"
+}
+
+func TestBodyLimit(t *testing.T) {
+	// decode answers like the handlers, with 400 for a body it cannot read
+	decode := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	})
+
+	large := `{"name": "` + strings.Repeat("x", 64) + `"}`
+
+	tests := []struct {
+		name           string
+		path           string
+		body           io.Reader
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Within the limit",
+			path:           "/api/orders",
+			body:           strings.NewReader(`{"name": "x"}`),
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Declared length over the limit",
+			path:           "/api/orders",
+			body:           strings.NewReader(large),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "request body exceeds 32 bytes",
+		},
+		{
+			name: "Streamed body over the limit",
+			path: "/api/orders",
+			// Hides the length, as a chunked request would
+			body:           io.MultiReader(strings.NewReader(large)),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "request body exceeds 32 bytes",
+		},
+		{
+			name:           "Invalid body within the limit",
+			path:           "/api/orders",
+			body:           strings.NewReader(`{`),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Exempt route",
+			path:           "/api/import",
+			body:           strings.NewReader(large),
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequestID(zerolog.Nop())(BodyLimit(32, isImport, zerolog.Nop())(decode))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, tt.body)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				var body struct {
+					Error         string `json:"error"`
+					CorrelationID string `json:"correlationId"`
+				}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body.Error)
+				assert.Equal(t, w.Header().Get(RequestIDHeader), body.CorrelationID)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	// wait answers after delay, failing like the handlers when its context
+	// is done first
+	wait := func(delay time.Duration, respond bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+				if respond {
+					w.Write([]byte("done"))
+				}
+			case <-r.Context().Done():
+				if respond {
+					http.Error(w, "failed to create order", http.StatusInternalServerError)
+				}
+			}
+		})
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		handler        http.Handler
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Completes in time",
+			path:           "/api/orders",
+			handler:        wait(0, true),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "done",
+		},
+		{
+			name:           "Handler fails at the deadline",
+			path:           "/api/orders",
+			handler:        wait(time.Second, true),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   `{"error":"request did not complete within 20ms"}`,
+		},
+		{
+			name:           "Handler returns without a response",
+			path:           "/api/orders",
+			handler:        wait(time.Second, false),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   `{"error":"request did not complete within 20ms"}`,
+		},
+		{
+			name: "Success after the deadline is kept",
+			path: "/api/orders",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				w.WriteHeader(http.StatusCreated)
+			}),
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Exempt route",
+			path:           "/api/import",
+			handler:        wait(50*time.Millisecond, true),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Timeout(20*time.Millisecond, isImport, zerolog.Nop())(tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedBody, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -11,8 +12,9 @@ import (
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, Idempotency-Key, X-Request-ID, X-On-Behalf-Of, X-Tenant-ID")
+		w.Header().Add("Access-Control-Expose-Headers", RequestIDHeader)
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -24,12 +26,24 @@ func CORS(next http.Handler) http.Handler {
 	})
 }
 
-// APIKeyAuth validates the API key from the X-API-Key header.
-func APIKeyAuth(apiKey string, logger zerolog.Logger) func(http.Handler) http.Handler {
+// APIKeyAuth validates the API key from the X-API-Key header. adminKeys maps
+// admin-scoped keys to the name of the admin they belong to; callers using
+// one are identified as "admin:<name>" and may act on behalf of customers.
+// tenantKeys maps tenant-scoped keys to the tenant they belong to; callers
+// using one are identified as "tenant:<id>" and only see that tenant's data.
+func APIKeyAuth(apiKey string, adminKeys, tenantKeys map[string]string, logger zerolog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip authentication for health check endpoint
-			if r.URL.Path == "/health" {
+			// Skip authentication for health and readiness probes, the admin
+			// dashboard's static page, which authenticates to the API itself,
+			// the anonymous public catalogue and the API documentation
+			if r.URL.Path == "/health" || r.URL.Path == "/ready" || isAdminAsset(r.URL.Path) || isPublic(r.URL.Path) || isDocs(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Callers already authenticated by a verified client certificate
+			if identity, ok := IdentityFromContext(r.Context()); ok && identity.Method == AuthMethodMTLS {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -41,7 +55,12 @@ func APIKeyAuth(apiKey string, logger zerolog.Logger) func(http.Handler) http.Ha
 				return
 			}
 
-			if providedKey != apiKey {
+			identity := Identity{Subject: "api-key", Method: AuthMethodAPIKey}
+			if admin, ok := adminKeys[providedKey]; ok {
+				identity = Identity{Subject: "admin:" + admin, Method: AuthMethodAdminKey}
+			} else if tenant, ok := tenantKeys[providedKey]; ok {
+				identity = Identity{Subject: "tenant:" + tenant, Method: AuthMethodTenantKey, Tenant: tenant}
+			} else if providedKey != apiKey {
 				logger.Warn().
 					Str("path", r.URL.Path).
 					Str("provided_key", providedKey[:min(8, len(providedKey))]).
@@ -50,11 +69,26 @@ func APIKeyAuth(apiKey string, logger zerolog.Logger) func(http.Handler) http.Ha
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
 		})
 	}
 }
 
+// isAdminAsset reports whether path is part of the admin dashboard's static page.
+func isAdminAsset(path string) bool {
+	return path == "/admin" || strings.HasPrefix(path, "/admin/")
+}
+
+// isDocs reports whether path is the OpenAPI document or its Swagger UI page.
+func isDocs(path string) bool {
+	return path == "/openapi.json" || path == "/docs"
+}
+
+// isPublic reports whether path is part of the anonymous public catalogue.
+func isPublic(path string) bool {
+	return strings.HasPrefix(path, "/public/")
+}
+
 // Logging logs HTTP requests with timing information.
 func Logging(logger zerolog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -67,7 +101,9 @@ func Logging(logger zerolog.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(rw, r)
 
 			duration := time.Since(start)
+			requestID, _ := RequestIDFromContext(r.Context())
 			logger.Info().
+				Str("request_id", requestID).
 				Str("method", r.Method).
 				Str("path", r.URL.Path).
 				Int("status", rw.statusCode).
@@ -84,15 +120,15 @@ func Recovery(logger zerolog.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
+					requestID, _ := RequestIDFromContext(r.Context())
 					logger.Error().
+						Str("request_id", requestID).
 						Interface("panic", err).
 						Str("method", r.Method).
 						Str("path", r.URL.Path).
 						Msg("panic recovered")
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					w.Write([]byte(`{"error": "internal server error"}`))
+					writeError(w, r, http.StatusInternalServerError, "internal server error")
 				}
 			}()
 
@@ -54,8 +54,8 @@ func TestCORS(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			assert.Equal(t, tt.expectHandler, handlerCalled)
 			assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
-			assert.Equal(t, "GET, POST, PUT, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
-			assert.Equal(t, "Content-Type, X-API-Key", w.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "Content-Type, X-API-Key, Idempotency-Key, X-Request-ID, X-On-Behalf-Of, X-Tenant-ID", w.Header().Get("Access-Control-Allow-Headers"))
 		})
 	}
 }
@@ -85,6 +85,20 @@ func TestAPIKeyAuth(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectHandler:  false,
 		},
+		{
+			name:           "Valid admin key",
+			path:           "/api/products",
+			apiKey:         "admin-key-456",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
+		{
+			name:           "Valid tenant key",
+			path:           "/api/products",
+			apiKey:         "tenant-key-789",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
 		{
 			name:           "Missing API key",
 			path:           "/api/products",
@@ -99,6 +113,62 @@ func TestAPIKeyAuth(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectHandler:  true,
 		},
+		{
+			name:           "Readiness check bypasses auth",
+			path:           "/ready",
+			apiKey:         "",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
+		{
+			name:           "Admin dashboard page bypasses auth",
+			path:           "/admin/dashboard.js",
+			apiKey:         "",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
+		{
+			name:           "Admin dashboard data requires auth",
+			path:           "/api/admin/dashboard",
+			apiKey:         "",
+			expectedStatus: http.StatusUnauthorized,
+			expectHandler:  false,
+		},
+		{
+			name:           "Paths starting with admin require auth",
+			path:           "/administrator",
+			apiKey:         "",
+			expectedStatus: http.StatusUnauthorized,
+			expectHandler:  false,
+		},
+		{
+			name:           "Public catalogue bypasses auth",
+			path:           "/public/products",
+			apiKey:         "",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
+		{
+			name:           "Paths starting with public require auth",
+			path:           "/publicity",
+			apiKey:         "",
+			expectedStatus: http.StatusUnauthorized,
+			expectHandler:  false,
+		},
+		{
+			name:           "OpenAPI document bypasses auth",
+			path:           "/openapi.json",
+			apiKey:         "",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
+		{
+			name:           "Swagger UI bypasses auth",
+			path:           "/docs",
+			apiKey:         "",
+			expectedStatus: http.StatusOK,
+			expectHandler:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,7 +179,7 @@ func TestAPIKeyAuth(t *testing.T) {
 				w.WriteHeader(http.StatusOK)
 			})
 
-			handler := APIKeyAuth(validAPIKey, logger)(testHandler)
+			handler := APIKeyAuth(validAPIKey, map[string]string{"admin-key-456": "support"}, map[string]string{"tenant-key-789": "acme"}, logger)(testHandler)
 
 			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
 			if tt.apiKey != "" {
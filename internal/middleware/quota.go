@@ -0,0 +1,316 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/notification"
+
+	"github.com/rs/zerolog"
+)
+
+// Quotas a caller's monthly usage is counted against.
+const (
+	QuotaRequests = "requests"
+	QuotaOrders   = "orders"
+)
+
+// quotaThresholds are the percentages of a quota at which a warning is sent.
+var quotaThresholds = []int{80, 100}
+
+// quotaNotifyTimeout bounds delivering a quota warning.
+const quotaNotifyTimeout = 10 * time.Second
+
+// QuotaTracker counts each caller's requests and created orders per calendar
+// month (UTC) against monthly quotas. A quota of zero is not tracked.
+// Counts are kept in memory, so they are per instance and restart from zero
+// when the process restarts.
+type QuotaTracker struct {
+	requests int
+	orders   int
+	enforce  bool
+	now      func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*quotaUsage
+}
+
+// quotaUsage is a caller's usage in the current month.
+type quotaUsage struct {
+	month  time.Time
+	counts map[string]int
+	warned map[string]int // highest threshold warned about, per quota
+}
+
+// QuotaWarning reports that a caller's usage reached a threshold of a quota.
+type QuotaWarning struct {
+	Client    string
+	Quota     string // QuotaRequests or QuotaOrders
+	Used      int
+	Limit     int
+	Threshold int // Percentage of the quota reached, 80 or 100
+	Reset     time.Time
+}
+
+// NewQuotaTracker creates a tracker allowing requests requests and orders
+// created orders per caller each month. With enforce, callers over a quota
+// are rejected; otherwise quotas only produce headers and warnings.
+func NewQuotaTracker(requests, orders int, enforce bool) *QuotaTracker {
+	return &QuotaTracker{
+		requests: requests,
+		orders:   orders,
+		enforce:  enforce,
+		now:      time.Now,
+		clients:  make(map[string]*quotaUsage),
+	}
+}
+
+// limit returns the monthly limit of quota, or zero when it is not tracked.
+func (t *QuotaTracker) limit(quota string) int {
+	if quota == QuotaOrders {
+		return t.orders
+	}
+	return t.requests
+}
+
+// usage returns client's usage in the current month, starting a new month's
+// usage when the month has changed. Callers must hold t.mu.
+func (t *QuotaTracker) usage(client string) *quotaUsage {
+	now := t.now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	u, ok := t.clients[client]
+	if !ok || !u.month.Equal(month) {
+		u = &quotaUsage{month: month, counts: make(map[string]int), warned: make(map[string]int)}
+		t.clients[client] = u
+	}
+	return u
+}
+
+// Exceeded reports whether client has used up quota this month. It is
+// always false when quotas are not enforced.
+func (t *QuotaTracker) Exceeded(client, quota string) bool {
+	limit := t.limit(quota)
+	if !t.enforce || limit == 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.usage(client).counts[quota] >= limit
+}
+
+// Record counts one unit of quota for client. It returns a warning when the
+// count reaches a warning threshold for the first time this month.
+func (t *QuotaTracker) Record(client, quota string) (QuotaWarning, bool) {
+	limit := t.limit(quota)
+	if limit == 0 {
+		return QuotaWarning{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.usage(client)
+	u.counts[quota]++
+	used := u.counts[quota]
+
+	reached := 0
+	for _, threshold := range quotaThresholds {
+		if used*100 >= limit*threshold {
+			reached = threshold
+		}
+	}
+	if reached <= u.warned[quota] {
+		return QuotaWarning{}, false
+	}
+	u.warned[quota] = reached
+
+	return QuotaWarning{
+		Client:    client,
+		Quota:     quota,
+		Used:      used,
+		Limit:     limit,
+		Threshold: reached,
+		Reset:     u.month.AddDate(0, 1, 0),
+	}, true
+}
+
+// Remaining returns how much of each tracked quota client has left this
+// month, and when the quotas reset.
+func (t *QuotaTracker) Remaining(client string) (map[string]int, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.usage(client)
+	remaining := make(map[string]int, 2)
+	for _, quota := range []string{QuotaRequests, QuotaOrders} {
+		if limit := t.limit(quota); limit > 0 {
+			remaining[quota] = max(limit-u.counts[quota], 0)
+		}
+	}
+	return remaining, u.month.AddDate(0, 1, 0)
+}
+
+// Quota counts authenticated callers' requests and created orders against
+// the tracker's monthly quotas. Responses carry X-Quota-Limit,
+// X-Quota-Remaining and X-Quota-Reset headers, and notifier is sent a
+// warning when a caller reaches 80% and 100% of a quota. When quotas are
+// enforced, callers over a quota are rejected with 429 Too Many Requests.
+// Anonymous requests, such as health probes and the public catalogue, are
+// not counted.
+func Quota(tracker *QuotaTracker, notifier notification.Notifier, logger zerolog.Logger) func(http.Handler) http.Handler {
+	notify := func(warning QuotaWarning) {
+		logger.Warn().
+			Str("client", warning.Client).
+			Str("quota", warning.Quota).
+			Int("used", warning.Used).
+			Int("limit", warning.Limit).
+			Msg("monthly quota threshold reached")
+
+		// Deliver the warning without holding up the request
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), quotaNotifyTimeout)
+			defer cancel()
+			if err := notifier.Notify(ctx, quotaNotification(warning)); err != nil {
+				logger.Error().Err(err).Str("client", warning.Client).Msg("failed to send quota warning")
+			}
+		}()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := IdentityFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			client := identity.Subject
+			order := isOrderCreation(r)
+
+			for _, quota := range []string{QuotaRequests, QuotaOrders} {
+				if quota == QuotaOrders && !order {
+					continue
+				}
+				if tracker.Exceeded(client, quota) {
+					_, reset := tracker.Remaining(client)
+					writeQuotaHeaders(w, tracker, client)
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(reset.Sub(tracker.now()).Seconds()))))
+
+					logger.Warn().
+						Str("client", client).
+						Str("quota", quota).
+						Str("path", r.URL.Path).
+						Msg("monthly quota exceeded")
+					http.Error(w, fmt.Sprintf("monthly %s quota exceeded", quota), http.StatusTooManyRequests)
+					return
+				}
+			}
+
+			if warning, ok := tracker.Record(client, QuotaRequests); ok {
+				notify(warning)
+			}
+
+			// Orders are counted once created or accepted for asynchronous
+			// creation, just before the response headers are written, so the
+			// headers include them
+			qw := &quotaWriter{ResponseWriter: w, onHeader: func(status int) {
+				if order && (status == http.StatusCreated || status == http.StatusAccepted) {
+					if warning, ok := tracker.Record(client, QuotaOrders); ok {
+						notify(warning)
+					}
+				}
+				writeQuotaHeaders(w, tracker, client)
+			}}
+
+			next.ServeHTTP(qw, r)
+			if !qw.wroteHeader {
+				qw.onHeader(http.StatusOK)
+			}
+		})
+	}
+}
+
+// writeQuotaHeaders sets the X-Quota-* headers for client, listing each
+// tracked quota, e.g. "X-Quota-Remaining: requests=9500, orders=120".
+func writeQuotaHeaders(w http.ResponseWriter, tracker *QuotaTracker, client string) {
+	remaining, reset := tracker.Remaining(client)
+
+	var limits, left []string
+	for _, quota := range []string{QuotaRequests, QuotaOrders} {
+		if n, ok := remaining[quota]; ok {
+			limits = append(limits, fmt.Sprintf("%s=%d", quota, tracker.limit(quota)))
+			left = append(left, fmt.Sprintf("%s=%d", quota, n))
+		}
+	}
+
+	header := w.Header()
+	header.Set("X-Quota-Limit", strings.Join(limits, ", "))
+	header.Set("X-Quota-Remaining", strings.Join(left, ", "))
+	header.Set("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+	header.Add("Access-Control-Expose-Headers", "X-Quota-Limit, X-Quota-Remaining, X-Quota-Reset")
+}
+
+// quotaNotification describes a quota warning for admins.
+func quotaNotification(warning QuotaWarning) notification.Notification {
+	notificationType := "quota.warning"
+	message := fmt.Sprintf("%s has used %d%% of its monthly %s quota", warning.Client, warning.Threshold, warning.Quota)
+	if warning.Threshold >= 100 {
+		notificationType = "quota.exceeded"
+		message = fmt.Sprintf("%s has used its monthly %s quota", warning.Client, warning.Quota)
+	}
+
+	return notification.Notification{
+		Type:    notificationType,
+		Message: message,
+		Fields: map[string]string{
+			"client":    warning.Client,
+			"quota":     warning.Quota,
+			"used":      strconv.Itoa(warning.Used),
+			"limit":     strconv.Itoa(warning.Limit),
+			"threshold": strconv.Itoa(warning.Threshold),
+			"reset":     warning.Reset.Format(time.RFC3339),
+		},
+	}
+}
+
+// isOrderCreation reports whether r creates an order, under either the
+// unversioned or the versioned API path.
+func isOrderCreation(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	return path == "/api/orders" || path == "/api/v1/orders"
+}
+
+// quotaWriter calls onHeader just before the response headers are written.
+type quotaWriter struct {
+	http.ResponseWriter
+	onHeader    func(status int)
+	wroteHeader bool
+}
+
+// WriteHeader runs onHeader before writing the headers.
+func (w *quotaWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.onHeader(status)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the headers with 200 OK first if they have not been written.
+func (w *quotaWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
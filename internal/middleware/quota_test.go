@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-kart/internal/notification"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chanNotifier delivers notifications to a channel.
+type chanNotifier chan notification.Notification
+
+func (n chanNotifier) Notify(ctx context.Context, notification notification.Notification) error {
+	n <- notification
+	return nil
+}
+
+func TestQuotaTracker_Record(t *testing.T) {
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	tracker := NewQuotaTracker(10, 0, false)
+	tracker.now = func() time.Time { return now }
+
+	var thresholds []int
+	for range 12 {
+		if warning, ok := tracker.Record("api-key", QuotaRequests); ok {
+			thresholds = append(thresholds, warning.Threshold)
+			assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), warning.Reset)
+		}
+	}
+
+	// Each threshold is warned about once
+	assert.Equal(t, []int{80, 100}, thresholds)
+
+	remaining, _ := tracker.Remaining("api-key")
+	assert.Equal(t, map[string]int{QuotaRequests: 0}, remaining)
+
+	// Untracked quotas are not counted
+	_, ok := tracker.Record("api-key", QuotaOrders)
+	assert.False(t, ok)
+
+	// Callers are counted separately
+	remaining, _ = tracker.Remaining("spiffe://cluster/ns/checkout")
+	assert.Equal(t, 10, remaining[QuotaRequests])
+
+	// Usage restarts each month
+	now = now.AddDate(0, 0, 2)
+	remaining, reset := tracker.Remaining("api-key")
+	assert.Equal(t, 10, remaining[QuotaRequests])
+	assert.Equal(t, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), reset)
+}
+
+func TestQuota(t *testing.T) {
+	logger := zerolog.Nop()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		enforce           bool
+		expectedCreated   int
+		expectedRemaining string
+	}{
+		{
+			name:              "Soft quotas only warn",
+			expectedCreated:   6,
+			expectedRemaining: "requests=14, orders=0",
+		},
+		{
+			name:              "Hard quotas reject callers over a quota",
+			enforce:           true,
+			expectedCreated:   5,
+			expectedRemaining: "requests=15, orders=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewQuotaTracker(20, 5, tt.enforce)
+			tracker.now = func() time.Time { return now }
+			notifications := make(chanNotifier, 10)
+
+			handler := Quota(tracker, notifications, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusCreated)
+					return
+				}
+				w.Write([]byte("[]"))
+			}))
+
+			serve := func(method, path string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, path, nil)
+				req = req.WithContext(WithIdentity(req.Context(), Identity{Subject: "api-key", Method: AuthMethodAPIKey}))
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				return w
+			}
+
+			// Place one order more than the order quota allows
+			var w *httptest.ResponseRecorder
+			created := 0
+			for range 6 {
+				w = serve(http.MethodPost, "/api/v1/orders")
+				if w.Code == http.StatusCreated {
+					created++
+				}
+			}
+			assert.Equal(t, tt.expectedCreated, created)
+
+			assert.Equal(t, "requests=20, orders=5", w.Header().Get("X-Quota-Limit"))
+			assert.Equal(t, tt.expectedRemaining, w.Header().Get("X-Quota-Remaining"))
+			assert.Equal(t, "1775001600", w.Header().Get("X-Quota-Reset"))
+			if tt.enforce {
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Equal(t, "2635200", w.Header().Get("Retry-After"))
+			}
+
+			// Reaching the order quota is reported once at 80% and once at 100%
+			var types []string
+			for range 2 {
+				select {
+				case n := <-notifications:
+					require.Equal(t, "orders", n.Fields["quota"])
+					types = append(types, n.Type)
+				case <-time.After(time.Second):
+					t.Fatal("expected a quota notification")
+				}
+			}
+			assert.ElementsMatch(t, []string{"quota.warning", "quota.exceeded"}, types)
+
+			// Other requests are still allowed by the order quota
+			w = serve(http.MethodGet, "/api/products")
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestQuota_Anonymous(t *testing.T) {
+	tracker := NewQuotaTracker(1, 0, true)
+	handler := Quota(tracker, make(chanNotifier, 1), zerolog.Nop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/products", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-Quota-Remaining"))
+	}
+}
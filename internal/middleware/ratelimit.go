@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SharedCounter counts hits in fixed windows on behalf of every API replica.
+type SharedCounter interface {
+	// Incr counts a hit on the counter under key, which is reset window
+	// after its first hit, and returns its value and how long until it resets.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// RateLimiter counts requests per client in fixed time windows.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	shared SharedCounter
+	logger zerolog.Logger
+
+	mu      sync.Mutex
+	clients map[string]*rateWindow
+	swept   time.Time
+	warned  time.Time
+}
+
+// rateWindow tracks a client's requests in the current window.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates a rate limiter allowing limit requests per client
+// in each window.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		clients: make(map[string]*rateWindow),
+	}
+}
+
+// NewSharedRateLimiter creates a rate limiter whose windows are counted by
+// shared, so a client's requests count against one limit across every API
+// replica. While shared is failing, requests are counted by each replica.
+func NewSharedRateLimiter(limit int, window time.Duration, shared SharedCounter, logger zerolog.Logger) *RateLimiter {
+	l := NewRateLimiter(limit, window)
+	l.shared = shared
+	l.logger = logger.With().Str("component", "rate_limiter").Logger()
+	return l
+}
+
+// Limit returns the number of requests allowed per window.
+func (l *RateLimiter) Limit() int {
+	return l.limit
+}
+
+// Allow records a request from client. It reports whether the request is
+// within the limit, how many requests remain in the current window and when
+// the window resets.
+func (l *RateLimiter) Allow(ctx context.Context, client string) (bool, int, time.Time) {
+	if l.shared != nil {
+		count, ttl, err := l.shared.Incr(ctx, "ratelimit:"+client, l.window)
+		if err == nil {
+			reset := l.now().Add(ttl)
+			if count > int64(l.limit) {
+				return false, 0, reset
+			}
+			return true, l.limit - int(count), reset
+		}
+		l.sharedFailed(err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.clients[client] = w
+	}
+
+	reset := w.start.Add(l.window)
+	if w.count >= l.limit {
+		return false, 0, reset
+	}
+
+	w.count++
+	return true, l.limit - w.count, reset
+}
+
+// sharedFailed logs that the shared counter failed, at most once per window,
+// so an outage does not log every request.
+func (l *RateLimiter) sharedFailed(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.warned) < l.window {
+		return
+	}
+	l.warned = now
+	l.logger.Warn().Err(err).Msg("failed to count request in shared rate limit, counting per replica")
+}
+
+// sweep drops expired client windows, at most once per window, so idle
+// clients don't accumulate.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < l.window {
+		return
+	}
+	l.swept = now
+
+	for client, w := range l.clients {
+		if now.Sub(w.start) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// RateLimit rejects clients exceeding the limiter's request rate with
+// 429 Too Many Requests. Every limited response carries X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset headers so clients can pace
+// themselves before being rejected.
+func RateLimit(limiter *RateLimiter, logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Health and readiness probes are never limited
+			if r.URL.Path == "/health" || r.URL.Path == "/ready" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := rateLimitClient(r)
+			allowed, remaining, reset := limiter.Allow(r.Context(), client)
+
+			header := w.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(limiter.Limit()))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			header.Add("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset")
+
+			if !allowed {
+				retryAfter := math.Ceil(reset.Sub(limiter.now()).Seconds())
+				header.Set("Retry-After", strconv.Itoa(int(retryAfter)))
+
+				logger.Warn().
+					Str("client", client).
+					Str("path", r.URL.Path).
+					Msg("rate limit exceeded")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the caller a request is counted against:
+// the client certificate identity for mTLS callers, otherwise the remote IP.
+func rateLimitClient(r *http.Request) string {
+	if identity, ok := IdentityFromContext(r.Context()); ok && identity.Method == AuthMethodMTLS {
+		return identity.Subject
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	allowed, remaining, reset := limiter.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, now.Add(time.Minute), reset)
+
+	allowed, remaining, _ = limiter.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 0, remaining)
+
+	allowed, remaining, _ = limiter.Allow(context.Background(), "10.0.0.1")
+	assert.False(t, allowed)
+	assert.Equal(t, 0, remaining)
+
+	// Clients are counted separately
+	allowed, _, _ = limiter.Allow(context.Background(), "10.0.0.2")
+	assert.True(t, allowed)
+
+	// A new window starts once the old one has passed
+	now = now.Add(time.Minute)
+	allowed, remaining, _ = limiter.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+
+	// Expired windows of idle clients are dropped
+	assert.NotContains(t, limiter.clients, "10.0.0.2")
+}
+
+// fakeCounter is a SharedCounter whose windows never expire.
+type fakeCounter struct {
+	counts map[string]int64
+	err    error
+}
+
+func (c *fakeCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	if c.err != nil {
+		return 0, 0, c.err
+	}
+	c.counts[key]++
+	return c.counts[key], window / 2, nil
+}
+
+func TestRateLimiter_Shared(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	counter := &fakeCounter{counts: make(map[string]int64)}
+
+	// Two replicas sharing the counter enforce one limit between them
+	first := NewSharedRateLimiter(2, time.Minute, counter, zerolog.Nop())
+	second := NewSharedRateLimiter(2, time.Minute, counter, zerolog.Nop())
+	first.now = func() time.Time { return now }
+	second.now = func() time.Time { return now }
+
+	allowed, remaining, reset := first.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, now.Add(30*time.Second), reset)
+
+	allowed, remaining, _ = second.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 0, remaining)
+
+	allowed, remaining, _ = first.Allow(context.Background(), "10.0.0.1")
+	assert.False(t, allowed)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, int64(3), counter.counts["ratelimit:10.0.0.1"])
+
+	// While the counter fails, each replica counts on its own
+	counter.err = errors.New("connection refused")
+	allowed, remaining, _ = first.Allow(context.Background(), "10.0.0.1")
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestRateLimit(t *testing.T) {
+	logger := zerolog.Nop()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	limiter := NewRateLimiter(1, 30*time.Second)
+	limiter.now = func() time.Time { return now }
+
+	handler := RateLimit(limiter, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Within limit", func(t *testing.T) {
+		w := serve("/api/orders", "192.0.2.1:5000")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "1772366430", w.Header().Get("X-RateLimit-Reset"))
+	})
+
+	t.Run("Limit exceeded from another port", func(t *testing.T) {
+		w := serve("/api/orders", "192.0.2.1:5001")
+
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Health probes are not limited", func(t *testing.T) {
+		w := serve("/health", "192.0.2.1:5002")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Remaining"))
+	})
+
+	t.Run("mTLS callers are limited by identity", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		req.RemoteAddr = "192.0.2.1:5003"
+		req = req.WithContext(WithIdentity(req.Context(), Identity{Subject: "spiffe://shop/checkout", Method: AuthMethodMTLS}))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
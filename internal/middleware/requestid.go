@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// RequestIDHeader carries the request's correlation ID on requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied request IDs so they cannot bloat logs.
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key for the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID propagates the caller's X-Request-ID, or generates one when it is
+// missing or malformed. The ID is echoed in the response header, stored in the
+// request context and attached to a request-scoped logger available through
+// zerolog.Ctx.
+func RequestID(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.New().String()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+
+			ctx := WithRequestID(r.Context(), id)
+			ctx = logger.With().Str("request_id", id).Logger().WithContext(ctx)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// validRequestID reports whether a caller-supplied request ID is safe to
+// propagate: non-empty, bounded and printable ASCII without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		expectSame bool
+	}{
+		{
+			name:       "Propagates caller ID",
+			header:     "checkout-7f3a9c",
+			expectSame: true,
+		},
+		{
+			name:   "Generates ID when missing",
+			header: "",
+		},
+		{
+			name:   "Replaces ID with spaces",
+			header: "not a valid id",
+		},
+		{
+			name:   "Replaces overlong ID",
+			header: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+
+			var seenID string
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id, ok := RequestIDFromContext(r.Context())
+				require.True(t, ok)
+				seenID = id
+				zerolog.Ctx(r.Context()).Info().Msg("handled")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			RequestID(logger)(testHandler).ServeHTTP(w, req)
+
+			responseID := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, seenID, responseID)
+			if tt.expectSame {
+				assert.Equal(t, tt.header, responseID)
+			} else {
+				_, err := uuid.Parse(responseID)
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, buf.String(), `"request_id":"`+responseID+`"`)
+		})
+	}
+}
+
+func TestRecovery_IncludesCorrelationID(t *testing.T) {
+	logger := zerolog.Nop()
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something went wrong")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+
+	RequestID(logger)(Recovery(logger)(testHandler)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"internal server error","correlationId":"req-123"}`, w.Body.String())
+}
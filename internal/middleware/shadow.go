@@ -0,0 +1,433 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+)
+
+// MetricShadowRequests counts mirrored requests by endpoint and outcome:
+// "match", "mismatch", "error" when the secondary failed to respond, and
+// "dropped" when too many mirrored requests were already in flight.
+const MetricShadowRequests = "shadow_requests_total"
+
+// Shadow request outcomes.
+const (
+	shadowMatch    = "match"
+	shadowMismatch = "mismatch"
+	shadowError    = "error"
+	shadowDropped  = "dropped"
+)
+
+// ShadowConfig configures which requests a Shadower mirrors and how their
+// responses are compared.
+type ShadowConfig struct {
+	// Percent is the share of read requests mirrored, from 0 to 100.
+	Percent float64
+
+	// Timeout bounds each mirrored request.
+	Timeout time.Duration
+
+	// MaxConcurrent bounds the mirrored requests in flight. Requests
+	// arriving while it is reached are not mirrored.
+	MaxConcurrent int
+
+	// MaxBodyBytes bounds the response bodies compared. Responses with a
+	// larger body are compared by status and body prefix.
+	MaxBodyBytes int
+
+	// IgnoreFields names JSON object fields left out of comparisons at any
+	// depth, such as timestamps generated per response.
+	IgnoreFields []string
+}
+
+// Shadower mirrors read traffic to a secondary handler, such as a new
+// implementation being migrated to, and reports where its responses differ
+// from the primary's. Mirrored requests run after the primary has responded
+// and their responses are discarded, so they never affect clients.
+type Shadower struct {
+	secondary http.Handler
+	config    ShadowConfig
+	ignore    map[string]bool
+	sample    func() float64
+	counters  *metrics.Registry
+	logger    zerolog.Logger
+
+	slots   chan struct{}
+	running sync.WaitGroup
+}
+
+// NewShadower creates a shadower mirroring requests to secondary and
+// counting their outcomes in counters.
+func NewShadower(secondary http.Handler, config ShadowConfig, counters *metrics.Registry, logger zerolog.Logger) *Shadower {
+	ignore := make(map[string]bool, len(config.IgnoreFields))
+	for _, field := range config.IgnoreFields {
+		ignore[field] = true
+	}
+
+	return &Shadower{
+		secondary: secondary,
+		config:    config,
+		ignore:    ignore,
+		sample:    func() float64 { return rand.Float64() * 100 },
+		counters:  counters,
+		logger:    logger.With().Str("component", "shadow").Logger(),
+		slots:     make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// Close waits for mirrored requests in flight to finish, or until ctx is done.
+func (s *Shadower) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shadow requests still running: %w", ctx.Err())
+	}
+}
+
+// Shadow mirrors a sample of GET and HEAD requests under /api/ and /public/
+// to the shadower's secondary handler. Admin routes and requests made with an
+// admin key, including on behalf of a customer, are never mirrored, so admin
+// credentials are not sent to the secondary. The primary response is copied
+// as it is written, and compared with the secondary's in the background once
+// the primary has finished.
+func Shadow(shadower *Shadower) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !shadowable(r) || shadower.sample() >= shadower.config.Percent {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			endpoint := shadowEndpoint(r.URL.Path)
+			select {
+			case shadower.slots <- struct{}{}:
+			default:
+				shadower.counters.Counter(MetricShadowRequests, "endpoint", endpoint, "outcome", shadowDropped).Inc()
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Clone before the primary runs, since later handlers may
+			// rewrite the request's URL
+			mirror := r.Clone(context.WithoutCancel(r.Context()))
+			mirror.Body = http.NoBody
+			if id, ok := RequestIDFromContext(r.Context()); ok {
+				// Correlate the secondary's logs with the primary's
+				mirror.Header.Set(RequestIDHeader, id)
+			}
+
+			primary := &teeWriter{ResponseWriter: w, recording: recording{status: http.StatusOK, limit: shadower.config.MaxBodyBytes}}
+			next.ServeHTTP(primary, r)
+
+			shadower.running.Add(1)
+			go func() {
+				defer shadower.running.Done()
+				defer func() { <-shadower.slots }()
+				shadower.compare(mirror, endpoint, &primary.recording)
+			}()
+		})
+	}
+}
+
+// compare serves the mirrored request with the secondary handler and
+// records how its response compares with the primary's.
+func (s *Shadower) compare(r *http.Request, endpoint string, primary *recording) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.config.Timeout)
+	defer cancel()
+
+	secondary := &recorder{recording: recording{status: http.StatusOK, limit: s.config.MaxBodyBytes}, header: make(http.Header)}
+	start := time.Now()
+	func() {
+		// A panicking secondary must not take down the server
+		defer func() {
+			if rec := recover(); rec != nil {
+				secondary.err = fmt.Errorf("secondary handler panicked: %v", rec)
+			}
+		}()
+		s.secondary.ServeHTTP(secondary, r.WithContext(ctx))
+	}()
+	elapsed := time.Since(start)
+
+	outcome := shadowMatch
+	var diff string
+	switch {
+	case secondary.err != nil:
+		outcome = shadowError
+		diff = secondary.err.Error()
+	case primary.status != secondary.status:
+		outcome = shadowMismatch
+		diff = fmt.Sprintf("status %d != %d", primary.status, secondary.status)
+	default:
+		diff = s.diffBodies(primary, &secondary.recording)
+		if diff != "" {
+			outcome = shadowMismatch
+		}
+	}
+
+	s.counters.Counter(MetricShadowRequests, "endpoint", endpoint, "outcome", outcome).Inc()
+	if outcome == shadowMatch {
+		return
+	}
+
+	s.logger.Warn().
+		Str("request_id", r.Header.Get(RequestIDHeader)).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("outcome", outcome).
+		Int("primary_status", primary.status).
+		Int("shadow_status", secondary.status).
+		Dur("shadow_duration", elapsed).
+		Str("diff", diff).
+		Msg("shadow response differs")
+}
+
+// diffBodies describes the first difference between two response bodies,
+// or returns "" when they match. JSON bodies are compared as values,
+// without the ignored fields; bodies cut off at MaxBodyBytes are compared
+// as far as both were recorded.
+func (s *Shadower) diffBodies(primary, secondary *recording) string {
+	a, b := primary.body.Bytes(), secondary.body.Bytes()
+	if primary.truncated || secondary.truncated {
+		n := min(len(a), len(b))
+		if !bytes.Equal(a[:n], b[:n]) {
+			return "body prefix differs"
+		}
+		return ""
+	}
+
+	var aValue, bValue any
+	if json.Unmarshal(a, &aValue) != nil || json.Unmarshal(b, &bValue) != nil {
+		if !bytes.Equal(a, b) {
+			return "body differs"
+		}
+		return ""
+	}
+	return s.diffJSON("$", aValue, bValue)
+}
+
+// diffJSON returns the path of the first difference between two decoded
+// JSON values, or "" when they are equal.
+func (s *Shadower) diffJSON(path string, a, b any) string {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			return path + ": type differs"
+		}
+		keys := make(map[string]bool, len(a)+len(b))
+		for key := range a {
+			keys[key] = true
+		}
+		for key := range b {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			if !s.ignore[key] {
+				sorted = append(sorted, key)
+			}
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			aField, aOK := a[key]
+			bField, bOK := b[key]
+			if aOK != bOK {
+				return path + "." + key + ": missing on one side"
+			}
+			if diff := s.diffJSON(path+"."+key, aField, bField); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			return path + ": type differs"
+		}
+		if len(a) != len(b) {
+			return fmt.Sprintf("%s: length %d != %d", path, len(a), len(b))
+		}
+		for i := range a {
+			if diff := s.diffJSON(fmt.Sprintf("%s[%d]", path, i), a[i], b[i]); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	default:
+		if !reflect.DeepEqual(a, b) {
+			return path + ": value differs"
+		}
+		return ""
+	}
+}
+
+// NewShadowProxy returns a secondary handler forwarding mirrored requests to
+// target, such as a deployment of a refactored build, through transport.
+// Requests keep their headers, so the target authenticates them as the
+// primary did, except X-On-Behalf-Of: only admin requests may impersonate
+// customers, and those are not mirrored. Failures to reach the target are
+// reported as shadow errors rather than as mismatched responses.
+func NewShadowProxy(target *url.URL, transport http.RoundTripper) http.Handler {
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Header.Del(OnBehalfOfHeader)
+		},
+		Transport: transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if rec, ok := w.(*recorder); ok {
+				rec.err = err
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+// shadowable reports whether r may be mirrored: only reads of the API and
+// the public catalogue, which have no side effects, made without admin
+// credentials.
+func shadowable(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if identity, ok := IdentityFromContext(r.Context()); ok {
+		if identity.Method == AuthMethodAdminKey || identity.Method == AuthMethodImpersonated {
+			return false
+		}
+	}
+	if isAdminAPI(r.URL.Path) {
+		return false
+	}
+	return strings.HasPrefix(r.URL.Path, "/api/") || isPublic(r.URL.Path)
+}
+
+// isAdminAPI reports whether path is an admin API route, versioned or not,
+// e.g. "/api/admin/orders" or "/api/v1/admin/orders".
+func isAdminAPI(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return false
+	}
+	if version, after, found := strings.Cut(rest, "/"); found && isVersion(version) {
+		rest = after
+	}
+	return strings.HasPrefix(rest, "admin/")
+}
+
+// shadowEndpoint reduces a path to its resource, e.g. "/api/v1/orders/{id}"
+// becomes "/api/v1/orders", keeping metric cardinality independent of
+// resource IDs.
+func shadowEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	n := 0
+	for n < len(segments) && (segments[n] == "api" || segments[n] == "public" || isVersion(segments[n])) {
+		n++
+	}
+	if n < len(segments) {
+		n++
+	}
+	return "/" + strings.Join(segments[:n], "/")
+}
+
+// isVersion reports whether a path segment is an API version, e.g. "v1".
+func isVersion(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	for _, c := range segment[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// recording holds a response's status and up to limit bytes of its body.
+type recording struct {
+	status    int
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// record appends p to the recorded body, up to the limit.
+func (rec *recording) record(p []byte) {
+	if room := rec.limit - rec.body.Len(); room < len(p) {
+		rec.truncated = true
+		p = p[:max(room, 0)]
+	}
+	rec.body.Write(p)
+}
+
+// teeWriter records the primary response as it is written to the client.
+type teeWriter struct {
+	http.ResponseWriter
+	recording
+	wroteHeader bool
+}
+
+func (w *teeWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	w.record(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the client's writer to http.ResponseController.
+func (w *teeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// recorder records the secondary's response in place of a client.
+type recorder struct {
+	recording
+	header      http.Header
+	wroteHeader bool
+	err         error
+}
+
+func (w *recorder) Header() http.Header {
+	return w.header
+}
+
+func (w *recorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+}
+
+func (w *recorder) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	w.record(p)
+	return len(p), nil
+}
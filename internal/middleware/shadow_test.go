@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"mini-kart/internal/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// respond returns a handler answering with status and body.
+func respond(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+// testShadower returns a shadower mirroring every request to secondary.
+func testShadower(secondary http.Handler, counters *metrics.Registry) *Shadower {
+	return NewShadower(secondary, ShadowConfig{
+		Percent:       100,
+		Timeout:       time.Second,
+		MaxConcurrent: 4,
+		MaxBodyBytes:  1024,
+		IgnoreFields:  []string{"generatedAt"},
+	}, counters, zerolog.Nop())
+}
+
+func TestShadow(t *testing.T) {
+	primaryBody := `{"id":"P001","price":"10.00","tags":["a","b"],"generatedAt":"2026-03-01T12:00:00Z"}`
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		secondary       http.Handler
+		expectedOutcome string
+	}{
+		{
+			name:            "Matching response",
+			method:          http.MethodGet,
+			path:            "/api/v1/products/P001",
+			secondary:       respond(http.StatusOK, primaryBody),
+			expectedOutcome: "match",
+		},
+		{
+			name:            "Equal JSON formatted differently, ignored field differs",
+			method:          http.MethodGet,
+			path:            "/api/v1/products/P001",
+			secondary:       respond(http.StatusOK, `{"tags": ["a", "b"], "price": "10.00", "id": "P001", "generatedAt": "2026-03-01T12:00:01Z"}`),
+			expectedOutcome: "match",
+		},
+		{
+			name:            "Different status",
+			method:          http.MethodGet,
+			path:            "/api/v1/products/P001",
+			secondary:       respond(http.StatusNotFound, `{"error":"not found"}`),
+			expectedOutcome: "mismatch",
+		},
+		{
+			name:            "Different field",
+			method:          http.MethodGet,
+			path:            "/api/v1/products/P001",
+			secondary:       respond(http.StatusOK, `{"id":"P001","price":10,"tags":["a","b"]}`),
+			expectedOutcome: "mismatch",
+		},
+		{
+			name:   "Panicking secondary",
+			method: http.MethodGet,
+			path:   "/api/v1/products/P001",
+			secondary: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			}),
+			expectedOutcome: "error",
+		},
+		{
+			name:      "Writes are not mirrored",
+			method:    http.MethodPost,
+			path:      "/api/v1/products",
+			secondary: respond(http.StatusOK, primaryBody),
+		},
+		{
+			name:      "Health checks are not mirrored",
+			method:    http.MethodGet,
+			path:      "/health",
+			secondary: respond(http.StatusOK, primaryBody),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counters := metrics.NewRegistry()
+			shadower := testShadower(tt.secondary, counters)
+
+			w := httptest.NewRecorder()
+			Shadow(shadower)(respond(http.StatusOK, primaryBody)).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			require.NoError(t, shadower.Close(context.Background()))
+
+			// The client always gets the primary response
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, primaryBody, w.Body.String())
+
+			samples := counters.Snapshot()
+			if tt.expectedOutcome == "" {
+				assert.Empty(t, samples)
+				return
+			}
+			require.Len(t, samples, 1)
+			assert.Equal(t, MetricShadowRequests, samples[0].Name)
+			assert.Equal(t, map[string]string{"endpoint": "/api/v1/products", "outcome": tt.expectedOutcome}, samples[0].Labels)
+		})
+	}
+}
+
+func TestShadow_Sampling(t *testing.T) {
+	counters := metrics.NewRegistry()
+	shadower := testShadower(respond(http.StatusOK, `{}`), counters)
+	shadower.config.Percent = 25
+
+	handler := Shadow(shadower)(respond(http.StatusOK, `{}`))
+	for _, sample := range []float64{10, 24.9, 25, 80} {
+		shadower.sample = func() float64 { return sample }
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/public/products", nil))
+	}
+	require.NoError(t, shadower.Close(context.Background()))
+
+	assert.Equal(t, int64(2), counters.Counter(MetricShadowRequests, "endpoint", "/public/products", "outcome", "match").Value())
+}
+
+func TestShadow_Dropped(t *testing.T) {
+	counters := metrics.NewRegistry()
+	release := make(chan struct{})
+	shadower := testShadower(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}), counters)
+	shadower.config.MaxConcurrent = 1
+	shadower.slots = make(chan struct{}, 1)
+
+	handler := Shadow(shadower)(respond(http.StatusOK, ``))
+	for range 3 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	}
+	close(release)
+	require.NoError(t, shadower.Close(context.Background()))
+
+	assert.Equal(t, int64(2), counters.Counter(MetricShadowRequests, "endpoint", "/api/orders", "outcome", "dropped").Value())
+	assert.Equal(t, int64(1), counters.Counter(MetricShadowRequests, "endpoint", "/api/orders", "outcome", "match").Value())
+}
+
+func TestShadowProxy(t *testing.T) {
+	var forwarded *http.Request
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r
+		w.Write([]byte(`{"id":"P001"}`))
+	}))
+	defer target.Close()
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+
+	counters := metrics.NewRegistry()
+	shadower := testShadower(NewShadowProxy(targetURL, http.DefaultTransport), counters)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/P001?currency=USD", nil)
+	req.Header.Set("X-API-Key", "test-key")
+	req.Header.Set(OnBehalfOfHeader, "customer-1")
+	Shadow(shadower)(respond(http.StatusOK, `{"id":"P001"}`)).ServeHTTP(httptest.NewRecorder(), req)
+	require.NoError(t, shadower.Close(context.Background()))
+
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "/api/v1/products/P001", forwarded.URL.Path)
+	assert.Equal(t, "USD", forwarded.URL.Query().Get("currency"))
+	assert.Equal(t, "test-key", forwarded.Header.Get("X-API-Key"))
+	assert.Empty(t, forwarded.Header.Get(OnBehalfOfHeader))
+	assert.Equal(t, targetURL.Host, forwarded.Host)
+	assert.Equal(t, int64(1), counters.Counter(MetricShadowRequests, "endpoint", "/api/v1/products", "outcome", "match").Value())
+
+	// A target that cannot be reached is an error, not a mismatch
+	target.Close()
+	Shadow(shadower)(respond(http.StatusOK, `{"id":"P001"}`)).ServeHTTP(httptest.NewRecorder(), req)
+	require.NoError(t, shadower.Close(context.Background()))
+	assert.Equal(t, int64(1), counters.Counter(MetricShadowRequests, "endpoint", "/api/v1/products", "outcome", "error").Value())
+}
+
+func TestShadowable(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		authMethod string
+		expected   bool
+	}{
+		{name: "API read", method: http.MethodGet, path: "/api/v1/orders/1", authMethod: AuthMethodAPIKey, expected: true},
+		{name: "Public catalogue read", method: http.MethodHead, path: "/public/products", expected: true},
+		{name: "Tenant key read", method: http.MethodGet, path: "/api/products", authMethod: AuthMethodTenantKey, expected: true},
+		{name: "API write", method: http.MethodPost, path: "/api/v1/orders", authMethod: AuthMethodAPIKey, expected: false},
+		{name: "Health check", method: http.MethodGet, path: "/health", expected: false},
+		{name: "Admin route", method: http.MethodGet, path: "/api/admin/orders", authMethod: AuthMethodAPIKey, expected: false},
+		{name: "Versioned admin route", method: http.MethodGet, path: "/api/v1/admin/metrics", authMethod: AuthMethodAPIKey, expected: false},
+		{name: "Admin key on a client route", method: http.MethodGet, path: "/api/v1/products/P001", authMethod: AuthMethodAdminKey, expected: false},
+		{name: "Impersonated customer", method: http.MethodGet, path: "/api/v1/orders/1", authMethod: AuthMethodImpersonated, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authMethod != "" {
+				req = req.WithContext(WithIdentity(req.Context(), Identity{Subject: "caller", Method: tt.authMethod}))
+			}
+
+			assert.Equal(t, tt.expected, shadowable(req))
+		})
+	}
+}
+
+func TestShadowEndpoint(t *testing.T) {
+	assert.Equal(t, "/api/v1/orders", shadowEndpoint("/api/v1/orders/8f14e45f-ceea-467f-a0e6-2b2b4c5f1e3a"))
+	assert.Equal(t, "/api/products", shadowEndpoint("/api/products/P001"))
+	assert.Equal(t, "/public/products", shadowEndpoint("/public/products"))
+	assert.Equal(t, "/api/v1", shadowEndpoint("/api/v1/"))
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// TenantHeader selects the tenant a request is served for.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantScope scopes each request to a tenant, so repositories only see that
+// tenant's products, orders and coupon discounts. Callers using a
+// tenant-scoped key are served for their key's tenant, and the shared API_KEY
+// for the default tenant; both are rejected with 403 Forbidden if they name
+// another one. Admin keys, client certificates and the anonymous public
+// catalogue name their tenant in the X-Tenant-ID header and are served for the
+// default tenant without it.
+func TenantScope(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get(TenantHeader)
+			if requested != "" && !model.ValidTenantID(requested) {
+				http.Error(w, "invalid "+TenantHeader+" header", http.StatusBadRequest)
+				return
+			}
+
+			tenantID := requested
+			if identity, ok := IdentityFromContext(r.Context()); ok {
+				// Everyone using the shared key shares one identity, so it
+				// cannot be trusted to pick a tenant
+				pinned := identity.Tenant
+				if pinned == "" && identity.Method == AuthMethodAPIKey {
+					pinned = model.DefaultTenant
+				}
+				if pinned != "" {
+					if requested != "" && requested != pinned {
+						logger.Warn().
+							Str("subject", identity.Subject).
+							Str("tenant_id", requested).
+							Str("path", r.URL.Path).
+							Msg("key used for another tenant")
+						http.Error(w, "forbidden: key does not belong to tenant "+requested, http.StatusForbidden)
+						return
+					}
+					tenantID = pinned
+				}
+			}
+			if tenantID == "" {
+				tenantID = model.DefaultTenant
+			}
+
+			ctx := model.WithTenant(r.Context(), tenantID)
+			ctx = zerolog.Ctx(ctx).With().Str("tenant_id", tenantID).Logger().WithContext(ctx)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
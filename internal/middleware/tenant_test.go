@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantScope(t *testing.T) {
+	logger := zerolog.Nop()
+	acme := Identity{Subject: "tenant:acme", Method: AuthMethodTenantKey, Tenant: "acme"}
+
+	tests := []struct {
+		name           string
+		identity       *Identity
+		tenantHeader   string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{
+			name:           "Tenant key is scoped to its tenant",
+			identity:       &acme,
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "Tenant key may name its own tenant",
+			identity:       &acme,
+			tenantHeader:   "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "Tenant key cannot name another tenant",
+			identity:       &acme,
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin key names tenant",
+			identity:       &Identity{Subject: "admin:support", Method: AuthMethodAdminKey},
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "globex",
+		},
+		{
+			name:           "Client certificate names tenant",
+			identity:       &Identity{Subject: "CN=billing", Method: AuthMethodMTLS},
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "globex",
+		},
+		{
+			name:           "API key defaults to default tenant",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			expectedStatus: http.StatusOK,
+			expectedTenant: model.DefaultTenant,
+		},
+		{
+			name:           "API key may name the default tenant",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			tenantHeader:   model.DefaultTenant,
+			expectedStatus: http.StatusOK,
+			expectedTenant: model.DefaultTenant,
+		},
+		{
+			name:           "API key cannot name another tenant",
+			identity:       &Identity{Subject: "api-key", Method: AuthMethodAPIKey},
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Anonymous caller names tenant",
+			tenantHeader:   "globex",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "globex",
+		},
+		{
+			name:           "Invalid tenant",
+			tenantHeader:   "Globex Corp",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tenantID string
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenantID, _ = model.TenantFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			if tt.identity != nil {
+				req = req.WithContext(WithIdentity(req.Context(), *tt.identity))
+			}
+			if tt.tenantHeader != "" {
+				req.Header.Set(TenantHeader, tt.tenantHeader)
+			}
+			w := httptest.NewRecorder()
+
+			TenantScope(logger)(testHandler).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedTenant, tenantID)
+		})
+	}
+}
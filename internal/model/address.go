@@ -0,0 +1,70 @@
+package model
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxAddressFieldLength bounds each line of an address.
+	maxAddressFieldLength = 200
+
+	// maxPostalCodeLength bounds postal codes, the longest in use being 10
+	// characters, with room for formatting.
+	maxPostalCodeLength = 20
+)
+
+// Address represents a delivery address. Price previews only need its
+// Country; orders shipped to it need it in full (see ValidateShipping).
+type Address struct {
+	Name       string `json:"name,omitempty"`
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country"`
+}
+
+// Normalize trims every field and puts the country code in upper case.
+func (a *Address) Normalize() {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.Region = strings.TrimSpace(a.Region)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+}
+
+// ValidateShipping checks a normalized address can be shipped to: it needs a
+// recipient name, first line, city, postal code and an ISO 3166-1 alpha-2
+// country code, and no field may exceed 200 characters, or 20 for the postal
+// code. Returns ErrInvalidShippingAddress otherwise.
+func (a Address) ValidateShipping() error {
+	if a.Name == "" || a.Line1 == "" || a.City == "" || a.PostalCode == "" || !validCountryCode(a.Country) {
+		return ErrInvalidShippingAddress
+	}
+	for _, field := range []string{a.Name, a.Line1, a.Line2, a.City, a.Region} {
+		if utf8.RuneCountInString(field) > maxAddressFieldLength {
+			return ErrInvalidShippingAddress
+		}
+	}
+	if utf8.RuneCountInString(a.PostalCode) > maxPostalCodeLength {
+		return ErrInvalidShippingAddress
+	}
+	return nil
+}
+
+// validCountryCode reports whether code is two upper case ASCII letters.
+func validCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
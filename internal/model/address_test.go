@@ -0,0 +1,47 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddress_ValidateShipping(t *testing.T) {
+	valid := func() Address {
+		return Address{Name: "Ada Lovelace", Line1: "1 Main Street", City: "Sydney", PostalCode: "2000", Country: "AU"}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(a *Address)
+		wantErr bool
+	}{
+		{name: "Complete address", modify: func(a *Address) {}},
+		{name: "Optional fields", modify: func(a *Address) { a.Line2 = "Unit 4"; a.Region = "NSW" }},
+		{name: "Lower case country is normalized", modify: func(a *Address) { a.Country = " au " }},
+		{name: "Missing name", modify: func(a *Address) { a.Name = "  " }, wantErr: true},
+		{name: "Missing first line", modify: func(a *Address) { a.Line1 = "" }, wantErr: true},
+		{name: "Missing city", modify: func(a *Address) { a.City = "" }, wantErr: true},
+		{name: "Missing postal code", modify: func(a *Address) { a.PostalCode = "" }, wantErr: true},
+		{name: "Country name instead of code", modify: func(a *Address) { a.Country = "Australia" }, wantErr: true},
+		{name: "Non-letter country code", modify: func(a *Address) { a.Country = "A1" }, wantErr: true},
+		{name: "Line too long", modify: func(a *Address) { a.Line2 = strings.Repeat("x", 201) }, wantErr: true},
+		{name: "Postal code too long", modify: func(a *Address) { a.PostalCode = strings.Repeat("9", 21) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := valid()
+			tt.modify(&address)
+			address.Normalize()
+
+			err := address.ValidateShipping()
+			if tt.wantErr {
+				assert.Equal(t, ErrInvalidShippingAddress, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
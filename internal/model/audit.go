@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions.
+const (
+	AuditActionProductArchive      = "product.archive"
+	AuditActionOrderCreate         = "order.create"
+	AuditActionOrderStatus         = "order.status"
+	AuditActionOrderStatusOverride = "order.status_override"
+	AuditActionOrderSnapshot       = "order.snapshot"
+)
+
+// Delegation identifies an admin acting on behalf of a customer through the
+// X-On-Behalf-Of header. Operations performed under a delegation are
+// recorded in the audit log with the admin as the actor.
+type Delegation struct {
+	Admin    string
+	Customer string
+}
+
+// AuditEntry records an admin operation and the identity that performed it.
+type AuditEntry struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	Actor      string         `json:"actor" db:"actor"`
+	Action     string         `json:"action" db:"action"`
+	EntityType string         `json:"entityType" db:"entity_type"`
+	Details    map[string]any `json:"details,omitempty" db:"details"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+}
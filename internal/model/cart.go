@@ -0,0 +1,51 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxCartItems bounds the items of a cart, keeping cart tokens short enough
+// to pass in URLs.
+const MaxCartItems = 50
+
+// CartRequest lists the items of an anonymous storefront cart to sign.
+// Carts are not stored: every change sends the whole cart to be signed
+// again, and the client keeps the latest token.
+type CartRequest struct {
+	Items      []OrderItemRequest `json:"items"`
+	CouponCode *string            `json:"couponCode,omitempty"`
+}
+
+// Validate checks the cart has 1 to MaxCartItems items, each naming a
+// product with a positive quantity. Returns ErrInvalidCart otherwise.
+func (r CartRequest) Validate() error {
+	if len(r.Items) == 0 || len(r.Items) > MaxCartItems {
+		return ErrInvalidCart
+	}
+	for _, item := range r.Items {
+		if strings.TrimSpace(item.ProductID) == "" || item.Quantity <= 0 {
+			return ErrInvalidCart
+		}
+	}
+	return nil
+}
+
+// Cart is the content of a verified cart token.
+type Cart struct {
+	TenantID   string
+	Items      []OrderItemRequest
+	CouponCode *string
+	ExpiresAt  time.Time
+}
+
+// SignedCart is a cart with the token that carries it, priced at current
+// catalogue prices. The token is all the server needs to restore the cart;
+// clients submit it at checkout in place of the items.
+type SignedCart struct {
+	Token      string             `json:"token"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	Items      []OrderItemRequest `json:"items"`
+	CouponCode *string            `json:"couponCode,omitempty"`
+	Pricing    *PriceBreakdown    `json:"pricing"`
+}
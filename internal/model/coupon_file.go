@@ -0,0 +1,152 @@
+package model
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// checksumPattern matches a hex-encoded SHA-256 checksum.
+var checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// CouponFile records the upload of a coupon file to where the validator
+// loads it from. Files are matched to the loads that consumed them by set
+// name and checksum, so a registered upload proves where the codes accepted
+// by those loads came from.
+type CouponFile struct {
+	ID uuid.UUID `json:"id" db:"id"`
+
+	// Name is the coupon set the file holds, its base name without
+	// extensions, e.g. couponbase1.
+	Name string `json:"name" db:"name"`
+
+	// Source is where the file came from, e.g. the partner feed or the
+	// object storage URL it was uploaded to.
+	Source string `json:"source" db:"source"`
+
+	// Checksum is the hex-encoded SHA-256 of the file as stored, before
+	// decompression.
+	Checksum string `json:"checksum" db:"checksum"`
+
+	SizeBytes    *int64    `json:"sizeBytes,omitempty" db:"size_bytes"`
+	UploadedBy   string    `json:"uploadedBy" db:"uploaded_by"`
+	UploadedAt   time.Time `json:"uploadedAt" db:"uploaded_at"`
+	RegisteredBy string    `json:"registeredBy" db:"registered_by"`
+	RegisteredAt time.Time `json:"registeredAt" db:"registered_at"`
+}
+
+// CouponFileRequest represents the request payload for registering an
+// uploaded coupon file. UploadedBy defaults to the registering admin and
+// UploadedAt to the time of registration.
+type CouponFileRequest struct {
+	Name       string     `json:"name"`
+	Source     string     `json:"source"`
+	Checksum   string     `json:"checksum"`
+	SizeBytes  *int64     `json:"sizeBytes,omitempty"`
+	UploadedBy string     `json:"uploadedBy,omitempty"`
+	UploadedAt *time.Time `json:"uploadedAt,omitempty"`
+}
+
+// Validate checks the file has a name, a source and a lowercase hex SHA-256
+// checksum, a non-negative size, and was not uploaded in the future. Returns
+// ErrInvalidCouponFile otherwise.
+func (r CouponFileRequest) Validate() error {
+	if r.Name == "" || len(r.Name) > 200 || r.Source == "" || len(r.Source) > 2000 || len(r.UploadedBy) > 200 {
+		return ErrInvalidCouponFile
+	}
+	if !checksumPattern.MatchString(r.Checksum) {
+		return ErrInvalidCouponFile
+	}
+	if r.SizeBytes != nil && *r.SizeBytes < 0 {
+		return ErrInvalidCouponFile
+	}
+	if r.UploadedAt != nil && r.UploadedAt.After(time.Now()) {
+		return ErrInvalidCouponFile
+	}
+	return nil
+}
+
+// CouponGeneration is one complete load of the coupon files by a validator
+// instance. It served validations from LoadedAt until the instance loaded
+// the next generation or stopped, recorded as RetiredAt.
+type CouponGeneration struct {
+	ID        int64      `json:"id" db:"id"`
+	Instance  string     `json:"instance" db:"instance"`
+	LoadedAt  time.Time  `json:"loadedAt" db:"loaded_at"`
+	RetiredAt *time.Time `json:"retiredAt,omitempty" db:"retired_at"`
+
+	Files []CouponGenerationFile `json:"files"`
+}
+
+// CouponGenerationFile is a coupon file consumed by a generation. Checksum is
+// empty for sets not read from a file, such as prebuilt indexes and the
+// database. Upload is the registered upload with the same name and checksum,
+// nil if the file was never registered.
+type CouponGenerationFile struct {
+	Name     string      `json:"name" db:"name"`
+	Path     string      `json:"path" db:"path"`
+	Checksum string      `json:"checksum,omitempty" db:"checksum"`
+	Codes    int         `json:"codes" db:"codes"`
+	Upload   *CouponFile `json:"upload,omitempty"`
+}
+
+// Verdicts on whether a promo code was valid at a past time.
+const (
+	// CouponVerdictValid means every generation serving validations at the
+	// time accepted the code.
+	CouponVerdictValid = "valid"
+
+	// CouponVerdictInvalid means the code was refused for its format, length
+	// or expiry, or every generation serving validations refused it.
+	CouponVerdictInvalid = "invalid"
+
+	// CouponVerdictMixed means some generations serving validations accepted
+	// the code and others refused it, e.g. during a rolling reload.
+	CouponVerdictMixed = "mixed"
+
+	// CouponVerdictUnknown means no generation was recorded serving
+	// validations at the time, or too few of their files were archived to
+	// tell.
+	CouponVerdictUnknown = "unknown"
+)
+
+// CouponValidityAt reports whether a promo code was valid at a past time,
+// checked against archived copies of the coupon files each generation serving
+// validations then had loaded. Reason holds the error code an invalid code
+// was refused with. MinMatchCount is the total weight of files a code had to
+// be found in.
+type CouponValidityAt struct {
+	Code          string                     `json:"code"`
+	At            time.Time                  `json:"at"`
+	Verdict       string                     `json:"verdict"`
+	Reason        string                     `json:"reason,omitempty"`
+	ExpiresAt     *time.Time                 `json:"expiresAt,omitempty"`
+	MinMatchCount int                        `json:"minMatchCount"`
+	Generations   []CouponGenerationValidity `json:"generations"`
+}
+
+// CouponGenerationValidity reports whether a generation accepted a promo
+// code. Valid is nil when files the code was not found in could not be
+// checked and would have decided the outcome. MatchCount is the total weight
+// of the files the code was found in.
+type CouponGenerationValidity struct {
+	Generation int64              `json:"generation"`
+	Instance   string             `json:"instance"`
+	LoadedAt   time.Time          `json:"loadedAt"`
+	RetiredAt  *time.Time         `json:"retiredAt,omitempty"`
+	Valid      *bool              `json:"valid"`
+	MatchCount int                `json:"matchCount"`
+	Files      []CouponFileSearch `json:"files"`
+}
+
+// CouponFileSearch reports whether a coupon file loaded by a generation
+// contained a promo code. Found is nil when the file could not be checked:
+// the set was not read from a file, or no archived copy with the recorded
+// checksum could be read.
+type CouponFileSearch struct {
+	Name     string `json:"name"`
+	Checksum string `json:"checksum,omitempty"`
+	Weight   int    `json:"weight"`
+	Found    *bool  `json:"found"`
+}
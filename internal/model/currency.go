@@ -0,0 +1,40 @@
+package model
+
+import "strings"
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit, by the number of decimals they are priced in.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// NormalizeCurrency returns code in upper case without surrounding spaces.
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidCurrency reports whether code has the shape of an ISO 4217 code:
+// three upper-case letters.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// CurrencyExponent returns the number of decimals amounts in the currency
+// are priced in: 2 for most currencies, 0 for the likes of JPY and 3 for the
+// likes of KWD.
+func CurrencyExponent(code string) int {
+	if exponent, ok := currencyExponents[code]; ok {
+		return exponent
+	}
+	return 2
+}
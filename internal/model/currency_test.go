@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidCurrency(t *testing.T) {
+	assert.True(t, ValidCurrency("AUD"))
+	assert.True(t, ValidCurrency(NormalizeCurrency(" usd ")))
+	assert.False(t, ValidCurrency("usd"))
+	assert.False(t, ValidCurrency("AU"))
+	assert.False(t, ValidCurrency("AUD1"))
+	assert.False(t, ValidCurrency("A$D"))
+}
+
+func TestProduct_InCurrency(t *testing.T) {
+	product := Product{ID: "P001", Price: NewMoney(10), EffectivePrice: NewMoney(7.5), Currency: "AUD", Sale: &ProductSale{Price: NewMoney(7.5)}}
+
+	converted := product.InCurrency("USD", 0.66)
+
+	assert.Equal(t, NewMoney(6.6), converted.Price)
+	assert.Equal(t, NewMoney(4.95), converted.EffectivePrice)
+	assert.Equal(t, NewMoney(4.95), converted.Sale.Price)
+	assert.Equal(t, "USD", converted.Currency)
+	// The original product and its sale are left as they were
+	assert.Equal(t, NewMoney(7.5), product.Sale.Price)
+	assert.Equal(t, "AUD", product.Currency)
+}
+
+func TestProductComparison_InCurrency(t *testing.T) {
+	comparison := ProductComparison{
+		Products: []Product{{ID: "P001", Price: NewMoney(4.5), EffectivePrice: NewMoney(4.5)}, {ID: "P002", Price: NewMoney(6), EffectivePrice: NewMoney(5)}},
+		Attributes: []ComparisonAttribute{
+			{Name: "category", Values: []any{"Cereal", "Cereal"}},
+			{Name: "price", Values: []any{NewMoney(4.5), NewMoney(5)}, Differs: true},
+		},
+	}
+
+	converted := comparison.InCurrency("USD", 0.5)
+
+	assert.Equal(t, []any{NewMoney(2.25), NewMoney(2.5)}, converted.Attributes[1].Values)
+	assert.True(t, converted.Attributes[1].Differs)
+	assert.Equal(t, []any{"Cereal", "Cereal"}, converted.Attributes[0].Values)
+	assert.Equal(t, NewMoney(3), converted.Products[1].Price)
+	assert.Equal(t, []any{NewMoney(4.5), NewMoney(5)}, comparison.Attributes[1].Values)
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxCustomerEmailLength is the longest address SMTP can deliver to.
+	maxCustomerEmailLength = 254
+
+	// maxCustomerNameLength bounds the names stored per customer.
+	maxCustomerNameLength = 200
+
+	// maxCustomerSegments and maxSegmentLength bound the segments stored per
+	// customer.
+	maxCustomerSegments = 20
+	maxSegmentLength    = 50
+)
+
+// Customer is a registered shopper of a tenant. Orders placed with the
+// customer's ID make up their order history. Segments group customers,
+// e.g. "vip" or "staff", for coupons restricted to them.
+type Customer struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Email     string    `json:"email" db:"email"`
+	Name      string    `json:"name" db:"name"`
+	Segments  []string  `json:"segments,omitempty" db:"segments"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// InSegment reports whether the customer belongs to any of the segments.
+func (c *Customer) InSegment(segments []string) bool {
+	for _, segment := range segments {
+		if slices.Contains(c.Segments, NormalizeSegment(segment)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomerRequest represents the request payload for registering a customer.
+type CustomerRequest struct {
+	Email    string   `json:"email"`
+	Name     string   `json:"name"`
+	Segments []string `json:"segments,omitempty"`
+}
+
+// Normalize trims the name, puts the email in canonical form, so an address
+// registers once however its case is typed, and puts the segments in
+// canonical form without duplicates.
+func (r *CustomerRequest) Normalize() {
+	r.Email = NormalizeEmail(r.Email)
+	r.Name = strings.TrimSpace(r.Name)
+
+	var segments []string
+	for _, segment := range r.Segments {
+		segment = NormalizeSegment(segment)
+		if !slices.Contains(segments, segment) {
+			segments = append(segments, segment)
+		}
+	}
+	r.Segments = segments
+}
+
+// Validate checks the customer has a bare email address of at most 254
+// characters, a name of at most 200, and at most 20 non-empty segments of at
+// most 50 characters. Returns ErrInvalidCustomer otherwise.
+func (r CustomerRequest) Validate() error {
+	if !ValidEmail(r.Email) || r.Name == "" || utf8.RuneCountInString(r.Name) > maxCustomerNameLength {
+		return ErrInvalidCustomer
+	}
+	if len(r.Segments) > maxCustomerSegments {
+		return ErrInvalidCustomer
+	}
+	for _, segment := range r.Segments {
+		if segment == "" || utf8.RuneCountInString(segment) > maxSegmentLength {
+			return ErrInvalidCustomer
+		}
+	}
+	return nil
+}
+
+// NormalizeSegment returns the canonical, lower case form of a customer
+// segment.
+func NormalizeSegment(segment string) string {
+	return strings.ToLower(strings.TrimSpace(segment))
+}
+
+// NormalizeEmail returns the canonical, lower case form of an email address.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidEmail reports whether email is a bare address such as
+// "ada@example.com", without a display name or angle brackets.
+func ValidEmail(email string) bool {
+	if len(email) > maxCustomerEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
@@ -9,15 +9,94 @@ type ErrorResponse struct {
 
 // Standard error codes for API responses
 const (
-	ErrCodeInvalidJSON        = "INVALID_JSON"
-	ErrCodeMissingField       = "MISSING_FIELD"
-	ErrCodeInvalidPromoCode   = "INVALID_PROMO_CODE"
-	ErrCodeInvalidPromoLength = "INVALID_PROMO_LENGTH"
-	ErrCodeProductNotFound    = "PRODUCT_NOT_FOUND"
-	ErrCodeInvalidQuantity    = "INVALID_QUANTITY"
-	ErrCodeUnauthorised       = "UNAUTHORIZED"
-	ErrCodeForbidden          = "FORBIDDEN"
-	ErrCodeInternalError      = "INTERNAL_ERROR"
+	ErrCodeInvalidJSON           = "INVALID_JSON"
+	ErrCodeMissingField          = "MISSING_FIELD"
+	ErrCodeInvalidPromoCode      = "INVALID_PROMO_CODE"
+	ErrCodeInvalidPromoLength    = "INVALID_PROMO_LENGTH"
+	ErrCodeInvalidPromoFormat    = "INVALID_PROMO_FORMAT"
+	ErrCodeProductNotFound       = "PRODUCT_NOT_FOUND"
+	ErrCodeInvalidQuantity       = "INVALID_QUANTITY"
+	ErrCodeCouponExhausted       = "COUPON_REDEMPTION_LIMIT_REACHED"
+	ErrCodeCouponCallerLimit     = "COUPON_CALLER_LIMIT_REACHED"
+	ErrCodeCouponNotApplicable   = "COUPON_NOT_APPLICABLE"
+	ErrCodeCouponFirstOrderOnly  = "COUPON_FIRST_ORDER_ONLY"
+	ErrCodeCouponNotForCustomer  = "COUPON_NOT_FOR_CUSTOMER"
+	ErrCodeCouponSegment         = "COUPON_CUSTOMER_SEGMENT"
+	ErrCodeCouponTimeout         = "COUPON_VALIDATION_TIMEOUT"
+	ErrCodeCouponsLoading        = "COUPONS_LOADING"
+	ErrCodeCouponExpired         = "COUPON_EXPIRED"
+	ErrCodeCouponMinSubtotal     = "COUPON_MIN_SUBTOTAL_NOT_MET"
+	ErrCodeInvalidOrderSource    = "INVALID_ORDER_SOURCE"
+	ErrCodeUnknownOrderFields    = "UNKNOWN_ORDER_FIELDS"
+	ErrCodeIdempotencyConflict   = "IDEMPOTENCY_KEY_CONFLICT"
+	ErrCodePaymentDeclined       = "PAYMENT_DECLINED"
+	ErrCodeInsufficientStock     = "INSUFFICIENT_STOCK"
+	ErrCodeCouponRejected        = "COUPON_REDEMPTION_REJECTED"
+	ErrCodeOrderStepFailed       = "ORDER_STEP_FAILED"
+	ErrCodeInvalidPrice          = "INVALID_PRICE"
+	ErrCodeInvalidSale           = "INVALID_SALE"
+	ErrCodePriceChangeNotFound   = "PRICE_CHANGE_NOT_FOUND"
+	ErrCodePriceChangePending    = "PRICE_CHANGE_ALREADY_PENDING"
+	ErrCodePriceChangeNotPending = "PRICE_CHANGE_NOT_PENDING"
+	ErrCodeSelfApproval          = "SELF_APPROVAL_NOT_ALLOWED"
+	ErrCodeUnsupportedCurrency   = "UNSUPPORTED_CURRENCY"
+	ErrCodeRatesUnavailable      = "EXCHANGE_RATES_UNAVAILABLE"
+	ErrCodeInvalidAddress        = "INVALID_ADDRESS"
+	ErrCodeShippingAddress       = "INVALID_SHIPPING_ADDRESS"
+	ErrCodeOrderNotFound         = "ORDER_NOT_FOUND"
+	ErrCodeInvalidOrderStatus    = "INVALID_ORDER_STATUS"
+	ErrCodeStatusTransition      = "ORDER_STATUS_TRANSITION_NOT_ALLOWED"
+	ErrCodeOrderCancelled        = "ORDER_CANCELLED"
+	ErrCodeOrderItemNotFound     = "ORDER_ITEM_NOT_FOUND"
+	ErrCodeInvalidShipment       = "INVALID_SHIPMENT"
+	ErrCodeOverFulfillment       = "FULFILLMENT_EXCEEDS_QUANTITY"
+	ErrCodeShipmentNotFound      = "SHIPMENT_NOT_FOUND"
+	ErrCodeInvalidTracking       = "INVALID_TRACKING"
+	ErrCodeOrderShipped          = "ORDER_ALREADY_SHIPPED"
+	ErrCodeInvalidNote           = "INVALID_ORDER_NOTE"
+	ErrCodeFulfillmentStarted    = "ORDER_FULFILLMENT_STARTED"
+	ErrCodePricingConflict       = "ORDER_PRICING_CONFLICT"
+	ErrCodeInvalidOrderImport    = "INVALID_ORDER_IMPORT"
+	ErrCodeInvalidImportedOrder  = "INVALID_IMPORTED_ORDER"
+	ErrCodeOrderImported         = "ORDER_ALREADY_IMPORTED"
+	ErrCodeInvalidProductIDs     = "INVALID_PRODUCT_IDS"
+	ErrCodeArchiveConflict       = "PRODUCT_ARCHIVE_CONFLICT"
+	ErrCodeInvalidComparison     = "INVALID_PRODUCT_COMPARISON"
+	ErrCodeInvalidProduct        = "INVALID_PRODUCT"
+	ErrCodeInvalidProductSort    = "INVALID_PRODUCT_SORT"
+	ErrCodeInvalidProductImport  = "INVALID_PRODUCT_IMPORT"
+	ErrCodeProductExists         = "PRODUCT_ALREADY_EXISTS"
+	ErrCodeProductInUse          = "PRODUCT_IN_USE"
+	ErrCodePriceUpdateNotAllowed = "PRICE_UPDATE_NOT_ALLOWED"
+	ErrCodeInvalidCouponFile     = "INVALID_COUPON_FILE"
+	ErrCodeCouponFileExists      = "COUPON_FILE_ALREADY_REGISTERED"
+	ErrCodeInvalidTenant         = "INVALID_TENANT"
+	ErrCodeTenantNotFound        = "TENANT_NOT_FOUND"
+	ErrCodeTenantExists          = "TENANT_ALREADY_EXISTS"
+	ErrCodeInvalidCustomer       = "INVALID_CUSTOMER"
+	ErrCodeCustomerNotFound      = "CUSTOMER_NOT_FOUND"
+	ErrCodeCustomerExists        = "CUSTOMER_ALREADY_EXISTS"
+	ErrCodeInvalidImage          = "INVALID_PRODUCT_IMAGE"
+	ErrCodeImageNotUploaded      = "PRODUCT_IMAGE_NOT_UPLOADED"
+	ErrCodeImageAttached         = "PRODUCT_IMAGE_ALREADY_ATTACHED"
+	ErrCodeImageNotFound         = "PRODUCT_IMAGE_NOT_FOUND"
+	ErrCodeInvalidImageOrder     = "INVALID_PRODUCT_IMAGE_ORDER"
+	ErrCodeTooManyImages         = "PRODUCT_IMAGE_LIMIT_REACHED"
+	ErrCodeInvalidCart           = "INVALID_CART"
+	ErrCodeInvalidCartToken      = "INVALID_CART_TOKEN"
+	ErrCodeCartExpired           = "CART_EXPIRED"
+	ErrCodeCartCheckout          = "INVALID_CART_CHECKOUT"
+	ErrCodeInvalidStatusOverride = "INVALID_STATUS_OVERRIDE"
+	ErrCodeInvalidFlashSale      = "INVALID_FLASH_SALE"
+	ErrCodeFlashSaleNotFound     = "FLASH_SALE_NOT_FOUND"
+	ErrCodeReferenceNotFound     = "REFERENCED_RECORD_NOT_FOUND"
+	ErrCodeRecordInUse           = "RECORD_IN_USE"
+	ErrCodeDuplicateRecord       = "DUPLICATE_RECORD"
+	ErrCodeConstraintViolation   = "CONSTRAINT_VIOLATION"
+	ErrCodeUnauthorised          = "UNAUTHORIZED"
+	ErrCodeServiceUnavailable    = "SERVICE_UNAVAILABLE"
+	ErrCodeForbidden             = "FORBIDDEN"
+	ErrCodeInternalError         = "INTERNAL_ERROR"
 )
 
 // Domain errors for business logic
@@ -42,6 +121,102 @@ func NewDomainError(code, message string) *DomainError {
 var (
 	ErrInvalidPromoCode   = NewDomainError(ErrCodeInvalidPromoCode, "Promo code must appear in at least two coupon files")
 	ErrInvalidPromoLength = NewDomainError(ErrCodeInvalidPromoLength, "Promo code must be between 8 and 10 characters")
+	ErrInvalidPromoFormat = NewDomainError(ErrCodeInvalidPromoFormat, "Promo code contains characters that are not allowed")
 	ErrProductNotFound    = NewDomainError(ErrCodeProductNotFound, "One or more products not found")
 	ErrInvalidQuantity    = NewDomainError(ErrCodeInvalidQuantity, "Quantity must be greater than zero")
+
+	ErrCouponRedemptionLimit   = NewDomainError(ErrCodeCouponExhausted, "Promo code has reached its redemption limit")
+	ErrCouponCallerLimit       = NewDomainError(ErrCodeCouponCallerLimit, "Promo code has already been redeemed the maximum number of times by this caller")
+	ErrCouponNotApplicable     = NewDomainError(ErrCodeCouponNotApplicable, "Promo code does not apply to any items in the order")
+	ErrCouponFirstOrderOnly    = NewDomainError(ErrCodeCouponFirstOrderOnly, "Promo code is only valid on a customer's first order")
+	ErrCouponNotForCustomer    = NewDomainError(ErrCodeCouponNotForCustomer, "Promo code is not valid for this customer")
+	ErrCouponSegment           = NewDomainError(ErrCodeCouponSegment, "Promo code is only valid for customers in its segments")
+	ErrCouponValidationTimeout = NewDomainError(ErrCodeCouponTimeout, "Promo code could not be validated in time; try again")
+	ErrCouponsLoading          = NewDomainError(ErrCodeCouponsLoading, "Promo codes are still loading; try again shortly")
+	ErrCouponExpired           = NewDomainError(ErrCodeCouponExpired, "Promo code has expired")
+	ErrCouponMinSubtotal       = NewDomainError(ErrCodeCouponMinSubtotal, "Order subtotal is below the promo code's minimum")
+	ErrInvalidOrderSource      = NewDomainError(ErrCodeInvalidOrderSource, "Order source is not one of the allowed channels")
+	ErrUnknownOrderFields      = NewDomainError(ErrCodeUnknownOrderFields, "Order request contains fields this server does not recognise")
+	ErrIdempotencyConflict     = NewDomainError(ErrCodeIdempotencyConflict, "Idempotency key was already used with a different request")
+
+	ErrPaymentDeclined   = NewDomainError(ErrCodePaymentDeclined, "Payment for the order was declined")
+	ErrInsufficientStock = NewDomainError(ErrCodeInsufficientStock, "One or more items are out of stock")
+	ErrCouponRejected    = NewDomainError(ErrCodeCouponRejected, "Promo code could not be redeemed for the order")
+	ErrOrderStepFailed   = NewDomainError(ErrCodeOrderStepFailed, "A system needed to place the order is unavailable; try again")
+
+	ErrInvalidPrice          = NewDomainError(ErrCodeInvalidPrice, "Price must not be negative and must be a whole number of cents")
+	ErrInvalidSale           = NewDomainError(ErrCodeInvalidSale, "Sale price must be non-negative, a whole number of cents and below the regular price, and the sale must end after it starts and in the future")
+	ErrPriceChangeNotFound   = NewDomainError(ErrCodePriceChangeNotFound, "Price change not found")
+	ErrPriceChangePending    = NewDomainError(ErrCodePriceChangePending, "Product already has a price change awaiting approval")
+	ErrPriceChangeNotPending = NewDomainError(ErrCodePriceChangeNotPending, "Price change has already been decided")
+	ErrSelfApproval          = NewDomainError(ErrCodeSelfApproval, "Price changes must be approved by a different admin")
+
+	ErrUnsupportedCurrency = NewDomainError(ErrCodeUnsupportedCurrency, "Currency is not supported")
+	ErrRatesUnavailable    = NewDomainError(ErrCodeRatesUnavailable, "Exchange rates are temporarily unavailable")
+	ErrInvalidAddress      = NewDomainError(ErrCodeInvalidAddress, "Address country is required")
+
+	ErrInvalidShippingAddress = NewDomainError(ErrCodeShippingAddress, "Shipping address needs a name, first line, city, postal code and two-letter country code, each of at most 200 characters")
+
+	ErrOrderNotFound        = NewDomainError(ErrCodeOrderNotFound, "Order not found")
+	ErrInvalidOrderStatus   = NewDomainError(ErrCodeInvalidOrderStatus, "Order status must be pending, confirmed, cancelled or fulfilled")
+	ErrStatusTransition     = NewDomainError(ErrCodeStatusTransition, "Order cannot move from its current status to the requested status")
+	ErrOrderCancelled       = NewDomainError(ErrCodeOrderCancelled, "Cancelled orders cannot be shipped")
+	ErrOrderItemNotFound    = NewDomainError(ErrCodeOrderItemNotFound, "One or more items do not belong to the order")
+	ErrInvalidShipment      = NewDomainError(ErrCodeInvalidShipment, "Shipment must list each order item once with a positive quantity")
+	ErrOverFulfillment      = NewDomainError(ErrCodeOverFulfillment, "Shipped quantity exceeds the unfulfilled quantity of an item")
+	ErrShipmentNotFound     = NewDomainError(ErrCodeShipmentNotFound, "Shipment not found")
+	ErrInvalidTracking      = NewDomainError(ErrCodeInvalidTracking, "Tracking number is required, and tracking number and carrier must be at most 100 characters")
+	ErrOrderShipped         = NewDomainError(ErrCodeOrderShipped, "Every item of the order has already been shipped")
+	ErrInvalidNote          = NewDomainError(ErrCodeInvalidNote, "Note body is required and must be at most 2000 characters")
+	ErrFulfillmentStarted   = NewDomainError(ErrCodeFulfillmentStarted, "Orders can only be repriced before fulfillment starts")
+	ErrPricingConflict      = NewDomainError(ErrCodePricingConflict, "Order pricing was changed by another request")
+	ErrInvalidOrderImport   = NewDomainError(ErrCodeInvalidOrderImport, "Import file must be CSV with an order_ref,created_at,product_id,quantity,unit_price header row, or JSON Lines")
+	ErrInvalidImportedOrder = NewDomainError(ErrCodeInvalidImportedOrder, "Imported orders need a reference, a creation time in the past, a known status, and items with product IDs and non-negative unit prices, with amounts in whole cents")
+	ErrOrderImported        = NewDomainError(ErrCodeOrderImported, "An order with this reference has already been imported")
+
+	ErrInvalidProductIDs      = NewDomainError(ErrCodeInvalidProductIDs, "Between 1 and 100 non-empty product IDs are required")
+	ErrProductArchiveConflict = NewDomainError(ErrCodeArchiveConflict, "One or more products cannot be archived")
+	ErrInvalidComparison      = NewDomainError(ErrCodeInvalidComparison, "Between 2 and 4 distinct product IDs are required to compare")
+
+	ErrInvalidProduct        = NewDomainError(ErrCodeInvalidProduct, "Product ID, name and category are required and price must be a non-negative whole number of cents")
+	ErrInvalidProductSort    = NewDomainError(ErrCodeInvalidProductSort, "Sort must be name, price or created_at and order asc or desc")
+	ErrInvalidProductImport  = NewDomainError(ErrCodeInvalidProductImport, "Import file must be CSV with an id,name,price,category header row")
+	ErrProductExists         = NewDomainError(ErrCodeProductExists, "A product with this ID already exists")
+	ErrProductInUse          = NewDomainError(ErrCodeProductInUse, "Product is referenced by orders; archive it instead")
+	ErrPriceUpdateNotAllowed = NewDomainError(ErrCodePriceUpdateNotAllowed, "Product prices are changed through the price change endpoint")
+
+	ErrInvalidCouponFile = NewDomainError(ErrCodeInvalidCouponFile, "Coupon file needs a name, a source and a lowercase hex SHA-256 checksum, and cannot be uploaded in the future")
+	ErrCouponFileExists  = NewDomainError(ErrCodeCouponFileExists, "A coupon file with this name and checksum is already registered")
+
+	ErrInvalidTenant  = NewDomainError(ErrCodeInvalidTenant, "Tenant ID must be a lowercase slug of at most 63 characters and name is required")
+	ErrTenantNotFound = NewDomainError(ErrCodeTenantNotFound, "Tenant not found")
+	ErrTenantExists   = NewDomainError(ErrCodeTenantExists, "A tenant with this ID already exists")
+
+	ErrInvalidCustomer  = NewDomainError(ErrCodeInvalidCustomer, "Customer needs a valid email address and a name of at most 200 characters")
+	ErrCustomerNotFound = NewDomainError(ErrCodeCustomerNotFound, "Customer not found")
+	ErrCustomerExists   = NewDomainError(ErrCodeCustomerExists, "A customer with this email is already registered")
+
+	ErrInvalidImage      = NewDomainError(ErrCodeInvalidImage, "Images must be JPEG, PNG, WebP or GIF files within the upload size limit, with alternative text of at most 300 characters")
+	ErrImageNotUploaded  = NewDomainError(ErrCodeImageNotUploaded, "No image of an allowed type and size has been uploaded under this key; upload it with a URL from the uploads endpoint first")
+	ErrImageAttached     = NewDomainError(ErrCodeImageAttached, "This uploaded image is already attached to the product")
+	ErrImageNotFound     = NewDomainError(ErrCodeImageNotFound, "Product image not found")
+	ErrInvalidImageOrder = NewDomainError(ErrCodeInvalidImageOrder, "Image order must list each of the product's images exactly once")
+	ErrTooManyImages     = NewDomainError(ErrCodeTooManyImages, "Product already has the maximum of 20 images; remove one first")
+
+	ErrInvalidCart      = NewDomainError(ErrCodeInvalidCart, "Cart needs 1 to 50 items, each with a product ID and a positive quantity")
+	ErrInvalidCartToken = NewDomainError(ErrCodeInvalidCartToken, "Cart token is malformed, was not issued by this store or belongs to another tenant")
+	ErrCartExpired      = NewDomainError(ErrCodeCartExpired, "Cart token has expired; sign the cart's items again")
+	ErrCartCheckout     = NewDomainError(ErrCodeCartCheckout, "Orders placed from a cart token take their items and promo code from the cart, not the request")
+
+	ErrInvalidStatusOverride = NewDomainError(ErrCodeInvalidStatusOverride, "Status overrides need a valid order status and a reason of at most 500 characters")
+
+	ErrInvalidFlashSale  = NewDomainError(ErrCodeInvalidFlashSale, "Flash sales need a quantity of at least 0")
+	ErrFlashSaleNotFound = NewDomainError(ErrCodeFlashSaleNotFound, "Product is not on a flash sale")
+
+	ErrReferenceNotFound   = NewDomainError(ErrCodeReferenceNotFound, "A record the request refers to does not exist; it may have just been deleted")
+	ErrRecordInUse         = NewDomainError(ErrCodeRecordInUse, "Record is still referenced by other records; remove those first")
+	ErrDuplicateRecord     = NewDomainError(ErrCodeDuplicateRecord, "A record with the same identifying values already exists")
+	ErrConstraintViolation = NewDomainError(ErrCodeConstraintViolation, "Request contains a value that is not allowed; check the request against the API documentation")
+
+	ErrDatabaseUnavailable = NewDomainError(ErrCodeServiceUnavailable, "The database is temporarily unavailable")
 )
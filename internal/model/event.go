@@ -0,0 +1,20 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a domain event waiting in the event outbox to be published.
+// Events are written in the same transaction as the change they describe,
+// so an event is published exactly when the change commits.
+type OutboxEvent struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	Type          string          `json:"type" db:"event_type"`
+	AggregateType string          `json:"aggregateType" db:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregateId" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportedOrder is an order with its items, as read for the warehouse export.
+type ExportedOrder struct {
+	Order
+	Items []OrderItem
+}
+
+// ExportCursor marks the last order an export has read. Orders are read in
+// (UpdatedAt, ID) order, so the next page continues after the cursor.
+type ExportCursor struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
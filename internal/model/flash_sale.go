@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FlashSale is the quantity of an advertised product allocated to a flash
+// sale. Orders for the product are refused once the allocation is reserved.
+type FlashSale struct {
+	ProductID string `json:"productId" db:"product_id"`
+	Quantity  int    `json:"quantity" db:"quantity"`
+
+	// Sold is the quantity reserved by orders and counted in the database.
+	Sold int `json:"sold" db:"sold"`
+
+	// Pending is the quantity reserved on Redis counters and not yet
+	// reconciled into Sold.
+	Pending int `json:"pending" db:"-"`
+
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Remaining returns the quantity still available to orders, which is zero
+// once the allocation is reserved or was lowered below what was sold.
+func (f FlashSale) Remaining() int {
+	return max(f.Quantity-f.Sold-f.Pending, 0)
+}
+
+// FlashSaleRequest sets the quantity of a product allocated to its flash
+// sale.
+type FlashSaleRequest struct {
+	Quantity *int `json:"quantity"`
+}
+
+// Validate checks the quantity is set and not negative. Returns
+// ErrInvalidFlashSale otherwise.
+func (r FlashSaleRequest) Validate() error {
+	if r.Quantity == nil || *r.Quantity < 0 {
+		return ErrInvalidFlashSale
+	}
+	return nil
+}
+
+// FlashSaleReservation is the quantity of a product reserved by an order.
+type FlashSaleReservation struct {
+	OrderID   uuid.UUID `json:"orderId" db:"order_id"`
+	ProductID string    `json:"productId" db:"product_id"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+}
+
+// FlashSaleReservations returns the quantity of each product an order
+// reserves, adding up items of the same product, in the items' order.
+func FlashSaleReservations(orderID uuid.UUID, items []OrderItemRequest) []FlashSaleReservation {
+	reservations := make([]FlashSaleReservation, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.ProductID]; ok {
+			reservations[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(reservations)
+		reservations = append(reservations, FlashSaleReservation{
+			OrderID:   orderID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return reservations
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// moneyScale is the number of Money units in one unit of a currency:
+// thousandths, the smallest minor unit of any currency prices are shown in.
+const moneyScale = 1000
+
+// centsPerUnit is the number of Money units in a cent, the unit amounts are
+// stored in.
+const centsPerUnit = moneyScale / 100
+
+// Money is an exact amount of a currency, counted in thousandths of its
+// major unit so that amounts, totals and discounts never pick up binary
+// floating point error. Amounts are stored in the database as integer cents
+// and encoded in JSON as decimal numbers, e.g. 10.99.
+type Money int64
+
+// NewMoney returns amount as Money, rounded half away from zero to the
+// nearest thousandth. It is for amounts that are computed as floats, such as
+// seeded or generated prices; parse user input with ParseMoney.
+func NewMoney(amount float64) Money {
+	return Money(math.Round(amount * moneyScale))
+}
+
+// MoneyFromCents returns the amount of cents as Money.
+func MoneyFromCents(cents int64) Money {
+	return Money(cents * centsPerUnit)
+}
+
+// ParseMoney parses a decimal amount such as "10.99" or "-2.5". Amounts
+// finer than thousandths, exponents and other formatting are refused.
+func ParseMoney(s string) (Money, error) {
+	invalid := fmt.Errorf("invalid amount %q", s)
+
+	digits := s
+	negative := strings.HasPrefix(digits, "-")
+	if negative {
+		digits = digits[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+	if whole == "" || (hasFraction && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, invalid
+	}
+	if fraction = strings.TrimRight(fraction, "0"); len(fraction) > 3 {
+		return 0, invalid
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/moneyScale {
+		return 0, invalid
+	}
+	fraction += strings.Repeat("0", 3-len(fraction))
+	thousandths, _ := strconv.ParseInt(fraction, 10, 64)
+
+	m := Money(units*moneyScale + thousandths)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// WholeCents reports whether m is a whole number of cents, the precision
+// amounts are stored at. Amounts finer than that, such as 9.995, are valid
+// in computations and responses but must be rejected before they are stored.
+func (m Money) WholeCents() bool {
+	return m%centsPerUnit == 0
+}
+
+// Cents returns m in cents, rounded half away from zero.
+func (m Money) Cents() int64 {
+	return int64(m.round(centsPerUnit)) / centsPerUnit
+}
+
+// Mul returns m multiplied by n, e.g. a unit price by a quantity.
+func (m Money) Mul(n int) Money {
+	return m * Money(n)
+}
+
+// Percent returns percent per cent of m, rounded half away from zero to the
+// cent.
+func (m Money) Percent(percent float64) Money {
+	return MoneyFromCents(int64(math.Round(float64(m) * percent / 100 / centsPerUnit)))
+}
+
+// Convert converts m at rate, rounding the result to the minor unit of
+// currency, the currency converted to.
+func (m Money) Convert(rate float64, currency string) Money {
+	unit := math.Pow10(3 - CurrencyExponent(currency))
+	return Money(math.Round(float64(m)*rate/unit) * unit)
+}
+
+// round returns m rounded half away from zero to a multiple of unit.
+func (m Money) round(unit Money) Money {
+	half := unit / 2
+	if m < 0 {
+		return -((-m + half) / unit * unit)
+	}
+	return (m + half) / unit * unit
+}
+
+// Float64 returns m as a float, for reporting only; do arithmetic on Money.
+func (m Money) Float64() float64 {
+	return float64(m) / moneyScale
+}
+
+// String formats m as a decimal amount without trailing zeros, e.g. "10.99",
+// "10" or "2.217".
+func (m Money) String() string {
+	abs := uint64(m)
+	sign := ""
+	if m < 0 {
+		abs = uint64(-m)
+		sign = "-"
+	}
+	whole, fraction := abs/moneyScale, abs%moneyScale
+	if fraction == 0 {
+		return sign + strconv.FormatUint(whole, 10)
+	}
+	return sign + strconv.FormatUint(whole, 10) + "." + strings.TrimRight(fmt.Sprintf("%03d", fraction), "0")
+}
+
+// MarshalJSON encodes m as a JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number with at most three decimals. null
+// leaves m unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	parsed, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores m as integer cents. Amounts finer than a cent are refused
+// rather than rounded, so a stored amount always reads back unchanged.
+func (m Money) Value() (driver.Value, error) {
+	if !m.WholeCents() {
+		return nil, fmt.Errorf("amount %s is not a whole number of cents", m)
+	}
+	return m.Cents(), nil
+}
+
+// Scan reads an amount stored as integer cents.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*m = MoneyFromCents(v)
+		return nil
+	case nil:
+		return errors.New("cannot scan NULL into Money")
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
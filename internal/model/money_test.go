@@ -0,0 +1,158 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Money
+		wantErr  bool
+	}{
+		{input: "10.99", expected: MoneyFromCents(1099)},
+		{input: "0.29", expected: MoneyFromCents(29)},
+		{input: "10", expected: MoneyFromCents(1000)},
+		{input: "2.217", expected: Money(2217)},
+		{input: "-2.5", expected: MoneyFromCents(-250)},
+		{input: "4.5000", expected: MoneyFromCents(450)},
+		{input: "10.9999", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "10.", wantErr: true},
+		{input: ".5", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "10.99", MoneyFromCents(1099).String())
+	assert.Equal(t, "10", MoneyFromCents(1000).String())
+	assert.Equal(t, "0.5", MoneyFromCents(50).String())
+	assert.Equal(t, "2.217", Money(2217).String())
+	assert.Equal(t, "-0.05", MoneyFromCents(-5).String())
+	assert.Equal(t, "0", Money(0).String())
+}
+
+func TestMoney_Cents(t *testing.T) {
+	// 0.29 * 100 is 28.999999999999996 in binary floating point
+	assert.Equal(t, int64(29), NewMoney(0.29).Cents())
+	assert.Equal(t, int64(1099), NewMoney(10.99).Cents())
+	assert.Equal(t, int64(222), Money(2217).Cents())
+	assert.Equal(t, int64(-222), Money(-2217).Cents())
+	assert.Equal(t, int64(221), Money(2214).Cents())
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		rate     float64
+		currency string
+		expected Money
+	}{
+		{name: "Rounds to cents", amount: NewMoney(10.99), rate: 0.6617, currency: "USD", expected: NewMoney(7.27)},
+		{name: "Currency without minor unit", amount: NewMoney(10.99), rate: 96.31, currency: "JPY", expected: NewMoney(1058)},
+		{name: "Currency with three decimals", amount: NewMoney(10.99), rate: 0.20173, currency: "KWD", expected: NewMoney(2.217)},
+		{name: "Identity rate", amount: NewMoney(10.99), rate: 1, currency: "AUD", expected: NewMoney(10.99)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.amount.Convert(tt.rate, tt.currency))
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	// Summing 0.1 ten times as float64 gives 0.9999999999999999
+	var total Money
+	for range 10 {
+		total += NewMoney(0.1)
+	}
+	assert.Equal(t, NewMoney(1), total)
+
+	assert.Equal(t, NewMoney(32.97), NewMoney(10.99).Mul(3))
+	assert.Equal(t, NewMoney(1.65), NewMoney(10.99).Percent(15))
+	assert.Equal(t, NewMoney(0.02), NewMoney(0.15).Percent(10))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var snapshot struct {
+		Price    Money  `json:"price"`
+		Discount *Money `json:"discount,omitempty"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 4.50, "discount": 0.29}`), &snapshot))
+	assert.Equal(t, MoneyFromCents(450), snapshot.Price)
+	require.NotNil(t, snapshot.Discount)
+	assert.Equal(t, MoneyFromCents(29), *snapshot.Discount)
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 4.5, "discount": 0.29}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": "4.50"}`), &snapshot))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 4.5001}`), &snapshot))
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan(int64(1099)))
+	assert.Equal(t, NewMoney(10.99), m)
+
+	value, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1099), value)
+
+	assert.Error(t, m.Scan(nil))
+	assert.Error(t, m.Scan("10.99"))
+}
+
+func TestMoney_StorageRoundTrip(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "10.99"},
+		{input: "0.29"},
+		{input: "-2.5"},
+		{input: "1000000"},
+		{input: "9.995", wantErr: true},
+		{input: "2.217", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, err := ParseMoney(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, !tt.wantErr, parsed.WholeCents())
+
+			value, err := parsed.Value()
+			if tt.wantErr {
+				assert.Error(t, err, "amounts finer than a cent must not be rounded on store")
+				return
+			}
+			require.NoError(t, err)
+
+			var stored Money
+			require.NoError(t, stored.Scan(value))
+			assert.Equal(t, parsed, stored)
+		})
+	}
+}
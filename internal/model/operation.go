@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperationStatus is the state of an asynchronous order creation.
+type OperationStatus string
+
+// Operation statuses. Succeeded and failed are terminal.
+const (
+	OperationStatusPending   OperationStatus = "pending"
+	OperationStatusSucceeded OperationStatus = "succeeded"
+	OperationStatusFailed    OperationStatus = "failed"
+)
+
+// Operation tracks an order accepted for asynchronous creation. OrderID is
+// set once the order has been created, and Error once creation has failed.
+type Operation struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	Status    OperationStatus `json:"status" db:"status"`
+	OrderID   *uuid.UUID      `json:"orderId,omitempty" db:"order_id"`
+	Error     *OperationError `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// Done reports whether the operation has reached a terminal status.
+func (o *Operation) Done() bool {
+	return o.Status != OperationStatusPending
+}
+
+// OperationError explains why an asynchronous order creation failed, with
+// the error code a synchronous request would have been refused with.
+// Retryable failures, such as timeouts, may succeed if the order is
+// submitted again.
+type OperationError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+// retryableErrorCodes are the error codes of failures that may succeed when
+// the request is retried.
+var retryableErrorCodes = map[string]bool{
+	ErrCodeCouponTimeout:      true,
+	ErrCodeCouponsLoading:     true,
+	ErrCodeOrderStepFailed:    true,
+	ErrCodeServiceUnavailable: true,
+	ErrCodeInternalError:      true,
+}
+
+// NewOperationError describes err as the failure of an operation. Errors
+// other than domain errors are reported as retryable internal errors
+// without exposing their details.
+func NewOperationError(err error) *OperationError {
+	code, message := ErrCodeInternalError, "Order could not be created"
+	if domainErr, ok := err.(*DomainError); ok {
+		code, message = domainErr.Code, domainErr.Message
+	}
+	return &OperationError{Code: code, Message: message, Retryable: retryableErrorCodes[code]}
+}
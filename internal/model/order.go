@@ -1,31 +1,202 @@
 package model
 
 import (
+	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// Order represents a customer order.
+// OrderStatus represents where an order is in its lifecycle.
+type OrderStatus string
+
+// Order statuses.
+const (
+	// OrderStatusPending marks a newly placed order.
+	OrderStatusPending OrderStatus = "pending"
+
+	// OrderStatusConfirmed marks an order accepted for fulfillment.
+	OrderStatusConfirmed OrderStatus = "confirmed"
+
+	// OrderStatusCancelled marks an order that will not be fulfilled.
+	OrderStatusCancelled OrderStatus = "cancelled"
+
+	// OrderStatusFulfilled marks an order that has been delivered in full.
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+)
+
+// orderStatusTransitions lists the statuses each status may move to.
+// Cancelled and fulfilled orders are final.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusFulfilled, OrderStatusCancelled},
+}
+
+// Valid reports whether s is a known order status.
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusConfirmed, OrderStatusCancelled, OrderStatusFulfilled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an order may move from s to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order represents a customer order. CouponWarning is set when the order's
+// coupon code was accepted without being validated (see
+// OrderResponse.CouponWarning). CustomerID is nil for anonymous orders, and
+// ShippingAddress for orders placed without one.
 type Order struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	CouponCode *string   `json:"couponCode,omitempty" db:"coupon_code"`
-	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+	ID              uuid.UUID   `json:"id" db:"id"`
+	CustomerID      *uuid.UUID  `json:"customerId,omitempty" db:"customer_id"`
+	CouponCode      *string     `json:"couponCode,omitempty" db:"coupon_code"`
+	CouponWarning   *string     `json:"couponWarning,omitempty" db:"coupon_warning"`
+	Source          *string     `json:"source,omitempty" db:"source"`
+	Status          OrderStatus `json:"status" db:"status"`
+	Metadata        Metadata    `json:"metadata,omitempty" db:"metadata"`
+	ShippingAddress *Address    `json:"shippingAddress,omitempty" db:"shipping_address"`
+	Subtotal        *Money      `json:"subtotal,omitempty" db:"subtotal"`
+	Discount        *Money      `json:"discount,omitempty" db:"discount"`
+	Total           *Money      `json:"total,omitempty" db:"total"`
+	CreatedAt       time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time   `json:"updatedAt" db:"updated_at"`
+
+	// Currency is set when the totals were converted out of the catalogue
+	// currency for display.
+	Currency string `json:"currency,omitempty" db:"-"`
+}
+
+// InCurrency returns o with its totals converted to currency at rate.
+func (o Order) InCurrency(currency string, rate float64) Order {
+	o.Subtotal = convertTotal(o.Subtotal, rate, currency)
+	o.Discount = convertTotal(o.Discount, rate, currency)
+	o.Total = convertTotal(o.Total, rate, currency)
+	o.Currency = currency
+	return o
+}
+
+// convertTotal converts an order total that may not have been recorded.
+func convertTotal(amount *Money, rate float64, currency string) *Money {
+	if amount == nil {
+		return nil
+	}
+	converted := amount.Convert(rate, currency)
+	return &converted
 }
 
 // OrderItem represents a line item in an order.
 type OrderItem struct {
-	ID        uuid.UUID `json:"-" db:"id"`
-	OrderID   uuid.UUID `json:"-" db:"order_id"`
-	ProductID string    `json:"productId" db:"product_id"`
-	Quantity  int       `json:"quantity" db:"quantity"`
+	ID                uuid.UUID        `json:"id" db:"id"`
+	OrderID           uuid.UUID        `json:"-" db:"order_id"`
+	ProductID         string           `json:"productId" db:"product_id"`
+	Quantity          int              `json:"quantity" db:"quantity"`
+	FulfilledQuantity int              `json:"fulfilledQuantity" db:"fulfilled_quantity"`
+	Product           *ProductSnapshot `json:"product,omitempty" db:"product_snapshot"`
+}
+
+// ProductSnapshot captures a product as it was when an order item was created.
+type ProductSnapshot struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Price    Money  `json:"price"`
+	OnSale   bool   `json:"onSale,omitempty"`
 }
 
+// Metadata holds order request fields this server version does not recognise,
+// keyed by field name with their raw JSON values.
+type Metadata map[string]json.RawMessage
+
 // OrderRequest represents the request payload for creating an order.
 type OrderRequest struct {
+	// CustomerID attaches the order to a registered customer's order
+	// history. Orders without one are anonymous.
+	CustomerID *uuid.UUID `json:"customerId,omitempty"`
+
 	CouponCode *string            `json:"couponCode,omitempty"`
+	Source     *string            `json:"source,omitempty"`
 	Items      []OrderItemRequest `json:"items"`
+
+	// ShippingAddress is where the order is delivered. Orders collected in
+	// person, e.g. at a point of sale, need none.
+	ShippingAddress *Address `json:"shippingAddress,omitempty"`
+
+	// CartToken places the order from a signed storefront cart, which
+	// supplies the items and promo code in place of Items and CouponCode.
+	CartToken string `json:"cartToken,omitempty"`
+
+	// Metadata collects unrecognised top-level fields while decoding, so
+	// requests from newer clients can be preserved or rejected predictably.
+	Metadata Metadata `json:"-"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header. Retried
+	// requests with the same key return the original order.
+	IdempotencyKey string `json:"-"`
+
+	// Caller is the authenticated subject placing the order, against whom
+	// per-caller coupon limits are counted.
+	Caller string `json:"-"`
+
+	// Delegation is set when an admin places the order on behalf of Caller.
+	Delegation *Delegation `json:"-"`
+}
+
+// orderRequestFields lists the top-level fields OrderRequest recognises.
+// Keys are lower case because encoding/json matches field names case-insensitively.
+var orderRequestFields = map[string]bool{
+	"customerid":      true,
+	"couponcode":      true,
+	"source":          true,
+	"items":           true,
+	"shippingaddress": true,
+	"carttoken":       true,
+}
+
+// UnmarshalJSON decodes an order request, collecting unrecognised top-level
+// fields into Metadata instead of dropping them.
+func (r *OrderRequest) UnmarshalJSON(data []byte) error {
+	type plain OrderRequest
+	var known plain
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, value := range fields {
+		if orderRequestFields[strings.ToLower(name)] {
+			continue
+		}
+		if known.Metadata == nil {
+			known.Metadata = make(Metadata)
+		}
+		known.Metadata[name] = value
+	}
+
+	*r = OrderRequest(known)
+	return nil
+}
+
+// UnknownFields returns the names of unrecognised fields in sorted order.
+func (r *OrderRequest) UnknownFields() []string {
+	names := make([]string, 0, len(r.Metadata))
+	for name := range r.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // OrderItemRequest represents a single item in an order request.
@@ -36,7 +207,125 @@ type OrderItemRequest struct {
 
 // OrderResponse represents the response payload for an order.
 type OrderResponse struct {
-	ID       uuid.UUID   `json:"id"`
-	Items    []OrderItem `json:"items"`
-	Products []Product   `json:"products"`
+	ID                uuid.UUID         `json:"id"`
+	CustomerID        *uuid.UUID        `json:"customerId,omitempty"`
+	Source            *string           `json:"source,omitempty"`
+	Status            OrderStatus       `json:"status"`
+	Metadata          Metadata          `json:"metadata,omitempty"`
+	ShippingAddress   *Address          `json:"shippingAddress,omitempty"`
+	FulfillmentStatus FulfillmentStatus `json:"fulfillmentStatus"`
+	Items             []OrderItem       `json:"items"`
+	Products          []Product         `json:"products"`
+	Pricing           *PriceBreakdown   `json:"pricing,omitempty"`
+
+	// Totals recorded when the order was placed. Nil for orders created
+	// before totals were recorded or without a pricing engine.
+	Subtotal *Money `json:"subtotal,omitempty"`
+	Discount *Money `json:"discount,omitempty"`
+	Total    *Money `json:"total,omitempty"`
+
+	// CouponWarning is the error code of the validation failure the
+	// order's coupon code was accepted despite, flagging the order for
+	// review. Nil when the code was validated or the order has none.
+	CouponWarning *string `json:"couponWarning,omitempty"`
+
+	// Currency is set when the prices were converted out of the catalogue
+	// currency for display.
+	Currency string `json:"currency,omitempty"`
+
+	// Replayed is set when the order was created by an earlier request with
+	// the same idempotency key.
+	Replayed bool `json:"-"`
+}
+
+// InCurrency returns r with its prices, those of its items' snapshots and
+// products included, converted to currency at rate.
+func (r OrderResponse) InCurrency(currency string, rate float64) OrderResponse {
+	items := make([]OrderItem, len(r.Items))
+	for i, item := range r.Items {
+		if item.Product != nil {
+			snapshot := *item.Product
+			snapshot.Price = snapshot.Price.Convert(rate, currency)
+			item.Product = &snapshot
+		}
+		items[i] = item
+	}
+	r.Items = items
+
+	products := make([]Product, len(r.Products))
+	for i, product := range r.Products {
+		products[i] = product.InCurrency(currency, rate)
+	}
+	r.Products = products
+
+	if r.Pricing != nil {
+		pricing := r.Pricing.InCurrency(currency, rate)
+		r.Pricing = &pricing
+	}
+	r.Subtotal = convertTotal(r.Subtotal, rate, currency)
+	r.Discount = convertTotal(r.Discount, rate, currency)
+	r.Total = convertTotal(r.Total, rate, currency)
+	r.Currency = currency
+	return r
+}
+
+// OrderStatusRequest represents the request payload for changing an order's status.
+type OrderStatusRequest struct {
+	Status OrderStatus `json:"status"`
+}
+
+// MaxOverrideReasonLength bounds the reason recorded for a status override.
+const MaxOverrideReasonLength = 500
+
+// StatusOverride moves an order to a status outside the order status
+// lifecycle, such as reopening an order cancelled by mistake. The reason is
+// recorded in the audit log.
+type StatusOverride struct {
+	Status OrderStatus `json:"status"`
+	Reason string      `json:"reason"`
+}
+
+// Validate checks the override names a known status and gives a reason.
+// Returns ErrInvalidStatusOverride otherwise.
+func (o StatusOverride) Validate() error {
+	reason := strings.TrimSpace(o.Reason)
+	if !o.Status.Valid() || reason == "" || len(reason) > MaxOverrideReasonLength {
+		return ErrInvalidStatusOverride
+	}
+	return nil
+}
+
+// CouponRedemption records a coupon code redeemed on an order.
+type CouponRedemption struct {
+	Code       string    `json:"code" db:"code"`
+	OrderID    uuid.UUID `json:"orderId" db:"order_id"`
+	RedeemedBy string    `json:"redeemedBy" db:"redeemed_by"`
+	RedeemedAt time.Time `json:"redeemedAt" db:"redeemed_at"`
+}
+
+// IdempotencyKey records the order created for a client-supplied idempotency
+// key. Keys are scoped to the tenant and caller that used them.
+type IdempotencyKey struct {
+	Key         string    `json:"key" db:"idempotency_key"`
+	Caller      string    `json:"caller" db:"caller"`
+	RequestHash string    `json:"requestHash" db:"request_hash"`
+	OrderID     uuid.UUID `json:"orderId" db:"order_id"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// OrderFilter represents filtering and pagination options for listing orders.
+type OrderFilter struct {
+	Source string
+	// CustomerID restricts the results to the customer's orders.
+	CustomerID *uuid.UUID
+	// ProductID restricts the results to orders containing the product.
+	ProductID string
+	Limit     int
+	Offset    int
+}
+
+// SourceCount represents the number of orders attributed to a source.
+type SourceCount struct {
+	Source string `json:"source"`
+	Orders int    `json:"orders"`
 }
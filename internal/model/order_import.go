@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// OrderImportFormat is the file format of an order import.
+type OrderImportFormat string
+
+// Order import formats.
+const (
+	// OrderImportCSV holds one row per order item. Rows of the same order
+	// are adjacent and share its reference.
+	OrderImportCSV OrderImportFormat = "csv"
+
+	// OrderImportJSONL holds one OrderImport JSON object per line.
+	OrderImportJSONL OrderImportFormat = "jsonl"
+)
+
+// Valid reports whether f is a known import format.
+func (f OrderImportFormat) Valid() bool {
+	return f == OrderImportCSV || f == OrderImportJSONL
+}
+
+// OrderImport is a historical order migrated from another platform. Ref is
+// the order's reference on that platform, unique per tenant, and CreatedAt
+// is when it was originally placed. Items are charged at their recorded unit
+// prices and Discount is taken off their total.
+type OrderImport struct {
+	Ref        string            `json:"ref"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	Status     OrderStatus       `json:"status,omitempty"`
+	Source     *string           `json:"source,omitempty"`
+	CouponCode *string           `json:"couponCode,omitempty"`
+	Discount   Money             `json:"discount,omitempty"`
+	Items      []OrderImportItem `json:"items"`
+}
+
+// OrderImportItem is an item of an imported order.
+type OrderImportItem struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unitPrice"`
+}
+
+// Validate checks the order has a reference, was placed before now, has a
+// known status and items with product IDs, positive quantities and
+// non-negative unit prices, and that its discount is non-negative. Amounts
+// must be whole cents. Returns
+// ErrInvalidQuantity for a quantity below one and ErrInvalidImportedOrder
+// otherwise.
+func (o OrderImport) Validate(now time.Time) error {
+	if strings.TrimSpace(o.Ref) == "" || o.CreatedAt.IsZero() || o.CreatedAt.After(now) || !o.Status.Valid() {
+		return ErrInvalidImportedOrder
+	}
+	if o.Discount < 0 || !o.Discount.WholeCents() || len(o.Items) == 0 {
+		return ErrInvalidImportedOrder
+	}
+	for _, item := range o.Items {
+		if item.ProductID == "" || item.UnitPrice < 0 || !item.UnitPrice.WholeCents() {
+			return ErrInvalidImportedOrder
+		}
+		if item.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+	}
+	return nil
+}
+
+// OrderImportResult reports the outcome of an order import. Valid orders are
+// imported; orders with errors are skipped and reported.
+type OrderImportResult struct {
+	Imported int64              `json:"imported"`
+	Errors   []OrderImportError `json:"errors"`
+}
+
+// OrderImportError explains why an order was not imported. Row is the line
+// of the file the error was found on, counting a CSV header as line 1, and
+// Code is the error code of the failure.
+type OrderImportError struct {
+	Row   int    `json:"row"`
+	Ref   string `json:"ref,omitempty"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
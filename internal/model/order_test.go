@@ -0,0 +1,114 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRequest_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedSource  string
+		expectedItems   int
+		expectedUnknown []string
+		expectErr       bool
+	}{
+		{
+			name:          "Known fields only",
+			body:          `{"source":"web","items":[{"productId":"P001","quantity":1}]}`,
+			expectedItems: 1, expectedSource: "web",
+			expectedUnknown: []string{},
+		},
+		{
+			name:            "Unknown fields are collected",
+			body:            `{"items":[{"productId":"P001","quantity":1}],"giftWrap":true,"delivery":{"window":"am"}}`,
+			expectedItems:   1,
+			expectedUnknown: []string{"delivery", "giftWrap"},
+		},
+		{
+			name:            "Field names match case-insensitively",
+			body:            `{"Source":"web","ITEMS":[{"productId":"P001","quantity":1}]}`,
+			expectedItems:   1,
+			expectedSource:  "web",
+			expectedUnknown: []string{},
+		},
+		{
+			name:      "Invalid JSON",
+			body:      `{"items":`,
+			expectErr: true,
+		},
+		{
+			name:      "Wrong type for known field",
+			body:      `{"items":"P001"}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req OrderRequest
+			err := json.Unmarshal([]byte(tt.body), &req)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, req.Items, tt.expectedItems)
+			if tt.expectedSource != "" {
+				require.NotNil(t, req.Source)
+				assert.Equal(t, tt.expectedSource, *req.Source)
+			}
+			assert.Equal(t, tt.expectedUnknown, req.UnknownFields())
+		})
+	}
+}
+
+func TestOrderRequest_MetadataRoundTrip(t *testing.T) {
+	var req OrderRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"items":[],"delivery":{"window":"am"}}`), &req))
+
+	// Unknown fields are not echoed when the request itself is encoded
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "delivery")
+
+	// They surface verbatim as order metadata
+	data, err = json.Marshal(OrderResponse{Metadata: req.Metadata})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"metadata":{"delivery":{"window":"am"}}`)
+}
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from    OrderStatus
+		to      OrderStatus
+		allowed bool
+	}{
+		{OrderStatusPending, OrderStatusConfirmed, true},
+		{OrderStatusPending, OrderStatusCancelled, true},
+		{OrderStatusPending, OrderStatusFulfilled, false},
+		{OrderStatusConfirmed, OrderStatusFulfilled, true},
+		{OrderStatusConfirmed, OrderStatusCancelled, true},
+		{OrderStatusConfirmed, OrderStatusPending, false},
+		{OrderStatusCancelled, OrderStatusPending, false},
+		{OrderStatusCancelled, OrderStatusConfirmed, false},
+		{OrderStatusFulfilled, OrderStatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOrderStatus_Valid(t *testing.T) {
+	assert.True(t, OrderStatusFulfilled.Valid())
+	assert.False(t, OrderStatus("shipped").Valid())
+	assert.False(t, OrderStatus("").Valid())
+}
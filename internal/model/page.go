@@ -0,0 +1,10 @@
+package model
+
+// Page describes the slice of a collection returned by a paginated list request.
+type Page struct {
+	Limit  int
+	Offset int
+
+	// Total is the number of items in the collection across all pages.
+	Total int
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChangeStatus represents the state of a product price change.
+type PriceChangeStatus string
+
+// Price change statuses.
+const (
+	// PriceChangeStatusPending marks a change awaiting approval by a second admin.
+	PriceChangeStatusPending PriceChangeStatus = "pending"
+
+	// PriceChangeStatusApproved marks a pending change that was approved and applied.
+	PriceChangeStatusApproved PriceChangeStatus = "approved"
+
+	// PriceChangeStatusRejected marks a pending change that was rejected.
+	PriceChangeStatusRejected PriceChangeStatus = "rejected"
+
+	// PriceChangeStatusApplied marks a change below the approval threshold that was applied immediately.
+	PriceChangeStatusApplied PriceChangeStatus = "applied"
+)
+
+// PriceChange represents a requested change to a product's price. When Sale
+// is set the change schedules that sale, at NewPrice, instead of changing the
+// regular price.
+type PriceChange struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	ProductID   string            `json:"productId" db:"product_id"`
+	OldPrice    Money             `json:"oldPrice" db:"old_price"`
+	NewPrice    Money             `json:"newPrice" db:"new_price"`
+	Sale        *ProductSale      `json:"sale,omitempty" db:"-"`
+	Status      PriceChangeStatus `json:"status" db:"status"`
+	RequestedBy string            `json:"requestedBy" db:"requested_by"`
+	DecidedBy   *string           `json:"decidedBy,omitempty" db:"decided_by"`
+	CreatedAt   time.Time         `json:"createdAt" db:"created_at"`
+	DecidedAt   *time.Time        `json:"decidedAt,omitempty" db:"decided_at"`
+}
+
+// PriceUpdateRequest represents the request payload for changing a product's price.
+type PriceUpdateRequest struct {
+	Price *Money `json:"price"`
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRequest represents the request payload for a price preview.
+type PricingRequest struct {
+	Items      []OrderItemRequest `json:"items"`
+	CouponCode *string            `json:"couponCode,omitempty"`
+	Currency   string             `json:"currency,omitempty"`
+	Address    *Address           `json:"address,omitempty"`
+}
+
+// PriceBreakdown represents the fully computed price of a set of items.
+// FreeShipping is set when a coupon waived the shipping charge.
+type PriceBreakdown struct {
+	Currency     string      `json:"currency"`
+	Lines        []PriceLine `json:"lines"`
+	Subtotal     Money       `json:"subtotal"`
+	Discount     Money       `json:"discount"`
+	Shipping     Money       `json:"shipping"`
+	FreeShipping bool        `json:"freeShipping,omitempty"`
+	Total        Money       `json:"total"`
+}
+
+// InCurrency returns b with its amounts converted to currency at rate.
+func (b PriceBreakdown) InCurrency(currency string, rate float64) PriceBreakdown {
+	lines := make([]PriceLine, len(b.Lines))
+	for i, line := range b.Lines {
+		line.UnitPrice = line.UnitPrice.Convert(rate, currency)
+		line.LineTotal = line.LineTotal.Convert(rate, currency)
+		lines[i] = line
+	}
+	b.Lines = lines
+	b.Subtotal = b.Subtotal.Convert(rate, currency)
+	b.Discount = b.Discount.Convert(rate, currency)
+	b.Shipping = b.Shipping.Convert(rate, currency)
+	b.Total = b.Total.Convert(rate, currency)
+	b.Currency = currency
+	return b
+}
+
+// PriceLine represents the price of a single item in a breakdown.
+type PriceLine struct {
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unitPrice"`
+	LineTotal Money  `json:"lineTotal"`
+
+	// OnSale is set when UnitPrice is the product's sale price.
+	OnSale bool `json:"onSale,omitempty"`
+}
+
+// CouponDiscount represents the discount a coupon code grants. At most one of
+// PercentOff and AmountOff is set; a coupon with neither grants free shipping
+// only. When Categories is non-empty the discount applies only to items in
+// those product categories. FirstOrderOnly limits the discount to a
+// customer's first order, CustomerIDs to the listed customers and Segments to
+// customers in any of the listed segments. The code is refused from ExpiresAt
+// onwards, and on orders whose subtotal, before discounts, is below
+// MinSubtotal.
+type CouponDiscount struct {
+	Code           string        `json:"code" db:"code"`
+	PercentOff     *float64      `json:"percentOff,omitempty" db:"percent_off"`
+	AmountOff      *Money        `json:"amountOff,omitempty" db:"amount_off"`
+	Categories     []string      `json:"categories,omitempty" db:"categories"`
+	FirstOrderOnly bool          `json:"firstOrderOnly,omitempty" db:"first_order_only"`
+	CustomerIDs    []uuid.UUID   `json:"customerIds,omitempty" db:"customer_ids"`
+	Segments       []string      `json:"segments,omitempty" db:"segments"`
+	ExpiresAt      *time.Time    `json:"expiresAt,omitempty" db:"expires_at"`
+	MinSubtotal    Money         `json:"minSubtotal,omitempty" db:"min_subtotal"`
+	FreeShipping   *FreeShipping `json:"freeShipping,omitempty"`
+}
+
+// Restricted reports whether the discount is limited to some customers, so
+// who is ordering must be checked before it is applied.
+func (d *CouponDiscount) Restricted() bool {
+	return d != nil && (d.FirstOrderOnly || len(d.CustomerIDs) > 0 || len(d.Segments) > 0)
+}
+
+// FreeShipping describes when a coupon waives the shipping charge. Zero
+// values impose no condition.
+type FreeShipping struct {
+	// MinSubtotal is the subtotal, before discounts, the order must reach.
+	MinSubtotal Money `json:"minSubtotal,omitempty" db:"free_shipping_min_subtotal"`
+
+	// Countries limits free shipping to deliveries to these ISO 3166-1 alpha-2 countries.
+	Countries []string `json:"countries,omitempty" db:"free_shipping_countries"`
+}
+
+// Qualifies reports whether a delivery to address with the given subtotal
+// meets the free shipping conditions.
+func (f *FreeShipping) Qualifies(subtotal Money, address *Address) bool {
+	if subtotal < f.MinSubtotal {
+		return false
+	}
+	if len(f.Countries) == 0 {
+		return true
+	}
+	if address == nil {
+		return false
+	}
+	return slices.ContainsFunc(f.Countries, func(country string) bool {
+		return strings.EqualFold(country, address.Country)
+	})
+}
+
+// Expired reports whether the coupon has expired at the given time.
+func (d *CouponDiscount) Expired(now time.Time) bool {
+	return d.ExpiresAt != nil && !now.Before(*d.ExpiresAt)
+}
+
+// AppliesTo reports whether the discount applies to products in a category.
+func (d *CouponDiscount) AppliesTo(category string) bool {
+	return len(d.Categories) == 0 || slices.Contains(d.Categories, category)
+}
+
+// ProductCouponPreview reports whether a coupon code could apply to a single
+// product and the price of one unit with it applied. Reason holds the error
+// code explaining why an inapplicable coupon was refused.
+type ProductCouponPreview struct {
+	Code            string `json:"code"`
+	Applicable      bool   `json:"applicable"`
+	Reason          string `json:"reason,omitempty"`
+	Price           Money  `json:"price"`
+	Discount        Money  `json:"discount"`
+	DiscountedPrice Money  `json:"discountedPrice"`
+}
+
+// InCurrency returns p with its amounts converted to currency at rate.
+func (p ProductCouponPreview) InCurrency(currency string, rate float64) ProductCouponPreview {
+	p.Price = p.Price.Convert(rate, currency)
+	p.Discount = p.Discount.Convert(rate, currency)
+	p.DiscountedPrice = p.DiscountedPrice.Convert(rate, currency)
+	return p
+}
+
+// CouponValidationRequest is the body of a standalone coupon validation.
+type CouponValidationRequest struct {
+	Code string `json:"code"`
+}
+
+// CouponValidation reports whether a promo code is valid. Reason holds the
+// error code explaining why an invalid code was refused. MatchCount is the
+// number of coupon files found to contain the code, counting each file's
+// weight. The lookup stops as soon
+// as the outcome is known, so it is a lower bound, and codes refused for
+// their format or length are not looked up at all.
+type CouponValidation struct {
+	Code       string `json:"code"`
+	Valid      bool   `json:"valid"`
+	Reason     string `json:"reason,omitempty"`
+	MatchCount int    `json:"matchCount"`
+}
+
+// ProductDetail is a product together with an optional coupon preview.
+type ProductDetail struct {
+	Product
+	CouponPreview *ProductCouponPreview `json:"couponPreview,omitempty"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderPricingVersion records one pricing of an order. Version 1 is the
+// pricing the order was placed with and each recalculation adds the next
+// version; the order's totals always match its latest version. Version 1 is
+// recorded from the order's totals when the order is first recalculated, so
+// it carries no breakdown.
+type OrderPricingVersion struct {
+	OrderID   uuid.UUID       `json:"orderId" db:"order_id"`
+	Version   int             `json:"version" db:"version"`
+	Subtotal  *Money          `json:"subtotal,omitempty" db:"subtotal"`
+	Discount  *Money          `json:"discount,omitempty" db:"discount"`
+	Total     *Money          `json:"total,omitempty" db:"total"`
+	Breakdown *PriceBreakdown `json:"breakdown,omitempty" db:"breakdown"`
+	CreatedBy *string         `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// OrderRepricing reports the outcome of recalculating an order's pricing.
+// When nothing changed no version is recorded, Changed is false and Version
+// is the order's current pricing version.
+type OrderRepricing struct {
+	OrderID uuid.UUID       `json:"orderId"`
+	Version int             `json:"version"`
+	Changed bool            `json:"changed"`
+	Pricing *PriceBreakdown `json:"pricing"`
+
+	// Totals of the version that was current before the recalculation. Nil
+	// for orders placed before totals were recorded.
+	PreviousSubtotal *Money `json:"previousSubtotal,omitempty"`
+	PreviousDiscount *Money `json:"previousDiscount,omitempty"`
+	PreviousTotal    *Money `json:"previousTotal,omitempty"`
+
+	Diff PricingDiff `json:"diff"`
+}
+
+// PricingDiff holds the change in an order's totals, new minus previous, and
+// the lines whose quantity or price changed. Missing previous totals count
+// as zero.
+type PricingDiff struct {
+	Subtotal Money           `json:"subtotal"`
+	Discount Money           `json:"discount"`
+	Total    Money           `json:"total"`
+	Lines    []PriceLineDiff `json:"lines"`
+}
+
+// PriceLineDiff describes how one product's line changed. Lines added since
+// the previous version have a zero previous quantity and removed lines a
+// zero quantity.
+type PriceLineDiff struct {
+	ProductID         string `json:"productId"`
+	PreviousQuantity  int    `json:"previousQuantity"`
+	Quantity          int    `json:"quantity"`
+	PreviousUnitPrice Money  `json:"previousUnitPrice"`
+	UnitPrice         Money  `json:"unitPrice"`
+	PreviousLineTotal Money  `json:"previousLineTotal"`
+	LineTotal         Money  `json:"lineTotal"`
+}
@@ -1,12 +1,301 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Product represents a food product in the catalogue.
 type Product struct {
 	ID        string    `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
-	Price     float64   `json:"price" db:"price"`
+	Price     Money     `json:"price" db:"price"`
 	Category  string    `json:"category" db:"category"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	CreatedAt time.Time `json:"createdAt,omitzero" db:"created_at"`
+
+	// Currency is the ISO 4217 code of the product's prices. Products are
+	// priced in the catalogue currency.
+	Currency string `json:"currency,omitempty" db:"currency"`
+
+	// Metadata holds descriptive attributes, such as weight or allergens,
+	// that vary by category.
+	Metadata map[string]any `json:"metadata,omitempty" db:"metadata"`
+
+	// Sale is the product's scheduled sale, if any. Price stays the regular
+	// price while the sale runs.
+	Sale *ProductSale `json:"sale,omitempty" db:"-"`
+
+	// EffectivePrice is the price customers pay when the product was read:
+	// the sale price while a sale is running, otherwise Price. OnSale is set
+	// when it is the sale price.
+	EffectivePrice Money `json:"effectivePrice" db:"-"`
+	OnSale         bool  `json:"onSale" db:"-"`
+
+	// Images are the product's images in position order, when product
+	// images are enabled.
+	Images []ProductImage `json:"images,omitempty" db:"-"`
+}
+
+// ProductSale is a time-boxed sale price. A sale without StartsAt runs from
+// when it is set and one without EndsAt until it is removed.
+type ProductSale struct {
+	Price    Money      `json:"price"`
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"`
+}
+
+// Validate checks the sale price is non-negative, in whole cents and below
+// the regular price, and the sale ends after it starts and after now.
+// Returns ErrInvalidSale otherwise.
+func (s ProductSale) Validate(regularPrice Money, now time.Time) error {
+	if s.Price < 0 || !s.Price.WholeCents() || s.Price >= regularPrice {
+		return ErrInvalidSale
+	}
+	if s.EndsAt != nil && (!s.EndsAt.After(now) || (s.StartsAt != nil && !s.EndsAt.After(*s.StartsAt))) {
+		return ErrInvalidSale
+	}
+	return nil
+}
+
+// ActiveAt reports whether the sale runs at t. Sales start at StartsAt and
+// end just before EndsAt.
+func (s ProductSale) ActiveAt(t time.Time) bool {
+	if s.StartsAt != nil && t.Before(*s.StartsAt) {
+		return false
+	}
+	return s.EndsAt == nil || t.Before(*s.EndsAt)
+}
+
+// PriceAt returns the price customers pay for the product at t, and whether
+// it is the sale price. A running sale only applies while it is cheaper than
+// the regular price, so a later price cut is never undone by an older sale.
+func (p Product) PriceAt(t time.Time) (Money, bool) {
+	if p.Sale != nil && p.Sale.ActiveAt(t) && p.Sale.Price < p.Price {
+		return p.Sale.Price, true
+	}
+	return p.Price, false
+}
+
+// WithEffectivePrice returns p with EffectivePrice and OnSale set for t.
+func (p Product) WithEffectivePrice(t time.Time) Product {
+	p.EffectivePrice, p.OnSale = p.PriceAt(t)
+	return p
+}
+
+// InCurrency returns p with its prices converted to currency at rate, the
+// amount of currency one unit of p's currency buys.
+func (p Product) InCurrency(currency string, rate float64) Product {
+	p.Price = p.Price.Convert(rate, currency)
+	p.EffectivePrice = p.EffectivePrice.Convert(rate, currency)
+	if p.Sale != nil {
+		sale := *p.Sale
+		sale.Price = sale.Price.Convert(rate, currency)
+		p.Sale = &sale
+	}
+	p.Currency = currency
+	return p
+}
+
+// Validate checks the fields required of a new product: an ID usable in URL
+// paths, a name, a category and a non-negative price in whole cents. Returns
+// ErrInvalidProduct otherwise.
+func (p Product) Validate() error {
+	if p.ID == "" || strings.Contains(p.ID, "/") || p.Name == "" || p.Category == "" {
+		return ErrInvalidProduct
+	}
+	if p.Price < 0 || !p.Price.WholeCents() {
+		return ErrInvalidProduct
+	}
+	return nil
+}
+
+// SnapshotAt returns the product details captured on order items placed at
+// t, with the price customers paid at that time.
+func (p Product) SnapshotAt(t time.Time) *ProductSnapshot {
+	price, onSale := p.PriceAt(t)
+	return &ProductSnapshot{
+		Name:     p.Name,
+		Category: p.Category,
+		Price:    price,
+		OnSale:   onSale,
+	}
+}
+
+// PublicProduct is the subset of a product shown on the anonymous public
+// catalogue.
+type PublicProduct struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Price          Money  `json:"price"`
+	EffectivePrice Money  `json:"effectivePrice"`
+	OnSale         bool   `json:"onSale"`
+	Category       string `json:"category"`
+	Currency       string `json:"currency,omitempty"`
+
+	Images []PublicImage `json:"images,omitempty"`
+}
+
+// PublicImage is a product image shown on the public catalogue.
+type PublicImage struct {
+	URL     string `json:"url"`
+	AltText string `json:"altText,omitempty"`
+}
+
+// Public returns the fields of p that may be shown without authentication.
+// The sale's schedule is left out; only whether it is running is shown.
+// Images keep only what a storefront needs to display them.
+func (p Product) Public() PublicProduct {
+	public := PublicProduct{
+		ID:             p.ID,
+		Name:           p.Name,
+		Price:          p.Price,
+		EffectivePrice: p.EffectivePrice,
+		OnSale:         p.OnSale,
+		Category:       p.Category,
+		Currency:       p.Currency,
+	}
+	for _, image := range p.Images {
+		public.Images = append(public.Images, PublicImage{URL: image.URL, AltText: image.AltText})
+	}
+	return public
+}
+
+// ProductSort is a field products can be listed by.
+type ProductSort string
+
+// Product sort fields.
+const (
+	ProductSortName      ProductSort = "name"
+	ProductSortPrice     ProductSort = "price"
+	ProductSortCreatedAt ProductSort = "created_at"
+)
+
+// Valid reports whether s is a known sort field.
+func (s ProductSort) Valid() bool {
+	switch s {
+	case ProductSortName, ProductSortPrice, ProductSortCreatedAt:
+		return true
+	}
+	return false
+}
+
+// SortOrder is the direction of a sorted listing.
+type SortOrder string
+
+// Sort directions.
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Valid reports whether o is a known sort direction.
+func (o SortOrder) Valid() bool {
+	return o == SortAsc || o == SortDesc
+}
+
+// ProductFilter represents filtering, sorting and pagination options for
+// listing products.
+type ProductFilter struct {
+	Category string
+	Sort     ProductSort
+	Order    SortOrder
+	Limit    int
+	Offset   int
+}
+
+// ProductRequest represents the request payload for creating or updating a product.
+// ID is only read on creation; on update the ID comes from the path.
+type ProductRequest struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Price    *Money         `json:"price"`
+	Currency string         `json:"currency,omitempty"` // the catalogue currency when empty
+	Category string         `json:"category"`
+	Metadata map[string]any `json:"metadata"` // nil keeps the current metadata on update
+}
+
+// ProductComparison sets products side by side. Attributes holds one row
+// per attribute any of the products has, with one value per product in the
+// order of Products.
+type ProductComparison struct {
+	Products   []Product             `json:"products"`
+	Attributes []ComparisonAttribute `json:"attributes"`
+}
+
+// InCurrency returns c with its products and price row converted to currency
+// at rate.
+func (c ProductComparison) InCurrency(currency string, rate float64) ProductComparison {
+	products := make([]Product, len(c.Products))
+	for i, p := range c.Products {
+		products[i] = p.InCurrency(currency, rate)
+	}
+	attributes := slices.Clone(c.Attributes)
+	for i, attribute := range attributes {
+		if attribute.Name != "price" {
+			continue
+		}
+		values := make([]any, len(products))
+		for j, p := range products {
+			values[j] = p.EffectivePrice
+		}
+		attributes[i].Values = values
+	}
+	return ProductComparison{Products: products, Attributes: attributes}
+}
+
+// ComparisonAttribute is one row of a product comparison. Values are nil for
+// products without the attribute. Differs is set when the products do not
+// all share the same value.
+type ComparisonAttribute struct {
+	Name    string `json:"name"`
+	Values  []any  `json:"values"`
+	Differs bool   `json:"differs"`
+}
+
+// ProductImportResult reports the outcome of a CSV product import. Valid rows
+// are imported; rows with errors are skipped and reported.
+type ProductImportResult struct {
+	Imported int64                `json:"imported"`
+	Errors   []ProductImportError `json:"errors"`
+}
+
+// ProductImportError explains why a CSV row was not imported. Row is the
+// row's line number in the file, counting the header as line 1, and Code is
+// the error code the row would have been refused with on its own.
+type ProductImportError struct {
+	Row       int    `json:"row"`
+	ProductID string `json:"productId,omitempty"`
+	Code      string `json:"code"`
+	Error     string `json:"error"`
+}
+
+// Reasons a product cannot be archived.
+const (
+	// ArchiveConflictNotFound marks an ID that does not match any product.
+	ArchiveConflictNotFound = "not_found"
+
+	// ArchiveConflictOpenOrders marks a product with unfulfilled items in open orders.
+	ArchiveConflictOpenOrders = "open_orders"
+)
+
+// ProductArchiveRequest represents the request payload for archiving products.
+type ProductArchiveRequest struct {
+	ProductIDs []string `json:"productIds"`
+}
+
+// ProductArchiveResult reports the outcome of a bulk archive. Products are
+// archived all-or-nothing: when any conflict is reported, none are archived.
+type ProductArchiveResult struct {
+	Archived  []string                 `json:"archived"`
+	Conflicts []ProductArchiveConflict `json:"conflicts"`
+}
+
+// ProductArchiveConflict explains why a product cannot be archived.
+type ProductArchiveConflict struct {
+	ProductID string      `json:"productId"`
+	Reason    string      `json:"reason"`
+	OrderIDs  []uuid.UUID `json:"orderIds,omitempty"`
 }
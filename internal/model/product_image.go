@@ -0,0 +1,105 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxProductImages bounds the images attached to one product.
+	MaxProductImages = 20
+
+	// maxImageAltTextLength bounds the alternative text stored per image.
+	maxImageAltTextLength = 300
+)
+
+// imageExtensions maps the image content types products may show to the
+// file extension their keys get.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// ImageExtension returns the file extension of keys storing images of the
+// content type, and false when products cannot show the type.
+func ImageExtension(contentType string) (string, bool) {
+	ext, ok := imageExtensions[strings.ToLower(contentType)]
+	return ext, ok
+}
+
+// ProductImage is an image shown with a product. Images are listed by
+// Position, starting at 0.
+type ProductImage struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ProductID   string    `json:"productId" db:"product_id"`
+	Key         string    `json:"key" db:"key"`
+	URL         string    `json:"url" db:"-"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	SizeBytes   int64     `json:"sizeBytes" db:"size_bytes"`
+	AltText     string    `json:"altText,omitempty" db:"alt_text"`
+	Position    int       `json:"position" db:"position"`
+	CreatedBy   string    `json:"createdBy" db:"created_by"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ImageUploadRequest asks for a presigned URL to upload a product image
+// with. SizeBytes is the exact size of the file to be uploaded.
+type ImageUploadRequest struct {
+	ContentType string `json:"contentType"`
+	SizeBytes   int64  `json:"sizeBytes"`
+}
+
+// Validate checks the upload is of an image type products can show and no
+// larger than maxBytes. Returns ErrInvalidImage otherwise.
+func (r ImageUploadRequest) Validate(maxBytes int64) error {
+	if _, ok := ImageExtension(r.ContentType); !ok {
+		return ErrInvalidImage
+	}
+	if r.SizeBytes <= 0 || r.SizeBytes > maxBytes {
+		return ErrInvalidImage
+	}
+	return nil
+}
+
+// ImageUpload is a presigned upload of a product image. The file is sent
+// with Method to URL along with Headers, before ExpiresAt, and then attached
+// to the product by Key.
+type ImageUpload struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// ProductImageRequest attaches an uploaded image to a product.
+type ProductImageRequest struct {
+	Key     string `json:"key"`
+	AltText string `json:"altText,omitempty"`
+}
+
+// Validate checks the request names an uploaded key and its alternative
+// text is at most 300 characters. Returns ErrInvalidImage otherwise.
+func (r ProductImageRequest) Validate() error {
+	if strings.TrimSpace(r.Key) == "" || utf8.RuneCountInString(r.AltText) > maxImageAltTextLength {
+		return ErrInvalidImage
+	}
+	return nil
+}
+
+// ImageOrderRequest lists every image of a product in the order to show
+// them.
+type ImageOrderRequest struct {
+	ImageIDs []uuid.UUID `json:"imageIds"`
+}
+
+// ImageObject describes an object found in image storage.
+type ImageObject struct {
+	ContentType string
+	SizeBytes   int64
+}
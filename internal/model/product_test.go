@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProduct_PriceAt(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	hourAgo, inAnHour := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		sale           *ProductSale
+		expectedPrice  float64
+		expectedOnSale bool
+	}{
+		{
+			name:          "No sale",
+			expectedPrice: 10,
+		},
+		{
+			name:           "Open-ended sale",
+			sale:           &ProductSale{Price: NewMoney(7.5)},
+			expectedPrice:  7.5,
+			expectedOnSale: true,
+		},
+		{
+			name:           "Sale within its window",
+			sale:           &ProductSale{Price: NewMoney(7.5), StartsAt: &hourAgo, EndsAt: &inAnHour},
+			expectedPrice:  7.5,
+			expectedOnSale: true,
+		},
+		{
+			name:          "Sale not yet started",
+			sale:          &ProductSale{Price: NewMoney(7.5), StartsAt: &inAnHour},
+			expectedPrice: 10,
+		},
+		{
+			name:          "Sale ends at its end time",
+			sale:          &ProductSale{Price: NewMoney(7.5), StartsAt: &hourAgo, EndsAt: &now},
+			expectedPrice: 10,
+		},
+		{
+			name:          "Sale above a later price cut",
+			sale:          &ProductSale{Price: NewMoney(12)},
+			expectedPrice: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := Product{ID: "P001", Price: NewMoney(10), Sale: tt.sale}
+
+			price, onSale := product.PriceAt(now)
+			assert.Equal(t, NewMoney(tt.expectedPrice), price)
+			assert.Equal(t, tt.expectedOnSale, onSale)
+
+			snapshot := product.SnapshotAt(now)
+			assert.Equal(t, NewMoney(tt.expectedPrice), snapshot.Price)
+			assert.Equal(t, tt.expectedOnSale, snapshot.OnSale)
+		})
+	}
+}
+
+func TestProductSale_Validate(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	hourAgo, inAnHour, tomorrow := now.Add(-time.Hour), now.Add(time.Hour), now.Add(24*time.Hour)
+
+	tests := []struct {
+		name      string
+		sale      ProductSale
+		expectErr bool
+	}{
+		{name: "Open-ended sale", sale: ProductSale{Price: NewMoney(7.5)}},
+		{name: "Free while on sale", sale: ProductSale{Price: NewMoney(0)}},
+		{name: "Scheduled sale", sale: ProductSale{Price: NewMoney(7.5), StartsAt: &inAnHour, EndsAt: &tomorrow}},
+		{name: "Sale already running", sale: ProductSale{Price: NewMoney(7.5), StartsAt: &hourAgo, EndsAt: &inAnHour}},
+		{name: "Negative price", sale: ProductSale{Price: NewMoney(-1)}, expectErr: true},
+		{name: "Finer than a cent", sale: ProductSale{Price: NewMoney(7.495)}, expectErr: true},
+		{name: "Not below regular price", sale: ProductSale{Price: NewMoney(10)}, expectErr: true},
+		{name: "Already ended", sale: ProductSale{Price: NewMoney(7.5), EndsAt: &hourAgo}, expectErr: true},
+		{name: "Ends before it starts", sale: ProductSale{Price: NewMoney(7.5), StartsAt: &tomorrow, EndsAt: &inAnHour}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sale.Validate(NewMoney(10), now)
+			if tt.expectErr {
+				assert.Equal(t, ErrInvalidSale, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProduct_Validate(t *testing.T) {
+	valid := Product{ID: "P001", Name: "Chips", Category: "Snacks", Price: NewMoney(9.99)}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name    string
+		product func(Product) Product
+	}{
+		{name: "Missing ID", product: func(p Product) Product { p.ID = ""; return p }},
+		{name: "ID with a slash", product: func(p Product) Product { p.ID = "P/001"; return p }},
+		{name: "Missing name", product: func(p Product) Product { p.Name = ""; return p }},
+		{name: "Negative price", product: func(p Product) Product { p.Price = NewMoney(-1); return p }},
+		{name: "Price finer than a cent", product: func(p Product) Product { p.Price = NewMoney(9.995); return p }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ErrInvalidProduct, tt.product(valid).Validate())
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SagaStatus represents the state of an order saga.
+type SagaStatus string
+
+// Order saga statuses. A running saga is completed in the transaction that
+// stores its order, or compensated if the order is never stored. Failed
+// sagas ran out of compensation attempts and need manual attention.
+const (
+	SagaRunning      SagaStatus = "running"
+	SagaCompleted    SagaStatus = "completed"
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompensated  SagaStatus = "compensated"
+	SagaFailed       SagaStatus = "failed"
+)
+
+// SagaStepStatus represents the state of one step of an order saga.
+type SagaStepStatus string
+
+// Saga step statuses. A pending step was started but its outcome is unknown,
+// e.g. because the request timed out, so it is compensated like a completed
+// one. Rejected steps were refused by their system and have nothing to undo.
+const (
+	SagaStepPending     SagaStepStatus = "pending"
+	SagaStepCompleted   SagaStepStatus = "completed"
+	SagaStepSkipped     SagaStepStatus = "skipped"
+	SagaStepRejected    SagaStepStatus = "rejected"
+	SagaStepCompensated SagaStepStatus = "compensated"
+)
+
+// Saga records the side effects order creation had on external systems, such
+// as a payment authorisation or a stock reservation, so they can be undone if
+// the order is not stored.
+type Saga struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	OrderID   uuid.UUID       `json:"orderId" db:"order_id"`
+	Status    SagaStatus      `json:"status" db:"status"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Steps     []SagaStep      `json:"steps" db:"steps"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	LastError *string         `json:"lastError,omitempty" db:"last_error"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// SagaStep is the progress of one step of a saga. Result is what the step's
+// system responded with, e.g. the authorisation ID, kept for compensation.
+type SagaStep struct {
+	Name   string          `json:"name"`
+	Status SagaStepStatus  `json:"status"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// OrderSagaPayload is the part of an order its saga steps act on.
+type OrderSagaPayload struct {
+	OrderID    uuid.UUID          `json:"orderId"`
+	CustomerID *uuid.UUID         `json:"customerId,omitempty"`
+	CouponCode *string            `json:"couponCode,omitempty"`
+	Items      []OrderItemRequest `json:"items"`
+	Total      *Money             `json:"total,omitempty"`
+	Currency   string             `json:"currency,omitempty"`
+}
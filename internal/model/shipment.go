@@ -0,0 +1,125 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// FulfillmentStatus represents how much of an order has been shipped.
+// It is derived from the fulfilled quantities of the order's items.
+type FulfillmentStatus string
+
+// Fulfillment statuses.
+const (
+	// FulfillmentStatusUnfulfilled marks an order with nothing shipped yet.
+	FulfillmentStatusUnfulfilled FulfillmentStatus = "unfulfilled"
+
+	// FulfillmentStatusPartial marks an order with some, but not all, items shipped.
+	FulfillmentStatusPartial FulfillmentStatus = "partially_fulfilled"
+
+	// FulfillmentStatusFulfilled marks an order with every item fully shipped.
+	FulfillmentStatusFulfilled FulfillmentStatus = "fulfilled"
+)
+
+// Order event types.
+const (
+	// OrderEventShipmentCreated is recorded for every shipment created against an order.
+	OrderEventShipmentCreated = "shipment.created"
+
+	// OrderEventTrackingUpdated is recorded when a shipment's tracking number
+	// is attached or changed after it was created.
+	OrderEventTrackingUpdated = "shipment.tracking_updated"
+)
+
+// maxTrackingFieldLength bounds shipment carriers and tracking numbers.
+const maxTrackingFieldLength = 100
+
+// DeriveFulfillmentStatus computes an order's fulfillment status from its items.
+func DeriveFulfillmentStatus(items []OrderItem) FulfillmentStatus {
+	var ordered, fulfilled int
+	for _, item := range items {
+		ordered += item.Quantity
+		fulfilled += item.FulfilledQuantity
+	}
+
+	switch {
+	case fulfilled == 0:
+		return FulfillmentStatusUnfulfilled
+	case fulfilled >= ordered:
+		return FulfillmentStatusFulfilled
+	default:
+		return FulfillmentStatusPartial
+	}
+}
+
+// Shipment represents a set of order items shipped together.
+type Shipment struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	OrderID        uuid.UUID      `json:"orderId" db:"order_id"`
+	Carrier        *string        `json:"carrier,omitempty" db:"carrier"`
+	TrackingNumber *string        `json:"trackingNumber,omitempty" db:"tracking_number"`
+	Items          []ShipmentItem `json:"items"`
+	CreatedAt      time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// ShipmentItem represents the quantity of an order item included in a shipment.
+type ShipmentItem struct {
+	OrderItemID uuid.UUID `json:"orderItemId" db:"order_item_id"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+}
+
+// ShipmentRequest represents the request payload for creating a shipment.
+type ShipmentRequest struct {
+	Carrier        *string        `json:"carrier,omitempty"`
+	TrackingNumber *string        `json:"trackingNumber,omitempty"`
+	Items          []ShipmentItem `json:"items"`
+}
+
+// TrackingRequest represents the request payload for attaching a tracking
+// number to a shipment. A nil Carrier keeps the shipment's carrier.
+type TrackingRequest struct {
+	Carrier        *string `json:"carrier,omitempty"`
+	TrackingNumber string  `json:"trackingNumber"`
+}
+
+// Normalize trims the carrier and tracking number.
+func (r *TrackingRequest) Normalize() {
+	r.TrackingNumber = strings.TrimSpace(r.TrackingNumber)
+	if r.Carrier != nil {
+		carrier := strings.TrimSpace(*r.Carrier)
+		r.Carrier = &carrier
+	}
+}
+
+// Validate checks the request has a tracking number, and that the tracking
+// number and any carrier are at most 100 characters. Returns
+// ErrInvalidTracking otherwise.
+func (r TrackingRequest) Validate() error {
+	if r.TrackingNumber == "" || utf8.RuneCountInString(r.TrackingNumber) > maxTrackingFieldLength {
+		return ErrInvalidTracking
+	}
+	if r.Carrier != nil && (*r.Carrier == "" || utf8.RuneCountInString(*r.Carrier) > maxTrackingFieldLength) {
+		return ErrInvalidTracking
+	}
+	return nil
+}
+
+// ShipRemainingRequest represents the request payload for shipping every
+// unfulfilled item of an order in one shipment.
+type ShipRemainingRequest struct {
+	Carrier        *string `json:"carrier,omitempty"`
+	TrackingNumber *string `json:"trackingNumber,omitempty"`
+}
+
+// OrderEvent represents a recorded change to an order's fulfillment.
+type OrderEvent struct {
+	ID                uuid.UUID         `json:"id" db:"id"`
+	OrderID           uuid.UUID         `json:"orderId" db:"order_id"`
+	ShipmentID        *uuid.UUID        `json:"shipmentId,omitempty" db:"shipment_id"`
+	Type              string            `json:"type" db:"type"`
+	FulfillmentStatus FulfillmentStatus `json:"fulfillmentStatus" db:"fulfillment_status"`
+	CreatedAt         time.Time         `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderSLAs holds how long orders may stay in each status before they breach
+// their service level agreement. Statuses without an SLA are not tracked.
+type OrderSLAs map[OrderStatus]time.Duration
+
+// AtRiskOrder is an order that has been in its status for close to, or
+// longer than, the status's SLA.
+type AtRiskOrder struct {
+	ID        uuid.UUID   `json:"id"`
+	Status    OrderStatus `json:"status"`
+	Source    *string     `json:"source,omitempty"`
+	Total     *Money      `json:"total,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+
+	// StatusSince is when the order entered its current status.
+	StatusSince time.Time `json:"statusSince"`
+
+	// TimeInStatusSeconds is how long the order has been in its status.
+	TimeInStatusSeconds int64 `json:"timeInStatusSeconds"`
+
+	// SLASeconds is the status's SLA; the order breaches it once
+	// TimeInStatusSeconds exceeds it.
+	SLASeconds int64 `json:"slaSeconds"`
+	Breached   bool  `json:"breached"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SnapshotSignatureAlgorithm names how snapshots are signed.
+const SnapshotSignatureAlgorithm = "HMAC-SHA256"
+
+// OrderSnapshot is a point-in-time record of an order and its full history,
+// kept as evidence for disputes and chargebacks.
+type OrderSnapshot struct {
+	ID              uuid.UUID             `json:"id"`
+	OrderID         uuid.UUID             `json:"orderId"`
+	TakenBy         string                `json:"takenBy"`
+	TakenAt         time.Time             `json:"takenAt"`
+	Order           *OrderResponse        `json:"order"`
+	Shipments       []Shipment            `json:"shipments"`
+	PricingVersions []OrderPricingVersion `json:"pricingVersions"`
+	AuditLog        []AuditEntry          `json:"auditLog"`
+	Timeline        []TimelineEntry       `json:"timeline"`
+}
+
+// SignedSnapshot is an order snapshot with the SHA-256 hash and signature of
+// its exact JSON encoding. Snapshot holds those bytes unchanged, so the hash
+// and signature can be checked against the snapshot value as sent.
+type SignedSnapshot struct {
+	Snapshot           json.RawMessage  `json:"snapshot"`
+	SHA256             string           `json:"sha256"`
+	Signature          string           `json:"signature"`
+	SignatureAlgorithm string           `json:"signatureAlgorithm"`
+	Storage            *SnapshotStorage `json:"storage,omitempty"`
+}
+
+// SnapshotStorage locates a snapshot stored under object lock. The object
+// cannot be overwritten or deleted before RetainUntil.
+type SnapshotStorage struct {
+	Location    string    `json:"location"`
+	VersionID   string    `json:"versionId,omitempty"`
+	LockMode    string    `json:"lockMode"`
+	RetainUntil time.Time `json:"retainUntil"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// DefaultTenant owns the data stored before tenants were introduced, and is
+// the tenant of callers not tied to another one, such as the API_KEY.
+const DefaultTenant = "default"
+
+// tenantIDPattern restricts tenant IDs to lowercase slugs, so they are safe
+// in headers, logs and configuration entries.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Tenant is a storefront brand hosted on the deployment. Products, orders
+// and coupon discounts belong to exactly one tenant.
+type Tenant struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// TenantRequest represents the request payload for creating a tenant, or
+// renaming one when ID is taken from the path.
+type TenantRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Validate checks the tenant has a slug ID of at most 63 lowercase letters,
+// digits and hyphens, and a name. Returns ErrInvalidTenant otherwise.
+func (r TenantRequest) Validate() error {
+	if !ValidTenantID(r.ID) || r.Name == "" {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// ValidTenantID reports whether id is a well-formed tenant ID.
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// tenantKey is the context key for the request's tenant.
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the given tenant.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to. Contexts of
+// background work, such as exports and SLA checks, are not scoped to a
+// tenant and see every tenant's data.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderNote is an internal note a support agent added to an order. Notes are
+// never shown to customers.
+type OrderNote struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	OrderID   uuid.UUID `json:"orderId" db:"order_id"`
+	Author    string    `json:"author" db:"author"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// OrderNoteRequest represents the request payload for adding an order note.
+type OrderNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// OrderStatusChange records an order moving from one status to another.
+type OrderStatusChange struct {
+	ID        uuid.UUID   `json:"id" db:"id"`
+	OrderID   uuid.UUID   `json:"orderId" db:"order_id"`
+	From      OrderStatus `json:"from" db:"from_status"`
+	To        OrderStatus `json:"to" db:"to_status"`
+	CreatedAt time.Time   `json:"createdAt" db:"created_at"`
+}
+
+// Timeline entry types. Fulfillment events keep their own event type, e.g.
+// OrderEventShipmentCreated.
+const (
+	TimelineOrderCreated  = "order.created"
+	TimelineNoteAdded     = "note.added"
+	TimelineStatusChanged = "status.changed"
+	TimelineWebhookQueued = "webhook.queued"
+)
+
+// TimelineEntry is one item in an order's history. Exactly one of Note,
+// StatusChange, Event and Webhook is set, matching Type; order.created
+// entries carry none of them.
+type TimelineEntry struct {
+	Type         string             `json:"type"`
+	At           time.Time          `json:"at"`
+	Note         *OrderNote         `json:"note,omitempty"`
+	StatusChange *OrderStatusChange `json:"statusChange,omitempty"`
+	Event        *OrderEvent        `json:"event,omitempty"`
+	Webhook      *WebhookDelivery   `json:"webhook,omitempty"`
+}
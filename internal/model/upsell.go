@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// UpsellProduct is a product recommended after an order: one of the best
+// sellers in the order's categories that the order does not contain.
+type UpsellProduct struct {
+	Product
+
+	// UnitsSold is how many units of the product were ordered within the
+	// sales window, excluding cancelled orders.
+	UnitsSold int64 `json:"unitsSold"`
+}
+
+// UpsellLimits bounds the products recommended after an order.
+type UpsellLimits struct {
+	// Limit is the number of products recommended.
+	Limit int
+
+	// PerCategory caps the products recommended from any one category.
+	// Zero disables the cap.
+	PerCategory int
+
+	// SalesWindow is how far back orders are counted when ranking products.
+	// Zero counts every order.
+	SalesWindow time.Duration
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order webhook event types.
+const (
+	WebhookOrderCreated   = "order.created"
+	WebhookOrderCancelled = "order.cancelled"
+)
+
+// WebhookEvent is the JSON body posted to webhook endpoints. ID is the same
+// for every endpoint and every retry, so receivers can discard duplicates.
+type WebhookEvent struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// WebhookOrderStatus is the data of an order status event.
+type WebhookOrderStatus struct {
+	ID             uuid.UUID   `json:"id"`
+	Status         OrderStatus `json:"status"`
+	PreviousStatus OrderStatus `json:"previousStatus"`
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+// Webhook delivery statuses. Failed deliveries ran out of attempts.
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an event waiting in the outbox to be posted to one
+// endpoint. Deliveries are written in the same transaction as the change
+// they announce, so an event is sent exactly when the change commits.
+type WebhookDelivery struct {
+	ID        uuid.UUID             `json:"id" db:"id"`
+	EventID   uuid.UUID             `json:"eventId" db:"event_id"`
+	EventType string                `json:"eventType" db:"event_type"`
+	OrderID   uuid.UUID             `json:"orderId" db:"order_id"`
+	Endpoint  string                `json:"endpoint" db:"endpoint"`
+	Payload   json.RawMessage       `json:"payload" db:"payload"`
+	Status    WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts  int                   `json:"attempts" db:"attempts"`
+	LastError *string               `json:"lastError,omitempty" db:"last_error"`
+	CreatedAt time.Time             `json:"createdAt" db:"created_at"`
+}
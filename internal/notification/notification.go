@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Notification is a message about a business event that admins may need to act on.
+type Notification struct {
+	// Type identifies the event, e.g. "price_change.requested".
+	Type string
+
+	// Message is a human-readable summary of the event.
+	Message string
+
+	// Fields carries structured details about the event.
+	Fields map[string]string
+}
+
+// Notifier delivers notifications to admins.
+type Notifier interface {
+	// Notify delivers a notification.
+	Notify(ctx context.Context, n Notification) error
+}
+
+// logNotifier implements Notifier by writing notifications to the application log.
+type logNotifier struct {
+	logger zerolog.Logger
+}
+
+// NewLogNotifier creates a notifier that writes notifications to the log.
+func NewLogNotifier(logger zerolog.Logger) Notifier {
+	return &logNotifier{
+		logger: logger.With().Str("component", "notifier").Logger(),
+	}
+}
+
+// Notify writes the notification as a structured log entry.
+func (n *logNotifier) Notify(ctx context.Context, notification Notification) error {
+	event := n.logger.Info().Str("notification_type", notification.Type)
+	for key, value := range notification.Fields {
+		event = event.Str(key, value)
+	}
+	event.Msg(notification.Message)
+
+	return nil
+}
+
+// webhookNotifier implements Notifier by posting notifications to a webhook.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+	logger zerolog.Logger
+}
+
+// webhookPayload is the JSON body posted to a webhook.
+type webhookPayload struct {
+	Type    string            `json:"type"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	SentAt  time.Time         `json:"sentAt"`
+}
+
+// NewWebhookNotifier creates a notifier that posts each notification to url
+// as JSON.
+func NewWebhookNotifier(url string, client *http.Client, logger zerolog.Logger) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: client,
+		logger: logger.With().Str("component", "webhook-notifier").Logger(),
+	}
+}
+
+// Notify posts the notification to the webhook. Any response other than 2xx
+// is an error.
+func (n *webhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Type:    notification.Type,
+		Message: notification.Message,
+		Fields:  notification.Fields,
+		SentAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook rejected notification: status %d", resp.StatusCode)
+	}
+
+	n.logger.Debug().Str("notification_type", notification.Type).Msg("notification sent to webhook")
+	return nil
+}
@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received map[string]any
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, server.Client(), zerolog.Nop())
+	n := Notification{
+		Type:    "quota.warning",
+		Message: "api-key has used 80% of its monthly requests quota",
+		Fields:  map[string]string{"quota": "requests"},
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), n))
+	assert.Equal(t, "quota.warning", received["type"])
+	assert.Equal(t, n.Message, received["message"])
+	assert.Equal(t, map[string]any{"quota": "requests"}, received["fields"])
+	assert.NotEmpty(t, received["sentAt"])
+
+	status = http.StatusBadGateway
+	err := notifier.Notify(context.Background(), n)
+	assert.EqualError(t, err, "webhook rejected notification: status 502")
+}
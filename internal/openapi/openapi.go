@@ -0,0 +1,280 @@
+// Package openapi generates OpenAPI 3 documents from typed route metadata.
+// Request and response bodies are described by the JSON encoding of Go
+// values of their types, so the document follows the types handlers decode
+// and encode.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.0.3"
+
+// securityScheme names the API key scheme in generated documents.
+const securityScheme = "apiKey"
+
+// pathParam matches the {name} segments of ServeMux patterns.
+var pathParam = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// Info describes the API in a generated document.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Route describes one operation: a method on a path.
+type Route struct {
+	// Method is the HTTP method, e.g. http.MethodGet.
+	Method string
+
+	// Path is the route's path. Segments such as {id} are documented as
+	// required path parameters.
+	Path string
+
+	// Operation is the operation ID client SDKs name the method after,
+	// e.g. "listProducts". IDs must be unique within a document.
+	Operation string
+
+	// Summary is a short description of the operation.
+	Summary string
+
+	// Tag groups the operation, e.g. "products".
+	Tag string
+
+	// Query and Headers are the operation's optional or required query
+	// parameters and request headers.
+	Query   []Param
+	Headers []Param
+
+	// Request is a value of the JSON request body's type, or nil when the
+	// operation takes no JSON body.
+	Request any
+
+	// Upload names the multipart/form-data field a file is uploaded in, for
+	// operations taking a file instead of a JSON body.
+	Upload string
+
+	// Responses maps the operation's success statuses to a value of the
+	// JSON response body's type, or to nil for responses without a body.
+	Responses map[int]any
+
+	// Profiles maps the media types of alternative representations of the
+	// 200 response, which clients select with the Accept header, to a value
+	// of each one's body type.
+	Profiles map[string]any
+
+	// Errors lists the error statuses the operation responds with. Their
+	// bodies are described by the document's error type.
+	Errors []int
+
+	// Public operations need no API key. Other operations may respond with
+	// 401 Unauthorized, which need not be listed in Errors.
+	Public bool
+}
+
+// Param describes a query parameter or request header.
+type Param struct {
+	Name        string
+	Description string
+
+	// Type is the parameter's JSON schema type: "string", "integer" or
+	// "boolean". Empty means "string".
+	Type string
+
+	// Enum optionally lists the values the parameter accepts.
+	Enum []string
+
+	Required bool
+}
+
+// Document is a generated OpenAPI document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []map[string][]string            `json:"security"`
+}
+
+// Operation is a documented operation.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a documented path or query parameter or request header.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is a documented request body.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a documented response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes a body in one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes operations refer to.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme is a documented authentication scheme.
+type SecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+// Generate builds the document describing routes, authenticated with an API
+// key in the X-API-Key header. Error responses are described by the type of
+// errorBody.
+func Generate(info Info, routes []Route, errorBody any) *Document {
+	schemas := newSchemas()
+	errorSchema := schemas.of(errorBody)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: schemas.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				securityScheme: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+		Security: []map[string][]string{{securityScheme: {}}},
+	}
+
+	for _, route := range routes {
+		item := doc.Paths[route.Path]
+		if item == nil {
+			item = make(map[string]*Operation)
+			doc.Paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation(route, schemas, errorSchema)
+	}
+
+	return doc
+}
+
+// operation documents a single route.
+func operation(route Route, schemas *schemas, errorSchema *Schema) *Operation {
+	op := &Operation{
+		OperationID: route.Operation,
+		Summary:     route.Summary,
+		Responses:   make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Public {
+		// An empty requirement overrides the document's API key requirement
+		op.Security = []map[string][]string{{}}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, p := range route.Query {
+		op.Parameters = append(op.Parameters, parameter(p, "query"))
+	}
+	for _, p := range route.Headers {
+		op.Parameters = append(op.Parameters, parameter(p, "header"))
+	}
+
+	switch {
+	case route.Upload != "":
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"multipart/form-data": {Schema: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{route.Upload: {Type: "string", Format: "binary"}},
+					Required:   []string{route.Upload},
+				}},
+			},
+		}
+	case route.Request != nil:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: schemas.of(route.Request)}},
+		}
+	}
+
+	for status, body := range route.Responses {
+		resp := Response{Description: http.StatusText(status)}
+		if body != nil {
+			resp.Content = map[string]MediaType{"application/json": {Schema: schemas.of(body)}}
+		}
+		if status == http.StatusOK {
+			for mediaType, profile := range route.Profiles {
+				if resp.Content == nil {
+					resp.Content = make(map[string]MediaType)
+				}
+				resp.Content[mediaType] = MediaType{Schema: schemas.of(profile)}
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+
+	errorResponse := func(status int) {
+		op.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+	for _, status := range route.Errors {
+		errorResponse(status)
+	}
+	if !route.Public {
+		errorResponse(http.StatusUnauthorized)
+	}
+
+	return op
+}
+
+// parameter documents a query parameter or request header.
+func parameter(p Param, in string) Parameter {
+	schema := &Schema{Type: p.Type, Enum: p.Enum}
+	if schema.Type == "" {
+		schema.Type = "string"
+	}
+	return Parameter{
+		Name:        p.Name,
+		In:          in,
+		Description: p.Description,
+		Required:    p.Required,
+		Schema:      schema,
+	}
+}
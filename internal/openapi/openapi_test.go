@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testError struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+type testItem struct {
+	ID        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	Price     float64         `json:"price"`
+	Quantity  int             `json:"quantity"`
+	Note      *string         `json:"note"`
+	Tags      []string        `json:"tags,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	internal  string
+}
+
+type testAudit struct {
+	UpdatedBy string `json:"updatedBy"`
+}
+
+type testOrder struct {
+	testAudit
+	Items  []testItem        `json:"items"`
+	Parent *testOrder        `json:"parent,omitempty"`
+	Counts map[string]int64  `json:"counts"`
+	Secret string            `json:"-"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func TestGenerate(t *testing.T) {
+	routes := []Route{
+		{
+			Method:    http.MethodGet,
+			Path:      "/api/v1/orders/{id}",
+			Operation: "getOrder",
+			Summary:   "Get an order",
+			Tag:       "orders",
+			Query:     []Param{{Name: "expand", Enum: []string{"items"}}},
+			Responses: map[int]any{http.StatusOK: testOrder{}},
+			Errors:    []int{http.StatusNotFound},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/api/v1/orders",
+			Operation: "createOrder",
+			Headers:   []Param{{Name: "Idempotency-Key", Required: true}},
+			Request:   testItem{},
+			Responses: map[int]any{http.StatusCreated: testOrder{}},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/api/v1/orders",
+			Operation: "listOrders",
+			Responses: map[int]any{http.StatusOK: []testOrder{}},
+			Profiles:  map[string]any{"application/vnd.test.v2+json": testPage[testOrder]{}},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/api/v1/products/import",
+			Operation: "importProducts",
+			Upload:    "file",
+			Responses: map[int]any{http.StatusNoContent: nil},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/health",
+			Operation: "health",
+			Responses: map[int]any{http.StatusOK: nil},
+			Public:    true,
+		},
+	}
+
+	doc := Generate(Info{Title: "test", Version: "1.0.0"}, routes, testError{})
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []map[string][]string{{securityScheme: {}}}, doc.Security)
+	assert.Equal(t, "X-API-Key", doc.Components.SecuritySchemes[securityScheme].Name)
+
+	t.Run("Path and query parameters", func(t *testing.T) {
+		op := doc.Paths["/api/v1/orders/{id}"]["get"]
+		require.NotNil(t, op)
+		assert.Equal(t, "getOrder", op.OperationID)
+		assert.Equal(t, []string{"orders"}, op.Tags)
+		require.Len(t, op.Parameters, 2)
+		assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}, op.Parameters[0])
+		assert.Equal(t, Parameter{Name: "expand", In: "query", Schema: &Schema{Type: "string", Enum: []string{"items"}}}, op.Parameters[1])
+	})
+
+	t.Run("Error responses include 401 for authenticated routes", func(t *testing.T) {
+		op := doc.Paths["/api/v1/orders/{id}"]["get"]
+		assert.Contains(t, op.Responses, "200")
+		assert.Equal(t, "#/components/schemas/testError", op.Responses["404"].Content["application/json"].Schema.Ref)
+		assert.Equal(t, "Unauthorized", op.Responses["401"].Description)
+		assert.Nil(t, op.Security)
+	})
+
+	t.Run("Public routes override security", func(t *testing.T) {
+		op := doc.Paths["/health"]["get"]
+		assert.Equal(t, []map[string][]string{{}}, op.Security)
+		assert.NotContains(t, op.Responses, "401")
+		assert.Empty(t, op.Responses["200"].Content)
+	})
+
+	t.Run("JSON request body and headers", func(t *testing.T) {
+		op := doc.Paths["/api/v1/orders"]["post"]
+		require.NotNil(t, op.RequestBody)
+		assert.True(t, op.RequestBody.Required)
+		assert.Equal(t, "#/components/schemas/testItem", op.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, []Parameter{{Name: "Idempotency-Key", In: "header", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+	})
+
+	t.Run("Response profiles", func(t *testing.T) {
+		content := doc.Paths["/api/v1/orders"]["get"].Responses["200"].Content
+		require.Len(t, content, 2)
+		assert.Equal(t, "#/components/schemas/testOrder", content["application/json"].Schema.Items.Ref)
+		assert.Equal(t, "#/components/schemas/testPagetestOrder", content["application/vnd.test.v2+json"].Schema.Ref)
+
+		page := doc.Components.Schemas["testPagetestOrder"]
+		require.NotNil(t, page)
+		assert.Equal(t, "#/components/schemas/testOrder", page.Properties["items"].Items.Ref)
+	})
+
+	t.Run("Multipart upload", func(t *testing.T) {
+		op := doc.Paths["/api/v1/products/import"]["post"]
+		schema := op.RequestBody.Content["multipart/form-data"].Schema
+		assert.Equal(t, &Schema{Type: "string", Format: "binary"}, schema.Properties["file"])
+		assert.Equal(t, []string{"file"}, schema.Required)
+	})
+
+	t.Run("Struct schemas", func(t *testing.T) {
+		item := doc.Components.Schemas["testItem"]
+		require.NotNil(t, item)
+		assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, item.Properties["id"])
+		assert.Equal(t, &Schema{Type: "number", Format: "double"}, item.Properties["price"])
+		assert.Equal(t, &Schema{Type: "integer", Format: "int32"}, item.Properties["quantity"])
+		assert.Equal(t, &Schema{Type: "string", Nullable: true}, item.Properties["note"])
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, item.Properties["tags"])
+		assert.Equal(t, &Schema{}, item.Properties["metadata"])
+		assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, item.Properties["createdAt"])
+		assert.NotContains(t, item.Properties, "internal")
+		assert.ElementsMatch(t, []string{"id", "name", "price", "quantity", "createdAt"}, item.Required)
+
+		order := doc.Components.Schemas["testOrder"]
+		require.NotNil(t, order)
+		assert.Equal(t, &Schema{Type: "string"}, order.Properties["updatedBy"])
+		assert.Equal(t, "#/components/schemas/testItem", order.Properties["items"].Items.Ref)
+		assert.Equal(t, "#/components/schemas/testOrder", order.Properties["parent"].Ref)
+		assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, order.Properties["counts"].AdditionalProperties)
+		assert.NotContains(t, order.Properties, "Secret")
+		assert.ElementsMatch(t, []string{"updatedBy", "items", "counts"}, order.Required)
+	})
+}
+
+func TestSchemas_NameCollision(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	s := newSchemas()
+	s.components["Item"] = &Schema{Type: "object"}
+
+	assert.Equal(t, "#/components/schemas/OpenapiItem", s.of(Item{}).Ref)
+	assert.Equal(t, "#/components/schemas/OpenapiItem", s.of(&Item{}).Ref)
+	assert.Contains(t, s.components["OpenapiItem"].Properties, "name")
+}
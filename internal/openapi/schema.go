@@ -0,0 +1,185 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON schema in an OpenAPI document.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	uuidType       = reflect.TypeFor[uuid.UUID]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemas describes Go types as schemas, collecting named struct types as
+// components operations refer to.
+type schemas struct {
+	components map[string]*Schema
+
+	// names maps each component's type to its name, so types from different
+	// packages sharing a name get distinct components
+	names map[reflect.Type]string
+}
+
+// newSchemas creates an empty schema collection.
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of describes the type of v.
+func (s *schemas) of(v any) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+// schema describes how a value of type t is encoded by encoding/json.
+func (s *schemas) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := s.schema(t.Elem())
+		if elem.Ref == "" {
+			elem.Nullable = true
+		}
+		return elem
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+
+	// Interfaces and anything else may hold any value
+	return &Schema{}
+}
+
+// component registers a named struct type as a component and returns its name.
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	// Qualify a name already taken by another package's type with the
+	// package name, e.g. health.Status as HealthStatus
+	name := typeName(t)
+	if _, taken := s.components[name]; taken {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Register the name before describing the fields, so recursive types
+	// refer to themselves
+	s.names[t] = name
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.object(t)
+	return name
+}
+
+// object describes a struct's JSON fields. Fields that are omitted when
+// empty, or may be null, are optional.
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.fields(t, schema)
+	return schema
+}
+
+// fields adds the JSON fields of struct type t to schema, including those
+// promoted from embedded structs.
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.fields(embedded, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = s.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// typeName returns the component name of a named type. Instances of generic
+// types are named after the type and its type arguments, e.g.
+// Page[model.Order] as PageOrder, since brackets are not allowed in names.
+func typeName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return name
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		arg = strings.TrimLeft(arg, "*[]")
+		name += arg[strings.LastIndex(arg, ".")+1:]
+	}
+	return name
+}
@@ -0,0 +1,147 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"mini-kart/internal/model"
+)
+
+// Engine computes price breakdowns. It is shared by the price preview endpoint
+// and the order pipeline so both always agree on totals.
+type Engine interface {
+	// Price computes the breakdown for the given input.
+	Price(ctx context.Context, input Input) (*model.PriceBreakdown, error)
+}
+
+// Input holds everything needed to price a set of items.
+type Input struct {
+	// Items are the requested products and quantities.
+	Items []model.OrderItemRequest
+
+	// Products are the catalogue entries for the items.
+	Products []model.Product
+
+	// Address is the delivery address. Shipping is only charged when an address is given.
+	Address *model.Address
+
+	// Discount is the coupon discount applied to the subtotal of eligible
+	// lines. Nil means no discount.
+	Discount *model.CouponDiscount
+
+	// At is when the items are priced, so products on sale at that time are
+	// priced at their sale price. Zero prices them as of now.
+	At time.Time
+}
+
+// Config holds pricing engine configuration.
+type Config struct {
+	// Currency is the ISO 4217 code all catalogue prices are expressed in.
+	Currency string
+
+	// ShippingFlatRate is the shipping charge.
+	ShippingFlatRate model.Money
+}
+
+// engine implements Engine. All arithmetic is done in model.Money, integer
+// minor units, so totals never drift from the sum of their lines.
+type engine struct {
+	config Config
+}
+
+// NewEngine creates a new pricing engine.
+func NewEngine(config Config) Engine {
+	return &engine{config: config}
+}
+
+// Price computes line totals, subtotal, discount, shipping and grand total.
+func (e *engine) Price(ctx context.Context, input Input) (*model.PriceBreakdown, error) {
+	products := make(map[string]model.Product, len(input.Products))
+	for _, p := range input.Products {
+		products[p.ID] = p
+	}
+
+	at := input.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	breakdown := &model.PriceBreakdown{
+		Currency: e.config.Currency,
+		Lines:    make([]model.PriceLine, 0, len(input.Items)),
+	}
+
+	var subtotal, eligible model.Money
+	var eligibleLines int
+	for _, item := range input.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return nil, model.ErrProductNotFound
+		}
+		if item.Quantity <= 0 {
+			return nil, model.ErrInvalidQuantity
+		}
+
+		unitPrice, onSale := product.PriceAt(at)
+		lineTotal := unitPrice.Mul(item.Quantity)
+		subtotal += lineTotal
+		if input.Discount != nil && input.Discount.AppliesTo(product.Category) {
+			eligible += lineTotal
+			eligibleLines++
+		}
+
+		breakdown.Lines = append(breakdown.Lines, model.PriceLine{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+			LineTotal: lineTotal,
+			OnSale:    onSale,
+		})
+	}
+
+	// A category-restricted coupon on an order with nothing from those
+	// categories is a mistake worth surfacing rather than a silent zero
+	if input.Discount != nil && len(input.Discount.Categories) > 0 && eligibleLines == 0 {
+		return nil, model.ErrCouponNotApplicable
+	}
+	if input.Discount != nil && subtotal < input.Discount.MinSubtotal {
+		return nil, model.ErrCouponMinSubtotal
+	}
+	discount := discountFor(input.Discount, eligible)
+
+	var shipping model.Money
+	if input.Address != nil {
+		shipping = e.config.ShippingFlatRate
+	}
+	if shipping > 0 && input.Discount != nil && input.Discount.FreeShipping != nil &&
+		input.Discount.FreeShipping.Qualifies(subtotal, input.Address) {
+		shipping = 0
+		breakdown.FreeShipping = true
+	}
+
+	breakdown.Subtotal = subtotal
+	breakdown.Discount = discount
+	breakdown.Shipping = shipping
+	breakdown.Total = subtotal - discount + shipping
+
+	return breakdown, nil
+}
+
+// discountFor returns the discount for the subtotal of eligible lines.
+// Discounts never exceed that subtotal; shipping is not discounted.
+func discountFor(d *model.CouponDiscount, subtotal model.Money) model.Money {
+	if d == nil {
+		return 0
+	}
+
+	var discount model.Money
+	switch {
+	case d.PercentOff != nil:
+		discount = subtotal.Percent(*d.PercentOff)
+	case d.AmountOff != nil:
+		discount = *d.AmountOff
+	}
+
+	return min(max(discount, 0), subtotal)
+}
@@ -0,0 +1,284 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Price(t *testing.T) {
+	ctx := context.Background()
+
+	products := []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(12.99), Category: "Waffle"},
+		{ID: "P002", Name: "Coffee", Price: model.NewMoney(0.10), Category: "Drinks"},
+	}
+	saleEnd := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		input            Input
+		shippingFlatRate int64 // cents
+		expectedSubtotal float64
+		expectedDiscount float64
+		expectedShipping float64
+		expectedTotal    float64
+		expectFree       bool
+		expectedErr      error
+	}{
+		{
+			name: "Single line",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+				Products: products,
+			},
+			expectedSubtotal: 25.98,
+			expectedTotal:    25.98,
+		},
+		{
+			name: "Amounts add up without floating point drift",
+			input: Input{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P002", Quantity: 3},
+					{ProductID: "P001", Quantity: 1},
+				},
+				Products: products,
+			},
+			expectedSubtotal: 13.29,
+			expectedTotal:    13.29,
+		},
+		{
+			name: "Shipping charged when address given",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+				Address:  &model.Address{Country: "AU"},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 12.99,
+			expectedShipping: 9.95,
+			expectedTotal:    22.94,
+		},
+		{
+			name: "No shipping without address",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 12.99,
+			expectedTotal:    12.99,
+		},
+		{
+			name: "Percentage discount rounds to the nearest cent",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "TENPERCENT", PercentOff: ptr(10.0)},
+			},
+			expectedSubtotal: 12.99,
+			expectedDiscount: 1.30,
+			expectedTotal:    11.69,
+		},
+		{
+			name: "Fixed discount does not apply to shipping",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P002", Quantity: 2}},
+				Products: products,
+				Address:  &model.Address{Country: "AU"},
+				Discount: &model.CouponDiscount{Code: "FIVEOFF12", AmountOff: ptr(model.NewMoney(5.0))},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 0.20,
+			expectedDiscount: 0.20,
+			expectedShipping: 9.95,
+			expectedTotal:    9.95,
+		},
+		{
+			name: "Category-restricted discount applies to eligible lines only",
+			input: Input{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 2},
+					{ProductID: "P002", Quantity: 5},
+				},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "WAFFLE20", PercentOff: ptr(20.0), Categories: []string{"Waffle"}},
+			},
+			expectedSubtotal: 26.48,
+			expectedDiscount: 5.20,
+			expectedTotal:    21.28,
+		},
+		{
+			name: "Category-restricted fixed discount is capped at eligible lines",
+			input: Input{
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+					{ProductID: "P002", Quantity: 1},
+				},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "DRINKS500", AmountOff: ptr(model.NewMoney(5.0)), Categories: []string{"Drinks"}},
+			},
+			expectedSubtotal: 13.09,
+			expectedDiscount: 0.10,
+			expectedTotal:    12.99,
+		},
+		{
+			name: "No items in the coupon's categories",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P002", Quantity: 1}},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "WAFFLE20", PercentOff: ptr(20.0), Categories: []string{"Waffle"}},
+			},
+			expectedErr: model.ErrCouponNotApplicable,
+		},
+		{
+			name: "Subtotal below the coupon's minimum",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 3}},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "TENOFF50", AmountOff: ptr(model.NewMoney(10.0)), MinSubtotal: model.NewMoney(50)},
+			},
+			expectedErr: model.ErrCouponMinSubtotal,
+		},
+		{
+			name: "Subtotal meets the coupon's minimum",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 4}},
+				Products: products,
+				Discount: &model.CouponDiscount{Code: "TENOFF50", AmountOff: ptr(model.NewMoney(10.0)), MinSubtotal: model.NewMoney(50)},
+			},
+			expectedSubtotal: 51.96,
+			expectedDiscount: 10.00,
+			expectedTotal:    41.96,
+		},
+		{
+			name: "Free shipping waives the shipping charge",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+				Address:  &model.Address{Country: "AU"},
+				Discount: &model.CouponDiscount{Code: "SHIPFREE", FreeShipping: &model.FreeShipping{}},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 12.99,
+			expectedTotal:    12.99,
+			expectFree:       true,
+		},
+		{
+			name: "Free shipping combines with a percentage discount",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+				Address:  &model.Address{Country: "au"},
+				Discount: &model.CouponDiscount{
+					Code:         "TENSHIPAU",
+					PercentOff:   ptr(10.0),
+					FreeShipping: &model.FreeShipping{MinSubtotal: model.NewMoney(10), Countries: []string{"AU", "NZ"}},
+				},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 12.99,
+			expectedDiscount: 1.30,
+			expectedTotal:    11.69,
+			expectFree:       true,
+		},
+		{
+			name: "Free shipping below minimum subtotal",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P002", Quantity: 1}},
+				Products: products,
+				Address:  &model.Address{Country: "AU"},
+				Discount: &model.CouponDiscount{Code: "SHIPFREE50", FreeShipping: &model.FreeShipping{MinSubtotal: model.NewMoney(50)}},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 0.10,
+			expectedShipping: 9.95,
+			expectedTotal:    10.05,
+		},
+		{
+			name: "Free shipping outside eligible countries",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Products: products,
+				Address:  &model.Address{Country: "US"},
+				Discount: &model.CouponDiscount{Code: "SHIPFREEAU", FreeShipping: &model.FreeShipping{Countries: []string{"AU"}}},
+			},
+			shippingFlatRate: 995,
+			expectedSubtotal: 12.99,
+			expectedShipping: 9.95,
+			expectedTotal:    22.94,
+		},
+		{
+			name: "Sale price applies while the sale runs",
+			input: Input{
+				Items: []model.OrderItemRequest{{ProductID: "P003", Quantity: 2}},
+				Products: []model.Product{
+					{ID: "P003", Name: "Tea", Price: model.NewMoney(4.00), Category: "Drinks", Sale: &model.ProductSale{Price: model.NewMoney(3.25), EndsAt: &saleEnd}},
+				},
+				At: saleEnd.Add(-time.Minute),
+			},
+			expectedSubtotal: 6.50,
+			expectedTotal:    6.50,
+		},
+		{
+			name: "Regular price applies once the sale ends",
+			input: Input{
+				Items: []model.OrderItemRequest{{ProductID: "P003", Quantity: 2}},
+				Products: []model.Product{
+					{ID: "P003", Name: "Tea", Price: model.NewMoney(4.00), Category: "Drinks", Sale: &model.ProductSale{Price: model.NewMoney(3.25), EndsAt: &saleEnd}},
+				},
+				At: saleEnd,
+			},
+			expectedSubtotal: 8.00,
+			expectedTotal:    8.00,
+		},
+		{
+			name: "Unknown product",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P999", Quantity: 1}},
+				Products: products,
+			},
+			expectedErr: model.ErrProductNotFound,
+		},
+		{
+			name: "Invalid quantity",
+			input: Input{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 0}},
+				Products: products,
+			},
+			expectedErr: model.ErrInvalidQuantity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(Config{Currency: "AUD", ShippingFlatRate: model.MoneyFromCents(tt.shippingFlatRate)})
+
+			breakdown, err := engine.Price(ctx, tt.input)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, breakdown)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "AUD", breakdown.Currency)
+			assert.Len(t, breakdown.Lines, len(tt.input.Items))
+			assert.Equal(t, model.NewMoney(tt.expectedSubtotal), breakdown.Subtotal)
+			assert.Equal(t, model.NewMoney(tt.expectedDiscount), breakdown.Discount)
+			assert.Equal(t, model.NewMoney(tt.expectedShipping), breakdown.Shipping)
+			assert.Equal(t, tt.expectFree, breakdown.FreeShipping)
+			assert.Equal(t, model.NewMoney(tt.expectedTotal), breakdown.Total)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// insertAuditEntry records an audit entry within the provided transaction, so
+// the entry is only kept if the audited operation commits.
+func insertAuditEntry(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, action, entity_type, details)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRow(ctx, query, entry.Actor, entry.Action, entry.EntityType, entry.Details).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", Classify(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"mini-kart/internal/breaker"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// maxStaleEntries bounds the last successful product reads kept for serving
+// during database outages.
+const maxStaleEntries = 1000
+
+// breakingProductRepository guards product reads with a circuit breaker.
+// While the database is failing, reads are answered from the last successful
+// result for the same query; writes pass through unchanged.
+type breakingProductRepository struct {
+	ProductRepository
+	breaker *breaker.Breaker
+	logger  zerolog.Logger
+
+	mu    sync.RWMutex
+	stale map[string]any
+}
+
+// NewBreakingProductRepository wraps a product repository so catalogue reads
+// fail fast, or serve stale results, while the breaker is open.
+func NewBreakingProductRepository(repo ProductRepository, b *breaker.Breaker, logger zerolog.Logger) ProductRepository {
+	return &breakingProductRepository{
+		ProductRepository: repo,
+		breaker:           b,
+		logger:            logger.With().Str("repository", "product_breaker").Logger(),
+		stale:             make(map[string]any),
+	}
+}
+
+// GetAll retrieves products, falling back to the last result for the filter.
+func (r *breakingProductRepository) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, error) {
+	return readStale(ctx, r, fmt.Sprintf("all:%+v", filter), func() ([]model.Product, error) {
+		return r.ProductRepository.GetAll(ctx, filter)
+	})
+}
+
+// Count counts products, falling back to the last count for the filter.
+func (r *breakingProductRepository) Count(ctx context.Context, filter model.ProductFilter) (int, error) {
+	return readStale(ctx, r, fmt.Sprintf("count:%+v", filter), func() (int, error) {
+		return r.ProductRepository.Count(ctx, filter)
+	})
+}
+
+// GetByID retrieves a product, falling back to the last result for the ID.
+func (r *breakingProductRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	return readStale(ctx, r, "id:"+id, func() (*model.Product, error) {
+		return r.ProductRepository.GetByID(ctx, id)
+	})
+}
+
+// readStale runs read through the breaker, remembering successful results
+// under key and returning the remembered result when read fails. Results are
+// remembered per tenant, since tenants see different products for a query.
+func readStale[T any](ctx context.Context, r *breakingProductRepository, key string, read func() (T, error)) (T, error) {
+	if tenantID, ok := model.TenantFromContext(ctx); ok {
+		key += "@" + tenantID
+	}
+
+	var result T
+	err := r.breaker.Do(func() error {
+		var err error
+		result, err = read()
+		return err
+	})
+	if err == nil {
+		r.remember(key, result)
+		return result, nil
+	}
+
+	if stale, ok := r.recall(key); ok {
+		r.logger.Warn().Err(err).Str("query", strings.SplitN(key, ":", 2)[0]).Msg("serving stale products")
+		return stale.(T), nil
+	}
+
+	var zero T
+	return zero, unavailable(err)
+}
+
+// remember stores a successful read. Once full, new queries are not stored
+// but already stored ones keep being refreshed.
+func (r *breakingProductRepository) remember(key string, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.stale[key]; ok || len(r.stale) < maxStaleEntries {
+		r.stale[key] = value
+	}
+}
+
+// recall returns the last successful read for key, if any.
+func (r *breakingProductRepository) recall(key string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.stale[key]
+	return value, ok
+}
+
+// breakingOrderRepository guards order reads with a circuit breaker. Order
+// data changes too often to serve stale, so reads fail fast with
+// model.ErrDatabaseUnavailable while the breaker is open.
+type breakingOrderRepository struct {
+	OrderRepository
+	breaker *breaker.Breaker
+}
+
+// NewBreakingOrderRepository wraps an order repository so reads fail fast
+// while the breaker is open. Writes pass through unchanged.
+func NewBreakingOrderRepository(repo OrderRepository, b *breaker.Breaker) OrderRepository {
+	return &breakingOrderRepository{
+		OrderRepository: repo,
+		breaker:         b,
+	}
+}
+
+// GetByID retrieves an order and its items through the breaker.
+func (r *breakingOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, []model.OrderItem, error) {
+	var order *model.Order
+	var items []model.OrderItem
+	err := r.breaker.Do(func() error {
+		var err error
+		order, items, err = r.OrderRepository.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, unavailable(err)
+	}
+	return order, items, nil
+}
+
+// GetIDByRef looks up an order by reference through the breaker.
+func (r *breakingOrderRepository) GetIDByRef(ctx context.Context, ref string) (*uuid.UUID, error) {
+	var id *uuid.UUID
+	err := r.breaker.Do(func() error {
+		var err error
+		id, err = r.OrderRepository.GetIDByRef(ctx, ref)
+		return err
+	})
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	return id, nil
+}
+
+// List retrieves orders through the breaker.
+func (r *breakingOrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]model.Order, error) {
+	var orders []model.Order
+	err := r.breaker.Do(func() error {
+		var err error
+		orders, err = r.OrderRepository.List(ctx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	return orders, nil
+}
+
+// Count counts orders through the breaker.
+func (r *breakingOrderRepository) Count(ctx context.Context, filter model.OrderFilter) (int, error) {
+	var count int
+	err := r.breaker.Do(func() error {
+		var err error
+		count, err = r.OrderRepository.Count(ctx, filter)
+		return err
+	})
+	if err != nil {
+		return 0, unavailable(err)
+	}
+	return count, nil
+}
+
+// unavailable translates a rejected call into model.ErrDatabaseUnavailable.
+// Other errors are returned unchanged.
+func unavailable(err error) error {
+	if errors.Is(err, breaker.ErrOpen) {
+		return model.ErrDatabaseUnavailable
+	}
+	return err
+}
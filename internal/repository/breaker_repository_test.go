@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/breaker"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyProductRepository serves products until down is set. Methods the
+// tests do not use are left to the nil embedded interface.
+type flakyProductRepository struct {
+	ProductRepository
+	down  bool
+	calls int
+}
+
+func (r *flakyProductRepository) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, error) {
+	r.calls++
+	if r.down {
+		return nil, errors.New("connection refused")
+	}
+	return []model.Product{{ID: "P001", Name: "Chicken Waffle", Category: filter.Category}}, nil
+}
+
+func (r *flakyProductRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	r.calls++
+	if r.down {
+		return nil, errors.New("connection refused")
+	}
+	return &model.Product{ID: id, Name: "Chicken Waffle"}, nil
+}
+
+// flakyOrderRepository fails every read.
+type flakyOrderRepository struct {
+	OrderRepository
+	calls int
+}
+
+func (r *flakyOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, []model.OrderItem, error) {
+	r.calls++
+	return nil, nil, errors.New("connection refused")
+}
+
+func TestBreakingProductRepository(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	t.Run("Serves stale results while the database is down", func(t *testing.T) {
+		inner := &flakyProductRepository{}
+		b := breaker.New("database", breaker.Config{FailureThreshold: 1, Cooldown: time.Minute}, logger)
+		repo := NewBreakingProductRepository(inner, b, logger)
+
+		fresh, err := repo.GetAll(ctx, model.ProductFilter{Category: "Waffle"})
+		require.NoError(t, err)
+
+		inner.down = true
+		stale, err := repo.GetAll(ctx, model.ProductFilter{Category: "Waffle"})
+		require.NoError(t, err)
+		assert.Equal(t, fresh, stale)
+		assert.Equal(t, breaker.StateOpen, b.State())
+
+		// The open breaker answers without touching the database
+		calls := inner.calls
+		_, err = repo.GetAll(ctx, model.ProductFilter{Category: "Waffle"})
+		require.NoError(t, err)
+		assert.Equal(t, calls, inner.calls)
+	})
+
+	t.Run("Does not serve another tenant's stale results", func(t *testing.T) {
+		inner := &flakyProductRepository{}
+		b := breaker.New("database", breaker.Config{FailureThreshold: 1, Cooldown: time.Minute}, logger)
+		repo := NewBreakingProductRepository(inner, b, logger)
+
+		_, err := repo.GetByID(model.WithTenant(ctx, "acme"), "P001")
+		require.NoError(t, err)
+
+		inner.down = true
+		product, err := repo.GetByID(model.WithTenant(ctx, "globex"), "P001")
+		assert.Error(t, err)
+		assert.Nil(t, product)
+	})
+
+	t.Run("Fails fast without a stale result", func(t *testing.T) {
+		inner := &flakyProductRepository{down: true}
+		b := breaker.New("database", breaker.Config{FailureThreshold: 1, Cooldown: time.Minute}, logger)
+		repo := NewBreakingProductRepository(inner, b, logger)
+
+		_, err := repo.GetByID(ctx, "P001")
+		require.Error(t, err)
+		assert.NotEqual(t, model.ErrDatabaseUnavailable, err)
+
+		product, err := repo.GetByID(ctx, "P001")
+		assert.Equal(t, model.ErrDatabaseUnavailable, err)
+		assert.Nil(t, product)
+		assert.Equal(t, 1, inner.calls)
+	})
+}
+
+func TestBreakingOrderRepository(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	inner := &flakyOrderRepository{}
+	b := breaker.New("database", breaker.Config{FailureThreshold: 2, Cooldown: time.Minute}, logger)
+	repo := NewBreakingOrderRepository(inner, b)
+
+	for range 2 {
+		_, _, err := repo.GetByID(ctx, uuid.New())
+		require.Error(t, err)
+	}
+
+	order, items, err := repo.GetByID(ctx, uuid.New())
+	assert.Equal(t, model.ErrDatabaseUnavailable, err)
+	assert.Nil(t, order)
+	assert.Nil(t, items)
+	assert.Equal(t, 2, inner.calls)
+}
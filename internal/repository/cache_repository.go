@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"mini-kart/internal/cache"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// cachedProductRepository caches products by ID in Redis, where every API
+// replica shares them. Product changes made through the repository drop the
+// cached copies, so no replica serves them stale; changes made around it,
+// such as seeding the catalogue, show once the cached copies expire. While
+// Redis is failing, products are read from the database.
+type cachedProductRepository struct {
+	ProductRepository
+	cache    *cache.Client
+	ttl      time.Duration
+	counters metrics.CacheCounters
+	logger   zerolog.Logger
+}
+
+// NewCachedProductRepository wraps a product repository so products read by
+// ID are cached for ttl. Each product looked up counts as a hit or a miss,
+// and each dropped copy as an eviction.
+func NewCachedProductRepository(repo ProductRepository, c *cache.Client, ttl time.Duration, counters metrics.CacheCounters, logger zerolog.Logger) ProductRepository {
+	return &cachedProductRepository{
+		ProductRepository: repo,
+		cache:             c,
+		ttl:               ttl,
+		counters:          counters,
+		logger:            logger.With().Str("repository", "product_cache").Logger(),
+	}
+}
+
+// GetByID retrieves a product from the cache, or from the database when it
+// is not cached.
+func (r *cachedProductRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	key := productCacheKey(ctx, id)
+	data, err := r.cache.Get(ctx, key)
+	if err == nil {
+		var product model.Product
+		if err := json.Unmarshal(data, &product); err == nil {
+			r.counters.Hits.Inc()
+			return &product, nil
+		}
+	} else if !errors.Is(err, cache.ErrMiss) {
+		r.logger.Warn().Err(err).Str("product_id", id).Msg("failed to read cached product")
+	}
+
+	r.counters.Misses.Inc()
+	product, err := r.ProductRepository.GetByID(ctx, id)
+	if err != nil || product == nil {
+		return product, err
+	}
+	r.store(ctx, []model.Product{*product})
+	return product, nil
+}
+
+// GetByIDs retrieves products from the cache, reading those that are not
+// cached from the database. Products are sorted by name, like the database
+// returns them.
+func (r *cachedProductRepository) GetByIDs(ctx context.Context, ids []string) ([]model.Product, error) {
+	if len(ids) == 0 {
+		return []model.Product{}, nil
+	}
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	keys := make([]string, len(unique))
+	for i, id := range unique {
+		keys[i] = productCacheKey(ctx, id)
+	}
+
+	cached, err := r.cache.GetMany(ctx, keys)
+	if err != nil {
+		r.logger.Warn().Err(err).Int("count", len(keys)).Msg("failed to read cached products")
+		cached = make([][]byte, len(keys))
+	}
+
+	var products []model.Product
+	var missing []string
+	for i, data := range cached {
+		var product model.Product
+		if data == nil || json.Unmarshal(data, &product) != nil {
+			missing = append(missing, unique[i])
+			continue
+		}
+		products = append(products, product)
+	}
+	r.counters.Hits.Add(int64(len(products)))
+	r.counters.Misses.Add(int64(len(missing)))
+
+	if len(missing) > 0 {
+		found, err := r.ProductRepository.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		r.store(ctx, found)
+		products = append(products, found...)
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		return products[i].Name < products[j].Name
+	})
+	return products, nil
+}
+
+// Update changes a product and drops its cached copies.
+func (r *cachedProductRepository) Update(ctx context.Context, product *model.Product) error {
+	defer r.drop(ctx, product.ID)
+	return r.ProductRepository.Update(ctx, product)
+}
+
+// SetSale changes a product's sale and drops its cached copies.
+func (r *cachedProductRepository) SetSale(ctx context.Context, product *model.Product) error {
+	defer r.drop(ctx, product.ID)
+	return r.ProductRepository.SetSale(ctx, product)
+}
+
+// Delete removes a product and drops its cached copies.
+func (r *cachedProductRepository) Delete(ctx context.Context, id string) error {
+	defer r.drop(ctx, id)
+	return r.ProductRepository.Delete(ctx, id)
+}
+
+// Archive hides products and drops their cached copies.
+func (r *cachedProductRepository) Archive(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error) {
+	defer r.drop(ctx, ids...)
+	return r.ProductRepository.Archive(ctx, ids, actor)
+}
+
+// store caches products read from the database.
+func (r *cachedProductRepository) store(ctx context.Context, products []model.Product) {
+	values := make(map[string][]byte, len(products))
+	for _, product := range products {
+		data, err := json.Marshal(product)
+		if err != nil {
+			continue
+		}
+		values[productCacheKey(ctx, product.ID)] = data
+	}
+
+	if err := r.cache.SetMany(ctx, values, r.ttl); err != nil {
+		r.logger.Warn().Err(err).Int("count", len(values)).Msg("failed to cache products")
+	}
+}
+
+// drop removes the cached copies of products.
+func (r *cachedProductRepository) drop(ctx context.Context, ids ...string) {
+	dropCachedProducts(ctx, r.cache, r.counters, r.logger, ids...)
+}
+
+// cachedPriceChangeRepository drops the cached copies of products whose
+// price it changes.
+type cachedPriceChangeRepository struct {
+	PriceChangeRepository
+	cache    *cache.Client
+	counters metrics.CacheCounters
+	logger   zerolog.Logger
+}
+
+// NewCachedPriceChangeRepository wraps a price change repository so products
+// cached by NewCachedProductRepository are dropped when their price changes.
+// counters are the product cache's.
+func NewCachedPriceChangeRepository(repo PriceChangeRepository, c *cache.Client, counters metrics.CacheCounters, logger zerolog.Logger) PriceChangeRepository {
+	return &cachedPriceChangeRepository{
+		PriceChangeRepository: repo,
+		cache:                 c,
+		counters:              counters,
+		logger:                logger.With().Str("repository", "price_change_cache").Logger(),
+	}
+}
+
+// Create records a price change, dropping the product's cached copies when
+// the change is applied immediately.
+func (r *cachedPriceChangeRepository) Create(ctx context.Context, change *model.PriceChange) error {
+	err := r.PriceChangeRepository.Create(ctx, change)
+	if err == nil && change.Status == model.PriceChangeStatusApplied {
+		dropCachedProducts(ctx, r.cache, r.counters, r.logger, change.ProductID)
+	}
+	return err
+}
+
+// Decide approves or rejects a price change, dropping the product's cached
+// copies when it is approved.
+func (r *cachedPriceChangeRepository) Decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error) {
+	change, err := r.PriceChangeRepository.Decide(ctx, id, status, decidedBy)
+	if err == nil && status == model.PriceChangeStatusApproved {
+		dropCachedProducts(ctx, r.cache, r.counters, r.logger, change.ProductID)
+	}
+	return change, err
+}
+
+// productCacheKey returns the key a product is cached under for ctx.
+// Products are cached per tenant, since tenants only see their own products.
+func productCacheKey(ctx context.Context, id string) string {
+	if tenantID := tenantScope(ctx); tenantID != nil {
+		return "product:" + *tenantID + ":" + id
+	}
+	return "product:*:" + id
+}
+
+// dropCachedProducts removes the copies of products cached for ctx's tenant
+// and for unscoped reads, the only ones that can hold a tenant's product.
+func dropCachedProducts(ctx context.Context, c *cache.Client, counters metrics.CacheCounters, logger zerolog.Logger, ids ...string) {
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, "product:"+tenantOf(ctx)+":"+id, "product:*:"+id)
+	}
+
+	dropped, err := c.Delete(ctx, keys...)
+	if err != nil {
+		logger.Error().Err(err).Strs("product_ids", ids).Msg("failed to drop cached products")
+		return
+	}
+	counters.Evictions.Add(dropped)
+}
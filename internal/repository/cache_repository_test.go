@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/cache"
+	"mini-kart/internal/config"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProductRepository serves products from a map and counts the
+// products it reads. Methods the tests do not use are left to the nil
+// embedded interface.
+type countingProductRepository struct {
+	ProductRepository
+	products map[string]model.Product
+	reads    int
+}
+
+func (r *countingProductRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
+	r.reads++
+	product, ok := r.products[id]
+	if !ok {
+		return nil, nil
+	}
+	return &product, nil
+}
+
+func (r *countingProductRepository) GetByIDs(ctx context.Context, ids []string) ([]model.Product, error) {
+	var products []model.Product
+	for _, id := range ids {
+		r.reads++
+		if product, ok := r.products[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *countingProductRepository) Update(ctx context.Context, product *model.Product) error {
+	r.products[product.ID] = *product
+	return nil
+}
+
+// stubPriceChangeRepository applies price changes to a counting repository.
+type stubPriceChangeRepository struct {
+	PriceChangeRepository
+	products *countingProductRepository
+}
+
+func (r *stubPriceChangeRepository) Decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error) {
+	product := r.products.products["P001"]
+	product.Price = model.NewMoney(15)
+	r.products.products["P001"] = product
+	return &model.PriceChange{ID: id, ProductID: "P001", NewPrice: model.NewMoney(15), Status: status}, nil
+}
+
+func TestCachedProductRepository(t *testing.T) {
+	ctx := model.WithTenant(context.Background(), "acme")
+	logger := zerolog.Nop()
+
+	setup := func(t *testing.T) (*countingProductRepository, ProductRepository, *cache.Client, *miniredis.Miniredis, metrics.CacheCounters) {
+		server := miniredis.RunT(t)
+		c, err := cache.New(context.Background(), config.RedisConfig{Addr: server.Addr()}, logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { c.Close() })
+
+		inner := &countingProductRepository{products: map[string]model.Product{
+			"P001": {ID: "P001", Name: "Waffle", Price: model.NewMoney(10), Category: "Waffle", Metadata: map[string]any{"weight": 120.0}},
+			"P002": {ID: "P002", Name: "Lemon Tart", Price: model.NewMoney(4), Category: "Tart"},
+		}}
+		counters := metrics.NewRegistry().Cache("product")
+		return inner, NewCachedProductRepository(inner, c, time.Minute, counters, logger), c, server, counters
+	}
+
+	t.Run("Reads each product from the database once", func(t *testing.T) {
+		inner, repo, _, server, counters := setup(t)
+
+		product, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		cached, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+
+		assert.Equal(t, product, cached)
+		assert.Equal(t, 1, inner.reads)
+		assert.True(t, server.Exists("mini-kart:product:acme:P001"))
+
+		products, err := repo.GetByIDs(ctx, []string{"P001", "P002", "P002", "P404"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P002", "P001"}, []string{products[0].ID, products[1].ID}, "sorted by name")
+		assert.Len(t, products, 2)
+		assert.Equal(t, 3, inner.reads, "only P002 and P404 were read")
+		assert.Equal(t, int64(2), counters.Hits.Value())
+		assert.Equal(t, int64(3), counters.Misses.Value())
+
+		// Products are cached per tenant
+		_, err = repo.GetByID(model.WithTenant(context.Background(), "globex"), "P001")
+		require.NoError(t, err)
+		assert.Equal(t, 4, inner.reads)
+	})
+
+	t.Run("Missing products are not cached", func(t *testing.T) {
+		inner, repo, _, _, _ := setup(t)
+
+		for range 2 {
+			product, err := repo.GetByID(ctx, "P404")
+			require.NoError(t, err)
+			assert.Nil(t, product)
+		}
+		assert.Equal(t, 2, inner.reads)
+	})
+
+	t.Run("Writes drop cached products", func(t *testing.T) {
+		inner, repo, c, _, counters := setup(t)
+
+		_, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Update(ctx, &model.Product{ID: "P001", Name: "Belgian Waffle", Price: model.NewMoney(10), Category: "Waffle"}))
+		product, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.Equal(t, "Belgian Waffle", product.Name)
+
+		priceChanges := NewCachedPriceChangeRepository(&stubPriceChangeRepository{products: inner}, c, counters, logger)
+		_, err = priceChanges.Decide(ctx, uuid.New(), model.PriceChangeStatusApproved, "admin")
+		require.NoError(t, err)
+		product, err = repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.Equal(t, model.NewMoney(15.0), product.Price)
+		assert.Equal(t, int64(2), counters.Evictions.Value())
+	})
+
+	t.Run("Reads from the database while Redis is down", func(t *testing.T) {
+		inner, repo, _, server, counters := setup(t)
+		server.Close()
+
+		product, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.Equal(t, "Waffle", product.Name)
+
+		products, err := repo.GetByIDs(ctx, []string{"P001", "P002"})
+		require.NoError(t, err)
+		assert.Len(t, products, 2)
+		assert.Equal(t, 3, inner.reads)
+		assert.Equal(t, int64(3), counters.Misses.Value())
+	})
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// couponCodeRepository implements CouponCodeRepository using PostgreSQL.
+// Coupon sets are shared by every tenant; they are loaded by a
+// deployment-wide background job, so no query is scoped to a tenant.
+type couponCodeRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewCouponCodeRepository creates a new PostgreSQL-backed coupon code repository.
+func NewCouponCodeRepository(pool *pgxpool.Pool, logger zerolog.Logger) CouponCodeRepository {
+	return &couponCodeRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "coupon_code").Logger(),
+	}
+}
+
+// StreamCodes calls fn for every code in the named coupon set.
+// Rows are streamed so large sets are never buffered in full.
+func (r *couponCodeRepository) StreamCodes(ctx context.Context, set string, fn func(code string) error) error {
+	rows, err := r.pool.Query(ctx, `SELECT code FROM coupon_codes WHERE coupon_set = $1`, set)
+	if err != nil {
+		r.logger.Error().Err(err).Str("coupon_set", set).Msg("failed to query coupon codes")
+		return fmt.Errorf("failed to query coupon codes: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var code string
+	for rows.Next() {
+		if err := rows.Scan(&code); err != nil {
+			return fmt.Errorf("failed to scan coupon code: %w", Classify(err))
+		}
+		if err := fn(code); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Str("coupon_set", set).Msg("error iterating coupon codes")
+		return fmt.Errorf("error iterating coupon codes: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// ReplaceSet atomically replaces the named coupon set.
+// Codes are copied into a staging table first so duplicates in the source
+// are collapsed and readers keep seeing the previous set until commit.
+func (r *couponCodeRepository) ReplaceSet(ctx context.Context, set string, next func() (string, bool, error)) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `CREATE TEMP TABLE coupon_codes_staging (code TEXT NOT NULL) ON COMMIT DROP`); err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", Classify(err))
+	}
+
+	source := pgx.CopyFromFunc(func() ([]any, error) {
+		code, ok, err := next()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []any{code}, nil
+	})
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"coupon_codes_staging"}, []string{"code"}, source); err != nil {
+		r.logger.Error().Err(err).Str("coupon_set", set).Msg("failed to copy coupon codes")
+		return 0, fmt.Errorf("failed to copy coupon codes: %w", Classify(err))
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM coupon_codes WHERE coupon_set = $1`, set); err != nil {
+		return 0, fmt.Errorf("failed to clear coupon set: %w", Classify(err))
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO coupon_codes (coupon_set, code)
+		SELECT DISTINCT $1, code FROM coupon_codes_staging
+	`, set)
+	if err != nil {
+		r.logger.Error().Err(err).Str("coupon_set", set).Msg("failed to store coupon codes")
+		return 0, fmt.Errorf("failed to store coupon codes: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit coupon set: %w", Classify(err))
+	}
+
+	r.logger.Info().
+		Str("coupon_set", set).
+		Int64("codes", tag.RowsAffected()).
+		Msg("coupon set replaced")
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCouponCodeSchema creates the coupon_codes table for testing.
+func createCouponCodeSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS coupon_codes (
+			coupon_set TEXT NOT NULL,
+			code TEXT NOT NULL,
+			PRIMARY KEY (coupon_set, code)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+// codeIterator returns a ReplaceSet source over a fixed list of codes.
+func codeIterator(codes []string) func() (string, bool, error) {
+	i := 0
+	return func() (string, bool, error) {
+		if i >= len(codes) {
+			return "", false, nil
+		}
+		i++
+		return codes[i-1], true, nil
+	}
+}
+
+// streamAll collects every code in a set.
+func streamAll(t *testing.T, repo CouponCodeRepository, set string) []string {
+	var codes []string
+	err := repo.StreamCodes(context.Background(), set, func(code string) error {
+		codes = append(codes, code)
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(codes)
+	return codes
+}
+
+func TestCouponCodeRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createCouponCodeSchema(t, pool)
+
+	ctx := context.Background()
+	repo := NewCouponCodeRepository(pool, zerolog.Nop())
+
+	t.Run("Replace collapses duplicates", func(t *testing.T) {
+		count, err := repo.ReplaceSet(ctx, "couponbase1", codeIterator([]string{"HAPPYHRS", "FIFTYOFF", "HAPPYHRS"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		assert.Equal(t, []string{"FIFTYOFF", "HAPPYHRS"}, streamAll(t, repo, "couponbase1"))
+	})
+
+	t.Run("Replace swaps the whole set", func(t *testing.T) {
+		_, err := repo.ReplaceSet(ctx, "couponbase2", codeIterator([]string{"OTHERSET"}))
+		require.NoError(t, err)
+
+		count, err := repo.ReplaceSet(ctx, "couponbase1", codeIterator([]string{"NEWCODE1"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.Equal(t, []string{"NEWCODE1"}, streamAll(t, repo, "couponbase1"))
+		assert.Equal(t, []string{"OTHERSET"}, streamAll(t, repo, "couponbase2"))
+	})
+
+	t.Run("Unknown set streams nothing", func(t *testing.T) {
+		assert.Empty(t, streamAll(t, repo, "couponbase9"))
+	})
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// couponDiscountRepository implements CouponDiscountRepository using PostgreSQL.
+type couponDiscountRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewCouponDiscountRepository creates a new PostgreSQL-backed coupon discount repository.
+func NewCouponDiscountRepository(pool *pgxpool.Pool, logger zerolog.Logger) CouponDiscountRepository {
+	return &couponDiscountRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "coupon_discount").Logger(),
+	}
+}
+
+// GetByCode retrieves the discount the context's tenant configured for a
+// coupon code.
+func (r *couponDiscountRepository) GetByCode(ctx context.Context, code string) (*model.CouponDiscount, error) {
+	query := `
+		SELECT code, percent_off, amount_off, COALESCE(categories, '{}'), first_order_only,
+		       expires_at, COALESCE(min_subtotal, 0), free_shipping, COALESCE(free_shipping_min_subtotal, 0), COALESCE(free_shipping_countries, '{}'),
+		       COALESCE(customer_ids, '{}'), COALESCE(segments, '{}')
+		FROM coupon_discounts
+		WHERE code = $1 AND tenant_id = $2
+	`
+
+	var discount model.CouponDiscount
+	var freeShipping bool
+	var shipping model.FreeShipping
+	err := r.pool.QueryRow(ctx, query, code, tenantOf(ctx)).Scan(
+		&discount.Code,
+		&discount.PercentOff,
+		&discount.AmountOff,
+		&discount.Categories,
+		&discount.FirstOrderOnly,
+		&discount.ExpiresAt,
+		&discount.MinSubtotal,
+		&freeShipping,
+		&shipping.MinSubtotal,
+		&shipping.Countries,
+		&discount.CustomerIDs,
+		&discount.Segments,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("coupon_code", code).Msg("failed to query coupon discount")
+		return nil, fmt.Errorf("failed to query coupon discount: %w", Classify(err))
+	}
+
+	if freeShipping {
+		discount.FreeShipping = &shipping
+	}
+
+	return &discount, nil
+}
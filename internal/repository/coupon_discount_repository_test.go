@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCouponDiscountSchema creates the coupon_discounts table for testing.
+func createCouponDiscountSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS coupon_discounts (
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			code TEXT NOT NULL,
+			percent_off DECIMAL(5,2) CHECK (percent_off > 0 AND percent_off <= 100),
+			amount_off BIGINT CHECK (amount_off > 0),
+			categories TEXT[],
+			first_order_only BOOLEAN NOT NULL DEFAULT FALSE,
+			expires_at TIMESTAMPTZ,
+			min_subtotal BIGINT CHECK (min_subtotal >= 0),
+			free_shipping BOOLEAN NOT NULL DEFAULT FALSE,
+			free_shipping_min_subtotal BIGINT CHECK (free_shipping_min_subtotal >= 0),
+			free_shipping_countries TEXT[],
+			customer_ids UUID[],
+			segments TEXT[],
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, code),
+			CONSTRAINT chk_coupon_discounts_kind CHECK (
+				(percent_off IS NULL OR amount_off IS NULL)
+				AND (percent_off IS NOT NULL OR amount_off IS NOT NULL OR free_shipping)
+			)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestCouponDiscountRepository_GetByCode(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createCouponDiscountSchema(t, pool)
+
+	logger := zerolog.Nop()
+	repo := NewCouponDiscountRepository(pool, logger)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO coupon_discounts (code, percent_off, amount_off, categories, first_order_only) VALUES
+			('QUARTER25', 25, NULL, NULL, FALSE),
+			('FIVEOFF12', NULL, 500, NULL, FALSE),
+			('WAFFLE20', 20, NULL, '{Waffle,Dessert}', FALSE),
+			('WELCOME15', 15, NULL, NULL, TRUE)
+	`)
+	require.NoError(t, err)
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO coupon_discounts (code, free_shipping, free_shipping_min_subtotal, free_shipping_countries) VALUES
+			('SHIPFREE50', TRUE, 5000, '{AU,NZ}')
+	`)
+	require.NoError(t, err)
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO coupon_discounts (code, amount_off, expires_at, min_subtotal) VALUES
+			('SUMMER1050', 1000, '2026-03-01T00:00:00Z', 5000)
+	`)
+	require.NoError(t, err)
+
+	staffID := uuid.New()
+	_, err = pool.Exec(ctx, `
+		INSERT INTO coupon_discounts (code, percent_off, customer_ids, segments) VALUES
+			('STAFF30', 30, ARRAY[$1::uuid], '{staff,vip}')
+	`, staffID)
+	require.NoError(t, err)
+
+	t.Run("Percentage discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "QUARTER25")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		require.NotNil(t, discount.PercentOff)
+		assert.Equal(t, 25.0, *discount.PercentOff)
+		assert.Nil(t, discount.AmountOff)
+		assert.Empty(t, discount.Categories)
+		assert.False(t, discount.FirstOrderOnly)
+		assert.Nil(t, discount.ExpiresAt)
+		assert.Zero(t, discount.MinSubtotal)
+		assert.Nil(t, discount.FreeShipping)
+	})
+
+	t.Run("Expiring discount with minimum subtotal", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "SUMMER1050")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		require.NotNil(t, discount.ExpiresAt)
+		assert.True(t, discount.ExpiresAt.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, model.NewMoney(50.0), discount.MinSubtotal)
+	})
+
+	t.Run("Free shipping discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "SHIPFREE50")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		assert.Nil(t, discount.PercentOff)
+		assert.Nil(t, discount.AmountOff)
+		require.NotNil(t, discount.FreeShipping)
+		assert.Equal(t, model.NewMoney(50.0), discount.FreeShipping.MinSubtotal)
+		assert.Equal(t, []string{"AU", "NZ"}, discount.FreeShipping.Countries)
+	})
+
+	t.Run("First-order-only discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "WELCOME15")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		assert.True(t, discount.FirstOrderOnly)
+	})
+
+	t.Run("Customer-restricted discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "STAFF30")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		assert.Equal(t, []uuid.UUID{staffID}, discount.CustomerIDs)
+		assert.Equal(t, []string{"staff", "vip"}, discount.Segments)
+		assert.True(t, discount.Restricted())
+	})
+
+	t.Run("Unrestricted discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "QUARTER25")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		assert.Empty(t, discount.CustomerIDs)
+		assert.Empty(t, discount.Segments)
+		assert.False(t, discount.Restricted())
+	})
+
+	t.Run("Category-restricted discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "WAFFLE20")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		assert.Equal(t, []string{"Waffle", "Dessert"}, discount.Categories)
+	})
+
+	t.Run("Fixed amount discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "FIVEOFF12")
+		require.NoError(t, err)
+		require.NotNil(t, discount)
+		require.NotNil(t, discount.AmountOff)
+		assert.Equal(t, model.NewMoney(5.0), *discount.AmountOff)
+		assert.Nil(t, discount.PercentOff)
+	})
+
+	t.Run("Code without a discount", func(t *testing.T) {
+		discount, err := repo.GetByCode(ctx, "NODISCOUNT")
+		require.NoError(t, err)
+		assert.Nil(t, discount)
+	})
+
+	t.Run("Rejects a discount with both kinds set", func(t *testing.T) {
+		_, err := pool.Exec(ctx, "INSERT INTO coupon_discounts (code, percent_off, amount_off) VALUES ('BOTHKINDS', 10, 5)")
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects a discount with no effect", func(t *testing.T) {
+		_, err := pool.Exec(ctx, "INSERT INTO coupon_discounts (code) VALUES ('NOEFFECT')")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// couponFileRepository implements CouponFileRepository using PostgreSQL.
+type couponFileRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewCouponFileRepository creates a new PostgreSQL-backed coupon file registry.
+func NewCouponFileRepository(pool *pgxpool.Pool, logger zerolog.Logger) CouponFileRepository {
+	return &couponFileRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "coupon_file").Logger(),
+	}
+}
+
+// Register records an uploaded coupon file. Returns model.ErrCouponFileExists
+// if the same file is already registered.
+func (r *couponFileRepository) Register(ctx context.Context, file *model.CouponFile) error {
+	query := `
+		INSERT INTO coupon_files (id, name, source, checksum, size_bytes, uploaded_by, uploaded_at, registered_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING registered_at
+	`
+
+	file.ID = uuid.New()
+	err := r.pool.QueryRow(ctx, query,
+		file.ID,
+		file.Name,
+		file.Source,
+		file.Checksum,
+		file.SizeBytes,
+		file.UploadedBy,
+		file.UploadedAt,
+		file.RegisteredBy,
+	).Scan(&file.RegisteredAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("name", file.Name).Str("checksum", file.Checksum).Msg("coupon file already registered")
+			return model.ErrCouponFileExists
+		}
+		r.logger.Error().Err(err).Str("name", file.Name).Msg("failed to register coupon file")
+		return fmt.Errorf("failed to register coupon file: %w", Classify(err))
+	}
+
+	r.logger.Info().
+		Str("file_id", file.ID.String()).
+		Str("name", file.Name).
+		Str("checksum", file.Checksum).
+		Msg("coupon file registered")
+
+	return nil
+}
+
+// List retrieves registered coupon files, newest upload first.
+func (r *couponFileRepository) List(ctx context.Context, name string) ([]model.CouponFile, error) {
+	query := `
+		SELECT id, name, source, checksum, size_bytes, uploaded_by, uploaded_at, registered_by, registered_at
+		FROM coupon_files
+		WHERE $1 = '' OR name = $1
+		ORDER BY uploaded_at DESC, registered_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, name)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query coupon files")
+		return nil, fmt.Errorf("failed to query coupon files: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	files := []model.CouponFile{}
+	for rows.Next() {
+		var f model.CouponFile
+		err := rows.Scan(
+			&f.ID,
+			&f.Name,
+			&f.Source,
+			&f.Checksum,
+			&f.SizeBytes,
+			&f.UploadedBy,
+			&f.UploadedAt,
+			&f.RegisteredBy,
+			&f.RegisteredAt,
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan coupon file row")
+			return nil, fmt.Errorf("failed to scan coupon file: %w", Classify(err))
+		}
+		files = append(files, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating coupon file rows")
+		return nil, fmt.Errorf("error iterating coupon files: %w", Classify(err))
+	}
+
+	return files, nil
+}
+
+// RecordGeneration records a load of the coupon files and its files in one
+// transaction, retiring the instance's previous generation as of the load.
+func (r *couponFileRepository) RecordGeneration(ctx context.Context, generation *model.CouponGeneration) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	retire := `
+		UPDATE coupon_generations
+		SET retired_at = $2
+		WHERE instance = $1 AND retired_at IS NULL AND loaded_at <= $2
+	`
+	if _, err := tx.Exec(ctx, retire, generation.Instance, generation.LoadedAt); err != nil {
+		r.logger.Error().Err(err).Str("instance", generation.Instance).Msg("failed to retire coupon generation")
+		return fmt.Errorf("failed to retire coupon generation: %w", Classify(err))
+	}
+
+	insert := `
+		INSERT INTO coupon_generations (instance, loaded_at)
+		VALUES ($1, $2)
+		RETURNING id
+	`
+	if err := tx.QueryRow(ctx, insert, generation.Instance, generation.LoadedAt).Scan(&generation.ID); err != nil {
+		r.logger.Error().Err(err).Str("instance", generation.Instance).Msg("failed to record coupon generation")
+		return fmt.Errorf("failed to record coupon generation: %w", Classify(err))
+	}
+
+	fileQuery := `
+		INSERT INTO coupon_generation_files (generation_id, position, name, path, checksum, codes)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	`
+	batch := &pgx.Batch{}
+	for i, f := range generation.Files {
+		batch.Queue(fileQuery, generation.ID, i, f.Name, f.Path, f.Checksum, f.Codes)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		r.logger.Error().Err(err).Int64("generation", generation.ID).Msg("failed to record coupon generation files")
+		return fmt.Errorf("failed to record coupon generation files: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// RetireGeneration records that a generation stopped serving validations.
+func (r *couponFileRepository) RetireGeneration(ctx context.Context, id int64, retiredAt time.Time) error {
+	query := `
+		UPDATE coupon_generations
+		SET retired_at = $2
+		WHERE id = $1 AND retired_at IS NULL
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, retiredAt); err != nil {
+		r.logger.Error().Err(err).Int64("generation", id).Msg("failed to retire coupon generation")
+		return fmt.Errorf("failed to retire coupon generation: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// ListGenerations retrieves the generations serving validations between from
+// and to. A generation loaded at exactly to is included and one retired at
+// exactly from is not.
+func (r *couponFileRepository) ListGenerations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error) {
+	query := `
+		SELECT id, instance, loaded_at, retired_at
+		FROM coupon_generations
+		WHERE loaded_at <= $2 AND (retired_at IS NULL OR retired_at > $1)
+		ORDER BY loaded_at DESC, id DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query coupon generations")
+		return nil, fmt.Errorf("failed to query coupon generations: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	generations := []model.CouponGeneration{}
+	index := make(map[int64]int)
+	ids := []int64{}
+	for rows.Next() {
+		g := model.CouponGeneration{Files: []model.CouponGenerationFile{}}
+		if err := rows.Scan(&g.ID, &g.Instance, &g.LoadedAt, &g.RetiredAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan coupon generation row")
+			return nil, fmt.Errorf("failed to scan coupon generation: %w", Classify(err))
+		}
+		index[g.ID] = len(generations)
+		ids = append(ids, g.ID)
+		generations = append(generations, g)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating coupon generation rows")
+		return nil, fmt.Errorf("error iterating coupon generations: %w", Classify(err))
+	}
+
+	if len(generations) == 0 {
+		return generations, nil
+	}
+
+	if err := r.loadGenerationFiles(ctx, generations, index, ids); err != nil {
+		return nil, err
+	}
+
+	return generations, nil
+}
+
+// loadGenerationFiles adds their files to the generations with the given
+// IDs, each with the registered upload matching its name and checksum.
+func (r *couponFileRepository) loadGenerationFiles(ctx context.Context, generations []model.CouponGeneration, index map[int64]int, ids []int64) error {
+	query := `
+		SELECT gf.generation_id, gf.name, gf.path, COALESCE(gf.checksum, ''), gf.codes,
+			cf.id, cf.source, cf.size_bytes, cf.uploaded_by, cf.uploaded_at, cf.registered_by, cf.registered_at
+		FROM coupon_generation_files gf
+		LEFT JOIN coupon_files cf ON cf.name = gf.name AND cf.checksum = gf.checksum
+		WHERE gf.generation_id = ANY($1)
+		ORDER BY gf.generation_id, gf.position
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query coupon generation files")
+		return fmt.Errorf("failed to query coupon generation files: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			generationID int64
+			f            model.CouponGenerationFile
+			uploadID     *uuid.UUID
+			upload       model.CouponFile
+			uploadedBy   *string
+			uploadedAt   *time.Time
+			registeredBy *string
+			registeredAt *time.Time
+			source       *string
+		)
+		err := rows.Scan(
+			&generationID,
+			&f.Name,
+			&f.Path,
+			&f.Checksum,
+			&f.Codes,
+			&uploadID,
+			&source,
+			&upload.SizeBytes,
+			&uploadedBy,
+			&uploadedAt,
+			&registeredBy,
+			&registeredAt,
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan coupon generation file row")
+			return fmt.Errorf("failed to scan coupon generation file: %w", Classify(err))
+		}
+
+		if uploadID != nil {
+			upload.ID = *uploadID
+			upload.Name = f.Name
+			upload.Checksum = f.Checksum
+			upload.Source = *source
+			upload.UploadedBy = *uploadedBy
+			upload.UploadedAt = *uploadedAt
+			upload.RegisteredBy = *registeredBy
+			upload.RegisteredAt = *registeredAt
+			f.Upload = &upload
+		}
+
+		g := &generations[index[generationID]]
+		g.Files = append(g.Files, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating coupon generation file rows")
+		return fmt.Errorf("error iterating coupon generation files: %w", Classify(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCouponFileSchema creates the coupon file registry tables for testing.
+func createCouponFileSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS coupon_files (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			source TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			size_bytes BIGINT,
+			uploaded_by TEXT NOT NULL,
+			uploaded_at TIMESTAMPTZ NOT NULL,
+			registered_by TEXT NOT NULL,
+			registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (name, checksum)
+		);
+
+		CREATE TABLE IF NOT EXISTS coupon_generations (
+			id BIGSERIAL PRIMARY KEY,
+			instance TEXT NOT NULL,
+			loaded_at TIMESTAMPTZ NOT NULL,
+			retired_at TIMESTAMPTZ
+		);
+
+		CREATE TABLE IF NOT EXISTS coupon_generation_files (
+			generation_id BIGINT NOT NULL REFERENCES coupon_generations(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			path TEXT NOT NULL,
+			checksum TEXT,
+			codes BIGINT NOT NULL,
+			PRIMARY KEY (generation_id, position)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestCouponFileRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createCouponFileSchema(t, pool)
+
+	logger := zerolog.Nop()
+	repo := NewCouponFileRepository(pool, logger)
+	ctx := context.Background()
+
+	checksumA := strings.Repeat("a", 64)
+	checksumB := strings.Repeat("b", 64)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	upload := &model.CouponFile{
+		Name:         "couponbase1",
+		Source:       "s3://coupons/couponbase1.gz",
+		Checksum:     checksumA,
+		UploadedBy:   "partner-feed",
+		UploadedAt:   day.Add(-time.Hour),
+		RegisteredBy: "admin:alice",
+	}
+
+	t.Run("Register", func(t *testing.T) {
+		require.NoError(t, repo.Register(ctx, upload))
+		assert.NotZero(t, upload.ID)
+		assert.False(t, upload.RegisteredAt.IsZero())
+
+		duplicate := *upload
+		assert.Equal(t, model.ErrCouponFileExists, repo.Register(ctx, &duplicate))
+
+		other := &model.CouponFile{
+			Name:         "couponbase2",
+			Source:       "s3://coupons/couponbase2.gz",
+			Checksum:     checksumA,
+			UploadedBy:   "partner-feed",
+			UploadedAt:   day,
+			RegisteredBy: "admin:alice",
+		}
+		require.NoError(t, repo.Register(ctx, other), "the same checksum may be registered under another set")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		files, err := repo.List(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, "couponbase2", files[0].Name, "newest upload should come first")
+
+		files, err = repo.List(ctx, "couponbase1")
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, upload.ID, files[0].ID)
+		assert.Equal(t, "partner-feed", files[0].UploadedBy)
+	})
+
+	t.Run("Generations", func(t *testing.T) {
+		first := &model.CouponGeneration{
+			Instance: "api-1",
+			LoadedAt: day,
+			Files: []model.CouponGenerationFile{
+				{Name: "couponbase1", Path: "data/coupons/couponbase1.gz", Checksum: checksumA, Codes: 100},
+				{Name: "couponbase3", Path: "data/coupons/couponbase3.gz", Codes: 50},
+			},
+		}
+		require.NoError(t, repo.RecordGeneration(ctx, first))
+		assert.NotZero(t, first.ID)
+
+		// Loading the next generation retires the instance's previous one
+		second := &model.CouponGeneration{
+			Instance: "api-1",
+			LoadedAt: day.Add(24 * time.Hour),
+			Files: []model.CouponGenerationFile{
+				{Name: "couponbase1", Path: "data/coupons/couponbase1.gz", Checksum: checksumB, Codes: 120},
+			},
+		}
+		require.NoError(t, repo.RecordGeneration(ctx, second))
+
+		other := &model.CouponGeneration{Instance: "api-2", LoadedAt: day.Add(time.Hour)}
+		require.NoError(t, repo.RecordGeneration(ctx, other))
+
+		generations, err := repo.ListGenerations(ctx, day.Add(2*time.Hour), day.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, generations, 2)
+		assert.Equal(t, other.ID, generations[0].ID)
+		assert.Empty(t, generations[0].Files)
+		assert.Equal(t, first.ID, generations[1].ID)
+		require.NotNil(t, generations[1].RetiredAt)
+		assert.True(t, generations[1].RetiredAt.Equal(second.LoadedAt))
+
+		files := generations[1].Files
+		require.Len(t, files, 2)
+		require.NotNil(t, files[0].Upload, "file should be traced to its registered upload")
+		assert.Equal(t, upload.ID, files[0].Upload.ID)
+		assert.Equal(t, "s3://coupons/couponbase1.gz", files[0].Upload.Source)
+		assert.Empty(t, files[1].Checksum)
+		assert.Nil(t, files[1].Upload)
+
+		// The unregistered file loaded by the second generation has no upload
+		generations, err = repo.ListGenerations(ctx, day.Add(25*time.Hour), day.Add(25*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, generations, 2)
+		assert.Equal(t, second.ID, generations[0].ID)
+		assert.Nil(t, generations[0].Files[0].Upload)
+
+		// Retired generations are no longer in use
+		require.NoError(t, repo.RetireGeneration(ctx, other.ID, day.Add(3*time.Hour)))
+		generations, err = repo.ListGenerations(ctx, day.Add(3*time.Hour), day.Add(3*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, generations, 1)
+		assert.Equal(t, first.ID, generations[0].ID)
+
+		generations, err = repo.ListGenerations(ctx, day.Add(-time.Minute), day.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, generations)
+
+		// A day includes every generation in use at some point during it
+		generations, err = repo.ListGenerations(ctx, day.Add(-time.Hour), day.Add(23*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, generations, 2)
+		assert.Equal(t, other.ID, generations[0].ID)
+		assert.Equal(t, first.ID, generations[1].ID)
+	})
+}
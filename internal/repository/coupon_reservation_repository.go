@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// couponReservationRepository implements CouponReservationRepository using PostgreSQL row locks.
+// Redemption limits are shared by every tenant, since a code's limit holds
+// wherever it is redeemed.
+type couponReservationRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewCouponReservationRepository creates a new PostgreSQL-backed coupon reservation repository.
+func NewCouponReservationRepository(pool *pgxpool.Pool, logger zerolog.Logger) CouponReservationRepository {
+	return &couponReservationRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "coupon_reservation").Logger(),
+	}
+}
+
+// Reserve claims one redemption of the coupon code within the provided
+// transaction and records it in coupon_redemptions. SELECT ... FOR UPDATE
+// serialises concurrent checkouts using the same code, so two orders can never
+// both observe the last remaining redemption, in total or for one caller.
+func (r *couponReservationRepository) Reserve(ctx context.Context, tx pgx.Tx, redemption model.CouponRedemption) error {
+	query := `
+		SELECT max_redemptions, max_per_caller, redeemed_count
+		FROM coupon_limits
+		WHERE code = $1
+		FOR UPDATE
+	`
+
+	var maxRedemptions, maxPerCaller *int
+	var redeemedCount int
+	err := tx.QueryRow(ctx, query, redemption.Code).Scan(&maxRedemptions, &maxPerCaller, &redeemedCount)
+	limited := err == nil
+	if err != nil && err != pgx.ErrNoRows {
+		r.logger.Error().Err(err).Str("coupon_code", redemption.Code).Msg("failed to lock coupon limit")
+		return fmt.Errorf("failed to lock coupon limit: %w", Classify(err))
+	}
+
+	if limited && maxRedemptions != nil && redeemedCount >= *maxRedemptions {
+		r.logger.Warn().
+			Str("coupon_code", redemption.Code).
+			Int("max_redemptions", *maxRedemptions).
+			Msg("coupon redemption limit reached")
+		return model.ErrCouponRedemptionLimit
+	}
+
+	if limited && maxPerCaller != nil {
+		countQuery := `
+			SELECT COUNT(*)
+			FROM coupon_redemptions
+			WHERE code = $1 AND redeemed_by = $2
+		`
+
+		var callerCount int
+		if err := tx.QueryRow(ctx, countQuery, redemption.Code, redemption.RedeemedBy).Scan(&callerCount); err != nil {
+			r.logger.Error().Err(err).Str("coupon_code", redemption.Code).Msg("failed to count caller redemptions")
+			return fmt.Errorf("failed to count caller redemptions: %w", Classify(err))
+		}
+		if callerCount >= *maxPerCaller {
+			r.logger.Warn().
+				Str("coupon_code", redemption.Code).
+				Str("redeemed_by", redemption.RedeemedBy).
+				Int("max_per_caller", *maxPerCaller).
+				Msg("coupon caller limit reached")
+			return model.ErrCouponCallerLimit
+		}
+	}
+
+	if limited {
+		updateQuery := `
+			UPDATE coupon_limits
+			SET redeemed_count = redeemed_count + 1, updated_at = NOW()
+			WHERE code = $1
+		`
+
+		if _, err := tx.Exec(ctx, updateQuery, redemption.Code); err != nil {
+			r.logger.Error().Err(err).Str("coupon_code", redemption.Code).Msg("failed to reserve coupon redemption")
+			return fmt.Errorf("failed to reserve coupon redemption: %w", Classify(err))
+		}
+	}
+
+	insertQuery := `
+		INSERT INTO coupon_redemptions (code, order_id, redeemed_by)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := tx.Exec(ctx, insertQuery, redemption.Code, redemption.OrderID, redemption.RedeemedBy); err != nil {
+		r.logger.Error().Err(err).Str("coupon_code", redemption.Code).Msg("failed to record coupon redemption")
+		return fmt.Errorf("failed to record coupon redemption: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("coupon_code", redemption.Code).
+		Str("order_id", redemption.OrderID.String()).
+		Msg("coupon redemption reserved")
+
+	return nil
+}
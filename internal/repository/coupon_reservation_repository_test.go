@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCouponLimitSchema creates the coupon_limits and coupon_redemptions
+// tables for testing. Redemptions skip the orders foreign key.
+func createCouponLimitSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS coupon_limits (
+			code TEXT PRIMARY KEY,
+			max_redemptions INTEGER CHECK (max_redemptions > 0),
+			max_per_caller INTEGER CHECK (max_per_caller > 0),
+			redeemed_count INTEGER NOT NULL DEFAULT 0 CHECK (redeemed_count >= 0),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT chk_coupon_limits_redeemed CHECK (max_redemptions IS NULL OR redeemed_count <= max_redemptions)
+		);
+
+		CREATE TABLE IF NOT EXISTS coupon_redemptions (
+			code TEXT NOT NULL,
+			order_id UUID NOT NULL,
+			redeemed_by TEXT NOT NULL,
+			redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (code, order_id)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestCouponReservationRepository_Reserve(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createCouponLimitSchema(t, pool)
+
+	logger := zerolog.Nop()
+	repo := NewCouponReservationRepository(pool, logger)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, "INSERT INTO coupon_limits (code, max_redemptions) VALUES ('ONCEONLY1', 1)")
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, "INSERT INTO coupon_limits (code, max_per_caller) VALUES ('WELCOME10', 1)")
+	require.NoError(t, err)
+
+	redemption := func(code, caller string) model.CouponRedemption {
+		return model.CouponRedemption{Code: code, OrderID: uuid.New(), RedeemedBy: caller}
+	}
+
+	t.Run("Unlimited code is always reservable", func(t *testing.T) {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		assert.NoError(t, repo.Reserve(ctx, tx, redemption("UNLIMITED1", "api-key")))
+
+		var recorded int
+		err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM coupon_redemptions WHERE code = 'UNLIMITED1'").Scan(&recorded)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recorded)
+	})
+
+	t.Run("Per-caller limit", func(t *testing.T) {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		require.NoError(t, repo.Reserve(ctx, tx, redemption("WELCOME10", "checkout-service")))
+		assert.Equal(t, model.ErrCouponCallerLimit, repo.Reserve(ctx, tx, redemption("WELCOME10", "checkout-service")))
+		assert.NoError(t, repo.Reserve(ctx, tx, redemption("WELCOME10", "partner-portal")))
+	})
+
+	t.Run("Rollback releases the reservation", func(t *testing.T) {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Reserve(ctx, tx, redemption("ONCEONLY1", "api-key")))
+		require.NoError(t, tx.Rollback(ctx))
+
+		var redeemed int
+		err = pool.QueryRow(ctx, "SELECT redeemed_count FROM coupon_limits WHERE code = 'ONCEONLY1'").Scan(&redeemed)
+		require.NoError(t, err)
+		assert.Equal(t, 0, redeemed)
+	})
+
+	t.Run("Concurrent reservations never exceed the limit", func(t *testing.T) {
+		const workers = 5
+		var wg sync.WaitGroup
+		results := make(chan error, workers)
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tx, err := pool.Begin(ctx)
+				if err != nil {
+					results <- err
+					return
+				}
+				if err := repo.Reserve(ctx, tx, redemption("ONCEONLY1", "api-key")); err != nil {
+					_ = tx.Rollback(ctx)
+					results <- err
+					return
+				}
+				results <- tx.Commit(ctx)
+			}()
+		}
+
+		wg.Wait()
+		close(results)
+
+		succeeded, exhausted := 0, 0
+		for err := range results {
+			switch err {
+			case nil:
+				succeeded++
+			case model.ErrCouponRedemptionLimit:
+				exhausted++
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		assert.Equal(t, 1, succeeded)
+		assert.Equal(t, workers-1, exhausted)
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// customerRepository implements CustomerRepository using PostgreSQL.
+type customerRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewCustomerRepository creates a new PostgreSQL-backed customer repository.
+func NewCustomerRepository(pool *pgxpool.Pool, logger zerolog.Logger) CustomerRepository {
+	return &customerRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "customer").Logger(),
+	}
+}
+
+// Create records a new customer with the context's tenant, setting its
+// creation time. Returns model.ErrCustomerExists if the email is taken.
+func (r *customerRepository) Create(ctx context.Context, customer *model.Customer) error {
+	query := `
+		INSERT INTO customers (id, tenant_id, email, name, segments)
+		VALUES ($1, $2, $3, $4, COALESCE($5, '{}'::text[]))
+		RETURNING created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, customer.ID, tenantOf(ctx), customer.Email, customer.Name, customer.Segments).Scan(&customer.CreatedAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("customer_id", customer.ID.String()).Msg("customer email already registered")
+			return model.ErrCustomerExists
+		}
+		r.logger.Error().Err(err).Str("customer_id", customer.ID.String()).Msg("failed to create customer")
+		return fmt.Errorf("failed to create customer: %w", Classify(err))
+	}
+
+	r.logger.Info().Str("customer_id", customer.ID.String()).Msg("customer created")
+
+	return nil
+}
+
+// GetByID retrieves a customer. Returns nil if the customer does not exist.
+func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	query := `
+		SELECT id, email, name, segments, created_at
+		FROM customers
+		WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2)
+	`
+
+	var c model.Customer
+	err := r.pool.QueryRow(ctx, query, id, tenantScope(ctx)).Scan(&c.ID, &c.Email, &c.Name, &c.Segments, &c.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("customer_id", id.String()).Msg("failed to query customer")
+		return nil, fmt.Errorf("failed to query customer: %w", Classify(err))
+	}
+
+	return &c, nil
+}
+
+// GetByEmail retrieves the context's tenant's customer with the email.
+// Returns nil if no customer has the address.
+func (r *customerRepository) GetByEmail(ctx context.Context, email string) (*model.Customer, error) {
+	query := `
+		SELECT id, email, name, segments, created_at
+		FROM customers
+		WHERE tenant_id = $1 AND email = $2
+	`
+
+	var c model.Customer
+	err := r.pool.QueryRow(ctx, query, tenantOf(ctx), email).Scan(&c.ID, &c.Email, &c.Name, &c.Segments, &c.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Msg("failed to query customer by email")
+		return nil, fmt.Errorf("failed to query customer: %w", Classify(err))
+	}
+
+	return &c, nil
+}
+
+// HasOrders reports whether the customer has placed an order that was not
+// cancelled.
+func (r *customerRepository) HasOrders(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM orders
+			WHERE customer_id = $1 AND status <> 'cancelled'
+				AND ($2::text IS NULL OR tenant_id = $2)
+		)
+	`
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, id, tenantScope(ctx)).Scan(&exists); err != nil {
+		r.logger.Error().Err(err).Str("customer_id", id.String()).Msg("failed to query customer orders")
+		return false, fmt.Errorf("failed to query customer orders: %w", Classify(err))
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewCustomerRepository(pool, logger)
+	ctx := context.Background()
+	acme := model.WithTenant(ctx, "acme")
+
+	ada := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}
+	require.NoError(t, repo.Create(acme, ada))
+	assert.False(t, ada.CreatedAt.IsZero())
+
+	assert.Equal(t, model.ErrCustomerExists, repo.Create(acme, &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada Again"}))
+
+	// Emails register once per tenant
+	require.NoError(t, repo.Create(model.WithTenant(ctx, "globex"), &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}))
+
+	customer, err := repo.GetByID(acme, ada.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", customer.Name)
+
+	customer, err = repo.GetByEmail(acme, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, ada.ID, customer.ID)
+
+	// Background work sees every tenant's customers
+	customer, err = repo.GetByID(ctx, ada.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, customer)
+
+	missing, err := repo.GetByID(model.WithTenant(ctx, "globex"), ada.ID)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	missing, err = repo.GetByEmail(acme, "grace@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
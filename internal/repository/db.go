@@ -36,7 +36,7 @@ func NewPool(ctx context.Context, connString string, config *DBConfig) (*pgxpool
 	// Parse connection string into config
 	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+		return nil, fmt.Errorf("failed to parse connection string: %w", Classify(err))
 	}
 
 	// Configure connection pool
@@ -48,13 +48,13 @@ func NewPool(ctx context.Context, connString string, config *DBConfig) (*pgxpool
 	// Create connection pool
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+		return nil, fmt.Errorf("failed to create connection pool: %w", Classify(err))
 	}
 
 	// Verify connectivity
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", Classify(err))
 	}
 
 	return pool, nil
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes the repositories classify.
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	adminShutdown        = "57P01"
+	crashShutdown        = "57P02"
+	cannotConnectNow     = "57P03"
+	tooManyConnections   = "53300"
+)
+
+// Classes of database errors. Repository errors wrap the driver error
+// together with its class, so callers can decide how to react with
+// errors.Is without inspecting driver errors. Errors of no known class, such
+// as syntax errors, match none of these. Constraint violations also match a
+// domain error naming what the client did wrong; see constraintError.
+var (
+	// ErrUniqueViolation marks a write that conflicts with an existing row.
+	ErrUniqueViolation = errors.New("unique constraint violation")
+
+	// ErrForeignKeyViolation marks a write referring to a row that does not
+	// exist, or a delete of a row that is still referred to.
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+
+	// ErrCheckViolation marks a write of a value a check constraint refuses,
+	// such as a negative price.
+	ErrCheckViolation = errors.New("check constraint violation")
+
+	// ErrRetryable marks a transaction PostgreSQL aborted because of a
+	// concurrent transaction, a serialization failure or deadlock. Running
+	// the whole transaction again may succeed.
+	ErrRetryable = errors.New("transaction aborted by a concurrent transaction")
+
+	// ErrConnection marks a failure to reach the database or a connection
+	// lost mid-query. Connection errors also match model.ErrDatabaseUnavailable.
+	ErrConnection = errors.New("database connection failed")
+)
+
+// classifiedError is a driver error together with its classes.
+type classifiedError struct {
+	err     error
+	classes []error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return append([]error{e.err}, e.classes...)
+}
+
+// Classify wraps a database error with its class. Errors of no known class,
+// nil and errors already classified are returned unchanged. Repositories
+// classify the errors they return; callers running their own transaction
+// through OrderRepository.BeginTx classify the errors the transaction returns.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	var classes []error
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == uniqueViolation:
+			classes = []error{ErrUniqueViolation, constraintError(pgErr)}
+		case pgErr.Code == foreignKeyViolation:
+			classes = []error{ErrForeignKeyViolation, constraintError(pgErr)}
+		case pgErr.Code == checkViolation:
+			classes = []error{ErrCheckViolation, constraintError(pgErr)}
+		case pgErr.Code == serializationFailure, pgErr.Code == deadlockDetected:
+			classes = []error{ErrRetryable}
+		case pgErr.Code == adminShutdown, pgErr.Code == crashShutdown, pgErr.Code == cannotConnectNow,
+			pgErr.Code == tooManyConnections, strings.HasPrefix(pgErr.Code, "08"):
+			classes = []error{ErrConnection, model.ErrDatabaseUnavailable}
+		}
+	case isConnectionError(err):
+		classes = []error{ErrConnection, model.ErrDatabaseUnavailable}
+	}
+
+	if classes == nil {
+		return err
+	}
+	return &classifiedError{err: err, classes: classes}
+}
+
+// constraintErrors maps the constraints a write can violate to the domain
+// errors describing the violation to the client. Foreign keys listed here are
+// those violated by writing a row referring to a missing row.
+var constraintErrors = map[string]*model.DomainError{
+	"products_pkey":                          model.ErrProductExists,
+	"products_price_check":                   model.ErrInvalidPrice,
+	"products_sale_price_check":              model.ErrInvalidSale,
+	"chk_products_sale_window":               model.ErrInvalidSale,
+	"products_tenant_id_fkey":                model.ErrTenantNotFound,
+	"orders_tenant_id_fkey":                  model.ErrTenantNotFound,
+	"orders_customer_fkey":                   model.ErrCustomerNotFound,
+	"order_items_product_id_fkey":            model.ErrProductNotFound,
+	"order_items_quantity_check":             model.ErrInvalidQuantity,
+	"order_items_check":                      model.ErrOverFulfillment,
+	"order_imports_tenant_id_fkey":           model.ErrTenantNotFound,
+	"order_imports_pkey":                     model.ErrOrderImported,
+	"shipment_items_quantity_check":          model.ErrInvalidShipment,
+	"shipment_items_order_item_id_fkey":      model.ErrOrderItemNotFound,
+	"price_change_approvals_product_id_fkey": model.ErrProductNotFound,
+	"product_images_product_id_fkey":         model.ErrProductNotFound,
+	"product_images_key_key":                 model.ErrImageAttached,
+	"flash_sales_product_id_fkey":            model.ErrProductNotFound,
+	"flash_sales_quantity_check":             model.ErrInvalidFlashSale,
+	"coupon_discounts_tenant_id_fkey":        model.ErrTenantNotFound,
+	"coupon_files_checksum_check":            model.ErrInvalidCouponFile,
+	"coupon_files_name_checksum_key":         model.ErrCouponFileExists,
+	"tenants_pkey":                           model.ErrTenantExists,
+	"customers_tenant_id_fkey":               model.ErrTenantNotFound,
+	"customers_tenant_id_email_key":          model.ErrCustomerExists,
+}
+
+// referencedErrors maps foreign keys to the domain errors describing a
+// delete of a row they still refer to.
+var referencedErrors = map[string]*model.DomainError{
+	"order_items_product_id_fkey": model.ErrProductInUse,
+}
+
+// constraintError returns the domain error describing a constraint
+// violation, falling back to a generic one for the violation's kind when the
+// constraint is not mapped.
+func constraintError(pgErr *pgconn.PgError) *model.DomainError {
+	switch pgErr.Code {
+	case foreignKeyViolation:
+		// PostgreSQL reports a delete of a referenced row against the
+		// referring table's constraint, so the message tells the two apart
+		if strings.HasPrefix(pgErr.Message, "update or delete on table") {
+			if err, ok := referencedErrors[pgErr.ConstraintName]; ok {
+				return err
+			}
+			return model.ErrRecordInUse
+		}
+		if err, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return err
+		}
+		return model.ErrReferenceNotFound
+	case uniqueViolation:
+		if err, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return err
+		}
+		return model.ErrDuplicateRecord
+	default:
+		if err, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return err
+		}
+		return model.ErrConstraintViolation
+	}
+}
+
+// isConnectionError reports whether err comes from connecting to the
+// database or from the network beneath an open connection. Queries cut short
+// by the caller's context are not connection errors.
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	classes := []error{ErrUniqueViolation, ErrForeignKeyViolation, ErrCheckViolation, ErrRetryable, ErrConnection}
+
+	tests := []struct {
+		name    string
+		err     error
+		classes []error
+	}{
+		{name: "Unique violation", err: &pgconn.PgError{Code: "23505"}, classes: []error{ErrUniqueViolation}},
+		{name: "Foreign key violation", err: &pgconn.PgError{Code: "23503"}, classes: []error{ErrForeignKeyViolation}},
+		{name: "Check violation", err: &pgconn.PgError{Code: "23514"}, classes: []error{ErrCheckViolation}},
+		{name: "Serialization failure", err: &pgconn.PgError{Code: "40001"}, classes: []error{ErrRetryable}},
+		{name: "Deadlock", err: &pgconn.PgError{Code: "40P01"}, classes: []error{ErrRetryable}},
+		{name: "Server shutting down", err: &pgconn.PgError{Code: "57P01"}, classes: []error{ErrConnection}},
+		{name: "Connection failure", err: &pgconn.PgError{Code: "08006"}, classes: []error{ErrConnection}},
+		{name: "Too many connections", err: &pgconn.PgError{Code: "53300"}, classes: []error{ErrConnection}},
+		{name: "Network error", err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, classes: []error{ErrConnection}},
+		{name: "Connection closed mid-message", err: io.ErrUnexpectedEOF, classes: []error{ErrConnection}},
+		{name: "Wrapped driver error", err: fmt.Errorf("failed to insert: %w", &pgconn.PgError{Code: "23505"}), classes: []error{ErrUniqueViolation}},
+		{name: "Syntax error", err: &pgconn.PgError{Code: "42601"}},
+		{name: "Cancelled query", err: fmt.Errorf("query: %w", context.DeadlineExceeded)},
+		{name: "Other error", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(tt.err)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.err.Error(), err.Error())
+			for _, class := range classes {
+				assert.Equal(t, slices.Contains(tt.classes, class), errors.Is(err, class), class.Error())
+			}
+			assert.Equal(t, slices.Contains(tt.classes, ErrConnection), errors.Is(err, model.ErrDatabaseUnavailable))
+
+			// Classifying again, or after wrapping, changes nothing
+			assert.Equal(t, err, Classify(err))
+			wrapped := fmt.Errorf("failed to commit: %w", err)
+			assert.Equal(t, wrapped, Classify(wrapped))
+		})
+	}
+
+	assert.NoError(t, Classify(nil))
+
+	var pgErr *pgconn.PgError
+	assert.True(t, errors.As(Classify(&pgconn.PgError{Code: "23505", Detail: "Key (id)=(P001) already exists."}), &pgErr))
+	assert.Equal(t, "Key (id)=(P001) already exists.", pgErr.Detail)
+}
+
+func TestClassify_ConstraintViolations(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *pgconn.PgError
+		domain *model.DomainError
+	}{
+		{
+			name:   "Order item for a missing product",
+			err:    &pgconn.PgError{Code: "23503", ConstraintName: "order_items_product_id_fkey", Message: `insert or update on table "order_items" violates foreign key constraint "order_items_product_id_fkey"`},
+			domain: model.ErrProductNotFound,
+		},
+		{
+			name:   "Delete of an ordered product",
+			err:    &pgconn.PgError{Code: "23503", ConstraintName: "order_items_product_id_fkey", Message: `update or delete on table "products" violates foreign key constraint "order_items_product_id_fkey" on table "order_items"`},
+			domain: model.ErrProductInUse,
+		},
+		{
+			name:   "Order for a missing customer",
+			err:    &pgconn.PgError{Code: "23503", ConstraintName: "orders_customer_fkey", Message: `insert or update on table "orders" violates foreign key constraint "orders_customer_fkey"`},
+			domain: model.ErrCustomerNotFound,
+		},
+		{
+			name:   "Unmapped foreign key",
+			err:    &pgconn.PgError{Code: "23503", ConstraintName: "order_notes_order_id_fkey", Message: `insert or update on table "order_notes" violates foreign key constraint "order_notes_order_id_fkey"`},
+			domain: model.ErrReferenceNotFound,
+		},
+		{
+			name:   "Delete of a row referenced by an unmapped foreign key",
+			err:    &pgconn.PgError{Code: "23503", ConstraintName: "products_tenant_id_fkey", Message: `update or delete on table "tenants" violates foreign key constraint "products_tenant_id_fkey" on table "products"`},
+			domain: model.ErrRecordInUse,
+		},
+		{
+			name:   "Negative price",
+			err:    &pgconn.PgError{Code: "23514", ConstraintName: "products_price_check"},
+			domain: model.ErrInvalidPrice,
+		},
+		{
+			name:   "Over-fulfilled item",
+			err:    &pgconn.PgError{Code: "23514", ConstraintName: "order_items_check"},
+			domain: model.ErrOverFulfillment,
+		},
+		{
+			name:   "Unmapped check",
+			err:    &pgconn.PgError{Code: "23514", ConstraintName: "chk_coupon_limits_redeemed"},
+			domain: model.ErrConstraintViolation,
+		},
+		{
+			name:   "Duplicate customer email",
+			err:    &pgconn.PgError{Code: "23505", ConstraintName: "customers_tenant_id_email_key"},
+			domain: model.ErrCustomerExists,
+		},
+		{
+			name:   "Unmapped unique constraint",
+			err:    &pgconn.PgError{Code: "23505", ConstraintName: "idempotency_keys_pkey"},
+			domain: model.ErrDuplicateRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(fmt.Errorf("failed to write: %w", tt.err))
+
+			assert.ErrorIs(t, err, tt.domain)
+			var domainErr *model.DomainError
+			require.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, tt.domain, domainErr)
+		})
+	}
+}
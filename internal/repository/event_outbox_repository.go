@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// eventRelayLockID is the transaction-level advisory lock held while
+// publishing outbox events, so only one relay publishes at a time and events
+// leave in sequence order.
+const eventRelayLockID = 7_348_201_556
+
+// eventOutboxRepository implements EventOutboxRepository using PostgreSQL.
+type eventOutboxRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewEventOutboxRepository creates a new PostgreSQL-backed event outbox repository.
+func NewEventOutboxRepository(pool *pgxpool.Pool, logger zerolog.Logger) EventOutboxRepository {
+	return &eventOutboxRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "event_outbox").Logger(),
+	}
+}
+
+// insertOutboxEvents adds events to the outbox within the provided
+// transaction, so they are only published if the change they describe commits.
+func insertOutboxEvents(ctx context.Context, tx pgx.Tx, events []model.OutboxEvent) error {
+	query := `
+		INSERT INTO event_outbox (id, event_type, aggregate_type, aggregate_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(query, e.ID, e.Type, e.AggregateType, e.AggregateID, e.Payload, e.CreatedAt)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to append outbox events: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// Append adds events to the outbox within the provided transaction.
+func (r *eventOutboxRepository) Append(ctx context.Context, tx pgx.Tx, events []model.OutboxEvent) error {
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		r.logger.Error().Err(err).Int("events", len(events)).Msg("failed to append outbox events")
+		return err
+	}
+	return nil
+}
+
+// PublishPending passes up to limit unpublished events, oldest first, to
+// publish and marks them published once it succeeds. The events stay locked
+// while publish runs; if another relay holds the lock, nothing is published
+// and 0 is returned.
+func (r *eventOutboxRepository) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return 0, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, eventRelayLockID).Scan(&locked); err != nil {
+		r.logger.Error().Err(err).Msg("failed to acquire event relay lock")
+		return 0, fmt.Errorf("failed to acquire event relay lock: %w", Classify(err))
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, created_at
+		FROM event_outbox
+		WHERE published_at IS NULL
+		ORDER BY sequence
+		LIMIT $1
+	`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to list outbox events")
+		return 0, fmt.Errorf("failed to list outbox events: %w", Classify(err))
+	}
+
+	events := []model.OutboxEvent{}
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var e model.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Payload, &e.CreatedAt); err != nil {
+			rows.Close()
+			r.logger.Error().Err(err).Msg("failed to scan outbox event row")
+			return 0, fmt.Errorf("failed to scan outbox event: %w", Classify(err))
+		}
+		events = append(events, e)
+		ids = append(ids, e.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating outbox event rows")
+		return 0, fmt.Errorf("error iterating outbox events: %w", Classify(err))
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	if err := publish(ctx, events); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE event_outbox SET published_at = NOW() WHERE id = ANY($1)`, ids); err != nil {
+		r.logger.Error().Err(err).Msg("failed to mark outbox events published")
+		return 0, fmt.Errorf("failed to mark outbox events published: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit transaction")
+		return 0, fmt.Errorf("failed to commit transaction: %w", Classify(err))
+	}
+
+	return len(events), nil
+}
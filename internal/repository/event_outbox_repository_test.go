@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createEventOutboxSchema creates the event_outbox table for testing.
+func createEventOutboxSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS event_outbox (
+			id UUID PRIMARY KEY,
+			sequence BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE,
+			event_type TEXT NOT NULL,
+			aggregate_type TEXT NOT NULL,
+			aggregate_id UUID NOT NULL,
+			payload JSONB NOT NULL,
+			published_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestEventOutboxRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createEventOutboxSchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewEventOutboxRepository(pool, logger)
+	ctx := context.Background()
+
+	newEvent := func(eventType string, aggregateID uuid.UUID) model.OutboxEvent {
+		return model.OutboxEvent{
+			ID:            uuid.New(),
+			Type:          eventType,
+			AggregateType: "order",
+			AggregateID:   aggregateID,
+			Payload:       json.RawMessage(`{"type":"` + eventType + `"}`),
+			CreatedAt:     time.Now().UTC(),
+		}
+	}
+
+	// publishAll publishes every pending event, returning them in order.
+	publishAll := func(t *testing.T) []model.OutboxEvent {
+		var published []model.OutboxEvent
+		_, err := repo.PublishPending(ctx, 100, func(ctx context.Context, events []model.OutboxEvent) error {
+			published = events
+			return nil
+		})
+		require.NoError(t, err)
+		return published
+	}
+
+	t.Run("Events are only appended when the transaction commits", func(t *testing.T) {
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Append(ctx, tx, []model.OutboxEvent{newEvent("order.created", uuid.New())}))
+		require.NoError(t, tx.Rollback(ctx))
+
+		assert.Empty(t, publishAll(t))
+	})
+
+	t.Run("Events are published once, in the order they were appended", func(t *testing.T) {
+		orderID := uuid.New()
+		created := newEvent("order.created", orderID)
+		changed := newEvent("order.status_changed", orderID)
+
+		for _, event := range []model.OutboxEvent{created, changed} {
+			tx, err := orderRepo.BeginTx(ctx)
+			require.NoError(t, err)
+			require.NoError(t, repo.Append(ctx, tx, []model.OutboxEvent{event}))
+			require.NoError(t, tx.Commit(ctx))
+		}
+
+		// A failed publish leaves the events pending
+		_, err := repo.PublishPending(ctx, 100, func(ctx context.Context, events []model.OutboxEvent) error {
+			return errors.New("broker unavailable")
+		})
+		assert.EqualError(t, err, "broker unavailable")
+
+		published := publishAll(t)
+		require.Len(t, published, 2)
+		assert.Equal(t, created.ID, published[0].ID)
+		assert.Equal(t, changed.ID, published[1].ID)
+		assert.JSONEq(t, string(created.Payload), string(published[0].Payload))
+
+		assert.Empty(t, publishAll(t))
+	})
+
+	t.Run("Only one relay publishes at a time", func(t *testing.T) {
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Append(ctx, tx, []model.OutboxEvent{newEvent("order.created", uuid.New())}))
+		require.NoError(t, tx.Commit(ctx))
+
+		published, err := repo.PublishPending(ctx, 100, func(ctx context.Context, events []model.OutboxEvent) error {
+			// A second relay finds the lock taken
+			concurrent, err := repo.PublishPending(ctx, 100, func(ctx context.Context, events []model.OutboxEvent) error {
+				t.Error("concurrent relay published events")
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 0, concurrent)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, published)
+	})
+
+	t.Run("Status changes append events with the update", func(t *testing.T) {
+		orderID := uuid.New()
+		now := time.Now()
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, &model.Order{ID: orderID, Status: model.OrderStatusPending, CreatedAt: now, UpdatedAt: now}))
+		require.NoError(t, tx.Commit(ctx))
+
+		changed := newEvent("order.status_changed", orderID)
+		_, err = orderRepo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, nil, nil, []model.OutboxEvent{changed})
+		require.NoError(t, err)
+
+		// A rejected transition appends nothing
+		_, err = orderRepo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, nil, nil,
+			[]model.OutboxEvent{newEvent("order.status_changed", orderID)})
+		assert.Equal(t, model.ErrStatusTransition, err)
+
+		published := publishAll(t)
+		require.Len(t, published, 1)
+		assert.Equal(t, changed.ID, published[0].ID)
+	})
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mini-kart/internal/cache"
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// counterFlashSaleRepository reserves the flash sales of hot products on
+// Redis counters shared by every API replica, instead of locking their rows,
+// so orders for one product are not serialised on its row during a sale
+// spike. Reservations are recorded as pending in the order's transaction and
+// counted as sold by Reconcile in batches.
+//
+// A counter is seeded from the database when a product is first reserved,
+// and reset when its flash sale is changed. Unlike cached products, counters
+// are the source of truth while a sale runs, so orders for counted products
+// fail with model.ErrOrderStepFailed while Redis is failing rather than fall
+// back to the database, which would let the two disagree on what is left.
+type counterFlashSaleRepository struct {
+	FlashSaleRepository
+	cache    *cache.Client
+	products map[string]bool
+	logger   zerolog.Logger
+}
+
+// NewCounterFlashSaleRepository wraps a flash sale repository so the given
+// products are reserved on Redis counters. Other products are reserved by
+// the wrapped repository.
+func NewCounterFlashSaleRepository(repo FlashSaleRepository, c *cache.Client, productIDs []string, logger zerolog.Logger) FlashSaleRepository {
+	products := make(map[string]bool, len(productIDs))
+	for _, id := range productIDs {
+		products[id] = true
+	}
+
+	return &counterFlashSaleRepository{
+		FlashSaleRepository: repo,
+		cache:               c,
+		products:            products,
+		logger:              logger.With().Str("repository", "flash_sale_counter").Logger(),
+	}
+}
+
+// Set allocates quantity of a product to its flash sale and resets its
+// counter, which is seeded with the new remaining quantity on the next
+// reservation. Orders in flight while it is reset are not reflected in the
+// new counter, so allocations are best set before the sale starts.
+func (r *counterFlashSaleRepository) Set(ctx context.Context, productID string, quantity int) (*model.FlashSale, error) {
+	flashSale, err := r.FlashSaleRepository.Set(ctx, productID, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.reset(ctx, productID); err != nil {
+		return nil, err
+	}
+	return flashSale, nil
+}
+
+// Delete ends a product's flash sale and drops its counter.
+func (r *counterFlashSaleRepository) Delete(ctx context.Context, productID string) (bool, error) {
+	deleted, err := r.FlashSaleRepository.Delete(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.reset(ctx, productID); err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
+
+// reset drops a counted product's counter.
+func (r *counterFlashSaleRepository) reset(ctx context.Context, productID string) error {
+	if !r.products[productID] {
+		return nil
+	}
+	if _, err := r.cache.Delete(ctx, flashSaleCounterKey(productID)); err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to reset flash sale counter")
+		return fmt.Errorf("flash sale changed, but its counter could not be reset; retry: %w", err)
+	}
+	return nil
+}
+
+// Reserve takes the reserved quantities of counted products from their
+// counters and records them as pending, and reserves other products with the
+// wrapped repository. The reservations returned are those claimed by either,
+// leaving out products not on a flash sale.
+func (r *counterFlashSaleRepository) Reserve(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	var counted, locked []model.FlashSaleReservation
+	for _, reservation := range reservations {
+		if r.products[reservation.ProductID] {
+			counted = append(counted, reservation)
+		} else {
+			locked = append(locked, reservation)
+		}
+	}
+
+	claimed, err := r.FlashSaleRepository.Reserve(ctx, tx, locked)
+	if err != nil {
+		return nil, err
+	}
+	if len(counted) == 0 {
+		return claimed, nil
+	}
+
+	counted, err = r.take(ctx, counted)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.FlashSaleRepository.RecordPending(ctx, tx, counted); err != nil {
+		r.putBack(ctx, counted)
+		return nil, err
+	}
+	return append(claimed, counted...), nil
+}
+
+// Release puts the reserved quantities of counted products back on their
+// counters. Only reservations Reserve returned may be released: the others
+// were never taken, and putting them back would add to a counter seeded
+// since.
+func (r *counterFlashSaleRepository) Release(ctx context.Context, reservations []model.FlashSaleReservation) error {
+	var counted []model.FlashSaleReservation
+	for _, reservation := range reservations {
+		if r.products[reservation.ProductID] {
+			counted = append(counted, reservation)
+		}
+	}
+	if err := r.putBack(ctx, counted); err != nil {
+		return err
+	}
+	return r.FlashSaleRepository.Release(ctx, reservations)
+}
+
+// take takes reservations' quantities from their counters, seeding counters
+// that do not exist yet, and returns the reservations of products on a flash
+// sale.
+func (r *counterFlashSaleRepository) take(ctx context.Context, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	taken, err := r.cache.Take(ctx, counterAmounts(reservations))
+	if errors.Is(err, cache.ErrMiss) {
+		if reservations, err = r.seed(ctx, reservations); err != nil {
+			return nil, err
+		}
+		taken, err = r.cache.Take(ctx, counterAmounts(reservations))
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to take flash sale quantities")
+		return nil, model.ErrOrderStepFailed
+	}
+
+	if !taken {
+		r.logger.Warn().Int("products", len(reservations)).Msg("flash sale sold out")
+		return nil, model.ErrInsufficientStock
+	}
+	return reservations, nil
+}
+
+// seed creates the missing counters of reservations' products with their
+// remaining quantity, and returns the reservations of products on a flash
+// sale. Counters created by a concurrent order are kept.
+func (r *counterFlashSaleRepository) seed(ctx context.Context, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	productIDs := make([]string, len(reservations))
+	for i, reservation := range reservations {
+		productIDs[i] = reservation.ProductID
+	}
+
+	flashSales, err := r.FlashSaleRepository.GetByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	onSale := make(map[string]bool, len(flashSales))
+	for _, flashSale := range flashSales {
+		created, err := r.cache.Seed(ctx, flashSaleCounterKey(flashSale.ProductID), int64(flashSale.Remaining()))
+		if err != nil {
+			r.logger.Error().Err(err).Str("product_id", flashSale.ProductID).Msg("failed to seed flash sale counter")
+			return nil, model.ErrOrderStepFailed
+		}
+		if created {
+			r.logger.Info().
+				Str("product_id", flashSale.ProductID).
+				Int("remaining", flashSale.Remaining()).
+				Msg("flash sale counter seeded")
+		}
+		onSale[flashSale.ProductID] = true
+	}
+
+	var filtered []model.FlashSaleReservation
+	for _, reservation := range reservations {
+		if onSale[reservation.ProductID] {
+			filtered = append(filtered, reservation)
+		}
+	}
+	return filtered, nil
+}
+
+// putBack puts reservations' quantities back on their counters. A failure is
+// logged and leaves the quantities unavailable until the counter is reset,
+// which undersells rather than oversells.
+func (r *counterFlashSaleRepository) putBack(ctx context.Context, reservations []model.FlashSaleReservation) error {
+	if err := r.cache.PutBack(ctx, counterAmounts(reservations)); err != nil {
+		r.logger.Error().Err(err).Msg("failed to put back flash sale quantities")
+		return err
+	}
+	return nil
+}
+
+// counterAmounts returns the quantity reserved per counter.
+func counterAmounts(reservations []model.FlashSaleReservation) map[string]int64 {
+	amounts := make(map[string]int64, len(reservations))
+	for _, reservation := range reservations {
+		amounts[flashSaleCounterKey(reservation.ProductID)] += int64(reservation.Quantity)
+	}
+	return amounts
+}
+
+// flashSaleCounterKey returns the key of a product's flash sale counter.
+// Product IDs are unique across tenants, so counters are not scoped.
+func flashSaleCounterKey(productID string) string {
+	return "flashsale:" + productID
+}
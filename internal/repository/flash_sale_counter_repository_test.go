@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"mini-kart/internal/cache"
+	"mini-kart/internal/config"
+	"mini-kart/internal/model"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFlashSaleRepository serves flash sales from a map and records the
+// reservations it is asked to lock or record as pending. Methods the tests
+// do not use are left to the nil embedded interface.
+type stubFlashSaleRepository struct {
+	FlashSaleRepository
+	flashSales map[string]model.FlashSale
+	reads      int
+	locked     []model.FlashSaleReservation
+	pending    []model.FlashSaleReservation
+}
+
+func (r *stubFlashSaleRepository) GetByProductIDs(ctx context.Context, productIDs []string) ([]model.FlashSale, error) {
+	r.reads++
+	var flashSales []model.FlashSale
+	for _, id := range productIDs {
+		if flashSale, ok := r.flashSales[id]; ok {
+			flashSales = append(flashSales, flashSale)
+		}
+	}
+	return flashSales, nil
+}
+
+func (r *stubFlashSaleRepository) Set(ctx context.Context, productID string, quantity int) (*model.FlashSale, error) {
+	flashSale := r.flashSales[productID]
+	flashSale.ProductID = productID
+	flashSale.Quantity = quantity
+	r.flashSales[productID] = flashSale
+	return &flashSale, nil
+}
+
+func (r *stubFlashSaleRepository) Reserve(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	var claimed []model.FlashSaleReservation
+	for _, reservation := range reservations {
+		if _, ok := r.flashSales[reservation.ProductID]; ok {
+			claimed = append(claimed, reservation)
+		}
+	}
+	r.locked = append(r.locked, claimed...)
+	return claimed, nil
+}
+
+func (r *stubFlashSaleRepository) Release(ctx context.Context, reservations []model.FlashSaleReservation) error {
+	return nil
+}
+
+func (r *stubFlashSaleRepository) RecordPending(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) error {
+	r.pending = append(r.pending, reservations...)
+	return nil
+}
+
+// newCounterFlashSaleTest wraps a stub repository with P001 and P002 on
+// flash sale, counting P001 and P003 on an in-memory Redis server.
+func newCounterFlashSaleTest(t *testing.T) (FlashSaleRepository, *stubFlashSaleRepository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client, err := cache.New(context.Background(), config.RedisConfig{Addr: server.Addr()}, zerolog.Nop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	base := &stubFlashSaleRepository{flashSales: map[string]model.FlashSale{
+		"P001": {ProductID: "P001", Quantity: 10, Sold: 3, Pending: 2},
+		"P002": {ProductID: "P002", Quantity: 10},
+	}}
+	return NewCounterFlashSaleRepository(base, client, []string{"P001", "P003"}, zerolog.Nop()), base, server
+}
+
+func TestCounterFlashSaleRepository_Reserve(t *testing.T) {
+	repo, base, server := newCounterFlashSaleTest(t)
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	reservations := []model.FlashSaleReservation{
+		{OrderID: orderID, ProductID: "P001", Quantity: 2},
+		{OrderID: orderID, ProductID: "P002", Quantity: 1},
+		{OrderID: orderID, ProductID: "P003", Quantity: 1},
+	}
+	claimed, err := repo.Reserve(ctx, nil, reservations)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, reservations[:2], claimed)
+
+	// The counter is seeded with what is left after sold and pending
+	// quantities, and P003, not on a flash sale, is left out
+	left, err := server.Get("mini-kart:flashsale:P001")
+	require.NoError(t, err)
+	assert.Equal(t, "3", left)
+	assert.False(t, server.Exists("mini-kart:flashsale:P003"))
+	assert.Equal(t, reservations[:1], base.pending)
+	assert.Equal(t, reservations[1:2], base.locked)
+
+	// Seeded counters are not read from the database again
+	_, err = repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, base.reads)
+
+	_, err = repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 1}})
+	assert.Equal(t, model.ErrInsufficientStock, err)
+	assert.Len(t, base.pending, 2)
+
+	// P003 goes on a flash sale, and its counter is seeded, after the order
+	// left it out
+	require.NoError(t, server.Set("mini-kart:flashsale:P003", "5"))
+
+	// Quantities of rolled back orders are put back, leaving out those
+	// never taken
+	require.NoError(t, repo.Release(ctx, claimed))
+	left, err = server.Get("mini-kart:flashsale:P001")
+	require.NoError(t, err)
+	assert.Equal(t, "2", left)
+	left, err = server.Get("mini-kart:flashsale:P003")
+	require.NoError(t, err)
+	assert.Equal(t, "5", left)
+
+	// Orders fail rather than oversell while Redis is down
+	server.Close()
+	_, err = repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 1}})
+	assert.Equal(t, model.ErrOrderStepFailed, err)
+}
+
+func TestCounterFlashSaleRepository_Set(t *testing.T) {
+	repo, _, server := newCounterFlashSaleTest(t)
+	ctx := context.Background()
+
+	_, err := repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 5}})
+	require.NoError(t, err)
+	_, err = repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 1}})
+	assert.Equal(t, model.ErrInsufficientStock, err)
+
+	// Raising the allocation resets the counter, which is seeded again
+	_, err = repo.Set(ctx, "P001", 20)
+	require.NoError(t, err)
+	assert.False(t, server.Exists("mini-kart:flashsale:P001"))
+	_, err = repo.Reserve(ctx, nil, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 1}})
+	require.NoError(t, err)
+	left, err := server.Get("mini-kart:flashsale:P001")
+	require.NoError(t, err)
+	assert.Equal(t, "14", left)
+}
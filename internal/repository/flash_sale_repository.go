@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// flashSaleQuery selects flash sales with the quantity of their reservations
+// not yet reconciled.
+const flashSaleQuery = `
+	SELECT f.product_id, f.quantity, f.sold, COALESCE(p.pending, 0), f.created_at, f.updated_at
+	FROM flash_sales f
+	LEFT JOIN (
+		SELECT product_id, SUM(quantity) AS pending
+		FROM flash_sale_reservations
+		WHERE reconciled_at IS NULL
+		GROUP BY product_id
+	) p ON p.product_id = f.product_id
+`
+
+// flashSaleTenantCondition restricts flash sales to those of the tenant's
+// products. Flash sales belong to the tenant of their product.
+const flashSaleTenantCondition = `($%[1]d::text IS NULL OR f.product_id IN (SELECT id FROM products WHERE tenant_id = $%[1]d))`
+
+// flashSaleRepository implements FlashSaleRepository using PostgreSQL row locks.
+type flashSaleRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewFlashSaleRepository creates a new PostgreSQL-backed flash sale
+// repository. Reservations lock the flash sale's row until the order's
+// transaction ends, serialising concurrent orders for the same product.
+func NewFlashSaleRepository(pool *pgxpool.Pool, logger zerolog.Logger) FlashSaleRepository {
+	return &flashSaleRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "flash_sale").Logger(),
+	}
+}
+
+// List retrieves every flash sale, ordered by product ID.
+func (r *flashSaleRepository) List(ctx context.Context) ([]model.FlashSale, error) {
+	return r.query(ctx, flashSaleQuery+" WHERE "+fmt.Sprintf(flashSaleTenantCondition, 1)+" ORDER BY f.product_id", tenantScope(ctx))
+}
+
+// GetByProductIDs retrieves the flash sales of the given products.
+func (r *flashSaleRepository) GetByProductIDs(ctx context.Context, productIDs []string) ([]model.FlashSale, error) {
+	if len(productIDs) == 0 {
+		return []model.FlashSale{}, nil
+	}
+	return r.query(ctx, flashSaleQuery+" WHERE f.product_id = ANY($1) AND "+fmt.Sprintf(flashSaleTenantCondition, 2)+" ORDER BY f.product_id",
+		productIDs, tenantScope(ctx))
+}
+
+// query runs a flash sale query and scans its rows.
+func (r *flashSaleRepository) query(ctx context.Context, query string, args ...any) ([]model.FlashSale, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query flash sales")
+		return nil, fmt.Errorf("failed to query flash sales: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	flashSales := []model.FlashSale{}
+	for rows.Next() {
+		var f model.FlashSale
+		if err := rows.Scan(&f.ProductID, &f.Quantity, &f.Sold, &f.Pending, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan flash sale row")
+			return nil, fmt.Errorf("failed to scan flash sale: %w", Classify(err))
+		}
+		flashSales = append(flashSales, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating flash sale rows")
+		return nil, fmt.Errorf("error iterating flash sales: %w", Classify(err))
+	}
+
+	return flashSales, nil
+}
+
+// Set allocates quantity of a product to its flash sale, keeping the
+// quantity already sold. Products of other tenants are not found.
+func (r *flashSaleRepository) Set(ctx context.Context, productID string, quantity int) (*model.FlashSale, error) {
+	query := `
+		INSERT INTO flash_sales (product_id, quantity)
+		SELECT id, $2 FROM products
+		WHERE id = $1 AND ($3::text IS NULL OR tenant_id = $3)
+		ON CONFLICT (product_id) DO UPDATE
+		SET quantity = EXCLUDED.quantity, updated_at = NOW()
+	`
+
+	tag, err := r.pool.Exec(ctx, query, productID, quantity, tenantScope(ctx))
+	if err != nil {
+		if errors.Is(Classify(err), ErrForeignKeyViolation) {
+			return nil, model.ErrProductNotFound
+		}
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to set flash sale")
+		return nil, fmt.Errorf("failed to set flash sale: %w", Classify(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, model.ErrProductNotFound
+	}
+
+	flashSales, err := r.GetByProductIDs(ctx, []string{productID})
+	if err != nil {
+		return nil, err
+	}
+	if len(flashSales) == 0 {
+		// Ended by a concurrent request
+		return nil, model.ErrFlashSaleNotFound
+	}
+
+	r.logger.Info().Str("product_id", productID).Int("quantity", quantity).Msg("flash sale set")
+
+	return &flashSales[0], nil
+}
+
+// Delete ends a product's flash sale.
+func (r *flashSaleRepository) Delete(ctx context.Context, productID string) (bool, error) {
+	query := `DELETE FROM flash_sales f WHERE f.product_id = $1 AND ` + fmt.Sprintf(flashSaleTenantCondition, 2)
+
+	tag, err := r.pool.Exec(ctx, query, productID, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to delete flash sale")
+		return false, fmt.Errorf("failed to delete flash sale: %w", Classify(err))
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Reserve claims the reserved quantities of flash-sale products within the
+// provided transaction and returns the reservations it claimed. SELECT ... FOR UPDATE serialises concurrent orders
+// for the same product, so two orders can never both take its last units.
+// Rows are locked in product ID order to avoid deadlocks between orders
+// for several flash-sale products.
+func (r *flashSaleRepository) Reserve(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	if len(reservations) == 0 {
+		return nil, nil
+	}
+
+	productIDs := make([]string, len(reservations))
+	for i, reservation := range reservations {
+		productIDs[i] = reservation.ProductID
+	}
+
+	query := `
+		SELECT f.product_id, f.quantity - f.sold - COALESCE((
+			SELECT SUM(r.quantity)
+			FROM flash_sale_reservations r
+			WHERE r.product_id = f.product_id AND r.reconciled_at IS NULL
+		), 0)
+		FROM flash_sales f
+		WHERE f.product_id = ANY($1) AND ` + fmt.Sprintf(flashSaleTenantCondition, 2) + `
+		ORDER BY f.product_id
+		FOR UPDATE OF f
+	`
+
+	rows, err := tx.Query(ctx, query, productIDs, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to lock flash sales")
+		return nil, fmt.Errorf("failed to lock flash sales: %w", Classify(err))
+	}
+	remaining := make(map[string]int, len(reservations))
+	for rows.Next() {
+		var productID string
+		var left int
+		if err := rows.Scan(&productID, &left); err != nil {
+			rows.Close()
+			r.logger.Error().Err(err).Msg("failed to scan flash sale row")
+			return nil, fmt.Errorf("failed to scan flash sale: %w", Classify(err))
+		}
+		remaining[productID] = left
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("failed to lock flash sales")
+		return nil, fmt.Errorf("failed to lock flash sales: %w", Classify(err))
+	}
+
+	var claimed []model.FlashSaleReservation
+	for _, reservation := range reservations {
+		left, ok := remaining[reservation.ProductID]
+		if !ok {
+			continue
+		}
+		if reservation.Quantity > left {
+			r.logger.Warn().
+				Str("product_id", reservation.ProductID).
+				Int("quantity", reservation.Quantity).
+				Int("remaining", max(left, 0)).
+				Msg("flash sale sold out")
+			return nil, model.ErrInsufficientStock
+		}
+		claimed = append(claimed, reservation)
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	updateQuery := `
+		UPDATE flash_sales f
+		SET sold = f.sold + r.quantity, updated_at = NOW()
+		FROM unnest($1::text[], $2::int[]) AS r(product_id, quantity)
+		WHERE f.product_id = r.product_id
+	`
+
+	ids, quantities := reservationColumns(claimed)
+	if _, err := tx.Exec(ctx, updateQuery, ids, quantities); err != nil {
+		r.logger.Error().Err(err).Msg("failed to reserve flash sale quantities")
+		return nil, fmt.Errorf("failed to reserve flash sale quantities: %w", Classify(err))
+	}
+
+	if err := r.record(ctx, tx, claimed, true); err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Release gives back nothing: reservations are only claimed within the
+// transaction, which gives them back when rolled back.
+func (r *flashSaleRepository) Release(ctx context.Context, reservations []model.FlashSaleReservation) error {
+	return nil
+}
+
+// RecordPending records reservations claimed outside the database, for
+// Reconcile to count as sold.
+func (r *flashSaleRepository) RecordPending(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	return r.record(ctx, tx, reservations, false)
+}
+
+// record inserts reservations of one order, reconciled when their quantities
+// were already counted as sold.
+func (r *flashSaleRepository) record(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation, reconciled bool) error {
+	query := `
+		INSERT INTO flash_sale_reservations (order_id, product_id, quantity, reconciled_at)
+		SELECT $1, r.product_id, r.quantity, CASE WHEN $4 THEN NOW() END
+		FROM unnest($2::text[], $3::int[]) AS r(product_id, quantity)
+	`
+
+	orderID := reservations[0].OrderID
+	ids, quantities := reservationColumns(reservations)
+	if _, err := tx.Exec(ctx, query, orderID, ids, quantities, reconciled); err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to record flash sale reservations")
+		return fmt.Errorf("failed to record flash sale reservations: %w", Classify(err))
+	}
+	return nil
+}
+
+// Reconcile counts up to limit pending reservations as sold in one
+// statement, for every tenant. FOR UPDATE SKIP LOCKED lets concurrent reconcilers on other
+// replicas take different reservations, and each flash sale's row is updated
+// once per batch rather than once per order.
+func (r *flashSaleRepository) Reconcile(ctx context.Context, limit int) (int, error) {
+	query := `
+		WITH batch AS (
+			SELECT order_id, product_id
+			FROM flash_sale_reservations
+			WHERE reconciled_at IS NULL
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), reconciled AS (
+			UPDATE flash_sale_reservations r
+			SET reconciled_at = NOW()
+			FROM batch b
+			WHERE r.order_id = b.order_id AND r.product_id = b.product_id
+			RETURNING r.product_id, r.quantity
+		), sold AS (
+			UPDATE flash_sales f
+			SET sold = f.sold + t.quantity, updated_at = NOW()
+			FROM (
+				SELECT product_id, SUM(quantity) AS quantity
+				FROM reconciled
+				GROUP BY product_id
+			) t
+			WHERE f.product_id = t.product_id
+			RETURNING f.product_id
+		)
+		SELECT (SELECT COUNT(*) FROM reconciled), (SELECT COUNT(*) FROM sold)
+	`
+
+	var reconciled, products int
+	if err := r.pool.QueryRow(ctx, query, limit).Scan(&reconciled, &products); err != nil {
+		r.logger.Error().Err(err).Msg("failed to reconcile flash sale reservations")
+		return 0, fmt.Errorf("failed to reconcile flash sale reservations: %w", Classify(err))
+	}
+
+	if reconciled > 0 {
+		r.logger.Debug().Int("reservations", reconciled).Int("products", products).Msg("flash sale reservations reconciled")
+	}
+
+	return reconciled, nil
+}
+
+// reservationColumns splits reservations into product ID and quantity
+// arrays, for passing to unnest.
+func reservationColumns(reservations []model.FlashSaleReservation) ([]string, []int32) {
+	ids := make([]string, len(reservations))
+	quantities := make([]int32, len(reservations))
+	for i, reservation := range reservations {
+		ids[i] = reservation.ProductID
+		quantities[i] = int32(reservation.Quantity)
+	}
+	return ids, quantities
+}
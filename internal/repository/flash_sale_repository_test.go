@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFlashSaleSchema creates the flash_sales and flash_sale_reservations
+// tables for testing. Reservations skip the orders foreign key.
+func createFlashSaleSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS flash_sales (
+			product_id TEXT PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+			quantity INTEGER NOT NULL CHECK (quantity >= 0),
+			sold INTEGER NOT NULL DEFAULT 0 CHECK (sold >= 0),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS flash_sale_reservations (
+			order_id UUID NOT NULL,
+			product_id TEXT NOT NULL,
+			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			reconciled_at TIMESTAMPTZ,
+			PRIMARY KEY (order_id, product_id)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestFlashSaleRepository_Reserve(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createFlashSaleSchema(t, pool)
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Flash Chips", Price: 250, Category: "Snacks"},
+		{ID: "P002", Name: "Regular Chips", Price: 300, Category: "Snacks"},
+	})
+
+	repo := NewFlashSaleRepository(pool, zerolog.Nop())
+	ctx := context.Background()
+
+	_, err := repo.Set(ctx, "P999", 10)
+	assert.Equal(t, model.ErrProductNotFound, err)
+
+	flashSale, err := repo.Set(ctx, "P001", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, flashSale.Remaining())
+
+	reserve := func(quantity int) error {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		orderID := uuid.New()
+		_, err = repo.Reserve(ctx, tx, []model.FlashSaleReservation{
+			{OrderID: orderID, ProductID: "P001", Quantity: quantity},
+			{OrderID: orderID, ProductID: "P002", Quantity: 100},
+		})
+		if err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+
+	// Concurrent orders never take more than the allocation
+	var wg sync.WaitGroup
+	results := make(chan error, 8)
+	for range 8 {
+		wg.Go(func() { results <- reserve(2) })
+	}
+	wg.Wait()
+	close(results)
+
+	var reserved, soldOut int
+	for err := range results {
+		if err == nil {
+			reserved++
+		} else {
+			assert.Equal(t, model.ErrInsufficientStock, err)
+			soldOut++
+		}
+	}
+	assert.Equal(t, 5, reserved)
+	assert.Equal(t, 3, soldOut)
+
+	flashSales, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, flashSales, 1)
+	assert.Equal(t, 10, flashSales[0].Sold)
+	assert.Equal(t, 0, flashSales[0].Remaining())
+
+	var recorded int
+	err = pool.QueryRow(ctx, "SELECT COUNT(*) FROM flash_sale_reservations WHERE product_id = 'P001' AND reconciled_at IS NOT NULL").Scan(&recorded)
+	require.NoError(t, err)
+	assert.Equal(t, 5, recorded, "products not on a flash sale must not be recorded")
+
+	deleted, err := repo.Delete(ctx, "P001")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, reserve(2))
+}
+
+func TestFlashSaleRepository_Reconcile(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createFlashSaleSchema(t, pool)
+	seedProducts(t, pool, []model.Product{{ID: "P001", Name: "Flash Chips", Price: 250, Category: "Snacks"}})
+
+	repo := NewFlashSaleRepository(pool, zerolog.Nop())
+	ctx := context.Background()
+
+	_, err := repo.Set(ctx, "P001", 10)
+	require.NoError(t, err)
+
+	for range 3 {
+		tx, err := pool.Begin(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.RecordPending(ctx, tx, []model.FlashSaleReservation{{OrderID: uuid.New(), ProductID: "P001", Quantity: 2}}))
+		require.NoError(t, tx.Commit(ctx))
+	}
+
+	flashSales, err := repo.GetByProductIDs(ctx, []string{"P001"})
+	require.NoError(t, err)
+	require.Len(t, flashSales, 1)
+	assert.Equal(t, 0, flashSales[0].Sold)
+	assert.Equal(t, 6, flashSales[0].Pending)
+	assert.Equal(t, 4, flashSales[0].Remaining())
+
+	reconciled, err := repo.Reconcile(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reconciled)
+	reconciled, err = repo.Reconcile(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reconciled)
+	reconciled, err = repo.Reconcile(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, reconciled)
+
+	flashSales, err = repo.GetByProductIDs(ctx, []string{"P001"})
+	require.NoError(t, err)
+	assert.Equal(t, 6, flashSales[0].Sold)
+	assert.Equal(t, 0, flashSales[0].Pending)
+	assert.Equal(t, 4, flashSales[0].Remaining())
+}
+
+func TestFlashSaleRepository_TenantScope(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createFlashSaleSchema(t, pool)
+
+	products := NewProductRepository(pool, zerolog.Nop())
+	repo := NewFlashSaleRepository(pool, zerolog.Nop())
+	acme := model.WithTenant(context.Background(), "acme")
+	globex := model.WithTenant(context.Background(), "globex")
+
+	require.NoError(t, products.Create(acme, &model.Product{ID: "A001", Name: "Acme Anvil", Price: model.NewMoney(99.00), Category: "Tools"}))
+	require.NoError(t, products.Create(globex, &model.Product{ID: "G001", Name: "Globex Gadget", Price: model.NewMoney(15.00), Category: "Tools"}))
+
+	_, err := repo.Set(globex, "A001", 10)
+	assert.Equal(t, model.ErrProductNotFound, err)
+
+	_, err = repo.Set(acme, "A001", 10)
+	require.NoError(t, err)
+	_, err = repo.Set(globex, "G001", 5)
+	require.NoError(t, err)
+
+	t.Run("List returns only the tenant's flash sales", func(t *testing.T) {
+		sales, err := repo.List(acme)
+		require.NoError(t, err)
+		require.Len(t, sales, 1)
+		assert.Equal(t, "A001", sales[0].ProductID)
+
+		all, err := repo.List(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("Other tenants cannot reserve or delete", func(t *testing.T) {
+		tx, err := pool.Begin(globex)
+		require.NoError(t, err)
+		claimed, err := repo.Reserve(globex, tx, []model.FlashSaleReservation{
+			{OrderID: uuid.New(), ProductID: "A001", Quantity: 1},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+		require.NoError(t, tx.Commit(globex))
+
+		deleted, err := repo.Delete(globex, "A001")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+
+		sales, err := repo.GetByProductIDs(acme, []string{"A001"})
+		require.NoError(t, err)
+		require.Len(t, sales, 1)
+		assert.Equal(t, 0, sales[0].Sold)
+		assert.Equal(t, 0, sales[0].Pending)
+	})
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// idempotencyRepository implements IdempotencyRepository using PostgreSQL.
+type idempotencyRepository struct {
+	pool   *pgxpool.Pool
+	ttl    time.Duration
+	logger zerolog.Logger
+}
+
+// NewIdempotencyRepository creates a new PostgreSQL-backed idempotency key
+// repository. Keys expire ttl after they were created; zero keeps them
+// forever.
+func NewIdempotencyRepository(pool *pgxpool.Pool, ttl time.Duration, logger zerolog.Logger) IdempotencyRepository {
+	return &idempotencyRepository{
+		pool:   pool,
+		ttl:    ttl,
+		logger: logger.With().Str("repository", "idempotency").Logger(),
+	}
+}
+
+// expiredBefore returns the creation time keys created at or before have
+// expired, or nil when keys never expire.
+func (r *idempotencyRepository) expiredBefore() *time.Time {
+	if r.ttl <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-r.ttl)
+	return &cutoff
+}
+
+// Get retrieves an unexpired idempotency key used by caller in the context's
+// tenant.
+func (r *idempotencyRepository) Get(ctx context.Context, caller, key string) (*model.IdempotencyKey, error) {
+	query := `
+		SELECT idempotency_key, caller, request_hash, order_id, created_at
+		FROM idempotency_keys
+		WHERE tenant_id = $1 AND caller = $2 AND idempotency_key = $3
+		  AND ($4::timestamptz IS NULL OR created_at > $4)
+	`
+
+	var k model.IdempotencyKey
+	err := r.pool.QueryRow(ctx, query, tenantOf(ctx), caller, key, r.expiredBefore()).Scan(&k.Key, &k.Caller, &k.RequestHash, &k.OrderID, &k.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Msg("failed to query idempotency key")
+		return nil, fmt.Errorf("failed to query idempotency key: %w", Classify(err))
+	}
+
+	return &k, nil
+}
+
+// Create records an idempotency key for the context's tenant within the
+// provided transaction. An expired key not yet swept is replaced.
+func (r *idempotencyRepository) Create(ctx context.Context, tx pgx.Tx, key *model.IdempotencyKey) error {
+	query := `
+		INSERT INTO idempotency_keys (tenant_id, caller, idempotency_key, request_hash, order_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, caller, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, order_id = EXCLUDED.order_id, created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at <= $7
+	`
+
+	tag, err := tx.Exec(ctx, query, tenantOf(ctx), key.Caller, key.Key, key.RequestHash, key.OrderID, key.CreatedAt, r.expiredBefore())
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", key.OrderID.String()).Msg("failed to create idempotency key")
+		return fmt.Errorf("failed to create idempotency key: %w", Classify(err))
+	}
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug().Str("order_id", key.OrderID.String()).Msg("idempotency key already used")
+		return model.ErrIdempotencyConflict
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes up to limit expired keys of every tenant, returning
+// how many it deleted. Nothing expires when keys are kept forever.
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, limit int) (int, error) {
+	cutoff := r.expiredBefore()
+	if cutoff == nil {
+		return 0, nil
+	}
+
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE ctid = ANY(ARRAY(
+			SELECT ctid FROM idempotency_keys
+			WHERE created_at <= $1
+			LIMIT $2
+		))
+	`
+
+	tag, err := r.pool.Exec(ctx, query, *cutoff, limit)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to delete expired idempotency keys")
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", Classify(err))
+	}
+
+	return int(tag.RowsAffected()), nil
+}
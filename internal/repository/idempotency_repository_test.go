@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createIdempotencySchema creates the idempotency_keys table for testing.
+// It references orders, so the order schema must exist.
+func createIdempotencySchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			caller TEXT NOT NULL DEFAULT '',
+			idempotency_key TEXT NOT NULL,
+			request_hash TEXT NOT NULL,
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, caller, idempotency_key)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createIdempotencySchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewIdempotencyRepository(pool, time.Hour, logger)
+	ctx := context.Background()
+
+	// createOrderWithKey creates an order and records key for it in one
+	// transaction, as the api-key caller of ctx's tenant.
+	createOrderWithKey := func(t *testing.T, ctx context.Context, key string) (uuid.UUID, error) {
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		now := time.Now()
+		order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+
+		err = repo.Create(ctx, tx, &model.IdempotencyKey{
+			Key:         key,
+			Caller:      "api-key",
+			RequestHash: "hash-1",
+			OrderID:     order.ID,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		require.NoError(t, tx.Commit(ctx))
+		return order.ID, nil
+	}
+
+	t.Run("Unused key", func(t *testing.T) {
+		key, err := repo.Get(ctx, "api-key", "unused")
+		require.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("Create and get", func(t *testing.T) {
+		orderID, err := createOrderWithKey(t, ctx, "checkout-1")
+		require.NoError(t, err)
+
+		key, err := repo.Get(ctx, "api-key", "checkout-1")
+		require.NoError(t, err)
+		require.NotNil(t, key)
+		assert.Equal(t, orderID, key.OrderID)
+		assert.Equal(t, "hash-1", key.RequestHash)
+	})
+
+	t.Run("Duplicate key", func(t *testing.T) {
+		_, err := createOrderWithKey(t, ctx, "checkout-2")
+		require.NoError(t, err)
+
+		_, err = createOrderWithKey(t, ctx, "checkout-2")
+		assert.Equal(t, model.ErrIdempotencyConflict, err)
+	})
+
+	t.Run("Keys are scoped to their tenant and caller", func(t *testing.T) {
+		orderID, err := createOrderWithKey(t, ctx, "checkout-3")
+		require.NoError(t, err)
+
+		// Another caller of the same tenant has not used the key
+		key, err := repo.Get(ctx, "admin:bob", "checkout-3")
+		require.NoError(t, err)
+		assert.Nil(t, key)
+
+		// Nor has the same caller of another tenant, which may use it too
+		acme := model.WithTenant(ctx, "acme")
+		key, err = repo.Get(acme, "api-key", "checkout-3")
+		require.NoError(t, err)
+		assert.Nil(t, key)
+
+		acmeOrderID, err := createOrderWithKey(t, acme, "checkout-3")
+		require.NoError(t, err)
+		key, err = repo.Get(acme, "api-key", "checkout-3")
+		require.NoError(t, err)
+		require.NotNil(t, key)
+		assert.Equal(t, acmeOrderID, key.OrderID)
+
+		key, err = repo.Get(ctx, "api-key", "checkout-3")
+		require.NoError(t, err)
+		require.NotNil(t, key)
+		assert.Equal(t, orderID, key.OrderID)
+	})
+
+	t.Run("Expired keys", func(t *testing.T) {
+		expire := func(key string) {
+			_, err := pool.Exec(ctx, "UPDATE idempotency_keys SET created_at = NOW() - INTERVAL '2 hours' WHERE idempotency_key = $1", key)
+			require.NoError(t, err)
+		}
+
+		_, err := createOrderWithKey(t, ctx, "checkout-4")
+		require.NoError(t, err)
+		expire("checkout-4")
+
+		key, err := repo.Get(ctx, "api-key", "checkout-4")
+		require.NoError(t, err)
+		assert.Nil(t, key, "expired keys are not replayed")
+
+		// An expired key not yet swept may be used again
+		orderID, err := createOrderWithKey(t, ctx, "checkout-4")
+		require.NoError(t, err)
+		key, err = repo.Get(ctx, "api-key", "checkout-4")
+		require.NoError(t, err)
+		require.NotNil(t, key)
+		assert.Equal(t, orderID, key.OrderID)
+
+		expire("checkout-4")
+		deleted, err := repo.DeleteExpired(ctx, 100)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		deleted, err = repo.DeleteExpired(ctx, 100)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("Keys kept forever without a TTL", func(t *testing.T) {
+		forever := NewIdempotencyRepository(pool, 0, logger)
+
+		_, err := createOrderWithKey(t, ctx, "checkout-5")
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, "UPDATE idempotency_keys SET created_at = NOW() - INTERVAL '1 year' WHERE idempotency_key = 'checkout-5'")
+		require.NoError(t, err)
+
+		key, err := forever.Get(ctx, "api-key", "checkout-5")
+		require.NoError(t, err)
+		assert.NotNil(t, key)
+
+		deleted, err := forever.DeleteExpired(ctx, 100)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
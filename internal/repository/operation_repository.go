@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// operationRepository implements OperationRepository using PostgreSQL.
+type operationRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewOperationRepository creates a new PostgreSQL-backed order operation repository.
+func NewOperationRepository(pool *pgxpool.Pool, logger zerolog.Logger) OperationRepository {
+	return &operationRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "operation").Logger(),
+	}
+}
+
+// Create records a new operation for the context's tenant.
+func (r *operationRepository) Create(ctx context.Context, op *model.Operation) error {
+	query := `
+		INSERT INTO order_operations (id, status, created_at, updated_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.pool.Exec(ctx, query, op.ID, string(op.Status), op.CreatedAt, op.UpdatedAt, tenantOf(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Str("operation_id", op.ID.String()).Msg("failed to create operation")
+		return fmt.Errorf("failed to create operation: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// Complete records an operation's terminal status along with the order it
+// created or the error it failed with.
+func (r *operationRepository) Complete(ctx context.Context, op *model.Operation) error {
+	query := `
+		UPDATE order_operations
+		SET status = $2, order_id = $3, error_code = $4, error_message = $5, updated_at = $6
+		WHERE id = $1 AND ($7::text IS NULL OR tenant_id = $7)
+	`
+
+	var errorCode, errorMessage *string
+	if op.Error != nil {
+		errorCode, errorMessage = &op.Error.Code, &op.Error.Message
+	}
+
+	_, err := r.pool.Exec(ctx, query, op.ID, string(op.Status), op.OrderID, errorCode, errorMessage, op.UpdatedAt, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Str("operation_id", op.ID.String()).Msg("failed to complete operation")
+		return fmt.Errorf("failed to complete operation: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// GetByID retrieves an operation by its ID.
+func (r *operationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Operation, error) {
+	query := `
+		SELECT id, status, order_id, error_code, error_message, created_at, updated_at
+		FROM order_operations
+		WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2)
+	`
+
+	var op model.Operation
+	var errorCode, errorMessage *string
+	err := r.pool.QueryRow(ctx, query, id, tenantScope(ctx)).Scan(
+		&op.ID,
+		&op.Status,
+		&op.OrderID,
+		&errorCode,
+		&errorMessage,
+		&op.CreatedAt,
+		&op.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("operation_id", id.String()).Msg("failed to query operation")
+		return nil, fmt.Errorf("failed to query operation: %w", Classify(err))
+	}
+
+	if errorCode != nil {
+		op.Error = model.NewOperationError(model.NewDomainError(*errorCode, *errorMessage))
+	}
+
+	return &op, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOperationSchema creates the order_operations table for testing.
+// It references orders, so the order schema must exist.
+func createOperationSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS order_operations (
+			id UUID PRIMARY KEY,
+			status TEXT NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+			order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+			error_code TEXT,
+			error_message TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			tenant_id TEXT NOT NULL DEFAULT 'default'
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestOperationRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createOperationSchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewOperationRepository(pool, logger)
+	ctx := context.Background()
+
+	// newOperation records a pending operation.
+	newOperation := func(t *testing.T) *model.Operation {
+		now := time.Now()
+		op := &model.Operation{ID: uuid.New(), Status: model.OperationStatusPending, CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, repo.Create(ctx, op))
+		return op
+	}
+
+	t.Run("Unknown operation", func(t *testing.T) {
+		op, err := repo.GetByID(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Nil(t, op)
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		created := newOperation(t)
+
+		op, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op)
+		assert.Equal(t, model.OperationStatusPending, op.Status)
+		assert.Nil(t, op.OrderID)
+		assert.Nil(t, op.Error)
+	})
+
+	t.Run("Succeeded", func(t *testing.T) {
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		now := time.Now()
+		order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+		require.NoError(t, tx.Commit(ctx))
+
+		created := newOperation(t)
+		created.Status = model.OperationStatusSucceeded
+		created.OrderID = &order.ID
+		created.UpdatedAt = time.Now()
+		require.NoError(t, repo.Complete(ctx, created))
+
+		op, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op)
+		assert.Equal(t, model.OperationStatusSucceeded, op.Status)
+		assert.Equal(t, &order.ID, op.OrderID)
+		assert.Nil(t, op.Error)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		created := newOperation(t)
+		created.Status = model.OperationStatusFailed
+		created.Error = model.NewOperationError(model.ErrCouponValidationTimeout)
+		created.UpdatedAt = time.Now()
+		require.NoError(t, repo.Complete(ctx, created))
+
+		op, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op)
+		assert.Equal(t, model.OperationStatusFailed, op.Status)
+		assert.Equal(t, &model.OperationError{
+			Code:      model.ErrCodeCouponTimeout,
+			Message:   model.ErrCouponValidationTimeout.Message,
+			Retryable: true,
+		}, op.Error)
+	})
+	t.Run("Operations are scoped to their tenant", func(t *testing.T) {
+		acme := model.WithTenant(ctx, "acme")
+		now := time.Now()
+		created := &model.Operation{ID: uuid.New(), Status: model.OperationStatusPending, CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, repo.Create(acme, created))
+
+		op, err := repo.GetByID(model.WithTenant(ctx, "globex"), created.ID)
+		require.NoError(t, err)
+		assert.Nil(t, op)
+
+		op, err = repo.GetByID(acme, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op)
+		assert.Equal(t, created.ID, op.ID)
+	})
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// orderExportRepository implements OrderExportRepository using PostgreSQL.
+type orderExportRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewOrderExportRepository creates a new PostgreSQL-backed order export repository.
+func NewOrderExportRepository(pool *pgxpool.Pool, logger zerolog.Logger) OrderExportRepository {
+	return &orderExportRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "order_export").Logger(),
+	}
+}
+
+// ListUpdated retrieves up to limit orders with their items, updated after
+// the cursor and before until, ordered by update time then ID.
+func (r *orderExportRepository) ListUpdated(ctx context.Context, after model.ExportCursor, until time.Time, limit int) ([]model.ExportedOrder, error) {
+	query := `
+		SELECT id, coupon_code, source, status, metadata, subtotal, discount, total, created_at, updated_at
+		FROM orders
+		WHERE (updated_at, id) > ($1, $2) AND updated_at < $3
+		ORDER BY updated_at, id
+		LIMIT $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, after.UpdatedAt, after.ID, until, limit)
+	if err != nil {
+		r.logger.Error().Err(err).Time("after", after.UpdatedAt).Time("until", until).Msg("failed to query updated orders")
+		return nil, fmt.Errorf("failed to query updated orders: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var orders []model.ExportedOrder
+	indexByID := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var o model.ExportedOrder
+		err := rows.Scan(&o.ID, &o.CouponCode, &o.Source, &o.Status, &o.Metadata, &o.Subtotal, &o.Discount, &o.Total, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order row")
+			return nil, fmt.Errorf("failed to scan order: %w", Classify(err))
+		}
+		indexByID[o.ID] = len(orders)
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order rows")
+		return nil, fmt.Errorf("error iterating orders: %w", Classify(err))
+	}
+
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	ids := make([]uuid.UUID, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	itemsQuery := `
+		SELECT id, order_id, product_id, quantity, fulfilled_quantity, product_snapshot
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id
+	`
+
+	itemRows, err := r.pool.Query(ctx, itemsQuery, ids)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query order items")
+		return nil, fmt.Errorf("failed to query order items: %w", Classify(err))
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item model.OrderItem
+		err := itemRows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Quantity, &item.FulfilledQuantity, &item.Product)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order item row")
+			return nil, fmt.Errorf("failed to scan order item: %w", Classify(err))
+		}
+		i := indexByID[item.OrderID]
+		orders[i].Items = append(orders[i].Items, item)
+	}
+
+	if err := itemRows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order item rows")
+		return nil, fmt.Errorf("error iterating order items: %w", Classify(err))
+	}
+
+	return orders, nil
+}
+
+// GetWatermark retrieves the named export's watermark.
+func (r *orderExportRepository) GetWatermark(ctx context.Context, name string) (*time.Time, error) {
+	query := `SELECT watermark FROM export_watermarks WHERE name = $1`
+
+	var watermark time.Time
+	if err := r.pool.QueryRow(ctx, query, name).Scan(&watermark); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("export", name).Msg("failed to query export watermark")
+		return nil, fmt.Errorf("failed to query export watermark: %w", Classify(err))
+	}
+
+	return &watermark, nil
+}
+
+// SetWatermark records the named export's watermark.
+func (r *orderExportRepository) SetWatermark(ctx context.Context, name string, watermark time.Time) error {
+	query := `
+		INSERT INTO export_watermarks (name, watermark, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE SET watermark = EXCLUDED.watermark, updated_at = NOW()
+	`
+
+	if _, err := r.pool.Exec(ctx, query, name, watermark); err != nil {
+		r.logger.Error().Err(err).Str("export", name).Msg("failed to update export watermark")
+		return fmt.Errorf("failed to update export watermark: %w", Classify(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createExportWatermarkSchema creates the export watermark table for testing.
+func createExportWatermarkSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS export_watermarks (
+			name TEXT PRIMARY KEY,
+			watermark TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestOrderExportRepository_ListUpdated(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewOrderExportRepository(pool, logger)
+
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: base},
+	})
+
+	orders := []*model.Order{
+		{ID: uuid.New(), Status: model.OrderStatusPending, CreatedAt: base, UpdatedAt: base},
+		{ID: uuid.New(), Status: model.OrderStatusPending, CreatedAt: base, UpdatedAt: base.Add(time.Minute)},
+		{ID: uuid.New(), Status: model.OrderStatusPending, CreatedAt: base, UpdatedAt: base.Add(2 * time.Minute)},
+	}
+
+	tx, err := orderRepo.BeginTx(ctx)
+	require.NoError(t, err)
+	for _, o := range orders {
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, o))
+	}
+	require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, []model.OrderItem{
+		{ID: uuid.New(), OrderID: orders[1].ID, ProductID: "P001", Quantity: 2},
+		{ID: uuid.New(), OrderID: orders[1].ID, ProductID: "P001", Quantity: 1},
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	t.Run("Window excludes until", func(t *testing.T) {
+		result, err := repo.ListUpdated(ctx, model.ExportCursor{UpdatedAt: base}, base.Add(2*time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, orders[0].ID, result[0].ID)
+		assert.Empty(t, result[0].Items)
+		assert.Equal(t, orders[1].ID, result[1].ID)
+		assert.Len(t, result[1].Items, 2)
+	})
+
+	t.Run("Pages continue after the cursor", func(t *testing.T) {
+		until := base.Add(time.Hour)
+
+		first, err := repo.ListUpdated(ctx, model.ExportCursor{UpdatedAt: base}, until, 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+
+		last := first[len(first)-1]
+		second, err := repo.ListUpdated(ctx, model.ExportCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}, until, 2)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Equal(t, orders[2].ID, second[0].ID)
+	})
+}
+
+func TestOrderExportRepository_Watermark(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createExportWatermarkSchema(t, pool)
+
+	repo := NewOrderExportRepository(pool, zerolog.Nop())
+	ctx := context.Background()
+
+	watermark, err := repo.GetWatermark(ctx, "orders")
+	require.NoError(t, err)
+	assert.Nil(t, watermark)
+
+	first := time.Date(2025, 12, 1, 2, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SetWatermark(ctx, "orders", first))
+
+	second := first.Add(24 * time.Hour)
+	require.NoError(t, repo.SetWatermark(ctx, "orders", second))
+
+	watermark, err = repo.GetWatermark(ctx, "orders")
+	require.NoError(t, err)
+	require.NotNil(t, watermark)
+	assert.True(t, second.Equal(*watermark))
+}
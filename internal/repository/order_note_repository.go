@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// orderNoteRepository implements OrderNoteRepository using PostgreSQL.
+type orderNoteRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewOrderNoteRepository creates a new PostgreSQL-backed order note repository.
+func NewOrderNoteRepository(pool *pgxpool.Pool, logger zerolog.Logger) OrderNoteRepository {
+	return &orderNoteRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "order_note").Logger(),
+	}
+}
+
+// Create records a note, setting its creation time.
+func (r *orderNoteRepository) Create(ctx context.Context, note *model.OrderNote) error {
+	query := `
+		INSERT INTO order_notes (id, order_id, author, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, note.ID, note.OrderID, note.Author, note.Body).Scan(&note.CreatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", note.OrderID.String()).Msg("failed to create order note")
+		return fmt.Errorf("failed to create order note: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// ListByOrder retrieves an order's notes, oldest first.
+func (r *orderNoteRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderNote, error) {
+	query := `
+		SELECT id, order_id, author, body, created_at
+		FROM order_notes
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order notes")
+		return nil, fmt.Errorf("failed to query order notes: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	notes := []model.OrderNote{}
+	for rows.Next() {
+		var n model.OrderNote
+		if err := rows.Scan(&n.ID, &n.OrderID, &n.Author, &n.Body, &n.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order note row")
+			return nil, fmt.Errorf("failed to scan order note: %w", Classify(err))
+		}
+		notes = append(notes, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order note rows")
+		return nil, fmt.Errorf("error iterating order notes: %w", Classify(err))
+	}
+
+	return notes, nil
+}
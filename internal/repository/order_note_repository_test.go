@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOrderNoteSchema creates the order notes table for testing.
+func createOrderNoteSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS order_notes (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			author TEXT NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestOrderNoteRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createOrderNoteSchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewOrderNoteRepository(pool, logger)
+
+	ctx := context.Background()
+
+	now := time.Now()
+	orderID := uuid.New()
+	tx, err := orderRepo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, orderRepo.CreateOrder(ctx, tx, &model.Order{
+		ID:        orderID,
+		Status:    model.OrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	first := &model.OrderNote{ID: uuid.New(), OrderID: orderID, Author: "agent-7", Body: "Customer called about delivery"}
+	require.NoError(t, repo.Create(ctx, first))
+	assert.False(t, first.CreatedAt.IsZero())
+
+	second := &model.OrderNote{ID: uuid.New(), OrderID: orderID, Author: "agent-9", Body: "Refund issued"}
+	require.NoError(t, repo.Create(ctx, second))
+
+	t.Run("List oldest first", func(t *testing.T) {
+		notes, err := repo.ListByOrder(ctx, orderID)
+		require.NoError(t, err)
+		require.Len(t, notes, 2)
+		assert.Equal(t, first.ID, notes[0].ID)
+		assert.Equal(t, "agent-7", notes[0].Author)
+		assert.Equal(t, second.ID, notes[1].ID)
+	})
+
+	t.Run("Order without notes", func(t *testing.T) {
+		notes, err := repo.ListByOrder(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("Unknown order", func(t *testing.T) {
+		err := repo.Create(ctx, &model.OrderNote{ID: uuid.New(), OrderID: uuid.New(), Author: "agent-7", Body: "Lost"})
+		require.Error(t, err)
+	})
+}
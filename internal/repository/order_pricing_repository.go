@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// orderPricingRepository implements OrderPricingRepository using PostgreSQL.
+type orderPricingRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewOrderPricingRepository creates a new PostgreSQL-backed order pricing repository.
+func NewOrderPricingRepository(pool *pgxpool.Pool, logger zerolog.Logger) OrderPricingRepository {
+	return &orderPricingRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "order_pricing").Logger(),
+	}
+}
+
+// Latest retrieves an order's latest recorded pricing version.
+func (r *orderPricingRepository) Latest(ctx context.Context, orderID uuid.UUID) (*model.OrderPricingVersion, error) {
+	query := `
+		SELECT order_id, version, subtotal, discount, total, breakdown, created_by, created_at
+		FROM order_pricing_versions
+		WHERE order_id = $1
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	var v model.OrderPricingVersion
+	err := r.pool.QueryRow(ctx, query, orderID).Scan(
+		&v.OrderID,
+		&v.Version,
+		&v.Subtotal,
+		&v.Discount,
+		&v.Total,
+		&v.Breakdown,
+		&v.CreatedBy,
+		&v.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order pricing version")
+		return nil, fmt.Errorf("failed to query order pricing version: %w", Classify(err))
+	}
+
+	return &v, nil
+}
+
+// ListByOrder retrieves an order's recorded pricing versions, oldest first.
+func (r *orderPricingRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderPricingVersion, error) {
+	query := `
+		SELECT order_id, version, subtotal, discount, total, breakdown, created_by, created_at
+		FROM order_pricing_versions
+		WHERE order_id = $1
+		ORDER BY version
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order pricing versions")
+		return nil, fmt.Errorf("failed to query order pricing versions: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	versions := []model.OrderPricingVersion{}
+	for rows.Next() {
+		var v model.OrderPricingVersion
+		err := rows.Scan(
+			&v.OrderID,
+			&v.Version,
+			&v.Subtotal,
+			&v.Discount,
+			&v.Total,
+			&v.Breakdown,
+			&v.CreatedBy,
+			&v.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order pricing version row")
+			return nil, fmt.Errorf("failed to scan order pricing version: %w", Classify(err))
+		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order pricing version rows")
+		return nil, fmt.Errorf("error iterating order pricing versions: %w", Classify(err))
+	}
+
+	return versions, nil
+}
+
+// Create records a pricing version and makes its totals the order's totals.
+// The order row is locked first, so concurrent recalculations and shipments
+// of the same order are serialised and the status and fulfillment checks
+// hold until commit.
+func (r *orderPricingRepository) Create(ctx context.Context, version *model.OrderPricingVersion) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	var status model.OrderStatus
+	lockQuery := `SELECT status FROM orders WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, lockQuery, version.OrderID).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return model.ErrOrderNotFound
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to lock order")
+		return fmt.Errorf("failed to lock order: %w", Classify(err))
+	}
+
+	switch status {
+	case model.OrderStatusCancelled:
+		return model.ErrOrderCancelled
+	case model.OrderStatusFulfilled:
+		return model.ErrFulfillmentStarted
+	}
+
+	var shipped bool
+	shippedQuery := `SELECT EXISTS(SELECT 1 FROM order_items WHERE order_id = $1 AND fulfilled_quantity > 0)`
+	if err := tx.QueryRow(ctx, shippedQuery, version.OrderID).Scan(&shipped); err != nil {
+		r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to query order items")
+		return fmt.Errorf("failed to query order items: %w", Classify(err))
+	}
+	if shipped {
+		return model.ErrFulfillmentStarted
+	}
+
+	var latest int
+	latestQuery := `SELECT COALESCE(MAX(version), 0) FROM order_pricing_versions WHERE order_id = $1`
+	if err := tx.QueryRow(ctx, latestQuery, version.OrderID).Scan(&latest); err != nil {
+		r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to query order pricing versions")
+		return fmt.Errorf("failed to query order pricing versions: %w", Classify(err))
+	}
+
+	insertQuery := `
+		INSERT INTO order_pricing_versions (order_id, version, subtotal, discount, total, breakdown, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	// Record the pricing the order was placed with before its first recalculation
+	if latest == 0 {
+		originalQuery := `
+			INSERT INTO order_pricing_versions (order_id, version, subtotal, discount, total, created_at)
+			SELECT id, 1, subtotal, discount, total, created_at FROM orders WHERE id = $1
+		`
+		if _, err := tx.Exec(ctx, originalQuery, version.OrderID); err != nil {
+			r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to record original order pricing")
+			return fmt.Errorf("failed to record original order pricing: %w", Classify(err))
+		}
+		latest = 1
+	}
+	if version.Version != latest+1 {
+		return model.ErrPricingConflict
+	}
+
+	err = tx.QueryRow(ctx, insertQuery,
+		version.OrderID,
+		version.Version,
+		version.Subtotal,
+		version.Discount,
+		version.Total,
+		version.Breakdown,
+		version.CreatedBy,
+	).Scan(&version.CreatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to create order pricing version")
+		return fmt.Errorf("failed to create order pricing version: %w", Classify(err))
+	}
+
+	updateQuery := `
+		UPDATE orders
+		SET subtotal = $2, discount = $3, total = $4, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := tx.Exec(ctx, updateQuery, version.OrderID, version.Subtotal, version.Discount, version.Total); err != nil {
+		r.logger.Error().Err(err).Str("order_id", version.OrderID.String()).Msg("failed to update order totals")
+		return fmt.Errorf("failed to update order totals: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit order pricing version")
+		return fmt.Errorf("failed to commit order pricing version: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("order_id", version.OrderID.String()).
+		Int("version", version.Version).
+		Msg("order pricing version created")
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOrderPricingSchema creates the order pricing versions table for testing.
+func createOrderPricingSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS order_pricing_versions (
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			version INTEGER NOT NULL CHECK (version > 0),
+			subtotal BIGINT,
+			discount BIGINT,
+			total BIGINT,
+			breakdown JSONB,
+			created_by TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (order_id, version)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestOrderPricingRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createOrderPricingSchema(t, pool)
+
+	now := time.Now()
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+	})
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewOrderPricingRepository(pool, logger)
+	ctx := context.Background()
+
+	createOrder := func(t *testing.T, status model.OrderStatus, fulfilled int) uuid.UUID {
+		subtotal, discount, total := model.NewMoney(20), model.Money(0), model.NewMoney(20)
+		order := &model.Order{
+			ID:        uuid.New(),
+			Status:    status,
+			Subtotal:  &subtotal,
+			Discount:  &discount,
+			Total:     &total,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+		require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, []model.OrderItem{
+			{ID: uuid.New(), OrderID: order.ID, ProductID: "P001", Quantity: 2},
+		}))
+		_, err = tx.Exec(ctx, `UPDATE order_items SET fulfilled_quantity = $2 WHERE order_id = $1`, order.ID, fulfilled)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit(ctx))
+		return order.ID
+	}
+
+	newVersion := func(orderID uuid.UUID, version int, total model.Money) *model.OrderPricingVersion {
+		var discount model.Money
+		actor := "admin-1"
+		return &model.OrderPricingVersion{
+			OrderID:  orderID,
+			Version:  version,
+			Subtotal: &total,
+			Discount: &discount,
+			Total:    &total,
+			Breakdown: &model.PriceBreakdown{
+				Currency: "AUD",
+				Lines:    []model.PriceLine{{ProductID: "P001", Name: "Product A", Quantity: 2, UnitPrice: total / 2, LineTotal: total}},
+				Subtotal: total,
+				Total:    total,
+			},
+			CreatedBy: &actor,
+		}
+	}
+
+	t.Run("First recalculation records the original pricing", func(t *testing.T) {
+		orderID := createOrder(t, model.OrderStatusPending, 0)
+
+		latest, err := repo.Latest(ctx, orderID)
+		require.NoError(t, err)
+		assert.Nil(t, latest)
+
+		version := newVersion(orderID, 2, 24.00)
+		require.NoError(t, repo.Create(ctx, version))
+		assert.False(t, version.CreatedAt.IsZero())
+
+		latest, err = repo.Latest(ctx, orderID)
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, 2, latest.Version)
+		assert.Equal(t, model.NewMoney(24.00), *latest.Total)
+		assert.Equal(t, "admin-1", *latest.CreatedBy)
+		require.NotNil(t, latest.Breakdown)
+		assert.Equal(t, model.NewMoney(12.00), latest.Breakdown.Lines[0].UnitPrice)
+
+		var original float64
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT total FROM order_pricing_versions WHERE order_id = $1 AND version = 1`, orderID).Scan(&original))
+		assert.Equal(t, 20.00, original)
+
+		order, _, err := orderRepo.GetByID(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, model.NewMoney(24.00), *order.Total)
+
+		// A version that does not follow the latest lost a race
+		assert.Equal(t, model.ErrPricingConflict, repo.Create(ctx, newVersion(orderID, 2, 26.00)))
+		require.NoError(t, repo.Create(ctx, newVersion(orderID, 3, 26.00)))
+
+		versions, err := repo.ListByOrder(ctx, orderID)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		for i, v := range versions {
+			assert.Equal(t, i+1, v.Version)
+		}
+		assert.Nil(t, versions[0].Breakdown)
+		assert.Equal(t, model.NewMoney(26.00), *versions[2].Total)
+	})
+
+	t.Run("Orders that cannot be repriced", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			orderID uuid.UUID
+			err     error
+		}{
+			{name: "Unknown order", orderID: uuid.New(), err: model.ErrOrderNotFound},
+			{name: "Cancelled order", orderID: createOrder(t, model.OrderStatusCancelled, 0), err: model.ErrOrderCancelled},
+			{name: "Fulfilled order", orderID: createOrder(t, model.OrderStatusFulfilled, 2), err: model.ErrFulfillmentStarted},
+			{name: "Partially shipped order", orderID: createOrder(t, model.OrderStatusConfirmed, 1), err: model.ErrFulfillmentStarted},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.err, repo.Create(ctx, newVersion(tt.orderID, 2, 24.00)))
+
+				latest, err := repo.Latest(ctx, tt.orderID)
+				require.NoError(t, err)
+				assert.Nil(t, latest)
+			})
+		}
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"mini-kart/internal/model"
@@ -31,25 +32,40 @@ func (r *orderRepository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		r.logger.Error().Err(err).Msg("failed to begin transaction")
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
 	}
 	return tx, nil
 }
 
-// CreateOrder inserts a new order within the provided transaction.
+// CreateOrder inserts a new order within the provided transaction. Returns
+// model.ErrCustomerNotFound if the order's customer is not a customer of its
+// tenant.
 func (r *orderRepository) CreateOrder(ctx context.Context, tx pgx.Tx, order *model.Order) error {
 	query := `
-		INSERT INTO orders (id, coupon_code, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO orders (id, coupon_code, coupon_warning, source, status, metadata, subtotal, discount, total, created_at, updated_at, tenant_id, customer_id, shipping_address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err := tx.Exec(ctx, query, order.ID, order.CouponCode, order.CreatedAt, order.UpdatedAt)
+	var metadata any
+	if len(order.Metadata) > 0 {
+		metadata = order.Metadata
+	}
+
+	_, err := tx.Exec(ctx, query,
+		order.ID, order.CouponCode, order.CouponWarning, order.Source, string(order.Status), metadata,
+		order.Subtotal, order.Discount, order.Total,
+		order.CreatedAt, order.UpdatedAt, tenantOf(ctx), order.CustomerID, order.ShippingAddress,
+	)
 	if err != nil {
+		if order.CustomerID != nil && errors.Is(Classify(err), ErrForeignKeyViolation) {
+			r.logger.Warn().Str("customer_id", order.CustomerID.String()).Msg("order customer not found")
+			return model.ErrCustomerNotFound
+		}
 		r.logger.Error().
 			Err(err).
 			Str("order_id", order.ID.String()).
 			Msg("failed to create order")
-		return fmt.Errorf("failed to create order: %w", err)
+		return fmt.Errorf("failed to create order: %w", Classify(err))
 	}
 
 	r.logger.Debug().
@@ -66,13 +82,13 @@ func (r *orderRepository) CreateOrderItems(ctx context.Context, tx pgx.Tx, items
 	}
 
 	query := `
-		INSERT INTO order_items (id, order_id, product_id, quantity)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO order_items (id, order_id, product_id, quantity, product_snapshot)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
 	batch := &pgx.Batch{}
 	for _, item := range items {
-		batch.Queue(query, item.ID, item.OrderID, item.ProductID, item.Quantity)
+		batch.Queue(query, item.ID, item.OrderID, item.ProductID, item.Quantity, item.Product)
 	}
 
 	results := tx.SendBatch(ctx, batch)
@@ -86,7 +102,7 @@ func (r *orderRepository) CreateOrderItems(ctx context.Context, tx pgx.Tx, items
 				Str("order_id", items[i].OrderID.String()).
 				Str("product_id", items[i].ProductID).
 				Msg("failed to create order item")
-			return fmt.Errorf("failed to create order item: %w", err)
+			return fmt.Errorf("failed to create order item: %w", Classify(err))
 		}
 	}
 
@@ -97,19 +113,124 @@ func (r *orderRepository) CreateOrderItems(ctx context.Context, tx pgx.Tx, items
 	return nil
 }
 
+// ImportOrder stores a historical order and its items under its legacy
+// reference in one transaction. Items keep their fulfilled quantities.
+func (r *orderRepository) ImportOrder(ctx context.Context, ref string, order *model.Order, items []model.OrderItem) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.CreateOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	importQuery := `INSERT INTO order_imports (tenant_id, legacy_ref, order_id) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, importQuery, tenantOf(ctx), ref, order.ID); err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Debug().Str("legacy_ref", ref).Msg("order already imported")
+			return model.ErrOrderImported
+		}
+		r.logger.Error().Err(err).Str("legacy_ref", ref).Msg("failed to record order import")
+		return fmt.Errorf("failed to record order import: %w", Classify(err))
+	}
+
+	itemQuery := `
+		INSERT INTO order_items (id, order_id, product_id, quantity, fulfilled_quantity, product_snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	batch := &pgx.Batch{}
+	for _, item := range items {
+		batch.Queue(itemQuery, item.ID, item.OrderID, item.ProductID, item.Quantity, item.FulfilledQuantity, item.Product)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range items {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			if errors.Is(Classify(err), ErrForeignKeyViolation) {
+				return model.ErrProductNotFound
+			}
+			r.logger.Error().Err(err).Str("legacy_ref", ref).Msg("failed to import order item")
+			return fmt.Errorf("failed to import order item: %w", Classify(err))
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to import order items: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Str("legacy_ref", ref).Msg("failed to commit order import")
+		return fmt.Errorf("failed to commit order import: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("order_id", order.ID.String()).
+		Str("legacy_ref", ref).
+		Int("item_count", len(items)).
+		Msg("order imported")
+
+	return nil
+}
+
+// GetIDByRef returns the ID of the order created with an idempotency key or
+// imported with a legacy reference. Legacy references are not stored per
+// tenant, so matches are scoped through their orders. When callers of the
+// tenant reused a key, the oldest order is returned.
+func (r *orderRepository) GetIDByRef(ctx context.Context, ref string) (*uuid.UUID, error) {
+	query := `
+		SELECT refs.order_id
+		FROM (
+			SELECT order_id, 1 AS precedence FROM idempotency_keys
+			WHERE idempotency_key = $1 AND ($2::text IS NULL OR tenant_id = $2)
+			UNION ALL
+			SELECT order_id, 2 AS precedence FROM order_imports WHERE legacy_ref = $1
+		) refs
+		JOIN orders o ON o.id = refs.order_id
+		WHERE ($2::text IS NULL OR o.tenant_id = $2)
+		ORDER BY refs.precedence, o.created_at
+		LIMIT 1
+	`
+
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, query, ref, tenantScope(ctx)).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			r.logger.Debug().Msg("no order with reference")
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Msg("failed to query order by reference")
+		return nil, fmt.Errorf("failed to query order by reference: %w", Classify(err))
+	}
+
+	return &id, nil
+}
+
 // GetByID retrieves an order by its ID along with its items.
 func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, []model.OrderItem, error) {
 	// Retrieve order
 	orderQuery := `
-		SELECT id, coupon_code, created_at, updated_at
+		SELECT id, customer_id, coupon_code, coupon_warning, source, status, metadata, shipping_address, subtotal, discount, total, created_at, updated_at
 		FROM orders
-		WHERE id = $1
+		WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2)
 	`
 
 	var order model.Order
-	err := r.pool.QueryRow(ctx, orderQuery, id).Scan(
+	err := r.pool.QueryRow(ctx, orderQuery, id, tenantScope(ctx)).Scan(
 		&order.ID,
+		&order.CustomerID,
 		&order.CouponCode,
+		&order.CouponWarning,
+		&order.Source,
+		&order.Status,
+		&order.Metadata,
+		&order.ShippingAddress,
+		&order.Subtotal,
+		&order.Discount,
+		&order.Total,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
@@ -119,12 +240,12 @@ func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Ord
 			return nil, nil, nil
 		}
 		r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to query order")
-		return nil, nil, fmt.Errorf("failed to query order: %w", err)
+		return nil, nil, fmt.Errorf("failed to query order: %w", Classify(err))
 	}
 
 	// Retrieve order items
 	itemsQuery := `
-		SELECT id, order_id, product_id, quantity
+		SELECT id, order_id, product_id, quantity, fulfilled_quantity, product_snapshot
 		FROM order_items
 		WHERE order_id = $1
 		ORDER BY id
@@ -136,25 +257,304 @@ func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Ord
 			Err(err).
 			Str("order_id", id.String()).
 			Msg("failed to query order items")
-		return nil, nil, fmt.Errorf("failed to query order items: %w", err)
+		return nil, nil, fmt.Errorf("failed to query order items: %w", Classify(err))
 	}
 	defer rows.Close()
 
 	var items []model.OrderItem
 	for rows.Next() {
 		var item model.OrderItem
-		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Quantity)
+		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Quantity, &item.FulfilledQuantity, &item.Product)
 		if err != nil {
 			r.logger.Error().Err(err).Msg("failed to scan order item row")
-			return nil, nil, fmt.Errorf("failed to scan order item: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan order item: %w", Classify(err))
 		}
 		items = append(items, item)
 	}
 
 	if err := rows.Err(); err != nil {
 		r.logger.Error().Err(err).Msg("error iterating order item rows")
-		return nil, nil, fmt.Errorf("error iterating order items: %w", err)
+		return nil, nil, fmt.Errorf("error iterating order items: %w", Classify(err))
 	}
 
 	return &order, items, nil
 }
+
+// List retrieves orders matching the filter, newest first.
+func (r *orderRepository) List(ctx context.Context, filter model.OrderFilter) ([]model.Order, error) {
+	query := `
+		SELECT id, customer_id, coupon_code, coupon_warning, source, status, metadata, shipping_address, subtotal, discount, total, created_at, updated_at
+		FROM orders
+		WHERE ($1 = '' OR source = $1)
+		  AND ($4 = '' OR EXISTS (
+			SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND oi.product_id = $4
+		  ))
+		  AND ($5::text IS NULL OR tenant_id = $5)
+		  AND ($6::uuid IS NULL OR customer_id = $6)
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, filter.Source, filter.Limit, filter.Offset, filter.ProductID, tenantScope(ctx), filter.CustomerID)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("source", filter.Source).
+			Str("product_id", filter.ProductID).
+			Int("limit", filter.Limit).
+			Int("offset", filter.Offset).
+			Msg("failed to query orders")
+		return nil, fmt.Errorf("failed to query orders: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var orders []model.Order
+	for rows.Next() {
+		var o model.Order
+		err := rows.Scan(&o.ID, &o.CustomerID, &o.CouponCode, &o.CouponWarning, &o.Source, &o.Status, &o.Metadata, &o.ShippingAddress, &o.Subtotal, &o.Discount, &o.Total, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order row")
+			return nil, fmt.Errorf("failed to scan order: %w", Classify(err))
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order rows")
+		return nil, fmt.Errorf("error iterating orders: %w", Classify(err))
+	}
+
+	return orders, nil
+}
+
+// UpdateStatus moves an order from one status to another and records the
+// change in the order's status history. It returns model.ErrOrderNotFound if
+// the order does not exist and model.ErrStatusTransition if the order is no
+// longer in the from status, e.g. because a concurrent request changed it first.
+// A non-nil audit entry and any webhook deliveries are recorded in the same
+// transaction as the change.
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to model.OrderStatus, audit *model.AuditEntry, webhooks []model.WebhookDelivery, events []model.OutboxEvent) (*model.Order, error) {
+	// The history row is written by the same statement, so it exists exactly
+	// when the update succeeds
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET status = $3, updated_at = NOW()
+			WHERE id = $1 AND status = $2 AND ($5::text IS NULL OR tenant_id = $5)
+			RETURNING id, customer_id, coupon_code, coupon_warning, source, status, metadata, shipping_address, subtotal, discount, total, created_at, updated_at
+		), recorded AS (
+			INSERT INTO order_status_changes (id, order_id, from_status, to_status, created_at)
+			SELECT $4, id, $2, $3, updated_at FROM updated
+		)
+		SELECT id, customer_id, coupon_code, coupon_warning, source, status, metadata, shipping_address, subtotal, discount, total, created_at, updated_at
+		FROM updated
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	var order model.Order
+	tenantID := tenantScope(ctx)
+	err = tx.QueryRow(ctx, query, id, string(from), string(to), uuid.New(), tenantID).Scan(
+		&order.ID,
+		&order.CustomerID,
+		&order.CouponCode,
+		&order.CouponWarning,
+		&order.Source,
+		&order.Status,
+		&order.Metadata,
+		&order.ShippingAddress,
+		&order.Subtotal,
+		&order.Discount,
+		&order.Total,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to update order status")
+			return nil, fmt.Errorf("failed to update order status: %w", Classify(err))
+		}
+
+		var exists bool
+		existsQuery := `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2))`
+		if err := r.pool.QueryRow(ctx, existsQuery, id, tenantID).Scan(&exists); err != nil {
+			r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to check order")
+			return nil, fmt.Errorf("failed to check order: %w", Classify(err))
+		}
+		if !exists {
+			return nil, model.ErrOrderNotFound
+		}
+		return nil, model.ErrStatusTransition
+	}
+
+	if audit != nil {
+		if err := insertAuditEntry(ctx, tx, audit); err != nil {
+			r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to record order status audit entry")
+			return nil, err
+		}
+	}
+
+	if len(webhooks) > 0 {
+		if err := insertWebhookDeliveries(ctx, tx, webhooks); err != nil {
+			r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to enqueue order status webhooks")
+			return nil, err
+		}
+	}
+
+	if len(events) > 0 {
+		if err := insertOutboxEvents(ctx, tx, events); err != nil {
+			r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to append order status events")
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to commit order status update")
+		return nil, fmt.Errorf("failed to commit order status update: %w", Classify(err))
+	}
+
+	return &order, nil
+}
+
+// RecordAudit records an audit entry within the provided transaction.
+func (r *orderRepository) RecordAudit(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error {
+	if err := insertAuditEntry(ctx, tx, entry); err != nil {
+		r.logger.Error().Err(err).Str("action", entry.Action).Msg("failed to record audit entry")
+		return err
+	}
+	return nil
+}
+
+// Count returns the number of orders matching the filter's source, product
+// and customer, ignoring its limit and offset.
+func (r *orderRepository) Count(ctx context.Context, filter model.OrderFilter) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM orders
+		WHERE ($1 = '' OR source = $1)
+		  AND ($2 = '' OR EXISTS (
+			SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND oi.product_id = $2
+		  ))
+		  AND ($3::text IS NULL OR tenant_id = $3)
+		  AND ($4::uuid IS NULL OR customer_id = $4)
+	`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, filter.Source, filter.ProductID, tenantScope(ctx), filter.CustomerID).Scan(&count); err != nil {
+		r.logger.Error().Err(err).
+			Str("source", filter.Source).
+			Str("product_id", filter.ProductID).
+			Msg("failed to count orders")
+		return 0, fmt.Errorf("failed to count orders: %w", Classify(err))
+	}
+
+	return count, nil
+}
+
+// ListStatusChanges retrieves an order's status history, oldest first.
+func (r *orderRepository) ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]model.OrderStatusChange, error) {
+	query := `
+		SELECT id, order_id, from_status, to_status, created_at
+		FROM order_status_changes
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order status changes")
+		return nil, fmt.Errorf("failed to query order status changes: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	changes := []model.OrderStatusChange{}
+	for rows.Next() {
+		var c model.OrderStatusChange
+		if err := rows.Scan(&c.ID, &c.OrderID, &c.From, &c.To, &c.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order status change row")
+			return nil, fmt.Errorf("failed to scan order status change: %w", Classify(err))
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order status change rows")
+		return nil, fmt.Errorf("error iterating order status changes: %w", Classify(err))
+	}
+
+	return changes, nil
+}
+
+// ListAuditEntries retrieves the audit entries recorded against an order,
+// oldest first. Order audit entries carry the order ID in their details.
+func (r *orderRepository) ListAuditEntries(ctx context.Context, orderID uuid.UUID) ([]model.AuditEntry, error) {
+	query := `
+		SELECT id, actor, action, entity_type, details, created_at
+		FROM audit_log
+		WHERE entity_type = 'order' AND details->>'orderId' = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID.String())
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order audit entries")
+		return nil, fmt.Errorf("failed to query order audit entries: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.Details, &e.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order audit entry row")
+			return nil, fmt.Errorf("failed to scan order audit entry: %w", Classify(err))
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order audit entry rows")
+		return nil, fmt.Errorf("error iterating order audit entries: %w", Classify(err))
+	}
+
+	return entries, nil
+}
+
+// CountBySource returns the number of orders per source channel.
+func (r *orderRepository) CountBySource(ctx context.Context) ([]model.SourceCount, error) {
+	query := `
+		SELECT COALESCE(source, 'unattributed') AS source, COUNT(*)
+		FROM orders
+		WHERE ($1::text IS NULL OR tenant_id = $1)
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to count orders by source")
+		return nil, fmt.Errorf("failed to count orders by source: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var counts []model.SourceCount
+	for rows.Next() {
+		var c model.SourceCount
+		if err := rows.Scan(&c.Source, &c.Orders); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan source count row")
+			return nil, fmt.Errorf("failed to scan source count: %w", Classify(err))
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating source count rows")
+		return nil, fmt.Errorf("error iterating source counts: %w", Classify(err))
+	}
+
+	return counts, nil
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"mini-kart/internal/fixtures"
 	"mini-kart/internal/model"
 
 	"github.com/google/uuid"
@@ -24,23 +26,70 @@ func createOrderSchema(t *testing.T, pool *pgxpool.Pool) {
 		CREATE TABLE IF NOT EXISTS products (
 			id TEXT PRIMARY KEY,
 			name TEXT NOT NULL,
-			price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+			price BIGINT NOT NULL CHECK (price >= 0),
 			category TEXT NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			archived_at TIMESTAMPTZ,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			sale_price BIGINT CHECK (sale_price >= 0),
+			sale_starts_at TIMESTAMPTZ,
+			sale_ends_at TIMESTAMPTZ,
+			currency CHAR(3)
+		);
+
+		CREATE TABLE IF NOT EXISTS customers (
+			id UUID PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			email TEXT NOT NULL,
+			name TEXT NOT NULL,
+			segments TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (tenant_id, email),
+			UNIQUE (tenant_id, id)
 		);
 
 		CREATE TABLE IF NOT EXISTS orders (
 			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
 			coupon_code TEXT,
+			coupon_warning TEXT,
+			source TEXT,
+			status TEXT NOT NULL DEFAULT 'pending',
+			metadata JSONB,
+			shipping_address JSONB,
+			subtotal BIGINT,
+			discount BIGINT,
+			total BIGINT,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			customer_id UUID,
+			FOREIGN KEY (tenant_id, customer_id) REFERENCES customers(tenant_id, id)
 		);
 
 		CREATE TABLE IF NOT EXISTS order_items (
 			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
 			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
 			product_id TEXT NOT NULL REFERENCES products(id),
-			quantity INTEGER NOT NULL CHECK (quantity > 0)
+			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			fulfilled_quantity INTEGER NOT NULL DEFAULT 0 CHECK (fulfilled_quantity >= 0 AND fulfilled_quantity <= quantity),
+			product_snapshot JSONB
+		);
+
+		CREATE TABLE IF NOT EXISTS order_status_changes (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			from_status TEXT NOT NULL,
+			to_status TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS order_imports (
+			tenant_id TEXT NOT NULL,
+			legacy_ref TEXT NOT NULL,
+			order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+			imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, legacy_ref)
 		);
 	`
 
@@ -89,6 +138,7 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 	now := time.Now()
 	orderID := uuid.New()
 	couponCode := "TESTCODE123"
+	couponWarning := model.ErrCodeCouponTimeout
 
 	tests := []struct {
 		name  string
@@ -112,6 +162,16 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 				UpdatedAt:  now,
 			},
 		},
+		{
+			name: "Create order with unverified coupon code",
+			order: &model.Order{
+				ID:            uuid.New(),
+				CouponCode:    &couponCode,
+				CouponWarning: &couponWarning,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -125,6 +185,11 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 			err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM orders WHERE id = $1", tt.order.ID).Scan(&count)
 			require.NoError(t, err)
 			assert.Equal(t, 1, count)
+
+			var warning *string
+			err = tx.QueryRow(ctx, "SELECT coupon_warning FROM orders WHERE id = $1", tt.order.ID).Scan(&warning)
+			require.NoError(t, err)
+			assert.Equal(t, tt.order.CouponWarning, warning)
 		})
 	}
 }
@@ -141,8 +206,8 @@ func TestOrderRepository_CreateOrderItems(t *testing.T) {
 	// Seed products
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P002", Name: "Product B", Price: 20.00, Category: "Cat2", CreatedAt: now},
+		fixtures.NewProduct(1).Name("Product A").CreatedAt(now).Build(),
+		fixtures.NewProduct(2).Name("Product B").CreatedAt(now).Build(),
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -228,20 +293,19 @@ func TestOrderRepository_GetByID(t *testing.T) {
 	// Seed products
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P002", Name: "Product B", Price: 20.00, Category: "Cat2", CreatedAt: now},
+		fixtures.NewProduct(1).Name("Product A").CreatedAt(now).Build(),
+		fixtures.NewProduct(2).Name("Product B").CreatedAt(now).Build(),
 	}
 	seedProducts(t, pool, testProducts)
 
 	// Create order with items
-	orderID := uuid.New()
-	couponCode := "TESTCODE123"
-	order := &model.Order{
-		ID:         orderID,
-		CouponCode: &couponCode,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
+	order, items := fixtures.NewOrder().
+		Coupon("TESTCODE123").
+		CreatedAt(now).
+		ProductItem(testProducts[0], 2).
+		Item("P002", 3).
+		Build()
+	orderID := order.ID
 
 	tx, err := repo.BeginTx(ctx)
 	require.NoError(t, err)
@@ -249,21 +313,6 @@ func TestOrderRepository_GetByID(t *testing.T) {
 	err = repo.CreateOrder(ctx, tx, order)
 	require.NoError(t, err)
 
-	items := []model.OrderItem{
-		{
-			ID:        uuid.New(),
-			OrderID:   orderID,
-			ProductID: "P001",
-			Quantity:  2,
-		},
-		{
-			ID:        uuid.New(),
-			OrderID:   orderID,
-			ProductID: "P002",
-			Quantity:  3,
-		},
-	}
-
 	err = repo.CreateOrderItems(ctx, tx, items)
 	require.NoError(t, err)
 
@@ -323,6 +372,232 @@ func TestOrderRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestOrderRepository_ListAndCountBySource(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	web := "web"
+	pos := "pos"
+	base := time.Now().Add(-time.Hour)
+	orders := []*model.Order{
+		{ID: uuid.New(), Source: &web, CreatedAt: base, UpdatedAt: base},
+		{ID: uuid.New(), Source: &web, CreatedAt: base.Add(time.Minute), UpdatedAt: base},
+		{ID: uuid.New(), Source: &pos, CreatedAt: base.Add(2 * time.Minute), UpdatedAt: base},
+		{ID: uuid.New(), CreatedAt: base.Add(3 * time.Minute), UpdatedAt: base},
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	for _, o := range orders {
+		require.NoError(t, repo.CreateOrder(ctx, tx, o))
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	t.Run("List all newest first", func(t *testing.T) {
+		result, err := repo.List(ctx, model.OrderFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+		assert.Equal(t, orders[3].ID, result[0].ID)
+		assert.Nil(t, result[0].Source)
+	})
+
+	t.Run("List filtered by source", func(t *testing.T) {
+		result, err := repo.List(ctx, model.OrderFilter{Source: "web", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		for _, o := range result {
+			require.NotNil(t, o.Source)
+			assert.Equal(t, "web", *o.Source)
+		}
+	})
+
+	t.Run("List with pagination", func(t *testing.T) {
+		result, err := repo.List(ctx, model.OrderFilter{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, orders[2].ID, result[0].ID)
+	})
+
+	t.Run("Count ignores pagination", func(t *testing.T) {
+		count, err := repo.Count(ctx, model.OrderFilter{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+
+		count, err = repo.Count(ctx, model.OrderFilter{Source: "web", Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Count by source", func(t *testing.T) {
+		counts, err := repo.CountBySource(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []model.SourceCount{
+			{Source: "web", Orders: 2},
+			{Source: "pos", Orders: 1},
+			{Source: "unattributed", Orders: 1},
+		}, counts)
+	})
+}
+
+func TestOrderRepository_ListByProduct(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	now := time.Now()
+	seedProducts(t, pool, []model.Product{
+		fixtures.NewProduct(1).Name("Product A").CreatedAt(now).Build(),
+		fixtures.NewProduct(2).Name("Product B").CreatedAt(now).Build(),
+	})
+
+	web := "web"
+	base := now.Add(-time.Hour)
+	orders := []*model.Order{
+		{ID: uuid.New(), Source: &web, CreatedAt: base, UpdatedAt: base},
+		{ID: uuid.New(), CreatedAt: base.Add(time.Minute), UpdatedAt: base},
+		{ID: uuid.New(), CreatedAt: base.Add(2 * time.Minute), UpdatedAt: base},
+	}
+	items := []model.OrderItem{
+		{ID: uuid.New(), OrderID: orders[0].ID, ProductID: "P001", Quantity: 1},
+		{ID: uuid.New(), OrderID: orders[0].ID, ProductID: "P002", Quantity: 1},
+		{ID: uuid.New(), OrderID: orders[1].ID, ProductID: "P001", Quantity: 2},
+		{ID: uuid.New(), OrderID: orders[2].ID, ProductID: "P002", Quantity: 1},
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	for _, o := range orders {
+		require.NoError(t, repo.CreateOrder(ctx, tx, o))
+	}
+	require.NoError(t, repo.CreateOrderItems(ctx, tx, items))
+	require.NoError(t, tx.Commit(ctx))
+
+	result, err := repo.List(ctx, model.OrderFilter{ProductID: "P001", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, orders[1].ID, result[0].ID)
+	assert.Equal(t, orders[0].ID, result[1].ID)
+
+	count, err := repo.Count(ctx, model.OrderFilter{ProductID: "P001"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	result, err = repo.List(ctx, model.OrderFilter{ProductID: "P001", Source: "web", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, orders[0].ID, result[0].ID)
+
+	count, err = repo.Count(ctx, model.OrderFilter{ProductID: "P999"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestOrderRepository_ListByCustomer(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+	customers := NewCustomerRepository(pool, logger)
+
+	ctx := context.Background()
+
+	ada := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}
+	require.NoError(t, customers.Create(ctx, ada))
+	// Customers of other tenants cannot have the default tenant's orders
+	globex := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}
+	require.NoError(t, customers.Create(model.WithTenant(ctx, "globex"), globex))
+
+	base := time.Now().Add(-time.Hour)
+	orders := []*model.Order{
+		{ID: uuid.New(), CustomerID: &ada.ID, CreatedAt: base, UpdatedAt: base},
+		{ID: uuid.New(), CreatedAt: base.Add(time.Minute), UpdatedAt: base},
+		{ID: uuid.New(), CustomerID: &ada.ID, CreatedAt: base.Add(2 * time.Minute), UpdatedAt: base},
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	for _, o := range orders {
+		require.NoError(t, repo.CreateOrder(ctx, tx, o))
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	result, err := repo.List(ctx, model.OrderFilter{CustomerID: &ada.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, orders[2].ID, result[0].ID)
+	assert.Equal(t, &ada.ID, result[0].CustomerID)
+	assert.Equal(t, orders[0].ID, result[1].ID)
+
+	count, err := repo.Count(ctx, model.OrderFilter{CustomerID: &ada.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	order, _, err := repo.GetByID(ctx, orders[1].ID)
+	require.NoError(t, err)
+	assert.Nil(t, order.CustomerID)
+
+	for _, customerID := range []uuid.UUID{globex.ID, uuid.New()} {
+		tx, err := repo.BeginTx(ctx)
+		require.NoError(t, err)
+		err = repo.CreateOrder(ctx, tx, fixtures.NewOrder().Customer(customerID).Order())
+		assert.Equal(t, model.ErrCustomerNotFound, err)
+		require.NoError(t, tx.Rollback(ctx))
+	}
+}
+
+func TestCustomerRepository_HasOrders(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+	customers := NewCustomerRepository(pool, logger)
+
+	ctx := context.Background()
+
+	ada := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada", Segments: []string{"vip"}}
+	require.NoError(t, customers.Create(ctx, ada))
+
+	found, err := customers.GetByID(ctx, ada.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, []string{"vip"}, found.Segments)
+
+	hasOrders, err := customers.HasOrders(ctx, ada.ID)
+	require.NoError(t, err)
+	assert.False(t, hasOrders)
+
+	// Cancelled orders do not count
+	order := fixtures.NewOrder().Customer(ada.ID).Order()
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, tx, order))
+	require.NoError(t, tx.Commit(ctx))
+	_, err = pool.Exec(ctx, "UPDATE orders SET status = 'cancelled' WHERE id = $1", order.ID)
+	require.NoError(t, err)
+
+	hasOrders, err = customers.HasOrders(ctx, ada.ID)
+	require.NoError(t, err)
+	assert.False(t, hasOrders)
+
+	_, err = pool.Exec(ctx, "UPDATE orders SET status = 'pending' WHERE id = $1", order.ID)
+	require.NoError(t, err)
+
+	hasOrders, err = customers.HasOrders(ctx, ada.ID)
+	require.NoError(t, err)
+	assert.True(t, hasOrders)
+}
+
 func TestOrderRepository_TransactionRollback(t *testing.T) {
 	pool, cleanup := setupOrderTestDB(t)
 	defer cleanup()
@@ -396,6 +671,340 @@ func TestOrderRepository_TransactionCommit(t *testing.T) {
 	assert.Equal(t, orderID, retrievedOrder.ID)
 }
 
+func TestOrderRepository_Metadata(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+
+	now := time.Now()
+	orderID := uuid.New()
+	order := &model.Order{
+		ID: orderID,
+		Metadata: model.Metadata{
+			"giftWrap": json.RawMessage(`true`),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	require.NoError(t, repo.CreateOrder(ctx, tx, order))
+	require.NoError(t, tx.Commit(ctx))
+
+	retrievedOrder, _, err := repo.GetByID(ctx, orderID)
+	require.NoError(t, err)
+	require.NotNil(t, retrievedOrder)
+	assert.JSONEq(t, `true`, string(retrievedOrder.Metadata["giftWrap"]))
+}
+
+func TestOrderRepository_ShippingAddress(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	address := &model.Address{
+		Name:       "Ada Lovelace",
+		Line1:      "1 Main Street",
+		City:       "Sydney",
+		Region:     "NSW",
+		PostalCode: "2000",
+		Country:    "AU",
+	}
+	shipped := fixtures.NewOrder().Order()
+	shipped.ShippingAddress = address
+	collected := fixtures.NewOrder().Order()
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, tx, shipped))
+	require.NoError(t, repo.CreateOrder(ctx, tx, collected))
+	require.NoError(t, tx.Commit(ctx))
+
+	retrieved, _, err := repo.GetByID(ctx, shipped.ID)
+	require.NoError(t, err)
+	assert.Equal(t, address, retrieved.ShippingAddress)
+
+	retrieved, _, err = repo.GetByID(ctx, collected.ID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.ShippingAddress)
+}
+
+func TestOrderRepository_Totals(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+
+	subtotal, discount, total := model.NewMoney(21), model.NewMoney(5.25), model.NewMoney(15.75)
+	now := time.Now()
+	orderID := uuid.New()
+	order := &model.Order{
+		ID:        orderID,
+		Subtotal:  &subtotal,
+		Discount:  &discount,
+		Total:     &total,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	require.NoError(t, repo.CreateOrder(ctx, tx, order))
+	require.NoError(t, tx.Commit(ctx))
+
+	retrievedOrder, _, err := repo.GetByID(ctx, orderID)
+	require.NoError(t, err)
+	require.NotNil(t, retrievedOrder)
+	require.NotNil(t, retrievedOrder.Subtotal)
+	require.NotNil(t, retrievedOrder.Discount)
+	require.NotNil(t, retrievedOrder.Total)
+	assert.Equal(t, subtotal, *retrievedOrder.Subtotal)
+	assert.Equal(t, discount, *retrievedOrder.Discount)
+	assert.Equal(t, total, *retrievedOrder.Total)
+}
+
+func TestOrderRepository_ImportOrder(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now()},
+	})
+
+	placedAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	newOrder := func(productID string) (*model.Order, []model.OrderItem) {
+		subtotal, discount, total := model.NewMoney(20), model.Money(0), model.NewMoney(20)
+		order := &model.Order{
+			ID:        uuid.New(),
+			Status:    model.OrderStatusFulfilled,
+			Subtotal:  &subtotal,
+			Discount:  &discount,
+			Total:     &total,
+			CreatedAt: placedAt,
+			UpdatedAt: placedAt,
+		}
+		items := []model.OrderItem{{
+			ID:                uuid.New(),
+			OrderID:           order.ID,
+			ProductID:         productID,
+			Quantity:          2,
+			FulfilledQuantity: 2,
+			Product:           &model.ProductSnapshot{Name: "Product A", Category: "Cat1", Price: model.NewMoney(10)},
+		}}
+		return order, items
+	}
+
+	order, items := newOrder("P001")
+	require.NoError(t, repo.ImportOrder(ctx, "L-1", order, items))
+
+	retrievedOrder, retrievedItems, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, retrievedOrder)
+	assert.Equal(t, model.OrderStatusFulfilled, retrievedOrder.Status)
+	assert.True(t, placedAt.Equal(retrievedOrder.CreatedAt))
+	require.Len(t, retrievedItems, 1)
+	assert.Equal(t, 2, retrievedItems[0].FulfilledQuantity)
+	assert.Equal(t, model.NewMoney(10.0), retrievedItems[0].Product.Price)
+
+	t.Run("Reference already imported", func(t *testing.T) {
+		again, againItems := newOrder("P001")
+		err := repo.ImportOrder(ctx, "L-1", again, againItems)
+		assert.Equal(t, model.ErrOrderImported, err)
+
+		found, _, err := repo.GetByID(ctx, again.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("Unknown product", func(t *testing.T) {
+		unknown, unknownItems := newOrder("P999")
+		err := repo.ImportOrder(ctx, "L-2", unknown, unknownItems)
+		assert.Equal(t, model.ErrProductNotFound, err)
+
+		var count int
+		require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM order_imports WHERE legacy_ref = 'L-2'").Scan(&count))
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestOrderRepository_GetIDByRef(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createIdempotencySchema(t, pool)
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+	keys := NewIdempotencyRepository(pool, 0, logger)
+
+	ctx := context.Background()
+	seedProducts(t, pool, []model.Product{fixtures.NewProduct(1).CreatedAt(time.Now()).Build()})
+
+	created := fixtures.NewOrder().Order()
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateOrder(ctx, tx, created))
+	require.NoError(t, keys.Create(ctx, tx, &model.IdempotencyKey{
+		Key: "client-key-1", RequestHash: "hash", OrderID: created.ID, CreatedAt: time.Now(),
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	imported, items := fixtures.NewOrder().Item("P001", 1).Build()
+	require.NoError(t, repo.ImportOrder(ctx, "L-1", imported, items))
+
+	// An import whose reference is also another order's idempotency key
+	shadowed, shadowedItems := fixtures.NewOrder().Item("P001", 1).Build()
+	require.NoError(t, repo.ImportOrder(ctx, "client-key-1", shadowed, shadowedItems))
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		ref      string
+		expected *uuid.UUID
+	}{
+		{name: "Idempotency key", ctx: ctx, ref: "client-key-1", expected: &created.ID},
+		{name: "Legacy reference", ctx: ctx, ref: "L-1", expected: &imported.ID},
+		{name: "Unknown reference", ctx: ctx, ref: "L-404"},
+		{name: "Other tenant's order", ctx: model.WithTenant(ctx, "globex"), ref: "L-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := repo.GetIDByRef(tt.ctx, tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
+
+func TestOrderRepository_UpdateStatus(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	ctx := context.Background()
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+
+	now := time.Now()
+	orderID := uuid.New()
+	order := &model.Order{
+		ID:        orderID,
+		Status:    model.OrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	require.NoError(t, repo.CreateOrder(ctx, tx, order))
+	require.NoError(t, tx.Commit(ctx))
+
+	updated, err := repo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, nil, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, model.OrderStatusConfirmed, updated.Status)
+	assert.False(t, updated.UpdatedAt.Before(now))
+
+	changes, err := repo.ListStatusChanges(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, model.OrderStatusPending, changes[0].From)
+	assert.Equal(t, model.OrderStatusConfirmed, changes[0].To)
+
+	// The order is no longer pending, so a stale transition is rejected
+	_, err = repo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusCancelled, nil, nil, nil)
+	assert.Equal(t, model.ErrStatusTransition, err)
+
+	_, err = repo.UpdateStatus(ctx, uuid.New(), model.OrderStatusPending, model.OrderStatusCancelled, nil, nil, nil)
+	assert.Equal(t, model.ErrOrderNotFound, err)
+
+	retrievedOrder, _, err := repo.GetByID(ctx, orderID)
+	require.NoError(t, err)
+	require.NotNil(t, retrievedOrder)
+	assert.Equal(t, model.OrderStatusConfirmed, retrievedOrder.Status)
+
+	// Rejected transitions leave no history
+	changes, err = repo.ListStatusChanges(ctx, orderID)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
+
+func TestOrderRepository_ListAuditEntries(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			actor TEXT NOT NULL,
+			action TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			details JSONB,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`)
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(pool, logger)
+
+	now := time.Now()
+	orderID := uuid.New()
+	otherID := uuid.New()
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	for _, id := range []uuid.UUID{orderID, otherID} {
+		require.NoError(t, repo.CreateOrder(ctx, tx, &model.Order{ID: id, Status: model.OrderStatusPending, CreatedAt: now, UpdatedAt: now}))
+		require.NoError(t, repo.RecordAudit(ctx, tx, &model.AuditEntry{
+			Actor:      "admin:alice",
+			Action:     model.AuditActionOrderCreate,
+			EntityType: "order",
+			Details:    map[string]any{"orderId": id.String(), "onBehalfOf": "customer-1"},
+		}))
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	_, err = repo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, &model.AuditEntry{
+		Actor:      "admin:alice",
+		Action:     model.AuditActionOrderStatus,
+		EntityType: "order",
+		Details:    map[string]any{"orderId": orderID.String(), "from": "pending", "to": "confirmed"},
+	}, nil, nil)
+	require.NoError(t, err)
+
+	entries, err := repo.ListAuditEntries(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, model.AuditActionOrderCreate, entries[0].Action)
+	assert.Equal(t, model.AuditActionOrderStatus, entries[1].Action)
+	assert.Equal(t, "confirmed", entries[1].Details["to"])
+
+	entries, err = repo.ListAuditEntries(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
 func TestOrderRepository_ErrorPaths(t *testing.T) {
 	pool, cleanup := setupOrderTestDB(t)
 	defer cleanup()
@@ -408,7 +1017,7 @@ func TestOrderRepository_ErrorPaths(t *testing.T) {
 	// Seed products for testing
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// orderSLARepository implements OrderSLARepository using PostgreSQL.
+type orderSLARepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewOrderSLARepository creates a new PostgreSQL-backed order SLA repository.
+func NewOrderSLARepository(pool *pgxpool.Pool, logger zerolog.Logger) OrderSLARepository {
+	return &orderSLARepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "order_sla").Logger(),
+	}
+}
+
+// ordersInStatusQuery selects orders that have been in one of the statuses
+// in $1 for at least the matching age in milliseconds in $2, with when they
+// entered the status and for how many seconds. An order entered its status at
+// its latest status change, or when it was created if its status never
+// changed. Callers append further conditions, ordering and a limit.
+const ordersInStatusQuery = `
+	SELECT o.id, o.status, o.source, o.total, o.created_at, s.since,
+		FLOOR(EXTRACT(EPOCH FROM NOW() - s.since))::BIGINT AS age
+	FROM orders o
+	JOIN unnest($1::text[], $2::bigint[]) AS t(status, min_age_ms) ON t.status = o.status
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(MAX(c.created_at), o.created_at) AS since
+		FROM order_status_changes c
+		WHERE c.order_id = o.id
+	) s
+	WHERE s.since <= NOW() - t.min_age_ms * INTERVAL '1 millisecond'
+`
+
+// ListInStatus lists up to limit orders that have been in one of the given
+// statuses for at least the status's minimum age, longest waiting first.
+// Only the context's tenant's orders are listed when it has one.
+func (r *orderSLARepository) ListInStatus(ctx context.Context, minAges map[model.OrderStatus]time.Duration, limit int) ([]model.AtRiskOrder, error) {
+	query := ordersInStatusQuery + `
+		AND ($4::text IS NULL OR o.tenant_id = $4)
+		ORDER BY s.since, o.id
+		LIMIT $3
+	`
+
+	statuses, ages := statusAges(minAges)
+	rows, err := r.pool.Query(ctx, query, statuses, ages, limit, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to list orders by time in status")
+		return nil, fmt.Errorf("failed to list orders by time in status: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	return r.scanOrders(rows)
+}
+
+// RecordBreaches records up to limit orders that have been in one of the
+// given statuses for longer than the status's SLA and were not recorded
+// before, longest waiting first, and returns them. A breach is recorded once
+// per order and status, even when several callers record breaches at once.
+func (r *orderSLARepository) RecordBreaches(ctx context.Context, slas model.OrderSLAs, limit int) ([]model.AtRiskOrder, error) {
+	query := `
+		WITH breached AS (` + ordersInStatusQuery + `
+			AND NOT EXISTS (
+				SELECT 1 FROM order_sla_breaches b
+				WHERE b.order_id = o.id AND b.status = o.status
+			)
+			ORDER BY s.since, o.id
+			LIMIT $3
+		), recorded AS (
+			INSERT INTO order_sla_breaches (order_id, status, status_since)
+			SELECT id, status, since FROM breached
+			ON CONFLICT (order_id, status) DO NOTHING
+			RETURNING order_id
+		)
+		SELECT b.id, b.status, b.source, b.total, b.created_at, b.since, b.age
+		FROM breached b
+		JOIN recorded ON recorded.order_id = b.id
+		ORDER BY b.since, b.id
+	`
+
+	statuses, ages := statusAges(slas)
+	rows, err := r.pool.Query(ctx, query, statuses, ages, limit)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to record SLA breaches")
+		return nil, fmt.Errorf("failed to record SLA breaches: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	return r.scanOrders(rows)
+}
+
+// scanOrders reads the rows of an ordersInStatusQuery.
+func (r *orderSLARepository) scanOrders(rows pgx.Rows) ([]model.AtRiskOrder, error) {
+	orders := []model.AtRiskOrder{}
+	for rows.Next() {
+		var o model.AtRiskOrder
+		err := rows.Scan(&o.ID, &o.Status, &o.Source, &o.Total, &o.CreatedAt, &o.StatusSince, &o.TimeInStatusSeconds)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order time in status row")
+			return nil, fmt.Errorf("failed to scan order time in status: %w", Classify(err))
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order time in status rows")
+		return nil, fmt.Errorf("error iterating orders by time in status: %w", Classify(err))
+	}
+
+	return orders, nil
+}
+
+// statusAges splits minimum ages by status into the parallel arrays
+// ordersInStatusQuery expects.
+func statusAges(minAges map[model.OrderStatus]time.Duration) ([]string, []int64) {
+	statuses := make([]string, 0, len(minAges))
+	ages := make([]int64, 0, len(minAges))
+	for status, age := range minAges {
+		statuses = append(statuses, string(status))
+		ages = append(ages, age.Milliseconds())
+	}
+	return statuses, ages
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOrderSLASchema creates the order SLA breaches table for testing.
+func createOrderSLASchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS order_sla_breaches (
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			status TEXT NOT NULL,
+			status_since TIMESTAMPTZ NOT NULL,
+			detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (order_id, status)
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestOrderSLARepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createOrderSLASchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewOrderSLARepository(pool, logger)
+
+	ctx := context.Background()
+
+	createOrder := func(createdAt time.Time) uuid.UUID {
+		id := uuid.New()
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, &model.Order{
+			ID:        id,
+			Status:    model.OrderStatusPending,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}))
+		require.NoError(t, tx.Commit(ctx))
+		return id
+	}
+
+	now := time.Now()
+	stale := createOrder(now.Add(-3 * time.Hour))
+	older := createOrder(now.Add(-5 * time.Hour))
+	fresh := createOrder(now.Add(-time.Minute))
+
+	// Confirming restarts the clock, so this order is no longer stale
+	confirmed := createOrder(now.Add(-4 * time.Hour))
+	_, err := orderRepo.UpdateStatus(ctx, confirmed, model.OrderStatusPending, model.OrderStatusConfirmed, nil, nil, nil)
+	require.NoError(t, err)
+
+	slas := model.OrderSLAs{
+		model.OrderStatusPending:   2 * time.Hour,
+		model.OrderStatusConfirmed: time.Hour,
+	}
+
+	t.Run("List longest waiting first", func(t *testing.T) {
+		orders, err := repo.ListInStatus(ctx, slas, 10)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, older, orders[0].ID)
+		assert.Equal(t, model.OrderStatusPending, orders[0].Status)
+		assert.GreaterOrEqual(t, orders[0].TimeInStatusSeconds, int64(5*60*60))
+		assert.Equal(t, stale, orders[1].ID)
+	})
+
+	t.Run("List includes recently changed statuses", func(t *testing.T) {
+		orders, err := repo.ListInStatus(ctx, map[model.OrderStatus]time.Duration{model.OrderStatusConfirmed: 0}, 10)
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, confirmed, orders[0].ID)
+		assert.Less(t, orders[0].TimeInStatusSeconds, int64(60))
+	})
+
+	t.Run("List respects limit", func(t *testing.T) {
+		orders, err := repo.ListInStatus(ctx, map[model.OrderStatus]time.Duration{model.OrderStatusPending: 0}, 2)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, older, orders[0].ID)
+		assert.Equal(t, stale, orders[1].ID)
+		assert.NotContains(t, []uuid.UUID{orders[0].ID, orders[1].ID}, fresh)
+	})
+
+	t.Run("Record breaches once", func(t *testing.T) {
+		recorded, err := repo.RecordBreaches(ctx, slas, 1)
+		require.NoError(t, err)
+		require.Len(t, recorded, 1)
+		assert.Equal(t, older, recorded[0].ID)
+
+		recorded, err = repo.RecordBreaches(ctx, slas, 10)
+		require.NoError(t, err)
+		require.Len(t, recorded, 1)
+		assert.Equal(t, stale, recorded[0].ID)
+
+		recorded, err = repo.RecordBreaches(ctx, slas, 10)
+		require.NoError(t, err)
+		assert.Empty(t, recorded)
+	})
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// priceChangeColumns lists the columns selected for a price change.
+const priceChangeColumns = `id, product_id, old_price, new_price, sale, sale_starts_at, sale_ends_at, status, requested_by, decided_by, created_at, decided_at`
+
+// priceChangeTenantCondition restricts price changes to those of the
+// tenant's products. Price changes belong to the tenant of their product.
+const priceChangeTenantCondition = `($%[1]d::text IS NULL OR product_id IN (SELECT id FROM products WHERE tenant_id = $%[1]d))`
+
+// priceChangeRepository implements PriceChangeRepository using PostgreSQL.
+type priceChangeRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewPriceChangeRepository creates a new PostgreSQL-backed price change repository.
+func NewPriceChangeRepository(pool *pgxpool.Pool, logger zerolog.Logger) PriceChangeRepository {
+	return &priceChangeRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "price_change").Logger(),
+	}
+}
+
+// Create records a price change, applying it immediately when its status is applied.
+func (r *priceChangeRepository) Create(ctx context.Context, change *model.PriceChange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO price_change_approvals (product_id, old_price, new_price, sale, sale_starts_at, sale_ends_at, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	var startsAt, endsAt *time.Time
+	if change.Sale != nil {
+		startsAt, endsAt = change.Sale.StartsAt, change.Sale.EndsAt
+	}
+
+	err = tx.QueryRow(ctx, query,
+		change.ProductID,
+		change.OldPrice,
+		change.NewPrice,
+		change.Sale != nil,
+		startsAt,
+		endsAt,
+		string(change.Status),
+		change.RequestedBy,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("product_id", change.ProductID).Msg("price change already pending")
+			return model.ErrPriceChangePending
+		}
+		r.logger.Error().Err(err).Str("product_id", change.ProductID).Msg("failed to create price change")
+		return fmt.Errorf("failed to create price change: %w", Classify(err))
+	}
+
+	if change.Status == model.PriceChangeStatusApplied {
+		if err := r.apply(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit price change")
+		return fmt.Errorf("failed to commit price change: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("price_change_id", change.ID.String()).
+		Str("product_id", change.ProductID).
+		Str("status", string(change.Status)).
+		Msg("price change created")
+
+	return nil
+}
+
+// GetByID retrieves a price change by its ID.
+func (r *priceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PriceChange, error) {
+	query := `SELECT ` + priceChangeColumns + ` FROM price_change_approvals WHERE id = $1 AND ` +
+		fmt.Sprintf(priceChangeTenantCondition, 2)
+
+	change, err := scanPriceChange(r.pool.QueryRow(ctx, query, id, tenantScope(ctx)))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			r.logger.Debug().Str("price_change_id", id.String()).Msg("price change not found")
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("price_change_id", id.String()).Msg("failed to query price change")
+		return nil, fmt.Errorf("failed to query price change: %w", Classify(err))
+	}
+
+	return change, nil
+}
+
+// ListPending retrieves price changes awaiting approval, oldest first.
+func (r *priceChangeRepository) ListPending(ctx context.Context) ([]model.PriceChange, error) {
+	query := `
+		SELECT ` + priceChangeColumns + `
+		FROM price_change_approvals
+		WHERE status = 'pending' AND ` + fmt.Sprintf(priceChangeTenantCondition, 1) + `
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query pending price changes")
+		return nil, fmt.Errorf("failed to query pending price changes: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	changes := []model.PriceChange{}
+	for rows.Next() {
+		change, err := scanPriceChange(rows)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan price change row")
+			return nil, fmt.Errorf("failed to scan price change: %w", Classify(err))
+		}
+		changes = append(changes, *change)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating price change rows")
+		return nil, fmt.Errorf("error iterating price changes: %w", Classify(err))
+	}
+
+	return changes, nil
+}
+
+// Decide approves or rejects a pending price change.
+// The change row is locked so concurrent decisions cannot both succeed.
+func (r *priceChangeRepository) Decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT ` + priceChangeColumns + ` FROM price_change_approvals WHERE id = $1 AND ` +
+		fmt.Sprintf(priceChangeTenantCondition, 2) + ` FOR UPDATE`
+
+	change, err := scanPriceChange(tx.QueryRow(ctx, query, id, tenantScope(ctx)))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrPriceChangeNotFound
+		}
+		r.logger.Error().Err(err).Str("price_change_id", id.String()).Msg("failed to lock price change")
+		return nil, fmt.Errorf("failed to lock price change: %w", Classify(err))
+	}
+
+	if change.Status != model.PriceChangeStatusPending {
+		return nil, model.ErrPriceChangeNotPending
+	}
+
+	updateQuery := `
+		UPDATE price_change_approvals
+		SET status = $2, decided_by = $3, decided_at = NOW()
+		WHERE id = $1
+		RETURNING decided_at
+	`
+
+	if err := tx.QueryRow(ctx, updateQuery, id, string(status), decidedBy).Scan(&change.DecidedAt); err != nil {
+		r.logger.Error().Err(err).Str("price_change_id", id.String()).Msg("failed to update price change")
+		return nil, fmt.Errorf("failed to update price change: %w", Classify(err))
+	}
+	change.Status = status
+	change.DecidedBy = &decidedBy
+
+	if status == model.PriceChangeStatusApproved {
+		if err := r.apply(ctx, tx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit price change decision")
+		return nil, fmt.Errorf("failed to commit price change decision: %w", Classify(err))
+	}
+
+	return change, nil
+}
+
+// apply sets a product's price, or schedules its sale for a sale change,
+// within the provided transaction.
+func (r *priceChangeRepository) apply(ctx context.Context, tx pgx.Tx, change *model.PriceChange) error {
+	query, args := `UPDATE products SET price = $2 WHERE id = $1`, []any{change.ProductID, change.NewPrice}
+	if change.Sale != nil {
+		query = `UPDATE products SET sale_price = $2, sale_starts_at = $3, sale_ends_at = $4 WHERE id = $1`
+		args = append(args, change.Sale.StartsAt, change.Sale.EndsAt)
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		r.logger.Error().Err(err).Str("product_id", change.ProductID).Msg("failed to update product price")
+		return fmt.Errorf("failed to update product price: %w", Classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// scanPriceChange scans a single price change row.
+func scanPriceChange(row pgx.Row) (*model.PriceChange, error) {
+	var c model.PriceChange
+	var sale bool
+	var startsAt, endsAt *time.Time
+	err := row.Scan(
+		&c.ID,
+		&c.ProductID,
+		&c.OldPrice,
+		&c.NewPrice,
+		&sale,
+		&startsAt,
+		&endsAt,
+		&c.Status,
+		&c.RequestedBy,
+		&c.DecidedBy,
+		&c.CreatedAt,
+		&c.DecidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if sale {
+		c.Sale = &model.ProductSale{Price: c.NewPrice, StartsAt: startsAt, EndsAt: endsAt}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPriceChangeSchema creates the price_change_approvals table for testing.
+func createPriceChangeSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+		CREATE TABLE IF NOT EXISTS price_change_approvals (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+			old_price BIGINT NOT NULL CHECK (old_price >= 0),
+			new_price BIGINT NOT NULL CHECK (new_price >= 0),
+			sale BOOLEAN NOT NULL DEFAULT FALSE,
+			sale_starts_at TIMESTAMPTZ,
+			sale_ends_at TIMESTAMPTZ,
+			status TEXT NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'applied')),
+			requested_by TEXT NOT NULL,
+			decided_by TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			decided_at TIMESTAMPTZ
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_price_change_approvals_pending
+			ON price_change_approvals(product_id) WHERE status = 'pending';
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+// productPrice returns the current price of a product.
+func productPrice(t *testing.T, pool *pgxpool.Pool, id string) model.Money {
+	var price model.Money
+	err := pool.QueryRow(context.Background(), "SELECT price FROM products WHERE id = $1", id).Scan(&price)
+	require.NoError(t, err)
+	return price
+}
+
+func TestPriceChangeRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createPriceChangeSchema(t, pool)
+
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now()},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat1", CreatedAt: time.Now()},
+	})
+
+	logger := zerolog.Nop()
+	repo := NewPriceChangeRepository(pool, logger)
+	ctx := context.Background()
+
+	t.Run("Applied change updates the product price", func(t *testing.T) {
+		change := &model.PriceChange{
+			ProductID:   "P002",
+			OldPrice:    model.NewMoney(20.00),
+			NewPrice:    model.NewMoney(21.00),
+			Status:      model.PriceChangeStatusApplied,
+			RequestedBy: "admin-a",
+		}
+		require.NoError(t, repo.Create(ctx, change))
+		assert.NotEqual(t, uuid.Nil, change.ID)
+		assert.Equal(t, 21.00, productPrice(t, pool, "P002"))
+	})
+
+	var pendingID uuid.UUID
+
+	t.Run("Pending change leaves the price unchanged", func(t *testing.T) {
+		change := &model.PriceChange{
+			ProductID:   "P001",
+			OldPrice:    model.NewMoney(10.00),
+			NewPrice:    model.NewMoney(15.00),
+			Status:      model.PriceChangeStatusPending,
+			RequestedBy: "admin-a",
+		}
+		require.NoError(t, repo.Create(ctx, change))
+		pendingID = change.ID
+		assert.Equal(t, 10.00, productPrice(t, pool, "P001"))
+
+		pending, err := repo.ListPending(ctx)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, pendingID, pending[0].ID)
+	})
+
+	t.Run("Only one pending change per product", func(t *testing.T) {
+		err := repo.Create(ctx, &model.PriceChange{
+			ProductID:   "P001",
+			OldPrice:    model.NewMoney(10.00),
+			NewPrice:    model.NewMoney(30.00),
+			Status:      model.PriceChangeStatusPending,
+			RequestedBy: "admin-c",
+		})
+		assert.Equal(t, model.ErrPriceChangePending, err)
+	})
+
+	t.Run("Approval applies the new price", func(t *testing.T) {
+		change, err := repo.Decide(ctx, pendingID, model.PriceChangeStatusApproved, "admin-b")
+		require.NoError(t, err)
+		assert.Equal(t, model.PriceChangeStatusApproved, change.Status)
+		require.NotNil(t, change.DecidedBy)
+		assert.Equal(t, "admin-b", *change.DecidedBy)
+		assert.NotNil(t, change.DecidedAt)
+		assert.Equal(t, 15.00, productPrice(t, pool, "P001"))
+	})
+
+	t.Run("Decided change cannot be decided again", func(t *testing.T) {
+		_, err := repo.Decide(ctx, pendingID, model.PriceChangeStatusRejected, "admin-b")
+		assert.Equal(t, model.ErrPriceChangeNotPending, err)
+	})
+
+	t.Run("Approved sale schedules the sale and keeps the price", func(t *testing.T) {
+		endsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Microsecond)
+		change := &model.PriceChange{
+			ProductID:   "P002",
+			OldPrice:    model.NewMoney(21.00),
+			NewPrice:    model.NewMoney(5.00),
+			Sale:        &model.ProductSale{Price: model.NewMoney(5.00), EndsAt: &endsAt},
+			Status:      model.PriceChangeStatusPending,
+			RequestedBy: "admin-a",
+		}
+		require.NoError(t, repo.Create(ctx, change))
+
+		pending, err := repo.GetByID(ctx, change.ID)
+		require.NoError(t, err)
+		require.NotNil(t, pending.Sale)
+		assert.Equal(t, model.NewMoney(5.00), pending.Sale.Price)
+		require.NotNil(t, pending.Sale.EndsAt)
+		assert.True(t, endsAt.Equal(*pending.Sale.EndsAt))
+
+		var salePrice *model.Money
+		require.NoError(t, pool.QueryRow(ctx, "SELECT sale_price FROM products WHERE id = 'P002'").Scan(&salePrice))
+		assert.Nil(t, salePrice)
+
+		_, err = repo.Decide(ctx, change.ID, model.PriceChangeStatusApproved, "admin-b")
+		require.NoError(t, err)
+		require.NoError(t, pool.QueryRow(ctx, "SELECT sale_price FROM products WHERE id = 'P002'").Scan(&salePrice))
+		require.NotNil(t, salePrice)
+		assert.Equal(t, model.NewMoney(5.00), *salePrice)
+		assert.Equal(t, model.NewMoney(21.00), productPrice(t, pool, "P002"))
+	})
+
+	t.Run("Unknown change", func(t *testing.T) {
+		_, err := repo.Decide(ctx, uuid.New(), model.PriceChangeStatusApproved, "admin-b")
+		assert.Equal(t, model.ErrPriceChangeNotFound, err)
+
+		change, err := repo.GetByID(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Nil(t, change)
+	})
+}
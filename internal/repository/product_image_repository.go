@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// productImageColumns lists the product_images columns scanned by scanProductImage.
+const productImageColumns = `id, product_id, key, content_type, size_bytes, alt_text, position, created_by, created_at`
+
+// productImageRepository implements ProductImageRepository using PostgreSQL.
+type productImageRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewProductImageRepository creates a new PostgreSQL-backed product image repository.
+func NewProductImageRepository(pool *pgxpool.Pool, logger zerolog.Logger) ProductImageRepository {
+	return &productImageRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "product_image").Logger(),
+	}
+}
+
+// Create attaches an image after the product's last image. The product row
+// is locked while the position is chosen, so concurrent attachments to one
+// product take consecutive positions. Returns model.ErrProductNotFound if no
+// active product has the ID.
+func (r *productImageRepository) Create(ctx context.Context, image *model.ProductImage) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR NO KEY UPDATE leaves orders free to refer to the product meanwhile
+	var count int
+	err = tx.QueryRow(ctx, `
+		SELECT (SELECT COUNT(*) FROM product_images WHERE product_id = p.id)
+		FROM products p
+		WHERE p.id = $1 AND p.archived_at IS NULL AND ($2::text IS NULL OR p.tenant_id = $2)
+		FOR NO KEY UPDATE OF p
+	`, image.ProductID, tenantScope(ctx)).Scan(&count)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrProductNotFound
+		}
+		r.logger.Error().Err(err).Str("product_id", image.ProductID).Msg("failed to lock product")
+		return fmt.Errorf("failed to lock product: %w", Classify(err))
+	}
+	if count >= model.MaxProductImages {
+		return model.ErrTooManyImages
+	}
+
+	query := `
+		INSERT INTO product_images (id, product_id, key, content_type, size_bytes, alt_text, position, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		image.ID, image.ProductID, image.Key, image.ContentType, image.SizeBytes, image.AltText, count, image.CreatedBy,
+	).Scan(&image.CreatedAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("product_id", image.ProductID).Str("key", image.Key).Msg("product image already attached")
+			return model.ErrImageAttached
+		}
+		r.logger.Error().Err(err).Str("product_id", image.ProductID).Msg("failed to create product image")
+		return fmt.Errorf("failed to create product image: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", Classify(err))
+	}
+	image.Position = count
+
+	r.logger.Info().Str("product_id", image.ProductID).Str("image_id", image.ID.String()).Msg("product image created")
+
+	return nil
+}
+
+// ListByProducts retrieves the images of each product, in position order.
+// Products without images are absent from the map.
+func (r *productImageRepository) ListByProducts(ctx context.Context, productIDs []string) (map[string][]model.ProductImage, error) {
+	images := make(map[string][]model.ProductImage)
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	query := `
+		SELECT ` + productImageColumns + `
+		FROM product_images
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position
+	`
+
+	rows, err := r.pool.Query(ctx, query, productIDs)
+	if err != nil {
+		r.logger.Error().Err(err).Int("products", len(productIDs)).Msg("failed to query product images")
+		return nil, fmt.Errorf("failed to query product images: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image, err := scanProductImage(rows)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan product image")
+			return nil, fmt.Errorf("failed to scan product image: %w", Classify(err))
+		}
+		images[image.ProductID] = append(images[image.ProductID], *image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product images: %w", Classify(err))
+	}
+
+	return images, nil
+}
+
+// Reorder sets the positions of a product's images to their order in ids.
+// The images are locked while they are compared with ids, so an image
+// attached or removed concurrently fails the reorder rather than being lost
+// from the order.
+func (r *productImageRepository) Reorder(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+productImageColumns+`
+		FROM product_images
+		WHERE product_id = $1
+		FOR UPDATE
+	`, productID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to lock product images")
+		return nil, fmt.Errorf("failed to lock product images: %w", Classify(err))
+	}
+	current := make(map[uuid.UUID]model.ProductImage)
+	for rows.Next() {
+		image, err := scanProductImage(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan product image: %w", Classify(err))
+		}
+		current[image.ID] = *image
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product images: %w", Classify(err))
+	}
+
+	if len(ids) != len(current) {
+		return nil, model.ErrInvalidImageOrder
+	}
+	images := make([]model.ProductImage, len(ids))
+	for i, id := range ids {
+		image, ok := current[id]
+		if !ok {
+			return nil, model.ErrInvalidImageOrder
+		}
+		delete(current, id)
+		image.Position = i
+		images[i] = image
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE product_images pi
+		SET position = t.position - 1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, position)
+		WHERE pi.product_id = $1 AND pi.id = t.id
+	`, productID, ids)
+	if err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to reorder product images")
+		return nil, fmt.Errorf("failed to reorder product images: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", Classify(err))
+	}
+
+	return images, nil
+}
+
+// Delete detaches an image and moves the product's later images up one
+// position.
+func (r *productImageRepository) Delete(ctx context.Context, productID string, id uuid.UUID) (*model.ProductImage, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	image, err := scanProductImage(tx.QueryRow(ctx, `
+		DELETE FROM product_images
+		WHERE product_id = $1 AND id = $2
+		RETURNING `+productImageColumns, productID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrImageNotFound
+		}
+		r.logger.Error().Err(err).Str("image_id", id.String()).Msg("failed to delete product image")
+		return nil, fmt.Errorf("failed to delete product image: %w", Classify(err))
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE product_images
+		SET position = position - 1
+		WHERE product_id = $1 AND position > $2
+	`, productID, image.Position)
+	if err != nil {
+		r.logger.Error().Err(err).Str("product_id", productID).Msg("failed to close product image gap")
+		return nil, fmt.Errorf("failed to close product image gap: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", Classify(err))
+	}
+
+	r.logger.Info().Str("product_id", productID).Str("image_id", id.String()).Msg("product image deleted")
+
+	return image, nil
+}
+
+// scanProductImage scans a row of productImageColumns.
+func scanProductImage(row pgx.Row) (*model.ProductImage, error) {
+	var image model.ProductImage
+	err := row.Scan(
+		&image.ID, &image.ProductID, &image.Key, &image.ContentType, &image.SizeBytes,
+		&image.AltText, &image.Position, &image.CreatedBy, &image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createProductImageSchema creates the product_images table for testing.
+func createProductImageSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS product_images (
+			id UUID PRIMARY KEY,
+			product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+			key TEXT NOT NULL UNIQUE,
+			content_type TEXT NOT NULL,
+			size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+			alt_text TEXT NOT NULL DEFAULT '',
+			position INTEGER NOT NULL CHECK (position >= 0),
+			created_by TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+// testImage returns an image of the product stored under key.
+func testImage(productID, key string) *model.ProductImage {
+	return &model.ProductImage{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		Key:         key,
+		ContentType: "image/jpeg",
+		SizeBytes:   2048,
+		CreatedBy:   "admin",
+	}
+}
+
+// imageKeys returns the keys of images, in order.
+func imageKeys(images []model.ProductImage) []string {
+	keys := make([]string, len(images))
+	for i, image := range images {
+		keys[i] = image.Key
+	}
+	return keys
+}
+
+func TestProductImageRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createProductImageSchema(t, pool)
+
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now()},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat1", CreatedAt: time.Now()},
+	})
+
+	repo := NewProductImageRepository(pool, zerolog.Nop())
+	ctx := context.Background()
+
+	front, side, back := testImage("P001", "P001/front.jpg"), testImage("P001", "P001/side.jpg"), testImage("P001", "P001/back.jpg")
+
+	t.Run("Images attached in order", func(t *testing.T) {
+		for i, image := range []*model.ProductImage{front, side, back} {
+			require.NoError(t, repo.Create(ctx, image))
+			assert.Equal(t, i, image.Position)
+		}
+
+		images, err := repo.ListByProducts(ctx, []string{"P001", "P002"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P001/front.jpg", "P001/side.jpg", "P001/back.jpg"}, imageKeys(images["P001"]))
+		assert.NotContains(t, images, "P002")
+	})
+
+	t.Run("Key attached twice", func(t *testing.T) {
+		err := repo.Create(ctx, testImage("P002", "P001/front.jpg"))
+		assert.Equal(t, model.ErrImageAttached, err)
+	})
+
+	t.Run("Missing product", func(t *testing.T) {
+		err := repo.Create(ctx, testImage("P999", "P999/front.jpg"))
+		assert.Equal(t, model.ErrProductNotFound, err)
+	})
+
+	t.Run("Images reordered", func(t *testing.T) {
+		images, err := repo.Reorder(ctx, "P001", []uuid.UUID{back.ID, front.ID, side.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P001/back.jpg", "P001/front.jpg", "P001/side.jpg"}, imageKeys(images))
+
+		stored, err := repo.ListByProducts(ctx, []string{"P001"})
+		require.NoError(t, err)
+		assert.Equal(t, imageKeys(images), imageKeys(stored["P001"]))
+	})
+
+	t.Run("Reorder missing an image", func(t *testing.T) {
+		_, err := repo.Reorder(ctx, "P001", []uuid.UUID{back.ID, front.ID})
+		assert.Equal(t, model.ErrInvalidImageOrder, err)
+
+		_, err = repo.Reorder(ctx, "P001", []uuid.UUID{back.ID, front.ID, front.ID})
+		assert.Equal(t, model.ErrInvalidImageOrder, err)
+	})
+
+	t.Run("Delete closes the gap", func(t *testing.T) {
+		deleted, err := repo.Delete(ctx, "P001", front.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "P001/front.jpg", deleted.Key)
+
+		images, err := repo.ListByProducts(ctx, []string{"P001"})
+		require.NoError(t, err)
+		require.Len(t, images["P001"], 2)
+		assert.Equal(t, "P001/side.jpg", images["P001"][1].Key)
+		assert.Equal(t, 1, images["P001"][1].Position)
+
+		_, err = repo.Delete(ctx, "P001", front.ID)
+		assert.Equal(t, model.ErrImageNotFound, err)
+	})
+
+	t.Run("Image limit", func(t *testing.T) {
+		for i := 0; i < model.MaxProductImages; i++ {
+			require.NoError(t, repo.Create(ctx, testImage("P002", "P002/"+uuid.NewString()+".jpg")))
+		}
+		err := repo.Create(ctx, testImage("P002", "P002/extra.jpg"))
+		assert.Equal(t, model.ErrTooManyImages, err)
+	})
+}
@@ -2,15 +2,69 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"mini-kart/internal/model"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 )
 
+// productSortColumns maps the sort fields to their columns. Columns cannot be
+// bound as query parameters, so only these values are written into the query.
+var productSortColumns = map[model.ProductSort]string{
+	model.ProductSortName:      "name",
+	model.ProductSortPrice:     effectivePriceColumn,
+	model.ProductSortCreatedAt: "created_at",
+}
+
+// effectivePriceColumn computes the price customers pay now, matching
+// model.Product.PriceAt, so listings sorted by price put running sales in
+// their place.
+const effectivePriceColumn = `CASE
+	WHEN sale_price < price
+	 AND (sale_starts_at IS NULL OR sale_starts_at <= NOW())
+	 AND (sale_ends_at IS NULL OR sale_ends_at > NOW())
+	THEN sale_price ELSE price END`
+
+// productSale holds a product row's sale columns, which are all NULL when no
+// sale is scheduled.
+type productSale struct {
+	price    *model.Money
+	startsAt *time.Time
+	endsAt   *time.Time
+}
+
+// scanProduct reads a row of productColumns into p, and any columns selected
+// after them into extra.
+func scanProduct(row pgx.Row, p *model.Product, extra ...any) error {
+	var sale productSale
+	dest := []any{&p.ID, &p.Name, &p.Price, &p.Category, &p.CreatedAt, &p.Metadata, &sale.price, &sale.startsAt, &sale.endsAt, &p.Currency}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return err
+	}
+	p.Sale = sale.sale()
+	return nil
+}
+
+// sale returns the scheduled sale, or nil when there is none.
+func (s productSale) sale() *model.ProductSale {
+	if s.price == nil {
+		return nil
+	}
+	return &model.ProductSale{Price: *s.price, StartsAt: s.startsAt, EndsAt: s.endsAt}
+}
+
+// productColumns are the columns scanProduct reads. Products in the
+// catalogue currency may not name it.
+const productColumns = `id, name, price, category, created_at, metadata, sale_price, sale_starts_at, sale_ends_at, COALESCE(currency, '')`
+
 // productRepository implements the ProductRepository interface using PostgreSQL.
 type productRepository struct {
 	pool   *pgxpool.Pool
@@ -25,61 +79,99 @@ func NewProductRepository(pool *pgxpool.Pool, logger zerolog.Logger) ProductRepo
 	}
 }
 
-// GetAll retrieves all products with pagination support.
-func (r *productRepository) GetAll(ctx context.Context, limit, offset int) ([]model.Product, error) {
-	query := `
-		SELECT id, name, price, category, created_at
+// GetAll retrieves products matching the filter, sorted and paginated.
+func (r *productRepository) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = model.ProductSortName
+	}
+	column, ok := productSortColumns[sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported product sort %q", sort)
+	}
+	direction := "ASC"
+	if filter.Order == model.SortDesc {
+		direction = "DESC"
+	}
+
+	// id breaks ties so that pages don't overlap
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM products
-		ORDER BY name
-		LIMIT $1 OFFSET $2
-	`
+		WHERE archived_at IS NULL
+		  AND ($1 = '' OR category = $1)
+		  AND ($4::text IS NULL OR tenant_id = $4)
+		ORDER BY %s %s, id
+		LIMIT $2 OFFSET $3
+	`, productColumns, column, direction)
 
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	rows, err := r.pool.Query(ctx, query, filter.Category, filter.Limit, filter.Offset, tenantScope(ctx))
 	if err != nil {
 		r.logger.Error().Err(err).
-			Int("limit", limit).
-			Int("offset", offset).
+			Str("category", filter.Category).
+			Str("sort", string(sort)).
+			Int("limit", filter.Limit).
+			Int("offset", filter.Offset).
 			Msg("failed to query products")
-		return nil, fmt.Errorf("failed to query products: %w", err)
+		return nil, fmt.Errorf("failed to query products: %w", Classify(err))
 	}
 	defer rows.Close()
 
 	var products []model.Product
 	for rows.Next() {
 		var p model.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Category, &p.CreatedAt)
-		if err != nil {
+		if err := scanProduct(rows, &p); err != nil {
 			r.logger.Error().Err(err).Msg("failed to scan product row")
-			return nil, fmt.Errorf("failed to scan product: %w", err)
+			return nil, fmt.Errorf("failed to scan product: %w", Classify(err))
 		}
 		products = append(products, p)
 	}
 
 	if err := rows.Err(); err != nil {
 		r.logger.Error().Err(err).Msg("error iterating product rows")
-		return nil, fmt.Errorf("error iterating products: %w", err)
+		return nil, fmt.Errorf("error iterating products: %w", Classify(err))
 	}
 
 	return products, nil
 }
 
+// Count returns the number of products GetAll pages through for the filter.
+func (r *productRepository) Count(ctx context.Context, filter model.ProductFilter) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM products
+		WHERE archived_at IS NULL
+		  AND ($1 = '' OR category = $1)
+		  AND ($2::text IS NULL OR tenant_id = $2)
+	`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, filter.Category, tenantScope(ctx)).Scan(&count); err != nil {
+		r.logger.Error().Err(err).Str("category", filter.Category).Msg("failed to count products")
+		return 0, fmt.Errorf("failed to count products: %w", Classify(err))
+	}
+
+	return count, nil
+}
+
 // GetByID retrieves a single product by its ID.
 func (r *productRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
 	query := `
-		SELECT id, name, price, category, created_at
+		SELECT ` + productColumns + `
 		FROM products
-		WHERE id = $1
+		WHERE id = $1 AND archived_at IS NULL
+		  AND ($2::text IS NULL OR tenant_id = $2)
 	`
 
 	var p model.Product
-	err := r.pool.QueryRow(ctx, query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Category, &p.CreatedAt)
+	err := scanProduct(r.pool.QueryRow(ctx, query, id, tenantScope(ctx)), &p)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			r.logger.Debug().Str("product_id", id).Msg("product not found")
 			return nil, nil
 		}
 		r.logger.Error().Err(err).Str("product_id", id).Msg("failed to query product")
-		return nil, fmt.Errorf("failed to query product: %w", err)
+		return nil, fmt.Errorf("failed to query product: %w", Classify(err))
 	}
 
 	return &p, nil
@@ -92,33 +184,33 @@ func (r *productRepository) GetByIDs(ctx context.Context, ids []string) ([]model
 	}
 
 	query := `
-		SELECT id, name, price, category, created_at
+		SELECT ` + productColumns + `
 		FROM products
-		WHERE id = ANY($1)
+		WHERE id = ANY($1) AND archived_at IS NULL
+		  AND ($2::text IS NULL OR tenant_id = $2)
 		ORDER BY name
 	`
 
-	rows, err := r.pool.Query(ctx, query, ids)
+	rows, err := r.pool.Query(ctx, query, ids, tenantScope(ctx))
 	if err != nil {
 		r.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to query products by IDs")
-		return nil, fmt.Errorf("failed to query products by IDs: %w", err)
+		return nil, fmt.Errorf("failed to query products by IDs: %w", Classify(err))
 	}
 	defer rows.Close()
 
 	var products []model.Product
 	for rows.Next() {
 		var p model.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Category, &p.CreatedAt)
-		if err != nil {
+		if err := scanProduct(rows, &p); err != nil {
 			r.logger.Error().Err(err).Msg("failed to scan product row")
-			return nil, fmt.Errorf("failed to scan product: %w", err)
+			return nil, fmt.Errorf("failed to scan product: %w", Classify(err))
 		}
 		products = append(products, p)
 	}
 
 	if err := rows.Err(); err != nil {
 		r.logger.Error().Err(err).Msg("error iterating product rows")
-		return nil, fmt.Errorf("error iterating products: %w", err)
+		return nil, fmt.Errorf("error iterating products: %w", Classify(err))
 	}
 
 	return products, nil
@@ -135,14 +227,15 @@ func (r *productRepository) ValidateProductsExist(ctx context.Context, ids []str
 	query := `
 		SELECT COUNT(DISTINCT id)
 		FROM products
-		WHERE id = ANY($1)
+		WHERE id = ANY($1) AND archived_at IS NULL
+		  AND ($2::text IS NULL OR tenant_id = $2)
 	`
 
 	var count int
-	err := r.pool.QueryRow(ctx, query, ids).Scan(&count)
+	err := r.pool.QueryRow(ctx, query, ids, tenantScope(ctx)).Scan(&count)
 	if err != nil {
 		r.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to validate products exist")
-		return fmt.Errorf("failed to validate products exist: %w", err)
+		return fmt.Errorf("failed to validate products exist: %w", Classify(err))
 	}
 
 	if count != len(ids) {
@@ -155,3 +248,405 @@ func (r *productRepository) ValidateProductsExist(ctx context.Context, ids []str
 
 	return nil
 }
+
+// Create inserts a new product and sets its creation time.
+func (r *productRepository) Create(ctx context.Context, product *model.Product) error {
+	query := `
+		INSERT INTO products (id, name, price, category, metadata, tenant_id, currency)
+		VALUES ($1, $2, $3, $4, COALESCE($5, '{}'), $6, $7)
+		RETURNING created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, product.ID, product.Name, product.Price, product.Category, product.Metadata, tenantOf(ctx), currencyOrNil(product.Currency)).
+		Scan(&product.CreatedAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("product_id", product.ID).Msg("product already exists")
+			return model.ErrProductExists
+		}
+		r.logger.Error().Err(err).Str("product_id", product.ID).Msg("failed to create product")
+		return fmt.Errorf("failed to create product: %w", Classify(err))
+	}
+
+	r.logger.Debug().Str("product_id", product.ID).Msg("product created")
+
+	return nil
+}
+
+// Update changes a product's name, category and, unless nil, metadata, and
+// fills in its stored price, creation time, metadata and sale.
+func (r *productRepository) Update(ctx context.Context, product *model.Product) error {
+	query := `
+		UPDATE products
+		SET name = $2, category = $3, metadata = COALESCE($4, metadata)
+		WHERE id = $1 AND archived_at IS NULL
+		  AND ($5::text IS NULL OR tenant_id = $5)
+		RETURNING ` + productColumns + `
+	`
+
+	err := scanProduct(r.pool.QueryRow(ctx, query, product.ID, product.Name, product.Category, product.Metadata, tenantScope(ctx)), product)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			r.logger.Debug().Str("product_id", product.ID).Msg("product not found")
+			return model.ErrProductNotFound
+		}
+		r.logger.Error().Err(err).Str("product_id", product.ID).Msg("failed to update product")
+		return fmt.Errorf("failed to update product: %w", Classify(err))
+	}
+
+	r.logger.Debug().Str("product_id", product.ID).Msg("product updated")
+
+	return nil
+}
+
+// SetSale schedules product.Sale on the product, or removes its sale when
+// nil, and fills in the rest of the product.
+func (r *productRepository) SetSale(ctx context.Context, product *model.Product) error {
+	var price *model.Money
+	var startsAt, endsAt *time.Time
+	if product.Sale != nil {
+		price, startsAt, endsAt = &product.Sale.Price, product.Sale.StartsAt, product.Sale.EndsAt
+	}
+
+	query := `
+		UPDATE products
+		SET sale_price = $2, sale_starts_at = $3, sale_ends_at = $4
+		WHERE id = $1 AND archived_at IS NULL
+		  AND ($5::text IS NULL OR tenant_id = $5)
+		RETURNING ` + productColumns + `
+	`
+
+	err := scanProduct(r.pool.QueryRow(ctx, query, product.ID, price, startsAt, endsAt, tenantScope(ctx)), product)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			r.logger.Debug().Str("product_id", product.ID).Msg("product not found")
+			return model.ErrProductNotFound
+		}
+		r.logger.Error().Err(err).Str("product_id", product.ID).Msg("failed to set product sale")
+		return fmt.Errorf("failed to set product sale: %w", Classify(err))
+	}
+
+	r.logger.Debug().Str("product_id", product.ID).Bool("on_sale", product.Sale != nil).Msg("product sale set")
+
+	return nil
+}
+
+// Delete removes a product that no order refers to.
+func (r *productRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM products WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2)`
+
+	tag, err := r.pool.Exec(ctx, query, id, tenantScope(ctx))
+	if err != nil {
+		if errors.Is(Classify(err), ErrForeignKeyViolation) {
+			r.logger.Warn().Str("product_id", id).Msg("product is referenced by orders")
+			return model.ErrProductInUse
+		}
+		r.logger.Error().Err(err).Str("product_id", id).Msg("failed to delete product")
+		return fmt.Errorf("failed to delete product: %w", Classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug().Str("product_id", id).Msg("product not found")
+		return model.ErrProductNotFound
+	}
+
+	r.logger.Debug().Str("product_id", id).Msg("product deleted")
+
+	return nil
+}
+
+// Archive archives the products in one transaction and records an audit entry.
+// Nothing is archived if any product is missing or referenced by an open order;
+// the conflicts are returned instead. Already archived products are accepted.
+func (r *productRepository) Archive(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the products so they can't change while references are checked
+	found, err := r.lockProducts(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	openOrders, err := r.openOrderReferences(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ProductArchiveResult{
+		Archived:  []string{},
+		Conflicts: []model.ProductArchiveConflict{},
+	}
+	for _, id := range ids {
+		switch {
+		case !found[id]:
+			result.Conflicts = append(result.Conflicts, model.ProductArchiveConflict{
+				ProductID: id,
+				Reason:    model.ArchiveConflictNotFound,
+			})
+		case len(openOrders[id]) > 0:
+			result.Conflicts = append(result.Conflicts, model.ProductArchiveConflict{
+				ProductID: id,
+				Reason:    model.ArchiveConflictOpenOrders,
+				OrderIDs:  openOrders[id],
+			})
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		r.logger.Warn().
+			Int("count", len(ids)).
+			Int("conflicts", len(result.Conflicts)).
+			Msg("product archive rejected")
+		return result, nil
+	}
+
+	updateQuery := `
+		UPDATE products
+		SET archived_at = NOW()
+		WHERE id = ANY($1) AND archived_at IS NULL
+	`
+
+	if _, err := tx.Exec(ctx, updateQuery, ids); err != nil {
+		r.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to archive products")
+		return nil, fmt.Errorf("failed to archive products: %w", Classify(err))
+	}
+
+	err = insertAuditEntry(ctx, tx, &model.AuditEntry{
+		Actor:      actor,
+		Action:     model.AuditActionProductArchive,
+		EntityType: "product",
+		Details:    map[string]any{"productIds": ids},
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to record product archive audit entry")
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit product archive")
+		return nil, fmt.Errorf("failed to commit product archive: %w", Classify(err))
+	}
+
+	result.Archived = ids
+
+	r.logger.Debug().
+		Int("count", len(ids)).
+		Str("actor", actor).
+		Msg("products archived")
+
+	return result, nil
+}
+
+// BulkInsert copies products in one transaction using COPY. On replace, the
+// tenant's products are deleted rather than truncated, because truncating
+// would cascade into the orders referring to them.
+func (r *productRepository) BulkInsert(ctx context.Context, next func() (model.Product, bool, error), replace bool) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return 0, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	tenantID := tenantOf(ctx)
+	if replace {
+		if _, err := tx.Exec(ctx, `DELETE FROM products WHERE tenant_id = $1`, tenantID); err != nil {
+			if errors.Is(Classify(err), ErrForeignKeyViolation) {
+				r.logger.Warn().Msg("products are referenced by orders")
+				return 0, model.ErrProductInUse
+			}
+			r.logger.Error().Err(err).Msg("failed to delete products")
+			return 0, fmt.Errorf("failed to delete products: %w", Classify(err))
+		}
+	}
+
+	source := pgx.CopyFromFunc(func() ([]any, error) {
+		product, ok, err := next()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []any{product.ID, product.Name, product.Price, product.Category, tenantID, currencyOrNil(product.Currency)}, nil
+	})
+
+	count, err := tx.CopyFrom(ctx, pgx.Identifier{"products"}, []string{"id", "name", "price", "category", "tenant_id", "currency"}, source)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			r.logger.Warn().Str("detail", pgErr.Detail).Msg("product already exists")
+			return 0, model.ErrProductExists
+		}
+		r.logger.Error().Err(err).Msg("failed to copy products")
+		return 0, fmt.Errorf("failed to copy products: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit products")
+		return 0, fmt.Errorf("failed to commit products: %w", Classify(err))
+	}
+
+	r.logger.Info().Int64("products", count).Bool("replace", replace).Msg("products inserted")
+
+	return count, nil
+}
+
+// Import stages products with COPY, then inserts the staged products whose
+// IDs are free. Taken IDs are reported rather than failing the whole import.
+func (r *productRepository) Import(ctx context.Context, next func() (model.Product, bool, error)) (int64, []string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	stagingQuery := `
+		CREATE TEMP TABLE products_staging (
+			id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			price BIGINT NOT NULL,
+			category TEXT NOT NULL,
+			currency CHAR(3)
+		) ON COMMIT DROP
+	`
+	if _, err := tx.Exec(ctx, stagingQuery); err != nil {
+		return 0, nil, fmt.Errorf("failed to create staging table: %w", Classify(err))
+	}
+
+	source := pgx.CopyFromFunc(func() ([]any, error) {
+		product, ok, err := next()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []any{product.ID, product.Name, product.Price, product.Category, currencyOrNil(product.Currency)}, nil
+	})
+
+	staged, err := tx.CopyFrom(ctx, pgx.Identifier{"products_staging"}, []string{"id", "name", "price", "category", "currency"}, source)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to copy products")
+		return 0, nil, fmt.Errorf("failed to copy products: %w", Classify(err))
+	}
+
+	insertQuery := `
+		WITH inserted AS (
+			INSERT INTO products (id, name, price, category, tenant_id, currency)
+			SELECT id, name, price, category, $1, currency FROM products_staging
+			ON CONFLICT (id) DO NOTHING
+			RETURNING id
+		)
+		SELECT s.id FROM products_staging s
+		WHERE NOT EXISTS (SELECT 1 FROM inserted i WHERE i.id = s.id)
+		ORDER BY s.id
+	`
+
+	rows, err := tx.Query(ctx, insertQuery, tenantOf(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to insert products")
+		return 0, nil, fmt.Errorf("failed to insert products: %w", Classify(err))
+	}
+
+	defer rows.Close()
+
+	existing := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan product ID")
+			return 0, nil, fmt.Errorf("failed to scan product ID: %w", Classify(err))
+		}
+		existing = append(existing, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("failed to insert products")
+		return 0, nil, fmt.Errorf("failed to insert products: %w", Classify(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit products")
+		return 0, nil, fmt.Errorf("failed to commit products: %w", Classify(err))
+	}
+
+	imported := staged - int64(len(existing))
+	r.logger.Info().Int64("products", imported).Int("existing", len(existing)).Msg("products imported")
+
+	return imported, existing, nil
+}
+
+// lockProducts locks the product rows and reports which IDs exist.
+func (r *productRepository) lockProducts(ctx context.Context, tx pgx.Tx, ids []string) (map[string]bool, error) {
+	query := `SELECT id FROM products WHERE id = ANY($1) AND ($2::text IS NULL OR tenant_id = $2) FOR UPDATE`
+
+	rows, err := tx.Query(ctx, query, ids, tenantScope(ctx))
+	if err != nil {
+		r.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to lock products")
+		return nil, fmt.Errorf("failed to lock products: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool, len(ids))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan product row")
+			return nil, fmt.Errorf("failed to scan product: %w", Classify(err))
+		}
+		found[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating product rows")
+		return nil, fmt.Errorf("error iterating products: %w", Classify(err))
+	}
+
+	return found, nil
+}
+
+// openOrderReferences returns, per product, the orders that still have
+// unfulfilled items for it.
+func (r *productRepository) openOrderReferences(ctx context.Context, tx pgx.Tx, ids []string) (map[string][]uuid.UUID, error) {
+	query := `
+		SELECT product_id, array_agg(DISTINCT order_id)
+		FROM order_items
+		WHERE product_id = ANY($1) AND fulfilled_quantity < quantity
+		GROUP BY product_id
+	`
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		r.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to query open order references")
+		return nil, fmt.Errorf("failed to query open order references: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	references := make(map[string][]uuid.UUID)
+	for rows.Next() {
+		var productID string
+		var orderIDs []uuid.UUID
+		if err := rows.Scan(&productID, &orderIDs); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan open order reference row")
+			return nil, fmt.Errorf("failed to scan open order reference: %w", Classify(err))
+		}
+		references[productID] = orderIDs
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating open order reference rows")
+		return nil, fmt.Errorf("error iterating open order references: %w", Classify(err))
+	}
+
+	return references, nil
+}
+
+// currencyOrNil returns currency, or nil to store NULL for products in the
+// catalogue currency that did not name it.
+func currencyOrNil(currency string) any {
+	if currency == "" {
+		return nil
+	}
+	return currency
+}
@@ -7,6 +7,7 @@ import (
 
 	"mini-kart/internal/model"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -61,9 +62,16 @@ func createSchema(t *testing.T, pool *pgxpool.Pool) {
 		CREATE TABLE IF NOT EXISTS products (
 			id TEXT PRIMARY KEY,
 			name TEXT NOT NULL,
-			price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+			price BIGINT NOT NULL CHECK (price >= 0),
 			category TEXT NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			archived_at TIMESTAMPTZ,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			sale_price BIGINT CHECK (sale_price >= 0),
+			sale_starts_at TIMESTAMPTZ,
+			sale_ends_at TIMESTAMPTZ,
+			currency CHAR(3)
 		);
 		CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
 		CREATE INDEX IF NOT EXISTS idx_products_created_at ON products(created_at DESC);
@@ -88,6 +96,38 @@ func seedProducts(t *testing.T, pool *pgxpool.Pool, products []model.Product) {
 	}
 }
 
+func TestProductRepository_TenantScope(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewProductRepository(pool, logger)
+	acme := model.WithTenant(context.Background(), "acme")
+	globex := model.WithTenant(context.Background(), "globex")
+
+	require.NoError(t, repo.Create(acme, &model.Product{ID: "A001", Name: "Acme Anvil", Price: model.NewMoney(99.00), Category: "Tools"}))
+	require.NoError(t, repo.Create(globex, &model.Product{ID: "G001", Name: "Globex Gadget", Price: model.NewMoney(15.00), Category: "Tools"}))
+
+	products, err := repo.GetAll(acme, model.ProductFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "A001", products[0].ID)
+
+	// Another tenant's product is not found, nor changed
+	product, err := repo.GetByID(acme, "G001")
+	require.NoError(t, err)
+	assert.Nil(t, product)
+
+	assert.Equal(t, model.ErrProductNotFound, repo.ValidateProductsExist(acme, []string{"A001", "G001"}))
+
+	assert.Equal(t, model.ErrProductNotFound, repo.Delete(acme, "G001"))
+
+	// Background work sees every tenant
+	count, err := repo.Count(context.Background(), model.ProductFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
 func TestProductRepository_GetAll(t *testing.T) {
 	pool, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -97,11 +137,11 @@ func TestProductRepository_GetAll(t *testing.T) {
 
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P002", Name: "Product B", Price: 20.00, Category: "Cat2", CreatedAt: now},
-		{ID: "P003", Name: "Product C", Price: 30.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P004", Name: "Product D", Price: 40.00, Category: "Cat3", CreatedAt: now},
-		{ID: "P005", Name: "Product E", Price: 50.00, Category: "Cat2", CreatedAt: now},
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: now},
+		{ID: "P003", Name: "Product C", Price: model.NewMoney(30.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P004", Name: "Product D", Price: model.NewMoney(40.00), Category: "Cat3", CreatedAt: now},
+		{ID: "P005", Name: "Product E", Price: model.NewMoney(50.00), Category: "Cat2", CreatedAt: now},
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -147,7 +187,7 @@ func TestProductRepository_GetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 
-			products, err := repo.GetAll(ctx, tt.limit, tt.offset)
+			products, err := repo.GetAll(ctx, model.ProductFilter{Limit: tt.limit, Offset: tt.offset})
 
 			require.NoError(t, err)
 			assert.Len(t, products, tt.expected)
@@ -160,6 +200,84 @@ func TestProductRepository_GetAll(t *testing.T) {
 	}
 }
 
+func TestProductRepository_GetAll_FilterAndSort(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	repo := NewProductRepository(pool, logger)
+
+	now := time.Now()
+	testProducts := []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(30.00), Category: "Cat1", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: now.Add(-time.Hour)},
+		{ID: "P003", Name: "Product C", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P004", Name: "Product D", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now.Add(-3 * time.Hour)},
+	}
+	seedProducts(t, pool, testProducts)
+
+	ids := func(products []model.Product) []string {
+		result := make([]string, len(products))
+		for i, p := range products {
+			result[i] = p.ID
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		filter   model.ProductFilter
+		expected []string
+	}{
+		{
+			name:     "Category filter",
+			filter:   model.ProductFilter{Category: "Cat1", Limit: 10},
+			expected: []string{"P001", "P003", "P004"},
+		},
+		{
+			name:     "Price ascending breaks ties by ID",
+			filter:   model.ProductFilter{Sort: model.ProductSortPrice, Order: model.SortAsc, Limit: 10},
+			expected: []string{"P003", "P004", "P002", "P001"},
+		},
+		{
+			name:     "Newest first",
+			filter:   model.ProductFilter{Sort: model.ProductSortCreatedAt, Order: model.SortDesc, Limit: 10},
+			expected: []string{"P003", "P002", "P001", "P004"},
+		},
+		{
+			name:     "Name descending within category",
+			filter:   model.ProductFilter{Category: "Cat1", Sort: model.ProductSortName, Order: model.SortDesc, Limit: 2},
+			expected: []string{"P004", "P003"},
+		},
+		{
+			name:     "Unknown category",
+			filter:   model.ProductFilter{Category: "Cat9", Limit: 10},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			products, err := repo.GetAll(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(products))
+		})
+	}
+
+	t.Run("Count by category", func(t *testing.T) {
+		count, err := repo.Count(context.Background(), model.ProductFilter{Category: "Cat1", Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("Unsupported sort", func(t *testing.T) {
+		_, err := repo.GetAll(context.Background(), model.ProductFilter{Sort: "price; DROP TABLE products", Limit: 10})
+		require.Error(t, err)
+	})
+}
+
 func TestProductRepository_GetByID(t *testing.T) {
 	pool, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -171,7 +289,7 @@ func TestProductRepository_GetByID(t *testing.T) {
 	testProduct := model.Product{
 		ID:        "P001",
 		Name:      "Test Product",
-		Price:     99.99,
+		Price:     model.NewMoney(99.99),
 		Category:  "TestCat",
 		CreatedAt: now,
 	}
@@ -224,9 +342,9 @@ func TestProductRepository_GetByIDs(t *testing.T) {
 
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P002", Name: "Product B", Price: 20.00, Category: "Cat2", CreatedAt: now},
-		{ID: "P003", Name: "Product C", Price: 30.00, Category: "Cat1", CreatedAt: now},
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: now},
+		{ID: "P003", Name: "Product C", Price: model.NewMoney(30.00), Category: "Cat1", CreatedAt: now},
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -288,9 +406,9 @@ func TestProductRepository_ValidateProductsExist(t *testing.T) {
 
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
-		{ID: "P002", Name: "Product B", Price: 20.00, Category: "Cat2", CreatedAt: now},
-		{ID: "P003", Name: "Product C", Price: 30.00, Category: "Cat1", CreatedAt: now},
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: now},
+		{ID: "P003", Name: "Product C", Price: model.NewMoney(30.00), Category: "Cat1", CreatedAt: now},
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -351,7 +469,7 @@ func TestProductRepository_ErrorPaths(t *testing.T) {
 
 	now := time.Now()
 	testProducts := []model.Product{
-		{ID: "P001", Name: "Product A", Price: 10.00, Category: "Cat1", CreatedAt: now},
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
 	}
 	seedProducts(t, pool, testProducts)
 
@@ -360,7 +478,7 @@ func TestProductRepository_ErrorPaths(t *testing.T) {
 
 	t.Run("GetAll with closed pool", func(t *testing.T) {
 		ctx := context.Background()
-		products, err := repo.GetAll(ctx, 10, 0)
+		products, err := repo.GetAll(ctx, model.ProductFilter{Limit: 10})
 
 		require.Error(t, err)
 		assert.Nil(t, products)
@@ -389,3 +507,325 @@ func TestProductRepository_ErrorPaths(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestProductRepository_Archive(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			actor TEXT NOT NULL,
+			action TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			details JSONB,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`)
+	require.NoError(t, err)
+
+	now := time.Now()
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P003", Name: "Product C", Price: model.NewMoney(30.00), Category: "Cat2", CreatedAt: now},
+	})
+
+	logger := zerolog.Nop()
+	repo := NewProductRepository(pool, logger)
+	orderRepo := NewOrderRepository(pool, logger)
+
+	// P002 is referenced by an open order
+	order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+	tx, err := orderRepo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+	require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, []model.OrderItem{
+		{ID: uuid.New(), OrderID: order.ID, ProductID: "P002", Quantity: 1},
+	}))
+	require.NoError(t, tx.Commit(ctx))
+
+	t.Run("Conflicts archive nothing", func(t *testing.T) {
+		result, err := repo.Archive(ctx, []string{"P001", "P002", "P999"}, "admin-a")
+		require.NoError(t, err)
+
+		assert.Empty(t, result.Archived)
+		require.Len(t, result.Conflicts, 2)
+		assert.Equal(t, "P002", result.Conflicts[0].ProductID)
+		assert.Equal(t, model.ArchiveConflictOpenOrders, result.Conflicts[0].Reason)
+		assert.Equal(t, []uuid.UUID{order.ID}, result.Conflicts[0].OrderIDs)
+		assert.Equal(t, "P999", result.Conflicts[1].ProductID)
+		assert.Equal(t, model.ArchiveConflictNotFound, result.Conflicts[1].Reason)
+
+		product, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.NotNil(t, product, "P001 must not be archived when the batch has conflicts")
+	})
+
+	t.Run("Archives products and records an audit entry", func(t *testing.T) {
+		result, err := repo.Archive(ctx, []string{"P001", "P003"}, "admin-a")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"P001", "P003"}, result.Archived)
+		assert.Empty(t, result.Conflicts)
+
+		product, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.Nil(t, product)
+
+		products, err := repo.GetAll(ctx, model.ProductFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, "P002", products[0].ID)
+
+		count, err := repo.Count(ctx, model.ProductFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		var actor string
+		err = pool.QueryRow(ctx, "SELECT actor FROM audit_log WHERE action = $1", model.AuditActionProductArchive).Scan(&actor)
+		require.NoError(t, err)
+		assert.Equal(t, "admin-a", actor)
+	})
+
+	t.Run("Archiving again is accepted", func(t *testing.T) {
+		result, err := repo.Archive(ctx, []string{"P001"}, "admin-a")
+		require.NoError(t, err)
+		assert.Empty(t, result.Conflicts)
+	})
+}
+
+func TestProductRepository_CreateUpdateDelete(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	repo := NewProductRepository(pool, logger)
+	orderRepo := NewOrderRepository(pool, logger)
+
+	t.Run("Create and reject duplicate", func(t *testing.T) {
+		product := &model.Product{ID: "P100", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle", Metadata: map[string]any{"weight": "120g"}}
+		require.NoError(t, repo.Create(ctx, product))
+		assert.False(t, product.CreatedAt.IsZero())
+
+		err := repo.Create(ctx, &model.Product{ID: "P100", Name: "Other", Price: model.NewMoney(1), Category: "Other"})
+		assert.Equal(t, model.ErrProductExists, err)
+	})
+
+	t.Run("Create stores price in cents with currency", func(t *testing.T) {
+		product := &model.Product{ID: "P101", Name: "Crepe", Price: model.NewMoney(10.99), Category: "Crepe", Currency: "AUD"}
+		require.NoError(t, repo.Create(ctx, product))
+
+		var cents int64
+		require.NoError(t, pool.QueryRow(ctx, `SELECT price FROM products WHERE id = 'P101'`).Scan(&cents))
+		assert.Equal(t, int64(1099), cents)
+
+		stored, err := repo.GetByID(ctx, "P101")
+		require.NoError(t, err)
+		assert.Equal(t, model.NewMoney(10.99), stored.Price)
+		assert.Equal(t, "AUD", stored.Currency)
+
+		// Products that did not name their currency are in the catalogue currency
+		stored, err = repo.GetByID(ctx, "P100")
+		require.NoError(t, err)
+		assert.Empty(t, stored.Currency)
+	})
+
+	t.Run("Update keeps price", func(t *testing.T) {
+		product := &model.Product{ID: "P100", Name: "Belgian Waffle", Category: "Dessert"}
+		require.NoError(t, repo.Update(ctx, product))
+		assert.Equal(t, model.NewMoney(6.5), product.Price)
+		assert.Equal(t, map[string]any{"weight": "120g"}, product.Metadata)
+
+		stored, err := repo.GetByID(ctx, "P100")
+		require.NoError(t, err)
+		assert.Equal(t, "Belgian Waffle", stored.Name)
+		assert.Equal(t, "Dessert", stored.Category)
+
+		product.Metadata = map[string]any{"weight": "150g", "vegan": true}
+		require.NoError(t, repo.Update(ctx, product))
+		stored, err = repo.GetByID(ctx, "P100")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"weight": "150g", "vegan": true}, stored.Metadata)
+
+		err = repo.Update(ctx, &model.Product{ID: "P999", Name: "A", Category: "B"})
+		assert.Equal(t, model.ErrProductNotFound, err)
+	})
+
+	t.Run("Delete refuses products on orders", func(t *testing.T) {
+		now := time.Now()
+		order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+		require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, []model.OrderItem{
+			{ID: uuid.New(), OrderID: order.ID, ProductID: "P100", Quantity: 1},
+		}))
+		require.NoError(t, tx.Commit(ctx))
+
+		assert.Equal(t, model.ErrProductInUse, repo.Delete(ctx, "P100"))
+	})
+
+	t.Run("Delete unreferenced product", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &model.Product{ID: "P200", Name: "Pie", Price: model.NewMoney(4), Category: "Pie"}))
+		require.NoError(t, repo.Delete(ctx, "P200"))
+		assert.Equal(t, model.ErrProductNotFound, repo.Delete(ctx, "P200"))
+	})
+}
+
+func TestProductRepository_SetSale(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewProductRepository(pool, zerolog.Nop())
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.50), Category: "Waffle", CreatedAt: time.Now()},
+		{ID: "P002", Name: "Pancake", Price: model.NewMoney(5.00), Category: "Waffle", CreatedAt: time.Now()},
+	})
+
+	t.Run("Schedules sale", func(t *testing.T) {
+		endsAt := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+		product := &model.Product{ID: "P001", Sale: &model.ProductSale{Price: model.NewMoney(4.25), EndsAt: &endsAt}}
+		require.NoError(t, repo.SetSale(ctx, product))
+		assert.Equal(t, "Waffle", product.Name)
+		assert.Equal(t, model.NewMoney(6.50), product.Price)
+
+		stored, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		require.NotNil(t, stored.Sale)
+		assert.Equal(t, model.NewMoney(4.25), stored.Sale.Price)
+		assert.Nil(t, stored.Sale.StartsAt)
+		require.NotNil(t, stored.Sale.EndsAt)
+		assert.True(t, endsAt.Equal(*stored.Sale.EndsAt))
+	})
+
+	t.Run("Sorts by the price paid", func(t *testing.T) {
+		products, err := repo.GetAll(ctx, model.ProductFilter{Sort: model.ProductSortPrice, Order: model.SortAsc, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, products, 2)
+		assert.Equal(t, "P001", products[0].ID)
+	})
+
+	t.Run("Removes sale", func(t *testing.T) {
+		require.NoError(t, repo.SetSale(ctx, &model.Product{ID: "P001"}))
+
+		stored, err := repo.GetByID(ctx, "P001")
+		require.NoError(t, err)
+		assert.Nil(t, stored.Sale)
+	})
+
+	t.Run("Unknown product", func(t *testing.T) {
+		err := repo.SetSale(ctx, &model.Product{ID: "P999", Sale: &model.ProductSale{Price: model.NewMoney(1)}})
+		assert.Equal(t, model.ErrProductNotFound, err)
+	})
+}
+
+// productIterator returns a BulkInsert source yielding products in order.
+func productIterator(products []model.Product) func() (model.Product, bool, error) {
+	i := 0
+	return func() (model.Product, bool, error) {
+		if i == len(products) {
+			return model.Product{}, false, nil
+		}
+		i++
+		return products[i-1], true, nil
+	}
+}
+
+func TestProductRepository_BulkInsert(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	repo := NewProductRepository(pool, logger)
+	orderRepo := NewOrderRepository(pool, logger)
+
+	catalog := []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(6.5), Category: "Waffle"},
+		{ID: "P002", Name: "Lemon Tart", Price: model.NewMoney(4.25), Category: "Tart"},
+	}
+
+	t.Run("Inserts all products", func(t *testing.T) {
+		count, err := repo.BulkInsert(ctx, productIterator(catalog), false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		stored, err := repo.GetByID(ctx, "P002")
+		require.NoError(t, err)
+		assert.Equal(t, "Lemon Tart", stored.Name)
+		assert.Equal(t, model.NewMoney(4.25), stored.Price)
+	})
+
+	t.Run("Existing ID inserts nothing", func(t *testing.T) {
+		_, err := repo.BulkInsert(ctx, productIterator([]model.Product{
+			{ID: "P003", Name: "Pie", Price: model.NewMoney(4), Category: "Pie"},
+			{ID: "P001", Name: "Other", Price: model.NewMoney(1), Category: "Other"},
+		}), false)
+		assert.Equal(t, model.ErrProductExists, err)
+
+		stored, err := repo.GetByID(ctx, "P003")
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+	})
+
+	t.Run("Replace deletes existing products", func(t *testing.T) {
+		count, err := repo.BulkInsert(ctx, productIterator([]model.Product{
+			{ID: "P001", Name: "Belgian Waffle", Price: model.NewMoney(7), Category: "Waffle"},
+		}), true)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		total, err := repo.Count(ctx, model.ProductFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+	})
+
+	t.Run("Replace refuses products on orders", func(t *testing.T) {
+		now := time.Now()
+		order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+		require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, []model.OrderItem{
+			{ID: uuid.New(), OrderID: order.ID, ProductID: "P001", Quantity: 1},
+		}))
+		require.NoError(t, tx.Commit(ctx))
+
+		_, err = repo.BulkInsert(ctx, productIterator(catalog[1:]), true)
+		assert.Equal(t, model.ErrProductInUse, err)
+	})
+}
+
+func TestProductRepository_Import(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewProductRepository(pool, zerolog.Nop())
+
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(6.5), Category: "Waffle", CreatedAt: time.Now()},
+	})
+
+	imported, existing, err := repo.Import(ctx, productIterator([]model.Product{
+		{ID: "P001", Name: "Other Waffle", Price: model.NewMoney(1), Category: "Waffle"},
+		{ID: "P002", Name: "Lemon Tart", Price: model.NewMoney(4.25), Category: "Tart"},
+		{ID: "P003", Name: "Apple Pie", Price: model.NewMoney(5), Category: "Pie"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), imported)
+	assert.Equal(t, []string{"P001"}, existing)
+
+	stored, err := repo.GetByID(ctx, "P001")
+	require.NoError(t, err)
+	assert.Equal(t, "Chicken Waffle", stored.Name)
+
+	stored, err = repo.GetByID(ctx, "P003")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "Apple Pie", stored.Name)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"mini-kart/internal/model"
 
@@ -10,9 +11,16 @@ import (
 )
 
 // ProductRepository defines the interface for product data access operations.
+// Products are scoped to the context's tenant: reads and writes only see its
+// products, and created products belong to it.
 type ProductRepository interface {
-	// GetAll retrieves all products with pagination support.
-	GetAll(ctx context.Context, limit, offset int) ([]model.Product, error)
+	// GetAll retrieves products matching the filter's category, sorted and
+	// paginated as requested. Products are sorted by name when no sort is set.
+	GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, error)
+
+	// Count returns the number of products GetAll pages through for the
+	// filter, ignoring its sort, limit and offset.
+	Count(ctx context.Context, filter model.ProductFilter) (int, error)
 
 	// GetByID retrieves a single product by its ID.
 	GetByID(ctx context.Context, id string) (*model.Product, error)
@@ -23,19 +31,454 @@ type ProductRepository interface {
 	// ValidateProductsExist checks if all provided product IDs exist in the database.
 	// Returns error if any product ID does not exist.
 	ValidateProductsExist(ctx context.Context, ids []string) error
+
+	// Create inserts a new product. Returns model.ErrProductExists if the ID is taken.
+	Create(ctx context.Context, product *model.Product) error
+
+	// Update changes a product's name, category and, unless nil, metadata.
+	// Prices are changed through PriceChangeRepository. Returns
+	// model.ErrProductNotFound if no active product has the ID.
+	Update(ctx context.Context, product *model.Product) error
+
+	// SetSale schedules the product's Sale, or removes its sale when nil,
+	// and fills in the rest of the product. Returns model.ErrProductNotFound
+	// if no active product has the ID.
+	SetSale(ctx context.Context, product *model.Product) error
+
+	// Delete removes a product. Returns model.ErrProductInUse if orders refer to
+	// it and model.ErrProductNotFound if it does not exist.
+	Delete(ctx context.Context, id string) error
+
+	// Archive hides products from the catalogue in one transaction with an
+	// audit entry. Nothing is archived when any product is missing or still
+	// referenced by an open order; the conflicts are returned instead.
+	Archive(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error)
+
+	// BulkInsert copies the products read from next, which returns false once
+	// exhausted, in one transaction. With replace set, existing products are
+	// deleted first. Returns model.ErrProductExists if an ID is taken or
+	// repeated and model.ErrProductInUse if replace would delete products
+	// that orders refer to. Returns the number of products inserted.
+	BulkInsert(ctx context.Context, next func() (model.Product, bool, error), replace bool) (int64, error)
+
+	// Import copies the products read from next, which returns false once
+	// exhausted, and inserts those whose IDs are not taken, in one
+	// transaction. Returns the number inserted and the IDs that already existed.
+	Import(ctx context.Context, next func() (model.Product, bool, error)) (int64, []string, error)
 }
 
 // OrderRepository defines the interface for order data access operations.
+// Orders are scoped to the context's tenant like products.
 type OrderRepository interface {
 	// BeginTx starts a new database transaction.
 	BeginTx(ctx context.Context) (pgx.Tx, error)
 
 	// CreateOrder inserts a new order within the provided transaction.
+	// Returns model.ErrCustomerNotFound if the order's customer is not a
+	// customer of the context's tenant.
 	CreateOrder(ctx context.Context, tx pgx.Tx, order *model.Order) error
 
 	// CreateOrderItems inserts multiple order items within the provided transaction.
 	CreateOrderItems(ctx context.Context, tx pgx.Tx, items []model.OrderItem) error
 
+	// ImportOrder stores a historical order and its items in a transaction
+	// of its own, recording ref as its reference on the platform it was
+	// migrated from. Returns model.ErrOrderImported if an order with the ref
+	// was already imported and model.ErrProductNotFound if an item's product
+	// does not exist.
+	ImportOrder(ctx context.Context, ref string, order *model.Order, items []model.OrderItem) error
+
 	// GetByID retrieves an order by its ID along with its items.
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Order, []model.OrderItem, error)
+
+	// GetIDByRef returns the ID of the context's tenant's order created with
+	// ref as its idempotency key, or else imported with ref as its legacy
+	// reference. Returns nil if no order has the reference.
+	GetIDByRef(ctx context.Context, ref string) (*uuid.UUID, error)
+
+	// List retrieves orders matching the filter, newest first.
+	List(ctx context.Context, filter model.OrderFilter) ([]model.Order, error)
+
+	// UpdateStatus moves an order from one status to another and records the
+	// change in its status history, failing with model.ErrStatusTransition if
+	// the order is no longer in the from status. A non-nil audit entry, any
+	// webhook deliveries and any outbox events are recorded in the same
+	// transaction.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to model.OrderStatus, audit *model.AuditEntry, webhooks []model.WebhookDelivery, events []model.OutboxEvent) (*model.Order, error)
+
+	// RecordAudit records an audit entry within the provided transaction.
+	RecordAudit(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error
+
+	// Count returns the number of orders matching the filter's source,
+	// product and customer, ignoring its limit and offset.
+	Count(ctx context.Context, filter model.OrderFilter) (int, error)
+
+	// ListStatusChanges retrieves an order's status history, oldest first.
+	ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]model.OrderStatusChange, error)
+
+	// ListAuditEntries retrieves the audit entries recorded against an order,
+	// oldest first.
+	ListAuditEntries(ctx context.Context, orderID uuid.UUID) ([]model.AuditEntry, error)
+
+	// CountBySource returns the number of orders per source channel.
+	// Orders without a source are reported under "unattributed".
+	CountBySource(ctx context.Context) ([]model.SourceCount, error)
+}
+
+// CouponReservationRepository defines the interface for coupon redemption limits.
+type CouponReservationRepository interface {
+	// Reserve claims one redemption of the coupon code within the provided
+	// transaction and records it against the order and caller. The coupon's
+	// limit row stays locked until the transaction ends, so the reservation
+	// is released automatically when the transaction is rolled back. Codes
+	// without a configured limit are always reservable.
+	Reserve(ctx context.Context, tx pgx.Tx, redemption model.CouponRedemption) error
+}
+
+// FlashSaleRepository defines the interface for flash-sale allocations and
+// the quantities orders reserve of them.
+type FlashSaleRepository interface {
+	// List retrieves every flash sale, ordered by product ID.
+	List(ctx context.Context) ([]model.FlashSale, error)
+
+	// GetByProductIDs retrieves the flash sales of the given products.
+	// Products not on a flash sale are left out.
+	GetByProductIDs(ctx context.Context, productIDs []string) ([]model.FlashSale, error)
+
+	// Set allocates quantity of a product to its flash sale, keeping the
+	// quantity already sold. Returns model.ErrProductNotFound if the product
+	// does not exist.
+	Set(ctx context.Context, productID string, quantity int) (*model.FlashSale, error)
+
+	// Delete ends a product's flash sale. Returns false if the product was
+	// not on one.
+	Delete(ctx context.Context, productID string) (bool, error)
+
+	// Reserve claims the reserved quantities of flash-sale products within
+	// the provided transaction, records them against the order and returns
+	// the reservations it claimed. Reservations of other products are
+	// ignored. Returns model.ErrInsufficientStock, claiming nothing, if a
+	// product has too little left. Quantities claimed outside the
+	// transaction are given back by Release if it is rolled back.
+	Reserve(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error)
+
+	// Release gives back quantities Reserve claimed outside a transaction
+	// that was then rolled back. Pass only the reservations Reserve
+	// returned, so nothing it ignored is given back.
+	Release(ctx context.Context, reservations []model.FlashSaleReservation) error
+
+	// RecordPending records reservations whose quantities were claimed
+	// outside the database within the provided transaction, for Reconcile to
+	// count as sold.
+	RecordPending(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) error
+
+	// Reconcile counts up to limit pending reservations, oldest first, as
+	// sold, and returns how many it counted. Concurrent calls count
+	// different reservations.
+	Reconcile(ctx context.Context, limit int) (int, error)
+}
+
+// IdempotencyRepository defines the interface for order creation idempotency keys.
+type IdempotencyRepository interface {
+	// Get retrieves an idempotency key used by caller in the context's
+	// tenant. Returns nil if the key has not been used or has expired.
+	Get(ctx context.Context, caller, key string) (*model.IdempotencyKey, error)
+
+	// Create records an idempotency key for the context's tenant within the
+	// order transaction, so the key is only stored if the order is. Returns
+	// model.ErrIdempotencyConflict if the caller already used the key; a
+	// concurrent insert of the same key blocks until the other transaction
+	// ends.
+	Create(ctx context.Context, tx pgx.Tx, key *model.IdempotencyKey) error
+
+	// DeleteExpired deletes up to limit expired keys of every tenant and
+	// returns how many it deleted.
+	DeleteExpired(ctx context.Context, limit int) (int, error)
+}
+
+// OperationRepository defines the interface for asynchronous order creations.
+type OperationRepository interface {
+	// Create records a new operation for the context's tenant.
+	Create(ctx context.Context, op *model.Operation) error
+
+	// Complete records an operation's terminal status along with the order
+	// it created or the error it failed with.
+	Complete(ctx context.Context, op *model.Operation) error
+
+	// GetByID retrieves an operation by its ID. Returns nil if the operation
+	// does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Operation, error)
+}
+
+// WebhookRepository defines the interface for the webhook delivery outbox.
+type WebhookRepository interface {
+	// Enqueue adds deliveries to the outbox within the provided transaction.
+	Enqueue(ctx context.Context, tx pgx.Tx, deliveries []model.WebhookDelivery) error
+
+	// ClaimDue claims up to limit pending deliveries whose next attempt is
+	// due, counting an attempt for each. Claimed deliveries are not claimed
+	// again until lease has passed.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+
+	// MarkDelivered records that a delivery was accepted by its endpoint.
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+
+	// Reschedule records a failed attempt and schedules the next one after delay.
+	Reschedule(ctx context.Context, id uuid.UUID, delay time.Duration, lastError string) error
+
+	// MarkFailed records a final failed attempt; the delivery is not retried.
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+
+	// ListByOrder retrieves the deliveries announcing changes to an order,
+	// oldest first.
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.WebhookDelivery, error)
+}
+
+// SagaRepository defines the interface for order saga state.
+type SagaRepository interface {
+	// Create records a new running saga, which is compensated unless it is
+	// completed within timeout.
+	Create(ctx context.Context, saga *model.Saga, timeout time.Duration) error
+
+	// Save records a saga's status, steps and last error. Completed sagas
+	// are never changed: reports false if the saga was completed, e.g.
+	// because its order committed although the commit reported an error.
+	Save(ctx context.Context, saga *model.Saga) (bool, error)
+
+	// Complete marks a running saga completed within the provided
+	// transaction, the one storing its order. Reports false if the saga is
+	// no longer running because it timed out and is being compensated.
+	Complete(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error)
+
+	// ClaimDue claims up to limit sagas to compensate: running sagas past
+	// their timeout and compensating sagas whose next attempt is due. Claimed
+	// sagas are marked compensating with an attempt counted, and are not
+	// claimed again until lease has passed.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Saga, error)
+
+	// Reschedule records a failed compensation attempt, with the saga's
+	// steps and last error, and schedules the next one after delay.
+	Reschedule(ctx context.Context, saga *model.Saga, delay time.Duration) error
+}
+
+// EventOutboxRepository defines the interface for the domain event outbox.
+type EventOutboxRepository interface {
+	// Append adds events to the outbox within the provided transaction.
+	Append(ctx context.Context, tx pgx.Tx, events []model.OutboxEvent) error
+
+	// PublishPending passes up to limit unpublished events, in the order
+	// they were appended, to publish and marks them published if it
+	// succeeds. Returns the number of events published.
+	PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error)
+}
+
+// OrderSLARepository defines the interface for tracking how long orders
+// stay in each status.
+type OrderSLARepository interface {
+	// ListInStatus lists up to limit orders that have been in one of the
+	// given statuses for at least the status's minimum age, longest waiting first.
+	ListInStatus(ctx context.Context, minAges map[model.OrderStatus]time.Duration, limit int) ([]model.AtRiskOrder, error)
+
+	// RecordBreaches records up to limit orders that have been in a status
+	// for longer than its SLA and were not recorded before, and returns them.
+	RecordBreaches(ctx context.Context, slas model.OrderSLAs, limit int) ([]model.AtRiskOrder, error)
+}
+
+// CouponDiscountRepository defines the interface for coupon discount rules.
+type CouponDiscountRepository interface {
+	// GetByCode retrieves the discount the context's tenant configured for a
+	// coupon code.
+	// Returns nil if the code grants no discount.
+	GetByCode(ctx context.Context, code string) (*model.CouponDiscount, error)
+}
+
+// PriceChangeRepository defines the interface for product price change records.
+type PriceChangeRepository interface {
+	// Create records a price change. Changes with status applied update the
+	// product price in the same transaction. Returns model.ErrPriceChangePending
+	// if a pending change already exists for the product.
+	Create(ctx context.Context, change *model.PriceChange) error
+
+	// GetByID retrieves a price change by its ID.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PriceChange, error)
+
+	// ListPending retrieves price changes awaiting approval, oldest first.
+	ListPending(ctx context.Context) ([]model.PriceChange, error)
+
+	// Decide approves or rejects a pending price change. Approving applies the
+	// new price to the product in the same transaction.
+	Decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error)
+}
+
+// ShipmentRepository defines the interface for order shipments and fulfillment events.
+type ShipmentRepository interface {
+	// Create records a shipment and its items, adds the shipped quantities to
+	// the order items and records a shipment event carrying the order's
+	// resulting fulfillment status, all in one transaction. Returns
+	// model.ErrOverFulfillment if an item would be shipped beyond its ordered
+	// quantity and model.ErrOrderItemNotFound if an item is not part of the order.
+	Create(ctx context.Context, shipment *model.Shipment) (*model.OrderEvent, error)
+
+	// UpdateTracking sets the tracking number, and the carrier unless it is
+	// nil, of an order's shipment and records a tracking event carrying the
+	// order's fulfillment status, in one transaction. Returns
+	// model.ErrShipmentNotFound if the order has no such shipment.
+	UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, tracking model.TrackingRequest) (*model.OrderEvent, error)
+
+	// ListByOrder retrieves an order's shipments with their items, oldest first.
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error)
+
+	// ListEvents retrieves an order's fulfillment events, oldest first.
+	ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error)
+}
+
+// OrderPricingRepository defines the interface for versioned order pricing.
+type OrderPricingRepository interface {
+	// Latest retrieves an order's latest recorded pricing version. Returns nil
+	// if the order has never been repriced.
+	Latest(ctx context.Context, orderID uuid.UUID) (*model.OrderPricingVersion, error)
+
+	// ListByOrder retrieves an order's recorded pricing versions, oldest first.
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderPricingVersion, error)
+
+	// Create records a pricing version and updates the order's totals to
+	// match, in one transaction. Before an order's first recalculation its
+	// original totals are recorded as version 1. Returns model.ErrOrderNotFound
+	// if the order does not exist, model.ErrOrderCancelled if it was
+	// cancelled, model.ErrFulfillmentStarted if any of it has shipped and
+	// model.ErrPricingConflict if the version does not follow the latest.
+	Create(ctx context.Context, version *model.OrderPricingVersion) error
+}
+
+// OrderNoteRepository defines the interface for internal order notes.
+type OrderNoteRepository interface {
+	// Create records a note, setting its creation time.
+	Create(ctx context.Context, note *model.OrderNote) error
+
+	// ListByOrder retrieves an order's notes, oldest first.
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderNote, error)
+}
+
+// CouponCodeRepository defines the interface for coupon bases stored in the database.
+type CouponCodeRepository interface {
+	// StreamCodes calls fn for every code in the named coupon set. Iteration
+	// stops at the first error returned by fn.
+	StreamCodes(ctx context.Context, set string, fn func(code string) error) error
+
+	// ReplaceSet atomically replaces the named coupon set with the codes read
+	// from next, which returns false once exhausted. Duplicate codes are
+	// stored once. Returns the number of distinct codes stored.
+	ReplaceSet(ctx context.Context, set string, next func() (string, bool, error)) (int64, error)
+}
+
+// OrderExportRepository defines read access for incremental order exports.
+type OrderExportRepository interface {
+	// ListUpdated retrieves up to limit orders with their items, updated after
+	// the cursor and before until, ordered by update time then ID.
+	ListUpdated(ctx context.Context, after model.ExportCursor, until time.Time, limit int) ([]model.ExportedOrder, error)
+
+	// GetWatermark retrieves the named export's watermark. Returns nil if the
+	// export has never completed.
+	GetWatermark(ctx context.Context, name string) (*time.Time, error)
+
+	// SetWatermark records the named export's watermark.
+	SetWatermark(ctx context.Context, name string, watermark time.Time) error
+}
+
+// CouponFileRepository defines the interface for the coupon file registry:
+// the uploads of coupon files and the generations of the validator that
+// loaded them.
+type CouponFileRepository interface {
+	// Register records an uploaded coupon file, setting its ID and
+	// registration time. Returns model.ErrCouponFileExists if a file with
+	// the same name and checksum is already registered.
+	Register(ctx context.Context, file *model.CouponFile) error
+
+	// List retrieves registered coupon files, newest upload first, limited
+	// to the named coupon set unless name is empty.
+	List(ctx context.Context, name string) ([]model.CouponFile, error)
+
+	// RecordGeneration records a load of the coupon files, setting its ID,
+	// and retires the generation the same instance loaded before it.
+	RecordGeneration(ctx context.Context, generation *model.CouponGeneration) error
+
+	// RetireGeneration records that a generation stopped serving validations.
+	// Generations already retired are left unchanged.
+	RetireGeneration(ctx context.Context, id int64, retiredAt time.Time) error
+
+	// ListGenerations retrieves the generations serving validations at any
+	// time from from to to, inclusive, newest first, with their files and
+	// the registered uploads matching them.
+	ListGenerations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error)
+}
+
+// TenantRepository defines the interface for managing tenants.
+type TenantRepository interface {
+	// List retrieves every tenant, ordered by ID.
+	List(ctx context.Context) ([]model.Tenant, error)
+
+	// GetByID retrieves a tenant. Returns nil if the tenant does not exist.
+	GetByID(ctx context.Context, id string) (*model.Tenant, error)
+
+	// Create records a new tenant, setting its creation time. Returns
+	// model.ErrTenantExists if the ID is taken.
+	Create(ctx context.Context, tenant *model.Tenant) error
+
+	// UpdateName renames a tenant. Returns nil if the tenant does not exist.
+	UpdateName(ctx context.Context, id, name string) (*model.Tenant, error)
+
+	// Ensure creates the given tenants, named after their IDs, unless they
+	// already exist.
+	Ensure(ctx context.Context, ids []string) error
+}
+
+// CustomerRepository defines the interface for managing customers. Customers
+// are scoped to the context's tenant like products.
+type CustomerRepository interface {
+	// Create records a new customer, setting its creation time. Returns
+	// model.ErrCustomerExists if the email is already registered.
+	Create(ctx context.Context, customer *model.Customer) error
+
+	// GetByID retrieves a customer. Returns nil if the customer does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error)
+
+	// GetByEmail retrieves the customer registered with a normalized email
+	// address. Returns nil if no customer has the address.
+	GetByEmail(ctx context.Context, email string) (*model.Customer, error)
+
+	// HasOrders reports whether the customer has placed an order that was
+	// not cancelled.
+	HasOrders(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// ProductImageRepository defines the interface for product image data access.
+type ProductImageRepository interface {
+	// Create attaches an image after the product's last image, setting its
+	// position and creation time. Returns model.ErrImageAttached if the key
+	// is already attached and model.ErrTooManyImages if the product has
+	// model.MaxProductImages images.
+	Create(ctx context.Context, image *model.ProductImage) error
+
+	// ListByProducts retrieves the images of each product, in position order.
+	ListByProducts(ctx context.Context, productIDs []string) (map[string][]model.ProductImage, error)
+
+	// Reorder sets the positions of a product's images to their order in
+	// ids and returns the images in that order. Returns
+	// model.ErrInvalidImageOrder unless ids lists each image exactly once.
+	Reorder(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error)
+
+	// Delete detaches an image from a product, closing the gap in positions,
+	// and returns it. Returns model.ErrImageNotFound if the product has no
+	// image with the ID.
+	Delete(ctx context.Context, productID string, id uuid.UUID) (*model.ProductImage, error)
+}
+
+// UpsellRepository defines the interface for recommending products after an
+// order.
+type UpsellRepository interface {
+	// ListUpsell lists the best selling active products in the order's
+	// categories that the order does not contain, within the limits, most
+	// units sold first. Returns model.ErrOrderNotFound if the context's
+	// tenant has no order with the ID.
+	ListUpsell(ctx context.Context, orderID uuid.UUID, limits model.UpsellLimits) ([]model.UpsellProduct, error)
 }
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// sagaRepository implements SagaRepository using PostgreSQL.
+type sagaRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewSagaRepository creates a new PostgreSQL-backed order saga repository.
+func NewSagaRepository(pool *pgxpool.Pool, logger zerolog.Logger) SagaRepository {
+	return &sagaRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "saga").Logger(),
+	}
+}
+
+// Create records a new running saga. Its next attempt is its timeout, after
+// which the recovery loop compensates it unless it was completed.
+func (r *sagaRepository) Create(ctx context.Context, saga *model.Saga, timeout time.Duration) error {
+	query := `
+		INSERT INTO order_sagas (id, order_id, status, payload, steps, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, NOW() + $6 * INTERVAL '1 millisecond')
+		RETURNING created_at, updated_at
+	`
+
+	steps := saga.Steps
+	if steps == nil {
+		steps = []model.SagaStep{}
+	}
+
+	err := r.pool.QueryRow(ctx, query, saga.ID, saga.OrderID, saga.Status, saga.Payload, steps, timeout.Milliseconds()).
+		Scan(&saga.CreatedAt, &saga.UpdatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("saga_id", saga.ID.String()).Msg("failed to create saga")
+		return fmt.Errorf("failed to create saga: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// Save records a saga's status, steps and last error unless the saga was
+// completed.
+func (r *sagaRepository) Save(ctx context.Context, saga *model.Saga) (bool, error) {
+	query := `
+		UPDATE order_sagas
+		SET status = $2, steps = $3, last_error = $4, updated_at = NOW()
+		WHERE id = $1 AND status <> 'completed'
+		RETURNING updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, saga.ID, saga.Status, saga.Steps, saga.LastError).Scan(&saga.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error().Err(err).Str("saga_id", saga.ID.String()).Msg("failed to save saga")
+		return false, fmt.Errorf("failed to save saga: %w", Classify(err))
+	}
+
+	return true, nil
+}
+
+// Complete marks a running saga completed within the order's transaction.
+// The row stays locked until the transaction ends, so the recovery loop
+// cannot claim the saga while its order is being committed; if the loop
+// claimed it first, no row is updated and the order must not be stored.
+func (r *sagaRepository) Complete(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE order_sagas
+		SET status = 'completed', updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`
+
+	tag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error().Err(err).Str("saga_id", id.String()).Msg("failed to complete saga")
+		return false, fmt.Errorf("failed to complete saga: %w", Classify(err))
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ClaimDue claims up to limit sagas due for compensation, oldest first.
+// Claiming counts an attempt and moves the next attempt lease into the
+// future, so other instances skip the sagas meanwhile and they are retried
+// if this one stops before recording the outcome.
+func (r *sagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Saga, error) {
+	query := `
+		UPDATE order_sagas
+		SET status = 'compensating', attempts = attempts + 1,
+			next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM order_sagas
+			WHERE status IN ('running', 'compensating') AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, order_id, status, payload, steps, attempts, last_error, created_at, updated_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to claim sagas")
+		return nil, fmt.Errorf("failed to claim sagas: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	sagas := []model.Saga{}
+	for rows.Next() {
+		var s model.Saga
+		err := rows.Scan(
+			&s.ID,
+			&s.OrderID,
+			&s.Status,
+			&s.Payload,
+			&s.Steps,
+			&s.Attempts,
+			&s.LastError,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan saga row")
+			return nil, fmt.Errorf("failed to scan saga: %w", Classify(err))
+		}
+		sagas = append(sagas, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating saga rows")
+		return nil, fmt.Errorf("error iterating sagas: %w", Classify(err))
+	}
+
+	return sagas, nil
+}
+
+// Reschedule records a failed compensation attempt and schedules the next
+// one after delay.
+func (r *sagaRepository) Reschedule(ctx context.Context, saga *model.Saga, delay time.Duration) error {
+	query := `
+		UPDATE order_sagas
+		SET status = 'compensating', steps = $2, last_error = $3,
+			next_attempt_at = NOW() + $4 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, saga.ID, saga.Steps, saga.LastError, delay.Milliseconds()).Scan(&saga.UpdatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("saga_id", saga.ID.String()).Msg("failed to reschedule saga")
+		return fmt.Errorf("failed to reschedule saga: %w", Classify(err))
+	}
+	saga.Status = model.SagaCompensating
+
+	return nil
+}
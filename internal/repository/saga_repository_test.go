@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSagaSchema creates the order_sagas table for testing.
+func createSagaSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS order_sagas (
+			id UUID PRIMARY KEY,
+			order_id UUID NOT NULL,
+			status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'compensating', 'compensated', 'failed')),
+			payload JSONB NOT NULL,
+			steps JSONB NOT NULL DEFAULT '[]',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL,
+			last_error TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestSagaRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createSagaSchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewSagaRepository(pool, logger)
+	ctx := context.Background()
+
+	newSaga := func() *model.Saga {
+		return &model.Saga{
+			ID:      uuid.New(),
+			OrderID: uuid.New(),
+			Status:  model.SagaRunning,
+			Payload: json.RawMessage(`{"items":[]}`),
+		}
+	}
+
+	// claimAll claims every due saga.
+	claimAll := func(t *testing.T, lease time.Duration) []model.Saga {
+		sagas, err := repo.ClaimDue(ctx, 100, lease)
+		require.NoError(t, err)
+		return sagas
+	}
+
+	t.Run("Completed sagas are not claimed", func(t *testing.T) {
+		saga := newSaga()
+		require.NoError(t, repo.Create(ctx, saga, 0))
+
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		completed, err := repo.Complete(ctx, tx, saga.ID)
+		require.NoError(t, err)
+		assert.True(t, completed)
+
+		// The saga stays locked by the order's transaction until it commits
+		assert.Empty(t, claimAll(t, time.Minute))
+		require.NoError(t, tx.Commit(ctx))
+
+		assert.Empty(t, claimAll(t, time.Minute))
+
+		// Completed sagas are never compensated
+		saga.Status = model.SagaCompensating
+		saved, err := repo.Save(ctx, saga)
+		require.NoError(t, err)
+		assert.False(t, saved)
+	})
+
+	t.Run("Running sagas are claimed once they time out", func(t *testing.T) {
+		saga := newSaga()
+		require.NoError(t, repo.Create(ctx, saga, time.Hour))
+		timedOut := newSaga()
+		require.NoError(t, repo.Create(ctx, timedOut, 0))
+
+		claimed := claimAll(t, time.Minute)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, timedOut.ID, claimed[0].ID)
+		assert.Equal(t, model.SagaCompensating, claimed[0].Status)
+		assert.Equal(t, 1, claimed[0].Attempts)
+		assert.JSONEq(t, `{"items":[]}`, string(claimed[0].Payload))
+
+		// A claimed saga can no longer be completed
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		completed, err := repo.Complete(ctx, tx, timedOut.ID)
+		require.NoError(t, err)
+		assert.False(t, completed)
+		require.NoError(t, tx.Rollback(ctx))
+
+		// Leased sagas are skipped
+		assert.Empty(t, claimAll(t, time.Minute))
+	})
+
+	t.Run("Steps are saved and compensation rescheduled", func(t *testing.T) {
+		saga := newSaga()
+		require.NoError(t, repo.Create(ctx, saga, 0))
+
+		saga.Steps = []model.SagaStep{
+			{Name: "payment", Status: model.SagaStepCompensated, Result: json.RawMessage(`{"id":"auth_1"}`)},
+			{Name: "stock", Status: model.SagaStepCompleted},
+		}
+		lastError := "stock: status 503"
+		saga.LastError = &lastError
+		require.NoError(t, repo.Reschedule(ctx, saga, 0))
+
+		claimed := claimAll(t, time.Minute)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, saga.Steps[0].Name, claimed[0].Steps[0].Name)
+		assert.Equal(t, model.SagaStepCompensated, claimed[0].Steps[0].Status)
+		assert.JSONEq(t, `{"id":"auth_1"}`, string(claimed[0].Steps[0].Result))
+		assert.Equal(t, model.SagaStepCompleted, claimed[0].Steps[1].Status)
+		require.NotNil(t, claimed[0].LastError)
+		assert.Equal(t, lastError, *claimed[0].LastError)
+
+		saga.Status = model.SagaCompensated
+		saga.LastError = nil
+		saved, err := repo.Save(ctx, saga)
+		require.NoError(t, err)
+		assert.True(t, saved)
+
+		var status string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM order_sagas WHERE id = $1`, saga.ID).Scan(&status))
+		assert.Equal(t, string(model.SagaCompensated), status)
+		assert.Empty(t, claimAll(t, 0))
+	})
+}
@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// shipmentRepository implements ShipmentRepository using PostgreSQL.
+type shipmentRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewShipmentRepository creates a new PostgreSQL-backed shipment repository.
+func NewShipmentRepository(pool *pgxpool.Pool, logger zerolog.Logger) ShipmentRepository {
+	return &shipmentRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "shipment").Logger(),
+	}
+}
+
+// Create records a shipment, fulfills its items and records a shipment event.
+func (r *shipmentRepository) Create(ctx context.Context, shipment *model.Shipment) (*model.OrderEvent, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO shipments (id, order_id, carrier, tracking_number)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		shipment.ID,
+		shipment.OrderID,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+	).Scan(&shipment.CreatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", shipment.OrderID.String()).Msg("failed to create shipment")
+		return nil, fmt.Errorf("failed to create shipment: %w", Classify(err))
+	}
+
+	for _, item := range shipment.Items {
+		if err := r.fulfillItem(ctx, tx, shipment, item); err != nil {
+			return nil, err
+		}
+	}
+
+	// Touch the order so incremental exports pick up the fulfilled quantities
+	if _, err := tx.Exec(ctx, `UPDATE orders SET updated_at = NOW() WHERE id = $1`, shipment.OrderID); err != nil {
+		r.logger.Error().Err(err).Str("order_id", shipment.OrderID.String()).Msg("failed to update order")
+		return nil, fmt.Errorf("failed to update order: %w", Classify(err))
+	}
+
+	status, err := r.fulfillmentStatus(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.OrderEvent{
+		OrderID:           shipment.OrderID,
+		ShipmentID:        &shipment.ID,
+		Type:              model.OrderEventShipmentCreated,
+		FulfillmentStatus: status,
+	}
+	if err := r.recordEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit shipment")
+		return nil, fmt.Errorf("failed to commit shipment: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("shipment_id", shipment.ID.String()).
+		Str("order_id", shipment.OrderID.String()).
+		Str("fulfillment_status", string(status)).
+		Msg("shipment created")
+
+	return event, nil
+}
+
+// UpdateTracking sets a shipment's tracking number and records a tracking event.
+func (r *shipmentRepository) UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, tracking model.TrackingRequest) (*model.OrderEvent, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", Classify(err))
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE shipments
+		SET tracking_number = $3, carrier = COALESCE($4, carrier)
+		WHERE id = $1 AND order_id = $2
+	`
+
+	tag, err := tx.Exec(ctx, query, shipmentID, orderID, tracking.TrackingNumber, tracking.Carrier)
+	if err != nil {
+		r.logger.Error().Err(err).Str("shipment_id", shipmentID.String()).Msg("failed to update shipment tracking")
+		return nil, fmt.Errorf("failed to update shipment tracking: %w", Classify(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, model.ErrShipmentNotFound
+	}
+
+	status, err := r.fulfillmentStatus(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.OrderEvent{
+		OrderID:           orderID,
+		ShipmentID:        &shipmentID,
+		Type:              model.OrderEventTrackingUpdated,
+		FulfillmentStatus: status,
+	}
+	if err := r.recordEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error().Err(err).Msg("failed to commit shipment tracking")
+		return nil, fmt.Errorf("failed to commit shipment tracking: %w", Classify(err))
+	}
+
+	r.logger.Debug().
+		Str("shipment_id", shipmentID.String()).
+		Str("order_id", orderID.String()).
+		Msg("shipment tracking updated")
+
+	return event, nil
+}
+
+// recordEvent records a fulfillment event within the transaction, setting
+// its ID and creation time.
+func (r *shipmentRepository) recordEvent(ctx context.Context, tx pgx.Tx, event *model.OrderEvent) error {
+	query := `
+		INSERT INTO order_events (order_id, shipment_id, type, fulfillment_status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		event.OrderID,
+		event.ShipmentID,
+		event.Type,
+		string(event.FulfillmentStatus),
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", event.OrderID.String()).Str("type", event.Type).Msg("failed to record shipment event")
+		return fmt.Errorf("failed to record shipment event: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// fulfillItem adds a shipment item and its quantity to the order item's
+// fulfilled quantity. The guarded update locks the order item row, so
+// concurrent shipments cannot ship more than was ordered.
+func (r *shipmentRepository) fulfillItem(ctx context.Context, tx pgx.Tx, shipment *model.Shipment, item model.ShipmentItem) error {
+	updateQuery := `
+		UPDATE order_items
+		SET fulfilled_quantity = fulfilled_quantity + $1
+		WHERE id = $2 AND order_id = $3 AND fulfilled_quantity + $1 <= quantity
+	`
+
+	tag, err := tx.Exec(ctx, updateQuery, item.Quantity, item.OrderItemID, shipment.OrderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_item_id", item.OrderItemID.String()).Msg("failed to fulfill order item")
+		return fmt.Errorf("failed to fulfill order item: %w", Classify(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		existsQuery := `SELECT EXISTS(SELECT 1 FROM order_items WHERE id = $1 AND order_id = $2)`
+		if err := tx.QueryRow(ctx, existsQuery, item.OrderItemID, shipment.OrderID).Scan(&exists); err != nil {
+			r.logger.Error().Err(err).Str("order_item_id", item.OrderItemID.String()).Msg("failed to query order item")
+			return fmt.Errorf("failed to query order item: %w", Classify(err))
+		}
+		if !exists {
+			return model.ErrOrderItemNotFound
+		}
+		return model.ErrOverFulfillment
+	}
+
+	insertQuery := `
+		INSERT INTO shipment_items (shipment_id, order_item_id, quantity)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := tx.Exec(ctx, insertQuery, shipment.ID, item.OrderItemID, item.Quantity); err != nil {
+		r.logger.Error().Err(err).Str("order_item_id", item.OrderItemID.String()).Msg("failed to create shipment item")
+		return fmt.Errorf("failed to create shipment item: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// fulfillmentStatus derives an order's fulfillment status within the transaction.
+func (r *shipmentRepository) fulfillmentStatus(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) (model.FulfillmentStatus, error) {
+	query := `SELECT quantity, fulfilled_quantity FROM order_items WHERE order_id = $1`
+
+	rows, err := tx.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order items")
+		return "", fmt.Errorf("failed to query order items: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var items []model.OrderItem
+	for rows.Next() {
+		var item model.OrderItem
+		if err := rows.Scan(&item.Quantity, &item.FulfilledQuantity); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order item row")
+			return "", fmt.Errorf("failed to scan order item: %w", Classify(err))
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order item rows")
+		return "", fmt.Errorf("error iterating order items: %w", Classify(err))
+	}
+
+	return model.DeriveFulfillmentStatus(items), nil
+}
+
+// ListByOrder retrieves an order's shipments with their items, oldest first.
+func (r *shipmentRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error) {
+	query := `
+		SELECT id, order_id, carrier, tracking_number, created_at
+		FROM shipments
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query shipments")
+		return nil, fmt.Errorf("failed to query shipments: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	shipments := []model.Shipment{}
+	indexByID := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var s model.Shipment
+		if err := rows.Scan(&s.ID, &s.OrderID, &s.Carrier, &s.TrackingNumber, &s.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan shipment row")
+			return nil, fmt.Errorf("failed to scan shipment: %w", Classify(err))
+		}
+		s.Items = []model.ShipmentItem{}
+		indexByID[s.ID] = len(shipments)
+		shipments = append(shipments, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating shipment rows")
+		return nil, fmt.Errorf("error iterating shipments: %w", Classify(err))
+	}
+
+	if len(shipments) == 0 {
+		return shipments, nil
+	}
+
+	itemsQuery := `
+		SELECT si.shipment_id, si.order_item_id, si.quantity
+		FROM shipment_items si
+		JOIN shipments s ON s.id = si.shipment_id
+		WHERE s.order_id = $1
+		ORDER BY si.order_item_id
+	`
+
+	itemRows, err := r.pool.Query(ctx, itemsQuery, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query shipment items")
+		return nil, fmt.Errorf("failed to query shipment items: %w", Classify(err))
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var shipmentID uuid.UUID
+		var item model.ShipmentItem
+		if err := itemRows.Scan(&shipmentID, &item.OrderItemID, &item.Quantity); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan shipment item row")
+			return nil, fmt.Errorf("failed to scan shipment item: %w", Classify(err))
+		}
+		if i, ok := indexByID[shipmentID]; ok {
+			shipments[i].Items = append(shipments[i].Items, item)
+		}
+	}
+
+	if err := itemRows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating shipment item rows")
+		return nil, fmt.Errorf("error iterating shipment items: %w", Classify(err))
+	}
+
+	return shipments, nil
+}
+
+// ListEvents retrieves an order's fulfillment events, oldest first.
+func (r *shipmentRepository) ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error) {
+	query := `
+		SELECT id, order_id, shipment_id, type, fulfillment_status, created_at
+		FROM order_events
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query order events")
+		return nil, fmt.Errorf("failed to query order events: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	events := []model.OrderEvent{}
+	for rows.Next() {
+		var e model.OrderEvent
+		var status string
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.ShipmentID, &e.Type, &status, &e.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan order event row")
+			return nil, fmt.Errorf("failed to scan order event: %w", Classify(err))
+		}
+		e.FulfillmentStatus = model.FulfillmentStatus(status)
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating order event rows")
+		return nil, fmt.Errorf("error iterating order events: %w", Classify(err))
+	}
+
+	return events, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createShipmentSchema creates the shipment tables for testing.
+func createShipmentSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS shipments (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			carrier TEXT,
+			tracking_number TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS shipment_items (
+			shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+			order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			PRIMARY KEY (shipment_id, order_item_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS order_events (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			shipment_id UUID REFERENCES shipments(id) ON DELETE SET NULL,
+			type TEXT NOT NULL,
+			fulfillment_status TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestShipmentRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createShipmentSchema(t, pool)
+
+	now := time.Now()
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Product A", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: now},
+		{ID: "P002", Name: "Product B", Price: model.NewMoney(20.00), Category: "Cat2", CreatedAt: now},
+	})
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewShipmentRepository(pool, logger)
+	ctx := context.Background()
+
+	// Create an order with two items
+	order := &model.Order{ID: uuid.New(), CreatedAt: now, UpdatedAt: now}
+	items := []model.OrderItem{
+		{ID: uuid.New(), OrderID: order.ID, ProductID: "P001", Quantity: 2},
+		{ID: uuid.New(), OrderID: order.ID, ProductID: "P002", Quantity: 1},
+	}
+
+	tx, err := orderRepo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, orderRepo.CreateOrder(ctx, tx, order))
+	require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, items))
+	require.NoError(t, tx.Commit(ctx))
+
+	carrier := "AusPost"
+
+	t.Run("First shipment partially fulfills the order", func(t *testing.T) {
+		shipment := &model.Shipment{
+			ID:      uuid.New(),
+			OrderID: order.ID,
+			Carrier: &carrier,
+			Items:   []model.ShipmentItem{{OrderItemID: items[0].ID, Quantity: 1}},
+		}
+
+		event, err := repo.Create(ctx, shipment)
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderEventShipmentCreated, event.Type)
+		assert.Equal(t, model.FulfillmentStatusPartial, event.FulfillmentStatus)
+		assert.Equal(t, shipment.ID, *event.ShipmentID)
+	})
+
+	t.Run("Shipping beyond the ordered quantity is rejected", func(t *testing.T) {
+		shipment := &model.Shipment{
+			ID:      uuid.New(),
+			OrderID: order.ID,
+			Items:   []model.ShipmentItem{{OrderItemID: items[0].ID, Quantity: 2}},
+		}
+
+		_, err := repo.Create(ctx, shipment)
+		assert.Equal(t, model.ErrOverFulfillment, err)
+	})
+
+	t.Run("Items from another order are rejected", func(t *testing.T) {
+		shipment := &model.Shipment{
+			ID:      uuid.New(),
+			OrderID: order.ID,
+			Items:   []model.ShipmentItem{{OrderItemID: uuid.New(), Quantity: 1}},
+		}
+
+		_, err := repo.Create(ctx, shipment)
+		assert.Equal(t, model.ErrOrderItemNotFound, err)
+	})
+
+	t.Run("Second shipment fulfills the order", func(t *testing.T) {
+		shipment := &model.Shipment{
+			ID:      uuid.New(),
+			OrderID: order.ID,
+			Items: []model.ShipmentItem{
+				{OrderItemID: items[0].ID, Quantity: 1},
+				{OrderItemID: items[1].ID, Quantity: 1},
+			},
+		}
+
+		event, err := repo.Create(ctx, shipment)
+		require.NoError(t, err)
+		assert.Equal(t, model.FulfillmentStatusFulfilled, event.FulfillmentStatus)
+
+		_, retrieved, err := orderRepo.GetByID(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.FulfillmentStatusFulfilled, model.DeriveFulfillmentStatus(retrieved))
+	})
+
+	t.Run("Shipments and events are listed oldest first", func(t *testing.T) {
+		shipments, err := repo.ListByOrder(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, shipments, 2)
+		assert.Equal(t, &carrier, shipments[0].Carrier)
+		assert.Len(t, shipments[0].Items, 1)
+		assert.Len(t, shipments[1].Items, 2)
+
+		events, err := repo.ListEvents(ctx, order.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.FulfillmentStatusPartial, events[0].FulfillmentStatus)
+		assert.Equal(t, model.FulfillmentStatusFulfilled, events[1].FulfillmentStatus)
+	})
+
+	t.Run("Tracking is attached to a shipment", func(t *testing.T) {
+		shipments, err := repo.ListByOrder(ctx, order.ID)
+		require.NoError(t, err)
+		first := shipments[0]
+
+		event, err := repo.UpdateTracking(ctx, order.ID, first.ID, model.TrackingRequest{TrackingNumber: "AP123456"})
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderEventTrackingUpdated, event.Type)
+		assert.Equal(t, model.FulfillmentStatusFulfilled, event.FulfillmentStatus)
+
+		shipments, err = repo.ListByOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "AP123456", *shipments[0].TrackingNumber)
+		assert.Equal(t, &carrier, shipments[0].Carrier, "a nil carrier keeps the shipment's carrier")
+	})
+
+	t.Run("Tracking another order's shipment is rejected", func(t *testing.T) {
+		shipments, err := repo.ListByOrder(ctx, order.ID)
+		require.NoError(t, err)
+
+		_, err = repo.UpdateTracking(ctx, uuid.New(), shipments[0].ID, model.TrackingRequest{TrackingNumber: "AP123456"})
+		assert.Equal(t, model.ErrShipmentNotFound, err)
+	})
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"mini-kart/internal/model"
+)
+
+// tenantScope returns the tenant queries for ctx are restricted to, or nil
+// for background work not scoped to a tenant, which sees every tenant. Queries
+// compare it with ($n::text IS NULL OR tenant_id = $n).
+func tenantScope(ctx context.Context) *string {
+	tenantID, ok := model.TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &tenantID
+}
+
+// tenantOf returns the tenant rows created for ctx belong to: the context's
+// tenant, or the default tenant for background work and tools.
+func tenantOf(ctx context.Context) string {
+	if tenantID, ok := model.TenantFromContext(ctx); ok {
+		return tenantID
+	}
+	return model.DefaultTenant
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// tenantRepository implements TenantRepository using PostgreSQL.
+type tenantRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewTenantRepository creates a new PostgreSQL-backed tenant repository.
+func NewTenantRepository(pool *pgxpool.Pool, logger zerolog.Logger) TenantRepository {
+	return &tenantRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "tenant").Logger(),
+	}
+}
+
+// List retrieves every tenant, ordered by ID.
+func (r *tenantRepository) List(ctx context.Context) ([]model.Tenant, error) {
+	query := `
+		SELECT id, name, created_at
+		FROM tenants
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to query tenants")
+		return nil, fmt.Errorf("failed to query tenants: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	tenants := []model.Tenant{}
+	for rows.Next() {
+		var t model.Tenant
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan tenant row")
+			return nil, fmt.Errorf("failed to scan tenant: %w", Classify(err))
+		}
+		tenants = append(tenants, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating tenant rows")
+		return nil, fmt.Errorf("error iterating tenants: %w", Classify(err))
+	}
+
+	return tenants, nil
+}
+
+// GetByID retrieves a tenant. Returns nil if the tenant does not exist.
+func (r *tenantRepository) GetByID(ctx context.Context, id string) (*model.Tenant, error) {
+	query := `
+		SELECT id, name, created_at
+		FROM tenants
+		WHERE id = $1
+	`
+
+	var t model.Tenant
+	err := r.pool.QueryRow(ctx, query, id).Scan(&t.ID, &t.Name, &t.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("tenant_id", id).Msg("failed to query tenant")
+		return nil, fmt.Errorf("failed to query tenant: %w", Classify(err))
+	}
+
+	return &t, nil
+}
+
+// Create records a new tenant, setting its creation time. Returns
+// model.ErrTenantExists if the ID is taken.
+func (r *tenantRepository) Create(ctx context.Context, tenant *model.Tenant) error {
+	query := `
+		INSERT INTO tenants (id, name)
+		VALUES ($1, $2)
+		RETURNING created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, tenant.ID, tenant.Name).Scan(&tenant.CreatedAt)
+	if err != nil {
+		if errors.Is(Classify(err), ErrUniqueViolation) {
+			r.logger.Warn().Str("tenant_id", tenant.ID).Msg("tenant already exists")
+			return model.ErrTenantExists
+		}
+		r.logger.Error().Err(err).Str("tenant_id", tenant.ID).Msg("failed to create tenant")
+		return fmt.Errorf("failed to create tenant: %w", Classify(err))
+	}
+
+	r.logger.Info().Str("tenant_id", tenant.ID).Msg("tenant created")
+
+	return nil
+}
+
+// UpdateName renames a tenant. Returns nil if the tenant does not exist.
+func (r *tenantRepository) UpdateName(ctx context.Context, id, name string) (*model.Tenant, error) {
+	query := `
+		UPDATE tenants
+		SET name = $2
+		WHERE id = $1
+		RETURNING id, name, created_at
+	`
+
+	var t model.Tenant
+	err := r.pool.QueryRow(ctx, query, id, name).Scan(&t.ID, &t.Name, &t.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error().Err(err).Str("tenant_id", id).Msg("failed to rename tenant")
+		return nil, fmt.Errorf("failed to rename tenant: %w", Classify(err))
+	}
+
+	return &t, nil
+}
+
+// Ensure creates the given tenants, named after their IDs, unless they
+// already exist.
+func (r *tenantRepository) Ensure(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tenants (id, name)
+		SELECT id, id FROM unnest($1::text[]) AS t(id)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, ids)
+	if err != nil {
+		r.logger.Error().Err(err).Strs("tenant_ids", ids).Msg("failed to ensure tenants")
+		return fmt.Errorf("failed to ensure tenants: %w", Classify(err))
+	}
+
+	if tag.RowsAffected() > 0 {
+		r.logger.Info().Int64("created", tag.RowsAffected()).Msg("configured tenants created")
+	}
+
+	return nil
+}
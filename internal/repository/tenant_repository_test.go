@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTenantSchema creates the tenants table for testing.
+func createTenantSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS tenants (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestTenantRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+	createTenantSchema(t, pool)
+
+	logger := zerolog.Nop()
+	repo := NewTenantRepository(pool, logger)
+	ctx := context.Background()
+
+	acme := &model.Tenant{ID: "acme", Name: "Acme"}
+	require.NoError(t, repo.Create(ctx, acme))
+	assert.False(t, acme.CreatedAt.IsZero())
+
+	assert.Equal(t, model.ErrTenantExists, repo.Create(ctx, &model.Tenant{ID: "acme", Name: "Acme Again"}))
+
+	// Ensure keeps existing tenants' names
+	require.NoError(t, repo.Ensure(ctx, []string{"acme", "globex"}))
+
+	tenants, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, tenants, 2)
+	assert.Equal(t, "Acme", tenants[0].Name)
+	assert.Equal(t, "globex", tenants[1].Name)
+
+	renamed, err := repo.UpdateName(ctx, "globex", "Globex Corporation")
+	require.NoError(t, err)
+	assert.Equal(t, "Globex Corporation", renamed.Name)
+
+	tenant, err := repo.GetByID(ctx, "globex")
+	require.NoError(t, err)
+	assert.Equal(t, "Globex Corporation", tenant.Name)
+
+	missing, err := repo.GetByID(ctx, "initech")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	missing, err = repo.UpdateName(ctx, "initech", "Initech")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// upsellRepository implements UpsellRepository using PostgreSQL.
+type upsellRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewUpsellRepository creates a new PostgreSQL-backed upsell repository.
+func NewUpsellRepository(pool *pgxpool.Pool, logger zerolog.Logger) UpsellRepository {
+	return &upsellRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "upsell").Logger(),
+	}
+}
+
+// ListUpsell lists the best selling products in the order's categories that
+// the order does not contain. Units are summed per candidate product through
+// the order_items (product_id, order_id) index, so only the order's
+// categories are counted rather than every sale.
+func (r *upsellRepository) ListUpsell(ctx context.Context, orderID uuid.UUID, limits model.UpsellLimits) ([]model.UpsellProduct, error) {
+	query := fmt.Sprintf(`
+		WITH ordered AS (
+			SELECT DISTINCT oi.product_id, p.category
+			FROM orders o
+			JOIN order_items oi ON oi.order_id = o.id
+			JOIN products p ON p.id = oi.product_id
+			WHERE o.id = $1
+			  AND ($5::text IS NULL OR o.tenant_id = $5)
+		), ranked AS (
+			SELECT p.*, s.units,
+				ROW_NUMBER() OVER (PARTITION BY p.category ORDER BY s.units DESC, p.id) AS category_rank
+			FROM products p
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(SUM(oi.quantity), 0)::BIGINT AS units
+				FROM order_items oi
+				JOIN orders o ON o.id = oi.order_id
+				WHERE oi.product_id = p.id
+				  AND o.status <> 'cancelled'
+				  AND ($4::bigint = 0 OR o.created_at >= NOW() - $4 * INTERVAL '1 millisecond')
+			) s
+			WHERE p.archived_at IS NULL
+			  AND p.category IN (SELECT category FROM ordered)
+			  AND p.id NOT IN (SELECT product_id FROM ordered)
+			  AND ($5::text IS NULL OR p.tenant_id = $5)
+		)
+		SELECT %s, units
+		FROM ranked
+		WHERE ($3 = 0 OR category_rank <= $3)
+		ORDER BY units DESC, id
+		LIMIT $2
+	`, productColumns)
+
+	tenant := tenantScope(ctx)
+	rows, err := r.pool.Query(ctx, query, orderID, limits.Limit, limits.PerCategory, limits.SalesWindow.Milliseconds(), tenant)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to query upsell products")
+		return nil, fmt.Errorf("failed to query upsell products: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	products := []model.UpsellProduct{}
+	for rows.Next() {
+		var p model.UpsellProduct
+		if err := scanProduct(rows, &p.Product, &p.UnitsSold); err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan upsell product row")
+			return nil, fmt.Errorf("failed to scan upsell product: %w", Classify(err))
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating upsell product rows")
+		return nil, fmt.Errorf("error iterating upsell products: %w", Classify(err))
+	}
+
+	if len(products) > 0 {
+		return products, nil
+	}
+
+	// Nothing to recommend may also mean there is no such order
+	var exists bool
+	err = r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1 AND ($2::text IS NULL OR tenant_id = $2))
+	`, orderID, tenant).Scan(&exists)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to check order exists")
+		return nil, fmt.Errorf("failed to check order exists: %w", Classify(err))
+	}
+	if !exists {
+		return nil, model.ErrOrderNotFound
+	}
+
+	return products, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsellRepository_ListUpsell(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewUpsellRepository(pool, logger)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	seedProducts(t, pool, []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(12.99), Category: "Waffle", CreatedAt: now},
+		{ID: "P002", Name: "Belgian Waffle", Price: model.NewMoney(9.99), Category: "Waffle", CreatedAt: now},
+		{ID: "P003", Name: "Berry Waffle", Price: model.NewMoney(10.99), Category: "Waffle", CreatedAt: now},
+		{ID: "P004", Name: "Plain Waffle", Price: model.NewMoney(7.99), Category: "Waffle", CreatedAt: now},
+		{ID: "P005", Name: "Iced Coffee", Price: model.NewMoney(4.50), Category: "Drinks", CreatedAt: now},
+		{ID: "P006", Name: "Lemonade", Price: model.NewMoney(3.50), Category: "Drinks", CreatedAt: now},
+		{ID: "P007", Name: "Old Waffle", Price: model.NewMoney(8.99), Category: "Waffle", CreatedAt: now},
+		{ID: "P008", Name: "Cheesecake", Price: model.NewMoney(6.99), Category: "Dessert", CreatedAt: now},
+	})
+	_, err := pool.Exec(ctx, `UPDATE products SET archived_at = NOW() WHERE id = 'P007'`)
+	require.NoError(t, err)
+
+	createOrder := func(createdAt time.Time, status model.OrderStatus, quantities map[string]int) uuid.UUID {
+		id := uuid.New()
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, &model.Order{ID: id, Status: status, CreatedAt: createdAt, UpdatedAt: createdAt}))
+		var items []model.OrderItem
+		for productID, quantity := range quantities {
+			items = append(items, model.OrderItem{ID: uuid.New(), OrderID: id, ProductID: productID, Quantity: quantity})
+		}
+		require.NoError(t, orderRepo.CreateOrderItems(ctx, tx, items))
+		require.NoError(t, tx.Commit(ctx))
+		return id
+	}
+
+	createOrder(now.Add(-24*time.Hour), model.OrderStatusFulfilled, map[string]int{"P002": 5, "P003": 2, "P006": 1, "P007": 9})
+	createOrder(now.Add(-48*time.Hour), model.OrderStatusConfirmed, map[string]int{"P003": 1, "P008": 20})
+	// Cancelled and old orders do not count towards sales
+	createOrder(now.Add(-time.Hour), model.OrderStatusCancelled, map[string]int{"P004": 50})
+	createOrder(now.Add(-200*24*time.Hour), model.OrderStatusFulfilled, map[string]int{"P004": 50})
+
+	order := createOrder(now, model.OrderStatusPending, map[string]int{"P001": 1, "P005": 2})
+
+	ids := func(products []model.UpsellProduct) []string {
+		out := []string{}
+		for _, p := range products {
+			out = append(out, p.ID)
+		}
+		return out
+	}
+
+	t.Run("Best sellers in the order's categories", func(t *testing.T) {
+		products, err := repo.ListUpsell(ctx, order, model.UpsellLimits{Limit: 10, SalesWindow: 90 * 24 * time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P002", "P003", "P006", "P004"}, ids(products))
+		assert.Equal(t, int64(5), products[0].UnitsSold)
+		assert.Equal(t, int64(3), products[1].UnitsSold)
+		assert.Equal(t, int64(0), products[3].UnitsSold)
+		assert.Equal(t, "Belgian Waffle", products[0].Name)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		products, err := repo.ListUpsell(ctx, order, model.UpsellLimits{Limit: 2, SalesWindow: 90 * 24 * time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P002", "P003"}, ids(products))
+
+		products, err = repo.ListUpsell(ctx, order, model.UpsellLimits{Limit: 10, PerCategory: 1, SalesWindow: 90 * 24 * time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P002", "P006"}, ids(products))
+	})
+
+	t.Run("Without a sales window every order counts", func(t *testing.T) {
+		products, err := repo.ListUpsell(ctx, order, model.UpsellLimits{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"P004"}, ids(products))
+		assert.Equal(t, int64(50), products[0].UnitsSold)
+	})
+
+	t.Run("Nothing to recommend", func(t *testing.T) {
+		onlyDessert := createOrder(now, model.OrderStatusPending, map[string]int{"P008": 1})
+		products, err := repo.ListUpsell(ctx, onlyDessert, model.UpsellLimits{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, products)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		_, err := repo.ListUpsell(ctx, uuid.New(), model.UpsellLimits{Limit: 10})
+		assert.Equal(t, model.ErrOrderNotFound, err)
+
+		// Another tenant's order is not found either
+		_, err = repo.ListUpsell(model.WithTenant(ctx, "acme"), order, model.UpsellLimits{Limit: 10})
+		assert.Equal(t, model.ErrOrderNotFound, err)
+	})
+}
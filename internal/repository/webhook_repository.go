@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// webhookRepository implements WebhookRepository using PostgreSQL.
+type webhookRepository struct {
+	pool   *pgxpool.Pool
+	logger zerolog.Logger
+}
+
+// NewWebhookRepository creates a new PostgreSQL-backed webhook outbox repository.
+func NewWebhookRepository(pool *pgxpool.Pool, logger zerolog.Logger) WebhookRepository {
+	return &webhookRepository{
+		pool:   pool,
+		logger: logger.With().Str("repository", "webhook").Logger(),
+	}
+}
+
+// insertWebhookDeliveries adds deliveries to the outbox within the provided
+// transaction, so they are only sent if the change they announce commits.
+func insertWebhookDeliveries(ctx context.Context, tx pgx.Tx, deliveries []model.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, event_id, event_type, order_id, endpoint, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(query, d.ID, d.EventID, d.EventType, d.OrderID, d.Endpoint, d.Payload)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// Enqueue adds deliveries to the outbox within the provided transaction.
+func (r *webhookRepository) Enqueue(ctx context.Context, tx pgx.Tx, deliveries []model.WebhookDelivery) error {
+	if err := insertWebhookDeliveries(ctx, tx, deliveries); err != nil {
+		r.logger.Error().Err(err).Int("deliveries", len(deliveries)).Msg("failed to enqueue webhook deliveries")
+		return err
+	}
+	return nil
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due,
+// oldest first. Claiming counts an attempt and moves the next attempt lease
+// into the future, so other dispatchers skip the deliveries meanwhile and
+// they are retried if this one stops before recording the outcome.
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_id, event_type, order_id, endpoint, payload, status, attempts, last_error, created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		r.logger.Error().Err(err).Msg("failed to claim webhook deliveries")
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	return r.scanDeliveries(rows)
+}
+
+// ListByOrder retrieves the deliveries announcing changes to an order,
+// oldest first.
+func (r *webhookRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.WebhookDelivery, error) {
+	query := `
+		SELECT id, event_id, event_type, order_id, endpoint, payload, status, attempts, last_error, created_at
+		FROM webhook_deliveries
+		WHERE order_id = $1
+		ORDER BY created_at, endpoint
+	`
+
+	rows, err := r.pool.Query(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list webhook deliveries")
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	return r.scanDeliveries(rows)
+}
+
+// scanDeliveries reads webhook delivery rows.
+func (r *webhookRepository) scanDeliveries(rows pgx.Rows) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		var d model.WebhookDelivery
+		err := rows.Scan(
+			&d.ID,
+			&d.EventID,
+			&d.EventType,
+			&d.OrderID,
+			&d.Endpoint,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.LastError,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("failed to scan webhook delivery row")
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", Classify(err))
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error().Err(err).Msg("error iterating webhook delivery rows")
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", Classify(err))
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered records that a delivery was accepted by its endpoint.
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', delivered_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		r.logger.Error().Err(err).Str("delivery_id", id.String()).Msg("failed to mark webhook delivered")
+		return fmt.Errorf("failed to mark webhook delivered: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// Reschedule records a failed attempt and schedules the next one after delay.
+func (r *webhookRepository) Reschedule(ctx context.Context, id uuid.UUID, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond', last_error = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, delay.Milliseconds(), lastError); err != nil {
+		r.logger.Error().Err(err).Str("delivery_id", id.String()).Msg("failed to reschedule webhook delivery")
+		return fmt.Errorf("failed to reschedule webhook delivery: %w", Classify(err))
+	}
+
+	return nil
+}
+
+// MarkFailed records a final failed attempt; the delivery is not retried.
+func (r *webhookRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'failed', last_error = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, lastError); err != nil {
+		r.logger.Error().Err(err).Str("delivery_id", id.String()).Msg("failed to mark webhook failed")
+		return fmt.Errorf("failed to mark webhook failed: %w", Classify(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createWebhookSchema creates the webhook_deliveries table for testing.
+func createWebhookSchema(t *testing.T, pool *pgxpool.Pool) {
+	ctx := context.Background()
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY,
+			event_id UUID NOT NULL,
+			event_type TEXT NOT NULL,
+			order_id UUID,
+			endpoint TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_error TEXT,
+			delivered_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := pool.Exec(ctx, schema)
+	require.NoError(t, err)
+}
+
+func TestWebhookRepository(t *testing.T) {
+	pool, cleanup := setupOrderTestDB(t)
+	defer cleanup()
+	createWebhookSchema(t, pool)
+
+	logger := zerolog.Nop()
+	orderRepo := NewOrderRepository(pool, logger)
+	repo := NewWebhookRepository(pool, logger)
+	ctx := context.Background()
+
+	newDelivery := func(eventType, endpoint string) model.WebhookDelivery {
+		return model.WebhookDelivery{
+			ID:        uuid.New(),
+			EventID:   uuid.New(),
+			EventType: eventType,
+			OrderID:   uuid.New(),
+			Endpoint:  endpoint,
+			Payload:   json.RawMessage(`{"type":"` + eventType + `"}`),
+		}
+	}
+
+	// claimAll claims every due delivery.
+	claimAll := func(t *testing.T, lease time.Duration) []model.WebhookDelivery {
+		deliveries, err := repo.ClaimDue(ctx, 100, lease)
+		require.NoError(t, err)
+		return deliveries
+	}
+
+	t.Run("Deliveries are only enqueued when the transaction commits", func(t *testing.T) {
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Enqueue(ctx, tx, []model.WebhookDelivery{newDelivery(model.WebhookOrderCreated, "https://a.example.com")}))
+		require.NoError(t, tx.Rollback(ctx))
+
+		assert.Empty(t, claimAll(t, time.Minute))
+	})
+
+	t.Run("Claimed deliveries are leased until the outcome is recorded", func(t *testing.T) {
+		delivered := newDelivery(model.WebhookOrderCreated, "https://a.example.com")
+		retried := newDelivery(model.WebhookOrderCreated, "https://b.example.com")
+		failed := newDelivery(model.WebhookOrderCreated, "https://c.example.com")
+
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Enqueue(ctx, tx, []model.WebhookDelivery{delivered, retried, failed}))
+		require.NoError(t, tx.Commit(ctx))
+
+		claimed := claimAll(t, time.Minute)
+		require.Len(t, claimed, 3)
+		for _, d := range claimed {
+			assert.Equal(t, 1, d.Attempts)
+			assert.Equal(t, model.WebhookDeliveryPending, d.Status)
+			assert.JSONEq(t, `{"type":"order.created"}`, string(d.Payload))
+		}
+
+		// Leased deliveries are skipped
+		assert.Empty(t, claimAll(t, time.Minute))
+
+		require.NoError(t, repo.MarkDelivered(ctx, delivered.ID))
+		require.NoError(t, repo.Reschedule(ctx, retried.ID, 0, "status 503"))
+		require.NoError(t, repo.MarkFailed(ctx, failed.ID, "status 410"))
+
+		claimed = claimAll(t, time.Minute)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, retried.ID, claimed[0].ID)
+		assert.Equal(t, 2, claimed[0].Attempts)
+		require.NotNil(t, claimed[0].LastError)
+		assert.Equal(t, "status 503", *claimed[0].LastError)
+		require.NoError(t, repo.MarkDelivered(ctx, retried.ID))
+
+		var status string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM webhook_deliveries WHERE id = $1`, failed.ID).Scan(&status))
+		assert.Equal(t, string(model.WebhookDeliveryFailed), status)
+	})
+
+	t.Run("Status changes enqueue deliveries with the change", func(t *testing.T) {
+		now := time.Now()
+		orderID := uuid.New()
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, orderRepo.CreateOrder(ctx, tx, &model.Order{ID: orderID, Status: model.OrderStatusPending, CreatedAt: now, UpdatedAt: now}))
+		require.NoError(t, tx.Commit(ctx))
+
+		cancelled := newDelivery(model.WebhookOrderCancelled, "https://a.example.com")
+		_, err = orderRepo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusCancelled, nil, []model.WebhookDelivery{cancelled}, nil)
+		require.NoError(t, err)
+
+		// A rejected transition enqueues nothing
+		_, err = orderRepo.UpdateStatus(ctx, orderID, model.OrderStatusPending, model.OrderStatusCancelled, nil,
+			[]model.WebhookDelivery{newDelivery(model.WebhookOrderCancelled, "https://a.example.com")}, nil)
+		assert.Equal(t, model.ErrStatusTransition, err)
+
+		claimed := claimAll(t, time.Minute)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, cancelled.ID, claimed[0].ID)
+	})
+
+	t.Run("Deliveries are listed by order", func(t *testing.T) {
+		orderID := uuid.New()
+		first := newDelivery(model.WebhookOrderCreated, "https://a.example.com")
+		second := newDelivery(model.WebhookOrderCreated, "https://b.example.com")
+		first.OrderID, second.OrderID = orderID, orderID
+
+		tx, err := orderRepo.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.Enqueue(ctx, tx, []model.WebhookDelivery{second, first, newDelivery(model.WebhookOrderCreated, "https://a.example.com")}))
+		require.NoError(t, tx.Commit(ctx))
+		claimAll(t, time.Minute)
+		require.NoError(t, repo.MarkDelivered(ctx, first.ID))
+
+		deliveries, err := repo.ListByOrder(ctx, orderID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, first.ID, deliveries[0].ID)
+		assert.Equal(t, model.WebhookDeliveryDelivered, deliveries[0].Status)
+		assert.Equal(t, second.ID, deliveries[1].ID)
+		assert.Equal(t, model.WebhookDeliveryPending, deliveries[1].Status)
+		assert.Equal(t, 1, deliveries[1].Attempts)
+
+		deliveries, err = repo.ListByOrder(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+}
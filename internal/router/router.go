@@ -3,19 +3,362 @@ package router
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"mini-kart/internal/handler"
+	"mini-kart/internal/metrics"
 	"mini-kart/internal/middleware"
+	"mini-kart/internal/notification"
+	"mini-kart/internal/openapi"
 
 	"github.com/rs/zerolog"
 )
 
+// VersionPrefix is the path prefix of the current API version. Every /api/
+// route is also served under it.
+const VersionPrefix = "/api/v1/"
+
+// Option registers optional routes and middleware on the router.
+type Option func(o *options)
+
+// options holds the router state that optional features are registered on.
+type options struct {
+	mux        *http.ServeMux
+	routes     *middleware.RouteRegistry
+	counters   *metrics.Registry
+	limiter    *middleware.RateLimiter
+	quotas     *middleware.QuotaTracker
+	shadower   *middleware.Shadower
+	notifier   notification.Notifier
+	adminKeys  map[string]string
+	tenantKeys map[string]string
+	swaggerUI  bool
+
+	// adminProductWrites restricts product writes under /api/products to admin keys
+	adminProductWrites bool
+
+	// maxBodyBytes and timeout bound each request's body and duration; zero
+	// disables either
+	maxBodyBytes int64
+	timeout      time.Duration
+
+	// spec describes the registered API routes for the OpenAPI document
+	spec []openapi.Route
+}
+
+// describe adds routes to the OpenAPI document.
+func (o *options) describe(routes ...openapi.Route) {
+	o.spec = append(o.spec, routes...)
+}
+
+// WithHealthHandler registers the readiness and health history endpoints.
+func WithHealthHandler(healthHandler *handler.HealthHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/ready", healthHandler.Ready)
+		o.mux.HandleFunc("/api/admin/health/history", healthHandler.History)
+		o.describe(healthRoutes...)
+	}
+}
+
+// WithPriceChangeHandler registers the product price change and approval endpoints.
+func WithPriceChangeHandler(priceChangeHandler *handler.PriceChangeHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/products/", func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/price") {
+				priceChangeHandler.UpdatePrice(w, r)
+				return
+			}
+			http.Error(w, "not found", http.StatusNotFound)
+		})
+		o.mux.HandleFunc("/api/admin/price-changes", priceChangeHandler.ListPending)
+		o.mux.HandleFunc("/api/admin/price-changes/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/admin/price-changes/" {
+				priceChangeHandler.ListPending(w, r)
+				return
+			}
+			priceChangeHandler.Decide(w, r)
+		})
+		o.describe(priceChangeRoutes...)
+	}
+}
+
+// WithPricingHandler registers the price preview and coupon validation endpoints.
+func WithPricingHandler(pricingHandler *handler.PricingHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/pricing/preview", pricingHandler.Preview)
+		o.mux.HandleFunc("/api/coupons/validate", pricingHandler.ValidateCoupon)
+		o.describe(pricingRoutes...)
+	}
+}
+
+// WithShipmentHandler registers the order shipment and fulfillment event endpoints.
+func WithShipmentHandler(shipmentHandler *handler.ShipmentHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/orders/{id}/shipments", shipmentHandler.Shipments)
+		o.mux.HandleFunc("/api/orders/{id}/shipments/{shipmentId}/tracking", shipmentHandler.Tracking)
+		o.mux.HandleFunc("/api/orders/{id}/ship", shipmentHandler.Ship)
+		o.mux.HandleFunc("/api/orders/{id}/events", shipmentHandler.Events)
+		o.describe(shipmentRoutes...)
+	}
+}
+
+// WithTimelineHandler registers the order note and timeline endpoints.
+func WithTimelineHandler(timelineHandler *handler.TimelineHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/orders/{id}/notes", timelineHandler.Notes)
+		o.mux.HandleFunc("/api/admin/orders/{id}/timeline", timelineHandler.Timeline)
+		o.describe(timelineRoutes...)
+	}
+}
+
+// WithOrderPricingHandler registers the order pricing recalculation endpoint.
+func WithOrderPricingHandler(orderPricingHandler *handler.OrderPricingHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/orders/{id}/recalculate", orderPricingHandler.Recalculate)
+		o.describe(orderPricingRoutes...)
+	}
+}
+
+// WithUpsellHandler registers the order upsell endpoint.
+func WithUpsellHandler(upsellHandler *handler.UpsellHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/orders/{id}/upsell", upsellHandler.Upsell)
+		o.describe(upsellRoutes...)
+	}
+}
+
+// WithSnapshotHandler registers the signed order snapshot endpoint.
+func WithSnapshotHandler(snapshotHandler *handler.SnapshotHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/orders/{id}/snapshots", snapshotHandler.Create)
+		o.describe(snapshotRoutes...)
+	}
+}
+
+// WithSLAHandler registers the at-risk order listing.
+func WithSLAHandler(slaHandler *handler.SLAHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/orders/at-risk", slaHandler.AtRisk)
+		o.describe(slaRoutes...)
+	}
+}
+
+// WithOrderImportHandler registers the historical order import endpoint.
+func WithOrderImportHandler(orderImportHandler *handler.OrderImportHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/orders/import", orderImportHandler.Import)
+		o.describe(orderImportRoutes...)
+	}
+}
+
+// WithCustomerHandler registers the customer registration, lookup and order
+// history endpoints.
+func WithCustomerHandler(customerHandler *handler.CustomerHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/customers", customerHandler.Customers)
+		o.mux.HandleFunc("/api/customers/{id}", customerHandler.Customer)
+		o.mux.HandleFunc("/api/customers/{id}/orders", customerHandler.Orders)
+		o.describe(customerRoutes...)
+	}
+}
+
+// WithOperationHandler registers the asynchronous order operation endpoint.
+func WithOperationHandler(operationHandler *handler.OperationHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/operations/", operationHandler.Get)
+		o.describe(operationRoutes...)
+	}
+}
+
+// WithMetricsHandler registers the operational metrics endpoints.
+func WithMetricsHandler(metricsHandler *handler.MetricsHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/metrics", metricsHandler.Metrics)
+		o.mux.HandleFunc("/api/admin/metrics/caches", metricsHandler.Caches)
+		o.describe(metricsRoutes...)
+	}
+}
+
+// WithLogLevelHandler registers the runtime log level endpoint.
+func WithLogLevelHandler(logLevelHandler *handler.LogLevelHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/log-level", logLevelHandler.LogLevel)
+		o.describe(logLevelRoutes...)
+	}
+}
+
+// WithTenantHandler registers the tenant management endpoints.
+func WithTenantHandler(tenantHandler *handler.TenantHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/tenants", tenantHandler.Tenants)
+		o.mux.HandleFunc("/api/admin/tenants/", tenantHandler.Tenant)
+		o.describe(tenantRoutes...)
+	}
+}
+
+// WithFlashSaleHandler registers the flash sale management endpoints.
+func WithFlashSaleHandler(flashSaleHandler *handler.FlashSaleHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/flash-sales", flashSaleHandler.FlashSales)
+		o.mux.HandleFunc("/api/admin/flash-sales/", flashSaleHandler.FlashSale)
+		o.describe(flashSaleRoutes...)
+	}
+}
+
+// WithCouponFileHandler registers the coupon file registry endpoints.
+func WithCouponFileHandler(couponFileHandler *handler.CouponFileHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/coupon-files", couponFileHandler.Files)
+		o.mux.HandleFunc("/api/admin/coupon-files/generations", couponFileHandler.Generations)
+		o.mux.HandleFunc("/api/admin/coupon-files/validity", couponFileHandler.Validity)
+		o.describe(couponFileRoutes...)
+	}
+}
+
+// WithProductImageHandler registers the product image upload and management
+// endpoints.
+func WithProductImageHandler(productImageHandler *handler.ProductImageHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/products/{id}/images", productImageHandler.Images)
+		o.mux.HandleFunc("/api/admin/products/{id}/images/uploads", productImageHandler.Uploads)
+		o.mux.HandleFunc("/api/admin/products/{id}/images/order", productImageHandler.Order)
+		o.mux.HandleFunc("/api/admin/products/{id}/images/{imageId}", productImageHandler.Image)
+		o.describe(productImageRoutes...)
+	}
+}
+
+// WithAdminHandler registers the admin dashboard page and its data endpoint.
+func WithAdminHandler(adminHandler *handler.AdminHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/admin", adminHandler.Dashboard)
+		o.mux.HandleFunc("/admin/", adminHandler.Dashboard)
+		o.mux.HandleFunc("/api/admin/dashboard", adminHandler.Summary)
+		o.describe(adminRoutes...)
+	}
+}
+
+// WithCouponReloadHandler registers the coupon data status and reload endpoints.
+func WithCouponReloadHandler(couponReloadHandler *handler.CouponReloadHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/coupons", couponReloadHandler.Status)
+		o.mux.HandleFunc("/api/admin/coupons/reload", couponReloadHandler.Reload)
+		o.describe(couponReloadRoutes...)
+	}
+}
+
+// WithConfigHandler registers the configuration introspection endpoint.
+func WithConfigHandler(configHandler *handler.ConfigHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/api/admin/config", configHandler.Config)
+		o.describe(configRoutes...)
+	}
+}
+
+// WithPublicHandler registers the anonymous public catalogue endpoint.
+func WithPublicHandler(publicHandler *handler.PublicHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/public/products", publicHandler.Products)
+		o.describe(publicRoutes...)
+	}
+}
+
+// WithCartHandler registers the anonymous signed cart endpoint.
+func WithCartHandler(cartHandler *handler.CartHandler) Option {
+	return func(o *options) {
+		o.mux.HandleFunc("/public/cart", cartHandler.Cart)
+		o.describe(cartRoutes...)
+	}
+}
+
+// WithSwaggerUI serves a Swagger UI page for the OpenAPI document at /docs.
+func WithSwaggerUI() Option {
+	return func(o *options) {
+		o.swaggerUI = true
+	}
+}
+
+// WithDeprecations adds Deprecation, Sunset and Link headers to routes marked
+// deprecated in the registry and counts their usage in counters.
+func WithDeprecations(routes *middleware.RouteRegistry, counters *metrics.Registry) Option {
+	return func(o *options) {
+		o.routes = routes
+		o.counters = counters
+	}
+}
+
+// WithRateLimit limits each client's request rate and reports the remaining
+// allowance in X-RateLimit-* response headers.
+func WithRateLimit(limiter *middleware.RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithQuotas counts each caller's monthly requests and orders against the
+// tracker's quotas, reports what is left in X-Quota-* response headers and
+// sends warnings to notifier as quotas run out.
+func WithQuotas(tracker *middleware.QuotaTracker, notifier notification.Notifier) Option {
+	return func(o *options) {
+		o.quotas = tracker
+		o.notifier = notifier
+	}
+}
+
+// WithShadow mirrors a sample of read requests to the shadower's secondary
+// handler and reports where its responses differ, without affecting the
+// responses clients get. Only requests that passed authentication, rate
+// limits and quotas are mirrored, and admin routes and admin callers never
+// are.
+func WithShadow(shadower *middleware.Shadower) Option {
+	return func(o *options) {
+		o.shadower = shadower
+	}
+}
+
+// WithRequestLimits answers requests with bodies over maxBodyBytes with 413
+// Request Entity Too Large, and requests not completed within timeout with
+// 504 Gateway Timeout. Zero disables either limit. Product and order imports
+// keep their own upload limit and are not timed out.
+func WithRequestLimits(maxBodyBytes int64, timeout time.Duration) Option {
+	return func(o *options) {
+		o.maxBodyBytes = maxBodyBytes
+		o.timeout = timeout
+	}
+}
+
+// WithAdminKeys accepts admin-scoped API keys, mapped to the name of the
+// admin each belongs to. Admin keys may act on behalf of customers through
+// the X-On-Behalf-Of header.
+func WithAdminKeys(keys map[string]string) Option {
+	return func(o *options) {
+		o.adminKeys = keys
+	}
+}
+
+// WithAdminOnlyProductWrites restricts creating, updating, deleting and
+// importing products under /api/products to admin keys, as under
+// /api/admin/products, leaving the client key read-only on the catalogue.
+func WithAdminOnlyProductWrites() Option {
+	return func(o *options) {
+		o.adminProductWrites = true
+	}
+}
+
+// WithTenantKeys accepts tenant-scoped API keys, mapped to the tenant each
+// belongs to. Callers using one only see their tenant's data.
+func WithTenantKeys(keys map[string]string) Option {
+	return func(o *options) {
+		o.tenantKeys = keys
+	}
+}
+
 // New creates a new HTTP router with all routes and middleware configured.
 func New(
 	productHandler *handler.ProductHandler,
 	orderHandler *handler.OrderHandler,
 	apiKey string,
 	logger zerolog.Logger,
+	opts ...Option,
 ) http.Handler {
 	mux := http.NewServeMux()
 
@@ -30,7 +373,18 @@ func New(
 	productRouteHandler := func(w http.ResponseWriter, r *http.Request) {
 		// Check if this is a request for a specific product ID
 		if r.URL.Path != "/api/products" && r.URL.Path != "/api/products/" {
-			productHandler.GetByID(w, r)
+			switch r.Method {
+			case http.MethodPut:
+				productHandler.Update(w, r)
+			case http.MethodDelete:
+				productHandler.Delete(w, r)
+			default:
+				productHandler.GetByID(w, r)
+			}
+			return
+		}
+		if r.Method == http.MethodPost {
+			productHandler.Create(w, r)
 			return
 		}
 		productHandler.GetAll(w, r)
@@ -39,6 +393,21 @@ func New(
 	// Register product routes (both with and without trailing slash)
 	mux.HandleFunc("/api/products", productRouteHandler)
 	mux.HandleFunc("/api/products/", productRouteHandler)
+	mux.HandleFunc("/api/products/import", productHandler.Import)
+	mux.HandleFunc("/api/products/compare", productHandler.Compare)
+
+	// Product administration
+	mux.HandleFunc("/api/admin/products", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			productHandler.Create(w, r)
+			return
+		}
+		productHandler.GetAll(w, r)
+	})
+	mux.HandleFunc("/api/admin/products/{id}", productRouteHandler)
+	mux.HandleFunc("/api/admin/products/import", productHandler.Import)
+	mux.HandleFunc("/api/admin/products/archive", productHandler.Archive)
+	mux.HandleFunc("/api/admin/products/{id}/sale", productHandler.Sale)
 
 	// Order handler function
 	orderRouteHandler := func(w http.ResponseWriter, r *http.Request) {
@@ -48,6 +417,17 @@ func New(
 			return
 		}
 
+		if r.URL.Path == "/api/orders" || r.URL.Path == "/api/orders/" {
+			orderHandler.List(w, r)
+			return
+		}
+
+		// Orders looked up by the reference a client created them with
+		if strings.HasPrefix(r.URL.Path, "/api/orders/by-ref/") {
+			orderHandler.GetByRef(w, r)
+			return
+		}
+
 		// Check if this is a request for a specific order ID
 		if strings.HasPrefix(r.URL.Path, "/api/orders/") && r.URL.Path != "/api/orders/" {
 			orderHandler.GetByID(w, r)
@@ -60,13 +440,110 @@ func New(
 	// Register order routes (both with and without trailing slash)
 	mux.HandleFunc("/api/orders", orderRouteHandler)
 	mux.HandleFunc("/api/orders/", orderRouteHandler)
+	mux.HandleFunc("/api/orders/{id}/status", orderHandler.UpdateStatus)
+
+	// Order administration and analytics
+	mux.HandleFunc("/api/admin/orders", orderHandler.Search)
+	mux.HandleFunc("/api/admin/orders/{id}/status", orderHandler.OverrideStatus)
+	mux.HandleFunc("/api/admin/analytics/orders-by-source", orderHandler.CountBySource)
 
-	// Apply middleware in order: Recovery -> Logging -> CORS -> APIKeyAuth
+	// Register optional routes
+	o := &options{mux: mux}
+	o.describe(coreRoutes...)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// Describe the registered routes, so client SDKs can be generated
+	docsHandler := handler.NewDocsHandler(openapi.Generate(apiInfo, versioned(o.spec), handler.ErrorResponse{}), logger)
+	mux.HandleFunc("/openapi.json", docsHandler.Spec)
+	if o.swaggerUI {
+		mux.HandleFunc("/docs", docsHandler.UI)
+	}
+
+	// Serve every /api/ route under the versioned prefix as well
+	mux.HandleFunc(VersionPrefix, func(w http.ResponseWriter, r *http.Request) {
+		unversioned := new(http.Request)
+		*unversioned = *r
+		u := *r.URL
+		u.Path = "/api/" + strings.TrimPrefix(r.URL.Path, VersionPrefix)
+		u.RawPath = ""
+		unversioned.URL = &u
+		mux.ServeHTTP(w, unversioned)
+	})
+
+	// Apply middleware in order: RequestID -> Recovery -> Logging -> CORS -> BodyLimit -> Timeout -> Deprecation -> ClientCertIdentity -> APIKeyAuth -> AdminScope -> OnBehalfOf -> TenantScope -> RateLimit -> Quota -> Shadow
 	var handler http.Handler = mux
-	handler = middleware.APIKeyAuth(apiKey, logger)(handler)
+	if o.shadower != nil {
+		handler = middleware.Shadow(o.shadower)(handler)
+	}
+	if o.quotas != nil {
+		handler = middleware.Quota(o.quotas, o.notifier, logger)(handler)
+	}
+	if o.limiter != nil {
+		handler = middleware.RateLimit(o.limiter, logger)(handler)
+	}
+	handler = middleware.TenantScope(logger)(handler)
+	handler = middleware.OnBehalfOf(logger)(handler)
+	handler = middleware.AdminScope(o.isAdminRoute, logger)(handler)
+	handler = middleware.APIKeyAuth(apiKey, o.adminKeys, o.tenantKeys, logger)(handler)
+	handler = middleware.ClientCertIdentity(logger)(handler)
+	if o.routes != nil {
+		handler = middleware.Deprecation(o.routes, o.counters, logger)(handler)
+	}
+	if o.timeout > 0 {
+		handler = middleware.Timeout(o.timeout, isImport, logger)(handler)
+	}
+	if o.maxBodyBytes > 0 {
+		handler = middleware.BodyLimit(o.maxBodyBytes, isImport, logger)(handler)
+	}
 	handler = middleware.CORS(handler)
 	handler = middleware.Logging(logger)(handler)
 	handler = middleware.Recovery(logger)(handler)
+	handler = middleware.RequestID(logger)(handler)
 
 	return handler
 }
+
+// isAdminRoute reports whether r is for the admin route group, which only
+// admin keys may call: every route under /api/admin/ and, with
+// WithAdminOnlyProductWrites, product writes under /api/products. The
+// middleware runs before versioned paths are rewritten, so both are matched.
+func (o *options) isAdminRoute(r *http.Request) bool {
+	path := r.URL.Path
+	if rest, ok := strings.CutPrefix(path, VersionPrefix); ok {
+		path = "/api/" + rest
+	}
+
+	if strings.HasPrefix(path, "/api/admin/") {
+		return true
+	}
+	if !o.adminProductWrites || (path != "/api/products" && !strings.HasPrefix(path, "/api/products/")) {
+		return false
+	}
+	return r.Method != http.MethodGet && r.Method != http.MethodHead
+}
+
+// isImport reports whether r uploads a product or order import file, which
+// is larger and takes longer than other requests. The middleware runs before
+// versioned paths are rewritten, so both are matched.
+func isImport(r *http.Request) bool {
+	path := r.URL.Path
+	if rest, ok := strings.CutPrefix(path, VersionPrefix); ok {
+		path = "/api/" + rest
+	}
+	return path == "/api/products/import" || path == "/api/admin/products/import" || path == "/api/admin/orders/import"
+}
+
+// versioned documents /api/ routes under VersionPrefix, since the
+// unversioned paths may be deprecated.
+func versioned(routes []openapi.Route) []openapi.Route {
+	out := make([]openapi.Route, len(routes))
+	for i, route := range routes {
+		if rest, ok := strings.CutPrefix(route.Path, "/api/"); ok {
+			route.Path = VersionPrefix + rest
+		}
+		out[i] = route
+	}
+	return out
+}
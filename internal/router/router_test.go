@@ -0,0 +1,263 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/handler"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/middleware"
+	"mini-kart/internal/openapi"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey   = "client-key"
+	testAdminKey = "admin-key"
+)
+
+// allOptions registers every optional route group and middleware. Handlers
+// are built without services, so only requests rejected before reaching a
+// service may be sent.
+func allOptions(logger zerolog.Logger) []Option {
+	registry := metrics.NewRegistry()
+
+	return []Option{
+		WithHealthHandler(handler.NewHealthHandler(nil, logger)),
+		WithPriceChangeHandler(handler.NewPriceChangeHandler(nil, logger)),
+		WithPricingHandler(handler.NewPricingHandler(nil, logger)),
+		WithShipmentHandler(handler.NewShipmentHandler(nil, logger)),
+		WithTimelineHandler(handler.NewTimelineHandler(nil, logger)),
+		WithOrderPricingHandler(handler.NewOrderPricingHandler(nil, logger)),
+		WithUpsellHandler(handler.NewUpsellHandler(nil, logger)),
+		WithSnapshotHandler(handler.NewSnapshotHandler(nil, logger)),
+		WithSLAHandler(handler.NewSLAHandler(nil, logger)),
+		WithOrderImportHandler(handler.NewOrderImportHandler(nil, logger)),
+		WithCustomerHandler(handler.NewCustomerHandler(nil, logger)),
+		WithOperationHandler(handler.NewOperationHandler(nil, logger)),
+		WithMetricsHandler(handler.NewMetricsHandler(registry, nil, logger)),
+		WithLogLevelHandler(handler.NewLogLevelHandler(logger)),
+		WithTenantHandler(handler.NewTenantHandler(nil, logger)),
+		WithFlashSaleHandler(handler.NewFlashSaleHandler(nil, logger)),
+		WithCouponFileHandler(handler.NewCouponFileHandler(nil, logger)),
+		WithProductImageHandler(handler.NewProductImageHandler(nil, logger)),
+		WithAdminHandler(handler.NewAdminHandler(nil, nil, nil, registry, logger)),
+		WithCouponReloadHandler(handler.NewCouponReloadHandler(nil, logger)),
+		WithConfigHandler(handler.NewConfigHandler(map[string]any{}, logger)),
+		WithPublicHandler(handler.NewPublicHandler(nil, time.Minute, time.Minute, logger)),
+		WithCartHandler(handler.NewCartHandler(nil, logger)),
+		WithSwaggerUI(),
+		WithDeprecations(middleware.NewRouteRegistry(), registry),
+		WithRateLimit(middleware.NewRateLimiter(1000, time.Minute)),
+		WithQuotas(middleware.NewQuotaTracker(0, 0, false), nil),
+		WithRequestLimits(64, 0),
+		WithAdminKeys(map[string]string{testAdminKey: "ops"}),
+		WithAdminOnlyProductWrites(),
+	}
+}
+
+// newTestRouter builds a router with every route registered.
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	logger := zerolog.Nop()
+
+	var router http.Handler
+	require.NotPanics(t, func() {
+		router = New(handler.NewProductHandler(nil, logger), handler.NewOrderHandler(nil, logger), testAPIKey, logger, allOptions(logger)...)
+	}, "routes must register without ServeMux pattern conflicts")
+	return router
+}
+
+func TestNew_RegistersAllRoutes(t *testing.T) {
+	router := newTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"/api/v1/orders"`)
+	assert.NotContains(t, w.Body.String(), `"/api/orders"`, "documented routes are versioned")
+}
+
+func TestNew_Routing(t *testing.T) {
+	router := newTestRouter(t)
+	oversized := strings.Repeat("x", 128)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		key            string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "Client route",
+			method:         http.MethodGet,
+			path:           "/api/orders/not-a-uuid",
+			key:            testAPIKey,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Versioned client route",
+			method:         http.MethodGet,
+			path:           "/api/v1/orders/not-a-uuid",
+			key:            testAPIKey,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown versioned route",
+			method:         http.MethodGet,
+			path:           "/api/v1/unknown",
+			key:            testAPIKey,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing API key",
+			method:         http.MethodGet,
+			path:           "/api/v1/orders/not-a-uuid",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Admin route with client key",
+			method:         http.MethodGet,
+			path:           "/api/admin/orders",
+			key:            testAPIKey,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Versioned admin route with client key",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/orders",
+			key:            testAPIKey,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Versioned admin route with admin key",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/orders/not-a-uuid/status",
+			key:            testAdminKey,
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Product write with client key",
+			method:         http.MethodPost,
+			path:           "/api/v1/products",
+			key:            testAPIKey,
+			body:           `{}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Oversized body",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/products",
+			key:            testAdminKey,
+			body:           oversized,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Import exempt from the body limit",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/products/import",
+			key:            testAdminKey,
+			body:           oversized,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestIsAdminRoute(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		productWrites bool
+		expected      bool
+	}{
+		{name: "Admin route", method: http.MethodGet, path: "/api/admin/orders", expected: true},
+		{name: "Versioned admin route", method: http.MethodGet, path: "/api/v1/admin/orders", expected: true},
+		{name: "Nested admin route", method: http.MethodPost, path: "/api/v1/admin/products/P001/sale", expected: true},
+		{name: "Admin prefix without slash", method: http.MethodGet, path: "/api/administrators", expected: false},
+		{name: "Client route", method: http.MethodGet, path: "/api/orders", expected: false},
+		{name: "Versioned client route", method: http.MethodGet, path: "/api/v1/orders", expected: false},
+		{name: "Dashboard page", method: http.MethodGet, path: "/admin/", expected: false},
+		{name: "Product write", method: http.MethodPost, path: "/api/products", expected: false},
+		{name: "Admin-only product write", method: http.MethodPost, path: "/api/products", productWrites: true, expected: true},
+		{name: "Admin-only versioned product update", method: http.MethodPut, path: "/api/v1/products/P001", productWrites: true, expected: true},
+		{name: "Admin-only product import", method: http.MethodPost, path: "/api/v1/products/import", productWrites: true, expected: true},
+		{name: "Product read with admin-only writes", method: http.MethodGet, path: "/api/v1/products/P001", productWrites: true, expected: false},
+		{name: "Product HEAD with admin-only writes", method: http.MethodHead, path: "/api/products", productWrites: true, expected: false},
+		{name: "Order write with admin-only product writes", method: http.MethodPost, path: "/api/v1/orders", productWrites: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &options{adminProductWrites: tt.productWrites}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+
+			assert.Equal(t, tt.expected, o.isAdminRoute(req))
+		})
+	}
+}
+
+func TestIsImport(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/api/products/import", expected: true},
+		{path: "/api/v1/products/import", expected: true},
+		{path: "/api/admin/products/import", expected: true},
+		{path: "/api/v1/admin/products/import", expected: true},
+		{path: "/api/v1/admin/orders/import", expected: true},
+		{path: "/api/products", expected: false},
+		{path: "/api/v1/admin/products", expected: false},
+		{path: "/api/orders/import", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+
+			assert.Equal(t, tt.expected, isImport(req))
+		})
+	}
+}
+
+func TestVersioned(t *testing.T) {
+	routes := []openapi.Route{
+		{Method: http.MethodGet, Path: "/api/orders"},
+		{Method: http.MethodGet, Path: "/api/admin/orders"},
+		{Method: http.MethodGet, Path: "/health"},
+	}
+
+	out := versioned(routes)
+
+	require.Len(t, out, 3)
+	assert.Equal(t, "/api/v1/orders", out[0].Path)
+	assert.Equal(t, "/api/v1/admin/orders", out[1].Path)
+	assert.Equal(t, "/health", out[2].Path)
+	assert.Equal(t, "/api/orders", routes[0].Path, "the described routes are not modified")
+}
@@ -0,0 +1,726 @@
+package router
+
+import (
+	"net/http"
+
+	"mini-kart/internal/handler"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+	"mini-kart/internal/openapi"
+)
+
+// apiInfo describes the API in its OpenAPI document.
+var apiInfo = openapi.Info{
+	Title:   "mini-kart API",
+	Version: "1.0.0",
+	Description: "Product catalogue, ordering and order fulfillment API. Requests are authenticated " +
+		"with an API key in the X-API-Key header. Routes tagged admin, under /api/v1/admin/, need an admin key.",
+}
+
+// Query parameters shared by several routes.
+var (
+	limitParam  = openapi.Param{Name: "limit", Type: "integer", Description: "Maximum number of items to return"}
+	offsetParam = openapi.Param{Name: "offset", Type: "integer", Description: "Number of items to skip"}
+
+	// currencyParam and acceptCurrencyHeader ask for prices converted out
+	// of the catalogue currency, when exchange rates are configured.
+	currencyParam        = openapi.Param{Name: "currency", Description: "ISO 4217 currency to show prices in; takes precedence over Accept-Currency"}
+	acceptCurrencyHeader = []openapi.Param{
+		{Name: handler.AcceptCurrencyHeader, Description: "ISO 4217 currency to show prices in"},
+	}
+
+	productListParams = []openapi.Param{
+		{Name: "category", Description: "Only list products in this category"},
+		{Name: "sort", Enum: []string{"name", "price", "created_at"}},
+		{Name: "order", Enum: []string{"asc", "desc"}},
+		limitParam,
+		offsetParam,
+	}
+	orderListParams = []openapi.Param{
+		{Name: "source", Description: "Only list orders placed through this channel"},
+		limitParam,
+		offsetParam,
+		currencyParam,
+	}
+)
+
+// listProfiles describes the representations of a paginated list of T that
+// clients may ask for in the Accept header.
+func listProfiles[T any]() map[string]any {
+	return map[string]any{
+		handler.ProfileMediaType(1): []T{},
+		handler.ProfileMediaType(2): handler.PageResponse[T]{},
+	}
+}
+
+// coreRoutes describes the health check and the product and order routes
+// every router serves.
+var coreRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/health", Operation: "getHealth", Tag: "health",
+		Summary:   "Report that the server is up",
+		Responses: map[int]any{http.StatusOK: map[string]string{}},
+		Public:    true,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/products", Operation: "listProducts", Tag: "products",
+		Summary:   "List products",
+		Query:     append([]openapi.Param{currencyParam}, productListParams...),
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: []model.Product{}},
+		Profiles:  listProfiles[model.Product](),
+		Errors:    []int{http.StatusBadRequest, http.StatusNotAcceptable, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/products", Operation: "createProduct", Tag: "products",
+		Summary:   "Create a product",
+		Request:   model.ProductRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/products/{id}", Operation: "getProduct", Tag: "products",
+		Summary: "Get a product, optionally with a preview of its price with a coupon code",
+		Query: []openapi.Param{
+			{Name: "couponCode", Description: "Promo code to preview the product's price with"},
+			currencyParam,
+		},
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: model.ProductDetail{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/products/{id}", Operation: "updateProduct", Tag: "products",
+		Summary:   "Update a product",
+		Request:   model.ProductRequest{},
+		Responses: map[int]any{http.StatusOK: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/products/{id}", Operation: "deleteProduct", Tag: "products",
+		Summary:   "Delete a product that has never been ordered",
+		Responses: map[int]any{http.StatusNoContent: nil},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/products/import", Operation: "importProducts", Tag: "products",
+		Summary: "Import products from a CSV file",
+		Upload:  "file",
+		Responses: map[int]any{
+			http.StatusOK:          handler.BatchResponse{},
+			http.StatusMultiStatus: handler.BatchResponse{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/products/compare", Operation: "compareProducts", Tag: "products",
+		Summary: "Compare 2 to 4 products side by side",
+		Query: []openapi.Param{
+			{Name: "ids", Required: true, Description: "Comma-separated product IDs"},
+			currencyParam,
+		},
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: model.ProductComparison{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/products", Operation: "adminListProducts", Tag: "admin",
+		Summary:   "List products",
+		Query:     append([]openapi.Param{currencyParam}, productListParams...),
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: []model.Product{}},
+		Profiles:  listProfiles[model.Product](),
+		Errors:    []int{http.StatusBadRequest, http.StatusNotAcceptable, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/products", Operation: "adminCreateProduct", Tag: "admin",
+		Summary:   "Create a product",
+		Request:   model.ProductRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/products/{id}", Operation: "adminGetProduct", Tag: "admin",
+		Summary:   "Get a product",
+		Query:     []openapi.Param{currencyParam},
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: model.ProductDetail{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/products/{id}", Operation: "adminUpdateProduct", Tag: "admin",
+		Summary:   "Update a product",
+		Request:   model.ProductRequest{},
+		Responses: map[int]any{http.StatusOK: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/admin/products/{id}", Operation: "adminDeleteProduct", Tag: "admin",
+		Summary:   "Delete a product that has never been ordered",
+		Responses: map[int]any{http.StatusNoContent: nil},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/products/import", Operation: "adminImportProducts", Tag: "admin",
+		Summary: "Import products from a CSV file",
+		Upload:  "file",
+		Responses: map[int]any{
+			http.StatusOK:          handler.BatchResponse{},
+			http.StatusMultiStatus: handler.BatchResponse{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/products/archive", Operation: "archiveProducts", Tag: "admin",
+		Summary: "Archive products",
+		Request: model.ProductArchiveRequest{},
+		Responses: map[int]any{
+			http.StatusOK:       model.ProductArchiveResult{},
+			http.StatusConflict: model.ProductArchiveResult{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/products/{id}/sale", Operation: "setProductSale", Tag: "admin",
+		Summary:   "Schedule a sale price on a product",
+		Request:   model.ProductSale{},
+		Responses: map[int]any{http.StatusOK: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/admin/products/{id}/sale", Operation: "deleteProductSale", Tag: "admin",
+		Summary:   "Remove a product's sale",
+		Responses: map[int]any{http.StatusOK: model.Product{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/orders", Operation: "listOrders", Tag: "orders",
+		Summary:   "List orders, newest first",
+		Query:     orderListParams,
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: []model.Order{}},
+		Profiles:  listProfiles[model.Order](),
+		Errors:    []int{http.StatusBadRequest, http.StatusNotAcceptable, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/orders", Operation: "createOrder", Tag: "orders",
+		Summary: "Place an order",
+		Headers: []openapi.Param{
+			{Name: "Idempotency-Key", Description: "Retries with the same key return the original order"},
+			{Name: "X-On-Behalf-Of", Description: "Customer an admin key places the order for"},
+		},
+		Request: model.OrderRequest{},
+		Responses: map[int]any{
+			http.StatusCreated:  model.OrderResponse{},
+			http.StatusAccepted: model.Operation{},
+		},
+		Errors: []int{
+			http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity,
+			http.StatusInternalServerError, http.StatusServiceUnavailable,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/orders/{id}", Operation: "getOrder", Tag: "orders",
+		Summary:   "Get an order",
+		Query:     []openapi.Param{currencyParam},
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: model.OrderResponse{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/orders/by-ref/{ref}", Operation: "getOrderByRef", Tag: "orders",
+		Summary:   "Get the order created with an Idempotency-Key or imported with a legacy reference",
+		Query:     []openapi.Param{currencyParam},
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: model.OrderResponse{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/orders/{id}/status", Operation: "updateOrderStatus", Tag: "orders",
+		Summary:   "Change an order's status",
+		Request:   model.OrderStatusRequest{},
+		Responses: map[int]any{http.StatusOK: model.Order{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/orders", Operation: "searchOrders", Tag: "admin",
+		Summary: "List the orders containing a product, newest first",
+		Query: append([]openapi.Param{
+			{Name: "productId", Required: true, Description: "Product the orders must contain"},
+		}, orderListParams...),
+		Headers:   acceptCurrencyHeader,
+		Responses: map[int]any{http.StatusOK: []model.Order{}},
+		Profiles:  listProfiles[model.Order](),
+		Errors:    []int{http.StatusBadRequest, http.StatusNotAcceptable, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/orders/{id}/status", Operation: "overrideOrderStatus", Tag: "admin",
+		Summary:   "Move an order to any status outside the order status lifecycle, recording why in the audit log",
+		Request:   model.StatusOverride{},
+		Responses: map[int]any{http.StatusOK: model.Order{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/analytics/orders-by-source", Operation: "countOrdersBySource", Tag: "admin",
+		Summary:   "Count orders per channel",
+		Responses: map[int]any{http.StatusOK: []model.SourceCount{}},
+		Errors:    []int{http.StatusInternalServerError},
+	},
+}
+
+// healthRoutes describes the routes registered by WithHealthHandler.
+var healthRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/ready", Operation: "getReadiness", Tag: "health",
+		Summary: "Report whether the server and its dependencies are ready",
+		Responses: map[int]any{
+			http.StatusOK:                 handler.ReadinessResponse{},
+			http.StatusServiceUnavailable: handler.ReadinessResponse{},
+		},
+		Public: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/health/history", Operation: "getHealthHistory", Tag: "health",
+		Summary:   "Get the recent probe results of each dependency",
+		Responses: map[int]any{http.StatusOK: handler.HealthHistoryResponse{}},
+	},
+}
+
+// priceChangeRoutes describes the routes registered by WithPriceChangeHandler.
+var priceChangeRoutes = []openapi.Route{
+	{
+		Method: http.MethodPut, Path: "/api/admin/products/{id}/price", Operation: "updateProductPrice", Tag: "admin",
+		Summary: "Change a product's price, pending approval above the threshold",
+		Request: model.PriceUpdateRequest{},
+		Responses: map[int]any{
+			http.StatusOK:       model.PriceChange{},
+			http.StatusAccepted: model.PriceChange{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/price-changes", Operation: "listPendingPriceChanges", Tag: "admin",
+		Summary:   "List price changes awaiting approval",
+		Responses: map[int]any{http.StatusOK: []model.PriceChange{}},
+		Errors:    []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/price-changes/{id}/approve", Operation: "approvePriceChange", Tag: "admin",
+		Summary:   "Approve and apply a pending price change",
+		Responses: map[int]any{http.StatusOK: model.PriceChange{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/price-changes/{id}/reject", Operation: "rejectPriceChange", Tag: "admin",
+		Summary:   "Reject a pending price change",
+		Responses: map[int]any{http.StatusOK: model.PriceChange{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+}
+
+// pricingRoutes describes the routes registered by WithPricingHandler.
+var pricingRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/pricing/preview", Operation: "previewPrice", Tag: "pricing",
+		Summary:   "Price a prospective order without placing it",
+		Request:   model.PricingRequest{},
+		Responses: map[int]any{http.StatusOK: model.PriceBreakdown{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/coupons/validate", Operation: "validateCoupon", Tag: "pricing",
+		Summary:   "Check whether a promo code is valid",
+		Request:   model.CouponValidationRequest{},
+		Responses: map[int]any{http.StatusOK: model.CouponValidation{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+}
+
+// shipmentRoutes describes the routes registered by WithShipmentHandler.
+var shipmentRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/orders/{id}/shipments", Operation: "createShipment", Tag: "fulfillment",
+		Summary:   "Ship some or all of an order's items",
+		Request:   model.ShipmentRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Shipment{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/orders/{id}/shipments", Operation: "listShipments", Tag: "fulfillment",
+		Summary:   "List an order's shipments",
+		Responses: map[int]any{http.StatusOK: []model.Shipment{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/orders/{id}/shipments/{shipmentId}/tracking", Operation: "updateShipmentTracking", Tag: "fulfillment",
+		Summary:   "Attach a tracking number to a shipment",
+		Request:   model.TrackingRequest{},
+		Responses: map[int]any{http.StatusOK: model.Shipment{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/orders/{id}/ship", Operation: "shipOrder", Tag: "fulfillment",
+		Summary:   "Ship every unfulfilled item of an order",
+		Request:   model.ShipRemainingRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Shipment{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/orders/{id}/events", Operation: "listOrderEvents", Tag: "fulfillment",
+		Summary:   "List an order's fulfillment events",
+		Responses: map[int]any{http.StatusOK: []model.OrderEvent{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// timelineRoutes describes the routes registered by WithTimelineHandler.
+var timelineRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/admin/orders/{id}/notes", Operation: "addOrderNote", Tag: "admin",
+		Summary:   "Add a support note to an order",
+		Request:   model.OrderNoteRequest{},
+		Responses: map[int]any{http.StatusCreated: model.OrderNote{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/orders/{id}/timeline", Operation: "getOrderTimeline", Tag: "admin",
+		Summary:   "Get an order's history of status changes, shipments and notes",
+		Responses: map[int]any{http.StatusOK: []model.TimelineEntry{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// orderPricingRoutes describes the route registered by WithOrderPricingHandler.
+var orderPricingRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/orders/{id}/recalculate", Operation: "recalculateOrderPricing", Tag: "orders",
+		Summary:   "Reprice an order with current prices",
+		Responses: map[int]any{http.StatusOK: model.OrderRepricing{}},
+		Errors:    []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+}
+
+// upsellRoutes describes the route registered by WithUpsellHandler.
+var upsellRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/orders/{id}/upsell", Operation: "listOrderUpsell", Tag: "orders",
+		Summary: "List best sellers from an order's categories that the order does not contain",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "Maximum number of products to return, up to UPSELL_MAX_LIMIT (default: UPSELL_DEFAULT_LIMIT)"},
+		},
+		Responses: map[int]any{http.StatusOK: []model.UpsellProduct{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+}
+
+// snapshotRoutes describes the route registered by WithSnapshotHandler.
+var snapshotRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/admin/orders/{id}/snapshots", Operation: "createOrderSnapshot", Tag: "admin",
+		Summary:   "Record a signed snapshot of an order",
+		Responses: map[int]any{http.StatusCreated: model.SignedSnapshot{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// orderImportRoutes describes the route registered by WithOrderImportHandler.
+var orderImportRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/admin/orders/import", Operation: "importOrders", Tag: "admin",
+		Summary: "Import historical orders from a CSV or JSON Lines file",
+		Query: []openapi.Param{
+			{Name: "format", Enum: []string{"csv", "jsonl"}, Description: "Format of the uploaded file (default: csv)"},
+		},
+		Upload: "file",
+		Responses: map[int]any{
+			http.StatusOK:          handler.BatchResponse{},
+			http.StatusMultiStatus: handler.BatchResponse{},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+}
+
+// slaRoutes describes the route registered by WithSLAHandler.
+var slaRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/orders/at-risk", Operation: "listAtRiskOrders", Tag: "admin",
+		Summary: "List orders close to or past their status's SLA",
+		Query: []openapi.Param{
+			{Name: "status", Enum: []string{"pending", "confirmed", "cancelled", "fulfilled"}},
+			limitParam,
+		},
+		Responses: map[int]any{http.StatusOK: []model.AtRiskOrder{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+}
+
+// operationRoutes describes the route registered by WithOperationHandler.
+var operationRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/operations/{id}", Operation: "getOperation", Tag: "orders",
+		Summary:   "Poll an order accepted for asynchronous creation",
+		Responses: map[int]any{http.StatusOK: model.Operation{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// metricsRoutes describes the routes registered by WithMetricsHandler.
+var metricsRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/metrics", Operation: "getMetrics", Tag: "admin",
+		Summary:   "Get the operational counters",
+		Responses: map[int]any{http.StatusOK: []metrics.Sample{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/metrics/caches", Operation: "getCacheStats", Tag: "admin",
+		Summary:   "Get the hit rate of each cache",
+		Responses: map[int]any{http.StatusOK: []metrics.CacheStats{}},
+	},
+}
+
+// logLevelRoutes describes the routes registered by WithLogLevelHandler.
+var logLevelRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/log-level", Operation: "getLogLevel", Tag: "admin",
+		Summary:   "Get the minimum level the service logs",
+		Responses: map[int]any{http.StatusOK: handler.LogLevelResponse{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/log-level", Operation: "setLogLevel", Tag: "admin",
+		Summary:   "Change the minimum level the service logs until it restarts",
+		Request:   handler.LogLevelRequest{},
+		Responses: map[int]any{http.StatusOK: handler.LogLevelResponse{}},
+		Errors:    []int{http.StatusBadRequest},
+	},
+}
+
+// tenantRoutes describes the routes registered by WithTenantHandler.
+var tenantRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/tenants", Operation: "listTenants", Tag: "admin",
+		Summary:   "List the storefront tenants hosted on the deployment",
+		Responses: map[int]any{http.StatusOK: []model.Tenant{}},
+		Errors:    []int{http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/tenants", Operation: "createTenant", Tag: "admin",
+		Summary:   "Create a tenant",
+		Request:   model.TenantRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Tenant{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/tenants/{id}", Operation: "getTenant", Tag: "admin",
+		Summary:   "Get a tenant",
+		Responses: map[int]any{http.StatusOK: model.Tenant{}},
+		Errors:    []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/tenants/{id}", Operation: "renameTenant", Tag: "admin",
+		Summary:   "Rename a tenant",
+		Request:   model.TenantRequest{},
+		Responses: map[int]any{http.StatusOK: model.Tenant{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// flashSaleRoutes describes the routes registered by WithFlashSaleHandler.
+var flashSaleRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/flash-sales", Operation: "listFlashSales", Tag: "admin",
+		Summary:   "List flash sales with their sold and pending quantities",
+		Responses: map[int]any{http.StatusOK: []model.FlashSale{}},
+		Errors:    []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/flash-sales/{productId}", Operation: "setFlashSale", Tag: "admin",
+		Summary:   "Allocate quantity of a product to its flash sale",
+		Request:   model.FlashSaleRequest{},
+		Responses: map[int]any{http.StatusOK: model.FlashSale{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/admin/flash-sales/{productId}", Operation: "endFlashSale", Tag: "admin",
+		Summary:   "End a product's flash sale",
+		Responses: map[int]any{http.StatusNoContent: nil},
+		Errors:    []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// productImageRoutes describes the routes registered by WithProductImageHandler.
+var productImageRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/admin/products/{id}/images/uploads", Operation: "createProductImageUpload", Tag: "admin",
+		Summary:   "Get a presigned URL to upload a product image file to",
+		Request:   model.ImageUploadRequest{},
+		Responses: map[int]any{http.StatusCreated: model.ImageUpload{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/products/{id}/images", Operation: "listProductImages", Tag: "admin",
+		Summary:   "List a product's images in display order",
+		Responses: map[int]any{http.StatusOK: []model.ProductImage{}},
+		Errors:    []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/products/{id}/images", Operation: "attachProductImage", Tag: "admin",
+		Summary:   "Attach an uploaded image after the product's other images",
+		Request:   model.ProductImageRequest{},
+		Responses: map[int]any{http.StatusCreated: model.ProductImage{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/admin/products/{id}/images/order", Operation: "reorderProductImages", Tag: "admin",
+		Summary:   "Set the display order of a product's images",
+		Request:   model.ImageOrderRequest{},
+		Responses: map[int]any{http.StatusOK: []model.ProductImage{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/admin/products/{id}/images/{imageId}", Operation: "deleteProductImage", Tag: "admin",
+		Summary:   "Remove an image from a product and delete its file",
+		Responses: map[int]any{http.StatusNoContent: nil},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+// couponFileRoutes describes the routes registered by WithCouponFileHandler.
+var couponFileRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/coupon-files", Operation: "listCouponFiles", Tag: "admin",
+		Summary: "List registered coupon file uploads, newest first",
+		Query: []openapi.Param{
+			{Name: "name", Description: "Only list uploads of this coupon set"},
+		},
+		Responses: map[int]any{http.StatusOK: []model.CouponFile{}},
+		Errors:    []int{http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/coupon-files", Operation: "registerCouponFile", Tag: "admin",
+		Summary:   "Register an uploaded coupon file by its SHA-256 checksum",
+		Request:   model.CouponFileRequest{},
+		Responses: map[int]any{http.StatusCreated: model.CouponFile{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/coupon-files/generations", Operation: "listCouponGenerations", Tag: "admin",
+		Summary: "List the coupon files validations were served from, traced to their uploads",
+		Query: []openapi.Param{
+			{Name: "date", Description: "UTC day (YYYY-MM-DD) to list every generation in use during"},
+			{Name: "at", Description: "RFC 3339 time to list the generations in use at; defaults to now"},
+		},
+		Responses: map[int]any{http.StatusOK: handler.CouponGenerationsResponse{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/admin/coupon-files/validity", Operation: "checkCouponValidityAt", Tag: "admin",
+		Summary: "Check whether a promo code was valid at a past time, against archived coupon files",
+		Query: []openapi.Param{
+			{Name: "code", Description: "Promo code to check", Required: true},
+			{Name: "at", Description: "RFC 3339 time to check the code at", Required: true},
+		},
+		Responses: map[int]any{http.StatusOK: model.CouponValidityAt{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError},
+	},
+}
+
+// customerRoutes describes the routes registered by WithCustomerHandler.
+var customerRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/api/customers", Operation: "registerCustomer", Tag: "customers",
+		Summary:   "Register a customer",
+		Request:   model.CustomerRequest{},
+		Responses: map[int]any{http.StatusCreated: model.Customer{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/customers", Operation: "findCustomer", Tag: "customers",
+		Summary: "Look up a customer by email",
+		Query: []openapi.Param{
+			{Name: "email", Description: "Email address the customer registered with, in any case", Required: true},
+		},
+		Responses: map[int]any{http.StatusOK: model.Customer{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/customers/{id}", Operation: "getCustomer", Tag: "customers",
+		Summary:   "Get a customer",
+		Responses: map[int]any{http.StatusOK: model.Customer{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/customers/{id}/orders", Operation: "listCustomerOrders", Tag: "customers",
+		Summary:   "List a customer's orders, newest first",
+		Query:     []openapi.Param{limitParam, offsetParam},
+		Responses: map[int]any{http.StatusOK: []model.Order{}},
+		Profiles:  listProfiles[model.Order](),
+		Errors:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+}
+
+// adminRoutes describes the dashboard data route registered by
+// WithAdminHandler. The dashboard page itself is not part of the API.
+var adminRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/dashboard", Operation: "getDashboard", Tag: "admin",
+		Summary:   "Get the admin dashboard's summary",
+		Responses: map[int]any{http.StatusOK: handler.DashboardResponse{}},
+		Errors:    []int{http.StatusInternalServerError},
+	},
+}
+
+// couponReloadRoutes describes the routes registered by WithCouponReloadHandler.
+var couponReloadRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/coupons", Operation: "getCouponStatus", Tag: "admin",
+		Summary:   "Get the loaded coupon data and whether a reload is running",
+		Responses: map[int]any{http.StatusOK: handler.CouponReloadStatus{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/admin/coupons/reload", Operation: "reloadCoupons", Tag: "admin",
+		Summary:   "Reload the coupon files in the background",
+		Responses: map[int]any{http.StatusAccepted: handler.CouponReloadStatus{}},
+		Errors:    []int{http.StatusConflict},
+	},
+}
+
+// configRoutes describes the route registered by WithConfigHandler.
+var configRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/api/admin/config", Operation: "getConfig", Tag: "admin",
+		Summary:   "Get the configuration the service was started with, with secrets redacted",
+		Responses: map[int]any{http.StatusOK: map[string]any{}},
+	},
+}
+
+// cartRoutes describes the routes registered by WithCartHandler.
+var cartRoutes = []openapi.Route{
+	{
+		Method: http.MethodPost, Path: "/public/cart", Operation: "signCart", Tag: "pricing",
+		Summary:   "Sign a storefront cart into a token to submit at checkout",
+		Request:   model.CartRequest{},
+		Responses: map[int]any{http.StatusOK: model.SignedCart{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		Public:    true,
+	},
+	{
+		Method: http.MethodGet, Path: "/public/cart", Operation: "getCart", Tag: "pricing",
+		Summary:   "Restore a signed storefront cart at current prices",
+		Query:     []openapi.Param{{Name: "token", Description: "Cart token from signCart", Required: true}},
+		Responses: map[int]any{http.StatusOK: model.SignedCart{}},
+		Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		Public:    true,
+	},
+}
+
+// publicRoutes describes the route registered by WithPublicHandler.
+var publicRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/public/products", Operation: "listPublicProducts", Tag: "products",
+		Summary:   "List the public catalogue",
+		Query:     productListParams,
+		Responses: map[int]any{http.StatusOK: []model.PublicProduct{}, http.StatusNotModified: nil},
+		Errors:    []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		Public:    true,
+	},
+}
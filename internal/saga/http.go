@@ -0,0 +1,199 @@
+package saga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"mini-kart/internal/model"
+)
+
+// maxResponseBytes caps the size of a step response kept for compensation.
+const maxResponseBytes = 64 << 10
+
+// Step names.
+const (
+	StepPayment = "payment"
+	StepStock   = "stock"
+	StepCoupon  = "coupon"
+)
+
+// httpStep implements Step with a service keeping one resource per order:
+// Execute puts the step's request at the endpoint followed by the order ID,
+// and Compensate deletes it. Both are idempotent by construction.
+type httpStep struct {
+	name     string
+	endpoint string
+	client   *http.Client
+	request  func(payload model.OrderSagaPayload) (any, error)
+}
+
+// NewPaymentStep creates a step authorising the order's total with a payment
+// service, e.g. PUT {endpoint}/{orderId} with {"orderId", "amount",
+// "currency", "customerId"}. Orders without a total are skipped.
+func NewPaymentStep(endpoint string, client *http.Client) Step {
+	return &httpStep{
+		name:     StepPayment,
+		endpoint: endpoint,
+		client:   client,
+		request: func(p model.OrderSagaPayload) (any, error) {
+			if p.Total == nil {
+				return nil, ErrSkip
+			}
+			return map[string]any{
+				"orderId":    p.OrderID,
+				"amount":     *p.Total,
+				"currency":   p.Currency,
+				"customerId": p.CustomerID,
+			}, nil
+		},
+	}
+}
+
+// NewStockStep creates a step reserving the order's items in a warehouse
+// management system, e.g. PUT {endpoint}/{orderId} with {"orderId", "items"}.
+func NewStockStep(endpoint string, client *http.Client) Step {
+	return &httpStep{
+		name:     StepStock,
+		endpoint: endpoint,
+		client:   client,
+		request: func(p model.OrderSagaPayload) (any, error) {
+			return map[string]any{
+				"orderId": p.OrderID,
+				"items":   p.Items,
+			}, nil
+		},
+	}
+}
+
+// NewCouponStep creates a step redeeming the order's coupon code with a
+// promotions service, e.g. PUT {endpoint}/{orderId} with {"orderId", "code",
+// "customerId"}. Orders without a coupon code are skipped.
+func NewCouponStep(endpoint string, client *http.Client) Step {
+	return &httpStep{
+		name:     StepCoupon,
+		endpoint: endpoint,
+		client:   client,
+		request: func(p model.OrderSagaPayload) (any, error) {
+			if p.CouponCode == nil || *p.CouponCode == "" {
+				return nil, ErrSkip
+			}
+			return map[string]any{
+				"orderId":    p.OrderID,
+				"code":       *p.CouponCode,
+				"customerId": p.CustomerID,
+			}, nil
+		},
+	}
+}
+
+// Name identifies the step in saga records.
+func (s *httpStep) Name() string {
+	return s.name
+}
+
+// Execute puts the step's request for the order. 4xx responses other than
+// 408 and 429 mean the service refused the step and are reported as a
+// *RejectedError; other failures leave the step's outcome unknown.
+func (s *httpStep) Execute(ctx context.Context, saga *model.Saga) (json.RawMessage, error) {
+	var payload model.OrderSagaPayload
+	if err := json.Unmarshal(saga.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode saga payload: %w", err)
+	}
+
+	request, err := s.request(payload)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", s.name, err)
+	}
+
+	status, response, err := s.send(ctx, http.MethodPut, saga.OrderID.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case status >= 200 && status < 300:
+		if !json.Valid(response) {
+			return nil, nil
+		}
+		return response, nil
+	case status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+		return nil, &RejectedError{Step: s.name, Reason: rejectionReason(status, response)}
+	default:
+		return nil, fmt.Errorf("%s service responded with status %d", s.name, status)
+	}
+}
+
+// Compensate deletes the step's resource for the order. A 404 means there
+// is nothing to undo.
+func (s *httpStep) Compensate(ctx context.Context, saga *model.Saga, result json.RawMessage) error {
+	status, _, err := s.send(ctx, http.MethodDelete, saga.OrderID.String(), nil)
+	if err != nil {
+		return err
+	}
+	if (status < 200 || status >= 300) && status != http.StatusNotFound {
+		return fmt.Errorf("%s service responded with status %d", s.name, status)
+	}
+	return nil
+}
+
+// send sends a request for the order's resource, returning the response
+// status and up to maxResponseBytes of its body.
+func (s *httpStep) send(ctx context.Context, method, orderID string, body []byte) (int, []byte, error) {
+	endpoint, err := url.JoinPath(s.endpoint, orderID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid %s endpoint: %w", s.name, err)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create %s request: %w", s.name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to send %s request: %w", s.name, err)
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read %s response: %w", s.name, err)
+	}
+
+	return resp.StatusCode, response, nil
+}
+
+// rejectionReason returns the message of a rejection response, falling back
+// to its status.
+func rejectionReason(status int, body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil {
+		if response.Message != "" {
+			return response.Message
+		}
+		if response.Error != "" {
+			return response.Error
+		}
+	}
+	return fmt.Sprintf("status %d", status)
+}
@@ -0,0 +1,167 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderSaga(t *testing.T, payload model.OrderSagaPayload) *model.Saga {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return &model.Saga{ID: uuid.New(), OrderID: payload.OrderID, Payload: data}
+}
+
+func TestHTTPStep_Execute(t *testing.T) {
+	total := model.NewMoney(18.5)
+	code := "HAPPYHRS"
+	payload := model.OrderSagaPayload{
+		OrderID:    uuid.New(),
+		CouponCode: &code,
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+		Total:      &total,
+		Currency:   "AUD",
+	}
+
+	tests := []struct {
+		name           string
+		step           func(endpoint string, client *http.Client) Step
+		status         int
+		response       string
+		expectedBody   string
+		expectedResult string
+		expectedErr    string
+		rejected       bool
+	}{
+		{
+			name:           "Payment authorised",
+			step:           NewPaymentStep,
+			status:         http.StatusCreated,
+			response:       `{"authorisationId":"auth_1"}`,
+			expectedBody:   `{"orderId":"` + payload.OrderID.String() + `","amount":18.5,"currency":"AUD","customerId":null}`,
+			expectedResult: `{"authorisationId":"auth_1"}`,
+		},
+		{
+			name:         "Stock reserved without a response body",
+			step:         NewStockStep,
+			status:       http.StatusNoContent,
+			expectedBody: `{"orderId":"` + payload.OrderID.String() + `","items":[{"productId":"P001","quantity":2}]}`,
+		},
+		{
+			name:         "Payment declined",
+			step:         NewPaymentStep,
+			status:       http.StatusPaymentRequired,
+			response:     `{"message":"card declined"}`,
+			expectedBody: `{"orderId":"` + payload.OrderID.String() + `","amount":18.5,"currency":"AUD","customerId":null}`,
+			expectedErr:  "payment rejected: card declined",
+			rejected:     true,
+		},
+		{
+			name:         "Coupon rejected without a message",
+			step:         NewCouponStep,
+			status:       http.StatusConflict,
+			expectedBody: `{"orderId":"` + payload.OrderID.String() + `","code":"HAPPYHRS","customerId":null}`,
+			expectedErr:  "coupon rejected: status 409",
+			rejected:     true,
+		},
+		{
+			name:         "Rate limited",
+			step:         NewStockStep,
+			status:       http.StatusTooManyRequests,
+			expectedBody: `{"orderId":"` + payload.OrderID.String() + `","items":[{"productId":"P001","quantity":2}]}`,
+			expectedErr:  "stock service responded with status 429",
+		},
+		{
+			name:         "Server error",
+			step:         NewStockStep,
+			status:       http.StatusBadGateway,
+			expectedBody: `{"orderId":"` + payload.OrderID.String() + `","items":[{"productId":"P001","quantity":2}]}`,
+			expectedErr:  "stock service responded with status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/v1/holds/"+payload.OrderID.String(), r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			step := tt.step(server.URL+"/v1/holds", server.Client())
+			result, err := step.Execute(context.Background(), newOrderSaga(t, payload))
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, tt.expectedErr)
+				var rejected *RejectedError
+				assert.Equal(t, tt.rejected, errors.As(err, &rejected))
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedResult == "" {
+				assert.Nil(t, result)
+			} else {
+				assert.JSONEq(t, tt.expectedResult, string(result))
+			}
+		})
+	}
+}
+
+func TestHTTPStep_Skip(t *testing.T) {
+	step := NewCouponStep("http://promotions.invalid/redemptions", http.DefaultClient)
+	_, err := step.Execute(context.Background(), newOrderSaga(t, model.OrderSagaPayload{OrderID: uuid.New()}))
+	assert.ErrorIs(t, err, ErrSkip)
+
+	step = NewPaymentStep("http://payments.invalid/authorisations", http.DefaultClient)
+	_, err = step.Execute(context.Background(), newOrderSaga(t, model.OrderSagaPayload{OrderID: uuid.New()}))
+	assert.ErrorIs(t, err, ErrSkip)
+}
+
+func TestHTTPStep_Compensate(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr string
+	}{
+		{name: "Deleted", status: http.StatusNoContent},
+		{name: "Nothing to undo", status: http.StatusNotFound},
+		{name: "Server error", status: http.StatusServiceUnavailable, expectedErr: "payment service responded with status 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderID := uuid.New()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/authorisations/"+orderID.String(), r.URL.Path)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			step := NewPaymentStep(server.URL+"/authorisations/", server.Client())
+			err := step.Compensate(context.Background(), newOrderSaga(t, model.OrderSagaPayload{OrderID: orderID}), nil)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
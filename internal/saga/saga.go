@@ -0,0 +1,363 @@
+// Package saga coordinates the side effects order creation has on external
+// systems, such as authorising a payment or reserving stock in a warehouse,
+// and undoes them when the order is not stored.
+//
+// Each step is recorded before and after it runs, so the steps to undo are
+// known even if the process stops midway. A saga is completed in the
+// transaction that stores its order; sagas neither completed nor compensated
+// by their timeout are compensated by the recovery loop.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// recordTimeout bounds recording a saga's progress, which continues when the
+// request that started it is cancelled so its side effects are not lost.
+const recordTimeout = 5 * time.Second
+
+// ErrSkip is returned by a step with nothing to do for a saga, e.g. the
+// coupon step of an order without a coupon.
+var ErrSkip = errors.New("step skipped")
+
+// errCompleted is returned when recording the progress of a saga that was
+// completed with its order, which must not be compensated.
+var errCompleted = errors.New("saga already completed")
+
+// ErrTimedOut is returned by Complete when the saga timed out and is being
+// compensated by the recovery loop, so its order must not be stored.
+var ErrTimedOut = errors.New("saga timed out")
+
+// Step is one side effect of order creation on an external system.
+// Execute and Compensate must be idempotent: either may be repeated when a
+// response is lost, and a step whose outcome is unknown is compensated even
+// if it never took effect.
+type Step interface {
+	// Name identifies the step in saga records.
+	Name() string
+
+	// Execute performs the step for the saga, returning the system's
+	// response to keep for compensation.
+	Execute(ctx context.Context, saga *model.Saga) (json.RawMessage, error)
+
+	// Compensate undoes the step. Result is what Execute returned, or nil
+	// if its outcome is unknown.
+	Compensate(ctx context.Context, saga *model.Saga, result json.RawMessage) error
+}
+
+// RejectedError reports that a step's system refused it, e.g. a declined
+// payment or insufficient stock. A rejected step has nothing to undo.
+type RejectedError struct {
+	Step   string
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s rejected: %s", e.Step, e.Reason)
+}
+
+// Config holds saga coordinator configuration.
+type Config struct {
+	// Timeout is how long a saga may run before the recovery loop assumes
+	// its order was never stored and compensates it. It must exceed the
+	// time order creation takes, steps included.
+	Timeout time.Duration
+
+	// BatchSize is the number of sagas claimed for compensation at once.
+	BatchSize int
+
+	// PollInterval is how often sagas due for compensation are looked for.
+	PollInterval time.Duration
+
+	// MaxAttempts is how many times a saga's compensation is tried before
+	// it is marked failed.
+	MaxAttempts int
+
+	// RetryDelay is the delay before compensation is retried. It doubles
+	// after each failed attempt, up to MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Coordinator runs the steps of order sagas and compensates them. Several
+// coordinators may share the saga table; each saga due for compensation is
+// claimed by one at a time.
+type Coordinator struct {
+	repo   repository.SagaRepository
+	steps  []Step
+	config Config
+	logger zerolog.Logger
+}
+
+// NewCoordinator creates a saga coordinator running steps in order.
+func NewCoordinator(repo repository.SagaRepository, steps []Step, config Config, logger zerolog.Logger) *Coordinator {
+	return &Coordinator{
+		repo:   repo,
+		steps:  steps,
+		config: config,
+		logger: logger.With().Str("component", "saga").Logger(),
+	}
+}
+
+// Begin records a saga for an order and runs its steps. If a step fails the
+// steps already run are compensated and the step's error is returned, a
+// *RejectedError if its system refused it.
+func (c *Coordinator) Begin(ctx context.Context, payload model.OrderSagaPayload) (*model.Saga, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode saga payload: %w", err)
+	}
+
+	saga := &model.Saga{
+		ID:      uuid.New(),
+		OrderID: payload.OrderID,
+		Status:  model.SagaRunning,
+		Payload: data,
+		Steps:   make([]model.SagaStep, 0, len(c.steps)),
+	}
+	if err := c.repo.Create(ctx, saga, c.config.Timeout); err != nil {
+		return nil, err
+	}
+
+	logger := c.sagaLogger(saga)
+	for _, step := range c.steps {
+		if err := c.execute(ctx, saga, step); err != nil {
+			logger.Warn().Err(err).Str("step", step.Name()).Msg("saga step failed, compensating")
+			c.Compensate(ctx, saga, err)
+			return nil, err
+		}
+	}
+
+	logger.Debug().Int("steps", len(saga.Steps)).Msg("saga steps completed")
+
+	return saga, nil
+}
+
+// execute runs one step, recording it as pending first so it is compensated
+// if its outcome is never recorded.
+func (c *Coordinator) execute(ctx context.Context, saga *model.Saga, step Step) error {
+	saga.Steps = append(saga.Steps, model.SagaStep{Name: step.Name(), Status: model.SagaStepPending})
+	record := &saga.Steps[len(saga.Steps)-1]
+	if err := c.save(ctx, saga); err != nil {
+		return err
+	}
+
+	result, err := step.Execute(ctx, saga)
+	var rejected *RejectedError
+	switch {
+	case err == nil:
+		record.Status, record.Result = model.SagaStepCompleted, result
+	case errors.Is(err, ErrSkip):
+		record.Status = model.SagaStepSkipped
+	case errors.As(err, &rejected):
+		record.Status, record.Error = model.SagaStepRejected, err.Error()
+		return err
+	default:
+		// The step may have taken effect before the error, so it stays
+		// pending and is compensated
+		record.Error = err.Error()
+		return err
+	}
+
+	return c.save(ctx, saga)
+}
+
+// Complete marks the saga completed within the transaction storing its
+// order. Returns ErrTimedOut if the recovery loop has begun compensating it.
+func (c *Coordinator) Complete(ctx context.Context, tx pgx.Tx, saga *model.Saga) error {
+	completed, err := c.repo.Complete(ctx, tx, saga.ID)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return ErrTimedOut
+	}
+	saga.Status = model.SagaCompleted
+	return nil
+}
+
+// Compensate undoes the saga's steps, latest first, after its order could
+// not be stored because of cause. Sagas that fail to compensate are retried
+// by the recovery loop.
+func (c *Coordinator) Compensate(ctx context.Context, saga *model.Saga, cause error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.Timeout)
+	defer cancel()
+
+	saga.Status = model.SagaCompensating
+	lastError := cause.Error()
+	saga.LastError = &lastError
+	if err := c.save(ctx, saga); err != nil {
+		if err == errCompleted {
+			// The order was stored although storing it reported an error
+			logger := c.sagaLogger(saga)
+			logger.Warn().Err(cause).Msg("saga completed with its order, not compensating")
+		}
+		// Otherwise the recovery loop compensates the saga once it times out
+		return
+	}
+
+	if err := c.compensate(ctx, saga); err != nil {
+		c.reschedule(ctx, saga, err)
+	}
+}
+
+// compensate undoes every step that completed or may have, latest first, and
+// records the saga compensated once all are undone.
+func (c *Coordinator) compensate(ctx context.Context, saga *model.Saga) error {
+	logger := c.sagaLogger(saga)
+
+	steps := make(map[string]Step, len(c.steps))
+	for _, step := range c.steps {
+		steps[step.Name()] = step
+	}
+
+	for i := len(saga.Steps) - 1; i >= 0; i-- {
+		record := &saga.Steps[i]
+		if record.Status != model.SagaStepCompleted && record.Status != model.SagaStepPending {
+			continue
+		}
+
+		step, ok := steps[record.Name]
+		if !ok {
+			return fmt.Errorf("saga step %s is not configured", record.Name)
+		}
+		if err := step.Compensate(ctx, saga, record.Result); err != nil {
+			return fmt.Errorf("failed to compensate %s: %w", record.Name, err)
+		}
+		record.Status = model.SagaStepCompensated
+		logger.Debug().Str("step", record.Name).Msg("saga step compensated")
+	}
+
+	saga.Status = model.SagaCompensated
+	if err := c.save(ctx, saga); err != nil {
+		return err
+	}
+
+	logger.Info().Msg("saga compensated")
+
+	return nil
+}
+
+// reschedule records a failed compensation attempt, giving up once the saga
+// is out of attempts.
+func (c *Coordinator) reschedule(ctx context.Context, saga *model.Saga, compensateErr error) {
+	logger := c.sagaLogger(saga).With().Int("attempt", saga.Attempts).Logger()
+
+	lastError := compensateErr.Error()
+	saga.LastError = &lastError
+
+	var err error
+	if saga.Attempts >= c.config.MaxAttempts {
+		saga.Status = model.SagaFailed
+		err = c.save(ctx, saga)
+		logger.Error().Err(compensateErr).Msg("saga compensation failed, giving up")
+	} else {
+		delay := c.retryDelay(saga.Attempts)
+		err = c.repo.Reschedule(ctx, saga, delay)
+		logger.Warn().Err(compensateErr).Dur("retry_in", delay).Msg("saga compensation failed, retrying")
+	}
+	if err != nil {
+		// The saga is retried once its lease expires
+		logger.Error().Err(err).Msg("failed to record saga compensation outcome")
+	}
+}
+
+// save records the saga's progress, even if ctx was cancelled. Returns
+// errCompleted if the saga was completed meanwhile.
+func (c *Coordinator) save(ctx context.Context, saga *model.Saga) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	saved, err := c.repo.Save(ctx, saga)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		saga.Status = model.SagaCompleted
+		return errCompleted
+	}
+	return nil
+}
+
+// retryDelay returns the delay after the given failed attempt.
+func (c *Coordinator) retryDelay(attempt int) time.Duration {
+	delay := c.config.RetryDelay
+	for i := 1; i < attempt && delay < c.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, c.config.MaxRetryDelay)
+}
+
+// Run compensates sagas that timed out or failed to compensate every poll
+// interval until ctx is cancelled.
+func (c *Coordinator) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		c.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain compensates batches until no more sagas are due.
+func (c *Coordinator) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := c.RecoverDue(ctx)
+		if err != nil {
+			c.logger.Error().Err(err).Msg("failed to recover sagas")
+			return
+		}
+		if claimed < c.config.BatchSize {
+			return
+		}
+	}
+}
+
+// RecoverDue claims one batch of sagas due for compensation and compensates
+// them concurrently, returning how many were claimed.
+func (c *Coordinator) RecoverDue(ctx context.Context) (int, error) {
+	sagas, err := c.repo.ClaimDue(ctx, c.config.BatchSize, c.config.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, saga := range sagas {
+		wg.Go(func() {
+			logger := c.sagaLogger(&saga)
+			logger.Info().Int("attempt", saga.Attempts).Msg("compensating saga")
+			if err := c.compensate(ctx, &saga); err != nil {
+				c.reschedule(ctx, &saga, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return len(sagas), nil
+}
+
+// sagaLogger returns the logger for messages about a saga.
+func (c *Coordinator) sagaLogger(saga *model.Saga) zerolog.Logger {
+	return c.logger.With().
+		Str("saga_id", saga.ID.String()).
+		Str("order_id", saga.OrderID.String()).
+		Logger()
+}
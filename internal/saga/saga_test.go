@@ -0,0 +1,335 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository is an in-memory saga table.
+type fakeRepository struct {
+	mu          sync.Mutex
+	sagas       map[uuid.UUID]model.Saga
+	due         []model.Saga
+	rescheduled map[uuid.UUID]time.Duration
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		sagas:       make(map[uuid.UUID]model.Saga),
+		rescheduled: make(map[uuid.UUID]time.Duration),
+	}
+}
+
+func (r *fakeRepository) Create(ctx context.Context, saga *model.Saga, timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sagas[saga.ID] = r.copy(saga)
+	return nil
+}
+
+func (r *fakeRepository) Save(ctx context.Context, saga *model.Saga) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sagas[saga.ID].Status == model.SagaCompleted {
+		return false, nil
+	}
+	r.sagas[saga.ID] = r.copy(saga)
+	return true, nil
+}
+
+func (r *fakeRepository) Complete(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saga := r.sagas[id]
+	if saga.Status != model.SagaRunning {
+		return false, nil
+	}
+	saga.Status = model.SagaCompleted
+	r.sagas[id] = saga
+	return true, nil
+}
+
+func (r *fakeRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Saga, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := min(limit, len(r.due))
+	claimed := r.due[:n]
+	r.due = r.due[n:]
+	for i := range claimed {
+		claimed[i].Status = model.SagaCompensating
+		claimed[i].Attempts++
+		r.sagas[claimed[i].ID] = r.copy(&claimed[i])
+	}
+	return claimed, nil
+}
+
+func (r *fakeRepository) Reschedule(ctx context.Context, saga *model.Saga, delay time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saga.Status = model.SagaCompensating
+	r.sagas[saga.ID] = r.copy(saga)
+	r.rescheduled[saga.ID] = delay
+	return nil
+}
+
+// get returns the stored copy of a saga.
+func (r *fakeRepository) get(id uuid.UUID) model.Saga {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sagas[id]
+}
+
+// copy returns saga with its own steps, as a database round trip would.
+func (r *fakeRepository) copy(saga *model.Saga) model.Saga {
+	stored := *saga
+	stored.Steps = slices.Clone(saga.Steps)
+	return stored
+}
+
+// fakeStep records the calls made to it in a shared log.
+type fakeStep struct {
+	name          string
+	log           *[]string
+	executeErr    error
+	compensateErr error
+}
+
+func (s *fakeStep) Name() string {
+	return s.name
+}
+
+func (s *fakeStep) Execute(ctx context.Context, saga *model.Saga) (json.RawMessage, error) {
+	*s.log = append(*s.log, "execute "+s.name)
+	if s.executeErr != nil {
+		return nil, s.executeErr
+	}
+	return json.RawMessage(`{"id":"` + s.name + `-1"}`), nil
+}
+
+func (s *fakeStep) Compensate(ctx context.Context, saga *model.Saga, result json.RawMessage) error {
+	*s.log = append(*s.log, "compensate "+s.name)
+	return s.compensateErr
+}
+
+func testConfig() Config {
+	return Config{
+		Timeout:       time.Minute,
+		BatchSize:     10,
+		PollInterval:  time.Second,
+		MaxAttempts:   3,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: 4 * time.Second,
+	}
+}
+
+func stepStatuses(saga model.Saga) []model.SagaStepStatus {
+	statuses := make([]model.SagaStepStatus, len(saga.Steps))
+	for i, step := range saga.Steps {
+		statuses[i] = step.Status
+	}
+	return statuses
+}
+
+func TestCoordinator_Begin(t *testing.T) {
+	ctx := context.Background()
+	payload := model.OrderSagaPayload{OrderID: uuid.New(), Items: []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}}}
+
+	t.Run("Runs steps in order", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log},
+			&fakeStep{name: "coupon", log: &log, executeErr: ErrSkip},
+			&fakeStep{name: "stock", log: &log},
+		}, testConfig(), zerolog.Nop())
+
+		saga, err := c.Begin(ctx, payload)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"execute payment", "execute coupon", "execute stock"}, log)
+		stored := repo.get(saga.ID)
+		assert.Equal(t, payload.OrderID, stored.OrderID)
+		assert.Equal(t, model.SagaRunning, stored.Status)
+		assert.Equal(t, []model.SagaStepStatus{model.SagaStepCompleted, model.SagaStepSkipped, model.SagaStepCompleted}, stepStatuses(stored))
+		assert.JSONEq(t, `{"id":"payment-1"}`, string(stored.Steps[0].Result))
+
+		require.NoError(t, c.Complete(ctx, nil, saga))
+		assert.Equal(t, model.SagaCompleted, repo.get(saga.ID).Status)
+	})
+
+	t.Run("Rejected step compensates earlier steps", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log},
+			&fakeStep{name: "stock", log: &log, executeErr: &RejectedError{Step: "stock", Reason: "P001 is out of stock"}},
+			&fakeStep{name: "coupon", log: &log},
+		}, testConfig(), zerolog.Nop())
+
+		saga, err := c.Begin(ctx, payload)
+
+		var rejected *RejectedError
+		require.ErrorAs(t, err, &rejected)
+		assert.Equal(t, "stock", rejected.Step)
+		assert.Nil(t, saga)
+		assert.Equal(t, []string{"execute payment", "execute stock", "compensate payment"}, log)
+	})
+
+	t.Run("Step with unknown outcome is compensated", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log},
+			&fakeStep{name: "stock", log: &log, executeErr: errors.New("context deadline exceeded")},
+		}, testConfig(), zerolog.Nop())
+
+		_, err := c.Begin(ctx, payload)
+
+		require.Error(t, err)
+		assert.Equal(t, []string{"execute payment", "execute stock", "compensate stock", "compensate payment"}, log)
+		require.Len(t, repo.sagas, 1)
+		for _, stored := range repo.sagas {
+			assert.Equal(t, model.SagaCompensated, stored.Status)
+			assert.Equal(t, []model.SagaStepStatus{model.SagaStepCompensated, model.SagaStepCompensated}, stepStatuses(stored))
+			require.NotNil(t, stored.LastError)
+			assert.Equal(t, "context deadline exceeded", *stored.LastError)
+		}
+	})
+
+	t.Run("Failed compensation is rescheduled", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log, compensateErr: errors.New("status 503")},
+			&fakeStep{name: "stock", log: &log, executeErr: &RejectedError{Step: "stock", Reason: "status 409"}},
+		}, testConfig(), zerolog.Nop())
+
+		_, err := c.Begin(ctx, payload)
+
+		require.Error(t, err)
+		require.Len(t, repo.rescheduled, 1)
+		for id, delay := range repo.rescheduled {
+			assert.Equal(t, time.Second, delay)
+			stored := repo.get(id)
+			assert.Equal(t, model.SagaCompensating, stored.Status)
+			assert.Equal(t, []model.SagaStepStatus{model.SagaStepCompleted, model.SagaStepRejected}, stepStatuses(stored))
+			require.NotNil(t, stored.LastError)
+			assert.Equal(t, "failed to compensate payment: status 503", *stored.LastError)
+		}
+	})
+}
+
+func TestCoordinator_Compensate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Completed saga is not compensated", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{&fakeStep{name: "payment", log: &log}}, testConfig(), zerolog.Nop())
+
+		saga, err := c.Begin(ctx, model.OrderSagaPayload{OrderID: uuid.New()})
+		require.NoError(t, err)
+		require.NoError(t, c.Complete(ctx, nil, saga))
+
+		// e.g. the commit reported an error although it succeeded
+		c.Compensate(ctx, saga, errors.New("connection reset"))
+
+		assert.Equal(t, []string{"execute payment"}, log)
+		assert.Equal(t, model.SagaCompleted, repo.get(saga.ID).Status)
+	})
+
+	t.Run("Timed out saga cannot be completed", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		c := NewCoordinator(repo, []Step{&fakeStep{name: "payment", log: &log}}, testConfig(), zerolog.Nop())
+
+		saga, err := c.Begin(ctx, model.OrderSagaPayload{OrderID: uuid.New()})
+		require.NoError(t, err)
+		repo.due = []model.Saga{repo.get(saga.ID)}
+		_, err = c.RecoverDue(ctx)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, c.Complete(ctx, nil, saga), ErrTimedOut)
+		assert.Equal(t, []string{"execute payment", "compensate payment"}, log)
+	})
+}
+
+func TestCoordinator_RecoverDue(t *testing.T) {
+	ctx := context.Background()
+
+	newSaga := func(attempts int, steps ...model.SagaStep) model.Saga {
+		return model.Saga{ID: uuid.New(), OrderID: uuid.New(), Status: model.SagaRunning, Attempts: attempts, Steps: steps}
+	}
+
+	t.Run("Compensates completed and pending steps", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		saga := newSaga(0,
+			model.SagaStep{Name: "payment", Status: model.SagaStepCompleted},
+			model.SagaStep{Name: "coupon", Status: model.SagaStepSkipped},
+			model.SagaStep{Name: "stock", Status: model.SagaStepPending},
+		)
+		repo.due = []model.Saga{saga}
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log},
+			&fakeStep{name: "coupon", log: &log},
+			&fakeStep{name: "stock", log: &log},
+		}, testConfig(), zerolog.Nop())
+
+		claimed, err := c.RecoverDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, []string{"compensate stock", "compensate payment"}, log)
+		stored := repo.get(saga.ID)
+		assert.Equal(t, model.SagaCompensated, stored.Status)
+		assert.Equal(t, []model.SagaStepStatus{model.SagaStepCompensated, model.SagaStepSkipped, model.SagaStepCompensated}, stepStatuses(stored))
+	})
+
+	t.Run("Backs off and gives up after max attempts", func(t *testing.T) {
+		var log []string
+		repo := newFakeRepository()
+		retried := newSaga(1, model.SagaStep{Name: "payment", Status: model.SagaStepCompleted})
+		exhausted := newSaga(2, model.SagaStep{Name: "payment", Status: model.SagaStepCompleted})
+		repo.due = []model.Saga{retried, exhausted}
+		c := NewCoordinator(repo, []Step{
+			&fakeStep{name: "payment", log: &log, compensateErr: errors.New("status 500")},
+		}, testConfig(), zerolog.Nop())
+
+		_, err := c.RecoverDue(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Second, repo.rescheduled[retried.ID])
+		assert.Equal(t, model.SagaCompensating, repo.get(retried.ID).Status)
+		assert.NotContains(t, repo.rescheduled, exhausted.ID)
+		assert.Equal(t, model.SagaFailed, repo.get(exhausted.ID).Status)
+	})
+
+	t.Run("Unconfigured step is not compensated", func(t *testing.T) {
+		repo := newFakeRepository()
+		saga := newSaga(0, model.SagaStep{Name: "loyalty", Status: model.SagaStepCompleted})
+		repo.due = []model.Saga{saga}
+		c := NewCoordinator(repo, nil, testConfig(), zerolog.Nop())
+
+		_, err := c.RecoverDue(ctx)
+
+		require.NoError(t, err)
+		stored := repo.get(saga.ID)
+		assert.Equal(t, model.SagaCompensating, stored.Status)
+		require.NotNil(t, stored.LastError)
+		assert.Contains(t, *stored.LastError, "loyalty is not configured")
+	})
+}
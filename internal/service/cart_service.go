@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// cartClaims is the signed payload of a cart token.
+type cartClaims struct {
+	TenantID   string                   `json:"tid"`
+	Items      []model.OrderItemRequest `json:"items"`
+	CouponCode *string                  `json:"coupon,omitempty"`
+	ExpiresAt  int64                    `json:"exp"`
+}
+
+// cartService implements CartService. Tokens are the base64url-encoded JSON
+// claims and their HMAC-SHA256, joined by a dot.
+type cartService struct {
+	pricing PricingService
+	secret  []byte
+	ttl     time.Duration
+	now     func() time.Time
+	logger  zerolog.Logger
+}
+
+// NewCartService creates a new cart service signing tokens with secret that
+// are valid for ttl. Carts are priced by pricing.
+func NewCartService(pricing PricingService, secret []byte, ttl time.Duration, logger zerolog.Logger) CartService {
+	return &cartService{
+		pricing: pricing,
+		secret:  secret,
+		ttl:     ttl,
+		now:     time.Now,
+		logger:  logger.With().Str("service", "cart").Logger(),
+	}
+}
+
+// SignCart prices the cart before signing it, so only carts that could be
+// checked out are signed.
+func (s *cartService) SignCart(ctx context.Context, req *model.CartRequest) (*model.SignedCart, error) {
+	if req == nil {
+		return nil, fmt.Errorf("cart request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	cart := &model.Cart{
+		TenantID:  cartTenant(ctx),
+		Items:     req.Items,
+		ExpiresAt: s.now().Add(s.ttl).UTC().Truncate(time.Second),
+	}
+	if req.CouponCode != nil && strings.TrimSpace(*req.CouponCode) != "" {
+		cart.CouponCode = req.CouponCode
+	}
+
+	pricing, err := s.price(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.sign(cart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SignedCart{
+		Token:      token,
+		ExpiresAt:  cart.ExpiresAt,
+		Items:      cart.Items,
+		CouponCode: cart.CouponCode,
+		Pricing:    pricing,
+	}, nil
+}
+
+// GetCart re-prices the cart on every read, since catalogue prices and
+// coupons may have changed since it was signed.
+func (s *cartService) GetCart(ctx context.Context, token string) (*model.SignedCart, error) {
+	cart, err := s.OpenCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := s.price(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SignedCart{
+		Token:      token,
+		ExpiresAt:  cart.ExpiresAt,
+		Items:      cart.Items,
+		CouponCode: cart.CouponCode,
+		Pricing:    pricing,
+	}, nil
+}
+
+// OpenCart checks the signature before decoding the claims, so tampered
+// tokens are never parsed.
+func (s *cartService) OpenCart(ctx context.Context, token string) (*model.Cart, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, model.ErrInvalidCartToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, model.ErrInvalidCartToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(payload)) {
+		s.logger.Warn().Msg("cart token signature mismatch")
+		return nil, model.ErrInvalidCartToken
+	}
+
+	var claims cartClaims
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&claims); err != nil {
+		s.logger.Error().Err(err).Msg("failed to decode signed cart claims")
+		return nil, model.ErrInvalidCartToken
+	}
+
+	// A token is only good for the tenant whose catalogue it was priced in
+	if tenantID := cartTenant(ctx); claims.TenantID != tenantID {
+		s.logger.Warn().Str("cart_tenant_id", claims.TenantID).Str("tenant_id", tenantID).Msg("cart token used for another tenant")
+		return nil, model.ErrInvalidCartToken
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+	if !s.now().Before(expiresAt) {
+		return nil, model.ErrCartExpired
+	}
+
+	return &model.Cart{
+		TenantID:   claims.TenantID,
+		Items:      claims.Items,
+		CouponCode: claims.CouponCode,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// price prices a cart with PricingService.Preview.
+func (s *cartService) price(ctx context.Context, cart *model.Cart) (*model.PriceBreakdown, error) {
+	return s.pricing.Preview(ctx, &model.PricingRequest{
+		Items:      cart.Items,
+		CouponCode: cart.CouponCode,
+	})
+}
+
+// sign encodes a cart into a token.
+func (s *cartService) sign(cart *model.Cart) (string, error) {
+	payload, err := json.Marshal(cartClaims{
+		TenantID:   cart.TenantID,
+		Items:      cart.Items,
+		CouponCode: cart.CouponCode,
+		ExpiresAt:  cart.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to encode cart claims")
+		return "", fmt.Errorf("failed to encode cart claims: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload)), nil
+}
+
+// mac returns the HMAC-SHA256 of payload.
+func (s *cartService) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cartTenant returns the tenant carts are signed for in ctx.
+func cartTenant(ctx context.Context) string {
+	if tenantID, ok := model.TenantFromContext(ctx); ok {
+		return tenantID
+	}
+	return model.DefaultTenant
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPricingService is a mock implementation of PricingService.
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) Preview(ctx context.Context, req *model.PricingRequest) (*model.PriceBreakdown, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceBreakdown), args.Error(1)
+}
+
+func (m *MockPricingService) PreviewProductCoupon(ctx context.Context, product *model.Product, code string) (*model.ProductCouponPreview, error) {
+	args := m.Called(ctx, product, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductCouponPreview), args.Error(1)
+}
+
+func (m *MockPricingService) ValidateCoupon(ctx context.Context, code string) (*model.CouponValidation, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponValidation), args.Error(1)
+}
+
+// testCartService returns a cart service whose clock reads now.
+func testCartService(pricing PricingService, now time.Time) *cartService {
+	svc := NewCartService(pricing, []byte("cart-secret"), 24*time.Hour, zerolog.Nop()).(*cartService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestCartService_SignCart(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	code := "SUMMER2025"
+	items := []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}}
+
+	t.Run("Signed cart opens for its tenant", func(t *testing.T) {
+		pricing := new(MockPricingService)
+		pricing.On("Preview", mock.Anything, &model.PricingRequest{Items: items, CouponCode: &code}).
+			Return(&model.PriceBreakdown{Total: model.NewMoney(18.00)}, nil)
+		svc := testCartService(pricing, now)
+		ctx := model.WithTenant(context.Background(), "acme")
+
+		cart, err := svc.SignCart(ctx, &model.CartRequest{Items: items, CouponCode: &code})
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(24*time.Hour), cart.ExpiresAt)
+		assert.Equal(t, model.NewMoney(18.00), cart.Pricing.Total)
+
+		opened, err := svc.OpenCart(ctx, cart.Token)
+		require.NoError(t, err)
+		assert.Equal(t, items, opened.Items)
+		assert.Equal(t, &code, opened.CouponCode)
+		assert.Equal(t, "acme", opened.TenantID)
+	})
+
+	t.Run("Invalid cart", func(t *testing.T) {
+		pricing := new(MockPricingService)
+		svc := testCartService(pricing, now)
+
+		for _, req := range []*model.CartRequest{
+			{},
+			{Items: []model.OrderItemRequest{{ProductID: "P001", Quantity: 0}}},
+			{Items: []model.OrderItemRequest{{ProductID: " ", Quantity: 1}}},
+			{Items: make([]model.OrderItemRequest, model.MaxCartItems+1)},
+		} {
+			_, err := svc.SignCart(context.Background(), req)
+			assert.Equal(t, model.ErrInvalidCart, err)
+		}
+		pricing.AssertNotCalled(t, "Preview", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cart that cannot be priced is not signed", func(t *testing.T) {
+		pricing := new(MockPricingService)
+		pricing.On("Preview", mock.Anything, mock.Anything).Return(nil, model.ErrProductNotFound)
+		svc := testCartService(pricing, now)
+
+		cart, err := svc.SignCart(context.Background(), &model.CartRequest{Items: items})
+		assert.Equal(t, model.ErrProductNotFound, err)
+		assert.Nil(t, cart)
+	})
+}
+
+func TestCartService_OpenCart(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}}
+
+	pricing := new(MockPricingService)
+	pricing.On("Preview", mock.Anything, mock.Anything).Return(&model.PriceBreakdown{}, nil)
+	ctx := model.WithTenant(context.Background(), "acme")
+	signed, err := testCartService(pricing, now).SignCart(ctx, &model.CartRequest{Items: items})
+	require.NoError(t, err)
+
+	payload, signature, _ := strings.Cut(signed.Token, ".")
+	claims, err := base64.RawURLEncoding.DecodeString(payload)
+	require.NoError(t, err)
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(claims), `"quantity":2`, `"quantity":200`, 1))) + "." + signature
+
+	tests := []struct {
+		name          string
+		token         string
+		ctx           context.Context
+		now           time.Time
+		expectedError error
+	}{
+		{name: "Valid token", token: signed.Token, ctx: ctx, now: now},
+		{name: "Tampered items", token: tampered, ctx: ctx, now: now, expectedError: model.ErrInvalidCartToken},
+		{name: "Malformed token", token: "not-a-token", ctx: ctx, now: now, expectedError: model.ErrInvalidCartToken},
+		{name: "Other tenant", token: signed.Token, ctx: model.WithTenant(context.Background(), "globex"), now: now, expectedError: model.ErrInvalidCartToken},
+		{name: "Expired", token: signed.Token, ctx: ctx, now: now.Add(24 * time.Hour), expectedError: model.ErrCartExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart, err := testCartService(pricing, tt.now).OpenCart(tt.ctx, tt.token)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, cart)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, items, cart.Items)
+			}
+		})
+	}
+
+	t.Run("Token signed with another secret", func(t *testing.T) {
+		other := NewCartService(pricing, []byte("other-secret"), time.Hour, zerolog.Nop())
+		_, err := other.OpenCart(ctx, signed.Token)
+		assert.Equal(t, model.ErrInvalidCartToken, err)
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// couponFileService implements CouponFileService.
+type couponFileService struct {
+	repo    repository.CouponFileRepository
+	history coupon.HistoryChecker
+	logger  zerolog.Logger
+}
+
+// NewCouponFileService creates a new coupon file registry service. Past
+// validity is checked by history against the files recorded in the registry.
+func NewCouponFileService(repo repository.CouponFileRepository, history coupon.HistoryChecker, logger zerolog.Logger) CouponFileService {
+	return &couponFileService{
+		repo:    repo,
+		history: history,
+		logger:  logger.With().Str("service", "coupon_file").Logger(),
+	}
+}
+
+// RegisterFile validates and records an uploaded coupon file. The file is
+// attributed to the registering admin unless the request names its uploader.
+func (s *couponFileService) RegisterFile(ctx context.Context, req *model.CouponFileRequest, registeredBy string) (*model.CouponFile, error) {
+	if req == nil {
+		return nil, fmt.Errorf("coupon file request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	file := &model.CouponFile{
+		Name:         req.Name,
+		Source:       req.Source,
+		Checksum:     req.Checksum,
+		SizeBytes:    req.SizeBytes,
+		UploadedBy:   req.UploadedBy,
+		UploadedAt:   time.Now().UTC(),
+		RegisteredBy: registeredBy,
+	}
+	if file.UploadedBy == "" {
+		file.UploadedBy = registeredBy
+	}
+	if req.UploadedAt != nil {
+		file.UploadedAt = req.UploadedAt.UTC()
+	}
+
+	if err := s.repo.Register(ctx, file); err != nil {
+		if err == model.ErrCouponFileExists {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("name", req.Name).Msg("failed to register coupon file")
+		return nil, fmt.Errorf("failed to register coupon file: %w", err)
+	}
+
+	return file, nil
+}
+
+// ListFiles retrieves registered coupon files, newest upload first.
+func (s *couponFileService) ListFiles(ctx context.Context, name string) ([]model.CouponFile, error) {
+	files, err := s.repo.List(ctx, name)
+	if err != nil {
+		s.logger.Error().Err(err).Str("name", name).Msg("failed to list coupon files")
+		return nil, fmt.Errorf("failed to list coupon files: %w", err)
+	}
+	return files, nil
+}
+
+// Generations retrieves the generations serving validations between from and to.
+func (s *couponFileService) Generations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error) {
+	generations, err := s.repo.ListGenerations(ctx, from, to)
+	if err != nil {
+		s.logger.Error().Err(err).Time("from", from).Time("to", to).Msg("failed to list coupon generations")
+		return nil, fmt.Errorf("failed to list coupon generations: %w", err)
+	}
+	return generations, nil
+}
+
+// ValidityAt checks whether a promo code was valid at the given time in the
+// generations serving validations then.
+func (s *couponFileService) ValidityAt(ctx context.Context, promoCode string, at time.Time) (*model.CouponValidityAt, error) {
+	generations, err := s.Generations(ctx, at, at)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.history.CheckAt(ctx, promoCode, at, generations)
+	if err != nil {
+		s.logger.Error().Err(err).Time("at", at).Msg("failed to check past coupon validity")
+		return nil, fmt.Errorf("failed to check past coupon validity: %w", err)
+	}
+
+	s.logger.Info().
+		Time("at", at).
+		Str("verdict", result.Verdict).
+		Int("generations", len(generations)).
+		Msg("past coupon validity checked")
+
+	return result, nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCouponFileRepository is a mock implementation of CouponFileRepository.
+type MockCouponFileRepository struct {
+	mock.Mock
+}
+
+func (m *MockCouponFileRepository) Register(ctx context.Context, file *model.CouponFile) error {
+	args := m.Called(ctx, file)
+	return args.Error(0)
+}
+
+func (m *MockCouponFileRepository) List(ctx context.Context, name string) ([]model.CouponFile, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CouponFile), args.Error(1)
+}
+
+func (m *MockCouponFileRepository) RecordGeneration(ctx context.Context, generation *model.CouponGeneration) error {
+	args := m.Called(ctx, generation)
+	return args.Error(0)
+}
+
+func (m *MockCouponFileRepository) RetireGeneration(ctx context.Context, id int64, retiredAt time.Time) error {
+	args := m.Called(ctx, id, retiredAt)
+	return args.Error(0)
+}
+
+func (m *MockCouponFileRepository) ListGenerations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CouponGeneration), args.Error(1)
+}
+
+// MockHistoryChecker is a mock implementation of coupon.HistoryChecker.
+type MockHistoryChecker struct {
+	mock.Mock
+}
+
+func (m *MockHistoryChecker) CheckAt(ctx context.Context, promoCode string, at time.Time, generations []model.CouponGeneration) (*model.CouponValidityAt, error) {
+	args := m.Called(ctx, promoCode, at, generations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponValidityAt), args.Error(1)
+}
+
+func TestCouponFileService_RegisterFile(t *testing.T) {
+	checksum := strings.Repeat("0f", 32)
+	uploadedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		req            *model.CouponFileRequest
+		setupMock      func(*MockCouponFileRepository)
+		expectedError  error
+		expectedUpload string
+	}{
+		{
+			name: "Uploader defaults to the registering admin",
+			req:  &model.CouponFileRequest{Name: "couponbase1", Source: "s3://coupons/couponbase1.gz", Checksum: checksum},
+			setupMock: func(m *MockCouponFileRepository) {
+				m.On("Register", mock.Anything, mock.MatchedBy(func(f *model.CouponFile) bool {
+					return f.UploadedBy == "admin:alice" && f.RegisteredBy == "admin:alice" && !f.UploadedAt.IsZero()
+				})).Return(nil)
+			},
+			expectedUpload: "admin:alice",
+		},
+		{
+			name: "Named uploader and upload time",
+			req: &model.CouponFileRequest{
+				Name:       "couponbase1",
+				Source:     "s3://coupons/couponbase1.gz",
+				Checksum:   checksum,
+				UploadedBy: "partner-feed",
+				UploadedAt: &uploadedAt,
+			},
+			setupMock: func(m *MockCouponFileRepository) {
+				m.On("Register", mock.Anything, mock.MatchedBy(func(f *model.CouponFile) bool {
+					return f.UploadedBy == "partner-feed" && f.UploadedAt.Equal(uploadedAt)
+				})).Return(nil)
+			},
+			expectedUpload: "partner-feed",
+		},
+		{
+			name:          "Malformed checksum",
+			req:           &model.CouponFileRequest{Name: "couponbase1", Source: "s3://coupons/couponbase1.gz", Checksum: strings.ToUpper(checksum)},
+			setupMock:     func(m *MockCouponFileRepository) {},
+			expectedError: model.ErrInvalidCouponFile,
+		},
+		{
+			name:          "Missing source",
+			req:           &model.CouponFileRequest{Name: "couponbase1", Checksum: checksum},
+			setupMock:     func(m *MockCouponFileRepository) {},
+			expectedError: model.ErrInvalidCouponFile,
+		},
+		{
+			name: "Already registered",
+			req:  &model.CouponFileRequest{Name: "couponbase1", Source: "s3://coupons/couponbase1.gz", Checksum: checksum},
+			setupMock: func(m *MockCouponFileRepository) {
+				m.On("Register", mock.Anything, mock.AnythingOfType("*model.CouponFile")).Return(model.ErrCouponFileExists)
+			},
+			expectedError: model.ErrCouponFileExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCouponFileRepository)
+			tt.setupMock(repo)
+			svc := NewCouponFileService(repo, new(MockHistoryChecker), zerolog.Nop())
+
+			file, err := svc.RegisterFile(context.Background(), tt.req, "admin:alice")
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, file)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedUpload, file.UploadedBy)
+				assert.Equal(t, tt.req.Checksum, file.Checksum)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCouponFileService_Generations(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Generations in use", func(t *testing.T) {
+		repo := new(MockCouponFileRepository)
+		repo.On("ListGenerations", mock.Anything, at, at).Return([]model.CouponGeneration{{ID: 7, Instance: "api-1"}}, nil)
+		svc := NewCouponFileService(repo, new(MockHistoryChecker), zerolog.Nop())
+
+		generations, err := svc.Generations(context.Background(), at, at)
+
+		require.NoError(t, err)
+		require.Len(t, generations, 1)
+		assert.Equal(t, int64(7), generations[0].ID)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := new(MockCouponFileRepository)
+		repo.On("ListGenerations", mock.Anything, at, at).Return(nil, errors.New("database error"))
+		svc := NewCouponFileService(repo, new(MockHistoryChecker), zerolog.Nop())
+
+		_, err := svc.Generations(context.Background(), at, at)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCouponFileService_ValidityAt(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	generations := []model.CouponGeneration{{ID: 7, Instance: "api-1"}}
+
+	t.Run("Checked against the generations in use", func(t *testing.T) {
+		repo := new(MockCouponFileRepository)
+		repo.On("ListGenerations", mock.Anything, at, at).Return(generations, nil)
+		history := new(MockHistoryChecker)
+		history.On("CheckAt", mock.Anything, "SUMMER2025", at, generations).
+			Return(&model.CouponValidityAt{Code: "SUMMER2025", At: at, Verdict: model.CouponVerdictValid}, nil)
+		svc := NewCouponFileService(repo, history, zerolog.Nop())
+
+		result, err := svc.ValidityAt(context.Background(), "SUMMER2025", at)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.CouponVerdictValid, result.Verdict)
+		history.AssertExpectations(t)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := new(MockCouponFileRepository)
+		repo.On("ListGenerations", mock.Anything, at, at).Return(nil, errors.New("database error"))
+		history := new(MockHistoryChecker)
+		svc := NewCouponFileService(repo, history, zerolog.Nop())
+
+		_, err := svc.ValidityAt(context.Background(), "SUMMER2025", at)
+
+		assert.Error(t, err)
+		history.AssertNotCalled(t, "CheckAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Archive read cancelled", func(t *testing.T) {
+		repo := new(MockCouponFileRepository)
+		repo.On("ListGenerations", mock.Anything, at, at).Return(generations, nil)
+		history := new(MockHistoryChecker)
+		history.On("CheckAt", mock.Anything, "SUMMER2025", at, generations).Return(nil, context.Canceled)
+		svc := NewCouponFileService(repo, history, zerolog.Nop())
+
+		_, err := svc.ValidityAt(context.Background(), "SUMMER2025", at)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mini-kart/internal/currency"
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+)
+
+// ratesRetryDelay is how long stale rates are served after a failed refresh
+// before the source is tried again.
+const ratesRetryDelay = time.Minute
+
+// currencyService implements CurrencyService, caching the rates it fetches.
+type currencyService struct {
+	base   string
+	source currency.RateSource
+	ttl    time.Duration
+	logger zerolog.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+// NewCurrencyService creates a currency service converting prices out of the
+// catalogue currency base. Rates are fetched from source and cached for ttl.
+func NewCurrencyService(base string, source currency.RateSource, ttl time.Duration, logger zerolog.Logger) CurrencyService {
+	return &currencyService{
+		base:   model.NormalizeCurrency(base),
+		source: source,
+		ttl:    ttl,
+		logger: logger.With().Str("service", "currency").Logger(),
+		now:    time.Now,
+	}
+}
+
+// Rate returns how much of code one unit of the catalogue currency buys.
+func (s *currencyService) Rate(ctx context.Context, code string) (float64, error) {
+	code = model.NormalizeCurrency(code)
+	if code == s.base {
+		return 1, nil
+	}
+	if !model.ValidCurrency(code) {
+		return 0, model.ErrUnsupportedCurrency
+	}
+
+	rates, err := s.currentRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, ok := rates[code]
+	if !ok {
+		s.logger.Debug().Str("currency", code).Msg("no exchange rate for currency")
+		return 0, model.ErrUnsupportedCurrency
+	}
+	return rate, nil
+}
+
+// currentRates returns the cached rates, fetching them once they are older
+// than the TTL. When a refresh fails the stale rates are served for a while
+// longer, so an outage of the source does not fail every converted request.
+func (s *currencyService) currentRates(ctx context.Context) (map[string]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.rates != nil && now.Sub(s.fetchedAt) < s.ttl {
+		return s.rates, nil
+	}
+
+	rates, err := s.source.Rates(ctx, s.base)
+	if err != nil {
+		if s.rates != nil {
+			s.logger.Warn().Err(err).Time("fetched_at", s.fetchedAt).Msg("failed to refresh exchange rates, serving stale rates")
+			s.fetchedAt = now.Add(ratesRetryDelay - s.ttl)
+			return s.rates, nil
+		}
+		s.logger.Error().Err(err).Str("base", s.base).Msg("failed to fetch exchange rates")
+		return nil, model.ErrRatesUnavailable
+	}
+
+	s.rates, s.fetchedAt = rates, now
+	s.logger.Info().Str("base", s.base).Int("currencies", len(rates)).Msg("exchange rates refreshed")
+
+	return rates, nil
+}
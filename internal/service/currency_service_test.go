@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRateSource is a mock implementation of currency.RateSource.
+type MockRateSource struct {
+	mock.Mock
+}
+
+func (m *MockRateSource) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	args := m.Called(ctx, base)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]float64), args.Error(1)
+}
+
+func TestCurrencyService_Rate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		code         string
+		expectFetch  bool
+		expectedRate float64
+		expectedErr  error
+	}{
+		{
+			name:         "Known currency",
+			code:         "usd",
+			expectFetch:  true,
+			expectedRate: 0.66,
+		},
+		{
+			name:         "Catalogue currency",
+			code:         "AUD",
+			expectedRate: 1,
+		},
+		{
+			name:        "Malformed code",
+			code:        "dollars",
+			expectedErr: model.ErrUnsupportedCurrency,
+		},
+		{
+			name:        "Currency without a rate",
+			code:        "XYZ",
+			expectFetch: true,
+			expectedErr: model.ErrUnsupportedCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := new(MockRateSource)
+			if tt.expectFetch {
+				source.On("Rates", ctx, "AUD").Return(map[string]float64{"USD": 0.66}, nil)
+			}
+
+			svc := NewCurrencyService("aud", source, time.Hour, zerolog.Nop())
+			rate, err := svc.Rate(ctx, tt.code)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedRate, rate)
+			}
+			source.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyService_Caching(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newService := func(source *MockRateSource) *currencyService {
+		svc := NewCurrencyService("AUD", source, time.Hour, zerolog.Nop()).(*currencyService)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("Caches rates for the TTL", func(t *testing.T) {
+		source := new(MockRateSource)
+		source.On("Rates", ctx, "AUD").Return(map[string]float64{"USD": 0.66}, nil).Once()
+		source.On("Rates", ctx, "AUD").Return(map[string]float64{"USD": 0.7}, nil).Once()
+		svc := newService(source)
+
+		rate, err := svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.66, rate)
+
+		now = now.Add(59 * time.Minute)
+		rate, err = svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.66, rate)
+
+		now = now.Add(time.Minute)
+		rate, err = svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.7, rate)
+		source.AssertExpectations(t)
+	})
+
+	t.Run("Serves stale rates when refresh fails", func(t *testing.T) {
+		source := new(MockRateSource)
+		source.On("Rates", ctx, "AUD").Return(map[string]float64{"USD": 0.66}, nil).Once()
+		source.On("Rates", ctx, "AUD").Return(nil, errors.New("connection refused")).Once()
+		source.On("Rates", ctx, "AUD").Return(map[string]float64{"USD": 0.7}, nil).Once()
+		svc := newService(source)
+
+		_, err := svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+
+		now = now.Add(2 * time.Hour)
+		rate, err := svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.66, rate)
+
+		// The source is not retried until the retry delay has passed
+		rate, err = svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.66, rate)
+
+		now = now.Add(ratesRetryDelay)
+		rate, err = svc.Rate(ctx, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0.7, rate)
+		source.AssertExpectations(t)
+	})
+
+	t.Run("Rates unavailable without cached rates", func(t *testing.T) {
+		source := new(MockRateSource)
+		source.On("Rates", ctx, "AUD").Return(nil, errors.New("connection refused"))
+		svc := newService(source)
+
+		_, err := svc.Rate(ctx, "USD")
+
+		assert.Equal(t, model.ErrRatesUnavailable, err)
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// customerService implements CustomerService.
+type customerService struct {
+	repo   repository.CustomerRepository
+	orders OrderService
+	logger zerolog.Logger
+}
+
+// NewCustomerService creates a new customer service. Order history is listed
+// through orders, so it is paginated like GET /api/orders.
+func NewCustomerService(repo repository.CustomerRepository, orders OrderService, logger zerolog.Logger) CustomerService {
+	return &customerService{
+		repo:   repo,
+		orders: orders,
+		logger: logger.With().Str("service", "customer").Logger(),
+	}
+}
+
+// RegisterCustomer validates and registers a customer.
+func (s *customerService) RegisterCustomer(ctx context.Context, req *model.CustomerRequest) (*model.Customer, error) {
+	if req == nil {
+		return nil, fmt.Errorf("customer request cannot be nil")
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	customer := &model.Customer{ID: uuid.New(), Email: req.Email, Name: req.Name, Segments: req.Segments}
+	if err := s.repo.Create(ctx, customer); err != nil {
+		if err == model.ErrCustomerExists {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Msg("failed to register customer")
+		return nil, fmt.Errorf("failed to register customer: %w", err)
+	}
+
+	s.logger.Info().Str("customer_id", customer.ID.String()).Msg("customer registered")
+
+	return customer, nil
+}
+
+// GetCustomer retrieves a customer.
+func (s *customerService) GetCustomer(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	customer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("customer_id", id.String()).Msg("failed to get customer")
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, model.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// FindCustomer retrieves the customer registered with an email address.
+func (s *customerService) FindCustomer(ctx context.Context, email string) (*model.Customer, error) {
+	email = model.NormalizeEmail(email)
+	if !model.ValidEmail(email) {
+		return nil, model.ErrCustomerNotFound
+	}
+
+	customer, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to find customer")
+		return nil, fmt.Errorf("failed to find customer: %w", err)
+	}
+	if customer == nil {
+		return nil, model.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// ListOrders retrieves a page of the customer's orders, newest first.
+func (s *customerService) ListOrders(ctx context.Context, id uuid.UUID, filter model.OrderFilter) ([]model.Order, model.Page, error) {
+	// Unknown customers are told apart from customers without orders
+	if _, err := s.GetCustomer(ctx, id); err != nil {
+		return nil, model.Page{}, err
+	}
+
+	filter.CustomerID = &id
+	return s.orders.List(ctx, filter)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCustomerRepository is a mock implementation of CustomerRepository.
+type MockCustomerRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) Create(ctx context.Context, customer *model.Customer) error {
+	args := m.Called(ctx, customer)
+	return args.Error(0)
+}
+
+func (m *MockCustomerRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerRepository) GetByEmail(ctx context.Context, email string) (*model.Customer, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerRepository) HasOrders(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestCustomerService_RegisterCustomer(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *model.CustomerRequest
+		setupMock     func(*MockCustomerRepository)
+		expectedEmail string
+		expectedError error
+	}{
+		{
+			name: "Customer registered with a normalized email",
+			req:  &model.CustomerRequest{Email: " Ada@Example.com ", Name: " Ada Lovelace "},
+			setupMock: func(m *MockCustomerRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Customer) bool {
+					return c.ID != uuid.Nil && c.Email == "ada@example.com" && c.Name == "Ada Lovelace"
+				})).Return(nil)
+			},
+			expectedEmail: "ada@example.com",
+		},
+		{
+			name: "Customer registered with normalized segments",
+			req:  &model.CustomerRequest{Email: "ada@example.com", Name: "Ada", Segments: []string{" VIP ", "staff", "vip"}},
+			setupMock: func(m *MockCustomerRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(c *model.Customer) bool {
+					return slices.Equal(c.Segments, []string{"vip", "staff"})
+				})).Return(nil)
+			},
+			expectedEmail: "ada@example.com",
+		},
+		{
+			name:          "Blank segment",
+			req:           &model.CustomerRequest{Email: "ada@example.com", Name: "Ada", Segments: []string{"vip", " "}},
+			setupMock:     func(m *MockCustomerRepository) {},
+			expectedError: model.ErrInvalidCustomer,
+		},
+		{
+			name:          "Invalid email",
+			req:           &model.CustomerRequest{Email: "ada.example.com", Name: "Ada"},
+			setupMock:     func(m *MockCustomerRepository) {},
+			expectedError: model.ErrInvalidCustomer,
+		},
+		{
+			name:          "Email with a display name",
+			req:           &model.CustomerRequest{Email: "Ada <ada@example.com>", Name: "Ada"},
+			setupMock:     func(m *MockCustomerRepository) {},
+			expectedError: model.ErrInvalidCustomer,
+		},
+		{
+			name:          "Missing name",
+			req:           &model.CustomerRequest{Email: "ada@example.com", Name: "  "},
+			setupMock:     func(m *MockCustomerRepository) {},
+			expectedError: model.ErrInvalidCustomer,
+		},
+		{
+			name: "Email already registered",
+			req:  &model.CustomerRequest{Email: "ada@example.com", Name: "Ada"},
+			setupMock: func(m *MockCustomerRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*model.Customer")).Return(model.ErrCustomerExists)
+			},
+			expectedError: model.ErrCustomerExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCustomerRepository)
+			tt.setupMock(repo)
+			svc := NewCustomerService(repo, nil, zerolog.Nop())
+
+			customer, err := svc.RegisterCustomer(context.Background(), tt.req)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, customer)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedEmail, customer.Email)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomerService_FindCustomer(t *testing.T) {
+	ada := &model.Customer{ID: uuid.New(), Email: "ada@example.com", Name: "Ada"}
+
+	t.Run("Found in any case", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		repo.On("GetByEmail", mock.Anything, "ada@example.com").Return(ada, nil)
+		svc := NewCustomerService(repo, nil, zerolog.Nop())
+
+		customer, err := svc.FindCustomer(context.Background(), "ADA@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ada, customer)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		repo.On("GetByEmail", mock.Anything, "grace@example.com").Return(nil, nil)
+		svc := NewCustomerService(repo, nil, zerolog.Nop())
+
+		_, err := svc.FindCustomer(context.Background(), "grace@example.com")
+
+		assert.Equal(t, model.ErrCustomerNotFound, err)
+	})
+
+	t.Run("Malformed email is not found", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		svc := NewCustomerService(repo, nil, zerolog.Nop())
+
+		_, err := svc.FindCustomer(context.Background(), "grace")
+
+		assert.Equal(t, model.ErrCustomerNotFound, err)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCustomerService_ListOrders(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+
+	t.Run("Customer's orders listed", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		repo.On("GetByID", ctx, customerID).Return(&model.Customer{ID: customerID}, nil)
+		orderRepo := new(MockOrderRepository)
+		expectedFilter := model.OrderFilter{CustomerID: &customerID, Limit: 10}
+		orders := []model.Order{{ID: uuid.New(), CustomerID: &customerID}}
+		orderRepo.On("List", ctx, expectedFilter).Return(orders, nil)
+		orderRepo.On("Count", ctx, expectedFilter).Return(1, nil)
+		orderSvc := NewOrderService(orderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+		svc := NewCustomerService(repo, orderSvc, zerolog.Nop())
+
+		got, page, err := svc.ListOrders(ctx, customerID, model.OrderFilter{})
+
+		require.NoError(t, err)
+		assert.Equal(t, orders, got)
+		assert.Equal(t, model.Page{Limit: 10, Total: 1}, page)
+		orderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Customer not found", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		repo.On("GetByID", ctx, customerID).Return(nil, nil)
+		orderRepo := new(MockOrderRepository)
+		orderSvc := NewOrderService(orderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+		svc := NewCustomerService(repo, orderSvc, zerolog.Nop())
+
+		_, _, err := svc.ListOrders(ctx, customerID, model.OrderFilter{})
+
+		assert.Equal(t, model.ErrCustomerNotFound, err)
+		orderRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := new(MockCustomerRepository)
+		repo.On("GetByID", ctx, customerID).Return(nil, errors.New("database error"))
+		svc := NewCustomerService(repo, nil, zerolog.Nop())
+
+		_, _, err := svc.ListOrders(ctx, customerID, model.OrderFilter{})
+
+		assert.Error(t, err)
+		assert.NotEqual(t, model.ErrCustomerNotFound, err)
+	})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// flashSaleService implements FlashSaleService.
+type flashSaleService struct {
+	repo   repository.FlashSaleRepository
+	logger zerolog.Logger
+}
+
+// NewFlashSaleService creates a new flash sale service.
+func NewFlashSaleService(repo repository.FlashSaleRepository, logger zerolog.Logger) FlashSaleService {
+	return &flashSaleService{
+		repo:   repo,
+		logger: logger.With().Str("service", "flash_sale").Logger(),
+	}
+}
+
+// ListFlashSales retrieves every flash sale, ordered by product ID.
+func (s *flashSaleService) ListFlashSales(ctx context.Context) ([]model.FlashSale, error) {
+	flashSales, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list flash sales")
+		return nil, fmt.Errorf("failed to list flash sales: %w", err)
+	}
+	return flashSales, nil
+}
+
+// SetFlashSale validates and sets a product's flash sale allocation.
+func (s *flashSaleService) SetFlashSale(ctx context.Context, productID string, req *model.FlashSaleRequest, actor string) (*model.FlashSale, error) {
+	if req == nil {
+		return nil, fmt.Errorf("flash sale request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	flashSale, err := s.repo.Set(ctx, productID, *req.Quantity)
+	if err != nil {
+		if _, ok := err.(*model.DomainError); ok {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to set flash sale")
+		return nil, fmt.Errorf("failed to set flash sale: %w", err)
+	}
+
+	s.logger.Warn().
+		Str("product_id", productID).
+		Int("quantity", flashSale.Quantity).
+		Int("remaining", flashSale.Remaining()).
+		Str("actor", actor).
+		Msg("flash sale set")
+
+	return flashSale, nil
+}
+
+// EndFlashSale ends a product's flash sale.
+func (s *flashSaleService) EndFlashSale(ctx context.Context, productID, actor string) error {
+	deleted, err := s.repo.Delete(ctx, productID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to end flash sale")
+		return fmt.Errorf("failed to end flash sale: %w", err)
+	}
+	if !deleted {
+		return model.ErrFlashSaleNotFound
+	}
+
+	s.logger.Warn().Str("product_id", productID).Str("actor", actor).Msg("flash sale ended")
+
+	return nil
+}
+
+// Reconcile counts reservations taken on Redis counters as sold.
+func (s *flashSaleService) Reconcile(ctx context.Context, limit int) (int, error) {
+	reconciled, err := s.repo.Reconcile(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile flash sales: %w", err)
+	}
+	return reconciled, nil
+}
+
+// RunFlashSaleReconciler counts reservations taken on Redis counters as sold
+// every interval until ctx is cancelled, in batches of batchSize until none
+// are left. Failed runs are logged and retried at the next interval.
+func RunFlashSaleReconciler(ctx context.Context, flashSales FlashSaleService, interval time.Duration, batchSize int, logger zerolog.Logger) {
+	logger = logger.With().Str("component", "flash-sale-reconciler").Logger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				reconciled, err := flashSales.Reconcile(ctx, batchSize)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error().Err(err).Msg("flash sale reconciliation failed")
+					}
+					break
+				}
+				if reconciled < batchSize {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockFlashSaleRepository is a mock implementation of FlashSaleRepository.
+type MockFlashSaleRepository struct {
+	mock.Mock
+}
+
+func (m *MockFlashSaleRepository) List(ctx context.Context) ([]model.FlashSale, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) GetByProductIDs(ctx context.Context, productIDs []string) ([]model.FlashSale, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) Set(ctx context.Context, productID string, quantity int) (*model.FlashSale, error) {
+	args := m.Called(ctx, productID, quantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FlashSale), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) Delete(ctx context.Context, productID string) (bool, error) {
+	args := m.Called(ctx, productID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) Reserve(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) ([]model.FlashSaleReservation, error) {
+	args := m.Called(ctx, tx, reservations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.FlashSaleReservation), args.Error(1)
+}
+
+func (m *MockFlashSaleRepository) Release(ctx context.Context, reservations []model.FlashSaleReservation) error {
+	args := m.Called(ctx, reservations)
+	return args.Error(0)
+}
+
+func (m *MockFlashSaleRepository) RecordPending(ctx context.Context, tx pgx.Tx, reservations []model.FlashSaleReservation) error {
+	args := m.Called(ctx, tx, reservations)
+	return args.Error(0)
+}
+
+func (m *MockFlashSaleRepository) Reconcile(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func TestFlashSaleService_SetFlashSale(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	quantity := func(q int) *int { return &q }
+
+	tests := []struct {
+		name        string
+		req         *model.FlashSaleRequest
+		setResult   *model.FlashSale
+		setError    error
+		expectSet   bool
+		expectedErr error
+	}{
+		{
+			name:      "Sets allocation",
+			req:       &model.FlashSaleRequest{Quantity: quantity(100)},
+			setResult: &model.FlashSale{ProductID: "P001", Quantity: 100, Sold: 10},
+			expectSet: true,
+		},
+		{
+			name:        "Missing quantity is rejected",
+			req:         &model.FlashSaleRequest{},
+			expectedErr: model.ErrInvalidFlashSale,
+		},
+		{
+			name:        "Negative quantity is rejected",
+			req:         &model.FlashSaleRequest{Quantity: quantity(-1)},
+			expectedErr: model.ErrInvalidFlashSale,
+		},
+		{
+			name:        "Unknown product",
+			req:         &model.FlashSaleRequest{Quantity: quantity(100)},
+			setError:    model.ErrProductNotFound,
+			expectSet:   true,
+			expectedErr: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFlashSaleRepository)
+			service := NewFlashSaleService(mockRepo, logger)
+
+			if tt.expectSet {
+				mockRepo.On("Set", ctx, "P001", *tt.req.Quantity).Return(tt.setResult, tt.setError)
+			}
+
+			flashSale, err := service.SetFlashSale(ctx, "P001", tt.req, "ops")
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, flashSale)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 90, flashSale.Remaining())
+			}
+			if !tt.expectSet {
+				mockRepo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlashSaleService_EndFlashSale(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	mockRepo := new(MockFlashSaleRepository)
+	service := NewFlashSaleService(mockRepo, logger)
+
+	mockRepo.On("Delete", ctx, "P001").Return(true, nil)
+	mockRepo.On("Delete", ctx, "P002").Return(false, nil)
+	mockRepo.On("Delete", ctx, "P003").Return(false, errors.New("database error"))
+
+	assert.NoError(t, service.EndFlashSale(ctx, "P001", "ops"))
+	assert.Equal(t, model.ErrFlashSaleNotFound, service.EndFlashSale(ctx, "P002", "ops"))
+	assert.Error(t, service.EndFlashSale(ctx, "P003", "ops"))
+}
+
+func TestRunFlashSaleReconciler(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockRepo := new(MockFlashSaleRepository)
+	service := NewFlashSaleService(mockRepo, logger)
+
+	// Full batches are followed by another until one comes back short
+	mockRepo.On("Reconcile", mock.Anything, 2).Return(2, nil).Twice()
+	mockRepo.On("Reconcile", mock.Anything, 2).Return(1, nil).Once().Run(func(mock.Arguments) { cancel() })
+
+	done := make(chan struct{})
+	go func() {
+		RunFlashSaleReconciler(ctx, service, time.Millisecond, 2, logger)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reconciler did not stop")
+	}
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// operationCompleteTimeout bounds recording an operation's outcome, which
+// happens after the order creation's own deadline may have passed.
+const operationCompleteTimeout = 5 * time.Second
+
+// operationService implements OperationService by creating orders in the
+// background.
+type operationService struct {
+	orders  OrderService
+	repo    repository.OperationRepository
+	timeout time.Duration
+	now     func() time.Time
+	logger  zerolog.Logger
+
+	// running tracks orders still being created
+	running sync.WaitGroup
+}
+
+// NewOperationService creates a service that creates orders through orders
+// in the background, tracking each in an operation. A creation still running
+// after timeout fails; zero leaves it unbounded.
+func NewOperationService(orders OrderService, repo repository.OperationRepository, timeout time.Duration, logger zerolog.Logger) OperationService {
+	return &operationService{
+		orders:  orders,
+		repo:    repo,
+		timeout: timeout,
+		now:     time.Now,
+		logger:  logger.With().Str("service", "operation").Logger(),
+	}
+}
+
+// SubmitOrder records a pending operation and creates the order in the
+// background. The order outlives the request, so it is created even if the
+// client disconnects once the operation has been returned.
+func (s *operationService) SubmitOrder(ctx context.Context, req *model.OrderRequest) (*model.Operation, error) {
+	if req == nil {
+		return nil, fmt.Errorf("order request cannot be nil")
+	}
+
+	now := s.now()
+	op := &model.Operation{
+		ID:        uuid.New(),
+		Status:    model.OperationStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, op); err != nil {
+		s.logger.Error().Err(err).Msg("failed to record order operation")
+		return nil, fmt.Errorf("failed to submit order: %w", err)
+	}
+
+	s.running.Add(1)
+	go func(op model.Operation) {
+		defer s.running.Done()
+		s.run(context.WithoutCancel(ctx), &op, req)
+	}(*op)
+
+	s.logger.Info().Str("operation_id", op.ID.String()).Msg("order submitted")
+
+	return op, nil
+}
+
+// run creates the order for op and records the outcome.
+func (s *operationService) run(ctx context.Context, op *model.Operation, req *model.OrderRequest) {
+	createCtx := ctx
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		createCtx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	order, err := s.orders.CreateOrder(createCtx, req)
+	if err != nil {
+		op.Status = model.OperationStatusFailed
+		op.Error = model.NewOperationError(err)
+	} else {
+		op.Status = model.OperationStatusSucceeded
+		op.OrderID = &order.ID
+	}
+	op.UpdatedAt = s.now()
+
+	completeCtx, cancel := context.WithTimeout(ctx, operationCompleteTimeout)
+	defer cancel()
+	if err := s.repo.Complete(completeCtx, op); err != nil {
+		s.logger.Error().Err(err).Str("operation_id", op.ID.String()).Msg("failed to record order operation outcome")
+		return
+	}
+
+	s.logger.Info().
+		Str("operation_id", op.ID.String()).
+		Str("status", string(op.Status)).
+		Dur("elapsed", op.UpdatedAt.Sub(op.CreatedAt)).
+		Msg("order operation completed")
+}
+
+// GetOperation retrieves an operation by its ID.
+func (s *operationService) GetOperation(ctx context.Context, id uuid.UUID) (*model.Operation, error) {
+	op, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("operation_id", id.String()).Msg("failed to get operation")
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	return op, nil
+}
+
+// Close waits for orders still being created in the background, until ctx
+// is done.
+func (s *operationService) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("order operations still running: %w", ctx.Err())
+	}
+}
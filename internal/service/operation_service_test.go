@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOperationRepository is a mock implementation of OperationRepository.
+type MockOperationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOperationRepository) Create(ctx context.Context, op *model.Operation) error {
+	args := m.Called(ctx, op)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) Complete(ctx context.Context, op *model.Operation) error {
+	args := m.Called(ctx, op)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Operation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Operation), args.Error(1)
+}
+
+// stubOrderService creates orders with createFunc.
+type stubOrderService struct {
+	OrderService
+	createFunc func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error)
+}
+
+func (s *stubOrderService) CreateOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	return s.createFunc(ctx, req)
+}
+
+func TestOperationService_SubmitOrder(t *testing.T) {
+	orderID := uuid.New()
+
+	tests := []struct {
+		name          string
+		createFunc    func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error)
+		expectedOrder *uuid.UUID
+		expectedError *model.OperationError
+	}{
+		{
+			name: "Order created",
+			createFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+				return &model.OrderResponse{ID: orderID}, nil
+			},
+			expectedOrder: &orderID,
+		},
+		{
+			name: "Order refused",
+			createFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+				return nil, model.ErrInvalidPromoCode
+			},
+			expectedError: &model.OperationError{
+				Code:    model.ErrCodeInvalidPromoCode,
+				Message: model.ErrInvalidPromoCode.Message,
+			},
+		},
+		{
+			name: "Order creation times out",
+			createFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			expectedError: &model.OperationError{
+				Code:      model.ErrCodeInternalError,
+				Message:   "Order could not be created",
+				Retryable: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOperationRepository)
+			orders := &stubOrderService{createFunc: tt.createFunc}
+			svc := NewOperationService(orders, repo, 50*time.Millisecond, zerolog.Nop())
+
+			completed := make(chan *model.Operation, 1)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Operation")).Return(nil)
+			repo.On("Complete", mock.Anything, mock.AnythingOfType("*model.Operation")).
+				Run(func(args mock.Arguments) { completed <- args.Get(1).(*model.Operation) }).
+				Return(nil)
+
+			// The order is still created once the request has been cancelled
+			ctx, cancel := context.WithCancel(context.Background())
+			op, err := svc.SubmitOrder(ctx, &model.OrderRequest{})
+			cancel()
+
+			require.NoError(t, err)
+			assert.Equal(t, model.OperationStatusPending, op.Status)
+			assert.False(t, op.Done())
+
+			require.NoError(t, svc.Close(context.Background()))
+			done := <-completed
+
+			assert.Equal(t, op.ID, done.ID)
+			assert.True(t, done.Done())
+			assert.Equal(t, tt.expectedOrder, done.OrderID)
+			assert.Equal(t, tt.expectedError, done.Error)
+			if tt.expectedError == nil {
+				assert.Equal(t, model.OperationStatusSucceeded, done.Status)
+			} else {
+				assert.Equal(t, model.OperationStatusFailed, done.Status)
+			}
+		})
+	}
+}
+
+func TestOperationService_SubmitOrder_RecordFails(t *testing.T) {
+	repo := new(MockOperationRepository)
+	orders := &stubOrderService{createFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+		t.Fatal("order must not be created without an operation")
+		return nil, nil
+	}}
+	svc := NewOperationService(orders, repo, 0, zerolog.Nop())
+
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Operation")).Return(errors.New("database error"))
+
+	op, err := svc.SubmitOrder(context.Background(), &model.OrderRequest{})
+
+	assert.Error(t, err)
+	assert.Nil(t, op)
+	require.NoError(t, svc.Close(context.Background()))
+}
+
+func TestOperationService_Close(t *testing.T) {
+	repo := new(MockOperationRepository)
+	release := make(chan struct{})
+	orders := &stubOrderService{createFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+		<-release
+		return &model.OrderResponse{ID: uuid.New()}, nil
+	}}
+	svc := NewOperationService(orders, repo, 0, zerolog.Nop())
+
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Operation")).Return(nil)
+	repo.On("Complete", mock.Anything, mock.AnythingOfType("*model.Operation")).Return(nil)
+
+	_, err := svc.SubmitOrder(context.Background(), &model.OrderRequest{})
+	require.NoError(t, err)
+
+	// Close gives up once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, svc.Close(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, svc.Close(context.Background()))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// orderImportColumns lists the CSV header columns an order import must have.
+// The status, source, coupon_code and discount columns are optional.
+var orderImportColumns = []string{"order_ref", "created_at", "product_id", "quantity", "unit_price"}
+
+// maxImportLine caps the length of an order in a JSON Lines import.
+const maxImportLine = 1 << 20
+
+// orderImportService implements OrderImportService.
+type orderImportService struct {
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	logger      zerolog.Logger
+	now         func() time.Time
+}
+
+// NewOrderImportService creates a new order import service.
+func NewOrderImportService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, logger zerolog.Logger) OrderImportService {
+	return &orderImportService{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		logger:      logger.With().Str("service", "order_import").Logger(),
+		now:         time.Now,
+	}
+}
+
+// rejectFunc reports an order that was not imported.
+type rejectFunc func(row int, ref, code, reason string)
+
+// ImportOrders reads orders as they stream in and stores each in a
+// transaction of its own, so the file is never held in memory. Orders stored
+// before a failure stay imported, and re-running the import skips them.
+func (s *orderImportService) ImportOrders(ctx context.Context, r io.Reader, format model.OrderImportFormat) (*model.OrderImportResult, error) {
+	result := &model.OrderImportResult{Errors: []model.OrderImportError{}}
+	reject := func(row int, ref, code, reason string) {
+		result.Errors = append(result.Errors, model.OrderImportError{Row: row, Ref: ref, Code: code, Error: reason})
+	}
+
+	var next func() (model.OrderImport, int, bool, error)
+	switch format {
+	case model.OrderImportCSV:
+		var err error
+		if next, err = csvOrderImports(r, reject); err != nil {
+			return nil, err
+		}
+	case model.OrderImportJSONL:
+		next = jsonlOrderImports(r, reject)
+	default:
+		return nil, model.ErrInvalidOrderImport
+	}
+
+	// Products are looked up once per import, since most orders share them
+	products := make(map[string]model.Product)
+	for {
+		order, row, ok, err := next()
+		if err != nil {
+			s.logger.Error().Err(err).Int64("imported", result.Imported).Msg("failed to read order import")
+			return nil, fmt.Errorf("failed to read order import: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		err = s.importOrder(ctx, order, products)
+		switch err {
+		case nil:
+			result.Imported++
+		case model.ErrInvalidImportedOrder, model.ErrInvalidQuantity, model.ErrProductNotFound, model.ErrOrderImported:
+			reject(row, order.Ref, err.(*model.DomainError).Code, err.Error())
+		default:
+			s.logger.Error().Err(err).Str("legacy_ref", order.Ref).Int64("imported", result.Imported).Msg("failed to import order")
+			return nil, fmt.Errorf("failed to import orders: %w", err)
+		}
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+
+	s.logger.Info().
+		Str("format", string(format)).
+		Int64("imported", result.Imported).
+		Int("rejected", len(result.Errors)).
+		Msg("orders imported")
+
+	return result, nil
+}
+
+// importOrder validates an order and stores it with its recorded prices.
+// Orders without a status are taken to be fulfilled, and the items of
+// fulfilled orders are stored as fulfilled in full.
+func (s *orderImportService) importOrder(ctx context.Context, imported model.OrderImport, products map[string]model.Product) error {
+	imported.Ref = strings.TrimSpace(imported.Ref)
+	if imported.Status == "" {
+		imported.Status = model.OrderStatusFulfilled
+	}
+	if err := imported.Validate(s.now()); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, item := range imported.Items {
+		if _, ok := products[item.ProductID]; !ok {
+			missing = append(missing, item.ProductID)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := s.productRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return fmt.Errorf("failed to get products: %w", err)
+		}
+		for _, p := range found {
+			products[p.ID] = p
+		}
+	}
+
+	order := &model.Order{
+		ID:         uuid.New(),
+		CouponCode: nonEmpty(imported.CouponCode),
+		Source:     nonEmpty(imported.Source),
+		Status:     imported.Status,
+		CreatedAt:  imported.CreatedAt,
+		UpdatedAt:  imported.CreatedAt,
+	}
+
+	items := make([]model.OrderItem, len(imported.Items))
+	var subtotal model.Money
+	for i, item := range imported.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return model.ErrProductNotFound
+		}
+		items[i] = model.OrderItem{
+			ID:        uuid.New(),
+			OrderID:   order.ID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Product:   &model.ProductSnapshot{Name: product.Name, Category: product.Category, Price: item.UnitPrice},
+		}
+		if order.Status == model.OrderStatusFulfilled {
+			items[i].FulfilledQuantity = item.Quantity
+		}
+		subtotal += item.UnitPrice.Mul(item.Quantity)
+	}
+
+	discount := imported.Discount
+	if discount > subtotal {
+		return model.ErrInvalidImportedOrder
+	}
+	total := subtotal - discount
+	order.Subtotal, order.Discount, order.Total = &subtotal, &discount, &total
+
+	return s.orderRepo.ImportOrder(ctx, imported.Ref, order, items)
+}
+
+// nonEmpty returns s, or nil if it is blank.
+func nonEmpty(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	return &trimmed
+}
+
+// csvOrderRow is a row of a CSV order import.
+type csvOrderRow struct {
+	row    int
+	ref    string
+	record []string
+}
+
+// csvOrderImports returns a source of the orders in a CSV import, grouping
+// adjacent rows with the same order_ref into one order. Orders with an
+// invalid row, and rows that cannot be parsed, are rejected and skipped.
+func csvOrderImports(r io.Reader, reject rejectFunc) (func() (model.OrderImport, int, bool, error), error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if err == io.EOF || errors.As(err, &parseErr) {
+			return nil, model.ErrInvalidOrderImport
+		}
+		return nil, fmt.Errorf("failed to read import header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range orderImportColumns {
+		if _, ok := index[column]; !ok {
+			return nil, model.ErrInvalidOrderImport
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := index[column]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	read := func() (*csvOrderRow, error) {
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil, nil
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) {
+					reject(parseErr.StartLine, "", model.ErrCodeInvalidImportedOrder, parseErr.Err.Error())
+					continue
+				}
+				return nil, err
+			}
+			row, _ := reader.FieldPos(0)
+			return &csvOrderRow{row: row, ref: field(record, "order_ref"), record: record}, nil
+		}
+	}
+
+	parseItem := func(record []string) (model.OrderImportItem, error) {
+		quantity, err := strconv.Atoi(field(record, "quantity"))
+		if err != nil {
+			return model.OrderImportItem{}, model.ErrInvalidQuantity
+		}
+		unitPrice, err := model.ParseMoney(field(record, "unit_price"))
+		if err != nil {
+			return model.OrderImportItem{}, model.ErrInvalidImportedOrder
+		}
+		return model.OrderImportItem{ProductID: field(record, "product_id"), Quantity: quantity, UnitPrice: unitPrice}, nil
+	}
+
+	// The order-level columns are read from the first row of each order
+	parseOrder := func(record []string) (model.OrderImport, error) {
+		createdAt, err := time.Parse(time.RFC3339, field(record, "created_at"))
+		if err != nil {
+			return model.OrderImport{}, model.ErrInvalidImportedOrder
+		}
+		source, couponCode := field(record, "source"), field(record, "coupon_code")
+		order := model.OrderImport{
+			Ref:        field(record, "order_ref"),
+			CreatedAt:  createdAt,
+			Status:     model.OrderStatus(strings.ToLower(field(record, "status"))),
+			Source:     &source,
+			CouponCode: &couponCode,
+		}
+		if discount := field(record, "discount"); discount != "" {
+			if order.Discount, err = model.ParseMoney(discount); err != nil {
+				return model.OrderImport{}, model.ErrInvalidImportedOrder
+			}
+		}
+		first, err := parseItem(record)
+		if err != nil {
+			return model.OrderImport{}, err
+		}
+		order.Items = []model.OrderImportItem{first}
+		return order, nil
+	}
+
+	var pending *csvOrderRow
+	firstRows := make(map[string]int)
+	return func() (model.OrderImport, int, bool, error) {
+		for {
+			first := pending
+			pending = nil
+			if first == nil {
+				var err error
+				if first, err = read(); err != nil || first == nil {
+					return model.OrderImport{}, 0, false, err
+				}
+			}
+
+			imported, failure := parseOrder(first.record)
+			failedRow := first.row
+			for {
+				next, err := read()
+				if err != nil {
+					return model.OrderImport{}, 0, false, err
+				}
+				if next == nil || next.ref != first.ref {
+					pending = next
+					break
+				}
+				if failure != nil {
+					continue
+				}
+				item, err := parseItem(next.record)
+				if err != nil {
+					failure, failedRow = err, next.row
+					continue
+				}
+				imported.Items = append(imported.Items, item)
+			}
+
+			if row, seen := firstRows[first.ref]; seen && first.ref != "" {
+				reject(first.row, first.ref, model.ErrCodeInvalidImportedOrder,
+					fmt.Sprintf("rows of an order must be adjacent; order_ref first seen on row %d", row))
+				continue
+			}
+			firstRows[first.ref] = first.row
+
+			if failure != nil {
+				reject(failedRow, first.ref, failure.(*model.DomainError).Code, failure.Error())
+				continue
+			}
+			return imported, first.row, true, nil
+		}
+	}, nil
+}
+
+// jsonlOrderImports returns a source of the orders in a JSON Lines import.
+// Blank lines are skipped and lines that are not valid orders are rejected.
+// A line longer than maxImportLine is rejected and ends the import.
+func jsonlOrderImports(r io.Reader, reject rejectFunc) func() (model.OrderImport, int, bool, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxImportLine)
+	row := 0
+
+	return func() (model.OrderImport, int, bool, error) {
+		for scanner.Scan() {
+			row++
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var order model.OrderImport
+			if err := json.Unmarshal(line, &order); err != nil {
+				reject(row, "", model.ErrCodeInvalidImportedOrder, "line is not a valid order: "+err.Error())
+				continue
+			}
+			return order, row, true, nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			if errors.Is(err, bufio.ErrTooLong) {
+				reject(row+1, "", model.ErrCodeInvalidImportedOrder, fmt.Sprintf("line exceeds %d bytes; the rest of the file was not read", maxImportLine))
+				return model.OrderImport{}, 0, false, nil
+			}
+			return model.OrderImport{}, 0, false, err
+		}
+		return model.OrderImport{}, 0, false, nil
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderImportService_ImportOrders(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	catalogue := []model.Product{
+		{ID: "P001", Name: "Chicken Waffle", Price: model.NewMoney(12.5), Category: "Waffle"},
+		{ID: "P002", Name: "Lemon Tart", Price: model.NewMoney(4), Category: "Tart"},
+	}
+
+	// stored is an order passed to the repository
+	type stored struct {
+		order *model.Order
+		items []model.OrderItem
+	}
+	newService := func(orderRepo *MockOrderRepository, productRepo *MockProductRepository) (OrderImportService, map[string]stored) {
+		imported := make(map[string]stored)
+		orderRepo.On("ImportOrder", ctx, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			imported[args.String(1)] = stored{order: args.Get(2).(*model.Order), items: args.Get(3).([]model.OrderItem)}
+		}).Return(nil).Maybe()
+		productRepo.On("GetByIDs", ctx, mock.Anything).Return(catalogue, nil).Maybe()
+
+		svc := NewOrderImportService(orderRepo, productRepo, logger)
+		svc.(*orderImportService).now = func() time.Time { return now }
+		return svc, imported
+	}
+
+	t.Run("Groups CSV rows into orders and reports the rest", func(t *testing.T) {
+		input := "order_ref,created_at,product_id,quantity,unit_price,status,source,coupon_code,discount\n" +
+			"L-1,2024-05-01T10:00:00Z,P001,2,10.00,,web,SPRING10,2.00\n" +
+			"L-1,,P002,1,3.50,,,,\n" +
+			"L-2,2024-05-02T10:00:00Z,P002,1,3.50,cancelled,,,\n" +
+			"L-3,not-a-date,P001,1,10.00,,,,\n" +
+			"L-4,2024-05-03T10:00:00Z,P001,0,10.00,,,,\n" +
+			"L-1,2024-05-04T10:00:00Z,P001,1,10.00,,,,\n" +
+			"L-5,2024-05-05T10:00:00Z,P999,1,10.00,,,,\n" +
+			"L-6,2024-05-06T10:00:00Z,P001,1,1.00,,,,5.00\n"
+
+		orderRepo, productRepo := new(MockOrderRepository), new(MockProductRepository)
+		svc, imported := newService(orderRepo, productRepo)
+
+		result, err := svc.ImportOrders(ctx, strings.NewReader(input), model.OrderImportCSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Imported)
+		assert.Equal(t, []model.OrderImportError{
+			{Row: 5, Ref: "L-3", Code: model.ErrCodeInvalidImportedOrder, Error: model.ErrInvalidImportedOrder.Message},
+			{Row: 6, Ref: "L-4", Code: model.ErrCodeInvalidQuantity, Error: model.ErrInvalidQuantity.Message},
+			{Row: 7, Ref: "L-1", Code: model.ErrCodeInvalidImportedOrder, Error: "rows of an order must be adjacent; order_ref first seen on row 2"},
+			{Row: 8, Ref: "L-5", Code: model.ErrCodeProductNotFound, Error: model.ErrProductNotFound.Message},
+			{Row: 9, Ref: "L-6", Code: model.ErrCodeInvalidImportedOrder, Error: model.ErrInvalidImportedOrder.Message},
+		}, result.Errors)
+
+		require.Contains(t, imported, "L-1")
+		first := imported["L-1"]
+		assert.Equal(t, model.OrderStatusFulfilled, first.order.Status)
+		assert.Equal(t, time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC), first.order.CreatedAt)
+		assert.Equal(t, "web", *first.order.Source)
+		assert.Equal(t, "SPRING10", *first.order.CouponCode)
+		assert.Equal(t, model.NewMoney(23.5), *first.order.Subtotal)
+		assert.Equal(t, model.NewMoney(2.0), *first.order.Discount)
+		assert.Equal(t, model.NewMoney(21.5), *first.order.Total)
+		require.Len(t, first.items, 2)
+		assert.Equal(t, 2, first.items[0].FulfilledQuantity)
+		assert.Equal(t, &model.ProductSnapshot{Name: "Chicken Waffle", Category: "Waffle", Price: model.NewMoney(10)}, first.items[0].Product)
+		assert.Equal(t, first.order.ID, first.items[1].OrderID)
+
+		require.Contains(t, imported, "L-2")
+		second := imported["L-2"]
+		assert.Equal(t, model.OrderStatusCancelled, second.order.Status)
+		assert.Nil(t, second.order.Source)
+		assert.Nil(t, second.order.CouponCode)
+		assert.Equal(t, 0, second.items[0].FulfilledQuantity)
+	})
+
+	t.Run("Reads JSON Lines", func(t *testing.T) {
+		input := `{"ref":"L-10","createdAt":"2024-06-01T09:30:00Z","status":"confirmed","items":[{"productId":"P002","quantity":3,"unitPrice":3.5}]}` + "\n" +
+			"\n" +
+			`{"ref":"L-11",` + "\n" +
+			`{"ref":"L-12","createdAt":"2030-01-01T00:00:00Z","items":[{"productId":"P001","quantity":1,"unitPrice":10}]}` + "\n"
+
+		orderRepo, productRepo := new(MockOrderRepository), new(MockProductRepository)
+		svc, imported := newService(orderRepo, productRepo)
+
+		result, err := svc.ImportOrders(ctx, strings.NewReader(input), model.OrderImportJSONL)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Imported)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, 3, result.Errors[0].Row)
+		assert.Equal(t, model.ErrCodeInvalidImportedOrder, result.Errors[0].Code)
+		assert.Equal(t, model.OrderImportError{Row: 4, Ref: "L-12", Code: model.ErrCodeInvalidImportedOrder, Error: model.ErrInvalidImportedOrder.Message}, result.Errors[1])
+
+		require.Contains(t, imported, "L-10")
+		assert.Equal(t, model.OrderStatusConfirmed, imported["L-10"].order.Status)
+		assert.Equal(t, model.NewMoney(10.5), *imported["L-10"].order.Total)
+	})
+
+	t.Run("Order already imported", func(t *testing.T) {
+		orderRepo, productRepo := new(MockOrderRepository), new(MockProductRepository)
+		orderRepo.On("ImportOrder", ctx, "L-1", mock.Anything, mock.Anything).Return(model.ErrOrderImported)
+		productRepo.On("GetByIDs", ctx, []string{"P001"}).Return(catalogue[:1], nil)
+
+		svc := NewOrderImportService(orderRepo, productRepo, logger)
+		result, err := svc.ImportOrders(ctx, strings.NewReader("order_ref,created_at,product_id,quantity,unit_price\nL-1,2024-05-01T10:00:00Z,P001,1,10\n"), model.OrderImportCSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), result.Imported)
+		assert.Equal(t, []model.OrderImportError{
+			{Row: 2, Ref: "L-1", Code: model.ErrCodeOrderImported, Error: model.ErrOrderImported.Message},
+		}, result.Errors)
+	})
+
+	t.Run("Header missing a column", func(t *testing.T) {
+		orderRepo, productRepo := new(MockOrderRepository), new(MockProductRepository)
+
+		svc := NewOrderImportService(orderRepo, productRepo, logger)
+		_, err := svc.ImportOrders(ctx, strings.NewReader("order_ref,created_at,product_id,quantity\nL-1,2024-05-01T10:00:00Z,P001,1\n"), model.OrderImportCSV)
+
+		assert.Equal(t, model.ErrInvalidOrderImport, err)
+		orderRepo.AssertNotCalled(t, "ImportOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		orderRepo, productRepo := new(MockOrderRepository), new(MockProductRepository)
+		orderRepo.On("ImportOrder", ctx, "L-1", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+		productRepo.On("GetByIDs", ctx, []string{"P001"}).Return(catalogue[:1], nil)
+
+		svc := NewOrderImportService(orderRepo, productRepo, logger)
+		result, err := svc.ImportOrders(ctx, strings.NewReader("order_ref,created_at,product_id,quantity,unit_price\nL-1,2024-05-01T10:00:00Z,P001,1,10\n"), model.OrderImportCSV)
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
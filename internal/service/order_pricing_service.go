@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// orderPricingService implements OrderPricingService.
+type orderPricingService struct {
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	pricingRepo repository.OrderPricingRepository
+	discounts   coupon.MetadataSource
+	engine      pricing.Engine
+	logger      zerolog.Logger
+}
+
+// NewOrderPricingService creates a new order pricing service. Coupon
+// discounts are looked up in discounts; without one, orders are repriced
+// without their coupon's discount.
+func NewOrderPricingService(
+	orderRepo repository.OrderRepository,
+	productRepo repository.ProductRepository,
+	pricingRepo repository.OrderPricingRepository,
+	discounts coupon.MetadataSource,
+	engine pricing.Engine,
+	logger zerolog.Logger,
+) OrderPricingService {
+	return &orderPricingService{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		pricingRepo: pricingRepo,
+		discounts:   discounts,
+		engine:      engine,
+		logger:      logger.With().Str("service", "order_pricing").Logger(),
+	}
+}
+
+// Recalculate re-prices an order's current items against the current
+// catalogue and coupon rules and records the result as a new pricing version
+// when it differs from the current one.
+func (s *orderPricingService) Recalculate(ctx context.Context, orderID uuid.UUID, actor string) (*model.OrderRepricing, error) {
+	order, items, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to get order")
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	// Check up front for a clear error; the repository repeats these checks
+	// under the order's row lock
+	if order.Status == model.OrderStatusCancelled {
+		return nil, model.ErrOrderCancelled
+	}
+	if order.Status == model.OrderStatusFulfilled || model.DeriveFulfillmentStatus(items) != model.FulfillmentStatusUnfulfilled {
+		return nil, model.ErrFulfillmentStarted
+	}
+
+	latest, err := s.pricingRepo.Latest(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to get order pricing")
+		return nil, fmt.Errorf("failed to get order pricing: %w", err)
+	}
+
+	// Orders that were never repriced are on the pricing they were placed
+	// with, whose lines are the prices captured on their items
+	version := 1
+	previousLines := snapshotLines(items)
+	if latest != nil {
+		version = latest.Version
+		if latest.Breakdown != nil {
+			previousLines = latest.Breakdown.Lines
+		}
+	}
+
+	breakdown, err := s.price(ctx, order, items)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.OrderRepricing{
+		OrderID:          orderID,
+		Version:          version,
+		Pricing:          breakdown,
+		PreviousSubtotal: order.Subtotal,
+		PreviousDiscount: order.Discount,
+		PreviousTotal:    order.Total,
+		Diff:             diffPricing(order, previousLines, breakdown),
+	}
+	if result.Diff.Subtotal == 0 && result.Diff.Discount == 0 && result.Diff.Total == 0 &&
+		len(result.Diff.Lines) == 0 && order.Total != nil {
+		s.logger.Debug().Str("order_id", orderID.String()).Msg("order pricing unchanged")
+		return result, nil
+	}
+
+	next := &model.OrderPricingVersion{
+		OrderID:   orderID,
+		Version:   version + 1,
+		Subtotal:  &breakdown.Subtotal,
+		Discount:  &breakdown.Discount,
+		Total:     &breakdown.Total,
+		Breakdown: breakdown,
+		CreatedBy: &actor,
+	}
+	if err := s.pricingRepo.Create(ctx, next); err != nil {
+		switch err {
+		case model.ErrOrderNotFound, model.ErrOrderCancelled, model.ErrFulfillmentStarted, model.ErrPricingConflict:
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to record order pricing")
+		return nil, fmt.Errorf("failed to record order pricing: %w", err)
+	}
+
+	result.Version = next.Version
+	result.Changed = true
+
+	s.logger.Info().
+		Str("order_id", orderID.String()).
+		Int("version", next.Version).
+		Stringer("total", breakdown.Total).
+		Stringer("total_change", result.Diff.Total).
+		Str("actor", actor).
+		Msg("order repriced")
+
+	return result, nil
+}
+
+// price runs an order's items through the pricing engine with the current
+// catalogue prices and coupon discount. A coupon the edited items no longer
+// qualify for is dropped rather than failing the recalculation.
+func (s *orderPricingService) price(ctx context.Context, order *model.Order, items []model.OrderItem) (*model.PriceBreakdown, error) {
+	requested := make([]model.OrderItemRequest, len(items))
+	productIDs := make([]string, len(items))
+	for i, item := range items {
+		requested[i] = model.OrderItemRequest{ProductID: item.ProductID, Quantity: item.Quantity}
+		productIDs[i] = item.ProductID
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to retrieve product details")
+		return nil, fmt.Errorf("failed to retrieve product details: %w", err)
+	}
+
+	// The coupon was accepted when the order was placed, so only its current
+	// discount terms matter here, not whether the code is still on offer
+	var discount *model.CouponDiscount
+	if order.CouponCode != nil && *order.CouponCode != "" && s.discounts != nil {
+		discount, err = s.discounts.GetByCode(ctx, *order.CouponCode)
+		if err != nil {
+			s.logger.Error().Err(err).Str("coupon_code", *order.CouponCode).Msg("failed to resolve coupon discount")
+			return nil, fmt.Errorf("failed to resolve coupon discount: %w", err)
+		}
+	}
+
+	input := pricing.Input{Items: requested, Products: products, Discount: discount}
+	breakdown, err := s.engine.Price(ctx, input)
+	if err == model.ErrCouponNotApplicable || err == model.ErrCouponMinSubtotal {
+		s.logger.Warn().
+			Str("order_id", order.ID.String()).
+			Str("coupon_code", *order.CouponCode).
+			Err(err).
+			Msg("coupon no longer applies to the order, repricing without it")
+		input.Discount = nil
+		breakdown, err = s.engine.Price(ctx, input)
+	}
+	if err != nil {
+		if err == model.ErrProductNotFound {
+			s.logger.Warn().Str("order_id", order.ID.String()).Msg("order has products that are no longer in the catalogue")
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to price order")
+		return nil, fmt.Errorf("failed to price order: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// snapshotLines builds price lines from the product prices captured on an
+// order's items. Items without a snapshot are priced at zero.
+func snapshotLines(items []model.OrderItem) []model.PriceLine {
+	lines := make([]model.PriceLine, len(items))
+	for i, item := range items {
+		line := model.PriceLine{ProductID: item.ProductID, Quantity: item.Quantity}
+		if item.Product != nil {
+			line.Name = item.Product.Name
+			line.UnitPrice = item.Product.Price
+			line.LineTotal = item.Product.Price.Mul(item.Quantity)
+			line.OnSale = item.Product.OnSale
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// diffPricing compares a new breakdown with an order's current totals and
+// price lines. Lines are matched by product; repeated products are combined.
+func diffPricing(order *model.Order, previous []model.PriceLine, current *model.PriceBreakdown) model.PricingDiff {
+	change := func(previous *model.Money, current model.Money) model.Money {
+		if previous == nil {
+			return current
+		}
+		return current - *previous
+	}
+
+	diff := model.PricingDiff{
+		Subtotal: change(order.Subtotal, current.Subtotal),
+		Discount: change(order.Discount, current.Discount),
+		Total:    change(order.Total, current.Total),
+		Lines:    []model.PriceLineDiff{},
+	}
+
+	before, beforeOrder := combineLines(previous)
+	after, afterOrder := combineLines(current.Lines)
+
+	for _, id := range afterOrder {
+		line := after[id]
+		prev := before[id]
+		line.PreviousQuantity = prev.Quantity
+		line.PreviousUnitPrice = prev.UnitPrice
+		line.PreviousLineTotal = prev.LineTotal
+		if line.Quantity != line.PreviousQuantity || line.UnitPrice != line.PreviousUnitPrice || line.LineTotal != line.PreviousLineTotal {
+			diff.Lines = append(diff.Lines, line)
+		}
+	}
+	for _, id := range beforeOrder {
+		if _, ok := after[id]; ok {
+			continue
+		}
+		prev := before[id]
+		diff.Lines = append(diff.Lines, model.PriceLineDiff{
+			ProductID:         id,
+			PreviousQuantity:  prev.Quantity,
+			PreviousUnitPrice: prev.UnitPrice,
+			PreviousLineTotal: prev.LineTotal,
+		})
+	}
+
+	return diff
+}
+
+// combineLines sums price lines per product, returning them keyed by product
+// ID together with the product IDs in order of first appearance.
+func combineLines(lines []model.PriceLine) (map[string]model.PriceLineDiff, []string) {
+	combined := make(map[string]model.PriceLineDiff, len(lines))
+	var order []string
+	for _, line := range lines {
+		c, ok := combined[line.ProductID]
+		if !ok {
+			order = append(order, line.ProductID)
+		}
+		c.ProductID = line.ProductID
+		c.Quantity += line.Quantity
+		c.UnitPrice = line.UnitPrice
+		c.LineTotal += line.LineTotal
+		combined[line.ProductID] = c
+	}
+	return combined, order
+}
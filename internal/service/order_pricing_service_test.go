@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderPricingRepository is a mock implementation of OrderPricingRepository.
+type MockOrderPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderPricingRepository) Latest(ctx context.Context, orderID uuid.UUID) (*model.OrderPricingVersion, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderPricingVersion), args.Error(1)
+}
+
+func (m *MockOrderPricingRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderPricingVersion, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OrderPricingVersion), args.Error(1)
+}
+
+func (m *MockOrderPricingRepository) Create(ctx context.Context, version *model.OrderPricingVersion) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+// MockMetadataSource is a mock implementation of coupon.MetadataSource.
+type MockMetadataSource struct {
+	mock.Mock
+}
+
+func (m *MockMetadataSource) GetByCode(ctx context.Context, code string) (*model.CouponDiscount, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponDiscount), args.Error(1)
+}
+
+func TestOrderPricingService_Recalculate(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	engine := pricing.NewEngine(pricing.Config{Currency: "AUD"})
+
+	orderID := uuid.New()
+	couponCode := "QUARTER25"
+	percentOff := 25.0
+	moneyPtr := func(f float64) *model.Money { m := model.NewMoney(f); return &m }
+
+	// Placed as 2 x P001 at 10.00 and 1 x P002 at 5.00; P001 has since
+	// risen to 12.00 and P002's quantity was edited to 3
+	items := []model.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2, Product: &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(10.00)}},
+		{ID: uuid.New(), OrderID: orderID, ProductID: "P002", Quantity: 3, Product: &model.ProductSnapshot{Name: "Product 2", Category: "Cat2", Price: model.NewMoney(5.00)}},
+	}
+	products := []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(12.00), Category: "Cat1"},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(5.00), Category: "Cat2"},
+	}
+	placed := func() *model.Order {
+		return &model.Order{ID: orderID, Status: model.OrderStatusPending, Subtotal: moneyPtr(25.00), Discount: moneyPtr(0), Total: moneyPtr(25.00)}
+	}
+
+	t.Run("Records a new version with diffs", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+
+		orderRepo.On("GetByID", ctx, orderID).Return(placed(), items, nil)
+		productRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(products, nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(nil, nil)
+		pricingRepo.On("Create", ctx, mock.MatchedBy(func(v *model.OrderPricingVersion) bool {
+			return v.OrderID == orderID && v.Version == 2 && *v.Total == model.NewMoney(39.00) && *v.CreatedBy == "admin-1" && v.Breakdown != nil
+		})).Return(nil)
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, nil, engine, logger)
+		result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, model.NewMoney(39.00), result.Pricing.Total)
+		assert.Equal(t, model.NewMoney(25.00), *result.PreviousTotal)
+		assert.Equal(t, model.NewMoney(14.00), result.Diff.Subtotal)
+		assert.Equal(t, model.NewMoney(14.00), result.Diff.Total)
+		assert.Equal(t, []model.PriceLineDiff{
+			{ProductID: "P001", PreviousQuantity: 2, Quantity: 2, PreviousUnitPrice: model.NewMoney(10.00), UnitPrice: model.NewMoney(12.00), PreviousLineTotal: model.NewMoney(20.00), LineTotal: model.NewMoney(24.00)},
+		}, result.Diff.Lines)
+		pricingRepo.AssertExpectations(t)
+	})
+
+	t.Run("Diffs lines against the latest version", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+
+		order := placed()
+		order.Subtotal, order.Total = moneyPtr(34.00), moneyPtr(34.00)
+		orderRepo.On("GetByID", ctx, orderID).Return(order, items[:1], nil)
+		productRepo.On("GetByIDs", ctx, []string{"P001"}).Return(products[:1], nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(&model.OrderPricingVersion{
+			OrderID: orderID,
+			Version: 2,
+			Breakdown: &model.PriceBreakdown{Lines: []model.PriceLine{
+				{ProductID: "P001", Quantity: 2, UnitPrice: model.NewMoney(12.00), LineTotal: model.NewMoney(24.00)},
+				{ProductID: "P002", Quantity: 2, UnitPrice: model.NewMoney(5.00), LineTotal: model.NewMoney(10.00)},
+			}},
+		}, nil)
+		pricingRepo.On("Create", ctx, mock.MatchedBy(func(v *model.OrderPricingVersion) bool {
+			return v.Version == 3
+		})).Return(nil)
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, nil, engine, logger)
+		result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Version)
+		assert.Equal(t, model.NewMoney(-10.00), result.Diff.Total)
+		assert.Equal(t, []model.PriceLineDiff{
+			{ProductID: "P002", PreviousQuantity: 2, PreviousUnitPrice: model.NewMoney(5.00), PreviousLineTotal: model.NewMoney(10.00)},
+		}, result.Diff.Lines)
+	})
+
+	t.Run("Unchanged pricing is not recorded", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+
+		order := placed()
+		order.Subtotal, order.Total = moneyPtr(39.00), moneyPtr(39.00)
+		orderRepo.On("GetByID", ctx, orderID).Return(order, items, nil)
+		productRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(products, nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(&model.OrderPricingVersion{
+			OrderID: orderID,
+			Version: 2,
+			Breakdown: &model.PriceBreakdown{Lines: []model.PriceLine{
+				{ProductID: "P001", Quantity: 2, UnitPrice: model.NewMoney(12.00), LineTotal: model.NewMoney(24.00)},
+				{ProductID: "P002", Quantity: 3, UnitPrice: model.NewMoney(5.00), LineTotal: model.NewMoney(15.00)},
+			}},
+		}, nil)
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, nil, engine, logger)
+		result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+		assert.Equal(t, 2, result.Version)
+		assert.Empty(t, result.Diff.Lines)
+		pricingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Applies the coupon's current discount", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+		discounts := new(MockMetadataSource)
+
+		order := placed()
+		order.CouponCode = &couponCode
+		orderRepo.On("GetByID", ctx, orderID).Return(order, items, nil)
+		productRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(products, nil)
+		discounts.On("GetByCode", ctx, couponCode).Return(&model.CouponDiscount{Code: couponCode, PercentOff: &percentOff}, nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(nil, nil)
+		pricingRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, discounts, engine, logger)
+		result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, model.NewMoney(9.75), result.Pricing.Discount)
+		assert.Equal(t, model.NewMoney(29.25), result.Pricing.Total)
+		assert.Equal(t, model.NewMoney(9.75), result.Diff.Discount)
+	})
+
+	t.Run("Drops a coupon the items no longer qualify for", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+		discounts := new(MockMetadataSource)
+
+		order := placed()
+		order.CouponCode = &couponCode
+		orderRepo.On("GetByID", ctx, orderID).Return(order, items, nil)
+		productRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(products, nil)
+		discounts.On("GetByCode", ctx, couponCode).
+			Return(&model.CouponDiscount{Code: couponCode, PercentOff: &percentOff, MinSubtotal: model.NewMoney(100)}, nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(nil, nil)
+		pricingRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, discounts, engine, logger)
+		result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.NoError(t, err)
+		assert.Zero(t, result.Pricing.Discount)
+		assert.Equal(t, model.NewMoney(39.00), result.Pricing.Total)
+	})
+
+	tests := []struct {
+		name        string
+		order       *model.Order
+		items       []model.OrderItem
+		products    []model.Product
+		createError error
+		expectedErr error
+	}{
+		{
+			name:        "Order not found",
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name:        "Cancelled order",
+			order:       &model.Order{ID: orderID, Status: model.OrderStatusCancelled},
+			expectedErr: model.ErrOrderCancelled,
+		},
+		{
+			name:        "Fulfillment started",
+			order:       placed(),
+			items:       []model.OrderItem{{ProductID: "P001", Quantity: 2, FulfilledQuantity: 1}},
+			expectedErr: model.ErrFulfillmentStarted,
+		},
+		{
+			name:        "Product no longer in the catalogue",
+			order:       placed(),
+			items:       items,
+			products:    products[:1],
+			expectedErr: model.ErrProductNotFound,
+		},
+		{
+			name:        "Concurrent recalculation",
+			order:       placed(),
+			items:       items,
+			products:    products,
+			createError: model.ErrPricingConflict,
+			expectedErr: model.ErrPricingConflict,
+		},
+		{
+			name:        "Shipped while repricing",
+			order:       placed(),
+			items:       items,
+			products:    products,
+			createError: model.ErrFulfillmentStarted,
+			expectedErr: model.ErrFulfillmentStarted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			productRepo := new(MockProductRepository)
+			pricingRepo := new(MockOrderPricingRepository)
+
+			orderRepo.On("GetByID", ctx, orderID).Return(tt.order, tt.items, nil)
+			productRepo.On("GetByIDs", ctx, mock.Anything).Return(tt.products, nil).Maybe()
+			pricingRepo.On("Latest", ctx, orderID).Return(nil, nil).Maybe()
+			pricingRepo.On("Create", ctx, mock.Anything).Return(tt.createError).Maybe()
+
+			svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, nil, engine, logger)
+			result, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Nil(t, result)
+		})
+	}
+
+	t.Run("Repository error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		productRepo := new(MockProductRepository)
+		pricingRepo := new(MockOrderPricingRepository)
+
+		orderRepo.On("GetByID", ctx, orderID).Return(placed(), items, nil)
+		productRepo.On("GetByIDs", ctx, mock.Anything).Return(products, nil)
+		pricingRepo.On("Latest", ctx, orderID).Return(nil, nil)
+		pricingRepo.On("Create", ctx, mock.Anything).Return(errors.New("database error"))
+
+		svc := NewOrderPricingService(orderRepo, productRepo, pricingRepo, nil, engine, logger)
+		_, err := svc.Recalculate(ctx, orderID, "admin-1")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to record order pricing")
+	})
+}
@@ -2,38 +2,160 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"mini-kart/internal/coupon"
+	"mini-kart/internal/events"
 	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
 	"mini-kart/internal/repository"
+	"mini-kart/internal/saga"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
+// maxOrderTxAttempts bounds how often an order transaction is run when
+// PostgreSQL keeps aborting it in favour of concurrent transactions.
+const maxOrderTxAttempts = 3
+
 // orderService implements OrderService.
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	productRepo repository.ProductRepository
-	validator   coupon.Validator
-	logger      zerolog.Logger
+	orderRepo    repository.OrderRepository
+	productRepo  repository.ProductRepository
+	validator    coupon.CodeValidator
+	reservations repository.CouponReservationRepository
+	flashSales   repository.FlashSaleRepository
+	idempotency  repository.IdempotencyRepository
+	sources      []string
+	pricing      pricing.Engine
+	strictFields bool
+	failOpen     bool
+	webhooks     repository.WebhookRepository
+	endpoints    []string
+	outbox       repository.EventOutboxRepository
+	sagas        *saga.Coordinator
+	logger       zerolog.Logger
+}
+
+// OrderServiceOption configures optional order service dependencies.
+type OrderServiceOption func(*orderService)
+
+// WithCouponReservations enables redemption limit enforcement for coupon codes.
+// Reservations are taken inside the order transaction and released on rollback.
+func WithCouponReservations(reservations repository.CouponReservationRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.reservations = reservations
+	}
+}
+
+// WithFlashSales limits orders for products on a flash sale to the quantity
+// allocated to it. Quantities are reserved inside the order transaction and
+// released when it is rolled back.
+func WithFlashSales(flashSales repository.FlashSaleRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.flashSales = flashSales
+	}
+}
+
+// WithIdempotency enables Idempotency-Key handling. Requests repeating a key
+// return the order created by the first request instead of a new one.
+func WithIdempotency(idempotency repository.IdempotencyRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.idempotency = idempotency
+	}
+}
+
+// WithAllowedSources restricts order sources to the given channels.
+// Entries ending in ":*" accept any sub-channel, e.g. "marketplace:*".
+// When no sources are configured, any source is accepted.
+func WithAllowedSources(sources []string) OrderServiceOption {
+	return func(s *orderService) {
+		s.sources = sources
+	}
+}
+
+// WithStrictOrderFields rejects order requests carrying fields this server
+// does not recognise with model.ErrUnknownOrderFields. By default such fields
+// are preserved in the order's metadata.
+func WithStrictOrderFields(strict bool) OrderServiceOption {
+	return func(s *orderService) {
+		s.strictFields = strict
+	}
+}
+
+// WithCouponFailOpen accepts coupon codes the validator could not check,
+// because the lookup timed out or the coupon files are still loading, instead
+// of rejecting the order. The code's discount is still resolved, and the order
+// is flagged with a coupon warning for review.
+func WithCouponFailOpen(failOpen bool) OrderServiceOption {
+	return func(s *orderService) {
+		s.failOpen = failOpen
+	}
+}
+
+// WithPricing computes a price breakdown for created orders using the shared pricing engine.
+func WithPricing(engine pricing.Engine) OrderServiceOption {
+	return func(s *orderService) {
+		s.pricing = engine
+	}
+}
+
+// WithWebhooks announces created and cancelled orders to the given webhook
+// endpoints. Deliveries are added to the outbox in the order's transaction
+// and posted by the webhook dispatcher.
+func WithWebhooks(webhooks repository.WebhookRepository, endpoints []string) OrderServiceOption {
+	return func(s *orderService) {
+		s.webhooks = webhooks
+		s.endpoints = endpoints
+	}
+}
+
+// WithEvents publishes order domain events for the analytics pipeline.
+// Events are appended to the outbox in the order's transaction and published
+// by the event relay.
+func WithEvents(outbox repository.EventOutboxRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.outbox = outbox
+	}
+}
+
+// WithSagas runs the coordinator's steps, such as payment authorisation and
+// stock reservation, before an order is stored. Orders whose steps fail are
+// not created, and the steps' effects are compensated if the order is not
+// stored after all.
+func WithSagas(sagas *saga.Coordinator) OrderServiceOption {
+	return func(s *orderService) {
+		s.sagas = sagas
+	}
 }
 
 // NewOrderService creates a new order service.
 func NewOrderService(
 	orderRepo repository.OrderRepository,
 	productRepo repository.ProductRepository,
-	validator coupon.Validator,
+	validator coupon.CodeValidator,
 	logger zerolog.Logger,
+	opts ...OrderServiceOption,
 ) OrderService {
-	return &orderService{
+	s := &orderService{
 		orderRepo:   orderRepo,
 		productRepo: productRepo,
 		validator:   validator,
 		logger:      logger.With().Str("service", "order").Logger(),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // CreateOrder creates a new order with optional coupon code validation.
@@ -43,32 +165,185 @@ func (s *orderService) CreateOrder(ctx context.Context, req *model.OrderRequest)
 		return nil, err
 	}
 
-	// Validate coupon code if provided
+	// Hold coupon codes in canonical form, so a code is redeemed once
+	// however its case is typed
 	if req.CouponCode != nil && *req.CouponCode != "" {
-		if err := s.validator.Validate(ctx, *req.CouponCode); err != nil {
-			s.logger.Warn().
-				Str("coupon_code", *req.CouponCode).
-				Err(err).
-				Msg("invalid coupon code")
-			return nil, err
+		code := s.validator.Normalize(*req.CouponCode)
+		req.CouponCode = &code
+	}
+
+	// Return the original order when a request is retried with the same key
+	var requestHash string
+	if s.idempotency != nil && req.IdempotencyKey != "" {
+		requestHash = hashOrderRequest(req)
+		resp, err := s.replay(ctx, req, requestHash)
+		if resp != nil || err != nil {
+			return resp, err
 		}
+	}
+
+	// Validate coupon code if provided and resolve the discount it grants
+	discount, err := resolveCoupon(ctx, s.validator, req.CouponCode, req.CustomerID)
+	var couponWarning *string
+	if s.failOpen && couponUnavailable(err) {
+		// Honour the code rather than block promotional checkouts while the
+		// coupon files can't be checked
+		s.logger.Warn().
+			Str("coupon_code", *req.CouponCode).
+			Err(err).
+			Msg("coupon code could not be validated, accepting it")
+		warning := err.(*model.DomainError).Code
+		couponWarning = &warning
+		discount, err = resolveUnverifiedCoupon(ctx, s.validator, *req.CouponCode, req.CustomerID)
+	}
+	if err != nil {
+		s.logger.Warn().
+			Str("coupon_code", *req.CouponCode).
+			Err(err).
+			Msg("invalid coupon code")
+		return nil, err
+	}
+	if req.CouponCode != nil && *req.CouponCode != "" {
 		s.logger.Debug().Str("coupon_code", *req.CouponCode).Msg("coupon code validated")
 	}
 
-	// Extract product IDs and validate they exist
+	// Extract product IDs and load the products being ordered
 	productIDs := make([]string, len(req.Items))
 	for i, item := range req.Items {
 		productIDs[i] = item.ProductID
 	}
 
-	if err := s.productRepo.ValidateProductsExist(ctx, productIDs); err != nil {
-		s.logger.Warn().
-			Int("product_count", len(productIDs)).
-			Err(err).
-			Msg("product validation failed")
-		return nil, err
+	products, err := s.productRepo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to retrieve product details")
+		return nil, fmt.Errorf("failed to retrieve product details: %w", err)
+	}
+
+	// Items are charged the prices, including sales, running when the order
+	// is placed
+	pricedAt := time.Now()
+	productsByID := make(map[string]model.Product, len(products))
+	for i, p := range products {
+		products[i] = p.WithEffectivePrice(pricedAt)
+		productsByID[p.ID] = products[i]
+	}
+
+	for _, id := range productIDs {
+		if _, ok := productsByID[id]; !ok {
+			s.logger.Warn().
+				Int("product_count", len(productIDs)).
+				Str("product_id", id).
+				Msg("product validation failed")
+			return nil, model.ErrProductNotFound
+		}
 	}
 
+	// Price the order with the same engine used by price previews, before it
+	// is stored, so its totals are recorded with it
+	var breakdown *model.PriceBreakdown
+	if s.pricing != nil {
+		breakdown, err = s.pricing.Price(ctx, pricing.Input{
+			Items:    req.Items,
+			Products: products,
+			Discount: discount,
+			At:       pricedAt,
+		})
+		if err != nil {
+			if err == model.ErrCouponNotApplicable || err == model.ErrCouponMinSubtotal {
+				s.logger.Warn().Str("coupon_code", *req.CouponCode).Err(err).Msg("coupon does not apply to the order")
+				return nil, err
+			}
+			s.logger.Error().Err(err).Msg("failed to price order")
+			return nil, fmt.Errorf("failed to price order: %w", err)
+		}
+	}
+
+	// Run the order's side effects on external systems before it is stored,
+	// so an order is never created without its payment or stock
+	orderID := uuid.New()
+	var orderSaga *model.Saga
+	if s.sagas != nil {
+		if orderSaga, err = s.beginSaga(ctx, orderID, req, breakdown); err != nil {
+			return nil, err
+		}
+	}
+
+	// Store the order, running the transaction again when PostgreSQL aborts
+	// it in favour of a concurrent one, e.g. checkouts deadlocking on a coupon
+	for attempt := 1; ; attempt++ {
+		resp, err := s.storeOrder(ctx, orderID, req, products, productsByID, pricedAt, breakdown, couponWarning, requestHash, orderSaga)
+		if errors.Is(err, repository.ErrRetryable) && attempt < maxOrderTxAttempts {
+			s.logger.Warn().Err(err).Int("attempt", attempt).Msg("order transaction aborted by a concurrent transaction, retrying")
+			continue
+		}
+
+		// Undo the saga unless its order was stored. A saga that timed out
+		// is already being compensated by the recovery loop
+		if orderSaga != nil && !errors.Is(err, saga.ErrTimedOut) {
+			if err != nil {
+				s.sagas.Compensate(ctx, orderSaga, err)
+			} else if resp.ID != orderID {
+				s.sagas.Compensate(ctx, orderSaga, errors.New("order replaced by a concurrent request with the same idempotency key"))
+			}
+		}
+		return resp, err
+	}
+}
+
+// beginSaga runs the saga steps for an order about to be stored. A step
+// refused by its system fails the order with the matching domain error.
+func (s *orderService) beginSaga(ctx context.Context, orderID uuid.UUID, req *model.OrderRequest, breakdown *model.PriceBreakdown) (*model.Saga, error) {
+	payload := model.OrderSagaPayload{
+		OrderID:    orderID,
+		CustomerID: req.CustomerID,
+		CouponCode: req.CouponCode,
+		Items:      req.Items,
+	}
+	if breakdown != nil {
+		payload.Total = &breakdown.Total
+		payload.Currency = breakdown.Currency
+	}
+
+	orderSaga, err := s.sagas.Begin(ctx, payload)
+	if err == nil {
+		return orderSaga, nil
+	}
+
+	var rejected *saga.RejectedError
+	if !errors.As(err, &rejected) {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to run order saga")
+		return nil, model.ErrOrderStepFailed
+	}
+
+	s.logger.Warn().Err(err).Str("order_id", orderID.String()).Msg("order rejected by saga step")
+	switch rejected.Step {
+	case saga.StepPayment:
+		return nil, model.ErrPaymentDeclined
+	case saga.StepStock:
+		return nil, model.ErrInsufficientStock
+	case saga.StepCoupon:
+		return nil, model.ErrCouponRejected
+	default:
+		return nil, model.ErrOrderStepFailed
+	}
+}
+
+// storeOrder creates an order and its items in one transaction, together with
+// its coupon redemption and idempotency key, and completes its saga. If a
+// concurrent request with the same idempotency key committed first, that
+// request's order is returned.
+func (s *orderService) storeOrder(
+	ctx context.Context,
+	orderID uuid.UUID,
+	req *model.OrderRequest,
+	products []model.Product,
+	productsByID map[string]model.Product,
+	pricedAt time.Time,
+	breakdown *model.PriceBreakdown,
+	couponWarning *string,
+	requestHash string,
+	orderSaga *model.Saga,
+) (*model.OrderResponse, error) {
 	// Start transaction
 	tx, err := s.orderRepo.BeginTx(ctx)
 	if err != nil {
@@ -76,25 +351,74 @@ func (s *orderService) CreateOrder(ctx context.Context, req *model.OrderRequest)
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
-	// Ensure transaction is rolled back on error
+	// Ensure transaction is rolled back on error, giving back flash sale
+	// quantities reserved outside it
+	var flashSaleReservations []model.FlashSaleReservation
 	defer func() {
 		if err != nil {
 			if rbErr := tx.Rollback(ctx); rbErr != nil {
 				s.logger.Error().Err(rbErr).Msg("failed to rollback transaction")
 			}
+			if len(flashSaleReservations) > 0 {
+				if relErr := s.flashSales.Release(context.WithoutCancel(ctx), flashSaleReservations); relErr != nil {
+					s.logger.Error().Err(relErr).Str("order_id", orderID.String()).Msg("failed to release flash sale reservations")
+				}
+			}
 		}
 	}()
 
+	// Reserve a coupon redemption for the caller; the row lock is held until
+	// commit or rollback
+	if s.reservations != nil && req.CouponCode != nil && *req.CouponCode != "" {
+		redemption := model.CouponRedemption{Code: *req.CouponCode, OrderID: orderID, RedeemedBy: req.Caller}
+		if err = s.reservations.Reserve(ctx, tx, redemption); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Str("coupon_code", *req.CouponCode).
+				Msg("failed to reserve coupon redemption")
+			return nil, err
+		}
+	}
+
+	// Reserve the quantities of products on a flash sale
+	if s.flashSales != nil {
+		reservations := model.FlashSaleReservations(orderID, req.Items)
+		var claimed []model.FlashSaleReservation
+		if claimed, err = s.flashSales.Reserve(ctx, tx, reservations); err != nil {
+			if err != model.ErrInsufficientStock && err != model.ErrOrderStepFailed {
+				s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to reserve flash sale quantities")
+				return nil, fmt.Errorf("failed to create order: %w", err)
+			}
+			return nil, err
+		}
+		// Only what was claimed is given back if the order is rolled back
+		flashSaleReservations = claimed
+	}
+
 	// Create order
 	now := time.Now()
 	order := &model.Order{
-		ID:         uuid.New(),
-		CouponCode: req.CouponCode,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:              orderID,
+		CustomerID:      req.CustomerID,
+		CouponCode:      req.CouponCode,
+		CouponWarning:   couponWarning,
+		Source:          req.Source,
+		Status:          model.OrderStatusPending,
+		Metadata:        req.Metadata,
+		ShippingAddress: req.ShippingAddress,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if breakdown != nil {
+		order.Subtotal = &breakdown.Subtotal
+		order.Discount = &breakdown.Discount
+		order.Total = &breakdown.Total
 	}
 
 	if err = s.orderRepo.CreateOrder(ctx, tx, order); err != nil {
+		if err == model.ErrCustomerNotFound {
+			return nil, err
+		}
 		s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to create order")
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
@@ -107,6 +431,7 @@ func (s *orderService) CreateOrder(ctx context.Context, req *model.OrderRequest)
 			OrderID:   order.ID,
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
+			Product:   productsByID[item.ProductID].SnapshotAt(pricedAt),
 		}
 	}
 
@@ -116,32 +441,105 @@ func (s *orderService) CreateOrder(ctx context.Context, req *model.OrderRequest)
 			Str("order_id", order.ID.String()).
 			Int("item_count", len(orderItems)).
 			Msg("failed to create order items")
+		if errors.Is(err, repository.ErrForeignKeyViolation) {
+			// A product was deleted since it was loaded
+			return nil, model.ErrProductNotFound
+		}
 		return nil, fmt.Errorf("failed to create order items: %w", err)
 	}
 
+	if req.Delegation != nil {
+		audit := delegationAuditEntry(req.Delegation, model.AuditActionOrderCreate, map[string]any{
+			"orderId": order.ID.String(),
+		})
+		if err = s.orderRepo.RecordAudit(ctx, tx, audit); err != nil {
+			s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to record order audit entry")
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+	}
+
+	resp := &model.OrderResponse{
+		ID:                order.ID,
+		CustomerID:        order.CustomerID,
+		Source:            order.Source,
+		Status:            order.Status,
+		Metadata:          order.Metadata,
+		ShippingAddress:   order.ShippingAddress,
+		FulfillmentStatus: model.FulfillmentStatusUnfulfilled,
+		Items:             orderItems,
+		Products:          products,
+		Pricing:           breakdown,
+		Subtotal:          order.Subtotal,
+		Discount:          order.Discount,
+		Total:             order.Total,
+		CouponWarning:     order.CouponWarning,
+	}
+
+	// Announce the order to webhook endpoints once the transaction commits
+	var deliveries []model.WebhookDelivery
+	if deliveries, err = s.webhookDeliveries(model.WebhookOrderCreated, order.ID, resp); err != nil {
+		return nil, err
+	}
+	if len(deliveries) > 0 {
+		if err = s.webhooks.Enqueue(ctx, tx, deliveries); err != nil {
+			s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to enqueue order webhooks")
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+	}
+
+	if s.outbox != nil {
+		var event model.OutboxEvent
+		if event, err = events.New(events.TypeOrderCreated, events.AggregateOrder, order.ID, resp); err != nil {
+			return nil, err
+		}
+		if err = s.outbox.Append(ctx, tx, []model.OutboxEvent{event}); err != nil {
+			s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to append order event")
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+	}
+
+	// Record the idempotency key with the order so neither exists without the other
+	if requestHash != "" {
+		err = s.idempotency.Create(ctx, tx, &model.IdempotencyKey{
+			Key:         req.IdempotencyKey,
+			Caller:      req.Caller,
+			RequestHash: requestHash,
+			OrderID:     order.ID,
+			CreatedAt:   now,
+		})
+		if err == model.ErrIdempotencyConflict {
+			// A concurrent request with the same key committed first; this
+			// order is rolled back and the other one returned
+			return s.replay(ctx, req, requestHash)
+		}
+		if err != nil {
+			s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to record idempotency key")
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+	}
+
+	// Complete the saga with the order, so the recovery loop compensates it
+	// exactly when the order is not stored
+	if orderSaga != nil {
+		if err = s.sagas.Complete(ctx, tx, orderSaga); err != nil {
+			s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to complete order saga")
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
+		err = repository.Classify(err)
 		s.logger.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to commit transaction")
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
-	// Retrieve product details
-	products, err := s.productRepo.GetByIDs(ctx, productIDs)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to retrieve product details")
-		return nil, fmt.Errorf("failed to retrieve product details: %w", err)
-	}
-
 	s.logger.Info().
 		Str("order_id", order.ID.String()).
 		Int("item_count", len(orderItems)).
 		Msg("order created successfully")
 
-	return &model.OrderResponse{
-		ID:       order.ID,
-		Items:    orderItems,
-		Products: products,
-	}, nil
+	return resp, nil
 }
 
 // GetByID retrieves an order by its ID with all items and product details.
@@ -157,24 +555,407 @@ func (s *orderService) GetByID(ctx context.Context, id uuid.UUID) (*model.OrderR
 		return nil, nil
 	}
 
-	// Extract product IDs
-	productIDs := make([]string, len(items))
-	for i, item := range items {
-		productIDs[i] = item.ProductID
+	return &model.OrderResponse{
+		ID:                order.ID,
+		CustomerID:        order.CustomerID,
+		Source:            order.Source,
+		Status:            order.Status,
+		Metadata:          order.Metadata,
+		ShippingAddress:   order.ShippingAddress,
+		FulfillmentStatus: model.DeriveFulfillmentStatus(items),
+		Items:             items,
+		Products:          snapshotProducts(items),
+		Subtotal:          order.Subtotal,
+		Discount:          order.Discount,
+		Total:             order.Total,
+		CouponWarning:     order.CouponWarning,
+	}, nil
+}
+
+// GetByRef retrieves the order created or imported with a client reference.
+func (s *orderService) GetByRef(ctx context.Context, ref string) (*model.OrderResponse, error) {
+	id, err := s.orderRepo.GetIDByRef(ctx, ref)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to look up order reference")
+		return nil, fmt.Errorf("failed to look up order reference: %w", err)
 	}
 
-	// Retrieve product details
-	products, err := s.productRepo.GetByIDs(ctx, productIDs)
+	if id == nil {
+		s.logger.Debug().Msg("no order with reference")
+		return nil, nil
+	}
+
+	return s.GetByID(ctx, *id)
+}
+
+// couponUnavailable reports whether a coupon validation error means the
+// coupon files could not be checked, rather than that the code is invalid.
+func couponUnavailable(err error) bool {
+	return err == model.ErrCouponValidationTimeout || err == model.ErrCouponsLoading
+}
+
+// replay returns the order previously created with the request's idempotency
+// key, or nil if its caller has not used the key. Reusing a key for a
+// different request returns model.ErrIdempotencyConflict.
+func (s *orderService) replay(ctx context.Context, req *model.OrderRequest, requestHash string) (*model.OrderResponse, error) {
+	existing, err := s.idempotency.Get(ctx, req.Caller, req.IdempotencyKey)
 	if err != nil {
-		s.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to retrieve product details")
-		return nil, fmt.Errorf("failed to retrieve product details: %w", err)
+		s.logger.Error().Err(err).Msg("failed to check idempotency key")
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
 	}
 
-	return &model.OrderResponse{
-		ID:       order.ID,
-		Items:    items,
-		Products: products,
-	}, nil
+	if existing.RequestHash != requestHash {
+		s.logger.Warn().Str("order_id", existing.OrderID.String()).Msg("idempotency key reused for a different request")
+		return nil, model.ErrIdempotencyConflict
+	}
+
+	resp, err := s.GetByID(ctx, existing.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("order %s for idempotency key not found", existing.OrderID)
+	}
+	resp.Replayed = true
+
+	s.logger.Info().Str("order_id", existing.OrderID.String()).Msg("returning order for repeated idempotency key")
+
+	return resp, nil
+}
+
+// idempotencySweepBatchSize is the number of expired idempotency keys
+// deleted at once.
+const idempotencySweepBatchSize = 1000
+
+// RunIdempotencyKeySweeper deletes expired idempotency keys every interval
+// until ctx is cancelled, in batches until none are left. Failed runs are
+// logged and retried at the next interval.
+func RunIdempotencyKeySweeper(ctx context.Context, keys repository.IdempotencyRepository, interval time.Duration, logger zerolog.Logger) {
+	logger = logger.With().Str("component", "idempotency-key-sweeper").Logger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			total := 0
+			for ctx.Err() == nil {
+				deleted, err := keys.DeleteExpired(ctx, idempotencySweepBatchSize)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error().Err(err).Msg("idempotency key sweep failed")
+					}
+					break
+				}
+				total += deleted
+				if deleted < idempotencySweepBatchSize {
+					break
+				}
+			}
+			if total > 0 {
+				logger.Info().Int("deleted", total).Msg("deleted expired idempotency keys")
+			}
+		}
+	}
+}
+
+// webhookDeliveries builds an outbox delivery of an event about an order for
+// each webhook endpoint. Returns nil when webhooks are not configured.
+func (s *orderService) webhookDeliveries(eventType string, orderID uuid.UUID, data any) ([]model.WebhookDelivery, error) {
+	if s.webhooks == nil || len(s.endpoints) == 0 {
+		return nil, nil
+	}
+
+	event := model.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		deliveries = append(deliveries, model.WebhookDelivery{
+			ID:        uuid.New(),
+			EventID:   event.ID,
+			EventType: eventType,
+			OrderID:   orderID,
+			Endpoint:  endpoint,
+			Payload:   payload,
+		})
+	}
+	return deliveries, nil
+}
+
+// hashOrderRequest fingerprints the request fields that determine the order
+// created, so a reused idempotency key can be told apart from a retry.
+func hashOrderRequest(req *model.OrderRequest) string {
+	// Marshalling these types cannot fail; map keys are sorted. The customer
+	// and shipping address are omitted when unset, so keys recorded before
+	// orders had them keep their fingerprints
+	data, _ := json.Marshal(struct {
+		CustomerID      *uuid.UUID               `json:"customerId,omitempty"`
+		CouponCode      *string                  `json:"couponCode"`
+		Source          *string                  `json:"source"`
+		Items           []model.OrderItemRequest `json:"items"`
+		Metadata        model.Metadata           `json:"metadata"`
+		ShippingAddress *model.Address           `json:"shippingAddress,omitempty"`
+	}{req.CustomerID, req.CouponCode, req.Source, req.Items, req.Metadata, req.ShippingAddress})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotProducts builds the product list for an order from the snapshots
+// captured on its items, so orders render as they were placed even after the
+// catalogue changes. Products are priced at what the items were charged,
+// sale or not. Items without a snapshot are omitted.
+func snapshotProducts(items []model.OrderItem) []model.Product {
+	products := make([]model.Product, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Product == nil || seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		products = append(products, model.Product{
+			ID:             item.ProductID,
+			Name:           item.Product.Name,
+			Price:          item.Product.Price,
+			Category:       item.Product.Category,
+			EffectivePrice: item.Product.Price,
+			OnSale:         item.Product.OnSale,
+		})
+	}
+	return products
+}
+
+// List retrieves orders with optional source and product filtering and pagination.
+func (s *orderService) List(ctx context.Context, filter model.OrderFilter) ([]model.Order, model.Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	orders, err := s.orderRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("source", filter.Source).
+			Str("product_id", filter.ProductID).
+			Int("limit", filter.Limit).
+			Int("offset", filter.Offset).
+			Msg("failed to list orders")
+		return nil, model.Page{}, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	total, err := s.orderRepo.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("source", filter.Source).
+			Str("product_id", filter.ProductID).
+			Msg("failed to count orders")
+		return nil, model.Page{}, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	return orders, model.Page{Limit: filter.Limit, Offset: filter.Offset, Total: total}, nil
+}
+
+// UpdateStatus moves an order to a new status. Pending orders can be
+// confirmed or cancelled and confirmed orders fulfilled or cancelled;
+// cancelled and fulfilled orders are final. Requesting the current status
+// is a no-op. Changes made by an admin on behalf of a customer are recorded
+// in the audit log.
+func (s *orderService) UpdateStatus(ctx context.Context, id uuid.UUID, status model.OrderStatus, delegation *model.Delegation) (*model.Order, error) {
+	if !status.Valid() {
+		return nil, model.ErrInvalidOrderStatus
+	}
+
+	order, _, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to get order")
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	if order.Status == status {
+		return order, nil
+	}
+	if !order.Status.CanTransitionTo(status) {
+		s.logger.Warn().
+			Str("order_id", id.String()).
+			Str("from", string(order.Status)).
+			Str("to", string(status)).
+			Msg("order status transition not allowed")
+		return nil, model.ErrStatusTransition
+	}
+
+	var audit *model.AuditEntry
+	if delegation != nil {
+		audit = delegationAuditEntry(delegation, model.AuditActionOrderStatus, map[string]any{
+			"orderId": id.String(),
+			"from":    string(order.Status),
+			"to":      string(status),
+		})
+	}
+
+	return s.changeStatus(ctx, id, order.Status, status, audit)
+}
+
+// OverrideStatus moves an order to any status, bypassing the order status
+// lifecycle, so admins can correct orders left in the wrong status. Every
+// override is recorded in the audit log with the admin and their reason.
+func (s *orderService) OverrideStatus(ctx context.Context, id uuid.UUID, override *model.StatusOverride, admin string) (*model.Order, error) {
+	if override == nil {
+		return nil, fmt.Errorf("status override cannot be nil")
+	}
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, _, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to get order")
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	if order.Status == override.Status {
+		return order, nil
+	}
+
+	s.logger.Warn().
+		Str("order_id", id.String()).
+		Str("from", string(order.Status)).
+		Str("to", string(override.Status)).
+		Str("admin", admin).
+		Str("reason", override.Reason).
+		Msg("overriding order status")
+
+	audit := &model.AuditEntry{
+		Actor:      admin,
+		Action:     model.AuditActionOrderStatusOverride,
+		EntityType: "order",
+		Details: map[string]any{
+			"orderId": id.String(),
+			"from":    string(order.Status),
+			"to":      string(override.Status),
+			"reason":  override.Reason,
+		},
+	}
+
+	return s.changeStatus(ctx, id, order.Status, override.Status, audit)
+}
+
+// changeStatus stores an order's new status with its audit entry, the
+// webhook deliveries cancelling it triggers and its status changed event.
+func (s *orderService) changeStatus(ctx context.Context, id uuid.UUID, from, status model.OrderStatus, audit *model.AuditEntry) (*model.Order, error) {
+	var (
+		deliveries []model.WebhookDelivery
+		err        error
+	)
+	if status == model.OrderStatusCancelled {
+		deliveries, err = s.webhookDeliveries(model.WebhookOrderCancelled, id, model.WebhookOrderStatus{
+			ID:             id,
+			Status:         status,
+			PreviousStatus: from,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var outboxEvents []model.OutboxEvent
+	if s.outbox != nil {
+		event, err := events.New(events.TypeOrderStatusChanged, events.AggregateOrder, id, events.OrderStatusChanged{
+			OrderID:        id,
+			Status:         status,
+			PreviousStatus: from,
+		})
+		if err != nil {
+			return nil, err
+		}
+		outboxEvents = []model.OutboxEvent{event}
+	}
+
+	updated, err := s.orderRepo.UpdateStatus(ctx, id, from, status, audit, deliveries, outboxEvents)
+	if err != nil {
+		if _, ok := err.(*model.DomainError); ok {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("order_id", id.String()).Msg("failed to update order status")
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	s.logger.Info().
+		Str("order_id", id.String()).
+		Str("from", string(from)).
+		Str("to", string(status)).
+		Msg("order status updated")
+
+	return updated, nil
+}
+
+// delegationAuditEntry describes an order operation an admin performed on
+// behalf of a customer.
+func delegationAuditEntry(delegation *model.Delegation, action string, details map[string]any) *model.AuditEntry {
+	details["onBehalfOf"] = delegation.Customer
+	return &model.AuditEntry{
+		Actor:      delegation.Admin,
+		Action:     action,
+		EntityType: "order",
+		Details:    details,
+	}
+}
+
+// CountBySource returns order counts per source channel for reporting.
+func (s *orderService) CountBySource(ctx context.Context) ([]model.SourceCount, error) {
+	counts, err := s.orderRepo.CountBySource(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to count orders by source")
+		return nil, fmt.Errorf("failed to count orders by source: %w", err)
+	}
+
+	return counts, nil
+}
+
+// isAllowedSource reports whether the source matches the configured channels.
+func (s *orderService) isAllowedSource(source string) bool {
+	if len(s.sources) == 0 {
+		return true
+	}
+
+	for _, allowed := range s.sources {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(source, prefix) && len(source) > len(prefix) {
+				return true
+			}
+			continue
+		}
+		if source == allowed {
+			return true
+		}
+	}
+
+	return false
 }
 
 // validateOrderRequest validates the order request.
@@ -187,6 +968,24 @@ func (s *orderService) validateOrderRequest(req *model.OrderRequest) error {
 		return fmt.Errorf("order must contain at least one item")
 	}
 
+	if req.Source != nil && !s.isAllowedSource(*req.Source) {
+		s.logger.Warn().Str("source", *req.Source).Msg("order source not allowed")
+		return model.ErrInvalidOrderSource
+	}
+
+	if req.ShippingAddress != nil {
+		req.ShippingAddress.Normalize()
+		if err := req.ShippingAddress.ValidateShipping(); err != nil {
+			s.logger.Warn().Msg("invalid shipping address")
+			return err
+		}
+	}
+
+	if s.strictFields && len(req.Metadata) > 0 {
+		s.logger.Warn().Strs("fields", req.UnknownFields()).Msg("order request has unknown fields")
+		return model.ErrUnknownOrderFields
+	}
+
 	// Validate each item
 	for i, item := range req.Items {
 		if item.ProductID == "" {
@@ -2,11 +2,19 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"mini-kart/internal/events"
+	"mini-kart/internal/fixtures"
 	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
+	"mini-kart/internal/repository"
+	"mini-kart/internal/saga"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -41,6 +49,11 @@ func (m *MockOrderRepository) CreateOrderItems(ctx context.Context, tx pgx.Tx, i
 	return args.Error(0)
 }
 
+func (m *MockOrderRepository) ImportOrder(ctx context.Context, ref string, order *model.Order, items []model.OrderItem) error {
+	args := m.Called(ctx, ref, order, items)
+	return args.Error(0)
+}
+
 func (m *MockOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, []model.OrderItem, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -49,9 +62,68 @@ func (m *MockOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return args.Get(0).(*model.Order), args.Get(1).([]model.OrderItem), args.Error(2)
 }
 
-// MockCouponValidator is a mock implementation of Validator.
+func (m *MockOrderRepository) GetIDByRef(ctx context.Context, ref string) (*uuid.UUID, error) {
+	args := m.Called(ctx, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*uuid.UUID), args.Error(1)
+}
+
+func (m *MockOrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]model.Order, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to model.OrderStatus, audit *model.AuditEntry, webhooks []model.WebhookDelivery, events []model.OutboxEvent) (*model.Order, error) {
+	args := m.Called(ctx, id, from, to, audit, webhooks, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) RecordAudit(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error {
+	args := m.Called(ctx, tx, entry)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]model.OrderStatusChange, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OrderStatusChange), args.Error(1)
+}
+
+func (m *MockOrderRepository) ListAuditEntries(ctx context.Context, orderID uuid.UUID) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockOrderRepository) Count(ctx context.Context, filter model.OrderFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrderRepository) CountBySource(ctx context.Context) ([]model.SourceCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SourceCount), args.Error(1)
+}
+
+// MockCouponValidator is a mock implementation of CodeValidator.
 type MockCouponValidator struct {
 	mock.Mock
+	caseInsensitive bool
 }
 
 func (m *MockCouponValidator) Validate(ctx context.Context, promoCode string) error {
@@ -59,11 +131,191 @@ func (m *MockCouponValidator) Validate(ctx context.Context, promoCode string) er
 	return args.Error(0)
 }
 
-func (m *MockCouponValidator) Close() error {
-	args := m.Called()
+func (m *MockCouponValidator) ValidateAndResolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error) {
+	args := m.Called(ctx, promoCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponDiscount), args.Error(1)
+}
+
+func (m *MockCouponValidator) Resolve(ctx context.Context, promoCode string) (*model.CouponDiscount, error) {
+	args := m.Called(ctx, promoCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CouponDiscount), args.Error(1)
+}
+
+func (m *MockCouponValidator) Check(ctx context.Context, promoCode string) model.CouponValidation {
+	args := m.Called(ctx, promoCode)
+	return args.Get(0).(model.CouponValidation)
+}
+
+// CheckEligibility accepts discounts open to every customer, so tests that
+// don't restrict their discounts need no expectation for it.
+func (m *MockCouponValidator) CheckEligibility(ctx context.Context, discount *model.CouponDiscount, customerID *uuid.UUID) error {
+	if !discount.Restricted() {
+		return nil
+	}
+	args := m.Called(ctx, discount, customerID)
+	return args.Error(0)
+}
+
+// Normalize upper-cases codes when the mock is set to match
+// case-insensitively and otherwise returns them unchanged, so tests that
+// don't care about case need no expectation for it.
+func (m *MockCouponValidator) Normalize(promoCode string) string {
+	if m.caseInsensitive {
+		return strings.ToUpper(promoCode)
+	}
+	return promoCode
+}
+
+// MockCouponReservationRepository is a mock implementation of CouponReservationRepository.
+type MockCouponReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockCouponReservationRepository) Reserve(ctx context.Context, tx pgx.Tx, redemption model.CouponRedemption) error {
+	args := m.Called(ctx, tx, redemption)
+	return args.Error(0)
+}
+
+// MockIdempotencyRepository is a mock implementation of IdempotencyRepository.
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Get(ctx context.Context, caller, key string) (*model.IdempotencyKey, error) {
+	args := m.Called(ctx, caller, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Create(ctx context.Context, tx pgx.Tx, key *model.IdempotencyKey) error {
+	args := m.Called(ctx, tx, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// MockWebhookRepository is a mock implementation of WebhookRepository.
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, tx pgx.Tx, deliveries []model.WebhookDelivery) error {
+	args := m.Called(ctx, tx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Reschedule(ctx context.Context, id uuid.UUID, delay time.Duration, lastError string) error {
+	args := m.Called(ctx, id, delay, lastError)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	args := m.Called(ctx, id, lastError)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.WebhookDelivery, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+// MockEventOutboxRepository is a mock implementation of EventOutboxRepository.
+type MockEventOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventOutboxRepository) Append(ctx context.Context, tx pgx.Tx, events []model.OutboxEvent) error {
+	args := m.Called(ctx, tx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventOutboxRepository) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error) {
+	args := m.Called(ctx, limit, publish)
+	return args.Int(0), args.Error(1)
+}
+
+// MockSagaRepository is a mock implementation of SagaRepository.
+type MockSagaRepository struct {
+	mock.Mock
+}
+
+func (m *MockSagaRepository) Create(ctx context.Context, saga *model.Saga, timeout time.Duration) error {
+	args := m.Called(ctx, saga, timeout)
+	return args.Error(0)
+}
+
+func (m *MockSagaRepository) Save(ctx context.Context, saga *model.Saga) (bool, error) {
+	args := m.Called(ctx, saga)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSagaRepository) Complete(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Saga, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Saga), args.Error(1)
+}
+
+func (m *MockSagaRepository) Reschedule(ctx context.Context, saga *model.Saga, delay time.Duration) error {
+	args := m.Called(ctx, saga, delay)
 	return args.Error(0)
 }
 
+// stubSagaStep is a saga step recording the orders it ran and undid.
+type stubSagaStep struct {
+	name        string
+	err         error
+	executed    []uuid.UUID
+	compensated []uuid.UUID
+}
+
+func (s *stubSagaStep) Name() string {
+	return s.name
+}
+
+func (s *stubSagaStep) Execute(ctx context.Context, sg *model.Saga) (json.RawMessage, error) {
+	s.executed = append(s.executed, sg.OrderID)
+	return nil, s.err
+}
+
+func (s *stubSagaStep) Compensate(ctx context.Context, sg *model.Saga, result json.RawMessage) error {
+	s.compensated = append(s.compensated, sg.OrderID)
+	return nil
+}
+
 // MockTx is a minimal mock implementation of pgx.Tx for testing.
 type MockTx struct {
 	mock.Mock
@@ -115,10 +367,7 @@ func TestOrderService_CreateOrder_Success(t *testing.T) {
 		},
 	}
 
-	testProducts := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-		{ID: "P002", Name: "Product 2", Price: 20.00, Category: "Cat2", CreatedAt: time.Now()},
-	}
+	testProducts := fixtures.Products(2)
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
@@ -128,8 +377,7 @@ func TestOrderService_CreateOrder_Success(t *testing.T) {
 	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger)
 
 	// Set up expectations
-	mockValidator.On("Validate", ctx, couponCode).Return(nil)
-	mockProductRepo.On("ValidateProductsExist", ctx, []string{"P001", "P002"}).Return(nil)
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, nil)
 	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
 	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
 	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
@@ -145,6 +393,8 @@ func TestOrderService_CreateOrder_Success(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, resp.ID)
 	assert.Len(t, resp.Items, 2)
 	assert.Len(t, resp.Products, 2)
+	require.NotNil(t, resp.Items[0].Product)
+	assert.Equal(t, "Product 1", resp.Items[0].Product.Name)
 
 	mockValidator.AssertExpectations(t)
 	mockProductRepo.AssertExpectations(t)
@@ -163,9 +413,7 @@ func TestOrderService_CreateOrder_WithoutCoupon(t *testing.T) {
 		},
 	}
 
-	testProducts := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-	}
+	testProducts := fixtures.Products(1)
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
@@ -175,7 +423,6 @@ func TestOrderService_CreateOrder_WithoutCoupon(t *testing.T) {
 	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger)
 
 	// Set up expectations (coupon validation should not be called)
-	mockProductRepo.On("ValidateProductsExist", ctx, []string{"P001"}).Return(nil)
 	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
 	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
 	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
@@ -195,6 +442,369 @@ func TestOrderService_CreateOrder_WithoutCoupon(t *testing.T) {
 	mockValidator.AssertNotCalled(t, "Validate")
 }
 
+func TestOrderService_CreateOrder_ForCustomer(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	testProducts := fixtures.Products(1)
+	newRequest := func() *model.OrderRequest {
+		return &model.OrderRequest{
+			CustomerID: &customerID,
+			Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+		}
+	}
+	forCustomer := mock.MatchedBy(func(order *model.Order) bool {
+		return order.CustomerID != nil && *order.CustomerID == customerID
+	})
+
+	t.Run("Order attached to the customer", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockTx := new(MockTx)
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, forCustomer).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockTx.On("Commit", ctx).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest())
+
+		require.NoError(t, err)
+		assert.Equal(t, &customerID, resp.CustomerID)
+		mockOrderRepo.AssertExpectations(t)
+		mockTx.AssertExpectations(t)
+	})
+
+	t.Run("Unknown customer", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockTx := new(MockTx)
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, forCustomer).Return(model.ErrCustomerNotFound)
+		mockTx.On("Rollback", ctx).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest())
+
+		assert.Equal(t, model.ErrCustomerNotFound, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "CreateOrderItems", mock.Anything, mock.Anything, mock.Anything)
+		mockTx.AssertExpectations(t)
+	})
+}
+
+func TestOrderService_CreateOrder_OnBehalfOfCustomer(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+		Caller:     "customer-42",
+		Delegation: &model.Delegation{Admin: "admin:support", Customer: "customer-42"},
+	}
+
+	testProducts := fixtures.Products(1)
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+	var audit *model.AuditEntry
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockOrderRepo.On("RecordAudit", ctx, mockTx, mock.AnythingOfType("*model.AuditEntry")).
+		Run(func(args mock.Arguments) { audit = args.Get(2).(*model.AuditEntry) }).
+		Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, audit)
+	assert.Equal(t, "admin:support", audit.Actor)
+	assert.Equal(t, model.AuditActionOrderCreate, audit.Action)
+	assert.Equal(t, "order", audit.EntityType)
+	assert.Equal(t, map[string]any{"orderId": resp.ID.String(), "onBehalfOf": "customer-42"}, audit.Details)
+
+	mockOrderRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_EnqueuesWebhooks(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	endpoints := []string{"https://fulfillment.example.com/hooks", "https://crm.example.com/hooks"}
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockWebhooks := new(MockWebhookRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger, WithWebhooks(mockWebhooks, endpoints))
+
+	var deliveries []model.WebhookDelivery
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockWebhooks.On("Enqueue", ctx, mockTx, mock.AnythingOfType("[]model.WebhookDelivery")).
+		Run(func(args mock.Arguments) { deliveries = args.Get(2).([]model.WebhookDelivery) }).
+		Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for i, d := range deliveries {
+		assert.Equal(t, endpoints[i], d.Endpoint)
+		assert.Equal(t, model.WebhookOrderCreated, d.EventType)
+		assert.Equal(t, resp.ID, d.OrderID)
+		assert.Equal(t, deliveries[0].EventID, d.EventID)
+
+		var event struct {
+			ID   uuid.UUID           `json:"id"`
+			Type string              `json:"type"`
+			Data model.OrderResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(d.Payload, &event))
+		assert.Equal(t, d.EventID, event.ID)
+		assert.Equal(t, model.WebhookOrderCreated, event.Type)
+		assert.Equal(t, resp.ID, event.Data.ID)
+		assert.Len(t, event.Data.Items, 1)
+	}
+
+	mockWebhooks.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_AppendsEvent(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockOutbox := new(MockEventOutboxRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger, WithEvents(mockOutbox))
+
+	var appended []model.OutboxEvent
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockOutbox.On("Append", ctx, mockTx, mock.AnythingOfType("[]model.OutboxEvent")).
+		Run(func(args mock.Arguments) { appended = args.Get(2).([]model.OutboxEvent) }).
+		Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.Len(t, appended, 1)
+	assert.Equal(t, events.TypeOrderCreated, appended[0].Type)
+	assert.Equal(t, events.AggregateOrder, appended[0].AggregateType)
+	assert.Equal(t, resp.ID, appended[0].AggregateID)
+
+	var envelope struct {
+		ID   uuid.UUID           `json:"id"`
+		Data model.OrderResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(appended[0].Payload, &envelope))
+	assert.Equal(t, appended[0].ID, envelope.ID)
+	assert.Equal(t, resp.ID, envelope.Data.ID)
+
+	mockOutbox.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_WebhookEnqueueFails(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockWebhooks := new(MockWebhookRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+		WithWebhooks(mockWebhooks, []string{"https://fulfillment.example.com/hooks"}))
+
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockWebhooks.On("Enqueue", ctx, mockTx, mock.AnythingOfType("[]model.WebhookDelivery")).Return(errors.New("database error"))
+	mockTx.On("Rollback", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	// The order is not created without its announcement
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.True(t, mockTx.rolledBack)
+	mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+}
+
+func TestOrderService_CreateOrder_Saga(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	newCoordinator := func(repo *MockSagaRepository, steps ...saga.Step) *saga.Coordinator {
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Saga"), time.Minute).Return(nil)
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*model.Saga")).Return(true, nil)
+		return saga.NewCoordinator(repo, steps, saga.Config{Timeout: time.Minute, MaxAttempts: 3, RetryDelay: time.Second, MaxRetryDelay: time.Minute}, logger)
+	}
+
+	t.Run("Saga completes with the order", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockSagas := new(MockSagaRepository)
+		mockTx := new(MockTx)
+		payment := &stubSagaStep{name: saga.StepPayment}
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithSagas(newCoordinator(mockSagas, payment)))
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockSagas.On("Complete", ctx, mockTx, mock.AnythingOfType("uuid.UUID")).Return(true, nil)
+		mockTx.On("Commit", ctx).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{resp.ID}, payment.executed)
+		assert.Empty(t, payment.compensated)
+		mockSagas.AssertExpectations(t)
+		mockTx.AssertExpectations(t)
+	})
+
+	t.Run("Rejected step fails the order", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockSagas := new(MockSagaRepository)
+		stock := &stubSagaStep{name: saga.StepStock}
+		payment := &stubSagaStep{name: saga.StepPayment, err: &saga.RejectedError{Step: saga.StepPayment, Reason: "card declined"}}
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithSagas(newCoordinator(mockSagas, stock, payment)))
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrPaymentDeclined, err)
+		assert.Nil(t, resp)
+		assert.Equal(t, stock.executed, stock.compensated)
+		assert.Empty(t, payment.compensated)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	})
+
+	t.Run("Unavailable step fails the order", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockSagas := new(MockSagaRepository)
+		stock := &stubSagaStep{name: saga.StepStock, err: errors.New("connection refused")}
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithSagas(newCoordinator(mockSagas, stock)))
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+		_, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrOrderStepFailed, err)
+		// The reservation may have been made before the connection failed
+		assert.Equal(t, stock.executed, stock.compensated)
+	})
+
+	t.Run("Saga is compensated when the order is not stored", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockSagas := new(MockSagaRepository)
+		mockTx := new(MockTx)
+		payment := &stubSagaStep{name: saga.StepPayment}
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithSagas(newCoordinator(mockSagas, payment)))
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(errors.New("database error"))
+		mockTx.On("Rollback", ctx).Return(nil)
+
+		_, err := service.CreateOrder(ctx, req)
+
+		require.Error(t, err)
+		require.Len(t, payment.executed, 1)
+		assert.Equal(t, payment.executed, payment.compensated)
+		mockSagas.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Timed out saga is left to the recovery loop", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockSagas := new(MockSagaRepository)
+		mockTx := new(MockTx)
+		payment := &stubSagaStep{name: saga.StepPayment}
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithSagas(newCoordinator(mockSagas, payment)))
+
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockSagas.On("Complete", ctx, mockTx, mock.AnythingOfType("uuid.UUID")).Return(false, nil)
+		mockTx.On("Rollback", ctx).Return(nil)
+
+		_, err := service.CreateOrder(ctx, req)
+
+		assert.ErrorIs(t, err, saga.ErrTimedOut)
+		assert.Empty(t, payment.compensated)
+		mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
 func TestOrderService_CreateOrder_InvalidCoupon(t *testing.T) {
 	logger := zerolog.Nop()
 	ctx := context.Background()
@@ -214,7 +824,7 @@ func TestOrderService_CreateOrder_InvalidCoupon(t *testing.T) {
 	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger)
 
 	// Set up expectations
-	mockValidator.On("Validate", ctx, couponCode).Return(model.ErrInvalidPromoCode)
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, model.ErrInvalidPromoCode)
 
 	// Execute
 	resp, err := service.CreateOrder(ctx, req)
@@ -225,7 +835,7 @@ func TestOrderService_CreateOrder_InvalidCoupon(t *testing.T) {
 	assert.Nil(t, resp)
 
 	mockValidator.AssertExpectations(t)
-	mockProductRepo.AssertNotCalled(t, "ValidateProductsExist")
+	mockProductRepo.AssertNotCalled(t, "GetByIDs")
 	mockOrderRepo.AssertNotCalled(t, "BeginTx")
 }
 
@@ -246,7 +856,7 @@ func TestOrderService_CreateOrder_ProductNotFound(t *testing.T) {
 	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger)
 
 	// Set up expectations
-	mockProductRepo.On("ValidateProductsExist", ctx, []string{"P999"}).Return(model.ErrProductNotFound)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P999"}).Return([]model.Product{}, nil)
 
 	// Execute
 	resp, err := service.CreateOrder(ctx, req)
@@ -347,7 +957,8 @@ func TestOrderService_CreateOrder_TransactionRollback(t *testing.T) {
 	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger)
 
 	// Set up expectations
-	mockProductRepo.On("ValidateProductsExist", ctx, []string{"P001"}).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return(fixtures.Products(1), nil)
 	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
 	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).
 		Return(errors.New("database error"))
@@ -365,7 +976,861 @@ func TestOrderService_CreateOrder_TransactionRollback(t *testing.T) {
 	mockTx.AssertExpectations(t)
 }
 
-func TestOrderService_GetByID(t *testing.T) {
+func TestOrderService_CreateOrder_RetriesAbortedTransaction(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+	deadlock := repository.Classify(&pgconn.PgError{Code: "40P01"})
+
+	tests := []struct {
+		name          string
+		failures      int
+		expectCommits int
+		expectErr     bool
+	}{
+		{name: "Succeeds on retry", failures: 2, expectCommits: 1},
+		{name: "Gives up after the last attempt", failures: maxOrderTxAttempts, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockTx := new(MockTx)
+
+			service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+			mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+				Return(fixtures.Products(1), nil)
+			mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+			mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).
+				Return(fmt.Errorf("failed to create order: %w", deadlock)).Times(tt.failures)
+			mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil).Maybe()
+			mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil).Maybe()
+			mockTx.On("Rollback", ctx).Return(nil).Times(tt.failures)
+			if tt.expectCommits > 0 {
+				mockTx.On("Commit", ctx).Return(nil).Times(tt.expectCommits)
+			}
+
+			resp, err := service.CreateOrder(ctx, req)
+
+			if tt.expectErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, repository.ErrRetryable)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, resp)
+			}
+			mockOrderRepo.AssertNumberOfCalls(t, "BeginTx", min(tt.failures+1, maxOrderTxAttempts))
+			mockTx.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_ProductDeletedConcurrently(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return(fixtures.Products(1), nil)
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).
+		Return(fmt.Errorf("failed to insert order item: %w", repository.Classify(&pgconn.PgError{Code: "23503"})))
+	mockTx.On("Rollback", ctx).Return(nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	assert.Equal(t, model.ErrProductNotFound, err)
+	assert.Nil(t, resp)
+	mockTx.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_ReservesCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "LIMITED123"
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+		Caller: "checkout-service",
+	}
+
+	testProducts := fixtures.Products(1)
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+	mockReservations := new(MockCouponReservationRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithCouponReservations(mockReservations))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, nil)
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	var reserved model.CouponRedemption
+	mockReservations.On("Reserve", ctx, mockTx, mock.AnythingOfType("model.CouponRedemption")).
+		Run(func(args mock.Arguments) { reserved = args.Get(2).(model.CouponRedemption) }).
+		Return(nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	// The redemption is recorded against the new order and its caller
+	assert.Equal(t, couponCode, reserved.Code)
+	assert.Equal(t, resp.ID, reserved.OrderID)
+	assert.Equal(t, "checkout-service", reserved.RedeemedBy)
+
+	mockReservations.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockTx.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_FlashSale(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 2},
+			{ProductID: "P002", Quantity: 1},
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		reserveError  error
+		itemsError    error
+		expectedErr   error
+		expectCreate  bool
+		expectCommit  bool
+		expectRelease bool
+	}{
+		{
+			name:         "Reserves quantities",
+			expectCreate: true,
+			expectCommit: true,
+		},
+		{
+			name:         "Sold out",
+			reserveError: model.ErrInsufficientStock,
+			expectedErr:  model.ErrInsufficientStock,
+		},
+		{
+			name:         "Counters unavailable",
+			reserveError: model.ErrOrderStepFailed,
+			expectedErr:  model.ErrOrderStepFailed,
+		},
+		{
+			name:          "Rolled back order releases its reservations",
+			itemsError:    errors.New("database error"),
+			expectCreate:  true,
+			expectRelease: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockFlashSales := new(MockFlashSaleRepository)
+			mockTx := new(MockTx)
+
+			service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+				WithFlashSales(mockFlashSales))
+
+			mockProductRepo.On("GetByIDs", ctx, []string{"P001", "P002", "P001"}).Return(fixtures.Products(2), nil)
+			mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+			// Only P001 is on a flash sale, so only its reservation is claimed
+			var claimed []model.FlashSaleReservation
+			if tt.reserveError == nil {
+				claimed = []model.FlashSaleReservation{{ProductID: "P001", Quantity: 3}}
+			}
+			var reserved []model.FlashSaleReservation
+			mockFlashSales.On("Reserve", ctx, mockTx, mock.AnythingOfType("[]model.FlashSaleReservation")).
+				Run(func(args mock.Arguments) { reserved = args.Get(2).([]model.FlashSaleReservation) }).
+				Return(claimed, tt.reserveError)
+			if tt.expectCreate {
+				mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+				mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(tt.itemsError)
+			}
+			if tt.expectCommit {
+				mockTx.On("Commit", ctx).Return(nil)
+			} else {
+				mockTx.On("Rollback", ctx).Return(nil)
+			}
+			if tt.expectRelease {
+				mockFlashSales.On("Release", mock.Anything, mock.AnythingOfType("[]model.FlashSaleReservation")).Return(nil)
+			}
+
+			resp, err := service.CreateOrder(ctx, req)
+
+			// Items of the same product are reserved together
+			require.Len(t, reserved, 2)
+			assert.Equal(t, "P001", reserved[0].ProductID)
+			assert.Equal(t, 3, reserved[0].Quantity)
+			assert.Equal(t, "P002", reserved[1].ProductID)
+			assert.Equal(t, 1, reserved[1].Quantity)
+
+			if tt.expectCommit {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				assert.Equal(t, resp.ID, reserved[0].OrderID)
+			} else {
+				assert.Nil(t, resp)
+				assert.True(t, mockTx.rolledBack)
+				if tt.expectedErr != nil {
+					assert.Equal(t, tt.expectedErr, err)
+				} else {
+					assert.Error(t, err)
+				}
+			}
+			if tt.expectRelease {
+				mockFlashSales.AssertCalled(t, "Release", mock.Anything, claimed)
+			} else {
+				mockFlashSales.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+			}
+			mockFlashSales.AssertExpectations(t)
+			mockOrderRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_NormalizesCouponCode(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "limited123"
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	testProducts := fixtures.Products(1)
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := &MockCouponValidator{caseInsensitive: true}
+	mockReservations := new(MockCouponReservationRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithCouponReservations(mockReservations))
+
+	mockValidator.On("ValidateAndResolve", ctx, "LIMITED123").Return(nil, nil)
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	var reserved model.CouponRedemption
+	mockReservations.On("Reserve", ctx, mockTx, mock.AnythingOfType("model.CouponRedemption")).
+		Run(func(args mock.Arguments) { reserved = args.Get(2).(model.CouponRedemption) }).
+		Return(nil)
+	var created *model.Order
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).
+		Run(func(args mock.Arguments) { created = args.Get(2).(*model.Order) }).
+		Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+
+	_, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+
+	// The code is redeemed and stored in its canonical form
+	assert.Equal(t, "LIMITED123", reserved.Code)
+	require.NotNil(t, created.CouponCode)
+	assert.Equal(t, "LIMITED123", *created.CouponCode)
+
+	mockValidator.AssertExpectations(t)
+	mockReservations.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_WithPricing(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 3},
+			{ProductID: "P002", Quantity: 1},
+		},
+	}
+
+	testProducts := []model.Product{
+		fixtures.NewProduct(1).Price(0.10).CreatedAt(time.Now()).Build(),
+		fixtures.NewProduct(2).Price(5.25).Category("Cat1").CreatedAt(time.Now()).Build(),
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(testProducts, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.Pricing)
+	assert.Equal(t, "AUD", resp.Pricing.Currency)
+	assert.Equal(t, model.NewMoney(5.55), resp.Pricing.Subtotal)
+	assert.Equal(t, model.NewMoney(5.55), resp.Pricing.Total)
+	assert.Len(t, resp.Pricing.Lines, 2)
+	require.NotNil(t, resp.Total)
+	assert.Equal(t, model.NewMoney(5.55), *resp.Total)
+}
+
+func TestOrderService_CreateOrder_SalePrice(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	req := &model.OrderRequest{
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 2},
+			{ProductID: "P002", Quantity: 1},
+		},
+	}
+
+	ended := time.Now().Add(-time.Hour)
+	testProducts := []model.Product{
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", CreatedAt: time.Now(),
+			Sale: &model.ProductSale{Price: model.NewMoney(7.50)}},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(5.25), Category: "Cat1", CreatedAt: time.Now(),
+			Sale: &model.ProductSale{Price: model.NewMoney(4.00), EndsAt: &ended}},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	var items []model.OrderItem
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).
+		Run(func(args mock.Arguments) { items = args.Get(2).([]model.OrderItem) }).
+		Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001", "P002"}).Return(testProducts, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.Pricing)
+	assert.Equal(t, model.NewMoney(20.25), resp.Pricing.Subtotal)
+	assert.True(t, resp.Pricing.Lines[0].OnSale)
+	assert.Equal(t, model.NewMoney(7.50), resp.Pricing.Lines[0].UnitPrice)
+	assert.False(t, resp.Pricing.Lines[1].OnSale)
+
+	// Items keep the price charged, so the order renders the same after the sale
+	require.Len(t, items, 2)
+	assert.Equal(t, &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(7.50), OnSale: true}, items[0].Product)
+	assert.Equal(t, &model.ProductSnapshot{Name: "Product 2", Category: "Cat1", Price: model.NewMoney(5.25)}, items[1].Product)
+	assert.Equal(t, model.NewMoney(7.50), resp.Products[0].EffectivePrice)
+	assert.True(t, resp.Products[0].OnSale)
+}
+
+func TestOrderService_CreateOrder_WithCouponDiscount(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "QUARTER25"
+	percentOff := 25.0
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 2},
+		},
+	}
+
+	testProducts := []model.Product{
+		fixtures.NewProduct(1).Price(10.50).CreatedAt(time.Now()).Build(),
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).
+		Return(&model.CouponDiscount{Code: couponCode, PercentOff: &percentOff}, nil)
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.MatchedBy(func(o *model.Order) bool {
+		return o.Subtotal != nil && *o.Subtotal == model.NewMoney(21.00) &&
+			o.Discount != nil && *o.Discount == model.NewMoney(5.25) &&
+			o.Total != nil && *o.Total == model.NewMoney(15.75)
+	})).Return(nil)
+	mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+	mockTx.On("Commit", ctx).Return(nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.Subtotal)
+	require.NotNil(t, resp.Discount)
+	require.NotNil(t, resp.Total)
+	assert.Equal(t, model.NewMoney(21.00), *resp.Subtotal)
+	assert.Equal(t, model.NewMoney(5.25), *resp.Discount)
+	assert.Equal(t, model.NewMoney(15.75), *resp.Total)
+	assert.Equal(t, model.NewMoney(5.25), resp.Pricing.Discount)
+	mockOrderRepo.AssertExpectations(t)
+	mockValidator.AssertExpectations(t)
+}
+
+func TestOrderService_CreateOrder_CouponNotApplicable(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "WAFFLE20"
+	percentOff := 20.0
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).
+		Return(&model.CouponDiscount{Code: couponCode, PercentOff: &percentOff, Categories: []string{"Waffle"}}, nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return([]model.Product{{ID: "P001", Name: "Product 1", Price: model.NewMoney(1), Category: "Drinks"}}, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	assert.Equal(t, model.ErrCouponNotApplicable, err)
+	assert.Nil(t, resp)
+	mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestOrderService_CreateOrder_CouponMinSubtotal(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "TENOFF50"
+	amountOff := model.NewMoney(10)
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).
+		Return(&model.CouponDiscount{Code: couponCode, AmountOff: &amountOff, MinSubtotal: model.NewMoney(50)}, nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return([]model.Product{fixtures.NewProduct(1).Price(24.99).Build()}, nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	assert.Equal(t, model.ErrCouponMinSubtotal, err)
+	assert.Nil(t, resp)
+	mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestOrderService_CreateOrder_RestrictedCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "WELCOME15"
+	percentOff := 15.0
+	customerID := uuid.New()
+
+	tests := []struct {
+		name        string
+		discount    *model.CouponDiscount
+		customerID  *uuid.UUID
+		eligibility error
+	}{
+		{
+			name:        "First order only",
+			discount:    &model.CouponDiscount{Code: couponCode, PercentOff: &percentOff, FirstOrderOnly: true},
+			customerID:  &customerID,
+			eligibility: model.ErrCouponFirstOrderOnly,
+		},
+		{
+			name:        "Other customer's code",
+			discount:    &model.CouponDiscount{Code: couponCode, PercentOff: &percentOff, CustomerIDs: []uuid.UUID{uuid.New()}},
+			customerID:  &customerID,
+			eligibility: model.ErrCouponNotForCustomer,
+		},
+		{
+			name:        "Segment code without a customer",
+			discount:    &model.CouponDiscount{Code: couponCode, PercentOff: &percentOff, Segments: []string{"vip"}},
+			eligibility: model.ErrCouponSegment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &model.OrderRequest{
+				CouponCode: &couponCode,
+				CustomerID: tt.customerID,
+				Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+			}
+
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockValidator := new(MockCouponValidator)
+
+			service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+				WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+			mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(tt.discount, nil)
+			mockValidator.On("CheckEligibility", ctx, tt.discount, tt.customerID).Return(tt.eligibility)
+
+			resp, err := service.CreateOrder(ctx, req)
+
+			assert.Equal(t, tt.eligibility, err)
+			assert.Nil(t, resp)
+			mockValidator.AssertExpectations(t)
+			mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_DiscountLookupFails(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "QUARTER25"
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, errors.New("connection reset"))
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+}
+
+func TestOrderService_CreateOrder_CouponFailOpen(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "QUARTER25"
+	percentOff := 25.0
+	testProducts := []model.Product{
+		fixtures.NewProduct(1).Price(10.50).CreatedAt(time.Now()).Build(),
+	}
+
+	t.Run("Accepts and flags codes that could not be checked", func(t *testing.T) {
+		for _, validationErr := range []error{model.ErrCouponValidationTimeout, model.ErrCouponsLoading} {
+			req := &model.OrderRequest{
+				CouponCode: &couponCode,
+				Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+			}
+
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockValidator := new(MockCouponValidator)
+			mockTx := new(MockTx)
+
+			service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+				WithPricing(pricing.NewEngine(pricing.Config{Currency: "AUD"})),
+				WithCouponFailOpen(true))
+
+			warning := validationErr.(*model.DomainError).Code
+			mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, validationErr)
+			mockValidator.On("Resolve", ctx, couponCode).
+				Return(&model.CouponDiscount{Code: couponCode, PercentOff: &percentOff}, nil)
+			mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+			mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.MatchedBy(func(o *model.Order) bool {
+				return o.CouponWarning != nil && *o.CouponWarning == warning &&
+					o.Discount != nil && *o.Discount == model.NewMoney(5.25)
+			})).Return(nil)
+			mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+			mockTx.On("Commit", ctx).Return(nil)
+			mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(testProducts, nil)
+
+			resp, err := service.CreateOrder(ctx, req)
+
+			require.NoError(t, err)
+			require.NotNil(t, resp.CouponWarning)
+			assert.Equal(t, warning, *resp.CouponWarning)
+			require.NotNil(t, resp.Discount)
+			assert.Equal(t, model.NewMoney(5.25), *resp.Discount)
+			mockOrderRepo.AssertExpectations(t)
+			mockValidator.AssertExpectations(t)
+		}
+	})
+
+	t.Run("Still rejects invalid codes", func(t *testing.T) {
+		req := &model.OrderRequest{
+			CouponCode: &couponCode,
+			Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+		}
+
+		mockOrderRepo := new(MockOrderRepository)
+		mockValidator := new(MockCouponValidator)
+
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), mockValidator, logger,
+			WithCouponFailOpen(true))
+
+		mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, model.ErrInvalidPromoCode)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrInvalidPromoCode, err)
+		assert.Nil(t, resp)
+		mockValidator.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	})
+
+	t.Run("Expired codes are rejected", func(t *testing.T) {
+		req := &model.OrderRequest{
+			CouponCode: &couponCode,
+			Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+		}
+
+		mockOrderRepo := new(MockOrderRepository)
+		mockValidator := new(MockCouponValidator)
+
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), mockValidator, logger,
+			WithCouponFailOpen(true))
+
+		mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, model.ErrCouponValidationTimeout)
+		mockValidator.On("Resolve", ctx, couponCode).Return(nil, model.ErrCouponExpired)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrCouponExpired, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	})
+
+	t.Run("Fails closed by default", func(t *testing.T) {
+		req := &model.OrderRequest{
+			CouponCode: &couponCode,
+			Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+		}
+
+		mockValidator := new(MockCouponValidator)
+		service := NewOrderService(new(MockOrderRepository), new(MockProductRepository), mockValidator, logger)
+
+		mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, model.ErrCouponValidationTimeout)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrCouponValidationTimeout, err)
+		assert.Nil(t, resp)
+		mockValidator.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderService_CreateOrder_CouponLimitReached(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	couponCode := "LIMITED123"
+	req := &model.OrderRequest{
+		CouponCode: &couponCode,
+		Items: []model.OrderItemRequest{
+			{ProductID: "P001", Quantity: 1},
+		},
+	}
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+	mockReservations := new(MockCouponReservationRepository)
+	mockTx := new(MockTx)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+		WithCouponReservations(mockReservations))
+
+	mockValidator.On("ValidateAndResolve", ctx, couponCode).Return(nil, nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return(fixtures.Products(1), nil)
+	mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+	mockReservations.On("Reserve", ctx, mockTx, mock.AnythingOfType("model.CouponRedemption")).Return(model.ErrCouponRedemptionLimit)
+	mockTx.On("Rollback", ctx).Return(nil)
+
+	resp, err := service.CreateOrder(ctx, req)
+
+	require.Error(t, err)
+	assert.Equal(t, model.ErrCouponRedemptionLimit, err)
+	assert.Nil(t, resp)
+
+	// Rolling back releases the reservation; no order rows are written
+	mockTx.AssertExpectations(t)
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder")
+}
+
+func TestOrderService_CreateOrder_Idempotency(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	const key = "checkout-7f3a"
+	newRequest := func(quantity int) *model.OrderRequest {
+		return &model.OrderRequest{
+			IdempotencyKey: key,
+			Caller:         "api-key",
+			Items:          []model.OrderItemRequest{{ProductID: "P001", Quantity: quantity}},
+		}
+	}
+	requestHash := hashOrderRequest(newRequest(1))
+
+	existingID := uuid.New()
+	existingOrder := &model.Order{ID: existingID, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	existingItems := []model.OrderItem{{ID: uuid.New(), OrderID: existingID, ProductID: "P001", Quantity: 1,
+		Product: &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(10.00)}}}
+	products := fixtures.Products(1)
+
+	t.Run("First request records the key", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockIdempotency := new(MockIdempotencyRepository)
+		mockTx := new(MockTx)
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithIdempotency(mockIdempotency))
+
+		mockIdempotency.On("Get", ctx, "api-key", key).Return(nil, nil)
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(products, nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockIdempotency.On("Create", ctx, mockTx, mock.MatchedBy(func(k *model.IdempotencyKey) bool {
+			return k.Key == key && k.Caller == "api-key" && k.RequestHash == requestHash
+		})).Return(nil)
+		mockTx.On("Commit", ctx).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest(1))
+
+		require.NoError(t, err)
+		assert.False(t, resp.Replayed)
+		mockIdempotency.AssertExpectations(t)
+	})
+
+	t.Run("Retry returns the original order", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockIdempotency := new(MockIdempotencyRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger,
+			WithIdempotency(mockIdempotency))
+
+		mockIdempotency.On("Get", ctx, "api-key", key).
+			Return(&model.IdempotencyKey{Key: key, RequestHash: requestHash, OrderID: existingID}, nil)
+		mockOrderRepo.On("GetByID", ctx, existingID).Return(existingOrder, existingItems, nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest(1))
+
+		require.NoError(t, err)
+		assert.Equal(t, existingID, resp.ID)
+		assert.True(t, resp.Replayed)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	})
+
+	t.Run("Key reused for a different request", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockIdempotency := new(MockIdempotencyRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger,
+			WithIdempotency(mockIdempotency))
+
+		mockIdempotency.On("Get", ctx, "api-key", key).
+			Return(&model.IdempotencyKey{Key: key, RequestHash: requestHash, OrderID: existingID}, nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest(2))
+
+		assert.Equal(t, model.ErrIdempotencyConflict, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	})
+
+	t.Run("Concurrent retry loses the race", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockIdempotency := new(MockIdempotencyRepository)
+		mockTx := new(MockTx)
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger,
+			WithIdempotency(mockIdempotency))
+
+		mockIdempotency.On("Get", ctx, "api-key", key).Return(nil, nil).Once()
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(products, nil)
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.AnythingOfType("*model.Order")).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockIdempotency.On("Create", ctx, mockTx, mock.AnythingOfType("*model.IdempotencyKey")).
+			Return(model.ErrIdempotencyConflict)
+		mockIdempotency.On("Get", ctx, "api-key", key).
+			Return(&model.IdempotencyKey{Key: key, RequestHash: requestHash, OrderID: existingID}, nil).Once()
+		mockOrderRepo.On("GetByID", ctx, existingID).Return(existingOrder, existingItems, nil)
+		mockTx.On("Rollback", ctx).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, newRequest(1))
+
+		require.NoError(t, err)
+		assert.Equal(t, existingID, resp.ID)
+		assert.True(t, resp.Replayed)
+		assert.True(t, mockTx.rolledBack)
+		mockTx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+func TestOrderService_GetByID(t *testing.T) {
 	logger := zerolog.Nop()
 	ctx := context.Background()
 
@@ -377,14 +1842,17 @@ func TestOrderService_GetByID(t *testing.T) {
 		UpdatedAt:  time.Now(),
 	}
 
+	// Products are rendered from the snapshots captured at order time
 	items := []model.OrderItem{
-		{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2},
-		{ID: uuid.New(), OrderID: orderID, ProductID: "P002", Quantity: 1},
+		{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2,
+			Product: &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(10.00)}},
+		{ID: uuid.New(), OrderID: orderID, ProductID: "P002", Quantity: 1,
+			Product: &model.ProductSnapshot{Name: "Product 2", Category: "Cat2", Price: model.NewMoney(20.00)}},
 	}
 
 	products := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-		{ID: "P002", Name: "Product 2", Price: 20.00, Category: "Cat2", CreatedAt: time.Now()},
+		{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1", EffectivePrice: model.NewMoney(10.00)},
+		{ID: "P002", Name: "Product 2", Price: model.NewMoney(20.00), Category: "Cat2", EffectivePrice: model.NewMoney(20.00)},
 	}
 
 	tests := []struct {
@@ -437,11 +1905,6 @@ func TestOrderService_GetByID(t *testing.T) {
 
 			mockOrderRepo.On("GetByID", ctx, tt.orderID).Return(tt.mockOrder, tt.mockItems, tt.mockError)
 
-			if tt.mockOrder != nil && !tt.expectError {
-				productIDs := []string{"P001", "P002"}
-				mockProductRepo.On("GetByIDs", ctx, productIDs).Return(tt.mockProducts, nil)
-			}
-
 			resp, err := service.GetByID(ctx, tt.orderID)
 
 			if tt.expectError {
@@ -460,7 +1923,594 @@ func TestOrderService_GetByID(t *testing.T) {
 			}
 
 			mockOrderRepo.AssertExpectations(t)
-			mockProductRepo.AssertExpectations(t)
+			mockProductRepo.AssertNotCalled(t, "GetByIDs")
+		})
+	}
+}
+
+func TestOrderService_GetByRef(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	order, items := fixtures.NewOrder().Item("P001", 1).Build()
+
+	t.Run("Found", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+		mockOrderRepo.On("GetIDByRef", ctx, "LEGACY-1001").Return(&order.ID, nil)
+		mockOrderRepo.On("GetByID", ctx, order.ID).Return(order, items, nil)
+
+		resp, err := service.GetByRef(ctx, "LEGACY-1001")
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, order.ID, resp.ID)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown reference", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+		mockOrderRepo.On("GetIDByRef", ctx, "LEGACY-404").Return(nil, nil)
+
+		resp, err := service.GetByRef(ctx, "LEGACY-404")
+
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "GetByID")
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+		mockOrderRepo.On("GetIDByRef", ctx, "LEGACY-1001").Return(nil, errors.New("database error"))
+
+		resp, err := service.GetByRef(ctx, "LEGACY-1001")
+
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestOrderService_CreateOrder_SourceValidation(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	allowed := []string{"web", "mobile", "pos", "marketplace:*"}
+
+	tests := []struct {
+		name      string
+		source    string
+		expectErr bool
+	}{
+		{name: "Exact match", source: "web", expectErr: false},
+		{name: "Wildcard sub-channel", source: "marketplace:amazon", expectErr: false},
+		{name: "Wildcard requires sub-channel", source: "marketplace:", expectErr: true},
+		{name: "Unknown channel", source: "fax", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			req := &model.OrderRequest{
+				Source: &source,
+				Items: []model.OrderItemRequest{
+					{ProductID: "P001", Quantity: 1},
+				},
+			}
+
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockValidator := new(MockCouponValidator)
+			mockTx := new(MockTx)
+
+			service := NewOrderService(mockOrderRepo, mockProductRepo, mockValidator, logger,
+				WithAllowedSources(allowed))
+
+			if !tt.expectErr {
+				mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+				mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.MatchedBy(func(o *model.Order) bool {
+					return o.Source != nil && *o.Source == tt.source
+				})).Return(nil)
+				mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+				mockTx.On("Commit", ctx).Return(nil)
+				mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+					Return(fixtures.Products(1), nil)
+			}
+
+			resp, err := service.CreateOrder(ctx, req)
+
+			if tt.expectErr {
+				assert.Equal(t, model.ErrInvalidOrderSource, err)
+				assert.Nil(t, resp)
+				mockOrderRepo.AssertNotCalled(t, "BeginTx")
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp.Source)
+				assert.Equal(t, tt.source, *resp.Source)
+				mockOrderRepo.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_ShippingAddress(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	t.Run("Normalized and stored", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockTx := new(MockTx)
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+
+		req := fixtures.NewOrder().Item("P001", 1).Request()
+		req.ShippingAddress = &model.Address{Name: " Ada Lovelace ", Line1: "1 Main Street", City: "Sydney", PostalCode: "2000", Country: "au"}
+		expected := &model.Address{Name: "Ada Lovelace", Line1: "1 Main Street", City: "Sydney", PostalCode: "2000", Country: "AU"}
+
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.MatchedBy(func(o *model.Order) bool {
+			return assert.ObjectsAreEqual(expected, o.ShippingAddress)
+		})).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockTx.On("Commit", ctx).Return(nil)
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).Return(fixtures.Products(1), nil)
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, resp.ShippingAddress)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Incomplete address is rejected", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+		req := fixtures.NewOrder().Item("P001", 1).Request()
+		req.ShippingAddress = &model.Address{Country: "AU"}
+
+		resp, err := service.CreateOrder(ctx, req)
+
+		assert.Equal(t, model.ErrInvalidShippingAddress, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx")
+	})
+}
+
+func TestOrderService_CreateOrder_UnknownFields(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	newRequest := func() *model.OrderRequest {
+		return &model.OrderRequest{
+			Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+			Metadata: model.Metadata{"giftWrap": json.RawMessage(`true`)},
+		}
+	}
+
+	t.Run("Preserved by default", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockTx := new(MockTx)
+
+		mockOrderRepo.On("BeginTx", ctx).Return(mockTx, nil)
+		mockOrderRepo.On("CreateOrder", ctx, mockTx, mock.MatchedBy(func(o *model.Order) bool {
+			return string(o.Metadata["giftWrap"]) == "true"
+		})).Return(nil)
+		mockOrderRepo.On("CreateOrderItems", ctx, mockTx, mock.AnythingOfType("[]model.OrderItem")).Return(nil)
+		mockTx.On("Commit", ctx).Return(nil)
+		mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+			Return(fixtures.Products(1), nil)
+
+		service := NewOrderService(mockOrderRepo, mockProductRepo, new(MockCouponValidator), logger)
+		resp, err := service.CreateOrder(ctx, newRequest())
+
+		require.NoError(t, err)
+		assert.Equal(t, json.RawMessage(`true`), resp.Metadata["giftWrap"])
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejected in strict mode", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger,
+			WithStrictOrderFields(true))
+		resp, err := service.CreateOrder(ctx, newRequest())
+
+		assert.Equal(t, model.ErrUnknownOrderFields, err)
+		assert.Nil(t, resp)
+		mockOrderRepo.AssertNotCalled(t, "BeginTx")
+	})
+}
+
+func TestOrderService_List(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	web := "web"
+	orders := []model.Order{
+		{ID: uuid.New(), Source: &web, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	customerID := uuid.New()
+
+	tests := []struct {
+		name           string
+		filter         model.OrderFilter
+		expectedFilter model.OrderFilter
+		mockError      error
+		expectError    bool
+	}{
+		{
+			name:           "Defaults applied",
+			filter:         model.OrderFilter{},
+			expectedFilter: model.OrderFilter{Limit: 10},
+		},
+		{
+			name:           "Limit capped and offset clamped",
+			filter:         model.OrderFilter{Source: "web", Limit: 500, Offset: -3},
+			expectedFilter: model.OrderFilter{Source: "web", Limit: 100},
+		},
+		{
+			name:           "Product filter passed through",
+			filter:         model.OrderFilter{ProductID: "P001"},
+			expectedFilter: model.OrderFilter{ProductID: "P001", Limit: 10},
+		},
+		{
+			name:           "Customer filter passed through",
+			filter:         model.OrderFilter{CustomerID: &customerID},
+			expectedFilter: model.OrderFilter{CustomerID: &customerID, Limit: 10},
+		},
+		{
+			name:           "Repository error",
+			filter:         model.OrderFilter{Limit: 5},
+			expectedFilter: model.OrderFilter{Limit: 5},
+			mockError:      errors.New("database error"),
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+			if tt.mockError != nil {
+				mockOrderRepo.On("List", ctx, tt.expectedFilter).Return(nil, tt.mockError)
+			} else {
+				mockOrderRepo.On("List", ctx, tt.expectedFilter).Return(orders, nil)
+				mockOrderRepo.On("Count", ctx, tt.expectedFilter).Return(25, nil)
+			}
+
+			result, page, err := service.List(ctx, tt.filter)
+
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, orders, result)
+				assert.Equal(t, model.Page{Limit: tt.expectedFilter.Limit, Offset: tt.expectedFilter.Offset, Total: 25}, page)
+			}
+			mockOrderRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name         string
+		current      *model.Order
+		status       model.OrderStatus
+		updateError  error
+		expectUpdate bool
+		expectedErr  error
+	}{
+		{
+			name:         "Pending to confirmed",
+			current:      &model.Order{ID: orderID, Status: model.OrderStatusPending},
+			status:       model.OrderStatusConfirmed,
+			expectUpdate: true,
+		},
+		{
+			name:         "Confirmed to cancelled",
+			current:      &model.Order{ID: orderID, Status: model.OrderStatusConfirmed},
+			status:       model.OrderStatusCancelled,
+			expectUpdate: true,
+		},
+		{
+			name:    "Same status is a no-op",
+			current: &model.Order{ID: orderID, Status: model.OrderStatusConfirmed},
+			status:  model.OrderStatusConfirmed,
+		},
+		{
+			name:        "Unknown status",
+			status:      "shipped",
+			expectedErr: model.ErrInvalidOrderStatus,
+		},
+		{
+			name:        "Order not found",
+			status:      model.OrderStatusCancelled,
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name:        "Cancelled orders are final",
+			current:     &model.Order{ID: orderID, Status: model.OrderStatusCancelled},
+			status:      model.OrderStatusConfirmed,
+			expectedErr: model.ErrStatusTransition,
+		},
+		{
+			name:        "Pending cannot skip to fulfilled",
+			current:     &model.Order{ID: orderID, Status: model.OrderStatusPending},
+			status:      model.OrderStatusFulfilled,
+			expectedErr: model.ErrStatusTransition,
+		},
+		{
+			name:         "Concurrent status change",
+			current:      &model.Order{ID: orderID, Status: model.OrderStatusPending},
+			status:       model.OrderStatusCancelled,
+			updateError:  model.ErrStatusTransition,
+			expectUpdate: true,
+			expectedErr:  model.ErrStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+
+			if tt.current != nil {
+				mockOrderRepo.On("GetByID", ctx, orderID).Return(tt.current, []model.OrderItem{}, nil).Maybe()
+			} else {
+				mockOrderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil).Maybe()
+			}
+			if tt.expectUpdate {
+				if tt.updateError != nil {
+					mockOrderRepo.On("UpdateStatus", ctx, orderID, tt.current.Status, tt.status, (*model.AuditEntry)(nil), []model.WebhookDelivery(nil), []model.OutboxEvent(nil)).Return(nil, tt.updateError)
+				} else {
+					mockOrderRepo.On("UpdateStatus", ctx, orderID, tt.current.Status, tt.status, (*model.AuditEntry)(nil), []model.WebhookDelivery(nil), []model.OutboxEvent(nil)).
+						Return(&model.Order{ID: orderID, Status: tt.status}, nil)
+				}
+			}
+
+			order, err := service.UpdateStatus(ctx, orderID, tt.status, nil)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, order)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.status, order.Status)
+			}
+
+			if !tt.expectUpdate {
+				mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockOrderRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestOrderService_UpdateStatus_OnBehalfOfCustomer(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	mockOrderRepo := new(MockOrderRepository)
+	service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+
+	expectedAudit := &model.AuditEntry{
+		Actor:      "admin:support",
+		Action:     model.AuditActionOrderStatus,
+		EntityType: "order",
+		Details: map[string]any{
+			"orderId":    orderID.String(),
+			"from":       "pending",
+			"to":         "cancelled",
+			"onBehalfOf": "customer-42",
+		},
+	}
+	mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusPending}, []model.OrderItem{}, nil)
+	mockOrderRepo.On("UpdateStatus", ctx, orderID, model.OrderStatusPending, model.OrderStatusCancelled, expectedAudit, []model.WebhookDelivery(nil), []model.OutboxEvent(nil)).
+		Return(&model.Order{ID: orderID, Status: model.OrderStatusCancelled}, nil)
+
+	delegation := &model.Delegation{Admin: "admin:support", Customer: "customer-42"}
+	order, err := service.UpdateStatus(ctx, orderID, model.OrderStatusCancelled, delegation)
+
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCancelled, order.Status)
+	mockOrderRepo.AssertExpectations(t)
+}
+
+func TestOrderService_OverrideStatus(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	reopen := &model.StatusOverride{Status: model.OrderStatusPending, Reason: "cancelled by mistake"}
+
+	t.Run("Final status is overridden and audited", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+
+		expectedAudit := &model.AuditEntry{
+			Actor:      "admin:ops",
+			Action:     model.AuditActionOrderStatusOverride,
+			EntityType: "order",
+			Details: map[string]any{
+				"orderId": orderID.String(),
+				"from":    "cancelled",
+				"to":      "pending",
+				"reason":  "cancelled by mistake",
+			},
+		}
+		mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusCancelled}, []model.OrderItem{}, nil)
+		mockOrderRepo.On("UpdateStatus", ctx, orderID, model.OrderStatusCancelled, model.OrderStatusPending, expectedAudit, []model.WebhookDelivery(nil), []model.OutboxEvent(nil)).
+			Return(&model.Order{ID: orderID, Status: model.OrderStatusPending}, nil)
+
+		order, err := service.OverrideStatus(ctx, orderID, reopen, "admin:ops")
+
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusPending, order.Status)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Current status is a no-op", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+
+		mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusPending}, []model.OrderItem{}, nil)
+
+		order, err := service.OverrideStatus(ctx, orderID, reopen, "admin:ops")
+
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusPending, order.Status)
+		mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+
+		mockOrderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil)
+
+		_, err := service.OverrideStatus(ctx, orderID, reopen, "admin:ops")
+
+		assert.Equal(t, model.ErrOrderNotFound, err)
+	})
+
+	t.Run("Invalid override", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop())
+
+		for _, override := range []*model.StatusOverride{
+			{Status: model.OrderStatusPending},
+			{Status: model.OrderStatusPending, Reason: "  "},
+			{Status: "reopened", Reason: "cancelled by mistake"},
+			{Status: model.OrderStatusPending, Reason: strings.Repeat("x", model.MaxOverrideReasonLength+1)},
+		} {
+			_, err := service.OverrideStatus(ctx, orderID, override, "admin:ops")
+			assert.Equal(t, model.ErrInvalidStatusOverride, err)
+		}
+		mockOrderRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderService_UpdateStatus_Events(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	mockOrderRepo := new(MockOrderRepository)
+	service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop(),
+		WithEvents(new(MockEventOutboxRepository)))
+
+	mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusPending}, []model.OrderItem{}, nil)
+	mockOrderRepo.On("UpdateStatus", ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, (*model.AuditEntry)(nil), []model.WebhookDelivery(nil),
+		mock.MatchedBy(func(outboxEvents []model.OutboxEvent) bool {
+			if len(outboxEvents) != 1 || outboxEvents[0].Type != events.TypeOrderStatusChanged || outboxEvents[0].AggregateID != orderID {
+				return false
+			}
+			var envelope struct {
+				Data events.OrderStatusChanged `json:"data"`
+			}
+			return json.Unmarshal(outboxEvents[0].Payload, &envelope) == nil &&
+				envelope.Data == events.OrderStatusChanged{OrderID: orderID, Status: model.OrderStatusConfirmed, PreviousStatus: model.OrderStatusPending}
+		})).
+		Return(&model.Order{ID: orderID, Status: model.OrderStatusConfirmed}, nil)
+
+	_, err := service.UpdateStatus(ctx, orderID, model.OrderStatusConfirmed, nil)
+
+	require.NoError(t, err)
+	mockOrderRepo.AssertExpectations(t)
+}
+
+func TestOrderService_UpdateStatus_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	endpoints := []string{"https://fulfillment.example.com/hooks"}
+
+	t.Run("Cancellation is announced", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop(),
+			WithWebhooks(new(MockWebhookRepository), endpoints))
+
+		mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusConfirmed}, []model.OrderItem{}, nil)
+		mockOrderRepo.On("UpdateStatus", ctx, orderID, model.OrderStatusConfirmed, model.OrderStatusCancelled, (*model.AuditEntry)(nil),
+			mock.MatchedBy(func(deliveries []model.WebhookDelivery) bool {
+				if len(deliveries) != 1 || deliveries[0].EventType != model.WebhookOrderCancelled || deliveries[0].Endpoint != endpoints[0] ||
+					deliveries[0].OrderID != orderID {
+					return false
+				}
+				var event struct {
+					Data model.WebhookOrderStatus `json:"data"`
+				}
+				return json.Unmarshal(deliveries[0].Payload, &event) == nil &&
+					event.Data == model.WebhookOrderStatus{ID: orderID, Status: model.OrderStatusCancelled, PreviousStatus: model.OrderStatusConfirmed}
+			}), []model.OutboxEvent(nil)).
+			Return(&model.Order{ID: orderID, Status: model.OrderStatusCancelled}, nil)
+
+		_, err := service.UpdateStatus(ctx, orderID, model.OrderStatusCancelled, nil)
+
+		require.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other status changes are not announced", func(t *testing.T) {
+		mockOrderRepo := new(MockOrderRepository)
+		service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), zerolog.Nop(),
+			WithWebhooks(new(MockWebhookRepository), endpoints))
+
+		mockOrderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusPending}, []model.OrderItem{}, nil)
+		mockOrderRepo.On("UpdateStatus", ctx, orderID, model.OrderStatusPending, model.OrderStatusConfirmed, (*model.AuditEntry)(nil), []model.WebhookDelivery(nil), []model.OutboxEvent(nil)).
+			Return(&model.Order{ID: orderID, Status: model.OrderStatusConfirmed}, nil)
+
+		_, err := service.UpdateStatus(ctx, orderID, model.OrderStatusConfirmed, nil)
+
+		require.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+}
+
+func TestOrderService_CountBySource(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	counts := []model.SourceCount{{Source: "web", Orders: 3}, {Source: "unattributed", Orders: 1}}
+
+	mockOrderRepo := new(MockOrderRepository)
+	service := NewOrderService(mockOrderRepo, new(MockProductRepository), new(MockCouponValidator), logger)
+	mockOrderRepo.On("CountBySource", ctx).Return(counts, nil)
+
+	result, err := service.CountBySource(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, counts, result)
+}
+
+func TestRunIdempotencyKeySweeper(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockIdempotency := new(MockIdempotencyRepository)
+
+	// Full batches are followed by another until one comes back short
+	mockIdempotency.On("DeleteExpired", mock.Anything, idempotencySweepBatchSize).Return(idempotencySweepBatchSize, nil).Once()
+	mockIdempotency.On("DeleteExpired", mock.Anything, idempotencySweepBatchSize).Return(3, nil).Once().Run(func(mock.Arguments) { cancel() })
+
+	done := make(chan struct{})
+	go func() {
+		RunIdempotencyKeySweeper(ctx, mockIdempotency, time.Millisecond, logger)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop")
+	}
+	mockIdempotency.AssertExpectations(t)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/notification"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Notification types emitted by the price change workflow.
+const (
+	NotificationPriceChangeRequested = "price_change.requested"
+	NotificationPriceChangeDecided   = "price_change.decided"
+)
+
+// priceChangeService implements PriceChangeService.
+type priceChangeService struct {
+	productRepo       repository.ProductRepository
+	priceChangeRepo   repository.PriceChangeRepository
+	notifier          notification.Notifier
+	approvalThreshold float64
+	logger            zerolog.Logger
+}
+
+// NewPriceChangeService creates a new price change service.
+// approvalThreshold is the percentage price delta above which a change
+// requires approval; zero requires approval for every change.
+func NewPriceChangeService(
+	productRepo repository.ProductRepository,
+	priceChangeRepo repository.PriceChangeRepository,
+	notifier notification.Notifier,
+	approvalThreshold float64,
+	logger zerolog.Logger,
+) PriceChangeService {
+	return &priceChangeService{
+		productRepo:       productRepo,
+		priceChangeRepo:   priceChangeRepo,
+		notifier:          notifier,
+		approvalThreshold: approvalThreshold,
+		logger:            logger.With().Str("service", "price_change").Logger(),
+	}
+}
+
+// RequestPriceChange applies or queues a product price change depending on its size.
+func (s *priceChangeService) RequestPriceChange(ctx context.Context, productID string, newPrice model.Money, requestedBy string) (*model.PriceChange, error) {
+	if newPrice < 0 || !newPrice.WholeCents() {
+		return nil, model.ErrInvalidPrice
+	}
+
+	product, err := s.product(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.request(ctx, &model.PriceChange{
+		ProductID:   productID,
+		OldPrice:    product.Price,
+		NewPrice:    newPrice,
+		RequestedBy: requestedBy,
+	})
+}
+
+// RequestSale applies or queues a product sale depending on its discount.
+func (s *priceChangeService) RequestSale(ctx context.Context, productID string, sale model.ProductSale, requestedBy string) (*model.PriceChange, error) {
+	product, err := s.product(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sale.Validate(product.Price, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.request(ctx, &model.PriceChange{
+		ProductID:   productID,
+		OldPrice:    product.Price,
+		NewPrice:    sale.Price,
+		Sale:        &sale,
+		RequestedBy: requestedBy,
+	})
+}
+
+// product loads a product, returning model.ErrProductNotFound if it does not
+// exist.
+func (s *priceChangeService) product(ctx context.Context, productID string) (*model.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to get product")
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// request records a change, applied immediately when within the approval
+// threshold and pending otherwise.
+func (s *priceChangeService) request(ctx context.Context, change *model.PriceChange) (*model.PriceChange, error) {
+	change.Status = model.PriceChangeStatusApplied
+	if s.requiresApproval(change.OldPrice, change.NewPrice) {
+		change.Status = model.PriceChangeStatusPending
+	}
+
+	if err := s.priceChangeRepo.Create(ctx, change); err != nil {
+		if err == model.ErrPriceChangePending {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", change.ProductID).Msg("failed to create price change")
+		return nil, fmt.Errorf("failed to create price change: %w", err)
+	}
+
+	s.logger.Info().
+		Str("price_change_id", change.ID.String()).
+		Str("product_id", change.ProductID).
+		Stringer("old_price", change.OldPrice).
+		Stringer("new_price", change.NewPrice).
+		Bool("sale", change.Sale != nil).
+		Str("status", string(change.Status)).
+		Str("requested_by", change.RequestedBy).
+		Msg("price change requested")
+
+	if change.Status == model.PriceChangeStatusPending {
+		s.notify(ctx, NotificationPriceChangeRequested, "price change awaiting approval", change)
+	}
+
+	return change, nil
+}
+
+// ListPending retrieves price changes awaiting approval.
+func (s *priceChangeService) ListPending(ctx context.Context) ([]model.PriceChange, error) {
+	changes, err := s.priceChangeRepo.ListPending(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list pending price changes")
+		return nil, fmt.Errorf("failed to list pending price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Approve applies a pending price change.
+func (s *priceChangeService) Approve(ctx context.Context, id uuid.UUID, approvedBy string) (*model.PriceChange, error) {
+	return s.decide(ctx, id, model.PriceChangeStatusApproved, approvedBy)
+}
+
+// Reject discards a pending price change.
+func (s *priceChangeService) Reject(ctx context.Context, id uuid.UUID, rejectedBy string) (*model.PriceChange, error) {
+	return s.decide(ctx, id, model.PriceChangeStatusRejected, rejectedBy)
+}
+
+// decide records a decision on a pending price change made by a second admin.
+func (s *priceChangeService) decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error) {
+	existing, err := s.priceChangeRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("price_change_id", id.String()).Msg("failed to get price change")
+		return nil, fmt.Errorf("failed to get price change: %w", err)
+	}
+	if existing == nil {
+		return nil, model.ErrPriceChangeNotFound
+	}
+	if existing.Status != model.PriceChangeStatusPending {
+		return nil, model.ErrPriceChangeNotPending
+	}
+	if existing.RequestedBy == decidedBy {
+		s.logger.Warn().
+			Str("price_change_id", id.String()).
+			Str("admin", decidedBy).
+			Msg("admin attempted to decide their own price change")
+		return nil, model.ErrSelfApproval
+	}
+
+	change, err := s.priceChangeRepo.Decide(ctx, id, status, decidedBy)
+	if err != nil {
+		if _, ok := err.(*model.DomainError); ok {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("price_change_id", id.String()).Msg("failed to decide price change")
+		return nil, fmt.Errorf("failed to decide price change: %w", err)
+	}
+
+	s.logger.Info().
+		Str("price_change_id", id.String()).
+		Str("product_id", change.ProductID).
+		Str("status", string(status)).
+		Str("decided_by", decidedBy).
+		Msg("price change decided")
+
+	s.notify(ctx, NotificationPriceChangeDecided, "price change "+string(status), change)
+
+	return change, nil
+}
+
+// requiresApproval reports whether the percentage delta between the old and
+// new price exceeds the approval threshold.
+func (s *priceChangeService) requiresApproval(oldPrice, newPrice model.Money) bool {
+	if oldPrice == newPrice {
+		return false
+	}
+	if oldPrice == 0 {
+		return true
+	}
+
+	delta := math.Abs(float64(newPrice-oldPrice)) / float64(oldPrice) * 100
+	return delta > s.approvalThreshold
+}
+
+// notify sends a notification about a price change. Delivery failures are
+// logged but do not fail the request.
+func (s *priceChangeService) notify(ctx context.Context, notificationType, message string, change *model.PriceChange) {
+	fields := map[string]string{
+		"price_change_id": change.ID.String(),
+		"product_id":      change.ProductID,
+		"old_price":       change.OldPrice.String(),
+		"new_price":       change.NewPrice.String(),
+		"status":          string(change.Status),
+		"requested_by":    change.RequestedBy,
+	}
+	if change.DecidedBy != nil {
+		fields["decided_by"] = *change.DecidedBy
+	}
+	if change.Sale != nil {
+		fields["sale"] = "true"
+	}
+
+	err := s.notifier.Notify(ctx, notification.Notification{
+		Type:    notificationType,
+		Message: message,
+		Fields:  fields,
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("price_change_id", change.ID.String()).Msg("failed to send price change notification")
+	}
+}
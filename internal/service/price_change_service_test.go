@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/notification"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPriceChangeRepository is a mock implementation of PriceChangeRepository.
+type MockPriceChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceChangeRepository) Create(ctx context.Context, change *model.PriceChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockPriceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PriceChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeRepository) ListPending(ctx context.Context) ([]model.PriceChange, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PriceChange), args.Error(1)
+}
+
+func (m *MockPriceChangeRepository) Decide(ctx context.Context, id uuid.UUID, status model.PriceChangeStatus, decidedBy string) (*model.PriceChange, error) {
+	args := m.Called(ctx, id, status, decidedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PriceChange), args.Error(1)
+}
+
+// MockNotifier is a mock implementation of notification.Notifier.
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, n notification.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func TestPriceChangeService_RequestPriceChange(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	product := &model.Product{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1"}
+
+	tests := []struct {
+		name           string
+		productID      string
+		newPrice       float64
+		mockProduct    *model.Product
+		createError    error
+		expectedStatus model.PriceChangeStatus
+		expectNotify   bool
+		expectedErr    error
+	}{
+		{
+			name:           "Within threshold is applied immediately",
+			productID:      "P001",
+			newPrice:       11.00,
+			mockProduct:    product,
+			expectedStatus: model.PriceChangeStatusApplied,
+		},
+		{
+			name:           "Exactly at threshold is applied immediately",
+			productID:      "P001",
+			newPrice:       8.00,
+			mockProduct:    product,
+			expectedStatus: model.PriceChangeStatusApplied,
+		},
+		{
+			name:           "Above threshold requires approval",
+			productID:      "P001",
+			newPrice:       15.00,
+			mockProduct:    product,
+			expectedStatus: model.PriceChangeStatusPending,
+			expectNotify:   true,
+		},
+		{
+			name:           "Large decrease requires approval",
+			productID:      "P001",
+			newPrice:       1.00,
+			mockProduct:    product,
+			expectedStatus: model.PriceChangeStatusPending,
+			expectNotify:   true,
+		},
+		{
+			name:        "Negative price",
+			productID:   "P001",
+			newPrice:    -1,
+			expectedErr: model.ErrInvalidPrice,
+		},
+		{
+			name:        "Product not found",
+			productID:   "P999",
+			newPrice:    5.00,
+			expectedErr: model.ErrProductNotFound,
+		},
+		{
+			name:        "Change already pending",
+			productID:   "P001",
+			newPrice:    50.00,
+			mockProduct: product,
+			createError: model.ErrPriceChangePending,
+			expectedErr: model.ErrPriceChangePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(MockProductRepository)
+			priceChangeRepo := new(MockPriceChangeRepository)
+			notifier := new(MockNotifier)
+			svc := NewPriceChangeService(productRepo, priceChangeRepo, notifier, 20, logger)
+
+			if tt.newPrice >= 0 {
+				productRepo.On("GetByID", ctx, tt.productID).Return(tt.mockProduct, nil)
+			}
+			if tt.mockProduct != nil {
+				priceChangeRepo.On("Create", ctx, mock.AnythingOfType("*model.PriceChange")).Return(tt.createError)
+			}
+			if tt.expectNotify {
+				notifier.On("Notify", ctx, mock.MatchedBy(func(n notification.Notification) bool {
+					return n.Type == NotificationPriceChangeRequested && n.Fields["product_id"] == tt.productID
+				})).Return(nil)
+			}
+
+			change, err := svc.RequestPriceChange(ctx, tt.productID, model.NewMoney(tt.newPrice), "admin-a")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, change)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, change.Status)
+				assert.Equal(t, product.Price, change.OldPrice)
+				assert.Equal(t, model.NewMoney(tt.newPrice), change.NewPrice)
+				assert.Equal(t, "admin-a", change.RequestedBy)
+			}
+
+			productRepo.AssertExpectations(t)
+			priceChangeRepo.AssertExpectations(t)
+			notifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceChangeService_RequestSale(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	product := &model.Product{ID: "P001", Name: "Product 1", Price: model.NewMoney(10.00), Category: "Cat1"}
+	ended := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		productID      string
+		sale           model.ProductSale
+		mockProduct    *model.Product
+		createError    error
+		expectCreate   bool
+		expectedStatus model.PriceChangeStatus
+		expectNotify   bool
+		expectedErr    error
+	}{
+		{
+			name:           "Small discount is applied immediately",
+			productID:      "P001",
+			sale:           model.ProductSale{Price: model.NewMoney(9.00)},
+			mockProduct:    product,
+			expectCreate:   true,
+			expectedStatus: model.PriceChangeStatusApplied,
+		},
+		{
+			name:           "Large discount requires approval",
+			productID:      "P001",
+			sale:           model.ProductSale{Price: model.NewMoney(2.00)},
+			mockProduct:    product,
+			expectCreate:   true,
+			expectedStatus: model.PriceChangeStatusPending,
+			expectNotify:   true,
+		},
+		{
+			name:           "Free open-ended sale requires approval",
+			productID:      "P001",
+			sale:           model.ProductSale{Price: 0},
+			mockProduct:    product,
+			expectCreate:   true,
+			expectedStatus: model.PriceChangeStatusPending,
+			expectNotify:   true,
+		},
+		{
+			name:        "Invalid sale",
+			productID:   "P001",
+			sale:        model.ProductSale{Price: model.NewMoney(5.00), EndsAt: &ended},
+			mockProduct: product,
+			expectedErr: model.ErrInvalidSale,
+		},
+		{
+			name:        "Product not found",
+			productID:   "P999",
+			sale:        model.ProductSale{Price: model.NewMoney(5.00)},
+			expectedErr: model.ErrProductNotFound,
+		},
+		{
+			name:         "Change already pending",
+			productID:    "P001",
+			sale:         model.ProductSale{Price: model.NewMoney(1.00)},
+			mockProduct:  product,
+			expectCreate: true,
+			createError:  model.ErrPriceChangePending,
+			expectedErr:  model.ErrPriceChangePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(MockProductRepository)
+			priceChangeRepo := new(MockPriceChangeRepository)
+			notifier := new(MockNotifier)
+			svc := NewPriceChangeService(productRepo, priceChangeRepo, notifier, 20, logger)
+
+			productRepo.On("GetByID", ctx, tt.productID).Return(tt.mockProduct, nil)
+			if tt.expectCreate {
+				priceChangeRepo.On("Create", ctx, mock.MatchedBy(func(c *model.PriceChange) bool {
+					return c.Sale != nil && *c.Sale == tt.sale && c.NewPrice == tt.sale.Price
+				})).Return(tt.createError)
+			}
+			if tt.expectNotify {
+				notifier.On("Notify", ctx, mock.MatchedBy(func(n notification.Notification) bool {
+					return n.Type == NotificationPriceChangeRequested && n.Fields["sale"] == "true"
+				})).Return(nil)
+			}
+
+			change, err := svc.RequestSale(ctx, tt.productID, tt.sale, "admin-a")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, change)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, change.Status)
+				assert.Equal(t, product.Price, change.OldPrice)
+				assert.Equal(t, tt.sale.Price, change.NewPrice)
+				assert.Equal(t, "admin-a", change.RequestedBy)
+			}
+
+			if !tt.expectCreate {
+				priceChangeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			productRepo.AssertExpectations(t)
+			priceChangeRepo.AssertExpectations(t)
+			notifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceChangeService_Approve(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	id := uuid.New()
+
+	pending := &model.PriceChange{
+		ID:          id,
+		ProductID:   "P001",
+		OldPrice:    model.NewMoney(10.00),
+		NewPrice:    model.NewMoney(15.00),
+		Status:      model.PriceChangeStatusPending,
+		RequestedBy: "admin-a",
+	}
+
+	tests := []struct {
+		name         string
+		approvedBy   string
+		existing     *model.PriceChange
+		decideError  error
+		expectDecide bool
+		expectedErr  error
+	}{
+		{
+			name:         "Second admin approves",
+			approvedBy:   "admin-b",
+			existing:     pending,
+			expectDecide: true,
+		},
+		{
+			name:        "Requester cannot approve own change",
+			approvedBy:  "admin-a",
+			existing:    pending,
+			expectedErr: model.ErrSelfApproval,
+		},
+		{
+			name:        "Change not found",
+			approvedBy:  "admin-b",
+			expectedErr: model.ErrPriceChangeNotFound,
+		},
+		{
+			name:       "Change already decided",
+			approvedBy: "admin-b",
+			existing: &model.PriceChange{
+				ID:          id,
+				Status:      model.PriceChangeStatusRejected,
+				RequestedBy: "admin-a",
+			},
+			expectedErr: model.ErrPriceChangeNotPending,
+		},
+		{
+			name:         "Concurrent decision wins",
+			approvedBy:   "admin-b",
+			existing:     pending,
+			decideError:  model.ErrPriceChangeNotPending,
+			expectDecide: true,
+			expectedErr:  model.ErrPriceChangeNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(MockProductRepository)
+			priceChangeRepo := new(MockPriceChangeRepository)
+			notifier := new(MockNotifier)
+			svc := NewPriceChangeService(productRepo, priceChangeRepo, notifier, 20, logger)
+
+			priceChangeRepo.On("GetByID", ctx, id).Return(tt.existing, nil)
+			if tt.expectDecide {
+				if tt.decideError != nil {
+					priceChangeRepo.On("Decide", ctx, id, model.PriceChangeStatusApproved, tt.approvedBy).
+						Return(nil, tt.decideError)
+				} else {
+					approved := *pending
+					approved.Status = model.PriceChangeStatusApproved
+					approved.DecidedBy = &tt.approvedBy
+					priceChangeRepo.On("Decide", ctx, id, model.PriceChangeStatusApproved, tt.approvedBy).
+						Return(&approved, nil)
+					notifier.On("Notify", ctx, mock.MatchedBy(func(n notification.Notification) bool {
+						return n.Type == NotificationPriceChangeDecided && n.Fields["decided_by"] == tt.approvedBy
+					})).Return(errors.New("notification channel unavailable"))
+				}
+			}
+
+			change, err := svc.Approve(ctx, id, tt.approvedBy)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, change)
+			} else {
+				require.NoError(t, err, "notification failures must not fail the approval")
+				assert.Equal(t, model.PriceChangeStatusApproved, change.Status)
+			}
+
+			priceChangeRepo.AssertExpectations(t)
+			notifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceChangeService_Reject(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	id := uuid.New()
+
+	pending := &model.PriceChange{ID: id, ProductID: "P001", Status: model.PriceChangeStatusPending, RequestedBy: "admin-a"}
+	rejectedBy := "admin-b"
+	rejected := *pending
+	rejected.Status = model.PriceChangeStatusRejected
+	rejected.DecidedBy = &rejectedBy
+
+	priceChangeRepo := new(MockPriceChangeRepository)
+	notifier := new(MockNotifier)
+	svc := NewPriceChangeService(new(MockProductRepository), priceChangeRepo, notifier, 20, logger)
+
+	priceChangeRepo.On("GetByID", ctx, id).Return(pending, nil)
+	priceChangeRepo.On("Decide", ctx, id, model.PriceChangeStatusRejected, rejectedBy).Return(&rejected, nil)
+	notifier.On("Notify", ctx, mock.AnythingOfType("notification.Notification")).Return(nil)
+
+	change, err := svc.Reject(ctx, id, rejectedBy)
+	require.NoError(t, err)
+	assert.Equal(t, model.PriceChangeStatusRejected, change.Status)
+
+	priceChangeRepo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
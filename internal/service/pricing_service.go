@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-kart/internal/coupon"
+	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// pricingService implements PricingService.
+type pricingService struct {
+	productRepo repository.ProductRepository
+	validator   coupon.CodeValidator
+	engine      pricing.Engine
+	currency    string
+	logger      zerolog.Logger
+}
+
+// NewPricingService creates a new pricing service.
+// currency is the catalogue currency; previews in any other currency are rejected.
+func NewPricingService(
+	productRepo repository.ProductRepository,
+	validator coupon.CodeValidator,
+	engine pricing.Engine,
+	currency string,
+	logger zerolog.Logger,
+) PricingService {
+	return &pricingService{
+		productRepo: productRepo,
+		validator:   validator,
+		engine:      engine,
+		currency:    currency,
+		logger:      logger.With().Str("service", "pricing").Logger(),
+	}
+}
+
+// Preview validates the request and computes its price breakdown.
+func (s *pricingService) Preview(ctx context.Context, req *model.PricingRequest) (*model.PriceBreakdown, error) {
+	if req == nil {
+		return nil, fmt.Errorf("pricing request is nil")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("pricing request must contain at least one item")
+	}
+
+	if req.Currency != "" && !strings.EqualFold(req.Currency, s.currency) {
+		s.logger.Debug().Str("currency", req.Currency).Msg("unsupported preview currency")
+		return nil, model.ErrUnsupportedCurrency
+	}
+
+	if req.Address != nil && strings.TrimSpace(req.Address.Country) == "" {
+		return nil, model.ErrInvalidAddress
+	}
+
+	productIDs := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		if item.ProductID == "" {
+			return nil, fmt.Errorf("item %d: product ID is required", i)
+		}
+		if item.Quantity <= 0 {
+			return nil, model.ErrInvalidQuantity
+		}
+		productIDs[i] = item.ProductID
+	}
+
+	discount, err := resolveCoupon(ctx, s.validator, req.CouponCode, nil)
+	if err != nil {
+		s.logger.Debug().Str("coupon_code", *req.CouponCode).Err(err).Msg("invalid coupon code in preview")
+		return nil, err
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", len(productIDs)).Msg("failed to get products for preview")
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	breakdown, err := s.engine.Price(ctx, pricing.Input{
+		Items:    req.Items,
+		Products: products,
+		Address:  req.Address,
+		Discount: discount,
+	})
+	if err != nil {
+		if err == model.ErrProductNotFound {
+			s.logger.Warn().Int("product_count", len(productIDs)).Msg("preview references unknown products")
+			return nil, err
+		}
+		if err == model.ErrCouponNotApplicable || err == model.ErrCouponMinSubtotal {
+			s.logger.Warn().Str("coupon_code", *req.CouponCode).Err(err).Msg("preview coupon does not apply")
+			return nil, err
+		}
+		s.logger.Error().Err(err).Msg("failed to price preview")
+		return nil, fmt.Errorf("failed to price preview: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// PreviewProductCoupon prices one unit of a product with a coupon applied,
+// through the same engine as Preview. Codes that are refused, or that do not
+// cover the product's category, give an inapplicable preview rather than an
+// error; only failures to evaluate the code are returned.
+func (s *pricingService) PreviewProductCoupon(ctx context.Context, product *model.Product, code string) (*model.ProductCouponPreview, error) {
+	now := time.Now()
+	price, _ := product.PriceAt(now)
+	preview := &model.ProductCouponPreview{
+		Code:            code,
+		Price:           price,
+		DiscountedPrice: price,
+	}
+
+	discount, err := resolveCoupon(ctx, s.validator, &code, nil)
+
+	var breakdown *model.PriceBreakdown
+	if err == nil {
+		breakdown, err = s.engine.Price(ctx, pricing.Input{
+			Items:    []model.OrderItemRequest{{ProductID: product.ID, Quantity: 1}},
+			Products: []model.Product{*product},
+			Discount: discount,
+			At:       now,
+		})
+	}
+
+	switch err {
+	case nil:
+	case model.ErrInvalidPromoCode, model.ErrInvalidPromoFormat, model.ErrInvalidPromoLength,
+		model.ErrCouponExpired, model.ErrCouponNotApplicable, model.ErrCouponMinSubtotal,
+		model.ErrCouponFirstOrderOnly, model.ErrCouponNotForCustomer, model.ErrCouponSegment:
+		preview.Reason = err.(*model.DomainError).Code
+		return preview, nil
+	default:
+		s.logger.Error().Err(err).Str("product_id", product.ID).Msg("failed to preview product coupon")
+		return nil, fmt.Errorf("failed to preview coupon: %w", err)
+	}
+
+	preview.Applicable = true
+	preview.Discount = breakdown.Discount
+	preview.DiscountedPrice = breakdown.Total
+	return preview, nil
+}
+
+// ValidateCoupon checks a promo code against the coupon files. Discount
+// rules that depend on the cart, such as expiry, minimum subtotal and
+// category restrictions, are left to Preview.
+func (s *pricingService) ValidateCoupon(ctx context.Context, code string) (*model.CouponValidation, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, fmt.Errorf("coupon code is required")
+	}
+
+	result := s.validator.Check(ctx, code)
+	if result.Reason == model.ErrCodeCouponsLoading {
+		// Says nothing about the code itself
+		return nil, model.ErrCouponsLoading
+	}
+	s.logger.Debug().
+		Bool("valid", result.Valid).
+		Str("reason", result.Reason).
+		Int("match_count", result.MatchCount).
+		Msg("validated coupon code")
+
+	return &result, nil
+}
+
+// resolveCoupon validates a coupon code and returns the discount it grants,
+// or nil when no code is given or the code grants none. Discounts restricted
+// to some customers are checked against the ordering customer, nil when
+// there is none, such as for price previews.
+func resolveCoupon(ctx context.Context, validator coupon.CodeValidator, code *string, customerID *uuid.UUID) (*model.CouponDiscount, error) {
+	if code == nil || *code == "" {
+		return nil, nil
+	}
+
+	discount, err := validator.ValidateAndResolve(ctx, *code)
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.CheckEligibility(ctx, discount, customerID); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+// resolveUnverifiedCoupon resolves the discount of a coupon code the
+// validator could not check, with the same restrictions as resolveCoupon.
+func resolveUnverifiedCoupon(ctx context.Context, validator coupon.CodeValidator, code string, customerID *uuid.UUID) (*model.CouponDiscount, error) {
+	discount, err := validator.Resolve(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.CheckEligibility(ctx, discount, customerID); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mini-kart/internal/fixtures"
+	"mini-kart/internal/model"
+	"mini-kart/internal/pricing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricingService_Preview(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	validCode := "HAPPYHRS"
+	invalidCode := "NOTACODE"
+	products := fixtures.Products(1)
+
+	tests := []struct {
+		name          string
+		req           *model.PricingRequest
+		setupMocks    func(*MockProductRepository, *MockCouponValidator)
+		expectedTotal float64
+		expectedErr   error
+		expectError   bool
+	}{
+		{
+			name: "Success with coupon and address",
+			req: &model.PricingRequest{
+				Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+				CouponCode: &validCode,
+				Currency:   "aud",
+				Address:    &model.Address{Country: "AU"},
+			},
+			setupMocks: func(pr *MockProductRepository, v *MockCouponValidator) {
+				v.On("ValidateAndResolve", ctx, validCode).Return(nil, nil)
+				pr.On("GetByIDs", ctx, []string{"P001"}).Return(products, nil)
+			},
+			expectedTotal: 25.00,
+		},
+		{
+			name: "Invalid coupon",
+			req: &model.PricingRequest{
+				Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				CouponCode: &invalidCode,
+			},
+			setupMocks: func(pr *MockProductRepository, v *MockCouponValidator) {
+				v.On("ValidateAndResolve", ctx, invalidCode).Return(nil, model.ErrInvalidPromoCode)
+			},
+			expectedErr: model.ErrInvalidPromoCode,
+		},
+		{
+			name: "Unknown product",
+			req: &model.PricingRequest{
+				Items: []model.OrderItemRequest{{ProductID: "P999", Quantity: 1}},
+			},
+			setupMocks: func(pr *MockProductRepository, v *MockCouponValidator) {
+				pr.On("GetByIDs", ctx, []string{"P999"}).Return([]model.Product{}, nil)
+			},
+			expectedErr: model.ErrProductNotFound,
+		},
+		{
+			name: "Unsupported currency",
+			req: &model.PricingRequest{
+				Items:    []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Currency: "EUR",
+			},
+			expectedErr: model.ErrUnsupportedCurrency,
+		},
+		{
+			name: "Address without country",
+			req: &model.PricingRequest{
+				Items:   []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+				Address: &model.Address{City: "Sydney"},
+			},
+			expectedErr: model.ErrInvalidAddress,
+		},
+		{
+			name: "Invalid quantity",
+			req: &model.PricingRequest{
+				Items: []model.OrderItemRequest{{ProductID: "P001", Quantity: 0}},
+			},
+			expectedErr: model.ErrInvalidQuantity,
+		},
+		{
+			name:        "Empty items",
+			req:         &model.PricingRequest{},
+			expectError: true,
+		},
+		{
+			name: "Repository error",
+			req: &model.PricingRequest{
+				Items: []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+			},
+			setupMocks: func(pr *MockProductRepository, v *MockCouponValidator) {
+				pr.On("GetByIDs", ctx, []string{"P001"}).Return(nil, errors.New("database error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProductRepo := new(MockProductRepository)
+			mockValidator := new(MockCouponValidator)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockProductRepo, mockValidator)
+			}
+
+			engine := pricing.NewEngine(pricing.Config{Currency: "AUD", ShippingFlatRate: model.MoneyFromCents(500)})
+			svc := NewPricingService(mockProductRepo, mockValidator, engine, "AUD", logger)
+
+			breakdown, err := svc.Preview(ctx, tt.req)
+
+			switch {
+			case tt.expectedErr != nil:
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, breakdown)
+			case tt.expectError:
+				assert.Error(t, err)
+				assert.Nil(t, breakdown)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, model.NewMoney(tt.expectedTotal), breakdown.Total)
+			}
+
+			mockProductRepo.AssertExpectations(t)
+			mockValidator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingService_Preview_AppliesCouponDiscount(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	code := "FIVEOFF12"
+	amountOff := model.NewMoney(5)
+
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+
+	mockValidator.On("ValidateAndResolve", ctx, code).
+		Return(&model.CouponDiscount{Code: code, AmountOff: &amountOff}, nil)
+	mockProductRepo.On("GetByIDs", ctx, []string{"P001"}).
+		Return(fixtures.Products(1), nil)
+
+	engine := pricing.NewEngine(pricing.Config{Currency: "AUD", ShippingFlatRate: model.MoneyFromCents(500)})
+	svc := NewPricingService(mockProductRepo, mockValidator, engine, "AUD", logger)
+
+	breakdown, err := svc.Preview(ctx, &model.PricingRequest{
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 2}},
+		CouponCode: &code,
+		Address:    &model.Address{Country: "AU"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.NewMoney(20.00), breakdown.Subtotal)
+	assert.Equal(t, model.NewMoney(5.00), breakdown.Discount)
+	assert.Equal(t, model.NewMoney(5.00), breakdown.Shipping)
+	assert.Equal(t, model.NewMoney(20.00), breakdown.Total)
+	mockValidator.AssertExpectations(t)
+}
+
+func TestPricingService_Preview_FirstOrderOnlyCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	code := "WELCOME15"
+	percentOff := 15.0
+
+	mockProductRepo := new(MockProductRepository)
+	mockValidator := new(MockCouponValidator)
+
+	discount := &model.CouponDiscount{Code: code, PercentOff: &percentOff, FirstOrderOnly: true}
+	mockValidator.On("ValidateAndResolve", ctx, code).Return(discount, nil)
+	// Previews have no customer to check
+	mockValidator.On("CheckEligibility", ctx, discount, (*uuid.UUID)(nil)).Return(model.ErrCouponFirstOrderOnly)
+
+	engine := pricing.NewEngine(pricing.Config{Currency: "AUD"})
+	svc := NewPricingService(mockProductRepo, mockValidator, engine, "AUD", logger)
+
+	breakdown, err := svc.Preview(ctx, &model.PricingRequest{
+		Items:      []model.OrderItemRequest{{ProductID: "P001", Quantity: 1}},
+		CouponCode: &code,
+	})
+
+	assert.Equal(t, model.ErrCouponFirstOrderOnly, err)
+	assert.Nil(t, breakdown)
+}
+
+func TestPricingService_PreviewProductCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	code := "TENPCT123"
+	percentOff := 10.0
+	product := fixtures.NewProduct(1).Price(12.50).Ptr()
+
+	tests := []struct {
+		name            string
+		discount        *model.CouponDiscount
+		resolveErr      error
+		eligibility     error
+		expectedPreview *model.ProductCouponPreview
+		expectError     bool
+	}{
+		{
+			name:     "Applies discount",
+			discount: &model.CouponDiscount{Code: code, PercentOff: &percentOff},
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Applicable: true, Price: model.NewMoney(12.50), Discount: model.NewMoney(1.25), DiscountedPrice: model.NewMoney(11.25),
+			},
+		},
+		{
+			name:     "Other category",
+			discount: &model.CouponDiscount{Code: code, PercentOff: &percentOff, Categories: []string{"Cat2"}},
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeCouponNotApplicable, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:        "First order only",
+			discount:    &model.CouponDiscount{Code: code, PercentOff: &percentOff, FirstOrderOnly: true},
+			eligibility: model.ErrCouponFirstOrderOnly,
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeCouponFirstOrderOnly, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:        "Customer segment only",
+			discount:    &model.CouponDiscount{Code: code, PercentOff: &percentOff, Segments: []string{"vip"}},
+			eligibility: model.ErrCouponSegment,
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeCouponSegment, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:     "Minimum basket above the product price",
+			discount: &model.CouponDiscount{Code: code, PercentOff: &percentOff, MinSubtotal: model.NewMoney(50)},
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeCouponMinSubtotal, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:       "Invalid code",
+			resolveErr: model.ErrInvalidPromoCode,
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeInvalidPromoCode, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:       "Expired code",
+			resolveErr: model.ErrCouponExpired,
+			expectedPreview: &model.ProductCouponPreview{
+				Code: code, Reason: model.ErrCodeCouponExpired, Price: model.NewMoney(12.50), DiscountedPrice: model.NewMoney(12.50),
+			},
+		},
+		{
+			name:        "Validation timeout",
+			resolveErr:  model.ErrCouponValidationTimeout,
+			expectError: true,
+		},
+		{
+			name:        "Discount lookup fails",
+			resolveErr:  errors.New("database error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockValidator := new(MockCouponValidator)
+			if tt.resolveErr != nil {
+				mockValidator.On("ValidateAndResolve", ctx, code).Return(nil, tt.resolveErr)
+			} else {
+				mockValidator.On("ValidateAndResolve", ctx, code).Return(tt.discount, nil)
+			}
+			if tt.discount.Restricted() {
+				mockValidator.On("CheckEligibility", ctx, tt.discount, (*uuid.UUID)(nil)).Return(tt.eligibility)
+			}
+
+			engine := pricing.NewEngine(pricing.Config{Currency: "AUD", ShippingFlatRate: model.MoneyFromCents(500)})
+			svc := NewPricingService(new(MockProductRepository), mockValidator, engine, "AUD", logger)
+
+			preview, err := svc.PreviewProductCoupon(ctx, product, code)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, preview)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedPreview, preview)
+			}
+			mockValidator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingService_ValidateCoupon(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	t.Run("Trims and checks the code", func(t *testing.T) {
+		expected := model.CouponValidation{Code: "HAPPYHRS", Reason: model.ErrCodeInvalidPromoCode, MatchCount: 1}
+		mockValidator := new(MockCouponValidator)
+		mockValidator.On("Check", ctx, "HAPPYHRS").Return(expected)
+
+		svc := NewPricingService(new(MockProductRepository), mockValidator, pricing.NewEngine(pricing.Config{Currency: "AUD"}), "AUD", logger)
+		result, err := svc.ValidateCoupon(ctx, " HAPPYHRS ")
+
+		require.NoError(t, err)
+		assert.Equal(t, &expected, result)
+		mockValidator.AssertExpectations(t)
+	})
+
+	t.Run("Empty code", func(t *testing.T) {
+		mockValidator := new(MockCouponValidator)
+
+		svc := NewPricingService(new(MockProductRepository), mockValidator, pricing.NewEngine(pricing.Config{Currency: "AUD"}), "AUD", logger)
+		result, err := svc.ValidateCoupon(ctx, "  ")
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		mockValidator.AssertNotCalled(t, "Check", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Coupons still loading", func(t *testing.T) {
+		mockValidator := new(MockCouponValidator)
+		mockValidator.On("Check", ctx, "HAPPYHRS").Return(model.CouponValidation{Code: "HAPPYHRS", Reason: model.ErrCodeCouponsLoading})
+
+		svc := NewPricingService(new(MockProductRepository), mockValidator, pricing.NewEngine(pricing.Config{Currency: "AUD"}), "AUD", logger)
+		result, err := svc.ValidateCoupon(ctx, "HAPPYHRS")
+
+		assert.Equal(t, model.ErrCouponsLoading, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mini-kart/internal/media"
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// productImageService implements ProductImageService.
+type productImageService struct {
+	repo        repository.ProductImageRepository
+	productRepo repository.ProductRepository
+	store       media.ImageStore
+	maxBytes    int64
+	logger      zerolog.Logger
+}
+
+// NewProductImageService creates a new product image service storing image
+// files of at most maxBytes in store.
+func NewProductImageService(repo repository.ProductImageRepository, productRepo repository.ProductRepository, store media.ImageStore, maxBytes int64, logger zerolog.Logger) ProductImageService {
+	return &productImageService{
+		repo:        repo,
+		productRepo: productRepo,
+		store:       store,
+		maxBytes:    maxBytes,
+		logger:      logger.With().Str("service", "product_image").Logger(),
+	}
+}
+
+// requireProduct returns model.ErrProductNotFound unless the context's
+// tenant has an active product with the ID.
+func (s *productImageService) requireProduct(ctx context.Context, productID string) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to get product")
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return model.ErrProductNotFound
+	}
+	return nil
+}
+
+// CreateUpload presigns an upload to a new key under the product's ID, so
+// uploads never replace an attached image.
+func (s *productImageService) CreateUpload(ctx context.Context, productID string, req *model.ImageUploadRequest) (*model.ImageUpload, error) {
+	if req == nil {
+		return nil, fmt.Errorf("image upload request cannot be nil")
+	}
+	if err := req.Validate(s.maxBytes); err != nil {
+		return nil, err
+	}
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	ext, _ := model.ImageExtension(req.ContentType)
+	key := productID + "/" + uuid.New().String() + ext
+
+	upload, err := s.store.PresignUpload(ctx, key, strings.ToLower(req.ContentType), req.SizeBytes)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to presign image upload")
+		return nil, fmt.Errorf("failed to presign image upload: %w", err)
+	}
+
+	return upload, nil
+}
+
+// AttachImage checks the uploaded file is an allowed image before attaching
+// it, since the client may not have uploaded it as presigned.
+func (s *productImageService) AttachImage(ctx context.Context, productID string, req *model.ProductImageRequest, actor string) (*model.ProductImage, error) {
+	if req == nil {
+		return nil, fmt.Errorf("product image request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Keys presigned for other products, or outside any product, were never
+	// uploads for this product
+	name, ok := strings.CutPrefix(req.Key, productID+"/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return nil, model.ErrImageNotUploaded
+	}
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	object, err := s.store.Stat(ctx, req.Key)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Str("key", req.Key).Msg("failed to check uploaded image")
+		return nil, fmt.Errorf("failed to check uploaded image: %w", err)
+	}
+	if object == nil {
+		return nil, model.ErrImageNotUploaded
+	}
+	upload := model.ImageUploadRequest{ContentType: object.ContentType, SizeBytes: object.SizeBytes}
+	if upload.Validate(s.maxBytes) != nil {
+		s.logger.Warn().
+			Str("product_id", productID).
+			Str("key", req.Key).
+			Str("content_type", object.ContentType).
+			Int64("size_bytes", object.SizeBytes).
+			Msg("uploaded image is not an allowed image")
+		return nil, model.ErrImageNotUploaded
+	}
+
+	image := &model.ProductImage{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		Key:         req.Key,
+		ContentType: strings.ToLower(object.ContentType),
+		SizeBytes:   object.SizeBytes,
+		AltText:     strings.TrimSpace(req.AltText),
+		CreatedBy:   actor,
+	}
+	if err := s.repo.Create(ctx, image); err != nil {
+		switch err {
+		case model.ErrProductNotFound, model.ErrImageAttached, model.ErrTooManyImages:
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to attach product image")
+		return nil, fmt.Errorf("failed to attach product image: %w", err)
+	}
+	image.URL = s.store.URL(image.Key)
+
+	s.logger.Info().Str("product_id", productID).Str("image_id", image.ID.String()).Str("actor", actor).Msg("product image attached")
+
+	return image, nil
+}
+
+// ListImages retrieves a product's images with their URLs.
+func (s *productImageService) ListImages(ctx context.Context, productID string) ([]model.ProductImage, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.ListByProducts(ctx, []string{productID})
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to list product images")
+		return nil, fmt.Errorf("failed to list product images: %w", err)
+	}
+
+	return withImageURLs(images[productID], s.store), nil
+}
+
+// ReorderImages sets the order of a product's images.
+func (s *productImageService) ReorderImages(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error) {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.Reorder(ctx, productID, ids)
+	if err != nil {
+		if err == model.ErrInvalidImageOrder {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to reorder product images")
+		return nil, fmt.Errorf("failed to reorder product images: %w", err)
+	}
+
+	return withImageURLs(images, s.store), nil
+}
+
+// DeleteImage detaches an image and then removes its file. A file that
+// cannot be removed is left in storage, unreferenced, rather than failing
+// the request.
+func (s *productImageService) DeleteImage(ctx context.Context, productID string, id uuid.UUID) error {
+	if err := s.requireProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	image, err := s.repo.Delete(ctx, productID, id)
+	if err != nil {
+		if err == model.ErrImageNotFound {
+			return err
+		}
+		s.logger.Error().Err(err).Str("product_id", productID).Msg("failed to delete product image")
+		return fmt.Errorf("failed to delete product image: %w", err)
+	}
+
+	if err := s.store.Delete(ctx, image.Key); err != nil {
+		s.logger.Warn().Err(err).Str("key", image.Key).Msg("failed to remove detached product image file")
+	}
+
+	s.logger.Info().Str("product_id", productID).Str("image_id", id.String()).Msg("product image deleted")
+
+	return nil
+}
+
+// withImageURLs sets the URL each image is served from in store.
+func withImageURLs(images []model.ProductImage, store media.ImageStore) []model.ProductImage {
+	if images == nil {
+		return []model.ProductImage{}
+	}
+	for i := range images {
+		images[i].URL = store.URL(images[i].Key)
+	}
+	return images
+}
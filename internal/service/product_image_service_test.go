@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProductImageRepository is a mock implementation of ProductImageRepository.
+type MockProductImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductImageRepository) Create(ctx context.Context, image *model.ProductImage) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
+
+func (m *MockProductImageRepository) ListByProducts(ctx context.Context, productIDs []string) (map[string][]model.ProductImage, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) Reorder(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error) {
+	args := m.Called(ctx, productID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) Delete(ctx context.Context, productID string, id uuid.UUID) (*model.ProductImage, error) {
+	args := m.Called(ctx, productID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductImage), args.Error(1)
+}
+
+// MockImageStore is a mock implementation of media.ImageStore. URLs are the
+// key under https://cdn.example.com/.
+type MockImageStore struct {
+	mock.Mock
+}
+
+func (m *MockImageStore) PresignUpload(ctx context.Context, key, contentType string, sizeBytes int64) (*model.ImageUpload, error) {
+	args := m.Called(ctx, key, contentType, sizeBytes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageUpload), args.Error(1)
+}
+
+func (m *MockImageStore) Stat(ctx context.Context, key string) (*model.ImageObject, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageObject), args.Error(1)
+}
+
+func (m *MockImageStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockImageStore) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+const testMaxImageBytes = 1024 * 1024
+
+func TestProductImageService_CreateUpload(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *model.ImageUploadRequest
+		setupMocks    func(*MockProductRepository, *MockImageStore)
+		expectedError error
+	}{
+		{
+			name: "Upload presigned under the product",
+			req:  &model.ImageUploadRequest{ContentType: "image/PNG", SizeBytes: 2048},
+			setupMocks: func(products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+				store.On("PresignUpload", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "P001/") && strings.HasSuffix(key, ".png")
+				}), "image/png", int64(2048)).Return(&model.ImageUpload{Key: "P001/a.png"}, nil)
+			},
+		},
+		{
+			name:          "Unsupported content type",
+			req:           &model.ImageUploadRequest{ContentType: "image/svg+xml", SizeBytes: 2048},
+			setupMocks:    func(products *MockProductRepository, store *MockImageStore) {},
+			expectedError: model.ErrInvalidImage,
+		},
+		{
+			name:          "File too large",
+			req:           &model.ImageUploadRequest{ContentType: "image/jpeg", SizeBytes: testMaxImageBytes + 1},
+			setupMocks:    func(products *MockProductRepository, store *MockImageStore) {},
+			expectedError: model.ErrInvalidImage,
+		},
+		{
+			name: "Product not found",
+			req:  &model.ImageUploadRequest{ContentType: "image/jpeg", SizeBytes: 2048},
+			setupMocks: func(products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(nil, nil)
+			},
+			expectedError: model.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockProductImageRepository)
+			products := new(MockProductRepository)
+			store := new(MockImageStore)
+			tt.setupMocks(products, store)
+			svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+			upload, err := svc.CreateUpload(context.Background(), "P001", tt.req)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, upload)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, upload)
+			}
+			products.AssertExpectations(t)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductImageService_AttachImage(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *model.ProductImageRequest
+		setupMocks    func(*MockProductImageRepository, *MockProductRepository, *MockImageStore)
+		expectedError error
+	}{
+		{
+			name: "Uploaded image attached",
+			req:  &model.ProductImageRequest{Key: "P001/a.jpg", AltText: " Front view "},
+			setupMocks: func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+				store.On("Stat", mock.Anything, "P001/a.jpg").Return(&model.ImageObject{ContentType: "image/jpeg", SizeBytes: 2048}, nil)
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(image *model.ProductImage) bool {
+					return image.ProductID == "P001" && image.AltText == "Front view" &&
+						image.SizeBytes == 2048 && image.CreatedBy == "admin"
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "Key of another product",
+			req:           &model.ProductImageRequest{Key: "P002/a.jpg"},
+			setupMocks:    func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {},
+			expectedError: model.ErrImageNotUploaded,
+		},
+		{
+			name:          "Key below the product",
+			req:           &model.ProductImageRequest{Key: "P001/x/a.jpg"},
+			setupMocks:    func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {},
+			expectedError: model.ErrImageNotUploaded,
+		},
+		{
+			name: "File never uploaded",
+			req:  &model.ProductImageRequest{Key: "P001/a.jpg"},
+			setupMocks: func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+				store.On("Stat", mock.Anything, "P001/a.jpg").Return(nil, nil)
+			},
+			expectedError: model.ErrImageNotUploaded,
+		},
+		{
+			name: "Uploaded file is not an image",
+			req:  &model.ProductImageRequest{Key: "P001/a.jpg"},
+			setupMocks: func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+				store.On("Stat", mock.Anything, "P001/a.jpg").Return(&model.ImageObject{ContentType: "text/html", SizeBytes: 2048}, nil)
+			},
+			expectedError: model.ErrImageNotUploaded,
+		},
+		{
+			name: "Image limit reached",
+			req:  &model.ProductImageRequest{Key: "P001/a.jpg"},
+			setupMocks: func(repo *MockProductImageRepository, products *MockProductRepository, store *MockImageStore) {
+				products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+				store.On("Stat", mock.Anything, "P001/a.jpg").Return(&model.ImageObject{ContentType: "image/jpeg", SizeBytes: 2048}, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*model.ProductImage")).Return(model.ErrTooManyImages)
+			},
+			expectedError: model.ErrTooManyImages,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockProductImageRepository)
+			products := new(MockProductRepository)
+			store := new(MockImageStore)
+			tt.setupMocks(repo, products, store)
+			svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+			image, err := svc.AttachImage(context.Background(), "P001", tt.req, "admin")
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, image)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "https://cdn.example.com/P001/a.jpg", image.URL)
+			}
+			repo.AssertExpectations(t)
+			products.AssertExpectations(t)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductImageService_DeleteImage(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("File removed with the image", func(t *testing.T) {
+		repo := new(MockProductImageRepository)
+		products := new(MockProductRepository)
+		store := new(MockImageStore)
+		products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+		repo.On("Delete", mock.Anything, "P001", id).Return(&model.ProductImage{ID: id, Key: "P001/a.jpg"}, nil)
+		store.On("Delete", mock.Anything, "P001/a.jpg").Return(nil)
+		svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+		require.NoError(t, svc.DeleteImage(context.Background(), "P001", id))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("File left behind when storage fails", func(t *testing.T) {
+		repo := new(MockProductImageRepository)
+		products := new(MockProductRepository)
+		store := new(MockImageStore)
+		products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+		repo.On("Delete", mock.Anything, "P001", id).Return(&model.ProductImage{ID: id, Key: "P001/a.jpg"}, nil)
+		store.On("Delete", mock.Anything, "P001/a.jpg").Return(errors.New("s3 unavailable"))
+		svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+		assert.NoError(t, svc.DeleteImage(context.Background(), "P001", id))
+	})
+
+	t.Run("Image not found", func(t *testing.T) {
+		repo := new(MockProductImageRepository)
+		products := new(MockProductRepository)
+		store := new(MockImageStore)
+		products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+		repo.On("Delete", mock.Anything, "P001", id).Return(nil, model.ErrImageNotFound)
+		svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+		assert.Equal(t, model.ErrImageNotFound, svc.DeleteImage(context.Background(), "P001", id))
+		store.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductImageService_ListImages(t *testing.T) {
+	repo := new(MockProductImageRepository)
+	products := new(MockProductRepository)
+	store := new(MockImageStore)
+	products.On("GetByID", mock.Anything, "P001").Return(&model.Product{ID: "P001"}, nil)
+	repo.On("ListByProducts", mock.Anything, []string{"P001"}).Return(map[string][]model.ProductImage{}, nil)
+	svc := NewProductImageService(repo, products, store, testMaxImageBytes, zerolog.Nop())
+
+	images, err := svc.ListImages(context.Background(), "P001")
+	require.NoError(t, err)
+	assert.NotNil(t, images)
+	assert.Empty(t, images)
+}
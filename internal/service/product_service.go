@@ -2,8 +2,16 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
+	"mini-kart/internal/media"
 	"mini-kart/internal/model"
 	"mini-kart/internal/repository"
 
@@ -13,45 +21,142 @@ import (
 // productService implements ProductService.
 type productService struct {
 	productRepo repository.ProductRepository
+	currency    string
+	images      repository.ProductImageRepository
+	imageStore  media.ImageStore
 	logger      zerolog.Logger
+	now         func() time.Time
+}
+
+// ProductServiceOption configures optional productService behaviour.
+type ProductServiceOption func(*productService)
+
+// WithCatalogueCurrency names the ISO 4217 currency catalogue prices are in.
+// New products must be priced in it, and products that do not name their
+// currency are shown in it. Without it products are created in the currency
+// they name, if any.
+func WithCatalogueCurrency(currency string) ProductServiceOption {
+	return func(s *productService) {
+		s.currency = model.NormalizeCurrency(currency)
+	}
+}
+
+// WithProductImages includes each product's images, read from images, in
+// the products GetAll and GetByID return, with URLs to the files in store.
+func WithProductImages(images repository.ProductImageRepository, store media.ImageStore) ProductServiceOption {
+	return func(s *productService) {
+		s.images = images
+		s.imageStore = store
+	}
 }
 
 // NewProductService creates a new product service.
-func NewProductService(productRepo repository.ProductRepository, logger zerolog.Logger) ProductService {
-	return &productService{
+func NewProductService(productRepo repository.ProductRepository, logger zerolog.Logger, opts ...ProductServiceOption) ProductService {
+	s := &productService{
 		productRepo: productRepo,
 		logger:      logger.With().Str("service", "product").Logger(),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// GetAll retrieves all products with pagination.
-func (s *productService) GetAll(ctx context.Context, limit, offset int) ([]model.Product, error) {
-	if limit <= 0 {
-		limit = 10
+// withEffectivePrice sets the price customers pay at now on p, and the
+// catalogue currency when p does not name its currency.
+func (s *productService) withEffectivePrice(p model.Product, now time.Time) model.Product {
+	if p.Currency == "" {
+		p.Currency = s.currency
 	}
-	if limit > 100 {
-		limit = 100
+	return p.WithEffectivePrice(now)
+}
+
+// withEffectivePrices sets the price customers pay now on each product.
+func (s *productService) withEffectivePrices(products []model.Product) []model.Product {
+	now := s.now()
+	for i := range products {
+		products[i] = s.withEffectivePrice(products[i], now)
 	}
-	if offset < 0 {
-		offset = 0
+	return products
+}
+
+// withImages sets the images of each product, when product images are
+// enabled.
+func (s *productService) withImages(ctx context.Context, products []model.Product) error {
+	if s.images == nil || len(products) == 0 {
+		return nil
 	}
 
-	products, err := s.productRepo.GetAll(ctx, limit, offset)
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	images, err := s.images.ListByProducts(ctx, ids)
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", len(ids)).Msg("failed to get product images")
+		return fmt.Errorf("failed to get product images: %w", err)
+	}
+
+	for i := range products {
+		products[i].Images = withImageURLs(images[products[i].ID], s.imageStore)
+	}
+	return nil
+}
+
+// GetAll retrieves products with optional category filtering, sorting and
+// pagination. Products are sorted by name in ascending order by default.
+func (s *productService) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, model.Page, error) {
+	if filter.Sort == "" {
+		filter.Sort = model.ProductSortName
+	}
+	if filter.Order == "" {
+		filter.Order = model.SortAsc
+	}
+	if !filter.Sort.Valid() || !filter.Order.Valid() {
+		return nil, model.Page{}, model.ErrInvalidProductSort
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	products, err := s.productRepo.GetAll(ctx, filter)
 	if err != nil {
 		s.logger.Error().Err(err).
-			Int("limit", limit).
-			Int("offset", offset).
+			Str("category", filter.Category).
+			Int("limit", filter.Limit).
+			Int("offset", filter.Offset).
 			Msg("failed to get all products")
-		return nil, fmt.Errorf("failed to get products: %w", err)
+		return nil, model.Page{}, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	total, err := s.productRepo.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Str("category", filter.Category).Msg("failed to count products")
+		return nil, model.Page{}, fmt.Errorf("failed to count products: %w", err)
 	}
 
 	s.logger.Debug().
 		Int("count", len(products)).
-		Int("limit", limit).
-		Int("offset", offset).
+		Str("category", filter.Category).
+		Str("sort", string(filter.Sort)).
+		Str("order", string(filter.Order)).
+		Int("limit", filter.Limit).
+		Int("offset", filter.Offset).
 		Msg("retrieved products")
 
-	return products, nil
+	if err := s.withImages(ctx, products); err != nil {
+		return nil, model.Page{}, err
+	}
+
+	return s.withEffectivePrices(products), model.Page{Limit: filter.Limit, Offset: filter.Offset, Total: total}, nil
 }
 
 // GetByID retrieves a single product by ID.
@@ -72,6 +177,12 @@ func (s *productService) GetByID(ctx context.Context, id string) (*model.Product
 		return nil, model.ErrProductNotFound
 	}
 
+	products := []model.Product{*product}
+	if err := s.withImages(ctx, products); err != nil {
+		return nil, err
+	}
+
+	*product = s.withEffectivePrice(products[0], s.now())
 	return product, nil
 }
 
@@ -92,5 +203,401 @@ func (s *productService) GetByIDs(ctx context.Context, ids []string) ([]model.Pr
 		Int("found", len(products)).
 		Msg("retrieved products by IDs")
 
-	return products, nil
+	return s.withEffectivePrices(products), nil
+}
+
+// maxArchiveBatch limits how many products one archive request may cover.
+const maxArchiveBatch = 100
+
+// ArchiveProducts archives a set of products on behalf of an admin.
+func (s *productService) ArchiveProducts(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error) {
+	// Normalise and de-duplicate the requested IDs
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, model.ErrInvalidProductIDs
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 || len(unique) > maxArchiveBatch {
+		return nil, model.ErrInvalidProductIDs
+	}
+
+	result, err := s.productRepo.Archive(ctx, unique, actor)
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", len(unique)).Msg("failed to archive products")
+		return nil, fmt.Errorf("failed to archive products: %w", err)
+	}
+
+	if len(result.Conflicts) > 0 {
+		return result, model.ErrProductArchiveConflict
+	}
+
+	s.logger.Info().
+		Int("count", len(result.Archived)).
+		Str("actor", actor).
+		Msg("products archived")
+
+	return result, nil
+}
+
+// maxCompareProducts limits how many products one comparison may cover.
+const maxCompareProducts = 4
+
+// Compare sets products side by side in the order requested.
+func (s *productService) Compare(ctx context.Context, ids []string) (*model.ProductComparison, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, model.ErrInvalidComparison
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) < 2 || len(unique) > maxCompareProducts {
+		return nil, model.ErrInvalidComparison
+	}
+
+	found, err := s.productRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		s.logger.Error().Err(err).Int("count", len(unique)).Msg("failed to get products to compare")
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	byID := make(map[string]model.Product, len(found))
+	for _, p := range s.withEffectivePrices(found) {
+		byID[p.ID] = p
+	}
+	products := make([]model.Product, len(unique))
+	for i, id := range unique {
+		p, ok := byID[id]
+		if !ok {
+			return nil, model.ErrProductNotFound
+		}
+		products[i] = p
+	}
+
+	return &model.ProductComparison{
+		Products:   products,
+		Attributes: compareAttributes(products),
+	}, nil
+}
+
+// compareAttributes builds the attribute matrix of a comparison: the
+// category and effective price rows, then a row per metadata attribute by name.
+// Metadata keys are matched case-insensitively and nested objects are
+// flattened into dotted names, so {"Nutrition": {"Protein": 3}} is compared
+// as "nutrition.protein".
+func compareAttributes(products []model.Product) []model.ComparisonAttribute {
+	flattened := make([]map[string]any, len(products))
+	var names []string
+	seen := make(map[string]bool)
+	for i, p := range products {
+		flattened[i] = make(map[string]any)
+		flattenMetadata("", p.Metadata, flattened[i])
+		for name := range flattened[i] {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	categories := make([]any, len(products))
+	prices := make([]any, len(products))
+	for i, p := range products {
+		categories[i] = p.Category
+		prices[i] = p.EffectivePrice
+	}
+
+	attributes := make([]model.ComparisonAttribute, 0, len(names)+2)
+	attributes = append(attributes, comparisonRow("category", categories), comparisonRow("price", prices))
+	for _, name := range names {
+		values := make([]any, len(products))
+		for i := range products {
+			values[i] = flattened[i][name]
+		}
+		attributes = append(attributes, comparisonRow(name, values))
+	}
+	return attributes
+}
+
+// comparisonRow returns the row of an attribute with the given value per
+// product.
+func comparisonRow(name string, values []any) model.ComparisonAttribute {
+	row := model.ComparisonAttribute{Name: name, Values: values}
+	for _, value := range values[1:] {
+		if !reflect.DeepEqual(value, values[0]) {
+			row.Differs = true
+		}
+	}
+	return row
+}
+
+// flattenMetadata copies metadata into attributes under lower-cased, dotted
+// names. Keys are visited in order, so of two keys differing only in case
+// the first in byte order wins.
+func flattenMetadata(prefix string, metadata map[string]any, attributes map[string]any) {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if nested, ok := metadata[key].(map[string]any); ok {
+			flattenMetadata(name, nested, attributes)
+			continue
+		}
+		if _, taken := attributes[name]; !taken {
+			attributes[name] = metadata[key]
+		}
+	}
+}
+
+// CreateProduct validates and adds a new product to the catalogue.
+func (s *productService) CreateProduct(ctx context.Context, req *model.ProductRequest) (*model.Product, error) {
+	if req.Price == nil {
+		return nil, model.ErrInvalidProduct
+	}
+
+	currency := model.NormalizeCurrency(req.Currency)
+	if currency == "" {
+		currency = s.currency
+	}
+	if currency != "" && (!model.ValidCurrency(currency) || (s.currency != "" && currency != s.currency)) {
+		s.logger.Debug().Str("currency", req.Currency).Msg("unsupported product currency")
+		return nil, model.ErrUnsupportedCurrency
+	}
+
+	id := strings.TrimSpace(req.ID)
+	product := &model.Product{
+		ID:       id,
+		Name:     strings.TrimSpace(req.Name),
+		Price:    *req.Price,
+		Category: strings.TrimSpace(req.Category),
+		Currency: currency,
+		Metadata: req.Metadata,
+	}
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		if err == model.ErrProductExists {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", id).Msg("failed to create product")
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	s.logger.Info().Str("product_id", id).Msg("product created")
+
+	*product = s.withEffectivePrice(*product, s.now())
+	return product, nil
+}
+
+// UpdateProduct changes a product's name and category.
+func (s *productService) UpdateProduct(ctx context.Context, id string, req *model.ProductRequest) (*model.Product, error) {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prices go through the reviewed price change workflow
+	if req.Price != nil && *req.Price != current.Price {
+		return nil, model.ErrPriceUpdateNotAllowed
+	}
+
+	product := &model.Product{
+		ID:       id,
+		Name:     strings.TrimSpace(req.Name),
+		Category: strings.TrimSpace(req.Category),
+		Metadata: req.Metadata,
+	}
+	if product.Name == "" || product.Category == "" {
+		return nil, model.ErrInvalidProduct
+	}
+
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		if err == model.ErrProductNotFound {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", id).Msg("failed to update product")
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	s.logger.Info().Str("product_id", id).Msg("product updated")
+
+	*product = s.withEffectivePrice(*product, s.now())
+	return product, nil
+}
+
+// SetSale schedules a sale on a product, or removes its sale when sale is
+// nil.
+func (s *productService) SetSale(ctx context.Context, id string, sale *model.ProductSale) (*model.Product, error) {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if sale != nil {
+		if err := sale.Validate(current.Price, now); err != nil {
+			return nil, err
+		}
+	}
+
+	product := &model.Product{ID: id, Sale: sale}
+	if err := s.productRepo.SetSale(ctx, product); err != nil {
+		if err == model.ErrProductNotFound {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("product_id", id).Msg("failed to set product sale")
+		return nil, fmt.Errorf("failed to set product sale: %w", err)
+	}
+
+	s.logger.Info().Str("product_id", id).Bool("on_sale", sale != nil).Msg("product sale set")
+
+	*product = s.withEffectivePrice(*product, now)
+	return product, nil
+}
+
+// DeleteProduct removes a product that no order refers to.
+func (s *productService) DeleteProduct(ctx context.Context, id string) error {
+	if id == "" {
+		return model.ErrProductNotFound
+	}
+
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		if err == model.ErrProductNotFound || err == model.ErrProductInUse {
+			return err
+		}
+		s.logger.Error().Err(err).Str("product_id", id).Msg("failed to delete product")
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	s.logger.Info().Str("product_id", id).Msg("product deleted")
+
+	return nil
+}
+
+// importColumns lists the CSV header columns a product import must have.
+var importColumns = []string{"id", "name", "price", "category"}
+
+// ImportProducts validates CSV rows as they stream into the repository, so
+// the file is never held in memory. Invalid rows and repeated IDs are
+// skipped and reported, as are IDs that already exist.
+func (s *productService) ImportProducts(ctx context.Context, r io.Reader) (*model.ProductImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if err == io.EOF || errors.As(err, &parseErr) {
+			return nil, model.ErrInvalidProductImport
+		}
+		return nil, fmt.Errorf("failed to read import header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range importColumns {
+		if _, ok := index[column]; !ok {
+			return nil, model.ErrInvalidProductImport
+		}
+	}
+
+	result := &model.ProductImportResult{Errors: []model.ProductImportError{}}
+	rowByID := make(map[string]int)
+	reject := func(row int, id, code, reason string) {
+		result.Errors = append(result.Errors, model.ProductImportError{Row: row, ProductID: id, Code: code, Error: reason})
+	}
+
+	next := func() (model.Product, bool, error) {
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return model.Product{}, false, nil
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) {
+					reject(parseErr.StartLine, "", model.ErrCodeInvalidProduct, parseErr.Err.Error())
+					continue
+				}
+				return model.Product{}, false, err
+			}
+
+			row, _ := reader.FieldPos(0)
+			product := model.Product{
+				ID:       strings.TrimSpace(record[index["id"]]),
+				Name:     strings.TrimSpace(record[index["name"]]),
+				Category: strings.TrimSpace(record[index["category"]]),
+				Currency: s.currency,
+			}
+
+			price, err := model.ParseMoney(strings.TrimSpace(record[index["price"]]))
+			if err != nil {
+				reject(row, product.ID, model.ErrCodeInvalidProduct, "price must be a number")
+				continue
+			}
+			product.Price = price
+
+			if err := product.Validate(); err != nil {
+				reject(row, product.ID, model.ErrCodeInvalidProduct, model.ErrInvalidProduct.Message)
+				continue
+			}
+			if first, ok := rowByID[product.ID]; ok {
+				reject(row, product.ID, model.ErrCodeProductExists, fmt.Sprintf("product ID repeats row %d", first))
+				continue
+			}
+			rowByID[product.ID] = row
+
+			return product, true, nil
+		}
+	}
+
+	imported, existing, err := s.productRepo.Import(ctx, next)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to import products")
+		return nil, fmt.Errorf("failed to import products: %w", err)
+	}
+
+	for _, id := range existing {
+		reject(rowByID[id], id, model.ErrCodeProductExists, model.ErrProductExists.Message)
+	}
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+	result.Imported = imported
+
+	s.logger.Info().
+		Int64("imported", imported).
+		Int("rejected", len(result.Errors)).
+		Msg("products imported")
+
+	return result, nil
 }
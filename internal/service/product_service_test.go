@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"mini-kart/internal/fixtures"
 	"mini-kart/internal/model"
 
 	"github.com/rs/zerolog"
@@ -19,14 +22,19 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) GetAll(ctx context.Context, limit, offset int) ([]model.Product, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockProductRepository) GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]model.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) Count(ctx context.Context, filter model.ProductFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockProductRepository) GetByID(ctx context.Context, id string) (*model.Product, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -48,77 +56,134 @@ func (m *MockProductRepository) ValidateProductsExist(ctx context.Context, ids [
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) Archive(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error) {
+	args := m.Called(ctx, ids, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductArchiveResult), args.Error(1)
+}
+
+func (m *MockProductRepository) BulkInsert(ctx context.Context, next func() (model.Product, bool, error), replace bool) (int64, error) {
+	args := m.Called(ctx, next, replace)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductRepository) Import(ctx context.Context, next func() (model.Product, bool, error)) (int64, []string, error) {
+	args := m.Called(ctx, next)
+	if args.Get(1) == nil {
+		return args.Get(0).(int64), nil, args.Error(2)
+	}
+	return args.Get(0).(int64), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *model.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *model.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) SetSale(ctx context.Context, product *model.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestProductService_GetAll(t *testing.T) {
 	logger := zerolog.Nop()
 	ctx := context.Background()
 
-	testProducts := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-		{ID: "P002", Name: "Product 2", Price: 20.00, Category: "Cat2", CreatedAt: time.Now()},
+	testProducts := fixtures.Products(2)
+
+	// byName is the filter applied when no sort is requested
+	byName := func(f model.ProductFilter) model.ProductFilter {
+		f.Sort = model.ProductSortName
+		f.Order = model.SortAsc
+		return f
 	}
 
 	tests := []struct {
-		name          string
-		limit         int
-		offset        int
-		expectedLimit int
-		mockReturn    []model.Product
-		mockError     error
-		expectError   bool
+		name           string
+		filter         model.ProductFilter
+		expectedFilter model.ProductFilter
+		mockReturn     []model.Product
+		mockError      error
+		expectedErr    error
+		expectError    bool
 	}{
 		{
-			name:          "Success with valid pagination",
-			limit:         10,
-			offset:        0,
-			expectedLimit: 10,
-			mockReturn:    testProducts,
-			mockError:     nil,
-			expectError:   false,
-		},
-		{
-			name:          "Success with zero limit defaults to 10",
-			limit:         0,
-			offset:        0,
-			expectedLimit: 10,
-			mockReturn:    testProducts,
-			mockError:     nil,
-			expectError:   false,
-		},
-		{
-			name:          "Success with negative limit defaults to 10",
-			limit:         -5,
-			offset:        0,
-			expectedLimit: 10,
-			mockReturn:    testProducts,
-			mockError:     nil,
-			expectError:   false,
-		},
-		{
-			name:          "Success with limit exceeding max caps at 100",
-			limit:         200,
-			offset:        0,
-			expectedLimit: 100,
-			mockReturn:    testProducts,
-			mockError:     nil,
-			expectError:   false,
-		},
-		{
-			name:          "Success with negative offset defaults to 0",
-			limit:         10,
-			offset:        -10,
-			expectedLimit: 10,
-			mockReturn:    testProducts,
-			mockError:     nil,
-			expectError:   false,
-		},
-		{
-			name:          "Repository error",
-			limit:         10,
-			offset:        0,
-			expectedLimit: 10,
-			mockReturn:    nil,
-			mockError:     errors.New("database error"),
-			expectError:   true,
+			name:           "Success with valid pagination",
+			filter:         model.ProductFilter{Limit: 10},
+			expectedFilter: byName(model.ProductFilter{Limit: 10}),
+			mockReturn:     testProducts,
+		},
+		{
+			name:           "Success with zero limit defaults to 10",
+			filter:         model.ProductFilter{},
+			expectedFilter: byName(model.ProductFilter{Limit: 10}),
+			mockReturn:     testProducts,
+		},
+		{
+			name:           "Success with negative limit defaults to 10",
+			filter:         model.ProductFilter{Limit: -5},
+			expectedFilter: byName(model.ProductFilter{Limit: 10}),
+			mockReturn:     testProducts,
+		},
+		{
+			name:           "Success with limit exceeding max caps at 100",
+			filter:         model.ProductFilter{Limit: 200},
+			expectedFilter: byName(model.ProductFilter{Limit: 100}),
+			mockReturn:     testProducts,
+		},
+		{
+			name:           "Success with negative offset defaults to 0",
+			filter:         model.ProductFilter{Limit: 10, Offset: -10},
+			expectedFilter: byName(model.ProductFilter{Limit: 10}),
+			mockReturn:     testProducts,
+		},
+		{
+			name:   "Category and sort passed through",
+			filter: model.ProductFilter{Category: "Cat1", Sort: model.ProductSortCreatedAt, Order: model.SortDesc, Limit: 5},
+			expectedFilter: model.ProductFilter{
+				Category: "Cat1",
+				Sort:     model.ProductSortCreatedAt,
+				Order:    model.SortDesc,
+				Limit:    5,
+			},
+			mockReturn: testProducts[:1],
+		},
+		{
+			name:           "Sort defaults to ascending",
+			filter:         model.ProductFilter{Sort: model.ProductSortPrice, Limit: 10},
+			expectedFilter: model.ProductFilter{Sort: model.ProductSortPrice, Order: model.SortAsc, Limit: 10},
+			mockReturn:     testProducts,
+		},
+		{
+			name:        "Unknown sort field",
+			filter:      model.ProductFilter{Sort: "rating", Limit: 10},
+			expectedErr: model.ErrInvalidProductSort,
+			expectError: true,
+		},
+		{
+			name:        "Unknown sort order",
+			filter:      model.ProductFilter{Order: "up", Limit: 10},
+			expectedErr: model.ErrInvalidProductSort,
+			expectError: true,
+		},
+		{
+			name:           "Repository error",
+			filter:         model.ProductFilter{Limit: 10},
+			expectedFilter: byName(model.ProductFilter{Limit: 10}),
+			mockError:      errors.New("database error"),
+			expectError:    true,
 		},
 	}
 
@@ -127,22 +192,26 @@ func TestProductService_GetAll(t *testing.T) {
 			mockRepo := new(MockProductRepository)
 			service := NewProductService(mockRepo, logger)
 
-			expectedOffset := tt.offset
-			if expectedOffset < 0 {
-				expectedOffset = 0
+			if tt.expectedErr == nil {
+				mockRepo.On("GetAll", ctx, tt.expectedFilter).
+					Return(tt.mockReturn, tt.mockError)
+			}
+			if !tt.expectError {
+				mockRepo.On("Count", ctx, tt.expectedFilter).Return(42, nil)
 			}
 
-			mockRepo.On("GetAll", ctx, tt.expectedLimit, expectedOffset).
-				Return(tt.mockReturn, tt.mockError)
-
-			products, err := service.GetAll(ctx, tt.limit, tt.offset)
+			products, page, err := service.GetAll(ctx, tt.filter)
 
 			if tt.expectError {
 				require.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.Equal(t, tt.expectedErr, err)
+				}
 				assert.Nil(t, products)
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.mockReturn, products)
+				assert.Equal(t, model.Page{Limit: tt.expectedFilter.Limit, Offset: tt.expectedFilter.Offset, Total: 42}, page)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -157,7 +226,7 @@ func TestProductService_GetByID(t *testing.T) {
 	testProduct := &model.Product{
 		ID:        "P001",
 		Name:      "Product 1",
-		Price:     10.00,
+		Price:     model.NewMoney(10.00),
 		Category:  "Cat1",
 		CreatedAt: time.Now(),
 	}
@@ -234,10 +303,7 @@ func TestProductService_GetByIDs(t *testing.T) {
 	logger := zerolog.Nop()
 	ctx := context.Background()
 
-	testProducts := []model.Product{
-		{ID: "P001", Name: "Product 1", Price: 10.00, Category: "Cat1", CreatedAt: time.Now()},
-		{ID: "P002", Name: "Product 2", Price: 20.00, Category: "Cat2", CreatedAt: time.Now()},
-	}
+	testProducts := fixtures.Products(2)
 
 	tests := []struct {
 		name        string
@@ -304,3 +370,554 @@ func TestProductService_GetByIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestProductService_ArchiveProducts(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	conflict := &model.ProductArchiveResult{
+		Archived: []string{},
+		Conflicts: []model.ProductArchiveConflict{
+			{ProductID: "P002", Reason: model.ArchiveConflictOpenOrders},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		ids           []string
+		expectedIDs   []string
+		mockResult    *model.ProductArchiveResult
+		mockError     error
+		expectArchive bool
+		expectedErr   error
+	}{
+		{
+			name:          "Archives de-duplicated IDs",
+			ids:           []string{"P001", " P001 ", "P002"},
+			expectedIDs:   []string{"P001", "P002"},
+			mockResult:    &model.ProductArchiveResult{Archived: []string{"P001", "P002"}},
+			expectArchive: true,
+		},
+		{
+			name:          "Conflicts are reported",
+			ids:           []string{"P001", "P002"},
+			expectedIDs:   []string{"P001", "P002"},
+			mockResult:    conflict,
+			expectArchive: true,
+			expectedErr:   model.ErrProductArchiveConflict,
+		},
+		{
+			name:        "No IDs",
+			ids:         []string{},
+			expectedErr: model.ErrInvalidProductIDs,
+		},
+		{
+			name:        "Blank ID",
+			ids:         []string{"P001", " "},
+			expectedErr: model.ErrInvalidProductIDs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			if tt.expectArchive {
+				mockRepo.On("Archive", ctx, tt.expectedIDs, "admin-a").Return(tt.mockResult, tt.mockError)
+			}
+
+			svc := NewProductService(mockRepo, logger)
+			result, err := svc.ArchiveProducts(ctx, tt.ids, "admin-a")
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expectArchive {
+				assert.Equal(t, tt.mockResult, result)
+			} else {
+				mockRepo.AssertNotCalled(t, "Archive", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Too many IDs", func(t *testing.T) {
+		ids := make([]string, maxArchiveBatch+1)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("P%03d", i)
+		}
+
+		svc := NewProductService(new(MockProductRepository), logger)
+		_, err := svc.ArchiveProducts(ctx, ids, "admin-a")
+
+		assert.Equal(t, model.ErrInvalidProductIDs, err)
+	})
+}
+
+func TestProductService_Compare(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	oats := model.Product{ID: "P001", Name: "Oats", Price: model.NewMoney(4.5), Category: "Cereal", Metadata: map[string]any{
+		"Weight":    "500g",
+		"organic":   true,
+		"Nutrition": map[string]any{"Protein": 13.0, "fibre": 10.0},
+	}}
+	muesli := model.Product{ID: "P002", Name: "Muesli", Price: model.NewMoney(6.0), Category: "Cereal", Metadata: map[string]any{
+		"weight":    "750g",
+		"organic":   true,
+		"nutrition": map[string]any{"protein": 9.0},
+	}}
+
+	t.Run("Builds the attribute matrix in request order", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		// The repository returns products by name
+		mockRepo.On("GetByIDs", ctx, []string{"P002", "P001"}).Return([]model.Product{muesli, oats}, nil)
+
+		svc := NewProductService(mockRepo, logger)
+		comparison, err := svc.Compare(ctx, []string{"P002", " P001", "P002"})
+
+		require.NoError(t, err)
+		require.Len(t, comparison.Products, 2)
+		assert.Equal(t, "P002", comparison.Products[0].ID)
+		assert.Equal(t, "P001", comparison.Products[1].ID)
+		assert.Equal(t, []model.ComparisonAttribute{
+			{Name: "category", Values: []any{"Cereal", "Cereal"}},
+			{Name: "price", Values: []any{model.NewMoney(6), model.NewMoney(4.5)}, Differs: true},
+			{Name: "nutrition.fibre", Values: []any{nil, 10.0}, Differs: true},
+			{Name: "nutrition.protein", Values: []any{9.0, 13.0}, Differs: true},
+			{Name: "organic", Values: []any{true, true}},
+			{Name: "weight", Values: []any{"750g", "500g"}, Differs: true},
+		}, comparison.Attributes)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown product", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("GetByIDs", ctx, []string{"P001", "P999"}).Return([]model.Product{oats}, nil)
+
+		svc := NewProductService(mockRepo, logger)
+		_, err := svc.Compare(ctx, []string{"P001", "P999"})
+
+		assert.Equal(t, model.ErrProductNotFound, err)
+	})
+
+	invalid := map[string][]string{
+		"No IDs":      nil,
+		"One product": {"P001", "P001"},
+		"Blank ID":    {"P001", ""},
+		"Too many":    {"P001", "P002", "P003", "P004", "P005"},
+	}
+	for name, ids := range invalid {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+
+			svc := NewProductService(mockRepo, logger)
+			_, err := svc.Compare(ctx, ids)
+
+			assert.Equal(t, model.ErrInvalidComparison, err)
+			mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductService_CreateProduct(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	price := model.NewMoney(12.5)
+	negative := model.NewMoney(-1)
+
+	tests := []struct {
+		name         string
+		req          *model.ProductRequest
+		mockError    error
+		expectCreate bool
+		expectedErr  error
+	}{
+		{
+			name:         "Creates trimmed product",
+			req:          &model.ProductRequest{ID: " P100 ", Name: " Waffle ", Price: &price, Category: "Waffle"},
+			expectCreate: true,
+		},
+		{
+			name:         "Duplicate ID",
+			req:          &model.ProductRequest{ID: "P001", Name: "Waffle", Price: &price, Category: "Waffle"},
+			mockError:    model.ErrProductExists,
+			expectCreate: true,
+			expectedErr:  model.ErrProductExists,
+		},
+		{
+			name:        "Missing price",
+			req:         &model.ProductRequest{ID: "P100", Name: "Waffle", Category: "Waffle"},
+			expectedErr: model.ErrInvalidProduct,
+		},
+		{
+			name:        "Negative price",
+			req:         &model.ProductRequest{ID: "P100", Name: "Waffle", Price: &negative, Category: "Waffle"},
+			expectedErr: model.ErrInvalidProduct,
+		},
+		{
+			name:        "Blank name",
+			req:         &model.ProductRequest{ID: "P100", Name: " ", Price: &price, Category: "Waffle"},
+			expectedErr: model.ErrInvalidProduct,
+		},
+		{
+			name:        "ID with slash",
+			req:         &model.ProductRequest{ID: "P1/00", Name: "Waffle", Price: &price, Category: "Waffle"},
+			expectedErr: model.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			if tt.expectCreate {
+				mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Product")).Return(tt.mockError)
+			}
+
+			svc := NewProductService(mockRepo, logger)
+			product, err := svc.CreateProduct(ctx, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, product)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "P100", product.ID)
+				assert.Equal(t, "Waffle", product.Name)
+				assert.Equal(t, price, product.Price)
+			}
+			if !tt.expectCreate {
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_CatalogueCurrency(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	price := model.NewMoney(12.5)
+
+	t.Run("Creates product in catalogue currency", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(p *model.Product) bool {
+			return p.Currency == "AUD"
+		})).Return(nil)
+
+		svc := NewProductService(mockRepo, logger, WithCatalogueCurrency("aud"))
+		product, err := svc.CreateProduct(ctx, &model.ProductRequest{ID: "P100", Name: "Waffle", Price: &price, Category: "Waffle", Currency: "aud"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "AUD", product.Currency)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects other currencies", func(t *testing.T) {
+		for _, currency := range []string{"USD", "dollars"} {
+			mockRepo := new(MockProductRepository)
+
+			svc := NewProductService(mockRepo, logger, WithCatalogueCurrency("AUD"))
+			product, err := svc.CreateProduct(ctx, &model.ProductRequest{ID: "P100", Name: "Waffle", Price: &price, Category: "Waffle", Currency: currency})
+
+			assert.Equal(t, model.ErrUnsupportedCurrency, err)
+			assert.Nil(t, product)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Fills in currency of stored products", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("GetByID", ctx, "P001").Return(&model.Product{ID: "P001", Name: "Waffle", Price: price, Category: "Waffle"}, nil)
+
+		svc := NewProductService(mockRepo, logger, WithCatalogueCurrency("AUD"))
+		product, err := svc.GetByID(ctx, "P001")
+
+		require.NoError(t, err)
+		assert.Equal(t, "AUD", product.Currency)
+	})
+}
+
+func TestProductService_Images(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	price := model.NewMoney(12.5)
+
+	mockRepo := new(MockProductRepository)
+	mockRepo.On("GetByID", ctx, "P001").Return(&model.Product{ID: "P001", Name: "Waffle", Price: price, Category: "Waffle"}, nil)
+	images := new(MockProductImageRepository)
+	images.On("ListByProducts", ctx, []string{"P001"}).Return(map[string][]model.ProductImage{
+		"P001": {{Key: "P001/front.jpg", AltText: "Front"}, {Key: "P001/back.jpg", Position: 1}},
+	}, nil)
+
+	svc := NewProductService(mockRepo, logger, WithProductImages(images, new(MockImageStore)))
+	product, err := svc.GetByID(ctx, "P001")
+
+	require.NoError(t, err)
+	require.Len(t, product.Images, 2)
+	assert.Equal(t, "https://cdn.example.com/P001/front.jpg", product.Images[0].URL)
+	assert.Equal(t, []model.PublicImage{
+		{URL: "https://cdn.example.com/P001/front.jpg", AltText: "Front"},
+		{URL: "https://cdn.example.com/P001/back.jpg"},
+	}, product.Public().Images)
+}
+
+func TestProductService_UpdateProduct(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	current := &model.Product{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle"}
+	samePrice := model.NewMoney(6.5)
+	newPrice := model.NewMoney(7)
+
+	tests := []struct {
+		name         string
+		req          *model.ProductRequest
+		expectUpdate bool
+		expectedErr  error
+	}{
+		{
+			name:         "Updates name and category",
+			req:          &model.ProductRequest{Name: "Belgian Waffle", Category: "Dessert"},
+			expectUpdate: true,
+		},
+		{
+			name:         "Unchanged price is accepted",
+			req:          &model.ProductRequest{Name: "Belgian Waffle", Price: &samePrice, Category: "Dessert"},
+			expectUpdate: true,
+		},
+		{
+			name:        "Price change is rejected",
+			req:         &model.ProductRequest{Name: "Belgian Waffle", Price: &newPrice, Category: "Dessert"},
+			expectedErr: model.ErrPriceUpdateNotAllowed,
+		},
+		{
+			name:        "Blank category",
+			req:         &model.ProductRequest{Name: "Belgian Waffle"},
+			expectedErr: model.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			mockRepo.On("GetByID", ctx, "P001").Return(current, nil)
+			if tt.expectUpdate {
+				mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Product")).Return(nil)
+			}
+
+			svc := NewProductService(mockRepo, logger)
+			product, err := svc.UpdateProduct(ctx, "P001", tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "Belgian Waffle", product.Name)
+				assert.Equal(t, "Dessert", product.Category)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Product not found", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("GetByID", ctx, "P999").Return(nil, nil)
+
+		svc := NewProductService(mockRepo, logger)
+		_, err := svc.UpdateProduct(ctx, "P999", &model.ProductRequest{Name: "A", Category: "B"})
+
+		assert.Equal(t, model.ErrProductNotFound, err)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductService_SetSale(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	ended := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		sale           *model.ProductSale
+		expectSet      bool
+		expectedErr    error
+		expectedPrice  float64
+		expectedOnSale bool
+	}{
+		{
+			name:           "Schedules sale",
+			sale:           &model.ProductSale{Price: model.NewMoney(5)},
+			expectSet:      true,
+			expectedPrice:  5,
+			expectedOnSale: true,
+		},
+		{
+			name:          "Removes sale",
+			expectSet:     true,
+			expectedPrice: 6.5,
+		},
+		{
+			name:        "Sale not below regular price",
+			sale:        &model.ProductSale{Price: model.NewMoney(6.5)},
+			expectedErr: model.ErrInvalidSale,
+		},
+		{
+			name:        "Sale already ended",
+			sale:        &model.ProductSale{Price: model.NewMoney(5), EndsAt: &ended},
+			expectedErr: model.ErrInvalidSale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			mockRepo.On("GetByID", ctx, "P001").Return(&model.Product{ID: "P001", Name: "Waffle", Price: model.NewMoney(6.5), Category: "Waffle"}, nil)
+			if tt.expectSet {
+				mockRepo.On("SetSale", ctx, mock.AnythingOfType("*model.Product")).
+					Run(func(args mock.Arguments) {
+						product := args.Get(1).(*model.Product)
+						product.Name, product.Price, product.Category = "Waffle", model.NewMoney(6.5), "Waffle"
+					}).
+					Return(nil)
+			}
+
+			svc := NewProductService(mockRepo, logger)
+			product, err := svc.SetSale(ctx, "P001", tt.sale)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, product)
+				mockRepo.AssertNotCalled(t, "SetSale", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.sale, product.Sale)
+				assert.Equal(t, model.NewMoney(tt.expectedPrice), product.EffectivePrice)
+				assert.Equal(t, tt.expectedOnSale, product.OnSale)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Product not found", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("GetByID", ctx, "P999").Return(nil, nil)
+
+		svc := NewProductService(mockRepo, logger)
+		_, err := svc.SetSale(ctx, "P999", &model.ProductSale{Price: model.NewMoney(5)})
+
+		assert.Equal(t, model.ErrProductNotFound, err)
+	})
+}
+
+func TestProductService_DeleteProduct(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		mockError   error
+		expectedErr error
+	}{
+		{name: "Deletes product"},
+		{name: "Referenced by orders", mockError: model.ErrProductInUse, expectedErr: model.ErrProductInUse},
+		{name: "Not found", mockError: model.ErrProductNotFound, expectedErr: model.ErrProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			mockRepo.On("Delete", ctx, "P001").Return(tt.mockError)
+
+			svc := NewProductService(mockRepo, logger)
+			err := svc.DeleteProduct(ctx, "P001")
+
+			assert.Equal(t, tt.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_ImportProducts(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	// drain consumes the import iterator the way the repository does
+	var imported []model.Product
+	drain := func(args mock.Arguments) {
+		imported = nil
+		next := args.Get(1).(func() (model.Product, bool, error))
+		for {
+			product, ok, err := next()
+			require.NoError(t, err)
+			if !ok {
+				return
+			}
+			imported = append(imported, product)
+		}
+	}
+
+	t.Run("Imports valid rows and reports the rest", func(t *testing.T) {
+		input := "Name,ID,Price,Category\n" +
+			"Chicken Waffle,P100,6.50,Waffle\n" +
+			"Lemon Tart,P101,cheap,Tart\n" +
+			"Berry Tart,P102,-1,Tart\n" +
+			"Plain Waffle,P100,4.00,Waffle\n" +
+			"Brownie,P001,3.00,Cake\n" +
+			"Too,Many,Fields,In,Row\n" +
+			" Muffin ,P103, 2.75 ,Cake\n"
+
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("Import", ctx, mock.Anything).Run(drain).Return(int64(2), []string{"P001"}, nil)
+
+		svc := NewProductService(mockRepo, logger)
+		result, err := svc.ImportProducts(ctx, strings.NewReader(input))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Imported)
+		assert.Equal(t, []model.Product{
+			{ID: "P100", Name: "Chicken Waffle", Price: model.NewMoney(6.5), Category: "Waffle"},
+			{ID: "P001", Name: "Brownie", Price: model.NewMoney(3), Category: "Cake"},
+			{ID: "P103", Name: "Muffin", Price: model.NewMoney(2.75), Category: "Cake"},
+		}, imported)
+		assert.Equal(t, []model.ProductImportError{
+			{Row: 3, ProductID: "P101", Code: model.ErrCodeInvalidProduct, Error: "price must be a number"},
+			{Row: 4, ProductID: "P102", Code: model.ErrCodeInvalidProduct, Error: model.ErrInvalidProduct.Message},
+			{Row: 5, ProductID: "P100", Code: model.ErrCodeProductExists, Error: "product ID repeats row 2"},
+			{Row: 6, ProductID: "P001", Code: model.ErrCodeProductExists, Error: model.ErrProductExists.Message},
+			{Row: 7, Code: model.ErrCodeInvalidProduct, Error: "wrong number of fields"},
+		}, result.Errors)
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+
+		svc := NewProductService(mockRepo, logger)
+		_, err := svc.ImportProducts(ctx, strings.NewReader(""))
+
+		assert.Equal(t, model.ErrInvalidProductImport, err)
+		mockRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Header missing a column", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+
+		svc := NewProductService(mockRepo, logger)
+		_, err := svc.ImportProducts(ctx, strings.NewReader("id,name,price\nP100,Waffle,6.50\n"))
+
+		assert.Equal(t, model.ErrInvalidProductImport, err)
+		mockRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		mockRepo.On("Import", ctx, mock.Anything).Return(int64(0), nil, errors.New("connection reset"))
+
+		svc := NewProductService(mockRepo, logger)
+		result, err := svc.ImportProducts(ctx, strings.NewReader("id,name,price,category\n"))
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
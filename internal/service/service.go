@@ -2,22 +2,59 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"mini-kart/internal/model"
 
 	"github.com/google/uuid"
 )
 
-// ProductService defines operations for product management.
+// ProductService defines operations for product management. Products are
+// returned with the price customers pay at the time of the call.
 type ProductService interface {
-	// GetAll retrieves all products with pagination.
-	GetAll(ctx context.Context, limit, offset int) ([]model.Product, error)
+	// GetAll retrieves products with optional category filtering, sorting
+	// and pagination. The returned page holds the applied limit and offset
+	// and the total number of matching products.
+	GetAll(ctx context.Context, filter model.ProductFilter) ([]model.Product, model.Page, error)
 
 	// GetByID retrieves a single product by ID.
 	GetByID(ctx context.Context, id string) (*model.Product, error)
 
 	// GetByIDs retrieves multiple products by their IDs.
 	GetByIDs(ctx context.Context, ids []string) ([]model.Product, error)
+
+	// ArchiveProducts hides products from the catalogue. Products are archived
+	// all-or-nothing; if any cannot be archived, the result lists the conflicts
+	// per ID and model.ErrProductArchiveConflict is returned.
+	ArchiveProducts(ctx context.Context, ids []string, actor string) (*model.ProductArchiveResult, error)
+
+	// Compare sets between 2 and 4 products side by side, with one row per
+	// attribute in their metadata. Returns model.ErrInvalidComparison for too
+	// few or too many IDs and model.ErrProductNotFound if any is unknown.
+	Compare(ctx context.Context, ids []string) (*model.ProductComparison, error)
+
+	// CreateProduct validates and adds a new product to the catalogue.
+	CreateProduct(ctx context.Context, req *model.ProductRequest) (*model.Product, error)
+
+	// UpdateProduct changes a product's name and category. A price that differs
+	// from the current price is rejected with model.ErrPriceUpdateNotAllowed.
+	UpdateProduct(ctx context.Context, id string, req *model.ProductRequest) (*model.Product, error)
+
+	// SetSale schedules a time-boxed sale price on a product, replacing any
+	// sale it has, or removes its sale when sale is nil. Returns
+	// model.ErrInvalidSale if the sale is not below the regular price or has
+	// already ended.
+	SetSale(ctx context.Context, id string, sale *model.ProductSale) (*model.Product, error)
+
+	// DeleteProduct removes a product that no order refers to.
+	DeleteProduct(ctx context.Context, id string) error
+
+	// ImportProducts streams products from a CSV file with an
+	// id,name,price,category header. Valid rows with new IDs are imported;
+	// other rows are reported per row. Returns model.ErrInvalidProductImport
+	// if the header is missing a column.
+	ImportProducts(ctx context.Context, r io.Reader) (*model.ProductImportResult, error)
 }
 
 // OrderService defines operations for order management.
@@ -27,4 +64,306 @@ type OrderService interface {
 
 	// GetByID retrieves an order by its ID with all items and product details.
 	GetByID(ctx context.Context, id uuid.UUID) (*model.OrderResponse, error)
+
+	// GetByRef retrieves the order created with an Idempotency-Key, or
+	// imported with a legacy reference, of ref, so clients that crashed
+	// before reading the response can recover their order. Returns nil if no
+	// order has the reference.
+	GetByRef(ctx context.Context, ref string) (*model.OrderResponse, error)
+
+	// List retrieves orders with optional source and product filtering and
+	// pagination. The returned page holds the applied limit and offset and
+	// the total number of matching orders.
+	List(ctx context.Context, filter model.OrderFilter) ([]model.Order, model.Page, error)
+
+	// CountBySource returns order counts per source channel for reporting.
+	CountBySource(ctx context.Context) ([]model.SourceCount, error)
+
+	// UpdateStatus moves an order to a new status, enforcing the order
+	// status lifecycle. A non-nil delegation is recorded in the audit log.
+	UpdateStatus(ctx context.Context, id uuid.UUID, status model.OrderStatus, delegation *model.Delegation) (*model.Order, error)
+
+	// OverrideStatus moves an order to any status, bypassing the order
+	// status lifecycle, and records the admin and their reason in the audit
+	// log. Returns model.ErrInvalidStatusOverride without a valid status
+	// and a reason.
+	OverrideStatus(ctx context.Context, id uuid.UUID, override *model.StatusOverride, admin string) (*model.Order, error)
+}
+
+// OrderImportService defines the migration of historical orders from
+// another platform.
+type OrderImportService interface {
+	// ImportOrders streams orders from a CSV or JSON Lines file and stores
+	// each with its original timestamp, status and prices. Coupon codes are
+	// recorded without being validated or redeemed, and no webhooks or
+	// events are sent. Orders already imported or with errors are skipped
+	// and reported. Returns model.ErrInvalidOrderImport if a CSV file lacks
+	// the required header columns.
+	ImportOrders(ctx context.Context, r io.Reader, format model.OrderImportFormat) (*model.OrderImportResult, error)
+}
+
+// OperationService defines asynchronous order creation, for clients that
+// would rather poll for the outcome than hold a connection open while a
+// slow order is created.
+type OperationService interface {
+	// SubmitOrder accepts an order for creation in the background and
+	// returns the pending operation tracking it.
+	SubmitOrder(ctx context.Context, req *model.OrderRequest) (*model.Operation, error)
+
+	// GetOperation retrieves an operation by its ID. Returns nil if the
+	// operation does not exist.
+	GetOperation(ctx context.Context, id uuid.UUID) (*model.Operation, error)
+
+	// Close waits for orders still being created, until ctx is done.
+	Close(ctx context.Context) error
+}
+
+// PriceChangeService defines operations for product price changes.
+type PriceChangeService interface {
+	// RequestPriceChange changes a product's price. Changes within the approval
+	// threshold are applied immediately; larger changes stay pending until
+	// approved by a different admin.
+	RequestPriceChange(ctx context.Context, productID string, newPrice model.Money, requestedBy string) (*model.PriceChange, error)
+
+	// RequestSale schedules a sale on a product. Sales discounting the price
+	// by no more than the approval threshold are applied immediately; deeper
+	// ones stay pending until approved by a different admin.
+	RequestSale(ctx context.Context, productID string, sale model.ProductSale, requestedBy string) (*model.PriceChange, error)
+
+	// ListPending retrieves price changes awaiting approval.
+	ListPending(ctx context.Context) ([]model.PriceChange, error)
+
+	// Approve applies a pending price change. The approver must differ from the requester.
+	Approve(ctx context.Context, id uuid.UUID, approvedBy string) (*model.PriceChange, error)
+
+	// Reject discards a pending price change.
+	Reject(ctx context.Context, id uuid.UUID, rejectedBy string) (*model.PriceChange, error)
+}
+
+// PricingService defines operations for pricing carts without creating orders.
+type PricingService interface {
+	// Preview computes the full price breakdown for a cart-like request.
+	Preview(ctx context.Context, req *model.PricingRequest) (*model.PriceBreakdown, error)
+
+	// PreviewProductCoupon reports whether a coupon code could apply to a
+	// product and the discounted price of one unit.
+	PreviewProductCoupon(ctx context.Context, product *model.Product, code string) (*model.ProductCouponPreview, error)
+
+	// ValidateCoupon checks a promo code on its own, before checkout, and
+	// reports whether it is valid and why not.
+	ValidateCoupon(ctx context.Context, code string) (*model.CouponValidation, error)
+}
+
+// CartService signs anonymous storefront carts into tokens the client keeps,
+// so simple carts need no server-side storage.
+type CartService interface {
+	// SignCart prices a cart and signs it into a token for the context's
+	// tenant. Returns model.ErrInvalidCart for malformed carts and the
+	// pricing errors of PricingService.Preview.
+	SignCart(ctx context.Context, req *model.CartRequest) (*model.SignedCart, error)
+
+	// GetCart restores the cart carried by a token, priced at current
+	// catalogue prices.
+	GetCart(ctx context.Context, token string) (*model.SignedCart, error)
+
+	// OpenCart verifies a token and returns its cart without pricing it.
+	// Returns model.ErrInvalidCartToken for tokens that were tampered with
+	// or signed for another tenant, and model.ErrCartExpired once expired.
+	OpenCart(ctx context.Context, token string) (*model.Cart, error)
+}
+
+// CurrencyService converts catalogue prices into other currencies.
+type CurrencyService interface {
+	// Rate returns how much of the ISO 4217 currency code one unit of the
+	// catalogue currency buys. Returns model.ErrUnsupportedCurrency for
+	// currencies without a rate, and model.ErrRatesUnavailable when no
+	// rates could be fetched.
+	Rate(ctx context.Context, code string) (float64, error)
+}
+
+// ShipmentService defines operations for fulfilling orders across shipments.
+type ShipmentService interface {
+	// CreateShipment ships some or all of an order's unfulfilled item quantities.
+	// An order may be fulfilled across any number of shipments.
+	CreateShipment(ctx context.Context, orderID uuid.UUID, req *model.ShipmentRequest) (*model.Shipment, error)
+
+	// ShipRemaining ships every unfulfilled item quantity of an order in one
+	// shipment. Returns model.ErrOrderShipped if nothing is left to ship.
+	ShipRemaining(ctx context.Context, orderID uuid.UUID, req *model.ShipRemainingRequest) (*model.Shipment, error)
+
+	// UpdateTracking attaches a tracking number to one of an order's
+	// shipments, e.g. once the carrier has collected it.
+	UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, req *model.TrackingRequest) (*model.Shipment, error)
+
+	// ListShipments retrieves an order's shipments, oldest first.
+	ListShipments(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error)
+
+	// ListEvents retrieves an order's fulfillment events, oldest first.
+	ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error)
+}
+
+// OrderPricingService defines operations for repricing orders after they are placed.
+type OrderPricingService interface {
+	// Recalculate re-prices an order's current items against the current
+	// catalogue and coupon rules, for orders edited before fulfillment. A
+	// result that differs from the order's current pricing is recorded as a
+	// new pricing version, recalculated by actor, and becomes the order's totals.
+	Recalculate(ctx context.Context, orderID uuid.UUID, actor string) (*model.OrderRepricing, error)
+}
+
+// TimelineService defines the interface for support notes and order history.
+type TimelineService interface {
+	// AddNote records an internal note on an order by the given author.
+	AddNote(ctx context.Context, orderID uuid.UUID, author string, req *model.OrderNoteRequest) (*model.OrderNote, error)
+
+	// Timeline retrieves an order's history, merging its creation, notes,
+	// status changes, fulfillment events and webhook deliveries, oldest first.
+	Timeline(ctx context.Context, orderID uuid.UUID) ([]model.TimelineEntry, error)
+}
+
+// SnapshotService defines signed order snapshots, kept as evidence for
+// disputes and chargebacks.
+type SnapshotService interface {
+	// CreateSnapshot captures an order with its shipments, pricing versions,
+	// audit entries and timeline, taken by actor. The snapshot is signed and,
+	// when an archive is configured, stored so it cannot be altered. Returns
+	// model.ErrOrderNotFound if the order does not exist.
+	CreateSnapshot(ctx context.Context, orderID uuid.UUID, actor string) (*model.SignedSnapshot, error)
+}
+
+// SLAService defines order lifecycle SLA tracking, so stuck orders are
+// caught before customers complain.
+type SLAService interface {
+	// AtRisk lists up to limit orders that have been in a tracked status for
+	// at least the warning share of its SLA, longest waiting first. An empty
+	// status lists every tracked status.
+	AtRisk(ctx context.Context, status model.OrderStatus, limit int) ([]model.AtRiskOrder, error)
+
+	// CheckBreaches counts and alerts on orders that have newly breached
+	// their status's SLA, returning how many were found. Each breach is
+	// reported once.
+	CheckBreaches(ctx context.Context) (int, error)
+}
+
+// TenantService defines management of the storefront brands hosted on the
+// deployment.
+type TenantService interface {
+	// ListTenants retrieves every tenant, ordered by ID.
+	ListTenants(ctx context.Context) ([]model.Tenant, error)
+
+	// GetTenant retrieves a tenant. Returns model.ErrTenantNotFound if the
+	// tenant does not exist.
+	GetTenant(ctx context.Context, id string) (*model.Tenant, error)
+
+	// CreateTenant creates a tenant. Returns model.ErrInvalidTenant for a
+	// malformed request and model.ErrTenantExists if the ID is taken.
+	CreateTenant(ctx context.Context, req *model.TenantRequest) (*model.Tenant, error)
+
+	// RenameTenant changes a tenant's name. Returns model.ErrTenantNotFound
+	// if the tenant does not exist.
+	RenameTenant(ctx context.Context, id, name string) (*model.Tenant, error)
+}
+
+// CustomerService defines customer registration and order history.
+type CustomerService interface {
+	// RegisterCustomer registers a customer with the context's tenant.
+	// Returns model.ErrInvalidCustomer for a malformed request and
+	// model.ErrCustomerExists if the email is already registered.
+	RegisterCustomer(ctx context.Context, req *model.CustomerRequest) (*model.Customer, error)
+
+	// GetCustomer retrieves a customer. Returns model.ErrCustomerNotFound if
+	// the customer does not exist.
+	GetCustomer(ctx context.Context, id uuid.UUID) (*model.Customer, error)
+
+	// FindCustomer retrieves the customer registered with an email address,
+	// in any case. Returns model.ErrCustomerNotFound if there is none.
+	FindCustomer(ctx context.Context, email string) (*model.Customer, error)
+
+	// ListOrders retrieves a page of the customer's orders, newest first,
+	// ignoring the filter's customer. Returns model.ErrCustomerNotFound if
+	// the customer does not exist.
+	ListOrders(ctx context.Context, id uuid.UUID, filter model.OrderFilter) ([]model.Order, model.Page, error)
+}
+
+// CouponFileService defines the coupon file registry, which traces the codes
+// the validator accepted at any time back to the uploaded files they came
+// from.
+type CouponFileService interface {
+	// RegisterFile records an uploaded coupon file on behalf of the admin
+	// registering it. Returns model.ErrInvalidCouponFile for a malformed
+	// request and model.ErrCouponFileExists if it is already registered.
+	RegisterFile(ctx context.Context, req *model.CouponFileRequest, registeredBy string) (*model.CouponFile, error)
+
+	// ListFiles retrieves registered coupon files, newest upload first,
+	// limited to the named coupon set unless name is empty.
+	ListFiles(ctx context.Context, name string) ([]model.CouponFile, error)
+
+	// Generations retrieves the generations of coupon files serving
+	// validations at any time from from to to, inclusive, with the uploads
+	// of their files.
+	Generations(ctx context.Context, from, to time.Time) ([]model.CouponGeneration, error)
+
+	// ValidityAt checks whether a promo code was valid at the given time,
+	// against archived copies of the files each generation serving
+	// validations then had loaded.
+	ValidityAt(ctx context.Context, promoCode string, at time.Time) (*model.CouponValidityAt, error)
+}
+
+// ProductImageService defines product image management. Image files are
+// uploaded straight to storage with presigned URLs and then attached to
+// their product.
+type ProductImageService interface {
+	// CreateUpload presigns an upload of an image for a product. Returns
+	// model.ErrInvalidImage for a type products cannot show or a file over
+	// the size limit and model.ErrProductNotFound if the product does not exist.
+	CreateUpload(ctx context.Context, productID string, req *model.ImageUploadRequest) (*model.ImageUpload, error)
+
+	// AttachImage attaches an uploaded image to its product after the
+	// product's other images, on behalf of the admin attaching it. Returns
+	// model.ErrImageNotUploaded unless a presigned upload for the product
+	// stored an allowed image under the key.
+	AttachImage(ctx context.Context, productID string, req *model.ProductImageRequest, actor string) (*model.ProductImage, error)
+
+	// ListImages retrieves a product's images in position order.
+	ListImages(ctx context.Context, productID string) ([]model.ProductImage, error)
+
+	// ReorderImages sets the order of a product's images. Returns
+	// model.ErrInvalidImageOrder unless ids lists each image exactly once.
+	ReorderImages(ctx context.Context, productID string, ids []uuid.UUID) ([]model.ProductImage, error)
+
+	// DeleteImage detaches an image from its product and removes its file.
+	// Returns model.ErrImageNotFound if the product has no image with the ID.
+	DeleteImage(ctx context.Context, productID string, id uuid.UUID) error
+}
+
+// FlashSaleService defines management of the quantities of advertised
+// products allocated to flash sales.
+type FlashSaleService interface {
+	// ListFlashSales retrieves every flash sale, ordered by product ID.
+	ListFlashSales(ctx context.Context) ([]model.FlashSale, error)
+
+	// SetFlashSale allocates a quantity of a product to its flash sale, on
+	// behalf of the admin setting it. Returns model.ErrInvalidFlashSale for a
+	// malformed request and model.ErrProductNotFound if the product does not
+	// exist.
+	SetFlashSale(ctx context.Context, productID string, req *model.FlashSaleRequest, actor string) (*model.FlashSale, error)
+
+	// EndFlashSale ends a product's flash sale, on behalf of the admin ending
+	// it. Returns model.ErrFlashSaleNotFound if the product is not on one.
+	EndFlashSale(ctx context.Context, productID, actor string) error
+
+	// Reconcile counts up to limit reservations taken on Redis counters as
+	// sold in the database, and returns how many it counted.
+	Reconcile(ctx context.Context, limit int) (int, error)
+}
+
+// UpsellService defines the products recommended after an order, such as in
+// the post-purchase email.
+type UpsellService interface {
+	// Upsell recommends up to limit best sellers from the order's categories
+	// that the order does not contain, most units sold first. A limit of zero
+	// uses the configured default and larger limits are capped at the
+	// configured maximum. Returns model.ErrOrderNotFound if the order does
+	// not exist.
+	Upsell(ctx context.Context, orderID uuid.UUID, limit int) ([]model.UpsellProduct, error)
 }
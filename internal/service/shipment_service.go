@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// shipmentService implements ShipmentService.
+type shipmentService struct {
+	orderRepo    repository.OrderRepository
+	shipmentRepo repository.ShipmentRepository
+	logger       zerolog.Logger
+}
+
+// NewShipmentService creates a new shipment service.
+func NewShipmentService(
+	orderRepo repository.OrderRepository,
+	shipmentRepo repository.ShipmentRepository,
+	logger zerolog.Logger,
+) ShipmentService {
+	return &shipmentService{
+		orderRepo:    orderRepo,
+		shipmentRepo: shipmentRepo,
+		logger:       logger.With().Str("service", "shipment").Logger(),
+	}
+}
+
+// CreateShipment validates and records a shipment against an order.
+func (s *shipmentService) CreateShipment(ctx context.Context, orderID uuid.UUID, req *model.ShipmentRequest) (*model.Shipment, error) {
+	if err := validateShipmentRequest(req); err != nil {
+		return nil, err
+	}
+
+	order, items, err := s.order(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == model.OrderStatusCancelled {
+		return nil, model.ErrOrderCancelled
+	}
+
+	// Check quantities up front for a clear error; the repository enforces
+	// the same limits under row locks for concurrent shipments.
+	itemsByID := make(map[uuid.UUID]model.OrderItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+	for _, shipped := range req.Items {
+		item, ok := itemsByID[shipped.OrderItemID]
+		if !ok {
+			return nil, model.ErrOrderItemNotFound
+		}
+		if item.FulfilledQuantity+shipped.Quantity > item.Quantity {
+			s.logger.Warn().
+				Str("order_id", orderID.String()).
+				Str("order_item_id", item.ID.String()).
+				Int("quantity", shipped.Quantity).
+				Int("remaining", item.Quantity-item.FulfilledQuantity).
+				Msg("shipment exceeds unfulfilled quantity")
+			return nil, model.ErrOverFulfillment
+		}
+	}
+
+	shipment := &model.Shipment{
+		ID:             uuid.New(),
+		OrderID:        orderID,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		Items:          req.Items,
+	}
+
+	event, err := s.shipmentRepo.Create(ctx, shipment)
+	if err != nil {
+		if err == model.ErrOverFulfillment || err == model.ErrOrderItemNotFound {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to create shipment")
+		return nil, fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	s.logger.Info().
+		Str("order_id", orderID.String()).
+		Str("shipment_id", shipment.ID.String()).
+		Int("item_count", len(shipment.Items)).
+		Str("fulfillment_status", string(event.FulfillmentStatus)).
+		Msg("shipment created")
+
+	return shipment, nil
+}
+
+// ShipRemaining ships whatever is left of every order item.
+func (s *shipmentService) ShipRemaining(ctx context.Context, orderID uuid.UUID, req *model.ShipRemainingRequest) (*model.Shipment, error) {
+	if req == nil {
+		req = &model.ShipRemainingRequest{}
+	}
+
+	order, items, err := s.order(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == model.OrderStatusCancelled {
+		return nil, model.ErrOrderCancelled
+	}
+
+	shipment := &model.ShipmentRequest{Carrier: req.Carrier, TrackingNumber: req.TrackingNumber}
+	for _, item := range items {
+		if remaining := item.Quantity - item.FulfilledQuantity; remaining > 0 {
+			shipment.Items = append(shipment.Items, model.ShipmentItem{OrderItemID: item.ID, Quantity: remaining})
+		}
+	}
+	if len(shipment.Items) == 0 {
+		return nil, model.ErrOrderShipped
+	}
+
+	return s.CreateShipment(ctx, orderID, shipment)
+}
+
+// UpdateTracking validates and records a shipment's tracking number.
+func (s *shipmentService) UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, req *model.TrackingRequest) (*model.Shipment, error) {
+	if req == nil {
+		return nil, model.ErrInvalidTracking
+	}
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := s.order(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.shipmentRepo.UpdateTracking(ctx, orderID, shipmentID, *req); err != nil {
+		if err == model.ErrShipmentNotFound {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("shipment_id", shipmentID.String()).Msg("failed to update shipment tracking")
+		return nil, fmt.Errorf("failed to update shipment tracking: %w", err)
+	}
+
+	s.logger.Info().
+		Str("order_id", orderID.String()).
+		Str("shipment_id", shipmentID.String()).
+		Msg("shipment tracking updated")
+
+	shipments, err := s.ListShipments(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shipments {
+		if shipments[i].ID == shipmentID {
+			return &shipments[i], nil
+		}
+	}
+	return nil, model.ErrShipmentNotFound
+}
+
+// ListShipments retrieves an order's shipments.
+func (s *shipmentService) ListShipments(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error) {
+	if _, _, err := s.order(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.shipmentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list shipments")
+		return nil, fmt.Errorf("failed to list shipments: %w", err)
+	}
+
+	return shipments, nil
+}
+
+// ListEvents retrieves an order's fulfillment events.
+func (s *shipmentService) ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error) {
+	if _, _, err := s.order(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.shipmentRepo.ListEvents(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order events")
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+
+	return events, nil
+}
+
+// order loads an order and its items, returning model.ErrOrderNotFound if the order does not exist.
+func (s *shipmentService) order(ctx context.Context, orderID uuid.UUID) (*model.Order, []model.OrderItem, error) {
+	order, items, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to get order")
+		return nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, nil, model.ErrOrderNotFound
+	}
+	return order, items, nil
+}
+
+// validateShipmentRequest checks that a shipment lists each order item once with a positive quantity.
+func validateShipmentRequest(req *model.ShipmentRequest) error {
+	if req == nil || len(req.Items) == 0 {
+		return model.ErrInvalidShipment
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	for _, item := range req.Items {
+		if item.OrderItemID == uuid.Nil || item.Quantity <= 0 || seen[item.OrderItemID] {
+			return model.ErrInvalidShipment
+		}
+		seen[item.OrderItemID] = true
+	}
+
+	return nil
+}
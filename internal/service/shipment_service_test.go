@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockShipmentRepository is a mock implementation of ShipmentRepository.
+type MockShipmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockShipmentRepository) Create(ctx context.Context, shipment *model.Shipment) (*model.OrderEvent, error) {
+	args := m.Called(ctx, shipment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderEvent), args.Error(1)
+}
+
+func (m *MockShipmentRepository) UpdateTracking(ctx context.Context, orderID, shipmentID uuid.UUID, tracking model.TrackingRequest) (*model.OrderEvent, error) {
+	args := m.Called(ctx, orderID, shipmentID, tracking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderEvent), args.Error(1)
+}
+
+func (m *MockShipmentRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentRepository) ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OrderEvent), args.Error(1)
+}
+
+func TestShipmentService_CreateShipment(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	order := &model.Order{ID: orderID}
+	itemA := model.OrderItem{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 3, FulfilledQuantity: 1}
+	itemB := model.OrderItem{ID: uuid.New(), OrderID: orderID, ProductID: "P002", Quantity: 1}
+	items := []model.OrderItem{itemA, itemB}
+
+	tests := []struct {
+		name         string
+		req          *model.ShipmentRequest
+		mockOrder    *model.Order
+		createError  error
+		expectCreate bool
+		expectedErr  error
+	}{
+		{
+			name: "Partial shipment",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 1},
+			}},
+			mockOrder:    order,
+			expectCreate: true,
+		},
+		{
+			name: "Ships remaining quantities",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 2},
+				{OrderItemID: itemB.ID, Quantity: 1},
+			}},
+			mockOrder:    order,
+			expectCreate: true,
+		},
+		{
+			name:        "No items",
+			req:         &model.ShipmentRequest{},
+			expectedErr: model.ErrInvalidShipment,
+		},
+		{
+			name: "Duplicate item",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 1},
+				{OrderItemID: itemA.ID, Quantity: 1},
+			}},
+			expectedErr: model.ErrInvalidShipment,
+		},
+		{
+			name: "Non-positive quantity",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 0},
+			}},
+			expectedErr: model.ErrInvalidShipment,
+		},
+		{
+			name: "Order not found",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 1},
+			}},
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name: "Item not in order",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: uuid.New(), Quantity: 1},
+			}},
+			mockOrder:   order,
+			expectedErr: model.ErrOrderItemNotFound,
+		},
+		{
+			name: "Exceeds unfulfilled quantity",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 3},
+			}},
+			mockOrder:   order,
+			expectedErr: model.ErrOverFulfillment,
+		},
+		{
+			name: "Cancelled order",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemA.ID, Quantity: 1},
+			}},
+			mockOrder:   &model.Order{ID: orderID, Status: model.OrderStatusCancelled},
+			expectedErr: model.ErrOrderCancelled,
+		},
+		{
+			name: "Concurrent shipment exceeds quantity",
+			req: &model.ShipmentRequest{Items: []model.ShipmentItem{
+				{OrderItemID: itemB.ID, Quantity: 1},
+			}},
+			mockOrder:    order,
+			createError:  model.ErrOverFulfillment,
+			expectCreate: true,
+			expectedErr:  model.ErrOverFulfillment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			shipmentRepo := new(MockShipmentRepository)
+
+			orderRepo.On("GetByID", ctx, orderID).Return(tt.mockOrder, items, nil).Maybe()
+			if tt.expectCreate {
+				if tt.createError != nil {
+					shipmentRepo.On("Create", ctx, mock.Anything).Return(nil, tt.createError)
+				} else {
+					shipmentRepo.On("Create", ctx, mock.MatchedBy(func(s *model.Shipment) bool {
+						return s.OrderID == orderID && len(s.Items) == len(tt.req.Items)
+					})).Return(&model.OrderEvent{
+						Type:              model.OrderEventShipmentCreated,
+						FulfillmentStatus: model.FulfillmentStatusPartial,
+					}, nil)
+				}
+			}
+
+			svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+			shipment, err := svc.CreateShipment(ctx, orderID, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, shipment)
+			} else {
+				require.NoError(t, err)
+				assert.NotEqual(t, uuid.Nil, shipment.ID)
+				assert.Equal(t, orderID, shipment.OrderID)
+			}
+
+			if !tt.expectCreate {
+				shipmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			shipmentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShipmentService_List(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	t.Run("Returns shipments for existing order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID}, []model.OrderItem{}, nil)
+		shipmentRepo.On("ListByOrder", ctx, orderID).Return([]model.Shipment{{ID: uuid.New(), OrderID: orderID}}, nil)
+
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		shipments, err := svc.ListShipments(ctx, orderID)
+
+		require.NoError(t, err)
+		assert.Len(t, shipments, 1)
+	})
+
+	t.Run("Events for missing order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil)
+
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		_, err := svc.ListEvents(ctx, orderID)
+
+		assert.Equal(t, model.ErrOrderNotFound, err)
+		shipmentRepo.AssertNotCalled(t, "ListEvents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID}, []model.OrderItem{}, nil)
+		shipmentRepo.On("ListByOrder", ctx, orderID).Return(nil, errors.New("database error"))
+
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		_, err := svc.ListShipments(ctx, orderID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestShipmentService_ShipRemaining(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	itemA := model.OrderItem{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 3, FulfilledQuantity: 1}
+	itemB := model.OrderItem{ID: uuid.New(), OrderID: orderID, ProductID: "P002", Quantity: 1, FulfilledQuantity: 1}
+	itemC := model.OrderItem{ID: uuid.New(), OrderID: orderID, ProductID: "P003", Quantity: 2}
+
+	t.Run("Ships unfulfilled quantities", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID}, []model.OrderItem{itemA, itemB, itemC}, nil)
+		shipmentRepo.On("Create", ctx, mock.Anything).Return(&model.OrderEvent{
+			Type:              model.OrderEventShipmentCreated,
+			FulfillmentStatus: model.FulfillmentStatusFulfilled,
+		}, nil)
+
+		carrier := "AusPost"
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		shipment, err := svc.ShipRemaining(ctx, orderID, &model.ShipRemainingRequest{Carrier: &carrier})
+
+		require.NoError(t, err)
+		assert.Equal(t, &carrier, shipment.Carrier)
+		assert.Equal(t, []model.ShipmentItem{
+			{OrderItemID: itemA.ID, Quantity: 2},
+			{OrderItemID: itemC.ID, Quantity: 2},
+		}, shipment.Items)
+	})
+
+	t.Run("Nothing left to ship", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID}, []model.OrderItem{itemB}, nil)
+
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		_, err := svc.ShipRemaining(ctx, orderID, nil)
+
+		assert.Equal(t, model.ErrOrderShipped, err)
+		shipmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancelled order", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, Status: model.OrderStatusCancelled}, []model.OrderItem{itemC}, nil)
+
+		svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+		_, err := svc.ShipRemaining(ctx, orderID, nil)
+
+		assert.Equal(t, model.ErrOrderCancelled, err)
+	})
+}
+
+func TestShipmentService_UpdateTracking(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	shipmentID := uuid.New()
+	carrier := "AusPost"
+
+	tests := []struct {
+		name        string
+		req         *model.TrackingRequest
+		updateError error
+		expectCall  bool
+		expectedErr error
+	}{
+		{
+			name:       "Attaches tracking number",
+			req:        &model.TrackingRequest{Carrier: &carrier, TrackingNumber: "  AP123456  "},
+			expectCall: true,
+		},
+		{
+			name:        "Missing tracking number",
+			req:         &model.TrackingRequest{TrackingNumber: " "},
+			expectedErr: model.ErrInvalidTracking,
+		},
+		{
+			name:        "Unknown shipment",
+			req:         &model.TrackingRequest{TrackingNumber: "AP123456"},
+			updateError: model.ErrShipmentNotFound,
+			expectCall:  true,
+			expectedErr: model.ErrShipmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			shipmentRepo := new(MockShipmentRepository)
+			orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID}, []model.OrderItem{}, nil).Maybe()
+			if tt.expectCall {
+				tracking := model.TrackingRequest{Carrier: tt.req.Carrier, TrackingNumber: "AP123456"}
+				if tt.updateError != nil {
+					shipmentRepo.On("UpdateTracking", ctx, orderID, shipmentID, tracking).Return(nil, tt.updateError)
+				} else {
+					shipmentRepo.On("UpdateTracking", ctx, orderID, shipmentID, tracking).Return(&model.OrderEvent{Type: model.OrderEventTrackingUpdated}, nil)
+					shipmentRepo.On("ListByOrder", ctx, orderID).Return([]model.Shipment{
+						{ID: uuid.New(), OrderID: orderID},
+						{ID: shipmentID, OrderID: orderID, Carrier: &carrier, TrackingNumber: &tracking.TrackingNumber},
+					}, nil)
+				}
+			}
+
+			svc := NewShipmentService(orderRepo, shipmentRepo, logger)
+			shipment, err := svc.UpdateTracking(ctx, orderID, shipmentID, tt.req)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, shipment)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, shipmentID, shipment.ID)
+				assert.Equal(t, "AP123456", *shipment.TrackingNumber)
+			}
+
+			if !tt.expectCall {
+				shipmentRepo.AssertNotCalled(t, "UpdateTracking", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			shipmentRepo.AssertExpectations(t)
+		})
+	}
+}
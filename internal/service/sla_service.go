@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+	"mini-kart/internal/notification"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// MetricOrderSLABreaches counts orders that breached the SLA of the status
+// in its "status" label.
+const MetricOrderSLABreaches = "order_sla_breaches_total"
+
+// NotificationOrderSLABreached is sent for each order that breaches an SLA.
+const NotificationOrderSLABreached = "order_sla.breached"
+
+// maxBreachesPerCheck bounds the breaches recorded by one check; any others
+// are recorded by the next one.
+const maxBreachesPerCheck = 500
+
+// slaService implements SLAService.
+type slaService struct {
+	repo         repository.OrderSLARepository
+	slas         model.OrderSLAs
+	warningRatio float64
+	counters     *metrics.Registry
+	notifier     notification.Notifier
+	logger       zerolog.Logger
+}
+
+// NewSLAService creates a new order SLA service. Orders are at risk once they
+// have been in a status for warningRatio of its SLA, e.g. 0.8 for 80%.
+func NewSLAService(
+	repo repository.OrderSLARepository,
+	slas model.OrderSLAs,
+	warningRatio float64,
+	counters *metrics.Registry,
+	notifier notification.Notifier,
+	logger zerolog.Logger,
+) SLAService {
+	return &slaService{
+		repo:         repo,
+		slas:         slas,
+		warningRatio: warningRatio,
+		counters:     counters,
+		notifier:     notifier,
+		logger:       logger.With().Str("service", "sla").Logger(),
+	}
+}
+
+// AtRisk lists orders approaching or past their status's SLA.
+func (s *slaService) AtRisk(ctx context.Context, status model.OrderStatus, limit int) ([]model.AtRiskOrder, error) {
+	if status != "" && !status.Valid() {
+		return nil, model.ErrInvalidOrderStatus
+	}
+
+	minAges := make(map[model.OrderStatus]time.Duration, len(s.slas))
+	for st, sla := range s.slas {
+		if status == "" || st == status {
+			minAges[st] = time.Duration(float64(sla) * s.warningRatio)
+		}
+	}
+	if len(minAges) == 0 {
+		return []model.AtRiskOrder{}, nil
+	}
+
+	orders, err := s.repo.ListInStatus(ctx, minAges, limit)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list at-risk orders")
+		return nil, fmt.Errorf("failed to list at-risk orders: %w", err)
+	}
+
+	s.applySLAs(orders)
+	return orders, nil
+}
+
+// CheckBreaches records, counts and alerts on new SLA breaches.
+func (s *slaService) CheckBreaches(ctx context.Context) (int, error) {
+	if len(s.slas) == 0 {
+		return 0, nil
+	}
+
+	breaches, err := s.repo.RecordBreaches(ctx, s.slas, maxBreachesPerCheck)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to record SLA breaches")
+		return 0, fmt.Errorf("failed to record SLA breaches: %w", err)
+	}
+
+	s.applySLAs(breaches)
+	for _, order := range breaches {
+		s.counters.Counter(MetricOrderSLABreaches, "status", string(order.Status)).Inc()
+		s.notifyBreach(ctx, order)
+	}
+
+	return len(breaches), nil
+}
+
+// applySLAs fills in each order's SLA and whether it has been breached.
+func (s *slaService) applySLAs(orders []model.AtRiskOrder) {
+	for i := range orders {
+		sla := int64(s.slas[orders[i].Status] / time.Second)
+		orders[i].SLASeconds = sla
+		orders[i].Breached = orders[i].TimeInStatusSeconds > sla
+	}
+}
+
+// notifyBreach alerts admins that an order breached its SLA. Failures are
+// logged; the breach stays recorded and counted.
+func (s *slaService) notifyBreach(ctx context.Context, order model.AtRiskOrder) {
+	inStatus := time.Duration(order.TimeInStatusSeconds) * time.Second
+	err := s.notifier.Notify(ctx, notification.Notification{
+		Type: NotificationOrderSLABreached,
+		Message: fmt.Sprintf("order %s has been %s for %s, beyond its %s SLA",
+			order.ID, order.Status, inStatus, time.Duration(order.SLASeconds)*time.Second),
+		Fields: map[string]string{
+			"order_id":               order.ID.String(),
+			"status":                 string(order.Status),
+			"status_since":           order.StatusSince.UTC().Format(time.RFC3339),
+			"time_in_status_seconds": strconv.FormatInt(order.TimeInStatusSeconds, 10),
+			"sla_seconds":            strconv.FormatInt(order.SLASeconds, 10),
+		},
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).Str("order_id", order.ID.String()).Msg("failed to send SLA breach notification")
+	}
+}
+
+// RunSLAChecks checks for SLA breaches every interval until ctx is
+// cancelled. Failed checks are logged and retried at the next interval.
+func RunSLAChecks(ctx context.Context, slas SLAService, interval time.Duration, logger zerolog.Logger) {
+	logger = logger.With().Str("component", "order-sla-checks").Logger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			breaches, err := slas.CheckBreaches(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Msg("order SLA check failed")
+				continue
+			}
+			if breaches > 0 {
+				logger.Warn().Int("breaches", breaches).Msg("orders breached their SLA")
+			}
+		}
+	}
+}
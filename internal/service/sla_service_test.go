@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/model"
+	"mini-kart/internal/notification"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderSLARepository is a mock implementation of OrderSLARepository.
+type MockOrderSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderSLARepository) ListInStatus(ctx context.Context, minAges map[model.OrderStatus]time.Duration, limit int) ([]model.AtRiskOrder, error) {
+	args := m.Called(ctx, minAges, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AtRiskOrder), args.Error(1)
+}
+
+func (m *MockOrderSLARepository) RecordBreaches(ctx context.Context, slas model.OrderSLAs, limit int) ([]model.AtRiskOrder, error) {
+	args := m.Called(ctx, slas, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AtRiskOrder), args.Error(1)
+}
+
+var testSLAs = model.OrderSLAs{
+	model.OrderStatusPending:   time.Hour,
+	model.OrderStatusConfirmed: 24 * time.Hour,
+}
+
+func TestSLAService_AtRisk(t *testing.T) {
+	ctx := context.Background()
+	pending := uuid.New()
+	confirmed := uuid.New()
+
+	tests := []struct {
+		name          string
+		status        model.OrderStatus
+		expectMinAges map[model.OrderStatus]time.Duration
+		expectError   error
+	}{
+		{
+			name:   "All tracked statuses",
+			status: "",
+			expectMinAges: map[model.OrderStatus]time.Duration{
+				model.OrderStatusPending:   48 * time.Minute,
+				model.OrderStatusConfirmed: 19*time.Hour + 12*time.Minute,
+			},
+		},
+		{
+			name:   "One status",
+			status: model.OrderStatusPending,
+			expectMinAges: map[model.OrderStatus]time.Duration{
+				model.OrderStatusPending: 48 * time.Minute,
+			},
+		},
+		{
+			name:   "Untracked status",
+			status: model.OrderStatusFulfilled,
+		},
+		{
+			name:        "Invalid status",
+			status:      "stuck",
+			expectError: model.ErrInvalidOrderStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOrderSLARepository)
+			service := NewSLAService(repo, testSLAs, 0.8, metrics.NewRegistry(), new(MockNotifier), zerolog.Nop())
+
+			if tt.expectMinAges != nil {
+				repo.On("ListInStatus", ctx, tt.expectMinAges, 50).Return([]model.AtRiskOrder{
+					{ID: pending, Status: model.OrderStatusPending, TimeInStatusSeconds: 3000},
+					{ID: confirmed, Status: model.OrderStatusConfirmed, TimeInStatusSeconds: 90000},
+				}, nil)
+			}
+
+			orders, err := service.AtRisk(ctx, tt.status, 50)
+
+			if tt.expectError != nil {
+				assert.Equal(t, tt.expectError, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectMinAges == nil {
+				assert.Empty(t, orders)
+				repo.AssertNotCalled(t, "ListInStatus", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.Len(t, orders, 2)
+			assert.Equal(t, int64(3600), orders[0].SLASeconds)
+			assert.False(t, orders[0].Breached)
+			assert.Equal(t, int64(86400), orders[1].SLASeconds)
+			assert.True(t, orders[1].Breached)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSLAService_CheckBreaches(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	since := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+
+	repo := new(MockOrderSLARepository)
+	notifier := new(MockNotifier)
+	counters := metrics.NewRegistry()
+	service := NewSLAService(repo, testSLAs, 0.8, counters, notifier, zerolog.Nop())
+
+	repo.On("RecordBreaches", ctx, testSLAs, maxBreachesPerCheck).Return([]model.AtRiskOrder{
+		{ID: orderID, Status: model.OrderStatusPending, StatusSince: since, TimeInStatusSeconds: 3900},
+	}, nil)
+	notifier.On("Notify", ctx, notification.Notification{
+		Type:    NotificationOrderSLABreached,
+		Message: "order " + orderID.String() + " has been pending for 1h5m0s, beyond its 1h0m0s SLA",
+		Fields: map[string]string{
+			"order_id":               orderID.String(),
+			"status":                 "pending",
+			"status_since":           "2025-01-20T09:00:00Z",
+			"time_in_status_seconds": "3900",
+			"sla_seconds":            "3600",
+		},
+	}).Return(errors.New("notification channel unavailable"))
+
+	breaches, err := service.CheckBreaches(ctx)
+
+	require.NoError(t, err, "notification failures must not fail the check")
+	assert.Equal(t, 1, breaches)
+	assert.Equal(t, int64(1), counters.Counter(MetricOrderSLABreaches, "status", "pending").Value())
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestSLAService_CheckBreaches_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOrderSLARepository)
+	service := NewSLAService(repo, testSLAs, 0.8, metrics.NewRegistry(), new(MockNotifier), zerolog.Nop())
+
+	repo.On("RecordBreaches", ctx, testSLAs, maxBreachesPerCheck).Return(nil, errors.New("database error"))
+
+	_, err := service.CheckBreaches(ctx)
+	assert.EqualError(t, err, "failed to record SLA breaches: database error")
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/export"
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// snapshotService implements SnapshotService.
+type snapshotService struct {
+	orders       OrderService
+	timeline     TimelineService
+	orderRepo    repository.OrderRepository
+	shipmentRepo repository.ShipmentRepository
+	pricingRepo  repository.OrderPricingRepository
+	archive      export.SnapshotArchive
+	signingKey   []byte
+	logger       zerolog.Logger
+	now          func() time.Time
+}
+
+// NewSnapshotService creates a new order snapshot service. Snapshots are
+// signed with signingKey; a nil archive returns them without storing them.
+func NewSnapshotService(
+	orders OrderService,
+	timeline TimelineService,
+	orderRepo repository.OrderRepository,
+	shipmentRepo repository.ShipmentRepository,
+	pricingRepo repository.OrderPricingRepository,
+	archive export.SnapshotArchive,
+	signingKey []byte,
+	logger zerolog.Logger,
+) SnapshotService {
+	return &snapshotService{
+		orders:       orders,
+		timeline:     timeline,
+		orderRepo:    orderRepo,
+		shipmentRepo: shipmentRepo,
+		pricingRepo:  pricingRepo,
+		archive:      archive,
+		signingKey:   signingKey,
+		logger:       logger.With().Str("service", "snapshot").Logger(),
+		now:          time.Now,
+	}
+}
+
+// CreateSnapshot captures an order and its full history, signs the snapshot
+// and archives it. Taking the snapshot is itself recorded in the audit log,
+// with its hash, so later snapshots show who collected earlier evidence.
+func (s *snapshotService) CreateSnapshot(ctx context.Context, orderID uuid.UUID, actor string) (*model.SignedSnapshot, error) {
+	snapshot, err := s.capture(ctx, orderID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode order snapshot: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write(data)
+
+	signed := &model.SignedSnapshot{
+		Snapshot:           data,
+		SHA256:             hex.EncodeToString(sum[:]),
+		Signature:          hex.EncodeToString(mac.Sum(nil)),
+		SignatureAlgorithm: model.SnapshotSignatureAlgorithm,
+	}
+
+	details := map[string]any{
+		"orderId":    orderID.String(),
+		"snapshotId": snapshot.ID.String(),
+		"sha256":     signed.SHA256,
+	}
+
+	if s.archive != nil {
+		body, err := json.Marshal(signed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode order snapshot: %w", err)
+		}
+
+		key := fmt.Sprintf("%s/%s.json", orderID, snapshot.ID)
+		storage, err := s.archive.Archive(ctx, key, body)
+		if err != nil {
+			s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to archive order snapshot")
+			return nil, fmt.Errorf("failed to archive order snapshot: %w", err)
+		}
+		signed.Storage = storage
+		details["location"] = storage.Location
+	}
+
+	if err := s.recordAudit(ctx, actor, details); err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to record order snapshot audit entry")
+		return nil, fmt.Errorf("failed to record order snapshot: %w", err)
+	}
+
+	s.logger.Info().
+		Str("order_id", orderID.String()).
+		Str("snapshot_id", snapshot.ID.String()).
+		Str("sha256", signed.SHA256).
+		Str("actor", actor).
+		Bool("archived", signed.Storage != nil).
+		Msg("order snapshot taken")
+
+	return signed, nil
+}
+
+// capture reads an order and its history into a snapshot.
+func (s *snapshotService) capture(ctx context.Context, orderID uuid.UUID, actor string) (*model.OrderSnapshot, error) {
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	shipments, err := s.shipmentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order shipments")
+		return nil, fmt.Errorf("failed to list order shipments: %w", err)
+	}
+
+	versions, err := s.pricingRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order pricing versions")
+		return nil, fmt.Errorf("failed to list order pricing versions: %w", err)
+	}
+
+	audit, err := s.orderRepo.ListAuditEntries(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order audit entries")
+		return nil, fmt.Errorf("failed to list order audit entries: %w", err)
+	}
+
+	timeline, err := s.timeline.Timeline(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OrderSnapshot{
+		ID:              uuid.New(),
+		OrderID:         orderID,
+		TakenBy:         actor,
+		TakenAt:         s.now().UTC(),
+		Order:           order,
+		Shipments:       shipments,
+		PricingVersions: versions,
+		AuditLog:        audit,
+		Timeline:        timeline,
+	}, nil
+}
+
+// recordAudit records that a snapshot was taken.
+func (s *snapshotService) recordAudit(ctx context.Context, actor string, details map[string]any) error {
+	tx, err := s.orderRepo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.orderRepo.RecordAudit(ctx, tx, &model.AuditEntry{
+		Actor:      actor,
+		Action:     model.AuditActionOrderSnapshot,
+		EntityType: "order",
+		Details:    details,
+	})
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			s.logger.Error().Err(rbErr).Msg("failed to rollback transaction")
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
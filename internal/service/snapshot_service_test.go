@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/export"
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSnapshotArchive is a mock implementation of export.SnapshotArchive.
+type MockSnapshotArchive struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotArchive) Archive(ctx context.Context, key string, body []byte) (*model.SnapshotStorage, error) {
+	args := m.Called(ctx, key, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SnapshotStorage), args.Error(1)
+}
+
+func TestSnapshotService_CreateSnapshot(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	signingKey := []byte("snapshot-signing-key")
+
+	orderID := uuid.New()
+	placedAt := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	total := model.NewMoney(20)
+	order := &model.Order{ID: orderID, Status: model.OrderStatusConfirmed, Total: &total, CreatedAt: placedAt}
+	items := []model.OrderItem{
+		{ID: uuid.New(), OrderID: orderID, ProductID: "P001", Quantity: 2, Product: &model.ProductSnapshot{Name: "Product 1", Category: "Cat1", Price: model.NewMoney(10.00)}},
+	}
+	changes := []model.OrderStatusChange{
+		{ID: uuid.New(), OrderID: orderID, From: model.OrderStatusPending, To: model.OrderStatusConfirmed, CreatedAt: placedAt.Add(time.Hour)},
+	}
+	versions := []model.OrderPricingVersion{{OrderID: orderID, Version: 1, Total: &total, CreatedAt: placedAt}}
+	audit := []model.AuditEntry{
+		{ID: uuid.New(), Actor: "admin:alice", Action: model.AuditActionOrderStatus, EntityType: "order", Details: map[string]any{"orderId": orderID.String()}},
+	}
+	storage := &model.SnapshotStorage{
+		Location:    "s3://evidence/snapshots/" + orderID.String() + ".json",
+		VersionID:   "v1",
+		LockMode:    "COMPLIANCE",
+		RetainUntil: placedAt.AddDate(7, 0, 0),
+	}
+
+	tests := []struct {
+		name          string
+		found         bool
+		archive       bool
+		archiveError  error
+		expectAudit   bool
+		expectedErr   error
+		expectError   bool
+		expectStorage bool
+	}{
+		{
+			name:          "Signed and archived",
+			found:         true,
+			archive:       true,
+			expectAudit:   true,
+			expectStorage: true,
+		},
+		{
+			name:        "Signed without an archive",
+			found:       true,
+			expectAudit: true,
+		},
+		{
+			name:        "Order not found",
+			archive:     true,
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name:         "Archive fails",
+			found:        true,
+			archive:      true,
+			archiveError: errors.New("access denied"),
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			noteRepo := new(MockOrderNoteRepository)
+			shipmentRepo := new(MockShipmentRepository)
+			webhookRepo := new(MockWebhookRepository)
+			pricingRepo := new(MockOrderPricingRepository)
+			archive := new(MockSnapshotArchive)
+
+			if !tt.found {
+				orderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil)
+			} else {
+				orderRepo.On("GetByID", ctx, orderID).Return(order, items, nil)
+				orderRepo.On("ListStatusChanges", ctx, orderID).Return(changes, nil)
+				orderRepo.On("ListAuditEntries", ctx, orderID).Return(audit, nil)
+				noteRepo.On("ListByOrder", ctx, orderID).Return([]model.OrderNote{}, nil)
+				shipmentRepo.On("ListByOrder", ctx, orderID).Return([]model.Shipment{}, nil)
+				shipmentRepo.On("ListEvents", ctx, orderID).Return([]model.OrderEvent{}, nil)
+				webhookRepo.On("ListByOrder", ctx, orderID).Return([]model.WebhookDelivery{}, nil)
+				pricingRepo.On("ListByOrder", ctx, orderID).Return(versions, nil)
+			}
+
+			var archived []byte
+			if tt.archive && tt.found {
+				var result *model.SnapshotStorage
+				if tt.archiveError == nil {
+					result = storage
+				}
+				archive.On("Archive", ctx, mock.MatchedBy(func(key string) bool {
+					return len(key) > len(orderID.String()) && key[:len(orderID.String())] == orderID.String()
+				}), mock.Anything).Run(func(args mock.Arguments) {
+					archived = args.Get(2).([]byte)
+				}).Return(result, tt.archiveError)
+			}
+
+			tx := new(MockTx)
+			if tt.expectAudit {
+				orderRepo.On("BeginTx", ctx).Return(tx, nil)
+				orderRepo.On("RecordAudit", ctx, tx, mock.MatchedBy(func(e *model.AuditEntry) bool {
+					return e.Actor == "admin:bob" && e.Action == model.AuditActionOrderSnapshot &&
+						e.Details["orderId"] == orderID.String() && e.Details["sha256"] != nil &&
+						(e.Details["location"] != nil) == tt.expectStorage
+				})).Return(nil)
+				tx.On("Commit", ctx).Return(nil)
+			}
+
+			var snapshotArchive export.SnapshotArchive
+			if tt.archive {
+				snapshotArchive = archive
+			}
+			svc := NewSnapshotService(
+				NewOrderService(orderRepo, nil, nil, logger),
+				NewTimelineService(orderRepo, noteRepo, shipmentRepo, webhookRepo, logger),
+				orderRepo,
+				shipmentRepo,
+				pricingRepo,
+				snapshotArchive,
+				signingKey,
+				logger,
+			)
+
+			signed, err := svc.CreateSnapshot(ctx, orderID, "admin:bob")
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, signed)
+				archive.AssertNotCalled(t, "Archive", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, signed)
+				orderRepo.AssertNotCalled(t, "RecordAudit", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+
+			sum := sha256.Sum256(signed.Snapshot)
+			assert.Equal(t, hex.EncodeToString(sum[:]), signed.SHA256)
+			mac := hmac.New(sha256.New, signingKey)
+			mac.Write(signed.Snapshot)
+			assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signed.Signature)
+			assert.Equal(t, model.SnapshotSignatureAlgorithm, signed.SignatureAlgorithm)
+
+			var snapshot model.OrderSnapshot
+			require.NoError(t, json.Unmarshal(signed.Snapshot, &snapshot))
+			assert.Equal(t, orderID, snapshot.OrderID)
+			assert.Equal(t, "admin:bob", snapshot.TakenBy)
+			assert.Equal(t, orderID, snapshot.Order.ID)
+			assert.Len(t, snapshot.PricingVersions, 1)
+			assert.Len(t, snapshot.AuditLog, 1)
+			require.Len(t, snapshot.Timeline, 2)
+			assert.Equal(t, model.TimelineStatusChanged, snapshot.Timeline[1].Type)
+
+			if tt.expectStorage {
+				assert.Equal(t, storage, signed.Storage)
+
+				// The archived object is the signed snapshot, before its storage was known
+				var stored model.SignedSnapshot
+				require.NoError(t, json.Unmarshal(archived, &stored))
+				assert.JSONEq(t, string(signed.Snapshot), string(stored.Snapshot))
+				assert.Equal(t, signed.Signature, stored.Signature)
+				assert.Nil(t, stored.Storage)
+			} else {
+				assert.Nil(t, signed.Storage)
+			}
+
+			orderRepo.AssertExpectations(t)
+			archive.AssertExpectations(t)
+			tx.AssertExpectations(t)
+		})
+	}
+}
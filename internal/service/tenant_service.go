@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// tenantService implements TenantService.
+type tenantService struct {
+	repo   repository.TenantRepository
+	logger zerolog.Logger
+}
+
+// NewTenantService creates a new tenant service.
+func NewTenantService(repo repository.TenantRepository, logger zerolog.Logger) TenantService {
+	return &tenantService{
+		repo:   repo,
+		logger: logger.With().Str("service", "tenant").Logger(),
+	}
+}
+
+// ListTenants retrieves every tenant, ordered by ID.
+func (s *tenantService) ListTenants(ctx context.Context) ([]model.Tenant, error) {
+	tenants, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list tenants")
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// GetTenant retrieves a tenant.
+func (s *tenantService) GetTenant(ctx context.Context, id string) (*model.Tenant, error) {
+	if !model.ValidTenantID(id) {
+		return nil, model.ErrTenantNotFound
+	}
+
+	tenant, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).Str("tenant_id", id).Msg("failed to get tenant")
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	if tenant == nil {
+		return nil, model.ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+// CreateTenant validates and creates a tenant.
+func (s *tenantService) CreateTenant(ctx context.Context, req *model.TenantRequest) (*model.Tenant, error) {
+	if req == nil {
+		return nil, fmt.Errorf("tenant request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	tenant := &model.Tenant{ID: req.ID, Name: req.Name}
+	if err := s.repo.Create(ctx, tenant); err != nil {
+		if err == model.ErrTenantExists {
+			return nil, err
+		}
+		s.logger.Error().Err(err).Str("tenant_id", req.ID).Msg("failed to create tenant")
+		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	s.logger.Info().Str("tenant_id", tenant.ID).Msg("tenant created")
+
+	return tenant, nil
+}
+
+// RenameTenant changes a tenant's name.
+func (s *tenantService) RenameTenant(ctx context.Context, id, name string) (*model.Tenant, error) {
+	if !model.ValidTenantID(id) {
+		return nil, model.ErrTenantNotFound
+	}
+	if err := (model.TenantRequest{ID: id, Name: name}).Validate(); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.repo.UpdateName(ctx, id, name)
+	if err != nil {
+		s.logger.Error().Err(err).Str("tenant_id", id).Msg("failed to rename tenant")
+		return nil, fmt.Errorf("failed to rename tenant: %w", err)
+	}
+	if tenant == nil {
+		return nil, model.ErrTenantNotFound
+	}
+
+	s.logger.Info().Str("tenant_id", id).Msg("tenant renamed")
+
+	return tenant, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mini-kart/internal/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantRepository is a mock implementation of TenantRepository.
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) List(ctx context.Context) ([]model.Tenant, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetByID(ctx context.Context, id string) (*model.Tenant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) Create(ctx context.Context, tenant *model.Tenant) error {
+	args := m.Called(ctx, tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) UpdateName(ctx context.Context, id, name string) (*model.Tenant, error) {
+	args := m.Called(ctx, id, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) Ensure(ctx context.Context, ids []string) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func TestTenantService_GetTenant(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		setupMock     func(*MockTenantRepository)
+		expectedError error
+	}{
+		{
+			name: "Tenant found",
+			id:   "acme",
+			setupMock: func(m *MockTenantRepository) {
+				m.On("GetByID", mock.Anything, "acme").Return(&model.Tenant{ID: "acme", Name: "Acme"}, nil)
+			},
+		},
+		{
+			name: "Tenant not found",
+			id:   "globex",
+			setupMock: func(m *MockTenantRepository) {
+				m.On("GetByID", mock.Anything, "globex").Return(nil, nil)
+			},
+			expectedError: model.ErrTenantNotFound,
+		},
+		{
+			name:          "Malformed ID is not found",
+			id:            "Globex Corp",
+			setupMock:     func(m *MockTenantRepository) {},
+			expectedError: model.ErrTenantNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTenantRepository)
+			tt.setupMock(repo)
+			svc := NewTenantService(repo, zerolog.Nop())
+
+			tenant, err := svc.GetTenant(context.Background(), tt.id)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, tenant)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, tenant.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTenantService_CreateTenant(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *model.TenantRequest
+		setupMock     func(*MockTenantRepository)
+		expectedError error
+	}{
+		{
+			name: "Tenant created",
+			req:  &model.TenantRequest{ID: "acme", Name: "Acme"},
+			setupMock: func(m *MockTenantRepository) {
+				m.On("Create", mock.Anything, &model.Tenant{ID: "acme", Name: "Acme"}).Return(nil)
+			},
+		},
+		{
+			name:          "Invalid ID",
+			req:           &model.TenantRequest{ID: "-acme", Name: "Acme"},
+			setupMock:     func(m *MockTenantRepository) {},
+			expectedError: model.ErrInvalidTenant,
+		},
+		{
+			name:          "Missing name",
+			req:           &model.TenantRequest{ID: "acme"},
+			setupMock:     func(m *MockTenantRepository) {},
+			expectedError: model.ErrInvalidTenant,
+		},
+		{
+			name: "Tenant exists",
+			req:  &model.TenantRequest{ID: "acme", Name: "Acme"},
+			setupMock: func(m *MockTenantRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*model.Tenant")).Return(model.ErrTenantExists)
+			},
+			expectedError: model.ErrTenantExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTenantRepository)
+			tt.setupMock(repo)
+			svc := NewTenantService(repo, zerolog.Nop())
+
+			tenant, err := svc.CreateTenant(context.Background(), tt.req)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, tenant)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.req.ID, tenant.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTenantService_RenameTenant(t *testing.T) {
+	t.Run("Tenant renamed", func(t *testing.T) {
+		repo := new(MockTenantRepository)
+		repo.On("UpdateName", mock.Anything, "acme", "Acme Outlet").Return(&model.Tenant{ID: "acme", Name: "Acme Outlet"}, nil)
+		svc := NewTenantService(repo, zerolog.Nop())
+
+		tenant, err := svc.RenameTenant(context.Background(), "acme", "Acme Outlet")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme Outlet", tenant.Name)
+	})
+
+	t.Run("Tenant not found", func(t *testing.T) {
+		repo := new(MockTenantRepository)
+		repo.On("UpdateName", mock.Anything, "globex", "Globex").Return(nil, nil)
+		svc := NewTenantService(repo, zerolog.Nop())
+
+		_, err := svc.RenameTenant(context.Background(), "globex", "Globex")
+
+		assert.Equal(t, model.ErrTenantNotFound, err)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := new(MockTenantRepository)
+		repo.On("UpdateName", mock.Anything, "acme", "Acme").Return(nil, errors.New("database error"))
+		svc := NewTenantService(repo, zerolog.Nop())
+
+		_, err := svc.RenameTenant(context.Background(), "acme", "Acme")
+
+		assert.Error(t, err)
+		assert.NotEqual(t, model.ErrTenantNotFound, err)
+	})
+}
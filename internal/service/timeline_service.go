@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// maxNoteLength is the maximum length of an order note in characters.
+const maxNoteLength = 2000
+
+// timelineService implements TimelineService.
+type timelineService struct {
+	orderRepo    repository.OrderRepository
+	noteRepo     repository.OrderNoteRepository
+	shipmentRepo repository.ShipmentRepository
+	webhookRepo  repository.WebhookRepository
+	logger       zerolog.Logger
+}
+
+// NewTimelineService creates a new order timeline service.
+func NewTimelineService(
+	orderRepo repository.OrderRepository,
+	noteRepo repository.OrderNoteRepository,
+	shipmentRepo repository.ShipmentRepository,
+	webhookRepo repository.WebhookRepository,
+	logger zerolog.Logger,
+) TimelineService {
+	return &timelineService{
+		orderRepo:    orderRepo,
+		noteRepo:     noteRepo,
+		shipmentRepo: shipmentRepo,
+		webhookRepo:  webhookRepo,
+		logger:       logger.With().Str("service", "timeline").Logger(),
+	}
+}
+
+// AddNote records an internal note on an order.
+func (s *timelineService) AddNote(ctx context.Context, orderID uuid.UUID, author string, req *model.OrderNoteRequest) (*model.OrderNote, error) {
+	if req == nil {
+		return nil, model.ErrInvalidNote
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxNoteLength {
+		return nil, model.ErrInvalidNote
+	}
+
+	if _, err := s.order(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	note := &model.OrderNote{
+		ID:      uuid.New(),
+		OrderID: orderID,
+		Author:  author,
+		Body:    body,
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to add order note")
+		return nil, fmt.Errorf("failed to add order note: %w", err)
+	}
+
+	s.logger.Info().
+		Str("order_id", orderID.String()).
+		Str("note_id", note.ID.String()).
+		Str("author", author).
+		Msg("order note added")
+
+	return note, nil
+}
+
+// Timeline merges an order's creation, notes, status changes, fulfillment
+// events and webhook deliveries into one history, oldest first. Deliveries
+// appear when they were queued and carry their current status and attempts.
+func (s *timelineService) Timeline(ctx context.Context, orderID uuid.UUID) ([]model.TimelineEntry, error) {
+	order, err := s.order(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order notes")
+		return nil, fmt.Errorf("failed to list order notes: %w", err)
+	}
+
+	changes, err := s.orderRepo.ListStatusChanges(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order status changes")
+		return nil, fmt.Errorf("failed to list order status changes: %w", err)
+	}
+
+	events, err := s.shipmentRepo.ListEvents(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order events")
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+
+	deliveries, err := s.webhookRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list order webhook deliveries")
+		return nil, fmt.Errorf("failed to list order webhook deliveries: %w", err)
+	}
+
+	timeline := make([]model.TimelineEntry, 0, 1+len(notes)+len(changes)+len(events)+len(deliveries))
+	timeline = append(timeline, model.TimelineEntry{Type: model.TimelineOrderCreated, At: order.CreatedAt})
+	for i := range notes {
+		timeline = append(timeline, model.TimelineEntry{Type: model.TimelineNoteAdded, At: notes[i].CreatedAt, Note: &notes[i]})
+	}
+	for i := range changes {
+		timeline = append(timeline, model.TimelineEntry{Type: model.TimelineStatusChanged, At: changes[i].CreatedAt, StatusChange: &changes[i]})
+	}
+	for i := range events {
+		timeline = append(timeline, model.TimelineEntry{Type: events[i].Type, At: events[i].CreatedAt, Event: &events[i]})
+	}
+	for i := range deliveries {
+		timeline = append(timeline, model.TimelineEntry{Type: model.TimelineWebhookQueued, At: deliveries[i].CreatedAt, Webhook: &deliveries[i]})
+	}
+
+	// Each source is already in order; a stable sort keeps that order for
+	// entries recorded at the same instant
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+
+	return timeline, nil
+}
+
+// order loads an order, returning model.ErrOrderNotFound if it does not exist.
+func (s *timelineService) order(ctx context.Context, orderID uuid.UUID) (*model.Order, error) {
+	order, _, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to get order")
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return order, nil
+}
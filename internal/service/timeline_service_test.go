@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderNoteRepository is a mock implementation of OrderNoteRepository.
+type MockOrderNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderNoteRepository) Create(ctx context.Context, note *model.OrderNote) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockOrderNoteRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.OrderNote, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OrderNote), args.Error(1)
+}
+
+func TestTimelineService_AddNote(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name         string
+		body         string
+		mockOrder    *model.Order
+		createError  error
+		expectCreate bool
+		expectedErr  error
+		expectError  bool
+	}{
+		{
+			name:         "Note added",
+			body:         "  Customer called about delivery  ",
+			mockOrder:    &model.Order{ID: orderID},
+			expectCreate: true,
+		},
+		{
+			name:        "Empty body",
+			body:        "   ",
+			expectedErr: model.ErrInvalidNote,
+		},
+		{
+			name:        "Body too long",
+			body:        strings.Repeat("a", maxNoteLength+1),
+			expectedErr: model.ErrInvalidNote,
+		},
+		{
+			name:        "Order not found",
+			body:        "Refund requested",
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name:         "Repository error",
+			body:         "Refund requested",
+			mockOrder:    &model.Order{ID: orderID},
+			createError:  errors.New("database error"),
+			expectCreate: true,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(MockOrderRepository)
+			noteRepo := new(MockOrderNoteRepository)
+
+			if tt.mockOrder != nil {
+				orderRepo.On("GetByID", ctx, orderID).Return(tt.mockOrder, []model.OrderItem{}, nil).Maybe()
+			} else {
+				orderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil).Maybe()
+			}
+			if tt.expectCreate {
+				noteRepo.On("Create", ctx, mock.MatchedBy(func(n *model.OrderNote) bool {
+					return n.OrderID == orderID && n.Author == "agent-7" && n.Body == strings.TrimSpace(tt.body)
+				})).Return(tt.createError)
+			}
+
+			svc := NewTimelineService(orderRepo, noteRepo, new(MockShipmentRepository), new(MockWebhookRepository), logger)
+			note, err := svc.AddNote(ctx, orderID, "agent-7", &model.OrderNoteRequest{Body: tt.body})
+
+			switch {
+			case tt.expectedErr != nil:
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, note)
+			case tt.expectError:
+				require.Error(t, err)
+				assert.Nil(t, note)
+			default:
+				require.NoError(t, err)
+				assert.NotEqual(t, uuid.Nil, note.ID)
+				assert.Equal(t, "Customer called about delivery", note.Body)
+			}
+
+			if !tt.expectCreate {
+				noteRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+			noteRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimelineService_Timeline(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx := context.Background()
+	orderID := uuid.New()
+	created := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+
+	notes := []model.OrderNote{
+		{ID: uuid.New(), OrderID: orderID, Author: "agent-7", Body: "Called customer", CreatedAt: created.Add(2 * time.Hour)},
+	}
+	changes := []model.OrderStatusChange{
+		{ID: uuid.New(), OrderID: orderID, From: model.OrderStatusPending, To: model.OrderStatusConfirmed, CreatedAt: created.Add(time.Hour)},
+		{ID: uuid.New(), OrderID: orderID, From: model.OrderStatusConfirmed, To: model.OrderStatusFulfilled, CreatedAt: created.Add(4 * time.Hour)},
+	}
+	events := []model.OrderEvent{
+		{ID: uuid.New(), OrderID: orderID, Type: model.OrderEventShipmentCreated, FulfillmentStatus: model.FulfillmentStatusFulfilled, CreatedAt: created.Add(3 * time.Hour)},
+	}
+	lastError := "endpoint returned 503"
+	deliveries := []model.WebhookDelivery{
+		{ID: uuid.New(), OrderID: orderID, EventType: model.WebhookOrderCreated, Endpoint: "https://a.example.com", Status: model.WebhookDeliveryDelivered, Attempts: 1, CreatedAt: created},
+		{ID: uuid.New(), OrderID: orderID, EventType: model.WebhookOrderCreated, Endpoint: "https://b.example.com", Status: model.WebhookDeliveryFailed, Attempts: 5, LastError: &lastError, CreatedAt: created},
+	}
+
+	t.Run("Merges history chronologically", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		noteRepo := new(MockOrderNoteRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		webhookRepo := new(MockWebhookRepository)
+
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, CreatedAt: created}, []model.OrderItem{}, nil)
+		orderRepo.On("ListStatusChanges", ctx, orderID).Return(changes, nil)
+		noteRepo.On("ListByOrder", ctx, orderID).Return(notes, nil)
+		shipmentRepo.On("ListEvents", ctx, orderID).Return(events, nil)
+		webhookRepo.On("ListByOrder", ctx, orderID).Return(deliveries, nil)
+
+		svc := NewTimelineService(orderRepo, noteRepo, shipmentRepo, webhookRepo, logger)
+		timeline, err := svc.Timeline(ctx, orderID)
+
+		require.NoError(t, err)
+		require.Len(t, timeline, 7)
+
+		types := make([]string, len(timeline))
+		for i, entry := range timeline {
+			types[i] = entry.Type
+		}
+		assert.Equal(t, []string{
+			model.TimelineOrderCreated,
+			model.TimelineWebhookQueued,
+			model.TimelineWebhookQueued,
+			model.TimelineStatusChanged,
+			model.TimelineNoteAdded,
+			model.OrderEventShipmentCreated,
+			model.TimelineStatusChanged,
+		}, types)
+		assert.Equal(t, created, timeline[0].At)
+		assert.Equal(t, model.WebhookDeliveryDelivered, timeline[1].Webhook.Status)
+		assert.Equal(t, "https://b.example.com", timeline[2].Webhook.Endpoint)
+		assert.Equal(t, 5, timeline[2].Webhook.Attempts)
+		assert.Equal(t, &lastError, timeline[2].Webhook.LastError)
+		assert.Equal(t, "Called customer", timeline[4].Note.Body)
+		assert.Equal(t, model.OrderStatusFulfilled, timeline[6].StatusChange.To)
+		require.NotNil(t, timeline[5].Event)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		orderRepo.On("GetByID", ctx, orderID).Return(nil, nil, nil)
+
+		svc := NewTimelineService(orderRepo, new(MockOrderNoteRepository), new(MockShipmentRepository), new(MockWebhookRepository), logger)
+		timeline, err := svc.Timeline(ctx, orderID)
+
+		assert.Equal(t, model.ErrOrderNotFound, err)
+		assert.Nil(t, timeline)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		noteRepo := new(MockOrderNoteRepository)
+
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, CreatedAt: created}, []model.OrderItem{}, nil)
+		noteRepo.On("ListByOrder", ctx, orderID).Return(nil, errors.New("database error"))
+
+		svc := NewTimelineService(orderRepo, noteRepo, new(MockShipmentRepository), new(MockWebhookRepository), logger)
+		timeline, err := svc.Timeline(ctx, orderID)
+
+		require.Error(t, err)
+		assert.Nil(t, timeline)
+	})
+
+	t.Run("Webhook repository error", func(t *testing.T) {
+		orderRepo := new(MockOrderRepository)
+		noteRepo := new(MockOrderNoteRepository)
+		shipmentRepo := new(MockShipmentRepository)
+		webhookRepo := new(MockWebhookRepository)
+
+		orderRepo.On("GetByID", ctx, orderID).Return(&model.Order{ID: orderID, CreatedAt: created}, []model.OrderItem{}, nil)
+		orderRepo.On("ListStatusChanges", ctx, orderID).Return(changes, nil)
+		noteRepo.On("ListByOrder", ctx, orderID).Return(notes, nil)
+		shipmentRepo.On("ListEvents", ctx, orderID).Return(events, nil)
+		webhookRepo.On("ListByOrder", ctx, orderID).Return(nil, errors.New("database error"))
+
+		svc := NewTimelineService(orderRepo, noteRepo, shipmentRepo, webhookRepo, logger)
+		timeline, err := svc.Timeline(ctx, orderID)
+
+		require.Error(t, err)
+		assert.Nil(t, timeline)
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// upsellService implements UpsellService.
+type upsellService struct {
+	repo     repository.UpsellRepository
+	limits   model.UpsellLimits
+	maxLimit int
+	currency string
+	logger   zerolog.Logger
+	now      func() time.Time
+}
+
+// NewUpsellService creates a new upsell service. limits holds the default
+// number of products recommended, the per-category cap and the sales window;
+// callers may ask for up to maxLimit products. Products that do not name
+// their currency are shown in the catalogue currency.
+func NewUpsellService(repo repository.UpsellRepository, limits model.UpsellLimits, maxLimit int, currency string, logger zerolog.Logger) UpsellService {
+	return &upsellService{
+		repo:     repo,
+		limits:   limits,
+		maxLimit: maxLimit,
+		currency: model.NormalizeCurrency(currency),
+		logger:   logger.With().Str("service", "upsell").Logger(),
+		now:      time.Now,
+	}
+}
+
+// Upsell recommends products to buy after an order.
+func (s *upsellService) Upsell(ctx context.Context, orderID uuid.UUID, limit int) ([]model.UpsellProduct, error) {
+	limits := s.limits
+	if limit > 0 {
+		limits.Limit = min(limit, s.maxLimit)
+	}
+
+	products, err := s.repo.ListUpsell(ctx, orderID, limits)
+	if err == model.ErrOrderNotFound {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to list upsell products")
+		return nil, fmt.Errorf("failed to list upsell products: %w", err)
+	}
+
+	now := s.now()
+	for i := range products {
+		if products[i].Currency == "" {
+			products[i].Currency = s.currency
+		}
+		products[i].Product = products[i].WithEffectivePrice(now)
+	}
+
+	return products, nil
+}
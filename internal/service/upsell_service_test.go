@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUpsellRepository is a mock implementation of UpsellRepository.
+type MockUpsellRepository struct {
+	mock.Mock
+}
+
+func (m *MockUpsellRepository) ListUpsell(ctx context.Context, orderID uuid.UUID, limits model.UpsellLimits) ([]model.UpsellProduct, error) {
+	args := m.Called(ctx, orderID, limits)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UpsellProduct), args.Error(1)
+}
+
+func TestUpsellService_Upsell(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	defaults := model.UpsellLimits{Limit: 4, PerCategory: 2, SalesWindow: 90 * 24 * time.Hour}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	saleEnds := now.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		limit        int
+		expectLimits model.UpsellLimits
+		repoErr      error
+		expectError  error
+	}{
+		{
+			name:         "Default limit",
+			limit:        0,
+			expectLimits: defaults,
+		},
+		{
+			name:         "Requested limit",
+			limit:        10,
+			expectLimits: model.UpsellLimits{Limit: 10, PerCategory: 2, SalesWindow: defaults.SalesWindow},
+		},
+		{
+			name:         "Limit capped at the maximum",
+			limit:        50,
+			expectLimits: model.UpsellLimits{Limit: 20, PerCategory: 2, SalesWindow: defaults.SalesWindow},
+		},
+		{
+			name:         "Order not found",
+			expectLimits: defaults,
+			repoErr:      model.ErrOrderNotFound,
+			expectError:  model.ErrOrderNotFound,
+		},
+		{
+			name:         "Repository error",
+			expectLimits: defaults,
+			repoErr:      errors.New("database error"),
+			expectError:  errors.New("failed to list upsell products: database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUpsellRepository)
+			svc := NewUpsellService(repo, defaults, 20, "aud", zerolog.Nop()).(*upsellService)
+			svc.now = func() time.Time { return now }
+
+			products := []model.UpsellProduct{
+				{
+					Product: model.Product{
+						ID: "P002", Name: "Belgian Waffle", Price: model.NewMoney(9.99), Category: "Waffle",
+						Sale: &model.ProductSale{Price: model.NewMoney(7.99), EndsAt: &saleEnds},
+					},
+					UnitsSold: 5,
+				},
+				{
+					Product:   model.Product{ID: "P006", Name: "Lemonade", Price: model.NewMoney(3.50), Category: "Drinks", Currency: "USD"},
+					UnitsSold: 1,
+				},
+			}
+			if tt.repoErr != nil {
+				repo.On("ListUpsell", ctx, orderID, tt.expectLimits).Return(nil, tt.repoErr)
+			} else {
+				repo.On("ListUpsell", ctx, orderID, tt.expectLimits).Return(products, nil)
+			}
+
+			result, err := svc.Upsell(ctx, orderID, tt.limit)
+
+			if tt.expectError != nil {
+				assert.EqualError(t, err, tt.expectError.Error())
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, result, 2)
+				assert.Equal(t, model.NewMoney(7.99), result[0].EffectivePrice)
+				assert.True(t, result[0].OnSale)
+				assert.Equal(t, "AUD", result[0].Currency)
+				assert.Equal(t, model.NewMoney(3.50), result[1].EffectivePrice)
+				assert.Equal(t, "USD", result[1].Currency)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
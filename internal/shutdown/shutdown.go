@@ -0,0 +1,120 @@
+// Package shutdown runs the application's cleanup steps in a fixed order when
+// the server stops. Each step gets its own timeout so one slow component
+// cannot consume the whole shutdown budget.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Func is a cleanup step. It should return promptly once ctx is done.
+type Func func(ctx context.Context) error
+
+// hook is a registered cleanup step.
+type hook struct {
+	name    string
+	timeout time.Duration
+	fn      Func
+}
+
+// Hooks is an ordered list of cleanup steps. It is safe for concurrent use.
+type Hooks struct {
+	mu     sync.Mutex
+	hooks  []hook
+	logger zerolog.Logger
+}
+
+// NewHooks creates an empty hook list.
+func NewHooks(logger zerolog.Logger) *Hooks {
+	return &Hooks{
+		logger: logger.With().Str("component", "shutdown").Logger(),
+	}
+}
+
+// Register appends a cleanup step. Steps run in registration order, each
+// limited to timeout or the remaining shutdown budget, whichever is shorter.
+// A non-positive timeout leaves the step limited by the budget only.
+func (h *Hooks) Register(name string, timeout time.Duration, fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hooks = append(h.hooks, hook{name: name, timeout: timeout, fn: fn})
+}
+
+// Run executes the registered steps in order. A failing or timed-out step is
+// logged and does not stop later steps; once ctx is done the remaining steps
+// are skipped. The returned error joins every failure.
+func (h *Hooks) Run(ctx context.Context) error {
+	h.mu.Lock()
+	hooks := append([]hook(nil), h.hooks...)
+	h.mu.Unlock()
+
+	var errs []error
+	for i, hk := range hooks {
+		if ctx.Err() != nil {
+			for _, skipped := range hooks[i:] {
+				h.logger.Warn().Str("hook", skipped.name).Msg("shutdown budget exhausted, skipping hook")
+				errs = append(errs, fmt.Errorf("%s: skipped: %w", skipped.name, ctx.Err()))
+			}
+			break
+		}
+
+		if err := h.run(ctx, hk); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hk.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// run executes a single step within its timeout. Steps that ignore their
+// context are abandoned when the timeout expires.
+func (h *Hooks) run(ctx context.Context, hk hook) error {
+	hookCtx := ctx
+	if hk.timeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, hk.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- hk.fn(hookCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-hookCtx.Done():
+		err = hookCtx.Err()
+	}
+
+	elapsed := time.Since(start)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+		h.logger.Warn().
+			Str("hook", hk.name).
+			Dur("duration", elapsed).
+			Msg("shutdown hook timed out")
+	case err != nil:
+		h.logger.Error().
+			Err(err).
+			Str("hook", hk.name).
+			Dur("duration", elapsed).
+			Msg("shutdown hook failed")
+	default:
+		h.logger.Info().
+			Str("hook", hk.name).
+			Dur("duration", elapsed).
+			Msg("shutdown hook completed")
+	}
+
+	return err
+}
@@ -0,0 +1,94 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks_RunInOrder(t *testing.T) {
+	hooks := NewHooks(zerolog.Nop())
+
+	var order []string
+	for _, name := range []string{"validator", "cache", "outbox"} {
+		hooks.Register(name, time.Second, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	require.NoError(t, hooks.Run(context.Background()))
+	assert.Equal(t, []string{"validator", "cache", "outbox"}, order)
+}
+
+func TestHooks_FailureDoesNotStopLaterHooks(t *testing.T) {
+	hooks := NewHooks(zerolog.Nop())
+
+	ran := false
+	hooks.Register("cache", time.Second, func(context.Context) error {
+		return errors.New("disk full")
+	})
+	hooks.Register("validator", time.Second, func(context.Context) error {
+		ran = true
+		return nil
+	})
+
+	err := hooks.Run(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache: disk full")
+	assert.True(t, ran)
+}
+
+func TestHooks_PerHookTimeout(t *testing.T) {
+	hooks := NewHooks(zerolog.Nop())
+
+	release := make(chan struct{})
+	defer close(release)
+
+	// The first hook ignores its context and is abandoned at its timeout
+	hooks.Register("stuck", 20*time.Millisecond, func(context.Context) error {
+		<-release
+		return nil
+	})
+	ran := false
+	hooks.Register("next", time.Second, func(context.Context) error {
+		ran = true
+		return nil
+	})
+
+	start := time.Now()
+	err := hooks.Run(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, ran)
+}
+
+func TestHooks_BudgetExhausted(t *testing.T) {
+	hooks := NewHooks(zerolog.Nop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	hooks.Register("slow", 0, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ran := false
+	hooks.Register("skipped", time.Second, func(context.Context) error {
+		ran = true
+		return nil
+	})
+
+	err := hooks.Run(ctx)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "skipped: skipped")
+	assert.False(t, ran)
+}
@@ -0,0 +1,219 @@
+// Package webhook posts order events from the outbox to downstream systems,
+// such as fulfillment, so they learn about orders without polling the API.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"mini-kart/internal/model"
+	"mini-kart/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// Headers sent with every delivery. The signature is computed over the
+// timestamp and body, so receivers can reject replayed requests.
+const (
+	HeaderEventID   = "X-Webhook-Id"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// leaseMargin is added to the delivery timeout when claiming deliveries, to
+// leave time for recording the outcome before another dispatcher may claim them.
+const leaseMargin = 30 * time.Second
+
+// recordTimeout bounds recording a delivery's outcome, which continues when
+// the dispatcher is stopping so delivered events are not sent again.
+const recordTimeout = 5 * time.Second
+
+// Config holds webhook dispatcher configuration.
+type Config struct {
+	// Secret signs deliveries with HMAC-SHA256.
+	Secret []byte
+
+	// BatchSize is the number of deliveries claimed and sent at once.
+	BatchSize int
+
+	// PollInterval is how often the outbox is checked for due deliveries.
+	PollInterval time.Duration
+
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+
+	// MaxAttempts is how many times a delivery is tried before it is
+	// marked failed.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry. It doubles after each
+	// failed attempt, up to MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Dispatcher posts due deliveries from the webhook outbox, retrying failed
+// ones with exponential backoff. Several dispatchers may share an outbox;
+// each delivery is claimed by one at a time. Delivery is at least once, so
+// receivers should discard events whose ID they have already processed.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	config Config
+	logger zerolog.Logger
+	now    func() time.Time
+}
+
+// NewDispatcher creates a webhook dispatcher.
+func NewDispatcher(repo repository.WebhookRepository, client *http.Client, config Config, logger zerolog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+		config: config,
+		logger: logger.With().Str("component", "webhook-dispatcher").Logger(),
+		now:    time.Now,
+	}
+}
+
+// Run dispatches due deliveries every poll interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain dispatches batches until the outbox has no more due deliveries.
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := d.DispatchDue(ctx)
+		if err != nil {
+			d.logger.Error().Err(err).Msg("failed to dispatch webhooks")
+			return
+		}
+		if claimed < d.config.BatchSize {
+			return
+		}
+	}
+}
+
+// DispatchDue claims one batch of due deliveries and sends them concurrently,
+// returning how many were claimed.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDue(ctx, d.config.BatchSize, d.config.Timeout+leaseMargin)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Go(func() {
+			d.dispatch(ctx, delivery)
+		})
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// dispatch sends a delivery and records the outcome.
+func (d *Dispatcher) dispatch(ctx context.Context, delivery model.WebhookDelivery) {
+	sendErr := d.send(ctx, delivery)
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	logger := d.logger.With().
+		Str("delivery_id", delivery.ID.String()).
+		Str("event_type", delivery.EventType).
+		Str("endpoint", delivery.Endpoint).
+		Int("attempt", delivery.Attempts).
+		Logger()
+
+	var err error
+	switch {
+	case sendErr == nil:
+		err = d.repo.MarkDelivered(recordCtx, delivery.ID)
+		logger.Debug().Msg("webhook delivered")
+	case delivery.Attempts >= d.config.MaxAttempts:
+		err = d.repo.MarkFailed(recordCtx, delivery.ID, sendErr.Error())
+		logger.Error().Err(sendErr).Msg("webhook delivery failed, giving up")
+	default:
+		delay := d.retryDelay(delivery.Attempts)
+		err = d.repo.Reschedule(recordCtx, delivery.ID, delay, sendErr.Error())
+		logger.Warn().Err(sendErr).Dur("retry_in", delay).Msg("webhook delivery failed, retrying")
+	}
+	if err != nil {
+		// The delivery is retried once its lease expires
+		logger.Error().Err(err).Msg("failed to record webhook delivery outcome")
+	}
+}
+
+// send posts a delivery's payload to its endpoint. Any response other than
+// 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Endpoint, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(d.config.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// retryDelay returns the delay after the given failed attempt.
+func (d *Dispatcher) retryDelay(attempt int) time.Duration {
+	delay := d.config.RetryDelay
+	for i := 1; i < attempt && delay < d.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, d.config.MaxRetryDelay)
+}
+
+// Sign returns the X-Webhook-Signature value for a delivery: "sha256=" and
+// the hex HMAC-SHA256 of the timestamp, a dot and the body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
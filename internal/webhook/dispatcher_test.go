@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-kart/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository is an in-memory webhook outbox that records outcomes.
+type fakeRepository struct {
+	mu          sync.Mutex
+	due         []model.WebhookDelivery
+	lease       time.Duration
+	delivered   []uuid.UUID
+	failed      map[uuid.UUID]string
+	rescheduled map[uuid.UUID]time.Duration
+}
+
+func newFakeRepository(deliveries ...model.WebhookDelivery) *fakeRepository {
+	return &fakeRepository{
+		due:         deliveries,
+		failed:      make(map[uuid.UUID]string),
+		rescheduled: make(map[uuid.UUID]time.Duration),
+	}
+}
+
+func (r *fakeRepository) Enqueue(ctx context.Context, tx pgx.Tx, deliveries []model.WebhookDelivery) error {
+	return nil
+}
+
+func (r *fakeRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lease = lease
+	n := min(limit, len(r.due))
+	claimed := r.due[:n]
+	r.due = r.due[n:]
+	for i := range claimed {
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+
+func (r *fakeRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *fakeRepository) Reschedule(ctx context.Context, id uuid.UUID, delay time.Duration, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rescheduled[id] = delay
+	return nil
+}
+
+func (r *fakeRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed[id] = lastError
+	return nil
+}
+
+func (r *fakeRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func testConfig() Config {
+	return Config{
+		Secret:        []byte("webhook-secret"),
+		BatchSize:     10,
+		PollInterval:  time.Second,
+		Timeout:       time.Second,
+		MaxAttempts:   3,
+		RetryDelay:    10 * time.Second,
+		MaxRetryDelay: time.Minute,
+	}
+}
+
+func testDelivery(endpoint string, attempts int) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:        uuid.New(),
+		EventID:   uuid.New(),
+		EventType: model.WebhookOrderCreated,
+		Endpoint:  endpoint,
+		Payload:   json.RawMessage(`{"type":"order.created"}`),
+		Status:    model.WebhookDeliveryPending,
+		Attempts:  attempts,
+	}
+}
+
+func TestDispatcher_DispatchDue_SignsDelivery(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := testDelivery(server.URL, 0)
+	repo := newFakeRepository(delivery)
+	dispatcher := NewDispatcher(repo, server.Client(), testConfig(), zerolog.Nop())
+	dispatcher.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	claimed, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, time.Second+leaseMargin, repo.lease)
+	assert.Equal(t, []uuid.UUID{delivery.ID}, repo.delivered)
+
+	assert.JSONEq(t, string(delivery.Payload), string(body))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, delivery.EventID.String(), header.Get(HeaderEventID))
+	assert.Equal(t, model.WebhookOrderCreated, header.Get(HeaderEventType))
+	assert.Equal(t, "1700000000", header.Get(HeaderTimestamp))
+	assert.Equal(t, Sign([]byte("webhook-secret"), "1700000000", body), header.Get(HeaderSignature))
+}
+
+func TestDispatcher_DispatchDue_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	first := testDelivery(server.URL, 0)
+	third := testDelivery(server.URL, 1)
+	last := testDelivery(server.URL, 2)
+	repo := newFakeRepository(first, third, last)
+	dispatcher := NewDispatcher(repo, server.Client(), testConfig(), zerolog.Nop())
+
+	claimed, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, claimed)
+	assert.Empty(t, repo.delivered)
+
+	// Retries back off exponentially until the final attempt fails
+	assert.Equal(t, map[uuid.UUID]time.Duration{
+		first.ID: 10 * time.Second,
+		third.ID: 20 * time.Second,
+	}, repo.rescheduled)
+	assert.Equal(t, map[uuid.UUID]string{
+		last.ID: "webhook endpoint responded with status 503",
+	}, repo.failed)
+}
+
+func TestDispatcher_RetryDelay(t *testing.T) {
+	dispatcher := NewDispatcher(newFakeRepository(), http.DefaultClient, testConfig(), zerolog.Nop())
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 10 * time.Second},
+		{attempt: 2, want: 20 * time.Second},
+		{attempt: 3, want: 40 * time.Second},
+		{attempt: 4, want: time.Minute},
+		{attempt: 100, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, dispatcher.retryDelay(tt.attempt), "attempt %d", tt.attempt)
+	}
+}
@@ -0,0 +1,2 @@
+-- Drop coupon_limits table
+DROP TABLE IF EXISTS coupon_limits;
@@ -0,0 +1,10 @@
+-- Create coupon_limits table
+-- Codes without a row here are unlimited; rows cap the number of redemptions.
+CREATE TABLE IF NOT EXISTS coupon_limits (
+    code TEXT PRIMARY KEY,
+    max_redemptions INTEGER NOT NULL CHECK (max_redemptions > 0),
+    redeemed_count INTEGER NOT NULL DEFAULT 0 CHECK (redeemed_count >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_coupon_limits_redeemed CHECK (redeemed_count <= max_redemptions)
+);
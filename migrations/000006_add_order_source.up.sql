@@ -0,0 +1,5 @@
+-- Add channel/source attribution to orders (e.g. web, mobile, pos, marketplace:amazon)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS source TEXT;
+
+-- Create index on source for channel filtering and reporting
+CREATE INDEX IF NOT EXISTS idx_orders_source ON orders(source) WHERE source IS NOT NULL;
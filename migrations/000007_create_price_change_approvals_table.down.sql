@@ -0,0 +1,2 @@
+-- Drop price_change_approvals table
+DROP TABLE IF EXISTS price_change_approvals;
@@ -0,0 +1,21 @@
+-- Create price_change_approvals table
+-- Every product price change is recorded here. Changes above the approval
+-- threshold stay pending until a second admin approves or rejects them.
+CREATE TABLE IF NOT EXISTS price_change_approvals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    old_price DECIMAL(10,2) NOT NULL CHECK (old_price >= 0),
+    new_price DECIMAL(10,2) NOT NULL CHECK (new_price >= 0),
+    status TEXT NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'applied')),
+    requested_by TEXT NOT NULL,
+    decided_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ
+);
+
+-- Allow at most one pending price change per product
+CREATE UNIQUE INDEX idx_price_change_approvals_pending
+    ON price_change_approvals(product_id) WHERE status = 'pending';
+
+-- Create index on created_at for listing queries
+CREATE INDEX idx_price_change_approvals_created_at ON price_change_approvals(created_at DESC);
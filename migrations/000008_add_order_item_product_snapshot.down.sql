@@ -0,0 +1,2 @@
+-- Drop product_snapshot column
+ALTER TABLE order_items DROP COLUMN IF EXISTS product_snapshot;
@@ -0,0 +1,14 @@
+-- Snapshot the product (name, category, price) on each order item at creation
+-- so historical orders render correctly after products change or disappear.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_snapshot JSONB;
+
+-- Backfill existing order items from the current catalogue
+UPDATE order_items oi
+SET product_snapshot = jsonb_build_object(
+    'name', p.name,
+    'category', p.category,
+    'price', p.price
+)
+FROM products p
+WHERE oi.product_id = p.id
+  AND oi.product_snapshot IS NULL;
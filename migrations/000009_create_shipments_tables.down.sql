@@ -0,0 +1,5 @@
+-- Drop shipment tables and fulfillment tracking
+DROP TABLE IF EXISTS order_events;
+DROP TABLE IF EXISTS shipment_items;
+DROP TABLE IF EXISTS shipments;
+ALTER TABLE order_items DROP COLUMN IF EXISTS fulfilled_quantity;
@@ -0,0 +1,38 @@
+-- Track how much of each order item has been shipped
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS fulfilled_quantity INTEGER NOT NULL DEFAULT 0
+    CHECK (fulfilled_quantity >= 0 AND fulfilled_quantity <= quantity);
+
+-- Create shipments table
+-- An order's items may be fulfilled across several shipments, e.g. from
+-- different warehouses.
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    carrier TEXT,
+    tracking_number TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shipments_order_id ON shipments(order_id);
+
+-- Create shipment_items table
+CREATE TABLE IF NOT EXISTS shipment_items (
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (shipment_id, order_item_id)
+);
+
+-- Create order_events table
+-- Records fulfillment events for an order, one per shipment.
+CREATE TABLE IF NOT EXISTS order_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    shipment_id UUID REFERENCES shipments(id) ON DELETE SET NULL,
+    type TEXT NOT NULL,
+    fulfillment_status TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_events_order_id ON order_events(order_id, created_at);
@@ -0,0 +1,3 @@
+-- Drop audit log and product archival
+DROP TABLE IF EXISTS audit_log;
+ALTER TABLE products DROP COLUMN IF EXISTS archived_at;
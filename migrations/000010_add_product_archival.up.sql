@@ -0,0 +1,16 @@
+-- Archived products are hidden from the catalogue and cannot be ordered.
+-- Historical orders keep rendering from their product snapshots.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- Create audit_log table
+-- Records admin operations with the acting identity.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC);
@@ -0,0 +1,8 @@
+-- Create coupon_codes table
+-- Holds coupon bases for deployments that load coupons from the database
+-- instead of gzipped files. Each coupon base is a named set of codes.
+CREATE TABLE IF NOT EXISTS coupon_codes (
+    coupon_set TEXT NOT NULL,
+    code TEXT NOT NULL,
+    PRIMARY KEY (coupon_set, code)
+);
@@ -0,0 +1,2 @@
+-- Remove metadata column from orders table
+ALTER TABLE orders DROP COLUMN IF EXISTS metadata;
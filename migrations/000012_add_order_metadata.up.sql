@@ -0,0 +1,4 @@
+-- Add metadata column to orders table
+-- Preserves request fields this server version does not recognise, so orders
+-- from newer clients keep their data during rolling upgrades.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS metadata JSONB;
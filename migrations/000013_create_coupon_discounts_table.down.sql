@@ -0,0 +1,2 @@
+-- Drop coupon_discounts table
+DROP TABLE IF EXISTS coupon_discounts;
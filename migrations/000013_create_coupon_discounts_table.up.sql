@@ -0,0 +1,10 @@
+-- Create coupon_discounts table
+-- Codes without a row here are accepted without a discount. Each row gives
+-- either a percentage off the subtotal or a fixed amount off, never both.
+CREATE TABLE IF NOT EXISTS coupon_discounts (
+    code TEXT PRIMARY KEY,
+    percent_off DECIMAL(5,2) CHECK (percent_off > 0 AND percent_off <= 100),
+    amount_off DECIMAL(10,2) CHECK (amount_off > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_coupon_discounts_kind CHECK ((percent_off IS NULL) <> (amount_off IS NULL))
+);
@@ -0,0 +1,4 @@
+-- Remove price totals from orders table
+ALTER TABLE orders DROP COLUMN IF EXISTS total;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
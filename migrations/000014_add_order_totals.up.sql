@@ -0,0 +1,5 @@
+-- Add price totals to orders table
+-- Orders created before totals were recorded keep NULL totals.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal DECIMAL(10,2);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount DECIMAL(10,2);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total DECIMAL(10,2);
@@ -0,0 +1,12 @@
+-- Create idempotency_keys table
+-- Maps a client-supplied Idempotency-Key to the order it created, so retried
+-- requests return the original order instead of creating a duplicate.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index on created_at for expiring old keys
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
@@ -0,0 +1,5 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_orders_status;
+
+-- Drop status column
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
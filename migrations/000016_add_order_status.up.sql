@@ -0,0 +1,15 @@
+-- Track each order's lifecycle status
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'confirmed', 'cancelled', 'fulfilled'));
+
+-- Orders whose items have all been shipped are already fulfilled
+UPDATE orders o
+SET status = 'fulfilled'
+WHERE EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM order_items i
+      WHERE i.order_id = o.id AND i.fulfilled_quantity < i.quantity
+  );
+
+-- Create index on status for filtering open orders
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
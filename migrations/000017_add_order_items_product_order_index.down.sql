@@ -0,0 +1,4 @@
+-- Restore the single-column product index
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+
+DROP INDEX IF EXISTS idx_order_items_product_order;
@@ -0,0 +1,5 @@
+-- Cover "orders containing product" lookups without visiting the table; the
+-- leading product_id column also serves plain product lookups
+CREATE INDEX IF NOT EXISTS idx_order_items_product_order ON order_items(product_id, order_id);
+
+DROP INDEX IF EXISTS idx_order_items_product_id;
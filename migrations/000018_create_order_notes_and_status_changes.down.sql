@@ -0,0 +1,3 @@
+-- Drop order notes and status history
+DROP TABLE IF EXISTS order_status_changes;
+DROP TABLE IF EXISTS order_notes;
@@ -0,0 +1,23 @@
+-- Create order_notes table
+-- Internal notes support agents add to an order; they are never shown to customers.
+CREATE TABLE IF NOT EXISTS order_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_notes_order_id ON order_notes(order_id, created_at);
+
+-- Create order_status_changes table
+-- Records every order status transition for the order timeline.
+CREATE TABLE IF NOT EXISTS order_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_changes_order_id ON order_status_changes(order_id, created_at);
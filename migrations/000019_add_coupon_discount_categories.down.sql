@@ -0,0 +1,2 @@
+-- Drop categories column
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS categories;
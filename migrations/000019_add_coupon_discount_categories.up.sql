@@ -0,0 +1,3 @@
+-- Restrict a coupon discount to products in the listed categories. NULL means
+-- the discount applies to every item.
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS categories TEXT[];
@@ -0,0 +1,2 @@
+-- Drop first_order_only column
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS first_order_only;
@@ -0,0 +1,2 @@
+-- Mark coupon discounts that are only valid on a customer's first order
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS first_order_only BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,11 @@
+-- Free-shipping-only coupons cannot satisfy the original constraint
+DELETE FROM coupon_discounts WHERE percent_off IS NULL AND amount_off IS NULL;
+
+-- Restore the original discount kind constraint
+ALTER TABLE coupon_discounts DROP CONSTRAINT IF EXISTS chk_coupon_discounts_kind;
+ALTER TABLE coupon_discounts ADD CONSTRAINT chk_coupon_discounts_kind CHECK ((percent_off IS NULL) <> (amount_off IS NULL));
+
+-- Drop free shipping columns
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS free_shipping_countries;
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS free_shipping_min_subtotal;
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS free_shipping;
@@ -0,0 +1,13 @@
+-- Free-shipping promotions waive the shipping charge, optionally only above a
+-- minimum subtotal or for deliveries to the listed countries
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS free_shipping BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS free_shipping_min_subtotal DECIMAL(10,2) CHECK (free_shipping_min_subtotal >= 0);
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS free_shipping_countries TEXT[];
+
+-- A coupon may now grant free shipping alone, but still never both a
+-- percentage and a fixed amount off
+ALTER TABLE coupon_discounts DROP CONSTRAINT IF EXISTS chk_coupon_discounts_kind;
+ALTER TABLE coupon_discounts ADD CONSTRAINT chk_coupon_discounts_kind CHECK (
+    (percent_off IS NULL OR amount_off IS NULL)
+    AND (percent_off IS NOT NULL OR amount_off IS NOT NULL OR free_shipping)
+);
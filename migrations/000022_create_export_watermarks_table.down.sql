@@ -0,0 +1,3 @@
+-- Drop export watermarks and the order change index
+DROP INDEX IF EXISTS idx_orders_updated_at;
+DROP TABLE IF EXISTS export_watermarks;
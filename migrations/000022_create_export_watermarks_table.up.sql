@@ -0,0 +1,11 @@
+-- Create export_watermarks table
+-- Records how far each incremental export has read, so the next run only
+-- extracts rows changed since then.
+CREATE TABLE IF NOT EXISTS export_watermarks (
+    name TEXT PRIMARY KEY,
+    watermark TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index on updated_at for extracting recently changed orders
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders(updated_at, id);
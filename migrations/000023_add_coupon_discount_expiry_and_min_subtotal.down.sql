@@ -0,0 +1,3 @@
+-- Drop coupon expiry and minimum basket columns
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS min_subtotal;
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,4 @@
+-- Coupons may expire, and may require the order subtotal, before discounts,
+-- to reach a minimum basket value
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS min_subtotal DECIMAL(10,2) CHECK (min_subtotal >= 0);
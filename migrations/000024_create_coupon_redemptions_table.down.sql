@@ -0,0 +1,13 @@
+-- Per-caller-only limits cannot satisfy the original constraints
+DELETE FROM coupon_limits WHERE max_redemptions IS NULL;
+
+-- Restore total-only limits
+ALTER TABLE coupon_limits DROP CONSTRAINT IF EXISTS chk_coupon_limits_kind;
+ALTER TABLE coupon_limits DROP CONSTRAINT IF EXISTS chk_coupon_limits_redeemed;
+ALTER TABLE coupon_limits ADD CONSTRAINT chk_coupon_limits_redeemed CHECK (redeemed_count <= max_redemptions);
+ALTER TABLE coupon_limits ALTER COLUMN max_redemptions SET NOT NULL;
+ALTER TABLE coupon_limits DROP COLUMN IF EXISTS max_per_caller;
+
+-- Drop coupon_redemptions table
+DROP INDEX IF EXISTS idx_coupon_redemptions_code_redeemed_by;
+DROP TABLE IF EXISTS coupon_redemptions;
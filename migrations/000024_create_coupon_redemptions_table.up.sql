@@ -0,0 +1,20 @@
+-- Create coupon_redemptions table
+-- Each order placed with a coupon records who redeemed it, so codes can be
+-- limited per caller as well as in total. The order row is written later in
+-- the same transaction, so the foreign key is checked at commit.
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    code TEXT NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    redeemed_by TEXT NOT NULL,
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (code, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_code_redeemed_by ON coupon_redemptions(code, redeemed_by);
+
+-- Limits may now cap redemptions in total, per caller, or both
+ALTER TABLE coupon_limits ADD COLUMN IF NOT EXISTS max_per_caller INTEGER CHECK (max_per_caller > 0);
+ALTER TABLE coupon_limits ALTER COLUMN max_redemptions DROP NOT NULL;
+ALTER TABLE coupon_limits DROP CONSTRAINT IF EXISTS chk_coupon_limits_redeemed;
+ALTER TABLE coupon_limits ADD CONSTRAINT chk_coupon_limits_redeemed CHECK (max_redemptions IS NULL OR redeemed_count <= max_redemptions);
+ALTER TABLE coupon_limits ADD CONSTRAINT chk_coupon_limits_kind CHECK (max_redemptions IS NOT NULL OR max_per_caller IS NOT NULL);
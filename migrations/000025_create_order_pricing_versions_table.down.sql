@@ -0,0 +1,2 @@
+-- Drop order_pricing_versions table
+DROP TABLE IF EXISTS order_pricing_versions;
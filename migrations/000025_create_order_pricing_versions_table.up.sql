@@ -0,0 +1,15 @@
+-- Create order_pricing_versions table
+-- Each recalculation of an order's pricing is kept as a new version, and the
+-- order's totals are updated to match the latest. Version 1 is the pricing the
+-- order was placed with, recorded from its totals when it is first recalculated.
+CREATE TABLE IF NOT EXISTS order_pricing_versions (
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    subtotal DECIMAL(10,2),
+    discount DECIMAL(10,2),
+    total DECIMAL(10,2),
+    breakdown JSONB,
+    created_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, version)
+);
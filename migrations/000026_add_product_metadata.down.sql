@@ -0,0 +1,2 @@
+-- Remove metadata column from products table
+ALTER TABLE products DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,4 @@
+-- Add metadata column to products table
+-- Holds descriptive attributes such as weight or ingredients, which vary by
+-- category and are compared side by side on the storefront.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- Drop order_operations table
+DROP TABLE IF EXISTS order_operations;
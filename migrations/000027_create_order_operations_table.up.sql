@@ -0,0 +1,15 @@
+-- Create order_operations table
+-- Tracks orders accepted for asynchronous creation, so clients can poll for
+-- the outcome from any API replica instead of holding the request open.
+CREATE TABLE IF NOT EXISTS order_operations (
+    id UUID PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    error_code TEXT,
+    error_message TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index on created_at for expiring old operations
+CREATE INDEX IF NOT EXISTS idx_order_operations_created_at ON order_operations(created_at);
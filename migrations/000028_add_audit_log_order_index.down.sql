@@ -0,0 +1,2 @@
+-- Remove order index from audit_log table
+DROP INDEX IF EXISTS idx_audit_log_order_id;
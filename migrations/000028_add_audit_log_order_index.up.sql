@@ -0,0 +1,3 @@
+-- Index order audit entries by order
+-- Order snapshots read every audit entry recorded against an order.
+CREATE INDEX IF NOT EXISTS idx_audit_log_order_id ON audit_log ((details->>'orderId')) WHERE entity_type = 'order';
@@ -0,0 +1,17 @@
+-- Create event_outbox table
+-- Domain events for the analytics pipeline. Rows are written in the
+-- transaction of the change they describe and published to Kafka by the
+-- event relay in sequence order.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id UUID PRIMARY KEY,
+    sequence BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE,
+    event_type TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    published_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create partial index for reading unpublished events in order
+CREATE INDEX IF NOT EXISTS idx_event_outbox_unpublished ON event_outbox(sequence) WHERE published_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop order_sla_breaches table
+DROP TABLE IF EXISTS order_sla_breaches;
@@ -0,0 +1,10 @@
+-- Create order_sla_breaches table
+-- Records each order that stayed in a status beyond its SLA, once per
+-- order and status, so breach alerts are sent once across replicas.
+CREATE TABLE IF NOT EXISTS order_sla_breaches (
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    status_since TIMESTAMPTZ NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, status)
+);
@@ -0,0 +1,5 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_orders_coupon_warning;
+
+-- Drop coupon_warning column
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_warning;
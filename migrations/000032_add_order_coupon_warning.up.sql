@@ -0,0 +1,6 @@
+-- Flag orders whose coupon was accepted without being validated because the
+-- coupon validator failed (see COUPON_FAILURE_POLICY=fail-open)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_warning TEXT;
+
+-- Create index so flagged orders can be found for review
+CREATE INDEX IF NOT EXISTS idx_orders_coupon_warning ON orders(created_at) WHERE coupon_warning IS NOT NULL;
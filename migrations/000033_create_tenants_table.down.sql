@@ -0,0 +1,16 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_tenant_created_at;
+DROP INDEX IF EXISTS idx_products_tenant_category;
+
+-- Only the default tenant's discounts can keep their codes as the key
+DELETE FROM coupon_discounts WHERE tenant_id <> 'default';
+ALTER TABLE coupon_discounts DROP CONSTRAINT IF EXISTS coupon_discounts_pkey;
+ALTER TABLE coupon_discounts ADD PRIMARY KEY (code);
+
+-- Drop tenant_id columns
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE products DROP COLUMN IF EXISTS tenant_id;
+
+-- Drop tenants table
+DROP TABLE IF EXISTS tenants;
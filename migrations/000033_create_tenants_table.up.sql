@@ -0,0 +1,23 @@
+-- Create tenants table
+-- Each tenant is a storefront brand hosted on the deployment. Existing data
+-- belongs to the default tenant.
+CREATE TABLE IF NOT EXISTS tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+-- Scope products, orders and coupon discounts to a tenant
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+-- Each tenant configures its own discount for a code
+ALTER TABLE coupon_discounts DROP CONSTRAINT IF EXISTS coupon_discounts_pkey;
+ALTER TABLE coupon_discounts ADD PRIMARY KEY (tenant_id, code);
+
+-- Create indexes for tenant-scoped listings
+CREATE INDEX IF NOT EXISTS idx_products_tenant_category ON products(tenant_id, category) WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON orders(tenant_id, created_at DESC);
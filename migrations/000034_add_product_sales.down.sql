@@ -0,0 +1,7 @@
+-- Drop sale window constraint
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_sale_window;
+
+-- Drop sale columns
+ALTER TABLE products DROP COLUMN IF EXISTS sale_ends_at;
+ALTER TABLE products DROP COLUMN IF EXISTS sale_starts_at;
+ALTER TABLE products DROP COLUMN IF EXISTS sale_price;
@@ -0,0 +1,10 @@
+-- Schedule time-boxed sale prices on products. A sale without a start runs
+-- from when it is set and one without an end until it is removed.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_price DECIMAL(10,2) CHECK (sale_price >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_starts_at TIMESTAMPTZ;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_ends_at TIMESTAMPTZ;
+
+ALTER TABLE products ADD CONSTRAINT chk_products_sale_window CHECK (
+    (sale_price IS NOT NULL OR (sale_starts_at IS NULL AND sale_ends_at IS NULL))
+    AND (sale_starts_at IS NULL OR sale_ends_at IS NULL OR sale_ends_at > sale_starts_at)
+);
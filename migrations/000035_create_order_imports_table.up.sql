@@ -0,0 +1,10 @@
+-- Create order imports table
+-- Records the legacy reference of each order imported from another platform,
+-- so re-running an import skips the orders it already created.
+CREATE TABLE IF NOT EXISTS order_imports (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    legacy_ref TEXT NOT NULL,
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, legacy_ref)
+);
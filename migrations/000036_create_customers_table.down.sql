@@ -0,0 +1,9 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_orders_customer_created_at;
+
+-- Detach orders from customers
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_customer_fkey;
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_id;
+
+-- Drop customers table
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,21 @@
+-- Create customers table
+-- Customers register with a tenant and are identified by their email, which
+-- is stored in lower case so each address registers once per tenant.
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id),
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, email),
+    UNIQUE (tenant_id, id)
+);
+
+-- Attach orders to the customer who placed them. The foreign key includes the
+-- tenant, so an order can only belong to a customer of its own tenant.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_id UUID;
+ALTER TABLE orders ADD CONSTRAINT orders_customer_fkey
+    FOREIGN KEY (tenant_id, customer_id) REFERENCES customers(tenant_id, id);
+
+-- Create index for customer order history
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created_at ON orders(customer_id, created_at DESC) WHERE customer_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Drop coupon eligibility columns
+ALTER TABLE customers DROP COLUMN IF EXISTS segments;
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS segments;
+ALTER TABLE coupon_discounts DROP COLUMN IF EXISTS customer_ids;
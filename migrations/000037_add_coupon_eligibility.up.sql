@@ -0,0 +1,8 @@
+-- Restrict coupon discounts to specific customers, or to customers in any of
+-- the given segments. NULL or empty arrays leave a discount unrestricted.
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS customer_ids UUID[];
+ALTER TABLE coupon_discounts ADD COLUMN IF NOT EXISTS segments TEXT[];
+
+-- Group customers into segments, e.g. "vip" or "staff", that coupon
+-- discounts can be restricted to
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS segments TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- Drop order shipping address
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_address;
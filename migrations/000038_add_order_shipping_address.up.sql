@@ -0,0 +1,3 @@
+-- Record where an order is to be delivered. NULL for orders placed without
+-- an address, e.g. at a point of sale.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_address JSONB;
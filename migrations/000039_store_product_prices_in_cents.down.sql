@@ -0,0 +1,6 @@
+-- Drop product currencies and store prices as decimals again
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE products
+    ALTER COLUMN price TYPE DECIMAL(10,2) USING price / 100.0,
+    ALTER COLUMN sale_price TYPE DECIMAL(10,2) USING sale_price / 100.0;
@@ -0,0 +1,8 @@
+-- Store product prices as integer cents, so they are exact, and record the
+-- currency they are in. Products created before currencies were recorded
+-- have no currency and are in the catalogue currency.
+ALTER TABLE products
+    ALTER COLUMN price TYPE BIGINT USING round(price * 100),
+    ALTER COLUMN sale_price TYPE BIGINT USING round(sale_price * 100);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3);
@@ -0,0 +1,2 @@
+-- Drop order_sagas table
+DROP TABLE IF EXISTS order_sagas;
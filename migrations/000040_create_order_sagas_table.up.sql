@@ -0,0 +1,24 @@
+-- Create order_sagas table
+-- Side effects order creation has on external systems: payment
+-- authorisations, stock reservations and coupon redemptions. A saga is
+-- completed in the transaction that stores its order. Running sagas still
+-- incomplete once next_attempt_at passes, and compensating sagas, are undone
+-- by the saga recovery loop.
+CREATE TABLE IF NOT EXISTS order_sagas (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'compensating', 'compensated', 'failed')),
+    payload JSONB NOT NULL,
+    steps JSONB NOT NULL DEFAULT '[]',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create partial index for claiming sagas due for compensation
+CREATE INDEX IF NOT EXISTS idx_order_sagas_due ON order_sagas(next_attempt_at) WHERE status IN ('running', 'compensating');
+
+-- Create index for looking up an order's sagas
+CREATE INDEX IF NOT EXISTS idx_order_sagas_order_id ON order_sagas(order_id);
@@ -0,0 +1,4 @@
+-- Drop coupon file registry tables
+DROP TABLE IF EXISTS coupon_generation_files;
+DROP TABLE IF EXISTS coupon_generations;
+DROP TABLE IF EXISTS coupon_files;
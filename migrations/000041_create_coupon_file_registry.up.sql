@@ -0,0 +1,48 @@
+-- Create coupon_files table
+-- Uploads of coupon files, registered by admins so every file the validator
+-- loads can be traced to where it came from and who uploaded it. Files are
+-- matched to the loads that consumed them by set name and checksum.
+CREATE TABLE IF NOT EXISTS coupon_files (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    source TEXT NOT NULL,
+    checksum TEXT NOT NULL CHECK (checksum ~ '^[0-9a-f]{64}$'),
+    size_bytes BIGINT CHECK (size_bytes >= 0),
+    uploaded_by TEXT NOT NULL,
+    uploaded_at TIMESTAMPTZ NOT NULL,
+    registered_by TEXT NOT NULL,
+    registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (name, checksum)
+);
+
+-- Create coupon_generations table
+-- Each complete load of the coupon files by a validator instance, serving
+-- validations from loaded_at until retired_at
+CREATE TABLE IF NOT EXISTS coupon_generations (
+    id BIGSERIAL PRIMARY KEY,
+    instance TEXT NOT NULL,
+    loaded_at TIMESTAMPTZ NOT NULL,
+    retired_at TIMESTAMPTZ
+);
+
+-- Create index for finding the generations in use at a point in time
+CREATE INDEX IF NOT EXISTS idx_coupon_generations_loaded_at ON coupon_generations(loaded_at);
+
+-- Create partial index for retiring an instance's current generation
+CREATE INDEX IF NOT EXISTS idx_coupon_generations_active ON coupon_generations(instance) WHERE retired_at IS NULL;
+
+-- Create coupon_generation_files table
+-- The files a generation loaded, in configured order. Checksum is NULL for
+-- sets not read from a file, such as prebuilt indexes and the database.
+CREATE TABLE IF NOT EXISTS coupon_generation_files (
+    generation_id BIGINT NOT NULL REFERENCES coupon_generations(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    path TEXT NOT NULL,
+    checksum TEXT,
+    codes BIGINT NOT NULL,
+    PRIMARY KEY (generation_id, position)
+);
+
+-- Create index for finding the generations that loaded a file
+CREATE INDEX IF NOT EXISTS idx_coupon_generation_files_checksum ON coupon_generation_files(name, checksum);
@@ -0,0 +1,19 @@
+-- Store amounts as decimals again
+ALTER TABLE coupon_discounts
+    ALTER COLUMN amount_off TYPE DECIMAL(10,2) USING amount_off / 100.0,
+    ALTER COLUMN min_subtotal TYPE DECIMAL(10,2) USING min_subtotal / 100.0,
+    ALTER COLUMN free_shipping_min_subtotal TYPE DECIMAL(10,2) USING free_shipping_min_subtotal / 100.0;
+
+ALTER TABLE price_change_approvals
+    ALTER COLUMN old_price TYPE DECIMAL(10,2) USING old_price / 100.0,
+    ALTER COLUMN new_price TYPE DECIMAL(10,2) USING new_price / 100.0;
+
+ALTER TABLE order_pricing_versions
+    ALTER COLUMN subtotal TYPE DECIMAL(10,2) USING subtotal / 100.0,
+    ALTER COLUMN discount TYPE DECIMAL(10,2) USING discount / 100.0,
+    ALTER COLUMN total TYPE DECIMAL(10,2) USING total / 100.0;
+
+ALTER TABLE orders
+    ALTER COLUMN subtotal TYPE DECIMAL(10,2) USING subtotal / 100.0,
+    ALTER COLUMN discount TYPE DECIMAL(10,2) USING discount / 100.0,
+    ALTER COLUMN total TYPE DECIMAL(10,2) USING total / 100.0;
@@ -0,0 +1,20 @@
+-- Store order totals, pricing versions, price change approvals and coupon
+-- amounts as integer cents, the same as product prices.
+ALTER TABLE orders
+    ALTER COLUMN subtotal TYPE BIGINT USING round(subtotal * 100),
+    ALTER COLUMN discount TYPE BIGINT USING round(discount * 100),
+    ALTER COLUMN total TYPE BIGINT USING round(total * 100);
+
+ALTER TABLE order_pricing_versions
+    ALTER COLUMN subtotal TYPE BIGINT USING round(subtotal * 100),
+    ALTER COLUMN discount TYPE BIGINT USING round(discount * 100),
+    ALTER COLUMN total TYPE BIGINT USING round(total * 100);
+
+ALTER TABLE price_change_approvals
+    ALTER COLUMN old_price TYPE BIGINT USING round(old_price * 100),
+    ALTER COLUMN new_price TYPE BIGINT USING round(new_price * 100);
+
+ALTER TABLE coupon_discounts
+    ALTER COLUMN amount_off TYPE BIGINT USING round(amount_off * 100),
+    ALTER COLUMN min_subtotal TYPE BIGINT USING round(min_subtotal * 100),
+    ALTER COLUMN free_shipping_min_subtotal TYPE BIGINT USING round(free_shipping_min_subtotal * 100);
@@ -0,0 +1,5 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_product_images_product_position;
+
+-- Drop product_images table
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,18 @@
+-- Create product_images table
+-- Images shown with a product, in position order. The image files are
+-- uploaded straight to object storage with presigned URLs; each row records
+-- the key an uploaded file is stored under.
+CREATE TABLE IF NOT EXISTS product_images (
+    id UUID PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    alt_text TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL CHECK (position >= 0),
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index for listing a product's images in order
+CREATE INDEX IF NOT EXISTS idx_product_images_product_position ON product_images(product_id, position);
@@ -0,0 +1,6 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_flash_sale_reservations_pending;
+
+-- Drop flash sale tables
+DROP TABLE IF EXISTS flash_sale_reservations;
+DROP TABLE IF EXISTS flash_sales;
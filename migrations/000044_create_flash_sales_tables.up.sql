@@ -0,0 +1,27 @@
+-- Create flash_sales table
+-- The quantity of an advertised product allocated to a flash sale. sold is
+-- the quantity reserved by orders; reservations taken on Redis counters are
+-- recorded in flash_sale_reservations first and folded into sold by the
+-- reconciler, so hot products are not serialised on this row.
+CREATE TABLE IF NOT EXISTS flash_sales (
+    product_id TEXT PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    sold INTEGER NOT NULL DEFAULT 0 CHECK (sold >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create flash_sale_reservations table
+-- The quantity of a flash-sale product each order reserved. Reservations not
+-- yet counted in flash_sales.sold have no reconciled_at.
+CREATE TABLE IF NOT EXISTS flash_sale_reservations (
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reconciled_at TIMESTAMPTZ,
+    PRIMARY KEY (order_id, product_id)
+);
+
+-- Create index for finding reservations still to reconcile
+CREATE INDEX IF NOT EXISTS idx_flash_sale_reservations_pending ON flash_sale_reservations(product_id, created_at) WHERE reconciled_at IS NULL;
@@ -0,0 +1,4 @@
+-- Drop the order of webhook deliveries
+DROP INDEX IF EXISTS idx_webhook_deliveries_order_id;
+
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS order_id;
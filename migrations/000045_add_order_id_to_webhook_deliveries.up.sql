@@ -0,0 +1,11 @@
+-- Record the order each webhook delivery announces, so deliveries can be
+-- shown in the order's timeline. Every order event carries the order ID in
+-- its data.
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS order_id UUID;
+
+UPDATE webhook_deliveries
+SET order_id = (payload->'data'->>'id')::uuid
+WHERE order_id IS NULL AND event_type IN ('order.created', 'order.cancelled');
+
+-- Create index for listing an order's deliveries
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_order_id ON webhook_deliveries(order_id);
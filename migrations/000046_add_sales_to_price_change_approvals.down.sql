@@ -0,0 +1,5 @@
+-- Drop sale prices from price_change_approvals
+ALTER TABLE price_change_approvals
+    DROP COLUMN IF EXISTS sale_ends_at,
+    DROP COLUMN IF EXISTS sale_starts_at,
+    DROP COLUMN IF EXISTS sale;
@@ -0,0 +1,7 @@
+-- Record sale prices in price_change_approvals, so sales deeper than the
+-- approval threshold wait for a second admin like price changes do. A sale
+-- change schedules a sale at new_price instead of changing the price.
+ALTER TABLE price_change_approvals
+    ADD COLUMN IF NOT EXISTS sale BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS sale_starts_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS sale_ends_at TIMESTAMPTZ;
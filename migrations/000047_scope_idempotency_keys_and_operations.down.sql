@@ -0,0 +1,14 @@
+-- Drop tenants from order operations
+ALTER TABLE order_operations DROP COLUMN IF EXISTS tenant_id;
+
+-- Make idempotency keys one namespace again, keeping the oldest use of each key
+DELETE FROM idempotency_keys a
+USING idempotency_keys b
+WHERE a.idempotency_key = b.idempotency_key
+  AND (a.created_at, a.tenant_id, a.caller) > (b.created_at, b.tenant_id, b.caller);
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (idempotency_key);
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS caller;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,11 @@
+-- Scope idempotency keys to the tenant and caller that used them, so callers
+-- choosing the same key neither replay nor learn of each other's orders.
+-- Keys recorded before callers were recorded have no caller.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS caller TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, caller, idempotency_key);
+
+-- Scope order operations to the tenant whose order they create
+ALTER TABLE order_operations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"mini-kart/internal/coupon"
 	"mini-kart/internal/handler"
+	"mini-kart/internal/metrics"
+	"mini-kart/internal/middleware"
 	"mini-kart/internal/model"
 	"mini-kart/internal/repository"
 	"mini-kart/internal/router"
@@ -20,7 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func setupTestServer(t *testing.T, testDB *TestDB) http.Handler {
+func setupTestServer(t *testing.T, testDB *TestDB, opts ...router.Option) http.Handler {
 	t.Helper()
 
 	logger := zerolog.Nop()
@@ -51,7 +54,7 @@ func setupTestServer(t *testing.T, testDB *TestDB) http.Handler {
 	orderHandler := handler.NewOrderHandler(orderService, logger)
 
 	// Create router
-	return router.New(productHandler, orderHandler, "test-api-key", logger)
+	return router.New(productHandler, orderHandler, "test-api-key", logger, opts...)
 }
 
 func TestProductAPI_Integration(t *testing.T) {
@@ -314,3 +317,52 @@ func TestCORS_Integration(t *testing.T) {
 		assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "GET")
 	})
 }
+
+func TestAPIVersioning_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	routes := middleware.NewRouteRegistry()
+	routes.Register(middleware.Route{Prefix: router.VersionPrefix})
+	routes.Register(middleware.Route{
+		Prefix: "/api/",
+		Deprecation: &middleware.DeprecationPolicy{
+			Deprecated: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:     time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			Successor:  router.VersionPrefix,
+		},
+	})
+	counters := metrics.NewRegistry()
+
+	testDB := SetupTestDB(t)
+	server := setupTestServer(t, testDB, router.WithDeprecations(routes, counters))
+
+	CleanupDB(t, testDB.Pool)
+	SeedProducts(t, testDB.Pool)
+
+	t.Run("Versioned path serves the same resource without deprecation headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/products/P001", nil)
+		req.Header.Set("X-API-Key", "test-api-key")
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Deprecation"))
+	})
+
+	t.Run("Unversioned path is marked deprecated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/products/P001", nil)
+		req.Header.Set("X-API-Key", "test-api-key")
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Deprecation"))
+		assert.NotEmpty(t, w.Header().Get("Sunset"))
+		assert.Contains(t, w.Header().Get("Link"), "</api/v1/products/P001>")
+		assert.NotEmpty(t, counters.Snapshot())
+	})
+}
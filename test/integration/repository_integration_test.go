@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"mini-kart/internal/fixtures"
 	"mini-kart/internal/model"
 	"mini-kart/internal/repository"
 
@@ -28,7 +29,7 @@ func TestProductRepository_Integration(t *testing.T) {
 		CleanupDB(t, testDB.Pool)
 		SeedProducts(t, testDB.Pool)
 
-		products, err := repo.GetAll(ctx, 10, 0)
+		products, err := repo.GetAll(ctx, model.ProductFilter{Limit: 10})
 		require.NoError(t, err)
 		assert.Len(t, products, 5)
 		assert.Equal(t, "P001", products[0].ID)
@@ -38,11 +39,11 @@ func TestProductRepository_Integration(t *testing.T) {
 		CleanupDB(t, testDB.Pool)
 		SeedProducts(t, testDB.Pool)
 
-		products, err := repo.GetAll(ctx, 2, 0)
+		products, err := repo.GetAll(ctx, model.ProductFilter{Limit: 2})
 		require.NoError(t, err)
 		assert.Len(t, products, 2)
 
-		products, err = repo.GetAll(ctx, 2, 2)
+		products, err = repo.GetAll(ctx, model.ProductFilter{Limit: 2, Offset: 2})
 		require.NoError(t, err)
 		assert.Len(t, products, 2)
 	})
@@ -56,7 +57,7 @@ func TestProductRepository_Integration(t *testing.T) {
 		require.NotNil(t, product)
 		assert.Equal(t, "P001", product.ID)
 		assert.Equal(t, "Test Product 1", product.Name)
-		assert.Equal(t, 10.00, product.Price)
+		assert.Equal(t, model.NewMoney(10), product.Price)
 	})
 
 	t.Run("GetByID returns nil for non-existent product", func(t *testing.T) {
@@ -114,32 +115,18 @@ func TestOrderRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Create order
-		orderID := uuid.New()
 		couponCode := "TESTCODE"
-		order := &model.Order{
-			ID:         orderID,
-			CouponCode: &couponCode,
-		}
+		order, items := fixtures.NewOrder().
+			Coupon(couponCode).
+			Item("P001", 2).
+			Item("P002", 1).
+			Build()
+		orderID := order.ID
 
 		err = repo.CreateOrder(ctx, tx, order)
 		require.NoError(t, err)
 
 		// Create order items
-		items := []model.OrderItem{
-			{
-				ID:        uuid.New(),
-				OrderID:   orderID,
-				ProductID: "P001",
-				Quantity:  2,
-			},
-			{
-				ID:        uuid.New(),
-				OrderID:   orderID,
-				ProductID: "P002",
-				Quantity:  1,
-			},
-		}
-
 		err = repo.CreateOrderItems(ctx, tx, items)
 		require.NoError(t, err)
 
@@ -174,11 +161,8 @@ func TestOrderRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Create order
-		orderID := uuid.New()
-		order := &model.Order{
-			ID:         orderID,
-			CouponCode: nil,
-		}
+		order := fixtures.NewOrder().Order()
+		orderID := order.ID
 
 		err = repo.CreateOrder(ctx, tx, order)
 		require.NoError(t, err)
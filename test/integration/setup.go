@@ -8,6 +8,8 @@ import (
 
 	"mini-kart/internal/config"
 	"mini-kart/internal/database"
+	"mini-kart/internal/fixtures"
+	"mini-kart/internal/model"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
@@ -108,16 +110,45 @@ func createSchema(t *testing.T, pool *pgxpool.Pool) {
 		CREATE TABLE IF NOT EXISTS products (
 			id VARCHAR(50) PRIMARY KEY,
 			name VARCHAR(255) NOT NULL,
-			price DECIMAL(10, 2) NOT NULL,
+			price BIGINT NOT NULL,
 			category VARCHAR(100) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			archived_at TIMESTAMPTZ,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			sale_price BIGINT CHECK (sale_price >= 0),
+			sale_starts_at TIMESTAMPTZ,
+			sale_ends_at TIMESTAMPTZ,
+			currency CHAR(3)
+		);
+
+		CREATE TABLE IF NOT EXISTS customers (
+			id UUID PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			email TEXT NOT NULL,
+			name TEXT NOT NULL,
+			segments TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (tenant_id, email),
+			UNIQUE (tenant_id, id)
 		);
 
 		CREATE TABLE IF NOT EXISTS orders (
 			id UUID PRIMARY KEY,
 			coupon_code VARCHAR(50),
+			coupon_warning TEXT,
+			source VARCHAR(100),
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			metadata JSONB,
+			shipping_address JSONB,
+			subtotal BIGINT,
+			discount BIGINT,
+			total BIGINT,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			customer_id UUID,
+			FOREIGN KEY (tenant_id, customer_id) REFERENCES customers(tenant_id, id)
 		);
 
 		CREATE TABLE IF NOT EXISTS order_items (
@@ -125,11 +156,29 @@ func createSchema(t *testing.T, pool *pgxpool.Pool) {
 			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
 			product_id VARCHAR(50) NOT NULL REFERENCES products(id),
 			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			fulfilled_quantity INTEGER NOT NULL DEFAULT 0 CHECK (fulfilled_quantity >= 0 AND fulfilled_quantity <= quantity),
+			product_snapshot JSONB,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
 
+		CREATE TABLE IF NOT EXISTS order_notes (
+			id UUID PRIMARY KEY,
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			author TEXT NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS order_status_changes (
+			id UUID PRIMARY KEY,
+			order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			from_status VARCHAR(20) NOT NULL,
+			to_status VARCHAR(20) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
 		CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
-		CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+		CREATE INDEX IF NOT EXISTS idx_order_items_product_order ON order_items(product_id, order_id);
 	`
 
 	_, err := pool.Exec(ctx, schema)
@@ -144,26 +193,21 @@ func SeedProducts(t *testing.T, pool *pgxpool.Pool) {
 
 	ctx := context.Background()
 
-	products := []struct {
-		id       string
-		name     string
-		price    float64
-		category string
-	}{
-		{"P001", "Test Product 1", 10.00, "Category A"},
-		{"P002", "Test Product 2", 20.00, "Category B"},
-		{"P003", "Test Product 3", 30.00, "Category A"},
-		{"P004", "Test Product 4", 40.00, "Category C"},
-		{"P005", "Test Product 5", 50.00, "Category B"},
+	products := []model.Product{
+		fixtures.NewProduct(1).Name("Test Product 1").Category("Category A").Build(),
+		fixtures.NewProduct(2).Name("Test Product 2").Category("Category B").Build(),
+		fixtures.NewProduct(3).Name("Test Product 3").Category("Category A").Build(),
+		fixtures.NewProduct(4).Name("Test Product 4").Category("Category C").Build(),
+		fixtures.NewProduct(5).Name("Test Product 5").Category("Category B").Build(),
 	}
 
 	for _, p := range products {
 		_, err := pool.Exec(ctx,
 			"INSERT INTO products (id, name, price, category) VALUES ($1, $2, $3, $4)",
-			p.id, p.name, p.price, p.category,
+			p.ID, p.Name, p.Price, p.Category,
 		)
 		if err != nil {
-			t.Fatalf("failed to seed product %s: %v", p.id, err)
+			t.Fatalf("failed to seed product %s: %v", p.ID, err)
 		}
 	}
 }